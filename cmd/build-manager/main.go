@@ -3,15 +3,20 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/nettracex/nettracex-tui/internal/build"
+	"github.com/nettracex/nettracex-tui/internal/distribution"
+	"github.com/nettracex/nettracex-tui/internal/update"
 )
 
 const (
@@ -30,6 +35,12 @@ func main() {
 		validate      = flag.Bool("validate", false, "Validate build environment only")
 		metadata      = flag.Bool("metadata", true, "Generate build metadata")
 		checksums     = flag.Bool("checksums", true, "Generate checksums")
+		sbom          = flag.String("sbom", getEnvOrDefault("SBOM", ""), "Comma-separated SBOM formats to generate per artifact (spdx, cyclonedx)")
+		packages      = flag.String("packages", getEnvOrDefault("PACKAGES", ""), "Comma-separated package formats to build of the Linux artifacts (deb, rpm)")
+		packageConfig = flag.String("package-config", "packaging/package.yaml", "Package configuration file")
+		msi           = flag.Bool("msi", getEnvOrDefault("MSI", "false") == "true", "Build MSI installers of the Windows artifacts")
+		macosSign     = flag.Bool("macos-sign", getEnvOrDefault("MACOS_SIGN", "false") == "true", "Codesign and notarize the darwin artifacts")
+		macosConfig   = flag.String("macos-config", "packaging/macos.yaml", "macOS signing configuration file")
 		wingetManifest = flag.Bool("winget", false, "Generate Winget package manifest")
 		windowsInstaller = flag.Bool("windows-installer", false, "Generate Windows installer scripts")
 		help          = flag.Bool("help", false, "Show help message")
@@ -109,6 +120,16 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Sign and notarize the darwin binaries before anything is derived from
+	// them; a rejected notarization fails the release
+	if *macosSign {
+		fmt.Println("Signing and notarizing macOS binaries...")
+		if err := signMacOSArtifacts(bm, config, *macosConfig); err != nil {
+			fmt.Fprintf(os.Stderr, "macOS signing failed: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Generate checksums if requested
 	if *checksums {
 		fmt.Println("Generating checksums...")
@@ -118,6 +139,33 @@ func main() {
 		}
 	}
 
+	// Generate SBOMs if requested
+	if *sbom != "" {
+		fmt.Println("Generating SBOMs...")
+		if err := generateSBOMs(bm, config, *sbom); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to generate SBOMs: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Build Linux packages if requested
+	if *packages != "" {
+		fmt.Println("Building Linux packages...")
+		if err := generatePackages(bm, config, *packages, *packageConfig); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to build packages: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Build MSI installers if requested
+	if *msi {
+		fmt.Println("Building MSI installers...")
+		if err := generateMSIs(bm, config, *packageConfig); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to build MSI installers: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Generate metadata if requested
 	if *metadata {
 		fmt.Println("Generating build metadata...")
@@ -136,8 +184,10 @@ func main() {
 		}
 	}
 
-	// Generate Winget manifest if requested
-	if *wingetManifest {
+	// Generate Winget manifest if requested; winget has no prereleases
+	if channel := update.VersionChannel(config.Version); *wingetManifest && channel != update.Stable {
+		fmt.Printf("Skipping Winget manifest: %s is a %s prerelease\n", config.Version, channel)
+	} else if *wingetManifest {
 		fmt.Println("Generating Winget package manifest...")
 		release := createReleaseFromArtifacts(bm, config)
 		if err := bm.GenerateWingetManifest(release); err != nil {
@@ -247,6 +297,146 @@ func printBuildSummary(bm *build.BuildManager, config build.BuildConfig) {
 	fmt.Println("\nBuild completed successfully! 🎉")
 }
 
+// generateSBOMs writes an SBOM of each artifact in each of formats, recording
+// the module dependencies and versions the artifact was built with
+func generateSBOMs(bm *build.BuildManager, config build.BuildConfig, formats string) error {
+	sbomFormats, err := distribution.ParseSBOMFormats(formats)
+	if err != nil {
+		return err
+	}
+
+	created := time.Now()
+	for _, artifact := range bm.GetArtifacts() {
+		path := filepath.Join(config.OutputDir, artifact.Filename)
+		files, err := distribution.WriteSBOMFiles(path, artifact.Filename, config.Version, sbomFormats, created)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			fmt.Printf("  %s\n", filepath.Base(file))
+		}
+	}
+	return nil
+}
+
+// generatePackages builds a package of each Linux artifact in each of
+// formats with nfpm, after writing the completion scripts it installs
+func generatePackages(bm *build.BuildManager, config build.BuildConfig, formats, configPath string) error {
+	packageFormats, err := distribution.ParsePackageFormats(formats)
+	if err != nil {
+		return err
+	}
+	packageConfig, err := distribution.LoadPackageConfig(configPath)
+	if err != nil {
+		return err
+	}
+	if err := writeCompletions(packageConfig.Completions); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	builder := distribution.NewPackageBuilder(packageConfig)
+	for _, artifact := range bm.GetArtifacts() {
+		if artifact.Target.OS != "linux" {
+			continue
+		}
+		binary := distribution.Binary{
+			Platform:     artifact.Target.OS,
+			Architecture: artifact.Target.Arch,
+			Filename:     artifact.Filename,
+			FilePath:     filepath.Join(config.OutputDir, artifact.Filename),
+		}
+		for _, format := range packageFormats {
+			path, err := builder.Build(ctx, binary, config.Version, format, config.OutputDir)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("  %s\n", filepath.Base(path))
+		}
+	}
+	return nil
+}
+
+// signMacOSArtifacts codesigns each darwin artifact with the Developer ID
+// of the signing configuration, notarizes it and staples the ticket when it
+// can be stapled
+func signMacOSArtifacts(bm *build.BuildManager, config build.BuildConfig, configPath string) error {
+	if runtime.GOOS != "darwin" {
+		return fmt.Errorf("codesigning needs macOS, not %s", runtime.GOOS)
+	}
+	signingConfig, err := distribution.LoadMacOSSigningConfig(configPath)
+	if err != nil {
+		return err
+	}
+	signer, err := distribution.NewMacOSSigner(signingConfig)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	for _, artifact := range bm.GetArtifacts() {
+		if artifact.Target.OS != "darwin" {
+			continue
+		}
+		path := filepath.Join(config.OutputDir, artifact.Filename)
+		if err := signer.Process(ctx, path); err != nil {
+			return fmt.Errorf("%s: %w", artifact.Filename, err)
+		}
+		if signingConfig.Notarize {
+			fmt.Printf("  %s (signed and notarized)\n", artifact.Filename)
+		} else {
+			fmt.Printf("  %s (signed)\n", artifact.Filename)
+		}
+	}
+	return nil
+}
+
+// generateMSIs builds an MSI installer of each Windows artifact with WiX
+func generateMSIs(bm *build.BuildManager, config build.BuildConfig, configPath string) error {
+	packageConfig, err := distribution.LoadPackageConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	builder := distribution.NewMSIBuilder(packageConfig)
+	for _, artifact := range bm.GetArtifacts() {
+		if artifact.Target.OS != "windows" {
+			continue
+		}
+		binary := distribution.Binary{
+			Platform:     artifact.Target.OS,
+			Architecture: artifact.Target.Arch,
+			Filename:     artifact.Filename,
+			FilePath:     filepath.Join(config.OutputDir, artifact.Filename),
+		}
+		path, err := builder.Build(ctx, binary, config.Version, config.OutputDir)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("  %s\n", filepath.Base(path))
+	}
+	return nil
+}
+
+// writeCompletions writes the completion script of each shell to its file
+// with the completion command of the program being packaged
+func writeCompletions(completions map[string]string) error {
+	for shell, path := range completions {
+		output, err := exec.Command("go", "run", ".", "completion", shell).Output()
+		if err != nil {
+			return fmt.Errorf("failed to generate %s completion: %w", shell, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, output, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // handleWingetManifest handles Winget manifest generation from environment variables
 func handleWingetManifest() {
 	version := getEnvOrDefault("WINGET_VERSION", "")
@@ -299,6 +489,12 @@ func handleWingetManifest() {
 		ReleaseNotes: fmt.Sprintf("NetTraceX version %s", version),
 	}
 
+	// Winget users all update to the manifest, so prereleases are skipped
+	if channel := update.VersionChannel(version); channel != update.Stable {
+		fmt.Printf("Skipping Winget manifest: %s is a %s prerelease\n", version, channel)
+		return
+	}
+
 	// Generate Winget manifest
 	fmt.Printf("Generating Winget manifest for version %s...\n", version)
 	if err := bm.GenerateWingetManifest(release); err != nil {
@@ -390,6 +586,12 @@ func showHelp() {
 	fmt.Println("  -validate              Validate build environment only")
 	fmt.Println("  -metadata              Generate build metadata (default: true)")
 	fmt.Println("  -checksums             Generate checksums (default: true)")
+	fmt.Println("  -sbom string           SBOM formats to generate per artifact (spdx, cyclonedx)")
+	fmt.Println("  -packages string       Package formats to build of the Linux artifacts (deb, rpm), needs nfpm")
+	fmt.Println("  -package-config string Package configuration file (default: packaging/package.yaml)")
+	fmt.Println("  -msi                   Build MSI installers of the Windows artifacts, needs WiX")
+	fmt.Println("  -macos-sign            Codesign and notarize the darwin artifacts, needs macOS")
+	fmt.Println("  -macos-config string   macOS signing configuration file (default: packaging/macos.yaml)")
 	fmt.Println("  -winget                Generate Winget package manifest")
 	fmt.Println("  -windows-installer     Generate Windows installer scripts")
 	fmt.Println("  -help                  Show this help message")
@@ -399,6 +601,10 @@ func showHelp() {
 	fmt.Println("  OUTPUT_DIR             Output directory for binaries")
 	fmt.Println("  GIT_COMMIT             Git commit hash")
 	fmt.Println("  COMPRESS               Enable compression (true/false)")
+	fmt.Println("  SBOM                   SBOM formats to generate per artifact")
+	fmt.Println("  PACKAGES               Package formats to build of the Linux artifacts")
+	fmt.Println("  MSI                    Build MSI installers (true/false)")
+	fmt.Println("  MACOS_SIGN             Codesign and notarize the darwin artifacts (true/false)")
 	fmt.Println()
 	fmt.Println("Winget Manifest Environment Variables:")
 	fmt.Println("  WINGET_VERSION         Version for Winget manifest")
@@ -416,6 +622,18 @@ func showHelp() {
 	fmt.Println("  # Build only for Linux and Windows")
 	fmt.Printf("  %s -targets linux/amd64,windows/amd64\n", filepath.Base(os.Args[0]))
 	fmt.Println()
+	fmt.Println("  # Build with SPDX and CycloneDX SBOMs")
+	fmt.Printf("  %s -sbom spdx,cyclonedx\n", filepath.Base(os.Args[0]))
+	fmt.Println()
+	fmt.Println("  # Build Debian and RPM packages of the Linux binaries")
+	fmt.Printf("  %s -version 1.0.0 -packages deb,rpm\n", filepath.Base(os.Args[0]))
+	fmt.Println()
+	fmt.Println("  # Build MSI installers of the Windows binaries")
+	fmt.Printf("  %s -version 1.0.0 -targets windows/amd64,windows/arm64 -msi\n", filepath.Base(os.Args[0]))
+	fmt.Println()
+	fmt.Println("  # Build, codesign and notarize the macOS binaries")
+	fmt.Printf("  %s -version 1.0.0 -targets darwin/amd64,darwin/arm64 -macos-sign\n", filepath.Base(os.Args[0]))
+	fmt.Println()
 	fmt.Println("  # Generate Winget manifest and Windows installer")
 	fmt.Printf("  %s -winget -windows-installer\n", filepath.Base(os.Args[0]))
 	fmt.Println()
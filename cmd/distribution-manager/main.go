@@ -10,27 +10,45 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/nettracex/nettracex-tui/internal/distribution"
+	"github.com/nettracex/nettracex-tui/internal/update"
 )
 
 const (
 	defaultConfigFile = ".kiro/distribution/config.json"
+	defaultRecordsDir = ".kiro/distribution/releases"
 )
 
 func main() {
 	var (
 		configFile  = flag.String("config", defaultConfigFile, "Configuration file path")
-		command     = flag.String("command", "distribute", "Command to execute (distribute, validate, status, generate-homebrew)")
+		command     = flag.String("command", "distribute", "Command to execute (distribute, plan, rollback, notes, validate, checksums, sbom, status, generate-homebrew, generate-scoop, generate-aur, generate-nix, generate-flatpak)")
 		version     = flag.String("version", "", "Release version")
 		tag         = flag.String("tag", "", "Git tag")
 		binDir      = flag.String("bin-dir", "bin", "Directory containing binaries")
 		verbose     = flag.Bool("verbose", false, "Verbose output")
-		binaryURL   = flag.String("binary-url", "", "Binary download URL (for generate-homebrew)")
-		output      = flag.String("output", "", "Output file path (for generate-homebrew)")
+		binaryURL   = flag.String("binary-url", "", "Binary download URL, with {platform} and {arch} for every generate-homebrew platform (for generate-homebrew, generate-scoop, generate-aur, generate-nix, generate-flatpak)")
+		output      = flag.String("output", "", "Output file path, or directory for generate-aur and generate-nix (for notes, generate-homebrew, generate-scoop, generate-aur, generate-nix, generate-flatpak)")
+		withSHA512  = flag.Bool("sha512", false, "Also write SHA-512 checksums to checksums.sha512.txt (for checksums)")
+		sbomFormats = flag.String("sbom-format", "spdx,cyclonedx", "Comma-separated SBOM formats, spdx and cyclonedx (for sbom)")
+		recordsDir  = flag.String("records-dir", defaultRecordsDir, "Directory where what each release published is recorded")
+		reason      = flag.String("reason", "", "Why the release is rolled back (for rollback)")
+		yank        = flag.Bool("yank", false, "Mark the release as withdrawn instead of deleting it, where supported (for rollback)")
+		resume      = flag.Bool("resume", false, "Retry only the publishers that have not published the release yet, from its record (for distribute)")
+		dryRun      = flag.Bool("dry-run", false, "Show what would be published, rendering files and checking credentials, without publishing (for distribute)")
+		notesFile   = flag.String("notes-file", "", "File with the hand-written notes of the release")
+		publisher   = flag.String("publisher", "", "Publisher whose release notes template is rendered (for notes)")
+		channel     = flag.String("channel", "", "Release channel (stable, rc, beta, alpha), by default the one of the version")
+		highlights  []string
 	)
+	flag.Func("highlight", "A highlight of the release, for its notes; may be repeated", func(value string) error {
+		highlights = append(highlights, value)
+		return nil
+	})
 	flag.Parse()
 
 	if *version == "" {
@@ -49,10 +67,13 @@ func main() {
 
 	// Create distribution coordinator
 	coordinator := distribution.NewDistributionCoordinator(config)
+	coordinator.SetReleaseStore(distribution.NewReleaseStore(*recordsDir))
 
 	// Set up notification service
 	notificationConfig := distribution.NotificationServiceConfig{
-		Enabled: true,
+		Enabled:   config.Notifications.Enabled,
+		Channels:  notificationChannelsFrom(config.Notifications),
+		Templates: config.Notifications.Templates,
 		RetryPolicy: distribution.NotificationRetryPolicy{
 			MaxRetries: 3,
 			BaseDelay:  time.Second,
@@ -77,6 +98,24 @@ func main() {
 		log.Fatalf("Failed to create release: %v", err)
 	}
 
+	if *channel != "" {
+		releaseChannel, err := update.ParseChannel(*channel)
+		if err != nil {
+			log.Fatal(err)
+		}
+		release.Metadata.Channel = releaseChannel
+	}
+	release.Metadata.IsPrerelease = distribution.IsPrerelease(*release)
+
+	release.Highlights = highlights
+	if *notesFile != "" {
+		notes, err := os.ReadFile(*notesFile)
+		if err != nil {
+			log.Fatalf("Failed to read release notes: %v", err)
+		}
+		release.ReleaseNotes = string(notes)
+	}
+
 	if *verbose {
 		fmt.Printf("Created release: %s\n", release.Version)
 		fmt.Printf("Binaries: %d\n", len(release.Binaries))
@@ -87,16 +126,104 @@ func main() {
 	ctx := context.Background()
 	switch *command {
 	case "distribute":
-		if err := coordinator.Distribute(ctx, *release); err != nil {
+		if *dryRun {
+			reports, err := coordinator.DryRun(ctx, *release)
+			if len(reports) > 0 {
+				printDryRun(*release, reports)
+			}
+			if err != nil {
+				log.Fatalf("Dry run failed: %v", err)
+			}
+			fmt.Printf("Dry run of release %s passed, nothing was published\n", release.Version)
+			break
+		}
+		distribute := coordinator.Distribute
+		if *resume {
+			distribute = coordinator.Resume
+		}
+		if err := distribute(ctx, *release); err != nil {
 			log.Fatalf("Distribution failed: %v", err)
 		}
 		fmt.Printf("Successfully distributed release %s\n", release.Version)
 
+	case "plan":
+		stages, err := coordinator.Plan()
+		if err != nil {
+			log.Fatalf("Invalid publishing plan: %v", err)
+		}
+		fmt.Printf("Publishing plan for release %s:\n", release.Version)
+		for _, stage := range stages {
+			fmt.Printf("  Stage %d:\n", stage.Number)
+			for _, name := range stage.Publishers {
+				if deps := stage.DependsOn[name]; len(deps) > 0 {
+					fmt.Printf("    %s (after %s)\n", name, strings.Join(deps, ", "))
+				} else {
+					fmt.Printf("    %s\n", name)
+				}
+			}
+		}
+
+	case "notes":
+		notes, err := coordinator.ReleaseNotes(*release, *publisher)
+		if err != nil {
+			log.Fatalf("Failed to render release notes: %v", err)
+		}
+		if *output == "" {
+			fmt.Print(notes)
+		} else if err := os.WriteFile(*output, []byte(notes), 0644); err != nil {
+			log.Fatalf("Failed to write release notes: %v", err)
+		}
+
+	case "rollback":
+		fmt.Printf("Rolling back release %s...\n", *tag)
+		results, err := coordinator.Rollback(ctx, *tag, distribution.RollbackOptions{Reason: *reason, Yank: *yank})
+		for _, result := range results {
+			switch {
+			case result.Err != nil:
+				fmt.Printf("  %s: failed: %v\n", result.Publisher, result.Err)
+			case result.Manual:
+				fmt.Printf("  %s: manual: %s\n", result.Publisher, result.Message)
+			default:
+				fmt.Printf("  %s: %s\n", result.Publisher, result.Message)
+			}
+		}
+		if err != nil {
+			log.Fatalf("Rollback failed: %v", err)
+		}
+
 	case "validate":
 		fmt.Printf("Validating release %s...\n", release.Version)
-		// Validation is performed as part of distribution
+		if err := verifyChecksumFiles(*binDir); err != nil {
+			log.Fatalf("Checksum verification failed: %v", err)
+		}
+		if err := verifySignatures(ctx, *release, config); err != nil {
+			log.Fatalf("Signature verification failed: %v", err)
+		}
+		// The release validators run as part of distribution
 		fmt.Println("Release validation completed successfully")
 
+	case "checksums":
+		files, err := writeChecksumFiles(*binDir, release, *withSHA512)
+		if err != nil {
+			log.Fatalf("Failed to write checksums: %v", err)
+		}
+		for _, file := range files {
+			fmt.Printf("Wrote %s\n", file)
+		}
+
+	case "sbom":
+		formats, err := distribution.ParseSBOMFormats(*sbomFormats)
+		if err != nil {
+			log.Fatalf("Invalid SBOM format: %v", err)
+		}
+		files, err := writeSBOMFiles(release, formats)
+		if err != nil {
+			log.Fatalf("Failed to write SBOMs: %v", err)
+		}
+		for _, file := range files {
+			fmt.Printf("Wrote %s\n", file)
+		}
+
 	case "status":
 		statuses := coordinator.GetPublisherStatus()
 		fmt.Println("Publisher Status:")
@@ -114,11 +241,61 @@ func main() {
 		}
 		fmt.Printf("Successfully generated Homebrew formula for version %s\n", *version)
 
+	case "generate-scoop":
+		if err := generateScoopManifest(*version, *binaryURL, *output, config); err != nil {
+			log.Fatalf("Failed to generate Scoop manifest: %v", err)
+		}
+		fmt.Printf("Successfully generated Scoop manifest for version %s\n", *version)
+
+	case "generate-aur":
+		if err := generateAURPackage(*version, *binaryURL, *output, config); err != nil {
+			log.Fatalf("Failed to generate AUR package: %v", err)
+		}
+		fmt.Printf("Successfully generated AUR package for version %s\n", *version)
+
+	case "generate-nix":
+		if err := generateNixPackage(*version, *binaryURL, *output, config); err != nil {
+			log.Fatalf("Failed to generate Nix package: %v", err)
+		}
+		fmt.Printf("Successfully generated Nix package for version %s\n", *version)
+
+	case "generate-flatpak":
+		if err := generateFlatpakManifest(*version, *binaryURL, *output, config); err != nil {
+			log.Fatalf("Failed to generate Flatpak manifest: %v", err)
+		}
+		fmt.Printf("Successfully generated Flatpak manifest for version %s\n", *version)
+
 	default:
 		log.Fatalf("Unknown command: %s", *command)
 	}
 }
 
+// printDryRun prints what each publisher would publish, with the files it
+// would write
+func printDryRun(release distribution.Release, reports []distribution.DryRunReport) {
+	fmt.Printf("Dry run of release %s:\n", release.Version)
+	for _, report := range reports {
+		fmt.Printf("\n%s (stage %d", report.Publisher, report.Stage)
+		if len(report.DependsOn) > 0 {
+			fmt.Printf(", after %s", strings.Join(report.DependsOn, ", "))
+		}
+		fmt.Println("):")
+		for _, action := range report.Actions {
+			fmt.Printf("  would %s\n", action)
+		}
+		for _, name := range report.FileNames() {
+			fmt.Printf("  --- %s ---\n", name)
+			for _, line := range strings.Split(strings.TrimRight(report.Files[name], "\n"), "\n") {
+				fmt.Printf("  | %s\n", line)
+			}
+		}
+		if report.Err != nil {
+			fmt.Printf("  would fail: %v\n", report.Err)
+		}
+	}
+	fmt.Println()
+}
+
 // loadConfig loads the distribution configuration
 func loadConfig(configFile string) (*distribution.DistributionConfig, error) {
 	// Create default config if file doesn't exist
@@ -184,6 +361,16 @@ func createDefaultConfig() *distribution.DistributionConfig {
 						"include_source":    false,
 					},
 				},
+				Signing: distribution.SigningConfig{
+					GPG: distribution.GPGConfig{
+						Enabled: false,
+					},
+					Cosign: distribution.CosignConfig{
+						Enabled:        false,
+						IdentityRegexp: "^https://github.com/nettracex/nettracex-tui/.github/workflows/release.yml@refs/tags/v",
+						OIDCIssuer:     "https://token.actions.githubusercontent.com",
+					},
+				},
 			},
 			"gomodule": {
 				Enabled:    true,
@@ -211,6 +398,7 @@ func createDefaultConfig() *distribution.DistributionConfig {
 			"homebrew": {
 				Enabled:    true,
 				Priority:   3,
+				DependsOn:  []string{"github"},
 				Timeout:    120 * time.Second,
 				RetryCount: 2,
 				Config: map[string]interface{}{
@@ -221,9 +409,145 @@ func createDefaultConfig() *distribution.DistributionConfig {
 					"license":      "MIT",
 					"custom_tap":   true,
 					"test_command": `"--version"`,
+					"head_url":     "https://github.com/nettracex/nettracex-tui.git",
 					"dependencies": []string{},
 				},
 			},
+			"scoop": {
+				Enabled:    false,
+				Priority:   4,
+				DependsOn:  []string{"github"},
+				Timeout:    60 * time.Second,
+				RetryCount: 2,
+				Config: map[string]interface{}{
+					"bucket_repo":   "nettracex/scoop-bucket",
+					"manifest_name": "nettracex",
+					"manifest_dir":  "bucket",
+					"branch":        "main",
+					"token":         "${GITHUB_TOKEN}",
+					"description":   "Network diagnostic toolkit with beautiful TUI",
+					"homepage":      "https://github.com/nettracex/nettracex-tui",
+					"license":       "MIT",
+					"release_repo":  "nettracex/nettracex-tui",
+				},
+			},
+			"aur": {
+				Enabled:    false,
+				Priority:   7,
+				DependsOn:  []string{"github"},
+				Timeout:    120 * time.Second,
+				RetryCount: 2,
+				Config: map[string]interface{}{
+					"package_name":     "nettracex-bin",
+					"maintainer":       "NetTraceX <packages@nettracex.dev>",
+					"description":      "Network diagnostic toolkit with beautiful TUI",
+					"homepage":         "https://github.com/nettracex/nettracex-tui",
+					"license":          "MIT",
+					"optdepends":       []string{"traceroute: traceroute fallback"},
+					"ssh_key":          "${AUR_SSH_KEY}",
+					"release_repo":     "nettracex/nettracex-tui",
+					"validate_makepkg": true,
+				},
+			},
+			"nix": {
+				Enabled:    false,
+				Priority:   8,
+				DependsOn:  []string{"github"},
+				Timeout:    600 * time.Second,
+				RetryCount: 1,
+				Config: map[string]interface{}{
+					"repo":           "git@github.com:nettracex/nix-packages.git",
+					"branch":         "main",
+					"package_name":   "nettracex",
+					"description":    "Network diagnostic toolkit with beautiful TUI",
+					"homepage":       "https://github.com/nettracex/nettracex-tui",
+					"license":        "mit",
+					"author":         "NetTraceX <packages@nettracex.dev>",
+					"ssh_key":        "${NIX_REPO_SSH_KEY}",
+					"release_repo":   "nettracex/nettracex-tui",
+					"validate_build": true,
+				},
+			},
+			"flatpak": {
+				Enabled:    false,
+				Priority:   9,
+				DependsOn:  []string{"github"},
+				Timeout:    120 * time.Second,
+				RetryCount: 2,
+				Config: map[string]interface{}{
+					"app_id":       "io.github.nettracex.NetTraceX",
+					"summary":      "Network diagnostic toolkit",
+					"description":  "Network diagnostic toolkit with beautiful TUI",
+					"homepage":     "https://github.com/nettracex/nettracex-tui",
+					"license":      "MIT",
+					"developer":    "NetTraceX",
+					"finish_args":  []string{"--share=network"},
+					"repo":         "flathub/io.github.nettracex.NetTraceX",
+					"branch":       "master",
+					"token":        "${FLATHUB_TOKEN}",
+					"release_repo": "nettracex/nettracex-tui",
+					"pull_request": true,
+				},
+			},
+			"winget": {
+				Enabled:    false,
+				Priority:   9,
+				DependsOn:  []string{"github"},
+				Timeout:    120 * time.Second,
+				RetryCount: 2,
+				Config: map[string]interface{}{
+					"package_identifier": "NetTraceX.NetTraceX",
+					"publisher":          "NetTraceX",
+					"package_name":       "NetTraceX",
+					"short_description":  "Network diagnostic toolkit with beautiful TUI",
+					"homepage":           "https://github.com/nettracex/nettracex-tui",
+					"license":            "MIT",
+					"tags":               []string{"network", "diagnostics", "traceroute", "tui"},
+					"repo":               "microsoft/winget-pkgs",
+					"token":              "${WINGET_GITHUB_TOKEN}",
+					"release_repo":       "nettracex/nettracex-tui",
+				},
+			},
+			"apt": {
+				Enabled:    false,
+				Priority:   5,
+				Timeout:    300 * time.Second,
+				RetryCount: 2,
+				Config: map[string]interface{}{
+					"url":          "${APT_REPO_URL}",
+					"distribution": "stable",
+					"component":    "main",
+					"username":     "${PACKAGE_REPO_USER}",
+					"password":     "${PACKAGE_REPO_PASSWORD}",
+				},
+			},
+			"yum": {
+				Enabled:    false,
+				Priority:   6,
+				Timeout:    300 * time.Second,
+				RetryCount: 2,
+				Config: map[string]interface{}{
+					"url":      "${YUM_REPO_URL}",
+					"username": "${PACKAGE_REPO_USER}",
+					"password": "${PACKAGE_REPO_PASSWORD}",
+				},
+			},
+			"s3": {
+				Enabled:    false,
+				Priority:   10,
+				Timeout:    600 * time.Second,
+				RetryCount: 2,
+				Config: map[string]interface{}{
+					"endpoint":   "${S3_ENDPOINT}",
+					"region":     "us-east-1",
+					"bucket":     "${S3_BUCKET}",
+					"prefix":     "releases",
+					"access_key": "${S3_ACCESS_KEY_ID}",
+					"secret_key": "${S3_SECRET_ACCESS_KEY}",
+					"path_style": false,
+					"latest":     true,
+				},
+			},
 		},
 		Validators: map[string]distribution.ValidatorConfig{
 			"github": {
@@ -232,6 +556,7 @@ func createDefaultConfig() *distribution.DistributionConfig {
 					"check_assets":     true,
 					"check_changelog":  true,
 					"check_tag":        true,
+					"require_sbom":     false,
 					"required_assets":  []string{"linux", "windows", "darwin"},
 				},
 			},
@@ -259,6 +584,9 @@ func createDefaultConfig() *distribution.DistributionConfig {
 			Multiplier: 2.0,
 		},
 		ConcurrentLimit: 2,
+		ReleaseNotes: distribution.ReleaseNotesConfig{
+			DownloadURL: "https://github.com/nettracex/nettracex-tui/releases/download/{tag}/{file}",
+		},
 	}
 }
 
@@ -350,6 +678,8 @@ func setupPublishers(coordinator *distribution.DistributionCoordinator, config *
 			License:     getStringFromConfig(publisherConfig.Config, "license", "MIT"),
 			CustomTap:   getBoolFromConfig(publisherConfig.Config, "custom_tap", true),
 			TestCommand: getStringFromConfig(publisherConfig.Config, "test_command", `"--version"`),
+			HeadURL:     getStringFromConfig(publisherConfig.Config, "head_url", ""),
+			HeadBranch:  getStringFromConfig(publisherConfig.Config, "head_branch", ""),
 		}
 
 		// Setup dependencies
@@ -371,9 +701,194 @@ func setupPublishers(coordinator *distribution.DistributionCoordinator, config *
 		}
 	}
 
+	// Setup Scoop publisher
+	if publisherConfig, exists := config.Publishers["scoop"]; exists && publisherConfig.Enabled {
+		publisher := distribution.NewScoopPublisher(scoopConfigFrom(publisherConfig.Config, distribution.ScoopConfig{License: "MIT"}))
+		if err := coordinator.RegisterPublisher(publisher); err != nil {
+			return err
+		}
+	}
+
+	// Setup AUR publisher
+	if publisherConfig, exists := config.Publishers["aur"]; exists && publisherConfig.Enabled {
+		publisher := distribution.NewAURPublisher(aurConfigFrom(publisherConfig.Config, distribution.AURConfig{License: "MIT"}))
+		if err := coordinator.RegisterPublisher(publisher); err != nil {
+			return err
+		}
+	}
+
+	// Setup Nix publisher
+	if publisherConfig, exists := config.Publishers["nix"]; exists && publisherConfig.Enabled {
+		publisher := distribution.NewNixPublisher(nixConfigFrom(publisherConfig.Config, distribution.NixConfig{}))
+		if err := coordinator.RegisterPublisher(publisher); err != nil {
+			return err
+		}
+	}
+
+	// Setup Flatpak publisher
+	if publisherConfig, exists := config.Publishers["flatpak"]; exists && publisherConfig.Enabled {
+		publisher := distribution.NewFlatpakPublisher(flatpakConfigFrom(publisherConfig.Config, distribution.FlatpakConfig{}))
+		if err := coordinator.RegisterPublisher(publisher); err != nil {
+			return err
+		}
+	}
+
+	// Setup winget publisher
+	if publisherConfig, exists := config.Publishers["winget"]; exists && publisherConfig.Enabled {
+		publisher := distribution.NewWingetPublisher(wingetConfigFrom(publisherConfig.Config))
+		if err := coordinator.RegisterPublisher(publisher); err != nil {
+			return err
+		}
+	}
+
+	// Setup apt and yum repository publishers
+	for _, repoType := range []string{"apt", "yum"} {
+		publisherConfig, exists := config.Publishers[repoType]
+		if !exists || !publisherConfig.Enabled {
+			continue
+		}
+		publisher, err := distribution.NewPackageRepositoryPublisher(distribution.PackageRepositoryConfig{
+			Type:         repoType,
+			URL:          expandEnvVars(getStringFromConfig(publisherConfig.Config, "url", "")),
+			Distribution: getStringFromConfig(publisherConfig.Config, "distribution", ""),
+			Component:    getStringFromConfig(publisherConfig.Config, "component", ""),
+			Username:     expandEnvVars(getStringFromConfig(publisherConfig.Config, "username", "")),
+			Password:     expandEnvVars(getStringFromConfig(publisherConfig.Config, "password", "")),
+			Token:        expandEnvVars(getStringFromConfig(publisherConfig.Config, "token", "")),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create %s publisher: %w", repoType, err)
+		}
+		if err := coordinator.RegisterPublisher(publisher); err != nil {
+			return err
+		}
+	}
+
+	// Setup object storage publisher
+	if publisherConfig, exists := config.Publishers["s3"]; exists && publisherConfig.Enabled {
+		publisher, err := distribution.NewS3Publisher(distribution.S3Config{
+			Endpoint:     expandEnvVars(getStringFromConfig(publisherConfig.Config, "endpoint", "")),
+			Region:       getStringFromConfig(publisherConfig.Config, "region", ""),
+			Bucket:       expandEnvVars(getStringFromConfig(publisherConfig.Config, "bucket", "")),
+			Prefix:       getStringFromConfig(publisherConfig.Config, "prefix", ""),
+			AccessKey:    expandEnvVars(getStringFromConfig(publisherConfig.Config, "access_key", "")),
+			SecretKey:    expandEnvVars(getStringFromConfig(publisherConfig.Config, "secret_key", "")),
+			SessionToken: expandEnvVars(getStringFromConfig(publisherConfig.Config, "session_token", "")),
+			PathStyle:    getBoolFromConfig(publisherConfig.Config, "path_style", false),
+			ACL:          getStringFromConfig(publisherConfig.Config, "acl", ""),
+			PublicURL:    getStringFromConfig(publisherConfig.Config, "public_url", ""),
+			Latest:       getBoolFromConfig(publisherConfig.Config, "latest", false),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create s3 publisher: %w", err)
+		}
+		if err := coordinator.RegisterPublisher(publisher); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// scoopConfigFrom reads the Scoop publisher settings over defaults
+func scoopConfigFrom(config map[string]interface{}, defaults distribution.ScoopConfig) distribution.ScoopConfig {
+	return distribution.ScoopConfig{
+		BucketRepo:   getStringFromConfig(config, "bucket_repo", defaults.BucketRepo),
+		ManifestName: getStringFromConfig(config, "manifest_name", defaults.ManifestName),
+		ManifestDir:  getStringFromConfig(config, "manifest_dir", defaults.ManifestDir),
+		Branch:       getStringFromConfig(config, "branch", defaults.Branch),
+		GitHubToken:  expandEnvVars(getStringFromConfig(config, "token", defaults.GitHubToken)),
+		BaseURL:      getStringFromConfig(config, "base_url", defaults.BaseURL),
+		Description:  getStringFromConfig(config, "description", defaults.Description),
+		Homepage:     getStringFromConfig(config, "homepage", defaults.Homepage),
+		License:      getStringFromConfig(config, "license", defaults.License),
+		ReleaseRepo:  getStringFromConfig(config, "release_repo", defaults.ReleaseRepo),
+	}
+}
+
+// aurConfigFrom reads the AUR publisher settings over defaults
+func aurConfigFrom(config map[string]interface{}, defaults distribution.AURConfig) distribution.AURConfig {
+	return distribution.AURConfig{
+		PackageName:     getStringFromConfig(config, "package_name", defaults.PackageName),
+		Binary:          getStringFromConfig(config, "binary", defaults.Binary),
+		Maintainer:      getStringFromConfig(config, "maintainer", defaults.Maintainer),
+		Description:     getStringFromConfig(config, "description", defaults.Description),
+		Homepage:        getStringFromConfig(config, "homepage", defaults.Homepage),
+		License:         getStringFromConfig(config, "license", defaults.License),
+		Depends:         getStringsFromConfig(config, "depends", defaults.Depends),
+		OptDepends:      getStringsFromConfig(config, "optdepends", defaults.OptDepends),
+		GitURL:          getStringFromConfig(config, "git_url", defaults.GitURL),
+		SSHKey:          expandEnvVars(getStringFromConfig(config, "ssh_key", defaults.SSHKey)),
+		ReleaseRepo:     getStringFromConfig(config, "release_repo", defaults.ReleaseRepo),
+		ValidateMakepkg: getBoolFromConfig(config, "validate_makepkg", defaults.ValidateMakepkg),
+	}
+}
+
+// nixConfigFrom reads the Nix publisher settings over defaults
+func nixConfigFrom(config map[string]interface{}, defaults distribution.NixConfig) distribution.NixConfig {
+	return distribution.NixConfig{
+		Repo:          getStringFromConfig(config, "repo", defaults.Repo),
+		Branch:        getStringFromConfig(config, "branch", defaults.Branch),
+		Dir:           getStringFromConfig(config, "dir", defaults.Dir),
+		PackageName:   getStringFromConfig(config, "package_name", defaults.PackageName),
+		Description:   getStringFromConfig(config, "description", defaults.Description),
+		Homepage:      getStringFromConfig(config, "homepage", defaults.Homepage),
+		License:       getStringFromConfig(config, "license", defaults.License),
+		Nixpkgs:       getStringFromConfig(config, "nixpkgs", defaults.Nixpkgs),
+		Author:        getStringFromConfig(config, "author", defaults.Author),
+		SSHKey:        expandEnvVars(getStringFromConfig(config, "ssh_key", defaults.SSHKey)),
+		ReleaseRepo:   getStringFromConfig(config, "release_repo", defaults.ReleaseRepo),
+		ValidateBuild: getBoolFromConfig(config, "validate_build", defaults.ValidateBuild),
+	}
+}
+
+// flatpakConfigFrom reads the Flatpak publisher settings over defaults
+func flatpakConfigFrom(config map[string]interface{}, defaults distribution.FlatpakConfig) distribution.FlatpakConfig {
+	return distribution.FlatpakConfig{
+		AppID:          getStringFromConfig(config, "app_id", defaults.AppID),
+		Command:        getStringFromConfig(config, "command", defaults.Command),
+		Name:           getStringFromConfig(config, "name", defaults.Name),
+		Summary:        getStringFromConfig(config, "summary", defaults.Summary),
+		Description:    getStringFromConfig(config, "description", defaults.Description),
+		Homepage:       getStringFromConfig(config, "homepage", defaults.Homepage),
+		License:        getStringFromConfig(config, "license", defaults.License),
+		Developer:      getStringFromConfig(config, "developer", defaults.Developer),
+		Runtime:        getStringFromConfig(config, "runtime", defaults.Runtime),
+		RuntimeVersion: getStringFromConfig(config, "runtime_version", defaults.RuntimeVersion),
+		SDK:            getStringFromConfig(config, "sdk", defaults.SDK),
+		FinishArgs:     getStringsFromConfig(config, "finish_args", defaults.FinishArgs),
+		Repo:           getStringFromConfig(config, "repo", defaults.Repo),
+		Branch:         getStringFromConfig(config, "branch", defaults.Branch),
+		GitHubToken:    expandEnvVars(getStringFromConfig(config, "token", defaults.GitHubToken)),
+		BaseURL:        getStringFromConfig(config, "base_url", defaults.BaseURL),
+		ReleaseRepo:    getStringFromConfig(config, "release_repo", defaults.ReleaseRepo),
+		PullRequest:    getBoolFromConfig(config, "pull_request", defaults.PullRequest),
+	}
+}
+
+// wingetConfigFrom reads the winget publisher settings
+func wingetConfigFrom(config map[string]interface{}) distribution.WingetConfig {
+	return distribution.WingetConfig{
+		PackageIdentifier: getStringFromConfig(config, "package_identifier", ""),
+		Publisher:         getStringFromConfig(config, "publisher", ""),
+		PublisherURL:      getStringFromConfig(config, "publisher_url", ""),
+		PackageName:       getStringFromConfig(config, "package_name", ""),
+		Command:           getStringFromConfig(config, "command", ""),
+		License:           getStringFromConfig(config, "license", ""),
+		LicenseURL:        getStringFromConfig(config, "license_url", ""),
+		ShortDescription:  getStringFromConfig(config, "short_description", ""),
+		Description:       getStringFromConfig(config, "description", ""),
+		Homepage:          getStringFromConfig(config, "homepage", ""),
+		Moniker:           getStringFromConfig(config, "moniker", ""),
+		Tags:              getStringsFromConfig(config, "tags", nil),
+		Repo:              getStringFromConfig(config, "repo", ""),
+		Branch:            getStringFromConfig(config, "branch", ""),
+		GitHubToken:       expandEnvVars(getStringFromConfig(config, "token", "")),
+		BaseURL:           getStringFromConfig(config, "base_url", ""),
+		ReleaseRepo:       getStringFromConfig(config, "release_repo", ""),
+	}
+}
+
 // setupValidators registers validators with the coordinator
 func setupValidators(coordinator *distribution.DistributionCoordinator, config *distribution.DistributionConfig) error {
 	// Setup GitHub validator
@@ -382,6 +897,7 @@ func setupValidators(coordinator *distribution.DistributionCoordinator, config *
 			CheckAssets:    getBoolFromConfig(validatorConfig.Config, "check_assets", true),
 			CheckChangelog: getBoolFromConfig(validatorConfig.Config, "check_changelog", true),
 			CheckTag:       getBoolFromConfig(validatorConfig.Config, "check_tag", true),
+			RequireSBOM:    getBoolFromConfig(validatorConfig.Config, "require_sbom", false),
 		}
 
 		if requiredAssets, ok := validatorConfig.Config["required_assets"].([]interface{}); ok {
@@ -420,10 +936,13 @@ func setupValidators(coordinator *distribution.DistributionCoordinator, config *
 // createRelease creates a release from the binary directory
 func createRelease(version, tag, binDir string) (*distribution.Release, error) {
 	release := &distribution.Release{
-		Version:   version,
-		Tag:       tag,
-		Binaries:  make(map[string]distribution.Binary),
-		Checksums: make(map[string]string),
+		Version:       version,
+		Tag:           tag,
+		Binaries:      make(map[string]distribution.Binary),
+		Checksums:     make(map[string]string),
+		ChecksumFiles: distribution.ReleaseChecksumFiles(binDir),
+		SBOMs:         make(map[string][]string),
+		Packages:      make(map[string]distribution.Binary),
 		Metadata: distribution.ReleaseMetadata{
 			CreatedAt:    time.Now(),
 			IsPrerelease: false,
@@ -437,7 +956,7 @@ func createRelease(version, tag, binDir string) (*distribution.Release, error) {
 	}
 
 	for _, entry := range entries {
-		if entry.IsDir() {
+		if entry.IsDir() || isChecksumFile(entry.Name()) || distribution.IsSBOMFile(entry.Name()) {
 			continue
 		}
 
@@ -461,14 +980,29 @@ func createRelease(version, tag, binDir string) (*distribution.Release, error) {
 		}
 
 		// Calculate checksum
-		checksum, err := calculateChecksum(filePath)
+		checksum, err := calculateChecksum(filePath, distribution.SHA256)
 		if err != nil {
 			return nil, fmt.Errorf("failed to calculate checksum for %s: %w", filename, err)
 		}
 
 		binary.Checksum = checksum
-		release.Binaries[filename] = binary
 		release.Checksums[filename] = checksum
+
+		// Packages of the Linux binaries are published apart from them
+		if distribution.IsPackageFile(filename) {
+			binary.Platform = "linux"
+			binary.Architecture = distribution.PackageGoArch(filename)
+			release.Packages[filename] = binary
+			continue
+		}
+		release.Binaries[filename] = binary
+
+		// Attach the SBOMs written for the binary
+		for _, format := range []distribution.SBOMFormat{distribution.SPDX, distribution.CycloneDX} {
+			if sbom := filePath + format.Suffix(); fileExists(sbom) {
+				release.SBOMs[filename] = append(release.SBOMs[filename], sbom)
+			}
+		}
 	}
 
 	return release, nil
@@ -483,6 +1017,24 @@ func getStringFromConfig(config map[string]interface{}, key, defaultValue string
 	return defaultValue
 }
 
+// getStringsFromConfig reads a list of strings, as decoded from JSON or
+// set in the default configuration
+func getStringsFromConfig(config map[string]interface{}, key string, defaultValue []string) []string {
+	switch values := config[key].(type) {
+	case []string:
+		return values
+	case []interface{}:
+		var strs []string
+		for _, value := range values {
+			if str, ok := value.(string); ok {
+				strs = append(strs, str)
+			}
+		}
+		return strs
+	}
+	return defaultValue
+}
+
 func getBoolFromConfig(config map[string]interface{}, key string, defaultValue bool) bool {
 	if value, ok := config[key].(bool); ok {
 		return value
@@ -497,6 +1049,26 @@ func getFloatFromConfig(config map[string]interface{}, key string, defaultValue
 	return defaultValue
 }
 
+// notificationChannelsFrom configures the selected notification channels,
+// expanding environment variables in their settings
+func notificationChannelsFrom(notifications distribution.NotificationConfig) map[string]distribution.NotificationChannelConfig {
+	channels := make(map[string]distribution.NotificationChannelConfig)
+	for _, name := range notifications.Channels {
+		channel := notifications.Settings[name]
+		channel.Enabled = true
+		settings := make(map[string]interface{}, len(channel.Config))
+		for key, value := range channel.Config {
+			if str, ok := value.(string); ok {
+				value = expandEnvVars(str)
+			}
+			settings[key] = value
+		}
+		channel.Config = settings
+		channels[name] = channel
+	}
+	return channels
+}
+
 func expandEnvVars(value string) string {
 	return os.ExpandEnv(value)
 }
@@ -526,10 +1098,111 @@ func parsePlatformArch(filename string) (platform, arch string) {
 	return platform, arch
 }
 
-func calculateChecksum(filePath string) (string, error) {
-	// This would typically use SHA256 or similar
-	// For now, return a placeholder
-	return "sha256:placeholder", nil
+// calculateChecksum returns the hex digest of a file, as sha256sum prints it
+func calculateChecksum(filePath string, algorithm distribution.ChecksumAlgorithm) (string, error) {
+	return distribution.FileChecksum(filePath, algorithm)
+}
+
+// isChecksumFile reports whether a file of the bin directory is a checksum
+// file or a signature rather than a release binary
+func isChecksumFile(filename string) bool {
+	return filename == distribution.ChecksumsFile || filename == distribution.SHA512ChecksumsFile ||
+		distribution.IsSignatureFile(filename)
+}
+
+// writeChecksumFiles writes the SHA-256 checksums of the release binaries to
+// binDir, and their SHA-512 checksums when withSHA512 is set
+func writeChecksumFiles(binDir string, release *distribution.Release, withSHA512 bool) ([]string, error) {
+	path := filepath.Join(binDir, distribution.ChecksumsFile)
+	if err := distribution.WriteChecksumsFile(path, release.Checksums); err != nil {
+		return nil, err
+	}
+	files := []string{path}
+
+	if withSHA512 {
+		sums := make(map[string]string, len(release.Checksums))
+		for _, files := range []map[string]distribution.Binary{release.Binaries, release.Packages} {
+			for filename, binary := range files {
+				checksum, err := calculateChecksum(binary.FilePath, distribution.SHA512)
+				if err != nil {
+					return nil, fmt.Errorf("failed to calculate checksum for %s: %w", filename, err)
+				}
+				sums[filename] = checksum
+			}
+		}
+		path := filepath.Join(binDir, distribution.SHA512ChecksumsFile)
+		if err := distribution.WriteChecksumsFile(path, sums); err != nil {
+			return nil, err
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+// verifyChecksumFiles checks the binaries of binDir against the checksum
+// files found there
+func verifyChecksumFiles(binDir string) error {
+	found := false
+	for _, name := range []string{distribution.ChecksumsFile, distribution.SHA512ChecksumsFile} {
+		path := filepath.Join(binDir, name)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		found = true
+		verified, err := distribution.VerifyChecksumsFile(path, binDir)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Verified %d files against %s\n", verified, name)
+	}
+	if !found {
+		fmt.Printf("No checksum files in %s, run -command checksums to write them\n", binDir)
+	}
+	return nil
+}
+
+// writeSBOMFiles writes an SBOM of each release binary in each format,
+// recording the module dependencies and versions it was built with
+func writeSBOMFiles(release *distribution.Release, formats []distribution.SBOMFormat) ([]string, error) {
+	var files []string
+	created := time.Now()
+	for filename, binary := range release.Binaries {
+		written, err := distribution.WriteSBOMFiles(binary.FilePath, filename, release.Version, formats, created)
+		if err != nil {
+			return nil, err
+		}
+		release.SBOMs[filename] = written
+		files = append(files, written...)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// fileExists reports whether a file exists at path
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// verifySignatures checks the signatures of the release files for each
+// enabled publisher that signs them
+func verifySignatures(ctx context.Context, release distribution.Release, config *distribution.DistributionConfig) error {
+	for name, publisherConfig := range config.Publishers {
+		if !publisherConfig.Enabled || !publisherConfig.Signing.Enabled() {
+			continue
+		}
+		signers, err := distribution.NewSigners(publisherConfig.Signing)
+		if err != nil {
+			return fmt.Errorf("publisher %s: %w", name, err)
+		}
+		if err := distribution.VerifyRelease(ctx, release, signers); err != nil {
+			return fmt.Errorf("publisher %s: %w", name, err)
+		}
+		for _, signer := range signers {
+			fmt.Printf("Verified %s signatures for %s\n", signer.GetName(), name)
+		}
+	}
+	return nil
 }
 
 // generateHomebrewFormula generates a Homebrew formula for the specified version
@@ -561,6 +1234,8 @@ func generateHomebrewFormula(version, binaryURL, outputFile string, config *dist
 		homebrewConfig.Homepage = getStringFromConfig(publisherConfig.Config, "homepage", homebrewConfig.Homepage)
 		homebrewConfig.License = getStringFromConfig(publisherConfig.Config, "license", homebrewConfig.License)
 		homebrewConfig.TestCommand = getStringFromConfig(publisherConfig.Config, "test_command", homebrewConfig.TestCommand)
+		homebrewConfig.HeadURL = getStringFromConfig(publisherConfig.Config, "head_url", homebrewConfig.HeadURL)
+		homebrewConfig.HeadBranch = getStringFromConfig(publisherConfig.Config, "head_branch", homebrewConfig.HeadBranch)
 	}
 
 	// Create publisher
@@ -569,20 +1244,36 @@ func generateHomebrewFormula(version, binaryURL, outputFile string, config *dist
 		return fmt.Errorf("failed to create Homebrew publisher: %w", err)
 	}
 
-	// Create release with the provided binary URL
-	release := distribution.Release{
-		Version: version,
-		Tag:     version,
-		Binaries: map[string]distribution.Binary{
-			"darwin-amd64": {
-				Platform:     "darwin",
-				Architecture: "amd64",
-				Filename:     homebrewConfig.FormulaName,
-				DownloadURL:  binaryURL,
-				Checksum:     "", // Will be calculated by the publisher
-			},
+	// Create release with the provided binary URL; a URL with {platform}
+	// and {arch} gives a download for each macOS and Linux architecture
+	binaries := map[string]distribution.Binary{
+		"darwin-amd64": {
+			Platform:     "darwin",
+			Architecture: "amd64",
+			Filename:     homebrewConfig.FormulaName,
+			DownloadURL:  binaryURL,
+			Checksum:     "", // Will be calculated by the publisher
 		},
 	}
+	if strings.Contains(binaryURL, "{platform}") {
+		binaries = make(map[string]distribution.Binary)
+		for _, platform := range []string{"darwin", "linux"} {
+			for _, arch := range []string{"amd64", "arm64"} {
+				url := strings.NewReplacer("{platform}", platform, "{arch}", arch).Replace(binaryURL)
+				binaries[platform+"-"+arch] = distribution.Binary{
+					Platform:     platform,
+					Architecture: arch,
+					Filename:     url[strings.LastIndex(url, "/")+1:],
+					DownloadURL:  url,
+				}
+			}
+		}
+	}
+	release := distribution.Release{
+		Version:  version,
+		Tag:      version,
+		Binaries: binaries,
+	}
 
 	// Generate formula
 	formula, err := publisher.GenerateFormula(release)
@@ -609,4 +1300,246 @@ func generateHomebrewFormula(version, binaryURL, outputFile string, config *dist
 
 	fmt.Printf("Homebrew formula written to: %s\n", outputFile)
 	return nil
-}
\ No newline at end of file
+}
+
+// generateScoopManifest generates a Scoop manifest for the specified version
+func generateScoopManifest(version, binaryURL, outputFile string, config *distribution.DistributionConfig) error {
+	if binaryURL == "" {
+		return fmt.Errorf("binary URL is required for Scoop manifest generation")
+	}
+
+	scoopConfig := distribution.ScoopConfig{
+		BucketRepo:   "nettracex/scoop-bucket",
+		ManifestName: "nettracex",
+		Description:  "Network diagnostic toolkit with beautiful TUI",
+		Homepage:     "https://github.com/nettracex/nettracex-tui",
+		License:      "MIT",
+		ReleaseRepo:  "nettracex/nettracex-tui",
+	}
+
+	// Override with config values if available
+	if publisherConfig, exists := config.Publishers["scoop"]; exists {
+		scoopConfig = scoopConfigFrom(publisherConfig.Config, scoopConfig)
+	}
+
+	if outputFile == "" {
+		outputFile = filepath.Join("scoop-bucket", scoopConfig.ManifestName+".json")
+	}
+
+	publisher := distribution.NewScoopPublisher(scoopConfig)
+
+	// Create release with the provided binary URL
+	release := distribution.Release{
+		Version: version,
+		Tag:     version,
+		Binaries: map[string]distribution.Binary{
+			"windows-amd64": {
+				Platform:     "windows",
+				Architecture: "amd64",
+				Filename:     filepath.Base(binaryURL),
+				DownloadURL:  binaryURL,
+				Checksum:     "", // Will be calculated by the publisher
+			},
+		},
+	}
+
+	manifest, err := publisher.GenerateManifest(release)
+	if err != nil {
+		return fmt.Errorf("failed to generate manifest: %w", err)
+	}
+
+	if err := distribution.ValidateScoopManifest(manifest); err != nil {
+		return fmt.Errorf("manifest validation failed: %w", err)
+	}
+
+	content, err := publisher.RenderManifest(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to render manifest: %w", err)
+	}
+
+	// Create output directory
+	if err := os.MkdirAll(filepath.Dir(outputFile), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if err := os.WriteFile(outputFile, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write manifest file: %w", err)
+	}
+
+	fmt.Printf("Scoop manifest written to: %s\n", outputFile)
+	return nil
+}
+
+// generateAURPackage writes the PKGBUILD and .SRCINFO of a Linux binary for
+// the specified version
+func generateAURPackage(version, binaryURL, outputDir string, config *distribution.DistributionConfig) error {
+	if binaryURL == "" {
+		return fmt.Errorf("binary URL is required for AUR package generation")
+	}
+
+	aurConfig := distribution.AURConfig{
+		PackageName: "nettracex-bin",
+		Maintainer:  "NetTraceX <packages@nettracex.dev>",
+		Description: "Network diagnostic toolkit with beautiful TUI",
+		Homepage:    "https://github.com/nettracex/nettracex-tui",
+		License:     "MIT",
+	}
+
+	// Override with config values if available
+	if publisherConfig, exists := config.Publishers["aur"]; exists {
+		aurConfig = aurConfigFrom(publisherConfig.Config, aurConfig)
+	}
+
+	if outputDir == "" {
+		outputDir = filepath.Join("aur", aurConfig.PackageName)
+	}
+
+	publisher := distribution.NewAURPublisher(aurConfig)
+
+	// Create release with the provided binary URL
+	_, arch := parsePlatformArch(filepath.Base(binaryURL))
+	if arch == "unknown" {
+		arch = "amd64"
+	}
+	release := distribution.Release{
+		Version: version,
+		Tag:     version,
+		Binaries: map[string]distribution.Binary{
+			"linux-" + arch: {
+				Platform:     "linux",
+				Architecture: arch,
+				Filename:     filepath.Base(binaryURL),
+				DownloadURL:  binaryURL,
+				Checksum:     "", // Will be calculated by the publisher
+			},
+		},
+	}
+
+	pkg, err := publisher.GeneratePackage(release)
+	if err != nil {
+		return fmt.Errorf("failed to generate package: %w", err)
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if err := publisher.WritePackage(context.Background(), pkg, outputDir); err != nil {
+		return fmt.Errorf("failed to write package: %w", err)
+	}
+
+	fmt.Printf("PKGBUILD and .SRCINFO written to: %s\n", outputDir)
+	return nil
+}
+
+// generateNixPackage writes the default.nix and flake.nix of a Linux or
+// macOS binary for the specified version
+func generateNixPackage(version, binaryURL, outputDir string, config *distribution.DistributionConfig) error {
+	if binaryURL == "" {
+		return fmt.Errorf("binary URL is required for Nix package generation")
+	}
+
+	nixConfig := distribution.NixConfig{
+		Description: "Network diagnostic toolkit with beautiful TUI",
+		Homepage:    "https://github.com/nettracex/nettracex-tui",
+	}
+
+	// Override with config values if available
+	if publisherConfig, exists := config.Publishers["nix"]; exists {
+		nixConfig = nixConfigFrom(publisherConfig.Config, nixConfig)
+	}
+
+	if outputDir == "" {
+		outputDir = "nix"
+	}
+
+	// Create release with the provided binary URL
+	platform, arch := parsePlatformArch(filepath.Base(binaryURL))
+	if platform == "unknown" {
+		platform = "linux"
+	}
+	if arch == "unknown" {
+		arch = "amd64"
+	}
+	release := distribution.Release{
+		Version: version,
+		Tag:     version,
+		Binaries: map[string]distribution.Binary{
+			platform + "-" + arch: {
+				Platform:     platform,
+				Architecture: arch,
+				Filename:     filepath.Base(binaryURL),
+				DownloadURL:  binaryURL,
+				Checksum:     "", // Will be calculated by the publisher
+			},
+		},
+	}
+
+	publisher := distribution.NewNixPublisher(nixConfig)
+	pkg, err := publisher.GeneratePackage(release)
+	if err != nil {
+		return fmt.Errorf("failed to generate package: %w", err)
+	}
+
+	if err := publisher.WritePackage(context.Background(), pkg, outputDir); err != nil {
+		return fmt.Errorf("failed to write package: %w", err)
+	}
+
+	fmt.Printf("default.nix and flake.nix written to: %s\n", outputDir)
+	return nil
+}
+
+// generateFlatpakManifest writes the flathub manifest and metainfo of a
+// Linux binary for the specified version
+func generateFlatpakManifest(version, binaryURL, outputDir string, config *distribution.DistributionConfig) error {
+	if binaryURL == "" {
+		return fmt.Errorf("binary URL is required for Flatpak manifest generation")
+	}
+
+	flatpakConfig := distribution.FlatpakConfig{
+		Summary:     "Network diagnostic toolkit",
+		Description: "Network diagnostic toolkit with beautiful TUI",
+		Homepage:    "https://github.com/nettracex/nettracex-tui",
+	}
+
+	// Override with config values if available
+	if publisherConfig, exists := config.Publishers["flatpak"]; exists {
+		flatpakConfig = flatpakConfigFrom(publisherConfig.Config, flatpakConfig)
+	}
+
+	if outputDir == "" {
+		outputDir = "flatpak"
+	}
+
+	// Create release with the provided binary URL
+	_, arch := parsePlatformArch(filepath.Base(binaryURL))
+	if arch == "unknown" {
+		arch = "amd64"
+	}
+	release := distribution.Release{
+		Version: version,
+		Tag:     version,
+		Binaries: map[string]distribution.Binary{
+			"linux-" + arch: {
+				Platform:     "linux",
+				Architecture: arch,
+				Filename:     filepath.Base(binaryURL),
+				DownloadURL:  binaryURL,
+				Checksum:     "", // Will be calculated by the publisher
+			},
+		},
+	}
+
+	publisher := distribution.NewFlatpakPublisher(flatpakConfig)
+	pkg, err := publisher.GeneratePackage(release)
+	if err != nil {
+		return fmt.Errorf("failed to generate manifest: %w", err)
+	}
+
+	if err := publisher.WritePackage(pkg, outputDir); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	fmt.Printf("Flatpak manifest written to: %s\n", outputDir)
+	return nil
+}
@@ -0,0 +1,245 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/agent/agentpb"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// testLogger implements domain.Logger and discards all output
+type testLogger struct{}
+
+func (testLogger) Debug(msg string, fields ...interface{}) {}
+func (testLogger) Info(msg string, fields ...interface{})  {}
+func (testLogger) Warn(msg string, fields ...interface{})  {}
+func (testLogger) Error(msg string, fields ...interface{}) {}
+func (testLogger) Fatal(msg string, fields ...interface{}) {}
+
+// pingTool answers with one reply whose TTL identifies the vantage point,
+// after checking the parameters arrived with their Go types
+type pingTool struct {
+	ttl int
+	err error
+}
+
+func (t *pingTool) Name() string                            { return "ping" }
+func (t *pingTool) Description() string                     { return "stub ping" }
+func (t *pingTool) Validate(params domain.Parameters) error { return nil }
+func (t *pingTool) GetModel() tea.Model                     { return nil }
+
+func (t *pingTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	host, _ := params.Get("host").(string)
+	count, ok := params.Get("count").(int)
+	if !ok {
+		return nil, errors.New("count is not an int")
+	}
+	if _, ok := params.Get("interval").(time.Duration); !ok {
+		return nil, errors.New("interval is not a duration")
+	}
+	results := make([]domain.PingResult, count)
+	for i := range results {
+		results[i] = domain.PingResult{Host: domain.NetworkHost{Hostname: host}, Sequence: i, TTL: t.ttl, RTT: time.Millisecond}
+	}
+	return domain.NewResult(results), nil
+}
+
+// stubRegistry implements domain.PluginRegistry over a fixed set of tools
+type stubRegistry map[string]domain.DiagnosticTool
+
+func (r stubRegistry) Register(tool domain.DiagnosticTool) error { return nil }
+func (r stubRegistry) Unregister(name string) error              { return nil }
+func (r stubRegistry) Get(name string) (domain.DiagnosticTool, bool) {
+	tool, exists := r[name]
+	return tool, exists
+}
+func (r stubRegistry) List() []domain.DiagnosticTool {
+	var tools []domain.DiagnosticTool
+	for _, tool := range r {
+		tools = append(tools, tool)
+	}
+	return tools
+}
+
+// startAgent serves tools without transport security and returns its address
+func startAgent(t *testing.T, tools stubRegistry, token string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := NewServer("edge", tools, token, testLogger{}).grpcServer()
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+// newClient creates a client that is closed when the test ends
+func newClient(t *testing.T, name, address, token string) *Client {
+	client, err := NewClient(name, address, token, true)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func pingParams() domain.Parameters {
+	return domain.NewPingParameters("example.com", domain.PingOptions{Count: 2, Interval: time.Second, Timeout: 5 * time.Second, PacketSize: 64})
+}
+
+func TestParameters_RoundTrip(t *testing.T) {
+	params := domain.NewTracerouteParameters("example.com", domain.TraceOptions{MaxHops: 20, Timeout: time.Second, Protocol: domain.TraceProtocolICMP})
+	params.Set("record_types", []domain.DNSRecordType{domain.DNSRecordTypeA, domain.DNSRecordTypeMX})
+	params.Set("mode", domain.PingModeAdaptive)
+	params.Set("acknowledge", true)
+
+	encoded, err := EncodeParameters(params)
+	require.NoError(t, err)
+	decoded, err := DecodeParameters(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, params.ToMap(), decoded.ToMap())
+}
+
+func TestParameters_Unsupported(t *testing.T) {
+	params := domain.NewParameters()
+	params.Set("callback", func() {})
+	_, err := EncodeParameters(params)
+	assert.Error(t, err)
+
+	_, err = DecodeParameters([]*agentpb.Param{{Key: "x", Type: "complex", Value: []byte("1")}})
+	assert.Error(t, err)
+}
+
+func TestClient_InfoAndExecute(t *testing.T) {
+	address := startAgent(t, stubRegistry{"ping": &pingTool{ttl: 64}}, "secret")
+	client := newClient(t, "edge", address, "secret")
+
+	info, err := client.Info(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "edge", info.Name)
+	assert.Equal(t, []string{"ping"}, info.Tools)
+
+	params, err := EncodeParameters(pingParams())
+	require.NoError(t, err)
+	result, err := client.Execute(context.Background(), "ping", params)
+	require.NoError(t, err)
+	replies, ok := result.Data().([]domain.PingResult)
+	require.True(t, ok, "result keeps its type")
+	require.Len(t, replies, 2)
+	assert.Equal(t, "example.com", replies[0].Host.Hostname)
+	assert.Equal(t, 64, replies[0].TTL)
+}
+
+func TestClient_Errors(t *testing.T) {
+	address := startAgent(t, stubRegistry{"ping": &pingTool{err: errors.New("no route to host")}}, "secret")
+
+	_, err := newClient(t, "edge", address, "wrong").Info(context.Background())
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = newClient(t, "edge", address, "").Info(context.Background())
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	client := newClient(t, "edge", address, "secret")
+	_, err = client.Execute(context.Background(), "traceroute", nil)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.Execute(context.Background(), "ping", nil)
+	assert.Equal(t, codes.Unknown, status.Code(err))
+	assert.Equal(t, "no route to host", status.Convert(err).Message())
+}
+
+func TestVantageTool_Execute(t *testing.T) {
+	edge := newClient(t, "edge", startAgent(t, stubRegistry{"ping": &pingTool{ttl: 55}}, ""), "")
+	down := newClient(t, "down", "127.0.0.1:1", "")
+
+	tool := Distribute(&pingTool{ttl: 64}, []*Client{edge, down})
+	assert.Equal(t, "ping", tool.Name())
+	result, err := tool.Execute(context.Background(), pingParams())
+	require.NoError(t, err)
+
+	data := result.Data().(domain.VantageResults)
+	assert.Equal(t, "ping", data.Tool)
+	require.Len(t, data.Points, 3)
+	assert.Equal(t, LocalName, data.Points[0].Name)
+	assert.Equal(t, 64, data.Points[0].Result.Data().([]domain.PingResult)[0].TTL)
+	assert.Equal(t, "edge", data.Points[1].Name)
+	assert.Equal(t, 55, data.Points[1].Result.Data().([]domain.PingResult)[0].TTL)
+	assert.Equal(t, "down", data.Points[2].Name)
+	assert.Error(t, data.Points[2].Error)
+	assert.Equal(t, 3, result.Metadata()["vantage_points"])
+}
+
+func TestVantageTool_AllFailed(t *testing.T) {
+	tool := Distribute(&pingTool{err: errors.New("host is required")}, []*Client{newClient(t, "down", "127.0.0.1:1", "")})
+	_, err := tool.Execute(context.Background(), pingParams())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "local: host is required")
+}
+
+func TestNewClient_RefusesPlaintextToken(t *testing.T) {
+	_, err := NewClient("edge", "edge.example.com:7443", "secret", false)
+	assert.ErrorContains(t, err, "without TLS")
+
+	for _, allowed := range []struct {
+		address, token string
+		allowPlaintext bool
+	}{
+		{"https://edge.example.com:7443", "secret", false},
+		{"edge.example.com:7443", "", false},
+		{"edge.example.com:7443", "secret", true},
+	} {
+		client, err := NewClient("edge", allowed.address, allowed.token, allowed.allowPlaintext)
+		require.NoError(t, err, allowed)
+		client.Close()
+	}
+}
+
+func TestResult_RoundTrip(t *testing.T) {
+	result := domain.NewResult([]domain.PingResult{{Host: domain.NetworkHost{Hostname: "example.com"}, TTL: 64}})
+	result.SetMetadata("tool", "ping")
+	entry, err := session.NewEntry(result, time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	encoded, err := encodeResult(entry)
+	require.NoError(t, err)
+	data, err := proto.Marshal(encoded)
+	require.NoError(t, err)
+	var wire agentpb.Result
+	require.NoError(t, proto.Unmarshal(data, &wire))
+
+	decoded, err := decodeResult(&wire)
+	require.NoError(t, err)
+	assert.Equal(t, entry.Kind, decoded.Kind)
+	assert.True(t, entry.Timestamp.Equal(decoded.Timestamp))
+	assert.Equal(t, "ping", decoded.Metadata["tool"])
+	restored, err := decoded.Result()
+	require.NoError(t, err)
+	assert.Equal(t, result.Data(), restored.Data())
+}
+
+func TestParseAddress(t *testing.T) {
+	for value, expected := range map[string][2]string{
+		"eu=eu.example.com:7443":         {"eu", "eu.example.com:7443"},
+		"10.0.0.5:7443":                  {"10.0.0.5", "10.0.0.5:7443"},
+		"https://us.example.com:7443":    {"us.example.com", "https://us.example.com:7443"},
+		" lab = https://lab.internal:1 ": {"lab", "https://lab.internal:1"},
+	} {
+		name, address, err := ParseAddress(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, [2]string{name, address}, value)
+	}
+	for _, invalid := range []string{"", "=host:1", "name="} {
+		_, _, err := ParseAddress(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,107 @@
+package agent
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/nettracex/nettracex-tui/internal/agent/agentpb"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// Client calls the agent service on one remote host
+type Client struct {
+	name  string
+	token string
+	conn  *grpc.ClientConn
+	agent agentpb.AgentClient
+}
+
+// NewClient creates a client for the agent at address. An address with an
+// https:// scheme uses TLS; plain host:port addresses connect without
+// transport security, which is refused when a token would be sent in the
+// clear unless allowPlaintext is set. The connection is made on the first call.
+func NewClient(name, address, token string, allowPlaintext bool) (*Client, error) {
+	creds := insecure.NewCredentials()
+	target := strings.TrimSuffix(address, "/")
+	secure := strings.HasPrefix(target, "https://")
+	if secure {
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+	if token != "" && !secure && !allowPlaintext {
+		return nil, fmt.Errorf("agent %s: refusing to send the agent token without TLS; use an https:// address or pass -agent-insecure", name)
+	}
+	target = strings.TrimPrefix(strings.TrimPrefix(target, "https://"), "http://")
+
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("invalid address for agent %s: %w", name, err)
+	}
+	return &Client{name: name, token: token, conn: conn, agent: agentpb.NewAgentClient(conn)}, nil
+}
+
+// ParseAddress parses an agent given as name=address or as a bare address,
+// which is then also its name
+func ParseAddress(value string) (name, address string, err error) {
+	name, address, found := strings.Cut(strings.TrimSpace(value), "=")
+	if !found {
+		address = name
+		name = strings.TrimPrefix(strings.TrimPrefix(address, "https://"), "http://")
+		if host, _, err := net.SplitHostPort(name); err == nil {
+			name = host
+		}
+	}
+	name = strings.TrimSpace(name)
+	address = strings.TrimSpace(address)
+	if name == "" || address == "" {
+		return "", "", fmt.Errorf("agent %q must be an address or name=address", value)
+	}
+	return name, address, nil
+}
+
+// Name returns the name the agent is displayed with
+func (c *Client) Name() string {
+	return c.name
+}
+
+// Close closes the connection to the agent
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Info describes the agent and the tools it can run
+func (c *Client) Info(ctx context.Context) (*agentpb.InfoResponse, error) {
+	return c.agent.Info(c.authorize(ctx), &agentpb.InfoRequest{})
+}
+
+// Execute runs tool on the agent with params already encoded by EncodeParameters
+func (c *Client) Execute(ctx context.Context, tool string, params []*agentpb.Param) (domain.Result, error) {
+	response, err := c.agent.Execute(c.authorize(ctx), &agentpb.ExecuteRequest{Tool: tool, Params: params})
+	if err != nil {
+		return nil, err
+	}
+	entry, err := decodeResult(response.GetResult())
+	if err != nil {
+		return nil, fmt.Errorf("agent %s returned an unreadable result: %w", c.name, err)
+	}
+	result, err := entry.Result()
+	if err != nil {
+		return nil, fmt.Errorf("agent %s returned an unreadable result: %w", c.name, err)
+	}
+	return result, nil
+}
+
+// authorize presents the token as a bearer token on calls made with ctx.
+// A failed call is returned as a gRPC status error.
+func (c *Client) authorize(ctx context.Context) context.Context {
+	if c.token == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.token)
+}
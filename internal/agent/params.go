@@ -0,0 +1,119 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/agent/agentpb"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// Parameter value types. Tools assert the Go type of their parameters, so
+// each value travels with its type and is restored exactly on the agent.
+const (
+	typeString       = "string"
+	typeStrings      = "strings"
+	typeInt          = "int"
+	typeFloat        = "float"
+	typeBool         = "bool"
+	typeDuration     = "duration"
+	typePingMode     = "ping_mode"
+	typeRecordType   = "record_type"
+	typeRecordTypes  = "record_types"
+	typeTraceOptions = "trace_options"
+	typeProtocol     = "trace_protocol"
+)
+
+// EncodeParameters converts params for an execute request, failing on value
+// types that cannot be restored on the agent
+func EncodeParameters(params domain.Parameters) ([]*agentpb.Param, error) {
+	values := params.ToMap()
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	encoded := make([]*agentpb.Param, 0, len(keys))
+	for _, key := range keys {
+		value := values[key]
+		if value == nil {
+			continue
+		}
+		var kind string
+		switch value.(type) {
+		case string:
+			kind = typeString
+		case []string:
+			kind = typeStrings
+		case int:
+			kind = typeInt
+		case float64:
+			kind = typeFloat
+		case bool:
+			kind = typeBool
+		case time.Duration:
+			kind = typeDuration
+		case domain.PingMode:
+			kind = typePingMode
+		case domain.DNSRecordType:
+			kind = typeRecordType
+		case []domain.DNSRecordType:
+			kind = typeRecordTypes
+		case domain.TraceOptions:
+			kind = typeTraceOptions
+		case domain.TraceProtocol:
+			kind = typeProtocol
+		default:
+			return nil, fmt.Errorf("parameter %q of type %T cannot be sent to an agent", key, value)
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode parameter %q: %w", key, err)
+		}
+		encoded = append(encoded, &agentpb.Param{Key: key, Type: kind, Value: data})
+	}
+	return encoded, nil
+}
+
+// DecodeParameters restores the parameters of an execute request
+func DecodeParameters(encoded []*agentpb.Param) (domain.Parameters, error) {
+	params := domain.NewParameters()
+	for _, param := range encoded {
+		var target interface{}
+		switch param.GetType() {
+		case typeString:
+			target = new(string)
+		case typeStrings:
+			target = new([]string)
+		case typeInt:
+			target = new(int)
+		case typeFloat:
+			target = new(float64)
+		case typeBool:
+			target = new(bool)
+		case typeDuration:
+			target = new(time.Duration)
+		case typePingMode:
+			target = new(domain.PingMode)
+		case typeRecordType:
+			target = new(domain.DNSRecordType)
+		case typeRecordTypes:
+			target = new([]domain.DNSRecordType)
+		case typeTraceOptions:
+			target = new(domain.TraceOptions)
+		case typeProtocol:
+			target = new(domain.TraceProtocol)
+		default:
+			return nil, fmt.Errorf("parameter %q has unknown type %q", param.GetKey(), param.GetType())
+		}
+		if err := json.Unmarshal(param.GetValue(), target); err != nil {
+			return nil, fmt.Errorf("invalid parameter %q: %w", param.GetKey(), err)
+		}
+		params.Set(param.GetKey(), reflect.ValueOf(target).Elem().Interface())
+	}
+	return params, nil
+}
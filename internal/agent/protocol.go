@@ -0,0 +1,51 @@
+// Package agent runs diagnostics on remote hosts. In agent mode nettracex
+// serves the registered tools as a gRPC service; a local instance connects
+// to one or more agents and runs each diagnostic from every vantage point.
+//
+// The service and its messages are defined in agentpb/agent.proto; the Go
+// code in agentpb is generated from it with `make proto`.
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nettracex/nettracex-tui/internal/agent/agentpb"
+	"github.com/nettracex/nettracex-tui/internal/session"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TokenEnv names the environment variable holding the shared agent token
+const TokenEnv = "NETTRACEX_AGENT_TOKEN"
+
+// encodeResult converts a session entry to its wire message
+func encodeResult(entry session.Entry) (*agentpb.Result, error) {
+	result := &agentpb.Result{
+		Kind:      entry.Kind,
+		Timestamp: timestamppb.New(entry.Timestamp),
+		Data:      entry.Data,
+	}
+	if len(entry.Metadata) > 0 {
+		metadata, err := json.Marshal(entry.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode result metadata: %w", err)
+		}
+		result.Metadata = metadata
+	}
+	return result, nil
+}
+
+// decodeResult restores the session entry of a wire message
+func decodeResult(result *agentpb.Result) (session.Entry, error) {
+	entry := session.Entry{
+		Kind:      result.GetKind(),
+		Timestamp: result.GetTimestamp().AsTime(),
+		Data:      result.GetData(),
+	}
+	if len(result.GetMetadata()) > 0 {
+		if err := json.Unmarshal(result.GetMetadata(), &entry.Metadata); err != nil {
+			return session.Entry{}, fmt.Errorf("invalid result metadata: %w", err)
+		}
+	}
+	return entry, nil
+}
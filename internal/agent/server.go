@@ -0,0 +1,135 @@
+package agent
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/agent/agentpb"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/session"
+	"github.com/nettracex/nettracex-tui/internal/version"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Server exposes the tools of a registry as the agent gRPC service
+type Server struct {
+	agentpb.UnimplementedAgentServer
+	name    string
+	plugins domain.PluginRegistry
+	token   string
+	logger  domain.Logger
+}
+
+// NewServer creates a server named name running the tools in plugins. When
+// token is set, calls must present it as a bearer token.
+func NewServer(name string, plugins domain.PluginRegistry, token string, logger domain.Logger) *Server {
+	return &Server{name: name, plugins: plugins, token: token, logger: logger}
+}
+
+// grpcServer creates a gRPC server with the agent service registered
+func (s *Server) grpcServer(options ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(append(options, grpc.UnaryInterceptor(s.authenticate))...)
+	agentpb.RegisterAgentServer(server, s)
+	return server
+}
+
+// authenticate rejects calls that do not present the agent token
+func (s *Server) authenticate(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if s.token != "" {
+		var presented string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get("authorization"); len(values) > 0 {
+				presented = strings.TrimPrefix(values[0], "Bearer ")
+			}
+		}
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(s.token)) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "invalid agent token")
+		}
+	}
+	return handler(ctx, request)
+}
+
+// Info describes the agent and its tools
+func (s *Server) Info(ctx context.Context, request *agentpb.InfoRequest) (*agentpb.InfoResponse, error) {
+	var tools []string
+	for _, tool := range s.plugins.List() {
+		tools = append(tools, tool.Name())
+	}
+	sort.Strings(tools)
+	return &agentpb.InfoResponse{Name: s.name, Version: version.Get().Version, Tools: tools}, nil
+}
+
+// Execute runs the requested tool and encodes its result
+func (s *Server) Execute(ctx context.Context, request *agentpb.ExecuteRequest) (*agentpb.ExecuteResponse, error) {
+	tool, exists := s.plugins.Get(request.GetTool())
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "tool %q is not available on agent %s", request.GetTool(), s.name)
+	}
+	params, err := DecodeParameters(request.GetParams())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	s.logger.Info("Executing tool for remote client", "tool", request.GetTool())
+	result, err := tool.Execute(ctx, params)
+	if err != nil {
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+	entry, err := session.NewEntry(result, time.Now())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	encoded, err := encodeResult(entry)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &agentpb.ExecuteResponse{Result: encoded}, nil
+}
+
+// Serve serves the agent on addr until ctx is cancelled. With a certificate
+// and key the agent uses TLS, otherwise it serves without transport
+// security.
+func (s *Server) Serve(ctx context.Context, addr, certFile, keyFile string) error {
+	var options []grpc.ServerOption
+	if certFile != "" || keyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load agent certificate: %w", err)
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("agent server failed: %w", err)
+	}
+	server := s.grpcServer(options...)
+
+	go func() {
+		<-ctx.Done()
+		stopped := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			server.Stop()
+		}
+	}()
+
+	if err := server.Serve(listener); err != nil {
+		return fmt.Errorf("agent server failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// LocalName names the local host among the vantage points
+const LocalName = "local"
+
+// VantageTool runs a diagnostic tool on the local host and on every agent
+// at once and combines the outcomes, so each vantage point can be compared
+type VantageTool struct {
+	domain.DiagnosticTool
+	agents []*Client
+}
+
+// Distribute wraps tool so it also runs on agents
+func Distribute(tool domain.DiagnosticTool, agents []*Client) domain.DiagnosticTool {
+	return &VantageTool{DiagnosticTool: tool, agents: agents}
+}
+
+// Unwrap returns the wrapped diagnostic tool
+func (t *VantageTool) Unwrap() domain.DiagnosticTool {
+	return t.DiagnosticTool
+}
+
+// Execute runs the tool from every vantage point. It fails only when every
+// vantage point failed; otherwise failures are reported per vantage point.
+func (t *VantageTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	points := make([]domain.VantageResult, len(t.agents)+1)
+	encoded, encodeErr := EncodeParameters(params)
+
+	var wg sync.WaitGroup
+	wg.Add(len(points))
+	go func() {
+		defer wg.Done()
+		result, err := t.DiagnosticTool.Execute(ctx, params)
+		points[0] = domain.VantageResult{Name: LocalName, Result: result, Error: err}
+	}()
+	for i, client := range t.agents {
+		go func(i int, client *Client) {
+			defer wg.Done()
+			point := domain.VantageResult{Name: client.Name(), Error: encodeErr}
+			if encodeErr == nil {
+				point.Result, point.Error = client.Execute(ctx, t.Name(), encoded)
+			}
+			points[i+1] = point
+		}(i, client)
+	}
+	wg.Wait()
+
+	var failures []string
+	for _, point := range points {
+		if point.Error != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", point.Name, point.Error))
+		}
+	}
+	if len(failures) == len(points) {
+		return nil, fmt.Errorf("%s failed from every vantage point: %s", t.Name(), strings.Join(failures, "; "))
+	}
+
+	result := domain.NewResult(domain.VantageResults{Tool: t.Name(), Points: points})
+	result.SetMetadata("tool", t.Name())
+	result.SetMetadata("vantage_points", len(points))
+	result.SetMetadata("timestamp", time.Now())
+	return result, nil
+}
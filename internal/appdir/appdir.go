@@ -0,0 +1,45 @@
+// Package appdir locates the per-user directories nettracex keeps its
+// files in. They are built on os.UserConfigDir and os.UserCacheDir, so they
+// follow the conventions of each platform rather than assuming $HOME.
+package appdir
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Name is the directory nettracex uses below the user directories
+const Name = "nettracex"
+
+// ConfigDir returns the nettracex configuration directory: ~/.config/nettracex
+// on Linux, %AppData%\nettracex on Windows and ~/Library/Application
+// Support/nettracex on macOS. When the user directory is unknown it is
+// relative to the working directory.
+func ConfigDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return Name
+	}
+	return filepath.Join(dir, Name)
+}
+
+// ConfigPath returns the file name in ConfigDir
+func ConfigPath(name string) string {
+	return filepath.Join(ConfigDir(), name)
+}
+
+// CacheDir returns the nettracex cache directory: ~/.cache/nettracex on
+// Linux, %LocalAppData%\nettracex on Windows and ~/Library/Caches/nettracex
+// on macOS
+func CacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return Name
+	}
+	return filepath.Join(dir, Name)
+}
+
+// CachePath returns the file name in CacheDir
+func CachePath(name string) string {
+	return filepath.Join(CacheDir(), name)
+}
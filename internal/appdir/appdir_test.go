@@ -0,0 +1,39 @@
+package appdir
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestDirs(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("XDG variables only apply to other Unix systems")
+	}
+	config, cache := t.TempDir(), t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", config)
+	t.Setenv("XDG_CACHE_HOME", cache)
+
+	if got, want := ConfigPath("targets.json"), filepath.Join(config, "nettracex", "targets.json"); got != want {
+		t.Errorf("ConfigPath() = %q, want %q", got, want)
+	}
+	if got, want := CachePath("responses.json"), filepath.Join(cache, "nettracex", "responses.json"); got != want {
+		t.Errorf("CachePath() = %q, want %q", got, want)
+	}
+}
+
+func TestDirsWithoutHome(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("HOME is not consulted first on this system")
+	}
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("HOME", "")
+
+	if got := ConfigDir(); got != Name {
+		t.Errorf("ConfigDir() = %q, want %q", got, Name)
+	}
+	if got := CacheDir(); got != Name {
+		t.Errorf("CacheDir() = %q, want %q", got, Name)
+	}
+}
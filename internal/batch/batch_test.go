@@ -0,0 +1,267 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/network"
+	"github.com/nettracex/nettracex-tui/internal/tools/whois"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testLogger implements domain.Logger and discards all output
+type testLogger struct{}
+
+func (testLogger) Debug(msg string, fields ...interface{}) {}
+func (testLogger) Info(msg string, fields ...interface{})  {}
+func (testLogger) Warn(msg string, fields ...interface{})  {}
+func (testLogger) Error(msg string, fields ...interface{}) {}
+func (testLogger) Fatal(msg string, fields ...interface{}) {}
+
+// slowTool records the highest number of concurrent executions
+type slowTool struct {
+	mu       sync.Mutex
+	running  int
+	peak     int
+	received []domain.Parameters
+}
+
+func (t *slowTool) Name() string                            { return "ping" }
+func (t *slowTool) Description() string                     { return "slow test tool" }
+func (t *slowTool) Validate(params domain.Parameters) error { return nil }
+func (t *slowTool) GetModel() tea.Model                     { return nil }
+
+func (t *slowTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	t.mu.Lock()
+	t.running++
+	if t.running > t.peak {
+		t.peak = t.running
+	}
+	t.received = append(t.received, params)
+	t.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	t.mu.Lock()
+	t.running--
+	t.mu.Unlock()
+	return domain.NewResult(params.Get("host")), nil
+}
+
+// namedTool is a tool that only has a name
+type namedTool struct{ name string }
+
+func (t namedTool) Name() string                            { return t.name }
+func (t namedTool) Description() string                     { return "named test tool" }
+func (t namedTool) Validate(params domain.Parameters) error { return nil }
+func (t namedTool) GetModel() tea.Model                     { return nil }
+func (t namedTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	return domain.NewResult(nil), nil
+}
+
+// describedTool is a plugin tool that describes its inputs
+type describedTool struct{ namedTool }
+
+func (describedTool) Parameters() []domain.ParameterSpec {
+	return []domain.ParameterSpec{
+		{Key: "port", Label: "Port", Default: "80"},
+		{Key: "url", Label: "URL", Required: true},
+	}
+}
+
+func TestReadTargets(t *testing.T) {
+	input := "# production hosts\nexample.com\n\n  example.org  # secondary\nexample.com\n"
+
+	targets, err := ReadTargets(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com", "example.org"}, targets)
+}
+
+func TestLoadTargets_Stdin(t *testing.T) {
+	targets, err := LoadTargets(StdinPath, strings.NewReader("10.0.0.1\n10.0.0.2\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, targets)
+
+	_, err = LoadTargets("does-not-exist.txt", nil)
+	assert.Error(t, err)
+}
+
+func TestParseOption(t *testing.T) {
+	key, value, err := ParseOption("count = 10")
+	require.NoError(t, err)
+	assert.Equal(t, "count", key)
+	assert.Equal(t, "10", value)
+
+	_, _, err = ParseOption("count")
+	assert.Error(t, err)
+	_, _, err = ParseOption("=10")
+	assert.Error(t, err)
+}
+
+func TestBuildParameters(t *testing.T) {
+	t.Run("options take the type of the default", func(t *testing.T) {
+		params, err := BuildParameters(namedTool{"ping"}, "example.com", map[string]string{
+			"count":    "10",
+			"interval": "500ms",
+			"timeout":  "2",
+			"ipv6":     "true",
+			"mode":     "adaptive",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "example.com", params.Get("host"))
+		assert.Equal(t, 10, params.Get("count"))
+		assert.Equal(t, 500*time.Millisecond, params.Get("interval"))
+		assert.Equal(t, 2*time.Second, params.Get("timeout"))
+		assert.Equal(t, true, params.Get("ipv6"))
+		assert.Equal(t, "adaptive", params.Get("mode"))
+	})
+
+	t.Run("invalid option values are rejected", func(t *testing.T) {
+		_, err := BuildParameters(namedTool{"ping"}, "example.com", map[string]string{"count": "many"})
+		assert.EqualError(t, err, "option count must be an integer")
+
+		_, err = BuildParameters(namedTool{"traceroute"}, "example.com", map[string]string{"timeout": "soon"})
+		assert.Error(t, err)
+	})
+
+	t.Run("dns record types", func(t *testing.T) {
+		params, err := BuildParameters(namedTool{"dns"}, "example.com", map[string]string{"record_types": "a, mx"})
+		require.NoError(t, err)
+		assert.Equal(t, []domain.DNSRecordType{domain.DNSRecordTypeA, domain.DNSRecordTypeMX}, params.Get("record_types"))
+
+		_, err = BuildParameters(namedTool{"dns"}, "example.com", map[string]string{"record_types": "bogus"})
+		assert.Error(t, err)
+	})
+
+	t.Run("target parameter per tool", func(t *testing.T) {
+		for tool, key := range map[string]string{
+			"whois":      "query",
+			"ping":       "host",
+			"traceroute": "host",
+			"ssl":        "host",
+			"dualstack":  "host",
+			"dns":        "domain",
+			"axfr":       "domain",
+			"sweep":      "cidr",
+		} {
+			params, err := BuildParameters(namedTool{tool}, "target", nil)
+			require.NoError(t, err, tool)
+			assert.Equal(t, "target", params.Get(key), tool)
+		}
+
+		_, err := BuildParameters(namedTool{"unknown"}, "target", nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("plugin tools take their described inputs", func(t *testing.T) {
+		params, err := BuildParameters(describedTool{namedTool{"http"}}, "https://example.com", map[string]string{"method": "HEAD"})
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com", params.Get("url"))
+		assert.Equal(t, "80", params.Get("port"))
+		assert.Equal(t, "HEAD", params.Get("method"))
+	})
+}
+
+func TestRunner_Run(t *testing.T) {
+	client := network.NewMockClient()
+	client.SetWHOISResponse("example.com", domain.WHOISResult{Domain: "example.com", Registrar: "Example Registrar"})
+	client.SetWHOISError("example.org", errors.New("whois server unavailable"))
+
+	runner := NewRunner(whois.NewTool(client, testLogger{}), nil, 2, testLogger{})
+	result := runner.Run(context.Background(), []string{"example.com", "example.org"})
+
+	batch, ok := result.Data().(domain.BatchResult)
+	require.True(t, ok)
+	assert.Equal(t, "whois", batch.Tool)
+	require.Len(t, batch.Targets, 2)
+
+	// Outcomes keep the order of the target list
+	assert.Equal(t, "example.com", batch.Targets[0].Target)
+	assert.True(t, batch.Targets[0].Succeeded())
+	assert.Equal(t, "Example Registrar", batch.Targets[0].Data.(domain.WHOISResult).Registrar)
+
+	assert.Equal(t, "example.org", batch.Targets[1].Target)
+	assert.False(t, batch.Targets[1].Succeeded())
+	assert.Equal(t, 1, batch.Failed())
+
+	assert.Equal(t, 2, result.Metadata()["targets"])
+	assert.Equal(t, 1, result.Metadata()["failed"])
+}
+
+func TestRunner_BoundedConcurrency(t *testing.T) {
+	tool := &slowTool{}
+	options := map[string]string{"count": "1"}
+	runner := NewRunner(tool, options, 2, testLogger{})
+
+	result := runner.Run(context.Background(), []string{"a", "b", "c", "d", "e"})
+
+	batch := result.Data().(domain.BatchResult)
+	assert.Len(t, batch.Targets, 5)
+	assert.Equal(t, 0, batch.Failed())
+	assert.LessOrEqual(t, tool.peak, 2)
+
+	// Shared options apply to every target
+	for _, params := range tool.received {
+		assert.Equal(t, 1, params.Get("count"))
+	}
+}
+
+func TestRunner_OnProgress(t *testing.T) {
+	runner := NewRunner(&slowTool{}, nil, 3, testLogger{})
+	var reported []int
+	runner.OnProgress(func(done, total int) {
+		assert.Equal(t, 4, total)
+		reported = append(reported, done)
+	})
+
+	runner.Run(context.Background(), []string{"a", "b", "c", "d"})
+	assert.Equal(t, []int{1, 2, 3, 4}, reported)
+}
+
+func TestRunner_CancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	runner := NewRunner(&slowTool{}, nil, 0, testLogger{})
+	result := runner.Run(ctx, []string{"a"})
+
+	batch := result.Data().(domain.BatchResult)
+	assert.Equal(t, 1, batch.Failed())
+	assert.Equal(t, context.Canceled.Error(), batch.Targets[0].Error)
+}
+
+func TestWriteReport(t *testing.T) {
+	format, err := ParseFormat("CSV")
+	require.NoError(t, err)
+	assert.Equal(t, domain.ExportFormatCSV, format)
+
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+
+	junit, err := ParseFormat("junit")
+	require.NoError(t, err)
+	assert.Equal(t, domain.ExportFormatJUnit, junit)
+
+	result := domain.NewResult(domain.BatchResult{
+		Tool:    "ping",
+		Targets: []domain.BatchTargetResult{{Target: "10.0.0.1", Error: "timeout"}},
+	})
+
+	var out strings.Builder
+	require.NoError(t, WriteReport(result, format, "", &out))
+	assert.Contains(t, out.String(), "10.0.0.1,failed,0.000,timeout")
+
+	path := t.TempDir() + "/report.csv"
+	require.NoError(t, WriteReport(result, format, path, nil))
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, out.String(), string(written))
+}
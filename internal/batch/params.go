@@ -0,0 +1,113 @@
+package batch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/toolparams"
+	"github.com/nettracex/nettracex-tui/internal/tools/dns"
+)
+
+// ParseOption splits a "key=value" command line option
+func ParseOption(option string) (string, string, error) {
+	key, value, ok := strings.Cut(option, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("option %q must be in key=value form", option)
+	}
+	return key, strings.TrimSpace(value), nil
+}
+
+// BuildParameters creates the parameters for running tool against target.
+// Each tool starts from the same defaults as the interactive form, and
+// options override them, converted to the type of the default value.
+func BuildParameters(tool domain.DiagnosticTool, target string, options map[string]string) (domain.Parameters, error) {
+	key := toolparams.TargetKey(tool)
+	if key == "" {
+		return nil, fmt.Errorf("batch mode does not support tool: %s", tool.Name())
+	}
+	params, err := toolparams.Build(tool, map[string]string{key: target})
+	if err != nil {
+		return nil, fmt.Errorf("batch mode does not support tool: %s", tool.Name())
+	}
+
+	for key, value := range options {
+		if tool.Name() == "dns" && key == "record_types" {
+			recordTypes, err := parseRecordTypes(value)
+			if err != nil {
+				return nil, err
+			}
+			params.Set(key, recordTypes)
+			continue
+		}
+
+		converted, err := convertOption(key, value, params.Get(key))
+		if err != nil {
+			return nil, err
+		}
+		params.Set(key, converted)
+	}
+
+	return params, nil
+}
+
+// convertOption converts value to the type of the current parameter value.
+// Options without a default are passed as int, bool or string.
+func convertOption(key, value string, current interface{}) (interface{}, error) {
+	switch current.(type) {
+	case int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("option %s must be an integer", key)
+		}
+		return n, nil
+	case bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("option %s must be true or false", key)
+		}
+		return b, nil
+	case time.Duration:
+		// Plain numbers are seconds, matching the interactive form
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+			return time.Duration(seconds * float64(time.Second)), nil
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("option %s must be a duration such as 500ms or 2s", key)
+		}
+		return d, nil
+	case string:
+		return value, nil
+	}
+
+	if n, err := strconv.Atoi(value); err == nil {
+		return n, nil
+	}
+	if b, err := strconv.ParseBool(value); err == nil {
+		return b, nil
+	}
+	return value, nil
+}
+
+// parseRecordTypes parses a comma-separated list of DNS record types
+func parseRecordTypes(value string) ([]domain.DNSRecordType, error) {
+	var recordTypes []domain.DNSRecordType
+	for _, name := range strings.Split(value, ",") {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		recordType, err := dns.ParseRecordTypeString(name)
+		if err != nil {
+			return nil, err
+		}
+		recordTypes = append(recordTypes, recordType)
+	}
+	if len(recordTypes) == 0 {
+		return nil, fmt.Errorf("option record_types must list at least one record type")
+	}
+	return recordTypes, nil
+}
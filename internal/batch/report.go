@@ -0,0 +1,50 @@
+package batch
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// ParseFormat parses a report format name given on the command line
+func ParseFormat(name string) (domain.ExportFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "json":
+		return domain.ExportFormatJSON, nil
+	case "csv":
+		return domain.ExportFormatCSV, nil
+	case "text", "txt":
+		return domain.ExportFormatText, nil
+	case "html":
+		return domain.ExportFormatHTML, nil
+	case "markdown", "md":
+		return domain.ExportFormatMarkdown, nil
+	case "pdf":
+		return domain.ExportFormatPDF, nil
+	case "junit":
+		return domain.ExportFormatJUnit, nil
+	default:
+		return 0, fmt.Errorf("invalid report format: %s (use json, csv, text, html, markdown, pdf, or junit)", name)
+	}
+}
+
+// WriteReport exports result in format to the file at path, or to stdout when path is empty
+func WriteReport(result domain.Result, format domain.ExportFormat, path string, stdout io.Writer) error {
+	data, err := result.Export(format)
+	if err != nil {
+		return fmt.Errorf("failed to export report: %w", err)
+	}
+
+	if path == "" {
+		_, err = stdout.Write(data)
+		return err
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package batch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// DefaultConcurrency is the number of targets processed at once when none is given
+const DefaultConcurrency = 4
+
+// Runner executes one diagnostic tool against many targets with shared options
+type Runner struct {
+	tool        domain.DiagnosticTool
+	options     map[string]string
+	concurrency int
+	logger      domain.Logger
+	progress    func(done, total int)
+}
+
+// NewRunner creates a batch runner for tool. A concurrency below one uses DefaultConcurrency.
+func NewRunner(tool domain.DiagnosticTool, options map[string]string, concurrency int, logger domain.Logger) *Runner {
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+	return &Runner{
+		tool:        tool,
+		options:     options,
+		concurrency: concurrency,
+		logger:      logger,
+	}
+}
+
+// OnProgress calls report with the number of finished targets each time one
+// finishes. report may be called from several goroutines but never at once.
+func (r *Runner) OnProgress(report func(done, total int)) {
+	r.progress = report
+}
+
+// Run executes the tool against every target and aggregates the outcomes in
+// target order. A failing target is recorded and does not stop the batch.
+func (r *Runner) Run(ctx context.Context, targets []string) domain.Result {
+	start := time.Now()
+	r.logger.Info("Starting batch run", "tool", r.tool.Name(), "targets", len(targets), "concurrency", r.concurrency)
+
+	outcomes := make([]domain.BatchTargetResult, len(targets))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+
+	// A fixed pool of workers bounds the goroutines however many targets there are
+	for w := 0; w < min(r.concurrency, len(targets)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				outcomes[i] = r.runTarget(ctx, targets[i])
+				if r.progress != nil {
+					mu.Lock()
+					done++
+					r.progress(done, len(targets))
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range targets {
+		indexes <- i
+	}
+	close(indexes)
+
+	wg.Wait()
+
+	batch := domain.BatchResult{
+		Tool:      r.tool.Name(),
+		Options:   r.options,
+		Targets:   outcomes,
+		Duration:  time.Since(start),
+		Timestamp: time.Now(),
+	}
+
+	result := domain.NewResult(batch)
+	result.SetMetadata("tool", r.tool.Name())
+	result.SetMetadata("targets", len(targets))
+	result.SetMetadata("failed", batch.Failed())
+	result.SetMetadata("timestamp", batch.Timestamp)
+
+	r.logger.Info("Batch run completed", "tool", r.tool.Name(), "targets", len(targets), "failed", batch.Failed())
+	return result
+}
+
+// runTarget executes the tool against a single target
+func (r *Runner) runTarget(ctx context.Context, target string) domain.BatchTargetResult {
+	outcome := domain.BatchTargetResult{Target: target}
+	start := time.Now()
+
+	if err := ctx.Err(); err != nil {
+		outcome.Error = err.Error()
+		return outcome
+	}
+
+	params, err := BuildParameters(r.tool, target, r.options)
+	if err != nil {
+		outcome.Error = err.Error()
+		return outcome
+	}
+
+	result, err := r.tool.Execute(ctx, params)
+	outcome.Duration = time.Since(start)
+	if err != nil {
+		r.logger.Debug("Batch target failed", "tool", r.tool.Name(), "target", target, "error", err)
+		outcome.Error = err.Error()
+		return outcome
+	}
+
+	outcome.Data = result.Data()
+	return outcome
+}
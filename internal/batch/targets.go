@@ -0,0 +1,54 @@
+// Package batch runs a diagnostic tool against a list of targets from the command line
+package batch
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// StdinPath is the target list path that reads targets from standard input
+const StdinPath = "-"
+
+// ReadTargets reads one target per line, skipping blank lines and # comments.
+// Duplicate targets are dropped so each one runs once.
+func ReadTargets(r io.Reader) ([]string, error) {
+	seen := make(map[string]bool)
+	var targets []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		target := strings.TrimSpace(line)
+		if target == "" || seen[target] {
+			continue
+		}
+		seen[target] = true
+		targets = append(targets, target)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read targets: %w", err)
+	}
+
+	return targets, nil
+}
+
+// LoadTargets reads the target list from path, or from stdin when path is "-"
+func LoadTargets(path string, stdin io.Reader) ([]string, error) {
+	if path == StdinPath {
+		return ReadTargets(stdin)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open target list: %w", err)
+	}
+	defer file.Close()
+
+	return ReadTargets(file)
+}
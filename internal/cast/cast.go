@@ -0,0 +1,136 @@
+// Package cast records the screens of a TUI session to a file in the
+// asciinema v2 format and plays them back, for incident reviews and bug
+// reports. A recording is a JSON header line followed by one JSON line per
+// event; every output event redraws the whole screen, so a recording can be
+// played from any frame and by asciinema itself.
+package cast
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// Version is the asciinema file format version written and read
+const Version = 2
+
+// Event kinds
+const (
+	// Output is a frame drawn to the terminal
+	Output = "o"
+	// Input is a key pressed, by its name such as "enter" or "ctrl+r"
+	// rather than the bytes the terminal sent
+	Input = "i"
+	// Resize is a new terminal size as WIDTHxHEIGHT
+	Resize = "r"
+	// Marker labels a point of the recording
+	Marker = "m"
+)
+
+// maxEventSize is the largest event line read; a frame of a large terminal
+// with colors takes a few hundred kilobytes
+const maxEventSize = 16 << 20
+
+// Header is the first line of a recording
+type Header struct {
+	Version   int   `json:"version"`
+	Width     int   `json:"width"`
+	Height    int   `json:"height"`
+	Timestamp int64 `json:"timestamp,omitempty"`
+	// IdleTimeLimit shortens longer pauses on playback, in seconds
+	IdleTimeLimit float64           `json:"idle_time_limit,omitempty"`
+	Title         string            `json:"title,omitempty"`
+	Env           map[string]string `json:"env,omitempty"`
+}
+
+// Event is something that happened at Time since the recording started
+type Event struct {
+	Time time.Duration
+	Kind string
+	Data string
+}
+
+// MarshalJSON writes the event as [seconds, kind, data]
+func (e Event) MarshalJSON() ([]byte, error) {
+	seconds := math.Round(e.Time.Seconds()*1e6) / 1e6
+	return json.Marshal([]interface{}{seconds, e.Kind, e.Data})
+}
+
+// UnmarshalJSON reads an event written as [seconds, kind, data]
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var fields []json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if len(fields) != 3 {
+		return fmt.Errorf("event has %d fields, want 3", len(fields))
+	}
+	var seconds float64
+	if err := json.Unmarshal(fields[0], &seconds); err != nil {
+		return fmt.Errorf("invalid event time: %w", err)
+	}
+	if err := json.Unmarshal(fields[1], &e.Kind); err != nil {
+		return fmt.Errorf("invalid event kind: %w", err)
+	}
+	if err := json.Unmarshal(fields[2], &e.Data); err != nil {
+		return fmt.Errorf("invalid event data: %w", err)
+	}
+	e.Time = time.Duration(seconds * float64(time.Second))
+	return nil
+}
+
+// Reader reads a recording event by event
+type Reader struct {
+	scanner *bufio.Scanner
+	header  Header
+	line    int
+}
+
+// NewReader reads the header of the recording in r
+func NewReader(r io.Reader) (*Reader, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventSize)
+	reader := &Reader{scanner: scanner}
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read recording: %w", err)
+		}
+		return nil, fmt.Errorf("recording is empty")
+	}
+	reader.line++
+	if err := json.Unmarshal(scanner.Bytes(), &reader.header); err != nil {
+		return nil, fmt.Errorf("invalid recording header: %w", err)
+	}
+	if reader.header.Version != Version {
+		return nil, fmt.Errorf("unsupported recording version %d, want %d", reader.header.Version, Version)
+	}
+	return reader, nil
+}
+
+// Header returns the header of the recording
+func (r *Reader) Header() Header {
+	return r.header
+}
+
+// Next returns the next event, or io.EOF after the last one. Blank lines
+// are skipped.
+func (r *Reader) Next() (Event, error) {
+	for r.scanner.Scan() {
+		r.line++
+		if len(r.scanner.Bytes()) == 0 {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal(r.scanner.Bytes(), &event); err != nil {
+			return Event{}, fmt.Errorf("line %d: %w", r.line, err)
+		}
+		return event, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Event{}, fmt.Errorf("failed to read recording: %w", err)
+	}
+	return Event{}, io.EOF
+}
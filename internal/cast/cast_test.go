@@ -0,0 +1,141 @@
+package cast
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// counter is a model drawing how many keys were pressed
+type counter struct {
+	keys int
+}
+
+func (c counter) Init() tea.Cmd { return nil }
+
+func (c counter) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(tea.KeyMsg); ok {
+		c.keys++
+	}
+	return c, nil
+}
+
+func (c counter) View() string {
+	return strings.Repeat("key\n", c.keys) + "done"
+}
+
+// record runs a short session through a recorder and returns the recording
+func record(t *testing.T) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	recorder := NewRecorder(counter{}, &out, "test session")
+	recorder.start = now
+	recorder.now = func() time.Time { return now }
+
+	recorder.View()
+	recorder.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	recorder.View()
+	now = now.Add(1500 * time.Millisecond)
+	recorder.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	recorder.View()
+	now = now.Add(time.Minute)
+	recorder.Update(MarkMsg{Label: "ping example.com"})
+	recorder.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	recorder.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	recorder.View()
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	return out.Bytes()
+}
+
+func TestRecorder(t *testing.T) {
+	reader, err := NewReader(bytes.NewReader(record(t)))
+	if err != nil {
+		t.Fatalf("Failed to read the recording: %v", err)
+	}
+	header := reader.Header()
+	if header.Version != 2 || header.Width != 100 || header.Height != 30 || header.Title != "test session" {
+		t.Errorf("Unexpected header %+v", header)
+	}
+
+	var events []Event
+	for {
+		event, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read an event: %v", err)
+		}
+		events = append(events, event)
+	}
+
+	want := []Event{
+		{0, Output, clearScreen + "done"},
+		{1500 * time.Millisecond, Input, "enter"},
+		{1500 * time.Millisecond, Output, clearScreen + "key\r\ndone"},
+		{61500 * time.Millisecond, Marker, "ping example.com"},
+		{61500 * time.Millisecond, Resize, "120x40"},
+		{61500 * time.Millisecond, Input, "q"},
+		{61500 * time.Millisecond, Output, clearScreen + "key\r\nkey\r\ndone"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %d events, unchanged frames left out, got %d: %+v", len(want), len(events), events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("Event %d: expected %+v, got %+v", i, want[i], events[i])
+		}
+	}
+}
+
+func TestRecorder_WriteError(t *testing.T) {
+	recorder := NewRecorder(counter{}, failingWriter{}, "")
+	recorder.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	recorder.View()
+	if err := recorder.Close(); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("Expected the write error, got %v", err)
+	}
+}
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestPlay(t *testing.T) {
+	recording := record(t)
+
+	// The pauses of 1.5s and a minute are cut to a second, then played ten
+	// times as fast
+	var out bytes.Buffer
+	start := time.Now()
+	if err := Play(context.Background(), bytes.NewReader(recording), &out, Options{Speed: 10, MaxIdle: time.Second}); err != nil {
+		t.Fatalf("Play failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 190*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("Expected about 200ms of playback, took %v", elapsed)
+	}
+	want := clearScreen + "done" + clearScreen + "key\r\ndone" + clearScreen + "key\r\nkey\r\ndone"
+	if out.String() != want {
+		t.Errorf("Expected the frames in order, got %q", out.String())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Play(ctx, bytes.NewReader(recording), io.Discard, Options{}); err != context.Canceled {
+		t.Errorf("Expected playback to stop when cancelled, got %v", err)
+	}
+
+	if err := Play(context.Background(), strings.NewReader(`{"version": 1}`), io.Discard, Options{}); err == nil {
+		t.Error("Expected an error for an unsupported version")
+	}
+}
@@ -0,0 +1,69 @@
+package cast
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Options control the playback of a recording
+type Options struct {
+	// Speed multiplies the pace of the recording; 2 plays it twice as fast.
+	// Zero or less plays it as recorded.
+	Speed float64
+	// MaxIdle shortens pauses longer than it, zero keeps them. A recording
+	// may set its own limit in the header, which applies when MaxIdle is zero.
+	MaxIdle time.Duration
+}
+
+// Play writes the output events of the recording read from r to w at the
+// pace they were recorded. It returns ctx.Err() when ctx is done first.
+func Play(ctx context.Context, r io.Reader, w io.Writer, options Options) error {
+	reader, err := NewReader(r)
+	if err != nil {
+		return err
+	}
+	speed := options.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+	maxIdle := options.MaxIdle
+	if maxIdle <= 0 && reader.Header().IdleTimeLimit > 0 {
+		maxIdle = time.Duration(reader.Header().IdleTimeLimit * float64(time.Second))
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	<-timer.C
+
+	var last time.Duration
+	for {
+		event, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if event.Kind != Output {
+			continue
+		}
+
+		pause := max(0, event.Time-last)
+		last = event.Time
+		if maxIdle > 0 && pause > maxIdle {
+			pause = maxIdle
+		}
+		timer.Reset(time.Duration(float64(pause) / speed))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		if _, err := io.WriteString(w, event.Data); err != nil {
+			return fmt.Errorf("failed to write frame: %w", err)
+		}
+	}
+}
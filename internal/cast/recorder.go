@@ -0,0 +1,155 @@
+package cast
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// clearScreen moves the cursor home and clears the screen before a frame
+const clearScreen = "\x1b[H\x1b[2J"
+
+// Default terminal size of a recording that ends before the size is known
+const (
+	DefaultWidth  = 80
+	DefaultHeight = 24
+)
+
+// MarkMsg adds a marker with Label to the recording, such as the start of a
+// diagnostic; send it to the program from other goroutines
+type MarkMsg struct {
+	Label string
+}
+
+// Recorder is a tea.Model that runs model and records the frames it draws,
+// the keys pressed and the resizes of the terminal. The header needs the
+// size of the terminal, so events are held until the first resize, which
+// Bubble Tea sends at start.
+type Recorder struct {
+	model  tea.Model
+	out    io.Writer
+	header Header
+	// now returns the time, so tests control the event times
+	now   func() time.Time
+	start time.Time
+
+	started   bool
+	pending   []Event
+	lastFrame string
+	err       error
+}
+
+// NewRecorder creates a recorder of model writing to out, with title in the
+// header of the recording
+func NewRecorder(model tea.Model, out io.Writer, title string) *Recorder {
+	now := time.Now()
+	return &Recorder{
+		model: model,
+		out:   out,
+		header: Header{
+			Version:   Version,
+			Timestamp: now.Unix(),
+			Title:     title,
+			Env:       map[string]string{"TERM": os.Getenv("TERM"), "SHELL": os.Getenv("SHELL")},
+		},
+		now:   time.Now,
+		start: now,
+	}
+}
+
+// Init implements tea.Model
+func (r *Recorder) Init() tea.Cmd {
+	return r.model.Init()
+}
+
+// Update implements tea.Model and records resizes and keys before passing
+// the message on
+func (r *Recorder) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		r.resize(msg.Width, msg.Height)
+	case tea.KeyMsg:
+		r.record(Input, msg.String())
+	case MarkMsg:
+		r.record(Marker, msg.Label)
+		return r, nil
+	}
+
+	var cmd tea.Cmd
+	r.model, cmd = r.model.Update(msg)
+	return r, cmd
+}
+
+// View implements tea.Model and records the frame when it changed
+func (r *Recorder) View() string {
+	frame := r.model.View()
+	if frame != r.lastFrame {
+		r.lastFrame = frame
+		r.record(Output, clearScreen+strings.ReplaceAll(frame, "\n", "\r\n"))
+	}
+	return frame
+}
+
+// Close writes the events still held, with the default size when the size
+// never became known, and returns the first error writing the recording
+func (r *Recorder) Close() error {
+	if !r.started {
+		r.writeHeader(DefaultWidth, DefaultHeight)
+	}
+	return r.err
+}
+
+// Err returns the first error writing the recording; recording stops at it
+func (r *Recorder) Err() error {
+	return r.err
+}
+
+// resize writes the header at the first resize and a resize event after it
+func (r *Recorder) resize(width, height int) {
+	if !r.started {
+		r.writeHeader(width, height)
+		return
+	}
+	r.record(Resize, fmt.Sprintf("%dx%d", width, height))
+}
+
+// writeHeader writes the header and the events held until then
+func (r *Recorder) writeHeader(width, height int) {
+	r.started = true
+	r.header.Width, r.header.Height = width, height
+	r.writeLine(r.header)
+	for _, event := range r.pending {
+		r.writeLine(event)
+	}
+	r.pending = nil
+}
+
+// record writes an event, or holds it until the header is written
+func (r *Recorder) record(kind, data string) {
+	event := Event{Time: r.now().Sub(r.start), Kind: kind, Data: data}
+	if !r.started {
+		r.pending = append(r.pending, event)
+		return
+	}
+	r.writeLine(event)
+}
+
+// writeLine writes value as a JSON line unless writing failed before
+func (r *Recorder) writeLine(value interface{}) {
+	if r.err != nil {
+		return
+	}
+	line, err := json.Marshal(value)
+	if err != nil {
+		r.err = fmt.Errorf("failed to encode recording: %w", err)
+		return
+	}
+	if _, err := r.out.Write(append(line, '\n')); err != nil {
+		r.err = fmt.Errorf("failed to write recording: %w", err)
+	}
+}
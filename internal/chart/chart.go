@@ -0,0 +1,325 @@
+// Package chart draws series of measurements such as latencies as line
+// charts in the terminal. Each cell holds several points: braille characters
+// give a cell two columns of four dots, half blocks a column of two, so a
+// chart shows several times more detail than one character per value.
+package chart
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+)
+
+// Mode selects the characters a chart is drawn with
+type Mode int
+
+const (
+	// Braille draws 2x4 dots per cell
+	Braille Mode = iota
+	// HalfBlock draws 1x2 blocks per cell, for fonts without braille
+	HalfBlock
+)
+
+// Sample is one value of a series. A missing sample, such as a lost ping,
+// leaves a gap in the line and a loss marker on the time axis.
+type Sample struct {
+	Value   float64
+	Missing bool
+}
+
+// Chart renders a series with a value axis on the left, a time axis below,
+// and optionally the moving average of the series over the line
+type Chart struct {
+	// Width and Height are the size of the whole chart in cells, including
+	// the axes
+	Width  int
+	Height int
+	Mode   Mode
+
+	// AverageWindow is the number of samples in the moving average, or 0 to
+	// draw none
+	AverageWindow int
+
+	// Label formats the values on the value axis
+	Label func(value float64) string
+
+	LineStyle    lipgloss.Style
+	AverageStyle lipgloss.Style
+	LossStyle    lipgloss.Style
+	AxisStyle    lipgloss.Style
+}
+
+// New creates a braille chart of width by height cells
+func New(width, height int) *Chart {
+	return &Chart{
+		Width:        width,
+		Height:       height,
+		Mode:         Braille,
+		Label:        formatLabel,
+		LineStyle:    lipgloss.NewStyle().Foreground(colors.Success),
+		AverageStyle: lipgloss.NewStyle().Foreground(colors.Warning),
+		LossStyle:    lipgloss.NewStyle().Foreground(colors.Error).Bold(true),
+		AxisStyle:    lipgloss.NewStyle().Foreground(colors.Muted),
+	}
+}
+
+// Durations turns durations into samples, with a negative duration marking
+// a missing sample
+func Durations(values []time.Duration) []Sample {
+	samples := make([]Sample, len(values))
+	for i, value := range values {
+		if value < 0 {
+			samples[i] = Sample{Missing: true}
+			continue
+		}
+		samples[i] = Sample{Value: float64(value)}
+	}
+	return samples
+}
+
+// DurationLabel labels the value axis of a chart of Durations
+func DurationLabel(value float64) string {
+	d := time.Duration(value)
+	switch {
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond).String()
+	case d >= 10*time.Millisecond:
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(10 * time.Microsecond).String()
+}
+
+// Render draws samples, oldest first, spread over the width of the chart
+func (c *Chart) Render(samples []Sample) string {
+	low, high, ok := bounds(samples)
+	if !ok {
+		low, high = 0, 1
+	}
+
+	// The value axis takes the width of its widest label
+	labels := []string{c.Label(high), c.Label((low + high) / 2), c.Label(low)}
+	labelWidth := 0
+	for _, label := range labels {
+		labelWidth = max(labelWidth, lipgloss.Width(label))
+	}
+	plotWidth := max(c.Width-labelWidth-1, 1)
+	plotHeight := max(c.Height-1, 1)
+
+	cellWidth, cellHeight := 2, 4
+	if c.Mode == HalfBlock {
+		cellWidth, cellHeight = 1, 2
+	}
+	line := newCanvas(plotWidth*cellWidth, plotHeight*cellHeight)
+	average := newCanvas(plotWidth*cellWidth, plotHeight*cellHeight)
+	lost := make([]bool, plotWidth)
+
+	y := func(value float64) int {
+		fraction := (value - low) / (high - low)
+		return int(math.Round((1 - fraction) * float64(line.height-1)))
+	}
+	x := func(i int) int {
+		return i * (line.width - 1) / max(len(samples)-1, 1)
+	}
+
+	averages := movingAverage(samples, c.AverageWindow)
+	for i, sample := range samples {
+		if sample.Missing {
+			lost[x(i)/cellWidth] = true
+			continue
+		}
+		line.plot(x(i), y(sample.Value), i > 0 && !samples[i-1].Missing, x(i-1), y(samples[max(i-1, 0)].Value))
+		if averages != nil {
+			average.plot(x(i), y(averages[i]), i > 0 && !samples[i-1].Missing, x(i-1), y(averages[max(i-1, 0)]))
+		}
+	}
+
+	var rows []string
+	for row := 0; row < plotHeight; row++ {
+		label := ""
+		tick := "│"
+		switch row {
+		case 0:
+			label, tick = labels[0], "┤"
+		case plotHeight / 2:
+			if plotHeight > 2 {
+				label, tick = labels[1], "┤"
+			}
+		case plotHeight - 1:
+			label, tick = labels[2], "┤"
+		}
+
+		var b strings.Builder
+		b.WriteString(c.AxisStyle.Render(strings.Repeat(" ", labelWidth-lipgloss.Width(label)) + label + tick))
+		for col := 0; col < plotWidth; col++ {
+			lineCell := line.cell(col, row, cellWidth, cellHeight)
+			averageCell := average.cell(col, row, cellWidth, cellHeight)
+			switch {
+			case lineCell != 0:
+				b.WriteString(c.LineStyle.Render(c.glyph(lineCell | averageCell)))
+			case averageCell != 0:
+				b.WriteString(c.AverageStyle.Render(c.glyph(averageCell)))
+			default:
+				b.WriteString(" ")
+			}
+		}
+		rows = append(rows, b.String())
+	}
+
+	// The time axis marks lost samples
+	var axis strings.Builder
+	axis.WriteString(c.AxisStyle.Render(strings.Repeat(" ", labelWidth) + "└"))
+	for _, loss := range lost {
+		if loss {
+			axis.WriteString(c.LossStyle.Render("×"))
+		} else {
+			axis.WriteString(c.AxisStyle.Render("─"))
+		}
+	}
+	rows = append(rows, axis.String())
+
+	return strings.Join(rows, "\n")
+}
+
+// glyph returns the character showing the points of a cell
+func (c *Chart) glyph(points uint8) string {
+	if c.Mode == HalfBlock {
+		switch points {
+		case 1:
+			return "▀"
+		case 2:
+			return "▄"
+		}
+		return "█"
+	}
+	return string(rune(0x2800) + rune(points))
+}
+
+// bounds returns the range of the values shown, padded by a tenth so the
+// line does not run along the edges
+func bounds(samples []Sample) (float64, float64, bool) {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, sample := range samples {
+		if sample.Missing {
+			continue
+		}
+		low = math.Min(low, sample.Value)
+		high = math.Max(high, sample.Value)
+	}
+	if math.IsInf(low, 1) {
+		return 0, 0, false
+	}
+
+	span := high - low
+	if span == 0 {
+		span = math.Abs(high) / 10
+		if span == 0 {
+			span = 1
+		}
+	}
+	return math.Max(low-span/10, 0), high + span/10, true
+}
+
+// movingAverage returns the average of each sample and the window-1 samples
+// before it, skipping missing ones, or nil without a window
+func movingAverage(samples []Sample, window int) []float64 {
+	if window <= 1 {
+		return nil
+	}
+	averages := make([]float64, len(samples))
+	sum, count := 0.0, 0
+	for i, sample := range samples {
+		if !sample.Missing {
+			sum += sample.Value
+			count++
+		}
+		if old := i - window; old >= 0 && !samples[old].Missing {
+			sum -= samples[old].Value
+			count--
+		}
+		if count > 0 {
+			averages[i] = sum / float64(count)
+		}
+	}
+	return averages
+}
+
+// formatLabel formats a value with up to two decimals
+func formatLabel(value float64) string {
+	label := strconv.FormatFloat(value, 'f', 2, 64)
+	return strings.TrimSuffix(strings.TrimRight(label, "0"), ".")
+}
+
+// canvas is a grid of points that cells of several points are read from
+type canvas struct {
+	width, height int
+	points        [][]bool
+}
+
+// newCanvas creates an empty canvas of width by height points
+func newCanvas(width, height int) *canvas {
+	points := make([][]bool, height)
+	for y := range points {
+		points[y] = make([]bool, width)
+	}
+	return &canvas{width: width, height: height, points: points}
+}
+
+// set marks the point at x, y if it is on the canvas
+func (c *canvas) set(x, y int) {
+	if x >= 0 && x < c.width && y >= 0 && y < c.height {
+		c.points[y][x] = true
+	}
+}
+
+// plot marks the point at x, y and, when joined, the points between it and
+// the previous point at fromX, fromY, so steep changes draw a solid line
+func (c *canvas) plot(x, y int, joined bool, fromX, fromY int) {
+	c.set(x, y)
+	if !joined {
+		return
+	}
+	steps := max(abs(y-fromY), x-fromX)
+	for step := 1; step < steps; step++ {
+		c.set(fromX+(x-fromX)*step/steps, fromY+(y-fromY)*step/steps)
+	}
+}
+
+// cell returns the points of the cell at col, row as a bit mask. Braille
+// cells number their dots down the left column and then the right, with the
+// bottom row last; half block cells use bit 1 for the top and 2 for the
+// bottom.
+func (c *canvas) cell(col, row, cellWidth, cellHeight int) uint8 {
+	var mask uint8
+	for dy := 0; dy < cellHeight; dy++ {
+		for dx := 0; dx < cellWidth; dx++ {
+			if !c.points[row*cellHeight+dy][col*cellWidth+dx] {
+				continue
+			}
+			mask |= dotBit(dx, dy, cellHeight)
+		}
+	}
+	return mask
+}
+
+// dotBit returns the bit of the point at dx, dy within a cell
+func dotBit(dx, dy, cellHeight int) uint8 {
+	if cellHeight == 2 {
+		return 1 << dy
+	}
+	if dy == 3 {
+		return 0x40 << dx
+	}
+	return 1 << (dy + 3*dx)
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
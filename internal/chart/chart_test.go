@@ -0,0 +1,98 @@
+package chart
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// plotRows returns the rendered rows without styles and the value axis
+func plotRows(t *testing.T, rendered string) []string {
+	t.Helper()
+	var rows []string
+	for _, row := range strings.Split(ansi.Strip(rendered), "\n") {
+		if i := strings.IndexAny(row, "│┤└"); i >= 0 {
+			_, size := utf8.DecodeRuneInString(row[i:])
+			row = row[i+size:]
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func TestChart_Braille(t *testing.T) {
+	c := New(12, 3)
+	rows := plotRows(t, c.Render([]Sample{{Value: 0}, {Value: 10}}))
+	if len(rows) != 3 {
+		t.Fatalf("Expected two plot rows and the time axis, got %q", rows)
+	}
+
+	// The rising line starts in the bottom left dot and climbs to the right
+	first := []rune(rows[1])[0]
+	if first < 0x2800 || (first-0x2800)&0x40 == 0 {
+		t.Errorf("Expected the first value in the bottom left dot, got %q", rows)
+	}
+	if top := strings.TrimRight(rows[0], " "); len(top) < len(rows[0]) {
+		t.Errorf("Expected the last value at the right edge, got %q", rows)
+	}
+	for _, r := range rows[0] + rows[1] {
+		if r != ' ' && (r < 0x2800 || r > 0x28ff) {
+			t.Errorf("Expected only braille in the plot, got %q", r)
+		}
+	}
+
+	if !strings.Contains(ansi.Strip(c.Render(nil)), "└") {
+		t.Error("Expected the axes without samples")
+	}
+}
+
+func TestChart_HalfBlock(t *testing.T) {
+	c := New(12, 2)
+	c.Mode = HalfBlock
+	rows := plotRows(t, c.Render([]Sample{{Value: 10}, {Value: 10}}))
+	if strings.Trim(rows[0], "▄") != "" {
+		t.Errorf("Expected a flat line of half blocks, got %q", rows)
+	}
+}
+
+func TestChart_LossMarkers(t *testing.T) {
+	c := New(12, 3)
+	rendered := ansi.Strip(c.Render([]Sample{{Value: 1}, {Missing: true}, {Value: 1}}))
+	axis := rendered[strings.LastIndex(rendered, "\n")+1:]
+	if strings.Count(axis, "×") != 1 {
+		t.Errorf("Expected one loss marker on the time axis, got %q", axis)
+	}
+}
+
+func TestChart_ValueAxis(t *testing.T) {
+	c := New(30, 5)
+	c.Label = DurationLabel
+	rendered := ansi.Strip(c.Render(Durations([]time.Duration{10 * time.Millisecond, 50 * time.Millisecond, -1})))
+	for _, label := range []string{"54ms┤", "6ms┤"} {
+		if !strings.Contains(rendered, label) {
+			t.Errorf("Expected the label %q, got:\n%s", label, rendered)
+		}
+	}
+	for _, row := range strings.Split(rendered, "\n") {
+		if width := ansi.StringWidth(row); width != 30 {
+			t.Errorf("Expected rows of the chart width, got %d in %q", width, row)
+		}
+	}
+}
+
+func TestMovingAverage(t *testing.T) {
+	averages := movingAverage([]Sample{{Value: 2}, {Value: 4}, {Missing: true}, {Value: 8}}, 2)
+	want := []float64{2, 3, 4, 8}
+	for i := range want {
+		if averages[i] != want[i] {
+			t.Errorf("Expected averages %v, got %v", want, averages)
+			break
+		}
+	}
+	if movingAverage([]Sample{{Value: 1}}, 0) != nil {
+		t.Error("Expected no average without a window")
+	}
+}
@@ -0,0 +1,115 @@
+// Package clipboard copies text to the system clipboard from Bubble Tea
+// models and provides the toast that confirms the copy
+package clipboard
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	systemclipboard "github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// ToastDuration is how long a confirmation toast stays visible
+const ToastDuration = 3 * time.Second
+
+var (
+	// writeAll writes to the system clipboard; replaced in tests
+	writeAll = systemclipboard.WriteAll
+	// osc52Output receives the OSC 52 escape sequence when no system
+	// clipboard is reachable; replaced in tests
+	osc52Output io.Writer = os.Stderr
+	// toastIDs distinguishes toasts so an expiry only hides its own toast
+	toastIDs atomic.Int64
+)
+
+// CopiedMsg is sent when text has been copied to the clipboard
+type CopiedMsg struct {
+	Description string
+	Error       error
+}
+
+// Status returns the confirmation shown to the user
+func (m CopiedMsg) Status() string {
+	if m.Error != nil {
+		return fmt.Sprintf("Copy failed: %v", m.Error)
+	}
+	return fmt.Sprintf("Copied %s to clipboard", m.Description)
+}
+
+// Copy copies text to the system clipboard, falling back to an OSC 52
+// terminal escape sequence so copying also works over SSH
+func Copy(text, description string) tea.Cmd {
+	return func() tea.Msg {
+		if err := writeAll(text); err == nil {
+			return CopiedMsg{Description: description}
+		}
+
+		sequence := osc52.New(text)
+		switch {
+		case os.Getenv("TMUX") != "":
+			sequence = sequence.Tmux()
+		case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+			sequence = sequence.Screen()
+		}
+		if _, err := sequence.WriteTo(osc52Output); err != nil {
+			return CopiedMsg{Description: description, Error: err}
+		}
+		return CopiedMsg{Description: description}
+	}
+}
+
+// CopyResult copies result data as plain text to the clipboard
+func CopyResult(data interface{}) tea.Cmd {
+	text, err := domain.NewResult(data).Export(domain.ExportFormatText)
+	if err != nil {
+		return func() tea.Msg {
+			return CopiedMsg{Description: "result", Error: err}
+		}
+	}
+	return Copy(string(text), "result")
+}
+
+// ToastExpiredMsg is sent when a toast should be hidden
+type ToastExpiredMsg struct {
+	ID int64
+}
+
+// Toast is a short confirmation message that hides itself after ToastDuration
+type Toast struct {
+	id      int64
+	message string
+}
+
+// Show displays message and returns the command that hides it again
+func (t *Toast) Show(message string) tea.Cmd {
+	t.id = toastIDs.Add(1)
+	t.message = message
+	id := t.id
+	return tea.Tick(ToastDuration, func(time.Time) tea.Msg {
+		return ToastExpiredMsg{ID: id}
+	})
+}
+
+// Update hides the toast when its expiry message arrives
+func (t *Toast) Update(msg tea.Msg) {
+	if expired, ok := msg.(ToastExpiredMsg); ok && expired.ID == t.id {
+		t.message = ""
+	}
+}
+
+// Clear hides the toast immediately
+func (t *Toast) Clear() {
+	t.message = ""
+}
+
+// Message returns the visible message, or an empty string
+func (t *Toast) Message() string {
+	return t.message
+}
@@ -0,0 +1,89 @@
+package clipboard
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubClipboard replaces the system clipboard and OSC 52 output for a test
+func stubClipboard(t *testing.T, err error) (*string, *bytes.Buffer) {
+	t.Helper()
+	previousWriteAll, previousOutput := writeAll, osc52Output
+	t.Cleanup(func() {
+		writeAll, osc52Output = previousWriteAll, previousOutput
+	})
+
+	var copied string
+	var output bytes.Buffer
+	writeAll = func(text string) error {
+		if err != nil {
+			return err
+		}
+		copied = text
+		return nil
+	}
+	osc52Output = &output
+	return &copied, &output
+}
+
+func TestCopy_SystemClipboard(t *testing.T) {
+	copied, output := stubClipboard(t, nil)
+
+	msg, ok := Copy("hello", "greeting")().(CopiedMsg)
+	require.True(t, ok)
+	assert.NoError(t, msg.Error)
+	assert.Equal(t, "Copied greeting to clipboard", msg.Status())
+	assert.Equal(t, "hello", *copied)
+	assert.Zero(t, output.Len())
+}
+
+func TestCopy_FallsBackToOSC52(t *testing.T) {
+	t.Setenv("TMUX", "")
+	t.Setenv("TERM", "xterm-256color")
+	_, output := stubClipboard(t, errors.New("no clipboard utilities available"))
+
+	msg, ok := Copy("hello", "greeting")().(CopiedMsg)
+	require.True(t, ok)
+	assert.NoError(t, msg.Error)
+	assert.Equal(t, "\x1b]52;c;"+base64.StdEncoding.EncodeToString([]byte("hello"))+"\x07", output.String())
+}
+
+func TestCopyResult(t *testing.T) {
+	copied, _ := stubClipboard(t, nil)
+
+	msg, ok := CopyResult(domain.DNSResult{Query: "example.com"})().(CopiedMsg)
+	require.True(t, ok)
+	assert.Equal(t, "result", msg.Description)
+	assert.Contains(t, *copied, "DNS Query: example.com")
+}
+
+func TestCopiedMsg_StatusOnError(t *testing.T) {
+	msg := CopiedMsg{Description: "result", Error: errors.New("terminal closed")}
+	assert.Equal(t, "Copy failed: terminal closed", msg.Status())
+}
+
+func TestToast(t *testing.T) {
+	var toast Toast
+	first := toast.Show("first")
+	require.NotNil(t, first)
+	firstID := toast.id
+	assert.Equal(t, "first", toast.Message())
+
+	// An expiry of an earlier toast does not hide a newer one
+	toast.Show("second")
+	toast.Update(ToastExpiredMsg{ID: firstID})
+	assert.Equal(t, "second", toast.Message())
+
+	toast.Update(ToastExpiredMsg{ID: toast.id})
+	assert.Empty(t, toast.Message())
+
+	toast.Show("third")
+	toast.Clear()
+	assert.Empty(t, toast.Message())
+}
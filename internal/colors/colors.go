@@ -0,0 +1,105 @@
+// Package colors provides the colors of the TUI, degraded to what the terminal can show
+package colors
+
+import (
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Color modes of ui.color_mode
+const (
+	ModeAuto   = "auto"
+	ModeAlways = "always"
+	ModeNever  = "never"
+)
+
+// ansiFallbacks maps the 256-color codes of the TUI to the 16-color code
+// shown instead on terminals without 256 colors. Hues map to the eight base
+// colors, which 8-color terminals show as well.
+var ansiFallbacks = map[string]string{
+	"25":  "4",
+	"28":  "2",
+	"33":  "4",
+	"39":  "6",
+	"42":  "2",
+	"46":  "2",
+	"57":  "5",
+	"62":  "4",
+	"86":  "6",
+	"125": "5",
+	"130": "3",
+	"136": "3",
+	"160": "1",
+	"196": "1",
+	"205": "5",
+	"214": "3",
+	"226": "3",
+	"230": "7",
+	"235": "0",
+	"236": "0",
+	"237": "0",
+	"240": "8",
+	"241": "8",
+	"243": "8",
+	"245": "7",
+	"250": "7",
+	"252": "7",
+	"255": "7",
+}
+
+// Named colors of the TUI
+var (
+	Primary   = Adaptive("62")
+	Info      = Adaptive("39")
+	Accent    = Adaptive("205")
+	Success   = Adaptive("42")
+	Online    = Adaptive("46")
+	Warning   = Adaptive("214")
+	Error     = Adaptive("196")
+	Text      = Adaptive("252")
+	Highlight = Adaptive("230")
+	Muted     = Adaptive("243")
+	Subtle    = Adaptive("241")
+	Border    = Adaptive("240")
+	Surface   = Adaptive("236")
+)
+
+// Adaptive returns color, a 256-color code, a 16-color code or a hex color,
+// as a color that shows the chosen 16-color equivalent of a 256-color code
+// on terminals without 256 colors. Other colors degrade to the closest
+// color the terminal has.
+func Adaptive(color string) lipgloss.TerminalColor {
+	if ansi, ok := ansiFallbacks[color]; ok {
+		return lipgloss.CompleteColor{TrueColor: color, ANSI256: color, ANSI: ansi}
+	}
+	return lipgloss.Color(color)
+}
+
+// Profile returns the color profile for mode on a terminal that shows the
+// colors of detected: never shows none, always shows colors even where
+// NO_COLOR is set or output is not a terminal, and auto shows those of the
+// terminal unless NO_COLOR is set.
+func Profile(mode string, detected termenv.Profile, noColor bool) termenv.Profile {
+	switch mode {
+	case ModeNever:
+		return termenv.Ascii
+	case ModeAlways:
+		if detected == termenv.Ascii {
+			return termenv.ANSI256
+		}
+		return detected
+	}
+	if noColor {
+		return termenv.Ascii
+	}
+	return detected
+}
+
+// Apply sets the color profile every lipgloss style renders with for mode,
+// the ui.color_mode setting
+func Apply(mode string) {
+	detected := termenv.NewOutput(os.Stdout).ColorProfile()
+	lipgloss.SetColorProfile(Profile(mode, detected, os.Getenv("NO_COLOR") != ""))
+}
@@ -0,0 +1,67 @@
+// Package colors provides color degradation tests
+package colors
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func TestAdaptive(t *testing.T) {
+	color, ok := Adaptive("62").(lipgloss.CompleteColor)
+	if !ok {
+		t.Fatalf("expected a complete color for a 256-color code, got %T", Adaptive("62"))
+	}
+	if color.ANSI256 != "62" || color.ANSI != "4" {
+		t.Errorf("expected 62 to degrade to blue, got %+v", color)
+	}
+
+	if got := Adaptive("#5fafff"); got != lipgloss.Color("#5fafff") {
+		t.Errorf("expected hex colors to be kept, got %v", got)
+	}
+	if got := Adaptive("9"); got != lipgloss.Color("9") {
+		t.Errorf("expected 16-color codes to be kept, got %v", got)
+	}
+}
+
+func TestAdaptive_Renders(t *testing.T) {
+	renderer := lipgloss.NewRenderer(nil)
+	style := renderer.NewStyle().Foreground(Warning)
+
+	renderer.SetColorProfile(termenv.ANSI)
+	if got := style.Render("x"); got != "\x1b[33mx\x1b[0m" {
+		t.Errorf("expected yellow on 16-color terminals, got %q", got)
+	}
+	renderer.SetColorProfile(termenv.ANSI256)
+	if got := style.Render("x"); got != "\x1b[38;5;214mx\x1b[0m" {
+		t.Errorf("expected 214 on 256-color terminals, got %q", got)
+	}
+	renderer.SetColorProfile(termenv.Ascii)
+	if got := style.Render("x"); got != "x" {
+		t.Errorf("expected no color, got %q", got)
+	}
+}
+
+func TestProfile(t *testing.T) {
+	tests := []struct {
+		mode     string
+		detected termenv.Profile
+		noColor  bool
+		want     termenv.Profile
+	}{
+		{ModeAuto, termenv.ANSI256, false, termenv.ANSI256},
+		{ModeAuto, termenv.ANSI, false, termenv.ANSI},
+		{ModeAuto, termenv.TrueColor, true, termenv.Ascii},
+		{"", termenv.ANSI256, false, termenv.ANSI256},
+		{ModeAlways, termenv.Ascii, true, termenv.ANSI256},
+		{ModeAlways, termenv.ANSI, true, termenv.ANSI},
+		{ModeNever, termenv.TrueColor, false, termenv.Ascii},
+	}
+
+	for _, tt := range tests {
+		if got := Profile(tt.mode, tt.detected, tt.noColor); got != tt.want {
+			t.Errorf("Profile(%q, %v, %v) = %v, want %v", tt.mode, tt.detected, tt.noColor, got, tt.want)
+		}
+	}
+}
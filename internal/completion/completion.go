@@ -0,0 +1,177 @@
+// Package completion writes shell completion scripts for the flags and
+// commands of a program
+package completion
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Shells lists the shells scripts are written for
+var Shells = []string{"bash", "zsh", "fish"}
+
+// Command is a subcommand of the program, completed as its first argument
+type Command struct {
+	Name  string
+	Usage string
+}
+
+// option is a flag as the scripts complete it
+type option struct {
+	name  string
+	usage string
+	value bool // takes a value
+	file  bool // the value is a file name
+}
+
+// options returns the flags of set in name order. Flags whose usage
+// mentions a file complete file names.
+func options(set *flag.FlagSet) []option {
+	var opts []option
+	set.VisitAll(func(f *flag.Flag) {
+		opt := option{name: f.Name, usage: firstLine(f.Usage), value: true}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			opt.value = false
+		}
+		opt.file = opt.value && strings.Contains(strings.ToLower(f.Usage), "file")
+		opts = append(opts, opt)
+	})
+	sort.Slice(opts, func(i, j int) bool { return opts[i].name < opts[j].name })
+	return opts
+}
+
+// firstLine returns the first line of a usage text
+func firstLine(usage string) string {
+	line, _, _ := strings.Cut(usage, "\n")
+	return line
+}
+
+// Write writes the completion script for shell of program, whose flags are
+// set and whose subcommands are commands
+func Write(w io.Writer, shell, program string, set *flag.FlagSet, commands []Command) error {
+	opts := options(set)
+	switch shell {
+	case "bash":
+		return writeBash(w, program, opts, commands)
+	case "zsh":
+		return writeZsh(w, program, opts, commands)
+	case "fish":
+		return writeFish(w, program, opts, commands)
+	}
+	return fmt.Errorf("unsupported shell %q, expected one of %s", shell, strings.Join(Shells, ", "))
+}
+
+// identifier turns program into a shell function name
+func identifier(program string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, program)
+}
+
+func writeBash(w io.Writer, program string, opts []option, commands []Command) error {
+	var flags, files, values, names []string
+	for _, opt := range opts {
+		flags = append(flags, "-"+opt.name)
+		switch {
+		case opt.file:
+			files = append(files, "-"+opt.name, "--"+opt.name)
+		case opt.value:
+			values = append(values, "-"+opt.name, "--"+opt.name)
+		}
+	}
+	for _, command := range commands {
+		names = append(names, command.Name)
+	}
+
+	var b strings.Builder
+	fn := "_" + identifier(program)
+	fmt.Fprintf(&b, "# bash completion for %s\n", program)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("    local cur prev\n")
+	b.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("    prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	b.WriteString("    case \"$prev\" in\n")
+	if len(files) > 0 {
+		fmt.Fprintf(&b, "        %s)\n            COMPREPLY=($(compgen -f -- \"$cur\"))\n            return ;;\n", strings.Join(files, "|"))
+	}
+	if len(values) > 0 {
+		fmt.Fprintf(&b, "        %s)\n            return ;;\n", strings.Join(values, "|"))
+	}
+	b.WriteString("    esac\n")
+	b.WriteString("    if [[ \"$cur\" == -* ]]; then\n")
+	fmt.Fprintf(&b, "        COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", strings.Join(flags, " "))
+	b.WriteString("        return\n")
+	b.WriteString("    fi\n")
+	if len(names) > 0 {
+		fmt.Fprintf(&b, "    COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", strings.Join(names, " "))
+	}
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "complete -F %s %s\n", fn, program)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// zshQuote quotes text for a single-quoted _arguments spec, where brackets
+// and colons of descriptions are escaped
+func zshQuote(text string) string {
+	text = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`, ":", `\:`).Replace(text)
+	return strings.ReplaceAll(text, "'", `'\''`)
+}
+
+func writeZsh(w io.Writer, program string, opts []option, commands []Command) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#compdef %s\n\n", program)
+	b.WriteString("_arguments \\\n")
+	for _, opt := range opts {
+		spec := "-" + opt.name + "[" + zshQuote(opt.usage) + "]"
+		switch {
+		case opt.file:
+			spec += ":file:_files"
+		case opt.value:
+			spec += ":value: "
+		}
+		fmt.Fprintf(&b, "  '%s' \\\n", spec)
+	}
+	if len(commands) > 0 {
+		var names []string
+		for _, command := range commands {
+			names = append(names, command.Name+`\:"`+strings.ReplaceAll(zshQuote(command.Usage), `"`, `\"`)+`"`)
+		}
+		fmt.Fprintf(&b, "  '1: :((%s))' \\\n", strings.Join(names, " "))
+	}
+	b.WriteString("  '*:: :_files'\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// fishQuote quotes text as a single-quoted fish string
+func fishQuote(text string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(text) + "'"
+}
+
+func writeFish(w io.Writer, program string, opts []option, commands []Command) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# fish completion for %s\n", program)
+	fmt.Fprintf(&b, "complete -c %s -f\n", program)
+	for _, opt := range opts {
+		line := fmt.Sprintf("complete -c %s -o %s", program, opt.name)
+		switch {
+		case opt.file:
+			line += " -r -F"
+		case opt.value:
+			line += " -r"
+		}
+		fmt.Fprintf(&b, "%s -d %s\n", line, fishQuote(opt.usage))
+	}
+	for _, command := range commands {
+		fmt.Fprintf(&b, "complete -c %s -n __fish_use_subcommand -a %s -d %s\n", program, command.Name, fishQuote(command.Usage))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,70 @@
+package completion
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func testFlags() *flag.FlagSet {
+	set := flag.NewFlagSet("nettracex", flag.ContinueOnError)
+	set.Bool("version", false, "Show version information")
+	set.String("config", "", "Load the configuration from this YAML, TOML or JSON file")
+	set.String("batch", "", "Run a tool against a target list [it's fast]: try it")
+	return set
+}
+
+var testCommands = []Command{
+	{Name: "update", Usage: "Update to the latest release"},
+	{Name: "completion", Usage: "Print a shell completion script"},
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{"bash", []string{
+			"complete -F _nettracex nettracex",
+			`-config|--config)` + "\n" + `            COMPREPLY=($(compgen -f -- "$cur"))`,
+			"-batch|--batch)\n            return ;;",
+			`compgen -W "-batch -config -version"`,
+			`compgen -W "update completion"`,
+		}},
+		{"zsh", []string{
+			"#compdef nettracex",
+			`'-version[Show version information]' \`,
+			`'-config[Load the configuration from this YAML, TOML or JSON file]:file:_files' \`,
+			`'-batch[Run a tool against a target list \[it'\''s fast\]\: try it]:value: ' \`,
+			`'1: :((update\:"Update to the latest release" completion\:"Print a shell completion script"))'`,
+		}},
+		{"fish", []string{
+			"complete -c nettracex -f\n",
+			"complete -c nettracex -o version -d 'Show version information'",
+			"complete -c nettracex -o config -r -F -d",
+			`complete -c nettracex -o batch -r -d 'Run a tool against a target list [it\'s fast]: try it'`,
+			"complete -c nettracex -n __fish_use_subcommand -a update -d 'Update to the latest release'",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			var b strings.Builder
+			if err := Write(&b, tt.shell, "nettracex", testFlags(), testCommands); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(b.String(), want) {
+					t.Errorf("Expected %q in\n%s", want, b.String())
+				}
+			}
+		})
+	}
+}
+
+func TestWrite_UnknownShell(t *testing.T) {
+	var b strings.Builder
+	if err := Write(&b, "tcsh", "nettracex", testFlags(), nil); err == nil {
+		t.Error("Expected an error for an unsupported shell")
+	}
+}
@@ -3,23 +3,41 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"text/template"
 	"time"
 
+	"github.com/nettracex/nettracex-tui/internal/appdir"
+	"github.com/nettracex/nettracex-tui/internal/colors"
 	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
+	"github.com/nettracex/nettracex-tui/internal/proxy"
+	"github.com/nettracex/nettracex-tui/internal/secrets"
+	"github.com/nettracex/nettracex-tui/internal/update"
 	"github.com/spf13/viper"
 )
 
-// Manager implements the ConfigurationManager interface
+// Manager implements the ConfigurationManager interface. The configuration
+// it hands out is an immutable snapshot: changes publish a new one, so
+// goroutines holding the previous snapshot never see it modified.
 type Manager struct {
-	config     *domain.Config
+	config     atomic.Pointer[domain.Config]
 	viper      *viper.Viper
 	configFile string
 	validator  *Validator
 	listeners  []ConfigChangeListener
+	flags      *Flags
+	settings   map[string]bool
+	secrets    secrets.Store
+	upgraded   *Upgrade
 }
 
 // ConfigChangeListener defines a callback for configuration changes
@@ -27,14 +45,29 @@ type ConfigChangeListener func(key string, oldValue, newValue interface{})
 
 // NewManager creates a new configuration manager
 func NewManager() *Manager {
+	m := &Manager{
+		viper:     newViper(),
+		validator: NewValidator(),
+		listeners: make([]ConfigChangeListener, 0),
+		secrets:   secrets.Default(),
+	}
+	m.config.Store(&domain.Config{})
+	return m
+}
+
+// newViper creates a viper instance with the search paths, environment
+// bindings and defaults of the configuration
+func newViper() *viper.Viper {
 	v := viper.New()
 	
-	// Set configuration file properties
+	// Set configuration file properties; the format of a found file
+	// follows its extension, e.g. nettracex.yaml or nettracex.toml
 	v.SetConfigName("nettracex")
-	v.SetConfigType("yaml")
 	
 	// Add configuration paths
 	v.AddConfigPath(".")
+	v.AddConfigPath(appdir.ConfigDir())
+	// Where earlier releases kept it on every system, e.g. on macOS
 	v.AddConfigPath("$HOME/.config/nettracex")
 	v.AddConfigPath("/etc/nettracex")
 	
@@ -49,14 +82,7 @@ func NewManager() *Manager {
 	// Set default values
 	setDefaults(v)
 	
-	validator := NewValidator()
-	
-	return &Manager{
-		config:    &domain.Config{},
-		viper:     v,
-		validator: validator,
-		listeners: make([]ConfigChangeListener, 0),
-	}
+	return v
 }
 
 // bindEnvironmentVariables binds all configuration keys to environment variables
@@ -70,6 +96,21 @@ func bindEnvironmentVariables(v *viper.Viper) {
 	v.BindEnv("network.max_concurrency", "NETTRACEX_NETWORK_MAX_CONCURRENCY")
 	v.BindEnv("network.retry_attempts", "NETTRACEX_NETWORK_RETRY_ATTEMPTS")
 	v.BindEnv("network.retry_delay", "NETTRACEX_NETWORK_RETRY_DELAY")
+	v.BindEnv("network.min_ping_interval", "NETTRACEX_NETWORK_MIN_PING_INTERVAL")
+	v.BindEnv("network.max_flood_count", "NETTRACEX_NETWORK_MAX_FLOOD_COUNT")
+	v.BindEnv("network.rate_limit", "NETTRACEX_NETWORK_RATE_LIMIT")
+	v.BindEnv("network.rate_burst", "NETTRACEX_NETWORK_RATE_BURST")
+	v.BindEnv("network.proxy.whois", "NETTRACEX_NETWORK_PROXY_WHOIS")
+	v.BindEnv("network.proxy.ssl", "NETTRACEX_NETWORK_PROXY_SSL")
+	v.BindEnv("network.proxy.http", "NETTRACEX_NETWORK_PROXY_HTTP")
+	v.BindEnv("network.proxy.tcp", "NETTRACEX_NETWORK_PROXY_TCP")
+	v.BindEnv("network.cache.enabled", "NETTRACEX_NETWORK_CACHE_ENABLED")
+	v.BindEnv("network.cache.persist", "NETTRACEX_NETWORK_CACHE_PERSIST")
+	v.BindEnv("network.cache.path", "NETTRACEX_NETWORK_CACHE_PATH")
+	v.BindEnv("network.cache.max_entries", "NETTRACEX_NETWORK_CACHE_MAX_ENTRIES")
+	v.BindEnv("network.cache.whois_ttl", "NETTRACEX_NETWORK_CACHE_WHOIS_TTL")
+	v.BindEnv("network.cache.max_ttl", "NETTRACEX_NETWORK_CACHE_MAX_TTL")
+	v.BindEnv("network.cache.negative_ttl", "NETTRACEX_NETWORK_CACHE_NEGATIVE_TTL")
 	
 	// UI configuration
 	v.BindEnv("ui.theme", "NETTRACEX_UI_THEME")
@@ -78,6 +119,18 @@ func bindEnvironmentVariables(v *viper.Viper) {
 	v.BindEnv("ui.refresh_interval", "NETTRACEX_UI_REFRESH_INTERVAL")
 	v.BindEnv("ui.show_help", "NETTRACEX_UI_SHOW_HELP")
 	v.BindEnv("ui.color_mode", "NETTRACEX_UI_COLOR_MODE")
+	v.BindEnv("ui.key_mode", "NETTRACEX_UI_KEY_MODE")
+	v.BindEnv("ui.language", "NETTRACEX_UI_LANGUAGE")
+	v.BindEnv("ui.accessible", "NETTRACEX_UI_ACCESSIBLE")
+	v.BindEnv("ui.reduced_motion", "NETTRACEX_UI_REDUCED_MOTION")
+	v.BindEnv("ui.check_updates", "NETTRACEX_UI_CHECK_UPDATES")
+	v.BindEnv("ui.update_channel", "NETTRACEX_UI_UPDATE_CHANNEL")
+	v.BindEnv("ui.dashboard.show_on_start", "NETTRACEX_UI_DASHBOARD_SHOW_ON_START")
+	v.BindEnv("ui.dashboard.widgets", "NETTRACEX_UI_DASHBOARD_WIDGETS")
+	v.BindEnv("ui.dashboard.hosts", "NETTRACEX_UI_DASHBOARD_HOSTS")
+	v.BindEnv("ui.dashboard.certificates", "NETTRACEX_UI_DASHBOARD_CERTIFICATES")
+	v.BindEnv("ui.dashboard.checks", "NETTRACEX_UI_DASHBOARD_CHECKS")
+	v.BindEnv("ui.dashboard.interval", "NETTRACEX_UI_DASHBOARD_INTERVAL")
 	
 	// Plugin configuration
 	v.BindEnv("plugins.enabled_plugins", "NETTRACEX_PLUGINS_ENABLED_PLUGINS")
@@ -94,9 +147,35 @@ func bindEnvironmentVariables(v *viper.Viper) {
 	v.BindEnv("logging.level", "NETTRACEX_LOGGING_LEVEL")
 	v.BindEnv("logging.format", "NETTRACEX_LOGGING_FORMAT")
 	v.BindEnv("logging.output", "NETTRACEX_LOGGING_OUTPUT")
+	v.BindEnv("logging.file", "NETTRACEX_LOGGING_FILE")
 	v.BindEnv("logging.max_size", "NETTRACEX_LOGGING_MAX_SIZE")
 	v.BindEnv("logging.max_backups", "NETTRACEX_LOGGING_MAX_BACKUPS")
 	v.BindEnv("logging.max_age", "NETTRACEX_LOGGING_MAX_AGE")
+	
+	// Policy configuration
+	v.BindEnv("policy.public_target_mode", "NETTRACEX_POLICY_PUBLIC_TARGET_MODE")
+	v.BindEnv("policy.allow_list", "NETTRACEX_POLICY_ALLOW_LIST")
+	v.BindEnv("policy.active_tools", "NETTRACEX_POLICY_ACTIVE_TOOLS")
+	v.BindEnv("policy.audit_log", "NETTRACEX_POLICY_AUDIT_LOG")
+	
+	// Notification configuration
+	v.BindEnv("notify.webhooks", "NETTRACEX_NOTIFY_WEBHOOKS")
+	v.BindEnv("notify.webhook_template", "NETTRACEX_NOTIFY_WEBHOOK_TEMPLATE")
+	v.BindEnv("notify.slack_webhook", "NETTRACEX_NOTIFY_SLACK_WEBHOOK")
+	v.BindEnv("notify.discord_webhook", "NETTRACEX_NOTIFY_DISCORD_WEBHOOK")
+	v.BindEnv("notify.host_down_after", "NETTRACEX_NOTIFY_HOST_DOWN_AFTER")
+	v.BindEnv("notify.cert_expiry_days", "NETTRACEX_NOTIFY_CERT_EXPIRY_DAYS")
+	v.BindEnv("notify.packet_loss_percent", "NETTRACEX_NOTIFY_PACKET_LOSS_PERCENT")
+	
+	// Telemetry configuration
+	v.BindEnv("telemetry.enabled", "NETTRACEX_TELEMETRY_ENABLED")
+	v.BindEnv("telemetry.endpoint", "NETTRACEX_TELEMETRY_ENDPOINT")
+	v.BindEnv("telemetry.interval", "NETTRACEX_TELEMETRY_INTERVAL")
+	
+	// Key bindings
+	for _, action := range domain.DefaultKeyConfig().Actions() {
+		v.BindEnv("keys."+action.Name, "NETTRACEX_KEYS_"+strings.ToUpper(action.Name))
+	}
 }
 
 // setDefaults sets default configuration values
@@ -110,14 +189,41 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("network.max_concurrency", 10)
 	v.SetDefault("network.retry_attempts", 3)
 	v.SetDefault("network.retry_delay", "1s")
+	v.SetDefault("network.min_ping_interval", "200ms")
+	v.SetDefault("network.max_flood_count", 1000)
+	v.SetDefault("network.rate_limit", 10.0)
+	v.SetDefault("network.rate_burst", 5)
+	v.SetDefault("network.proxy.whois", "")
+	v.SetDefault("network.proxy.ssl", "")
+	v.SetDefault("network.proxy.http", "")
+	v.SetDefault("network.proxy.tcp", "")
+	v.SetDefault("network.cache.enabled", true)
+	v.SetDefault("network.cache.persist", true)
+	v.SetDefault("network.cache.path", "")
+	v.SetDefault("network.cache.max_entries", 1000)
+	v.SetDefault("network.cache.whois_ttl", "1h")
+	v.SetDefault("network.cache.max_ttl", "1h")
+	v.SetDefault("network.cache.negative_ttl", "1m")
 	
 	// UI defaults
-	v.SetDefault("ui.theme", "default")
+	v.SetDefault("ui.theme", domain.ThemeAuto)
 	v.SetDefault("ui.animation_speed", "250ms")
 	v.SetDefault("ui.auto_refresh", false)
 	v.SetDefault("ui.refresh_interval", "5s")
 	v.SetDefault("ui.show_help", true)
 	v.SetDefault("ui.color_mode", "auto")
+	v.SetDefault("ui.key_mode", domain.KeyModeDefault)
+	v.SetDefault("ui.language", i18n.Auto)
+	v.SetDefault("ui.accessible", false)
+	v.SetDefault("ui.reduced_motion", false)
+	v.SetDefault("ui.check_updates", true)
+	v.SetDefault("ui.update_channel", string(update.Stable))
+	v.SetDefault("ui.dashboard.show_on_start", true)
+	v.SetDefault("ui.dashboard.widgets", append([]string(nil), domain.DashboardWidgets...))
+	v.SetDefault("ui.dashboard.hosts", []string{})
+	v.SetDefault("ui.dashboard.certificates", []string{})
+	v.SetDefault("ui.dashboard.checks", []string{})
+	v.SetDefault("ui.dashboard.interval", "1m")
 	
 	// Default key bindings
 	keyBindings := map[string]string{
@@ -146,6 +252,7 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("plugins.disabled_plugins", []string{})
 	v.SetDefault("plugins.plugin_paths", []string{"./plugins"})
 	v.SetDefault("plugins.plugin_settings", map[string]interface{}{})
+	v.SetDefault("plugins.commands", []interface{}{})
 	
 	// Export defaults
 	v.SetDefault("export.default_format", int(domain.ExportFormatJSON))
@@ -157,9 +264,35 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("logging.level", "info")
 	v.SetDefault("logging.format", "text")
 	v.SetDefault("logging.output", "stdout")
+	v.SetDefault("logging.file", "")
 	v.SetDefault("logging.max_size", 100)
 	v.SetDefault("logging.max_backups", 3)
 	v.SetDefault("logging.max_age", 28)
+	
+	// Policy defaults
+	v.SetDefault("policy.public_target_mode", "warn")
+	v.SetDefault("policy.allow_list", []string{})
+	v.SetDefault("policy.active_tools", []string{"ping", "traceroute", "dualstack", "sweep", "axfr"})
+	v.SetDefault("policy.audit_log", "")
+	
+	// Notification defaults
+	v.SetDefault("notify.webhooks", []string{})
+	v.SetDefault("notify.webhook_template", "")
+	v.SetDefault("notify.slack_webhook", "")
+	v.SetDefault("notify.discord_webhook", "")
+	v.SetDefault("notify.host_down_after", 2)
+	v.SetDefault("notify.cert_expiry_days", 14)
+	v.SetDefault("notify.packet_loss_percent", 20.0)
+	
+	// Telemetry defaults: off until the user opts in
+	v.SetDefault("telemetry.enabled", false)
+	v.SetDefault("telemetry.endpoint", "")
+	v.SetDefault("telemetry.interval", "168h")
+	
+	// Key binding defaults
+	for _, action := range domain.DefaultKeyConfig().Actions() {
+		v.SetDefault("keys."+action.Name, action.Keys)
+	}
 }
 
 // Load loads configuration from file and environment variables
@@ -173,24 +306,35 @@ func (m *Manager) Load() error {
 	} else {
 		// Store the config file path for future saves
 		m.configFile = m.viper.ConfigFileUsed()
+		
+		// Bring files written by older versions up to date
+		upgraded, err := upgradeFile(m.viper)
+		if err != nil {
+			return err
+		}
+		m.upgraded = upgraded
 	}
 	
 	// Unmarshal configuration into struct
-	if err := m.viper.Unmarshal(m.config); err != nil {
+	config, err := m.unmarshal(m.viper)
+	if err != nil {
 		return fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	m.config.Store(config)
 	
 	// Validate the loaded configuration
-	if err := m.Validate(); err != nil {
+	if err := m.validateLoaded(m.viper, config); err != nil {
 		return fmt.Errorf("configuration validation failed: %w", err)
 	}
 	
 	return nil
 }
 
-// LoadFromFile loads configuration from a specific file path
+// LoadFromFile loads configuration from a specific file path. The format
+// follows the extension: .yaml, .yml, .toml or .json, else YAML.
 func (m *Manager) LoadFromFile(filePath string) error {
 	m.viper.SetConfigFile(filePath)
+	m.viper.SetConfigType(FormatFromPath(filePath))
 	
 	if err := m.viper.ReadInConfig(); err != nil {
 		return fmt.Errorf("failed to read config file %s: %w", filePath, err)
@@ -198,11 +342,22 @@ func (m *Manager) LoadFromFile(filePath string) error {
 	
 	m.configFile = filePath
 	
-	if err := m.viper.Unmarshal(m.config); err != nil {
+	upgraded, err := upgradeFile(m.viper)
+	if err != nil {
+		return err
+	}
+	m.upgraded = upgraded
+	
+	config, err := m.unmarshal(m.viper)
+	if err != nil {
 		return fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	m.config.Store(config)
 	
-	return m.Validate()
+	if err := m.validateLoaded(m.viper, config); err != nil {
+		return fmt.Errorf("configuration validation failed: %w", err)
+	}
+	return nil
 }
 
 // GetConfigFile returns the path of the currently loaded config file
@@ -219,7 +374,7 @@ func (m *Manager) Save() error {
 		configFile = m.configFile
 	} else {
 		// Create default config file location
-		configDir := filepath.Join(os.Getenv("HOME"), ".config", "nettracex")
+		configDir := appdir.ConfigDir()
 		if err := os.MkdirAll(configDir, 0755); err != nil {
 			return fmt.Errorf("failed to create config directory: %w", err)
 		}
@@ -232,6 +387,7 @@ func (m *Manager) Save() error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 	
+	m.viper.Set(VersionKey, CurrentVersion)
 	if err := m.viper.WriteConfigAs(configFile); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
@@ -246,6 +402,7 @@ func (m *Manager) SaveAs(filePath string) error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 	
+	m.viper.Set(VersionKey, CurrentVersion)
 	if err := m.viper.WriteConfigAs(filePath); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
@@ -265,20 +422,23 @@ func (m *Manager) Set(key string, value interface{}) error {
 	
 	m.viper.Set(key, value)
 	
-	// Re-unmarshal to update the config struct
-	if err := m.viper.Unmarshal(m.config); err != nil {
+	// Decode the new configuration
+	config, err := m.unmarshal(m.viper)
+	if err != nil {
+		m.viper.Set(key, oldValue)
 		return fmt.Errorf("failed to update config: %w", err)
 	}
 	
 	// Validate the new configuration
-	if err := m.Validate(); err != nil {
+	if err := m.validate(config); err != nil {
 		// Rollback on validation failure
 		m.viper.Set(key, oldValue)
-		m.viper.Unmarshal(m.config)
 		return fmt.Errorf("validation failed for key %s: %w", key, err)
 	}
+	m.config.Store(config)
 	
 	// Notify listeners of the change
+	m.markSettings(key)
 	m.notifyListeners(key, oldValue, value)
 	
 	return nil
@@ -297,28 +457,29 @@ func (m *Manager) SetMultiple(values map[string]interface{}) error {
 		m.viper.Set(key, value)
 	}
 	
-	// Re-unmarshal to update the config struct
-	if err := m.viper.Unmarshal(m.config); err != nil {
+	// Decode the new configuration
+	config, err := m.unmarshal(m.viper)
+	if err != nil {
 		// Rollback all changes
 		for key, value := range originalValues {
 			m.viper.Set(key, value)
 		}
-		m.viper.Unmarshal(m.config)
 		return fmt.Errorf("failed to update config: %w", err)
 	}
 	
 	// Validate the new configuration
-	if err := m.Validate(); err != nil {
+	if err := m.validate(config); err != nil {
 		// Rollback all changes
 		for key, value := range originalValues {
 			m.viper.Set(key, value)
 		}
-		m.viper.Unmarshal(m.config)
 		return fmt.Errorf("validation failed: %w", err)
 	}
+	m.config.Store(config)
 	
 	// Notify listeners of all changes
 	for key, newValue := range values {
+		m.markSettings(key)
 		m.notifyListeners(key, originalValues[key], newValue)
 	}
 	
@@ -349,38 +510,105 @@ func (m *Manager) notifyListeners(key string, oldValue, newValue interface{}) {
 
 // Validate validates the current configuration
 func (m *Manager) Validate() error {
+	return m.validate(m.config.Load())
+}
+
+// validate validates config against the rules and the installed themes
+func (m *Manager) validate(config *domain.Config) error {
 	validator := NewValidator()
-	return validator.Validate(m.config)
+	validator.SetThemes(m.Themes())
+	return validator.Validate(config)
+}
+
+// validateLoaded validates config read through v, also reporting keys of
+// the configuration file that no setting reads
+func (m *Manager) validateLoaded(v *viper.Viper, config *domain.Config) error {
+	p := unknownKeys(v)
+	m.validator.SetThemes(m.Themes())
+	p.merge("", m.validator.Validate(config))
+	return p.err()
 }
 
 // GetNetworkConfig returns the network configuration
 func (m *Manager) GetNetworkConfig() domain.NetworkConfig {
-	return m.config.Network
+	return m.config.Load().Network
 }
 
 // GetUIConfig returns the UI configuration
 func (m *Manager) GetUIConfig() domain.UIConfig {
-	return m.config.UI
+	return m.config.Load().UI
 }
 
-// GetConfig returns the complete configuration
+// GetConfig returns the current configuration snapshot. It must not be
+// modified, and it is not updated by later changes: call GetConfig again,
+// or listen for changes, to see them.
 func (m *Manager) GetConfig() *domain.Config {
-	return m.config
+	return m.config.Load()
 }
 
 // GetPluginConfig returns the plugin configuration
 func (m *Manager) GetPluginConfig() domain.PluginConfig {
-	return m.config.Plugins
+	return m.config.Load().Plugins
+}
+
+// SetPluginEnabled enables or disables the plugin called name through
+// plugins.enabled_plugins and plugins.disabled_plugins and saves the file.
+// Plugins are loaded at startup, so the change applies after a restart.
+func (m *Manager) SetPluginEnabled(name string, enabled bool) error {
+	plugins := m.config.Load().Plugins
+	var disabledPlugins []string
+	for _, disabled := range plugins.DisabledPlugins {
+		if disabled != name {
+			disabledPlugins = append(disabledPlugins, disabled)
+		}
+	}
+	enabledPlugins := append([]string(nil), plugins.EnabledPlugins...)
+	if !enabled {
+		disabledPlugins = append(disabledPlugins, name)
+	} else if len(enabledPlugins) > 0 && !contains(enabledPlugins, name) {
+		enabledPlugins = append(enabledPlugins, name)
+	}
+
+	if err := m.SetMultiple(map[string]interface{}{
+		"plugins.enabled_plugins":  stringsOrEmpty(enabledPlugins),
+		"plugins.disabled_plugins": stringsOrEmpty(disabledPlugins),
+	}); err != nil {
+		return err
+	}
+	return m.Save()
+}
+
+// stringsOrEmpty returns an empty list for nil, so it is written to the file
+func stringsOrEmpty(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
 }
 
 // GetExportConfig returns the export configuration
 func (m *Manager) GetExportConfig() domain.ExportConfig {
-	return m.config.Export
+	return m.config.Load().Export
 }
 
 // GetLoggingConfig returns the logging configuration
 func (m *Manager) GetLoggingConfig() domain.LoggingConfig {
-	return m.config.Logging
+	return m.config.Load().Logging
+}
+
+// GetPolicyConfig returns the policy configuration
+func (m *Manager) GetPolicyConfig() domain.PolicyConfig {
+	return m.config.Load().Policy
+}
+
+// GetNotifyConfig returns the notification configuration
+func (m *Manager) GetNotifyConfig() domain.NotifyConfig {
+	return m.config.Load().Notify
+}
+
+// GetTelemetryConfig returns the telemetry configuration
+func (m *Manager) GetTelemetryConfig() domain.TelemetryConfig {
+	return m.config.Load().Telemetry
 }
 
 // Reset resets configuration to default values
@@ -389,14 +617,18 @@ func (m *Manager) Reset() error {
 	v := viper.New()
 	setDefaults(v)
 	bindEnvironmentVariables(v)
+	m.bindFlags(v)
 	
 	// Replace the current viper instance
 	m.viper = v
+	m.settings = nil
 	
 	// Re-unmarshal to update the config struct
-	if err := m.viper.Unmarshal(m.config); err != nil {
+	config, err := m.unmarshal(m.viper)
+	if err != nil {
 		return fmt.Errorf("failed to reset config: %w", err)
 	}
+	m.config.Store(config)
 	
 	return nil
 }
@@ -413,13 +645,40 @@ func (m *Manager) ResetSection(section string) error {
 		m.viper.Set("network.max_concurrency", 10)
 		m.viper.Set("network.retry_attempts", 3)
 		m.viper.Set("network.retry_delay", "1s")
+		m.viper.Set("network.min_ping_interval", "200ms")
+		m.viper.Set("network.max_flood_count", 1000)
+		m.viper.Set("network.rate_limit", 10.0)
+		m.viper.Set("network.rate_burst", 5)
+		m.viper.Set("network.proxy.whois", "")
+		m.viper.Set("network.proxy.ssl", "")
+		m.viper.Set("network.proxy.http", "")
+		m.viper.Set("network.proxy.tcp", "")
+		m.viper.Set("network.cache.enabled", true)
+		m.viper.Set("network.cache.persist", true)
+		m.viper.Set("network.cache.path", "")
+		m.viper.Set("network.cache.max_entries", 1000)
+		m.viper.Set("network.cache.whois_ttl", "1h")
+		m.viper.Set("network.cache.max_ttl", "1h")
+		m.viper.Set("network.cache.negative_ttl", "1m")
 	case "ui":
-		m.viper.Set("ui.theme", "default")
+		m.viper.Set("ui.theme", domain.ThemeAuto)
 		m.viper.Set("ui.animation_speed", "250ms")
 		m.viper.Set("ui.auto_refresh", false)
 		m.viper.Set("ui.refresh_interval", "5s")
 		m.viper.Set("ui.show_help", true)
 		m.viper.Set("ui.color_mode", "auto")
+		m.viper.Set("ui.key_mode", domain.KeyModeDefault)
+		m.viper.Set("ui.language", i18n.Auto)
+		m.viper.Set("ui.accessible", false)
+		m.viper.Set("ui.reduced_motion", false)
+		m.viper.Set("ui.check_updates", true)
+		m.viper.Set("ui.update_channel", string(update.Stable))
+		m.viper.Set("ui.dashboard.show_on_start", true)
+		m.viper.Set("ui.dashboard.widgets", append([]string(nil), domain.DashboardWidgets...))
+		m.viper.Set("ui.dashboard.hosts", []string{})
+		m.viper.Set("ui.dashboard.certificates", []string{})
+		m.viper.Set("ui.dashboard.checks", []string{})
+		m.viper.Set("ui.dashboard.interval", "1m")
 		// Reset key bindings to defaults
 		keyBindings := map[string]string{
 			"quit": "q", "help": "?", "back": "esc",
@@ -434,6 +693,7 @@ func (m *Manager) ResetSection(section string) error {
 		m.viper.Set("plugins.disabled_plugins", []string{})
 		m.viper.Set("plugins.plugin_paths", []string{"./plugins"})
 		m.viper.Set("plugins.plugin_settings", map[string]interface{}{})
+		m.viper.Set("plugins.commands", []interface{}{})
 	case "export":
 		m.viper.Set("export.default_format", int(domain.ExportFormatJSON))
 		m.viper.Set("export.output_directory", "./output")
@@ -443,24 +703,58 @@ func (m *Manager) ResetSection(section string) error {
 		m.viper.Set("logging.level", "info")
 		m.viper.Set("logging.format", "text")
 		m.viper.Set("logging.output", "stdout")
+		m.viper.Set("logging.file", "")
 		m.viper.Set("logging.max_size", 100)
 		m.viper.Set("logging.max_backups", 3)
 		m.viper.Set("logging.max_age", 28)
+	case "policy":
+		m.viper.Set("policy.public_target_mode", "warn")
+		m.viper.Set("policy.allow_list", []string{})
+		m.viper.Set("policy.active_tools", []string{"ping", "traceroute", "dualstack", "sweep", "axfr"})
+		m.viper.Set("policy.audit_log", "")
+	case "notify":
+		m.viper.Set("notify.webhooks", []string{})
+		m.viper.Set("notify.webhook_template", "")
+		m.viper.Set("notify.slack_webhook", "")
+		m.viper.Set("notify.discord_webhook", "")
+		m.viper.Set("notify.host_down_after", 2)
+		m.viper.Set("notify.cert_expiry_days", 14)
+		m.viper.Set("notify.packet_loss_percent", 20.0)
+	case "telemetry":
+		m.viper.Set("telemetry.enabled", false)
+		m.viper.Set("telemetry.endpoint", "")
+		m.viper.Set("telemetry.interval", "168h")
+	case "keys":
+		for _, action := range domain.DefaultKeyConfig().Actions() {
+			m.viper.Set("keys."+action.Name, action.Keys)
+		}
+	case "secrets":
+		if err := m.resetSecrets(); err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("unknown configuration section: %s", section)
 	}
+	for _, key := range m.viper.AllKeys() {
+		if strings.HasPrefix(key, section+".") {
+			m.markSettings(key)
+		}
+	}
 	
 	// Re-unmarshal to update the config struct
-	if err := m.viper.Unmarshal(m.config); err != nil {
+	config, err := m.unmarshal(m.viper)
+	if err != nil {
 		return fmt.Errorf("failed to reset section %s: %w", section, err)
 	}
+	m.config.Store(config)
 	
 	return m.Validate()
 }
 
 // Validator implements configuration validation
 type Validator struct {
-	rules map[string][]ValidationRule
+	rules  map[string][]ValidationRule
+	themes []string
 }
 
 // ValidationRule represents a single validation rule
@@ -473,7 +767,8 @@ type ValidationRule struct {
 // NewValidator creates a new configuration validator
 func NewValidator() *Validator {
 	v := &Validator{
-		rules: make(map[string][]ValidationRule),
+		rules:  make(map[string][]ValidationRule),
+		themes: append([]string(nil), domain.BuiltinThemes...),
 	}
 	v.setupValidationRules()
 	return v
@@ -530,22 +825,22 @@ func (v *Validator) setupValidationRules() {
 		{
 			Name: "valid_theme",
 			Validate: func(value interface{}) error {
-				if theme, ok := value.(string); ok {
-					validThemes := []string{"default", "dark", "light", "minimal"}
-					for _, valid := range validThemes {
-						if theme == valid {
-							return nil
-						}
-					}
-					return fmt.Errorf("theme must be one of: %v", validThemes)
+				if theme, ok := value.(string); ok && !contains(v.themes, theme) {
+					return fmt.Errorf("theme must be one of: %v", v.themes)
 				}
 				return nil
 			},
-			Message: "Theme must be one of: default, dark, light, minimal",
+			Message: "Theme must be a built-in theme or a theme file",
 		},
 	}
 }
 
+// SetThemes sets the theme names ui.theme may take, the built-in themes and
+// those of the theme files
+func (v *Validator) SetThemes(themes []string) {
+	v.themes = themes
+}
+
 // ValidateField validates a specific configuration field
 func (v *Validator) ValidateField(key string, value interface{}) error {
 	if rules, exists := v.rules[key]; exists {
@@ -558,90 +853,372 @@ func (v *Validator) ValidateField(key string, value interface{}) error {
 	return nil
 }
 
-// Validate validates the configuration
+// Validate checks ranges, enum values and cross-field constraints of the
+// whole configuration. It returns a *ValidationError listing every problem
+// with its key and a suggested fix, rather than stopping at the first one.
 func (v *Validator) Validate(config *domain.Config) error {
-	if err := v.validateNetworkConfig(&config.Network); err != nil {
-		return fmt.Errorf("network config validation failed: %w", err)
-	}
-	
-	if err := v.validateUIConfig(&config.UI); err != nil {
-		return fmt.Errorf("UI config validation failed: %w", err)
-	}
-	
-	if err := v.validateExportConfig(&config.Export); err != nil {
-		return fmt.Errorf("export config validation failed: %w", err)
-	}
-	
-	return nil
+	var p problems
+	p.merge("network", v.validateNetworkConfig(&config.Network))
+	p.merge("ui", v.validateUIConfig(&config.UI))
+	p.merge("plugins", v.validatePluginConfig(&config.Plugins))
+	p.merge("export", v.validateExportConfig(&config.Export))
+	p.merge("logging", v.validateLoggingConfig(&config.Logging))
+	p.merge("policy", v.validatePolicyConfig(&config.Policy))
+	p.merge("notify", v.validateNotifyConfig(&config.Notify))
+	p.merge("keys", v.validateKeyConfig(&config.Keys))
+	p.merge("telemetry", v.validateTelemetryConfig(&config.Telemetry))
+	return p.err()
 }
 
 // validateNetworkConfig validates network configuration
 func (v *Validator) validateNetworkConfig(config *domain.NetworkConfig) error {
+	var p problems
+	
 	if config.Timeout <= 0 {
-		return fmt.Errorf("timeout must be positive")
+		p.add("network.timeout", "timeout must be positive", "set a duration such as 30s")
 	}
 	
 	if config.MaxHops <= 0 || config.MaxHops > 255 {
-		return fmt.Errorf("max_hops must be between 1 and 255")
+		p.add("network.max_hops", "max_hops must be between 1 and 255", "30 reaches most hosts")
 	}
 	
 	if config.PacketSize <= 0 || config.PacketSize > 65507 {
-		return fmt.Errorf("packet_size must be between 1 and 65507")
+		p.add("network.packet_size", "packet_size must be between 1 and 65507", "the default is 64")
 	}
 	
 	if config.MaxConcurrency <= 0 {
-		return fmt.Errorf("max_concurrency must be positive")
+		p.add("network.max_concurrency", "max_concurrency must be positive", "the default is 10")
 	}
 	
 	if config.RetryAttempts < 0 {
-		return fmt.Errorf("retry_attempts must be non-negative")
+		p.add("network.retry_attempts", "retry_attempts must be non-negative", "use 0 to disable retries")
 	}
 	
 	if config.RetryDelay < 0 {
-		return fmt.Errorf("retry_delay must be non-negative")
+		p.add("network.retry_delay", "retry_delay must be non-negative", "use 0s to retry at once")
+	}
+	
+	if config.MinPingInterval < 0 {
+		p.add("network.min_ping_interval", "min_ping_interval must be non-negative", "use 0s for the default of 200ms")
+	}
+	
+	if config.MaxFloodCount < 0 {
+		p.add("network.max_flood_count", "max_flood_count must be non-negative", "use 0 for the default of 1000")
+	}
+	
+	if config.RateLimit < 0 {
+		p.add("network.rate_limit", "rate_limit must be non-negative", "use 0 to disable rate limiting")
+	}
+	
+	if config.RateBurst < 0 {
+		p.add("network.rate_burst", "rate_burst must be non-negative", "the default is 5")
 	}
 	
 	if len(config.DNSServers) == 0 {
-		return fmt.Errorf("at least one DNS server must be configured")
+		p.add("network.dns_servers", "at least one DNS server must be configured", `add a resolver such as 1.1.1.1, or "`+domain.SystemDNSServer+`" for the system resolver`)
 	}
 	
-	return nil
+	for _, server := range config.DNSServers {
+		if !isValidDNSServer(server) {
+			p.add("network.dns_servers", fmt.Sprintf("invalid DNS server: %q", server), `use an IP address, an IP address and port such as 9.9.9.9:53, or "`+domain.SystemDNSServer+`"`)
+		}
+	}
+	
+	for _, kind := range []struct {
+		key string
+		raw string
+	}{{"whois", config.Proxy.WHOIS}, {"ssl", config.Proxy.SSL}, {"http", config.Proxy.HTTP}, {"tcp", config.Proxy.TCP}} {
+		if _, err := proxy.Parse(kind.raw); err != nil {
+			p.add("network.proxy."+kind.key, err.Error(), "leave it empty to connect directly")
+		}
+	}
+	
+	if config.Cache.MaxEntries < 0 {
+		p.add("network.cache.max_entries", "cache.max_entries must be non-negative", "use 0 for the default size")
+	}
+	
+	for _, ttl := range []struct {
+		key   string
+		value time.Duration
+	}{{"whois_ttl", config.Cache.WHOISTTL}, {"max_ttl", config.Cache.MaxTTL}, {"negative_ttl", config.Cache.NegativeTTL}} {
+		if ttl.value < 0 {
+			p.add("network.cache."+ttl.key, "cache TTLs must be non-negative", "set a duration such as 1h")
+		}
+	}
+	
+	if config.Cache.Path != "" && (!config.Cache.Enabled || !config.Cache.Persist) {
+		p.add("network.cache.path", "cache.path is unused while the cache is disabled or not persisted", "set network.cache.enabled and network.cache.persist to true, or remove path")
+	}
+	
+	return p.err()
 }
 
 // validateUIConfig validates UI configuration
 func (v *Validator) validateUIConfig(config *domain.UIConfig) error {
+	var p problems
+	
 	if config.AnimationSpeed < 0 {
-		return fmt.Errorf("animation_speed must be non-negative")
+		p.add("ui.animation_speed", "animation_speed must be non-negative", "use 0s to disable animations")
 	}
 	
 	if config.RefreshInterval <= 0 {
-		return fmt.Errorf("refresh_interval must be positive")
+		p.add("ui.refresh_interval", "refresh_interval must be positive", "set a duration such as 5s")
 	}
 	
-	validThemes := []string{"default", "dark", "light", "minimal"}
-	if !contains(validThemes, config.Theme) {
-		return fmt.Errorf("theme must be one of: %v", validThemes)
+	if !contains(v.themes, config.Theme) {
+		p.add("ui.theme", fmt.Sprintf("theme must be one of: %v", v.themes), didYouMean(config.Theme, v.themes))
 	}
 	
-	validColorModes := []string{"auto", "always", "never"}
+	validColorModes := []string{colors.ModeAuto, colors.ModeAlways, colors.ModeNever}
 	if !contains(validColorModes, config.ColorMode) {
-		return fmt.Errorf("color_mode must be one of: %v", validColorModes)
+		p.add("ui.color_mode", fmt.Sprintf("color_mode must be one of: %v", validColorModes), didYouMean(config.ColorMode, validColorModes))
 	}
 	
-	return nil
+	if config.KeyMode != "" && !contains(domain.KeyModes, config.KeyMode) {
+		p.add("ui.key_mode", fmt.Sprintf("key_mode must be one of: %v", domain.KeyModes), didYouMean(config.KeyMode, domain.KeyModes))
+	}
+	
+	if channels := update.ChannelNames(); config.UpdateChannel != "" && !contains(channels, config.UpdateChannel) {
+		p.add("ui.update_channel", fmt.Sprintf("update_channel must be one of: %v", channels), didYouMean(config.UpdateChannel, channels))
+	}
+	
+	validLanguages := append([]string{i18n.Auto}, i18n.Languages...)
+	if config.Language != "" && !contains(validLanguages, config.Language) {
+		p.add("ui.language", fmt.Sprintf("language must be one of: %v", validLanguages), didYouMean(config.Language, validLanguages))
+	}
+	
+	dashboard := config.Dashboard
+	for _, widget := range dashboard.Widgets {
+		if !contains(domain.DashboardWidgets, widget) {
+			p.add("ui.dashboard.widgets", fmt.Sprintf("unknown widget %q, widgets must be among: %v", widget, domain.DashboardWidgets), didYouMean(widget, domain.DashboardWidgets))
+		}
+	}
+	for _, certificate := range dashboard.Certificates {
+		if host, port, err := net.SplitHostPort(certificate); err == nil {
+			if n, err := strconv.Atoi(port); host == "" || err != nil || n < 1 || n > 65535 {
+				p.add("ui.dashboard.certificates", fmt.Sprintf("invalid certificate target %q", certificate), "use host or host:port with a port between 1 and 65535")
+			}
+		}
+	}
+	for _, check := range dashboard.Checks {
+		tool, target, ok := strings.Cut(check, ":")
+		if !ok || strings.TrimSpace(tool) == "" || strings.TrimSpace(target) == "" {
+			p.add("ui.dashboard.checks", fmt.Sprintf("invalid check %q", check), "use tool:target, such as dns:example.com")
+		}
+	}
+	if dashboard.Interval < 0 {
+		p.add("ui.dashboard.interval", "interval must be non-negative", "set a duration such as 1m")
+	}
+	
+	return p.err()
+}
+
+// validatePluginConfig validates plugin configuration
+func (v *Validator) validatePluginConfig(config *domain.PluginConfig) error {
+	var p problems
+	
+	for _, name := range config.EnabledPlugins {
+		if contains(config.DisabledPlugins, name) {
+			p.add("plugins.enabled_plugins", fmt.Sprintf("plugin %q is both enabled and disabled", name), "remove it from one of plugins.enabled_plugins and plugins.disabled_plugins")
+		}
+	}
+	
+	names := make(map[string]bool)
+	for i, command := range config.Commands {
+		key := fmt.Sprintf("plugins.commands[%d]", i)
+		if command.Name == "" {
+			p.add(key+".name", "command tool has no name", "name it, e.g. name = \"dig\"")
+		} else if names[command.Name] {
+			p.add(key+".name", fmt.Sprintf("command tool %q is defined twice", command.Name), "rename or remove one of them")
+		}
+		names[command.Name] = true
+		if command.Command == "" {
+			p.add(key+".command", "command tool has no command", "set the executable to run, e.g. command = \"dig\"")
+		}
+		for j, arg := range command.Args {
+			if _, err := template.New("arg").Parse(arg); err != nil {
+				p.add(fmt.Sprintf("%s.args[%d]", key, j), fmt.Sprintf("invalid template: %v", err), "refer to parameters as {{.key}}")
+			}
+		}
+		validParse := []string{"text", "json", "regex"}
+		if command.Parse != "" && !contains(validParse, command.Parse) {
+			p.add(key+".parse", fmt.Sprintf("parse must be one of: %v", validParse), didYouMean(command.Parse, validParse))
+		}
+		if command.Parse == "regex" {
+			pattern, err := regexp.Compile(command.Pattern)
+			switch {
+			case command.Pattern == "":
+				p.add(key+".pattern", "regex parsing needs a pattern", "capture the columns with named groups, e.g. (?P<port>\\d+)/tcp")
+			case err != nil:
+				p.add(key+".pattern", fmt.Sprintf("invalid pattern: %v", err), "")
+			case strings.Join(pattern.SubexpNames(), "") == "":
+				p.add(key+".pattern", "pattern has no named groups", "capture the columns with named groups, e.g. (?P<port>\\d+)/tcp")
+			}
+		}
+		if command.Timeout < 0 {
+			p.add(key+".timeout", "timeout cannot be negative", "leave it unset for 60s")
+		}
+	}
+	
+	return p.err()
 }
 
 // validateExportConfig validates export configuration
 func (v *Validator) validateExportConfig(config *domain.ExportConfig) error {
-	if config.DefaultFormat < 0 || config.DefaultFormat > domain.ExportFormatText {
-		return fmt.Errorf("invalid default_format")
+	var p problems
+	
+	if config.DefaultFormat < 0 || config.DefaultFormat > domain.ExportFormatPDF {
+		p.add("export.default_format", "invalid default_format", fmt.Sprintf("use a number from %d (JSON) to %d (PDF)", domain.ExportFormatJSON, domain.ExportFormatPDF))
 	}
 	
 	if config.OutputDirectory == "" {
-		return fmt.Errorf("output_directory cannot be empty")
+		p.add("export.output_directory", "output_directory cannot be empty", "the default is ./output")
 	}
 	
-	return nil
+	return p.err()
+}
+
+// validateLoggingConfig validates logging configuration
+func (v *Validator) validateLoggingConfig(config *domain.LoggingConfig) error {
+	var p problems
+	
+	// Empty values fall back to info level text output on stdout
+	validLevels := []string{"debug", "info", "warn", "error", "fatal"}
+	if config.Level != "" && !contains(validLevels, strings.ToLower(config.Level)) {
+		p.add("logging.level", fmt.Sprintf("level must be one of: %v", validLevels), didYouMean(config.Level, validLevels))
+	}
+	
+	validFormats := []string{"text", "json"}
+	if config.Format != "" && !contains(validFormats, config.Format) {
+		p.add("logging.format", fmt.Sprintf("format must be one of: %v", validFormats), didYouMean(config.Format, validFormats))
+	}
+	
+	validOutputs := []string{"stdout", "stderr", "file", "syslog", "journald"}
+	if config.Output != "" && !contains(validOutputs, config.Output) {
+		p.add("logging.output", fmt.Sprintf("output must be one of: %v", validOutputs), didYouMean(config.Output, validOutputs))
+	}
+	
+	for _, limit := range []struct {
+		key   string
+		value int
+	}{{"max_size", config.MaxSize}, {"max_backups", config.MaxBackups}, {"max_age", config.MaxAge}} {
+		if limit.value < 0 {
+			p.add("logging."+limit.key, "max_size, max_backups and max_age must be non-negative", "use 0 for no limit")
+		}
+	}
+	
+	if config.File != "" && config.Output != "file" {
+		p.add("logging.file", "file is unused unless output is file", "set logging.output to file, or remove file")
+	}
+	
+	return p.err()
+}
+
+// validatePolicyConfig validates policy configuration
+func (v *Validator) validatePolicyConfig(config *domain.PolicyConfig) error {
+	var p problems
+	
+	// An empty mode falls back to the "warn" default
+	validModes := []string{"off", "warn", "block"}
+	if config.PublicTargetMode != "" && !contains(validModes, config.PublicTargetMode) {
+		p.add("policy.public_target_mode", fmt.Sprintf("public_target_mode must be one of: %v", validModes), didYouMean(config.PublicTargetMode, validModes))
+	}
+	
+	for _, entry := range config.AllowList {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			p.add("policy.allow_list", "allow_list entries cannot be empty", "remove the empty entry")
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			if _, _, err := net.ParseCIDR(entry); err != nil {
+				p.add("policy.allow_list", fmt.Sprintf("invalid allow_list CIDR %q", entry), "use a network such as 192.0.2.0/24, or a host name")
+			}
+		}
+	}
+	
+	return p.err()
+}
+
+// validateNotifyConfig validates notification configuration
+func (v *Validator) validateNotifyConfig(config *domain.NotifyConfig) error {
+	var p problems
+	
+	if config.HostDownAfter < 0 {
+		p.add("notify.host_down_after", "host_down_after must be non-negative", "the default is 2 probes")
+	}
+	
+	if config.CertExpiryDays < 0 {
+		p.add("notify.cert_expiry_days", "cert_expiry_days must be non-negative", "the default is 14 days")
+	}
+	
+	if config.PacketLossPercent < 0 || config.PacketLossPercent > 100 {
+		p.add("notify.packet_loss_percent", "packet_loss_percent must be between 0 and 100", "the default is 20")
+	}
+	
+	for _, webhook := range []struct {
+		key string
+		raw string
+	}{{"notify.slack_webhook", config.SlackWebhook}, {"notify.discord_webhook", config.DiscordWebhook}} {
+		if webhook.raw != "" && !isValidWebhook(webhook.raw) {
+			p.add(webhook.key, fmt.Sprintf("invalid webhook URL %q", webhook.raw), "use an http:// or https:// URL")
+		}
+	}
+	for _, raw := range config.Webhooks {
+		if raw != "" && !isValidWebhook(raw) {
+			p.add("notify.webhooks", fmt.Sprintf("invalid webhook URL %q", raw), "use an http:// or https:// URL")
+		}
+	}
+	
+	if config.WebhookTemplate != "" && len(config.Webhooks) == 0 {
+		p.add("notify.webhook_template", "webhook_template is unused without webhooks", "add URLs to notify.webhooks, or remove webhook_template")
+	}
+	
+	return p.err()
+}
+
+// validateTelemetryConfig validates telemetry configuration
+func (v *Validator) validateTelemetryConfig(config *domain.TelemetryConfig) error {
+	var p problems
+	
+	if config.Endpoint != "" && !isValidWebhook(config.Endpoint) {
+		p.add("telemetry.endpoint", fmt.Sprintf("invalid endpoint URL %q", config.Endpoint), "use an http:// or https:// URL")
+	}
+	
+	if config.Interval != 0 && config.Interval < time.Hour {
+		p.add("telemetry.interval", "interval must be at least 1h", "the default is 168h, a week")
+	}
+	
+	return p.err()
+}
+
+// validateKeyConfig validates key bindings. A key bound to two actions
+// would only ever trigger one of them, so conflicts are reported.
+func (v *Validator) validateKeyConfig(config *domain.KeyConfig) error {
+	var p problems
+	
+	boundTo := make(map[string]string)
+	for _, action := range config.Actions() {
+		for _, binding := range action.Keys {
+			if strings.TrimSpace(binding) == "" || strings.TrimSpace(binding) != binding {
+				p.add("keys."+action.Name, fmt.Sprintf("invalid key %q", binding), `use Bubble Tea key names such as "j", "ctrl+n" or "pgdown"`)
+				continue
+			}
+			if other, ok := boundTo[binding]; ok && other != action.Name {
+				p.add("keys."+action.Name, fmt.Sprintf("%q is also bound to keys.%s", binding, other), "bind one of the two actions to another key")
+				continue
+			}
+			boundTo[binding] = action.Name
+		}
+	}
+	
+	return p.err()
+}
+
+// isValidWebhook reports whether raw is an absolute http or https URL
+func isValidWebhook(raw string) bool {
+	parsed, err := url.Parse(raw)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
 }
 
 // contains checks if a slice contains a string
@@ -652,4 +1229,20 @@ func contains(slice []string, item string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
+// isValidDNSServer reports whether server is the system resolver, an IP address, or an IP address with port
+func isValidDNSServer(server string) bool {
+	server = strings.TrimSpace(server)
+	if server == domain.SystemDNSServer || net.ParseIP(server) != nil {
+		return true
+	}
+	host, port, err := net.SplitHostPort(server)
+	if err != nil || net.ParseIP(host) == nil {
+		return false
+	}
+	var portNum int
+	if _, err := fmt.Sscanf(port, "%d", &portNum); err != nil {
+		return false
+	}
+	return portNum > 0 && portNum <= 65535
+}
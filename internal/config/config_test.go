@@ -15,7 +15,7 @@ func TestNewManager(t *testing.T) {
 	manager := NewManager()
 	
 	assert.NotNil(t, manager)
-	assert.NotNil(t, manager.config)
+	assert.NotNil(t, manager.GetConfig())
 	assert.NotNil(t, manager.viper)
 }
 
@@ -35,13 +35,22 @@ func TestManagerLoad(t *testing.T) {
 	assert.Equal(t, 10, config.Network.MaxConcurrency)
 	assert.Equal(t, 3, config.Network.RetryAttempts)
 	assert.Equal(t, time.Second, config.Network.RetryDelay)
+	assert.Equal(t, 200*time.Millisecond, config.Network.MinPingInterval)
+	assert.Equal(t, 1000, config.Network.MaxFloodCount)
+	assert.True(t, config.Network.Cache.Enabled)
+	assert.Equal(t, time.Hour, config.Network.Cache.WHOISTTL)
+	assert.Equal(t, time.Minute, config.Network.Cache.NegativeTTL)
 	
-	assert.Equal(t, "default", config.UI.Theme)
+	assert.Equal(t, domain.ThemeAuto, config.UI.Theme)
 	assert.Equal(t, 250*time.Millisecond, config.UI.AnimationSpeed)
 	assert.False(t, config.UI.AutoRefresh)
 	assert.Equal(t, 5*time.Second, config.UI.RefreshInterval)
 	assert.True(t, config.UI.ShowHelp)
 	assert.Equal(t, "auto", config.UI.ColorMode)
+	assert.True(t, config.UI.Dashboard.ShowOnStart)
+	assert.Equal(t, domain.DashboardWidgets, config.UI.Dashboard.Widgets)
+	assert.Empty(t, config.UI.Dashboard.Hosts)
+	assert.Equal(t, time.Minute, config.UI.Dashboard.Interval)
 	
 	assert.Equal(t, domain.ExportFormatJSON, config.Export.DefaultFormat)
 	assert.Equal(t, "./output", config.Export.OutputDirectory)
@@ -63,7 +72,7 @@ func TestManagerGetSet(t *testing.T) {
 	assert.Equal(t, "30s", timeout)
 	
 	theme := manager.Get("ui.theme")
-	assert.Equal(t, "default", theme)
+	assert.Equal(t, domain.ThemeAuto, theme)
 	
 	// Test Set
 	err = manager.Set("network.timeout", "60s")
@@ -98,7 +107,7 @@ func TestManagerGetUIConfig(t *testing.T) {
 	assert.NoError(t, err)
 	
 	uiConfig := manager.GetUIConfig()
-	assert.Equal(t, "default", uiConfig.Theme)
+	assert.Equal(t, domain.ThemeAuto, uiConfig.Theme)
 	assert.Equal(t, 250*time.Millisecond, uiConfig.AnimationSpeed)
 	assert.False(t, uiConfig.AutoRefresh)
 	assert.Equal(t, 5*time.Second, uiConfig.RefreshInterval)
@@ -225,12 +234,54 @@ func TestValidatorValidateNetworkConfig(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "retry_delay must be non-negative")
 	
+	// Test invalid ping limits
+	invalidConfig = *validConfig
+	invalidConfig.MinPingInterval = -time.Millisecond
+	err = validator.validateNetworkConfig(&invalidConfig)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "min_ping_interval must be non-negative")
+	
+	invalidConfig = *validConfig
+	invalidConfig.MaxFloodCount = -1
+	err = validator.validateNetworkConfig(&invalidConfig)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "max_flood_count must be non-negative")
+	
+	invalidConfig = *validConfig
+	invalidConfig.RateLimit = -1
+	err = validator.validateNetworkConfig(&invalidConfig)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "rate_limit must be non-negative")
+	
 	// Test empty DNS servers
 	invalidConfig = *validConfig
 	invalidConfig.DNSServers = []string{}
 	err = validator.validateNetworkConfig(&invalidConfig)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "at least one DNS server must be configured")
+	
+	// Test invalid DNS server
+	invalidConfig = *validConfig
+	invalidConfig.DNSServers = []string{"8.8.8.8", "dns.google"}
+	err = validator.validateNetworkConfig(&invalidConfig)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid DNS server")
+	
+	// Test DNS servers with ports and the system resolver
+	validServers := *validConfig
+	validServers.DNSServers = []string{"1.1.1.1:53", "[2606:4700:4700::1111]:53", "system"}
+	assert.NoError(t, validator.validateNetworkConfig(&validServers))
+
+	// Test proxies
+	proxied := *validConfig
+	proxied.Proxy = domain.ProxyConfig{WHOIS: "socks5://127.0.0.1:1080", HTTP: "http://proxy.internal:3128"}
+	assert.NoError(t, validator.validateNetworkConfig(&proxied))
+	assert.Equal(t, []string{"whois", "http"}, proxied.Proxy.Proxied())
+
+	proxied.Proxy.SSL = "https://proxy.internal:443"
+	err = validator.validateNetworkConfig(&proxied)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "socks5://")
 }
 
 func TestValidatorValidateUIConfig(t *testing.T) {
@@ -277,6 +328,50 @@ func TestValidatorValidateUIConfig(t *testing.T) {
 	err = validator.validateUIConfig(&invalidConfig)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "color_mode must be one of")
+	
+	// Test key modes
+	invalidConfig = *validConfig
+	invalidConfig.KeyMode = "vim"
+	err = validator.validateUIConfig(&invalidConfig)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "key_mode must be one of")
+	assert.Contains(t, err.Error(), `did you mean "vi"?`)
+	invalidConfig.KeyMode = domain.KeyModeVi
+	assert.NoError(t, validator.validateUIConfig(&invalidConfig))
+	
+	// Test languages
+	invalidConfig = *validConfig
+	invalidConfig.Language = "fr"
+	err = validator.validateUIConfig(&invalidConfig)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "language must be one of")
+	invalidConfig.Language = "ja"
+	assert.NoError(t, validator.validateUIConfig(&invalidConfig))
+	
+	// Test valid dashboard
+	invalidConfig = *validConfig
+	invalidConfig.Dashboard = domain.DashboardConfig{
+		Widgets:      []string{"hosts", "results"},
+		Certificates: []string{"example.com", "example.com:8443", "[2001:db8::1]:443"},
+		Checks:       []string{"dns:example.com"},
+		Interval:     time.Minute,
+	}
+	assert.NoError(t, validator.validateUIConfig(&invalidConfig))
+	
+	// Test invalid dashboard
+	invalidConfig.Dashboard = domain.DashboardConfig{
+		Widgets:      []string{"certificate"},
+		Certificates: []string{"example.com:99999"},
+		Checks:       []string{"example.com"},
+		Interval:     -time.Second,
+	}
+	err = validator.validateUIConfig(&invalidConfig)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown widget "certificate"`)
+	assert.Contains(t, err.Error(), `did you mean "certificates"?`)
+	assert.Contains(t, err.Error(), `invalid certificate target "example.com:99999"`)
+	assert.Contains(t, err.Error(), `invalid check "example.com"`)
+	assert.Contains(t, err.Error(), "interval must be non-negative")
 }
 
 func TestValidatorValidateExportConfig(t *testing.T) {
@@ -308,6 +403,211 @@ func TestValidatorValidateExportConfig(t *testing.T) {
 	assert.Contains(t, err.Error(), "output_directory cannot be empty")
 }
 
+func TestValidatorValidateLoggingConfig(t *testing.T) {
+	validator := NewValidator()
+
+	// Test valid logging config
+	validConfig := &domain.LoggingConfig{
+		Level:      "debug",
+		Format:     "json",
+		Output:     "journald",
+		MaxSize:    100,
+		MaxBackups: 3,
+		MaxAge:     28,
+	}
+
+	err := validator.validateLoggingConfig(validConfig)
+	assert.NoError(t, err)
+
+	// Empty values fall back to the defaults
+	assert.NoError(t, validator.validateLoggingConfig(&domain.LoggingConfig{}))
+
+	// Test invalid output
+	invalidConfig := *validConfig
+	invalidConfig.Output = "printer"
+	err = validator.validateLoggingConfig(&invalidConfig)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "output must be one of")
+
+	// Test negative rotation settings
+	invalidConfig = *validConfig
+	invalidConfig.MaxBackups = -1
+	err = validator.validateLoggingConfig(&invalidConfig)
+	assert.Error(t, err)
+}
+
+func TestValidatorValidatePolicyConfig(t *testing.T) {
+	validator := NewValidator()
+
+	// Test valid policy config
+	validConfig := &domain.PolicyConfig{
+		PublicTargetMode: "block",
+		AllowList:        []string{"10.0.0.0/8", "203.0.113.7", "lab.example.com"},
+		ActiveTools:      []string{"ping", "traceroute"},
+	}
+
+	err := validator.validatePolicyConfig(validConfig)
+	assert.NoError(t, err)
+
+	// Empty mode falls back to the default
+	emptyMode := *validConfig
+	emptyMode.PublicTargetMode = ""
+	assert.NoError(t, validator.validatePolicyConfig(&emptyMode))
+
+	// Test invalid mode
+	invalidConfig := *validConfig
+	invalidConfig.PublicTargetMode = "sometimes"
+	err = validator.validatePolicyConfig(&invalidConfig)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "public_target_mode must be one of")
+
+	// Test invalid CIDR
+	invalidConfig = *validConfig
+	invalidConfig.AllowList = []string{"10.0.0.0/40"}
+	err = validator.validatePolicyConfig(&invalidConfig)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid allow_list CIDR")
+}
+
+func TestManagerPolicyDefaults(t *testing.T) {
+	manager := NewManager()
+	err := manager.Load()
+	assert.NoError(t, err)
+
+	policyConfig := manager.GetPolicyConfig()
+	assert.Equal(t, "warn", policyConfig.PublicTargetMode)
+	assert.Equal(t, []string{"ping", "traceroute", "dualstack", "sweep", "axfr"}, policyConfig.ActiveTools)
+
+	err = manager.Set("policy.public_target_mode", "block")
+	assert.NoError(t, err)
+
+	err = manager.ResetSection("policy")
+	assert.NoError(t, err)
+	assert.Equal(t, "warn", manager.GetPolicyConfig().PublicTargetMode)
+}
+
+func TestValidatorValidateNotifyConfig(t *testing.T) {
+	validator := NewValidator()
+
+	// Test valid notify config
+	validConfig := &domain.NotifyConfig{
+		Webhooks:          []string{"https://alerts.example.com/hook"},
+		SlackWebhook:      "https://hooks.slack.com/services/T000/B000/XXXX",
+		HostDownAfter:     2,
+		CertExpiryDays:    14,
+		PacketLossPercent: 20,
+	}
+
+	err := validator.validateNotifyConfig(validConfig)
+	assert.NoError(t, err)
+
+	// Test packet loss out of range
+	invalidConfig := *validConfig
+	invalidConfig.PacketLossPercent = 120
+	err = validator.validateNotifyConfig(&invalidConfig)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "packet_loss_percent must be between 0 and 100")
+
+	// Test invalid webhook URL
+	invalidConfig = *validConfig
+	invalidConfig.DiscordWebhook = "discord.com/api/webhooks/1"
+	err = validator.validateNotifyConfig(&invalidConfig)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid webhook URL")
+}
+
+func TestManagerNotifyDefaults(t *testing.T) {
+	manager := NewManager()
+	err := manager.Load()
+	assert.NoError(t, err)
+
+	notifyConfig := manager.GetNotifyConfig()
+	assert.Equal(t, 2, notifyConfig.HostDownAfter)
+	assert.Equal(t, 14, notifyConfig.CertExpiryDays)
+	assert.Equal(t, 20.0, notifyConfig.PacketLossPercent)
+	assert.Empty(t, notifyConfig.Webhooks)
+
+	err = manager.Set("notify.packet_loss_percent", 5.0)
+	assert.NoError(t, err)
+	assert.Equal(t, 5.0, manager.GetNotifyConfig().PacketLossPercent)
+
+	err = manager.ResetSection("notify")
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, manager.GetNotifyConfig().PacketLossPercent)
+}
+
+func TestManagerTelemetryDefaults(t *testing.T) {
+	manager := NewManager()
+	err := manager.Load()
+	assert.NoError(t, err)
+
+	telemetryConfig := manager.GetTelemetryConfig()
+	assert.False(t, telemetryConfig.Enabled, "telemetry is opt-in")
+	assert.Empty(t, telemetryConfig.Endpoint)
+	assert.Equal(t, 168*time.Hour, telemetryConfig.Interval)
+
+	err = manager.Set("telemetry.enabled", true)
+	assert.NoError(t, err)
+	assert.True(t, manager.GetTelemetryConfig().Enabled)
+
+	err = manager.ResetSection("telemetry")
+	assert.NoError(t, err)
+	assert.False(t, manager.GetTelemetryConfig().Enabled)
+}
+
+func TestValidatorValidateTelemetryConfig(t *testing.T) {
+	validator := NewValidator()
+
+	err := validator.validateTelemetryConfig(&domain.TelemetryConfig{Enabled: true, Endpoint: "https://telemetry.example.com/v1", Interval: 24 * time.Hour})
+	assert.NoError(t, err)
+
+	err = validator.validateTelemetryConfig(&domain.TelemetryConfig{Endpoint: "ftp://telemetry.example.com", Interval: time.Minute})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid endpoint URL")
+	assert.Contains(t, err.Error(), "at least 1h")
+}
+
+func TestValidatorValidateKeyConfig(t *testing.T) {
+	validator := NewValidator()
+
+	// Empty actions keep their defaults, which do not conflict
+	err := validator.validateKeyConfig(&domain.KeyConfig{})
+	assert.NoError(t, err)
+
+	err = validator.validateKeyConfig(&domain.KeyConfig{Down: []string{"ctrl+n", "down"}, Up: []string{"ctrl+p", "up"}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `keys.palette: "ctrl+p" is also bound to keys.up`)
+
+	err = validator.validateKeyConfig(&domain.KeyConfig{Up: []string{"ctrl+p", "up"}, Palette: []string{"ctrl+k"}})
+	assert.NoError(t, err)
+
+	err = validator.validateKeyConfig(&domain.KeyConfig{Quit: []string{" "}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "keys.quit: invalid key")
+}
+
+func TestManagerKeyDefaults(t *testing.T) {
+	manager := NewManager()
+	err := manager.Load()
+	assert.NoError(t, err)
+
+	keys := manager.GetConfig().Keys
+	assert.Equal(t, []string{"ctrl+p"}, keys.Palette)
+	assert.Equal(t, []string{"up", "k"}, keys.Up)
+
+	err = manager.Set("keys.export", []string{"t"})
+	assert.Error(t, err, "t switches to the table view")
+	assert.Equal(t, []string{"e"}, manager.GetConfig().Keys.Export)
+
+	err = manager.Set("keys.export", []string{"x"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"x"}, manager.GetConfig().Keys.Export)
+
+	err = manager.ResetSection("keys")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"e"}, manager.GetConfig().Keys.Export)
+}
+
 func TestValidatorValidateCompleteConfig(t *testing.T) {
 	validator := NewValidator()
 	
@@ -348,21 +648,21 @@ func TestValidatorValidateCompleteConfig(t *testing.T) {
 	invalidConfig.Network.Timeout = 0
 	err = validator.Validate(&invalidConfig)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "network config validation failed")
+	assert.Contains(t, err.Error(), "network.timeout: timeout must be positive")
 	
 	// Test config with invalid UI settings
 	invalidConfig = *validConfig
 	invalidConfig.UI.Theme = "invalid"
 	err = validator.Validate(&invalidConfig)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "UI config validation failed")
+	assert.Contains(t, err.Error(), "ui.theme: theme must be one of")
 	
 	// Test config with invalid export settings
 	invalidConfig = *validConfig
 	invalidConfig.Export.OutputDirectory = ""
 	err = validator.Validate(&invalidConfig)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "export config validation failed")
+	assert.Contains(t, err.Error(), "export.output_directory: output_directory cannot be empty")
 }
 
 func TestManagerValidation(t *testing.T) {
@@ -472,6 +772,31 @@ func TestManagerSetMultiple(t *testing.T) {
 	assert.Equal(t, 25, manager.Get("network.max_hops"))
 }
 
+func TestManagerSetPluginEnabled(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "nettracex.yaml")
+	err := os.WriteFile(configFile, []byte("plugins:\n  disabled_plugins: [http]\n"), 0644)
+	assert.NoError(t, err)
+
+	manager := NewManager()
+	assert.NoError(t, manager.LoadFromFile(configFile))
+
+	assert.NoError(t, manager.SetPluginEnabled("http", true))
+	assert.NoError(t, manager.SetPluginEnabled("nmap", false))
+	assert.Empty(t, manager.GetPluginConfig().EnabledPlugins)
+	assert.Equal(t, []string{"nmap"}, manager.GetPluginConfig().DisabledPlugins)
+
+	// Saved to the file
+	reloaded := NewManager()
+	assert.NoError(t, reloaded.LoadFromFile(configFile))
+	assert.Equal(t, []string{"nmap"}, reloaded.GetPluginConfig().DisabledPlugins)
+
+	// With an allow list, enabling adds to it
+	assert.NoError(t, reloaded.SetMultiple(map[string]interface{}{"plugins.enabled_plugins": []string{"dig"}}))
+	assert.NoError(t, reloaded.SetPluginEnabled("nmap", true))
+	assert.Equal(t, []string{"dig", "nmap"}, reloaded.GetPluginConfig().EnabledPlugins)
+	assert.Empty(t, reloaded.GetPluginConfig().DisabledPlugins)
+}
+
 func TestManagerChangeListeners(t *testing.T) {
 	manager := NewManager()
 	err := manager.Load()
@@ -526,7 +851,7 @@ func TestManagerReset(t *testing.T) {
 	
 	// Verify defaults are restored
 	assert.Equal(t, "30s", manager.Get("network.timeout"))
-	assert.Equal(t, "default", manager.Get("ui.theme"))
+	assert.Equal(t, "auto", manager.Get("ui.theme"))
 }
 
 func TestManagerResetSection(t *testing.T) {
@@ -602,7 +927,7 @@ func TestManagerGetConfigSections(t *testing.T) {
 	assert.Equal(t, 30*time.Second, networkConfig.Timeout)
 	
 	uiConfig := manager.GetUIConfig()
-	assert.Equal(t, "default", uiConfig.Theme)
+	assert.Equal(t, domain.ThemeAuto, uiConfig.Theme)
 	
 	pluginConfig := manager.GetPluginConfig()
 	assert.Empty(t, pluginConfig.EnabledPlugins)
@@ -0,0 +1,198 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Configuration file formats, chosen by file extension
+const (
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+	FormatJSON = "json"
+)
+
+// formatExtensions maps the supported file extensions to their format
+var formatExtensions = map[string]string{
+	".yaml": FormatYAML,
+	".yml":  FormatYAML,
+	".toml": FormatTOML,
+	".json": FormatJSON,
+}
+
+// FormatFromPath returns the format of a configuration file from its
+// extension. Files without a known extension are read as YAML.
+func FormatFromPath(path string) string {
+	if format, ok := formatExtensions[strings.ToLower(filepath.Ext(path))]; ok {
+		return format
+	}
+	return FormatYAML
+}
+
+// MigrateFile converts the configuration file src into dst, each in the
+// format of its extension. Only the settings in src are written, not
+// defaults or overrides, and full-line comments are carried over to the
+// matching keys when both formats support comments. An existing dst is
+// never overwritten.
+func MigrateFile(src, dst string) error {
+	if _, ok := formatExtensions[strings.ToLower(filepath.Ext(dst))]; !ok {
+		return fmt.Errorf("unsupported configuration format %q: use .yaml, .yml, .toml or .json", filepath.Ext(dst))
+	}
+	if _, err := os.Stat(dst); err == nil {
+		return fmt.Errorf("%s already exists", dst)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to check %s: %w", dst, err)
+	}
+
+	srcFormat, dstFormat := FormatFromPath(src), FormatFromPath(dst)
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	v := viper.New()
+	v.SetConfigType(srcFormat)
+	if err := v.ReadConfig(strings.NewReader(string(data))); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", src, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := v.WriteConfigAs(dst); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if srcFormat == FormatJSON || dstFormat == FormatJSON {
+		return nil
+	}
+
+	comments := collectComments(string(data), srcFormat)
+	if len(comments) == 0 {
+		return nil
+	}
+	converted, err := os.ReadFile(dst)
+	if err != nil {
+		return fmt.Errorf("failed to read converted config file: %w", err)
+	}
+	if err := os.WriteFile(dst, []byte(insertComments(string(converted), dstFormat, comments)), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// Migrate converts the loaded configuration file into dst, see MigrateFile
+func (m *Manager) Migrate(dst string) error {
+	if m.configFile == "" {
+		return errors.New("no configuration file loaded")
+	}
+	return MigrateFile(m.configFile, dst)
+}
+
+// headerComments is the key of the comments at the top of a file
+const headerComments = ""
+
+// collectComments returns the full-line comments of a YAML or TOML file by
+// the dotted key they precede. Comments before a blank line at the top of
+// the file are kept as its header.
+func collectComments(data, format string) map[string][]string {
+	comments := make(map[string][]string)
+	var pending []string
+	seenKey := false
+	walkKeys(data, format, func(line, key string, isKey bool) {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case isKey:
+			seenKey = true
+			if len(pending) > 0 {
+				comments[key] = append(comments[key], pending...)
+				pending = nil
+			}
+		case strings.HasPrefix(trimmed, "#"):
+			pending = append(pending, strings.TrimSpace(strings.TrimPrefix(trimmed, "#")))
+		case trimmed == "" && !seenKey && len(pending) > 0:
+			comments[headerComments] = append(comments[headerComments], pending...)
+			pending = nil
+		}
+	})
+	return comments
+}
+
+// insertComments adds comments above the lines of their keys
+func insertComments(data, format string, comments map[string][]string) string {
+	var out strings.Builder
+	for _, comment := range comments[headerComments] {
+		out.WriteString("# " + comment + "\n")
+	}
+	if len(comments[headerComments]) > 0 {
+		out.WriteString("\n")
+	}
+	walkKeys(data, format, func(line, key string, isKey bool) {
+		if isKey {
+			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			for _, comment := range comments[key] {
+				out.WriteString(indent + "# " + comment + "\n")
+			}
+		}
+		out.WriteString(line + "\n")
+	})
+	return strings.TrimRight(out.String(), "\n") + "\n"
+}
+
+// walkKeys calls fn for every line of a YAML or TOML file with the dotted
+// key the line defines, if any. Keys are found by indentation in YAML and
+// by table headers in TOML; list items and multi-line values are skipped.
+func walkKeys(data, format string, fn func(line, key string, isKey bool)) {
+	type level struct {
+		indent int
+		key    string
+	}
+	var stack []level
+	table := ""
+
+	lines := strings.Split(strings.TrimRight(data, "\n"), "\n")
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			fn(line, "", false)
+			continue
+		}
+
+		key := ""
+		if format == FormatTOML {
+			switch {
+			case strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]"):
+				table = unquoteKey(strings.Trim(trimmed, "[]"))
+				key = table
+			case strings.Contains(trimmed, "="):
+				key = unquoteKey(trimmed[:strings.Index(trimmed, "=")])
+				if table != "" {
+					key = table + "." + key
+				}
+			}
+		} else if !strings.HasPrefix(trimmed, "- ") && strings.Contains(trimmed, ":") {
+			indent := len(line) - len(strings.TrimLeft(line, " "))
+			for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+				stack = stack[:len(stack)-1]
+			}
+			name := unquoteKey(trimmed[:strings.Index(trimmed, ":")])
+			parts := make([]string, 0, len(stack)+1)
+			for _, l := range stack {
+				parts = append(parts, l.key)
+			}
+			key = strings.Join(append(parts, name), ".")
+			if strings.HasSuffix(trimmed, ":") {
+				stack = append(stack, level{indent: indent, key: name})
+			}
+		}
+		fn(line, strings.ToLower(key), key != "")
+	}
+}
+
+// unquoteKey strips whitespace and quotes around a key
+func unquoteKey(key string) string {
+	return strings.Trim(strings.TrimSpace(key), `"'`)
+}
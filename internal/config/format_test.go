@@ -0,0 +1,92 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const commentedYAML = `# NetTraceX configuration
+
+network:
+  # Give slow links more time
+  timeout: 45s
+  dns_servers:
+    - 9.9.9.9
+  cache:
+    # Keep WHOIS answers for a day
+    whois_ttl: 24h
+ui:
+  theme: dark
+`
+
+func TestFormatFromPath(t *testing.T) {
+	assert.Equal(t, FormatYAML, FormatFromPath("nettracex.yaml"))
+	assert.Equal(t, FormatYAML, FormatFromPath("nettracex.YML"))
+	assert.Equal(t, FormatTOML, FormatFromPath("/etc/nettracex/nettracex.toml"))
+	assert.Equal(t, FormatJSON, FormatFromPath("nettracex.json"))
+	assert.Equal(t, FormatYAML, FormatFromPath("nettracex"))
+}
+
+func TestManagerLoadTOML(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "nettracex.toml")
+	writeTestConfig(t, configFile, "[network]\ntimeout = \"45s\"\ndns_servers = [\"9.9.9.9\"]\n\n[ui]\ntheme = \"dark\"\n")
+
+	manager := NewManager()
+	require.NoError(t, manager.LoadFromFile(configFile))
+	config := manager.GetConfig()
+	assert.Equal(t, 45*time.Second, config.Network.Timeout)
+	assert.Equal(t, []string{"9.9.9.9"}, config.Network.DNSServers)
+	assert.Equal(t, "dark", config.UI.Theme)
+
+	require.NoError(t, manager.Set("ui.theme", "light"))
+	require.NoError(t, manager.Save())
+	data, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "theme = 'light'")
+}
+
+func TestMigrateFile(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, "nettracex.yaml")
+	writeTestConfig(t, yamlFile, commentedYAML)
+
+	tomlFile := filepath.Join(dir, "nettracex.toml")
+	require.NoError(t, MigrateFile(yamlFile, tomlFile))
+	data, err := os.ReadFile(tomlFile)
+	require.NoError(t, err)
+	toml := string(data)
+	assert.True(t, strings.HasPrefix(toml, "# NetTraceX configuration\n\n"), toml)
+	assert.Contains(t, toml, "# Give slow links more time\ntimeout = '45s'")
+	assert.Contains(t, toml, "# Keep WHOIS answers for a day\nwhois_ttl = '24h'")
+	assert.NotContains(t, toml, "max_hops", "Expected defaults to stay out of the converted file")
+
+	manager := NewManager()
+	require.NoError(t, manager.LoadFromFile(tomlFile))
+	assert.Equal(t, 45*time.Second, manager.GetConfig().Network.Timeout)
+	assert.Equal(t, 24*time.Hour, manager.GetConfig().Network.Cache.WHOISTTL)
+
+	// And back again
+	backFile := filepath.Join(dir, "converted", "nettracex.yml")
+	require.NoError(t, manager.Migrate(backFile))
+	data, err = os.ReadFile(backFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "    # Give slow links more time\n    timeout: 45s")
+	assert.Contains(t, string(data), "        # Keep WHOIS answers for a day\n        whois_ttl: 24h")
+}
+
+func TestMigrateFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, "nettracex.yaml")
+	writeTestConfig(t, yamlFile, commentedYAML)
+
+	assert.Error(t, MigrateFile(yamlFile, filepath.Join(dir, "nettracex.ini")))
+	assert.Error(t, MigrateFile(yamlFile, yamlFile), "Expected an existing file not to be overwritten")
+	assert.Error(t, MigrateFile(filepath.Join(dir, "missing.yaml"), filepath.Join(dir, "out.toml")))
+	assert.Error(t, NewManager().Migrate(filepath.Join(dir, "out.toml")))
+}
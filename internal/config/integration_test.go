@@ -33,7 +33,7 @@ func TestConfigurationIntegration(t *testing.T) {
 		// Verify default values
 		config := manager.GetConfig()
 		assert.Equal(t, 30*time.Second, config.Network.Timeout)
-		assert.Equal(t, "default", config.UI.Theme)
+		assert.Equal(t, "auto", config.UI.Theme)
 		assert.Equal(t, domain.ExportFormatJSON, config.Export.DefaultFormat)
 		
 		// Modify configuration
@@ -414,6 +414,8 @@ ui:
 			{"network.retry_attempts", -1, "retry_attempts must be non-negative"},
 			{"ui.theme", "invalid", "theme must be one of"},
 			{"ui.color_mode", "invalid", "color_mode must be one of"},
+			{"ui.key_mode", "emacs", "key_mode must be one of"},
+			{"ui.language", "english", "language must be one of"},
 			{"export.output_directory", "", "output_directory cannot be empty"},
 		}
 		
@@ -431,16 +433,11 @@ ui:
 		err := manager.Load()
 		assert.NoError(t, err)
 		
-		// Try to save to an invalid path on Windows
-		// Use a path that should fail on Windows
-		invalidPath := "Z:\\nonexistent\\path\\config.yaml"
+		// Try to save below a regular file, which cannot be a directory
+		blocker := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(blocker, nil, 0644))
+		invalidPath := filepath.Join(blocker, "path", "config.yaml")
 		err = manager.SaveAs(invalidPath)
-		if err != nil {
-			// Error is expected for invalid paths
-			assert.Error(t, err)
-		} else {
-			// If no error, skip this test (might be running with special permissions)
-			t.Skip("Skipping file system error test - no error occurred for invalid path")
-		}
+		assert.Error(t, err)
 	})
 }
\ No newline at end of file
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/spf13/viper"
+)
+
+// Problem is one invalid configuration value
+type Problem struct {
+	Key     string // dotted key, e.g. network.timeout
+	Message string
+	Fix     string // suggested fix, may be empty
+}
+
+// String formats the problem as key: message (fix)
+func (p Problem) String() string {
+	s := p.Key + ": " + p.Message
+	if p.Fix != "" {
+		s += " (" + p.Fix + ")"
+	}
+	return s
+}
+
+// ValidationError lists every problem found in a configuration
+type ValidationError struct {
+	Problems []Problem
+}
+
+// Error implements error, one problem per line when there are several
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return e.Problems[0].String()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d problems:", len(e.Problems))
+	for _, problem := range e.Problems {
+		b.WriteString("\n  - " + problem.String())
+	}
+	return b.String()
+}
+
+// problems collects the problems of a validation pass
+type problems []Problem
+
+// add records a problem with key
+func (p *problems) add(key, message, fix string) {
+	*p = append(*p, Problem{Key: key, Message: message, Fix: fix})
+}
+
+// merge records the problems of err, or err itself as a problem with key
+func (p *problems) merge(key string, err error) {
+	if err == nil {
+		return
+	}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		*p = append(*p, validationErr.Problems...)
+		return
+	}
+	p.add(key, err.Error(), "")
+}
+
+// err returns the collected problems as a *ValidationError, or nil
+func (p problems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: p}
+}
+
+// unknownKeys reports the keys set in the configuration file of v that no
+// setting reads, which are usually misspelled. Keys below maps such as
+// ui.key_bindings are free-form.
+func unknownKeys(v *viper.Viper) problems {
+	known := newViper()
+	knownKeys := map[string]bool{VersionKey: true}
+	for _, key := range known.AllKeys() {
+		knownKeys[key] = true
+	}
+	maps := mapPrefixes(reflect.TypeOf(domain.Config{}), "")
+
+	var p problems
+	for _, key := range v.AllKeys() {
+		if knownKeys[key] || !v.InConfig(key) || hasAnyPrefix(key, maps) {
+			continue
+		}
+		fix := didYouMean(key, known.AllKeys())
+		if fix == "" {
+			fix = "remove it; nettracex -help lists the keys"
+		}
+		p.add(key, "unknown configuration key", fix)
+	}
+	return p
+}
+
+// mapPrefixes returns the key prefixes of the map fields of t, such as
+// "ui.key_bindings.", from their mapstructure tags
+func mapPrefixes(t reflect.Type, prefix string) []string {
+	var prefixes []string
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("mapstructure"), ",")[0]
+		switch t.Field(i).Type.Kind() {
+		case reflect.Map:
+			prefixes = append(prefixes, prefix+tag+".")
+		case reflect.Struct:
+			prefixes = append(prefixes, mapPrefixes(t.Field(i).Type, prefix+tag+".")...)
+		}
+	}
+	return prefixes
+}
+
+// hasAnyPrefix reports whether s starts with one of prefixes
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// didYouMean suggests the option closest to a misspelled value, if any
+func didYouMean(value string, options []string) string {
+	if suggestion := closest(strings.ToLower(value), options); suggestion != "" {
+		return fmt.Sprintf("did you mean %q?", suggestion)
+	}
+	return ""
+}
+
+// closest returns the option within a few edits of value, if any
+func closest(value string, options []string) string {
+	best, bestDistance := "", 3
+	for _, option := range options {
+		if distance := editDistance(value, option); distance < bestDistance {
+			best, bestDistance = option, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagerListsAllProblems(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "nettracex.yaml")
+	writeTestConfig(t, configFile, `network:
+  timout: 10s
+  max_hops: 0
+  dns_servers: []
+ui:
+  theme: drak
+logging:
+  file: /var/log/nettracex.log
+notify:
+  webhook_template: "{{.Message}}"
+`)
+
+	err := NewManager().LoadFromFile(configFile)
+	require.Error(t, err)
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr), "Expected a *ValidationError, got %v", err)
+
+	fixes := make(map[string]string)
+	for _, problem := range validationErr.Problems {
+		fixes[problem.Key] = problem.Fix
+	}
+	assert.Equal(t, `did you mean "network.timeout"?`, fixes["network.timout"])
+	assert.Contains(t, fixes, "network.max_hops")
+	assert.Contains(t, fixes, "network.dns_servers")
+	assert.Equal(t, `did you mean "dark"?`, fixes["ui.theme"])
+	assert.Contains(t, fixes, "logging.file")
+	assert.Contains(t, fixes, "notify.webhook_template")
+	assert.Contains(t, err.Error(), "6 problems:")
+}
+
+func TestValidationErrorSingleProblem(t *testing.T) {
+	err := (&problems{{Key: "ui.theme", Message: "theme must be one of: [default dark light minimal]"}}).err()
+	assert.Equal(t, "ui.theme: theme must be one of: [default dark light minimal]", err.Error())
+	assert.NoError(t, problems(nil).err())
+}
+
+func TestUnknownKeysAllowsMaps(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "nettracex.yaml")
+	writeTestConfig(t, configFile, "ui:\n  key_bindings:\n    refresh: ctrl+r\nplugins:\n  plugin_settings:\n    geo:\n      database: /tmp/geo.mmdb\n")
+	assert.NoError(t, NewManager().LoadFromFile(configFile))
+}
+
+func TestCommandTools(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "nettracex.yaml")
+	writeTestConfig(t, configFile, `plugins:
+  commands:
+    - name: dig
+      command: dig
+      args: ["+short", "{{.name}}", "{{.type}}"]
+      parameters:
+        - key: name
+          required: true
+        - key: type
+          default: A
+      timeout: 5s
+`)
+	manager := NewManager()
+	require.NoError(t, manager.LoadFromFile(configFile))
+	commands := manager.GetConfig().Plugins.Commands
+	require.Len(t, commands, 1)
+	assert.Equal(t, []string{"+short", "{{.name}}", "{{.type}}"}, commands[0].Args)
+	assert.Equal(t, "A", commands[0].Parameters[1].Default)
+	assert.True(t, commands[0].Parameters[0].Required)
+	assert.Equal(t, 5*time.Second, commands[0].Timeout)
+
+	writeTestConfig(t, configFile, `plugins:
+  commands:
+    - name: ports
+      args: ["{{.host"]
+      parse: regexp
+    - name: ports
+      command: nmap
+      parse: regex
+      pattern: "(\\d+)/tcp"
+`)
+	err := NewManager().LoadFromFile(configFile)
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr), "Expected a *ValidationError, got %v", err)
+	fixes := make(map[string]string)
+	for _, problem := range validationErr.Problems {
+		fixes[problem.Key] = problem.Fix
+	}
+	assert.Contains(t, fixes, "plugins.commands[0].command")
+	assert.Contains(t, fixes, "plugins.commands[0].args[0]")
+	assert.Equal(t, `did you mean "regex"?`, fixes["plugins.commands[0].parse"])
+	assert.Contains(t, fixes, "plugins.commands[1].name")
+	assert.Contains(t, fixes, "plugins.commands[1].pattern")
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("dark", "dark"))
+	assert.Equal(t, 2, editDistance("drak", "dark"))
+	assert.Equal(t, 1, editDistance("timout", "timeout"))
+	assert.Equal(t, 4, editDistance("", "json"))
+}
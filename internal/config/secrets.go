@@ -0,0 +1,183 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/secrets"
+	"github.com/spf13/viper"
+)
+
+// AgentTokenSecret names the keychain entry of the shared agent token,
+// used when NETTRACEX_AGENT_TOKEN is not set
+const AgentTokenSecret = "agent.token"
+
+// SecretKeys lists the configuration keys holding credentials. Their
+// values may be references such as keychain:notify.slack_webhook, which
+// are resolved from the keychain when the configuration is loaded.
+var SecretKeys = []string{
+	"network.proxy.http",
+	"network.proxy.ssl",
+	"network.proxy.tcp",
+	"network.proxy.whois",
+	"notify.discord_webhook",
+	"notify.slack_webhook",
+}
+
+// IsSecretKey reports whether key holds a credential
+func IsSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	return key == AgentTokenSecret || contains(SecretKeys, key)
+}
+
+// SetSecretStore replaces the keychain secrets are read from and written to
+func (m *Manager) SetSecretStore(store secrets.Store) {
+	m.secrets = store
+}
+
+// unmarshal decodes v into a new configuration and resolves the keychain
+// references of the secret keys. The published configuration is never
+// decoded into, since other goroutines may be reading it.
+func (m *Manager) unmarshal(v *viper.Viper) (*domain.Config, error) {
+	config := &domain.Config{}
+	if err := v.Unmarshal(config); err != nil {
+		return nil, err
+	}
+	for _, key := range SecretKeys {
+		name, ok := secrets.ParseReference(v.GetString(key))
+		if !ok {
+			continue
+		}
+		value, err := m.secrets.Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from the keychain: %w", key, err)
+		}
+		setField(reflect.ValueOf(config).Elem(), strings.Split(key, "."), value)
+	}
+	return config, nil
+}
+
+// setField sets the string field at the mapstructure path to value
+func setField(v reflect.Value, path []string, value string) {
+	for i := 0; i < v.NumField(); i++ {
+		tag := strings.Split(v.Type().Field(i).Tag.Get("mapstructure"), ",")[0]
+		if tag != path[0] {
+			continue
+		}
+		field := v.Field(i)
+		if len(path) == 1 && field.Kind() == reflect.String {
+			field.SetString(value)
+		} else if len(path) > 1 && field.Kind() == reflect.Struct {
+			setField(field, path[1:], value)
+		}
+		return
+	}
+}
+
+// SetSecret stores value in the keychain and points key at it, so the
+// configuration file only holds the reference. An empty value removes the
+// secret. The agent token is kept in the keychain alone.
+func (m *Manager) SetSecret(key, value string) error {
+	key = strings.ToLower(key)
+	if !IsSecretKey(key) {
+		return fmt.Errorf("%s is not a secret", key)
+	}
+
+	if value == "" {
+		if err := m.secrets.Delete(key); err != nil && !errors.Is(err, secrets.ErrNotFound) {
+			return fmt.Errorf("failed to remove %s from the keychain: %w", key, err)
+		}
+		if key == AgentTokenSecret {
+			return nil
+		}
+		return m.Set(key, "")
+	}
+
+	oldValue, err := m.secrets.Get(key)
+	hadValue := err == nil
+	if err := m.secrets.Set(key, value); err != nil {
+		return fmt.Errorf("failed to store %s in the keychain: %w", key, err)
+	}
+	// Only drop the plain text once the keychain is known to hold the value
+	if stored, err := m.secrets.Get(key); err != nil || stored != value {
+		return fmt.Errorf("failed to store %s in the keychain: the stored value could not be read back", key)
+	}
+	if key == AgentTokenSecret {
+		return nil
+	}
+	if err := m.Set(key, secrets.Reference(key)); err != nil {
+		if hadValue {
+			m.secrets.Set(key, oldValue)
+		} else {
+			m.secrets.Delete(key)
+		}
+		return err
+	}
+	return nil
+}
+
+// StoreSecrets moves the plain text secrets of the configuration into the
+// keychain, replacing them with references, and returns the moved keys.
+// Save writes the references to the configuration file.
+func (m *Manager) StoreSecrets() ([]string, error) {
+	var moved []string
+	for _, key := range SecretKeys {
+		if !m.viper.InConfig(key) {
+			continue
+		}
+		value := m.viper.GetString(key)
+		if _, ok := secrets.ParseReference(value); ok || value == "" {
+			continue
+		}
+		if err := m.SetSecret(key, value); err != nil {
+			return moved, err
+		}
+		moved = append(moved, key)
+	}
+	return moved, nil
+}
+
+// SecretStatus describes where the secret key is kept without revealing it
+func (m *Manager) SecretStatus(key string) string {
+	key = strings.ToLower(key)
+	if key == AgentTokenSecret {
+		if os.Getenv(EnvName(key)) != "" {
+			return "set in " + EnvName(key)
+		}
+		if _, err := m.secrets.Get(key); err == nil {
+			return "stored in keychain"
+		}
+		return "not set"
+	}
+
+	value := m.viper.GetString(key)
+	if name, ok := secrets.ParseReference(value); ok {
+		if _, err := m.secrets.Get(name); err != nil {
+			return "missing from keychain"
+		}
+		return "stored in keychain"
+	}
+	if value == "" {
+		return "not set"
+	}
+	return secrets.Mask(value) + " (plain text)"
+}
+
+// resetSecrets removes the secrets from the keychain and clears the keys
+// referring to them
+func (m *Manager) resetSecrets() error {
+	for _, key := range append([]string{AgentTokenSecret}, SecretKeys...) {
+		if err := m.secrets.Delete(key); err != nil && !errors.Is(err, secrets.ErrNotFound) {
+			return fmt.Errorf("failed to remove %s from the keychain: %w", key, err)
+		}
+		if key != AgentTokenSecret {
+			m.viper.Set(key, "")
+			m.markSettings(key)
+		}
+	}
+	return nil
+}
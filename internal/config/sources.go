@@ -0,0 +1,180 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/spf13/viper"
+)
+
+// ValueSource identifies the layer an effective configuration value comes from
+type ValueSource string
+
+// Configuration layers, from the lowest to the highest precedence
+const (
+	SourceDefault  ValueSource = "default"
+	SourceFile     ValueSource = "file"
+	SourceEnv      ValueSource = "env"
+	SourceFlag     ValueSource = "flag"
+	SourceSettings ValueSource = "settings"
+)
+
+// Precedence lists the configuration layers from the highest precedence down
+var Precedence = []ValueSource{SourceSettings, SourceFlag, SourceEnv, SourceFile, SourceDefault}
+
+// EnvName returns the environment variable overriding key, e.g.
+// NETTRACEX_NETWORK_TIMEOUT for network.timeout
+func EnvName(key string) string {
+	return "NETTRACEX_" + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+// Keys returns every configuration key that takes a value or a list, in
+// sorted order. Maps such as ui.key_bindings and lists of tables such as
+// plugins.commands can only be set in the file.
+func Keys() []string {
+	v := newViper()
+	tables := tableLists(reflect.TypeOf(domain.Config{}), "")
+	var keys []string
+	for _, key := range v.AllKeys() {
+		if value := v.Get(key); (value == nil || reflect.TypeOf(value).Kind() != reflect.Map) && !tables[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// tableLists returns the keys of the fields of t that are lists of
+// structs, from their mapstructure tags
+func tableLists(t reflect.Type, prefix string) map[string]bool {
+	keys := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		switch {
+		case field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Struct:
+			keys[prefix+tag] = true
+		case field.Type.Kind() == reflect.Struct:
+			for key := range tableLists(field.Type, prefix+tag+".") {
+				keys[key] = true
+			}
+		}
+	}
+	return keys
+}
+
+// Flags holds the command line overrides of configuration keys
+type Flags struct {
+	values map[string]*flagValue
+}
+
+// flagValue is a command line flag named after a configuration key. It
+// implements flag.Value for parsing and viper.FlagValue for binding.
+type flagValue struct {
+	key     string
+	value   string
+	changed bool
+}
+
+// String implements flag.Value
+func (f *flagValue) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.value
+}
+
+// Set implements flag.Value
+func (f *flagValue) Set(value string) error {
+	f.value = value
+	f.changed = true
+	return nil
+}
+
+// HasChanged implements viper.FlagValue
+func (f *flagValue) HasChanged() bool { return f.changed }
+
+// Name implements viper.FlagValue
+func (f *flagValue) Name() string { return f.key }
+
+// ValueString implements viper.FlagValue
+func (f *flagValue) ValueString() string { return f.value }
+
+// ValueType implements viper.FlagValue. Values are decoded like
+// environment variables when the configuration is unmarshalled.
+func (f *flagValue) ValueType() string { return "string" }
+
+// RegisterFlags defines a flag on fs for every configuration key, named
+// after the key, e.g. -network.timeout 5s. Lists are comma separated.
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	flags := &Flags{values: make(map[string]*flagValue)}
+	for _, key := range Keys() {
+		value := &flagValue{key: key}
+		flags.values[key] = value
+		fs.Var(value, key, "Override the "+key+" setting (also "+EnvName(key)+")")
+	}
+	return flags
+}
+
+// Changed returns the keys set on the command line in sorted order
+func (f *Flags) Changed() []string {
+	var keys []string
+	if f == nil {
+		return keys
+	}
+	for key, value := range f.values {
+		if value.changed {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// BindFlags layers the keys set on the command line over the environment
+// and the configuration file. Call it before Load.
+func (m *Manager) BindFlags(flags *Flags) {
+	m.flags = flags
+	m.bindFlags(m.viper)
+}
+
+// bindFlags binds the command line overrides to v
+func (m *Manager) bindFlags(v *viper.Viper) {
+	if m.flags == nil {
+		return
+	}
+	for key, value := range m.flags.values {
+		if value.changed {
+			v.BindFlagValue(key, value)
+		}
+	}
+}
+
+// markSettings records that key was changed in the settings screen
+func (m *Manager) markSettings(key string) {
+	if m.settings == nil {
+		m.settings = make(map[string]bool)
+	}
+	m.settings[key] = true
+}
+
+// Source returns the layer the effective value of key comes from
+func (m *Manager) Source(key string) ValueSource {
+	key = strings.ToLower(key)
+	switch {
+	case m.settings[key]:
+		return SourceSettings
+	case m.flags != nil && m.flags.values[key] != nil && m.flags.values[key].changed:
+		return SourceFlag
+	case os.Getenv(EnvName(key)) != "":
+		return SourceEnv
+	case m.viper.InConfig(key):
+		return SourceFile
+	default:
+		return SourceDefault
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"flag"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvName(t *testing.T) {
+	assert.Equal(t, "NETTRACEX_NETWORK_TIMEOUT", EnvName("network.timeout"))
+	assert.Equal(t, "NETTRACEX_NETWORK_CACHE_MAX_TTL", EnvName("network.cache.max_ttl"))
+}
+
+func TestKeys(t *testing.T) {
+	keys := Keys()
+	assert.Contains(t, keys, "network.timeout")
+	assert.Contains(t, keys, "notify.webhooks")
+	assert.NotContains(t, keys, "ui.key_bindings")
+	assert.NotContains(t, keys, "plugins.commands")
+	assert.IsIncreasing(t, keys)
+}
+
+func TestManagerPrecedence(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "nettracex.yaml")
+	writeTestConfig(t, configFile, "network:\n  timeout: 10s\n  max_hops: 20\n  packet_size: 128\n")
+	t.Setenv("NETTRACEX_NETWORK_TIMEOUT", "15s")
+	t.Setenv("NETTRACEX_NETWORK_MAX_HOPS", "25")
+
+	fs := flag.NewFlagSet("nettracex", flag.ContinueOnError)
+	flags := RegisterFlags(fs)
+	require.NoError(t, fs.Parse([]string{"-network.max_hops", "12", "-network.dns_servers", "9.9.9.9,1.1.1.1"}))
+	assert.Equal(t, []string{"network.dns_servers", "network.max_hops"}, flags.Changed())
+
+	manager := NewManager()
+	manager.BindFlags(flags)
+	require.NoError(t, manager.LoadFromFile(configFile))
+	config := manager.GetConfig()
+
+	assert.Equal(t, 12, config.Network.MaxHops)
+	assert.Equal(t, []string{"9.9.9.9", "1.1.1.1"}, config.Network.DNSServers)
+	assert.Equal(t, 15*time.Second, config.Network.Timeout)
+	assert.Equal(t, 128, config.Network.PacketSize)
+	assert.Equal(t, "NetTraceX/1.0", config.Network.UserAgent)
+
+	assert.Equal(t, SourceFlag, manager.Source("network.max_hops"))
+	assert.Equal(t, SourceFlag, manager.Source("network.dns_servers"))
+	assert.Equal(t, SourceEnv, manager.Source("network.timeout"))
+	assert.Equal(t, SourceFile, manager.Source("network.packet_size"))
+	assert.Equal(t, SourceDefault, manager.Source("network.user_agent"))
+
+	require.NoError(t, manager.Set("network.max_hops", 8))
+	assert.Equal(t, SourceSettings, manager.Source("network.max_hops"))
+	assert.Equal(t, 8, manager.GetConfig().Network.MaxHops)
+
+	// Reloading the file drops settings changes but keeps the flags
+	_, err := manager.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, SourceFlag, manager.Source("network.max_hops"))
+	assert.Equal(t, 12, manager.GetConfig().Network.MaxHops)
+}
+
+func TestManagerInvalidFlag(t *testing.T) {
+	fs := flag.NewFlagSet("nettracex", flag.ContinueOnError)
+	flags := RegisterFlags(fs)
+	require.NoError(t, fs.Parse([]string{"-network.max_hops", "1000"}))
+
+	manager := NewManager()
+	manager.BindFlags(flags)
+	assert.Error(t, manager.Load())
+}
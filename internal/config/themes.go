@@ -0,0 +1,54 @@
+// Package config contains the theme files of the configuration directory
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nettracex/nettracex-tui/internal/appdir"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// ThemeDir returns the directory theme files are read from, themes next to
+// the loaded configuration file or in the nettracex configuration directory
+func (m *Manager) ThemeDir() string {
+	dir := appdir.ConfigDir()
+	if m.configFile != "" {
+		dir = filepath.Dir(m.configFile)
+	}
+	return filepath.Join(dir, "themes")
+}
+
+// Themes returns the names ui.theme may take: the built-in themes followed
+// by the theme files of ThemeDir, sorted
+func (m *Manager) Themes() []string {
+	themes := append([]string(nil), domain.BuiltinThemes...)
+	var names []string
+	for name := range ThemeFiles(m.ThemeDir()) {
+		if !contains(themes, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return append(themes, names...)
+}
+
+// ThemeFiles returns the paths of the theme files in dir by theme name, the
+// file name without its .yaml or .yml extension. A missing dir has none.
+func ThemeFiles(dir string) map[string]string {
+	files := make(map[string]string)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return files
+	}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		files[strings.TrimSuffix(entry.Name(), ext)] = filepath.Join(dir, entry.Name())
+	}
+	return files
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagerThemes(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "nettracex.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("ui:\n  theme: ocean\n"), 0644))
+
+	manager := NewManager()
+	err := manager.LoadFromFile(configFile)
+	assert.Error(t, err, "ocean has no theme file yet")
+
+	themeDir := filepath.Join(dir, "themes")
+	require.NoError(t, os.MkdirAll(themeDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(themeDir, "ocean.yaml"), []byte("extends: dark\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(themeDir, "dark.yml"), []byte("border: thick\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(themeDir, "notes.txt"), []byte("not a theme"), 0644))
+
+	manager = NewManager()
+	require.NoError(t, manager.LoadFromFile(configFile))
+	assert.Equal(t, themeDir, manager.ThemeDir())
+	assert.Equal(t, append(append([]string(nil), domain.BuiltinThemes...), "ocean"), manager.Themes())
+	assert.Equal(t, "ocean", manager.GetConfig().UI.Theme)
+
+	files := ThemeFiles(themeDir)
+	assert.Equal(t, filepath.Join(themeDir, "dark.yml"), files["dark"])
+	assert.Len(t, files, 2)
+	assert.Empty(t, ThemeFiles(filepath.Join(dir, "missing")))
+}
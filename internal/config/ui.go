@@ -13,7 +13,10 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
 	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
+	"github.com/nettracex/nettracex-tui/internal/update"
 )
 
 // ConfigUIModel represents the configuration UI model
@@ -24,6 +27,9 @@ type ConfigUIModel struct {
 	settings    list.Model
 	editor      textinput.Model
 	currentKey  string
+	options     []string
+	original    string
+	previewed   string
 	width       int
 	height      int
 	styles      configUIStyles
@@ -32,6 +38,12 @@ type ConfigUIModel struct {
 	messageType messageType
 }
 
+// ThemePreviewMsg asks for the UI to be shown in Theme while ui.theme is
+// edited, before the value is saved
+type ThemePreviewMsg struct {
+	Theme string
+}
+
 type configUIState int
 
 const (
@@ -90,6 +102,7 @@ type ConfigSetting struct {
 	Value       interface{}
 	Type        string
 	Options     []string // For enum-like settings
+	Source      ValueSource
 }
 
 // ConfigSettingDelegate is a custom list delegate for configuration settings
@@ -153,8 +166,11 @@ func (d *ConfigSettingDelegate) Render(w io.Writer, m list.Model, index int, ite
 		settingLine += " - " + nameStyle.Copy().Faint(true).Render(setting.Description)
 	}
 	
-	// Render current value
+	// Render current value and the layer it comes from
 	valueLine := valueStyle.Render(fmt.Sprintf("    Current: %s", currentValue))
+	if setting.Source != "" {
+		valueLine += " " + valueStyle.Copy().Faint(true).Render("("+sourceLabel(setting.Key, setting.Source)+")")
+	}
 	
 	// Write both lines
 	fmt.Fprint(w, settingLine)
@@ -180,16 +196,16 @@ func NewConfigUIModel(manager *Manager) *ConfigUIModel {
 
 	// Initialize styles
 	styles := configUIStyles{
-		titleStyle:    lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205")),
-		sectionStyle:  lipgloss.NewStyle().Foreground(lipgloss.Color("86")),
-		settingStyle:  lipgloss.NewStyle().Foreground(lipgloss.Color("39")),
-		valueStyle:    lipgloss.NewStyle().Foreground(lipgloss.Color("214")),
-		selectedStyle: lipgloss.NewStyle().Background(lipgloss.Color("57")).Foreground(lipgloss.Color("230")),
-		errorStyle:    lipgloss.NewStyle().Foreground(lipgloss.Color("196")),
-		successStyle:  lipgloss.NewStyle().Foreground(lipgloss.Color("46")),
-		infoStyle:     lipgloss.NewStyle().Foreground(lipgloss.Color("33")),
-		helpStyle:     lipgloss.NewStyle().Foreground(lipgloss.Color("241")),
-		borderStyle:   lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("62")),
+		titleStyle:    lipgloss.NewStyle().Bold(true).Foreground(colors.Accent),
+		sectionStyle:  lipgloss.NewStyle().Foreground(colors.Adaptive("86")),
+		settingStyle:  lipgloss.NewStyle().Foreground(colors.Info),
+		valueStyle:    lipgloss.NewStyle().Foreground(colors.Warning),
+		selectedStyle: lipgloss.NewStyle().Background(colors.Adaptive("57")).Foreground(colors.Highlight),
+		errorStyle:    lipgloss.NewStyle().Foreground(colors.Error),
+		successStyle:  lipgloss.NewStyle().Foreground(colors.Online),
+		infoStyle:     lipgloss.NewStyle().Foreground(colors.Adaptive("33")),
+		helpStyle:     lipgloss.NewStyle().Foreground(colors.Subtle),
+		borderStyle:   lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(colors.Primary),
 	}
 
 	// Create sections list
@@ -254,6 +270,31 @@ func (m *ConfigUIModel) loadSections() {
 			Description: "Logging configuration",
 			Settings:    m.getLoggingSettings(config.Logging),
 		},
+		ConfigSection{
+			Name:        "Policy",
+			Description: "Safety policy for active scanning tools",
+			Settings:    m.getPolicySettings(config.Policy),
+		},
+		ConfigSection{
+			Name:        "Notify",
+			Description: "Alert notifications for scheduled probes",
+			Settings:    m.getNotifySettings(config.Notify),
+		},
+		ConfigSection{
+			Name:        "Telemetry",
+			Description: "Opt-in usage counts and crash signatures",
+			Settings:    m.getTelemetrySettings(config.Telemetry),
+		},
+		ConfigSection{
+			Name:        "Keys",
+			Description: "Key bindings of the TUI",
+			Settings:    m.getKeySettings(config.Keys),
+		},
+		ConfigSection{
+			Name:        "Secrets",
+			Description: "Credentials kept in the OS keychain",
+			Settings:    m.getSecretSettings(),
+		},
 	}
 	
 	m.sections.SetItems(sections)
@@ -311,6 +352,118 @@ func (m *ConfigUIModel) getNetworkSettings(config domain.NetworkConfig) []Config
 			Value:       config.RetryDelay.String(),
 			Type:        "duration",
 		},
+		{
+			Key:         "network.min_ping_interval",
+			Name:        "Min Ping Interval",
+			Description: "Shortest allowed gap between ping probes (0 uses the built-in 200ms)",
+			Value:       config.MinPingInterval.String(),
+			Type:        "duration",
+		},
+		{
+			Key:         "network.max_flood_count",
+			Name:        "Max Flood Count",
+			Description: "Maximum probes sent by a flood ping (0 uses the built-in 1000)",
+			Value:       config.MaxFloodCount,
+			Type:        "int",
+		},
+		{
+			Key:         "network.rate_limit",
+			Name:        "Rate Limit",
+			Description: "Operations started per second against one destination (0 for unlimited)",
+			Value:       config.RateLimit,
+			Type:        "float",
+		},
+		{
+			Key:         "network.rate_burst",
+			Name:        "Rate Burst",
+			Description: "Operations allowed at once before the rate limit applies",
+			Value:       config.RateBurst,
+			Type:        "int",
+		},
+		{
+			Key:         "network.dns_servers",
+			Name:        "DNS Servers",
+			Description: "DNS servers used for lookups, tried in order ('system' for the OS resolver)",
+			Value:       strings.Join(config.DNSServers, ", "),
+			Type:        "string_array",
+		},
+		{
+			Key:         "network.proxy.whois",
+			Name:        "WHOIS Proxy",
+			Description: "Proxy for WHOIS queries, e.g. socks5://host:1080 or http://host:3128 (empty for direct)",
+			Value:       config.Proxy.WHOIS,
+			Type:        "string",
+		},
+		{
+			Key:         "network.proxy.ssl",
+			Name:        "SSL Proxy",
+			Description: "Proxy for SSL certificate checks (empty for direct)",
+			Value:       config.Proxy.SSL,
+			Type:        "string",
+		},
+		{
+			Key:         "network.proxy.http",
+			Name:        "HTTP Proxy",
+			Description: "Proxy for HTTP requests such as geolocation lookups (empty for direct)",
+			Value:       config.Proxy.HTTP,
+			Type:        "string",
+		},
+		{
+			Key:         "network.proxy.tcp",
+			Name:        "TCP Proxy",
+			Description: "Proxy for TCP connect probes (empty for direct)",
+			Value:       config.Proxy.TCP,
+			Type:        "string",
+		},
+		{
+			Key:         "network.cache.enabled",
+			Name:        "Response Cache",
+			Description: "Cache DNS and WHOIS responses so repeated lookups are instant",
+			Value:       config.Cache.Enabled,
+			Type:        "bool",
+		},
+		{
+			Key:         "network.cache.persist",
+			Name:        "Persist Cache",
+			Description: "Keep cached responses on disk between sessions",
+			Value:       config.Cache.Persist,
+			Type:        "bool",
+		},
+		{
+			Key:         "network.cache.path",
+			Name:        "Cache File",
+			Description: "File holding persisted responses (empty for ~/.cache/nettracex/responses.json)",
+			Value:       config.Cache.Path,
+			Type:        "string",
+		},
+		{
+			Key:         "network.cache.max_entries",
+			Name:        "Cache Size",
+			Description: "Maximum number of cached responses",
+			Value:       config.Cache.MaxEntries,
+			Type:        "int",
+		},
+		{
+			Key:         "network.cache.whois_ttl",
+			Name:        "WHOIS Cache TTL",
+			Description: "How long WHOIS responses are cached",
+			Value:       config.Cache.WHOISTTL.String(),
+			Type:        "duration",
+		},
+		{
+			Key:         "network.cache.max_ttl",
+			Name:        "DNS Cache Max TTL",
+			Description: "Longest time a DNS answer is cached, whatever its TTL (0 for no cap)",
+			Value:       config.Cache.MaxTTL.String(),
+			Type:        "duration",
+		},
+		{
+			Key:         "network.cache.negative_ttl",
+			Name:        "DNS Negative TTL",
+			Description: "How long empty DNS answers and answers without TTLs are cached",
+			Value:       config.Cache.NegativeTTL.String(),
+			Type:        "duration",
+		},
 	}
 }
 
@@ -323,7 +476,7 @@ func (m *ConfigUIModel) getUISettings(config domain.UIConfig) []ConfigSetting {
 			Description: "UI color theme",
 			Value:       config.Theme,
 			Type:        "enum",
-			Options:     []string{"default", "dark", "light", "minimal"},
+			Options:     m.manager.Themes(),
 		},
 		{
 			Key:         "ui.animation_speed",
@@ -356,10 +509,97 @@ func (m *ConfigUIModel) getUISettings(config domain.UIConfig) []ConfigSetting {
 		{
 			Key:         "ui.color_mode",
 			Name:        "Color Mode",
-			Description: "Color output mode",
+			Description: "Color output mode, auto drops colors if NO_COLOR is set",
 			Value:       config.ColorMode,
 			Type:        "enum",
-			Options:     []string{"auto", "always", "never"},
+			Options:     []string{colors.ModeAuto, colors.ModeAlways, colors.ModeNever},
+		},
+		{
+			Key:         "ui.key_mode",
+			Name:        "Key Mode",
+			Description: "Key mode, vi adds hjkl, gg/G, / search and a : command line",
+			Value:       config.KeyMode,
+			Type:        "enum",
+			Options:     append([]string(nil), domain.KeyModes...),
+		},
+		{
+			Key:         "ui.language",
+			Name:        "Language",
+			Description: "Language of the TUI, auto follows LANG; applies on the next launch",
+			Value:       config.Language,
+			Type:        "enum",
+			Options:     append([]string{i18n.Auto}, i18n.Languages...),
+		},
+		{
+			Key:         "ui.accessible",
+			Name:        "Accessible Mode",
+			Description: "Plain text without borders, emoji or colors for screen readers; applies on the next launch",
+			Value:       config.Accessible,
+			Type:        "bool",
+		},
+		{
+			Key:         "ui.reduced_motion",
+			Name:        "Reduced Motion",
+			Description: "Stop spinners and once a second refreshes",
+			Value:       config.ReducedMotion,
+			Type:        "bool",
+		},
+		{
+			Key:         "ui.check_updates",
+			Name:        "Check For Updates",
+			Description: "Look for a newer release on GitHub once a day and show it in the header",
+			Value:       config.CheckUpdates,
+			Type:        "bool",
+		},
+		{
+			Key:         "ui.update_channel",
+			Name:        "Update Channel",
+			Description: "Release channel to look for updates in; rc, beta and alpha include prereleases",
+			Value:       config.UpdateChannel,
+			Type:        "enum",
+			Options:     update.ChannelNames(),
+		},
+		{
+			Key:         "ui.dashboard.show_on_start",
+			Name:        "Dashboard On Start",
+			Description: "Open the dashboard instead of the menu on start",
+			Value:       config.Dashboard.ShowOnStart,
+			Type:        "bool",
+		},
+		{
+			Key:         "ui.dashboard.widgets",
+			Name:        "Dashboard Widgets",
+			Description: "Widgets shown on the dashboard, in order (results, certificates, checks, hosts)",
+			Value:       strings.Join(config.Dashboard.Widgets, ", "),
+			Type:        "string_array",
+		},
+		{
+			Key:         "ui.dashboard.hosts",
+			Name:        "Favorite Hosts",
+			Description: "Hosts pinged live on the dashboard",
+			Value:       strings.Join(config.Dashboard.Hosts, ", "),
+			Type:        "string_array",
+		},
+		{
+			Key:         "ui.dashboard.certificates",
+			Name:        "Watched Certificates",
+			Description: "Certificates checked on the dashboard, as host or host:port",
+			Value:       strings.Join(config.Dashboard.Certificates, ", "),
+			Type:        "string_array",
+		},
+		{
+			Key:         "ui.dashboard.checks",
+			Name:        "Dashboard Checks",
+			Description: "Checks run on the dashboard, as tool:target",
+			Value:       strings.Join(config.Dashboard.Checks, ", "),
+			Type:        "string_array",
+		},
+		{
+			Key:         "ui.dashboard.interval",
+			Name:        "Dashboard Interval",
+			Description: "How often certificates and checks are rerun",
+			Value:       config.Dashboard.Interval.String(),
+			Type:        "duration",
 		},
 	}
 }
@@ -452,7 +692,14 @@ func (m *ConfigUIModel) getLoggingSettings(config domain.LoggingConfig) []Config
 			Description: "Log output destination",
 			Value:       config.Output,
 			Type:        "enum",
-			Options:     []string{"stdout", "stderr", "file"},
+			Options:     []string{"stdout", "stderr", "file", "syslog", "journald"},
+		},
+		{
+			Key:         "logging.file",
+			Name:        "Log File",
+			Description: "Log file used by the file output (empty for default)",
+			Value:       config.File,
+			Type:        "string",
 		},
 		{
 			Key:         "logging.max_size",
@@ -478,6 +725,187 @@ func (m *ConfigUIModel) getLoggingSettings(config domain.LoggingConfig) []Config
 	}
 }
 
+// getPolicySettings returns policy configuration settings
+func (m *ConfigUIModel) getPolicySettings(config domain.PolicyConfig) []ConfigSetting {
+	return []ConfigSetting{
+		{
+			Key:         "policy.public_target_mode",
+			Name:        "Public Target Mode",
+			Description: "Action when active tools target public IP space",
+			Value:       config.PublicTargetMode,
+			Type:        "enum",
+			Options:     []string{"off", "warn", "block"},
+		},
+		{
+			Key:         "policy.allow_list",
+			Name:        "Allow List",
+			Description: "Hosts, IPs or CIDRs that may be probed without confirmation",
+			Value:       strings.Join(config.AllowList, ", "),
+			Type:        "string_array",
+		},
+		{
+			Key:         "policy.active_tools",
+			Name:        "Active Tools",
+			Description: "Tools that generate traffic and are subject to the policy",
+			Value:       strings.Join(config.ActiveTools, ", "),
+			Type:        "string_array",
+		},
+		{
+			Key:         "policy.audit_log",
+			Name:        "Audit Log",
+			Description: "File recording consent acknowledgements (empty for default)",
+			Value:       config.AuditLog,
+			Type:        "string",
+		},
+	}
+}
+
+// getNotifySettings returns notification configuration settings
+func (m *ConfigUIModel) getNotifySettings(config domain.NotifyConfig) []ConfigSetting {
+	return []ConfigSetting{
+		{
+			Key:         "notify.webhooks",
+			Name:        "Webhooks",
+			Description: "URLs receiving alerts as generic webhook posts",
+			Value:       strings.Join(config.Webhooks, ", "),
+			Type:        "string_array",
+		},
+		{
+			Key:         "notify.webhook_template",
+			Name:        "Webhook Template",
+			Description: "Go template for generic webhook payloads (empty for JSON)",
+			Value:       config.WebhookTemplate,
+			Type:        "string",
+		},
+		{
+			Key:         "notify.slack_webhook",
+			Name:        "Slack Webhook",
+			Description: "Slack incoming webhook URL",
+			Value:       config.SlackWebhook,
+			Type:        "string",
+		},
+		{
+			Key:         "notify.discord_webhook",
+			Name:        "Discord Webhook",
+			Description: "Discord webhook URL",
+			Value:       config.DiscordWebhook,
+			Type:        "string",
+		},
+		{
+			Key:         "notify.host_down_after",
+			Name:        "Host Down After",
+			Description: "Consecutive failed probes before a host is reported down",
+			Value:       config.HostDownAfter,
+			Type:        "int",
+		},
+		{
+			Key:         "notify.cert_expiry_days",
+			Name:        "Certificate Expiry Days",
+			Description: "Alert when a certificate expires within this many days",
+			Value:       config.CertExpiryDays,
+			Type:        "int",
+		},
+		{
+			Key:         "notify.packet_loss_percent",
+			Name:        "Packet Loss Percent",
+			Description: "Alert when ping packet loss exceeds this percentage",
+			Value:       config.PacketLossPercent,
+			Type:        "float",
+		},
+	}
+}
+
+// getTelemetrySettings returns telemetry configuration settings
+func (m *ConfigUIModel) getTelemetrySettings(config domain.TelemetryConfig) []ConfigSetting {
+	return []ConfigSetting{
+		{
+			Key:         "telemetry.enabled",
+			Name:        "Enabled",
+			Description: "Count the features used and crash signatures, nothing else; see nettracex -telemetry-report",
+			Value:       config.Enabled,
+			Type:        "bool",
+		},
+		{
+			Key:         "telemetry.endpoint",
+			Name:        "Endpoint",
+			Description: "URL the report is posted to; empty keeps it local",
+			Value:       config.Endpoint,
+			Type:        "string",
+		},
+		{
+			Key:         "telemetry.interval",
+			Name:        "Interval",
+			Description: "How often the report is sent",
+			Value:       config.Interval,
+			Type:        "duration",
+		},
+	}
+}
+
+// getKeySettings returns the key bindings, one setting per action
+func (m *ConfigUIModel) getKeySettings(config domain.KeyConfig) []ConfigSetting {
+	var settings []ConfigSetting
+	for _, action := range config.Actions() {
+		settings = append(settings, ConfigSetting{
+			Key:         "keys." + action.Name,
+			Name:        action.Description,
+			Description: "Keys, comma separated, e.g. ctrl+n, j; each key may be bound to one action",
+			Value:       strings.Join(action.Keys, ", "),
+			Type:        "string_array",
+		})
+	}
+	return settings
+}
+
+// getSecretSettings returns the credentials that can be kept in the keychain.
+// Their values are never shown, only where they are stored.
+func (m *ConfigUIModel) getSecretSettings() []ConfigSetting {
+	return []ConfigSetting{
+		{
+			Key:         AgentTokenSecret,
+			Name:        "Agent Token",
+			Description: "Shared token for remote agents",
+			Type:        "secret",
+		},
+		{
+			Key:         "notify.slack_webhook",
+			Name:        "Slack Webhook",
+			Description: "Slack incoming webhook URL",
+			Type:        "secret",
+		},
+		{
+			Key:         "notify.discord_webhook",
+			Name:        "Discord Webhook",
+			Description: "Discord webhook URL",
+			Type:        "secret",
+		},
+		{
+			Key:         "network.proxy.http",
+			Name:        "HTTP Proxy",
+			Description: "Proxy URL with credentials for HTTP traffic",
+			Type:        "secret",
+		},
+		{
+			Key:         "network.proxy.ssl",
+			Name:        "SSL Proxy",
+			Description: "Proxy URL with credentials for TLS checks",
+			Type:        "secret",
+		},
+		{
+			Key:         "network.proxy.tcp",
+			Name:        "TCP Proxy",
+			Description: "Proxy URL with credentials for TCP connects",
+			Type:        "secret",
+		},
+		{
+			Key:         "network.proxy.whois",
+			Name:        "WHOIS Proxy",
+			Description: "Proxy URL with credentials for WHOIS lookups",
+			Type:        "secret",
+		},
+	}
+}
+
 // Init implements tea.Model
 func (m *ConfigUIModel) Init() tea.Cmd {
 	return nil
@@ -569,12 +997,18 @@ func (m *ConfigUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case stateEditingValue:
 			switch {
 			case key.Matches(msg, m.keyMap.Escape):
+				cmds = append(cmds, m.previewTheme(m.original))
 				m.cancelEditing()
 			case key.Matches(msg, m.keyMap.Enter):
 				m.saveCurrentValue()
+			case msg.Type == tea.KeyTab && len(m.options) > 0:
+				m.editor.SetValue(nextOption(m.options, m.editor.Value()))
+				m.editor.CursorEnd()
+				cmds = append(cmds, m.previewTheme(m.editor.Value()))
+				return m, tea.Batch(cmds...)
 			}
 			m.editor, cmd = m.editor.Update(msg)
-			cmds = append(cmds, cmd)
+			cmds = append(cmds, cmd, m.previewTheme(m.editor.Value()))
 		}
 	}
 
@@ -648,6 +1082,9 @@ func (m *ConfigUIModel) renderValueEditor() string {
 	content.WriteString(m.styles.helpStyle.Render("Editing: "+m.currentKey) + "\n\n")
 	content.WriteString("New value:\n")
 	content.WriteString(m.editor.View() + "\n\n")
+	if len(m.options) > 0 {
+		content.WriteString(m.styles.helpStyle.Render("Options: "+strings.Join(m.options, ", ")) + "\n")
+	}
 	content.WriteString(m.styles.helpStyle.Render("Press Enter to save, Esc to cancel"))
 	
 	return content.String()
@@ -662,8 +1099,12 @@ func (m *ConfigUIModel) renderHelp() string {
 		help.WriteString("Enter/→: Select section • s: Save config • r: Reset section • q: Quit")
 	case stateSelectingSetting:
 		help.WriteString("Enter/→: Edit setting • ←/Esc: Back • s: Save config")
+		help.WriteString("\nPrecedence: " + precedenceLabel())
 	case stateEditingValue:
 		help.WriteString("Enter: Save • Esc: Cancel")
+		if len(m.options) > 0 {
+			help.WriteString(" • Tab: Next option")
+		}
 	}
 	
 	return m.styles.helpStyle.Render(help.String())
@@ -677,6 +1118,10 @@ func (m *ConfigUIModel) loadSettings(settings []ConfigSetting) {
 		currentValue := m.manager.Get(setting.Key)
 		updatedSetting := setting
 		updatedSetting.Value = currentValue
+		updatedSetting.Source = m.manager.Source(setting.Key)
+		if IsSecretKey(setting.Key) {
+			updatedSetting.Value = m.manager.SecretStatus(setting.Key)
+		}
 		items[i] = updatedSetting
 	}
 	m.settings.SetItems(items)
@@ -685,7 +1130,16 @@ func (m *ConfigUIModel) loadSettings(settings []ConfigSetting) {
 // startEditing starts editing a configuration value
 func (m *ConfigUIModel) startEditing(setting ConfigSetting) {
 	m.currentKey = setting.Key
-	m.editor.SetValue(fmt.Sprintf("%v", setting.Value))
+	m.options = setting.Options
+	m.original = fmt.Sprintf("%v", setting.Value)
+	m.previewed = m.original
+	m.editor.SetValue(m.original)
+	if IsSecretKey(setting.Key) {
+		// Secrets are typed blind and never prefilled
+		m.editor.SetValue("")
+		m.editor.EchoMode = textinput.EchoPassword
+		m.editor.Placeholder = "Enter secret (empty to remove)..."
+	}
 	m.editor.Focus()
 	m.state = stateEditingValue
 }
@@ -694,14 +1148,60 @@ func (m *ConfigUIModel) startEditing(setting ConfigSetting) {
 func (m *ConfigUIModel) cancelEditing() {
 	m.editor.Blur()
 	m.editor.SetValue("")
+	m.editor.EchoMode = textinput.EchoNormal
+	m.editor.Placeholder = "Enter value..."
 	m.currentKey = ""
+	m.options = nil
 	m.state = stateSelectingSetting
 }
 
+// previewTheme asks for the UI to be shown in theme while ui.theme is
+// edited and theme is one of the themes, unless it already is
+func (m *ConfigUIModel) previewTheme(theme string) tea.Cmd {
+	if m.currentKey != "ui.theme" || theme == m.previewed || !contains(m.options, theme) {
+		return nil
+	}
+	m.previewed = theme
+	return func() tea.Msg {
+		return ThemePreviewMsg{Theme: theme}
+	}
+}
+
+// CapturesInput reports whether a value is being edited and needs every key
+func (m *ConfigUIModel) CapturesInput() bool {
+	return m.state == stateEditingValue
+}
+
+// nextOption returns the option after value, or the first option
+func nextOption(options []string, value string) string {
+	for i, option := range options {
+		if option == value {
+			return options[(i+1)%len(options)]
+		}
+	}
+	return options[0]
+}
+
 // saveCurrentValue saves the currently edited value
 func (m *ConfigUIModel) saveCurrentValue() {
 	value := m.editor.Value()
 	
+	// Secrets go to the keychain, leaving a reference in the file
+	if IsSecretKey(m.currentKey) {
+		if err := m.manager.SetSecret(m.currentKey, value); err != nil {
+			m.setMessage("Failed to store secret: "+err.Error(), messageTypeError)
+			return
+		}
+		if value == "" {
+			m.setMessage("Secret removed", messageTypeSuccess)
+		} else {
+			m.setMessage("Secret stored in the keychain", messageTypeSuccess)
+		}
+		m.cancelEditing()
+		m.reloadSelectedSection()
+		return
+	}
+	
 	// Parse value based on the setting type
 	parsedValue, err := m.parseValue(m.currentKey, value)
 	if err != nil {
@@ -719,6 +1219,22 @@ func (m *ConfigUIModel) saveCurrentValue() {
 	m.cancelEditing()
 	
 	// Reload the current section to show updated values
+	m.reloadSelectedSection()
+}
+
+// Refresh shows the current configuration values, e.g. after the
+// configuration file was reloaded
+func (m *ConfigUIModel) Refresh() {
+	selected := m.sections.Index()
+	m.loadSections()
+	m.sections.Select(selected)
+	if m.state != stateSelectingSection {
+		m.reloadSelectedSection()
+	}
+}
+
+// reloadSelectedSection loads fresh settings of the selected section
+func (m *ConfigUIModel) reloadSelectedSection() {
 	if section, ok := m.sections.SelectedItem().(ConfigSection); ok {
 		// Get fresh configuration and reload settings
 		config := m.manager.GetConfig()
@@ -735,6 +1251,16 @@ func (m *ConfigUIModel) saveCurrentValue() {
 			freshSettings = m.getExportSettings(config.Export)
 		case "Logging":
 			freshSettings = m.getLoggingSettings(config.Logging)
+		case "Policy":
+			freshSettings = m.getPolicySettings(config.Policy)
+		case "Notify":
+			freshSettings = m.getNotifySettings(config.Notify)
+		case "Telemetry":
+			freshSettings = m.getTelemetrySettings(config.Telemetry)
+		case "Keys":
+			freshSettings = m.getKeySettings(config.Keys)
+		case "Secrets":
+			freshSettings = m.getSecretSettings()
 		}
 		
 		m.loadSettings(freshSettings)
@@ -744,12 +1270,17 @@ func (m *ConfigUIModel) saveCurrentValue() {
 // parseValue parses a string value based on the configuration key
 func (m *ConfigUIModel) parseValue(key, value string) (interface{}, error) {
 	switch {
-	case strings.Contains(key, "timeout") || strings.Contains(key, "delay") || strings.Contains(key, "interval") || strings.Contains(key, "speed"):
+	case strings.Contains(key, "timeout") || strings.Contains(key, "delay") || strings.Contains(key, "interval") || strings.Contains(key, "speed") ||
+		 strings.HasSuffix(key, "_ttl"):
 		return time.ParseDuration(value)
 	case key == "network.max_hops" || key == "network.packet_size" || key == "network.max_concurrency" || key == "network.retry_attempts" || 
-		 key == "logging.max_size" || key == "logging.max_backups" || key == "logging.max_age":
+		 key == "network.max_flood_count" || key == "logging.max_size" || key == "logging.max_backups" || key == "logging.max_age" ||
+		 key == "notify.host_down_after" || key == "notify.cert_expiry_days" || key == "network.rate_burst" || key == "network.cache.max_entries":
 		return strconv.Atoi(value)
-	case strings.Contains(key, "auto_refresh") || strings.Contains(key, "show_help") || strings.Contains(key, "metadata") || strings.Contains(key, "compression"):
+	case key == "notify.packet_loss_percent" || key == "network.rate_limit":
+		return strconv.ParseFloat(value, 64)
+	case strings.Contains(key, "auto_refresh") || strings.Contains(key, "show_help") || strings.Contains(key, "metadata") || strings.Contains(key, "compression") ||
+		 key == "network.cache.enabled" || key == "network.cache.persist" || key == "ui.dashboard.show_on_start" || key == "telemetry.enabled":
 		return strconv.ParseBool(value)
 	case strings.Contains(key, "default_format"):
 		// Handle export format enum
@@ -760,10 +1291,19 @@ func (m *ConfigUIModel) parseValue(key, value string) (interface{}, error) {
 			return domain.ExportFormatCSV, nil
 		case "text":
 			return domain.ExportFormatText, nil
+		case "html":
+			return domain.ExportFormatHTML, nil
+		case "markdown", "md":
+			return domain.ExportFormatMarkdown, nil
+		case "pdf":
+			return domain.ExportFormatPDF, nil
 		default:
 			return nil, fmt.Errorf("invalid export format: %s", value)
 		}
-	case strings.Contains(key, "_plugins") || strings.Contains(key, "_paths") || strings.Contains(key, "dns_servers"):
+	case strings.Contains(key, "_plugins") || strings.Contains(key, "_paths") || strings.Contains(key, "dns_servers") ||
+		 key == "policy.allow_list" || key == "policy.active_tools" || key == "notify.webhooks" ||
+		 key == "ui.dashboard.widgets" || key == "ui.dashboard.hosts" || key == "ui.dashboard.certificates" || key == "ui.dashboard.checks" ||
+		 strings.HasPrefix(key, "keys."):
 		// Handle string arrays
 		if value == "" {
 			return []string{}, nil
@@ -784,6 +1324,31 @@ func (m *ConfigUIModel) setMessage(message string, msgType messageType) {
 	m.messageType = msgType
 }
 
+// sourceLabel describes where the value of key comes from
+func sourceLabel(key string, source ValueSource) string {
+	switch source {
+	case SourceEnv:
+		return "from " + EnvName(key)
+	case SourceFlag:
+		return "from flag -" + key
+	case SourceFile:
+		return "from config file"
+	case SourceSettings:
+		return "changed in settings"
+	default:
+		return "default"
+	}
+}
+
+// precedenceLabel lists the configuration layers from the highest precedence down
+func precedenceLabel() string {
+	names := make([]string, len(Precedence))
+	for i, source := range Precedence {
+		names[i] = string(source)
+	}
+	return strings.Join(names, " > ")
+}
+
 // FilterValue implements list.Item for ConfigSection
 func (c ConfigSection) FilterValue() string {
 	return c.Name
@@ -806,10 +1371,10 @@ func (m *ConfigUIModel) SetSize(width, height int) {
 func (m *ConfigUIModel) SetTheme(theme domain.Theme) {
 	// Update styles based on theme if needed
 	if theme != nil {
-		m.styles.titleStyle = m.styles.titleStyle.Foreground(lipgloss.Color(theme.GetColor("primary")))
-		m.styles.selectedStyle = m.styles.selectedStyle.Background(lipgloss.Color(theme.GetColor("primary")))
-		m.styles.errorStyle = m.styles.errorStyle.Foreground(lipgloss.Color(theme.GetColor("error")))
-		m.styles.successStyle = m.styles.successStyle.Foreground(lipgloss.Color(theme.GetColor("success")))
+		m.styles.titleStyle = m.styles.titleStyle.Foreground(colors.Adaptive(theme.GetColor("primary")))
+		m.styles.selectedStyle = m.styles.selectedStyle.Background(colors.Adaptive(theme.GetColor("primary")))
+		m.styles.errorStyle = m.styles.errorStyle.Foreground(colors.Adaptive(theme.GetColor("error")))
+		m.styles.successStyle = m.styles.successStyle.Foreground(colors.Adaptive(theme.GetColor("success")))
 	}
 }
 
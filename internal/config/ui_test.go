@@ -7,6 +7,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/nettracex/nettracex-tui/internal/domain"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewConfigUIModel(t *testing.T) {
@@ -251,6 +252,52 @@ func TestConfigUIModelGetSettings(t *testing.T) {
 	assert.Greater(t, len(loggingSettings), 0)
 }
 
+func TestConfigUIModelKeySettings(t *testing.T) {
+	manager := NewManager()
+	require.NoError(t, manager.Load())
+	model := NewConfigUIModel(manager)
+
+	settings := model.getKeySettings(manager.GetConfig().Keys)
+	require.NotEmpty(t, settings)
+	assert.Equal(t, "keys.up", settings[0].Key)
+	assert.Equal(t, "up, k", settings[0].Value)
+
+	value, err := model.parseValue("keys.down", "ctrl+n, down")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ctrl+n", "down"}, value)
+}
+
+func TestConfigUIModelThemePreview(t *testing.T) {
+	manager := NewManager()
+	require.NoError(t, manager.Load())
+	require.NoError(t, manager.Set("ui.theme", "default"))
+	model := NewConfigUIModel(manager)
+	model.width = 100
+	model.height = 50
+
+	settings := model.getUISettings(manager.GetConfig().UI)
+	require.Equal(t, "ui.theme", settings[0].Key)
+	assert.Equal(t, domain.BuiltinThemes, settings[0].Options)
+	model.startEditing(settings[0])
+	assert.True(t, model.CapturesInput())
+	assert.Contains(t, model.View(), "Options: auto, default, dark, light, solarized, high-contrast, minimal")
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	require.NotNil(t, cmd)
+	assert.Equal(t, ThemePreviewMsg{Theme: "dark"}, cmd())
+	assert.Equal(t, "dark", model.editor.Value())
+
+	// Half typed names are not previewed
+	model.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.Equal(t, "dark", model.previewed)
+
+	_, cmd = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	require.NotNil(t, cmd)
+	assert.Equal(t, ThemePreviewMsg{Theme: "default"}, cmd(), "cancelling restores the theme")
+	assert.False(t, model.CapturesInput())
+	assert.Equal(t, "default", manager.GetConfig().UI.Theme)
+}
+
 func TestConfigUIModelSetMessage(t *testing.T) {
 	manager := NewManager()
 	err := manager.Load()
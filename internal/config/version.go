@@ -0,0 +1,228 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// VersionKey is the key recording the schema version of a configuration
+// file. Files without it are version 0.
+const VersionKey = "config_version"
+
+// CurrentVersion is the schema version this build reads and writes
+const CurrentVersion = 1
+
+// Migration upgrades configuration files to Version from the version
+// before it. Renames and removals are applied first, then Apply.
+type Migration struct {
+	Version     int
+	Description string
+	Renames     map[string]string // old key to new key
+	Removes     []string
+	Apply       func(settings map[string]interface{}) error
+}
+
+// migrations upgrades configuration files one version at a time, in order.
+// Append a migration and bump CurrentVersion when a key is renamed or
+// removed, e.g.
+//
+//	{Version: 2, Description: "Rename network.timeout to network.dial_timeout",
+//		Renames: map[string]string{"network.timeout": "network.dial_timeout"}}
+var migrations = []Migration{
+	{Version: 1, Description: "Record the schema version in the file"},
+}
+
+// Upgrade describes how a configuration file was migrated when loaded
+type Upgrade struct {
+	File    string
+	From    int
+	To      int
+	Backup  string   // copy of the file before the upgrade
+	Applied []string // descriptions of the applied migrations
+	Err     error    // set when the upgraded file could not be written
+}
+
+// Upgraded returns the migration of the loaded configuration file, or nil
+// when it was already current
+func (m *Manager) Upgraded() *Upgrade {
+	return m.upgraded
+}
+
+// fileVersion returns the schema version of the file read by v
+func fileVersion(v *viper.Viper) int {
+	if !v.InConfig(VersionKey) {
+		return 0
+	}
+	return v.GetInt(VersionKey)
+}
+
+// upgradeFile migrates the configuration file read by v to the current
+// version and returns what it did, or nil when no migration changed a
+// key. The original is kept as a backup next to it and the upgraded file
+// replaces it. A file the migrations leave as it is, e.g. one that only
+// lacks the version, is not rewritten; Save records the version. When the
+// file cannot be written, e.g. a read-only system configuration, the
+// upgrade still applies in memory and Upgrade.Err says why the file was
+// left alone.
+func upgradeFile(v *viper.Viper) (*Upgrade, error) {
+	path := v.ConfigFileUsed()
+	version := fileVersion(v)
+	if version == CurrentVersion {
+		return nil, nil
+	}
+	if version > CurrentVersion {
+		return nil, fmt.Errorf("config file %s has version %d but this build reads up to version %d; upgrade NetTraceX", path, version, CurrentVersion)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	format := FormatFromPath(path)
+	plain := viper.New()
+	plain.SetConfigType(format)
+	if err := plain.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	settings := plain.AllSettings()
+	original := plain.AllSettings()
+	comments := collectComments(string(data), format)
+	upgrade := &Upgrade{File: path, From: version, To: CurrentVersion}
+	for _, migration := range migrations {
+		if migration.Version <= version {
+			continue
+		}
+		for oldKey, newKey := range migration.Renames {
+			if value, ok := removeSetting(settings, oldKey); ok {
+				setSetting(settings, newKey, value)
+			}
+			renameComments(comments, oldKey, newKey)
+		}
+		for _, key := range migration.Removes {
+			removeSetting(settings, key)
+		}
+		if migration.Apply != nil {
+			if err := migration.Apply(settings); err != nil {
+				return nil, fmt.Errorf("failed to upgrade config file %s to version %d: %w", path, migration.Version, err)
+			}
+		}
+		upgrade.Applied = append(upgrade.Applied, migration.Description)
+	}
+	if reflect.DeepEqual(settings, original) {
+		return nil, nil
+	}
+	settings[VersionKey] = CurrentVersion
+
+	upgraded, err := encodeSettings(settings, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upgrade config file %s: %w", path, err)
+	}
+	if format != FormatJSON && len(comments) > 0 {
+		upgraded = []byte(insertComments(string(upgraded), format, comments))
+	}
+
+	upgrade.Backup, upgrade.Err = backupAndReplace(path, version, data, upgraded)
+	if err := v.ReadConfig(bytes.NewReader(upgraded)); err != nil {
+		return nil, fmt.Errorf("failed to read upgraded config file: %w", err)
+	}
+	return upgrade, nil
+}
+
+// encodeSettings writes settings in format through a temporary file, since
+// viper only writes to files
+func encodeSettings(settings map[string]interface{}, format string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "nettracex-config")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	v := viper.New()
+	if err := v.MergeConfigMap(settings); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, "nettracex."+format)
+	if err := v.WriteConfigAs(path); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
+// backupAndReplace saves original next to path as path.v<version>.bak,
+// numbered if that exists, and then writes upgraded to path
+func backupAndReplace(path string, version int, original, upgraded []byte) (string, error) {
+	backup := fmt.Sprintf("%s.v%d.bak", path, version)
+	for i := 1; ; i++ {
+		if _, err := os.Stat(backup); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		backup = fmt.Sprintf("%s.v%d.%d.bak", path, version, i)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(backup, original, info.Mode().Perm()); err != nil {
+		return "", fmt.Errorf("failed to back up config file: %w", err)
+	}
+	if err := os.WriteFile(path, upgraded, info.Mode().Perm()); err != nil {
+		return backup, fmt.Errorf("failed to write upgraded config file: %w", err)
+	}
+	return backup, nil
+}
+
+// removeSetting deletes the dotted key from nested settings and returns
+// its value
+func removeSetting(settings map[string]interface{}, key string) (interface{}, bool) {
+	parts := strings.Split(strings.ToLower(key), ".")
+	parent := settings
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := parent[part].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		parent = child
+	}
+	value, ok := parent[parts[len(parts)-1]]
+	delete(parent, parts[len(parts)-1])
+	return value, ok
+}
+
+// setSetting sets the dotted key in nested settings, creating the maps on
+// the way
+func setSetting(settings map[string]interface{}, key string, value interface{}) {
+	parts := strings.Split(strings.ToLower(key), ".")
+	parent := settings
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := parent[part].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			parent[part] = child
+		}
+		parent = child
+	}
+	parent[parts[len(parts)-1]] = value
+}
+
+// renameComments moves the comments of oldKey and the keys below it to
+// newKey
+func renameComments(comments map[string][]string, oldKey, newKey string) {
+	renamed := make(map[string][]string)
+	for key, lines := range comments {
+		if key == oldKey || strings.HasPrefix(key, oldKey+".") {
+			delete(comments, key)
+			renamed[newKey+strings.TrimPrefix(key, oldKey)] = lines
+		}
+	}
+	for key, lines := range renamed {
+		comments[key] = lines
+	}
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagerUpgradesOldFiles(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "nettracex.yaml")
+	writeTestConfig(t, configFile, commentedYAML)
+
+	// Recording the version changes no key, so the file is left as written
+	manager := NewManager()
+	require.NoError(t, manager.LoadFromFile(configFile))
+	assert.Nil(t, manager.Upgraded())
+	assert.Equal(t, 45*time.Second, manager.GetConfig().Network.Timeout)
+	data, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, commentedYAML, string(data))
+	_, err = os.Stat(configFile + ".v0.bak")
+	assert.True(t, os.IsNotExist(err), "Expected no backup of an unchanged file")
+
+	// Saving records the version
+	require.NoError(t, manager.Save())
+	data, err = os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "config_version: 1")
+
+	// A current file is left alone
+	manager = NewManager()
+	require.NoError(t, manager.LoadFromFile(configFile))
+	assert.Nil(t, manager.Upgraded())
+}
+
+func TestManagerMigrationChain(t *testing.T) {
+	saved := migrations
+	defer func() { migrations = saved }()
+	migrations = append(append([]Migration{}, saved...), Migration{
+		Version:     CurrentVersion + 1,
+		Description: "Rename network.timeout",
+		Renames:     map[string]string{"network.timeout": "network.dial_timeout"},
+		Removes:     []string{"ui.theme"},
+	})
+
+	configFile := filepath.Join(t.TempDir(), "nettracex.yaml")
+	writeTestConfig(t, configFile, commentedYAML)
+	writeTestConfig(t, configFile+".v0.bak", "taken")
+
+	// The renamed key is not a setting of this build, so validation fails
+	// after the upgrade
+	manager := NewManager()
+	assert.ErrorContains(t, manager.LoadFromFile(configFile), "network.dial_timeout")
+	upgrade := manager.Upgraded()
+	require.NotNil(t, upgrade)
+	assert.Equal(t, []string{"Record the schema version in the file", "Rename network.timeout"}, upgrade.Applied)
+	assert.Equal(t, configFile+".v0.1.bak", upgrade.Backup)
+
+	data, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# Give slow links more time\n    dial_timeout: 45s")
+	assert.NotContains(t, string(data), "theme")
+}
+
+func TestManagerRejectsNewerFiles(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "nettracex.yaml")
+	writeTestConfig(t, configFile, "config_version: 99\n")
+	assert.ErrorContains(t, NewManager().LoadFromFile(configFile), "version 99")
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long Watch waits for writes to the configuration
+// file to settle, since editors often save in several steps
+const watchDebounce = 200 * time.Millisecond
+
+// Reload re-reads the configuration file and applies it when it is valid,
+// returning the keys whose values changed. The new configuration is
+// published as a fresh snapshot, leaving the one other goroutines may hold
+// untouched, and listeners are notified of every change. Command line flags keep their precedence while
+// changes made in the settings screen give way to the file. Files of an
+// older schema version are upgraded as in Load. An unreadable or invalid
+// file leaves the current configuration untouched.
+func (m *Manager) Reload() ([]string, error) {
+	if m.configFile == "" {
+		return nil, nil
+	}
+
+	v := newViper()
+	m.bindFlags(v)
+	v.SetConfigFile(m.configFile)
+	v.SetConfigType(FormatFromPath(m.configFile))
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", m.configFile, err)
+	}
+	if _, err := upgradeFile(v); err != nil {
+		return nil, err
+	}
+	config, err := m.unmarshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	if err := m.validateLoaded(v, config); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	keys := make(map[string]bool)
+	for _, key := range m.viper.AllKeys() {
+		keys[key] = true
+	}
+	for _, key := range v.AllKeys() {
+		keys[key] = true
+	}
+
+	// Values set in the settings screen are typed while values read from
+	// the file are not, so compare their printed form
+	oldValues := make(map[string]interface{})
+	var changed []string
+	delete(keys, VersionKey)
+	for key := range keys {
+		oldValue, newValue := m.viper.Get(key), v.Get(key)
+		if fmt.Sprint(oldValue) != fmt.Sprint(newValue) {
+			oldValues[key] = oldValue
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+
+	m.viper = v
+	m.settings = nil
+	m.config.Store(config)
+	for _, key := range changed {
+		m.notifyListeners(key, oldValues[key], v.Get(key))
+	}
+	return changed, nil
+}
+
+// Watch calls onChange whenever the loaded configuration file is written,
+// replaced or recreated, typically to Reload it. The directory is watched
+// rather than the file so editors that save by renaming are noticed. The
+// returned function stops watching.
+func (m *Manager) Watch(onChange func()) (func() error, error) {
+	if m.configFile == "" {
+		return nil, errors.New("no configuration file loaded")
+	}
+	path, err := filepath.Abs(m.configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config file: %w", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch config file: %w", err)
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("failed to watch config file: %w", err)
+	}
+
+	timer := time.AfterFunc(watchDebounce, onChange)
+	timer.Stop()
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) == path && event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+					timer.Reset(watchDebounce)
+				}
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	return func() error {
+		timer.Stop()
+		return watcher.Close()
+	}, nil
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestConfig writes content to a config file in a temporary directory
+func writeTestConfig(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestManagerReload(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "nettracex.yaml")
+	writeTestConfig(t, configFile, "network:\n  timeout: 10s\n  dns_servers: [\"1.1.1.1\"]\nui:\n  theme: default\n")
+
+	manager := NewManager()
+	require.NoError(t, manager.LoadFromFile(configFile))
+	before := manager.GetConfig()
+
+	var notified []string
+	manager.AddChangeListener(func(key string, oldValue, newValue interface{}) {
+		notified = append(notified, key)
+	})
+
+	writeTestConfig(t, configFile, "network:\n  timeout: 5s\n  dns_servers: [\"9.9.9.9\"]\nui:\n  theme: dark\n")
+	changed, err := manager.Reload()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"network.dns_servers", "network.timeout", "ui.theme"}, changed)
+	assert.Equal(t, changed, notified)
+	config := manager.GetConfig()
+	assert.Equal(t, 5*time.Second, config.Network.Timeout)
+	assert.Equal(t, []string{"9.9.9.9"}, config.Network.DNSServers)
+	assert.Equal(t, "dark", config.UI.Theme)
+	// The previous snapshot is left as it was for goroutines still reading it
+	assert.Equal(t, 10*time.Second, before.Network.Timeout)
+	assert.Equal(t, []string{"1.1.1.1"}, before.Network.DNSServers)
+
+	changed, err = manager.Reload()
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+}
+
+func TestManagerReloadInvalid(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "nettracex.yaml")
+	writeTestConfig(t, configFile, "network:\n  timeout: 10s\n")
+
+	manager := NewManager()
+	require.NoError(t, manager.LoadFromFile(configFile))
+
+	writeTestConfig(t, configFile, "network:\n  timeout: 10s\n  max_hops: 1000\n")
+	_, err := manager.Reload()
+	assert.Error(t, err)
+	assert.Equal(t, 30, manager.GetConfig().Network.MaxHops)
+
+	writeTestConfig(t, configFile, "network: [unterminated\n")
+	_, err = manager.Reload()
+	assert.Error(t, err)
+	assert.Equal(t, 10*time.Second, manager.GetConfig().Network.Timeout)
+}
+
+func TestManagerReloadWithoutFile(t *testing.T) {
+	manager := NewManager()
+	changed, err := manager.Reload()
+	assert.NoError(t, err)
+	assert.Empty(t, changed)
+
+	_, err = manager.Watch(func() {})
+	assert.Error(t, err)
+}
+
+func TestManagerWatch(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "nettracex.yaml")
+	writeTestConfig(t, configFile, "ui:\n  theme: default\n")
+
+	manager := NewManager()
+	require.NoError(t, manager.LoadFromFile(configFile))
+
+	changes := make(chan struct{}, 10)
+	stop, err := manager.Watch(func() { changes <- struct{}{} })
+	require.NoError(t, err)
+	defer stop()
+
+	// Other files in the directory are ignored
+	writeTestConfig(t, filepath.Join(dir, "other.yaml"), "ignored: true\n")
+	select {
+	case <-changes:
+		t.Fatal("Expected changes to other files to be ignored")
+	case <-time.After(2 * watchDebounce):
+	}
+
+	// Several writes in quick succession are reported once
+	writeTestConfig(t, configFile, "ui:\n  theme: light\n")
+	writeTestConfig(t, configFile, "ui:\n  theme: dark\n")
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the change to be reported")
+	}
+	select {
+	case <-changes:
+		t.Fatal("Expected the writes to be reported once")
+	case <-time.After(2 * watchDebounce):
+	}
+
+	changed, err := manager.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ui.theme"}, changed)
+}
@@ -0,0 +1,409 @@
+// Package diff compares two results of the same diagnostic tool row by row
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/stats"
+	"github.com/nettracex/nettracex-tui/internal/tools/dns"
+)
+
+// Change classifies how a row differs between the two results
+type Change int
+
+const (
+	// Unchanged means both results agree
+	Unchanged Change = iota
+	// Changed means the row exists in both results with different values
+	Changed
+	// Added means the row only exists in the newer result
+	Added
+	// Removed means the row only exists in the older result
+	Removed
+)
+
+// Row is one line of a side-by-side comparison
+type Row struct {
+	Label  string
+	Before string
+	After  string
+	Delta  string
+	Change Change
+}
+
+// Results compares two result data values of the same type, older first
+func Results(before, after interface{}) ([]Row, error) {
+	if fmt.Sprintf("%T", before) != fmt.Sprintf("%T", after) {
+		return nil, fmt.Errorf("cannot compare %T with %T", before, after)
+	}
+
+	switch b := before.(type) {
+	case []domain.PingResult:
+		return pingRows("", b, after.([]domain.PingResult)), nil
+	case domain.MultiPingResult:
+		return multiPingRows(b, after.(domain.MultiPingResult)), nil
+	case []domain.TraceHop:
+		return traceRows(b, after.([]domain.TraceHop)), nil
+	case domain.DNSResult:
+		return dnsRows(b, after.(domain.DNSResult)), nil
+	case domain.WHOISResult:
+		return whoisRows(b, after.(domain.WHOISResult)), nil
+	case domain.SSLResult:
+		return sslRows(b, after.(domain.SSLResult)), nil
+	case domain.DualStackResult:
+		return dualStackRows(b, after.(domain.DualStackResult)), nil
+	case domain.SweepResult:
+		return sweepRows(b, after.(domain.SweepResult)), nil
+	case domain.ZoneTransferResult:
+		return zoneTransferRows(b, after.(domain.ZoneTransferResult)), nil
+	default:
+		return nil, fmt.Errorf("comparison is not supported for %T", before)
+	}
+}
+
+// Summary counts the changed, added and removed rows
+func Summary(rows []Row) (changed, added, removed int) {
+	for _, row := range rows {
+		switch row.Change {
+		case Changed:
+			changed++
+		case Added:
+			added++
+		case Removed:
+			removed++
+		}
+	}
+	return changed, added, removed
+}
+
+// valueRow compares two display values
+func valueRow(label, before, after string) Row {
+	row := Row{Label: label, Before: before, After: after}
+	if before != after {
+		row.Change = Changed
+	}
+	return row
+}
+
+// durationRow compares two latencies and reports the delta. Latency always
+// varies a little, so the row only counts as changed when the delta is
+// larger than threshold.
+func durationRow(label string, before, after, threshold time.Duration) Row {
+	delta := after - before
+	row := Row{
+		Label:  label,
+		Before: formatDuration(before),
+		After:  formatDuration(after),
+		Delta:  formatDelta(delta),
+	}
+	if delta > threshold || -delta > threshold {
+		row.Change = Changed
+	}
+	return row
+}
+
+// presenceRow builds a row for a keyed entry that may exist in only one result
+func presenceRow(label, before, after string, inBefore, inAfter bool) Row {
+	switch {
+	case !inBefore:
+		return Row{Label: label, After: after, Change: Added}
+	case !inAfter:
+		return Row{Label: label, Before: before, Change: Removed}
+	default:
+		return valueRow(label, before, after)
+	}
+}
+
+// formatDuration formats a latency with a precision suited to network RTTs
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	return d.Round(10 * time.Microsecond).String()
+}
+
+// formatDelta formats a latency delta with an explicit sign
+func formatDelta(d time.Duration) string {
+	if d == 0 {
+		return "±0"
+	}
+	if d > 0 {
+		return "+" + d.Round(10*time.Microsecond).String()
+	}
+	return "-" + (-d).Round(10*time.Microsecond).String()
+}
+
+// pingRows compares loss and latency statistics of two ping runs
+func pingRows(prefix string, before, after []domain.PingResult) []Row {
+	sentBefore, lossBefore, summaryBefore := pingSummary(before)
+	sentAfter, lossAfter, summaryAfter := pingSummary(after)
+	threshold := rttThreshold(summaryBefore.Mean)
+
+	return []Row{
+		valueRow(prefix+"Sent", fmt.Sprintf("%d", sentBefore), fmt.Sprintf("%d", sentAfter)),
+		valueRow(prefix+"Loss", fmt.Sprintf("%.1f%%", lossBefore), fmt.Sprintf("%.1f%%", lossAfter)),
+		durationRow(prefix+"Min RTT", summaryBefore.Min, summaryAfter.Min, threshold),
+		durationRow(prefix+"Avg RTT", summaryBefore.Mean, summaryAfter.Mean, threshold),
+		durationRow(prefix+"Max RTT", summaryBefore.Max, summaryAfter.Max, threshold),
+		durationRow(prefix+"Jitter", summaryBefore.Jitter, summaryAfter.Jitter, threshold),
+	}
+}
+
+// pingSummary returns the probes sent, loss percentage and RTT summary of a ping run
+func pingSummary(results []domain.PingResult) (int, float64, stats.Summary) {
+	var samples []time.Duration
+	for _, result := range results {
+		if result.Error == nil {
+			samples = append(samples, result.RTT)
+		}
+	}
+	return len(results), stats.Loss(len(results), len(samples)), stats.Summarize(samples)
+}
+
+// rttThreshold returns the latency change considered significant: 10% of
+// the baseline, but at least one millisecond
+func rttThreshold(baseline time.Duration) time.Duration {
+	if threshold := baseline / 10; threshold > time.Millisecond {
+		return threshold
+	}
+	return time.Millisecond
+}
+
+// multiPingRows compares each host of a multi-target ping
+func multiPingRows(before, after domain.MultiPingResult) []Row {
+	beforeByHost := make(map[string]domain.PingTargetResult)
+	for _, target := range before.Targets {
+		beforeByHost[target.Host] = target
+	}
+	afterByHost := make(map[string]domain.PingTargetResult)
+	for _, target := range after.Targets {
+		afterByHost[target.Host] = target
+	}
+
+	var rows []Row
+	for _, host := range unionKeys(beforeByHost, afterByHost) {
+		b, inBefore := beforeByHost[host]
+		a, inAfter := afterByHost[host]
+		if !inBefore || !inAfter {
+			rows = append(rows, presenceRow(host, formatDuration(b.AvgRTT), formatDuration(a.AvgRTT), inBefore, inAfter))
+			continue
+		}
+		rows = append(rows, pingRows(host+" ", b.Results, a.Results)...)
+	}
+	return rows
+}
+
+// traceRows compares the responding router and latency of each hop
+func traceRows(before, after []domain.TraceHop) []Row {
+	beforeByHop := make(map[int]domain.TraceHop)
+	for _, hop := range before {
+		beforeByHop[hop.Number] = hop
+	}
+	afterByHop := make(map[int]domain.TraceHop)
+	for _, hop := range after {
+		afterByHop[hop.Number] = hop
+	}
+
+	var numbers []int
+	for number := range beforeByHop {
+		numbers = append(numbers, number)
+	}
+	for number := range afterByHop {
+		if _, ok := beforeByHop[number]; !ok {
+			numbers = append(numbers, number)
+		}
+	}
+	sort.Ints(numbers)
+
+	var rows []Row
+	for _, number := range numbers {
+		b, inBefore := beforeByHop[number]
+		a, inAfter := afterByHop[number]
+		label := fmt.Sprintf("Hop %d", number)
+		if !inBefore || !inAfter {
+			rows = append(rows, presenceRow(label, hopAddress(b), hopAddress(a), inBefore, inAfter))
+			continue
+		}
+
+		avgBefore, avgAfter := stats.Mean(b.RTT), stats.Mean(a.RTT)
+		row := Row{
+			Label:  label,
+			Before: fmt.Sprintf("%s %s", hopAddress(b), formatDuration(avgBefore)),
+			After:  fmt.Sprintf("%s %s", hopAddress(a), formatDuration(avgAfter)),
+		}
+		delta := avgAfter - avgBefore
+		answered := !b.Timeout && !a.Timeout
+		if answered {
+			row.Delta = formatDelta(delta)
+		}
+		// A different router at the same hop means the path changed
+		threshold := rttThreshold(avgBefore)
+		if hopAddress(b) != hopAddress(a) || (answered && (delta > threshold || -delta > threshold)) {
+			row.Change = Changed
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// hopAddress returns the responding address of a hop, or "*" when it timed out
+func hopAddress(hop domain.TraceHop) string {
+	if hop.Timeout || hop.Host.IPAddress == nil {
+		return "*"
+	}
+	return hop.Host.IPAddress.String()
+}
+
+// dnsRows compares the record sets of two lookups
+func dnsRows(before, after domain.DNSResult) []Row {
+	beforeRecords := dnsRecordSet(before.Records)
+	afterRecords := dnsRecordSet(after.Records)
+
+	var rows []Row
+	for _, key := range unionKeys(beforeRecords, afterRecords) {
+		b, inBefore := beforeRecords[key]
+		a, inAfter := afterRecords[key]
+		label := strings.SplitN(key, " ", 2)[0]
+		row := presenceRow(label, b.Value, a.Value, inBefore, inAfter)
+		if inBefore && inAfter {
+			row.Before = fmt.Sprintf("%s (TTL %d)", b.Value, b.TTL)
+			row.After = fmt.Sprintf("%s (TTL %d)", a.Value, a.TTL)
+			// TTLs count down in caches, so only the value decides the change
+			row.Change = Unchanged
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// dnsRecordSet indexes records by "<type> <value>"
+func dnsRecordSet(records []domain.DNSRecord) map[string]domain.DNSRecord {
+	set := make(map[string]domain.DNSRecord, len(records))
+	for _, record := range records {
+		set[fmt.Sprintf("%s %s", dns.GetRecordTypeString(record.Type), record.Value)] = record
+	}
+	return set
+}
+
+// whoisRows compares registration details
+func whoisRows(before, after domain.WHOISResult) []Row {
+	return []Row{
+		valueRow("Registrar", before.Registrar, after.Registrar),
+		valueRow("Updated", formatDate(before.Updated), formatDate(after.Updated)),
+		valueRow("Expires", formatDate(before.Expires), formatDate(after.Expires)),
+		valueRow("Name Servers", joinSorted(before.NameServers), joinSorted(after.NameServers)),
+		valueRow("Status", joinSorted(before.Status), joinSorted(after.Status)),
+	}
+}
+
+// sslRows compares certificate details
+func sslRows(before, after domain.SSLResult) []Row {
+	return []Row{
+		valueRow("Subject", before.Subject, after.Subject),
+		valueRow("Issuer", before.Issuer, after.Issuer),
+		valueRow("Valid", fmt.Sprintf("%t", before.Valid), fmt.Sprintf("%t", after.Valid)),
+		valueRow("Expires", formatDate(before.Expiry), formatDate(after.Expiry)),
+		valueRow("SANs", joinSorted(before.SANs), joinSorted(after.SANs)),
+	}
+}
+
+// dualStackRows compares connection times per address family
+func dualStackRows(before, after domain.DualStackResult) []Row {
+	return []Row{
+		valueRow("IPv4 Address", before.IPv4.Connected, after.IPv4.Connected),
+		durationRow("IPv4 Connect", before.IPv4.ConnectTime, after.IPv4.ConnectTime, rttThreshold(before.IPv4.ConnectTime)),
+		valueRow("IPv6 Address", before.IPv6.Connected, after.IPv6.Connected),
+		durationRow("IPv6 Connect", before.IPv6.ConnectTime, after.IPv6.ConnectTime, rttThreshold(before.IPv6.ConnectTime)),
+		valueRow("Winner", before.Winner, after.Winner),
+	}
+}
+
+// sweepRows reports hosts that appeared or disappeared between sweeps
+func sweepRows(before, after domain.SweepResult) []Row {
+	beforeHosts := make(map[string]domain.SweepHost)
+	for _, host := range before.Hosts {
+		beforeHosts[host.IP.String()] = host
+	}
+	afterHosts := make(map[string]domain.SweepHost)
+	for _, host := range after.Hosts {
+		afterHosts[host.IP.String()] = host
+	}
+
+	rows := []Row{valueRow("Alive", fmt.Sprintf("%d", len(before.Hosts)), fmt.Sprintf("%d", len(after.Hosts)))}
+	for _, ip := range unionKeys(beforeHosts, afterHosts) {
+		b, inBefore := beforeHosts[ip]
+		a, inAfter := afterHosts[ip]
+		if inBefore && inAfter {
+			rows = append(rows, durationRow(ip, b.RTT, a.RTT, rttThreshold(b.RTT)))
+			continue
+		}
+		rows = append(rows, presenceRow(ip, formatDuration(b.RTT), formatDuration(a.RTT), inBefore, inAfter))
+	}
+	return rows
+}
+
+// zoneTransferRows compares the AXFR outcome per nameserver address
+func zoneTransferRows(before, after domain.ZoneTransferResult) []Row {
+	beforeServers := make(map[string]domain.ZoneTransferServer)
+	for _, server := range before.Servers {
+		beforeServers[server.Nameserver+" "+server.Address] = server
+	}
+	afterServers := make(map[string]domain.ZoneTransferServer)
+	for _, server := range after.Servers {
+		afterServers[server.Nameserver+" "+server.Address] = server
+	}
+
+	var rows []Row
+	for _, key := range unionKeys(beforeServers, afterServers) {
+		b, inBefore := beforeServers[key]
+		a, inAfter := afterServers[key]
+		rows = append(rows, presenceRow(strings.TrimSpace(key), transferStatus(b), transferStatus(a), inBefore, inAfter))
+	}
+	return rows
+}
+
+// transferStatus describes the AXFR outcome of one server
+func transferStatus(server domain.ZoneTransferServer) string {
+	switch {
+	case server.Allowed:
+		return fmt.Sprintf("ALLOWED (%d records)", server.Records)
+	case server.Refused:
+		return "refused"
+	default:
+		return "error"
+	}
+}
+
+// formatDate formats a date, or "-" when unknown
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02")
+}
+
+// joinSorted joins values in sorted order so ordering differences do not count as changes
+func joinSorted(values []string) string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ", ")
+}
+
+// unionKeys returns the sorted keys present in either map
+func unionKeys[V any](before, after map[string]V) []string {
+	var keys []string
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,141 @@
+package diff
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// findRow returns the row with label
+func findRow(t *testing.T, rows []Row, label string) Row {
+	t.Helper()
+	for _, row := range rows {
+		if row.Label == label {
+			return row
+		}
+	}
+	t.Fatalf("row %q not found in %+v", label, rows)
+	return Row{}
+}
+
+func TestResults_TypeMismatch(t *testing.T) {
+	_, err := Results(domain.DNSResult{}, domain.SSLResult{})
+	assert.Error(t, err)
+
+	_, err = Results("text", "text")
+	assert.Error(t, err)
+}
+
+func TestResults_Traceroute(t *testing.T) {
+	hop := func(number int, ip string, rtt time.Duration) domain.TraceHop {
+		return domain.TraceHop{Number: number, Host: domain.NetworkHost{IPAddress: net.ParseIP(ip)}, RTT: []time.Duration{rtt}}
+	}
+	before := []domain.TraceHop{
+		hop(1, "192.168.1.1", time.Millisecond),
+		hop(2, "10.0.0.1", 10*time.Millisecond),
+		hop(3, "203.0.113.1", 20*time.Millisecond),
+	}
+	after := []domain.TraceHop{
+		hop(1, "192.168.1.1", 1200*time.Microsecond),
+		hop(2, "10.0.0.2", 11*time.Millisecond),
+		hop(3, "203.0.113.1", 45*time.Millisecond),
+		hop(4, "203.0.113.9", 46*time.Millisecond),
+	}
+
+	rows, err := Results(before, after)
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+
+	// Small RTT jitter on the same router is not a change
+	assert.Equal(t, Unchanged, findRow(t, rows, "Hop 1").Change)
+	assert.Equal(t, "+200µs", findRow(t, rows, "Hop 1").Delta)
+
+	// A different router is a path change
+	assert.Equal(t, Changed, findRow(t, rows, "Hop 2").Change)
+
+	// A large RTT increase on the same router is a change
+	hop3 := findRow(t, rows, "Hop 3")
+	assert.Equal(t, Changed, hop3.Change)
+	assert.Equal(t, "+25ms", hop3.Delta)
+
+	assert.Equal(t, Added, findRow(t, rows, "Hop 4").Change)
+
+	changed, added, removed := Summary(rows)
+	assert.Equal(t, 2, changed)
+	assert.Equal(t, 1, added)
+	assert.Equal(t, 0, removed)
+}
+
+func TestResults_DNS(t *testing.T) {
+	before := domain.DNSResult{Records: []domain.DNSRecord{
+		{Type: domain.DNSRecordTypeA, Value: "192.0.2.1", TTL: 300},
+		{Type: domain.DNSRecordTypeA, Value: "192.0.2.2", TTL: 300},
+	}}
+	after := domain.DNSResult{Records: []domain.DNSRecord{
+		{Type: domain.DNSRecordTypeA, Value: "192.0.2.1", TTL: 120},
+		{Type: domain.DNSRecordTypeA, Value: "192.0.2.3", TTL: 300},
+	}}
+
+	rows, err := Results(before, after)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	// A TTL counting down is not a change
+	assert.Equal(t, Row{Label: "A", Before: "192.0.2.1 (TTL 300)", After: "192.0.2.1 (TTL 120)"}, rows[0])
+	assert.Equal(t, Row{Label: "A", Before: "192.0.2.2", Change: Removed}, rows[1])
+	assert.Equal(t, Row{Label: "A", After: "192.0.2.3", Change: Added}, rows[2])
+}
+
+func TestResults_Ping(t *testing.T) {
+	before := []domain.PingResult{{RTT: 10 * time.Millisecond}, {RTT: 10 * time.Millisecond}}
+	after := []domain.PingResult{{RTT: 30 * time.Millisecond}, {Error: errors.New("timeout")}}
+
+	rows, err := Results(before, after)
+	require.NoError(t, err)
+
+	assert.Equal(t, Row{Label: "Sent", Before: "2", After: "2"}, findRow(t, rows, "Sent"))
+	loss := findRow(t, rows, "Loss")
+	assert.Equal(t, Changed, loss.Change)
+	assert.Equal(t, "50.0%", loss.After)
+	avg := findRow(t, rows, "Avg RTT")
+	assert.Equal(t, Changed, avg.Change)
+	assert.Equal(t, "+20ms", avg.Delta)
+}
+
+func TestResults_Sweep(t *testing.T) {
+	before := domain.SweepResult{Hosts: []domain.SweepHost{
+		{IP: net.ParseIP("10.0.0.1"), RTT: time.Millisecond},
+		{IP: net.ParseIP("10.0.0.2"), RTT: time.Millisecond},
+	}}
+	after := domain.SweepResult{Hosts: []domain.SweepHost{
+		{IP: net.ParseIP("10.0.0.1"), RTT: time.Millisecond},
+		{IP: net.ParseIP("10.0.0.3"), RTT: time.Millisecond},
+	}}
+
+	rows, err := Results(before, after)
+	require.NoError(t, err)
+
+	assert.Equal(t, Unchanged, findRow(t, rows, "Alive").Change)
+	assert.Equal(t, Unchanged, findRow(t, rows, "10.0.0.1").Change)
+	assert.Equal(t, Removed, findRow(t, rows, "10.0.0.2").Change)
+	assert.Equal(t, Added, findRow(t, rows, "10.0.0.3").Change)
+}
+
+func TestResults_SSL(t *testing.T) {
+	expiry := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	before := domain.SSLResult{Subject: "CN=example.com", Issuer: "R3", Valid: true, Expiry: expiry, SANs: []string{"www.example.com", "example.com"}}
+	after := domain.SSLResult{Subject: "CN=example.com", Issuer: "R10", Valid: true, Expiry: expiry.AddDate(0, 3, 0), SANs: []string{"example.com", "www.example.com"}}
+
+	rows, err := Results(before, after)
+	require.NoError(t, err)
+
+	assert.Equal(t, Changed, findRow(t, rows, "Issuer").Change)
+	assert.Equal(t, "2025-09-01", findRow(t, rows, "Expires").After)
+	// SAN order does not matter
+	assert.Equal(t, Unchanged, findRow(t, rows, "SANs").Change)
+}
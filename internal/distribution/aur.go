@@ -0,0 +1,444 @@
+package distribution
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AURPublisher manages PKGBUILD creation and publishing to the Arch User
+// Repository
+type AURPublisher struct {
+	config AURConfig
+	client *http.Client
+	run    commandRunner
+	status PublishStatus
+}
+
+// AURConfig contains AUR publishing configuration
+type AURConfig struct {
+	PackageName string   `json:"package_name"` // e.g., "nettracex-bin"
+	Binary      string   `json:"binary"`       // command installed to /usr/bin
+	Maintainer  string   `json:"maintainer"`   // "Name <email>", also the commit author
+	Description string   `json:"description"`
+	Homepage    string   `json:"homepage"`
+	License     string   `json:"license"`
+	Depends     []string `json:"depends"`
+	OptDepends  []string `json:"optdepends"` // "package: reason"
+	GitURL      string   `json:"git_url"`    // AUR repository, cloned over SSH
+	SSHKey      string   `json:"ssh_key"`    // private key registered with the AUR account
+	ReleaseRepo string   `json:"release_repo"`
+	// ValidateMakepkg checks that makepkg parses the PKGBUILD and derives
+	// the same .SRCINFO from it before anything is pushed
+	ValidateMakepkg bool `json:"validate_makepkg"`
+}
+
+// AURPackage is the content of a PKGBUILD of prebuilt binaries
+type AURPackage struct {
+	Name        string
+	Version     string
+	Release     int
+	Maintainer  string
+	Description string
+	URL         string
+	License     string
+	Binary      string
+	Depends     []string
+	OptDepends  []string
+	Provides    []string
+	Conflicts   []string
+	// Sources are the downloads of each architecture, in arch order
+	Sources []AURSource
+}
+
+// AURSource is the download of one architecture
+type AURSource struct {
+	Arch     string
+	Filename string // name the download is saved as
+	URL      string
+	SHA256   string
+	Archive  bool // the binary is inside the download
+}
+
+// aurArchitectures maps Go architectures to Arch Linux's names
+var aurArchitectures = map[string]string{
+	"amd64": "x86_64",
+	"arm64": "aarch64",
+	"arm":   "armv7h",
+	"386":   "i686",
+}
+
+// NewAURPublisher creates a new AUR publisher
+func NewAURPublisher(config AURConfig) *AURPublisher {
+	if config.PackageName == "" {
+		config.PackageName = "nettracex-bin"
+	}
+	if config.Binary == "" {
+		config.Binary = strings.TrimSuffix(config.PackageName, "-bin")
+	}
+	if config.GitURL == "" {
+		config.GitURL = fmt.Sprintf("ssh://aur@aur.archlinux.org/%s.git", config.PackageName)
+	}
+
+	return &AURPublisher{
+		config: config,
+		client: &http.Client{Timeout: 5 * time.Minute},
+		run:    runCommand,
+		status: PublishStatus{
+			Name:   "aur",
+			Status: StatusIdle,
+		},
+	}
+}
+
+// GetName returns the publisher name
+func (p *AURPublisher) GetName() string {
+	return "aur"
+}
+
+// Publish publishes a release to the AUR
+func (p *AURPublisher) Publish(ctx context.Context, release Release) error {
+	p.updateStatus(StatusPublishing, "")
+
+	pkg, err := p.GeneratePackage(release)
+	if err != nil {
+		p.updateStatus(StatusError, err.Error())
+		return fmt.Errorf("failed to generate package: %w", err)
+	}
+
+	if err := p.pushPackage(ctx, pkg); err != nil {
+		p.updateStatus(StatusError, err.Error())
+		return fmt.Errorf("failed to push package: %w", err)
+	}
+
+	p.updateStatus(StatusSuccess, "")
+	return nil
+}
+
+// DryRun renders the PKGBUILD and .SRCINFO, checking them with makepkg in
+// a scratch directory when validation is enabled, and checks that the AUR
+// repository can be reached with the SSH key
+func (p *AURPublisher) DryRun(ctx context.Context, release Release) (*DryRunReport, error) {
+	report := &DryRunReport{Files: make(map[string]string)}
+	if err := p.Validate(ctx, release); err != nil {
+		return report, err
+	}
+
+	pkg, err := p.GeneratePackage(release)
+	if err != nil {
+		return report, fmt.Errorf("failed to generate package: %w", err)
+	}
+	report.Files["PKGBUILD"] = RenderPKGBUILD(pkg)
+	report.Files[".SRCINFO"] = RenderSRCINFO(pkg)
+
+	if p.config.ValidateMakepkg {
+		dir, err := os.MkdirTemp("", "aur-dry-run-*")
+		if err != nil {
+			return report, err
+		}
+		defer os.RemoveAll(dir)
+		if err := p.WritePackage(ctx, pkg, dir); err != nil {
+			return report, err
+		}
+	}
+
+	report.Actions = append(report.Actions, fmt.Sprintf("push PKGBUILD and .SRCINFO of %s-%d to %s", pkg.Version, pkg.Release, p.config.GitURL))
+	repo := gitClient{run: p.run, author: p.config.Maintainer, sshKey: p.config.SSHKey}
+	if err := repo.checkAccess(ctx, p.config.GitURL); err != nil {
+		return report, fmt.Errorf("credential check failed: %w", err)
+	}
+	return report, nil
+}
+
+// Validate validates a release for AUR publishing
+func (p *AURPublisher) Validate(ctx context.Context, release Release) error {
+	if release.Version == "" {
+		return fmt.Errorf("release version is required")
+	}
+
+	found := false
+	for filename, binary := range release.Binaries {
+		if binary.Platform != "linux" {
+			continue
+		}
+		if _, supported := aurArchitectures[binary.Architecture]; !supported {
+			continue
+		}
+		if downloadURL(p.config.ReleaseRepo, release, binary) == "" {
+			return fmt.Errorf("binary %s missing download URL", filename)
+		}
+		found = true
+	}
+
+	if !found {
+		return fmt.Errorf("no supported binary found in release (Linux required)")
+	}
+
+	return nil
+}
+
+// GetStatus returns the current status of the AUR publisher
+func (p *AURPublisher) GetStatus() PublishStatus {
+	p.status.Metadata = map[string]string{
+		"package": p.config.PackageName,
+		"git_url": p.config.GitURL,
+	}
+	return p.status
+}
+
+// updateStatus updates the publisher status
+func (p *AURPublisher) updateStatus(status StatusType, lastError string) {
+	p.status.Status = status
+	p.status.LastError = lastError
+	if status == StatusSuccess {
+		p.status.LastPublish = time.Now()
+		p.status.PublishCount++
+	} else if status == StatusError {
+		p.status.ErrorCount++
+	}
+}
+
+// GeneratePackage creates the package of the Linux binaries of a release
+func (p *AURPublisher) GeneratePackage(release Release) (*AURPackage, error) {
+	// pkgver may not contain hyphens
+	version := strings.ReplaceAll(strings.TrimPrefix(release.Version, "v"), "-", "_")
+	pkg := &AURPackage{
+		Name:        p.config.PackageName,
+		Version:     version,
+		Release:     1,
+		Maintainer:  p.config.Maintainer,
+		Description: p.config.Description,
+		URL:         p.config.Homepage,
+		License:     p.config.License,
+		Binary:      p.config.Binary,
+		Depends:     p.config.Depends,
+		OptDepends:  p.config.OptDepends,
+	}
+	if pkg.Name != pkg.Binary {
+		pkg.Provides = []string{pkg.Binary}
+		pkg.Conflicts = []string{pkg.Binary}
+	}
+
+	for _, binary := range release.Binaries {
+		if binary.Platform != "linux" {
+			continue
+		}
+		arch, supported := aurArchitectures[binary.Architecture]
+		if !supported {
+			continue
+		}
+		url := downloadURL(p.config.ReleaseRepo, release, binary)
+
+		// Calculate SHA256 if not provided
+		hash := binary.Checksum
+		if hash == "" {
+			sum, err := downloadSHA256(p.client, url)
+			if err != nil {
+				return nil, fmt.Errorf("failed to calculate SHA256 for %s: %w", binary.Filename, err)
+			}
+			hash = sum
+		}
+
+		source := AURSource{
+			Arch:     arch,
+			Filename: fmt.Sprintf("%s-%s-%s", pkg.Binary, version, arch),
+			URL:      url,
+			SHA256:   hash,
+			Archive:  strings.HasSuffix(binary.Filename, ".tar.gz"),
+		}
+		if source.Archive {
+			source.Filename += ".tar.gz"
+		}
+		pkg.Sources = append(pkg.Sources, source)
+	}
+
+	if len(pkg.Sources) == 0 {
+		return nil, fmt.Errorf("no supported binaries found (Linux required)")
+	}
+	sort.Slice(pkg.Sources, func(i, j int) bool { return pkg.Sources[i].Arch < pkg.Sources[j].Arch })
+
+	if err := ValidateAURPackage(pkg); err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+// ValidateAURPackage validates the fields the AUR requires
+func ValidateAURPackage(pkg *AURPackage) error {
+	if pkg.Name == "" {
+		return fmt.Errorf("package name is required")
+	}
+	if pkg.Version == "" {
+		return fmt.Errorf("package version is required")
+	}
+	if strings.ContainsAny(pkg.Version, ":/ ") {
+		return fmt.Errorf("invalid package version %q", pkg.Version)
+	}
+	if pkg.Description == "" {
+		return fmt.Errorf("package description is required")
+	}
+	if pkg.URL == "" {
+		return fmt.Errorf("package URL is required")
+	}
+	if pkg.License == "" {
+		return fmt.Errorf("package license is required")
+	}
+	for _, source := range pkg.Sources {
+		if len(source.SHA256) != 64 {
+			return fmt.Errorf("invalid %s SHA256 hash length", source.Arch)
+		}
+	}
+	return nil
+}
+
+// shellQuote quotes a value for a PKGBUILD
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// shellArray renders a PKGBUILD array
+func shellArray(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = shellQuote(value)
+	}
+	return "(" + strings.Join(quoted, " ") + ")"
+}
+
+// RenderPKGBUILD renders the PKGBUILD of a package
+func RenderPKGBUILD(pkg *AURPackage) string {
+	var b strings.Builder
+	if pkg.Maintainer != "" {
+		fmt.Fprintf(&b, "# Maintainer: %s\n", pkg.Maintainer)
+	}
+	fmt.Fprintf(&b, "pkgname=%s\n", pkg.Name)
+	fmt.Fprintf(&b, "pkgver=%s\n", pkg.Version)
+	fmt.Fprintf(&b, "pkgrel=%d\n", pkg.Release)
+	fmt.Fprintf(&b, "pkgdesc=%s\n", shellQuote(pkg.Description))
+
+	arches := make([]string, len(pkg.Sources))
+	for i, source := range pkg.Sources {
+		arches[i] = source.Arch
+	}
+	fmt.Fprintf(&b, "arch=%s\n", shellArray(arches))
+	fmt.Fprintf(&b, "url=%s\n", shellQuote(pkg.URL))
+	fmt.Fprintf(&b, "license=%s\n", shellArray([]string{pkg.License}))
+	if len(pkg.Depends) > 0 {
+		fmt.Fprintf(&b, "depends=%s\n", shellArray(pkg.Depends))
+	}
+	if len(pkg.OptDepends) > 0 {
+		fmt.Fprintf(&b, "optdepends=%s\n", shellArray(pkg.OptDepends))
+	}
+	if len(pkg.Provides) > 0 {
+		fmt.Fprintf(&b, "provides=%s\n", shellArray(pkg.Provides))
+	}
+	if len(pkg.Conflicts) > 0 {
+		fmt.Fprintf(&b, "conflicts=%s\n", shellArray(pkg.Conflicts))
+	}
+	for _, source := range pkg.Sources {
+		fmt.Fprintf(&b, "source_%s=(\"%s::%s\")\n", source.Arch, source.Filename, source.URL)
+		fmt.Fprintf(&b, "sha256sums_%s=('%s')\n", source.Arch, source.SHA256)
+	}
+
+	b.WriteString("\npackage() {\n")
+	b.WriteString("  local binary\n")
+	b.WriteString("  case \"$CARCH\" in\n")
+	for _, source := range pkg.Sources {
+		binary := source.Filename
+		if source.Archive {
+			binary = pkg.Binary
+		}
+		fmt.Fprintf(&b, "    %s) binary=%s ;;\n", source.Arch, shellQuote(binary))
+	}
+	b.WriteString("  esac\n")
+	fmt.Fprintf(&b, "  install -Dm755 \"$srcdir/$binary\" \"$pkgdir/usr/bin/%s\"\n", pkg.Binary)
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// RenderSRCINFO renders the .SRCINFO of a package the way makepkg
+// --printsrcinfo does
+func RenderSRCINFO(pkg *AURPackage) string {
+	var b strings.Builder
+	attr := func(key string, values ...string) {
+		for _, value := range values {
+			fmt.Fprintf(&b, "\t%s = %s\n", key, value)
+		}
+	}
+
+	fmt.Fprintf(&b, "pkgbase = %s\n", pkg.Name)
+	attr("pkgdesc", pkg.Description)
+	attr("pkgver", pkg.Version)
+	attr("pkgrel", fmt.Sprint(pkg.Release))
+	attr("url", pkg.URL)
+	for _, source := range pkg.Sources {
+		attr("arch", source.Arch)
+	}
+	attr("license", pkg.License)
+	attr("depends", pkg.Depends...)
+	attr("optdepends", pkg.OptDepends...)
+	attr("provides", pkg.Provides...)
+	attr("conflicts", pkg.Conflicts...)
+	for _, source := range pkg.Sources {
+		attr("source_"+source.Arch, source.Filename+"::"+source.URL)
+		attr("sha256sums_"+source.Arch, source.SHA256)
+	}
+	fmt.Fprintf(&b, "\npkgname = %s\n", pkg.Name)
+	return b.String()
+}
+
+// pushPackage commits the PKGBUILD and .SRCINFO to the AUR repository
+func (p *AURPublisher) pushPackage(ctx context.Context, pkg *AURPackage) error {
+	repo := gitClient{run: p.run, author: p.config.Maintainer, sshKey: p.config.SSHKey}
+	dir, err := repo.clone(ctx, p.config.GitURL, "master")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := p.WritePackage(ctx, pkg, dir); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Update to %s-%d", pkg.Version, pkg.Release)
+	return repo.commitAndPush(ctx, dir, "master", message, "PKGBUILD", ".SRCINFO")
+}
+
+// WritePackage writes the PKGBUILD and .SRCINFO of a package to dir,
+// checking them with makepkg when validation is enabled
+func (p *AURPublisher) WritePackage(ctx context.Context, pkg *AURPackage, dir string) error {
+	pkgbuild := filepath.Join(dir, "PKGBUILD")
+	srcinfo := RenderSRCINFO(pkg)
+	if err := os.WriteFile(pkgbuild, []byte(RenderPKGBUILD(pkg)), 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".SRCINFO"), []byte(srcinfo), 0644); err != nil {
+		return err
+	}
+
+	if p.config.ValidateMakepkg {
+		return p.validateMakepkg(ctx, pkgbuild, srcinfo)
+	}
+	return nil
+}
+
+// validateMakepkg checks that makepkg parses the PKGBUILD into the same
+// .SRCINFO as the one written
+func (p *AURPublisher) validateMakepkg(ctx context.Context, pkgbuild, srcinfo string) error {
+	output, err := p.run(ctx, "makepkg", "--printsrcinfo", "-p", pkgbuild)
+	if err != nil {
+		if message := strings.TrimSpace(string(output)); message != "" {
+			return fmt.Errorf("makepkg rejected the PKGBUILD: %w: %s", err, message)
+		}
+		return fmt.Errorf("makepkg rejected the PKGBUILD: %w", err)
+	}
+	if strings.TrimSpace(string(output)) != strings.TrimSpace(srcinfo) {
+		return fmt.Errorf("makepkg derives a different .SRCINFO:\n%s", output)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package distribution
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func aurRelease() Release {
+	return testRelease("v1.2.3-rc.1", linuxPlatforms...)
+}
+
+func aurConfig() AURConfig {
+	return AURConfig{
+		Maintainer:  "NetTraceX <packages@nettracex.dev>",
+		Description: testDescription,
+		Homepage:    testHomepage,
+		License:     "MIT",
+		OptDepends:  []string{"traceroute: system traceroute fallback"},
+		ReleaseRepo: testReleaseRepo,
+	}
+}
+
+func TestAURPublisher_GeneratePackage(t *testing.T) {
+	publisher := NewAURPublisher(aurConfig())
+	require.NoError(t, publisher.Validate(context.Background(), aurRelease()))
+
+	pkg, err := publisher.GeneratePackage(aurRelease())
+	require.NoError(t, err)
+	assert.Equal(t, "nettracex-bin", pkg.Name)
+	assert.Equal(t, "1.2.3_rc.1", pkg.Version, "pkgver may not contain hyphens")
+	assert.Equal(t, []string{"nettracex"}, pkg.Provides)
+	require.Len(t, pkg.Sources, 2, "only Linux binaries are packaged")
+	assert.Equal(t, AURSource{
+		Arch:     "x86_64",
+		Filename: "nettracex-1.2.3_rc.1-x86_64",
+		URL:      "https://github.com/nettracex/nettracex-tui/releases/download/v1.2.3-rc.1/nettracex-linux-amd64",
+		SHA256:   strings.Repeat("a", 64),
+	}, pkg.Sources[1])
+	assert.True(t, pkg.Sources[0].Archive)
+
+	pkgbuild := RenderPKGBUILD(pkg)
+	assert.Contains(t, pkgbuild, "# Maintainer: NetTraceX <packages@nettracex.dev>\n")
+	assert.Contains(t, pkgbuild, "arch=('aarch64' 'x86_64')\n")
+	assert.Contains(t, pkgbuild, "optdepends=('traceroute: system traceroute fallback')\n")
+	assert.Contains(t, pkgbuild, `source_aarch64=("nettracex-1.2.3_rc.1-aarch64.tar.gz::https://downloads.example.com/nettracex_1.2.3_Linux_arm64.tar.gz")`)
+	assert.Contains(t, pkgbuild, "    aarch64) binary='nettracex' ;;\n")
+	assert.Contains(t, pkgbuild, "    x86_64) binary='nettracex-1.2.3_rc.1-x86_64' ;;\n")
+	assert.Contains(t, pkgbuild, `install -Dm755 "$srcdir/$binary" "$pkgdir/usr/bin/nettracex"`)
+
+	if bash, err := exec.LookPath("bash"); err == nil {
+		output, err := exec.Command(bash, "-n", "-c", pkgbuild).CombinedOutput()
+		assert.NoError(t, err, string(output))
+	}
+
+	srcinfo := RenderSRCINFO(pkg)
+	assert.True(t, strings.HasPrefix(srcinfo, "pkgbase = nettracex-bin\n\tpkgdesc = Network diagnostic toolkit with beautiful TUI\n\tpkgver = 1.2.3_rc.1\n\tpkgrel = 1\n"))
+	assert.Contains(t, srcinfo, "\tarch = aarch64\n\tarch = x86_64\n\tlicense = MIT\n")
+	assert.Contains(t, srcinfo, "\tsource_x86_64 = nettracex-1.2.3_rc.1-x86_64::https://github.com/nettracex/nettracex-tui/releases/download/v1.2.3-rc.1/nettracex-linux-amd64\n\tsha256sums_x86_64 = "+strings.Repeat("a", 64)+"\n")
+	assert.True(t, strings.HasSuffix(srcinfo, "\n\npkgname = nettracex-bin\n"))
+
+	// A release without Linux binaries has nothing to publish
+	release := aurRelease()
+	for name, binary := range release.Binaries {
+		if binary.Platform == "linux" {
+			delete(release.Binaries, name)
+		}
+	}
+	assert.Error(t, publisher.Validate(context.Background(), release))
+	_, err = publisher.GeneratePackage(release)
+	assert.Error(t, err)
+}
+
+func TestValidateAURPackage(t *testing.T) {
+	pkg, err := NewAURPublisher(aurConfig()).GeneratePackage(aurRelease())
+	require.NoError(t, err)
+
+	pkg.License = ""
+	assert.ErrorContains(t, ValidateAURPackage(pkg), "license")
+
+	pkg.License = "MIT"
+	pkg.Sources[0].SHA256 = "abc"
+	assert.ErrorContains(t, ValidateAURPackage(pkg), "aarch64 SHA256")
+}
+
+func TestAURPublisher_Publish(t *testing.T) {
+	runner := &recordingRunner{}
+	config := aurConfig()
+	config.SSHKey = "/keys/aur"
+	config.ValidateMakepkg = true
+	publisher := NewAURPublisher(config)
+
+	var srcinfo string
+	publisher.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		runner.run(ctx, name, args...)
+		switch {
+		case name == "makepkg":
+			data, err := os.ReadFile(filepath.Join(filepath.Dir(args[2]), ".SRCINFO"))
+			require.NoError(t, err)
+			srcinfo = string(data)
+			return data, nil
+		case args[len(args)-1] == "--porcelain":
+			return []byte("A  PKGBUILD\n"), nil
+		}
+		return nil, runner.err
+	}
+	require.NoError(t, publisher.Publish(context.Background(), aurRelease()))
+	assert.Contains(t, srcinfo, "pkgver = 1.2.3_rc.1")
+
+	ssh := "core.sshCommand=ssh -i /keys/aur -o IdentitiesOnly=yes -o StrictHostKeyChecking=accept-new"
+	require.Len(t, runner.commands, 8)
+	assert.True(t, strings.HasPrefix(runner.commands[0], "git -c user.name=NetTraceX -c user.email=packages@nettracex.dev -c "+ssh+" clone ssh://aur@aur.archlinux.org/nettracex-bin.git "))
+	assert.True(t, strings.HasPrefix(runner.commands[3], "makepkg --printsrcinfo -p "))
+	assert.True(t, strings.HasSuffix(runner.commands[4], ssh+" add PKGBUILD .SRCINFO"))
+	assert.True(t, strings.HasSuffix(runner.commands[6], ssh+" commit -m Update to 1.2.3_rc.1-1"))
+	assert.True(t, strings.HasSuffix(runner.commands[7], ssh+" push origin HEAD:master"))
+	assert.Equal(t, StatusSuccess, publisher.GetStatus().Status)
+
+	// A PKGBUILD makepkg derives another .SRCINFO from is not pushed
+	runner.commands = nil
+	publisher.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		runner.run(ctx, name, args...)
+		if name == "makepkg" {
+			return []byte("pkgbase = nettracex-bin\n"), nil
+		}
+		return nil, nil
+	}
+	err := publisher.Publish(context.Background(), aurRelease())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "different .SRCINFO")
+	assert.Len(t, runner.commands, 4)
+	assert.Equal(t, StatusError, publisher.GetStatus().Status)
+
+	// A refused push is reported
+	publisher.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if args[len(args)-1] == "HEAD:master" {
+			return []byte("ERROR: Permission denied (publickey)\n"), errors.New("exit status 128")
+		}
+		if name == "makepkg" {
+			return []byte(srcinfo), nil
+		}
+		if args[len(args)-1] == "--porcelain" {
+			return []byte("M  PKGBUILD\n"), nil
+		}
+		return nil, nil
+	}
+	err = publisher.Publish(context.Background(), aurRelease())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Permission denied")
+}
+
+func TestAURPublisher_PublishToRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	// An empty bare repository stands in for a new AUR package
+	remote := filepath.Join(t.TempDir(), "nettracex-bin.git")
+	output, err := exec.Command("git", "init", "--bare", remote).CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	config := aurConfig()
+	config.GitURL = remote
+	publisher := NewAURPublisher(config)
+	require.NoError(t, publisher.Publish(context.Background(), aurRelease()))
+
+	// Publishing the same release again has nothing to commit
+	require.NoError(t, publisher.Publish(context.Background(), aurRelease()))
+
+	output, err = exec.Command("git", "--git-dir", remote, "log", "--format=%an <%ae> %s", "master").CombinedOutput()
+	require.NoError(t, err, string(output))
+	assert.Equal(t, "NetTraceX <packages@nettracex.dev> Update to 1.2.3_rc.1-1\n", string(output))
+
+	output, err = exec.Command("git", "--git-dir", remote, "show", "master:.SRCINFO").CombinedOutput()
+	require.NoError(t, err, string(output))
+	assert.Contains(t, string(output), "pkgver = 1.2.3_rc.1")
+}
@@ -0,0 +1,69 @@
+package distribution
+
+import (
+	"log"
+
+	"github.com/nettracex/nettracex-tui/internal/update"
+)
+
+// ChannelPublisher is implemented by publishers that only ship some release
+// channels unless configured otherwise, such as package managers without a
+// notion of prereleases
+type ChannelPublisher interface {
+	DefaultChannels() []update.Channel
+}
+
+// ChannelOf returns the release channel of a release: the one of its
+// metadata, or else the one of its version. A release marked a prerelease
+// without a prerelease version is a beta.
+func ChannelOf(release Release) update.Channel {
+	if release.Metadata.Channel != "" {
+		return release.Metadata.Channel
+	}
+	channel := update.VersionChannel(release.Version)
+	if channel == update.Stable && release.Metadata.IsPrerelease {
+		return update.Beta
+	}
+	return channel
+}
+
+// IsPrerelease reports whether a release is of a channel other than stable
+func IsPrerelease(release Release) bool {
+	return ChannelOf(release) != update.Stable
+}
+
+// publishesChannel reports whether a publisher ships releases of a channel:
+// the channels of its configuration, or else its default ones, or else all
+func (dc *DistributionCoordinator) publishesChannel(publisher Publisher, channel update.Channel) bool {
+	dc.mu.RLock()
+	channels := dc.config.Publishers[publisher.GetName()].Channels
+	dc.mu.RUnlock()
+	if channels == nil {
+		channelPublisher, ok := publisher.(ChannelPublisher)
+		if !ok {
+			return true
+		}
+		channels = channelPublisher.DefaultChannels()
+	}
+	for _, c := range channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// channelPublishers returns the publishers that ship the channel of a
+// release, logging the ones skipped
+func (dc *DistributionCoordinator) channelPublishers(publishers []Publisher, release Release) []Publisher {
+	channel := ChannelOf(release)
+	var shipping []Publisher
+	for _, publisher := range publishers {
+		if dc.publishesChannel(publisher, channel) {
+			shipping = append(shipping, publisher)
+			continue
+		}
+		log.Printf("Publisher %s does not publish %s releases, skipping it", publisher.GetName(), channel)
+	}
+	return shipping
+}
@@ -0,0 +1,163 @@
+package distribution
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/update"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelOf(t *testing.T) {
+	tests := []struct {
+		release Release
+		want    update.Channel
+	}{
+		{Release{Version: "v1.3.0"}, update.Stable},
+		{Release{Version: "v1.3.0-rc.1"}, update.RC},
+		{Release{Version: "v1.3.0-beta.2+build.7"}, update.Beta},
+		{Release{Version: "v1.3.0", Metadata: ReleaseMetadata{IsPrerelease: true}}, update.Beta},
+		{Release{Version: "v1.3.0-rc.1", Metadata: ReleaseMetadata{Channel: update.Alpha}}, update.Alpha},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ChannelOf(tt.release), tt.release.Version)
+		assert.Equal(t, tt.want != update.Stable, IsPrerelease(tt.release), tt.release.Version)
+	}
+}
+
+// stableStagePublisher is a stage publisher shipping stable releases only
+type stableStagePublisher struct {
+	stagePublisher
+}
+
+func (p *stableStagePublisher) DefaultChannels() []update.Channel {
+	return []update.Channel{update.Stable}
+}
+
+func TestDistribute_Channels(t *testing.T) {
+	log := &publishLog{}
+	coordinator := NewDistributionCoordinator(&DistributionConfig{Publishers: map[string]PublisherConfig{
+		"github":   {Enabled: true, Priority: 1},
+		"scoop":    {Enabled: true, Priority: 2},
+		"homebrew": {Enabled: true, Priority: 3, Channels: []update.Channel{update.Stable, update.RC}},
+	}})
+	require.NoError(t, coordinator.RegisterPublisher(&stagePublisher{name: "github", log: log}))
+	require.NoError(t, coordinator.RegisterPublisher(&stableStagePublisher{stagePublisher{name: "scoop", log: log}}))
+	require.NoError(t, coordinator.RegisterPublisher(&stagePublisher{name: "homebrew", log: log}))
+
+	require.NoError(t, coordinator.Distribute(context.Background(), Release{Version: "v1.3.0-beta.1"}))
+	assert.Equal(t, []string{"start github", "end github"}, log.events)
+
+	log.events = nil
+	require.NoError(t, coordinator.Distribute(context.Background(), Release{Version: "v1.3.0-rc.1"}))
+	assert.Equal(t, -1, log.index("start scoop"), "scoop skips prereleases")
+	assert.NotEqual(t, -1, log.index("start homebrew"), "the configured channels replace the defaults")
+
+	log.events = nil
+	require.NoError(t, coordinator.Distribute(context.Background(), Release{Version: "v1.3.0"}))
+	assert.Len(t, log.events, 6)
+}
+
+func TestHomebrewPublisher_PrereleaseFormula(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	publisher, err := NewHomebrewPublisher(HomebrewConfig{
+		FormulaName: "nettracex",
+		Description: "Network diagnostic toolkit",
+		Homepage:    "https://github.com/nettracex/nettracex-tui",
+		License:     "MIT",
+		CustomTap:   true,
+		HeadURL:     "https://github.com/nettracex/nettracex-tui.git",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, update.Channels, publisher.DefaultChannels())
+
+	release := Release{
+		Version: "1.3.0-beta.1",
+		Binaries: map[string]Binary{
+			"darwin-arm64": {Platform: "darwin", Architecture: "arm64", Filename: "nettracex", DownloadURL: server.URL + "/nettracex", Checksum: strings.Repeat("a", 64)},
+		},
+	}
+	formula, err := publisher.GenerateFormula(release)
+	require.NoError(t, err)
+	assert.Equal(t, "nettracex@beta", formula.Name)
+	assert.Equal(t, "NettracexATBeta", formula.Class)
+	assert.Equal(t, []string{"nettracex"}, formula.ConflictsWith)
+
+	content, err := publisher.RenderFormula(formula)
+	require.NoError(t, err)
+	assert.Contains(t, content, "class NettracexATBeta < Formula")
+	assert.Contains(t, content, `conflicts_with "nettracex", because: "both install the nettracex command"`)
+	assert.Contains(t, content, `url "https://github.com/nettracex/nettracex-tui.git", branch: "main"`)
+	assert.Contains(t, content, "if build.head?")
+	assert.Contains(t, content, `bin.install "nettracex" => "nettracex"`)
+
+	report, err := publisher.DryRun(context.Background(), release)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nettracex@beta.rb"}, report.FileNames())
+
+	// Stable releases keep the plain formula
+	release.Version = "1.3.0"
+	formula, err = publisher.GenerateFormula(release)
+	require.NoError(t, err)
+	assert.Equal(t, "nettracex", formula.Name)
+	assert.Equal(t, "Nettracex", formula.Class)
+	assert.Empty(t, formula.ConflictsWith)
+
+	core, err := NewHomebrewPublisher(HomebrewConfig{FormulaName: "nettracex"})
+	require.NoError(t, err)
+	assert.Equal(t, []update.Channel{update.Stable}, core.DefaultChannels())
+}
+
+func TestS3Publisher_ChannelManifests(t *testing.T) {
+	storage, server := newS3Server(t)
+	publisher, err := NewS3Publisher(S3Config{
+		Endpoint:  server.URL,
+		Bucket:    "downloads",
+		Prefix:    "nettracex",
+		AccessKey: "minio",
+		SecretKey: "minio-secret",
+		PathStyle: true,
+		Latest:    true,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, publisher.Publish(context.Background(), s3Release(t, "v1.2.3")))
+	for _, name := range []string{"latest.json", "latest-rc.json", "latest-beta.json", "latest-alpha.json"} {
+		assert.Equal(t, storage.objects["nettracex/v1.2.3/release.json"], storage.objects["nettracex/"+name], name)
+	}
+
+	// A beta moves the beta and alpha manifests only
+	require.NoError(t, publisher.Publish(context.Background(), s3Release(t, "v1.3.0-beta.1")))
+	beta := storage.objects["nettracex/v1.3.0-beta.1/release.json"]
+	assert.Equal(t, storage.objects["nettracex/v1.2.3/release.json"], storage.objects["nettracex/latest.json"])
+	assert.Equal(t, storage.objects["nettracex/v1.2.3/release.json"], storage.objects["nettracex/latest-rc.json"])
+	assert.Equal(t, beta, storage.objects["nettracex/latest-beta.json"])
+	assert.Equal(t, beta, storage.objects["nettracex/latest-alpha.json"])
+
+	var manifest S3Manifest
+	require.NoError(t, json.Unmarshal(beta, &manifest))
+	assert.True(t, manifest.Prerelease)
+
+	// An updater following the beta channel reads its manifest
+	updater := update.New()
+	updater.ManifestURL = publisher.GetStatus().Metadata["latest"]
+	updater.Channel = update.Beta
+	updater.Client.Timeout = 5 * time.Second
+	latest, err := updater.Latest(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "v1.3.0-beta.1", latest.Tag)
+
+	report, err := publisher.DryRun(context.Background(), s3Release(t, "v1.3.0-rc.1"))
+	require.NoError(t, err)
+	assert.Contains(t, report.Actions, "point "+server.URL+"/downloads/nettracex/latest-rc.json at v1.3.0-rc.1")
+	assert.Contains(t, report.Actions, "point "+server.URL+"/downloads/nettracex/latest-beta.json at v1.3.0-rc.1")
+	assert.NotContains(t, report.Actions, "point "+server.URL+"/downloads/nettracex/latest.json at v1.3.0-rc.1")
+}
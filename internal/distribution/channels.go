@@ -0,0 +1,382 @@
+package distribution
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewNotificationChannel creates the channel of a type: "log", "console",
+// "slack", "discord" or "email", named name
+func NewNotificationChannel(name string, config NotificationChannelConfig) (NotificationChannel, error) {
+	channelType := config.Type
+	if channelType == "" {
+		channelType = name
+	}
+
+	switch channelType {
+	case "log":
+		return &LogNotificationChannel{name: name, enabled: config.Enabled, logger: log.Default()}, nil
+	case "console":
+		colored := true
+		if value, ok := config.Config["colored"].(bool); ok {
+			colored = value
+		}
+		return &ConsoleNotificationChannel{name: name, enabled: config.Enabled, colored: colored}, nil
+	case "slack":
+		return NewSlackNotificationChannel(name, config)
+	case "discord":
+		return NewDiscordNotificationChannel(name, config)
+	case "email":
+		return NewEmailNotificationChannel(name, config)
+	}
+	return nil, fmt.Errorf("unknown notification channel type %q", channelType)
+}
+
+// channelString reads a string setting of a channel
+func channelString(config map[string]interface{}, key string) string {
+	value, _ := config[key].(string)
+	return value
+}
+
+// channelStrings reads a list setting of a channel, given as a list or a
+// comma-separated string
+func channelStrings(config map[string]interface{}, key string) []string {
+	var values []string
+	switch value := config[key].(type) {
+	case []string:
+		values = value
+	case []interface{}:
+		for _, item := range value {
+			if str, ok := item.(string); ok {
+				values = append(values, str)
+			}
+		}
+	case string:
+		values = strings.Split(value, ",")
+	}
+
+	var trimmed []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			trimmed = append(trimmed, value)
+		}
+	}
+	return trimmed
+}
+
+// channelInt reads a number setting of a channel, given as a number or a
+// string
+func channelInt(config map[string]interface{}, key string, defaultValue int) int {
+	switch value := config[key].(type) {
+	case int:
+		return value
+	case float64:
+		return int(value)
+	case string:
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+// notificationFields returns the facts of a notification that chat
+// messages list
+func notificationFields(notification Notification) [][2]string {
+	var fields [][2]string
+	if notification.Publisher != "" {
+		fields = append(fields, [2]string{"Publisher", notification.Publisher})
+	}
+	if notification.Release.Version != "" {
+		fields = append(fields, [2]string{"Version", notification.Release.Version})
+	}
+	if notification.Error != nil {
+		fields = append(fields, [2]string{"Error", notification.Error.Error()})
+	}
+	return fields
+}
+
+// postJSON posts payload to a webhook, failing on any status but 2xx
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// SlackNotificationChannel posts notifications to a Slack incoming webhook
+type SlackNotificationChannel struct {
+	name       string
+	enabled    bool
+	webhookURL string
+	channel    string // overrides the channel of the webhook when set
+	username   string
+	client     *http.Client
+}
+
+// slackColors are the attachment colors of notification types
+var slackColors = map[NotificationType]string{
+	NotificationTypeSuccess:  "good",
+	NotificationTypeFailure:  "danger",
+	NotificationTypeWarning:  "warning",
+	NotificationTypeProgress: "#439FE0",
+}
+
+// NewSlackNotificationChannel creates a Slack channel from its webhook_url,
+// channel and username settings
+func NewSlackNotificationChannel(name string, config NotificationChannelConfig) (*SlackNotificationChannel, error) {
+	webhookURL := channelString(config.Config, "webhook_url")
+	if webhookURL == "" {
+		return nil, fmt.Errorf("slack channel %s needs a webhook_url", name)
+	}
+	return &SlackNotificationChannel{
+		name:       name,
+		enabled:    config.Enabled,
+		webhookURL: webhookURL,
+		channel:    channelString(config.Config, "channel"),
+		username:   channelString(config.Config, "username"),
+		client:     &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Send posts a notification as a message with a colored attachment
+func (snc *SlackNotificationChannel) Send(ctx context.Context, notification Notification) error {
+	type field struct {
+		Title string `json:"title"`
+		Value string `json:"value"`
+		Short bool   `json:"short"`
+	}
+	attachment := map[string]interface{}{
+		"color":    slackColors[notification.Type],
+		"title":    notification.Title,
+		"text":     notification.Message,
+		"fallback": notification.Title + ": " + notification.Message,
+	}
+	if !notification.Timestamp.IsZero() {
+		attachment["ts"] = notification.Timestamp.Unix()
+	}
+	var fields []field
+	for _, f := range notificationFields(notification) {
+		fields = append(fields, field{Title: f[0], Value: f[1], Short: f[0] != "Error"})
+	}
+	if len(fields) > 0 {
+		attachment["fields"] = fields
+	}
+
+	payload := map[string]interface{}{
+		"text":        notification.Title,
+		"attachments": []interface{}{attachment},
+	}
+	if snc.channel != "" {
+		payload["channel"] = snc.channel
+	}
+	if snc.username != "" {
+		payload["username"] = snc.username
+	}
+	return postJSON(ctx, snc.client, snc.webhookURL, payload)
+}
+
+// GetName returns the channel name
+func (snc *SlackNotificationChannel) GetName() string {
+	return snc.name
+}
+
+// IsEnabled returns whether the channel is enabled
+func (snc *SlackNotificationChannel) IsEnabled() bool {
+	return snc.enabled
+}
+
+// DiscordNotificationChannel posts notifications to a Discord webhook
+type DiscordNotificationChannel struct {
+	name       string
+	enabled    bool
+	webhookURL string
+	username   string
+	client     *http.Client
+}
+
+// discordColors are the embed colors of notification types
+var discordColors = map[NotificationType]int{
+	NotificationTypeSuccess:  0x2EB886,
+	NotificationTypeFailure:  0xD50200,
+	NotificationTypeWarning:  0xDAA038,
+	NotificationTypeProgress: 0x439FE0,
+}
+
+// NewDiscordNotificationChannel creates a Discord channel from its
+// webhook_url and username settings
+func NewDiscordNotificationChannel(name string, config NotificationChannelConfig) (*DiscordNotificationChannel, error) {
+	webhookURL := channelString(config.Config, "webhook_url")
+	if webhookURL == "" {
+		return nil, fmt.Errorf("discord channel %s needs a webhook_url", name)
+	}
+	return &DiscordNotificationChannel{
+		name:       name,
+		enabled:    config.Enabled,
+		webhookURL: webhookURL,
+		username:   channelString(config.Config, "username"),
+		client:     &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Send posts a notification as an embed
+func (dnc *DiscordNotificationChannel) Send(ctx context.Context, notification Notification) error {
+	type field struct {
+		Name   string `json:"name"`
+		Value  string `json:"value"`
+		Inline bool   `json:"inline"`
+	}
+	embed := map[string]interface{}{
+		"title":       notification.Title,
+		"description": notification.Message,
+		"color":       discordColors[notification.Type],
+	}
+	if !notification.Timestamp.IsZero() {
+		embed["timestamp"] = notification.Timestamp.UTC().Format(time.RFC3339)
+	}
+	var fields []field
+	for _, f := range notificationFields(notification) {
+		fields = append(fields, field{Name: f[0], Value: f[1], Inline: f[0] != "Error"})
+	}
+	if len(fields) > 0 {
+		embed["fields"] = fields
+	}
+
+	payload := map[string]interface{}{
+		"embeds": []interface{}{embed},
+	}
+	if dnc.username != "" {
+		payload["username"] = dnc.username
+	}
+	return postJSON(ctx, dnc.client, dnc.webhookURL, payload)
+}
+
+// GetName returns the channel name
+func (dnc *DiscordNotificationChannel) GetName() string {
+	return dnc.name
+}
+
+// IsEnabled returns whether the channel is enabled
+func (dnc *DiscordNotificationChannel) IsEnabled() bool {
+	return dnc.enabled
+}
+
+// EmailNotificationChannel mails notifications over SMTP
+type EmailNotificationChannel struct {
+	name     string
+	enabled  bool
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+	to       []string
+	sendMail func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailNotificationChannel creates an email channel from its host, port
+// (587 by default), username, password, from and to settings
+func NewEmailNotificationChannel(name string, config NotificationChannelConfig) (*EmailNotificationChannel, error) {
+	channel := &EmailNotificationChannel{
+		name:     name,
+		enabled:  config.Enabled,
+		host:     channelString(config.Config, "host"),
+		port:     channelInt(config.Config, "port", 587),
+		username: channelString(config.Config, "username"),
+		password: channelString(config.Config, "password"),
+		from:     channelString(config.Config, "from"),
+		to:       channelStrings(config.Config, "to"),
+		sendMail: smtp.SendMail,
+	}
+	if channel.host == "" {
+		return nil, fmt.Errorf("email channel %s needs a host", name)
+	}
+	if channel.from == "" || len(channel.to) == 0 {
+		return nil, fmt.Errorf("email channel %s needs from and to addresses", name)
+	}
+	return channel, nil
+}
+
+// Send mails a notification as plain text, authenticating when a username
+// is set. net/smtp upgrades the connection with STARTTLS when the server
+// offers it.
+func (enc *EmailNotificationChannel) Send(ctx context.Context, notification Notification) error {
+	var auth smtp.Auth
+	if enc.username != "" {
+		auth = smtp.PlainAuth("", enc.username, enc.password, enc.host)
+	}
+	addr := net.JoinHostPort(enc.host, strconv.Itoa(enc.port))
+	return enc.sendMail(addr, auth, enc.from, enc.to, enc.message(notification))
+}
+
+// message renders the mail of a notification
+func (enc *EmailNotificationChannel) message(notification Notification) []byte {
+	timestamp := notification.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	subject := "[NetTraceX] " + notification.Title
+	if notification.Publisher != "" && notification.Release.Version != "" {
+		subject += fmt.Sprintf(": %s %s", notification.Publisher, notification.Release.Version)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", enc.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(enc.to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", headerValue(subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", timestamp.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(notification.Message, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	if fields := notificationFields(notification); len(fields) > 0 {
+		b.WriteString("\r\n")
+		for _, f := range fields {
+			fmt.Fprintf(&b, "%s: %s\r\n", f[0], strings.ReplaceAll(f[1], "\n", "\r\n"))
+		}
+	}
+	return []byte(b.String())
+}
+
+// headerValue keeps a value on one header line
+func headerValue(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// GetName returns the channel name
+func (enc *EmailNotificationChannel) GetName() string {
+	return enc.name
+}
+
+// IsEnabled returns whether the channel is enabled
+func (enc *EmailNotificationChannel) IsEnabled() bool {
+	return enc.enabled
+}
@@ -0,0 +1,162 @@
+package distribution
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func channelNotification() Notification {
+	return Notification{
+		Type:      NotificationTypeFailure,
+		Title:     "Release Publishing Failed",
+		Message:   "Failed to publish release v1.2.3 to homebrew: tap is read-only",
+		Publisher: "homebrew",
+		Release:   Release{Version: "v1.2.3", Tag: "v1.2.3"},
+		Error:     errors.New("tap is read-only"),
+		Timestamp: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+// webhookServer records the JSON payloads posted to it, answering with status
+func webhookServer(t *testing.T, status int, payloads *[]map[string]interface{}) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		*payloads = append(*payloads, payload)
+		w.WriteHeader(status)
+		if status >= 300 {
+			w.Write([]byte("invalid_token"))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestNewNotificationChannel(t *testing.T) {
+	channel, err := NewNotificationChannel("ops", NotificationChannelConfig{Type: "slack", Enabled: true, Config: map[string]interface{}{"webhook_url": "https://hooks.slack.com/services/T/B/X"}})
+	require.NoError(t, err)
+	assert.IsType(t, &SlackNotificationChannel{}, channel)
+	assert.Equal(t, "ops", channel.GetName())
+	assert.True(t, channel.IsEnabled())
+
+	// The type defaults to the name
+	channel, err = NewNotificationChannel("console", NotificationChannelConfig{Config: map[string]interface{}{"colored": false}})
+	require.NoError(t, err)
+	assert.Equal(t, &ConsoleNotificationChannel{name: "console", colored: false}, channel)
+
+	_, err = NewNotificationChannel("discord", NotificationChannelConfig{Enabled: true})
+	assert.ErrorContains(t, err, "webhook_url")
+	_, err = NewNotificationChannel("email", NotificationChannelConfig{Config: map[string]interface{}{"host": "smtp.example.com"}})
+	assert.ErrorContains(t, err, "from and to")
+	_, err = NewNotificationChannel("pager", NotificationChannelConfig{})
+	assert.ErrorContains(t, err, `unknown notification channel type "pager"`)
+}
+
+func TestSlackNotificationChannel_Send(t *testing.T) {
+	var payloads []map[string]interface{}
+	server := webhookServer(t, http.StatusOK, &payloads)
+
+	channel, err := NewSlackNotificationChannel("slack", NotificationChannelConfig{Enabled: true, Config: map[string]interface{}{
+		"webhook_url": server.URL,
+		"channel":     "#releases",
+		"username":    "NetTraceX",
+	}})
+	require.NoError(t, err)
+	require.NoError(t, channel.Send(context.Background(), channelNotification()))
+
+	require.Len(t, payloads, 1)
+	assert.Equal(t, "#releases", payloads[0]["channel"])
+	assert.Equal(t, "NetTraceX", payloads[0]["username"])
+	assert.Equal(t, "Release Publishing Failed", payloads[0]["text"])
+	attachment := payloads[0]["attachments"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "danger", attachment["color"])
+	assert.Equal(t, "Failed to publish release v1.2.3 to homebrew: tap is read-only", attachment["text"])
+	assert.Equal(t, float64(1709294400), attachment["ts"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"title": "Publisher", "value": "homebrew", "short": true},
+		map[string]interface{}{"title": "Version", "value": "v1.2.3", "short": true},
+		map[string]interface{}{"title": "Error", "value": "tap is read-only", "short": false},
+	}, attachment["fields"])
+
+	// A refused webhook is reported
+	server = webhookServer(t, http.StatusForbidden, &payloads)
+	channel.webhookURL = server.URL
+	err = channel.Send(context.Background(), channelNotification())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 403: invalid_token")
+}
+
+func TestDiscordNotificationChannel_Send(t *testing.T) {
+	var payloads []map[string]interface{}
+	server := webhookServer(t, http.StatusNoContent, &payloads)
+
+	channel, err := NewDiscordNotificationChannel("discord", NotificationChannelConfig{Enabled: true, Config: map[string]interface{}{
+		"webhook_url": server.URL,
+		"username":    "NetTraceX",
+	}})
+	require.NoError(t, err)
+
+	notification := channelNotification()
+	notification.Type = NotificationTypeSuccess
+	notification.Error = nil
+	require.NoError(t, channel.Send(context.Background(), notification))
+
+	require.Len(t, payloads, 1)
+	assert.Equal(t, "NetTraceX", payloads[0]["username"])
+	embed := payloads[0]["embeds"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "Release Publishing Failed", embed["title"])
+	assert.Equal(t, float64(0x2EB886), embed["color"])
+	assert.Equal(t, "2024-03-01T12:00:00Z", embed["timestamp"])
+	assert.Len(t, embed["fields"], 2)
+}
+
+func TestEmailNotificationChannel_Send(t *testing.T) {
+	channel, err := NewEmailNotificationChannel("email", NotificationChannelConfig{Enabled: true, Config: map[string]interface{}{
+		"host":     "smtp.example.com",
+		"port":     "2525",
+		"username": "releases",
+		"password": "secret",
+		"from":     "releases@example.com",
+		"to":       "ops@example.com, dev@example.com",
+	}})
+	require.NoError(t, err)
+
+	var addr string
+	var to []string
+	var msg string
+	channel.sendMail = func(a string, auth smtp.Auth, from string, recipients []string, m []byte) error {
+		assert.NotNil(t, auth)
+		assert.Equal(t, "releases@example.com", from)
+		addr, to, msg = a, recipients, string(m)
+		return nil
+	}
+	require.NoError(t, channel.Send(context.Background(), channelNotification()))
+
+	assert.Equal(t, "smtp.example.com:2525", addr)
+	assert.Equal(t, []string{"ops@example.com", "dev@example.com"}, to)
+	assert.Contains(t, msg, "To: ops@example.com, dev@example.com\r\n")
+	assert.Contains(t, msg, "Subject: [NetTraceX] Release Publishing Failed: homebrew v1.2.3\r\n")
+	assert.Contains(t, msg, "Date: Fri, 01 Mar 2024 12:00:00 +0000\r\n")
+	body := msg[strings.Index(msg, "\r\n\r\n")+4:]
+	assert.Equal(t, "Failed to publish release v1.2.3 to homebrew: tap is read-only\r\n\r\n"+
+		"Publisher: homebrew\r\nVersion: v1.2.3\r\nError: tap is read-only\r\n", body)
+
+	// Without a username the mail is sent unauthenticated
+	channel.username = ""
+	channel.sendMail = func(a string, auth smtp.Auth, from string, recipients []string, m []byte) error {
+		assert.Nil(t, auth)
+		return errors.New("connection refused")
+	}
+	assert.ErrorContains(t, channel.Send(context.Background(), channelNotification()), "connection refused")
+}
@@ -0,0 +1,184 @@
+package distribution
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ChecksumAlgorithm is a hash release files are summed with
+type ChecksumAlgorithm string
+
+const (
+	SHA256 ChecksumAlgorithm = "sha256"
+	SHA512 ChecksumAlgorithm = "sha512"
+)
+
+// Checksum files written next to the release files, in the format of
+// sha256sum and sha512sum
+const (
+	ChecksumsFile       = "checksums.txt"
+	SHA512ChecksumsFile = "checksums.sha512.txt"
+)
+
+// ChecksumsFileName returns the checksum file written for algorithm
+func ChecksumsFileName(algorithm ChecksumAlgorithm) string {
+	if algorithm == SHA512 {
+		return SHA512ChecksumsFile
+	}
+	return ChecksumsFile
+}
+
+// newHash returns a hash of algorithm
+func newHash(algorithm ChecksumAlgorithm) (hash.Hash, error) {
+	switch algorithm {
+	case SHA256:
+		return sha256.New(), nil
+	case SHA512:
+		return sha512.New(), nil
+	}
+	return nil, fmt.Errorf("unsupported checksum algorithm %q", algorithm)
+}
+
+// Checksum streams r through algorithm and returns the hex digest
+func Checksum(r io.Reader, algorithm ChecksumAlgorithm) (string, error) {
+	h, err := newHash(algorithm)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// FileChecksum returns the hex digest of the file at path, read in chunks so
+// large binaries are not held in memory
+func FileChecksum(path string, algorithm ChecksumAlgorithm) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	return Checksum(file, algorithm)
+}
+
+// WriteChecksums writes sums as "digest  name" lines sorted by name
+func WriteChecksums(w io.Writer, sums map[string]string) error {
+	names := make([]string, 0, len(sums))
+	for name := range sums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := fmt.Fprintf(w, "%s  %s\n", sums[name], name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteChecksumsFile writes sums to the file at path
+func WriteChecksumsFile(path string, sums map[string]string) error {
+	var content strings.Builder
+	if err := WriteChecksums(&content, sums); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content.String()), 0644)
+}
+
+// ParseChecksums reads "digest  name" lines, also accepting the "*name" of
+// binary mode; blank lines are skipped
+func ParseChecksums(r io.Reader) (map[string]string, error) {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		digest, name, ok := strings.Cut(text, " ")
+		name = strings.TrimPrefix(strings.TrimSpace(name), "*")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("line %d: expected a digest and a file name", line)
+		}
+		if _, err := hex.DecodeString(digest); err != nil {
+			return nil, fmt.Errorf("line %d: invalid digest %q", line, digest)
+		}
+		sums[name] = strings.ToLower(digest)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return sums, nil
+}
+
+// algorithmOf tells the algorithm of a hex digest from its length
+func algorithmOf(digest string) (ChecksumAlgorithm, bool) {
+	switch len(digest) {
+	case sha256.Size * 2:
+		return SHA256, true
+	case sha512.Size * 2:
+		return SHA512, true
+	}
+	return "", false
+}
+
+// VerifyChecksumsFile checks every file listed in the checksum file at path
+// against the files in dir, telling SHA-256 from SHA-512 digests by their
+// length. It returns the number of files verified and an error listing
+// every missing or mismatched file.
+func VerifyChecksumsFile(path, dir string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	sums, err := ParseChecksums(file)
+	if err != nil {
+		return 0, fmt.Errorf("invalid checksum file %s: %w", path, err)
+	}
+
+	names := make([]string, 0, len(sums))
+	for name := range sums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	verified := 0
+	for _, name := range names {
+		want := sums[name]
+		algorithm, ok := algorithmOf(want)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: digest of unknown length %d", name, len(want)))
+			continue
+		}
+		got, err := FileChecksum(filepath.Join(dir, name), algorithm)
+		switch {
+		case os.IsNotExist(err):
+			problems = append(problems, fmt.Sprintf("%s: missing", name))
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+		case got != want:
+			problems = append(problems, fmt.Sprintf("%s: %s mismatch, got %s, want %s", name, algorithm, got, want))
+		default:
+			verified++
+		}
+	}
+	if len(problems) > 0 {
+		return verified, fmt.Errorf("%s: %s", filepath.Base(path), strings.Join(problems, "; "))
+	}
+	return verified, nil
+}
@@ -0,0 +1,84 @@
+package distribution
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksum(t *testing.T) {
+	sum, err := Checksum(strings.NewReader("hello\n"), SHA256)
+	require.NoError(t, err)
+	assert.Equal(t, "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03", sum)
+
+	sum, err = Checksum(strings.NewReader("hello\n"), SHA512)
+	require.NoError(t, err)
+	assert.Len(t, sum, 128)
+	assert.True(t, strings.HasPrefix(sum, "e7c22b994c59d9cf2b48e549b1e24666636045930d3da7c1acb299d1c3b7f931"))
+
+	_, err = Checksum(strings.NewReader(""), "md5")
+	assert.Error(t, err)
+}
+
+func TestWriteAndParseChecksums(t *testing.T) {
+	sums := map[string]string{
+		"nettracex_Windows_x86_64.zip":  strings.Repeat("b", 64),
+		"nettracex_Linux_x86_64.tar.gz": strings.Repeat("a", 64),
+	}
+	var buf bytes.Buffer
+	require.NoError(t, WriteChecksums(&buf, sums))
+	assert.Equal(t, strings.Repeat("a", 64)+"  nettracex_Linux_x86_64.tar.gz\n"+
+		strings.Repeat("b", 64)+"  nettracex_Windows_x86_64.zip\n", buf.String(), "lines are sorted by name")
+
+	parsed, err := ParseChecksums(strings.NewReader(buf.String() + "\n" + strings.Repeat("C", 64) + " *nettracex_Darwin_arm64.tar.gz\n"))
+	require.NoError(t, err)
+	assert.Len(t, parsed, 3)
+	assert.Equal(t, strings.Repeat("c", 64), parsed["nettracex_Darwin_arm64.tar.gz"])
+
+	_, err = ParseChecksums(strings.NewReader("abc123\n"))
+	assert.Error(t, err)
+	_, err = ParseChecksums(strings.NewReader("not-hex  app\n"))
+	assert.Error(t, err)
+}
+
+func TestVerifyChecksumsFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app-linux"), []byte("linux binary"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app-windows"), []byte("windows binary"), 0644))
+
+	sums := make(map[string]string)
+	for _, name := range []string{"app-linux", "app-windows"} {
+		sum, err := FileChecksum(filepath.Join(dir, name), SHA256)
+		require.NoError(t, err)
+		sums[name] = sum
+	}
+	path := filepath.Join(dir, ChecksumsFile)
+	require.NoError(t, WriteChecksumsFile(path, sums))
+
+	verified, err := VerifyChecksumsFile(path, dir)
+	require.NoError(t, err)
+	assert.Equal(t, 2, verified)
+
+	// SHA-512 digests are told by their length
+	linux512, err := FileChecksum(filepath.Join(dir, "app-linux"), SHA512)
+	require.NoError(t, err)
+	path512 := filepath.Join(dir, SHA512ChecksumsFile)
+	require.NoError(t, WriteChecksumsFile(path512, map[string]string{"app-linux": linux512}))
+	verified, err = VerifyChecksumsFile(path512, dir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, verified)
+
+	// A changed and a missing file are both reported
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app-linux"), []byte("tampered"), 0644))
+	require.NoError(t, os.Remove(filepath.Join(dir, "app-windows")))
+	verified, err = VerifyChecksumsFile(path, dir)
+	require.Error(t, err)
+	assert.Equal(t, 0, verified)
+	assert.Contains(t, err.Error(), "app-linux: sha256 mismatch")
+	assert.Contains(t, err.Error(), "app-windows: missing")
+}
@@ -0,0 +1,223 @@
+package distribution
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// githubContents writes files of a GitHub repository with the contents API
+// and opens pull requests for them, for the publishers that keep their
+// manifests in a repository
+type githubContents struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// repoContent is a file of a repository as the contents API describes it
+type repoContent struct {
+	SHA string `json:"sha"`
+}
+
+// request sends an authenticated GitHub API request
+func (gc githubContents) request(ctx context.Context, method, url string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if gc.token != "" {
+		req.Header.Set("Authorization", "token "+gc.token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return gc.client.Do(req)
+}
+
+// send sends a JSON request and decodes the JSON response into result,
+// when given, failing on any status but want
+func (gc githubContents) send(ctx context.Context, method, url string, payload, result interface{}, want int) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	resp, err := gc.request(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != want {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s failed with status %d: %s", method, url, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	if result != nil {
+		return json.NewDecoder(resp.Body).Decode(result)
+	}
+	return nil
+}
+
+// fileURL returns the contents API URL of a file of repo
+func (gc githubContents) fileURL(repo, path string) string {
+	return fmt.Sprintf("%s/repos/%s/contents/%s", gc.baseURL, repo, path)
+}
+
+// fileSHA returns the blob SHA of a file of repo on branch, or "" when the
+// file does not exist yet
+func (gc githubContents) fileSHA(ctx context.Context, repo, path, branch string) (string, error) {
+	resp, err := gc.request(ctx, http.MethodGet, gc.fileURL(repo, path)+"?ref="+branch, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var existing repoContent
+		if err := json.NewDecoder(resp.Body).Decode(&existing); err != nil {
+			return "", fmt.Errorf("failed to decode %s: %w", path, err)
+		}
+		return existing.SHA, nil
+	case http.StatusNotFound:
+		return "", nil
+	}
+	return "", fmt.Errorf("failed to read %s: status %d", path, resp.StatusCode)
+}
+
+// putFile creates or updates a file of repo on branch with a commit
+func (gc githubContents) putFile(ctx context.Context, repo, path, branch, message, content string) error {
+	// An existing file is replaced by naming its blob
+	sha, err := gc.fileSHA(ctx, repo, path, branch)
+	if err != nil {
+		return err
+	}
+
+	body := map[string]string{
+		"message": message,
+		"content": base64.StdEncoding.EncodeToString([]byte(content)),
+		"branch":  branch,
+	}
+	if sha != "" {
+		body["sha"] = sha
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := gc.request(ctx, http.MethodPut, gc.fileURL(repo, path), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("update of %s failed with status %d: %s", path, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// createBranch creates branch in repo at the head of base
+func (gc githubContents) createBranch(ctx context.Context, repo, branch, base string) error {
+	var ref struct {
+		Object struct {
+			SHA string `json:"sha"`
+		} `json:"object"`
+	}
+	url := fmt.Sprintf("%s/repos/%s/git/ref/heads/%s", gc.baseURL, repo, base)
+	if err := gc.send(ctx, http.MethodGet, url, nil, &ref, http.StatusOK); err != nil {
+		return err
+	}
+
+	payload := map[string]string{"ref": "refs/heads/" + branch, "sha": ref.Object.SHA}
+	return gc.send(ctx, http.MethodPost, fmt.Sprintf("%s/repos/%s/git/refs", gc.baseURL, repo), payload, nil, http.StatusCreated)
+}
+
+// createPullRequest opens a pull request of head into base and returns its
+// URL
+func (gc githubContents) createPullRequest(ctx context.Context, repo, head, base, title, body string) (string, error) {
+	var pull struct {
+		HTMLURL string `json:"html_url"`
+	}
+	payload := map[string]string{"title": title, "head": head, "base": base, "body": body}
+	if err := gc.send(ctx, http.MethodPost, fmt.Sprintf("%s/repos/%s/pulls", gc.baseURL, repo), payload, &pull, http.StatusCreated); err != nil {
+		return "", err
+	}
+	return pull.HTMLURL, nil
+}
+
+// checkPush checks that the token can push to repo
+func (gc githubContents) checkPush(ctx context.Context, repo string) error {
+	var info struct {
+		Permissions struct {
+			Push bool `json:"push"`
+		} `json:"permissions"`
+	}
+	if err := gc.send(ctx, http.MethodGet, fmt.Sprintf("%s/repos/%s", gc.baseURL, repo), nil, &info, http.StatusOK); err != nil {
+		return err
+	}
+	if !info.Permissions.Push {
+		return fmt.Errorf("token cannot push to %s", repo)
+	}
+	return nil
+}
+
+// exists reports whether a file or directory of repo exists on branch
+func (gc githubContents) exists(ctx context.Context, repo, path, branch string) (bool, error) {
+	resp, err := gc.request(ctx, http.MethodGet, gc.fileURL(repo, path)+"?ref="+branch, nil)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to read %s: status %d", path, resp.StatusCode)
+}
+
+// fork forks repo to the account of the token, or finds the fork it
+// already has, and returns the "owner/repo" of the fork
+func (gc githubContents) fork(ctx context.Context, repo string) (string, error) {
+	var fork struct {
+		FullName string `json:"full_name"`
+	}
+	// GitHub creates forks in the background and answers with the fork
+	if err := gc.send(ctx, http.MethodPost, fmt.Sprintf("%s/repos/%s/forks", gc.baseURL, repo), map[string]string{}, &fork, http.StatusAccepted); err != nil {
+		return "", err
+	}
+	return fork.FullName, nil
+}
+
+// syncFork brings branch of a fork up to date with its upstream repository
+func (gc githubContents) syncFork(ctx context.Context, fork, branch string) error {
+	payload := map[string]string{"branch": branch}
+	return gc.send(ctx, http.MethodPost, fmt.Sprintf("%s/repos/%s/merge-upstream", gc.baseURL, fork), payload, nil, http.StatusOK)
+}
+
+// user returns the login of the account of the token
+func (gc githubContents) user(ctx context.Context) (string, error) {
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := gc.send(ctx, http.MethodGet, gc.baseURL+"/user", nil, &user, http.StatusOK); err != nil {
+		return "", err
+	}
+	return user.Login, nil
+}
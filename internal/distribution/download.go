@@ -0,0 +1,35 @@
+package distribution
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// downloadURL returns the download URL of a binary, which defaults to its
+// asset of the GitHub release in repo. It is empty when the binary has no
+// URL and repo or the release tag is unknown.
+func downloadURL(repo string, release Release, binary Binary) string {
+	if binary.DownloadURL != "" {
+		return binary.DownloadURL
+	}
+	if repo == "" || release.Tag == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://github.com/%s/releases/download/%s/%s", repo, release.Tag, binary.Filename)
+}
+
+// downloadSHA256 downloads url with client and returns the SHA-256 hex
+// digest of the file, for manifests of binaries not built locally
+func downloadSHA256(client *http.Client, url string) (string, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download file: %s", resp.Status)
+	}
+
+	return Checksum(resp.Body, SHA256)
+}
@@ -0,0 +1,124 @@
+package distribution
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DryRunPublisher is implemented by publishers that can show what they
+// would publish without publishing anything
+type DryRunPublisher interface {
+	Publisher
+	// DryRun renders what publishing a release would write and checks the
+	// publisher's preconditions and credentials, without side effects
+	DryRun(ctx context.Context, release Release) (*DryRunReport, error)
+}
+
+// DryRunReport describes what a publisher would publish for a release
+type DryRunReport struct {
+	Publisher string
+	Stage     int
+	// DependsOn lists the publishers the publisher would wait for
+	DependsOn []string
+	// Actions lists what publishing would do, in order
+	Actions []string
+	// Files are the rendered files publishing would write, by name
+	Files map[string]string
+	// Err is why publishing would fail
+	Err error
+}
+
+// FileNames returns the names of the rendered files, in order
+func (r *DryRunReport) FileNames() []string {
+	names := make([]string, 0, len(r.Files))
+	for name := range r.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DryRun walks the distribution of a release without publishing it: it runs
+// the validators, plans the stages and has each publisher report what it
+// would publish. Signing is reported, not run, since it writes signature
+// files. Publishers without dry-run support are only validated.
+func (dc *DistributionCoordinator) DryRun(ctx context.Context, release Release) ([]DryRunReport, error) {
+	if err := dc.validateRelease(ctx, release); err != nil {
+		return nil, fmt.Errorf("release validation failed: %w", err)
+	}
+
+	publishers := dc.getEnabledPublishers()
+	if len(publishers) == 0 {
+		return nil, fmt.Errorf("no enabled publishers configured")
+	}
+	if publishers = dc.channelPublishers(publishers, release); len(publishers) == 0 {
+		return nil, fmt.Errorf("no enabled publishers publish %s releases", ChannelOf(release))
+	}
+	stages, err := dc.planStages(publishers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid publishing plan: %w", err)
+	}
+	byName := make(map[string]Publisher, len(publishers))
+	releases := make(map[string]Release, len(publishers))
+	for _, publisher := range publishers {
+		byName[publisher.GetName()] = publisher
+		releases[publisher.GetName()] = release
+	}
+	if err := dc.renderNotes(releases); err != nil {
+		return nil, fmt.Errorf("release notes rendering failed: %w", err)
+	}
+
+	var reports []DryRunReport
+	failures := 0
+	for _, stage := range stages {
+		for _, name := range stage.Publishers {
+			report := dc.dryRunPublisher(ctx, byName[name], releases[name])
+			report.Stage = stage.Number
+			report.DependsOn = stage.DependsOn[name]
+			if report.Err != nil {
+				failures++
+			}
+			reports = append(reports, *report)
+		}
+	}
+
+	if failures > 0 {
+		return reports, fmt.Errorf("publishing would fail for %d publishers", failures)
+	}
+	return reports, nil
+}
+
+// dryRunPublisher reports what a single publisher would publish
+func (dc *DistributionCoordinator) dryRunPublisher(ctx context.Context, publisher Publisher, release Release) *DryRunReport {
+	name := publisher.GetName()
+
+	var signing []string
+	signers, err := dc.getSigners(name)
+	if err != nil {
+		return &DryRunReport{Publisher: name, Err: err}
+	}
+	for _, signer := range signers {
+		signing = append(signing, signer.GetName())
+	}
+
+	var report *DryRunReport
+	if dryRunner, ok := publisher.(DryRunPublisher); ok {
+		report, err = dryRunner.DryRun(ctx, release)
+		if report == nil {
+			report = &DryRunReport{}
+		}
+	} else {
+		report = &DryRunReport{Actions: []string{fmt.Sprintf("publish release %s (no dry run support, only validated)", release.Tag)}}
+		err = publisher.Validate(ctx, release)
+	}
+	report.Publisher = name
+	report.Err = err
+
+	if len(signing) > 0 {
+		action := fmt.Sprintf("sign the release with %s", strings.Join(signing, " and "))
+		report.Actions = append([]string{action}, report.Actions...)
+	}
+	return report
+}
@@ -0,0 +1,114 @@
+package distribution
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bucketServer answers the repository lookup of the Scoop bucket, failing
+// the test on any write
+func bucketServer(t *testing.T, push *bool) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method, "a dry run writes nothing")
+		assert.Equal(t, "/repos/nettracex/scoop-bucket", r.URL.Path)
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"permissions": map[string]bool{"push": *push},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDistributionCoordinator_DryRun(t *testing.T) {
+	push := true
+	server := bucketServer(t, &push)
+
+	log := &publishLog{}
+	coordinator := NewDistributionCoordinator(&DistributionConfig{Publishers: map[string]PublisherConfig{
+		"scoop":  {Enabled: true, Priority: 1},
+		"winget": {Enabled: true, Priority: 2, Stage: 1, DependsOn: []string{"scoop"}},
+	}})
+	require.NoError(t, coordinator.RegisterPublisher(NewScoopPublisher(scoopConfig(server.URL))))
+	require.NoError(t, coordinator.RegisterPublisher(&stagePublisher{name: "winget", log: log}))
+
+	reports, err := coordinator.DryRun(context.Background(), scoopRelease())
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+
+	scoop := reports[0]
+	assert.Equal(t, "scoop", scoop.Publisher)
+	assert.Equal(t, 0, scoop.Stage)
+	assert.NoError(t, scoop.Err)
+	assert.Equal(t, []string{"commit bucket/nettracex.json to nettracex/scoop-bucket on branch main"}, scoop.Actions)
+	require.Equal(t, []string{"bucket/nettracex.json"}, scoop.FileNames())
+	var manifest ScoopManifest
+	require.NoError(t, json.Unmarshal([]byte(scoop.Files["bucket/nettracex.json"]), &manifest))
+	assert.Equal(t, "1.2.3", manifest.Version)
+
+	winget := reports[1]
+	assert.Equal(t, "winget", winget.Publisher)
+	assert.Equal(t, 1, winget.Stage)
+	assert.Equal(t, []string{"scoop"}, winget.DependsOn)
+	assert.Equal(t, []string{"publish release v1.2.3 (no dry run support, only validated)"}, winget.Actions)
+
+	assert.Empty(t, log.events, "nothing is published")
+	for _, status := range coordinator.GetPublisherStatus() {
+		assert.Zero(t, status.PublishCount)
+	}
+
+	// A token that cannot push fails the dry run
+	push = false
+	reports, err = coordinator.DryRun(context.Background(), scoopRelease())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "publishing would fail for 1 publishers")
+	require.Error(t, reports[0].Err)
+	assert.Contains(t, reports[0].Err.Error(), "cannot push to nettracex/scoop-bucket")
+	assert.NotEmpty(t, reports[0].Files, "what would be published is still shown")
+}
+
+func TestDistributionCoordinator_DryRunPlanErrors(t *testing.T) {
+	coordinator := stageCoordinator(t, map[string]PublisherConfig{
+		"github":   {Enabled: true, DependsOn: []string{"homebrew"}},
+		"homebrew": {Enabled: true, DependsOn: []string{"github"}},
+	}, &publishLog{})
+
+	_, err := coordinator.DryRun(context.Background(), scoopRelease())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dependency cycle")
+}
+
+func TestS3Publisher_DryRun(t *testing.T) {
+	storage, server := newS3Server(t)
+	publisher, err := NewS3Publisher(S3Config{
+		Endpoint:  server.URL,
+		Bucket:    "downloads",
+		Prefix:    "nettracex",
+		AccessKey: "minio",
+		SecretKey: "minio-secret",
+		PathStyle: true,
+		Latest:    true,
+	})
+	require.NoError(t, err)
+
+	report, err := publisher.DryRun(context.Background(), s3Release(t, "v1.2.3"))
+	require.NoError(t, err)
+	assert.Empty(t, storage.objects, "nothing is uploaded")
+	assert.Contains(t, report.Actions, "upload nettracex_Linux_x86_64.tar.gz to "+server.URL+"/downloads/nettracex/v1.2.3/nettracex_Linux_x86_64.tar.gz")
+	assert.Contains(t, report.Actions, "point "+server.URL+"/downloads/nettracex/latest.json at v1.2.3")
+	var manifest S3Manifest
+	require.NoError(t, json.Unmarshal([]byte(report.Files[S3ManifestFile]), &manifest))
+	assert.Equal(t, "v1.2.3", manifest.Tag)
+
+	// An older release leaves latest.json alone
+	require.NoError(t, publisher.Publish(context.Background(), s3Release(t, "v1.2.4")))
+	report, err = publisher.DryRun(context.Background(), s3Release(t, "v1.2.3"))
+	require.NoError(t, err)
+	assert.Contains(t, report.Actions, "leave latest.json at the newer v1.2.4")
+}
@@ -0,0 +1,64 @@
+package distribution
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	testHomepage    = "https://github.com/nettracex/nettracex-tui"
+	testReleaseRepo = "nettracex/nettracex-tui"
+	testDescription = "Network diagnostic toolkit with beautiful TUI"
+)
+
+// testPlatform is one binary of a test release; url is left empty for
+// assets served from the GitHub release
+type testPlatform struct {
+	os, arch, filename, url string
+}
+
+// testRelease builds a release of version with one binary per platform,
+// checksummed "aaa…", "bbb…" and so on in the order given
+func testRelease(version string, platforms ...testPlatform) Release {
+	release := Release{Version: version, Tag: version, Binaries: map[string]Binary{}}
+	for i, platform := range platforms {
+		release.Binaries[platform.filename] = Binary{
+			Platform:     platform.os,
+			Architecture: platform.arch,
+			Filename:     platform.filename,
+			Checksum:     strings.Repeat(string(rune('a'+i)), 64),
+			DownloadURL:  platform.url,
+		}
+	}
+	return release
+}
+
+// createdAt pins the release date so generated metadata is stable
+func createdAt(release Release) Release {
+	release.Metadata.CreatedAt = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	return release
+}
+
+// linuxPlatforms are the assets of the Linux package publishers' release
+var linuxPlatforms = []testPlatform{
+	{"linux", "amd64", "nettracex-linux-amd64", ""},
+	{"linux", "arm64", "nettracex_1.2.3_Linux_arm64.tar.gz", "https://downloads.example.com/nettracex_1.2.3_Linux_arm64.tar.gz"},
+	{"darwin", "arm64", "nettracex-darwin-arm64", ""},
+}
+
+// windowsPlatforms are the assets of the Windows package publishers' release
+var windowsPlatforms = []testPlatform{
+	{"windows", "amd64", "nettracex_1.2.3_Windows_x86_64.zip", "https://downloads.example.com/nettracex_1.2.3_Windows_x86_64.zip"},
+	{"windows", "arm64", "nettracex-windows-arm64.exe", ""},
+	{"linux", "amd64", "nettracex-linux-amd64", ""},
+}
+
+// hostedAt returns platforms with every asset downloaded from base
+func hostedAt(base string, platforms []testPlatform) []testPlatform {
+	hosted := make([]testPlatform, len(platforms))
+	for i, platform := range platforms {
+		platform.url = base + platform.filename
+		hosted[i] = platform
+	}
+	return hosted
+}
@@ -0,0 +1,600 @@
+package distribution
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/update"
+)
+
+// FlatpakPublisher manages Flatpak manifest creation and publishing to the
+// app's flathub repository
+type FlatpakPublisher struct {
+	config FlatpakConfig
+	client *http.Client
+	status PublishStatus
+	// pullRequest is the URL of the last pull request opened
+	pullRequest string
+}
+
+// FlatpakConfig contains Flatpak publishing configuration
+type FlatpakConfig struct {
+	AppID          string   `json:"app_id"`  // reverse-DNS application ID, e.g. "io.github.nettracex.NetTraceX"
+	Command        string   `json:"command"` // name of the installed executable
+	Name           string   `json:"name"`
+	Summary        string   `json:"summary"`
+	Description    string   `json:"description"`
+	Homepage       string   `json:"homepage"`
+	License        string   `json:"license"` // SPDX license expression
+	Developer      string   `json:"developer"`
+	Runtime        string   `json:"runtime"`
+	RuntimeVersion string   `json:"runtime_version"`
+	SDK            string   `json:"sdk"`
+	FinishArgs     []string `json:"finish_args"` // sandbox permissions, e.g. "--share=network"
+	Repo           string   `json:"repo"`        // "owner/repo" of the manifest, e.g. "flathub/<app id>"
+	Branch         string   `json:"branch"`      // branch of the repository the manifest lives on
+	GitHubToken    string   `json:"github_token"`
+	BaseURL        string   `json:"base_url"`
+	ReleaseRepo    string   `json:"release_repo"`
+	// PullRequest commits the manifest to a branch of its own and opens a
+	// pull request for flathub's build bot to test, instead of committing
+	// to Branch
+	PullRequest bool `json:"pull_request"`
+}
+
+// FlatpakPackage is what is published to flathub: the manifest, the
+// AppStream metainfo it installs and the architectures it builds on
+type FlatpakPackage struct {
+	Manifest FlatpakManifest
+	Metainfo FlatpakMetainfo
+	Arches   []string
+}
+
+// FlatpakManifest is a flatpak-builder manifest
+type FlatpakManifest struct {
+	ID             string          `json:"id"`
+	Runtime        string          `json:"runtime"`
+	RuntimeVersion string          `json:"runtime-version"`
+	SDK            string          `json:"sdk"`
+	Command        string          `json:"command"`
+	FinishArgs     []string        `json:"finish-args,omitempty"`
+	Modules        []FlatpakModule `json:"modules"`
+}
+
+// FlatpakModule is a module of a manifest
+type FlatpakModule struct {
+	Name          string          `json:"name"`
+	Buildsystem   string          `json:"buildsystem"`
+	BuildCommands []string        `json:"build-commands"`
+	Sources       []FlatpakSource `json:"sources"`
+}
+
+// FlatpakSource is a source of a module: a download of one architecture,
+// or a file next to the manifest
+type FlatpakSource struct {
+	Type            string              `json:"type"`
+	URL             string              `json:"url,omitempty"`
+	SHA256          string              `json:"sha256,omitempty"`
+	Path            string              `json:"path,omitempty"`
+	DestFilename    string              `json:"dest-filename,omitempty"`
+	StripComponents *int                `json:"strip-components,omitempty"`
+	OnlyArches      []string            `json:"only-arches,omitempty"`
+	CheckerData     *FlatpakCheckerData `json:"x-checker-data,omitempty"`
+}
+
+// FlatpakCheckerData tells flathub's external data checker how to find the
+// download of a new release
+type FlatpakCheckerData struct {
+	Type         string `json:"type"`
+	URL          string `json:"url"`
+	VersionQuery string `json:"version-query"`
+	URLQuery     string `json:"url-query"`
+}
+
+// FlatpakMetainfo is the AppStream metainfo of a console application
+type FlatpakMetainfo struct {
+	XMLName         xml.Name          `xml:"component"`
+	Type            string            `xml:"type,attr"`
+	ID              string            `xml:"id"`
+	MetadataLicense string            `xml:"metadata_license"`
+	ProjectLicense  string            `xml:"project_license"`
+	Name            string            `xml:"name"`
+	Summary         string            `xml:"summary"`
+	Developer       *FlatpakDeveloper `xml:"developer,omitempty"`
+	Description     string            `xml:"description>p"`
+	Homepage        *FlatpakURL       `xml:"url,omitempty"`
+	Binary          string            `xml:"provides>binary"`
+	Categories      []string          `xml:"categories>category"`
+	ContentRating   FlatpakRating     `xml:"content_rating"`
+	Releases        []FlatpakRelease  `xml:"releases>release"`
+}
+
+// FlatpakDeveloper is the developer of an application
+type FlatpakDeveloper struct {
+	ID   string `xml:"id,attr"`
+	Name string `xml:"name"`
+}
+
+// FlatpakURL is a link of an application
+type FlatpakURL struct {
+	Type string `xml:"type,attr"`
+	URL  string `xml:",chardata"`
+}
+
+// FlatpakRating is the OARS content rating flathub requires
+type FlatpakRating struct {
+	Type string `xml:"type,attr"`
+}
+
+// FlatpakRelease is a release of an application
+type FlatpakRelease struct {
+	Version string `xml:"version,attr"`
+	Date    string `xml:"date,attr"`
+}
+
+// flatpakArches maps Go architectures of Linux binaries to the
+// architectures flathub builds
+var flatpakArches = map[string]string{
+	"amd64": "x86_64",
+	"arm64": "aarch64",
+}
+
+// flatpakAppID matches application IDs flathub accepts: at least three
+// dot-separated components, none starting with a digit
+var flatpakAppID = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*(\.[A-Za-z_][A-Za-z0-9_-]*){2,}$`)
+
+// NewFlatpakPublisher creates a new Flatpak publisher
+func NewFlatpakPublisher(config FlatpakConfig) *FlatpakPublisher {
+	if config.AppID == "" {
+		config.AppID = "io.github.nettracex.NetTraceX"
+	}
+	if config.Command == "" {
+		config.Command = "nettracex"
+	}
+	if config.Name == "" {
+		config.Name = "NetTraceX"
+	}
+	if config.License == "" {
+		config.License = "MIT"
+	}
+	if config.Runtime == "" {
+		config.Runtime = "org.freedesktop.Platform"
+	}
+	if config.RuntimeVersion == "" {
+		config.RuntimeVersion = "24.08"
+	}
+	if config.SDK == "" {
+		config.SDK = "org.freedesktop.Sdk"
+	}
+	if config.FinishArgs == nil {
+		config.FinishArgs = []string{"--share=network"}
+	}
+	if config.Repo == "" {
+		config.Repo = "flathub/" + config.AppID
+	}
+	if config.Branch == "" {
+		config.Branch = "master"
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = "https://api.github.com"
+	}
+
+	return &FlatpakPublisher{
+		config: config,
+		client: &http.Client{Timeout: 5 * time.Minute},
+		status: PublishStatus{
+			Name:   "flatpak",
+			Status: StatusIdle,
+		},
+	}
+}
+
+// GetName returns the publisher name
+func (p *FlatpakPublisher) GetName() string {
+	return "flatpak"
+}
+
+// DefaultChannels ships stable releases only; prereleases belong on the
+// beta branch of Flathub, which is configured apart
+func (p *FlatpakPublisher) DefaultChannels() []update.Channel {
+	return []update.Channel{update.Stable}
+}
+
+// Publish publishes a release to the flathub repository
+func (p *FlatpakPublisher) Publish(ctx context.Context, release Release) error {
+	p.updateStatus(StatusPublishing, "")
+
+	pkg, err := p.GeneratePackage(release)
+	if err != nil {
+		p.updateStatus(StatusError, err.Error())
+		return fmt.Errorf("failed to generate package: %w", err)
+	}
+
+	if err := p.pushPackage(ctx, pkg); err != nil {
+		p.updateStatus(StatusError, err.Error())
+		return fmt.Errorf("failed to push package: %w", err)
+	}
+
+	p.updateStatus(StatusSuccess, "")
+	return nil
+}
+
+// DryRun renders the manifest, metainfo and flathub.json, and checks that
+// the token can push to the flathub repository
+func (p *FlatpakPublisher) DryRun(ctx context.Context, release Release) (*DryRunReport, error) {
+	report := &DryRunReport{}
+	if err := p.Validate(ctx, release); err != nil {
+		return report, err
+	}
+
+	pkg, err := p.GeneratePackage(release)
+	if err != nil {
+		return report, fmt.Errorf("failed to generate package: %w", err)
+	}
+	report.Files, err = p.Files(pkg)
+	if err != nil {
+		return report, err
+	}
+
+	version := pkg.Metainfo.Releases[0].Version
+	if p.config.PullRequest {
+		branch := "update-" + version
+		report.Actions = append(report.Actions,
+			fmt.Sprintf("create branch %s of %s from %s", branch, p.config.Repo, p.config.Branch),
+			fmt.Sprintf("commit %s to %s", strings.Join(report.FileNames(), ", "), branch),
+			fmt.Sprintf("open a pull request of %s into %s", branch, p.config.Branch))
+	} else {
+		report.Actions = append(report.Actions, fmt.Sprintf("commit %s to %s on branch %s", strings.Join(report.FileNames(), ", "), p.config.Repo, p.config.Branch))
+	}
+
+	repo := githubContents{baseURL: p.config.BaseURL, token: p.config.GitHubToken, client: p.client}
+	if err := repo.checkPush(ctx, p.config.Repo); err != nil {
+		return report, fmt.Errorf("credential check failed: %w", err)
+	}
+	return report, nil
+}
+
+// Validate validates a release for Flatpak publishing
+func (p *FlatpakPublisher) Validate(ctx context.Context, release Release) error {
+	if release.Version == "" {
+		return fmt.Errorf("release version is required")
+	}
+
+	found := false
+	for filename, binary := range release.Binaries {
+		if binary.Platform != "linux" {
+			continue
+		}
+		if _, supported := flatpakArches[binary.Architecture]; !supported {
+			continue
+		}
+		if downloadURL(p.config.ReleaseRepo, release, binary) == "" {
+			return fmt.Errorf("binary %s missing download URL", filename)
+		}
+		found = true
+	}
+
+	if !found {
+		return fmt.Errorf("no supported binary found in release (Linux amd64 or arm64 required)")
+	}
+
+	return nil
+}
+
+// GetStatus returns the current status of the Flatpak publisher
+func (p *FlatpakPublisher) GetStatus() PublishStatus {
+	p.status.Metadata = map[string]string{
+		"repo":   p.config.Repo,
+		"app_id": p.config.AppID,
+	}
+	if p.pullRequest != "" {
+		p.status.Metadata["pull_request"] = p.pullRequest
+	}
+	return p.status
+}
+
+// updateStatus updates the publisher status
+func (p *FlatpakPublisher) updateStatus(status StatusType, lastError string) {
+	p.status.Status = status
+	p.status.LastError = lastError
+	if status == StatusSuccess {
+		p.status.LastPublish = time.Now()
+		p.status.PublishCount++
+	} else if status == StatusError {
+		p.status.ErrorCount++
+	}
+}
+
+// metainfoFile returns the name of the metainfo file
+func (p *FlatpakPublisher) metainfoFile() string {
+	return p.config.AppID + ".metainfo.xml"
+}
+
+// GeneratePackage creates the manifest and metainfo of the Linux binaries
+// of a release, with the version and hashes of its downloads
+func (p *FlatpakPublisher) GeneratePackage(release Release) (*FlatpakPackage, error) {
+	version := strings.TrimPrefix(release.Version, "v")
+	command := p.config.Command
+
+	module := FlatpakModule{
+		Name:        command,
+		Buildsystem: "simple",
+		BuildCommands: []string{
+			fmt.Sprintf("install -Dm755 %s /app/bin/%s", command, command),
+			fmt.Sprintf("install -Dm644 %s /app/share/metainfo/%s", p.metainfoFile(), p.metainfoFile()),
+		},
+	}
+
+	var arches []string
+	var downloads []FlatpakSource
+	for _, binary := range release.Binaries {
+		if binary.Platform != "linux" {
+			continue
+		}
+		arch, supported := flatpakArches[binary.Architecture]
+		if !supported {
+			continue
+		}
+		url := downloadURL(p.config.ReleaseRepo, release, binary)
+
+		// Calculate SHA256 if not provided
+		checksum := binary.Checksum
+		if checksum == "" {
+			sum, err := downloadSHA256(p.client, url)
+			if err != nil {
+				return nil, fmt.Errorf("failed to calculate SHA256 for %s: %w", binary.Filename, err)
+			}
+			checksum = sum
+		}
+
+		source := FlatpakSource{
+			Type:       "file",
+			URL:        url,
+			SHA256:     checksum,
+			OnlyArches: []string{arch},
+		}
+		if strings.HasSuffix(binary.Filename, ".tar.gz") {
+			// The archive holds the executable at its top level
+			strip := 0
+			source.Type = "archive"
+			source.StripComponents = &strip
+		} else {
+			source.DestFilename = command
+		}
+		if p.config.ReleaseRepo != "" {
+			source.CheckerData = p.checkerData(binary.Filename, version)
+		}
+
+		arches = append(arches, arch)
+		downloads = append(downloads, source)
+	}
+
+	if len(downloads) == 0 {
+		return nil, fmt.Errorf("no supported binaries found (Linux amd64 or arm64 required)")
+	}
+	sort.Strings(arches)
+	sort.Slice(downloads, func(i, j int) bool { return downloads[i].OnlyArches[0] < downloads[j].OnlyArches[0] })
+	module.Sources = append(downloads, FlatpakSource{Type: "file", Path: p.metainfoFile()})
+
+	pkg := &FlatpakPackage{
+		Manifest: FlatpakManifest{
+			ID:             p.config.AppID,
+			Runtime:        p.config.Runtime,
+			RuntimeVersion: p.config.RuntimeVersion,
+			SDK:            p.config.SDK,
+			Command:        command,
+			FinishArgs:     p.config.FinishArgs,
+			Modules:        []FlatpakModule{module},
+		},
+		Metainfo: p.generateMetainfo(release, version),
+		Arches:   arches,
+	}
+
+	if err := ValidateFlatpakPackage(pkg); err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+// checkerData lets flathub's external data checker follow the GitHub
+// releases, finding the download of a new version by its asset name
+func (p *FlatpakPublisher) checkerData(filename, version string) *FlatpakCheckerData {
+	asset := strings.ReplaceAll(filename, version, `\($version)`)
+	return &FlatpakCheckerData{
+		Type:         "json",
+		URL:          fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", p.config.ReleaseRepo),
+		VersionQuery: `.tag_name | sub("^v"; "")`,
+		URLQuery:     fmt.Sprintf(`.assets[] | select(.name == "%s") | .browser_download_url`, asset),
+	}
+}
+
+// generateMetainfo creates the metainfo of a release
+func (p *FlatpakPublisher) generateMetainfo(release Release, version string) FlatpakMetainfo {
+	date := release.Metadata.CreatedAt
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	metainfo := FlatpakMetainfo{
+		Type:            "console-application",
+		ID:              p.config.AppID,
+		MetadataLicense: "CC0-1.0",
+		ProjectLicense:  p.config.License,
+		Name:            p.config.Name,
+		Summary:         p.config.Summary,
+		Description:     p.config.Description,
+		Binary:          p.config.Command,
+		Categories:      []string{"Network"},
+		ContentRating:   FlatpakRating{Type: "oars-1.1"},
+		Releases:        []FlatpakRelease{{Version: version, Date: date.UTC().Format("2006-01-02")}},
+	}
+	if metainfo.Description == "" {
+		metainfo.Description = p.config.Summary
+	}
+	if p.config.Developer != "" {
+		// The developer ID is the reverse-DNS prefix of the application ID
+		metainfo.Developer = &FlatpakDeveloper{
+			ID:   p.config.AppID[:strings.LastIndex(p.config.AppID, ".")],
+			Name: p.config.Developer,
+		}
+	}
+	if p.config.Homepage != "" {
+		metainfo.Homepage = &FlatpakURL{Type: "homepage", URL: p.config.Homepage}
+	}
+	return metainfo
+}
+
+// ValidateFlatpakPackage validates a Flatpak package
+func ValidateFlatpakPackage(pkg *FlatpakPackage) error {
+	manifest := pkg.Manifest
+	if !flatpakAppID.MatchString(manifest.ID) {
+		return fmt.Errorf("invalid application ID %q", manifest.ID)
+	}
+	if manifest.Runtime == "" || manifest.RuntimeVersion == "" || manifest.SDK == "" {
+		return fmt.Errorf("runtime, runtime version and SDK are required")
+	}
+	if manifest.Command == "" {
+		return fmt.Errorf("command is required")
+	}
+	if pkg.Metainfo.Summary == "" {
+		return fmt.Errorf("summary is required")
+	}
+	if pkg.Metainfo.ProjectLicense == "" {
+		return fmt.Errorf("license is required")
+	}
+
+	for _, module := range manifest.Modules {
+		for _, source := range module.Sources {
+			if source.URL == "" {
+				continue
+			}
+			if sum, err := hex.DecodeString(source.SHA256); err != nil || len(sum) != 32 {
+				return fmt.Errorf("invalid %s SHA256 hash %q", strings.Join(source.OnlyArches, ","), source.SHA256)
+			}
+		}
+	}
+	return nil
+}
+
+// RenderFlatpakManifest renders the manifest as the JSON flathub
+// repositories keep
+func RenderFlatpakManifest(pkg *FlatpakPackage) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(pkg.Manifest); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// RenderFlatpakMetainfo renders the AppStream metainfo
+func RenderFlatpakMetainfo(pkg *FlatpakPackage) (string, error) {
+	data, err := xml.MarshalIndent(pkg.Metainfo, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(data) + "\n", nil
+}
+
+// RenderFlathubJSON renders the flathub.json restricting the architectures
+// flathub builds, or "" when the package builds on all of them
+func RenderFlathubJSON(pkg *FlatpakPackage) string {
+	if len(pkg.Arches) == len(flatpakArches) {
+		return ""
+	}
+	data, _ := json.MarshalIndent(map[string][]string{"only-arches": pkg.Arches}, "", "    ")
+	return string(data) + "\n"
+}
+
+// Files renders the files of a package, by path in the repository
+func (p *FlatpakPublisher) Files(pkg *FlatpakPackage) (map[string]string, error) {
+	manifest, err := RenderFlatpakManifest(pkg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render manifest: %w", err)
+	}
+	metainfo, err := RenderFlatpakMetainfo(pkg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render metainfo: %w", err)
+	}
+
+	files := map[string]string{
+		p.config.AppID + ".json": manifest,
+		p.metainfoFile():         metainfo,
+	}
+	if flathub := RenderFlathubJSON(pkg); flathub != "" {
+		files["flathub.json"] = flathub
+	}
+	return files, nil
+}
+
+// WritePackage writes the files of a package to dir
+func (p *FlatpakPublisher) WritePackage(pkg *FlatpakPackage, dir string) error {
+	files, err := p.Files(pkg)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pushPackage commits the files of a package to the repository, on a
+// branch of their own with a pull request when enabled
+func (p *FlatpakPublisher) pushPackage(ctx context.Context, pkg *FlatpakPackage) error {
+	files, err := p.Files(pkg)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	repo := githubContents{baseURL: p.config.BaseURL, token: p.config.GitHubToken, client: p.client}
+	version := pkg.Metainfo.Releases[0].Version
+	message := fmt.Sprintf("Update to %s", version)
+
+	branch := p.config.Branch
+	if p.config.PullRequest {
+		branch = "update-" + version
+		if err := repo.createBranch(ctx, p.config.Repo, branch, p.config.Branch); err != nil {
+			return fmt.Errorf("failed to create branch %s: %w", branch, err)
+		}
+	}
+
+	for _, name := range names {
+		if err := repo.putFile(ctx, p.config.Repo, name, branch, message, files[name]); err != nil {
+			return err
+		}
+	}
+
+	if p.config.PullRequest {
+		body := fmt.Sprintf("Updates %s to %s for architectures %s.", p.config.Name, version, strings.Join(pkg.Arches, ", "))
+		url, err := repo.createPullRequest(ctx, p.config.Repo, branch, p.config.Branch, message, body)
+		if err != nil {
+			return fmt.Errorf("failed to open pull request: %w", err)
+		}
+		p.pullRequest = url
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package distribution
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func flatpakRelease() Release {
+	return createdAt(aurRelease())
+}
+
+func flatpakConfig(baseURL string) FlatpakConfig {
+	return FlatpakConfig{
+		Summary:     "Network diagnostic toolkit",
+		Description: "Traceroute, ping, DNS, WHOIS and SSL checks in a terminal UI.",
+		Homepage:    testHomepage,
+		Developer:   "NetTraceX",
+		GitHubToken: "secret",
+		BaseURL:     baseURL,
+		ReleaseRepo: testReleaseRepo,
+	}
+}
+
+func TestFlatpakPublisher_GeneratePackage(t *testing.T) {
+	publisher := NewFlatpakPublisher(flatpakConfig(""))
+	require.NoError(t, publisher.Validate(context.Background(), flatpakRelease()))
+
+	pkg, err := publisher.GeneratePackage(flatpakRelease())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aarch64", "x86_64"}, pkg.Arches)
+
+	manifest, err := RenderFlatpakManifest(pkg)
+	require.NoError(t, err)
+	var rendered map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(manifest), &rendered))
+	assert.Equal(t, "io.github.nettracex.NetTraceX", rendered["id"])
+	assert.Equal(t, "24.08", rendered["runtime-version"])
+	assert.Equal(t, []interface{}{"--share=network"}, rendered["finish-args"])
+
+	sources := pkg.Manifest.Modules[0].Sources
+	require.Len(t, sources, 3)
+	assert.Equal(t, "archive", sources[0].Type)
+	require.NotNil(t, sources[0].StripComponents)
+	assert.Equal(t, 0, *sources[0].StripComponents)
+	assert.Equal(t, FlatpakSource{
+		Type:         "file",
+		URL:          "https://github.com/nettracex/nettracex-tui/releases/download/v1.2.3-rc.1/nettracex-linux-amd64",
+		SHA256:       strings.Repeat("a", 64),
+		DestFilename: "nettracex",
+		OnlyArches:   []string{"x86_64"},
+		CheckerData: &FlatpakCheckerData{
+			Type:         "json",
+			URL:          "https://api.github.com/repos/nettracex/nettracex-tui/releases/latest",
+			VersionQuery: `.tag_name | sub("^v"; "")`,
+			URLQuery:     `.assets[] | select(.name == "nettracex-linux-amd64") | .browser_download_url`,
+		},
+	}, sources[1])
+	assert.Equal(t, `.assets[] | select(.name == "nettracex_1.2.3_Linux_arm64.tar.gz") | .browser_download_url`, sources[0].CheckerData.URLQuery)
+	assert.Equal(t, FlatpakSource{Type: "file", Path: "io.github.nettracex.NetTraceX.metainfo.xml"}, sources[2])
+	assert.Contains(t, manifest, `"strip-components": 0`)
+
+	metainfo, err := RenderFlatpakMetainfo(pkg)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(metainfo, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+`<component type="console-application">`))
+	assert.Contains(t, metainfo, `<developer id="io.github.nettracex">`)
+	assert.Contains(t, metainfo, `<url type="homepage">https://github.com/nettracex/nettracex-tui</url>`)
+	assert.Contains(t, metainfo, "<provides>\n    <binary>nettracex</binary>\n  </provides>")
+	assert.Contains(t, metainfo, `<release version="1.2.3-rc.1" date="2024-03-01"></release>`)
+
+	// Both architectures flathub builds are covered
+	assert.Empty(t, RenderFlathubJSON(pkg))
+
+	// A release without Linux binaries flathub builds has nothing to publish
+	release := flatpakRelease()
+	delete(release.Binaries, "nettracex-linux-amd64")
+	delete(release.Binaries, "nettracex_1.2.3_Linux_arm64.tar.gz")
+	assert.Error(t, publisher.Validate(context.Background(), release))
+	_, err = publisher.GeneratePackage(release)
+	assert.Error(t, err)
+}
+
+func TestValidateFlatpakPackage(t *testing.T) {
+	pkg, err := NewFlatpakPublisher(flatpakConfig("")).GeneratePackage(flatpakRelease())
+	require.NoError(t, err)
+
+	pkg.Manifest.ID = "nettracex"
+	assert.ErrorContains(t, ValidateFlatpakPackage(pkg), "application ID")
+
+	pkg.Manifest.ID = "io.github.nettracex.NetTraceX"
+	pkg.Manifest.Modules[0].Sources[1].SHA256 = "abc"
+	assert.ErrorContains(t, ValidateFlatpakPackage(pkg), "x86_64 SHA256")
+
+	config := flatpakConfig("")
+	config.Summary = ""
+	_, err = NewFlatpakPublisher(config).GeneratePackage(flatpakRelease())
+	assert.ErrorContains(t, err, "summary")
+}
+
+// flathubServer fakes the GitHub API of a flathub repository, recording the
+// requests and the files written
+type flathubServer struct {
+	mu       sync.Mutex
+	requests []string
+	files    map[string]string
+}
+
+func (s *flathubServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+	if r.Header.Get("Authorization") != "token secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message":"Bad credentials"}`))
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/git/ref/heads/master"):
+		w.Write([]byte(`{"object":{"sha":"abc123"}}`))
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/git/refs"):
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/contents/"):
+		w.WriteHeader(http.StatusNotFound)
+	case r.Method == http.MethodPut:
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		content, _ := base64.StdEncoding.DecodeString(body["content"])
+		name := r.URL.Path[strings.Index(r.URL.Path, "/contents/")+len("/contents/"):]
+		s.files[body["branch"]+":"+name] = string(content)
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/pulls"):
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["head"] != "update-1.2.3-rc.1" || body["base"] != "master" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"html_url":"https://github.com/flathub/io.github.nettracex.NetTraceX/pull/7"}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestFlatpakPublisher_Publish(t *testing.T) {
+	flathub := &flathubServer{files: make(map[string]string)}
+	server := httptest.NewServer(flathub)
+	defer server.Close()
+
+	// Only the amd64 binary leaves flathub a single architecture to build
+	release := flatpakRelease()
+	delete(release.Binaries, "nettracex_1.2.3_Linux_arm64.tar.gz")
+
+	publisher := NewFlatpakPublisher(flatpakConfig(server.URL))
+	require.NoError(t, publisher.Publish(context.Background(), release))
+	assert.Contains(t, flathub.files["master:io.github.nettracex.NetTraceX.json"], `"sha256": "`+strings.Repeat("a", 64)+`"`)
+	assert.Contains(t, flathub.files["master:io.github.nettracex.NetTraceX.metainfo.xml"], `version="1.2.3-rc.1"`)
+	assert.JSONEq(t, `{"only-arches": ["x86_64"]}`, flathub.files["master:flathub.json"])
+	assert.Equal(t, StatusSuccess, publisher.GetStatus().Status)
+	assert.Empty(t, publisher.GetStatus().Metadata["pull_request"])
+
+	// With pull requests the files go to a branch of their own
+	flathub.requests = nil
+	config := flatpakConfig(server.URL)
+	config.PullRequest = true
+	publisher = NewFlatpakPublisher(config)
+	require.NoError(t, publisher.Publish(context.Background(), flatpakRelease()))
+	assert.Equal(t, []string{
+		"GET /repos/flathub/io.github.nettracex.NetTraceX/git/ref/heads/master",
+		"POST /repos/flathub/io.github.nettracex.NetTraceX/git/refs",
+		"GET /repos/flathub/io.github.nettracex.NetTraceX/contents/io.github.nettracex.NetTraceX.json",
+		"PUT /repos/flathub/io.github.nettracex.NetTraceX/contents/io.github.nettracex.NetTraceX.json",
+		"GET /repos/flathub/io.github.nettracex.NetTraceX/contents/io.github.nettracex.NetTraceX.metainfo.xml",
+		"PUT /repos/flathub/io.github.nettracex.NetTraceX/contents/io.github.nettracex.NetTraceX.metainfo.xml",
+		"POST /repos/flathub/io.github.nettracex.NetTraceX/pulls",
+	}, flathub.requests)
+	assert.Contains(t, flathub.files, "update-1.2.3-rc.1:io.github.nettracex.NetTraceX.json")
+	status := publisher.GetStatus()
+	assert.Equal(t, "https://github.com/flathub/io.github.nettracex.NetTraceX/pull/7", status.Metadata["pull_request"])
+	assert.Equal(t, "flathub/io.github.nettracex.NetTraceX", status.Metadata["repo"])
+
+	// Refused credentials are reported
+	config.GitHubToken = "wrong"
+	publisher = NewFlatpakPublisher(config)
+	err := publisher.Publish(context.Background(), flatpakRelease())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Bad credentials")
+	assert.Equal(t, StatusError, publisher.GetStatus().Status)
+}
@@ -0,0 +1,108 @@
+package distribution
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"os"
+	"strings"
+)
+
+// gitClient commits files to a git repository for the publishers that keep
+// their packages in one
+type gitClient struct {
+	run    commandRunner
+	author string // "Name <email>" commits are made as
+	sshKey string // private key the repository is reached with over SSH
+}
+
+// identity returns the name and email commits are made as
+func (g gitClient) identity() (string, string) {
+	if address, err := mail.ParseAddress(g.author); err == nil {
+		if address.Name == "" {
+			address.Name = address.Address
+		}
+		return address.Name, address.Address
+	}
+	return "NetTraceX Release", "release@nettracex.dev"
+}
+
+// git runs a git command in the clone at dir, committing as the author and
+// connecting over SSH with the key
+func (g gitClient) git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	var gitArgs []string
+	if dir != "" {
+		gitArgs = append(gitArgs, "-C", dir)
+	}
+	name, email := g.identity()
+	gitArgs = append(gitArgs, "-c", "user.name="+name, "-c", "user.email="+email)
+	if g.sshKey != "" {
+		gitArgs = append(gitArgs, "-c", fmt.Sprintf("core.sshCommand=ssh -i %s -o IdentitiesOnly=yes -o StrictHostKeyChecking=accept-new", g.sshKey))
+	}
+	gitArgs = append(gitArgs, args...)
+
+	output, err := g.run(ctx, "git", gitArgs...)
+	if err != nil {
+		if message := strings.TrimSpace(string(output)); message != "" {
+			return output, fmt.Errorf("git %s: %w: %s", args[0], err, message)
+		}
+		return output, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return output, nil
+}
+
+// clone clones url into a new temporary directory, which the caller
+// removes, and checks out branch. A branch that does not exist yet, as in
+// a new empty repository, is created by the first push.
+func (g gitClient) clone(ctx context.Context, url, branch string) (string, error) {
+	dir, err := os.MkdirTemp("", "publish-*")
+	if err != nil {
+		return "", err
+	}
+	if err := g.checkout(ctx, dir, url, branch); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+// checkout clones url into dir and checks out branch
+func (g gitClient) checkout(ctx context.Context, dir, url, branch string) error {
+	if _, err := g.git(ctx, "", "clone", url, dir); err != nil {
+		return err
+	}
+	if _, err := g.git(ctx, dir, "rev-parse", "--verify", "--quiet", "refs/remotes/origin/"+branch); err != nil {
+		_, err = g.git(ctx, dir, "symbolic-ref", "HEAD", "refs/heads/"+branch)
+		return err
+	}
+	_, err := g.git(ctx, dir, "checkout", "-B", branch, "origin/"+branch)
+	return err
+}
+
+// commitAndPush commits files of the clone at dir and pushes them to
+// branch. Files that did not change leave the repository as it is.
+func (g gitClient) commitAndPush(ctx context.Context, dir, branch, message string, files ...string) error {
+	if _, err := g.git(ctx, dir, append([]string{"add"}, files...)...); err != nil {
+		return err
+	}
+	changes, err := g.git(ctx, dir, "status", "--porcelain")
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(string(changes)) == "" {
+		return nil // already published
+	}
+
+	if _, err := g.git(ctx, dir, "commit", "-m", message); err != nil {
+		return err
+	}
+	_, err = g.git(ctx, dir, "push", "origin", "HEAD:"+branch)
+	return err
+}
+
+// checkAccess checks that url can be reached, with the SSH key, without
+// cloning it
+func (g gitClient) checkAccess(ctx context.Context, url string) error {
+	_, err := g.git(ctx, "", "ls-remote", "--heads", url)
+	return err
+}
@@ -8,6 +8,9 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -18,6 +21,8 @@ type GitHubPublisher struct {
 	client    *http.Client
 	status    PublishStatus
 	validator *GitHubValidator
+	// published are the releases created, by tag, for a rollback
+	published map[string]*GitHubReleaseResponse
 }
 
 // GitHubConfig contains configuration for GitHub publishing
@@ -61,6 +66,7 @@ type GitHubValidatorConfig struct {
 	CheckAssets     bool `json:"check_assets"`
 	CheckChangelog  bool `json:"check_changelog"`
 	CheckTag        bool `json:"check_tag"`
+	RequireSBOM     bool `json:"require_sbom"`
 	RequiredAssets  []string `json:"required_assets"`
 }
 
@@ -123,6 +129,7 @@ func NewGitHubPublisher(config GitHubConfig) *GitHubPublisher {
 			CheckChangelog: true,
 			CheckTag:       true,
 		}),
+		published: make(map[string]*GitHubReleaseResponse),
 	}
 }
 
@@ -174,24 +181,21 @@ func (ghp *GitHubPublisher) Publish(ctx context.Context, release Release) error
 	}
 	
 	// Generate changelog if configured
-	changelog := release.Changelog
-	if ghp.config.Changelog.AutoGenerate {
-		var err error
-		changelog, err = ghp.generateChangelog(ctx, release)
-		if err != nil {
-			ghp.updateStatus(StatusError, err.Error())
-			return fmt.Errorf("changelog generation failed: %w", err)
-		}
+	changelog, err := ghp.releaseBody(ctx, release)
+	if err != nil {
+		ghp.updateStatus(StatusError, err.Error())
+		return err
 	}
 	
-	// Create GitHub release
+	// Create GitHub release, letting GitHub add its own notes unless the
+	// notes were rendered from a template
 	githubRelease := &GitHubRelease{
 		TagName:       release.Tag,
 		Name:          fmt.Sprintf("Release %s", release.Version),
 		Body:          changelog,
 		Draft:         false,
-		Prerelease:    release.Metadata.IsPrerelease,
-		GenerateNotes: ghp.config.Changelog.AutoGenerate,
+		Prerelease:    IsPrerelease(release),
+		GenerateNotes: ghp.config.Changelog.AutoGenerate && release.Notes == "",
 	}
 	
 	releaseResp, err := ghp.createRelease(ctx, githubRelease)
@@ -199,6 +203,7 @@ func (ghp *GitHubPublisher) Publish(ctx context.Context, release Release) error
 		ghp.updateStatus(StatusError, err.Error())
 		return fmt.Errorf("release creation failed: %w", err)
 	}
+	ghp.published[release.Tag] = releaseResp
 	
 	// Upload assets
 	if err := ghp.uploadAssets(ctx, releaseResp, release); err != nil {
@@ -251,6 +256,14 @@ func (ghv *GitHubValidator) ValidateRelease(ctx context.Context, release Release
 		}
 	}
 	
+	// Check every binary ships with an SBOM
+	if ghv.config.RequireSBOM {
+		if errors := ghv.validateSBOMs(release); len(errors) > 0 {
+			result.Errors = append(result.Errors, errors...)
+			result.Valid = false
+		}
+	}
+	
 	return result, nil
 }
 
@@ -307,6 +320,58 @@ func (ghv *GitHubValidator) validateAssets(release Release) []ValidationError {
 	return warnings
 }
 
+// validateSBOMs checks each binary has an SBOM and that the SBOM files exist
+func (ghv *GitHubValidator) validateSBOMs(release Release) []ValidationError {
+	var errors []ValidationError
+	
+	filenames := make([]string, 0, len(release.Binaries))
+	for filename := range release.Binaries {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+	
+	for _, filename := range filenames {
+		sboms := release.SBOMs[filename]
+		if len(sboms) == 0 {
+			errors = append(errors, ValidationError{
+				Code:     "MISSING_SBOM",
+				Message:  fmt.Sprintf("No SBOM for binary: %s", filename),
+				Field:    "sboms",
+				Severity: "error",
+			})
+			continue
+		}
+		for _, sbom := range sboms {
+			if _, err := os.Stat(sbom); err != nil {
+				errors = append(errors, ValidationError{
+					Code:     "MISSING_SBOM",
+					Message:  fmt.Sprintf("SBOM file for %s not found: %s", filename, sbom),
+					Field:    "sboms",
+					Severity: "error",
+				})
+			}
+		}
+	}
+	
+	return errors
+}
+
+// releaseBody returns the notes of the release: the ones rendered from the
+// release notes template, the generated changelog or the release changelog
+func (ghp *GitHubPublisher) releaseBody(ctx context.Context, release Release) (string, error) {
+	if release.Notes != "" {
+		return release.Notes, nil
+	}
+	if !ghp.config.Changelog.AutoGenerate {
+		return release.Changelog, nil
+	}
+	changelog, err := ghp.generateChangelog(ctx, release)
+	if err != nil {
+		return "", fmt.Errorf("changelog generation failed: %w", err)
+	}
+	return changelog, nil
+}
+
 // generateChangelog generates a changelog for the release
 func (ghp *GitHubPublisher) generateChangelog(ctx context.Context, release Release) (string, error) {
 	var changelog strings.Builder
@@ -446,30 +511,80 @@ func (ghp *GitHubPublisher) uploadAssets(ctx context.Context, release *GitHubRel
 		}
 	}
 	
+	// Upload packages
+	if ghp.config.Assets.IncludeBinaries {
+		for filename, pkg := range releaseData.Packages {
+			if err := ghp.uploadAsset(ctx, release, filename, pkg.FilePath, packageContentType(filename)); err != nil {
+				return fmt.Errorf("failed to upload package %s: %w", filename, err)
+			}
+		}
+	}
+	
 	// Upload checksums
 	if ghp.config.Assets.IncludeChecksums {
-		checksumFile := "checksums.txt"
-		if err := ghp.createChecksumsFile(releaseData, checksumFile); err != nil {
-			return fmt.Errorf("failed to create checksums file: %w", err)
+		// A checksum file written with the release is uploaded as is since
+		// it may be signed
+		checksumFile, exists := releaseData.ChecksumFiles[ChecksumsFile]
+		if !exists {
+			dir, err := os.MkdirTemp("", "nettracex-checksums")
+			if err != nil {
+				return fmt.Errorf("failed to create checksums file: %w", err)
+			}
+			defer os.RemoveAll(dir)
+			checksumFile = filepath.Join(dir, ChecksumsFile)
+			if err := ghp.createChecksumsFile(releaseData, checksumFile); err != nil {
+				return fmt.Errorf("failed to create checksums file: %w", err)
+			}
 		}
 		
-		if err := ghp.uploadAsset(ctx, release, checksumFile, checksumFile, "text/plain"); err != nil {
+		if err := ghp.uploadAsset(ctx, release, ChecksumsFile, checksumFile, "text/plain"); err != nil {
 			return fmt.Errorf("failed to upload checksums: %w", err)
 		}
 	}
 	
+	// Upload SBOMs
+	for filename := range releaseData.Binaries {
+		for _, sbom := range releaseData.SBOMs[filename] {
+			name := filepath.Base(sbom)
+			if err := ghp.uploadAsset(ctx, release, name, sbom, "application/json"); err != nil {
+				return fmt.Errorf("failed to upload SBOM %s: %w", name, err)
+			}
+		}
+	}
+	
+	// Upload signatures
+	for _, filename := range sortedNames(signedFiles(releaseData)) {
+		for _, signature := range releaseData.Signatures[filename] {
+			name := filepath.Base(signature)
+			if err := ghp.uploadAsset(ctx, release, name, signature, signatureContentType(name)); err != nil {
+				return fmt.Errorf("failed to upload signature %s: %w", name, err)
+			}
+		}
+	}
+	
 	return nil
 }
 
+// signatureContentType returns the content type a signature file is
+// uploaded with
+func signatureContentType(name string) string {
+	if strings.HasSuffix(name, GPGSignatureSuffix) {
+		return "application/pgp-signature"
+	}
+	return "text/plain"
+}
+
+// packageContentType returns the content type a package is uploaded with
+func packageContentType(name string) string {
+	if PackageFormatOf(name) == RPM {
+		return "application/x-rpm"
+	}
+	return "application/vnd.debian.binary-package"
+}
+
 // createChecksumsFile creates a checksums file
 func (ghp *GitHubPublisher) createChecksumsFile(release Release, filename string) error {
-	var content strings.Builder
-	
-	for filename, checksum := range release.Checksums {
-		content.WriteString(fmt.Sprintf("%s  %s\n", checksum, filename))
-	}
-	
-	return os.WriteFile(filename, []byte(content.String()), 0644)
+	return WriteChecksumsFile(filename, release.Checksums)
 }
 
 // uploadAsset uploads a single asset to GitHub release
@@ -506,6 +621,182 @@ func (ghp *GitHubPublisher) uploadAsset(ctx context.Context, release *GitHubRele
 	return nil
 }
 
+// DryRun renders the release notes and lists the assets the release would
+// get, checking that the token can push to the repository
+func (ghp *GitHubPublisher) DryRun(ctx context.Context, release Release) (*DryRunReport, error) {
+	repo := ghp.config.Owner + "/" + ghp.config.Repo
+	report := &DryRunReport{Files: make(map[string]string)}
+	if err := ghp.Validate(ctx, release); err != nil {
+		return report, err
+	}
+
+	notes, err := ghp.releaseBody(ctx, release)
+	if err != nil {
+		return report, err
+	}
+	report.Files["release notes"] = notes
+
+	kind := "release"
+	if IsPrerelease(release) {
+		kind = fmt.Sprintf("%s prerelease", ChannelOf(release))
+	}
+	report.Actions = append(report.Actions, fmt.Sprintf("create %s %s on %s", kind, release.Tag, repo))
+	for _, name := range ghp.assetNames(release) {
+		report.Actions = append(report.Actions, "upload "+name)
+	}
+
+	contents := githubContents{baseURL: ghp.config.BaseURL, token: ghp.config.Token, client: ghp.client}
+	if err := contents.checkPush(ctx, repo); err != nil {
+		return report, fmt.Errorf("credential check failed: %w", err)
+	}
+	return report, nil
+}
+
+// assetNames returns the names of the assets uploadAssets uploads, in order
+func (ghp *GitHubPublisher) assetNames(release Release) []string {
+	var names []string
+	if ghp.config.Assets.IncludeBinaries {
+		names = append(names, sortedBinaries(release.Binaries)...)
+		names = append(names, sortedBinaries(release.Packages)...)
+	}
+	if ghp.config.Assets.IncludeChecksums {
+		names = append(names, ChecksumsFile)
+	}
+	for _, filename := range sortedBinaries(release.Binaries) {
+		for _, sbom := range release.SBOMs[filename] {
+			names = append(names, filepath.Base(sbom))
+		}
+	}
+	for _, filename := range sortedNames(signedFiles(release)) {
+		for _, signature := range release.Signatures[filename] {
+			names = append(names, filepath.Base(signature))
+		}
+	}
+	return names
+}
+
+// sortedBinaries returns the names of binaries in order
+func sortedBinaries(binaries map[string]Binary) []string {
+	names := make([]string, 0, len(binaries))
+	for name := range binaries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PublishRecord returns the GitHub release created for a release's tag
+func (ghp *GitHubPublisher) PublishRecord(release Release) map[string]string {
+	created, exists := ghp.published[release.Tag]
+	if !exists {
+		return nil
+	}
+	return map[string]string{
+		"release_id": strconv.FormatInt(created.ID, 10),
+		"url":        created.HTMLURL,
+	}
+}
+
+// Rollback deletes the recorded GitHub release or, with Yank, marks it as a
+// withdrawn prerelease. The tag is kept since the Go module proxy may
+// already serve it.
+func (ghp *GitHubPublisher) Rollback(ctx context.Context, record PublishRecord, options RollbackOptions) (string, error) {
+	id := record.Details["release_id"]
+	if id == "" {
+		return "", fmt.Errorf("no GitHub release recorded for %s", record.Tag)
+	}
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/%s", ghp.config.BaseURL, ghp.config.Owner, ghp.config.Repo, id)
+
+	if options.Yank {
+		return ghp.yankRelease(ctx, url, record, options.Reason)
+	}
+
+	status, body, err := ghp.apiRequest(ctx, "DELETE", url, nil)
+	if err != nil {
+		return "", err
+	}
+	switch status {
+	case http.StatusNoContent:
+		return fmt.Sprintf("deleted GitHub release %s, keeping tag %s", record.Details["url"], record.Tag), nil
+	case http.StatusNotFound:
+		return fmt.Sprintf("GitHub release %s was already deleted", record.Tag), nil
+	}
+	return "", fmt.Errorf("GitHub API error: %s", string(body))
+}
+
+// yankRelease marks a release as a withdrawn prerelease, so it is no longer
+// the latest release, and notes why in its description
+func (ghp *GitHubPublisher) yankRelease(ctx context.Context, url string, record PublishRecord, reason string) (string, error) {
+	status, body, err := ghp.apiRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("GitHub API error: %s", string(body))
+	}
+	var current GitHubReleaseResponse
+	if err := json.Unmarshal(body, &current); err != nil {
+		return "", err
+	}
+
+	notice := "**This release has been withdrawn.**"
+	if reason != "" {
+		notice = fmt.Sprintf("**This release has been withdrawn:** %s", reason)
+	}
+	update := map[string]interface{}{
+		"prerelease":  true,
+		"make_latest": "false",
+	}
+	if !strings.HasSuffix(current.Name, " (withdrawn)") {
+		update["name"] = current.Name + " (withdrawn)"
+		update["body"] = notice + "\n\n" + current.Body
+	}
+
+	status, body, err = ghp.apiRequest(ctx, "PATCH", url, update)
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("GitHub API error: %s", string(body))
+	}
+	return fmt.Sprintf("marked GitHub release %s as withdrawn", record.Details["url"]), nil
+}
+
+// apiRequest sends a GitHub API request, returning the status and body of
+// the response
+func (ghp *GitHubPublisher) apiRequest(ctx context.Context, method, url string, payload interface{}) (int, []byte, error) {
+	var reader io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return 0, nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Authorization", "token "+ghp.config.Token)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := ghp.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, body, nil
+}
+
 // updateStatus updates the publisher status
 func (ghp *GitHubPublisher) updateStatus(status StatusType, errorMsg string) {
 	ghp.status.Status = status
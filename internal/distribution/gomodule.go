@@ -19,6 +19,8 @@ type GoModulePublisher struct {
 	client     *http.Client
 	status     PublishStatus
 	validator  *GoModuleValidator
+	// tagged are the tags pushed, for a rollback
+	tagged     map[string]bool
 	
 	// Function fields for testing
 	createGitTag              func(ctx context.Context, release Release) error
@@ -88,6 +90,7 @@ func NewGoModulePublisher(config GoModuleConfig) *GoModulePublisher {
 			CheckDocumentation: true,
 			MinCoverage:        80.0,
 		}),
+		tagged: make(map[string]bool),
 	}
 	
 	// Set default implementations
@@ -140,6 +143,27 @@ func (gmp *GoModulePublisher) Validate(ctx context.Context, release Release) err
 	return nil
 }
 
+// DryRun validates the module and lists what publishing would do. The proxy
+// is not asked for the version, since it would cache that it is missing.
+func (gmp *GoModulePublisher) DryRun(ctx context.Context, release Release) (*DryRunReport, error) {
+	report := &DryRunReport{}
+	if err := gmp.Validate(ctx, release); err != nil {
+		return report, err
+	}
+	
+	docs := gmp.config.Documentation
+	if docs.GenerateReadme {
+		report.Actions = append(report.Actions, "update README.md")
+	}
+	if docs.GenerateExamples {
+		report.Actions = append(report.Actions, "generate examples")
+	}
+	report.Actions = append(report.Actions,
+		fmt.Sprintf("create and push git tag %s", release.Tag),
+		fmt.Sprintf("request %s/%s/@v/%s.info", gmp.config.ProxyURL, gmp.config.ModulePath, release.Version))
+	return report, nil
+}
+
 // Publish publishes the Go module to pkg.go.dev
 func (gmp *GoModulePublisher) Publish(ctx context.Context, release Release) error {
 	gmp.updateStatus(StatusPublishing, "")
@@ -160,6 +184,7 @@ func (gmp *GoModulePublisher) Publish(ctx context.Context, release Release) erro
 		gmp.updateStatus(StatusError, err.Error())
 		return fmt.Errorf("git tag creation failed: %w", err)
 	}
+	gmp.tagged[release.Tag] = true
 	
 	// Trigger module proxy update
 	if err := gmp.triggerProxyUpdate(ctx, release); err != nil {
@@ -177,6 +202,29 @@ func (gmp *GoModulePublisher) Publish(ctx context.Context, release Release) erro
 	return nil
 }
 
+// PublishRecord returns the module version a release tagged
+func (gmp *GoModulePublisher) PublishRecord(release Release) map[string]string {
+	if !gmp.tagged[release.Tag] {
+		return nil
+	}
+	return map[string]string{
+		"module":  gmp.config.ModulePath,
+		"version": release.Version,
+	}
+}
+
+// Rollback returns how to retract the module version. The module proxy and
+// checksum database keep every version they fetched, so a version can only
+// be retracted by a later one.
+func (gmp *GoModulePublisher) Rollback(ctx context.Context, record PublishRecord, options RollbackOptions) (string, error) {
+	directive := "retract " + record.Details["version"]
+	if options.Reason != "" {
+		directive += " // " + options.Reason
+	}
+	return fmt.Sprintf("%s@%s cannot be removed from the module proxy: add %q to go.mod and release a newer version to retract it",
+		record.Details["module"], record.Details["version"], directive), nil
+}
+
 // defaultValidateRelease validates a release for Go module publishing
 func (gmv *GoModuleValidator) defaultValidateRelease(ctx context.Context, release Release) (*PackageValidationResult, error) {
 	result := &PackageValidationResult{
@@ -3,17 +3,18 @@ package distribution
 import (
 	"bytes"
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"text/template"
 	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/update"
 )
 
 // HomebrewPublisher manages Homebrew formula creation and publishing
@@ -22,6 +23,8 @@ type HomebrewPublisher struct {
 	validator *HomebrewValidator
 	client    *http.Client
 	status    PublishStatus
+	// published records the formula written for each version, for a rollback
+	published map[string]map[string]string
 }
 
 // HomebrewConfig contains Homebrew publishing configuration
@@ -35,11 +38,22 @@ type HomebrewConfig struct {
 	TestCommand  string `json:"test_command"`
 	Dependencies []string `json:"dependencies"`
 	CustomTap    bool   `json:"custom_tap"`    // true for custom tap, false for homebrew-core
+	// HeadURL is the git repository `brew install --HEAD` builds from
+	HeadURL      string `json:"head_url,omitempty"`
+	HeadBranch   string `json:"head_branch,omitempty"` // "main" by default
 }
 
 // HomebrewFormula represents a complete Homebrew formula
 type HomebrewFormula struct {
+	// Name is the name of the formula file, such as nettracex@beta for
+	// the beta channel
+	Name         string            `json:"name"`
 	Class        string            `json:"class"`
+	// Command is the name the binary is installed as
+	Command      string            `json:"command"`
+	ConflictsWith []string         `json:"conflicts_with,omitempty"`
+	HeadURL      string            `json:"head_url,omitempty"`
+	HeadBranch   string            `json:"head_branch,omitempty"`
 	Description  string            `json:"description"`
 	Homepage     string            `json:"homepage"`
 	URL          string            `json:"url"`
@@ -55,8 +69,9 @@ type HomebrewFormula struct {
 
 // PlatformBinary represents a platform-specific binary
 type PlatformBinary struct {
-	URL    string `json:"url"`
-	SHA256 string `json:"sha256"`
+	URL      string `json:"url"`
+	SHA256   string `json:"sha256"`
+	Filename string `json:"filename,omitempty"`
 }
 
 // HomebrewValidator validates Homebrew formulas
@@ -79,6 +94,7 @@ func NewHomebrewPublisher(config HomebrewConfig) (*HomebrewPublisher, error) {
 			Name:   "homebrew",
 			Status: StatusIdle,
 		},
+		published: make(map[string]map[string]string),
 	}, nil
 }
 
@@ -100,6 +116,15 @@ func (p *HomebrewPublisher) GetName() string {
 	return "homebrew"
 }
 
+// DefaultChannels ships every channel to a custom tap, prereleases as
+// versioned formulae, and only stable releases to homebrew-core
+func (p *HomebrewPublisher) DefaultChannels() []update.Channel {
+	if p.config.CustomTap {
+		return update.Channels
+	}
+	return []update.Channel{update.Stable}
+}
+
 // Publish publishes a release to Homebrew
 func (p *HomebrewPublisher) Publish(ctx context.Context, release Release) error {
 	p.updateStatus(StatusPublishing, "")
@@ -141,6 +166,35 @@ func (p *HomebrewPublisher) Publish(ctx context.Context, release Release) error
 	return nil
 }
 
+// DryRun renders the formula and reports where it would be submitted
+func (p *HomebrewPublisher) DryRun(ctx context.Context, release Release) (*DryRunReport, error) {
+	report := &DryRunReport{Files: make(map[string]string)}
+	if err := p.Validate(ctx, release); err != nil {
+		return report, err
+	}
+
+	formula, err := p.GenerateFormula(release)
+	if err != nil {
+		return report, fmt.Errorf("failed to generate formula: %w", err)
+	}
+	if err := p.validator.ValidateFormula(formula); err != nil {
+		return report, fmt.Errorf("formula validation failed: %w", err)
+	}
+	content, err := p.RenderFormula(formula)
+	if err != nil {
+		return report, fmt.Errorf("failed to render formula: %w", err)
+	}
+
+	name := formula.Name + ".rb"
+	report.Files[name] = content
+	if p.config.CustomTap {
+		report.Actions = append(report.Actions, fmt.Sprintf("write %s for the custom tap", filepath.Join("homebrew-formula", name)))
+	} else {
+		report.Actions = append(report.Actions, fmt.Sprintf("submit %s to homebrew-core", name))
+	}
+	return report, nil
+}
+
 // Validate validates a release for Homebrew publishing
 func (p *HomebrewPublisher) Validate(ctx context.Context, release Release) error {
 	// Check for supported platform binaries (macOS and Linux)
@@ -219,8 +273,9 @@ func (p *HomebrewPublisher) GenerateFormula(release Release) (*HomebrewFormula,
 			}
 			
 			platformURLs[key] = PlatformBinary{
-				URL:    binary.DownloadURL,
-				SHA256: sha256Hash,
+				URL:      binary.DownloadURL,
+				SHA256:   sha256Hash,
+				Filename: binary.Filename,
 			}
 		}
 	}
@@ -259,11 +314,12 @@ func (p *HomebrewPublisher) GenerateFormula(release Release) (*HomebrewFormula,
 		return nil, fmt.Errorf("no primary binary found")
 	}
 
-	// Generate class name (capitalize first letter)
-	className := strings.Title(strings.ToLower(p.config.FormulaName))
-
 	formula := &HomebrewFormula{
-		Class:        className,
+		Name:         p.formulaName(release),
+		Class:        formulaClass(p.formulaName(release)),
+		Command:      strings.ToLower(p.config.FormulaName),
+		HeadURL:      p.config.HeadURL,
+		HeadBranch:   p.config.HeadBranch,
 		Description:  p.config.Description,
 		Homepage:     p.config.Homepage,
 		URL:          primaryBinary.DownloadURL,
@@ -281,28 +337,45 @@ func (p *HomebrewPublisher) GenerateFormula(release Release) (*HomebrewFormula,
 			"supported_count":  fmt.Sprintf("%d", len(supportedBinaries)),
 		},
 	}
+	if formula.Name != formula.Command {
+		formula.ConflictsWith = []string{formula.Command}
+	}
+	if formula.HeadURL != "" && formula.HeadBranch == "" {
+		formula.HeadBranch = "main"
+	}
 
 	return formula, nil
 }
 
-// calculateSHA256 downloads a file and calculates its SHA256 hash
-func (p *HomebrewPublisher) calculateSHA256(url string) (string, error) {
-	resp, err := p.client.Get(url)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to download file: %s", resp.Status)
+// formulaName returns the name of the formula of a release: the formula of
+// a stable release, or a versioned formula such as nettracex@beta for a
+// prerelease, so prereleases install side by side with the stable formula
+func (p *HomebrewPublisher) formulaName(release Release) string {
+	name := strings.ToLower(p.config.FormulaName)
+	if channel := ChannelOf(release); channel != update.Stable {
+		name += "@" + string(channel)
 	}
+	return name
+}
 
-	hash := sha256.New()
-	if _, err := io.Copy(hash, resp.Body); err != nil {
-		return "", err
+// formulaClass returns the class of a formula, as Homebrew names it:
+// nettracex@beta is NettracexATBeta
+func formulaClass(name string) string {
+	var class strings.Builder
+	for i, part := range strings.Split(name, "@") {
+		if i > 0 {
+			class.WriteString("AT")
+		}
+		for _, word := range strings.FieldsFunc(part, func(r rune) bool { return r == '-' || r == '_' }) {
+			class.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
 	}
+	return class.String()
+}
 
-	return hex.EncodeToString(hash.Sum(nil)), nil
+// calculateSHA256 downloads a file and calculates its SHA256 hash
+func (p *HomebrewPublisher) calculateSHA256(url string) (string, error) {
+	return downloadSHA256(p.client, url)
 }
 
 // generateTestBlock creates the test block for the formula
@@ -313,87 +386,194 @@ func (p *HomebrewPublisher) generateTestBlock() string {
 	return fmt.Sprintf(`system "#{bin}/%s", "--version"`, p.config.FormulaName)
 }
 
-// RenderFormula renders the formula to Ruby code
-func (p *HomebrewPublisher) RenderFormula(formula *HomebrewFormula) (string, error) {
-	// Check if we have multiple platforms
-	hasMultiplePlatforms := len(formula.PlatformURLs) > 1
-	
-	var tmpl string
-	if hasMultiplePlatforms {
-		// Multi-platform template
-		tmpl = `class {{ .Class }} < Formula
+// formulaTemplate renders a formula with its stanzas in the order brew
+// audit expects. Formulae with platform downloads get an on_macos and an
+// on_linux block holding an on_intel and an on_arm download each.
+const formulaTemplate = `class {{ .Class }} < Formula
   desc "{{ .Description }}"
   homepage "{{ .Homepage }}"
-  license "{{ .License }}"
+{{- if not .PlatformURLs }}
+  url "{{ .URL }}"
+{{- end }}
   version "{{ .Version }}"
+{{- if not .PlatformURLs }}
+  sha256 "{{ .SHA256 }}"
+{{- end }}
+  license "{{ .License }}"
+{{- if .HeadURL }}
 
-{{- if .Dependencies }}
-{{- range .Dependencies }}
+  head do
+    url "{{ .HeadURL }}", branch: "{{ .HeadBranch }}"
+    depends_on "go" => :build
+  end
+{{- end }}
+{{- if or .Dependencies .OnlyOS }}
+{{ range .Dependencies }}
   depends_on "{{ . }}"
 {{- end }}
+{{- with .OnlyOS }}
+  depends_on :{{ . }}
+{{- end }}
+{{- end }}
+{{- range .Platforms }}
+
+  on_{{ .OS }} do
+{{- range .Downloads }}
+{{- if .CPU }}
+    on_{{ .CPU }} do
+      url "{{ .URL }}"
+      sha256 "{{ .SHA256 }}"
+    end
+{{- else }}
+    url "{{ .URL }}"
+    sha256 "{{ .SHA256 }}"
+{{- end }}
 {{- end }}
-
-{{- range $key, $binary := .PlatformURLs }}
-{{- if eq $key "darwin-amd64" }}
-  if OS.mac? && Hardware::CPU.intel?
-    url "{{ $binary.URL }}"
-    sha256 "{{ $binary.SHA256 }}"
-  end
-{{- else if eq $key "darwin-arm64" }}
-  if OS.mac? && Hardware::CPU.arm?
-    url "{{ $binary.URL }}"
-    sha256 "{{ $binary.SHA256 }}"
-  end
-{{- else if eq $key "linux-amd64" }}
-  if OS.linux? && Hardware::CPU.intel?
-    url "{{ $binary.URL }}"
-    sha256 "{{ $binary.SHA256 }}"
-  end
-{{- else if eq $key "linux-arm64" }}
-  if OS.linux? && Hardware::CPU.arm?
-    url "{{ $binary.URL }}"
-    sha256 "{{ $binary.SHA256 }}"
   end
 {{- end }}
+{{- range .ConflictsWith }}
+
+  conflicts_with "{{ . }}", because: "both install the {{ . }} command"
 {{- end }}
 
   def install
-    bin.install "{{ .Metadata.binary_name }}" => "{{ .Class | lower }}"
-  end
-
-  test do
-    {{ .TestBlock }}
-  end
-end`
-	} else {
-		// Single platform template (backward compatibility)
-		tmpl = `class {{ .Class }} < Formula
-  desc "{{ .Description }}"
-  homepage "{{ .Homepage }}"
-  url "{{ .URL }}"
-  sha256 "{{ .SHA256 }}"
-  license "{{ .License }}"
-  version "{{ .Version }}"
+{{- if .HeadURL }}
+    if build.head?
+      system "go", "build", *std_go_args(output: bin/"{{ .Command }}", ldflags: "-s -w")
+      return
+    end
 
-{{- if .Dependencies }}
-{{- range .Dependencies }}
-  depends_on "{{ . }}"
+{{- end }}
+{{- with .SharedInstall }}
+    bin.install "{{ . }}" => "{{ $.Command }}"
+{{- else }}
+{{- range .Platforms }}
+    on_{{ .OS }} do
+{{- range .Downloads }}
+{{- if .CPU }}
+      on_{{ .CPU }} do
+        bin.install "{{ .Install }}" => "{{ $.Command }}"
+      end
+{{- else }}
+      bin.install "{{ .Install }}" => "{{ $.Command }}"
+{{- end }}
+{{- end }}
+    end
 {{- end }}
 {{- end }}
-
-  def install
-    bin.install "{{ .Metadata.binary_name }}" => "{{ .Class | lower }}"
   end
 
   test do
     {{ .TestBlock }}
   end
-end`
+end
+`
+
+// HomebrewPlatform is the on_macos or on_linux block of a formula
+type HomebrewPlatform struct {
+	OS        string // macos or linux
+	Downloads []HomebrewDownload
+}
+
+// HomebrewDownload is a download of a platform block
+type HomebrewDownload struct {
+	// CPU is intel or arm, or empty when the download serves every CPU
+	CPU     string
+	URL     string
+	SHA256  string
+	Install string // the downloaded file installed as the command
+}
+
+// formulaPlatforms are the platforms formulae are written for, in the order
+// their blocks are written, with the on_ block and CPU of each
+var formulaPlatforms = []struct {
+	Key, OS, CPU string
+}{
+	{"darwin-amd64", "macos", "intel"},
+	{"darwin-arm64", "macos", "arm"},
+	{"linux-amd64", "linux", "intel"},
+	{"linux-arm64", "linux", "arm"},
+}
+
+// Platforms returns the platform blocks of the formula. A macOS block with
+// only an Intel download serves Apple silicon too, through Rosetta.
+func (f *HomebrewFormula) Platforms() []HomebrewPlatform {
+	var platforms []HomebrewPlatform
+	for _, platform := range formulaPlatforms {
+		binary, exists := f.PlatformURLs[platform.Key]
+		if !exists {
+			continue
+		}
+		if len(platforms) == 0 || platforms[len(platforms)-1].OS != platform.OS {
+			platforms = append(platforms, HomebrewPlatform{OS: platform.OS})
+		}
+		block := &platforms[len(platforms)-1]
+		block.Downloads = append(block.Downloads, HomebrewDownload{
+			CPU:     platform.CPU,
+			URL:     binary.URL,
+			SHA256:  binary.SHA256,
+			Install: f.installName(binary.Filename),
+		})
+	}
+	for i, platform := range platforms {
+		if platform.OS == "macos" && len(platform.Downloads) == 1 && platform.Downloads[0].CPU == "intel" {
+			platforms[i].Downloads[0].CPU = ""
+		}
+	}
+	return platforms
+}
+
+// OnlyOS returns macos or linux when the formula only has downloads for
+// that system, which it then depends on
+func (f *HomebrewFormula) OnlyOS() string {
+	if platforms := f.Platforms(); len(platforms) == 1 {
+		return platforms[0].OS
+	}
+	return ""
+}
+
+// SharedInstall returns the file every download installs as the command,
+// or an empty string when the downloads are named apart
+func (f *HomebrewFormula) SharedInstall() string {
+	platforms := f.Platforms()
+	if len(platforms) == 0 {
+		return f.installName("")
+	}
+	shared := platforms[0].Downloads[0].Install
+	for _, platform := range platforms {
+		for _, download := range platform.Downloads {
+			if download.Install != shared {
+				return ""
+			}
+		}
+	}
+	return shared
+}
+
+// installName returns the file a download is installed from: the download
+// itself, or the command inside it when it is an archive
+func (f *HomebrewFormula) installName(filename string) string {
+	if filename == "" {
+		filename = f.Metadata["binary_name"]
+	}
+	for _, suffix := range []string{".tar.gz", ".tgz", ".zip"} {
+		if strings.HasSuffix(filename, suffix) {
+			return f.Command
+		}
+	}
+	return filename
+}
+
+// RenderFormula renders the formula to Ruby code
+func (p *HomebrewPublisher) RenderFormula(formula *HomebrewFormula) (string, error) {
+	// Formulae built by hand install the binary under their class name
+	if formula.Command == "" {
+		named := *formula
+		named.Command = strings.ToLower(formula.Class)
+		formula = &named
 	}
 
-	t, err := template.New("formula").Funcs(template.FuncMap{
-		"lower": strings.ToLower,
-	}).Parse(tmpl)
+	t, err := template.New("formula").Parse(formulaTemplate)
 	if err != nil {
 		return "", err
 	}
@@ -437,21 +617,93 @@ func (v *HomebrewValidator) ValidateFormula(formula *HomebrewFormula) error {
 		return fmt.Errorf("formula license is required")
 	}
 
-	// Validate URL is accessible
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Head(formula.URL)
-	if err != nil {
-		return fmt.Errorf("formula URL not accessible: %w", err)
+	if err := auditDescription(formula); err != nil {
+		return err
+	}
+
+	urls := []string{formula.URL}
+	for _, key := range sortedPlatformKeys(formula.PlatformURLs) {
+		binary := formula.PlatformURLs[key]
+		if !isFormulaPlatform(key) {
+			return fmt.Errorf("unsupported formula platform %s", key)
+		}
+		if binary.URL == "" {
+			return fmt.Errorf("formula URL for %s is required", key)
+		}
+		if !sha256Pattern.MatchString(binary.SHA256) {
+			return fmt.Errorf("invalid SHA256 hash for %s", key)
+		}
+		if binary.URL != formula.URL {
+			urls = append(urls, binary.URL)
+		}
 	}
-	resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("formula URL returned status: %s", resp.Status)
+	// Validate URLs are accessible
+	client := &http.Client{Timeout: 10 * time.Second}
+	for _, url := range urls {
+		resp, err := client.Head(url)
+		if err != nil {
+			return fmt.Errorf("formula URL not accessible: %w", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("formula URL returned status: %s", resp.Status)
+		}
 	}
 
 	return nil
 }
 
+// sha256Pattern matches the lowercase hex SHA-256 brew audit accepts
+var sha256Pattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// auditDescription applies the rules brew audit has for descriptions
+func auditDescription(formula *HomebrewFormula) error {
+	desc := formula.Description
+	if len(desc) > 80 {
+		return fmt.Errorf("formula description is %d characters long, brew audit allows 80", len(desc))
+	}
+	if strings.HasSuffix(desc, ".") {
+		return fmt.Errorf("formula description should not end with a full stop")
+	}
+	first, _, _ := strings.Cut(strings.ToLower(desc), " ")
+	switch first {
+	case "a", "an", "the":
+		return fmt.Errorf("formula description should not start with an article")
+	}
+	name := formula.Command
+	if name == "" {
+		name = strings.ToLower(formula.Class)
+	}
+	if first == name {
+		return fmt.Errorf("formula description should not start with the formula name")
+	}
+	return nil
+}
+
+// isFormulaPlatform reports whether a formula can have a download for a
+// platform
+func isFormulaPlatform(key string) bool {
+	for _, platform := range formulaPlatforms {
+		if platform.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedPlatformKeys returns the platforms of the downloads of a formula in
+// order
+func sortedPlatformKeys(platforms map[string]PlatformBinary) []string {
+	keys := make([]string, 0, len(platforms))
+	for key := range platforms {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // testFormulaInstallation tests the formula installation process
 func (p *HomebrewPublisher) testFormulaInstallation(ctx context.Context, formulaContent string) error {
 	if p.validator.brewPath == "" {
@@ -479,6 +731,32 @@ func (p *HomebrewPublisher) testFormulaInstallation(ctx context.Context, formula
 	return nil
 }
 
+// PublishRecord returns the formula written for a release to a custom tap
+func (p *HomebrewPublisher) PublishRecord(release Release) map[string]string {
+	return p.published[release.Version]
+}
+
+// Rollback restores the formula the release replaced, or removes the
+// formula when the release added it
+func (p *HomebrewPublisher) Rollback(ctx context.Context, record PublishRecord, options RollbackOptions) (string, error) {
+	formulaFile := record.Details["formula_path"]
+	if formulaFile == "" {
+		return "the formula was submitted to homebrew-core, close its pull request by hand", nil
+	}
+
+	if previous, exists := record.Details["previous_formula"]; exists {
+		if err := os.WriteFile(formulaFile, []byte(previous), 0644); err != nil {
+			return "", fmt.Errorf("failed to restore formula: %w", err)
+		}
+		return fmt.Sprintf("restored the previous formula at %s", formulaFile), nil
+	}
+
+	if err := os.Remove(formulaFile); err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to remove formula: %w", err)
+	}
+	return fmt.Sprintf("removed the formula at %s", formulaFile), nil
+}
+
 // submitFormula submits the formula to the appropriate repository
 func (p *HomebrewPublisher) submitFormula(ctx context.Context, formula *HomebrewFormula, content string) (string, error) {
 	if p.config.CustomTap {
@@ -497,7 +775,22 @@ func (p *HomebrewPublisher) submitToCustomTap(ctx context.Context, formula *Home
 		return "", fmt.Errorf("failed to create formula directory: %w", err)
 	}
 
-	formulaFile := filepath.Join(formulaDir, fmt.Sprintf("%s.rb", strings.ToLower(p.config.FormulaName)))
+	name := formula.Name
+	if name == "" {
+		name = strings.ToLower(p.config.FormulaName)
+	}
+	formulaFile := filepath.Join(formulaDir, name+".rb")
+
+	// Keep the formula being replaced, once per version so a retry does not
+	// record its own formula as the previous one
+	if _, exists := p.published[formula.Version]; !exists {
+		record := map[string]string{"formula_path": formulaFile}
+		if previous, err := os.ReadFile(formulaFile); err == nil {
+			record["previous_formula"] = string(previous)
+		}
+		p.published[formula.Version] = record
+	}
+
 	if err := os.WriteFile(formulaFile, []byte(content), 0644); err != nil {
 		return "", fmt.Errorf("failed to write formula file: %w", err)
 	}
@@ -327,6 +327,111 @@ func TestHomebrewPublisher_RenderFormula(t *testing.T) {
 	}
 }
 
+func TestHomebrewPublisher_RenderFormula_MultiPlatform(t *testing.T) {
+	publisher, _ := NewHomebrewPublisher(HomebrewConfig{
+		FormulaName: "nettracex",
+		Description: "Network diagnostic toolkit",
+		Homepage:    "https://github.com/test/nettracex",
+		License:     "MIT",
+	})
+
+	binaries := map[string]Binary{}
+	for _, key := range []string{"darwin-amd64", "darwin-arm64", "linux-amd64", "linux-arm64"} {
+		platform, arch, _ := strings.Cut(key, "-")
+		binaries[key] = Binary{
+			Platform:     platform,
+			Architecture: arch,
+			Filename:     "nettracex-" + key,
+			DownloadURL:  "https://github.com/test/nettracex/releases/download/v1.0.0/nettracex-" + key,
+			Checksum:     strings.Repeat(string(key[0]), 64),
+		}
+	}
+	formula, err := publisher.GenerateFormula(Release{Version: "1.0.0", Binaries: binaries})
+	if err != nil {
+		t.Fatalf("Failed to generate formula: %v", err)
+	}
+	content, err := publisher.RenderFormula(formula)
+	if err != nil {
+		t.Fatalf("Failed to render formula: %v", err)
+	}
+
+	expected := `  on_macos do
+    on_intel do
+      url "https://github.com/test/nettracex/releases/download/v1.0.0/nettracex-darwin-amd64"
+      sha256 "` + strings.Repeat("d", 64) + `"
+    end
+    on_arm do
+      url "https://github.com/test/nettracex/releases/download/v1.0.0/nettracex-darwin-arm64"
+      sha256 "` + strings.Repeat("d", 64) + `"
+    end
+  end
+
+  on_linux do
+    on_intel do
+      url "https://github.com/test/nettracex/releases/download/v1.0.0/nettracex-linux-amd64"
+      sha256 "` + strings.Repeat("l", 64) + `"
+    end
+    on_arm do
+      url "https://github.com/test/nettracex/releases/download/v1.0.0/nettracex-linux-arm64"
+      sha256 "` + strings.Repeat("l", 64) + `"
+    end
+  end
+
+  def install
+    on_macos do
+      on_intel do
+        bin.install "nettracex-darwin-amd64" => "nettracex"
+      end
+      on_arm do
+        bin.install "nettracex-darwin-arm64" => "nettracex"
+      end
+    end
+    on_linux do
+      on_intel do
+        bin.install "nettracex-linux-amd64" => "nettracex"
+      end
+      on_arm do
+        bin.install "nettracex-linux-arm64" => "nettracex"
+      end
+    end
+  end
+`
+	if !strings.Contains(content, expected) {
+		t.Errorf("Expected per-platform blocks:\n%s\nFormula content:\n%s", expected, content)
+	}
+	if strings.Contains(content, "OS.mac?") || strings.Contains(content, "depends_on :") {
+		t.Errorf("Expected no OS conditionals or system dependency:\n%s", content)
+	}
+
+	// Stanzas come in the order brew audit expects
+	last := -1
+	for _, stanza := range []string{"desc ", "homepage ", "version ", "license ", "on_macos do", "on_linux do", "def install", "test do"} {
+		index := strings.Index(content, "  "+stanza)
+		if index < last {
+			t.Errorf("Expected %q after the previous stanzas:\n%s", stanza, content)
+		}
+		last = index
+	}
+
+	// A single Intel macOS download serves every Mac, and archives install
+	// the command inside them
+	formula, err = publisher.GenerateFormula(Release{Version: "1.0.0", Binaries: map[string]Binary{
+		"darwin-amd64": {Platform: "darwin", Architecture: "amd64", Filename: "nettracex_Darwin_x86_64.tar.gz", DownloadURL: "https://example.com/mac.tar.gz", Checksum: strings.Repeat("a", 64)},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to generate formula: %v", err)
+	}
+	content, _ = publisher.RenderFormula(formula)
+	for _, element := range []string{"  depends_on :macos\n", "  on_macos do\n    url \"https://example.com/mac.tar.gz\"", `bin.install "nettracex" => "nettracex"`} {
+		if !strings.Contains(content, element) {
+			t.Errorf("Expected formula to contain %q:\n%s", element, content)
+		}
+	}
+	if strings.Contains(content, "on_intel") {
+		t.Errorf("Expected no on_intel block for a single macOS download:\n%s", content)
+	}
+}
+
 func TestHomebrewValidator_ValidateFormula(t *testing.T) {
 	validator, _ := NewHomebrewValidator()
 
@@ -408,6 +513,109 @@ func TestHomebrewValidator_ValidateFormula(t *testing.T) {
 			wantErr: true,
 			errMsg:  "invalid SHA256 hash length",
 		},
+		{
+			name: "description starting with an article",
+			formula: HomebrewFormula{
+				Class:       "Nettracex",
+				Description: "A network diagnostic toolkit",
+				Homepage:    "https://github.com/test/nettracex",
+				URL:         server.URL + "/valid",
+				SHA256:      "abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
+				License:     "MIT",
+			},
+			wantErr: true,
+			errMsg:  "should not start with an article",
+		},
+		{
+			name: "description ending with a full stop",
+			formula: HomebrewFormula{
+				Class:       "Nettracex",
+				Description: "Network diagnostic toolkit.",
+				Homepage:    "https://github.com/test/nettracex",
+				URL:         server.URL + "/valid",
+				SHA256:      "abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
+				License:     "MIT",
+			},
+			wantErr: true,
+			errMsg:  "should not end with a full stop",
+		},
+		{
+			name: "description starting with the formula name",
+			formula: HomebrewFormula{
+				Class:       "Nettracex",
+				Description: "NetTraceX is a network diagnostic toolkit",
+				Homepage:    "https://github.com/test/nettracex",
+				URL:         server.URL + "/valid",
+				SHA256:      "abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
+				License:     "MIT",
+			},
+			wantErr: true,
+			errMsg:  "should not start with the formula name",
+		},
+		{
+			name: "valid platform downloads",
+			formula: HomebrewFormula{
+				Class:       "Nettracex",
+				Description: "Network diagnostic toolkit",
+				Homepage:    "https://github.com/test/nettracex",
+				URL:         server.URL + "/valid",
+				SHA256:      "abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
+				License:     "MIT",
+				PlatformURLs: map[string]PlatformBinary{
+					"darwin-amd64": {URL: server.URL + "/valid", SHA256: "abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890"},
+					"linux-arm64":  {URL: server.URL + "/valid", SHA256: "abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unsupported platform download",
+			formula: HomebrewFormula{
+				Class:       "Nettracex",
+				Description: "Network diagnostic toolkit",
+				Homepage:    "https://github.com/test/nettracex",
+				URL:         server.URL + "/valid",
+				SHA256:      "abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
+				License:     "MIT",
+				PlatformURLs: map[string]PlatformBinary{
+					"freebsd-amd64": {URL: server.URL + "/valid", SHA256: "abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890"},
+				},
+			},
+			wantErr: true,
+			errMsg:  "unsupported formula platform freebsd-amd64",
+		},
+		{
+			name: "uppercase platform SHA256",
+			formula: HomebrewFormula{
+				Class:       "Nettracex",
+				Description: "Network diagnostic toolkit",
+				Homepage:    "https://github.com/test/nettracex",
+				URL:         server.URL + "/valid",
+				SHA256:      "abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
+				License:     "MIT",
+				PlatformURLs: map[string]PlatformBinary{
+					"darwin-arm64": {URL: server.URL + "/valid", SHA256: strings.ToUpper("abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890")},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid SHA256 hash for darwin-arm64",
+		},
+		{
+			name: "inaccessible platform download",
+			formula: HomebrewFormula{
+				Class:       "Nettracex",
+				Description: "Network diagnostic toolkit",
+				Homepage:    "https://github.com/test/nettracex",
+				URL:         server.URL + "/valid",
+				SHA256:      "abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
+				License:     "MIT",
+				PlatformURLs: map[string]PlatformBinary{
+					"linux-amd64": {URL: server.URL + "/invalid", SHA256: "abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890"},
+				},
+			},
+			wantErr: true,
+			errMsg:  "URL returned status",
+		},
 	}
 
 	for _, tt := range tests {
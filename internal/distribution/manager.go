@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 	"sync"
 	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/update"
 )
 
 // DistributionCoordinator manages cross-platform package publishing
@@ -13,6 +16,8 @@ type DistributionCoordinator struct {
 	publishers map[string]Publisher
 	validators map[string]Validator
 	notifier   NotificationService
+	signers    map[string][]Signer
+	store      *ReleaseStore
 	config     *DistributionConfig
 	mu         sync.RWMutex
 }
@@ -45,6 +50,8 @@ type DistributionConfig struct {
 	Notifications   NotificationConfig         `json:"notifications"`
 	RetryPolicy     RetryPolicy                `json:"retry_policy"`
 	ConcurrentLimit int                        `json:"concurrent_limit"`
+	// ReleaseNotes renders the notes every publisher ships from a template
+	ReleaseNotes ReleaseNotesConfig `json:"release_notes"`
 }
 
 // PublisherConfig contains publisher-specific configuration
@@ -54,6 +61,18 @@ type PublisherConfig struct {
 	Timeout    time.Duration          `json:"timeout"`
 	RetryCount int                    `json:"retry_count"`
 	Config     map[string]interface{} `json:"config"`
+	Signing    SigningConfig          `json:"signing"`
+	// DependsOn names the publishers that must succeed before this one
+	// runs, such as GitHub for publishers that download its assets
+	DependsOn  []string               `json:"depends_on,omitempty"`
+	// Stage is the rollout stage of the publisher, lowest first
+	Stage      int                    `json:"stage,omitempty"`
+	// NotesTemplate is the file of the release notes template of the
+	// publisher, in place of the one of ReleaseNotes
+	NotesTemplate string `json:"notes_template,omitempty"`
+	// Channels are the release channels the publisher ships, by default
+	// all of them or the ones the publisher picks
+	Channels []update.Channel `json:"channels,omitempty"`
 }
 
 // ValidatorConfig contains validator-specific configuration
@@ -68,6 +87,12 @@ type NotificationConfig struct {
 	Channels []string `json:"channels"`
 	OnError  bool     `json:"on_error"`
 	OnSuccess bool    `json:"on_success"`
+	// Settings configures the selected channels by name; a channel's type
+	// defaults to its name (log, console, slack, discord or email)
+	Settings  map[string]NotificationChannelConfig `json:"settings,omitempty"`
+	// Templates are text/template messages by notification type, or by
+	// "<publisher>.<type>" for a single publisher
+	Templates map[string]string `json:"templates,omitempty"`
 }
 
 // RetryPolicy defines retry behavior
@@ -83,6 +108,7 @@ func NewDistributionCoordinator(config *DistributionConfig) *DistributionCoordin
 	return &DistributionCoordinator{
 		publishers: make(map[string]Publisher),
 		validators: make(map[string]Validator),
+		signers:    make(map[string][]Signer),
 		config:     config,
 	}
 }
@@ -124,8 +150,22 @@ func (dc *DistributionCoordinator) SetNotificationService(service NotificationSe
 	dc.notifier = service
 }
 
+// SetSigners sets the signers of a publisher's release, in place of the ones
+// built from its signing configuration
+func (dc *DistributionCoordinator) SetSigners(publisher string, signers ...Signer) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	dc.signers[publisher] = signers
+}
+
 // Distribute publishes a release to all configured publishers
 func (dc *DistributionCoordinator) Distribute(ctx context.Context, release Release) error {
+	return dc.distribute(ctx, release, nil)
+}
+
+// distribute publishes a release to the configured publishers, except the
+// completed ones
+func (dc *DistributionCoordinator) distribute(ctx context.Context, release Release, completed map[string]bool) error {
 	// Validate release first
 	if err := dc.validateRelease(ctx, release); err != nil {
 		return fmt.Errorf("release validation failed: %w", err)
@@ -136,9 +176,75 @@ func (dc *DistributionCoordinator) Distribute(ctx context.Context, release Relea
 	if len(publishers) == 0 {
 		return fmt.Errorf("no enabled publishers configured")
 	}
+	if publishers = dc.channelPublishers(publishers, release); len(publishers) == 0 {
+		return fmt.Errorf("no enabled publishers publish %s releases", ChannelOf(release))
+	}
+	
+	// Group the publishers into stages ordered by their dependencies
+	stages, err := dc.planStages(publishers)
+	if err != nil {
+		return fmt.Errorf("invalid publishing plan: %w", err)
+	}
 	
-	// Publish to all publishers with concurrency control
-	return dc.publishConcurrently(ctx, release, publishers)
+	// Sign the release for the publishers that ship signatures
+	var pending []Publisher
+	for _, publisher := range publishers {
+		if !completed[publisher.GetName()] {
+			pending = append(pending, publisher)
+		}
+	}
+	releases, err := dc.signRelease(ctx, release, pending)
+	if err != nil {
+		return fmt.Errorf("release signing failed: %w", err)
+	}
+	
+	// Render the release notes each publisher ships
+	if err := dc.renderNotes(releases); err != nil {
+		return fmt.Errorf("release notes rendering failed: %w", err)
+	}
+	
+	// Publish stage by stage with concurrency control
+	return dc.publishStages(ctx, releases, publishers, stages, completed)
+}
+
+// signRelease returns the release each publisher ships. Publishers with
+// signing configured get a copy listing its signatures, which are verified
+// before anything is published. Signing runs one publisher at a time since
+// publishers sharing a method write the same signature files.
+func (dc *DistributionCoordinator) signRelease(ctx context.Context, release Release, publishers []Publisher) (map[string]Release, error) {
+	releases := make(map[string]Release, len(publishers))
+	for _, publisher := range publishers {
+		name := publisher.GetName()
+		signers, err := dc.getSigners(name)
+		if err != nil {
+			return nil, fmt.Errorf("publisher %s: %w", name, err)
+		}
+		if len(signers) == 0 {
+			releases[name] = release
+			continue
+		}
+		
+		signed, err := SignRelease(ctx, release, signers)
+		if err != nil {
+			return nil, fmt.Errorf("publisher %s: %w", name, err)
+		}
+		if err := VerifyRelease(ctx, signed, signers); err != nil {
+			return nil, fmt.Errorf("publisher %s: %w", name, err)
+		}
+		releases[name] = signed
+	}
+	return releases, nil
+}
+
+// getSigners returns the signers of a publisher
+func (dc *DistributionCoordinator) getSigners(publisher string) ([]Signer, error) {
+	dc.mu.RLock()
+	defer dc.mu.RUnlock()
+	
+	if signers, exists := dc.signers[publisher]; exists {
+		return signers, nil
+	}
+	return NewSigners(dc.config.Publishers[publisher].Signing)
 }
 
 // validateRelease runs all enabled validators
@@ -169,60 +275,19 @@ func (dc *DistributionCoordinator) getEnabledPublishers() []Publisher {
 		}
 	}
 	
-	// Sort by priority (higher priority first)
-	// Implementation would sort based on config.Priority
+	// Sort by priority (lowest number first)
+	sort.Slice(publishers, func(i, j int) bool {
+		pi := dc.config.Publishers[publishers[i].GetName()].Priority
+		pj := dc.config.Publishers[publishers[j].GetName()].Priority
+		if pi != pj {
+			return pi < pj
+		}
+		return publishers[i].GetName() < publishers[j].GetName()
+	})
 	
 	return publishers
 }
 
-// publishConcurrently publishes to multiple publishers with concurrency control
-func (dc *DistributionCoordinator) publishConcurrently(ctx context.Context, release Release, publishers []Publisher) error {
-	concurrentLimit := dc.config.ConcurrentLimit
-	if concurrentLimit <= 0 {
-		concurrentLimit = len(publishers)
-	}
-	
-	semaphore := make(chan struct{}, concurrentLimit)
-	errChan := make(chan error, len(publishers))
-	var wg sync.WaitGroup
-	
-	for _, publisher := range publishers {
-		wg.Add(1)
-		go func(pub Publisher) {
-			defer wg.Done()
-			
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
-			
-			if err := dc.publishWithRetry(ctx, pub, release); err != nil {
-				errChan <- fmt.Errorf("publisher %s failed: %w", pub.GetName(), err)
-				if dc.notifier != nil {
-					dc.notifier.NotifyFailure(pub.GetName(), release, err)
-				}
-			} else {
-				if dc.notifier != nil {
-					dc.notifier.NotifySuccess(pub.GetName(), release)
-				}
-			}
-		}(publisher)
-	}
-	
-	wg.Wait()
-	close(errChan)
-	
-	// Collect all errors
-	var errors []error
-	for err := range errChan {
-		errors = append(errors, err)
-	}
-	
-	if len(errors) > 0 {
-		return fmt.Errorf("publishing failed for %d publishers: %v", len(errors), errors)
-	}
-	
-	return nil
-}
-
 // publishWithRetry publishes with retry logic
 func (dc *DistributionCoordinator) publishWithRetry(ctx context.Context, publisher Publisher, release Release) error {
 	policy := dc.config.RetryPolicy
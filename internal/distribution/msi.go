@@ -0,0 +1,222 @@
+package distribution
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// msiArchitectures maps Go architectures of Windows binaries to the
+// platforms WiX builds installers for
+var msiArchitectures = map[string]string{
+	"amd64": "x64",
+	"386":   "x86",
+	"arm64": "arm64",
+}
+
+// msiGUID matches the registry format of a GUID WiX accepts, with or
+// without braces
+var msiGUID = regexp.MustCompile(`^\{?[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{12}\}?$`)
+
+// MSIConfig describes the Windows installer built for a binary. The
+// upgrade code identifies the product across versions, so it must never
+// change once installers are released.
+type MSIConfig struct {
+	UpgradeCode  string `yaml:"upgrade_code" json:"upgrade_code"`
+	ProductName  string `yaml:"product_name" json:"product_name"`
+	Manufacturer string `yaml:"manufacturer" json:"manufacturer"`
+	// Shortcut adds a Start Menu entry opening the TUI in a console
+	Shortcut bool `yaml:"shortcut" json:"shortcut"`
+}
+
+// MSIFilename returns the file name of the installer of a binary:
+// name_version_arch.msi
+func MSIFilename(name, version, goarch string) (string, error) {
+	arch, supported := msiArchitectures[goarch]
+	if !supported {
+		return "", fmt.Errorf("architecture %s is not supported by MSI installers", goarch)
+	}
+	return fmt.Sprintf("%s_%s_%s.msi", name, strings.TrimPrefix(version, "v"), arch), nil
+}
+
+// MSIVersion returns the product version of an installer. Windows Installer
+// compares major.minor.build only, each part at most 255, 255 and 65535,
+// so prerelease and build suffixes are dropped.
+func MSIVersion(version string) (string, error) {
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("installer version %q must be major.minor.patch", version)
+	}
+	for i, limit := range []int{255, 255, 65535} {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil || n < 0 || n > limit {
+			return "", fmt.Errorf("installer version %q is out of range of Windows Installer versions", version)
+		}
+	}
+	return version, nil
+}
+
+// wixSource is the WiX v4 source of an installer: a per-machine install of
+// the executable into Program Files, its folder appended to the system
+// PATH, and optionally a Start Menu shortcut. Major upgrades replace any
+// installed version, so every build gets a new product code.
+const wixSource = `<?xml version="1.0" encoding="UTF-8"?>
+<Wix xmlns="http://wixtoolset.org/schemas/v4/wxs">
+  <Package Name="{{xml .ProductName}}" Manufacturer="{{xml .Manufacturer}}" Version="{{.Version}}" UpgradeCode="{{.UpgradeCode}}" Scope="perMachine" Compressed="yes">
+    <SummaryInformation Description="{{xml .Description}}" />
+    <MajorUpgrade AllowSameVersionUpgrades="yes" DowngradeErrorMessage="A newer version of [ProductName] is already installed." />
+    <MediaTemplate EmbedCab="yes" />
+{{- if .Homepage}}
+    <Property Id="ARPURLINFOABOUT" Value="{{xml .Homepage}}" />
+{{- end}}
+
+    <StandardDirectory Id="ProgramFiles6432Folder">
+      <Directory Id="INSTALLFOLDER" Name="{{xml .ProductName}}" />
+    </StandardDirectory>
+{{- if .Shortcut}}
+    <StandardDirectory Id="ProgramMenuFolder">
+      <Directory Id="ApplicationMenuFolder" Name="{{xml .ProductName}}" />
+    </StandardDirectory>
+{{- end}}
+
+    <Feature Id="Main" Title="{{xml .ProductName}}">
+      <Component Id="Executable" Directory="INSTALLFOLDER">
+        <File Id="ExecutableFile" Source="{{xml .Source}}" Name="{{xml .Command}}.exe" KeyPath="yes" />
+        <Environment Id="PathEntry" Name="PATH" Value="[INSTALLFOLDER]" Action="set" Part="last" System="yes" Permanent="no" />
+      </Component>
+{{- if .Shortcut}}
+      <Component Id="StartMenuShortcut" Directory="ApplicationMenuFolder">
+        <Shortcut Id="ApplicationShortcut" Name="{{xml .ProductName}}" Description="{{xml .Description}}" Target="[INSTALLFOLDER]{{xml .Command}}.exe" WorkingDirectory="INSTALLFOLDER" />
+        <RemoveFolder Id="RemoveApplicationMenuFolder" On="uninstall" />
+        <RegistryValue Root="HKCU" Key="Software\{{xml .Manufacturer}}\{{xml .ProductName}}" Name="Shortcut" Type="integer" Value="1" KeyPath="yes" />
+      </Component>
+{{- end}}
+    </Feature>
+  </Package>
+</Wix>
+`
+
+// wixData fills wixSource
+type wixData struct {
+	ProductName  string
+	Manufacturer string
+	Description  string
+	Homepage     string
+	Version      string
+	UpgradeCode  string
+	Command      string
+	Source       string
+	Shortcut     bool
+}
+
+var wixTemplate = template.Must(template.New("wxs").Funcs(template.FuncMap{
+	"xml": func(s string) (string, error) {
+		var buf bytes.Buffer
+		err := xml.EscapeText(&buf, []byte(s))
+		return buf.String(), err
+	},
+}).Parse(wixSource))
+
+// MSIBuilder builds Windows installers of release binaries with the WiX
+// toolset
+type MSIBuilder struct {
+	config PackageConfig
+	run    commandRunner
+}
+
+// NewMSIBuilder creates an installer builder
+func NewMSIBuilder(config PackageConfig) *MSIBuilder {
+	return &MSIBuilder{config: config, run: runCommand}
+}
+
+// validate checks the fields every installer needs
+func (mb *MSIBuilder) validate() error {
+	if mb.config.Name == "" {
+		return fmt.Errorf("package name is required")
+	}
+	if mb.config.MSI.UpgradeCode == "" {
+		return fmt.Errorf("msi upgrade_code is required")
+	}
+	if !msiGUID.MatchString(mb.config.MSI.UpgradeCode) {
+		return fmt.Errorf("msi upgrade_code %q is not a GUID", mb.config.MSI.UpgradeCode)
+	}
+	if mb.config.MSI.Manufacturer == "" && mb.config.Vendor == "" {
+		return fmt.Errorf("msi manufacturer or package vendor is required")
+	}
+	return nil
+}
+
+// Build builds the installer of a Windows binary, writing it to outputDir,
+// and returns the installer path
+func (mb *MSIBuilder) Build(ctx context.Context, binary Binary, version string, outputDir string) (string, error) {
+	if err := mb.validate(); err != nil {
+		return "", err
+	}
+	if binary.Platform != "windows" {
+		return "", fmt.Errorf("MSI installers need a windows binary, not %s", binary.Platform)
+	}
+	productVersion, err := MSIVersion(version)
+	if err != nil {
+		return "", err
+	}
+	filename, err := MSIFilename(mb.config.Name, version, binary.Architecture)
+	if err != nil {
+		return "", err
+	}
+	source, err := filepath.Abs(binary.FilePath)
+	if err != nil {
+		return "", err
+	}
+
+	workDir, err := os.MkdirTemp("", "wix-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(workDir)
+
+	data := wixData{
+		ProductName:  mb.config.MSI.ProductName,
+		Manufacturer: mb.config.MSI.Manufacturer,
+		Description:  mb.config.Description,
+		Homepage:     mb.config.Homepage,
+		Version:      productVersion,
+		UpgradeCode:  strings.ToUpper(strings.Trim(mb.config.MSI.UpgradeCode, "{}")),
+		Command:      mb.config.Name,
+		Source:       source,
+		Shortcut:     mb.config.MSI.Shortcut,
+	}
+	if data.ProductName == "" {
+		data.ProductName = mb.config.Name
+	}
+	if data.Manufacturer == "" {
+		data.Manufacturer = mb.config.Vendor
+	}
+
+	var wxs bytes.Buffer
+	if err := wixTemplate.Execute(&wxs, data); err != nil {
+		return "", fmt.Errorf("failed to render WiX source: %w", err)
+	}
+	sourcePath := filepath.Join(workDir, mb.config.Name+".wxs")
+	if err := os.WriteFile(sourcePath, wxs.Bytes(), 0644); err != nil {
+		return "", err
+	}
+
+	target := filepath.Join(outputDir, filename)
+	arch := msiArchitectures[binary.Architecture]
+	if err := run(ctx, mb.run, "wix", "build", "-arch", arch, "-out", target, sourcePath); err != nil {
+		return "", fmt.Errorf("failed to build %s: %w", filename, err)
+	}
+	return target, nil
+}
@@ -0,0 +1,117 @@
+package distribution
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMSIVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+		err     bool
+	}{
+		{version: "v1.2.3", want: "1.2.3"},
+		{version: "1.3.0-rc.1", want: "1.3.0"},
+		{version: "2.0.1+build.7", want: "2.0.1"},
+		{version: "dev", err: true},
+		{version: "1.2", err: true},
+		{version: "256.0.0", err: true},
+		{version: "1.2.65536", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := MSIVersion(tt.version)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	name, err := MSIFilename("nettracex", "v1.2.3", "arm64")
+	require.NoError(t, err)
+	assert.Equal(t, "nettracex_1.2.3_arm64.msi", name)
+	_, err = MSIFilename("nettracex", "1.2.3", "arm")
+	assert.Error(t, err)
+}
+
+func TestMSIBuilder_Build(t *testing.T) {
+	builder := NewMSIBuilder(PackageConfig{
+		Name:        "nettracex",
+		Vendor:      "NetTraceX",
+		Description: "Network diagnostic toolkit with <beautiful> TUI",
+		Homepage:    "https://github.com/nettracex/nettracex-tui",
+		MSI: MSIConfig{
+			UpgradeCode: "{c5a7a8f9-58ee-4779-9802-aae23dde88b1}",
+			ProductName: "NetTraceX",
+			Shortcut:    true,
+		},
+	})
+
+	// The runner reads the WiX source while it still exists
+	var commands [][]string
+	var wxs string
+	builder.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		commands = append(commands, append([]string{name}, args...))
+		data, err := os.ReadFile(args[len(args)-1])
+		require.NoError(t, err)
+		wxs = string(data)
+		return nil, nil
+	}
+
+	binary := Binary{Platform: "windows", Architecture: "amd64", Filename: "nettracex-windows-amd64.exe", FilePath: "bin/nettracex-windows-amd64.exe"}
+	path, err := builder.Build(context.Background(), binary, "v1.2.3-rc.1", "dist")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("dist", "nettracex_1.2.3-rc.1_x64.msi"), path)
+
+	require.Len(t, commands, 1)
+	assert.Equal(t, []string{"wix", "build", "-arch", "x64", "-out", path}, commands[0][:6])
+
+	// The source is well-formed XML with escaped values
+	require.NoError(t, xml.Unmarshal([]byte(wxs), new(struct{})))
+	source, err := filepath.Abs(binary.FilePath)
+	require.NoError(t, err)
+	assert.Contains(t, wxs, `Version="1.2.3" UpgradeCode="C5A7A8F9-58EE-4779-9802-AAE23DDE88B1"`)
+	assert.Contains(t, wxs, `Manufacturer="NetTraceX"`)
+	assert.Contains(t, wxs, `Description="Network diagnostic toolkit with &lt;beautiful&gt; TUI"`)
+	assert.Contains(t, wxs, `<File Id="ExecutableFile" Source="`+source+`" Name="nettracex.exe" KeyPath="yes" />`)
+	assert.Contains(t, wxs, `<Environment Id="PathEntry" Name="PATH" Value="[INSTALLFOLDER]" Action="set" Part="last" System="yes" Permanent="no" />`)
+	assert.Contains(t, wxs, `<MajorUpgrade AllowSameVersionUpgrades="yes"`)
+	assert.Contains(t, wxs, `<Shortcut Id="ApplicationShortcut" Name="NetTraceX"`)
+	assert.Contains(t, wxs, `<Property Id="ARPURLINFOABOUT" Value="https://github.com/nettracex/nettracex-tui" />`)
+
+	// Without a shortcut there is no Start Menu folder
+	builder.config.MSI.Shortcut = false
+	_, err = builder.Build(context.Background(), binary, "1.2.3", "dist")
+	require.NoError(t, err)
+	assert.NotContains(t, wxs, "ProgramMenuFolder")
+
+	// Installers need a Windows binary, a numeric version and an upgrade code
+	_, err = builder.Build(context.Background(), Binary{Platform: "linux", Architecture: "amd64"}, "1.2.3", "dist")
+	assert.Error(t, err)
+	_, err = builder.Build(context.Background(), binary, "dev", "dist")
+	assert.ErrorContains(t, err, "major.minor.patch")
+	builder.config.MSI.UpgradeCode = "nettracex"
+	_, err = builder.Build(context.Background(), binary, "1.2.3", "dist")
+	assert.ErrorContains(t, err, "not a GUID")
+
+	// The WiX output explains a failure
+	builder.config.MSI.UpgradeCode = "C5A7A8F9-58EE-4779-9802-AAE23DDE88B1"
+	builder.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		return []byte("error WIX0103: Cannot find the File file\n"), errors.New("exit status 1")
+	}
+	_, err = builder.Build(context.Background(), binary, "1.2.3", "dist")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "WIX0103")
+}
@@ -0,0 +1,422 @@
+package distribution
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// NixPublisher manages Nix expression creation and publishing to a flake
+// repository
+type NixPublisher struct {
+	config NixConfig
+	client *http.Client
+	run    commandRunner
+	status PublishStatus
+}
+
+// NixConfig contains Nix publishing configuration
+type NixConfig struct {
+	Repo        string `json:"repo"`   // git URL of the repository holding the expressions
+	Branch      string `json:"branch"` // branch pushed to
+	Dir         string `json:"dir"`    // directory of flake.nix and default.nix in the repository
+	PackageName string `json:"package_name"`
+	Description string `json:"description"`
+	Homepage    string `json:"homepage"`
+	License     string `json:"license"` // attribute of lib.licenses, e.g. "mit"
+	Nixpkgs     string `json:"nixpkgs"` // flake reference of the nixpkgs input
+	Author      string `json:"author"`  // "Name <email>" commits are made as
+	SSHKey      string `json:"ssh_key"`
+	ReleaseRepo string `json:"release_repo"`
+	// ValidateBuild builds the package with nix in its sandbox before
+	// anything is pushed, which also checks the hashes
+	ValidateBuild bool `json:"validate_build"`
+}
+
+// NixPackage is the content of a Nix expression of prebuilt binaries
+type NixPackage struct {
+	Name        string
+	Version     string
+	Description string
+	Homepage    string
+	License     string
+	Nixpkgs     string
+	// Sources are the downloads of each Nix system, in system order
+	Sources []NixSource
+}
+
+// NixSource is the download of one system
+type NixSource struct {
+	System  string
+	URL     string
+	Hash    string // SRI hash, e.g. "sha256-..."
+	Archive bool   // the binary is inside the download
+}
+
+// nixSystems maps Go platforms to Nix systems
+var nixSystems = map[string]string{
+	"linux/amd64":  "x86_64-linux",
+	"linux/arm64":  "aarch64-linux",
+	"darwin/amd64": "x86_64-darwin",
+	"darwin/arm64": "aarch64-darwin",
+}
+
+// NewNixPublisher creates a new Nix publisher
+func NewNixPublisher(config NixConfig) *NixPublisher {
+	if config.Branch == "" {
+		config.Branch = "main"
+	}
+	if config.PackageName == "" {
+		config.PackageName = "nettracex"
+	}
+	if config.License == "" {
+		config.License = "mit"
+	}
+	if config.Nixpkgs == "" {
+		config.Nixpkgs = "github:NixOS/nixpkgs/nixos-unstable"
+	}
+
+	return &NixPublisher{
+		config: config,
+		client: &http.Client{Timeout: 5 * time.Minute},
+		run:    runCommand,
+		status: PublishStatus{
+			Name:   "nix",
+			Status: StatusIdle,
+		},
+	}
+}
+
+// GetName returns the publisher name
+func (p *NixPublisher) GetName() string {
+	return "nix"
+}
+
+// Publish publishes a release to the flake repository
+func (p *NixPublisher) Publish(ctx context.Context, release Release) error {
+	p.updateStatus(StatusPublishing, "")
+
+	pkg, err := p.GeneratePackage(release)
+	if err != nil {
+		p.updateStatus(StatusError, err.Error())
+		return fmt.Errorf("failed to generate package: %w", err)
+	}
+
+	if err := p.pushPackage(ctx, pkg); err != nil {
+		p.updateStatus(StatusError, err.Error())
+		return fmt.Errorf("failed to push package: %w", err)
+	}
+
+	p.updateStatus(StatusSuccess, "")
+	return nil
+}
+
+// DryRun renders default.nix and flake.nix and checks that the repository
+// can be reached with the SSH key. The sandbox build is not run since it
+// downloads release assets that are not published yet.
+func (p *NixPublisher) DryRun(ctx context.Context, release Release) (*DryRunReport, error) {
+	report := &DryRunReport{Files: make(map[string]string)}
+	if err := p.Validate(ctx, release); err != nil {
+		return report, err
+	}
+
+	pkg, err := p.GeneratePackage(release)
+	if err != nil {
+		return report, fmt.Errorf("failed to generate package: %w", err)
+	}
+	report.Files[filepath.Join(p.config.Dir, "default.nix")] = RenderDefaultNix(pkg)
+	report.Files[filepath.Join(p.config.Dir, "flake.nix")] = RenderFlakeNix(pkg)
+
+	if p.config.Repo == "" {
+		return report, fmt.Errorf("repository is required")
+	}
+	if p.config.ValidateBuild {
+		report.Actions = append(report.Actions, "build the package in the nix sandbox")
+	}
+	report.Actions = append(report.Actions, fmt.Sprintf("commit %s %s to %s on branch %s", pkg.Name, pkg.Version, p.config.Repo, p.config.Branch))
+
+	repo := gitClient{run: p.run, author: p.config.Author, sshKey: p.config.SSHKey}
+	if err := repo.checkAccess(ctx, p.config.Repo); err != nil {
+		return report, fmt.Errorf("credential check failed: %w", err)
+	}
+	return report, nil
+}
+
+// Validate validates a release for Nix publishing
+func (p *NixPublisher) Validate(ctx context.Context, release Release) error {
+	if release.Version == "" {
+		return fmt.Errorf("release version is required")
+	}
+
+	found := false
+	for filename, binary := range release.Binaries {
+		if _, supported := nixSystems[binary.Platform+"/"+binary.Architecture]; !supported {
+			continue
+		}
+		if downloadURL(p.config.ReleaseRepo, release, binary) == "" {
+			return fmt.Errorf("binary %s missing download URL", filename)
+		}
+		found = true
+	}
+
+	if !found {
+		return fmt.Errorf("no supported binary found in release (Linux or macOS required)")
+	}
+
+	return nil
+}
+
+// GetStatus returns the current status of the Nix publisher
+func (p *NixPublisher) GetStatus() PublishStatus {
+	p.status.Metadata = map[string]string{
+		"repo":    p.config.Repo,
+		"package": p.config.PackageName,
+	}
+	return p.status
+}
+
+// updateStatus updates the publisher status
+func (p *NixPublisher) updateStatus(status StatusType, lastError string) {
+	p.status.Status = status
+	p.status.LastError = lastError
+	if status == StatusSuccess {
+		p.status.LastPublish = time.Now()
+		p.status.PublishCount++
+	} else if status == StatusError {
+		p.status.ErrorCount++
+	}
+}
+
+// GeneratePackage creates the package of the Linux and macOS binaries of a
+// release
+func (p *NixPublisher) GeneratePackage(release Release) (*NixPackage, error) {
+	pkg := &NixPackage{
+		Name:        p.config.PackageName,
+		Version:     strings.TrimPrefix(release.Version, "v"),
+		Description: p.config.Description,
+		Homepage:    p.config.Homepage,
+		License:     p.config.License,
+		Nixpkgs:     p.config.Nixpkgs,
+	}
+
+	for _, binary := range release.Binaries {
+		system, supported := nixSystems[binary.Platform+"/"+binary.Architecture]
+		if !supported {
+			continue
+		}
+		url := downloadURL(p.config.ReleaseRepo, release, binary)
+
+		// Calculate SHA256 if not provided
+		checksum := binary.Checksum
+		if checksum == "" {
+			sum, err := downloadSHA256(p.client, url)
+			if err != nil {
+				return nil, fmt.Errorf("failed to calculate SHA256 for %s: %w", binary.Filename, err)
+			}
+			checksum = sum
+		}
+		hash, err := NixHash(checksum)
+		if err != nil {
+			return nil, fmt.Errorf("invalid checksum of %s: %w", binary.Filename, err)
+		}
+
+		pkg.Sources = append(pkg.Sources, NixSource{
+			System:  system,
+			URL:     url,
+			Hash:    hash,
+			Archive: strings.HasSuffix(binary.Filename, ".tar.gz"),
+		})
+	}
+
+	if len(pkg.Sources) == 0 {
+		return nil, fmt.Errorf("no supported binaries found (Linux or macOS required)")
+	}
+	sort.Slice(pkg.Sources, func(i, j int) bool { return pkg.Sources[i].System < pkg.Sources[j].System })
+
+	if err := ValidateNixPackage(pkg); err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+// NixHash converts a hex SHA-256 digest to the SRI hash fetchurl takes
+func NixHash(checksum string) (string, error) {
+	sum, err := hex.DecodeString(checksum)
+	if err != nil {
+		return "", err
+	}
+	if len(sum) != 32 {
+		return "", fmt.Errorf("expected a SHA-256 digest, got %d bytes", len(sum))
+	}
+	return "sha256-" + base64.StdEncoding.EncodeToString(sum), nil
+}
+
+// ValidateNixPackage validates a Nix package
+func ValidateNixPackage(pkg *NixPackage) error {
+	if pkg.Name == "" {
+		return fmt.Errorf("package name is required")
+	}
+	if pkg.Version == "" {
+		return fmt.Errorf("package version is required")
+	}
+	if pkg.Description == "" {
+		return fmt.Errorf("package description is required")
+	}
+	if pkg.Homepage == "" {
+		return fmt.Errorf("package homepage is required")
+	}
+	for _, source := range pkg.Sources {
+		if !strings.HasPrefix(source.Hash, "sha256-") {
+			return fmt.Errorf("invalid %s hash %q", source.System, source.Hash)
+		}
+	}
+	return nil
+}
+
+// nixString quotes a value as a Nix string
+func nixString(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "${", `\${`, "\n", `\n`).Replace(value) + `"`
+}
+
+// RenderDefaultNix renders the package expression, to be called with
+// callPackage
+func RenderDefaultNix(pkg *NixPackage) string {
+	var b strings.Builder
+	b.WriteString("# Generated by the NetTraceX distribution manager on release; edits are overwritten.\n")
+	b.WriteString("{ lib, stdenvNoCC, fetchurl }:\n\n")
+	b.WriteString("let\n")
+	fmt.Fprintf(&b, "  version = %s;\n", nixString(pkg.Version))
+	b.WriteString("  sources = {\n")
+	for _, source := range pkg.Sources {
+		fmt.Fprintf(&b, "    %s = {\n", nixString(source.System))
+		fmt.Fprintf(&b, "      url = %s;\n", nixString(source.URL))
+		fmt.Fprintf(&b, "      hash = %s;\n", nixString(source.Hash))
+		fmt.Fprintf(&b, "      archive = %t;\n", source.Archive)
+		b.WriteString("    };\n")
+	}
+	b.WriteString("  };\n")
+	b.WriteString("  system = stdenvNoCC.hostPlatform.system;\n")
+	fmt.Fprintf(&b, "  source = sources.${system} or (throw \"%s: unsupported system ${system}\");\n", pkg.Name)
+	b.WriteString("in\n")
+	b.WriteString("stdenvNoCC.mkDerivation {\n")
+	fmt.Fprintf(&b, "  pname = %s;\n", nixString(pkg.Name))
+	b.WriteString("  inherit version;\n\n")
+	b.WriteString("  src = fetchurl { inherit (source) url hash; };\n\n")
+	b.WriteString("  dontUnpack = !source.archive;\n")
+	b.WriteString("  sourceRoot = \".\";\n\n")
+	b.WriteString("  installPhase = ''\n")
+	b.WriteString("    runHook preInstall\n")
+	fmt.Fprintf(&b, "    install -Dm755 ${if source.archive then %s else \"$src\"} $out/bin/%s\n", nixString(pkg.Name), pkg.Name)
+	b.WriteString("    runHook postInstall\n")
+	b.WriteString("  '';\n\n")
+	b.WriteString("  meta = {\n")
+	fmt.Fprintf(&b, "    description = %s;\n", nixString(pkg.Description))
+	fmt.Fprintf(&b, "    homepage = %s;\n", nixString(pkg.Homepage))
+	fmt.Fprintf(&b, "    license = lib.licenses.%s;\n", pkg.License)
+	fmt.Fprintf(&b, "    mainProgram = %s;\n", nixString(pkg.Name))
+	b.WriteString("    platforms = builtins.attrNames sources;\n")
+	b.WriteString("    sourceProvenance = [ lib.sourceTypes.binaryNativeCode ];\n")
+	b.WriteString("  };\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// RenderFlakeNix renders the flake exposing the package for each system
+func RenderFlakeNix(pkg *NixPackage) string {
+	systems := make([]string, len(pkg.Sources))
+	for i, source := range pkg.Sources {
+		systems[i] = nixString(source.System)
+	}
+
+	var b strings.Builder
+	b.WriteString("# Generated by the NetTraceX distribution manager on release; edits are overwritten.\n")
+	b.WriteString("{\n")
+	fmt.Fprintf(&b, "  description = %s;\n\n", nixString(pkg.Description))
+	fmt.Fprintf(&b, "  inputs.nixpkgs.url = %s;\n\n", nixString(pkg.Nixpkgs))
+	b.WriteString("  outputs = { self, nixpkgs }:\n")
+	b.WriteString("    let\n")
+	fmt.Fprintf(&b, "      forAllSystems = nixpkgs.lib.genAttrs [ %s ];\n", strings.Join(systems, " "))
+	b.WriteString("    in\n")
+	b.WriteString("    {\n")
+	b.WriteString("      packages = forAllSystems (system: rec {\n")
+	fmt.Fprintf(&b, "        %s = nixpkgs.legacyPackages.${system}.callPackage ./default.nix { };\n", pkg.Name)
+	fmt.Fprintf(&b, "        default = %s;\n", pkg.Name)
+	b.WriteString("      });\n")
+	b.WriteString("    };\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// WritePackage writes the default.nix and flake.nix of a package to dir,
+// building the package with nix when validation is enabled
+func (p *NixPublisher) WritePackage(ctx context.Context, pkg *NixPackage, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "default.nix"), []byte(RenderDefaultNix(pkg)), 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "flake.nix"), []byte(RenderFlakeNix(pkg)), 0644); err != nil {
+		return err
+	}
+
+	if p.config.ValidateBuild {
+		return p.validateBuild(ctx, dir)
+	}
+	return nil
+}
+
+// validateBuild builds the flake in dir in the nix sandbox, which fetches
+// the download of this system and checks its hash
+func (p *NixPublisher) validateBuild(ctx context.Context, dir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	output, err := p.run(ctx, "nix", "build", "--no-link",
+		"--extra-experimental-features", "nix-command flakes",
+		"--option", "sandbox", "true",
+		"path:"+abs+"#default")
+	if err != nil {
+		if message := strings.TrimSpace(string(output)); message != "" {
+			return fmt.Errorf("nix build failed: %w: %s", err, message)
+		}
+		return fmt.Errorf("nix build failed: %w", err)
+	}
+	return nil
+}
+
+// pushPackage commits the expressions, and the flake.lock a validating
+// build writes, to the repository
+func (p *NixPublisher) pushPackage(ctx context.Context, pkg *NixPackage) error {
+	if p.config.Repo == "" {
+		return fmt.Errorf("repository is required")
+	}
+	repo := gitClient{run: p.run, author: p.config.Author, sshKey: p.config.SSHKey}
+	clone, err := repo.clone(ctx, p.config.Repo, p.config.Branch)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(clone)
+
+	dir := filepath.Join(clone, p.config.Dir)
+	if err := p.WritePackage(ctx, pkg, dir); err != nil {
+		return err
+	}
+
+	var files []string
+	for _, name := range []string{"default.nix", "flake.nix", "flake.lock"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			files = append(files, filepath.Join(p.config.Dir, name))
+		}
+	}
+	message := fmt.Sprintf("%s: %s", pkg.Name, pkg.Version)
+	return repo.commitAndPush(ctx, clone, p.config.Branch, message, files...)
+}
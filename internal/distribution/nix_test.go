@@ -0,0 +1,154 @@
+package distribution
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nixRelease() Release {
+	return testRelease("v1.2.3-rc.1", append(linuxPlatforms, testPlatform{"windows", "amd64", "nettracex-windows-amd64.exe", ""})...)
+}
+
+func nixConfig() NixConfig {
+	return NixConfig{
+		Repo:        "git@github.com:nettracex/nix-packages.git",
+		Description: `Network diagnostic toolkit with "beautiful" TUI`,
+		Homepage:    testHomepage,
+		Author:      "NetTraceX <packages@nettracex.dev>",
+		ReleaseRepo: testReleaseRepo,
+	}
+}
+
+func TestNixHash(t *testing.T) {
+	hash, err := NixHash("e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+	require.NoError(t, err)
+	assert.Equal(t, "sha256-47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=", hash)
+
+	_, err = NixHash("abc")
+	assert.Error(t, err)
+	_, err = NixHash(strings.Repeat("a", 40))
+	assert.Error(t, err)
+}
+
+func TestNixPublisher_GeneratePackage(t *testing.T) {
+	publisher := NewNixPublisher(nixConfig())
+	require.NoError(t, publisher.Validate(context.Background(), nixRelease()))
+
+	pkg, err := publisher.GeneratePackage(nixRelease())
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3-rc.1", pkg.Version)
+	require.Len(t, pkg.Sources, 3, "Windows binaries are left out")
+	assert.Equal(t, []string{"aarch64-darwin", "aarch64-linux", "x86_64-linux"},
+		[]string{pkg.Sources[0].System, pkg.Sources[1].System, pkg.Sources[2].System})
+	assert.True(t, pkg.Sources[1].Archive)
+	assert.Equal(t, "https://github.com/nettracex/nettracex-tui/releases/download/v1.2.3-rc.1/nettracex-linux-amd64", pkg.Sources[2].URL)
+
+	defaultNix := RenderDefaultNix(pkg)
+	assert.Contains(t, defaultNix, "{ lib, stdenvNoCC, fetchurl }:")
+	assert.Contains(t, defaultNix, `  version = "1.2.3-rc.1";`)
+	hash, _ := NixHash(strings.Repeat("a", 64))
+	assert.Contains(t, defaultNix, `    "x86_64-linux" = {
+      url = "https://github.com/nettracex/nettracex-tui/releases/download/v1.2.3-rc.1/nettracex-linux-amd64";
+      hash = "`+hash+`";
+      archive = false;
+    };`)
+	assert.Contains(t, defaultNix, `install -Dm755 ${if source.archive then "nettracex" else "$src"} $out/bin/nettracex`)
+	assert.Contains(t, defaultNix, `description = "Network diagnostic toolkit with \"beautiful\" TUI";`)
+	assert.Contains(t, defaultNix, "license = lib.licenses.mit;")
+	assert.Equal(t, strings.Count(defaultNix, "{"), strings.Count(defaultNix, "}"))
+
+	flake := RenderFlakeNix(pkg)
+	assert.Contains(t, flake, `inputs.nixpkgs.url = "github:NixOS/nixpkgs/nixos-unstable";`)
+	assert.Contains(t, flake, `nixpkgs.lib.genAttrs [ "aarch64-darwin" "aarch64-linux" "x86_64-linux" ]`)
+	assert.Contains(t, flake, "nettracex = nixpkgs.legacyPackages.${system}.callPackage ./default.nix { };")
+	assert.Equal(t, strings.Count(flake, "{"), strings.Count(flake, "}"))
+
+	// A release without Linux or macOS binaries has nothing to publish
+	release := Release{Version: "v1.2.3", Binaries: map[string]Binary{"nettracex-windows-amd64.exe": nixRelease().Binaries["nettracex-windows-amd64.exe"]}}
+	assert.Error(t, publisher.Validate(context.Background(), release))
+	_, err = publisher.GeneratePackage(release)
+	assert.Error(t, err)
+}
+
+func TestNixPublisher_Publish(t *testing.T) {
+	config := nixConfig()
+	config.Dir = "pkgs/nettracex"
+	config.ValidateBuild = true
+	publisher := NewNixPublisher(config)
+
+	// The validating build writes a flake.lock, which is committed too
+	runner := &recordingRunner{}
+	publisher.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		runner.run(ctx, name, args...)
+		switch {
+		case name == "nix":
+			flake := strings.TrimSuffix(strings.TrimPrefix(args[len(args)-1], "path:"), "#default")
+			require.FileExists(t, filepath.Join(flake, "default.nix"))
+			require.NoError(t, os.WriteFile(filepath.Join(flake, "flake.lock"), []byte("{}\n"), 0644))
+		case args[len(args)-1] == "--porcelain":
+			return []byte("A  pkgs/nettracex/default.nix\n"), nil
+		}
+		return nil, nil
+	}
+	require.NoError(t, publisher.Publish(context.Background(), nixRelease()))
+
+	require.Len(t, runner.commands, 8)
+	assert.Contains(t, runner.commands[0], " clone git@github.com:nettracex/nix-packages.git ")
+	assert.Regexp(t, `^nix build --no-link --extra-experimental-features nix-command flakes --option sandbox true path:/.+/pkgs/nettracex#default$`, runner.commands[3])
+	assert.True(t, strings.HasSuffix(runner.commands[4], " add pkgs/nettracex/default.nix pkgs/nettracex/flake.nix pkgs/nettracex/flake.lock"))
+	assert.True(t, strings.HasSuffix(runner.commands[6], " commit -m nettracex: 1.2.3-rc.1"))
+	assert.True(t, strings.HasSuffix(runner.commands[7], " push origin HEAD:main"))
+	assert.Equal(t, StatusSuccess, publisher.GetStatus().Status)
+
+	// An expression that does not build is not pushed
+	runner.commands = nil
+	publisher.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		runner.run(ctx, name, args...)
+		if name == "nix" {
+			return []byte("error: hash mismatch in fixed-output derivation\n"), errors.New("exit status 1")
+		}
+		return nil, nil
+	}
+	err := publisher.Publish(context.Background(), nixRelease())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "hash mismatch")
+	assert.Len(t, runner.commands, 4)
+	assert.Equal(t, StatusError, publisher.GetStatus().Status)
+}
+
+func TestNixPublisher_PublishToRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	remote := filepath.Join(t.TempDir(), "nix-packages.git")
+	output, err := exec.Command("git", "init", "--bare", remote).CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	config := nixConfig()
+	config.Repo = remote
+	publisher := NewNixPublisher(config)
+	require.NoError(t, publisher.Publish(context.Background(), nixRelease()))
+
+	// A new version updates the expressions
+	release := nixRelease()
+	release.Version = "v1.2.4"
+	release.Tag = "v1.2.4"
+	require.NoError(t, publisher.Publish(context.Background(), release))
+
+	output, err = exec.Command("git", "--git-dir", remote, "log", "--format=%s", "main").CombinedOutput()
+	require.NoError(t, err, string(output))
+	assert.Equal(t, "nettracex: 1.2.4\nnettracex: 1.2.3-rc.1\n", string(output))
+
+	output, err = exec.Command("git", "--git-dir", remote, "show", "main:default.nix").CombinedOutput()
+	require.NoError(t, err, string(output))
+	assert.Contains(t, string(output), `version = "1.2.4";`)
+}
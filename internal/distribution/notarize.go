@@ -0,0 +1,289 @@
+package distribution
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NotaryAccepted is the status notarytool reports for an accepted
+// submission; any other final status is a rejection
+const NotaryAccepted = "Accepted"
+
+// MacOSSigningConfig describes how darwin binaries are codesigned with a
+// Developer ID and notarized by Apple. Notarization authenticates with a
+// keychain profile stored by notarytool store-credentials, an Apple ID with
+// an app-specific password, or an App Store Connect API key.
+type MacOSSigningConfig struct {
+	// Identity is the Developer ID Application certificate, e.g.
+	// "Developer ID Application: NetTraceX (TEAMID1234)"
+	Identity     string `yaml:"identity" json:"identity"`
+	Keychain     string `yaml:"keychain" json:"keychain"`
+	Entitlements string `yaml:"entitlements" json:"entitlements"`
+	Notarize     bool   `yaml:"notarize" json:"notarize"`
+	// Timeout bounds the wait for a notarization verdict, e.g. "30m"
+	Timeout         string `yaml:"timeout" json:"timeout"`
+	KeychainProfile string `yaml:"keychain_profile" json:"keychain_profile"`
+	AppleID         string `yaml:"apple_id" json:"apple_id"`
+	TeamID          string `yaml:"team_id" json:"team_id"`
+	Password        string `yaml:"password" json:"password"`
+	APIKey          string `yaml:"api_key" json:"api_key"` // path of the .p8 key
+	APIKeyID        string `yaml:"api_key_id" json:"api_key_id"`
+	APIIssuer       string `yaml:"api_issuer" json:"api_issuer"`
+}
+
+// LoadMacOSSigningConfig reads a signing configuration from a YAML file,
+// expanding environment variables so credentials can stay out of it
+func LoadMacOSSigningConfig(path string) (MacOSSigningConfig, error) {
+	var config MacOSSigningConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), &config); err != nil {
+		return config, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return config, config.Validate()
+}
+
+// Validate checks that the configuration can sign, and notarize when
+// enabled
+func (mc MacOSSigningConfig) Validate() error {
+	if mc.Identity == "" {
+		return fmt.Errorf("signing identity is required")
+	}
+	if !mc.Notarize {
+		return nil
+	}
+	switch {
+	case mc.KeychainProfile != "":
+	case mc.AppleID != "" || mc.Password != "" || mc.TeamID != "":
+		if mc.AppleID == "" || mc.Password == "" || mc.TeamID == "" {
+			return fmt.Errorf("notarization with an Apple ID requires apple_id, password and team_id")
+		}
+	case mc.APIKey != "" || mc.APIKeyID != "" || mc.APIIssuer != "":
+		if mc.APIKey == "" || mc.APIKeyID == "" || mc.APIIssuer == "" {
+			return fmt.Errorf("notarization with an API key requires api_key, api_key_id and api_issuer")
+		}
+	default:
+		return fmt.Errorf("notarization requires keychain_profile, an Apple ID or an API key")
+	}
+	return nil
+}
+
+// notaryCredentials returns the notarytool options authenticating with
+// Apple
+func (mc MacOSSigningConfig) notaryCredentials() []string {
+	switch {
+	case mc.KeychainProfile != "":
+		args := []string{"--keychain-profile", mc.KeychainProfile}
+		if mc.Keychain != "" {
+			args = append(args, "--keychain", mc.Keychain)
+		}
+		return args
+	case mc.AppleID != "":
+		return []string{"--apple-id", mc.AppleID, "--team-id", mc.TeamID, "--password", mc.Password}
+	}
+	return []string{"--key", mc.APIKey, "--key-id", mc.APIKeyID, "--issuer", mc.APIIssuer}
+}
+
+// notarySubmission is the JSON notarytool prints for a submission
+type notarySubmission struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// notaryLog is the part of a submission's log explaining a rejection
+type notaryLog struct {
+	StatusSummary string `json:"statusSummary"`
+	Issues        []struct {
+		Path     string `json:"path"`
+		Message  string `json:"message"`
+		Severity string `json:"severity"`
+	} `json:"issues"`
+}
+
+// MacOSSigner codesigns darwin binaries, notarizes them and staples the
+// notarization ticket where it can be stapled. It needs macOS with the
+// Xcode command line tools.
+type MacOSSigner struct {
+	config MacOSSigningConfig
+	run    commandRunner
+}
+
+// NewMacOSSigner creates a macOS signer
+func NewMacOSSigner(config MacOSSigningConfig) (*MacOSSigner, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &MacOSSigner{config: config, run: runCommand}, nil
+}
+
+// Process signs path, then notarizes it and staples the ticket when
+// notarization is enabled. A rejected notarization is an error.
+func (ms *MacOSSigner) Process(ctx context.Context, path string) error {
+	if err := ms.Sign(ctx, path); err != nil {
+		return fmt.Errorf("codesigning failed: %w", err)
+	}
+	if !ms.config.Notarize {
+		return nil
+	}
+	if err := ms.Notarize(ctx, path); err != nil {
+		return err
+	}
+	if _, err := ms.Staple(ctx, path); err != nil {
+		return fmt.Errorf("stapling failed: %w", err)
+	}
+	return nil
+}
+
+// Sign codesigns path with the hardened runtime and a secure timestamp,
+// both of which notarization requires, and verifies the signature
+func (ms *MacOSSigner) Sign(ctx context.Context, path string) error {
+	args := []string{"--force", "--timestamp", "--options", "runtime", "--sign", ms.config.Identity}
+	if ms.config.Keychain != "" {
+		args = append(args, "--keychain", ms.config.Keychain)
+	}
+	if ms.config.Entitlements != "" {
+		args = append(args, "--entitlements", ms.config.Entitlements)
+	}
+	args = append(args, path)
+	if err := run(ctx, ms.run, "codesign", args...); err != nil {
+		return err
+	}
+	return run(ctx, ms.run, "codesign", "--verify", "--strict", "--verbose=2", path)
+}
+
+// Notarize submits path to Apple's notary service and waits for the
+// verdict. Bare executables are submitted in a zip archive, since the
+// service only takes archives, disk images and installer packages.
+func (ms *MacOSSigner) Notarize(ctx context.Context, path string) error {
+	submission := path
+	if !isNotaryContainer(path) {
+		dir, err := os.MkdirTemp("", "notarize-*")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+
+		submission = filepath.Join(dir, filepath.Base(path)+".zip")
+		if err := zipFile(path, submission); err != nil {
+			return fmt.Errorf("failed to archive %s for notarization: %w", filepath.Base(path), err)
+		}
+	}
+
+	args := append([]string{"notarytool", "submit", submission, "--wait", "--output-format", "json"}, ms.config.notaryCredentials()...)
+	if ms.config.Timeout != "" {
+		args = append(args, "--timeout", ms.config.Timeout)
+	}
+	// notarytool fails on rejected submissions too, so the verdict is read
+	// from its output before its exit status
+	output, runErr := ms.run(ctx, "xcrun", args...)
+	var result notarySubmission
+	if err := json.Unmarshal(output, &result); err != nil || result.Status == "" {
+		if runErr != nil {
+			return fmt.Errorf("notarization of %s failed: %w: %s", filepath.Base(path), runErr, strings.TrimSpace(string(output)))
+		}
+		return fmt.Errorf("notarization of %s failed: unexpected notarytool output: %s", filepath.Base(path), strings.TrimSpace(string(output)))
+	}
+
+	if result.Status != NotaryAccepted {
+		return fmt.Errorf("notarization of %s was %s (submission %s): %s", filepath.Base(path), strings.ToLower(result.Status), result.ID, ms.rejection(ctx, result))
+	}
+	return nil
+}
+
+// rejection explains why a submission was rejected from its log, or from
+// the submission's message when the log cannot be read
+func (ms *MacOSSigner) rejection(ctx context.Context, submission notarySubmission) string {
+	args := append([]string{"notarytool", "log", submission.ID}, ms.config.notaryCredentials()...)
+	output, err := ms.run(ctx, "xcrun", args...)
+	var log notaryLog
+	if err != nil || json.Unmarshal(output, &log) != nil {
+		return submission.Message
+	}
+
+	var problems []string
+	for _, issue := range log.Issues {
+		problems = append(problems, fmt.Sprintf("%s: %s", issue.Path, issue.Message))
+	}
+	if len(problems) == 0 {
+		return log.StatusSummary
+	}
+	return strings.Join(problems, "; ")
+}
+
+// Staple attaches the notarization ticket to path so Gatekeeper can check
+// it offline, and reports whether it did. Tickets can only be stapled to
+// disk images, installer packages and app bundles; Gatekeeper looks up the
+// ticket of a bare executable online.
+func (ms *MacOSSigner) Staple(ctx context.Context, path string) (bool, error) {
+	if !isStapleable(path) {
+		return false, nil
+	}
+	if err := run(ctx, ms.run, "xcrun", "stapler", "staple", path); err != nil {
+		return false, err
+	}
+	return true, run(ctx, ms.run, "xcrun", "stapler", "validate", path)
+}
+
+// isNotaryContainer reports whether the notary service takes path as it is
+func isNotaryContainer(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".zip", ".dmg", ".pkg":
+		return true
+	}
+	return false
+}
+
+// isStapleable reports whether a ticket can be stapled to path
+func isStapleable(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".dmg", ".pkg", ".app":
+		return true
+	}
+	return false
+}
+
+// zipFile writes a zip archive of the file src to dst, keeping its mode
+func zipFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Method = zip.Deflate
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	archive := zip.NewWriter(out)
+	writer, err := archive.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(writer, in); err != nil {
+		return err
+	}
+	return archive.Close()
+}
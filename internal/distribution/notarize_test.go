@@ -0,0 +1,168 @@
+package distribution
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMacOSSigningConfig_Validate(t *testing.T) {
+	identity := "Developer ID Application: NetTraceX (TEAMID1234)"
+	tests := []struct {
+		name   string
+		config MacOSSigningConfig
+		err    string
+	}{
+		{name: "sign only", config: MacOSSigningConfig{Identity: identity}},
+		{name: "no identity", config: MacOSSigningConfig{Notarize: true, KeychainProfile: "nettracex"}, err: "signing identity"},
+		{name: "keychain profile", config: MacOSSigningConfig{Identity: identity, Notarize: true, KeychainProfile: "nettracex"}},
+		{name: "apple id", config: MacOSSigningConfig{Identity: identity, Notarize: true, AppleID: "dev@nettracex.dev", TeamID: "TEAMID1234", Password: "app-password"}},
+		{name: "partial apple id", config: MacOSSigningConfig{Identity: identity, Notarize: true, AppleID: "dev@nettracex.dev"}, err: "apple_id, password and team_id"},
+		{name: "partial api key", config: MacOSSigningConfig{Identity: identity, Notarize: true, APIKey: "AuthKey.p8"}, err: "api_key, api_key_id and api_issuer"},
+		{name: "no credentials", config: MacOSSigningConfig{Identity: identity, Notarize: true}, err: "requires keychain_profile"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestLoadMacOSSigningConfig(t *testing.T) {
+	t.Setenv("APPLE_APP_PASSWORD", "app-password")
+	path := filepath.Join(t.TempDir(), "macos.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`identity: "Developer ID Application: NetTraceX (TEAMID1234)"
+notarize: true
+timeout: 30m
+apple_id: dev@nettracex.dev
+team_id: TEAMID1234
+password: ${APPLE_APP_PASSWORD}
+`), 0644))
+
+	config, err := LoadMacOSSigningConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "app-password", config.Password)
+	assert.Equal(t, []string{"--apple-id", "dev@nettracex.dev", "--team-id", "TEAMID1234", "--password", "app-password"}, config.notaryCredentials())
+}
+
+func TestMacOSSigner_Process(t *testing.T) {
+	binary := filepath.Join(t.TempDir(), "nettracex-darwin-arm64")
+	require.NoError(t, os.WriteFile(binary, []byte("mach-o"), 0755))
+
+	signer, err := NewMacOSSigner(MacOSSigningConfig{
+		Identity:        "Developer ID Application: NetTraceX (TEAMID1234)",
+		Entitlements:    "packaging/entitlements.plist",
+		Notarize:        true,
+		Timeout:         "30m",
+		KeychainProfile: "nettracex",
+	})
+	require.NoError(t, err)
+
+	// The runner checks the archive submitted while it still exists
+	var commands [][]string
+	var archived string
+	signer.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		commands = append(commands, append([]string{name}, args...))
+		if name == "xcrun" && args[1] == "submit" {
+			reader, err := zip.OpenReader(args[2])
+			require.NoError(t, err)
+			defer reader.Close()
+			require.Len(t, reader.File, 1)
+			archived = reader.File[0].Name
+			assert.Equal(t, os.FileMode(0755), reader.File[0].Mode().Perm())
+			return []byte(`{"id":"2efe2717-52ef-43a5-96dc-0797e4ca1041","status":"Accepted","message":"Processing complete"}`), nil
+		}
+		return nil, nil
+	}
+
+	require.NoError(t, signer.Process(context.Background(), binary))
+	assert.Equal(t, "nettracex-darwin-arm64", archived)
+	require.Len(t, commands, 3)
+	assert.Equal(t, []string{"codesign", "--force", "--timestamp", "--options", "runtime", "--sign", "Developer ID Application: NetTraceX (TEAMID1234)", "--entitlements", "packaging/entitlements.plist", binary}, commands[0])
+	assert.Equal(t, []string{"codesign", "--verify", "--strict", "--verbose=2", binary}, commands[1])
+	assert.Equal(t, []string{"--wait", "--output-format", "json", "--keychain-profile", "nettracex", "--timeout", "30m"}, commands[2][4:])
+
+	// Tickets are stapled to disk images, not to bare executables
+	commands = nil
+	stapled, err := signer.Staple(context.Background(), "dist/nettracex.dmg")
+	require.NoError(t, err)
+	assert.True(t, stapled)
+	assert.Equal(t, [][]string{
+		{"xcrun", "stapler", "staple", "dist/nettracex.dmg"},
+		{"xcrun", "stapler", "validate", "dist/nettracex.dmg"},
+	}, commands)
+	stapled, err = signer.Staple(context.Background(), binary)
+	require.NoError(t, err)
+	assert.False(t, stapled)
+}
+
+func TestMacOSSigner_Rejected(t *testing.T) {
+	binary := filepath.Join(t.TempDir(), "nettracex-darwin-amd64")
+	require.NoError(t, os.WriteFile(binary, []byte("mach-o"), 0755))
+
+	signer, err := NewMacOSSigner(MacOSSigningConfig{
+		Identity:  "Developer ID Application: NetTraceX (TEAMID1234)",
+		Notarize:  true,
+		APIKey:    "AuthKey_ABC123.p8",
+		APIKeyID:  "ABC123",
+		APIIssuer: "69a6de7e-6b3a-47e3-e053-5b8c7c11a4d1",
+	})
+	require.NoError(t, err)
+
+	// notarytool exits with an error on a rejection, after printing it
+	var logArgs []string
+	signer.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if name != "xcrun" {
+			return nil, nil
+		}
+		switch args[1] {
+		case "submit":
+			return []byte(`{"id":"4b5f3c2a","status":"Invalid","message":"Processing complete"}`), errors.New("exit status 1")
+		case "log":
+			logArgs = args
+			return []byte(`{"status":"Invalid","statusSummary":"Archive contains critical validation errors","issues":[{"path":"nettracex-darwin-amd64.zip/nettracex-darwin-amd64","message":"The executable does not have the hardened runtime enabled.","severity":"error"}]}`), nil
+		}
+		t.Fatalf("unexpected command %v", args)
+		return nil, nil
+	}
+
+	err = signer.Process(context.Background(), binary)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "notarization of nettracex-darwin-amd64 was invalid (submission 4b5f3c2a)")
+	assert.Contains(t, err.Error(), "does not have the hardened runtime enabled")
+	assert.Equal(t, []string{"notarytool", "log", "4b5f3c2a", "--key", "AuthKey_ABC123.p8", "--key-id", "ABC123", "--issuer", "69a6de7e-6b3a-47e3-e053-5b8c7c11a4d1"}, logArgs)
+
+	// Failures without a verdict report notarytool's output
+	signer.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if name != "xcrun" {
+			return nil, nil
+		}
+		return []byte("Error: HTTP status code: 401. Unable to authenticate.\n"), errors.New("exit status 69")
+	}
+	err = signer.Notarize(context.Background(), binary)
+	require.Error(t, err)
+	assert.True(t, strings.HasSuffix(err.Error(), "Unable to authenticate."))
+
+	// Signing failures stop before notarization
+	signer.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if name == "codesign" {
+			return []byte("Developer ID Application: NetTraceX (TEAMID1234): no identity found\n"), errors.New("exit status 1")
+		}
+		t.Fatalf("unexpected command %s", name)
+		return nil, nil
+	}
+	assert.ErrorContains(t, signer.Process(context.Background(), binary), "no identity found")
+}
@@ -0,0 +1,308 @@
+package distribution
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultReleaseNotesTemplate is the template release notes are rendered
+// from when none is configured
+const DefaultReleaseNotesTemplate = `# {{.Project}} {{.Version}}
+{{- if .Prerelease}}
+
+> This is a prerelease of the {{.Channel}} channel.
+{{- end}}
+{{- if .Highlights}}
+
+## Highlights
+{{range .Highlights}}
+- {{.}}
+{{- end}}
+{{- end}}
+{{- if .Notes}}
+
+{{.Notes}}
+{{- end}}
+{{- if .Platforms}}
+
+## Installation
+{{- range .Platforms}}
+
+### {{platformName .}}
+
+` + "```" + `{{shell .}}
+{{index $.Install .}}
+` + "```" + `
+{{- end}}
+{{- end}}
+{{- if .Checksums}}
+
+## Checksums
+
+{{.ChecksumsTable}}
+{{- end}}
+`
+
+// ReleaseNotesConfig contains release notes templating settings
+type ReleaseNotesConfig struct {
+	// Enabled renders notes for every publisher; publishers with a
+	// template of their own get notes either way
+	Enabled bool `json:"enabled"`
+	// Template is the file of the template notes are rendered from, by
+	// default DefaultReleaseNotesTemplate
+	Template string `json:"template,omitempty"`
+	// Project is the name of the installed command, "nettracex" by default
+	Project string `json:"project,omitempty"`
+	// DownloadURL is where binaries without a download URL of their own
+	// are downloaded from, with {tag} and {file} replaced
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+// ReleaseNotesData are the variables of a release notes template
+type ReleaseNotesData struct {
+	Project    string
+	Publisher  string
+	Version    string // without the leading v
+	Tag        string
+	Date       time.Time
+	Prerelease bool
+	Channel    string // stable, rc, beta or alpha
+	CommitSHA  string
+	Highlights []string
+	Notes      string // the hand-written notes of the release
+	Changelog  string
+	Checksums  []NotesChecksum
+	// ChecksumsTable is a Markdown table of the checksums
+	ChecksumsTable string
+	// Install holds a shell snippet installing the release by platform,
+	// for the Platforms the release has binaries for
+	Install   map[string]string
+	Platforms []string
+}
+
+// NotesChecksum is the checksum of a release file
+type NotesChecksum struct {
+	File     string
+	Checksum string
+}
+
+// releaseNotesFuncs are the functions release notes templates can call
+var releaseNotesFuncs = template.FuncMap{
+	"join":         strings.Join,
+	"platformName": platformName,
+	"shell": func(platform string) string {
+		if platform == "windows" {
+			return "powershell"
+		}
+		return "bash"
+	},
+}
+
+// notesPlatforms are the platforms install snippets are written for, in
+// the order they are listed
+var notesPlatforms = []string{"linux", "darwin", "windows"}
+
+// platformName returns the display name of a platform
+func platformName(platform string) string {
+	switch platform {
+	case "linux":
+		return "Linux"
+	case "darwin":
+		return "macOS"
+	case "windows":
+		return "Windows"
+	}
+	return platform
+}
+
+// NewReleaseNotesData collects the variables of the notes of a release
+func NewReleaseNotesData(release Release, publisher string, config ReleaseNotesConfig) ReleaseNotesData {
+	data := ReleaseNotesData{
+		Project:    config.Project,
+		Publisher:  publisher,
+		Version:    strings.TrimPrefix(release.Version, "v"),
+		Tag:        release.Tag,
+		Date:       release.Metadata.CreatedAt,
+		Prerelease: IsPrerelease(release),
+		Channel:    string(ChannelOf(release)),
+		CommitSHA:  release.Metadata.CommitSHA,
+		Highlights: release.Highlights,
+		Notes:      strings.TrimSpace(release.ReleaseNotes),
+		Changelog:  strings.TrimSpace(release.Changelog),
+		Install:    make(map[string]string),
+	}
+	if data.Project == "" {
+		data.Project = "nettracex"
+	}
+	if data.Tag == "" {
+		data.Tag = release.Version
+	}
+	if data.Date.IsZero() {
+		data.Date = time.Now().UTC()
+	}
+
+	for _, name := range sortedNames(release.Checksums) {
+		data.Checksums = append(data.Checksums, NotesChecksum{File: name, Checksum: release.Checksums[name]})
+	}
+	data.ChecksumsTable = checksumsTable(data.Checksums)
+
+	for _, platform := range notesPlatforms {
+		binary, ok := notesBinary(release, platform)
+		if !ok {
+			continue
+		}
+		url := binary.DownloadURL
+		if url == "" && config.DownloadURL != "" {
+			url = strings.NewReplacer("{tag}", data.Tag, "{file}", binary.Filename).Replace(config.DownloadURL)
+		}
+		if url == "" {
+			continue
+		}
+		data.Install[platform] = installSnippet(platform, binary.Filename, url, data.Project)
+		data.Platforms = append(data.Platforms, platform)
+	}
+	return data
+}
+
+// notesBinary returns the binary install snippets use for a platform,
+// preferring amd64, then arm64
+func notesBinary(release Release, platform string) (Binary, bool) {
+	var found []Binary
+	for _, name := range sortedBinaries(release.Binaries) {
+		if binary := release.Binaries[name]; binary.Platform == platform {
+			found = append(found, binary)
+		}
+	}
+	if len(found) == 0 {
+		return Binary{}, false
+	}
+	for _, arch := range []string{"amd64", "arm64"} {
+		for _, binary := range found {
+			if binary.Architecture == arch {
+				return binary, true
+			}
+		}
+	}
+	return found[0], true
+}
+
+// installSnippet returns the commands that download and install a binary
+func installSnippet(platform, filename, url, project string) string {
+	if platform == "windows" {
+		if strings.HasSuffix(filename, ".zip") {
+			return fmt.Sprintf("Invoke-WebRequest -Uri %q -OutFile %q\nExpand-Archive %q -DestinationPath %q", url, filename, filename, project)
+		}
+		return fmt.Sprintf("Invoke-WebRequest -Uri %q -OutFile %q", url, project+".exe")
+	}
+
+	var b strings.Builder
+	switch {
+	case strings.HasSuffix(filename, ".tar.gz"), strings.HasSuffix(filename, ".tgz"):
+		fmt.Fprintf(&b, "curl -L %q | tar xz %s\n", url, project)
+	case strings.HasSuffix(filename, ".zip"):
+		fmt.Fprintf(&b, "curl -L %q -o %s.zip\nunzip %s.zip %s\n", url, project, project, project)
+	default:
+		fmt.Fprintf(&b, "curl -L %q -o %s\n", url, project)
+	}
+	fmt.Fprintf(&b, "chmod +x %s\nsudo mv %s /usr/local/bin/", project, project)
+	return b.String()
+}
+
+// checksumsTable renders checksums as a Markdown table
+func checksumsTable(checksums []NotesChecksum) string {
+	if len(checksums) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("| File | SHA-256 |\n|------|---------|")
+	for _, checksum := range checksums {
+		fmt.Fprintf(&b, "\n| `%s` | `%s` |", checksum.File, checksum.Checksum)
+	}
+	return b.String()
+}
+
+// RenderReleaseNotes renders release notes from a template
+func RenderReleaseNotes(text string, data ReleaseNotesData) (string, error) {
+	tmpl, err := template.New("release-notes").Funcs(releaseNotesFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid release notes template: %w", err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render release notes: %w", err)
+	}
+	return strings.TrimSpace(b.String()) + "\n", nil
+}
+
+// readNotesTemplate reads a release notes template file, or returns the
+// default template when path is empty
+func readNotesTemplate(path string) (string, error) {
+	if path == "" {
+		return DefaultReleaseNotesTemplate, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read release notes template: %w", err)
+	}
+	return string(data), nil
+}
+
+// notesTemplate returns the release notes template of a publisher, and
+// whether notes are rendered for it at all
+func (dc *DistributionCoordinator) notesTemplate(publisher string) (string, bool, error) {
+	path := dc.config.Publishers[publisher].NotesTemplate
+	if path == "" {
+		if !dc.config.ReleaseNotes.Enabled {
+			return "", false, nil
+		}
+		path = dc.config.ReleaseNotes.Template
+	}
+	text, err := readNotesTemplate(path)
+	return text, err == nil, err
+}
+
+// ReleaseNotes renders the release notes a publisher ships, from its
+// template or the configured one, even when rendering notes is disabled
+func (dc *DistributionCoordinator) ReleaseNotes(release Release, publisher string) (string, error) {
+	text, ok, err := dc.notesTemplate(publisher)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		if text, err = readNotesTemplate(dc.config.ReleaseNotes.Template); err != nil {
+			return "", err
+		}
+	}
+	return RenderReleaseNotes(text, NewReleaseNotesData(release, publisher, dc.config.ReleaseNotes))
+}
+
+// renderNotes sets the notes of the release each publisher ships, for the
+// publishers notes are rendered for
+func (dc *DistributionCoordinator) renderNotes(releases map[string]Release) error {
+	names := make([]string, 0, len(releases))
+	for name := range releases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		text, ok, err := dc.notesTemplate(name)
+		if err != nil {
+			return fmt.Errorf("publisher %s: %w", name, err)
+		}
+		if !ok {
+			continue
+		}
+		release := releases[name]
+		release.Notes, err = RenderReleaseNotes(text, NewReleaseNotesData(release, name, dc.config.ReleaseNotes))
+		if err != nil {
+			return fmt.Errorf("publisher %s: %w", name, err)
+		}
+		releases[name] = release
+	}
+	return nil
+}
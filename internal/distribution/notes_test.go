@@ -0,0 +1,132 @@
+package distribution
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func notesRelease() Release {
+	return Release{
+		Version: "v1.2.3",
+		Tag:     "v1.2.3",
+		Binaries: map[string]Binary{
+			"nettracex_Linux_arm64.tar.gz":  {Platform: "linux", Architecture: "arm64", Filename: "nettracex_Linux_arm64.tar.gz"},
+			"nettracex_Linux_x86_64.tar.gz": {Platform: "linux", Architecture: "amd64", Filename: "nettracex_Linux_x86_64.tar.gz"},
+			"nettracex_Windows_x86_64.zip":  {Platform: "windows", Architecture: "amd64", Filename: "nettracex_Windows_x86_64.zip"},
+			"nettracex-darwin-arm64": {
+				Platform:     "darwin",
+				Architecture: "arm64",
+				Filename:     "nettracex-darwin-arm64",
+				DownloadURL:  "https://downloads.example.com/nettracex-darwin-arm64",
+			},
+		},
+		Checksums: map[string]string{
+			"nettracex_Windows_x86_64.zip":  strings.Repeat("b", 64),
+			"nettracex_Linux_x86_64.tar.gz": strings.Repeat("a", 64),
+		},
+		ReleaseNotes: "Faster traceroutes on lossy links.\n",
+		Highlights:   []string{"Parallel DNS lookups", "WHOIS expiry warnings"},
+		Metadata:     ReleaseMetadata{CreatedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+}
+
+func TestRenderReleaseNotes_Default(t *testing.T) {
+	data := NewReleaseNotesData(notesRelease(), "github", ReleaseNotesConfig{
+		DownloadURL: "https://github.com/nettracex/nettracex-tui/releases/download/{tag}/{file}",
+	})
+	assert.Equal(t, []string{"linux", "darwin", "windows"}, data.Platforms)
+
+	notes, err := RenderReleaseNotes(DefaultReleaseNotesTemplate, data)
+	require.NoError(t, err)
+	assert.Equal(t, "# nettracex 1.2.3\n\n## Highlights\n\n- Parallel DNS lookups\n- WHOIS expiry warnings\n\nFaster traceroutes on lossy links.\n\n## Installation", notes[:strings.Index(notes, "\n\n### Linux")])
+
+	// The amd64 archive is used over the arm64 one, and a binary's own
+	// download URL over the configured one
+	assert.Contains(t, notes, "### Linux\n\n```bash\ncurl -L \"https://github.com/nettracex/nettracex-tui/releases/download/v1.2.3/nettracex_Linux_x86_64.tar.gz\" | tar xz nettracex\nchmod +x nettracex\nsudo mv nettracex /usr/local/bin/\n```")
+	assert.Contains(t, notes, "### macOS\n\n```bash\ncurl -L \"https://downloads.example.com/nettracex-darwin-arm64\" -o nettracex\n")
+	assert.Contains(t, notes, "### Windows\n\n```powershell\nInvoke-WebRequest -Uri \"https://github.com/nettracex/nettracex-tui/releases/download/v1.2.3/nettracex_Windows_x86_64.zip\" -OutFile \"nettracex_Windows_x86_64.zip\"\n")
+	assert.True(t, strings.HasSuffix(notes, "## Checksums\n\n| File | SHA-256 |\n|------|---------|\n| `nettracex_Linux_x86_64.tar.gz` | `"+strings.Repeat("a", 64)+"` |\n| `nettracex_Windows_x86_64.zip` | `"+strings.Repeat("b", 64)+"` |\n"))
+
+	// Without download URLs there is nothing to install from
+	notes, err = RenderReleaseNotes(DefaultReleaseNotesTemplate, NewReleaseNotesData(notesRelease(), "github", ReleaseNotesConfig{}))
+	require.NoError(t, err)
+	assert.Contains(t, notes, "### macOS")
+	assert.NotContains(t, notes, "### Linux")
+}
+
+func TestRenderReleaseNotes_Errors(t *testing.T) {
+	_, err := RenderReleaseNotes("{{.Version", ReleaseNotesData{})
+	assert.ErrorContains(t, err, "invalid release notes template")
+
+	_, err = RenderReleaseNotes("{{.Missing}}", ReleaseNotesData{})
+	assert.ErrorContains(t, err, "failed to render release notes")
+}
+
+// notesPublisher records the notes of the release it publishes
+type notesPublisher struct {
+	*stagePublisher
+	mu    *sync.Mutex
+	notes map[string]string
+}
+
+func (p *notesPublisher) Publish(ctx context.Context, release Release) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.notes[p.name] = release.Notes
+	return nil
+}
+
+func TestDistribute_ReleaseNotes(t *testing.T) {
+	brand := filepath.Join(t.TempDir(), "scoop.md.tmpl")
+	require.NoError(t, os.WriteFile(brand, []byte("{{.Project}} {{.Version}} for {{.Publisher}}: {{join .Highlights \", \"}}\n"), 0644))
+
+	config := &DistributionConfig{Publishers: map[string]PublisherConfig{
+		"github": {Enabled: true},
+		"scoop":  {Enabled: true, NotesTemplate: brand},
+	}}
+	notes := make(map[string]string)
+	var mu sync.Mutex
+	coordinator := NewDistributionCoordinator(config)
+	for _, name := range []string{"github", "scoop"} {
+		require.NoError(t, coordinator.RegisterPublisher(&notesPublisher{stagePublisher: &stagePublisher{name: name, log: &publishLog{}}, mu: &mu, notes: notes}))
+	}
+
+	// Only publishers with a template of their own get notes by default
+	require.NoError(t, coordinator.Distribute(context.Background(), notesRelease()))
+	assert.Equal(t, "", notes["github"])
+	assert.Equal(t, "nettracex 1.2.3 for scoop: Parallel DNS lookups, WHOIS expiry warnings\n", notes["scoop"])
+
+	// Enabled, every publisher gets notes
+	config.ReleaseNotes = ReleaseNotesConfig{Enabled: true, Project: "ntx"}
+	require.NoError(t, coordinator.Distribute(context.Background(), notesRelease()))
+	assert.True(t, strings.HasPrefix(notes["github"], "# ntx 1.2.3\n"))
+	assert.Equal(t, "ntx 1.2.3 for scoop: Parallel DNS lookups, WHOIS expiry warnings\n", notes["scoop"])
+
+	// A broken template stops the release before anything is published
+	require.NoError(t, os.WriteFile(brand, []byte("{{.Version"), 0644))
+	err := coordinator.Distribute(context.Background(), notesRelease())
+	assert.ErrorContains(t, err, "release notes rendering failed: publisher scoop: invalid release notes template")
+}
+
+func TestGitHubPublisher_ReleaseBody(t *testing.T) {
+	publisher := NewGitHubPublisher(GitHubConfig{Owner: "nettracex", Repo: "nettracex-tui", Changelog: ChangelogConfig{AutoGenerate: true}})
+
+	release := notesRelease()
+	release.Notes = "# nettracex 1.2.3\n"
+	body, err := publisher.releaseBody(context.Background(), release)
+	require.NoError(t, err)
+	assert.Equal(t, "# nettracex 1.2.3\n", body, "rendered notes replace the generated changelog")
+
+	release.Notes = ""
+	body, err = publisher.releaseBody(context.Background(), release)
+	require.NoError(t, err)
+	assert.Contains(t, body, "## Installation")
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
+	"text/template"
 	"time"
 )
 
@@ -87,12 +89,29 @@ func NewDefaultNotificationService(config NotificationServiceConfig) *DefaultNot
 		channels: make(map[string]NotificationChannel),
 	}
 	
-	// Initialize default channels
-	service.initializeDefaultChannels()
+	// Initialize the configured channels, or the default ones
+	if len(config.Channels) > 0 {
+		service.initializeConfiguredChannels()
+	} else {
+		service.initializeDefaultChannels()
+	}
 	
 	return service
 }
 
+// initializeConfiguredChannels creates the channels of the configuration,
+// skipping the ones that are misconfigured
+func (dns *DefaultNotificationService) initializeConfiguredChannels() {
+	for name, channelConfig := range dns.config.Channels {
+		channel, err := NewNotificationChannel(name, channelConfig)
+		if err != nil {
+			log.Printf("Skipping notification channel %s: %v", name, err)
+			continue
+		}
+		dns.channels[name] = channel
+	}
+}
+
 // initializeDefaultChannels initializes default notification channels
 func (dns *DefaultNotificationService) initializeDefaultChannels() {
 	// Add log channel
@@ -190,6 +209,8 @@ func (dns *DefaultNotificationService) NotifyProgress(publisher string, release
 func (dns *DefaultNotificationService) sendNotification(ctx context.Context, notification Notification) error {
 	var lastError error
 	
+	notification.Message = dns.renderMessage(notification)
+	
 	for _, channel := range dns.channels {
 		if !channel.IsEnabled() {
 			continue
@@ -204,6 +225,31 @@ func (dns *DefaultNotificationService) sendNotification(ctx context.Context, not
 	return lastError
 }
 
+// renderMessage renders the message of a notification with the template
+// configured for its publisher and type ("<publisher>.<type>"), or for its
+// type, keeping the default message when there is none or it fails
+func (dns *DefaultNotificationService) renderMessage(notification Notification) string {
+	text, exists := dns.config.Templates[notification.Publisher+"."+string(notification.Type)]
+	if !exists {
+		text, exists = dns.config.Templates[string(notification.Type)]
+	}
+	if !exists {
+		return notification.Message
+	}
+	
+	tmpl, err := template.New(string(notification.Type)).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		log.Printf("Invalid %s notification template: %v", notification.Type, err)
+		return notification.Message
+	}
+	var message strings.Builder
+	if err := tmpl.Execute(&message, notification); err != nil {
+		log.Printf("Failed to render %s notification template: %v", notification.Type, err)
+		return notification.Message
+	}
+	return message.String()
+}
+
 // sendWithRetry sends a notification with retry logic
 func (dns *DefaultNotificationService) sendWithRetry(ctx context.Context, channel NotificationChannel, notification Notification) error {
 	policy := dns.config.RetryPolicy
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewDefaultNotificationService(t *testing.T) {
@@ -29,6 +30,48 @@ func TestNewDefaultNotificationService(t *testing.T) {
 	assert.Len(t, service.channels, 2) // log and console channels
 }
 
+func TestNewDefaultNotificationService_ConfiguredChannels(t *testing.T) {
+	service := NewDefaultNotificationService(NotificationServiceConfig{
+		Enabled: true,
+		Channels: map[string]NotificationChannelConfig{
+			"console": {Enabled: true},
+			"ops":     {Type: "slack", Enabled: true, Config: map[string]interface{}{"webhook_url": "https://hooks.slack.com/services/T/B/X"}},
+			"discord": {Enabled: true}, // without a webhook_url
+		},
+	})
+
+	assert.Len(t, service.channels, 2)
+	assert.IsType(t, &ConsoleNotificationChannel{}, service.channels["console"])
+	assert.IsType(t, &SlackNotificationChannel{}, service.channels["ops"])
+	assert.NotContains(t, service.channels, "discord")
+}
+
+func TestDefaultNotificationService_Templates(t *testing.T) {
+	service := NewDefaultNotificationService(NotificationServiceConfig{
+		Enabled: true,
+		Templates: map[string]string{
+			"success":          "{{.Release.Version}} is out on {{.Publisher}}",
+			"homebrew.success": "brew upgrade nettracex for {{.Release.Version}}",
+			"failure":          "{{.Publisher}} failed: {{.Error}",
+		},
+	})
+	mockChannel := &MockNotificationChannel{name: "mock", enabled: true}
+	service.channels = map[string]NotificationChannel{"mock": mockChannel}
+
+	release := Release{Version: "v1.2.3", Tag: "v1.2.3"}
+	assert.NoError(t, service.NotifySuccess("scoop", release))
+	assert.NoError(t, service.NotifySuccess("homebrew", release))
+	assert.NoError(t, service.NotifyFailure("scoop", release, errors.New("bucket is read-only")))
+	assert.NoError(t, service.NotifyProgress("scoop", release, 0.5))
+
+	require.Len(t, mockChannel.notifications, 4)
+	assert.Equal(t, "v1.2.3 is out on scoop", mockChannel.notifications[0].Message)
+	assert.Equal(t, "brew upgrade nettracex for v1.2.3", mockChannel.notifications[1].Message)
+	// An invalid template keeps the default message, as does a type without one
+	assert.Equal(t, "Failed to publish release v1.2.3 to scoop: bucket is read-only", mockChannel.notifications[2].Message)
+	assert.Equal(t, "Publishing release v1.2.3 to scoop: 50.0% complete", mockChannel.notifications[3].Message)
+}
+
 func TestDefaultNotificationService_RegisterChannel(t *testing.T) {
 	service := NewDefaultNotificationService(NotificationServiceConfig{})
 	
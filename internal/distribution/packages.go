@@ -0,0 +1,337 @@
+package distribution
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PackageFormat is a Linux package format
+type PackageFormat string
+
+const (
+	// Deb is the Debian package format installed with apt
+	Deb PackageFormat = "deb"
+	// RPM is the RPM package format installed with dnf or yum
+	RPM PackageFormat = "rpm"
+)
+
+// PackageFormats lists the supported package formats
+var PackageFormats = []PackageFormat{Deb, RPM}
+
+// packageArchitectures maps Go architectures to the names each package
+// format uses
+var packageArchitectures = map[PackageFormat]map[string]string{
+	Deb: {"amd64": "amd64", "arm64": "arm64", "386": "i386", "arm": "armhf"},
+	RPM: {"amd64": "x86_64", "arm64": "aarch64", "386": "i386", "arm": "armv7hl"},
+}
+
+// completionPaths are where each package format installs the completion
+// script of each shell. Debian's zsh looks in vendor-completions, Fedora's
+// in site-functions.
+var completionPaths = map[PackageFormat]map[string]string{
+	Deb: {
+		"bash": "/usr/share/bash-completion/completions/%s",
+		"zsh":  "/usr/share/zsh/vendor-completions/_%s",
+		"fish": "/usr/share/fish/vendor_completions.d/%s.fish",
+	},
+	RPM: {
+		"bash": "/usr/share/bash-completion/completions/%s",
+		"zsh":  "/usr/share/zsh/site-functions/_%s",
+		"fish": "/usr/share/fish/vendor_completions.d/%s.fish",
+	},
+}
+
+// ParsePackageFormats parses a comma-separated list of package formats
+func ParsePackageFormats(list string) ([]PackageFormat, error) {
+	var formats []PackageFormat
+	for _, name := range strings.Split(list, ",") {
+		format := PackageFormat(strings.ToLower(strings.TrimSpace(name)))
+		if _, supported := packageArchitectures[format]; !supported {
+			return nil, fmt.Errorf("unsupported package format %q (use deb or rpm)", name)
+		}
+		formats = append(formats, format)
+	}
+	return formats, nil
+}
+
+// IsPackageFile reports whether a file is a Linux package
+func IsPackageFile(filename string) bool {
+	return PackageFormatOf(filename) != ""
+}
+
+// PackageFormatOf returns the format of a package file, or "" when the file
+// is not a package
+func PackageFormatOf(filename string) PackageFormat {
+	for _, format := range PackageFormats {
+		if strings.HasSuffix(filename, "."+string(format)) {
+			return format
+		}
+	}
+	return ""
+}
+
+// PackageGoArch returns the Go architecture of a package file named by
+// PackageFilename, or "unknown"
+func PackageGoArch(filename string) string {
+	format := PackageFormatOf(filename)
+	base := strings.TrimSuffix(filename, "."+string(format))
+	for goarch, arch := range packageArchitectures[format] {
+		if strings.HasSuffix(base, "_"+arch) || strings.HasSuffix(base, "."+arch) {
+			return goarch
+		}
+	}
+	return "unknown"
+}
+
+// PackageFilename returns the conventional file name of a package:
+// name_version_arch.deb and name-version-1.arch.rpm
+func PackageFilename(name, version, goarch string, format PackageFormat) (string, error) {
+	arch, supported := packageArchitectures[format][goarch]
+	if !supported {
+		return "", fmt.Errorf("architecture %s is not supported by %s packages", goarch, format)
+	}
+	version = strings.TrimPrefix(version, "v")
+	if format == RPM {
+		return fmt.Sprintf("%s-%s-1.%s.rpm", name, version, arch), nil
+	}
+	return fmt.Sprintf("%s_%s_%s.deb", name, version, arch), nil
+}
+
+// PackageConfig describes the packages built for a binary, in the terms
+// nfpm uses. ManPages and Completions are installed where each format
+// expects them.
+type PackageConfig struct {
+	Name        string   `yaml:"name" json:"name"`
+	Maintainer  string   `yaml:"maintainer" json:"maintainer"`
+	Description string   `yaml:"description" json:"description"`
+	Vendor      string   `yaml:"vendor" json:"vendor"`
+	Homepage    string   `yaml:"homepage" json:"homepage"`
+	License     string   `yaml:"license" json:"license"`
+	Section     string   `yaml:"section" json:"section"`
+	Priority    string   `yaml:"priority" json:"priority"`
+	Depends     []string `yaml:"depends" json:"depends"`
+	Recommends  []string `yaml:"recommends" json:"recommends"`
+	Suggests    []string `yaml:"suggests" json:"suggests"`
+	Conflicts   []string `yaml:"conflicts" json:"conflicts"`
+	// ManPages are roff man pages, compressed into /usr/share/man on
+	// install
+	ManPages []string `yaml:"man_pages" json:"man_pages"`
+	// Completions are the completion scripts of each shell
+	Completions map[string]string `yaml:"completions" json:"completions"`
+	// Contents are further files installed as they are
+	Contents []PackageContent `yaml:"contents" json:"contents"`
+	// MSI describes the Windows installers built of the Windows binaries
+	MSI MSIConfig `yaml:"msi" json:"msi"`
+}
+
+// PackageContent is a file installed by a package
+type PackageContent struct {
+	Src      string           `yaml:"src" json:"src"`
+	Dst      string           `yaml:"dst" json:"dst"`
+	Type     string           `yaml:"type,omitempty" json:"type,omitempty"`
+	FileInfo *PackageFileInfo `yaml:"file_info,omitempty" json:"file_info,omitempty"`
+}
+
+// PackageFileInfo sets the mode of an installed file
+type PackageFileInfo struct {
+	Mode os.FileMode `yaml:"mode" json:"mode"`
+}
+
+// LoadPackageConfig reads a package configuration from a YAML file
+func LoadPackageConfig(path string) (PackageConfig, error) {
+	var config PackageConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return config, config.Validate()
+}
+
+// Validate checks the fields every package needs
+func (pc PackageConfig) Validate() error {
+	if pc.Name == "" {
+		return fmt.Errorf("package name is required")
+	}
+	if pc.Maintainer == "" {
+		return fmt.Errorf("package maintainer is required")
+	}
+	if pc.Description == "" {
+		return fmt.Errorf("package description is required")
+	}
+	for shell := range pc.Completions {
+		if _, supported := completionPaths[Deb][shell]; !supported {
+			return fmt.Errorf("unsupported completion shell %q", shell)
+		}
+	}
+	return nil
+}
+
+// nfpmConfig is the nfpm configuration of one package
+type nfpmConfig struct {
+	Name        string           `yaml:"name"`
+	Arch        string           `yaml:"arch"`
+	Platform    string           `yaml:"platform"`
+	Version     string           `yaml:"version"`
+	Maintainer  string           `yaml:"maintainer"`
+	Description string           `yaml:"description"`
+	Vendor      string           `yaml:"vendor,omitempty"`
+	Homepage    string           `yaml:"homepage,omitempty"`
+	License     string           `yaml:"license,omitempty"`
+	Section     string           `yaml:"section,omitempty"`
+	Priority    string           `yaml:"priority,omitempty"`
+	Depends     []string         `yaml:"depends,omitempty"`
+	Recommends  []string         `yaml:"recommends,omitempty"`
+	Suggests    []string         `yaml:"suggests,omitempty"`
+	Conflicts   []string         `yaml:"conflicts,omitempty"`
+	Contents    []PackageContent `yaml:"contents"`
+}
+
+// PackageBuilder builds deb and rpm packages of release binaries with nfpm
+type PackageBuilder struct {
+	config PackageConfig
+	run    commandRunner
+}
+
+// NewPackageBuilder creates a package builder
+func NewPackageBuilder(config PackageConfig) *PackageBuilder {
+	return &PackageBuilder{config: config, run: runCommand}
+}
+
+// Build packages a Linux binary in format, writing the package to outputDir,
+// and returns the package path
+func (pb *PackageBuilder) Build(ctx context.Context, binary Binary, version string, format PackageFormat, outputDir string) (string, error) {
+	if err := pb.config.Validate(); err != nil {
+		return "", err
+	}
+	if binary.Platform != "linux" {
+		return "", fmt.Errorf("%s packages need a linux binary, not %s", format, binary.Platform)
+	}
+	version = strings.TrimPrefix(version, "v")
+	if version == "" || version[0] < '0' || version[0] > '9' {
+		return "", fmt.Errorf("package version %q must start with a digit", version)
+	}
+	filename, err := PackageFilename(pb.config.Name, version, binary.Architecture, format)
+	if err != nil {
+		return "", err
+	}
+
+	workDir, err := os.MkdirTemp("", "nfpm-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(workDir)
+
+	config, err := pb.nfpmConfig(binary, version, format, workDir)
+	if err != nil {
+		return "", err
+	}
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	configPath := filepath.Join(workDir, "nfpm.yaml")
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		return "", err
+	}
+
+	target := filepath.Join(outputDir, filename)
+	if err := run(ctx, pb.run, "nfpm", "package", "--config", configPath, "--packager", string(format), "--target", target); err != nil {
+		return "", fmt.Errorf("failed to build %s: %w", filename, err)
+	}
+	return target, nil
+}
+
+// nfpmConfig returns the nfpm configuration packaging binary, with the man
+// pages compressed into workDir
+func (pb *PackageBuilder) nfpmConfig(binary Binary, version string, format PackageFormat, workDir string) (nfpmConfig, error) {
+	config := nfpmConfig{
+		Name:        pb.config.Name,
+		Arch:        binary.Architecture,
+		Platform:    "linux",
+		Version:     version,
+		Maintainer:  pb.config.Maintainer,
+		Description: pb.config.Description,
+		Vendor:      pb.config.Vendor,
+		Homepage:    pb.config.Homepage,
+		License:     pb.config.License,
+		Section:     pb.config.Section,
+		Priority:    pb.config.Priority,
+		Depends:     pb.config.Depends,
+		Recommends:  pb.config.Recommends,
+		Suggests:    pb.config.Suggests,
+		Conflicts:   pb.config.Conflicts,
+	}
+
+	config.Contents = append(config.Contents, PackageContent{
+		Src:      binary.FilePath,
+		Dst:      "/usr/bin/" + pb.config.Name,
+		FileInfo: &PackageFileInfo{Mode: 0755},
+	})
+
+	for _, page := range pb.config.ManPages {
+		compressed := filepath.Join(workDir, filepath.Base(page)+".gz")
+		if err := gzipFile(page, compressed); err != nil {
+			return config, fmt.Errorf("failed to compress man page %s: %w", page, err)
+		}
+		section := strings.TrimPrefix(filepath.Ext(page), ".")
+		config.Contents = append(config.Contents, PackageContent{
+			Src:      compressed,
+			Dst:      fmt.Sprintf("/usr/share/man/man%s/%s.gz", section, filepath.Base(page)),
+			FileInfo: &PackageFileInfo{Mode: 0644},
+		})
+	}
+
+	shells := make([]string, 0, len(pb.config.Completions))
+	for shell := range pb.config.Completions {
+		shells = append(shells, shell)
+	}
+	sort.Strings(shells)
+	for _, shell := range shells {
+		config.Contents = append(config.Contents, PackageContent{
+			Src:      pb.config.Completions[shell],
+			Dst:      fmt.Sprintf(completionPaths[format][shell], pb.config.Name),
+			FileInfo: &PackageFileInfo{Mode: 0644},
+		})
+	}
+
+	config.Contents = append(config.Contents, pb.config.Contents...)
+	return config, nil
+}
+
+// gzipFile writes the gzip-compressed content of src to dst
+func gzipFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	writer, err := gzip.NewWriterLevel(out, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(writer, in); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
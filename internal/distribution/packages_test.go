@@ -0,0 +1,143 @@
+package distribution
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestPackageFilename(t *testing.T) {
+	name, err := PackageFilename("nettracex", "v1.2.3", "amd64", Deb)
+	require.NoError(t, err)
+	assert.Equal(t, "nettracex_1.2.3_amd64.deb", name)
+	assert.Equal(t, "amd64", PackageGoArch(name))
+
+	name, err = PackageFilename("nettracex", "1.2.3", "arm64", RPM)
+	require.NoError(t, err)
+	assert.Equal(t, "nettracex-1.2.3-1.aarch64.rpm", name)
+	assert.Equal(t, "arm64", PackageGoArch(name))
+	assert.Equal(t, RPM, PackageFormatOf(name))
+
+	_, err = PackageFilename("nettracex", "1.2.3", "riscv64", Deb)
+	assert.Error(t, err)
+
+	formats, err := ParsePackageFormats("deb, RPM")
+	require.NoError(t, err)
+	assert.Equal(t, []PackageFormat{Deb, RPM}, formats)
+	_, err = ParsePackageFormats("deb,apk")
+	assert.Error(t, err)
+
+	assert.False(t, IsPackageFile("nettracex-linux-amd64"))
+}
+
+func TestLoadPackageConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "package.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`name: nettracex
+maintainer: NetTraceX <packages@nettracex.dev>
+description: Network diagnostic toolkit
+depends: [ca-certificates]
+man_pages: [packaging/nettracex.1]
+completions:
+  bash: completions/nettracex.bash
+contents:
+  - src: LICENSE
+    dst: /usr/share/doc/nettracex/copyright
+    file_info:
+      mode: 0o644
+`), 0644))
+
+	config, err := LoadPackageConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ca-certificates"}, config.Depends)
+	assert.Equal(t, "completions/nettracex.bash", config.Completions["bash"])
+	require.Len(t, config.Contents, 1)
+	assert.Equal(t, os.FileMode(0644), config.Contents[0].FileInfo.Mode)
+
+	config.Completions["tcsh"] = "completions/nettracex.tcsh"
+	assert.ErrorContains(t, config.Validate(), "tcsh")
+	config.Maintainer = ""
+	assert.ErrorContains(t, config.Validate(), "maintainer")
+}
+
+func TestPackageBuilder_Build(t *testing.T) {
+	dir := t.TempDir()
+	manPage := filepath.Join(dir, "nettracex.1")
+	require.NoError(t, os.WriteFile(manPage, []byte(".TH NETTRACEX 1\n"), 0644))
+
+	builder := NewPackageBuilder(PackageConfig{
+		Name:        "nettracex",
+		Maintainer:  "NetTraceX <packages@nettracex.dev>",
+		Description: "Network diagnostic toolkit",
+		Depends:     []string{"ca-certificates"},
+		ManPages:    []string{manPage},
+		Completions: map[string]string{"zsh": "completions/_nettracex", "bash": "completions/nettracex.bash"},
+	})
+
+	// The runner reads the nfpm configuration while it still exists
+	var commands [][]string
+	var config nfpmConfig
+	var manPageContent string
+	builder.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		commands = append(commands, append([]string{name}, args...))
+		data, err := os.ReadFile(args[2])
+		require.NoError(t, err)
+		require.NoError(t, yaml.Unmarshal(data, &config))
+
+		file, err := os.Open(config.Contents[1].Src)
+		require.NoError(t, err)
+		defer file.Close()
+		reader, err := gzip.NewReader(file)
+		require.NoError(t, err)
+		content, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		manPageContent = string(content)
+		return nil, nil
+	}
+
+	binary := Binary{Platform: "linux", Architecture: "arm64", Filename: "nettracex-linux-arm64", FilePath: "bin/nettracex-linux-arm64"}
+	path, err := builder.Build(context.Background(), binary, "v1.2.3", Deb, "dist")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("dist", "nettracex_1.2.3_arm64.deb"), path)
+
+	require.Len(t, commands, 1)
+	assert.Equal(t, []string{"nfpm", "package", "--config"}, commands[0][:3])
+	assert.Equal(t, []string{"--packager", "deb", "--target", path}, commands[0][4:])
+
+	assert.Equal(t, "arm64", config.Arch)
+	assert.Equal(t, "1.2.3", config.Version)
+	assert.Equal(t, []string{"ca-certificates"}, config.Depends)
+	require.Len(t, config.Contents, 4)
+	assert.Equal(t, PackageContent{Src: binary.FilePath, Dst: "/usr/bin/nettracex", FileInfo: &PackageFileInfo{Mode: 0755}}, config.Contents[0])
+	assert.Equal(t, "/usr/share/man/man1/nettracex.1.gz", config.Contents[1].Dst)
+	assert.Equal(t, ".TH NETTRACEX 1\n", manPageContent)
+	assert.Equal(t, "/usr/share/bash-completion/completions/nettracex", config.Contents[2].Dst)
+	assert.Equal(t, "/usr/share/zsh/vendor-completions/_nettracex", config.Contents[3].Dst)
+
+	// RPM installs zsh completions where Fedora looks for them
+	path, err = builder.Build(context.Background(), binary, "1.2.3", RPM, "dist")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("dist", "nettracex-1.2.3-1.aarch64.rpm"), path)
+	assert.Equal(t, "/usr/share/zsh/site-functions/_nettracex", config.Contents[3].Dst)
+
+	// Packages need a Linux binary and a numeric version
+	_, err = builder.Build(context.Background(), Binary{Platform: "darwin", Architecture: "arm64"}, "1.2.3", Deb, "dist")
+	assert.Error(t, err)
+	_, err = builder.Build(context.Background(), binary, "dev", Deb, "dist")
+	assert.ErrorContains(t, err, "must start with a digit")
+
+	// The nfpm output explains a failure
+	builder.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		return []byte("nfpm: invalid arch\n"), errors.New("exit status 1")
+	}
+	_, err = builder.Build(context.Background(), binary, "1.2.3", Deb, "dist")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid arch")
+}
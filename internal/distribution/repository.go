@@ -0,0 +1,208 @@
+package distribution
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PackageRepositoryPublisher uploads the deb or rpm packages of a release to
+// an apt or yum repository that indexes what is uploaded to it, such as an
+// Artifactory Debian or RPM repository
+type PackageRepositoryPublisher struct {
+	config PackageRepositoryConfig
+	client *http.Client
+	status PublishStatus
+}
+
+// PackageRepositoryConfig contains apt or yum repository configuration
+type PackageRepositoryConfig struct {
+	Type string `json:"type"` // "apt" or "yum"
+	URL  string `json:"url"`  // base URL packages are uploaded under
+	// Distribution and Component place packages of an apt repository,
+	// e.g. "stable" and "main"
+	Distribution string `json:"distribution"`
+	Component    string `json:"component"`
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+	Token        string `json:"token"`
+}
+
+// repositoryFormats maps repository types to the package format they hold
+var repositoryFormats = map[string]PackageFormat{
+	"apt": Deb,
+	"yum": RPM,
+}
+
+// NewPackageRepositoryPublisher creates a new apt or yum repository
+// publisher
+func NewPackageRepositoryPublisher(config PackageRepositoryConfig) (*PackageRepositoryPublisher, error) {
+	if _, supported := repositoryFormats[config.Type]; !supported {
+		return nil, fmt.Errorf("unsupported repository type %q (use apt or yum)", config.Type)
+	}
+	if config.URL == "" {
+		return nil, fmt.Errorf("repository URL is required")
+	}
+	if config.Type == "apt" {
+		if config.Distribution == "" {
+			config.Distribution = "stable"
+		}
+		if config.Component == "" {
+			config.Component = "main"
+		}
+	}
+	config.URL = strings.TrimSuffix(config.URL, "/")
+
+	return &PackageRepositoryPublisher{
+		config: config,
+		client: &http.Client{Timeout: 5 * time.Minute},
+		status: PublishStatus{
+			Name:   config.Type,
+			Status: StatusIdle,
+		},
+	}, nil
+}
+
+// GetName returns the publisher name
+func (p *PackageRepositoryPublisher) GetName() string {
+	return p.config.Type
+}
+
+// Publish uploads the packages of a release to the repository
+func (p *PackageRepositoryPublisher) Publish(ctx context.Context, release Release) error {
+	p.updateStatus(StatusPublishing, "")
+
+	for _, filename := range p.packages(release) {
+		if err := p.upload(ctx, release.Packages[filename]); err != nil {
+			p.updateStatus(StatusError, err.Error())
+			return fmt.Errorf("failed to upload %s: %w", filename, err)
+		}
+	}
+
+	p.updateStatus(StatusSuccess, "")
+	return nil
+}
+
+// DryRun lists the packages that would be uploaded and where
+func (p *PackageRepositoryPublisher) DryRun(ctx context.Context, release Release) (*DryRunReport, error) {
+	report := &DryRunReport{}
+	if err := p.Validate(ctx, release); err != nil {
+		return report, err
+	}
+	for _, filename := range p.packages(release) {
+		report.Actions = append(report.Actions, fmt.Sprintf("upload %s to %s", filename, p.uploadURL(release.Packages[filename])))
+	}
+	return report, nil
+}
+
+// Validate validates a release for the repository
+func (p *PackageRepositoryPublisher) Validate(ctx context.Context, release Release) error {
+	packages := p.packages(release)
+	if len(packages) == 0 {
+		return fmt.Errorf("no %s packages found in release", repositoryFormats[p.config.Type])
+	}
+	for _, filename := range packages {
+		if release.Packages[filename].FilePath == "" {
+			return fmt.Errorf("package %s missing file path", filename)
+		}
+	}
+	return nil
+}
+
+// GetStatus returns the current status of the repository publisher
+func (p *PackageRepositoryPublisher) GetStatus() PublishStatus {
+	p.status.Metadata = map[string]string{
+		"url": p.config.URL,
+	}
+	if p.config.Type == "apt" {
+		p.status.Metadata["distribution"] = p.config.Distribution
+		p.status.Metadata["component"] = p.config.Component
+	}
+	return p.status
+}
+
+// updateStatus updates the publisher status
+func (p *PackageRepositoryPublisher) updateStatus(status StatusType, lastError string) {
+	p.status.Status = status
+	p.status.LastError = lastError
+	if status == StatusSuccess {
+		p.status.LastPublish = time.Now()
+		p.status.PublishCount++
+	} else if status == StatusError {
+		p.status.ErrorCount++
+	}
+}
+
+// packages returns the names of the release packages the repository holds,
+// in name order
+func (p *PackageRepositoryPublisher) packages(release Release) []string {
+	format := repositoryFormats[p.config.Type]
+	var names []string
+	for filename := range release.Packages {
+		if PackageFormatOf(filename) == format {
+			names = append(names, filename)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// uploadURL returns where a package is uploaded. Debian packages carry the
+// distribution, component and architecture they are indexed under as
+// matrix parameters.
+func (p *PackageRepositoryPublisher) uploadURL(pkg Binary) string {
+	target := p.config.URL + "/" + url.PathEscape(pkg.Filename)
+	if p.config.Type == "apt" {
+		arch := packageArchitectures[Deb][pkg.Architecture]
+		target += fmt.Sprintf(";deb.distribution=%s;deb.component=%s;deb.architecture=%s",
+			url.PathEscape(p.config.Distribution), url.PathEscape(p.config.Component), arch)
+	}
+	return target
+}
+
+// upload puts a package into the repository
+func (p *PackageRepositoryPublisher) upload(ctx context.Context, pkg Binary) error {
+	file, err := os.Open(pkg.FilePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, p.uploadURL(pkg), file)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if pkg.Checksum != "" {
+		req.Header.Set("X-Checksum-Sha256", pkg.Checksum)
+	}
+	if p.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.config.Token)
+	} else if p.config.Username != "" {
+		req.SetBasicAuth(p.config.Username, p.config.Password)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("upload failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
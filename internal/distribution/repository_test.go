@@ -0,0 +1,89 @@
+package distribution
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func packageRelease(t *testing.T) Release {
+	dir := t.TempDir()
+	release := Release{Version: "v1.2.3", Packages: make(map[string]Binary)}
+	for _, name := range []string{"nettracex_1.2.3_amd64.deb", "nettracex_1.2.3_arm64.deb", "nettracex-1.2.3-1.x86_64.rpm"} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(name), 0644))
+		release.Packages[name] = Binary{Platform: "linux", Architecture: PackageGoArch(name), Filename: name, FilePath: path}
+	}
+	return release
+}
+
+func TestNewPackageRepositoryPublisher(t *testing.T) {
+	_, err := NewPackageRepositoryPublisher(PackageRepositoryConfig{Type: "apk", URL: "https://packages.example.com"})
+	assert.Error(t, err)
+	_, err = NewPackageRepositoryPublisher(PackageRepositoryConfig{Type: "apt"})
+	assert.Error(t, err)
+
+	publisher, err := NewPackageRepositoryPublisher(PackageRepositoryConfig{Type: "apt", URL: "https://packages.example.com/debian/"})
+	require.NoError(t, err)
+	assert.Equal(t, "apt", publisher.GetName())
+	assert.Equal(t, "stable", publisher.GetStatus().Metadata["distribution"])
+	assert.Equal(t, "main", publisher.GetStatus().Metadata["component"])
+}
+
+func TestPackageRepositoryPublisher_Publish(t *testing.T) {
+	uploads := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		user, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "ci:secret", user+":"+password)
+		body, _ := io.ReadAll(r.Body)
+		uploads[r.URL.Path] = string(body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	release := packageRelease(t)
+	apt, err := NewPackageRepositoryPublisher(PackageRepositoryConfig{
+		Type:         "apt",
+		URL:          server.URL + "/debian",
+		Distribution: "noble",
+		Username:     "ci",
+		Password:     "secret",
+	})
+	require.NoError(t, err)
+	require.NoError(t, apt.Validate(context.Background(), release))
+	require.NoError(t, apt.Publish(context.Background(), release))
+
+	assert.Equal(t, map[string]string{
+		"/debian/nettracex_1.2.3_amd64.deb;deb.distribution=noble;deb.component=main;deb.architecture=amd64": "nettracex_1.2.3_amd64.deb",
+		"/debian/nettracex_1.2.3_arm64.deb;deb.distribution=noble;deb.component=main;deb.architecture=arm64": "nettracex_1.2.3_arm64.deb",
+	}, uploads, "only Debian packages go to an apt repository")
+	assert.Equal(t, StatusSuccess, apt.GetStatus().Status)
+
+	// A yum repository takes the RPM packages as they are
+	uploads = make(map[string]string)
+	yum, err := NewPackageRepositoryPublisher(PackageRepositoryConfig{Type: "yum", URL: server.URL + "/rpm", Username: "ci", Password: "secret"})
+	require.NoError(t, err)
+	require.NoError(t, yum.Publish(context.Background(), release))
+	assert.Equal(t, map[string]string{"/rpm/nettracex-1.2.3-1.x86_64.rpm": "nettracex-1.2.3-1.x86_64.rpm"}, uploads)
+
+	// A refused upload is reported
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	})
+	err = yum.Publish(context.Background(), release)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "forbidden")
+	assert.Equal(t, StatusError, yum.GetStatus().Status)
+
+	// A release without packages has nothing to publish
+	assert.Error(t, yum.Validate(context.Background(), Release{Version: "v1.2.3"}))
+}
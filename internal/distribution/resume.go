@@ -0,0 +1,64 @@
+package distribution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+)
+
+// Resume publishes a release again, skipping the publishers its record shows
+// already published it, so an interrupted or partially failed run only
+// retries what did not go out. Publishes that were rolled back are redone.
+// Without a record, the whole release is distributed.
+func (dc *DistributionCoordinator) Resume(ctx context.Context, release Release) error {
+	completed, err := dc.Completed(release.Tag)
+	if err != nil {
+		return err
+	}
+	if len(completed) == 0 {
+		log.Printf("Release %s has no completed publishers, distributing it in full", release.Tag)
+	} else {
+		log.Printf("Resuming release %s, already published by: %v", release.Tag, sortedKeys(completed))
+	}
+	return dc.distribute(ctx, release, completed)
+}
+
+// Completed returns the publishers the record of a tag shows succeeded and
+// were not rolled back
+func (dc *DistributionCoordinator) Completed(tag string) (map[string]bool, error) {
+	dc.mu.RLock()
+	store := dc.store
+	dc.mu.RUnlock()
+	if store == nil {
+		return nil, fmt.Errorf("no release store configured")
+	}
+
+	record, err := store.Load(tag)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	completed := make(map[string]bool)
+	for name, publish := range record.Publishers {
+		if publish.Status == StatusSuccess && publish.RolledBackAt.IsZero() {
+			completed[name] = true
+		}
+	}
+	return completed, nil
+}
+
+// sortedKeys returns the keys of a set in order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
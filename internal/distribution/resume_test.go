@@ -0,0 +1,87 @@
+package distribution
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistributionCoordinator_Resume(t *testing.T) {
+	log := &publishLog{}
+	coordinator := NewDistributionCoordinator(&DistributionConfig{Publishers: map[string]PublisherConfig{
+		"github":   {Enabled: true, Priority: 1},
+		"gomodule": {Enabled: true, Priority: 2},
+		"homebrew": {Enabled: true, Priority: 3, Stage: 1, DependsOn: []string{"github"}},
+		"scoop":    {Enabled: true, Priority: 4, Stage: 1, DependsOn: []string{"github"}},
+	}})
+	store := NewReleaseStore(t.TempDir())
+	coordinator.SetReleaseStore(store)
+
+	homebrew := &stagePublisher{name: "homebrew", log: log, err: errors.New("tap is read-only")}
+	for _, publisher := range []Publisher{
+		&stagePublisher{name: "github", log: log},
+		&stagePublisher{name: "gomodule", log: log},
+		homebrew,
+		&stagePublisher{name: "scoop", log: log},
+	} {
+		require.NoError(t, coordinator.RegisterPublisher(publisher))
+	}
+	release := Release{Version: "v1.2.3", Tag: "v1.2.3"}
+
+	require.Error(t, coordinator.Distribute(context.Background(), release))
+	completed, err := coordinator.Completed("v1.2.3")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"github": true, "gomodule": true, "scoop": true}, completed)
+
+	// Only the failed publisher is retried, its dependency counting as
+	// succeeded
+	log.events = nil
+	homebrew.err = nil
+	require.NoError(t, coordinator.Resume(context.Background(), release))
+	assert.Equal(t, []string{"start homebrew", "end homebrew"}, log.events)
+
+	completed, err = coordinator.Completed("v1.2.3")
+	require.NoError(t, err)
+	assert.Len(t, completed, 4)
+
+	// Nothing is left to publish
+	log.events = nil
+	require.NoError(t, coordinator.Resume(context.Background(), release))
+	assert.Empty(t, log.events)
+}
+
+func TestDistributionCoordinator_ResumeWithoutRecord(t *testing.T) {
+	log := &publishLog{}
+	coordinator := stageCoordinator(t, map[string]PublisherConfig{
+		"github":   {Enabled: true, Priority: 1},
+		"homebrew": {Enabled: true, Priority: 3, DependsOn: []string{"github"}},
+	}, log)
+
+	// Resuming needs somewhere to read progress from
+	assert.EqualError(t, coordinator.Resume(context.Background(), Release{Version: "v1.2.3", Tag: "v1.2.3"}), "no release store configured")
+
+	// A release that was never distributed is distributed in full
+	coordinator.SetReleaseStore(NewReleaseStore(t.TempDir()))
+	require.NoError(t, coordinator.Resume(context.Background(), Release{Version: "v1.2.3", Tag: "v1.2.3"}))
+	assert.Less(t, log.index("end github"), log.index("start homebrew"))
+}
+
+func TestDistributionCoordinator_CompletedSkipsRolledBack(t *testing.T) {
+	coordinator := NewDistributionCoordinator(&DistributionConfig{})
+	store := NewReleaseStore(t.TempDir())
+	coordinator.SetReleaseStore(store)
+
+	require.NoError(t, store.Save(&ReleaseRecord{Version: "v1.2.3", Tag: "v1.2.3", Publishers: map[string]PublishRecord{
+		"github":   {Publisher: "github", Status: StatusSuccess},
+		"homebrew": {Publisher: "homebrew", Status: StatusSuccess, RolledBackAt: time.Now()},
+		"scoop":    {Publisher: "scoop", Status: StatusError, Details: map[string]string{"id": "manifest"}},
+	}}))
+
+	completed, err := coordinator.Completed("v1.2.3")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"github": true}, completed)
+}
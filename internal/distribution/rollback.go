@@ -0,0 +1,277 @@
+package distribution
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RollbackPublisher is implemented by publishers that can undo what they
+// published for a release
+type RollbackPublisher interface {
+	Publisher
+	// PublishRecord returns what publishing a release created, or nil when
+	// it created nothing
+	PublishRecord(release Release) map[string]string
+	// Rollback undoes a recorded publish, returning what it did or what is
+	// left to do by hand
+	Rollback(ctx context.Context, record PublishRecord, options RollbackOptions) (string, error)
+}
+
+// RollbackOptions controls how a release is rolled back
+type RollbackOptions struct {
+	// Reason is shown wherever the release is marked as withdrawn
+	Reason string `json:"reason"`
+	// Yank marks the release as withdrawn, where a publisher supports it,
+	// instead of deleting it
+	Yank bool `json:"yank"`
+}
+
+// PublishRecord records what a publisher published for a release
+type PublishRecord struct {
+	Publisher   string            `json:"publisher"`
+	Version     string            `json:"version"`
+	Tag         string            `json:"tag"`
+	Status      StatusType        `json:"status"`
+	PublishedAt time.Time         `json:"published_at"`
+	Details     map[string]string `json:"details,omitempty"`
+	// RolledBackAt is when the publish was rolled back, and RollbackResult
+	// what the rollback did
+	RolledBackAt   time.Time `json:"rolled_back_at,omitzero"`
+	RollbackResult string    `json:"rollback_result,omitempty"`
+}
+
+// ReleaseRecord records what each publisher published for a release
+type ReleaseRecord struct {
+	Version    string                   `json:"version"`
+	Tag        string                   `json:"tag"`
+	Publishers map[string]PublishRecord `json:"publishers"`
+}
+
+// RollbackResult is the outcome of rolling back a publisher
+type RollbackResult struct {
+	Publisher string
+	Message   string
+	// Manual is set when the publisher has to be rolled back by hand
+	Manual bool
+	Err    error
+}
+
+// ReleaseStore keeps release records as JSON files in a directory, one per
+// tag
+type ReleaseStore struct {
+	dir string
+	mu  sync.Mutex // serializes saves, which read and rewrite a record
+}
+
+// NewReleaseStore creates a release store in dir
+func NewReleaseStore(dir string) *ReleaseStore {
+	return &ReleaseStore{dir: dir}
+}
+
+// path returns the file of a tag's record
+func (rs *ReleaseStore) path(tag string) string {
+	return filepath.Join(rs.dir, strings.ReplaceAll(tag, "/", "_")+".json")
+}
+
+// Load reads the record of a tag, returning an error wrapping
+// os.ErrNotExist when the release was not recorded
+func (rs *ReleaseStore) Load(tag string) (*ReleaseRecord, error) {
+	data, err := os.ReadFile(rs.path(tag))
+	if err != nil {
+		return nil, err
+	}
+
+	var record ReleaseRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("invalid release record %s: %w", rs.path(tag), err)
+	}
+	if record.Publishers == nil {
+		record.Publishers = make(map[string]PublishRecord)
+	}
+	return &record, nil
+}
+
+// Save writes a release record, adding to the publishers already recorded
+// for its tag
+func (rs *ReleaseStore) Save(record *ReleaseRecord) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	existing, err := rs.Load(record.Tag)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		existing = &ReleaseRecord{Publishers: make(map[string]PublishRecord)}
+	}
+	existing.Version = record.Version
+	existing.Tag = record.Tag
+	for name, publish := range record.Publishers {
+		existing.Publishers[name] = publish
+	}
+
+	data, err := json.MarshalIndent(existing, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(rs.dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(rs.path(record.Tag), append(data, '\n'), 0644)
+}
+
+// SetReleaseStore sets the store publishes are recorded in, for rollbacks
+func (dc *DistributionCoordinator) SetReleaseStore(store *ReleaseStore) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	dc.store = store
+}
+
+// publishRecord returns the record of a publisher's outcome. Failed
+// publishes are recorded when they created something to roll back.
+func (dc *DistributionCoordinator) publishRecord(publisher Publisher, release Release, publishErr error) (PublishRecord, bool) {
+	publish := PublishRecord{
+		Publisher:   publisher.GetName(),
+		Version:     release.Version,
+		Tag:         release.Tag,
+		Status:      StatusSuccess,
+		PublishedAt: time.Now().UTC(),
+	}
+	if rollbacker, ok := publisher.(RollbackPublisher); ok {
+		publish.Details = rollbacker.PublishRecord(release)
+	}
+	if publishErr != nil {
+		if publish.Details == nil {
+			return publish, false
+		}
+		publish.Status = StatusError
+	}
+	return publish, true
+}
+
+// savePublish adds a publisher's outcome to the record of its release, if
+// there is a store. Each outcome is saved as soon as the publisher finishes,
+// so an interrupted run leaves a record to resume from.
+func (dc *DistributionCoordinator) savePublish(publish PublishRecord) error {
+	dc.mu.RLock()
+	store := dc.store
+	dc.mu.RUnlock()
+
+	if store == nil {
+		return nil
+	}
+	record := &ReleaseRecord{
+		Version:    publish.Version,
+		Tag:        publish.Tag,
+		Publishers: map[string]PublishRecord{publish.Publisher: publish},
+	}
+	if err := store.Save(record); err != nil {
+		return fmt.Errorf("failed to record %s of release %s: %w", publish.Publisher, publish.Tag, err)
+	}
+	return nil
+}
+
+// Rollback undoes the recorded publishes of a release. Publishers are rolled
+// back one at a time, latest stage and lowest priority first, so the ones
+// depending on others go before them. Publishers without rollback support
+// are reported for manual clean-up. Rolled back publishes are marked in the
+// record, so running a rollback again skips them.
+func (dc *DistributionCoordinator) Rollback(ctx context.Context, tag string, options RollbackOptions) ([]RollbackResult, error) {
+	dc.mu.RLock()
+	store := dc.store
+	dc.mu.RUnlock()
+	if store == nil {
+		return nil, fmt.Errorf("no release store configured")
+	}
+
+	record, err := store.Load(tag)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("release %s has no publish record", tag)
+		}
+		return nil, err
+	}
+
+	var results []RollbackResult
+	failures := 0
+	for _, name := range dc.rollbackOrder(record) {
+		publish := record.Publishers[name]
+		if !publish.RolledBackAt.IsZero() {
+			continue
+		}
+
+		result := dc.rollbackPublisher(ctx, publish, options)
+		results = append(results, result)
+		if result.Err != nil {
+			failures++
+			log.Printf("Rollback of %s failed: %v", name, result.Err)
+			continue
+		}
+		if result.Manual {
+			continue
+		}
+		publish.RolledBackAt = time.Now().UTC()
+		publish.RollbackResult = result.Message
+		record.Publishers[name] = publish
+	}
+
+	if err := store.Save(record); err != nil {
+		return results, fmt.Errorf("failed to record rollback: %w", err)
+	}
+	if failures > 0 {
+		return results, fmt.Errorf("rollback failed for %d publishers", failures)
+	}
+	return results, nil
+}
+
+// rollbackOrder returns the recorded publishers in the order to roll them
+// back: later stages first, then higher priority numbers
+func (dc *DistributionCoordinator) rollbackOrder(record *ReleaseRecord) []string {
+	names := make([]string, 0, len(record.Publishers))
+	for name := range record.Publishers {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ci, cj := dc.config.Publishers[names[i]], dc.config.Publishers[names[j]]
+		if ci.Stage != cj.Stage {
+			return ci.Stage > cj.Stage
+		}
+		if ci.Priority != cj.Priority {
+			return ci.Priority > cj.Priority
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// rollbackPublisher rolls back the publish of a single publisher
+func (dc *DistributionCoordinator) rollbackPublisher(ctx context.Context, publish PublishRecord, options RollbackOptions) RollbackResult {
+	dc.mu.RLock()
+	publisher, exists := dc.publishers[publish.Publisher]
+	dc.mu.RUnlock()
+
+	result := RollbackResult{Publisher: publish.Publisher}
+	if !exists {
+		result.Message = fmt.Sprintf("%s is not enabled, remove release %s by hand", publish.Publisher, publish.Tag)
+		result.Manual = true
+		return result
+	}
+	rollbacker, ok := publisher.(RollbackPublisher)
+	if !ok {
+		result.Message = fmt.Sprintf("%s has no automated rollback, remove release %s by hand", publish.Publisher, publish.Tag)
+		result.Manual = true
+		return result
+	}
+
+	result.Message, result.Err = rollbacker.Rollback(ctx, publish, options)
+	return result
+}
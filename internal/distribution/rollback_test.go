@@ -0,0 +1,205 @@
+package distribution
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseStore(t *testing.T) {
+	store := NewReleaseStore(filepath.Join(t.TempDir(), "releases"))
+
+	_, err := store.Load("v1.2.3")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, store.Save(&ReleaseRecord{Version: "v1.2.3", Tag: "v1.2.3", Publishers: map[string]PublishRecord{
+		"github": {Publisher: "github", Status: StatusSuccess, Details: map[string]string{"release_id": "42"}},
+	}}))
+	// A later run adds to the publishers recorded
+	require.NoError(t, store.Save(&ReleaseRecord{Version: "v1.2.3", Tag: "v1.2.3", Publishers: map[string]PublishRecord{
+		"homebrew": {Publisher: "homebrew", Status: StatusError},
+	}}))
+
+	record, err := store.Load("v1.2.3")
+	require.NoError(t, err)
+	assert.Len(t, record.Publishers, 2)
+	assert.Equal(t, "42", record.Publishers["github"].Details["release_id"])
+	assert.Equal(t, StatusError, record.Publishers["homebrew"].Status)
+}
+
+// rollbackStagePublisher is a stage publisher that records what it
+// published and what was rolled back
+type rollbackStagePublisher struct {
+	*stagePublisher
+	details     map[string]string
+	rollbacks   *[]string
+	rollbackErr error
+}
+
+func (p *rollbackStagePublisher) PublishRecord(release Release) map[string]string {
+	return p.details
+}
+
+func (p *rollbackStagePublisher) Rollback(ctx context.Context, record PublishRecord, options RollbackOptions) (string, error) {
+	*p.rollbacks = append(*p.rollbacks, p.name+":"+record.Details["id"]+":"+options.Reason)
+	if p.rollbackErr != nil {
+		return "", p.rollbackErr
+	}
+	return "rolled back " + p.name, nil
+}
+
+func TestDistributionCoordinator_Rollback(t *testing.T) {
+	log := &publishLog{}
+	var rollbacks []string
+	coordinator := NewDistributionCoordinator(&DistributionConfig{Publishers: map[string]PublisherConfig{
+		"github":   {Enabled: true, Priority: 1},
+		"homebrew": {Enabled: true, Priority: 3, DependsOn: []string{"github"}},
+		"scoop":    {Enabled: true, Priority: 4, DependsOn: []string{"github"}},
+		"aur":      {Enabled: true, Priority: 7},
+	}})
+	store := NewReleaseStore(t.TempDir())
+	coordinator.SetReleaseStore(store)
+
+	github := &rollbackStagePublisher{stagePublisher: &stagePublisher{name: "github", log: log}, details: map[string]string{"id": "42"}, rollbacks: &rollbacks}
+	homebrew := &rollbackStagePublisher{stagePublisher: &stagePublisher{name: "homebrew", log: log}, details: map[string]string{"id": "formula"}, rollbacks: &rollbacks}
+	// A failed publish is recorded when it created something
+	scoop := &rollbackStagePublisher{stagePublisher: &stagePublisher{name: "scoop", log: log, err: errors.New("bucket is read-only")}, details: map[string]string{"id": "manifest"}, rollbacks: &rollbacks}
+	aur := &stagePublisher{name: "aur", log: log}
+	for _, publisher := range []Publisher{github, homebrew, scoop, aur} {
+		require.NoError(t, coordinator.RegisterPublisher(publisher))
+	}
+
+	require.Error(t, coordinator.Distribute(context.Background(), Release{Version: "v1.2.3", Tag: "v1.2.3"}))
+	record, err := store.Load("v1.2.3")
+	require.NoError(t, err)
+	assert.Len(t, record.Publishers, 4)
+	assert.Equal(t, StatusError, record.Publishers["scoop"].Status)
+	assert.Nil(t, record.Publishers["aur"].Details)
+
+	// The GitHub release goes last since the others download from it
+	homebrew.rollbackErr = errors.New("tap is read-only")
+	results, err := coordinator.Rollback(context.Background(), "v1.2.3", RollbackOptions{Reason: "broken build"})
+	assert.EqualError(t, err, "rollback failed for 1 publishers")
+	assert.Equal(t, []string{"scoop:manifest:broken build", "homebrew:formula:broken build", "github:42:broken build"}, rollbacks)
+	require.Len(t, results, 4)
+	assert.Equal(t, RollbackResult{Publisher: "aur", Message: "aur has no automated rollback, remove release v1.2.3 by hand", Manual: true}, results[0])
+	assert.EqualError(t, results[2].Err, "tap is read-only")
+
+	record, err = store.Load("v1.2.3")
+	require.NoError(t, err)
+	assert.Equal(t, "rolled back github", record.Publishers["github"].RollbackResult)
+	assert.False(t, record.Publishers["github"].RolledBackAt.IsZero())
+	assert.True(t, record.Publishers["homebrew"].RolledBackAt.IsZero())
+
+	// Running the rollback again retries what is left
+	rollbacks = nil
+	homebrew.rollbackErr = nil
+	results, err = coordinator.Rollback(context.Background(), "v1.2.3", RollbackOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"homebrew:formula:"}, rollbacks)
+	assert.Len(t, results, 2)
+
+	_, err = coordinator.Rollback(context.Background(), "v9.9.9", RollbackOptions{})
+	assert.EqualError(t, err, "release v9.9.9 has no publish record")
+}
+
+func TestGitHubPublisher_Rollback(t *testing.T) {
+	var requests []string
+	var patch map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		switch {
+		case r.Method == http.MethodDelete && r.URL.Path == "/repos/nettracex/nettracex-tui/releases/42":
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/nettracex/nettracex-tui/releases/42":
+			w.Write([]byte(`{"id": 42, "name": "Release v1.2.3", "body": "Changes"}`))
+		case r.Method == http.MethodPatch:
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&patch))
+			w.Write([]byte(`{"id": 42}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	publisher := NewGitHubPublisher(GitHubConfig{Owner: "nettracex", Repo: "nettracex-tui", Token: "secret", BaseURL: server.URL})
+	publisher.published["v1.2.3"] = &GitHubReleaseResponse{ID: 42, HTMLURL: "https://github.com/nettracex/nettracex-tui/releases/tag/v1.2.3"}
+	assert.Nil(t, publisher.PublishRecord(Release{Tag: "v1.2.4"}))
+	record := PublishRecord{Tag: "v1.2.3", Details: publisher.PublishRecord(Release{Tag: "v1.2.3"})}
+	assert.Equal(t, "42", record.Details["release_id"])
+
+	message, err := publisher.Rollback(context.Background(), record, RollbackOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "deleted GitHub release https://github.com/nettracex/nettracex-tui/releases/tag/v1.2.3, keeping tag v1.2.3", message)
+
+	// Yanking keeps the release, withdrawn
+	message, err = publisher.Rollback(context.Background(), record, RollbackOptions{Yank: true, Reason: "crashes on start"})
+	require.NoError(t, err)
+	assert.Equal(t, "marked GitHub release https://github.com/nettracex/nettracex-tui/releases/tag/v1.2.3 as withdrawn", message)
+	assert.Equal(t, map[string]interface{}{
+		"name":        "Release v1.2.3 (withdrawn)",
+		"body":        "**This release has been withdrawn:** crashes on start\n\nChanges",
+		"prerelease":  true,
+		"make_latest": "false",
+	}, patch)
+
+	// A release deleted by hand is already rolled back
+	record.Details["release_id"] = "7"
+	message, err = publisher.Rollback(context.Background(), record, RollbackOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "GitHub release v1.2.3 was already deleted", message)
+	_, err = publisher.Rollback(context.Background(), record, RollbackOptions{Yank: true})
+	assert.ErrorContains(t, err, "Not Found")
+
+	assert.Equal(t, []string{
+		"DELETE /repos/nettracex/nettracex-tui/releases/42",
+		"GET /repos/nettracex/nettracex-tui/releases/42",
+		"PATCH /repos/nettracex/nettracex-tui/releases/42",
+		"DELETE /repos/nettracex/nettracex-tui/releases/7",
+		"GET /repos/nettracex/nettracex-tui/releases/7",
+	}, requests)
+}
+
+func TestHomebrewPublisher_Rollback(t *testing.T) {
+	publisher, err := NewHomebrewPublisher(HomebrewConfig{FormulaName: "nettracex", CustomTap: true})
+	require.NoError(t, err)
+	formulaFile := filepath.Join(t.TempDir(), "nettracex.rb")
+
+	// The formula the release replaced is restored
+	require.NoError(t, os.WriteFile(formulaFile, []byte("v1.2.3 formula"), 0644))
+	message, err := publisher.Rollback(context.Background(), PublishRecord{Details: map[string]string{
+		"formula_path":     formulaFile,
+		"previous_formula": "v1.2.2 formula",
+	}}, RollbackOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "restored the previous formula at "+formulaFile, message)
+	content, err := os.ReadFile(formulaFile)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.2 formula", string(content))
+
+	// A formula the release added is removed
+	_, err = publisher.Rollback(context.Background(), PublishRecord{Details: map[string]string{"formula_path": formulaFile}}, RollbackOptions{})
+	require.NoError(t, err)
+	assert.NoFileExists(t, formulaFile)
+}
+
+func TestGoModulePublisher_Rollback(t *testing.T) {
+	publisher := NewGoModulePublisher(GoModuleConfig{ModulePath: "github.com/nettracex/nettracex-tui"})
+	assert.Nil(t, publisher.PublishRecord(Release{Version: "v1.2.3", Tag: "v1.2.3"}))
+
+	publisher.tagged["v1.2.3"] = true
+	record := PublishRecord{Details: publisher.PublishRecord(Release{Version: "v1.2.3", Tag: "v1.2.3"})}
+	message, err := publisher.Rollback(context.Background(), record, RollbackOptions{Reason: "crashes on start"})
+	require.NoError(t, err)
+	assert.Equal(t, `github.com/nettracex/nettracex-tui@v1.2.3 cannot be removed from the module proxy: add "retract v1.2.3 // crashes on start" to go.mod and release a newer version to retract it`, message)
+}
@@ -0,0 +1,508 @@
+package distribution
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/nettracex/nettracex-tui/internal/update"
+)
+
+// S3Publisher uploads the artifacts of a release to S3-compatible object
+// storage, such as AWS S3, Google Cloud Storage or MinIO, under a prefix per
+// version, and keeps a latest.json the self-updater can read. Requests are
+// signed and sent by minio-go.
+type S3Publisher struct {
+	config S3Config
+	client *minio.Client
+	status PublishStatus
+	now    func() time.Time
+}
+
+// S3Config contains object storage configuration
+type S3Config struct {
+	// Endpoint is the S3 API, e.g. "https://s3.eu-west-1.amazonaws.com",
+	// "https://storage.googleapis.com" or a MinIO server, without a path
+	Endpoint     string `json:"endpoint"`
+	Region       string `json:"region"` // signing region; "auto" for Google Cloud Storage
+	Bucket       string `json:"bucket"`
+	Prefix       string `json:"prefix"` // key prefix the versions are stored under, e.g. "releases"
+	AccessKey    string `json:"access_key"`
+	SecretKey    string `json:"secret_key"`
+	SessionToken string `json:"session_token"`
+	// PathStyle addresses the bucket in the path rather than the host name,
+	// as MinIO needs
+	PathStyle bool   `json:"path_style"`
+	ACL       string `json:"acl"`        // canned ACL objects are uploaded with, e.g. "public-read"
+	PublicURL string `json:"public_url"` // URL the bucket is downloaded from, when not the endpoint
+	// Latest keeps <prefix>/latest.json pointing at the newest release
+	Latest bool `json:"latest"`
+}
+
+// S3Manifest describes the objects of a release. It reads as a GitHub
+// release to the self-updater, which is pointed at latest.json in place of
+// the GitHub API.
+type S3Manifest struct {
+	update.Release
+	Version   string            `json:"version"`
+	CreatedAt time.Time         `json:"created_at"`
+	CommitSHA string            `json:"commit_sha,omitempty"`
+	Checksums map[string]string `json:"checksums,omitempty"`
+	// Body is the release notes rendered from the release notes template
+	Body string `json:"body,omitempty"`
+}
+
+// s3Object is a file of a release to upload
+type s3Object struct {
+	Name        string
+	Path        string // file the object is read from, or
+	Data        []byte // its content
+	ContentType string
+}
+
+const (
+	// S3ManifestFile is the manifest of the objects of each version
+	S3ManifestFile = "release.json"
+	// S3LatestFile is the manifest of the newest stable release, under the
+	// prefix, next to a latest-<channel>.json for each prerelease channel
+	S3LatestFile = "latest.json"
+)
+
+// NewS3Publisher creates a new object storage publisher
+func NewS3Publisher(config S3Config) (*S3Publisher, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" || strings.Trim(endpoint.Path, "/") != "" {
+		return nil, fmt.Errorf("invalid endpoint %q", config.Endpoint)
+	}
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("bucket is required")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+	config.Prefix = strings.Trim(config.Prefix, "/")
+	config.PublicURL = strings.TrimSuffix(config.PublicURL, "/")
+
+	// Without an access key requests are sent unsigned
+	lookup := minio.BucketLookupDNS
+	if config.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+	client, err := minio.New(endpoint.Host, &minio.Options{
+		Creds:        credentials.NewStaticV4(config.AccessKey, config.SecretKey, config.SessionToken),
+		Secure:       endpoint.Scheme == "https",
+		Region:       config.Region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", config.Endpoint, err)
+	}
+
+	return &S3Publisher{
+		config: config,
+		client: client,
+		status: PublishStatus{
+			Name:   "s3",
+			Status: StatusIdle,
+		},
+		now: time.Now,
+	}, nil
+}
+
+// GetName returns the publisher name
+func (p *S3Publisher) GetName() string {
+	return "s3"
+}
+
+// Publish uploads the artifacts and manifest of a release, then points
+// latest.json at it when enabled
+func (p *S3Publisher) Publish(ctx context.Context, release Release) error {
+	p.updateStatus(StatusPublishing, "")
+
+	objects, err := p.objects(release)
+	if err != nil {
+		p.updateStatus(StatusError, err.Error())
+		return fmt.Errorf("failed to collect artifacts: %w", err)
+	}
+	for _, object := range objects {
+		if err := p.upload(ctx, p.versionKey(release, object.Name), object, ""); err != nil {
+			p.updateStatus(StatusError, err.Error())
+			return fmt.Errorf("failed to upload %s: %w", object.Name, err)
+		}
+	}
+
+	manifest, err := p.GenerateManifest(release, objects)
+	if err != nil {
+		p.updateStatus(StatusError, err.Error())
+		return fmt.Errorf("failed to generate manifest: %w", err)
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		p.updateStatus(StatusError, err.Error())
+		return fmt.Errorf("failed to render manifest: %w", err)
+	}
+	object := s3Object{Name: S3ManifestFile, Data: append(data, '\n'), ContentType: "application/json"}
+	if err := p.upload(ctx, p.versionKey(release, S3ManifestFile), object, ""); err != nil {
+		p.updateStatus(StatusError, err.Error())
+		return fmt.Errorf("failed to upload manifest: %w", err)
+	}
+
+	if p.config.Latest {
+		for _, name := range latestFiles(release) {
+			if err := p.updateLatest(ctx, name, manifest, object); err != nil {
+				p.updateStatus(StatusError, err.Error())
+				return fmt.Errorf("failed to update %s: %w", name, err)
+			}
+		}
+	}
+
+	p.updateStatus(StatusSuccess, "")
+	return nil
+}
+
+// DryRun renders the manifest of a release and lists the uploads, reading
+// latest.json to check the credentials and whether it would be updated
+func (p *S3Publisher) DryRun(ctx context.Context, release Release) (*DryRunReport, error) {
+	report := &DryRunReport{Files: make(map[string]string)}
+	if err := p.Validate(ctx, release); err != nil {
+		return report, err
+	}
+
+	objects, err := p.objects(release)
+	if err != nil {
+		return report, fmt.Errorf("failed to collect artifacts: %w", err)
+	}
+	for _, object := range objects {
+		report.Actions = append(report.Actions, fmt.Sprintf("upload %s to %s", object.Name, p.objectURL(p.versionKey(release, object.Name))))
+	}
+
+	manifest, err := p.GenerateManifest(release, objects)
+	if err != nil {
+		return report, fmt.Errorf("failed to generate manifest: %w", err)
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return report, fmt.Errorf("failed to render manifest: %w", err)
+	}
+	report.Files[S3ManifestFile] = string(data) + "\n"
+	report.Actions = append(report.Actions, fmt.Sprintf("upload %s to %s", S3ManifestFile, p.objectURL(p.versionKey(release, S3ManifestFile))))
+
+	if _, err := p.get(ctx, p.key(S3LatestFile)); err != nil {
+		return report, fmt.Errorf("credential check failed: %w", err)
+	}
+	if !p.config.Latest {
+		return report, nil
+	}
+	for _, name := range latestFiles(release) {
+		current, err := p.get(ctx, p.key(name))
+		if err != nil {
+			return report, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		var latest S3Manifest
+		if current != nil && json.Unmarshal(current, &latest) == nil && update.Newer(manifest.Tag, latest.Tag) {
+			report.Actions = append(report.Actions, fmt.Sprintf("leave %s at the newer %s", name, latest.Tag))
+		} else {
+			report.Actions = append(report.Actions, fmt.Sprintf("point %s at %s", p.objectURL(p.key(name)), manifest.Tag))
+		}
+	}
+	return report, nil
+}
+
+// Validate validates a release for object storage
+func (p *S3Publisher) Validate(ctx context.Context, release Release) error {
+	if release.Version == "" {
+		return fmt.Errorf("release version is required")
+	}
+	if len(release.Binaries) == 0 {
+		return fmt.Errorf("no binaries found in release")
+	}
+	for filename, binary := range release.Binaries {
+		if binary.FilePath == "" {
+			return fmt.Errorf("binary %s missing file path", filename)
+		}
+	}
+	for filename, pkg := range release.Packages {
+		if pkg.FilePath == "" {
+			return fmt.Errorf("package %s missing file path", filename)
+		}
+	}
+	return nil
+}
+
+// GetStatus returns the current status of the object storage publisher
+func (p *S3Publisher) GetStatus() PublishStatus {
+	p.status.Metadata = map[string]string{
+		"endpoint": p.config.Endpoint,
+		"bucket":   p.config.Bucket,
+		"prefix":   p.config.Prefix,
+	}
+	if p.config.Latest {
+		p.status.Metadata["latest"] = p.publicURL(p.key(S3LatestFile))
+	}
+	return p.status
+}
+
+// updateStatus updates the publisher status
+func (p *S3Publisher) updateStatus(status StatusType, lastError string) {
+	p.status.Status = status
+	p.status.LastError = lastError
+	if status == StatusSuccess {
+		p.status.LastPublish = time.Now()
+		p.status.PublishCount++
+	} else if status == StatusError {
+		p.status.ErrorCount++
+	}
+}
+
+// objects returns the files of a release: binaries, packages, checksum
+// files, SBOMs and signatures, each in name order. Without a written
+// checksums file one is made from the release checksums.
+func (p *S3Publisher) objects(release Release) ([]s3Object, error) {
+	var objects []s3Object
+	add := func(files map[string]string, contentType func(string) string) {
+		for _, name := range sortedNames(files) {
+			objects = append(objects, s3Object{Name: name, Path: files[name], ContentType: contentType(name)})
+		}
+	}
+	binaryType := func(string) string { return "application/octet-stream" }
+	textType := func(string) string { return "text/plain" }
+
+	binaries := make(map[string]string, len(release.Binaries))
+	for filename, binary := range release.Binaries {
+		binaries[filename] = binary.FilePath
+	}
+	add(binaries, binaryType)
+
+	packages := make(map[string]string, len(release.Packages))
+	for filename, pkg := range release.Packages {
+		packages[filename] = pkg.FilePath
+	}
+	add(packages, packageContentType)
+
+	if len(release.ChecksumFiles) > 0 {
+		add(release.ChecksumFiles, textType)
+	} else if len(release.Checksums) > 0 {
+		var buf bytes.Buffer
+		if err := WriteChecksums(&buf, release.Checksums); err != nil {
+			return nil, err
+		}
+		objects = append(objects, s3Object{Name: ChecksumsFile, Data: buf.Bytes(), ContentType: "text/plain"})
+	}
+
+	sboms := make(map[string]string)
+	signatures := make(map[string]string)
+	for _, paths := range release.SBOMs {
+		for _, path := range paths {
+			sboms[filepath.Base(path)] = path
+		}
+	}
+	for _, paths := range release.Signatures {
+		for _, path := range paths {
+			signatures[filepath.Base(path)] = path
+		}
+	}
+	add(sboms, func(string) string { return "application/json" })
+	add(signatures, signatureContentType)
+
+	return objects, nil
+}
+
+// GenerateManifest describes the uploaded objects of a release
+func (p *S3Publisher) GenerateManifest(release Release, objects []s3Object) (*S3Manifest, error) {
+	manifest := &S3Manifest{
+		Release: update.Release{
+			Tag:        p.tag(release),
+			Name:       release.Version,
+			URL:        p.publicURL(p.versionKey(release, S3ManifestFile)),
+			Prerelease: IsPrerelease(release),
+		},
+		Version:   strings.TrimPrefix(release.Version, "v"),
+		CreatedAt: release.Metadata.CreatedAt,
+		CommitSHA: release.Metadata.CommitSHA,
+		Checksums: release.Checksums,
+		Body:      release.Notes,
+	}
+	if manifest.CreatedAt.IsZero() {
+		manifest.CreatedAt = p.now().UTC()
+	}
+
+	for _, object := range objects {
+		size := int64(len(object.Data))
+		if object.Path != "" {
+			info, err := os.Stat(object.Path)
+			if err != nil {
+				return nil, err
+			}
+			size = info.Size()
+		}
+		manifest.Assets = append(manifest.Assets, update.Asset{
+			Name: object.Name,
+			URL:  p.publicURL(p.versionKey(release, object.Name)),
+			Size: size,
+		})
+	}
+	return manifest, nil
+}
+
+// updateLatest points a latest manifest at a release unless it already
+// names a newer one, so publishing a fix of an older line leaves it alone
+func (p *S3Publisher) updateLatest(ctx context.Context, name string, manifest *S3Manifest, object s3Object) error {
+	key := p.key(name)
+	current, err := p.get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if current != nil {
+		var latest S3Manifest
+		if err := json.Unmarshal(current, &latest); err == nil && update.Newer(manifest.Tag, latest.Tag) {
+			return nil
+		}
+	}
+	return p.upload(ctx, key, object, "no-cache")
+}
+
+// latestFiles returns the latest manifests a release is a candidate for:
+// latest.json names stable releases only, and latest-<channel>.json the
+// newest release of the channel or of a more stable one, which is what
+// updaters following the channel read
+func latestFiles(release Release) []string {
+	channel := ChannelOf(release)
+	var names []string
+	for _, c := range update.Channels {
+		if !c.Follows(channel) {
+			continue
+		}
+		if c == update.Stable {
+			names = append(names, S3LatestFile)
+		} else {
+			names = append(names, "latest-"+string(c)+".json")
+		}
+	}
+	return names
+}
+
+// tag returns the tag of a release, which names its prefix
+func (p *S3Publisher) tag(release Release) string {
+	if release.Tag != "" {
+		return release.Tag
+	}
+	return release.Version
+}
+
+// key returns the object key of name under the prefix
+func (p *S3Publisher) key(name string) string {
+	if p.config.Prefix == "" {
+		return name
+	}
+	return p.config.Prefix + "/" + name
+}
+
+// versionKey returns the object key of a file of a release
+func (p *S3Publisher) versionKey(release Release, name string) string {
+	return p.key(p.tag(release) + "/" + name)
+}
+
+// objectURL returns the S3 API URL of an object
+func (p *S3Publisher) objectURL(key string) string {
+	if p.config.PathStyle {
+		return p.config.Endpoint + "/" + s3Escape(p.config.Bucket) + "/" + s3Escape(key)
+	}
+	endpoint, _ := url.Parse(p.config.Endpoint)
+	return fmt.Sprintf("%s://%s.%s/%s", endpoint.Scheme, p.config.Bucket, endpoint.Host, s3Escape(key))
+}
+
+// publicURL returns the URL an object is downloaded from
+func (p *S3Publisher) publicURL(key string) string {
+	if p.config.PublicURL != "" {
+		return p.config.PublicURL + "/" + s3Escape(key)
+	}
+	return p.objectURL(key)
+}
+
+// upload puts an object
+func (p *S3Publisher) upload(ctx context.Context, key string, object s3Object, cacheControl string) error {
+	var body io.Reader = bytes.NewReader(object.Data)
+	size := int64(len(object.Data))
+	if object.Path != "" {
+		file, err := os.Open(object.Path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		info, err := file.Stat()
+		if err != nil {
+			return err
+		}
+		body, size = file, info.Size()
+	}
+
+	options := minio.PutObjectOptions{ContentType: object.ContentType, CacheControl: cacheControl}
+	if p.config.ACL != "" {
+		// x-amz- metadata is sent as a header of its own rather than as x-amz-meta-
+		options.UserMetadata = map[string]string{"x-amz-acl": p.config.ACL}
+	}
+	if _, err := p.client.PutObject(ctx, p.config.Bucket, key, body, size, options); err != nil {
+		return s3Error(err)
+	}
+	return nil
+}
+
+// get reads an object, returning nil when it does not exist
+func (p *S3Publisher) get(ctx context.Context, key string) ([]byte, error) {
+	object, err := p.client.GetObject(ctx, p.config.Bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, s3Error(err)
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(io.LimitReader(object, 1<<20))
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, s3Error(err)
+	}
+	return data, nil
+}
+
+// s3Error adds the S3 error code of a refused request to err, which
+// minio-go describes by its message only
+func s3Error(err error) error {
+	if code := minio.ToErrorResponse(err).Code; code != "" {
+		return fmt.Errorf("%s: %w", code, err)
+	}
+	return err
+}
+
+// s3Escape percent-encodes an object key for a URL, keeping slashes
+func s3Escape(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = s3EscapeComponent(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// s3EscapeComponent percent-encodes everything but unreserved characters
+func s3EscapeComponent(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
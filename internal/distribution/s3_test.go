@@ -0,0 +1,228 @@
+package distribution
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nettracex/nettracex-tui/internal/update"
+)
+
+func TestS3Escape(t *testing.T) {
+	assert.Equal(t, "releases/v1.2.3/nettracex%2Bdebug%20build.tar.gz", s3Escape("releases/v1.2.3/nettracex+debug build.tar.gz"))
+}
+
+// s3Server fakes an S3 API with path-style addressing, checking each
+// upload against the payload hash or decoded length it is signed with
+type s3Server struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	headers map[string]http.Header
+	fail    string
+}
+
+func newS3Server(t *testing.T) (*s3Server, *httptest.Server) {
+	s := &s3Server{objects: make(map[string][]byte), headers: make(map[string]http.Header)}
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return s, server
+}
+
+func (s *s3Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.TrimPrefix(r.URL.Path, "/downloads/")
+	switch r.Method {
+	case http.MethodGet:
+		data, exists := s.objects[key]
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("<Error><Code>NoSuchKey</Code></Error>"))
+			return
+		}
+		sum := sha256.Sum256(data)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+		w.Header().Set("Last-Modified", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat))
+		w.Write(data)
+	case http.MethodPut:
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=minio/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		data, err := readS3Payload(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("<Error><Code>XAmzContentSHA256Mismatch</Code><Message>" + err.Error() + "</Message></Error>"))
+			return
+		}
+		if key == s.fail {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("<Error><Code>AccessDenied</Code></Error>"))
+			return
+		}
+		s.objects[key] = data
+		s.headers[key] = r.Header.Clone()
+	}
+}
+
+// readS3Payload reads the body of an upload, decoding the aws-chunked
+// encoding of a streaming signature
+func readS3Payload(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	if r.Header.Get("X-Amz-Content-Sha256") != "STREAMING-AWS4-HMAC-SHA256-PAYLOAD" {
+		sum := sha256.Sum256(body)
+		if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) {
+			return nil, fmt.Errorf("payload hash mismatch")
+		}
+		return body, nil
+	}
+
+	var data []byte
+	for {
+		header, rest, ok := bytes.Cut(body, []byte("\r\n"))
+		if !ok {
+			return nil, fmt.Errorf("truncated chunk")
+		}
+		sizeHex, _, _ := bytes.Cut(header, []byte(";"))
+		size, err := strconv.ParseInt(string(sizeHex), 16, 64)
+		if err != nil || int64(len(rest)) < size+2 {
+			return nil, fmt.Errorf("invalid chunk %q", header)
+		}
+		if size == 0 {
+			break
+		}
+		data = append(data, rest[:size]...)
+		body = rest[size+2:]
+	}
+	if r.Header.Get("X-Amz-Decoded-Content-Length") != strconv.Itoa(len(data)) {
+		return nil, fmt.Errorf("decoded length mismatch")
+	}
+	return data, nil
+}
+
+func s3Release(t *testing.T, version string) Release {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	return Release{
+		Version: version,
+		Tag:     version,
+		Binaries: map[string]Binary{
+			"nettracex_Linux_x86_64.tar.gz": {Platform: "linux", Architecture: "amd64", Filename: "nettracex_Linux_x86_64.tar.gz", FilePath: write("nettracex_Linux_x86_64.tar.gz", "linux archive")},
+			"nettracex_Windows_x86_64.zip":  {Platform: "windows", Architecture: "amd64", Filename: "nettracex_Windows_x86_64.zip", FilePath: write("nettracex_Windows_x86_64.zip", "windows archive")},
+		},
+		Packages: map[string]Binary{
+			"nettracex_1.2.3_amd64.deb": {Platform: "linux", Architecture: "amd64", Filename: "nettracex_1.2.3_amd64.deb", FilePath: write("nettracex_1.2.3_amd64.deb", "deb")},
+		},
+		Checksums:  map[string]string{"nettracex_Linux_x86_64.tar.gz": strings.Repeat("a", 64)},
+		Signatures: map[string][]string{ChecksumsFile: {write(ChecksumsFile+".sig", "signature")}},
+		Metadata:   ReleaseMetadata{CreatedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), CommitSHA: "abc123"},
+	}
+}
+
+func TestS3Publisher_Publish(t *testing.T) {
+	storage, server := newS3Server(t)
+	publisher, err := NewS3Publisher(S3Config{
+		Endpoint:  server.URL,
+		Bucket:    "downloads",
+		Prefix:    "/nettracex/",
+		AccessKey: "minio",
+		SecretKey: "minio-secret",
+		PathStyle: true,
+		ACL:       "public-read",
+		Latest:    true,
+	})
+	require.NoError(t, err)
+
+	release := s3Release(t, "v1.2.3")
+	require.NoError(t, publisher.Validate(context.Background(), release))
+	require.NoError(t, publisher.Publish(context.Background(), release))
+
+	assert.Equal(t, "linux archive", string(storage.objects["nettracex/v1.2.3/nettracex_Linux_x86_64.tar.gz"]))
+	assert.Equal(t, "application/vnd.debian.binary-package", storage.headers["nettracex/v1.2.3/nettracex_1.2.3_amd64.deb"].Get("Content-Type"))
+	assert.Equal(t, "public-read", storage.headers["nettracex/v1.2.3/nettracex_1.2.3_amd64.deb"].Get("X-Amz-Acl"))
+	assert.Equal(t, strings.Repeat("a", 64)+"  nettracex_Linux_x86_64.tar.gz\n", string(storage.objects["nettracex/v1.2.3/checksums.txt"]))
+	assert.Equal(t, "signature", string(storage.objects["nettracex/v1.2.3/checksums.txt.sig"]))
+
+	var manifest S3Manifest
+	require.NoError(t, json.Unmarshal(storage.objects["nettracex/v1.2.3/release.json"], &manifest))
+	assert.Equal(t, "v1.2.3", manifest.Tag)
+	assert.Equal(t, "1.2.3", manifest.Version)
+	assert.Equal(t, "abc123", manifest.CommitSHA)
+	require.Len(t, manifest.Assets, 5)
+	asset, ok := manifest.Asset("nettracex_Linux_x86_64.tar.gz")
+	require.True(t, ok)
+	assert.Equal(t, server.URL+"/downloads/nettracex/v1.2.3/nettracex_Linux_x86_64.tar.gz", asset.URL)
+	assert.Equal(t, int64(len("linux archive")), asset.Size)
+
+	assert.Equal(t, storage.objects["nettracex/v1.2.3/release.json"], storage.objects["nettracex/latest.json"])
+	assert.Equal(t, "no-cache", storage.headers["nettracex/latest.json"].Get("Cache-Control"))
+
+	// The self-updater reads latest.json as the latest release
+	updater := update.New()
+	updater.ManifestURL = publisher.GetStatus().Metadata["latest"]
+	latest, newer, err := updater.Check(context.Background(), "1.2.0")
+	require.NoError(t, err)
+	assert.True(t, newer)
+	assert.Equal(t, "v1.2.3", latest.Tag)
+
+	// Neither a prerelease nor an older release replaces latest.json
+	require.NoError(t, publisher.Publish(context.Background(), s3Release(t, "v1.3.0-rc.1")))
+	require.NoError(t, publisher.Publish(context.Background(), s3Release(t, "v1.2.2")))
+	assert.Contains(t, storage.objects, "nettracex/v1.3.0-rc.1/release.json")
+	assert.Contains(t, storage.objects, "nettracex/v1.2.2/release.json")
+	assert.Equal(t, storage.objects["nettracex/v1.2.3/release.json"], storage.objects["nettracex/latest.json"])
+
+	require.NoError(t, publisher.Publish(context.Background(), s3Release(t, "v1.2.4")))
+	assert.Equal(t, storage.objects["nettracex/v1.2.4/release.json"], storage.objects["nettracex/latest.json"])
+	assert.Equal(t, StatusSuccess, publisher.GetStatus().Status)
+
+	// A refused upload is reported
+	storage.fail = "nettracex/v1.2.5/nettracex_Windows_x86_64.zip"
+	err = publisher.Publish(context.Background(), s3Release(t, "v1.2.5"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "AccessDenied")
+	assert.Equal(t, StatusError, publisher.GetStatus().Status)
+}
+
+func TestS3Publisher_URLs(t *testing.T) {
+	_, err := NewS3Publisher(S3Config{Endpoint: "storage.googleapis.com", Bucket: "downloads"})
+	assert.Error(t, err)
+	_, err = NewS3Publisher(S3Config{Endpoint: "https://storage.googleapis.com"})
+	assert.Error(t, err)
+	_, err = NewS3Publisher(S3Config{Endpoint: "https://storage.googleapis.com/storage/v1", Bucket: "downloads"})
+	assert.Error(t, err, "minio-go addresses buckets from the host")
+
+	publisher, err := NewS3Publisher(S3Config{Endpoint: "https://storage.googleapis.com/", Bucket: "downloads", Prefix: "releases"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://downloads.storage.googleapis.com/releases/latest.json", publisher.objectURL(publisher.key(S3LatestFile)))
+
+	publisher.config.PublicURL = "https://downloads.nettracex.dev"
+	assert.Equal(t, "https://downloads.nettracex.dev/releases/v1.2.3/release.json", publisher.publicURL(publisher.versionKey(Release{Version: "v1.2.3"}, S3ManifestFile)))
+
+	// A binary without a file has nothing to upload
+	assert.ErrorContains(t, publisher.Validate(context.Background(), Release{Version: "v1.2.3", Binaries: map[string]Binary{"nettracex": {}}}), "missing file path")
+}
@@ -0,0 +1,346 @@
+package distribution
+
+import (
+	"crypto/sha256"
+	"debug/buildinfo"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SBOMFormat is a software bill of materials format
+type SBOMFormat string
+
+const (
+	SPDX      SBOMFormat = "spdx"
+	CycloneDX SBOMFormat = "cyclonedx"
+)
+
+// SBOM file suffixes, appended to the name of the artifact described
+const (
+	SPDXSuffix      = ".spdx.json"
+	CycloneDXSuffix = ".cdx.json"
+)
+
+// sbomTool names the generator in the documents written
+const sbomTool = "nettracex-distribution"
+
+// Suffix returns the suffix of SBOM files in format
+func (f SBOMFormat) Suffix() string {
+	if f == CycloneDX {
+		return CycloneDXSuffix
+	}
+	return SPDXSuffix
+}
+
+// ParseSBOMFormats parses a comma-separated list of SBOM formats
+func ParseSBOMFormats(value string) ([]SBOMFormat, error) {
+	var formats []SBOMFormat
+	for _, name := range strings.Split(value, ",") {
+		switch format := SBOMFormat(strings.ToLower(strings.TrimSpace(name))); format {
+		case SPDX, CycloneDX:
+			formats = append(formats, format)
+		case "":
+		default:
+			return nil, fmt.Errorf("unsupported SBOM format %q, expected spdx or cyclonedx", name)
+		}
+	}
+	return formats, nil
+}
+
+// IsSBOMFile reports whether filename is an SBOM written next to an artifact
+func IsSBOMFile(filename string) bool {
+	return strings.HasSuffix(filename, SPDXSuffix) || strings.HasSuffix(filename, CycloneDXSuffix)
+}
+
+// SBOMModule is a Go module linked into an artifact
+type SBOMModule struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+}
+
+// SBOMArtifact is what an SBOM records about a release artifact: its
+// checksum, the toolchain it was built with and its main module and
+// dependencies
+type SBOMArtifact struct {
+	Name      string       `json:"name"`
+	Checksum  string       `json:"checksum"`
+	GoVersion string       `json:"go_version"`
+	Main      SBOMModule   `json:"main"`
+	Deps      []SBOMModule `json:"deps"`
+}
+
+// ReadSBOMArtifact reads the modules embedded in the Go binary at path. A
+// main module built outside a tagged checkout reports "(devel)", which is
+// replaced with version.
+func ReadSBOMArtifact(path, name, version string) (SBOMArtifact, error) {
+	info, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return SBOMArtifact{}, fmt.Errorf("failed to read modules of %s: %w", name, err)
+	}
+	checksum, err := FileChecksum(path, SHA256)
+	if err != nil {
+		return SBOMArtifact{}, err
+	}
+
+	artifact := SBOMArtifact{
+		Name:      name,
+		Checksum:  checksum,
+		GoVersion: info.GoVersion,
+		Main:      SBOMModule{Path: info.Main.Path, Version: info.Main.Version},
+	}
+	if artifact.Main.Version == "" || artifact.Main.Version == "(devel)" {
+		artifact.Main.Version = version
+	}
+	for _, dep := range info.Deps {
+		// A replaced module is recorded as the module actually linked
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		artifact.Deps = append(artifact.Deps, SBOMModule{Path: dep.Path, Version: dep.Version})
+	}
+	sort.Slice(artifact.Deps, func(i, j int) bool {
+		return artifact.Deps[i].Path < artifact.Deps[j].Path
+	})
+	return artifact, nil
+}
+
+// purl returns the package URL of a Go module
+func purl(module SBOMModule) string {
+	return "pkg:golang/" + module.Path + "@" + strings.ReplaceAll(module.Version, "+", "%2B")
+}
+
+// stdlib returns the Go standard library an artifact was built with, which
+// vulnerability scanners match like any other module
+func (a SBOMArtifact) stdlib() SBOMModule {
+	return SBOMModule{Path: "stdlib", Version: a.GoVersion}
+}
+
+// documentID derives a stable identifier for the SBOM of an artifact in
+// format, so regenerating it for the same binary gives the same document
+func (a SBOMArtifact) documentID(format SBOMFormat) string {
+	sum := sha256.Sum256([]byte(a.Checksum + string(format)))
+	id := sum[:16]
+	id[6] = id[6]&0x0f | 0x50
+	id[8] = id[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:16])
+}
+
+// WriteSBOM writes the SBOM of artifact in format as JSON
+func WriteSBOM(w io.Writer, artifact SBOMArtifact, format SBOMFormat, created time.Time) error {
+	var document interface{}
+	switch format {
+	case SPDX:
+		document = spdxDocument(artifact, created)
+	case CycloneDX:
+		document = cycloneDXDocument(artifact, created)
+	default:
+		return fmt.Errorf("unsupported SBOM format %q", format)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
+
+// WriteSBOMFiles writes an SBOM in each format next to the Go binary at
+// path and returns the files written
+func WriteSBOMFiles(path, name, version string, formats []SBOMFormat, created time.Time) ([]string, error) {
+	artifact, err := ReadSBOMArtifact(path, name, version)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, format := range formats {
+		var content strings.Builder
+		if err := WriteSBOM(&content, artifact, format, created); err != nil {
+			return nil, err
+		}
+		file := path + format.Suffix()
+		if err := os.WriteFile(file, []byte(content.String()), 0644); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// SPDX 2.3 documents
+
+type spdxDoc struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	Checksums        []spdxChecksum    `json:"checksums,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// spdxID turns name into an SPDX element identifier, which allows only
+// letters, digits, dots and dashes
+func spdxID(name string) string {
+	id := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+			return r
+		}
+		return '-'
+	}, name)
+	return "SPDXRef-Package-" + id
+}
+
+// spdxModule returns the SPDX package of a module
+func spdxModule(module SBOMModule) spdxPackage {
+	return spdxPackage{
+		Name:             module.Path,
+		SPDXID:           spdxID(module.Path + "-" + module.Version),
+		VersionInfo:      module.Version,
+		DownloadLocation: "NOASSERTION",
+		ExternalRefs: []spdxExternalRef{{
+			ReferenceCategory: "PACKAGE-MANAGER",
+			ReferenceType:     "purl",
+			ReferenceLocator:  purl(module),
+		}},
+	}
+}
+
+func spdxDocument(artifact SBOMArtifact, created time.Time) spdxDoc {
+	main := spdxModule(artifact.Main)
+	main.Name = artifact.Name
+	main.SPDXID = spdxID(artifact.Name)
+	main.Checksums = []spdxChecksum{{Algorithm: "SHA256", ChecksumValue: artifact.Checksum}}
+
+	doc := spdxDoc{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              artifact.Name,
+		DocumentNamespace: "https://" + artifact.Main.Path + "/spdx/" + artifact.Name + "-" + artifact.documentID(SPDX),
+		CreationInfo: spdxCreationInfo{
+			Created:  created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: " + sbomTool},
+		},
+		Packages: []spdxPackage{main},
+		Relationships: []spdxRelationship{
+			{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: main.SPDXID},
+		},
+	}
+	for _, module := range append([]SBOMModule{artifact.stdlib()}, artifact.Deps...) {
+		pkg := spdxModule(module)
+		doc.Packages = append(doc.Packages, pkg)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID: main.SPDXID, RelationshipType: "DEPENDS_ON", RelatedSPDXElement: pkg.SPDXID,
+		})
+	}
+	return doc
+}
+
+// CycloneDX 1.5 documents
+
+type cdxDoc struct {
+	BOMFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	SerialNumber string          `json:"serialNumber"`
+	Version      int             `json:"version"`
+	Metadata     cdxMetadata     `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies"`
+}
+
+type cdxMetadata struct {
+	Timestamp string       `json:"timestamp"`
+	Tools     cdxTools     `json:"tools"`
+	Component cdxComponent `json:"component"`
+}
+
+type cdxTools struct {
+	Components []cdxComponent `json:"components"`
+}
+
+type cdxComponent struct {
+	Type    string    `json:"type"`
+	BOMRef  string    `json:"bom-ref,omitempty"`
+	Name    string    `json:"name"`
+	Version string    `json:"version,omitempty"`
+	PURL    string    `json:"purl,omitempty"`
+	Hashes  []cdxHash `json:"hashes,omitempty"`
+}
+
+type cdxHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+func cycloneDXDocument(artifact SBOMArtifact, created time.Time) cdxDoc {
+	main := cdxComponent{
+		Type:    "application",
+		BOMRef:  purl(artifact.Main),
+		Name:    artifact.Name,
+		Version: artifact.Main.Version,
+		PURL:    purl(artifact.Main),
+		Hashes:  []cdxHash{{Alg: "SHA-256", Content: artifact.Checksum}},
+	}
+	doc := cdxDoc{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + artifact.documentID(CycloneDX),
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: created.UTC().Format(time.RFC3339),
+			Tools:     cdxTools{Components: []cdxComponent{{Type: "application", Name: sbomTool}}},
+			Component: main,
+		},
+		Components: []cdxComponent{},
+	}
+	dependsOn := []string{}
+	for _, module := range append([]SBOMModule{artifact.stdlib()}, artifact.Deps...) {
+		ref := purl(module)
+		doc.Components = append(doc.Components, cdxComponent{
+			Type: "library", BOMRef: ref, Name: module.Path, Version: module.Version, PURL: ref,
+		})
+		dependsOn = append(dependsOn, ref)
+	}
+	doc.Dependencies = []cdxDependency{{Ref: main.BOMRef, DependsOn: dependsOn}}
+	return doc
+}
@@ -0,0 +1,143 @@
+package distribution
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBinary copies the running test binary, a Go binary with embedded
+// module information, into a temporary directory
+func testBinary(t *testing.T) string {
+	data, err := os.ReadFile(os.Args[0])
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "app-linux-amd64")
+	require.NoError(t, os.WriteFile(path, data, 0755))
+	return path
+}
+
+func TestParseSBOMFormats(t *testing.T) {
+	formats, err := ParseSBOMFormats("spdx, CycloneDX")
+	require.NoError(t, err)
+	assert.Equal(t, []SBOMFormat{SPDX, CycloneDX}, formats)
+
+	_, err = ParseSBOMFormats("spdx,swid")
+	assert.Error(t, err)
+
+	assert.True(t, IsSBOMFile("app-linux.spdx.json"))
+	assert.True(t, IsSBOMFile("app-linux.cdx.json"))
+	assert.False(t, IsSBOMFile("app-linux.json"))
+}
+
+func TestReadSBOMArtifact(t *testing.T) {
+	path := testBinary(t)
+	artifact, err := ReadSBOMArtifact(path, "app-linux-amd64", "v1.2.3")
+	require.NoError(t, err)
+
+	assert.Equal(t, "app-linux-amd64", artifact.Name)
+	assert.Equal(t, "github.com/nettracex/nettracex-tui", artifact.Main.Path)
+	assert.Equal(t, "v1.2.3", artifact.Main.Version, "a development build takes the release version")
+	assert.True(t, strings.HasPrefix(artifact.GoVersion, "go"))
+	assert.Len(t, artifact.Checksum, 64)
+
+	found := false
+	for _, dep := range artifact.Deps {
+		if dep.Path == "github.com/stretchr/testify" {
+			found = true
+			assert.NotEmpty(t, dep.Version)
+		}
+	}
+	assert.True(t, found, "expected testify among the dependencies")
+
+	// A file that is not a Go binary has no modules to record
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"), 0755))
+	_, err = ReadSBOMArtifact(path, "app-linux-amd64", "v1.2.3")
+	assert.Error(t, err)
+}
+
+func TestWriteSBOM(t *testing.T) {
+	artifact := SBOMArtifact{
+		Name:      "app-linux-amd64",
+		Checksum:  strings.Repeat("a", 64),
+		GoVersion: "go1.24.0",
+		Main:      SBOMModule{Path: "github.com/nettracex/nettracex-tui", Version: "v1.2.3"},
+		Deps: []SBOMModule{
+			{Path: "github.com/miekg/dns", Version: "v1.1.62"},
+			{Path: "github.com/old/thing", Version: "v2.0.0+incompatible"},
+		},
+	}
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var spdx bytes.Buffer
+	require.NoError(t, WriteSBOM(&spdx, artifact, SPDX, created))
+	var doc spdxDoc
+	require.NoError(t, json.Unmarshal(spdx.Bytes(), &doc))
+	assert.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+	assert.Equal(t, "2024-03-01T12:00:00Z", doc.CreationInfo.Created)
+	require.Len(t, doc.Packages, 4, "the artifact, stdlib and two dependencies")
+	assert.Equal(t, "app-linux-amd64", doc.Packages[0].Name)
+	assert.Equal(t, strings.Repeat("a", 64), doc.Packages[0].Checksums[0].ChecksumValue)
+	assert.Equal(t, "pkg:golang/stdlib@go1.24.0", doc.Packages[1].ExternalRefs[0].ReferenceLocator)
+	assert.Equal(t, "pkg:golang/github.com/old/thing@v2.0.0%2Bincompatible", doc.Packages[3].ExternalRefs[0].ReferenceLocator)
+	assert.Equal(t, "SPDXRef-Package-github.com-miekg-dns-v1.1.62", doc.Packages[2].SPDXID)
+	assert.Len(t, doc.Relationships, 4)
+	assert.Equal(t, "DESCRIBES", doc.Relationships[0].RelationshipType)
+
+	var cdx bytes.Buffer
+	require.NoError(t, WriteSBOM(&cdx, artifact, CycloneDX, created))
+	var bom cdxDoc
+	require.NoError(t, json.Unmarshal(cdx.Bytes(), &bom))
+	assert.Equal(t, "CycloneDX", bom.BOMFormat)
+	assert.Regexp(t, `^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, bom.SerialNumber)
+	assert.Equal(t, "pkg:golang/github.com/nettracex/nettracex-tui@v1.2.3", bom.Metadata.Component.PURL)
+	assert.Len(t, bom.Components, 3)
+	require.Len(t, bom.Dependencies, 1)
+	assert.Len(t, bom.Dependencies[0].DependsOn, 3)
+
+	// The same binary gives the same document
+	var again bytes.Buffer
+	require.NoError(t, WriteSBOM(&again, artifact, CycloneDX, created))
+	assert.Equal(t, cdx.String(), again.String())
+
+	assert.Error(t, WriteSBOM(&again, artifact, "swid", created))
+}
+
+func TestGitHubValidator_RequireSBOM(t *testing.T) {
+	path := testBinary(t)
+	files, err := WriteSBOMFiles(path, "app-linux-amd64", "v1.2.3", []SBOMFormat{SPDX, CycloneDX}, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []string{path + SPDXSuffix, path + CycloneDXSuffix}, files)
+
+	validator := NewGitHubValidator(GitHubValidatorConfig{RequireSBOM: true})
+	release := Release{
+		Tag: "v1.2.3",
+		Binaries: map[string]Binary{
+			"app-linux-amd64":   {Size: 1024, FilePath: path},
+			"app-windows-amd64": {Size: 1024},
+		},
+		SBOMs: map[string][]string{"app-linux-amd64": files},
+	}
+
+	result, err := validator.ValidateRelease(context.Background(), release)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "MISSING_SBOM", result.Errors[0].Code)
+	assert.Contains(t, result.Errors[0].Message, "app-windows-amd64")
+
+	// A listed SBOM that was removed is reported too
+	delete(release.Binaries, "app-windows-amd64")
+	require.NoError(t, os.Remove(files[1]))
+	result, err = validator.ValidateRelease(context.Background(), release)
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0].Message, filepath.Base(files[1]))
+}
@@ -0,0 +1,377 @@
+package distribution
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/update"
+)
+
+// ScoopPublisher manages Scoop manifest creation and publishing to a bucket
+type ScoopPublisher struct {
+	config ScoopConfig
+	client *http.Client
+	status PublishStatus
+}
+
+// ScoopConfig contains Scoop publishing configuration
+type ScoopConfig struct {
+	BucketRepo   string `json:"bucket_repo"`   // e.g., "nettracex/scoop-bucket"
+	ManifestName string `json:"manifest_name"` // e.g., "nettracex"
+	ManifestDir  string `json:"manifest_dir"`  // directory of the bucket holding manifests
+	Branch       string `json:"branch"`
+	GitHubToken  string `json:"github_token"`
+	BaseURL      string `json:"base_url"`
+	Description  string `json:"description"`
+	Homepage     string `json:"homepage"`
+	License      string `json:"license"`
+	ReleaseRepo  string `json:"release_repo"` // "owner/repo" whose releases checkver follows
+}
+
+// ScoopManifest represents a Scoop app manifest
+type ScoopManifest struct {
+	Version      string                       `json:"version"`
+	Description  string                       `json:"description"`
+	Homepage     string                       `json:"homepage"`
+	License      string                       `json:"license"`
+	Architecture map[string]ScoopArchitecture `json:"architecture"`
+	Checkver     *ScoopCheckver               `json:"checkver,omitempty"`
+	Autoupdate   ScoopAutoupdate              `json:"autoupdate"`
+}
+
+// ScoopArchitecture is the download of one architecture. Bin lists the
+// executables to shim, each as the file and the command it is run as.
+type ScoopArchitecture struct {
+	URL  string     `json:"url"`
+	Hash string     `json:"hash,omitempty"`
+	Bin  [][]string `json:"bin,omitempty"`
+}
+
+// ScoopCheckver tells Scoop where to look for new versions
+type ScoopCheckver struct {
+	GitHub string `json:"github"`
+}
+
+// ScoopAutoupdate describes how a new version's manifest is derived: the
+// download URLs with $version in place of the version, and hashes read from
+// the release checksum file
+type ScoopAutoupdate struct {
+	Architecture map[string]ScoopArchitecture `json:"architecture"`
+	Hash         ScoopHashSource              `json:"hash"`
+}
+
+// ScoopHashSource is where autoupdate reads hashes from
+type ScoopHashSource struct {
+	URL string `json:"url"`
+}
+
+// scoopArchitectures maps Go architectures to Scoop's names
+var scoopArchitectures = map[string]string{
+	"amd64": "64bit",
+	"386":   "32bit",
+	"arm64": "arm64",
+}
+
+// NewScoopPublisher creates a new Scoop publisher
+func NewScoopPublisher(config ScoopConfig) *ScoopPublisher {
+	if config.BaseURL == "" {
+		config.BaseURL = "https://api.github.com"
+	}
+	if config.ManifestDir == "" {
+		config.ManifestDir = "bucket"
+	}
+	if config.Branch == "" {
+		config.Branch = "main"
+	}
+
+	return &ScoopPublisher{
+		config: config,
+		client: &http.Client{Timeout: 30 * time.Second},
+		status: PublishStatus{
+			Name:   "scoop",
+			Status: StatusIdle,
+		},
+	}
+}
+
+// GetName returns the publisher name
+func (p *ScoopPublisher) GetName() string {
+	return "scoop"
+}
+
+// DefaultChannels ships stable releases only, as Scoop has no prereleases
+// and the bucket manifest is what every user updates to
+func (p *ScoopPublisher) DefaultChannels() []update.Channel {
+	return []update.Channel{update.Stable}
+}
+
+// Publish publishes a release to the Scoop bucket
+func (p *ScoopPublisher) Publish(ctx context.Context, release Release) error {
+	p.updateStatus(StatusPublishing, "")
+
+	// Generate manifest
+	manifest, err := p.GenerateManifest(release)
+	if err != nil {
+		p.updateStatus(StatusError, err.Error())
+		return fmt.Errorf("failed to generate manifest: %w", err)
+	}
+
+	// Validate manifest
+	if err := ValidateScoopManifest(manifest); err != nil {
+		p.updateStatus(StatusError, err.Error())
+		return fmt.Errorf("manifest validation failed: %w", err)
+	}
+
+	content, err := p.RenderManifest(manifest)
+	if err != nil {
+		p.updateStatus(StatusError, err.Error())
+		return fmt.Errorf("failed to render manifest: %w", err)
+	}
+
+	// Commit the manifest to the bucket repository
+	if err := p.pushManifest(ctx, manifest.Version, content); err != nil {
+		p.updateStatus(StatusError, err.Error())
+		return fmt.Errorf("failed to push manifest: %w", err)
+	}
+
+	p.updateStatus(StatusSuccess, "")
+	return nil
+}
+
+// DryRun renders the manifest the bucket would get, checking that the
+// token can push to the bucket repository
+func (p *ScoopPublisher) DryRun(ctx context.Context, release Release) (*DryRunReport, error) {
+	report := &DryRunReport{Files: make(map[string]string)}
+	if err := p.Validate(ctx, release); err != nil {
+		return report, err
+	}
+
+	manifest, err := p.GenerateManifest(release)
+	if err != nil {
+		return report, fmt.Errorf("failed to generate manifest: %w", err)
+	}
+	if err := ValidateScoopManifest(manifest); err != nil {
+		return report, fmt.Errorf("manifest validation failed: %w", err)
+	}
+	content, err := p.RenderManifest(manifest)
+	if err != nil {
+		return report, fmt.Errorf("failed to render manifest: %w", err)
+	}
+	report.Files[p.manifestPath()] = content
+
+	if p.config.BucketRepo == "" {
+		return report, fmt.Errorf("bucket repository is required")
+	}
+	report.Actions = append(report.Actions, fmt.Sprintf("commit %s to %s on branch %s", p.manifestPath(), p.config.BucketRepo, p.config.Branch))
+
+	bucket := githubContents{baseURL: p.config.BaseURL, token: p.config.GitHubToken, client: p.client}
+	if err := bucket.checkPush(ctx, p.config.BucketRepo); err != nil {
+		return report, fmt.Errorf("credential check failed: %w", err)
+	}
+	return report, nil
+}
+
+// Validate validates a release for Scoop publishing
+func (p *ScoopPublisher) Validate(ctx context.Context, release Release) error {
+	if release.Version == "" {
+		return fmt.Errorf("release version is required")
+	}
+
+	found := false
+	for filename, binary := range release.Binaries {
+		if binary.Platform != "windows" {
+			continue
+		}
+		if _, supported := scoopArchitectures[binary.Architecture]; !supported {
+			continue
+		}
+		if binary.DownloadURL == "" {
+			return fmt.Errorf("binary %s missing download URL", filename)
+		}
+		found = true
+	}
+
+	if !found {
+		return fmt.Errorf("no supported binary found in release (Windows required)")
+	}
+
+	return nil
+}
+
+// GetStatus returns the current status of the Scoop publisher
+func (p *ScoopPublisher) GetStatus() PublishStatus {
+	p.status.Metadata = map[string]string{
+		"bucket_repo": p.config.BucketRepo,
+		"manifest":    p.config.ManifestName,
+	}
+	return p.status
+}
+
+// updateStatus updates the publisher status
+func (p *ScoopPublisher) updateStatus(status StatusType, lastError string) {
+	p.status.Status = status
+	p.status.LastError = lastError
+	if status == StatusSuccess {
+		p.status.LastPublish = time.Now()
+		p.status.PublishCount++
+	} else if status == StatusError {
+		p.status.ErrorCount++
+	}
+}
+
+// GenerateManifest creates a Scoop manifest from the Windows binaries of a
+// release
+func (p *ScoopPublisher) GenerateManifest(release Release) (*ScoopManifest, error) {
+	version := strings.TrimPrefix(release.Version, "v")
+	manifest := &ScoopManifest{
+		Version:      version,
+		Description:  p.config.Description,
+		Homepage:     p.config.Homepage,
+		License:      p.config.License,
+		Architecture: make(map[string]ScoopArchitecture),
+		Autoupdate: ScoopAutoupdate{
+			Architecture: make(map[string]ScoopArchitecture),
+		},
+	}
+	if p.config.ReleaseRepo != "" {
+		manifest.Checkver = &ScoopCheckver{GitHub: "https://github.com/" + p.config.ReleaseRepo}
+	}
+
+	for _, binary := range release.Binaries {
+		if binary.Platform != "windows" {
+			continue
+		}
+		arch, supported := scoopArchitectures[binary.Architecture]
+		if !supported {
+			continue
+		}
+
+		// Calculate SHA256 if not provided
+		hash := binary.Checksum
+		if hash == "" {
+			sum, err := downloadSHA256(p.client, binary.DownloadURL)
+			if err != nil {
+				return nil, fmt.Errorf("failed to calculate SHA256 for %s: %w", binary.Filename, err)
+			}
+			hash = sum
+		}
+
+		bin := p.scoopBin(binary.Filename)
+		manifest.Architecture[arch] = ScoopArchitecture{
+			URL:  binary.DownloadURL,
+			Hash: hash,
+			Bin:  bin,
+		}
+		manifest.Autoupdate.Architecture[arch] = ScoopArchitecture{
+			URL: strings.ReplaceAll(binary.DownloadURL, version, "$version"),
+			Bin: scoopAutoupdateBin(bin, version),
+		}
+	}
+
+	if len(manifest.Architecture) == 0 {
+		return nil, fmt.Errorf("no supported binaries found (Windows required)")
+	}
+
+	manifest.Autoupdate.Hash.URL = "$baseurl/" + ChecksumsFile
+	return manifest, nil
+}
+
+// scoopBin returns the executable shimmed for a download: the download
+// itself for a bare executable, the executable inside it for an archive.
+// Either way it runs as the manifest name.
+func (p *ScoopPublisher) scoopBin(filename string) [][]string {
+	executable := filename
+	if strings.HasSuffix(strings.ToLower(filename), ".zip") {
+		executable = p.config.ManifestName + ".exe"
+	}
+	return [][]string{{executable, p.config.ManifestName}}
+}
+
+// scoopAutoupdateBin puts $version in place of the version in the names of
+// executables
+func scoopAutoupdateBin(bin [][]string, version string) [][]string {
+	updated := make([][]string, len(bin))
+	for i, entry := range bin {
+		updated[i] = []string{strings.ReplaceAll(entry[0], version, "$version"), entry[1]}
+	}
+	return updated
+}
+
+// RenderManifest renders the manifest as the indented JSON Scoop buckets
+// keep
+func (p *ScoopPublisher) RenderManifest(manifest *ScoopManifest) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(manifest); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ValidateScoopManifest validates a Scoop manifest
+func ValidateScoopManifest(manifest *ScoopManifest) error {
+	if manifest.Version == "" {
+		return fmt.Errorf("manifest version is required")
+	}
+
+	if manifest.Description == "" {
+		return fmt.Errorf("manifest description is required")
+	}
+
+	if manifest.Homepage == "" {
+		return fmt.Errorf("manifest homepage is required")
+	}
+
+	if manifest.License == "" {
+		return fmt.Errorf("manifest license is required")
+	}
+
+	if len(manifest.Architecture) == 0 {
+		return fmt.Errorf("manifest needs at least one architecture")
+	}
+
+	archs := make([]string, 0, len(manifest.Architecture))
+	for arch := range manifest.Architecture {
+		archs = append(archs, arch)
+	}
+	sort.Strings(archs)
+	for _, arch := range archs {
+		download := manifest.Architecture[arch]
+		if download.URL == "" {
+			return fmt.Errorf("%s URL is required", arch)
+		}
+		if len(download.Hash) != 64 {
+			return fmt.Errorf("invalid %s SHA256 hash length", arch)
+		}
+		if len(download.Bin) == 0 {
+			return fmt.Errorf("%s bin is required", arch)
+		}
+	}
+
+	return nil
+}
+
+// manifestPath returns the path of the manifest in the bucket repository
+func (p *ScoopPublisher) manifestPath() string {
+	return strings.Trim(p.config.ManifestDir, "/") + "/" + p.config.ManifestName + ".json"
+}
+
+// pushManifest creates or updates the manifest in the bucket repository
+// with the GitHub contents API
+func (p *ScoopPublisher) pushManifest(ctx context.Context, version, content string) error {
+	if p.config.BucketRepo == "" {
+		return fmt.Errorf("bucket repository is required")
+	}
+
+	bucket := githubContents{baseURL: p.config.BaseURL, token: p.config.GitHubToken, client: p.client}
+	message := fmt.Sprintf("%s: Update to version %s", p.config.ManifestName, version)
+	return bucket.putFile(ctx, p.config.BucketRepo, p.manifestPath(), p.config.Branch, message, content)
+}
@@ -0,0 +1,142 @@
+package distribution
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scoopRelease() Release {
+	base := "https://github.com/nettracex/nettracex-tui/releases/download/v1.2.3/"
+	return testRelease("v1.2.3", hostedAt(base, windowsPlatforms)...)
+}
+
+func scoopConfig(baseURL string) ScoopConfig {
+	return ScoopConfig{
+		BucketRepo:   "nettracex/scoop-bucket",
+		ManifestName: "nettracex",
+		GitHubToken:  "secret",
+		BaseURL:      baseURL,
+		Description:  testDescription,
+		Homepage:     testHomepage,
+		License:      "MIT",
+		ReleaseRepo:  testReleaseRepo,
+	}
+}
+
+func TestScoopPublisher_GenerateManifest(t *testing.T) {
+	publisher := NewScoopPublisher(scoopConfig(""))
+	require.NoError(t, publisher.Validate(context.Background(), scoopRelease()))
+
+	manifest, err := publisher.GenerateManifest(scoopRelease())
+	require.NoError(t, err)
+	require.NoError(t, ValidateScoopManifest(manifest))
+
+	assert.Equal(t, "1.2.3", manifest.Version)
+	assert.Len(t, manifest.Architecture, 2, "only Windows binaries are listed")
+	assert.Equal(t, ScoopArchitecture{
+		URL:  "https://github.com/nettracex/nettracex-tui/releases/download/v1.2.3/nettracex_1.2.3_Windows_x86_64.zip",
+		Hash: strings.Repeat("a", 64),
+		Bin:  [][]string{{"nettracex.exe", "nettracex"}},
+	}, manifest.Architecture["64bit"])
+	assert.Equal(t, [][]string{{"nettracex-windows-arm64.exe", "nettracex"}}, manifest.Architecture["arm64"].Bin)
+
+	assert.Equal(t, "https://github.com/nettracex/nettracex-tui", manifest.Checkver.GitHub)
+	assert.Equal(t, "https://github.com/nettracex/nettracex-tui/releases/download/v$version/nettracex_$version_Windows_x86_64.zip",
+		manifest.Autoupdate.Architecture["64bit"].URL)
+	assert.Empty(t, manifest.Autoupdate.Architecture["64bit"].Hash)
+	assert.Equal(t, "$baseurl/checksums.txt", manifest.Autoupdate.Hash.URL)
+
+	content, err := publisher.RenderManifest(manifest)
+	require.NoError(t, err)
+	assert.Contains(t, content, `"checkver": {`)
+	assert.Contains(t, content, `"64bit": {`)
+
+	// A release without Windows binaries has nothing to publish
+	release := scoopRelease()
+	for name, binary := range release.Binaries {
+		if binary.Platform == "windows" {
+			delete(release.Binaries, name)
+		}
+	}
+	assert.Error(t, publisher.Validate(context.Background(), release))
+	_, err = publisher.GenerateManifest(release)
+	assert.Error(t, err)
+}
+
+func TestValidateScoopManifest(t *testing.T) {
+	publisher := NewScoopPublisher(scoopConfig(""))
+	manifest, err := publisher.GenerateManifest(scoopRelease())
+	require.NoError(t, err)
+
+	manifest.License = ""
+	assert.ErrorContains(t, ValidateScoopManifest(manifest), "license")
+
+	manifest.License = "MIT"
+	download := manifest.Architecture["arm64"]
+	download.Hash = "abc"
+	manifest.Architecture["arm64"] = download
+	assert.ErrorContains(t, ValidateScoopManifest(manifest), "arm64 SHA256")
+}
+
+func TestScoopPublisher_Publish(t *testing.T) {
+	var pushed map[string]string
+	existing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/nettracex/scoop-bucket/contents/bucket/nettracex.json", r.URL.Path)
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		switch r.Method {
+		case http.MethodGet:
+			assert.Equal(t, "main", r.URL.Query().Get("ref"))
+			if !existing {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"sha": "3d21ec53a331a6f037a91c368710b99387d012c1"})
+		case http.MethodPut:
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&pushed))
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	publisher := NewScoopPublisher(scoopConfig(server.URL))
+	require.NoError(t, publisher.Publish(context.Background(), scoopRelease()))
+
+	assert.Equal(t, "nettracex: Update to version 1.2.3", pushed["message"])
+	assert.Equal(t, "main", pushed["branch"])
+	assert.Equal(t, "3d21ec53a331a6f037a91c368710b99387d012c1", pushed["sha"], "an existing manifest is replaced")
+	content, err := base64.StdEncoding.DecodeString(pushed["content"])
+	require.NoError(t, err)
+	var manifest ScoopManifest
+	require.NoError(t, json.Unmarshal(content, &manifest))
+	assert.Equal(t, "1.2.3", manifest.Version)
+	assert.Equal(t, StatusSuccess, publisher.GetStatus().Status)
+
+	// A new manifest is created without a SHA
+	existing = false
+	pushed = nil
+	require.NoError(t, publisher.Publish(context.Background(), scoopRelease()))
+	_, hasSHA := pushed["sha"]
+	assert.False(t, hasSHA)
+
+	// A refused push is reported
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"message":"Bad credentials"}`, http.StatusUnauthorized)
+	})
+	err = publisher.Publish(context.Background(), scoopRelease())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Bad credentials")
+	assert.Equal(t, StatusError, publisher.GetStatus().Status)
+}
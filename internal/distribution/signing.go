@@ -0,0 +1,299 @@
+package distribution
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Signature file suffixes written next to each signed file. Cosign
+// signatures get their own suffix so they are not mistaken for the ed25519
+// checksums.txt.sig the self-updater verifies.
+const (
+	GPGSignatureSuffix      = ".asc"
+	CosignSignatureSuffix   = ".cosign.sig"
+	CosignCertificateSuffix = ".cosign.pem"
+)
+
+// SigningConfig selects how the files a publisher ships are signed
+type SigningConfig struct {
+	GPG    GPGConfig    `json:"gpg"`
+	Cosign CosignConfig `json:"cosign"`
+}
+
+// GPGConfig contains settings for detached GPG signatures
+type GPGConfig struct {
+	Enabled bool   `json:"enabled"`
+	KeyID   string `json:"key_id"`  // signing key, gpg's default key when empty
+	Homedir string `json:"homedir"` // GNUPGHOME to use instead of the user's
+	Keyring string `json:"keyring"` // public keyring signatures are verified against
+}
+
+// CosignConfig contains settings for Sigstore signing with cosign. With a
+// key the files are signed with it, otherwise keyless signing is used and
+// verification checks the certificate's identity, or an expression matching
+// it, and its issuer.
+type CosignConfig struct {
+	Enabled        bool   `json:"enabled"`
+	Key            string `json:"key"`        // private key file or KMS URI
+	PublicKey      string `json:"public_key"` // verification key, Key when empty
+	Identity       string `json:"identity"`
+	IdentityRegexp string `json:"identity_regexp"`
+	OIDCIssuer     string `json:"oidc_issuer"`
+}
+
+// Enabled reports whether any signing method is enabled
+func (sc SigningConfig) Enabled() bool {
+	return sc.GPG.Enabled || sc.Cosign.Enabled
+}
+
+// Signer signs release files and verifies their signatures
+type Signer interface {
+	// Sign writes the signature files of path and returns their paths
+	Sign(ctx context.Context, path string) ([]string, error)
+	// Verify checks the signature files of path written by Sign
+	Verify(ctx context.Context, path string) error
+	GetName() string
+}
+
+// commandRunner runs an external command and returns its combined output
+type commandRunner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+// runCommand runs a command with exec
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).CombinedOutput()
+}
+
+// run runs a command through runner, adding its output to the error
+func run(ctx context.Context, runner commandRunner, name string, args ...string) error {
+	output, err := runner(ctx, name, args...)
+	if err != nil {
+		if message := strings.TrimSpace(string(output)); message != "" {
+			return fmt.Errorf("%s: %w: %s", name, err, message)
+		}
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// NewSigners returns a signer for each method enabled in config
+func NewSigners(config SigningConfig) ([]Signer, error) {
+	var signers []Signer
+	if config.GPG.Enabled {
+		signers = append(signers, NewGPGSigner(config.GPG))
+	}
+	if config.Cosign.Enabled {
+		signer, err := NewCosignSigner(config.Cosign)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, signer)
+	}
+	return signers, nil
+}
+
+// GPGSigner writes ASCII-armored detached GPG signatures
+type GPGSigner struct {
+	config GPGConfig
+	run    commandRunner
+}
+
+// NewGPGSigner creates a new GPG signer
+func NewGPGSigner(config GPGConfig) *GPGSigner {
+	return &GPGSigner{config: config, run: runCommand}
+}
+
+// GetName returns the signer name
+func (gs *GPGSigner) GetName() string {
+	return "gpg"
+}
+
+// args returns the options shared by signing and verifying
+func (gs *GPGSigner) args() []string {
+	args := []string{"--batch"}
+	if gs.config.Homedir != "" {
+		args = append(args, "--homedir", gs.config.Homedir)
+	}
+	return args
+}
+
+// Sign writes path.asc
+func (gs *GPGSigner) Sign(ctx context.Context, path string) ([]string, error) {
+	signature := path + GPGSignatureSuffix
+	args := append(gs.args(), "--yes", "--armor")
+	if gs.config.KeyID != "" {
+		args = append(args, "--local-user", gs.config.KeyID)
+	}
+	args = append(args, "--output", signature, "--detach-sign", path)
+	if err := run(ctx, gs.run, "gpg", args...); err != nil {
+		return nil, err
+	}
+	return []string{signature}, nil
+}
+
+// Verify checks path against path.asc
+func (gs *GPGSigner) Verify(ctx context.Context, path string) error {
+	args := gs.args()
+	if gs.config.Keyring != "" {
+		args = append(args, "--no-default-keyring", "--keyring", gs.config.Keyring)
+	}
+	args = append(args, "--verify", path+GPGSignatureSuffix, path)
+	return run(ctx, gs.run, "gpg", args...)
+}
+
+// CosignSigner signs files with cosign sign-blob
+type CosignSigner struct {
+	config CosignConfig
+	run    commandRunner
+}
+
+// NewCosignSigner creates a new cosign signer. Keyless signing needs the
+// identity and issuer its certificates are verified against.
+func NewCosignSigner(config CosignConfig) (*CosignSigner, error) {
+	if config.Key == "" && ((config.Identity == "" && config.IdentityRegexp == "") || config.OIDCIssuer == "") {
+		return nil, fmt.Errorf("cosign keyless signing requires identity or identity_regexp, and oidc_issuer")
+	}
+	return &CosignSigner{config: config, run: runCommand}, nil
+}
+
+// GetName returns the signer name
+func (cs *CosignSigner) GetName() string {
+	return "cosign"
+}
+
+// keyless reports whether files are signed with a Fulcio certificate
+func (cs *CosignSigner) keyless() bool {
+	return cs.config.Key == ""
+}
+
+// Sign writes path.cosign.sig, and path.cosign.pem when signing keyless
+func (cs *CosignSigner) Sign(ctx context.Context, path string) ([]string, error) {
+	signature := path + CosignSignatureSuffix
+	files := []string{signature}
+	args := []string{"sign-blob", "--yes", "--output-signature", signature}
+	if cs.keyless() {
+		certificate := path + CosignCertificateSuffix
+		args = append(args, "--output-certificate", certificate)
+		files = append(files, certificate)
+	} else {
+		args = append(args, "--key", cs.config.Key)
+	}
+	args = append(args, path)
+	if err := run(ctx, cs.run, "cosign", args...); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// Verify checks path against its cosign signature
+func (cs *CosignSigner) Verify(ctx context.Context, path string) error {
+	args := []string{"verify-blob", "--signature", path + CosignSignatureSuffix}
+	if cs.keyless() {
+		args = append(args, "--certificate", path+CosignCertificateSuffix)
+		if cs.config.Identity != "" {
+			args = append(args, "--certificate-identity", cs.config.Identity)
+		} else {
+			args = append(args, "--certificate-identity-regexp", cs.config.IdentityRegexp)
+		}
+		args = append(args, "--certificate-oidc-issuer", cs.config.OIDCIssuer)
+	} else {
+		key := cs.config.PublicKey
+		if key == "" {
+			key = cs.config.Key
+		}
+		args = append(args, "--key", key)
+	}
+	args = append(args, path)
+	return run(ctx, cs.run, "cosign", args...)
+}
+
+// signedFiles returns the files of release that are signed, by name: the
+// binaries and the checksum files
+func signedFiles(release Release) map[string]string {
+	files := make(map[string]string, len(release.Binaries)+len(release.ChecksumFiles))
+	for filename, binary := range release.Binaries {
+		files[filename] = binary.FilePath
+	}
+	for filename, path := range release.ChecksumFiles {
+		files[filename] = path
+	}
+	return files
+}
+
+// sortedNames returns the keys of files in order
+func sortedNames(files map[string]string) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SignRelease signs the binaries and checksum files of release with every
+// signer and returns a copy of release listing the signature files
+func SignRelease(ctx context.Context, release Release, signers []Signer) (Release, error) {
+	files := signedFiles(release)
+	signatures := make(map[string][]string, len(files))
+	for filename, paths := range release.Signatures {
+		signatures[filename] = append([]string(nil), paths...)
+	}
+
+	for _, signer := range signers {
+		for _, filename := range sortedNames(files) {
+			written, err := signer.Sign(ctx, files[filename])
+			if err != nil {
+				return release, fmt.Errorf("%s signing of %s failed: %w", signer.GetName(), filename, err)
+			}
+			signatures[filename] = append(signatures[filename], written...)
+		}
+	}
+
+	release.Signatures = signatures
+	return release, nil
+}
+
+// VerifyRelease checks the signatures of the binaries and checksum files of
+// release with every signer, returning an error listing each failure
+func VerifyRelease(ctx context.Context, release Release, signers []Signer) error {
+	files := signedFiles(release)
+	var problems []string
+	for _, signer := range signers {
+		for _, filename := range sortedNames(files) {
+			if err := signer.Verify(ctx, files[filename]); err != nil {
+				problems = append(problems, fmt.Sprintf("%s %s: %v", signer.GetName(), filename, err))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("signature verification failed: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// ReleaseChecksumFiles returns the checksum files present in dir, by name
+func ReleaseChecksumFiles(dir string) map[string]string {
+	files := make(map[string]string)
+	for _, name := range []string{ChecksumsFile, SHA512ChecksumsFile} {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			files[name] = path
+		}
+	}
+	return files
+}
+
+// IsSignatureFile reports whether filename is a signature or certificate
+// written next to a signed file
+func IsSignatureFile(filename string) bool {
+	for _, suffix := range []string{GPGSignatureSuffix, ".sig", CosignCertificateSuffix} {
+		if strings.HasSuffix(filename, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package distribution
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingRunner records the commands it is asked to run
+type recordingRunner struct {
+	commands []string
+	err      error
+}
+
+func (r *recordingRunner) run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	r.commands = append(r.commands, name+" "+strings.Join(args, " "))
+	if r.err != nil {
+		return []byte("gpg: BAD signature\n"), r.err
+	}
+	return nil, nil
+}
+
+func TestGPGSigner(t *testing.T) {
+	runner := &recordingRunner{}
+	signer := NewGPGSigner(GPGConfig{Enabled: true, KeyID: "release@nettracex.dev", Homedir: "/tmp/gnupg", Keyring: "/keys/release.gpg"})
+	signer.run = runner.run
+
+	files, err := signer.Sign(context.Background(), "bin/app-linux")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bin/app-linux.asc"}, files)
+	require.NoError(t, signer.Verify(context.Background(), "bin/app-linux"))
+
+	assert.Equal(t, []string{
+		"gpg --batch --homedir /tmp/gnupg --yes --armor --local-user release@nettracex.dev --output bin/app-linux.asc --detach-sign bin/app-linux",
+		"gpg --batch --homedir /tmp/gnupg --no-default-keyring --keyring /keys/release.gpg --verify bin/app-linux.asc bin/app-linux",
+	}, runner.commands)
+
+	// The command output explains a failure
+	runner.err = errors.New("exit status 1")
+	err = signer.Verify(context.Background(), "bin/app-linux")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "BAD signature")
+}
+
+func TestCosignSigner(t *testing.T) {
+	_, err := NewCosignSigner(CosignConfig{Enabled: true, OIDCIssuer: "https://token.actions.githubusercontent.com"})
+	assert.Error(t, err, "keyless signing needs an identity")
+
+	runner := &recordingRunner{}
+	keyless, err := NewCosignSigner(CosignConfig{
+		Enabled:        true,
+		IdentityRegexp: "^https://github.com/nettracex/",
+		OIDCIssuer:     "https://token.actions.githubusercontent.com",
+	})
+	require.NoError(t, err)
+	keyless.run = runner.run
+
+	files, err := keyless.Sign(context.Background(), "bin/checksums.txt")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bin/checksums.txt.cosign.sig", "bin/checksums.txt.cosign.pem"}, files)
+	require.NoError(t, keyless.Verify(context.Background(), "bin/checksums.txt"))
+
+	withKey, err := NewCosignSigner(CosignConfig{Enabled: true, Key: "cosign.key", PublicKey: "cosign.pub"})
+	require.NoError(t, err)
+	withKey.run = runner.run
+	files, err = withKey.Sign(context.Background(), "bin/app-linux")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bin/app-linux.cosign.sig"}, files)
+	require.NoError(t, withKey.Verify(context.Background(), "bin/app-linux"))
+
+	assert.Equal(t, []string{
+		"cosign sign-blob --yes --output-signature bin/checksums.txt.cosign.sig --output-certificate bin/checksums.txt.cosign.pem bin/checksums.txt",
+		"cosign verify-blob --signature bin/checksums.txt.cosign.sig --certificate bin/checksums.txt.cosign.pem --certificate-identity-regexp ^https://github.com/nettracex/ --certificate-oidc-issuer https://token.actions.githubusercontent.com bin/checksums.txt",
+		"cosign sign-blob --yes --output-signature bin/app-linux.cosign.sig --key cosign.key bin/app-linux",
+		"cosign verify-blob --signature bin/app-linux.cosign.sig --key cosign.pub bin/app-linux",
+	}, runner.commands)
+}
+
+// fileSigner "signs" a file by copying it next to itself
+type fileSigner struct{}
+
+func (fileSigner) GetName() string { return "copy" }
+
+func (fileSigner) Sign(ctx context.Context, path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return []string{path + ".copy"}, os.WriteFile(path+".copy", data, 0644)
+}
+
+func (fileSigner) Verify(ctx context.Context, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	signature, err := os.ReadFile(path + ".copy")
+	if err != nil {
+		return err
+	}
+	if string(signature) != string(data) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func TestDistribute_Signing(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"app-linux", ChecksumsFile} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name), 0644))
+	}
+	release := Release{
+		Version:       "v1.0.0",
+		Binaries:      map[string]Binary{"app-linux": {Filename: "app-linux", FilePath: filepath.Join(dir, "app-linux")}},
+		ChecksumFiles: ReleaseChecksumFiles(dir),
+	}
+
+	config := &DistributionConfig{
+		Publishers: map[string]PublisherConfig{
+			"signed":   {Enabled: true},
+			"unsigned": {Enabled: true},
+		},
+		RetryPolicy: RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: time.Second, Multiplier: 2.0},
+	}
+	coordinator := NewDistributionCoordinator(config)
+	coordinator.SetSigners("signed", fileSigner{})
+
+	signed := &MockPublisher{name: "signed"}
+	signed.On("Publish", mock.Anything, mock.MatchedBy(func(r Release) bool {
+		return assert.ObjectsAreEqual(map[string][]string{
+			"app-linux":   {filepath.Join(dir, "app-linux.copy")},
+			ChecksumsFile: {filepath.Join(dir, ChecksumsFile+".copy")},
+		}, r.Signatures)
+	})).Return(nil)
+	unsigned := &MockPublisher{name: "unsigned"}
+	unsigned.On("Publish", mock.Anything, mock.MatchedBy(func(r Release) bool {
+		return len(r.Signatures) == 0
+	})).Return(nil)
+	coordinator.RegisterPublisher(signed)
+	coordinator.RegisterPublisher(unsigned)
+
+	require.NoError(t, coordinator.Distribute(context.Background(), release))
+	signed.AssertExpectations(t)
+	unsigned.AssertExpectations(t)
+
+	// Signatures that do not verify stop the release
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app-linux"), []byte("tampered"), 0644))
+	assert.Error(t, VerifyRelease(context.Background(), release, []Signer{fileSigner{}}))
+	require.NoError(t, os.Remove(filepath.Join(dir, ChecksumsFile+".copy")))
+	err := VerifyRelease(context.Background(), release, []Signer{fileSigner{}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "copy app-linux: signature mismatch")
+	assert.Contains(t, err.Error(), "copy checksums.txt:")
+}
+
+func TestIsSignatureFile(t *testing.T) {
+	for _, name := range []string{"checksums.txt.asc", "checksums.txt.sig", "app-linux.cosign.sig", "app-linux.cosign.pem"} {
+		assert.True(t, IsSignatureFile(name), name)
+	}
+	assert.False(t, IsSignatureFile("app-linux"))
+	assert.False(t, IsSignatureFile(ChecksumsFile))
+}
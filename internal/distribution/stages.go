@@ -0,0 +1,289 @@
+package distribution
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PublishStage is a group of publishers that run together. Stages run in
+// order, each once the previous one has succeeded.
+type PublishStage struct {
+	Number     int      `json:"number"`
+	Publishers []string `json:"publishers"`
+	// DependsOn lists the publishers of the run each publisher waits for
+	DependsOn map[string][]string `json:"depends_on,omitempty"`
+}
+
+// Plan returns the stages the enabled publishers run in
+func (dc *DistributionCoordinator) Plan() ([]PublishStage, error) {
+	return dc.planStages(dc.getEnabledPublishers())
+}
+
+// planStages groups publishers by stage, in priority order within a stage,
+// and resolves their dependencies. A dependency on a publisher that is
+// configured but not enabled is left out, since its work is not part of
+// the run; unknown publishers, dependencies on a later stage and cycles
+// are errors.
+func (dc *DistributionCoordinator) planStages(publishers []Publisher) ([]PublishStage, error) {
+	dc.mu.RLock()
+	defer dc.mu.RUnlock()
+
+	enabled := make(map[string]bool, len(publishers))
+	for _, publisher := range publishers {
+		enabled[publisher.GetName()] = true
+	}
+
+	dependencies := make(map[string][]string)
+	for _, publisher := range publishers {
+		name := publisher.GetName()
+		config := dc.config.Publishers[name]
+		for _, dep := range config.DependsOn {
+			switch {
+			case dep == name:
+				return nil, fmt.Errorf("publisher %s depends on itself", name)
+			case enabled[dep]:
+				if stage := dc.config.Publishers[dep].Stage; stage > config.Stage {
+					return nil, fmt.Errorf("publisher %s of stage %d depends on %s of later stage %d", name, config.Stage, dep, stage)
+				}
+				dependencies[name] = append(dependencies[name], dep)
+			default:
+				_, configured := dc.config.Publishers[dep]
+				_, registered := dc.publishers[dep]
+				if !configured && !registered {
+					return nil, fmt.Errorf("publisher %s depends on unknown publisher %s", name, dep)
+				}
+				log.Printf("Publisher %s: dependency %s is not enabled, not waiting for it", name, dep)
+			}
+		}
+	}
+	if cycle := dependencyCycle(dependencies); cycle != nil {
+		return nil, fmt.Errorf("publisher dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	byStage := make(map[int]*PublishStage)
+	var numbers []int
+	for _, publisher := range publishers {
+		name := publisher.GetName()
+		number := dc.config.Publishers[name].Stage
+		stage, exists := byStage[number]
+		if !exists {
+			stage = &PublishStage{Number: number}
+			byStage[number] = stage
+			numbers = append(numbers, number)
+		}
+		stage.Publishers = append(stage.Publishers, name)
+		if deps := dependencies[name]; len(deps) > 0 {
+			if stage.DependsOn == nil {
+				stage.DependsOn = make(map[string][]string)
+			}
+			stage.DependsOn[name] = deps
+		}
+	}
+	sort.Ints(numbers)
+
+	stages := make([]PublishStage, 0, len(numbers))
+	for _, number := range numbers {
+		stages = append(stages, *byStage[number])
+	}
+	return stages, nil
+}
+
+// dependencyCycle returns a cycle of dependencies, or nil when there is none
+func dependencyCycle(dependencies map[string][]string) []string {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	var path []string
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch state[name] {
+		case visiting:
+			for i, step := range path {
+				if step == name {
+					return append(append([]string{}, path[i:]...), name)
+				}
+			}
+		case visited:
+			return nil
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range dependencies[name] {
+			if cycle := visit(dep); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+
+	names := make([]string, 0, len(dependencies))
+	for name := range dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if cycle := visit(name); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// publishStages runs the stages in order, recording what each publisher
+// published. Publishers in completed already published the release in an
+// earlier run; they are skipped and count as succeeded for the ones
+// depending on them. A failing stage stops the rollout, skipping the stages
+// after it.
+func (dc *DistributionCoordinator) publishStages(ctx context.Context, releases map[string]Release, publishers []Publisher, stages []PublishStage, completed map[string]bool) error {
+	byName := make(map[string]Publisher, len(publishers))
+	for _, publisher := range publishers {
+		byName[publisher.GetName()] = publisher
+	}
+
+	run := &stageRun{results: make(map[string]error), completed: completed}
+	var errors []error
+	failedStage := -1
+	for i, stage := range stages {
+		if failedStage >= 0 {
+			for _, name := range stage.Publishers {
+				if completed[name] {
+					continue
+				}
+				err := fmt.Errorf("publisher %s skipped: stage %d failed", name, stages[failedStage].Number)
+				errors = append(errors, err)
+				dc.notifyResult(name, releases[name], err)
+			}
+			continue
+		}
+
+		if stageErrors := dc.publishStage(ctx, releases, byName, stage, run); len(stageErrors) > 0 {
+			errors = append(errors, stageErrors...)
+			failedStage = i
+		}
+	}
+
+	// Whatever was published is recorded for a rollback or a resume, even
+	// when publishing failed
+	if len(errors) > 0 {
+		if run.recordErr != nil {
+			log.Printf("Warning: %v", run.recordErr)
+		}
+		return fmt.Errorf("publishing failed for %d publishers: %v", len(errors), errors)
+	}
+
+	return run.recordErr
+}
+
+// stageRun is the state of a run shared by its stages
+type stageRun struct {
+	results   map[string]error
+	completed map[string]bool
+	recordErr error // first failure to record a publish
+}
+
+// publishStage runs the publishers of a stage concurrently, each once its
+// dependencies have finished. A publisher whose dependency failed is skipped.
+func (dc *DistributionCoordinator) publishStage(ctx context.Context, releases map[string]Release, publishers map[string]Publisher, stage PublishStage, run *stageRun) []error {
+	concurrentLimit := dc.config.ConcurrentLimit
+	if concurrentLimit <= 0 {
+		concurrentLimit = len(stage.Publishers)
+	}
+
+	done := make(map[string]chan struct{}, len(stage.Publishers))
+	for _, name := range stage.Publishers {
+		done[name] = make(chan struct{})
+	}
+
+	semaphore := make(chan struct{}, concurrentLimit)
+	var mu sync.Mutex
+	var errors []error
+	var wg sync.WaitGroup
+
+	for _, name := range stage.Publishers {
+		if run.completed[name] {
+			log.Printf("Publisher %s already published the release, skipping", name)
+			mu.Lock()
+			run.results[name] = nil
+			mu.Unlock()
+			close(done[name])
+			continue
+		}
+
+		wg.Add(1)
+		go func(pub Publisher) {
+			defer wg.Done()
+			defer close(done[pub.GetName()])
+
+			// Dependencies in earlier stages have already finished
+			for _, dep := range stage.DependsOn[pub.GetName()] {
+				if finished, exists := done[dep]; exists {
+					<-finished
+				}
+			}
+
+			release := releases[pub.GetName()]
+			mu.Lock()
+			err := dependencyError(pub.GetName(), stage.DependsOn[pub.GetName()], run.results)
+			mu.Unlock()
+
+			published := err == nil
+			if published {
+				semaphore <- struct{}{}
+				if publishErr := dc.publishWithRetry(ctx, pub, release); publishErr != nil {
+					err = fmt.Errorf("publisher %s failed: %w", pub.GetName(), publishErr)
+				}
+				<-semaphore
+			}
+
+			mu.Lock()
+			if published {
+				if publish, ok := dc.publishRecord(pub, release, err); ok {
+					if recordErr := dc.savePublish(publish); recordErr != nil && run.recordErr == nil {
+						run.recordErr = recordErr
+					}
+				}
+			}
+			run.results[pub.GetName()] = err
+			if err != nil {
+				errors = append(errors, err)
+			}
+			mu.Unlock()
+			dc.notifyResult(pub.GetName(), release, err)
+		}(publishers[name])
+	}
+
+	wg.Wait()
+	return errors
+}
+
+// dependencyError returns why a publisher is skipped, if a dependency failed
+func dependencyError(name string, dependencies []string, results map[string]error) error {
+	for _, dep := range dependencies {
+		if results[dep] != nil {
+			return fmt.Errorf("publisher %s skipped: dependency %s failed", name, dep)
+		}
+	}
+	return nil
+}
+
+// notifyResult notifies the outcome of a publisher
+func (dc *DistributionCoordinator) notifyResult(publisher string, release Release, err error) {
+	if dc.notifier == nil {
+		return
+	}
+	if err != nil {
+		dc.notifier.NotifyFailure(publisher, release, err)
+	} else {
+		dc.notifier.NotifySuccess(publisher, release)
+	}
+}
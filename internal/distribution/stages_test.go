@@ -0,0 +1,186 @@
+package distribution
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// publishLog records the order publishers start and finish in
+type publishLog struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (l *publishLog) add(event string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func (l *publishLog) index(event string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, e := range l.events {
+		if e == event {
+			return i
+		}
+	}
+	return -1
+}
+
+// stagePublisher records its run in a publish log, taking delay to publish
+type stagePublisher struct {
+	name  string
+	delay time.Duration
+	err   error
+	log   *publishLog
+}
+
+func (p *stagePublisher) Publish(ctx context.Context, release Release) error {
+	p.log.add("start " + p.name)
+	time.Sleep(p.delay)
+	p.log.add("end " + p.name)
+	return p.err
+}
+
+func (p *stagePublisher) Validate(ctx context.Context, release Release) error { return nil }
+func (p *stagePublisher) GetStatus() PublishStatus                            { return PublishStatus{} }
+func (p *stagePublisher) GetName() string                                     { return p.name }
+
+func stageCoordinator(t *testing.T, publishers map[string]PublisherConfig, log *publishLog, failing ...string) *DistributionCoordinator {
+	coordinator := NewDistributionCoordinator(&DistributionConfig{Publishers: publishers})
+	for name := range publishers {
+		publisher := &stagePublisher{name: name, delay: 20 * time.Millisecond, log: log}
+		for _, failed := range failing {
+			if failed == name {
+				publisher.err = errors.New("upstream refused the release")
+			}
+		}
+		require.NoError(t, coordinator.RegisterPublisher(publisher))
+	}
+	return coordinator
+}
+
+func TestDistributionCoordinator_Plan(t *testing.T) {
+	coordinator := stageCoordinator(t, map[string]PublisherConfig{
+		"github":   {Enabled: true, Priority: 1},
+		"gomodule": {Enabled: true, Priority: 2},
+		"homebrew": {Enabled: true, Priority: 3, DependsOn: []string{"github"}},
+		"scoop":    {Enabled: true, Priority: 4, DependsOn: []string{"github", "winget"}},
+		"winget":   {Enabled: false},
+		"aur":      {Enabled: true, Priority: 7, Stage: 2, DependsOn: []string{"github"}},
+		"nix":      {Enabled: true, Priority: 7, Stage: 2},
+	}, &publishLog{})
+
+	stages, err := coordinator.Plan()
+	require.NoError(t, err)
+	assert.Equal(t, []PublishStage{
+		{
+			Number:     0,
+			Publishers: []string{"github", "gomodule", "homebrew", "scoop"},
+			DependsOn:  map[string][]string{"homebrew": {"github"}, "scoop": {"github"}},
+		},
+		{
+			Number:     2,
+			Publishers: []string{"aur", "nix"},
+			DependsOn:  map[string][]string{"aur": {"github"}},
+		},
+	}, stages)
+}
+
+func TestDistributionCoordinator_PlanErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		publishers map[string]PublisherConfig
+		err        string
+	}{
+		{
+			name:       "self",
+			publishers: map[string]PublisherConfig{"github": {Enabled: true, DependsOn: []string{"github"}}},
+			err:        "publisher github depends on itself",
+		},
+		{
+			name:       "unknown",
+			publishers: map[string]PublisherConfig{"homebrew": {Enabled: true, DependsOn: []string{"gihub"}}},
+			err:        "publisher homebrew depends on unknown publisher gihub",
+		},
+		{
+			name: "later stage",
+			publishers: map[string]PublisherConfig{
+				"github":   {Enabled: true, Stage: 2},
+				"homebrew": {Enabled: true, Stage: 1, DependsOn: []string{"github"}},
+			},
+			err: "publisher homebrew of stage 1 depends on github of later stage 2",
+		},
+		{
+			name: "cycle",
+			publishers: map[string]PublisherConfig{
+				"github":   {Enabled: true, DependsOn: []string{"scoop"}},
+				"homebrew": {Enabled: true, DependsOn: []string{"github"}},
+				"scoop":    {Enabled: true, DependsOn: []string{"homebrew"}},
+			},
+			err: "publisher dependency cycle: github -> scoop -> homebrew -> github",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := stageCoordinator(t, tt.publishers, &publishLog{}).Plan()
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestDistribute_Dependencies(t *testing.T) {
+	log := &publishLog{}
+	coordinator := stageCoordinator(t, map[string]PublisherConfig{
+		"github":   {Enabled: true, Priority: 1},
+		"gomodule": {Enabled: true, Priority: 2},
+		"homebrew": {Enabled: true, Priority: 3, DependsOn: []string{"github"}},
+		"aur":      {Enabled: true, Priority: 7, Stage: 1, DependsOn: []string{"homebrew"}},
+	}, log)
+	notifier := &MockNotificationService{}
+	notifier.On("NotifySuccess", "github", Release{Version: "v1.2.3"}).Return(nil)
+	notifier.On("NotifySuccess", "gomodule", Release{Version: "v1.2.3"}).Return(nil)
+	notifier.On("NotifySuccess", "homebrew", Release{Version: "v1.2.3"}).Return(nil)
+	notifier.On("NotifySuccess", "aur", Release{Version: "v1.2.3"}).Return(nil)
+	coordinator.SetNotificationService(notifier)
+
+	require.NoError(t, coordinator.Distribute(context.Background(), Release{Version: "v1.2.3"}))
+
+	// Homebrew waits for GitHub while the Go module publishes alongside it
+	assert.Less(t, log.index("end github"), log.index("start homebrew"))
+	assert.Less(t, log.index("start gomodule"), log.index("end github"))
+	// The next stage starts once the whole stage has finished
+	assert.Less(t, log.index("end gomodule"), log.index("start aur"))
+	assert.Less(t, log.index("end homebrew"), log.index("start aur"))
+	notifier.AssertExpectations(t)
+}
+
+func TestDistribute_FailedDependency(t *testing.T) {
+	log := &publishLog{}
+	coordinator := stageCoordinator(t, map[string]PublisherConfig{
+		"github":   {Enabled: true, Priority: 1},
+		"gomodule": {Enabled: true, Priority: 2},
+		"homebrew": {Enabled: true, Priority: 3, DependsOn: []string{"github"}},
+		"aur":      {Enabled: true, Priority: 7, Stage: 1},
+	}, log, "github")
+
+	err := coordinator.Distribute(context.Background(), Release{Version: "v1.2.3"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "publishing failed for 3 publishers")
+	assert.Contains(t, err.Error(), "publisher github failed")
+	assert.Contains(t, err.Error(), "publisher homebrew skipped: dependency github failed")
+	assert.Contains(t, err.Error(), "publisher aur skipped: stage 0 failed")
+
+	// Publishers that do not depend on the failure still run
+	assert.NotEqual(t, -1, log.index("end gomodule"))
+	assert.Equal(t, -1, log.index("start homebrew"))
+	assert.Equal(t, -1, log.index("start aur"))
+}
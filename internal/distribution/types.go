@@ -2,6 +2,8 @@ package distribution
 
 import (
 	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/update"
 )
 
 // Release represents a software release across all platforms
@@ -13,6 +15,19 @@ type Release struct {
 	Changelog    string            `json:"changelog"`
 	ReleaseNotes string            `json:"release_notes"`
 	Metadata     ReleaseMetadata   `json:"metadata"`
+	// ChecksumFiles are the written checksum files, by name, Signatures
+	// the signature files of each signed file and SBOMs the software bills
+	// of materials of each binary, by name
+	ChecksumFiles map[string]string   `json:"checksum_files"`
+	Signatures    map[string][]string `json:"signatures"`
+	SBOMs         map[string][]string `json:"sboms"`
+	// Packages are the deb and rpm packages of the Linux binaries, by name
+	Packages map[string]Binary `json:"packages"`
+	// Highlights are the main changes of the release, for its notes
+	Highlights []string `json:"highlights"`
+	// Notes are the release notes rendered for the publisher shipping the
+	// release, when release notes templating applies to it
+	Notes string `json:"notes"`
 }
 
 // Binary represents a platform-specific executable
@@ -33,6 +48,8 @@ type ReleaseMetadata struct {
 	CommitSHA    string            `json:"commit_sha"`
 	BuildNumber  string            `json:"build_number"`
 	IsPrerelease bool              `json:"is_prerelease"`
+	// Channel is the release channel, by default the one of the version
+	Channel      update.Channel    `json:"channel,omitempty"`
 	Tags         []string          `json:"tags"`
 	Assets       map[string]string `json:"assets"`
 }
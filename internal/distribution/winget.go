@@ -0,0 +1,567 @@
+package distribution
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/update"
+	"gopkg.in/yaml.v3"
+)
+
+// WingetManifestVersion is the schema version of the manifests written
+const WingetManifestVersion = "1.6.0"
+
+// WingetPublisher submits the manifests of a release to the
+// microsoft/winget-pkgs repository with a pull request from a fork, the way
+// wingetcreate submits them
+type WingetPublisher struct {
+	config WingetConfig
+	client *http.Client
+	status PublishStatus
+	// pullRequest is the URL of the last pull request opened
+	pullRequest string
+	// forkPoll is how long to wait between checks that a new fork is ready
+	forkPoll time.Duration
+}
+
+// WingetConfig contains winget publishing configuration
+type WingetConfig struct {
+	PackageIdentifier string   `json:"package_identifier"` // "Publisher.Package", e.g. "NetTraceX.NetTraceX"
+	Publisher         string   `json:"publisher"`
+	PublisherURL      string   `json:"publisher_url"`
+	PackageName       string   `json:"package_name"`
+	Command           string   `json:"command"` // command the portable executable is installed as
+	License           string   `json:"license"`
+	LicenseURL        string   `json:"license_url"`
+	ShortDescription  string   `json:"short_description"`
+	Description       string   `json:"description"`
+	Homepage          string   `json:"homepage"`
+	Moniker           string   `json:"moniker"`
+	Tags              []string `json:"tags"`
+	Repo              string   `json:"repo"`   // "owner/repo" submissions go to, "microsoft/winget-pkgs"
+	Branch            string   `json:"branch"` // branch of the repository pull requests target
+	GitHubToken       string   `json:"github_token"`
+	BaseURL           string   `json:"base_url"`
+	ReleaseRepo       string   `json:"release_repo"`
+}
+
+// WingetPackage is what is submitted to winget-pkgs: the version, installer
+// and default locale manifests of a package version
+type WingetPackage struct {
+	Version   WingetVersionManifest
+	Installer WingetInstallerManifest
+	Locale    WingetLocaleManifest
+}
+
+// WingetVersionManifest is the version manifest of a package version
+type WingetVersionManifest struct {
+	PackageIdentifier string `yaml:"PackageIdentifier"`
+	PackageVersion    string `yaml:"PackageVersion"`
+	DefaultLocale     string `yaml:"DefaultLocale"`
+	ManifestType      string `yaml:"ManifestType"`
+	ManifestVersion   string `yaml:"ManifestVersion"`
+}
+
+// WingetInstallerManifest is the installer manifest of a package version
+type WingetInstallerManifest struct {
+	PackageIdentifier string            `yaml:"PackageIdentifier"`
+	PackageVersion    string            `yaml:"PackageVersion"`
+	ReleaseDate       string            `yaml:"ReleaseDate,omitempty"`
+	Installers        []WingetInstaller `yaml:"Installers"`
+	ManifestType      string            `yaml:"ManifestType"`
+	ManifestVersion   string            `yaml:"ManifestVersion"`
+}
+
+// WingetInstaller is the download of one architecture. A bare executable
+// installs as a portable command; an archive holds one as a nested
+// installer.
+type WingetInstaller struct {
+	Architecture         string             `yaml:"Architecture"`
+	InstallerType        string             `yaml:"InstallerType"`
+	NestedInstallerType  string             `yaml:"NestedInstallerType,omitempty"`
+	NestedInstallerFiles []WingetNestedFile `yaml:"NestedInstallerFiles,omitempty"`
+	InstallerURL         string             `yaml:"InstallerUrl"`
+	InstallerSha256      string             `yaml:"InstallerSha256"`
+	Commands             []string           `yaml:"Commands,omitempty"`
+}
+
+// WingetNestedFile is an executable inside an archive
+type WingetNestedFile struct {
+	RelativeFilePath     string `yaml:"RelativeFilePath"`
+	PortableCommandAlias string `yaml:"PortableCommandAlias,omitempty"`
+}
+
+// WingetLocaleManifest is the default locale manifest of a package version
+type WingetLocaleManifest struct {
+	PackageIdentifier string   `yaml:"PackageIdentifier"`
+	PackageVersion    string   `yaml:"PackageVersion"`
+	PackageLocale     string   `yaml:"PackageLocale"`
+	Publisher         string   `yaml:"Publisher"`
+	PublisherURL      string   `yaml:"PublisherUrl,omitempty"`
+	PackageName       string   `yaml:"PackageName"`
+	PackageURL        string   `yaml:"PackageUrl,omitempty"`
+	License           string   `yaml:"License"`
+	LicenseURL        string   `yaml:"LicenseUrl,omitempty"`
+	ShortDescription  string   `yaml:"ShortDescription"`
+	Description       string   `yaml:"Description,omitempty"`
+	Moniker           string   `yaml:"Moniker,omitempty"`
+	Tags              []string `yaml:"Tags,omitempty"`
+	ReleaseNotesURL   string   `yaml:"ReleaseNotesUrl,omitempty"`
+	ManifestType      string   `yaml:"ManifestType"`
+	ManifestVersion   string   `yaml:"ManifestVersion"`
+}
+
+// wingetArchitectures maps Go architectures of Windows binaries to winget's
+// names
+var wingetArchitectures = map[string]string{
+	"amd64": "x64",
+	"386":   "x86",
+	"arm64": "arm64",
+}
+
+// wingetIdentifier matches package identifiers winget-pkgs accepts: two to
+// eight dot-separated parts, the first naming the publisher
+var wingetIdentifier = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}(\.[A-Za-z0-9_-]{1,32}){1,7}$`)
+
+// wingetLocale is the locale the package is described in
+const wingetLocale = "en-US"
+
+// NewWingetPublisher creates a new winget publisher
+func NewWingetPublisher(config WingetConfig) *WingetPublisher {
+	if config.PackageIdentifier == "" {
+		config.PackageIdentifier = "NetTraceX.NetTraceX"
+	}
+	if config.Publisher == "" {
+		config.Publisher = "NetTraceX"
+	}
+	if config.PackageName == "" {
+		config.PackageName = "NetTraceX"
+	}
+	if config.Command == "" {
+		config.Command = "nettracex"
+	}
+	if config.License == "" {
+		config.License = "MIT"
+	}
+	if config.Moniker == "" {
+		config.Moniker = config.Command
+	}
+	if config.Repo == "" {
+		config.Repo = "microsoft/winget-pkgs"
+	}
+	if config.Branch == "" {
+		config.Branch = "master"
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = "https://api.github.com"
+	}
+
+	return &WingetPublisher{
+		config:   config,
+		client:   &http.Client{Timeout: 5 * time.Minute},
+		forkPoll: 5 * time.Second,
+		status: PublishStatus{
+			Name:   "winget",
+			Status: StatusIdle,
+		},
+	}
+}
+
+// GetName returns the publisher name
+func (p *WingetPublisher) GetName() string {
+	return "winget"
+}
+
+// DefaultChannels ships stable releases only; winget has no prereleases, so
+// every user would be upgraded to one
+func (p *WingetPublisher) DefaultChannels() []update.Channel {
+	return []update.Channel{update.Stable}
+}
+
+// Publish submits a release to winget-pkgs
+func (p *WingetPublisher) Publish(ctx context.Context, release Release) error {
+	p.updateStatus(StatusPublishing, "")
+
+	pkg, err := p.GeneratePackage(release)
+	if err != nil {
+		p.updateStatus(StatusError, err.Error())
+		return fmt.Errorf("failed to generate package: %w", err)
+	}
+
+	if err := p.submitPackage(ctx, pkg); err != nil {
+		p.updateStatus(StatusError, err.Error())
+		return fmt.Errorf("failed to submit package: %w", err)
+	}
+
+	p.updateStatus(StatusSuccess, "")
+	return nil
+}
+
+// DryRun renders the manifests and checks the token
+func (p *WingetPublisher) DryRun(ctx context.Context, release Release) (*DryRunReport, error) {
+	report := &DryRunReport{}
+	if err := p.Validate(ctx, release); err != nil {
+		return report, err
+	}
+
+	pkg, err := p.GeneratePackage(release)
+	if err != nil {
+		return report, fmt.Errorf("failed to generate package: %w", err)
+	}
+	report.Files, err = p.Files(pkg)
+	if err != nil {
+		return report, err
+	}
+
+	repo := githubContents{baseURL: p.config.BaseURL, token: p.config.GitHubToken, client: p.client}
+	login, err := repo.user(ctx)
+	if err != nil {
+		return report, fmt.Errorf("credential check failed: %w", err)
+	}
+
+	branch := p.branchName(pkg)
+	report.Actions = append(report.Actions,
+		fmt.Sprintf("fork %s to %s and sync its %s branch", p.config.Repo, login, p.config.Branch),
+		fmt.Sprintf("create branch %s of the fork from %s", branch, p.config.Branch),
+		fmt.Sprintf("commit %s to %s", strings.Join(report.FileNames(), ", "), branch),
+		fmt.Sprintf("open a pull request of %s:%s into %s of %s", login, branch, p.config.Branch, p.config.Repo))
+	return report, nil
+}
+
+// Validate validates a release for winget publishing
+func (p *WingetPublisher) Validate(ctx context.Context, release Release) error {
+	if release.Version == "" {
+		return fmt.Errorf("release version is required")
+	}
+	if !wingetIdentifier.MatchString(p.config.PackageIdentifier) {
+		return fmt.Errorf("invalid package identifier %q", p.config.PackageIdentifier)
+	}
+
+	found := false
+	for filename, binary := range release.Binaries {
+		if binary.Platform != "windows" {
+			continue
+		}
+		if _, supported := wingetArchitectures[binary.Architecture]; !supported {
+			continue
+		}
+		if downloadURL(p.config.ReleaseRepo, release, binary) == "" {
+			return fmt.Errorf("binary %s missing download URL", filename)
+		}
+		found = true
+	}
+
+	if !found {
+		return fmt.Errorf("no supported binary found in release (Windows amd64, 386 or arm64 required)")
+	}
+
+	return nil
+}
+
+// GetStatus returns the current status of the winget publisher
+func (p *WingetPublisher) GetStatus() PublishStatus {
+	p.status.Metadata = map[string]string{
+		"repo":               p.config.Repo,
+		"package_identifier": p.config.PackageIdentifier,
+	}
+	if p.pullRequest != "" {
+		p.status.Metadata["pull_request"] = p.pullRequest
+	}
+	return p.status
+}
+
+// updateStatus updates the publisher status
+func (p *WingetPublisher) updateStatus(status StatusType, lastError string) {
+	p.status.Status = status
+	p.status.LastError = lastError
+	if status == StatusSuccess {
+		p.status.LastPublish = time.Now()
+		p.status.PublishCount++
+	} else if status == StatusError {
+		p.status.ErrorCount++
+	}
+}
+
+// GeneratePackage creates the manifests of the Windows binaries of a
+// release, with the version and hashes of its downloads
+func (p *WingetPublisher) GeneratePackage(release Release) (*WingetPackage, error) {
+	version := strings.TrimPrefix(release.Version, "v")
+	id := p.config.PackageIdentifier
+
+	var installers []WingetInstaller
+	for _, binary := range release.Binaries {
+		if binary.Platform != "windows" {
+			continue
+		}
+		arch, supported := wingetArchitectures[binary.Architecture]
+		if !supported {
+			continue
+		}
+		url := downloadURL(p.config.ReleaseRepo, release, binary)
+
+		// Calculate SHA256 if not provided
+		checksum := binary.Checksum
+		if checksum == "" {
+			sum, err := downloadSHA256(p.client, url)
+			if err != nil {
+				return nil, fmt.Errorf("failed to calculate SHA256 for %s: %w", binary.Filename, err)
+			}
+			checksum = sum
+		}
+
+		installer := WingetInstaller{
+			Architecture:    arch,
+			InstallerURL:    url,
+			InstallerSha256: strings.ToUpper(checksum),
+		}
+		if strings.HasSuffix(strings.ToLower(binary.Filename), ".zip") {
+			// The archive holds the executable at its top level
+			installer.InstallerType = "zip"
+			installer.NestedInstallerType = "portable"
+			installer.NestedInstallerFiles = []WingetNestedFile{{
+				RelativeFilePath:     p.config.Command + ".exe",
+				PortableCommandAlias: p.config.Command,
+			}}
+		} else {
+			installer.InstallerType = "portable"
+			installer.Commands = []string{p.config.Command}
+		}
+		installers = append(installers, installer)
+	}
+
+	if len(installers) == 0 {
+		return nil, fmt.Errorf("no supported binaries found (Windows amd64, 386 or arm64 required)")
+	}
+	sort.Slice(installers, func(i, j int) bool { return installers[i].Architecture < installers[j].Architecture })
+
+	pkg := &WingetPackage{
+		Version: WingetVersionManifest{
+			PackageIdentifier: id,
+			PackageVersion:    version,
+			DefaultLocale:     wingetLocale,
+			ManifestType:      "version",
+			ManifestVersion:   WingetManifestVersion,
+		},
+		Installer: WingetInstallerManifest{
+			PackageIdentifier: id,
+			PackageVersion:    version,
+			Installers:        installers,
+			ManifestType:      "installer",
+			ManifestVersion:   WingetManifestVersion,
+		},
+		Locale: WingetLocaleManifest{
+			PackageIdentifier: id,
+			PackageVersion:    version,
+			PackageLocale:     wingetLocale,
+			Publisher:         p.config.Publisher,
+			PublisherURL:      p.config.PublisherURL,
+			PackageName:       p.config.PackageName,
+			PackageURL:        p.config.Homepage,
+			License:           p.config.License,
+			LicenseURL:        p.config.LicenseURL,
+			ShortDescription:  p.config.ShortDescription,
+			Description:       p.config.Description,
+			Moniker:           p.config.Moniker,
+			Tags:              p.config.Tags,
+			ManifestType:      "defaultLocale",
+			ManifestVersion:   WingetManifestVersion,
+		},
+	}
+	if date := release.Metadata.CreatedAt; !date.IsZero() {
+		pkg.Installer.ReleaseDate = date.UTC().Format("2006-01-02")
+	}
+	if p.config.ReleaseRepo != "" && release.Tag != "" {
+		pkg.Locale.ReleaseNotesURL = fmt.Sprintf("https://github.com/%s/releases/tag/%s", p.config.ReleaseRepo, release.Tag)
+	}
+
+	if err := ValidateWingetPackage(pkg); err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+// ValidateWingetPackage validates a winget package against the rules of
+// the winget-pkgs manifest schema
+func ValidateWingetPackage(pkg *WingetPackage) error {
+	if !wingetIdentifier.MatchString(pkg.Version.PackageIdentifier) {
+		return fmt.Errorf("invalid package identifier %q", pkg.Version.PackageIdentifier)
+	}
+	if pkg.Version.PackageVersion == "" {
+		return fmt.Errorf("package version is required")
+	}
+
+	locale := pkg.Locale
+	if locale.Publisher == "" || locale.PackageName == "" || locale.License == "" {
+		return fmt.Errorf("publisher, package name and license are required")
+	}
+	if locale.ShortDescription == "" {
+		return fmt.Errorf("short description is required")
+	}
+	if len(locale.ShortDescription) > 256 {
+		return fmt.Errorf("short description is longer than 256 characters")
+	}
+
+	for _, installer := range pkg.Installer.Installers {
+		if !strings.HasPrefix(installer.InstallerURL, "https://") {
+			return fmt.Errorf("%s installer URL %q is not HTTPS", installer.Architecture, installer.InstallerURL)
+		}
+		if sum, err := hex.DecodeString(installer.InstallerSha256); err != nil || len(sum) != 32 {
+			return fmt.Errorf("invalid %s SHA256 hash %q", installer.Architecture, installer.InstallerSha256)
+		}
+	}
+	return nil
+}
+
+// renderWingetManifest renders a manifest as YAML after the schema comment
+// editors and wingetcreate put at the top
+func renderWingetManifest(schema string, manifest interface{}) (string, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# yaml-language-server: $schema=https://aka.ms/winget-manifest.%s.%s.schema.json\n\n", schema, WingetManifestVersion)
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(manifest); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ManifestDir returns the directory of a package version in winget-pkgs:
+// manifests/<first letter>/<identifier parts>/<version>
+func (pkg *WingetPackage) ManifestDir() string {
+	id := pkg.Version.PackageIdentifier
+	parts := append([]string{"manifests", strings.ToLower(id[:1])}, strings.Split(id, ".")...)
+	return path.Join(append(parts, pkg.Version.PackageVersion)...)
+}
+
+// Files renders the manifests of a package, by path in winget-pkgs
+func (p *WingetPublisher) Files(pkg *WingetPackage) (map[string]string, error) {
+	dir := pkg.ManifestDir()
+	id := pkg.Version.PackageIdentifier
+
+	manifests := []struct {
+		name     string
+		schema   string
+		manifest interface{}
+	}{
+		{id + ".yaml", "version", pkg.Version},
+		{id + ".installer.yaml", "installer", pkg.Installer},
+		{id + ".locale." + wingetLocale + ".yaml", "defaultLocale", pkg.Locale},
+	}
+
+	files := make(map[string]string, len(manifests))
+	for _, m := range manifests {
+		content, err := renderWingetManifest(m.schema, m.manifest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s manifest: %w", m.schema, err)
+		}
+		files[path.Join(dir, m.name)] = content
+	}
+	return files, nil
+}
+
+// branchName returns the branch of the fork a package version is
+// submitted from
+func (p *WingetPublisher) branchName(pkg *WingetPackage) string {
+	return pkg.Version.PackageIdentifier + "-" + pkg.Version.PackageVersion
+}
+
+// submitPackage forks winget-pkgs, commits the manifests to a branch of the
+// fork and opens a pull request of it upstream
+func (p *WingetPublisher) submitPackage(ctx context.Context, pkg *WingetPackage) error {
+	files, err := p.Files(pkg)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	repo := githubContents{baseURL: p.config.BaseURL, token: p.config.GitHubToken, client: p.client}
+	id, version := pkg.Version.PackageIdentifier, pkg.Version.PackageVersion
+
+	// winget-pkgs refuses changes to submitted versions, and names the pull
+	// request after whether the package is new
+	dir := pkg.ManifestDir()
+	submitted, err := repo.exists(ctx, p.config.Repo, dir, p.config.Branch)
+	if err != nil {
+		return err
+	}
+	if submitted {
+		return fmt.Errorf("%s %s is already in %s", id, version, p.config.Repo)
+	}
+	known, err := repo.exists(ctx, p.config.Repo, path.Dir(dir), p.config.Branch)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("New version: %s version %s", id, version)
+	if !known {
+		title = fmt.Sprintf("New package: %s version %s", id, version)
+	}
+
+	fork, err := repo.fork(ctx, p.config.Repo)
+	if err != nil {
+		return fmt.Errorf("failed to fork %s: %w", p.config.Repo, err)
+	}
+	if err := p.waitForFork(ctx, repo, fork); err != nil {
+		return fmt.Errorf("failed to sync fork %s: %w", fork, err)
+	}
+
+	branch := p.branchName(pkg)
+	if err := repo.createBranch(ctx, fork, branch, p.config.Branch); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w", branch, err)
+	}
+	for _, name := range names {
+		if err := repo.putFile(ctx, fork, name, branch, title, files[name]); err != nil {
+			return err
+		}
+	}
+
+	owner := fork[:strings.Index(fork, "/")]
+	body := fmt.Sprintf("Adds the manifests of %s %s (schema %s) for architectures %s.", id, version, WingetManifestVersion, strings.Join(pkg.architectures(), ", "))
+	url, err := repo.createPullRequest(ctx, p.config.Repo, owner+":"+branch, p.config.Branch, title, body)
+	if err != nil {
+		return fmt.Errorf("failed to open pull request: %w", err)
+	}
+	p.pullRequest = url
+	return nil
+}
+
+// waitForFork syncs the fork with upstream, waiting for GitHub to finish
+// creating a new fork
+func (p *WingetPublisher) waitForFork(ctx context.Context, repo githubContents, fork string) error {
+	var err error
+	for attempt := 0; attempt < 10; attempt++ {
+		if err = repo.syncFork(ctx, fork, p.config.Branch); err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(p.forkPoll):
+		}
+	}
+	return err
+}
+
+// architectures returns the architectures of the installers of a package
+func (pkg *WingetPackage) architectures() []string {
+	arches := make([]string, len(pkg.Installer.Installers))
+	for i, installer := range pkg.Installer.Installers {
+		arches[i] = installer.Architecture
+	}
+	return arches
+}
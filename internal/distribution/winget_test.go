@@ -0,0 +1,254 @@
+package distribution
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/update"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func wingetRelease() Release {
+	return createdAt(testRelease("v1.2.3", windowsPlatforms...))
+}
+
+func wingetConfig(baseURL string) WingetConfig {
+	return WingetConfig{
+		ShortDescription: testDescription,
+		Homepage:         testHomepage,
+		Tags:             []string{"network", "traceroute"},
+		GitHubToken:      "secret",
+		BaseURL:          baseURL,
+		ReleaseRepo:      testReleaseRepo,
+	}
+}
+
+func TestWingetPublisher_GeneratePackage(t *testing.T) {
+	publisher := NewWingetPublisher(wingetConfig(""))
+	require.NoError(t, publisher.Validate(context.Background(), wingetRelease()))
+
+	pkg, err := publisher.GeneratePackage(wingetRelease())
+	require.NoError(t, err)
+	assert.Equal(t, "manifests/n/NetTraceX/NetTraceX/1.2.3", pkg.ManifestDir())
+	assert.Equal(t, []WingetInstaller{
+		{
+			Architecture:    "arm64",
+			InstallerType:   "portable",
+			InstallerURL:    "https://github.com/nettracex/nettracex-tui/releases/download/v1.2.3/nettracex-windows-arm64.exe",
+			InstallerSha256: strings.Repeat("B", 64),
+			Commands:        []string{"nettracex"},
+		},
+		{
+			Architecture:         "x64",
+			InstallerType:        "zip",
+			NestedInstallerType:  "portable",
+			NestedInstallerFiles: []WingetNestedFile{{RelativeFilePath: "nettracex.exe", PortableCommandAlias: "nettracex"}},
+			InstallerURL:         "https://downloads.example.com/nettracex_1.2.3_Windows_x86_64.zip",
+			InstallerSha256:      strings.Repeat("A", 64),
+		},
+	}, pkg.Installer.Installers)
+
+	files, err := publisher.Files(pkg)
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+
+	version := files["manifests/n/NetTraceX/NetTraceX/1.2.3/NetTraceX.NetTraceX.yaml"]
+	assert.Equal(t, `# yaml-language-server: $schema=https://aka.ms/winget-manifest.version.1.6.0.schema.json
+
+PackageIdentifier: NetTraceX.NetTraceX
+PackageVersion: 1.2.3
+DefaultLocale: en-US
+ManifestType: version
+ManifestVersion: 1.6.0
+`, version)
+
+	installer := files["manifests/n/NetTraceX/NetTraceX/1.2.3/NetTraceX.NetTraceX.installer.yaml"]
+	assert.True(t, strings.HasPrefix(installer, "# yaml-language-server: $schema=https://aka.ms/winget-manifest.installer.1.6.0.schema.json\n"))
+	assert.Contains(t, installer, "ReleaseDate: \"2024-03-01\"")
+	assert.Contains(t, installer, "    NestedInstallerFiles:\n      - RelativeFilePath: nettracex.exe\n        PortableCommandAlias: nettracex\n")
+
+	var locale map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(files["manifests/n/NetTraceX/NetTraceX/1.2.3/NetTraceX.NetTraceX.locale.en-US.yaml"]), &locale))
+	assert.Equal(t, "NetTraceX", locale["Publisher"])
+	assert.Equal(t, "MIT", locale["License"])
+	assert.Equal(t, "nettracex", locale["Moniker"])
+	assert.Equal(t, []interface{}{"network", "traceroute"}, locale["Tags"])
+	assert.Equal(t, "https://github.com/nettracex/nettracex-tui/releases/tag/v1.2.3", locale["ReleaseNotesUrl"])
+	assert.Equal(t, "defaultLocale", locale["ManifestType"])
+
+	// A release without Windows binaries has nothing to submit
+	release := wingetRelease()
+	delete(release.Binaries, "nettracex_1.2.3_Windows_x86_64.zip")
+	delete(release.Binaries, "nettracex-windows-arm64.exe")
+	assert.Error(t, publisher.Validate(context.Background(), release))
+	_, err = publisher.GeneratePackage(release)
+	assert.Error(t, err)
+}
+
+func TestValidateWingetPackage(t *testing.T) {
+	pkg, err := NewWingetPublisher(wingetConfig("")).GeneratePackage(wingetRelease())
+	require.NoError(t, err)
+
+	pkg.Version.PackageIdentifier = "nettracex"
+	assert.ErrorContains(t, ValidateWingetPackage(pkg), "package identifier")
+
+	pkg.Version.PackageIdentifier = "NetTraceX.NetTraceX"
+	pkg.Installer.Installers[0].InstallerURL = "http://downloads.example.com/nettracex.exe"
+	assert.ErrorContains(t, ValidateWingetPackage(pkg), "not HTTPS")
+
+	config := wingetConfig("")
+	config.ShortDescription = ""
+	_, err = NewWingetPublisher(config).GeneratePackage(wingetRelease())
+	assert.ErrorContains(t, err, "short description")
+}
+
+// wingetServer fakes the GitHub API of winget-pkgs and the fork of the
+// token's account, recording the requests and the files written
+type wingetServer struct {
+	mu       sync.Mutex
+	requests []string
+	files    map[string]string
+	// submitted are the directories already in winget-pkgs
+	submitted map[string]bool
+	// syncFailures is how often syncing fails while the fork is created
+	syncFailures int
+}
+
+func (s *wingetServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+	if r.Header.Get("Authorization") != "token secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message":"Bad credentials"}`))
+		return
+	}
+
+	const upstream, fork = "/repos/microsoft/winget-pkgs", "/repos/nettracex-bot/winget-pkgs"
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/user":
+		w.Write([]byte(`{"login":"nettracex-bot"}`))
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, upstream+"/contents/"):
+		if !s.submitted[strings.TrimPrefix(r.URL.Path, upstream+"/contents/")] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`[]`))
+	case r.Method == http.MethodPost && r.URL.Path == upstream+"/forks":
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"full_name":"nettracex-bot/winget-pkgs"}`))
+	case r.Method == http.MethodPost && r.URL.Path == fork+"/merge-upstream":
+		if s.syncFailures > 0 {
+			s.syncFailures--
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.Write([]byte(`{"merge_type":"fast-forward"}`))
+	case r.Method == http.MethodGet && r.URL.Path == fork+"/git/ref/heads/master":
+		w.Write([]byte(`{"object":{"sha":"abc123"}}`))
+	case r.Method == http.MethodPost && r.URL.Path == fork+"/git/refs":
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, fork+"/contents/"):
+		w.WriteHeader(http.StatusNotFound)
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, fork+"/contents/"):
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		content, _ := base64.StdEncoding.DecodeString(body["content"])
+		s.files[body["branch"]+":"+strings.TrimPrefix(r.URL.Path, fork+"/contents/")] = string(content)
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPost && r.URL.Path == upstream+"/pulls":
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["head"] != "nettracex-bot:NetTraceX.NetTraceX-1.2.3" || body["base"] != "master" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		s.files["pull:"+body["title"]] = body["body"]
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"html_url":"https://github.com/microsoft/winget-pkgs/pull/12345"}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestWingetPublisher_Publish(t *testing.T) {
+	winget := &wingetServer{
+		files:        make(map[string]string),
+		submitted:    map[string]bool{"manifests/n/NetTraceX/NetTraceX": true},
+		syncFailures: 1,
+	}
+	server := httptest.NewServer(winget)
+	defer server.Close()
+
+	publisher := NewWingetPublisher(wingetConfig(server.URL))
+	publisher.forkPoll = time.Millisecond
+	require.NoError(t, publisher.Publish(context.Background(), wingetRelease()))
+	assert.Equal(t, []string{
+		"GET /repos/microsoft/winget-pkgs/contents/manifests/n/NetTraceX/NetTraceX/1.2.3",
+		"GET /repos/microsoft/winget-pkgs/contents/manifests/n/NetTraceX/NetTraceX",
+		"POST /repos/microsoft/winget-pkgs/forks",
+		"POST /repos/nettracex-bot/winget-pkgs/merge-upstream",
+		"POST /repos/nettracex-bot/winget-pkgs/merge-upstream",
+		"GET /repos/nettracex-bot/winget-pkgs/git/ref/heads/master",
+		"POST /repos/nettracex-bot/winget-pkgs/git/refs",
+		"GET /repos/nettracex-bot/winget-pkgs/contents/manifests/n/NetTraceX/NetTraceX/1.2.3/NetTraceX.NetTraceX.installer.yaml",
+		"PUT /repos/nettracex-bot/winget-pkgs/contents/manifests/n/NetTraceX/NetTraceX/1.2.3/NetTraceX.NetTraceX.installer.yaml",
+		"GET /repos/nettracex-bot/winget-pkgs/contents/manifests/n/NetTraceX/NetTraceX/1.2.3/NetTraceX.NetTraceX.locale.en-US.yaml",
+		"PUT /repos/nettracex-bot/winget-pkgs/contents/manifests/n/NetTraceX/NetTraceX/1.2.3/NetTraceX.NetTraceX.locale.en-US.yaml",
+		"GET /repos/nettracex-bot/winget-pkgs/contents/manifests/n/NetTraceX/NetTraceX/1.2.3/NetTraceX.NetTraceX.yaml",
+		"PUT /repos/nettracex-bot/winget-pkgs/contents/manifests/n/NetTraceX/NetTraceX/1.2.3/NetTraceX.NetTraceX.yaml",
+		"POST /repos/microsoft/winget-pkgs/pulls",
+	}, winget.requests)
+	assert.Contains(t, winget.files["NetTraceX.NetTraceX-1.2.3:manifests/n/NetTraceX/NetTraceX/1.2.3/NetTraceX.NetTraceX.installer.yaml"], "InstallerSha256: "+strings.Repeat("A", 64))
+	assert.Equal(t, "Adds the manifests of NetTraceX.NetTraceX 1.2.3 (schema 1.6.0) for architectures arm64, x64.",
+		winget.files["pull:New version: NetTraceX.NetTraceX version 1.2.3"])
+	status := publisher.GetStatus()
+	assert.Equal(t, StatusSuccess, status.Status)
+	assert.Equal(t, "https://github.com/microsoft/winget-pkgs/pull/12345", status.Metadata["pull_request"])
+
+	// The first version of a package is submitted as a new package
+	winget.submitted = map[string]bool{}
+	require.NoError(t, publisher.Publish(context.Background(), wingetRelease()))
+	assert.Contains(t, winget.files, "pull:New package: NetTraceX.NetTraceX version 1.2.3")
+
+	// A submitted version is not submitted again
+	winget.requests = nil
+	winget.submitted = map[string]bool{"manifests/n/NetTraceX/NetTraceX/1.2.3": true}
+	err := publisher.Publish(context.Background(), wingetRelease())
+	assert.ErrorContains(t, err, "NetTraceX.NetTraceX 1.2.3 is already in microsoft/winget-pkgs")
+	assert.Len(t, winget.requests, 1)
+	assert.Equal(t, StatusError, publisher.GetStatus().Status)
+
+	// Refused credentials are reported by the dry run
+	config := wingetConfig(server.URL)
+	config.GitHubToken = "wrong"
+	_, err = NewWingetPublisher(config).DryRun(context.Background(), wingetRelease())
+	assert.ErrorContains(t, err, "Bad credentials")
+}
+
+func TestWingetPublisher_DryRun(t *testing.T) {
+	server := httptest.NewServer(&wingetServer{files: make(map[string]string)})
+	defer server.Close()
+
+	report, err := NewWingetPublisher(wingetConfig(server.URL)).DryRun(context.Background(), wingetRelease())
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"fork microsoft/winget-pkgs to nettracex-bot and sync its master branch",
+		"create branch NetTraceX.NetTraceX-1.2.3 of the fork from master",
+		"commit manifests/n/NetTraceX/NetTraceX/1.2.3/NetTraceX.NetTraceX.installer.yaml, manifests/n/NetTraceX/NetTraceX/1.2.3/NetTraceX.NetTraceX.locale.en-US.yaml, manifests/n/NetTraceX/NetTraceX/1.2.3/NetTraceX.NetTraceX.yaml to NetTraceX.NetTraceX-1.2.3",
+		"open a pull request of nettracex-bot:NetTraceX.NetTraceX-1.2.3 into master of microsoft/winget-pkgs",
+	}, report.Actions)
+	assert.Len(t, report.Files, 3)
+
+	// Prereleases are not submitted by default
+	assert.Equal(t, []update.Channel{update.Stable}, NewWingetPublisher(wingetConfig("")).DefaultChannels())
+}
@@ -0,0 +1,32 @@
+// Package domain contains context helpers shared across layers
+package domain
+
+import "context"
+
+// dnsServerKey is the context key for a per-request DNS server override
+type dnsServerKey struct{}
+
+// WithDNSServer returns a context that directs DNS lookups to server instead of the configured list
+func WithDNSServer(ctx context.Context, server string) context.Context {
+	return context.WithValue(ctx, dnsServerKey{}, server)
+}
+
+// DNSServerFromContext returns the DNS server override carried by ctx, if any
+func DNSServerFromContext(ctx context.Context) string {
+	server, _ := ctx.Value(dnsServerKey{}).(string)
+	return server
+}
+
+// refreshKey is the context key for bypassing cached responses
+type refreshKey struct{}
+
+// WithRefresh returns a context whose lookups skip cached responses and replace them with fresh ones
+func WithRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, refreshKey{}, true)
+}
+
+// RefreshFromContext reports whether ctx asks for fresh responses
+func RefreshFromContext(ctx context.Context) bool {
+	refresh, _ := ctx.Value(refreshKey{}).(bool)
+	return refresh
+}
@@ -4,6 +4,7 @@ package domain
 import (
 	"context"
 	"net"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -48,6 +49,10 @@ const (
 	ExportFormatJSON ExportFormat = iota
 	ExportFormatCSV
 	ExportFormatText
+	ExportFormatHTML
+	ExportFormatMarkdown
+	ExportFormatPDF
+	ExportFormatJUnit
 )
 
 // NetworkClient abstracts network operations for testing and flexibility
@@ -60,6 +65,47 @@ type NetworkClient interface {
 	SSLCheck(ctx context.Context, host string, port int) (SSLResult, error)
 }
 
+// ConnectivityClient measures transport-level connection establishment to a specific address
+// Kept separate from NetworkClient so existing implementations are not forced to change
+type ConnectivityClient interface {
+	TCPConnect(ctx context.Context, ip net.IP, port int) (time.Duration, error)
+}
+
+// ReverseResolver looks up the names registered for an address (PTR records)
+type ReverseResolver interface {
+	ReverseLookup(ctx context.Context, ip net.IP) ([]string, error)
+}
+
+// ZoneTransferClient requests full zone transfers (AXFR) from a nameserver.
+// It returns the transferred records of supported types and the total number
+// of records in the zone.
+type ZoneTransferClient interface {
+	ZoneTransfer(ctx context.Context, server, zone string) ([]DNSRecord, int, error)
+}
+
+// DNSServerReporter exposes the health of the DNS servers used for lookups
+type DNSServerReporter interface {
+	DNSServerStatus() []DNSServerStatus
+	CheckDNSServers(ctx context.Context) []DNSServerStatus
+}
+
+// PluginReporter exposes the plugins found at startup and their health
+type PluginReporter interface {
+	PluginStatus() []PluginStatus
+	CheckPlugins(ctx context.Context) []PluginStatus
+}
+
+// CacheReporter exposes the response cache used for DNS and WHOIS lookups
+type CacheReporter interface {
+	CacheStats() CacheStats
+	ClearCache() error
+}
+
+// CapabilityReporter exposes the privileged operations available for diagnostics
+type CapabilityReporter interface {
+	Capabilities() Capabilities
+}
+
 // TUIComponent defines reusable UI components
 // Follows Open/Closed Principle - extensible without modification
 type TUIComponent interface {
@@ -79,6 +125,61 @@ type PluginRegistry interface {
 	Unregister(name string) error
 }
 
+// ParameterSpec describes one input of a tool that has no built-in form,
+// such as an external plugin. Values reach the tool as strings.
+type ParameterSpec struct {
+	Key      string `json:"key" mapstructure:"key"`
+	Label    string `json:"label" mapstructure:"label"`
+	Required bool   `json:"required,omitempty" mapstructure:"required"`
+	Default  string `json:"default,omitempty" mapstructure:"default"`
+}
+
+// DescribedTool is implemented by tools that describe their own inputs
+type DescribedTool interface {
+	DiagnosticTool
+	Parameters() []ParameterSpec
+}
+
+// ParameterSpecs returns the inputs tool describes, looking through
+// wrappers such as policy guards that expose the tool they wrap
+func ParameterSpecs(tool DiagnosticTool) ([]ParameterSpec, bool) {
+	for tool != nil {
+		if described, ok := tool.(DescribedTool); ok {
+			return described.Parameters(), true
+		}
+		wrapper, ok := tool.(interface{ Unwrap() DiagnosticTool })
+		if !ok {
+			break
+		}
+		tool = wrapper.Unwrap()
+	}
+	return nil, false
+}
+
+// LiveTool is implemented by tools that show a run on a live screen of
+// their own, such as ping's latency graph, rather than as a final result.
+// Wrappers implement it too when a live run keeps their guarantees, passing
+// the call on to the tool they wrap.
+type LiveTool interface {
+	// LiveModel validates params and returns a screen already running them,
+	// or nil when the run is shown as a final result from Execute
+	LiveModel(ctx context.Context, params Parameters) (tea.Model, error)
+}
+
+// LiveModel returns the live screen of tool for params, or nil when tool,
+// or a wrapper around it that may do more than run it, has none
+func LiveModel(ctx context.Context, tool DiagnosticTool, params Parameters) (tea.Model, error) {
+	live, ok := tool.(LiveTool)
+	if !ok {
+		return nil, nil
+	}
+	return live.LiveModel(ctx, params)
+}
+
+// LiveExitMsg is sent by a live screen when the user leaves it, so the
+// screen that opened it takes over again
+type LiveExitMsg struct{}
+
 // ConfigurationManager handles application configuration
 // Follows Interface Segregation Principle - focused on configuration operations
 type ConfigurationManager interface {
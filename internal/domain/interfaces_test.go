@@ -333,4 +333,30 @@ var (
 	_ NetworkClient  = (*MockNetworkClient)(nil)
 	_ TUIComponent   = (*MockTUIComponent)(nil)
 	_ PluginRegistry = (*MockPluginRegistry)(nil)
-)
\ No newline at end of file
+)
+// describedTool is a DescribedTool for testing ParameterSpecs
+type describedTool struct {
+	MockDiagnosticTool
+}
+
+func (t *describedTool) Parameters() []ParameterSpec {
+	return []ParameterSpec{{Key: "url", Label: "URL", Required: true}}
+}
+
+// wrappedTool wraps a tool the way policy guards do
+type wrappedTool struct {
+	DiagnosticTool
+}
+
+func (w *wrappedTool) Unwrap() DiagnosticTool {
+	return w.DiagnosticTool
+}
+
+func TestParameterSpecs(t *testing.T) {
+	specs, ok := ParameterSpecs(&wrappedTool{&describedTool{}})
+	assert.True(t, ok)
+	assert.Equal(t, "url", specs[0].Key)
+
+	_, ok = ParameterSpecs(&wrappedTool{&MockDiagnosticTool{}})
+	assert.False(t, ok)
+}
@@ -52,6 +52,21 @@ func (p *BaseParameters) ToMap() map[string]interface{} {
 	return result
 }
 
+// TargetParamKeys lists the parameter keys tools use for their target, in
+// lookup order
+var TargetParamKeys = []string{"host", "target", "domain", "query", "cidr", "url"}
+
+// TargetParam returns the value of the first target parameter set in
+// params, or "" when none is
+func TargetParam(params Parameters) string {
+	for _, key := range TargetParamKeys {
+		if value, ok := params.Get(key).(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
 // PingParameters represents parameters for ping operations
 type PingParameters struct {
 	*BaseParameters
@@ -69,6 +84,9 @@ func NewPingParameters(host string, options PingOptions) *PingParameters {
 	params.Set("packet_size", options.PacketSize)
 	params.Set("ttl", options.TTL)
 	params.Set("ipv6", options.IPv6)
+	if options.Mode != "" {
+		params.Set("mode", options.Mode)
+	}
 	return params
 }
 
@@ -88,6 +106,14 @@ func (p *PingParameters) Validate() error {
 	if packetSize != nil && (packetSize.(int) <= 0 || packetSize.(int) > 65507) {
 		return fmt.Errorf("packet_size must be between 1 and 65507")
 	}
+
+	if mode, ok := p.Get("mode").(PingMode); ok {
+		switch mode {
+		case PingModeNormal, PingModeAdaptive, PingModeFlood:
+		default:
+			return fmt.Errorf("mode must be normal, adaptive, or flood")
+		}
+	}
 	
 	return nil
 }
@@ -108,6 +134,12 @@ func NewTracerouteParameters(host string, options TraceOptions) *TracerouteParam
 	params.Set("packet_size", options.PacketSize)
 	params.Set("queries", options.Queries)
 	params.Set("ipv6", options.IPv6)
+	if options.Protocol != "" {
+		params.Set("protocol", options.Protocol)
+	}
+	if options.Port != 0 {
+		params.Set("port", options.Port)
+	}
 	return params
 }
 
@@ -127,6 +159,19 @@ func (p *TracerouteParameters) Validate() error {
 	if queries != nil && queries.(int) <= 0 {
 		return fmt.Errorf("queries must be positive")
 	}
+
+	if protocol, ok := p.Get("protocol").(TraceProtocol); ok {
+		switch protocol {
+		case TraceProtocolICMP, TraceProtocolUDP, TraceProtocolTCP:
+		default:
+			return fmt.Errorf("protocol must be icmp, udp, or tcp")
+		}
+	}
+
+	port := p.Get("port")
+	if port != nil && (port.(int) < 1 || port.(int) > 65535) {
+		return fmt.Errorf("port must be between 1 and 65535")
+	}
 	
 	return nil
 }
@@ -224,4 +269,34 @@ func (p *SSLParameters) Validate() error {
 	}
 	
 	return nil
-}
\ No newline at end of file
+}
+
+// DualStackParameters represents parameters for dual-stack comparison operations
+type DualStackParameters struct {
+	*BaseParameters
+}
+
+// NewDualStackParameters creates new dual-stack comparison parameters
+func NewDualStackParameters(host string, port int) *DualStackParameters {
+	params := &DualStackParameters{
+		BaseParameters: NewParameters(),
+	}
+	params.Set("host", host)
+	params.Set("port", port)
+	return params
+}
+
+// Validate validates dual-stack comparison parameters
+func (p *DualStackParameters) Validate() error {
+	host := p.Get("host")
+	if host == nil || host.(string) == "" {
+		return fmt.Errorf("host parameter is required")
+	}
+	
+	portNum, ok := p.Get("port").(int)
+	if !ok || portNum <= 0 || portNum > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535")
+	}
+	
+	return nil
+}
@@ -48,6 +48,21 @@ func TestBaseParametersValidation(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestTargetParam(t *testing.T) {
+	params := NewParameters()
+	assert.Equal(t, "", TargetParam(params))
+
+	params.Set("cidr", "192.168.1.0/24")
+	assert.Equal(t, "192.168.1.0/24", TargetParam(params))
+
+	// Earlier keys win and empty values are skipped
+	params.Set("host", "")
+	params.Set("domain", "example.com")
+	assert.Equal(t, "example.com", TargetParam(params))
+	params.Set("host", "router.lan")
+	assert.Equal(t, "router.lan", TargetParam(params))
+}
+
 func TestPingParameters(t *testing.T) {
 	options := PingOptions{
 		Count:      10,
@@ -98,6 +113,17 @@ func TestPingParametersValidation(t *testing.T) {
 	err = params.Validate()
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "packet_size must be between 1 and 65507")
+
+	// Test invalid mode
+	params = NewPingParameters("example.com", PingOptions{Count: 1, PacketSize: 64, Mode: "burst"})
+	err = params.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "mode must be normal, adaptive, or flood")
+
+	// Test valid flood mode
+	params = NewPingParameters("example.com", PingOptions{Count: 100, PacketSize: 64, Mode: PingModeFlood})
+	assert.NoError(t, params.Validate())
+	assert.Equal(t, PingModeFlood, params.Get("mode"))
 }
 
 func TestTracerouteParameters(t *testing.T) {
@@ -148,6 +174,35 @@ func TestTracerouteParametersValidation(t *testing.T) {
 	err = params.Validate()
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "queries must be positive")
+
+	// Test invalid protocol
+	params = NewTracerouteParameters("example.com", TraceOptions{MaxHops: 30, Queries: 3, Protocol: "sctp"})
+	err = params.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "protocol must be icmp, udp, or tcp")
+
+	// Test invalid port
+	params = NewTracerouteParameters("example.com", TraceOptions{MaxHops: 30, Queries: 3, Protocol: TraceProtocolTCP, Port: 70000})
+	err = params.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "port must be between 1 and 65535")
+}
+
+func TestTracerouteParametersProtocol(t *testing.T) {
+	params := NewTracerouteParameters("example.com", TraceOptions{
+		MaxHops:  30,
+		Queries:  3,
+		Protocol: TraceProtocolTCP,
+		Port:     443,
+	})
+
+	assert.Equal(t, TraceProtocolTCP, params.Get("protocol"))
+	assert.Equal(t, 443, params.Get("port"))
+	assert.NoError(t, params.Validate())
+
+	assert.Equal(t, 33434, DefaultTracePort(TraceProtocolUDP))
+	assert.Equal(t, 80, DefaultTracePort(TraceProtocolTCP))
+	assert.Equal(t, 0, DefaultTracePort(TraceProtocolICMP))
 }
 
 func TestDNSParameters(t *testing.T) {
@@ -0,0 +1,288 @@
+// Package domain contains the tabular report layout shared by document exports
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// reportTable is a titled table describing part of a result
+type reportTable struct {
+	Title   string
+	Headers []string
+	Rows    [][]string
+}
+
+// reportBar is one labelled value of a report chart
+type reportBar struct {
+	Label string
+	Value time.Duration
+}
+
+// reportChart is a latency bar chart describing part of a result
+type reportChart struct {
+	Title string
+	Bars  []reportBar
+}
+
+// reportTitle returns the report heading for a result
+func reportTitle(metadata map[string]interface{}) string {
+	if tool, ok := metadata["tool"].(string); ok && tool != "" {
+		return fmt.Sprintf("NetTraceX %s Report", tool)
+	}
+	return "NetTraceX Report"
+}
+
+// reportSummary returns the result metadata as sorted key/value rows
+func reportSummary(metadata map[string]interface{}) [][]string {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	rows := make([][]string, 0, len(keys))
+	for _, key := range keys {
+		rows = append(rows, []string{key, formatReportValue(metadata[key])})
+	}
+	return rows
+}
+
+// formatReportValue formats a metadata value for a report cell
+func formatReportValue(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case time.Duration:
+		return v.String()
+	case string:
+		return v
+	case fmt.Stringer:
+		return v.String()
+	case int, int64, float64, bool:
+		return fmt.Sprintf("%v", v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	}
+}
+
+// reportSections returns the tables and charts describing result data
+func reportSections(data interface{}) ([]reportTable, []reportChart) {
+	switch data := data.(type) {
+	case []PingResult:
+		table := reportTable{Title: "Replies", Headers: []string{"Seq", "Host", "RTT (ms)", "TTL", "Status"}}
+		chart := reportChart{Title: "Round-trip time"}
+		for _, result := range data {
+			status, rtt := "ok", formatMs(result.RTT)
+			if result.Error != nil {
+				status, rtt = result.Error.Error(), "-"
+			} else {
+				chart.Bars = append(chart.Bars, reportBar{Label: fmt.Sprintf("seq %d", result.Sequence), Value: result.RTT})
+			}
+			table.Rows = append(table.Rows, []string{fmt.Sprintf("%d", result.Sequence), result.Host.Hostname, rtt, fmt.Sprintf("%d", result.TTL), status})
+		}
+		return []reportTable{table}, []reportChart{chart}
+	case MultiPingResult:
+		table := reportTable{Title: "Targets", Headers: []string{"Host", "Sent", "Received", "Loss (%)", "Avg RTT (ms)", "Error"}}
+		chart := reportChart{Title: "Average round-trip time"}
+		for _, target := range data.Targets {
+			errText := ""
+			if target.Error != nil {
+				errText = target.Error.Error()
+			}
+			table.Rows = append(table.Rows, []string{target.Host, fmt.Sprintf("%d", target.PacketsSent), fmt.Sprintf("%d", target.PacketsReceived),
+				fmt.Sprintf("%.1f", target.PacketLoss), formatMs(target.AvgRTT), errText})
+			if target.PacketsReceived > 0 {
+				chart.Bars = append(chart.Bars, reportBar{Label: target.Host, Value: target.AvgRTT})
+			}
+		}
+		return []reportTable{table}, []reportChart{chart}
+	case []TraceHop:
+		table := reportTable{Title: "Hops", Headers: []string{"Hop", "Hostname", "IP Address", "Avg RTT (ms)", "Status"}}
+		chart := reportChart{Title: "Average RTT per hop"}
+		for _, hop := range data {
+			ip, status, rtt := "*", "ok", "-"
+			if hop.Host.IPAddress != nil {
+				ip = hop.Host.IPAddress.String()
+			}
+			if hop.Timeout {
+				status = "timeout"
+			} else if avg := averageDuration(hop.RTT); avg > 0 {
+				rtt = formatMs(avg)
+				chart.Bars = append(chart.Bars, reportBar{Label: fmt.Sprintf("%d %s", hop.Number, ip), Value: avg})
+			}
+			table.Rows = append(table.Rows, []string{fmt.Sprintf("%d", hop.Number), hop.Host.Hostname, ip, rtt, status})
+		}
+		return []reportTable{table}, []reportChart{chart}
+	case DNSResult:
+		table := reportTable{Title: fmt.Sprintf("Records for %s", data.Query), Headers: []string{"Name", "Type", "Value", "TTL", "Priority"}}
+		for _, record := range data.Records {
+			table.Rows = append(table.Rows, []string{record.Name, dnsTypeName(record.Type), record.Value, fmt.Sprintf("%d", record.TTL), fmt.Sprintf("%d", record.Priority)})
+		}
+		return []reportTable{table}, nil
+	case WHOISResult:
+		tables := []reportTable{{
+			Title:   "Registration",
+			Headers: []string{"Field", "Value"},
+			Rows: [][]string{
+				{"Domain", data.Domain},
+				{"Registrar", data.Registrar},
+				{"Created", formatReportDate(data.Created)},
+				{"Updated", formatReportDate(data.Updated)},
+				{"Expires", formatReportDate(data.Expires)},
+				{"Status", strings.Join(data.Status, ", ")},
+			},
+		}}
+		if len(data.NameServers) > 0 {
+			tables = append(tables, listTable("Name Servers", "Name Server", data.NameServers))
+		}
+		return tables, nil
+	case SSLResult:
+		tables := []reportTable{{
+			Title:   fmt.Sprintf("Certificate for %s:%d", data.Host, data.Port),
+			Headers: []string{"Field", "Value"},
+			Rows: [][]string{
+				{"Subject", data.Subject},
+				{"Issuer", data.Issuer},
+				{"Valid", fmt.Sprintf("%t", data.Valid)},
+				{"Expires", formatReportDate(data.Expiry)},
+			},
+		}}
+		if len(data.SANs) > 0 {
+			tables = append(tables, listTable("Subject Alternative Names", "Name", data.SANs))
+		}
+		if len(data.Errors) > 0 {
+			tables = append(tables, listTable("Errors", "Error", data.Errors))
+		}
+		return tables, nil
+	case DualStackResult:
+		table := reportTable{Title: fmt.Sprintf("Connections to %s port %d", data.Host, data.Port), Headers: []string{"Family", "Connected", "Connect (ms)", "Success", "Error"}}
+		chart := reportChart{Title: "Connect time"}
+		for _, family := range []DualStackFamilyResult{data.IPv4, data.IPv6} {
+			table.Rows = append(table.Rows, []string{family.Family, family.Connected, formatMs(family.ConnectTime), fmt.Sprintf("%t", family.Success), family.Error})
+			if family.Success {
+				chart.Bars = append(chart.Bars, reportBar{Label: family.Family, Value: family.ConnectTime})
+			}
+		}
+		tables := []reportTable{table}
+		if len(data.Diagnosis) > 0 {
+			tables = append(tables, listTable("Diagnosis", "Finding", data.Diagnosis))
+		}
+		return tables, []reportChart{chart}
+	case SweepResult:
+		table := reportTable{Title: fmt.Sprintf("Live hosts in %s (%d of %d)", data.CIDR, len(data.Hosts), data.Scanned), Headers: []string{"IP", "Hostname", "MAC", "Vendor", "RTT (ms)"}}
+		chart := reportChart{Title: "Round-trip time"}
+		for _, host := range data.Hosts {
+			table.Rows = append(table.Rows, []string{host.IP.String(), host.Hostname, host.MAC, host.Vendor, formatMs(host.RTT)})
+			chart.Bars = append(chart.Bars, reportBar{Label: host.IP.String(), Value: host.RTT})
+		}
+		return []reportTable{table}, []reportChart{chart}
+	case ZoneTransferResult:
+		table := reportTable{Title: fmt.Sprintf("Zone transfers for %s", data.Domain), Headers: []string{"Nameserver", "Address", "AXFR", "Records", "Error"}}
+		for _, server := range data.Servers {
+			status := "error"
+			switch {
+			case server.Allowed:
+				status = "ALLOWED"
+			case server.Refused:
+				status = "refused"
+			}
+			table.Rows = append(table.Rows, []string{server.Nameserver, server.Address, status, fmt.Sprintf("%d", server.Records), server.Error})
+		}
+		return []reportTable{table}, nil
+	case BatchResult:
+		table := reportTable{Title: fmt.Sprintf("Batch %s (%d targets, %d failed)", data.Tool, len(data.Targets), data.Failed()), Headers: []string{"Target", "Status", "Duration (ms)", "Error"}}
+		for _, target := range data.Targets {
+			status := "ok"
+			if !target.Succeeded() {
+				status = "failed"
+			}
+			table.Rows = append(table.Rows, []string{target.Target, status, formatMs(target.Duration), target.Error})
+		}
+		return []reportTable{table}, nil
+	case ScenarioResult:
+		steps := reportTable{Title: fmt.Sprintf("Scenario %s (%d of %d steps passed)", data.Name, data.PassedSteps(), len(data.Steps)), Headers: []string{"Step", "Tool", "Target", "Status", "Duration (ms)", "Error"}}
+		assertions := reportTable{Title: "Assertions", Headers: []string{"Step", "Assertion", "Actual", "Result"}}
+		for _, step := range data.Steps {
+			steps.Rows = append(steps.Rows, []string{step.Name, step.Tool, step.Target, step.Status(), formatMs(step.Duration), step.Error})
+			for _, assertion := range step.Assertions {
+				outcome := "pass"
+				if !assertion.Passed {
+					outcome = "FAIL"
+					if assertion.Error != "" {
+						outcome = "FAIL: " + assertion.Error
+					}
+				}
+				assertions.Rows = append(assertions.Rows, []string{step.Name, assertion.Expression, assertion.Actual, outcome})
+			}
+		}
+		return []reportTable{steps, assertions}, nil
+	default:
+		return []reportTable{{Title: "Data", Headers: []string{"Value"}, Rows: [][]string{{formatReportValue(data)}}}}, nil
+	}
+}
+
+// listTable builds a single-column table from values
+func listTable(title, header string, values []string) reportTable {
+	table := reportTable{Title: title, Headers: []string{header}}
+	for _, value := range values {
+		table.Rows = append(table.Rows, []string{value})
+	}
+	return table
+}
+
+// formatMs formats a duration in milliseconds with microsecond precision
+func formatMs(d time.Duration) string {
+	return fmt.Sprintf("%.3f", float64(d.Nanoseconds())/1000000.0)
+}
+
+// formatReportDate formats a date, or "-" when unknown
+func formatReportDate(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02")
+}
+
+// averageDuration returns the mean of samples
+func averageDuration(samples []time.Duration) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, sample := range samples {
+		total += sample
+	}
+	return total / time.Duration(len(samples))
+}
+
+// dnsTypeName returns the mnemonic of a DNS record type
+func dnsTypeName(recordType DNSRecordType) string {
+	switch recordType {
+	case DNSRecordTypeA:
+		return "A"
+	case DNSRecordTypeAAAA:
+		return "AAAA"
+	case DNSRecordTypeMX:
+		return "MX"
+	case DNSRecordTypeTXT:
+		return "TXT"
+	case DNSRecordTypeCNAME:
+		return "CNAME"
+	case DNSRecordTypeNS:
+		return "NS"
+	case DNSRecordTypeSOA:
+		return "SOA"
+	case DNSRecordTypePTR:
+		return "PTR"
+	default:
+		return fmt.Sprintf("TYPE%d", recordType)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 )
@@ -52,6 +53,14 @@ func (r *BaseResult) Export(format ExportFormat) ([]byte, error) {
 		return r.exportCSV()
 	case ExportFormatText:
 		return r.exportText()
+	case ExportFormatHTML:
+		return r.exportHTML()
+	case ExportFormatMarkdown:
+		return r.exportMarkdown()
+	case ExportFormatPDF:
+		return r.exportPDF()
+	case ExportFormatJUnit:
+		return r.exportJUnit()
 	default:
 		return nil, fmt.Errorf("unsupported export format: %d", format)
 	}
@@ -81,6 +90,8 @@ func (r *BaseResult) exportCSV() ([]byte, error) {
 	switch data := r.data.(type) {
 	case []PingResult:
 		return r.exportPingResultsCSV(data)
+	case MultiPingResult:
+		return r.exportMultiPingResultCSV(data)
 	case []TraceHop:
 		return r.exportTraceHopsCSV(data)
 	case DNSResult:
@@ -89,6 +100,16 @@ func (r *BaseResult) exportCSV() ([]byte, error) {
 		return r.exportWHOISResultCSV(data)
 	case SSLResult:
 		return r.exportSSLResultCSV(data)
+	case DualStackResult:
+		return r.exportDualStackResultCSV(data)
+	case SweepResult:
+		return r.exportSweepResultCSV(data)
+	case ZoneTransferResult:
+		return r.exportZoneTransferResultCSV(data)
+	case BatchResult:
+		return r.exportBatchResultCSV(data)
+	case ScenarioResult:
+		return r.exportScenarioResultCSV(data)
 	default:
 		// Fallback to JSON for unknown types
 		jsonData, err := json.Marshal(data)
@@ -114,9 +135,14 @@ func (r *BaseResult) exportText() ([]byte, error) {
 		buf.WriteString(fmt.Sprintf("%s: %v\n", key, value))
 	}
 	buf.WriteString("\n=== Data ===\n")
+	writeTextData(&buf, r.data)
 	
-	// Format data based on type
-	switch data := r.data.(type) {
+	return []byte(buf.String()), nil
+}
+
+// writeTextData formats result data as plain text based on its type
+func writeTextData(buf *strings.Builder, data interface{}) {
+	switch data := data.(type) {
 	case []PingResult:
 		for _, result := range data {
 			buf.WriteString(fmt.Sprintf("Ping %s: seq=%d time=%v ttl=%d\n",
@@ -143,11 +169,59 @@ func (r *BaseResult) exportText() ([]byte, error) {
 		buf.WriteString(fmt.Sprintf("Issuer: %s\n", data.Issuer))
 		buf.WriteString(fmt.Sprintf("Valid: %t\n", data.Valid))
 		buf.WriteString(fmt.Sprintf("Expires: %s\n", data.Expiry.Format(time.RFC3339)))
+	case SweepResult:
+		buf.WriteString(fmt.Sprintf("Sweep %s: %d of %d addresses alive\n", data.CIDR, len(data.Hosts), data.Scanned))
+		for _, host := range data.Hosts {
+			buf.WriteString(fmt.Sprintf("  %s %s %s %s %v\n", host.IP, orDash(host.Hostname), orDash(host.MAC), orDash(host.Vendor), host.RTT))
+		}
+	case BatchResult:
+		buf.WriteString(fmt.Sprintf("Batch %s: %d targets, %d failed\n", data.Tool, len(data.Targets), data.Failed()))
+		for _, target := range data.Targets {
+			buf.WriteString(fmt.Sprintf("\n--- %s (%v) ---\n", target.Target, target.Duration))
+			if !target.Succeeded() {
+				buf.WriteString(fmt.Sprintf("Error: %s\n", target.Error))
+				continue
+			}
+			writeTextData(buf, target.Data)
+		}
+	case ScenarioResult:
+		status := "PASS"
+		if !data.Passed() {
+			status = "FAIL"
+		}
+		buf.WriteString(fmt.Sprintf("Scenario %s: %s (%d of %d steps passed)\n", data.Name, status, data.PassedSteps(), len(data.Steps)))
+		for _, step := range data.Steps {
+			label := step.Name
+			if invocation := step.Tool + " " + step.Target; label != invocation {
+				label = fmt.Sprintf("%s (%s)", label, invocation)
+			}
+			buf.WriteString(fmt.Sprintf("[%s] %s %v\n", step.Status(), label, step.Duration))
+			if step.Error != "" {
+				buf.WriteString(fmt.Sprintf("  Error: %s\n", step.Error))
+			}
+			for _, assertion := range step.Assertions {
+				writeAssertionText(buf, assertion)
+			}
+		}
 	default:
 		buf.WriteString(fmt.Sprintf("%+v\n", data))
 	}
-	
-	return []byte(buf.String()), nil
+}
+
+// writeAssertionText formats one scenario assertion outcome as plain text
+func writeAssertionText(buf *strings.Builder, assertion ScenarioAssertionResult) {
+	mark := "ok"
+	if !assertion.Passed {
+		mark = "FAILED"
+	}
+	buf.WriteString(fmt.Sprintf("  %s: %s", mark, assertion.Expression))
+	if assertion.Actual != "" {
+		buf.WriteString(fmt.Sprintf(" (actual %s)", assertion.Actual))
+	}
+	if assertion.Error != "" {
+		buf.WriteString(fmt.Sprintf(" (%s)", assertion.Error))
+	}
+	buf.WriteString("\n")
 }
 
 // Helper methods for specific CSV exports
@@ -156,11 +230,15 @@ func (r *BaseResult) exportPingResultsCSV(results []PingResult) ([]byte, error)
 	writer := csv.NewWriter(&buf)
 	
 	// Write header
-	writer.Write([]string{"timestamp", "host", "sequence", "rtt_ms", "ttl", "packet_size"})
+	writer.Write([]string{"timestamp", "host", "sequence", "rtt_ms", "ttl", "packet_size", "error"})
 	
 	// Write data
 	for _, result := range results {
 		rttMs := float64(result.RTT.Nanoseconds()) / 1000000.0
+		errText := ""
+		if result.Error != nil {
+			errText = result.Error.Error()
+		}
 		writer.Write([]string{
 			result.Timestamp.Format(time.RFC3339),
 			result.Host.Hostname,
@@ -168,6 +246,7 @@ func (r *BaseResult) exportPingResultsCSV(results []PingResult) ([]byte, error)
 			fmt.Sprintf("%.3f", rttMs),
 			fmt.Sprintf("%d", result.TTL),
 			fmt.Sprintf("%d", result.PacketSize),
+			errText,
 		})
 	}
 	
@@ -175,6 +254,33 @@ func (r *BaseResult) exportPingResultsCSV(results []PingResult) ([]byte, error)
 	return []byte(buf.String()), writer.Error()
 }
 
+// exportMultiPingResultCSV exports one summary row per pinged host
+func (r *BaseResult) exportMultiPingResultCSV(result MultiPingResult) ([]byte, error) {
+	var buf strings.Builder
+	writer := csv.NewWriter(&buf)
+
+	writer.Write([]string{"host", "packets_sent", "packets_received", "packet_loss_percent", "avg_rtt_ms", "error"})
+
+	for _, target := range result.Targets {
+		errText := ""
+		if target.Error != nil {
+			errText = target.Error.Error()
+		}
+		avgMs := float64(target.AvgRTT.Nanoseconds()) / 1000000.0
+		writer.Write([]string{
+			target.Host,
+			fmt.Sprintf("%d", target.PacketsSent),
+			fmt.Sprintf("%d", target.PacketsReceived),
+			fmt.Sprintf("%.1f", target.PacketLoss),
+			fmt.Sprintf("%.3f", avgMs),
+			errText,
+		})
+	}
+
+	writer.Flush()
+	return []byte(buf.String()), writer.Error()
+}
+
 func (r *BaseResult) exportTraceHopsCSV(hops []TraceHop) ([]byte, error) {
 	var buf strings.Builder
 	writer := csv.NewWriter(&buf)
@@ -194,10 +300,15 @@ func (r *BaseResult) exportTraceHopsCSV(hops []TraceHop) ([]byte, error) {
 			}
 		}
 		
+		ip := ""
+		if hop.Host.IPAddress != nil {
+			ip = hop.Host.IPAddress.String()
+		}
+		
 		writer.Write([]string{
 			fmt.Sprintf("%d", hop.Number),
 			hop.Host.Hostname,
-			hop.Host.IPAddress.String(),
+			ip,
 			rttStrs[0],
 			rttStrs[1],
 			rttStrs[2],
@@ -241,13 +352,37 @@ func (r *BaseResult) exportWHOISResultCSV(result WHOISResult) ([]byte, error) {
 	// Write data
 	writer.Write([]string{"domain", result.Domain})
 	writer.Write([]string{"registrar", result.Registrar})
-	writer.Write([]string{"created", result.Created.Format(time.RFC3339)})
-	writer.Write([]string{"updated", result.Updated.Format(time.RFC3339)})
-	writer.Write([]string{"expires", result.Expires.Format(time.RFC3339)})
+	writer.Write([]string{"created", formatCSVTime(result.Created)})
+	writer.Write([]string{"updated", formatCSVTime(result.Updated)})
+	writer.Write([]string{"expires", formatCSVTime(result.Expires)})
 	
 	for _, ns := range result.NameServers {
 		writer.Write([]string{"nameserver", ns})
 	}
+	for _, status := range result.Status {
+		writer.Write([]string{"status", status})
+	}
+	
+	// Contacts are written in a stable role order as role-prefixed fields
+	roles := make([]string, 0, len(result.Contacts))
+	for role := range result.Contacts {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	for _, role := range roles {
+		contact := result.Contacts[role]
+		for _, field := range [][2]string{
+			{"name", contact.Name},
+			{"organization", contact.Organization},
+			{"email", contact.Email},
+			{"phone", contact.Phone},
+			{"address", contact.Address},
+		} {
+			if field[1] != "" {
+				writer.Write([]string{fmt.Sprintf("%s_%s", role, field[0]), field[1]})
+			}
+		}
+	}
 	
 	writer.Flush()
 	return []byte(buf.String()), writer.Error()
@@ -266,12 +401,165 @@ func (r *BaseResult) exportSSLResultCSV(result SSLResult) ([]byte, error) {
 	writer.Write([]string{"subject", result.Subject})
 	writer.Write([]string{"issuer", result.Issuer})
 	writer.Write([]string{"valid", fmt.Sprintf("%t", result.Valid)})
-	writer.Write([]string{"expires", result.Expiry.Format(time.RFC3339)})
+	writer.Write([]string{"expires", formatCSVTime(result.Expiry)})
 	
 	for _, san := range result.SANs {
 		writer.Write([]string{"san", san})
 	}
+	for _, validationError := range result.Errors {
+		writer.Write([]string{"error", validationError})
+	}
 	
 	writer.Flush()
 	return []byte(buf.String()), writer.Error()
-}
\ No newline at end of file
+}
+
+// exportDualStackResultCSV exports one row per address family
+func (r *BaseResult) exportDualStackResultCSV(result DualStackResult) ([]byte, error) {
+	var buf strings.Builder
+	writer := csv.NewWriter(&buf)
+
+	writer.Write([]string{"host", "port", "family", "addresses", "connected", "connect_ms", "success", "winner", "error"})
+
+	for _, family := range []DualStackFamilyResult{result.IPv4, result.IPv6} {
+		connectMs := float64(family.ConnectTime.Nanoseconds()) / 1000000.0
+		writer.Write([]string{
+			result.Host,
+			fmt.Sprintf("%d", result.Port),
+			family.Family,
+			strings.Join(family.Addresses, " "),
+			family.Connected,
+			fmt.Sprintf("%.3f", connectMs),
+			fmt.Sprintf("%t", family.Success),
+			fmt.Sprintf("%t", result.Winner != "" && result.Winner == family.Family),
+			family.Error,
+		})
+	}
+
+	writer.Flush()
+	return []byte(buf.String()), writer.Error()
+}
+
+// exportSweepResultCSV exports the live hosts of a sweep as an inventory list
+func (r *BaseResult) exportSweepResultCSV(result SweepResult) ([]byte, error) {
+	var buf strings.Builder
+	writer := csv.NewWriter(&buf)
+
+	writer.Write([]string{"ip", "hostname", "mac", "vendor", "rtt_ms"})
+
+	for _, host := range result.Hosts {
+		rttMs := float64(host.RTT.Nanoseconds()) / 1000000.0
+		writer.Write([]string{
+			host.IP.String(),
+			host.Hostname,
+			host.MAC,
+			host.Vendor,
+			fmt.Sprintf("%.3f", rttMs),
+		})
+	}
+
+	writer.Flush()
+	return []byte(buf.String()), writer.Error()
+}
+
+// exportZoneTransferResultCSV exports one row per nameserver address checked
+func (r *BaseResult) exportZoneTransferResultCSV(result ZoneTransferResult) ([]byte, error) {
+	var buf strings.Builder
+	writer := csv.NewWriter(&buf)
+
+	writer.Write([]string{"domain", "nameserver", "address", "allowed", "refused", "records", "duration_ms", "error"})
+
+	for _, server := range result.Servers {
+		durationMs := float64(server.Duration.Nanoseconds()) / 1000000.0
+		writer.Write([]string{
+			result.Domain,
+			server.Nameserver,
+			server.Address,
+			fmt.Sprintf("%t", server.Allowed),
+			fmt.Sprintf("%t", server.Refused),
+			fmt.Sprintf("%d", server.Records),
+			fmt.Sprintf("%.3f", durationMs),
+			server.Error,
+		})
+	}
+
+	writer.Flush()
+	return []byte(buf.String()), writer.Error()
+}
+
+// exportBatchResultCSV exports one row per batch target with its outcome
+func (r *BaseResult) exportBatchResultCSV(result BatchResult) ([]byte, error) {
+	var buf strings.Builder
+	writer := csv.NewWriter(&buf)
+
+	writer.Write([]string{"target", "status", "duration_ms", "error"})
+
+	for _, target := range result.Targets {
+		status := "ok"
+		if !target.Succeeded() {
+			status = "failed"
+		}
+		durationMs := float64(target.Duration.Nanoseconds()) / 1000000.0
+		writer.Write([]string{
+			target.Target,
+			status,
+			fmt.Sprintf("%.3f", durationMs),
+			target.Error,
+		})
+	}
+
+	writer.Flush()
+	return []byte(buf.String()), writer.Error()
+}
+
+// exportScenarioResultCSV exports one row per assertion, or per step when it has none
+func (r *BaseResult) exportScenarioResultCSV(result ScenarioResult) ([]byte, error) {
+	var buf strings.Builder
+	writer := csv.NewWriter(&buf)
+
+	writer.Write([]string{"step", "tool", "target", "status", "duration_ms", "assertion", "actual", "assertion_passed", "error"})
+
+	for _, step := range result.Steps {
+		durationMs := fmt.Sprintf("%.3f", float64(step.Duration.Nanoseconds())/1000000.0)
+		if len(step.Assertions) == 0 {
+			writer.Write([]string{step.Name, step.Tool, step.Target, step.Status(), durationMs, "", "", "", step.Error})
+			continue
+		}
+		for _, assertion := range step.Assertions {
+			message := step.Error
+			if assertion.Error != "" {
+				message = assertion.Error
+			}
+			writer.Write([]string{
+				step.Name,
+				step.Tool,
+				step.Target,
+				step.Status(),
+				durationMs,
+				assertion.Expression,
+				assertion.Actual,
+				fmt.Sprintf("%t", assertion.Passed),
+				message,
+			})
+		}
+	}
+
+	writer.Flush()
+	return []byte(buf.String()), writer.Error()
+}
+
+// formatCSVTime formats a time for CSV, leaving unknown times empty
+func formatCSVTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// orDash returns "-" for empty values in plain text exports
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,147 @@
+// Package domain contains the standalone HTML report export
+package domain
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"time"
+)
+
+// Chart geometry for the inline SVG bar charts
+const (
+	htmlChartWidth      = 640
+	htmlChartLabelWidth = 180
+	htmlChartValueWidth = 90
+	htmlChartBarHeight  = 18
+	htmlChartBarGap     = 6
+)
+
+// htmlChartBar is a bar with precomputed SVG geometry
+type htmlChartBar struct {
+	Label   string
+	Display string
+	Y       int
+	TextY   int
+	Width   int
+	ValueX  int
+}
+
+// htmlChart is a chart with precomputed SVG geometry
+type htmlChart struct {
+	Title  string
+	Width  int
+	Height int
+	BarX   int
+	Bars   []htmlChartBar
+}
+
+// htmlReport is the data rendered by htmlReportTemplate
+type htmlReport struct {
+	Title     string
+	Generated string
+	Summary   [][]string
+	Tables    []reportTable
+	Charts    []htmlChart
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem auto; max-width: 960px; padding: 0 1rem; color: #1e1e1e; background: #fafafa; }
+h1 { color: #1c71d8; margin-bottom: 0.2rem; }
+h2 { border-bottom: 2px solid #62a0ea; padding-bottom: 0.3rem; margin-top: 2rem; }
+.generated { color: #77767b; margin-top: 0; }
+table { border-collapse: collapse; width: 100%; margin: 0.5rem 0 1rem; background: #ffffff; }
+th, td { border: 1px solid #deddda; padding: 0.35rem 0.6rem; text-align: left; vertical-align: top; font-size: 0.9rem; }
+th { background: #f0f0f0; }
+tr:nth-child(even) td { background: #f6f5f4; }
+td { word-break: break-word; }
+svg text { font-size: 12px; fill: #3d3846; }
+svg rect { fill: #62a0ea; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="generated">Generated {{.Generated}}</p>
+{{if .Summary}}<h2>Summary</h2>
+<table>
+{{range .Summary}}<tr><th>{{index . 0}}</th><td>{{index . 1}}</td></tr>
+{{end}}</table>
+{{end}}{{range .Charts}}<h2>{{.Title}}</h2>
+<svg width="{{.Width}}" height="{{.Height}}" viewBox="0 0 {{.Width}} {{.Height}}" role="img" aria-label="{{.Title}}">
+{{$barX := .BarX}}{{range .Bars}}<text x="0" y="{{.TextY}}">{{.Label}}</text><rect x="{{$barX}}" y="{{.Y}}" width="{{.Width}}" height="18" rx="3"></rect><text x="{{.ValueX}}" y="{{.TextY}}">{{.Display}}</text>
+{{end}}</svg>
+{{end}}{{range .Tables}}<h2>{{.Title}}</h2>
+{{if .Rows}}<table>
+<tr>{{range .Headers}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>
+{{else}}<p>No entries</p>
+{{end}}{{end}}</body>
+</html>
+`))
+
+// exportHTML exports the result as a standalone HTML report with tables and inline SVG charts
+func (r *BaseResult) exportHTML() ([]byte, error) {
+	tables, charts := reportSections(r.data)
+
+	report := htmlReport{
+		Title:     reportTitle(r.metadata),
+		Generated: time.Now().Format(time.RFC1123),
+		Summary:   reportSummary(r.metadata),
+		Tables:    tables,
+	}
+	for _, chart := range charts {
+		if len(chart.Bars) > 0 {
+			report.Charts = append(report.Charts, layoutHTMLChart(chart))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := htmlReportTemplate.Execute(&buf, report); err != nil {
+		return nil, fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// layoutHTMLChart scales the bars of chart to the SVG width
+func layoutHTMLChart(chart reportChart) htmlChart {
+	var max time.Duration
+	for _, bar := range chart.Bars {
+		if bar.Value > max {
+			max = bar.Value
+		}
+	}
+
+	barSpace := htmlChartWidth - htmlChartLabelWidth - htmlChartValueWidth
+	layout := htmlChart{
+		Title:  chart.Title,
+		Width:  htmlChartWidth,
+		Height: len(chart.Bars) * (htmlChartBarHeight + htmlChartBarGap),
+		BarX:   htmlChartLabelWidth,
+	}
+	for i, bar := range chart.Bars {
+		width := 1
+		if max > 0 {
+			width = int(float64(bar.Value) / float64(max) * float64(barSpace))
+		}
+		if width < 1 {
+			width = 1
+		}
+		y := i * (htmlChartBarHeight + htmlChartBarGap)
+		layout.Bars = append(layout.Bars, htmlChartBar{
+			Label:   bar.Label,
+			Display: bar.Value.Round(10 * time.Microsecond).String(),
+			Y:       y,
+			TextY:   y + htmlChartBarHeight - 5,
+			Width:   width,
+			ValueX:  htmlChartLabelWidth + width + 6,
+		})
+	}
+	return layout
+}
@@ -0,0 +1,178 @@
+// Package domain contains the JUnit XML export of batch and scenario runs
+package domain
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// junitTestSuites is the root element of a JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite groups the checks of one batch or scenario run
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase is one batch target or scenario step
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitMessage describes why a test case failed, errored or was skipped
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Details string `xml:",chardata"`
+}
+
+// exportJUnit exports batch and scenario runs as JUnit XML so CI dashboards
+// show a test case per target or step
+func (r *BaseResult) exportJUnit() ([]byte, error) {
+	var suite junitTestSuite
+	switch data := r.data.(type) {
+	case BatchResult:
+		suite = junitBatchSuite(data)
+	case ScenarioResult:
+		suite = junitScenarioSuite(data)
+	default:
+		return nil, fmt.Errorf("JUnit export is only supported for batch and scenario results, not %T", r.data)
+	}
+
+	report := junitTestSuites{
+		Name:     suite.Name,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to render JUnit report: %w", err)
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// junitBatchSuite reports each batch target as a test case that fails when the tool failed
+func junitBatchSuite(result BatchResult) junitTestSuite {
+	suite := junitTestSuite{
+		Name:      fmt.Sprintf("batch %s", result.Tool),
+		Tests:     len(result.Targets),
+		Time:      junitSeconds(result.Duration),
+		Timestamp: junitTimestamp(result.Timestamp),
+	}
+
+	for _, target := range result.Targets {
+		testCase := junitTestCase{
+			Name:      target.Target,
+			Classname: result.Tool,
+			Time:      junitSeconds(target.Duration),
+		}
+		if !target.Succeeded() {
+			suite.Failures++
+			testCase.Failure = &junitMessage{Message: target.Error, Type: "ToolFailure"}
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	return suite
+}
+
+// junitScenarioSuite reports each scenario step as a test case; failed assertions
+// are failures and steps whose tool could not run are errors
+func junitScenarioSuite(result ScenarioResult) junitTestSuite {
+	suite := junitTestSuite{
+		Name:      result.Name,
+		Tests:     len(result.Steps),
+		Time:      junitSeconds(result.Duration),
+		Timestamp: junitTimestamp(result.Timestamp),
+	}
+
+	for _, step := range result.Steps {
+		testCase := junitTestCase{
+			Name:      step.Name,
+			Classname: step.Tool,
+			Time:      junitSeconds(step.Duration),
+		}
+
+		var assertions, failed []string
+		for _, assertion := range step.Assertions {
+			line := fmt.Sprintf("[%s] %s (actual: %s)", passFail(assertion.Passed), assertion.Expression, assertion.Actual)
+			if assertion.Error != "" {
+				line += ": " + assertion.Error
+			}
+			assertions = append(assertions, line)
+			if !assertion.Passed {
+				failed = append(failed, assertion.Expression)
+			}
+		}
+		if len(assertions) > 0 {
+			testCase.SystemOut = strings.Join(assertions, "\n")
+		}
+
+		switch {
+		case step.Skipped:
+			suite.Skipped++
+			testCase.Skipped = &junitMessage{Message: step.Error}
+		case len(failed) > 0:
+			suite.Failures++
+			testCase.Failure = &junitMessage{
+				Message: fmt.Sprintf("%d of %d assertions failed", len(failed), len(step.Assertions)),
+				Type:    "AssertionFailure",
+				Details: strings.Join(failed, "\n"),
+			}
+		case !step.Passed:
+			suite.Errors++
+			testCase.Error = &junitMessage{Message: step.Error, Type: "ToolError"}
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	return suite
+}
+
+// passFail labels an assertion outcome
+func passFail(passed bool) string {
+	if passed {
+		return "PASS"
+	}
+	return "FAIL"
+}
+
+// junitSeconds formats a duration as the fractional seconds JUnit expects
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// junitTimestamp formats the start of a run, or nothing when unknown
+func junitTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02T15:04:05")
+}
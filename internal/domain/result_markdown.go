@@ -0,0 +1,61 @@
+// Package domain contains the GitHub-flavored Markdown report export
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// markdownCellReplacer escapes characters that would break a Markdown table cell
+var markdownCellReplacer = strings.NewReplacer(
+	"|", "\\|",
+	"\r\n", "<br>",
+	"\n", "<br>",
+)
+
+// exportMarkdown exports the result as GitHub-flavored Markdown sections and tables
+func (r *BaseResult) exportMarkdown() ([]byte, error) {
+	var buf strings.Builder
+
+	buf.WriteString("# " + reportTitle(r.metadata) + "\n\n")
+	buf.WriteString("_Generated " + time.Now().Format(time.RFC1123) + "_\n")
+
+	if summary := reportSummary(r.metadata); len(summary) > 0 {
+		writeMarkdownTable(&buf, reportTable{Title: "Summary", Headers: []string{"Field", "Value"}, Rows: summary})
+	}
+
+	tables, _ := reportSections(r.data)
+	for _, table := range tables {
+		writeMarkdownTable(&buf, table)
+	}
+
+	return []byte(buf.String()), nil
+}
+
+// writeMarkdownTable writes table as a second-level section
+func writeMarkdownTable(buf *strings.Builder, table reportTable) {
+	buf.WriteString("\n## " + table.Title + "\n\n")
+	if len(table.Rows) == 0 {
+		buf.WriteString("_No entries_\n")
+		return
+	}
+
+	writeMarkdownRow(buf, table.Headers)
+	separators := make([]string, len(table.Headers))
+	for i := range separators {
+		separators[i] = "---"
+	}
+	writeMarkdownRow(buf, separators)
+	for _, row := range table.Rows {
+		writeMarkdownRow(buf, row)
+	}
+}
+
+// writeMarkdownRow writes one table row with escaped cells
+func writeMarkdownRow(buf *strings.Builder, cells []string) {
+	buf.WriteString("|")
+	for _, cell := range cells {
+		buf.WriteString(" " + markdownCellReplacer.Replace(cell) + " |")
+	}
+	buf.WriteString("\n")
+}
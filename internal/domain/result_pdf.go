@@ -0,0 +1,373 @@
+// Package domain contains the PDF report export
+package domain
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+)
+
+// Page geometry of the PDF report in points (A4 portrait)
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 48.0
+	pdfRowHeight  = 14.0
+	pdfCellPad    = 4.0
+	pdfFontSize   = 9.0
+	pdfChartLabel = 150.0
+	pdfChartValue = 70.0
+)
+
+// Font families of the PDF report
+const (
+	pdfFamily         = "Go"
+	pdfFallbackFamily = "Fallback"
+)
+
+// pdfFallbackFonts lists system TrueType fonts with CJK coverage; the first
+// one installed prints the characters the Go fonts have no glyph for
+var pdfFallbackFonts = []string{
+	"/usr/share/fonts/truetype/droid/DroidSansFallbackFull.ttf",
+	"/usr/share/fonts/google-droid-sans-fonts/DroidSansFallbackFull.ttf",
+	"/System/Library/Fonts/Supplemental/Arial Unicode.ttf",
+	"/Library/Fonts/Arial Unicode.ttf",
+	`C:\Windows\Fonts\arialuni.ttf`,
+}
+
+var (
+	pdfFontsOnce sync.Once
+	pdfGlyphs    *sfnt.Font
+	pdfFallback  []byte
+)
+
+// loadPDFFonts parses the Go font for its glyph coverage and reads the
+// fallback font, if one is installed
+func loadPDFFonts() {
+	pdfFontsOnce.Do(func() {
+		pdfGlyphs, _ = sfnt.Parse(goregular.TTF)
+		for _, path := range pdfFallbackFonts {
+			if data, err := os.ReadFile(path); err == nil {
+				pdfFallback = data
+				return
+			}
+		}
+	})
+}
+
+// pdfDocument lays out text, tables and bars top to bottom across A4 pages,
+// with the cursor y measured down from the top of the page
+type pdfDocument struct {
+	pdf      *gofpdf.Fpdf
+	fallback bool
+	y        float64
+}
+
+// newPDFDocument creates an empty document with the Go fonts embedded
+func newPDFDocument() *pdfDocument {
+	loadPDFFonts()
+
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetAutoPageBreak(false, pdfMargin)
+	pdf.SetCreator("NetTraceX", false)
+	pdf.AddUTF8FontFromBytes(pdfFamily, "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes(pdfFamily, "B", gobold.TTF)
+
+	doc := &pdfDocument{pdf: pdf}
+	if pdfFallback != nil && pdfGlyphs != nil {
+		pdf.AddUTF8FontFromBytes(pdfFallbackFamily, "", pdfFallback)
+		pdf.AddUTF8FontFromBytes(pdfFallbackFamily, "B", pdfFallback)
+		// A font the PDF library cannot read leaves the Go fonts to print everything
+		if pdf.Err() {
+			pdf.ClearError()
+		} else {
+			doc.fallback = true
+		}
+	}
+	return doc
+}
+
+// exportPDF exports the result as a paginated PDF report with tables and bar
+// charts. Text is set in the embedded Go fonts, which cover Latin, Greek and
+// Cyrillic; other characters, such as CJK WHOIS data, use a system fallback
+// font when one is installed.
+func (r *BaseResult) exportPDF() ([]byte, error) {
+	doc := newPDFDocument()
+	doc.report(r.metadata, r.data)
+	return doc.bytes()
+}
+
+// report lays out the title, summary, charts and tables of a result
+func (d *pdfDocument) report(metadata map[string]interface{}, data interface{}) {
+	tables, charts := reportSections(data)
+
+	d.newPage()
+	d.text(pdfMargin, 18, true, reportTitle(metadata))
+	d.y += 8
+	d.text(pdfMargin, pdfFontSize, false, "Generated "+time.Now().Format(time.RFC1123))
+
+	if summary := reportSummary(metadata); len(summary) > 0 {
+		d.table(reportTable{Title: "Summary", Headers: []string{"Field", "Value"}, Rows: summary})
+	}
+	for _, chart := range charts {
+		if len(chart.Bars) > 0 {
+			d.chart(chart)
+		}
+	}
+	for _, table := range tables {
+		d.table(table)
+	}
+}
+
+// newPage starts a new page with the cursor at the top margin
+func (d *pdfDocument) newPage() {
+	d.pdf.AddPage()
+	d.y = pdfMargin
+}
+
+// ensureSpace starts a new page unless height fits above the bottom margin
+// and reports whether it did
+func (d *pdfDocument) ensureSpace(height float64) bool {
+	if d.y+height <= pdfPageHeight-pdfMargin {
+		return false
+	}
+	d.newPage()
+	return true
+}
+
+// text writes a line of text at x below the cursor and advances the cursor
+func (d *pdfDocument) text(x, size float64, bold bool, value string) {
+	d.ensureSpace(size * 1.4)
+	d.y += size * 1.4
+	d.textAt(x, d.y-size*0.3, size, bold, value)
+}
+
+// textAt writes value with its baseline at (x, y) without moving the cursor
+func (d *pdfDocument) textAt(x, y, size float64, bold bool, value string) {
+	for _, run := range pdfRuns(value, d.fallback) {
+		d.setFont(run.fallback, bold, size)
+		d.pdf.Text(x, y, run.text)
+		x += d.pdf.GetStringWidth(run.text)
+	}
+}
+
+// setFont selects the regular or bold face of the Go or fallback font
+func (d *pdfDocument) setFont(fallback, bold bool, size float64) {
+	family, style := pdfFamily, ""
+	if fallback {
+		family = pdfFallbackFamily
+	}
+	if bold {
+		style = "B"
+	}
+	d.pdf.SetFont(family, style, size)
+}
+
+// rect fills a rectangle whose top left corner is (x, top) with an RGB color
+func (d *pdfDocument) rect(x, top, width, height float64, red, green, blue int) {
+	d.pdf.SetFillColor(red, green, blue)
+	d.pdf.Rect(x, top, width, height, "F")
+}
+
+// heading writes a section title, keeping it on the page of the first rows below it
+func (d *pdfDocument) heading(title string) {
+	d.ensureSpace(40 + 2*pdfRowHeight)
+	d.y += 14
+	d.text(pdfMargin, 13, true, title)
+	d.y += 4
+}
+
+// table writes a titled table, repeating the header row on every page it spans
+func (d *pdfDocument) table(table reportTable) {
+	d.heading(table.Title)
+	if len(table.Rows) == 0 {
+		d.text(pdfMargin, pdfFontSize, false, "No entries")
+		return
+	}
+
+	widths := d.columnWidths(table, pdfPageWidth-2*pdfMargin)
+	d.tableRow(table.Headers, widths, true, false)
+	for i, row := range table.Rows {
+		if d.ensureSpace(pdfRowHeight) {
+			d.tableRow(table.Headers, widths, true, false)
+		}
+		d.tableRow(row, widths, false, i%2 == 1)
+	}
+}
+
+// tableRow writes one row of cells truncated to their column widths
+func (d *pdfDocument) tableRow(cells []string, widths []float64, header, shaded bool) {
+	d.ensureSpace(pdfRowHeight)
+	switch {
+	case header:
+		d.rect(pdfMargin, d.y, sumWidths(widths), pdfRowHeight, 230, 230, 230)
+	case shaded:
+		d.rect(pdfMargin, d.y, sumWidths(widths), pdfRowHeight, 245, 245, 245)
+	}
+
+	x := pdfMargin
+	for i, width := range widths {
+		if i < len(cells) {
+			cell := d.truncate(cells[i], header, width-2*pdfCellPad)
+			d.textAt(x+pdfCellPad, d.y+pdfRowHeight-4, pdfFontSize, header, cell)
+		}
+		x += width
+	}
+	d.y += pdfRowHeight
+}
+
+// chart writes a titled horizontal bar chart scaled to its largest value
+func (d *pdfDocument) chart(chart reportChart) {
+	d.heading(chart.Title)
+
+	var max time.Duration
+	for _, bar := range chart.Bars {
+		if bar.Value > max {
+			max = bar.Value
+		}
+	}
+
+	barSpace := pdfPageWidth - 2*pdfMargin - pdfChartLabel - pdfChartValue
+	for _, bar := range chart.Bars {
+		d.ensureSpace(pdfRowHeight)
+		width := 1.0
+		if max > 0 {
+			width = float64(bar.Value) / float64(max) * barSpace
+		}
+		if width < 1 {
+			width = 1
+		}
+		baseline := d.y + pdfRowHeight - 4
+		d.textAt(pdfMargin, baseline, pdfFontSize, false, d.truncate(bar.Label, false, pdfChartLabel-pdfCellPad))
+		d.rect(pdfMargin+pdfChartLabel, d.y+2, width, pdfRowHeight-4, 97, 161, 235)
+		d.textAt(pdfMargin+pdfChartLabel+width+pdfCellPad, baseline, pdfFontSize, false, bar.Value.Round(10*time.Microsecond).String())
+		d.y += pdfRowHeight
+	}
+}
+
+// bytes numbers the pages in their footers and writes the PDF file
+func (d *pdfDocument) bytes() ([]byte, error) {
+	pages := d.pdf.PageCount()
+	for i := 1; i <= pages; i++ {
+		d.pdf.SetPage(i)
+		footer := fmt.Sprintf("Page %d of %d", i, pages)
+		d.textAt(pdfPageWidth-pdfMargin-d.textWidth(footer, false, 8), pdfPageHeight-pdfMargin/2, 8, false, footer)
+	}
+
+	var out bytes.Buffer
+	if err := d.pdf.Output(&out); err != nil {
+		return nil, fmt.Errorf("failed to write PDF report: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// columnWidths sizes columns to their content, shrinking wide columns to fit available
+func (d *pdfDocument) columnWidths(table reportTable, available float64) []float64 {
+	widths := make([]float64, len(table.Headers))
+	for i, header := range table.Headers {
+		widths[i] = d.textWidth(header, true, pdfFontSize) + 2*pdfCellPad
+	}
+	for _, row := range table.Rows {
+		for i := 0; i < len(row) && i < len(widths); i++ {
+			if width := d.textWidth(row[i], false, pdfFontSize) + 2*pdfCellPad; width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	total := sumWidths(widths)
+	if total <= available {
+		return widths
+	}
+
+	// Columns narrower than an even share keep their width and the rest
+	// split the remaining space in proportion to their content
+	share := available / float64(len(widths))
+	narrow, wide := 0.0, 0.0
+	for _, width := range widths {
+		if width <= share {
+			narrow += width
+		} else {
+			wide += width
+		}
+	}
+	for i, width := range widths {
+		if width > share {
+			widths[i] = width / wide * (available - narrow)
+		}
+	}
+	return widths
+}
+
+// sumWidths returns the total of column widths
+func sumWidths(widths []float64) float64 {
+	total := 0.0
+	for _, width := range widths {
+		total += width
+	}
+	return total
+}
+
+// textWidth returns the width of value in points
+func (d *pdfDocument) textWidth(value string, bold bool, size float64) float64 {
+	width := 0.0
+	for _, run := range pdfRuns(value, d.fallback) {
+		d.setFont(run.fallback, bold, size)
+		width += d.pdf.GetStringWidth(run.text)
+	}
+	return width
+}
+
+// truncate shortens value with an ellipsis so it fits width
+func (d *pdfDocument) truncate(value string, bold bool, width float64) string {
+	if d.textWidth(value, bold, pdfFontSize) <= width {
+		return value
+	}
+	runes := []rune(value)
+	for len(runes) > 0 && d.textWidth(string(runes)+"…", bold, pdfFontSize) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// pdfRun is a piece of text printed in one font
+type pdfRun struct {
+	text     string
+	fallback bool
+}
+
+// pdfRuns splits value into runs the Go fonts have glyphs for and runs
+// printed with the fallback font. Without a fallback font everything is
+// printed with the Go fonts, which show missing glyphs as empty boxes.
+func pdfRuns(value string, fallback bool) []pdfRun {
+	value = strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' {
+			return ' '
+		}
+		return r
+	}, value)
+	if !fallback || pdfGlyphs == nil {
+		return []pdfRun{{text: value}}
+	}
+
+	var runs []pdfRun
+	var buf sfnt.Buffer
+	for _, r := range value {
+		index, err := pdfGlyphs.GlyphIndex(&buf, r)
+		missing := err == nil && index == 0
+		if n := len(runs); n > 0 && runs[n-1].fallback == missing {
+			runs[n-1].text += string(r)
+		} else {
+			runs = append(runs, pdfRun{text: string(r), fallback: missing})
+		}
+	}
+	return runs
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pdfString encodes ASCII text the way it appears in the uncompressed content
+// stream of a page set in the embedded UTF-8 fonts
+func pdfString(text string) string {
+	var buf strings.Builder
+	for _, c := range []byte(text) {
+		buf.WriteByte(0)
+		if c == '\\' || c == '(' || c == ')' {
+			buf.WriteByte('\\')
+		}
+		buf.WriteByte(c)
+	}
+	return buf.String()
+}
+
+// uncompressedPDF lays out the report of result without compressing the pages
+func uncompressedPDF(t *testing.T, result *BaseResult) (string, int) {
+	t.Helper()
+
+	doc := newPDFDocument()
+	doc.pdf.SetCompression(false)
+	doc.report(result.metadata, result.data)
+	out, err := doc.bytes()
+	require.NoError(t, err)
+	return string(out), doc.pdf.PageCount()
+}
+
+func TestBaseResultExportPDF(t *testing.T) {
+	pingResults := []PingResult{
+		{Host: NetworkHost{Hostname: "example.com"}, Sequence: 1, RTT: 10 * time.Millisecond, TTL: 56},
+		{Host: NetworkHost{Hostname: "example.com"}, Sequence: 2, Error: fmt.Errorf("timeout (no reply)")},
+	}
+
+	result := NewResult(pingResults)
+	result.SetMetadata("tool", "ping")
+
+	exported, err := result.Export(ExportFormatPDF)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(exported, []byte("%PDF-")))
+	assert.Contains(t, string(exported), "/FontFile2", "the TrueType font is embedded")
+
+	pdf, pages := uncompressedPDF(t, result)
+	require.Equal(t, 1, pages)
+	for _, text := range []string{"NetTraceX ping Report", "Summary", "Round-trip time", "Replies", "timeout (no reply)", "Page 1 of 1"} {
+		assert.Contains(t, pdf, "("+pdfString(text)+") Tj", text)
+	}
+}
+
+func TestBaseResultExportPDF_Pagination(t *testing.T) {
+	var hosts []SweepHost
+	for i := 0; i < 150; i++ {
+		hosts = append(hosts, SweepHost{Hostname: strings.Repeat("very-long-hostname-", 10), RTT: time.Duration(i+1) * time.Millisecond})
+	}
+
+	pdf, pages := uncompressedPDF(t, NewResult(SweepResult{CIDR: "10.0.0.0/24", Scanned: 254, Hosts: hosts}))
+	require.Greater(t, pages, 2)
+	assert.Contains(t, pdf, pdfString(fmt.Sprintf("Page %d of %d", pages, pages)))
+
+	// The header row is repeated on pages the table continues on and wide cells are truncated
+	assert.Greater(t, strings.Count(pdf, "("+pdfString("Hostname")+") Tj"), 1)
+	assert.Contains(t, pdf, "\x20\x26) Tj", "truncated cells end in an ellipsis")
+}
+
+func TestBaseResultExportPDF_NonLatin(t *testing.T) {
+	result := NewResult([]PingResult{{Host: NetworkHost{Hostname: "ドメイン.jp"}, Sequence: 1, RTT: time.Millisecond}})
+	exported, err := result.Export(ExportFormatPDF)
+	require.NoError(t, err)
+	assert.NotContains(t, string(exported), "?.jp")
+}
+
+func TestPDFRuns(t *testing.T) {
+	loadPDFFonts()
+
+	assert.Equal(t, []pdfRun{{text: "Москва café"}}, pdfRuns("Москва\tcafé", true))
+	assert.Equal(t, []pdfRun{{text: "ドメイン", fallback: true}, {text: ".jp"}}, pdfRuns("ドメイン.jp", true))
+	assert.Equal(t, []pdfRun{{text: "ドメイン.jp"}}, pdfRuns("ドメイン.jp", false))
+}
+
+func TestPDFColumnWidths(t *testing.T) {
+	table := reportTable{
+		Headers: []string{"Seq", "Value"},
+		Rows:    [][]string{{"1", strings.Repeat("x", 400)}},
+	}
+
+	widths := newPDFDocument().columnWidths(table, 400)
+	require.Len(t, widths, 2)
+	assert.InDelta(t, 400, sumWidths(widths), 0.01)
+	assert.Less(t, widths[0], 40.0)
+}
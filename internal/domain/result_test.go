@@ -2,12 +2,14 @@ package domain
 
 import (
 	"encoding/json"
+	"fmt"
 	"net"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // MockOutputFormatter for testing
@@ -334,6 +336,158 @@ func TestBaseResultExportSSLResult(t *testing.T) {
 	assert.Contains(t, exportedStr, "Expires: 2025-12-31T23:59:59Z")
 }
 
+func TestBaseResultExportSweepResult(t *testing.T) {
+	sweepResult := SweepResult{
+		CIDR:    "192.168.1.0/30",
+		Scanned: 2,
+		Hosts: []SweepHost{
+			{IP: net.ParseIP("192.168.1.1"), Hostname: "router.lan", MAC: "b8:27:eb:12:34:56", Vendor: "Raspberry Pi", RTT: 1500 * time.Microsecond},
+			{IP: net.ParseIP("192.168.1.2"), RTT: 3 * time.Millisecond},
+		},
+	}
+
+	result := NewResult(sweepResult)
+
+	// Test CSV inventory export
+	exported, err := result.Export(ExportFormatCSV)
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(exported)), "\n")
+	assert.Equal(t, []string{
+		"ip,hostname,mac,vendor,rtt_ms",
+		"192.168.1.1,router.lan,b8:27:eb:12:34:56,Raspberry Pi,1.500",
+		"192.168.1.2,,,,3.000",
+	}, lines)
+
+	// Test text export
+	exported, err = result.Export(ExportFormatText)
+	assert.NoError(t, err)
+
+	exportedStr := string(exported)
+	assert.Contains(t, exportedStr, "Sweep 192.168.1.0/30: 2 of 2 addresses alive")
+	assert.Contains(t, exportedStr, "192.168.1.2 - - - 3ms")
+}
+
+func TestBaseResultExportCSV_PerToolSchemas(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     interface{}
+		expected []string
+	}{
+		{
+			name: "multi ping",
+			data: MultiPingResult{Targets: []PingTargetResult{
+				{Host: "a.example.com", PacketsSent: 4, PacketsReceived: 3, PacketLoss: 25, AvgRTT: 12 * time.Millisecond},
+				{Host: "b.example.com", Error: fmt.Errorf("unknown host")},
+			}},
+			expected: []string{
+				"host,packets_sent,packets_received,packet_loss_percent,avg_rtt_ms,error",
+				"a.example.com,4,3,25.0,12.000,",
+				"b.example.com,0,0,0.0,0.000,unknown host",
+			},
+		},
+		{
+			name: "dual stack",
+			data: DualStackResult{
+				Host:   "example.com",
+				Port:   443,
+				IPv4:   DualStackFamilyResult{Family: "ipv4", Addresses: []string{"93.184.216.34"}, Connected: "93.184.216.34", ConnectTime: 20 * time.Millisecond, Success: true},
+				IPv6:   DualStackFamilyResult{Family: "ipv6", Error: "no AAAA records"},
+				Winner: "ipv4",
+			},
+			expected: []string{
+				"host,port,family,addresses,connected,connect_ms,success,winner,error",
+				"example.com,443,ipv4,93.184.216.34,93.184.216.34,20.000,true,true,",
+				"example.com,443,ipv6,,,0.000,false,false,no AAAA records",
+			},
+		},
+		{
+			name: "zone transfer",
+			data: ZoneTransferResult{Domain: "example.com", Servers: []ZoneTransferServer{
+				{Nameserver: "ns1.example.com", Address: "192.0.2.1", Allowed: true, Records: 42, Duration: 30 * time.Millisecond},
+				{Nameserver: "ns2.example.com", Address: "192.0.2.2", Refused: true, Error: "transfer refused"},
+			}},
+			expected: []string{
+				"domain,nameserver,address,allowed,refused,records,duration_ms,error",
+				"example.com,ns1.example.com,192.0.2.1,true,false,42,30.000,",
+				"example.com,ns2.example.com,192.0.2.2,false,true,0,0.000,transfer refused",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exported, err := NewResult(tt.data).Export(ExportFormatCSV)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, strings.Split(strings.TrimSpace(string(exported)), "\n"))
+		})
+	}
+}
+
+func TestBaseResultExportCSV_OptionalFields(t *testing.T) {
+	// Failed pings carry their error, and unresolved hops have no address
+	exported, err := NewResult([]PingResult{{Host: NetworkHost{Hostname: "example.com"}, Sequence: 1, Error: fmt.Errorf("timeout")}}).Export(ExportFormatCSV)
+	assert.NoError(t, err)
+	assert.Contains(t, string(exported), "example.com,1,0.000,0,0,timeout")
+
+	exported, err = NewResult([]TraceHop{{Number: 3, Timeout: true}}).Export(ExportFormatCSV)
+	assert.NoError(t, err)
+	assert.Contains(t, string(exported), "3,,,,,,true")
+
+	// WHOIS status and contacts are exported as key/value rows, unknown dates stay empty
+	exported, err = NewResult(WHOISResult{
+		Domain:   "example.com",
+		Status:   []string{"clientTransferProhibited"},
+		Contacts: map[string]Contact{"registrant": {Name: "Jane Doe", Email: "jane@example.com"}},
+	}).Export(ExportFormatCSV)
+	assert.NoError(t, err)
+	exportedStr := string(exported)
+	assert.Contains(t, exportedStr, "created,\n")
+	assert.Contains(t, exportedStr, "status,clientTransferProhibited")
+	assert.Contains(t, exportedStr, "registrant_name,Jane Doe")
+	assert.Contains(t, exportedStr, "registrant_email,jane@example.com")
+	assert.NotContains(t, exportedStr, "registrant_phone")
+
+	exported, err = NewResult(SSLResult{Host: "example.com", Port: 443, Errors: []string{"certificate has expired"}}).Export(ExportFormatCSV)
+	assert.NoError(t, err)
+	assert.Contains(t, string(exported), "error,certificate has expired")
+}
+
+func TestBaseResultExportBatchResult(t *testing.T) {
+	batchResult := BatchResult{
+		Tool: "whois",
+		Targets: []BatchTargetResult{
+			{Target: "example.com", Data: WHOISResult{Domain: "example.com", Registrar: "Example Registrar"}, Duration: 250 * time.Millisecond},
+			{Target: "invalid..com", Error: "query must be a valid domain", Duration: time.Millisecond},
+		},
+	}
+
+	assert.Equal(t, 1, batchResult.Failed())
+
+	result := NewResult(batchResult)
+
+	// Test CSV summary export
+	exported, err := result.Export(ExportFormatCSV)
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(exported)), "\n")
+	assert.Equal(t, []string{
+		"target,status,duration_ms,error",
+		"example.com,ok,250.000,",
+		"invalid..com,failed,1.000,query must be a valid domain",
+	}, lines)
+
+	// Test text export includes each target's own data
+	exported, err = result.Export(ExportFormatText)
+	assert.NoError(t, err)
+
+	exportedStr := string(exported)
+	assert.Contains(t, exportedStr, "Batch whois: 2 targets, 1 failed")
+	assert.Contains(t, exportedStr, "--- example.com (250ms) ---")
+	assert.Contains(t, exportedStr, "Registrar: Example Registrar")
+	assert.Contains(t, exportedStr, "Error: query must be a valid domain")
+}
+
 func TestBaseResultExportUnsupportedFormat(t *testing.T) {
 	result := NewResult("test data")
 	
@@ -376,6 +530,137 @@ func TestBaseResultExportUnknownDataType(t *testing.T) {
 	assert.Contains(t, exportedStr, "Field2:42")
 }
 
+func TestBaseResultExportHTML(t *testing.T) {
+	pingResults := []PingResult{
+		{Host: NetworkHost{Hostname: "example.com"}, Sequence: 1, RTT: 10 * time.Millisecond, TTL: 56},
+		{Host: NetworkHost{Hostname: "example.com"}, Sequence: 2, Error: fmt.Errorf("timeout")},
+		{Host: NetworkHost{Hostname: "example.com"}, Sequence: 3, RTT: 20 * time.Millisecond, TTL: 56},
+	}
+
+	result := NewResult(pingResults)
+	result.SetMetadata("tool", "ping")
+	result.SetMetadata("host", "<script>alert(1)</script>")
+
+	exported, err := result.Export(ExportFormatHTML)
+	assert.NoError(t, err)
+
+	exportedStr := string(exported)
+	assert.True(t, strings.HasPrefix(exportedStr, "<!DOCTYPE html>"))
+	assert.Contains(t, exportedStr, "<title>NetTraceX ping Report</title>")
+	assert.Contains(t, exportedStr, "<h2>Replies</h2>")
+	assert.Contains(t, exportedStr, "<td>10.000</td>")
+	assert.Contains(t, exportedStr, "<td>timeout</td>")
+
+	// Successful replies are charted, scaled to the slowest one
+	assert.Contains(t, exportedStr, "<h2>Round-trip time</h2>")
+	assert.Equal(t, 2, strings.Count(exportedStr, "<rect "))
+	assert.Contains(t, exportedStr, `width="370"`)
+	assert.Contains(t, exportedStr, `width="185"`)
+
+	// Metadata is escaped
+	assert.NotContains(t, exportedStr, "<script>")
+	assert.Contains(t, exportedStr, "&lt;script&gt;")
+}
+
+func TestBaseResultExportHTML_AllTypes(t *testing.T) {
+	results := []interface{}{
+		[]TraceHop{{Number: 1, Host: NetworkHost{IPAddress: net.ParseIP("192.168.1.1")}, RTT: []time.Duration{time.Millisecond}}},
+		DNSResult{Query: "example.com", Records: []DNSRecord{{Name: "example.com", Type: DNSRecordTypeMX, Value: "mail.example.com", Priority: 10}}},
+		WHOISResult{Domain: "example.com", NameServers: []string{"ns1.example.com"}},
+		SSLResult{Host: "example.com", Port: 443, SANs: []string{"www.example.com"}},
+		DualStackResult{Host: "example.com", Port: 443, IPv4: DualStackFamilyResult{Family: "ipv4", Success: true, ConnectTime: time.Millisecond}},
+		SweepResult{CIDR: "10.0.0.0/30", Hosts: []SweepHost{{IP: net.ParseIP("10.0.0.1"), RTT: time.Millisecond}}},
+		ZoneTransferResult{Domain: "example.com", Servers: []ZoneTransferServer{{Nameserver: "ns1.example.com", Refused: true}}},
+		MultiPingResult{Targets: []PingTargetResult{{Host: "a.example.com", PacketsSent: 2, PacketsReceived: 2, AvgRTT: time.Millisecond}}},
+		"plain data",
+	}
+
+	for _, data := range results {
+		exported, err := NewResult(data).Export(ExportFormatHTML)
+		assert.NoError(t, err, "%T", data)
+		assert.Contains(t, string(exported), "</html>", "%T", data)
+	}
+}
+
+func TestBaseResultExportMarkdown(t *testing.T) {
+	result := NewResult(DNSResult{
+		Query: "example.com",
+		Records: []DNSRecord{
+			{Name: "example.com", Type: DNSRecordTypeA, Value: "93.184.216.34", TTL: 300},
+			{Name: "example.com", Type: DNSRecordTypeTXT, Value: "v=spf1 | -all", TTL: 300},
+		},
+	})
+	result.SetMetadata("tool", "dns")
+
+	exported, err := result.Export(ExportFormatMarkdown)
+	assert.NoError(t, err)
+
+	exportedStr := string(exported)
+	assert.True(t, strings.HasPrefix(exportedStr, "# NetTraceX dns Report\n"))
+	assert.Contains(t, exportedStr, "## Summary\n\n| Field | Value |\n| --- | --- |\n| tool | dns |\n")
+	assert.Contains(t, exportedStr, "## Records for example.com\n\n| Name | Type | Value | TTL | Priority |\n| --- | --- | --- | --- | --- |\n")
+	assert.Contains(t, exportedStr, "| example.com | A | 93.184.216.34 | 300 | 0 |\n")
+
+	// Pipes inside cells are escaped so the table stays intact
+	assert.Contains(t, exportedStr, "| v=spf1 \\| -all |")
+
+	// Empty sections are marked rather than rendered as empty tables
+	exported, err = NewResult(ZoneTransferResult{Domain: "example.com"}).Export(ExportFormatMarkdown)
+	assert.NoError(t, err)
+	assert.Contains(t, string(exported), "## Zone transfers for example.com\n\n_No entries_\n")
+}
+
+func TestBaseResultExportJUnit_Batch(t *testing.T) {
+	result := NewResult(BatchResult{
+		Tool:     "ping",
+		Duration: 1500 * time.Millisecond,
+		Targets: []BatchTargetResult{
+			{Target: "10.0.0.1", Duration: time.Second},
+			{Target: "10.0.0.2", Error: "host unreachable", Duration: 500 * time.Millisecond},
+		},
+	})
+
+	exported, err := result.Export(ExportFormatJUnit)
+	require.NoError(t, err)
+
+	exportedStr := string(exported)
+	assert.True(t, strings.HasPrefix(exportedStr, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<testsuites"))
+	assert.Contains(t, exportedStr, `<testsuite name="batch ping" tests="2" failures="1" errors="0" skipped="0" time="1.500">`)
+	assert.Contains(t, exportedStr, `<testcase name="10.0.0.1" classname="ping" time="1.000"></testcase>`)
+	assert.Contains(t, exportedStr, `<failure message="host unreachable" type="ToolFailure"></failure>`)
+}
+
+func TestBaseResultExportJUnit_Scenario(t *testing.T) {
+	result := NewResult(ScenarioResult{
+		Name:      "edge <checks>",
+		Timestamp: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Steps: []ScenarioStepResult{
+			{Name: "gateway", Tool: "ping", Passed: true, Assertions: []ScenarioAssertionResult{{Expression: "packet_loss == 0", Actual: "0", Passed: true}}},
+			{Name: "latency", Tool: "ping", Assertions: []ScenarioAssertionResult{
+				{Expression: "avg_rtt < 50ms", Actual: "80ms"},
+				{Expression: "packet_loss < 5%", Actual: "0", Passed: true},
+			}},
+			{Name: "cert", Tool: "ssl", Error: "connection refused"},
+			{Name: "dns", Tool: "dns", Skipped: true, Error: "skipped after failure"},
+		},
+	})
+
+	exported, err := result.Export(ExportFormatJUnit)
+	require.NoError(t, err)
+
+	exportedStr := string(exported)
+	assert.Contains(t, exportedStr, `<testsuites name="edge &lt;checks&gt;" tests="4" failures="1" errors="1" skipped="1"`)
+	assert.Contains(t, exportedStr, `timestamp="2024-05-01T12:00:00"`)
+	assert.Contains(t, exportedStr, `<failure message="1 of 2 assertions failed" type="AssertionFailure">avg_rtt &lt; 50ms</failure>`)
+	assert.Contains(t, exportedStr, `<system-out>[FAIL] avg_rtt &lt; 50ms (actual: 80ms)`)
+	assert.Contains(t, exportedStr, `<error message="connection refused" type="ToolError"></error>`)
+	assert.Contains(t, exportedStr, `<skipped message="skipped after failure"></skipped>`)
+
+	// Other results have no notion of pass/fail
+	_, err = NewResult(DNSResult{Query: "example.com"}).Export(ExportFormatJUnit)
+	assert.Error(t, err)
+}
+
 func TestResultInterfaceCompliance(t *testing.T) {
 	// Test that BaseResult implements the Result interface
 	var _ Result = (*BaseResult)(nil)
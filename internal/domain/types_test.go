@@ -1,11 +1,14 @@
 package domain
 
 import (
+	"errors"
+	"fmt"
 	"net"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNetworkHost(t *testing.T) {
@@ -35,6 +38,14 @@ func TestNetworkHost(t *testing.T) {
 	assert.Equal(t, 12345, host.ASN.Number)
 	assert.NotNil(t, host.Geographic)
 	assert.Equal(t, "San Francisco", host.Geographic.City)
+	assert.Equal(t, "AS12345 Example ASN", host.ASN.String())
+}
+
+func TestASNInfoString(t *testing.T) {
+	var missing *ASNInfo
+	assert.Equal(t, "", missing.String())
+	assert.Equal(t, "", (&ASNInfo{}).String())
+	assert.Equal(t, "AS64500", (&ASNInfo{Number: 64500}).String())
 }
 
 func TestPingOptions(t *testing.T) {
@@ -79,6 +90,46 @@ func TestPingResult(t *testing.T) {
 	assert.Nil(t, result.Error)
 }
 
+func TestInferTTLHint(t *testing.T) {
+	tests := []struct {
+		ttl      int
+		expected TTLHint
+		ok       bool
+	}{
+		{ttl: 64, expected: TTLHint{InitialTTL: 64, Hops: 0, Family: "Linux/Unix"}, ok: true},
+		{ttl: 57, expected: TTLHint{InitialTTL: 64, Hops: 7, Family: "Linux/Unix"}, ok: true},
+		{ttl: 117, expected: TTLHint{InitialTTL: 128, Hops: 11, Family: "Windows"}, ok: true},
+		{ttl: 249, expected: TTLHint{InitialTTL: 255, Hops: 6, Family: "Network device"}, ok: true},
+		{ttl: 0, ok: false},
+		{ttl: 300, ok: false},
+	}
+
+	for _, tt := range tests {
+		hint, ok := InferTTLHint(tt.ttl)
+		assert.Equal(t, tt.ok, ok, "ttl %d", tt.ttl)
+		assert.Equal(t, tt.expected, hint, "ttl %d", tt.ttl)
+	}
+
+	hint, _ := InferTTLHint(127)
+	assert.Equal(t, "Windows (initial TTL 128, 1 hop)", hint.String())
+}
+
+func TestPingTTLHint(t *testing.T) {
+	results := []PingResult{
+		{Sequence: 1, TTL: 57},
+		{Sequence: 2, TTL: 56},
+		{Sequence: 3, TTL: 56},
+		{Sequence: 4, TTL: 120, Error: assert.AnError},
+	}
+
+	hint, ok := PingTTLHint(results)
+	assert.True(t, ok)
+	assert.Equal(t, 8, hint.Hops)
+
+	_, ok = PingTTLHint([]PingResult{{Sequence: 1, Error: assert.AnError}})
+	assert.False(t, ok)
+}
+
 func TestTraceHop(t *testing.T) {
 	now := time.Now()
 	hop := TraceHop{
@@ -267,6 +318,19 @@ func TestConfig(t *testing.T) {
 	assert.Equal(t, "info", config.Logging.Level)
 }
 
+func TestKeyConfigActions(t *testing.T) {
+	actions := KeyConfig{Down: []string{"ctrl+n"}}.Actions()
+	require.Len(t, actions, len(DefaultKeyConfig().Actions()))
+
+	keys := make(map[string][]string)
+	for _, action := range actions {
+		keys[action.Name] = action.Keys
+	}
+	assert.Equal(t, []string{"ctrl+n"}, keys["down"])
+	assert.Equal(t, []string{"up", "k"}, keys["up"], "empty actions keep their defaults")
+	assert.Equal(t, []string{"ctrl+p"}, keys["palette"])
+}
+
 func TestNetTraceError(t *testing.T) {
 	now := time.Now()
 	err := &NetTraceError{
@@ -300,10 +364,33 @@ func TestNetTraceErrorWithCause(t *testing.T) {
 	assert.Contains(t, err.Error(), cause.Error())
 }
 
+func TestNetTraceErrorKind(t *testing.T) {
+	err := fmt.Errorf("dns tool: %w", &NetTraceError{
+		Type:    ErrorTypeNetwork,
+		Message: "DNS lookup failed",
+		Code:    "DNS_LOOKUP_FAILED",
+		Kind:    ErrorKindDNSNXDomain,
+	})
+
+	assert.True(t, errors.Is(err, ErrDNSNXDomain))
+	assert.False(t, errors.Is(err, ErrTimeout))
+	assert.Equal(t, ErrorKindDNSNXDomain, ErrorKindOf(err))
+	assert.Equal(t, ErrorKindDNSNXDomain.Hint(), RemediationHint(err))
+
+	withHint := &NetTraceError{Message: "ICMP socket refused", Kind: ErrorKindPermissionDenied, Hint: "Run as root"}
+	assert.Equal(t, "Run as root", RemediationHint(withHint))
+	assert.Empty(t, RemediationHint(assert.AnError))
+	assert.Empty(t, ErrorKindOf(nil))
+}
+
 func TestExportFormat(t *testing.T) {
 	assert.Equal(t, ExportFormat(0), ExportFormatJSON)
 	assert.Equal(t, ExportFormat(1), ExportFormatCSV)
 	assert.Equal(t, ExportFormat(2), ExportFormatText)
+	assert.Equal(t, ExportFormat(3), ExportFormatHTML)
+	assert.Equal(t, ExportFormat(4), ExportFormatMarkdown)
+	assert.Equal(t, ExportFormat(5), ExportFormatPDF)
+	assert.Equal(t, ExportFormat(6), ExportFormatJUnit)
 }
 
 func TestErrorType(t *testing.T) {
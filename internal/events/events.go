@@ -0,0 +1,120 @@
+// Package events publishes what happens to diagnostic tools, such as a run
+// starting, a result arriving, a run failing or a report being exported, to
+// the subscribers of a Bus. Audit logs, notifications and plugins subscribe
+// to the bus instead of each tool reporting to them.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Kind identifies what an event reports
+type Kind string
+
+const (
+	KindToolStarted    Kind = "tool.started"
+	KindResultReceived Kind = "tool.result"
+	KindToolFailed     Kind = "tool.error"
+	KindResultExported Kind = "result.exported"
+)
+
+// Kinds lists every event kind in the order they occur
+var Kinds = []Kind{KindToolStarted, KindResultReceived, KindToolFailed, KindResultExported}
+
+// Event is one thing that happened to a tool. Duration is set once a run
+// has finished, Err when it failed and Path when a report was exported.
+type Event struct {
+	Kind     Kind
+	Tool     string
+	Target   string
+	Params   map[string]interface{}
+	Result   interface{}
+	Err      error
+	Path     string
+	Duration time.Duration
+	Time     time.Time
+}
+
+// Handler receives the events a subscriber asked for
+type Handler func(Event)
+
+// subscription is a handler and the kinds it receives; no kinds means all
+type subscription struct {
+	id      int
+	handler Handler
+	kinds   map[Kind]bool
+}
+
+// Bus delivers published events to its subscribers. Handlers run in the
+// order they subscribed, on the goroutine that publishes, so a handler
+// that does slow work should hand it off.
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions []subscription
+	next          int
+}
+
+// NewBus creates a bus without subscribers
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe calls handler for every event of the given kinds, or of every
+// kind when none are given. The returned function cancels the subscription.
+func (b *Bus) Subscribe(handler Handler, kinds ...Kind) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := subscription{id: b.next, handler: handler}
+	b.next++
+	if len(kinds) > 0 {
+		sub.kinds = make(map[Kind]bool, len(kinds))
+		for _, kind := range kinds {
+			sub.kinds[kind] = true
+		}
+	}
+	b.subscriptions = append(b.subscriptions, sub)
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for i, s := range b.subscriptions {
+			if s.id == sub.id {
+				b.subscriptions = append(b.subscriptions[:i:i], b.subscriptions[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Publish delivers event to the subscribers of its kind, stamping its time
+// if unset. A nil bus drops the event.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	b.mu.RLock()
+	subscriptions := b.subscriptions
+	b.mu.RUnlock()
+
+	for _, sub := range subscriptions {
+		if sub.kinds == nil || sub.kinds[event.Kind] {
+			sub.handler(event)
+		}
+	}
+}
+
+// ParseKind returns the kind named s
+func ParseKind(s string) (Kind, bool) {
+	for _, kind := range Kinds {
+		if string(kind) == s {
+			return kind, true
+		}
+	}
+	return "", false
+}
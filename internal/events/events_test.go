@@ -0,0 +1,142 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubTool returns a fixed result or error
+type stubTool struct {
+	err error
+}
+
+func (t *stubTool) Name() string                            { return "ping" }
+func (t *stubTool) Description() string                     { return "stub tool" }
+func (t *stubTool) Validate(params domain.Parameters) error { return nil }
+func (t *stubTool) GetModel() tea.Model                     { return nil }
+
+func (t *stubTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	return domain.NewResult("pong"), nil
+}
+
+// record subscribes to kinds on bus and returns the events received
+func record(bus *Bus, kinds ...Kind) *[]Event {
+	var received []Event
+	bus.Subscribe(func(event Event) { received = append(received, event) }, kinds...)
+	return &received
+}
+
+func TestBusSubscribe(t *testing.T) {
+	bus := NewBus()
+	all := record(bus)
+	exports := record(bus, KindResultExported)
+	var cancelled []Event
+	cancel := bus.Subscribe(func(event Event) { cancelled = append(cancelled, event) })
+
+	bus.Publish(Event{Kind: KindToolStarted, Tool: "dns"})
+	cancel()
+	bus.Publish(Event{Kind: KindResultExported, Tool: "dns", Path: "/tmp/dns.json"})
+
+	require.Len(t, *all, 2)
+	assert.False(t, (*all)[0].Time.IsZero(), "Publish stamps the time")
+	require.Len(t, *exports, 1)
+	assert.Equal(t, "/tmp/dns.json", (*exports)[0].Path)
+	assert.Len(t, cancelled, 1)
+
+	var nilBus *Bus
+	nilBus.Publish(Event{Kind: KindToolStarted})
+}
+
+func TestObserve(t *testing.T) {
+	bus := NewBus()
+	received := record(bus)
+
+	params := domain.NewParameters()
+	params.Set("host", "example.com")
+	params.Set("count", 4)
+	tool := Observe(bus, &stubTool{})
+	_, err := tool.Execute(context.Background(), params)
+	require.NoError(t, err)
+
+	require.Len(t, *received, 2)
+	started, finished := (*received)[0], (*received)[1]
+	assert.Equal(t, KindToolStarted, started.Kind)
+	assert.Equal(t, "ping", started.Tool)
+	assert.Equal(t, "example.com", started.Target)
+	assert.Equal(t, 4, started.Params["count"])
+	assert.Equal(t, KindResultReceived, finished.Kind)
+	assert.Equal(t, "pong", finished.Result)
+	assert.False(t, finished.Time.Before(started.Time))
+
+	unreachable := errors.New("unreachable")
+	*received = nil
+	_, err = Observe(bus, &stubTool{err: unreachable}).Execute(context.Background(), params)
+	assert.Equal(t, unreachable, err)
+	require.Len(t, *received, 2)
+	assert.Equal(t, KindToolFailed, (*received)[1].Kind)
+	assert.Equal(t, unreachable, (*received)[1].Err)
+
+	assert.Equal(t, &stubTool{}, tool.(*ObservedTool).Unwrap())
+}
+
+// liveStubTool runs on a live screen
+type liveStubTool struct {
+	stubTool
+}
+
+func (t *liveStubTool) LiveModel(ctx context.Context, params domain.Parameters) (tea.Model, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	return liveScreen{}, nil
+}
+
+// liveScreen is the screen of liveStubTool
+type liveScreen struct{}
+
+func (s liveScreen) Init() tea.Cmd                           { return nil }
+func (s liveScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) { return s, nil }
+func (s liveScreen) View() string                            { return "live" }
+
+func TestObserve_LiveModel(t *testing.T) {
+	bus := NewBus()
+	received := record(bus)
+
+	params := domain.NewParameters()
+	params.Set("host", "example.com")
+
+	// Tools without a live screen are run through Execute and publish then
+	model, err := domain.LiveModel(context.Background(), Observe(bus, &stubTool{}), params)
+	assert.Nil(t, model)
+	assert.NoError(t, err)
+	assert.Empty(t, *received)
+
+	denied := errors.New("denied")
+	_, err = domain.LiveModel(context.Background(), Observe(bus, &liveStubTool{stubTool{err: denied}}), params)
+	assert.Equal(t, denied, err)
+	assert.Empty(t, *received, "a screen that does not open has not started")
+
+	model, err = domain.LiveModel(context.Background(), Observe(bus, &liveStubTool{}), params)
+	require.NoError(t, err)
+	assert.NotNil(t, model)
+	require.Len(t, *received, 1)
+	assert.Equal(t, KindToolStarted, (*received)[0].Kind)
+	assert.Equal(t, "example.com", (*received)[0].Target)
+}
+
+func TestParseKind(t *testing.T) {
+	kind, ok := ParseKind("tool.error")
+	assert.True(t, ok)
+	assert.Equal(t, KindToolFailed, kind)
+	_, ok = ParseKind("tool.everything")
+	assert.False(t, ok)
+}
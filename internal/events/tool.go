@@ -0,0 +1,68 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// ObservedTool wraps a diagnostic tool and publishes the start and the
+// outcome of every execution on a bus
+type ObservedTool struct {
+	domain.DiagnosticTool
+	bus *Bus
+}
+
+// Observe wraps tool so its executions are published on bus
+func Observe(bus *Bus, tool domain.DiagnosticTool) domain.DiagnosticTool {
+	return &ObservedTool{DiagnosticTool: tool, bus: bus}
+}
+
+// Unwrap returns the wrapped diagnostic tool
+func (t *ObservedTool) Unwrap() domain.DiagnosticTool {
+	return t.DiagnosticTool
+}
+
+// Execute runs the wrapped tool between a started event and a result or
+// error event
+func (t *ObservedTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	values := params.ToMap()
+	started := Event{Kind: KindToolStarted, Tool: t.Name(), Target: domain.TargetParam(params), Params: values}
+	t.bus.Publish(started)
+
+	start := time.Now()
+	result, err := t.DiagnosticTool.Execute(ctx, params)
+
+	finished := started
+	finished.Kind, finished.Duration, finished.Time = KindResultReceived, time.Since(start), time.Time{}
+	if err != nil {
+		finished.Kind, finished.Err = KindToolFailed, err
+	} else if result != nil {
+		finished.Result = result.Data()
+	}
+	t.bus.Publish(finished)
+	return result, err
+}
+
+// LiveModel returns the live screen of the wrapped tool, publishing a
+// started event once it runs. A live run has no final result to publish.
+func (t *ObservedTool) LiveModel(ctx context.Context, params domain.Parameters) (tea.Model, error) {
+	model, err := domain.LiveModel(ctx, t.DiagnosticTool, params)
+	if err == nil && model != nil {
+		t.bus.Publish(Event{Kind: KindToolStarted, Tool: t.Name(), Target: domain.TargetParam(params), Params: params.ToMap()})
+	}
+	return model, err
+}
+
+// Target returns the value of the first target parameter in params, a map
+// of parameters or result metadata; see domain.TargetParam
+func Target(params map[string]interface{}) string {
+	for _, key := range domain.TargetParamKeys {
+		if value, ok := params[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,403 @@
+// Package geo provides geolocation and ASN lookups for IP addresses using ip-api
+// with a Team Cymru whois fallback for ASN data
+package geo
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/network"
+	"github.com/nettracex/nettracex-tui/internal/proxy"
+)
+
+const (
+	// DefaultIPAPIURL is the ip-api JSON endpoint queried for address details
+	DefaultIPAPIURL = "http://ip-api.com/json/"
+	// DefaultCymruServer is the Team Cymru IP-to-ASN whois service
+	DefaultCymruServer = "whois.cymru.com:43"
+
+	// maxLookupTimeout bounds a single lookup regardless of the network timeout
+	maxLookupTimeout = 5 * time.Second
+
+	ipAPIFields = "status,message,country,countryCode,regionName,city,lat,lon,timezone,isp,org,as,asname"
+)
+
+// ipAPIResponse is the subset of the ip-api response used by the service
+type ipAPIResponse struct {
+	Status      string  `json:"status"`
+	Message     string  `json:"message"`
+	Country     string  `json:"country"`
+	CountryCode string  `json:"countryCode"`
+	Region      string  `json:"regionName"`
+	City        string  `json:"city"`
+	Latitude    float64 `json:"lat"`
+	Longitude   float64 `json:"lon"`
+	Timezone    string  `json:"timezone"`
+	ISP         string  `json:"isp"`
+	Org         string  `json:"org"`
+	AS          string  `json:"as"`
+	ASName      string  `json:"asname"`
+}
+
+// Service implements domain.GeoLocationService. Results are cached per address
+// since traceroutes to the same destination repeat most hops.
+type Service struct {
+	// config and retry are replaced together by Reconfigure
+	config      atomic.Pointer[domain.NetworkConfig]
+	retry       atomic.Pointer[network.RetryManager]
+	logger      domain.Logger
+	httpClient  *http.Client
+	apiURL      string
+	whoisServer string
+
+	mu       sync.Mutex
+	ipAPI    map[string]*ipAPIResponse
+	asnCache map[string]*domain.ASNInfo
+}
+
+// NewService creates a geolocation service using the timeout, user agent and
+// proxies from config
+func NewService(config *domain.NetworkConfig, logger domain.Logger) *Service {
+	s := &Service{
+		logger:      logger,
+		apiURL:      DefaultIPAPIURL,
+		whoisServer: DefaultCymruServer,
+		ipAPI:       make(map[string]*ipAPIResponse),
+		asnCache:    make(map[string]*domain.ASNInfo),
+	}
+	s.httpClient = &http.Client{Transport: proxy.Transport(func() string { return s.proxies().HTTP })}
+	if config != nil {
+		s.Reconfigure(*config)
+	} else {
+		s.retry.Store(network.NewRetryManager(0, 0))
+	}
+	return s
+}
+
+// Reconfigure applies the timeout, user agent, proxies and retries of config
+// to later lookups
+func (s *Service) Reconfigure(config domain.NetworkConfig) {
+	s.retry.Store(network.NewRetryManager(config.RetryAttempts, config.RetryDelay))
+	s.config.Store(&config)
+}
+
+// GetLocation returns the geographic location of ip
+func (s *Service) GetLocation(ip net.IP) (*domain.GeoLocation, error) {
+	info, err := s.lookupIPAPI(ip)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.GeoLocation{
+		Latitude:    info.Latitude,
+		Longitude:   info.Longitude,
+		City:        info.City,
+		Region:      info.Region,
+		Country:     info.Country,
+		CountryCode: info.CountryCode,
+		Timezone:    info.Timezone,
+	}, nil
+}
+
+// GetASNInfo returns the autonomous system announcing ip
+func (s *Service) GetASNInfo(ip net.IP) (*domain.ASNInfo, error) {
+	if err := checkPublic(ip); err != nil {
+		return nil, err
+	}
+
+	key := ip.String()
+	s.mu.Lock()
+	cached, exists := s.asnCache[key]
+	s.mu.Unlock()
+	if exists {
+		return cached, nil
+	}
+
+	asn, err := s.asnFromIPAPI(ip)
+	if err != nil {
+		s.logger.Debug("ip-api ASN lookup failed, trying whois", "ip", key, "error", err)
+		asn, err = s.asnFromWhois(ip)
+		if err != nil {
+			return nil, &domain.NetTraceError{
+				Type:      domain.ErrorTypeNetwork,
+				Message:   "ASN lookup failed",
+				Cause:     err,
+				Context:   map[string]interface{}{"ip": key},
+				Timestamp: time.Now(),
+				Code:      "GEO_ASN_LOOKUP_FAILED",
+			}
+		}
+	}
+
+	s.mu.Lock()
+	s.asnCache[key] = asn
+	s.mu.Unlock()
+	return asn, nil
+}
+
+// GetISPInfo returns the ISP operating ip
+func (s *Service) GetISPInfo(ip net.IP) (*domain.ISPInfo, error) {
+	info, err := s.lookupIPAPI(ip)
+	if err != nil {
+		return nil, err
+	}
+	number, _ := parseASField(info.AS)
+	return &domain.ISPInfo{
+		Name:         info.ISP,
+		Organization: info.Org,
+		ASN:          number,
+		Country:      info.CountryCode,
+	}, nil
+}
+
+// asnFromIPAPI extracts ASN details from the ip-api response for ip
+func (s *Service) asnFromIPAPI(ip net.IP) (*domain.ASNInfo, error) {
+	info, err := s.lookupIPAPI(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	number, description := parseASField(info.AS)
+	if number == 0 {
+		return nil, fmt.Errorf("no ASN reported for %s", ip)
+	}
+
+	name := info.ASName
+	if name == "" {
+		name = description
+	}
+	return &domain.ASNInfo{
+		Number:      number,
+		Name:        name,
+		Description: description,
+		Country:     info.CountryCode,
+	}, nil
+}
+
+// lookupIPAPI queries ip-api for ip, caching successful responses
+func (s *Service) lookupIPAPI(ip net.IP) (*ipAPIResponse, error) {
+	if err := checkPublic(ip); err != nil {
+		return nil, err
+	}
+
+	key := ip.String()
+	s.mu.Lock()
+	cached, exists := s.ipAPI[key]
+	s.mu.Unlock()
+	if exists {
+		return cached, nil
+	}
+
+	result, err := s.retry.Load().ExecuteWithRetry(context.Background(), func() (interface{}, error) {
+		return s.requestIPAPI(key)
+	}, network.IsRetryable)
+	if err != nil {
+		return nil, err
+	}
+	info := result.(*ipAPIResponse)
+
+	s.mu.Lock()
+	s.ipAPI[key] = info
+	s.mu.Unlock()
+	return info, nil
+}
+
+// requestIPAPI makes a single ip-api request for the address key
+func (s *Service) requestIPAPI(key string) (*ipAPIResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.lookupTimeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.apiURL+key+"?fields="+ipAPIFields, nil)
+	if err != nil {
+		return nil, err
+	}
+	if config := s.config.Load(); config != nil && config.UserAgent != "" {
+		req.Header.Set("User-Agent", config.UserAgent)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, &domain.NetTraceError{
+			Type:      domain.ErrorTypeNetwork,
+			Message:   "ip-api request failed",
+			Cause:     err,
+			Context:   map[string]interface{}{"ip": key},
+			Timestamp: time.Now(),
+			Code:      "GEO_REQUEST_FAILED",
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &domain.NetTraceError{
+			Type:      domain.ErrorTypeNetwork,
+			Message:   fmt.Sprintf("ip-api returned status %d", resp.StatusCode),
+			Cause:     &network.HTTPStatusError{StatusCode: resp.StatusCode},
+			Context:   map[string]interface{}{"ip": key, "status": resp.StatusCode},
+			Timestamp: time.Now(),
+			Code:      "GEO_REQUEST_FAILED",
+		}
+	}
+
+	var info ipAPIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, &domain.NetTraceError{
+			Type:      domain.ErrorTypeNetwork,
+			Message:   "failed to decode ip-api response",
+			Cause:     err,
+			Context:   map[string]interface{}{"ip": key},
+			Timestamp: time.Now(),
+			Code:      "GEO_INVALID_RESPONSE",
+		}
+	}
+	if info.Status != "success" {
+		return nil, &domain.NetTraceError{
+			Type:      domain.ErrorTypeNetwork,
+			Message:   fmt.Sprintf("ip-api lookup failed: %s", info.Message),
+			Context:   map[string]interface{}{"ip": key},
+			Timestamp: time.Now(),
+			Code:      "GEO_LOOKUP_FAILED",
+		}
+	}
+
+	return &info, nil
+}
+
+// asnFromWhois queries the Team Cymru whois service for ip, retrying transient failures
+func (s *Service) asnFromWhois(ip net.IP) (*domain.ASNInfo, error) {
+	result, err := s.retry.Load().ExecuteWithRetry(context.Background(), func() (interface{}, error) {
+		return s.requestWhois(ip)
+	}, network.IsRetryable)
+	if err != nil {
+		return nil, err
+	}
+	return result.(*domain.ASNInfo), nil
+}
+
+// requestWhois makes a single Team Cymru whois query for ip
+func (s *Service) requestWhois(ip net.IP) (*domain.ASNInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.lookupTimeout())
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := proxy.Dial(ctx, &dialer, s.proxies().WHOIS, "tcp", s.whoisServer)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	// -v requests the verbose format that includes country, registry and AS name
+	if _, err := fmt.Fprintf(conn, " -v %s\n", ip.String()); err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return parseCymruResponse(lines)
+}
+
+// proxies returns the configured proxies, none without a config
+func (s *Service) proxies() domain.ProxyConfig {
+	config := s.config.Load()
+	if config == nil {
+		return domain.ProxyConfig{}
+	}
+	return config.Proxy
+}
+
+// lookupTimeout returns the timeout for a single lookup
+func (s *Service) lookupTimeout() time.Duration {
+	if config := s.config.Load(); config != nil && config.Timeout > 0 && config.Timeout < maxLookupTimeout {
+		return config.Timeout
+	}
+	return maxLookupTimeout
+}
+
+// parseASField splits an ip-api "as" value such as "AS15169 Google LLC"
+// into the AS number and its description
+func parseASField(value string) (int, string) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(strings.ToUpper(value), "AS") {
+		return 0, ""
+	}
+
+	fields := strings.SplitN(value[2:], " ", 2)
+	number, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, ""
+	}
+	if len(fields) == 1 {
+		return number, ""
+	}
+	return number, strings.TrimSpace(fields[1])
+}
+
+// parseCymruResponse parses verbose Team Cymru whois output:
+//
+//	AS      | IP               | BGP Prefix          | CC | Registry | Allocated  | AS Name
+//	15169   | 8.8.8.8          | 8.8.8.0/24          | US | arin     | 2023-12-28 | GOOGLE, US
+func parseCymruResponse(lines []string) (*domain.ASNInfo, error) {
+	for _, line := range lines {
+		fields := strings.Split(line, "|")
+		if len(fields) < 7 {
+			continue
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+
+		// Multi-origin prefixes list several ASNs; the first is used
+		origins := strings.Fields(fields[0])
+		if len(origins) == 0 {
+			continue
+		}
+		number, err := strconv.Atoi(origins[0])
+		if err != nil {
+			continue // header row or "NA"
+		}
+
+		return &domain.ASNInfo{
+			Number:      number,
+			Name:        fields[6],
+			Description: fields[2],
+			Country:     fields[3],
+			Registry:    strings.ToUpper(fields[4]),
+		}, nil
+	}
+	return nil, fmt.Errorf("no ASN found in whois response")
+}
+
+// checkPublic rejects addresses that have no meaningful public geolocation or ASN
+func checkPublic(ip net.IP) error {
+	if ip == nil || ip.IsUnspecified() || ip.IsLoopback() || ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return &domain.NetTraceError{
+			Type:      domain.ErrorTypeValidation,
+			Message:   "address is not publicly routable",
+			Context:   map[string]interface{}{"ip": ip.String()},
+			Timestamp: time.Now(),
+			Code:      "GEO_NON_PUBLIC_ADDRESS",
+		}
+	}
+	return nil
+}
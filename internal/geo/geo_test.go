@@ -0,0 +1,217 @@
+// Package geo provides geolocation and ASN lookup tests
+package geo
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// testLogger implements domain.Logger and discards all output
+type testLogger struct{}
+
+func (testLogger) Debug(msg string, fields ...interface{}) {}
+func (testLogger) Info(msg string, fields ...interface{})  {}
+func (testLogger) Warn(msg string, fields ...interface{})  {}
+func (testLogger) Error(msg string, fields ...interface{}) {}
+func (testLogger) Fatal(msg string, fields ...interface{}) {}
+
+const googleResponse = `{"status":"success","country":"United States","countryCode":"US","regionName":"Virginia","city":"Ashburn","lat":39.03,"lon":-77.5,"timezone":"America/New_York","isp":"Google LLC","org":"Google Public DNS","as":"AS15169 Google LLC","asname":"GOOGLE"}`
+
+// newTestService creates a service backed by an ip-api stub that returns body
+func newTestService(t *testing.T, status int, body string) (*Service, *int32) {
+	t.Helper()
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Header.Get("User-Agent") != "NetTraceX/test" {
+			t.Errorf("Expected configured user agent, got %q", r.Header.Get("User-Agent"))
+		}
+		if !strings.Contains(r.URL.RawQuery, "fields=") {
+			t.Errorf("Expected fields query, got %q", r.URL.RawQuery)
+		}
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	service := NewService(&domain.NetworkConfig{Timeout: time.Second, UserAgent: "NetTraceX/test", RetryAttempts: 2, RetryDelay: time.Millisecond}, testLogger{})
+	service.apiURL = server.URL + "/json/"
+	service.whoisServer = "127.0.0.1:1" // unreachable unless a test overrides it
+	return service, &requests
+}
+
+// newWhoisServer serves a fixed Team Cymru style response
+func newWhoisServer(t *testing.T, response string) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			bufio.NewReader(conn).ReadString('\n')
+			fmt.Fprint(conn, response)
+			conn.Close()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestService_GetASNInfo(t *testing.T) {
+	service, requests := newTestService(t, http.StatusOK, googleResponse)
+
+	asn, err := service.GetASNInfo(net.ParseIP("8.8.8.8"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if asn.Number != 15169 || asn.Name != "GOOGLE" || asn.Description != "Google LLC" || asn.Country != "US" {
+		t.Errorf("Unexpected ASN info: %+v", asn)
+	}
+
+	// Location and ISP reuse the cached response
+	location, err := service.GetLocation(net.ParseIP("8.8.8.8"))
+	if err != nil || location.City != "Ashburn" || location.CountryCode != "US" {
+		t.Errorf("Unexpected location %+v (err %v)", location, err)
+	}
+	isp, err := service.GetISPInfo(net.ParseIP("8.8.8.8"))
+	if err != nil || isp.Name != "Google LLC" || isp.ASN != 15169 {
+		t.Errorf("Unexpected ISP %+v (err %v)", isp, err)
+	}
+	if atomic.LoadInt32(requests) != 1 {
+		t.Errorf("Expected a single ip-api request, got %d", atomic.LoadInt32(requests))
+	}
+}
+
+func TestService_WhoisFallback(t *testing.T) {
+	service, _ := newTestService(t, http.StatusTooManyRequests, "")
+	service.whoisServer = newWhoisServer(t,
+		"AS      | IP               | BGP Prefix          | CC | Registry | Allocated  | AS Name\n"+
+			"13335   | 1.1.1.1          | 1.1.1.0/24          | AU | apnic    | 2011-08-11 | CLOUDFLARENET, US\n")
+
+	asn, err := service.GetASNInfo(net.ParseIP("1.1.1.1"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if asn.Number != 13335 || asn.Name != "CLOUDFLARENET, US" || asn.Registry != "APNIC" || asn.Country != "AU" {
+		t.Errorf("Unexpected ASN info: %+v", asn)
+	}
+}
+
+func TestService_RetriesTransientFailures(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, googleResponse)
+	}))
+	t.Cleanup(server.Close)
+
+	service := NewService(&domain.NetworkConfig{Timeout: time.Second, RetryAttempts: 3, RetryDelay: time.Millisecond}, testLogger{})
+	service.apiURL = server.URL + "/json/"
+
+	location, err := service.GetLocation(net.ParseIP("8.8.8.8"))
+	if err != nil || location.City != "Ashburn" {
+		t.Fatalf("Expected the retried lookup to succeed, got %+v (err %v)", location, err)
+	}
+	if atomic.LoadInt32(&requests) != 2 {
+		t.Errorf("Expected 2 ip-api requests, got %d", atomic.LoadInt32(&requests))
+	}
+}
+
+func TestService_LookupFailure(t *testing.T) {
+	service, _ := newTestService(t, http.StatusOK, `{"status":"fail","message":"reserved range"}`)
+
+	_, err := service.GetASNInfo(net.ParseIP("1.1.1.1"))
+	netErr, ok := err.(*domain.NetTraceError)
+	if !ok {
+		t.Fatalf("Expected NetTraceError, got %v", err)
+	}
+	if netErr.Code != "GEO_ASN_LOOKUP_FAILED" {
+		t.Errorf("Expected GEO_ASN_LOOKUP_FAILED, got %s", netErr.Code)
+	}
+
+	if _, err := service.GetLocation(net.ParseIP("1.1.1.1")); err == nil {
+		t.Error("Expected location lookup to fail")
+	}
+}
+
+func TestService_NonPublicAddresses(t *testing.T) {
+	service, requests := newTestService(t, http.StatusOK, googleResponse)
+
+	for _, address := range []string{"10.0.0.1", "192.168.1.1", "127.0.0.1", "169.254.1.1", "::1", "fe80::1"} {
+		_, err := service.GetASNInfo(net.ParseIP(address))
+		netErr, ok := err.(*domain.NetTraceError)
+		if !ok || netErr.Code != "GEO_NON_PUBLIC_ADDRESS" {
+			t.Errorf("%s: expected GEO_NON_PUBLIC_ADDRESS, got %v", address, err)
+		}
+	}
+	if _, err := service.GetASNInfo(nil); err == nil {
+		t.Error("Expected error for nil address")
+	}
+	if atomic.LoadInt32(requests) != 0 {
+		t.Errorf("Non-public addresses should not be sent to ip-api, got %d requests", atomic.LoadInt32(requests))
+	}
+}
+
+func TestParseASField(t *testing.T) {
+	tests := []struct {
+		value       string
+		number      int
+		description string
+	}{
+		{"AS15169 Google LLC", 15169, "Google LLC"},
+		{"AS3356", 3356, ""},
+		{"", 0, ""},
+		{"Google", 0, ""},
+		{"ASX Bad", 0, ""},
+	}
+
+	for _, tt := range tests {
+		number, description := parseASField(tt.value)
+		if number != tt.number || description != tt.description {
+			t.Errorf("parseASField(%q) = %d, %q; expected %d, %q", tt.value, number, description, tt.number, tt.description)
+		}
+	}
+}
+
+func TestParseCymruResponse(t *testing.T) {
+	asn, err := parseCymruResponse([]string{
+		"AS      | IP               | BGP Prefix          | CC | Registry | Allocated  | AS Name",
+		"3356 1299 | 4.2.2.2        | 4.0.0.0/9           | US | arin     | 1992-12-01 | LEVEL3, US",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if asn.Number != 3356 || asn.Description != "4.0.0.0/9" {
+		t.Errorf("Expected first origin AS3356, got %+v", asn)
+	}
+
+	for _, lines := range [][]string{
+		nil,
+		{"NA      | 10.0.0.1         | NA                  |    | other    |            | NA"},
+		{" | | | | | | "},
+	} {
+		if _, err := parseCymruResponse(lines); err == nil {
+			t.Errorf("Expected error for %v", lines)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Package i18n provides the translated messages of the TUI
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Auto is the ui.language setting that follows the locale of the environment
+const Auto = "auto"
+
+// Default is the language of messages missing from a catalog
+const Default = "en"
+
+// Languages lists the languages with a locale file
+var Languages = []string{"en", "de", "es", "ja"}
+
+//go:embed locales/*.yaml
+var locales embed.FS
+
+var (
+	mu       sync.RWMutex
+	language = Default
+	catalog  map[string]string
+	fallback map[string]string
+)
+
+// Load returns the messages of the locale file of language
+func Load(language string) (map[string]string, error) {
+	data, err := locales.ReadFile("locales/" + language + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("no messages for language %q", language)
+	}
+	messages := make(map[string]string)
+	if err := yaml.Unmarshal(data, &messages); err != nil {
+		return nil, fmt.Errorf("locale %s: %w", language, err)
+	}
+	return messages, nil
+}
+
+// Detect returns the language of the locale set by LC_ALL, LC_MESSAGES or
+// LANG, e.g. de for de_DE.UTF-8, or Default when it has no locale file
+func Detect(getenv func(string) string) string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		fields := strings.FieldsFunc(getenv(name), func(r rune) bool {
+			return r == '_' || r == '.' || r == '@' || r == '-'
+		})
+		if len(fields) == 0 {
+			continue
+		}
+		code := strings.ToLower(fields[0])
+		for _, supported := range Languages {
+			if code == supported {
+				return code
+			}
+		}
+		return Default
+	}
+	return Default
+}
+
+// Resolve returns the language of the ui.language setting, detecting it from
+// the environment for auto or an empty setting
+func Resolve(setting string, getenv func(string) string) string {
+	if setting == "" || setting == Auto {
+		return Detect(getenv)
+	}
+	for _, supported := range Languages {
+		if setting == supported {
+			return setting
+		}
+	}
+	return Default
+}
+
+// SetLanguage shows the messages of language from now on. Messages missing
+// from its locale file are shown in English.
+func SetLanguage(lang string) error {
+	messages, err := Load(lang)
+	if err != nil {
+		return err
+	}
+	english := messages
+	if lang != Default {
+		if english, err = Load(Default); err != nil {
+			return err
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	language, catalog, fallback = lang, messages, english
+	return nil
+}
+
+// Language returns the language messages are shown in
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return language
+}
+
+// T returns the message id in the current language, formatted with args
+// when given. Unknown ids are returned as they are.
+func T(id string, args ...interface{}) string {
+	mu.RLock()
+	message, ok := catalog[id]
+	if !ok {
+		message, ok = fallback[id]
+	}
+	mu.RUnlock()
+	if !ok {
+		message = id
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+func init() {
+	if err := SetLanguage(Default); err != nil {
+		panic(err)
+	}
+}
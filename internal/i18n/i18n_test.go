@@ -0,0 +1,113 @@
+// Package i18n provides message catalog tests
+package i18n
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+// env returns a getenv function reading vars
+func env(vars map[string]string) func(string) string {
+	return func(name string) string { return vars[name] }
+}
+
+func TestLocalesMatchEnglish(t *testing.T) {
+	english, err := Load(Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, language := range Languages {
+		messages, err := Load(language)
+		if err != nil {
+			t.Fatalf("language %s: %v", language, err)
+		}
+		var missing, unknown []string
+		for id, message := range english {
+			translated, ok := messages[id]
+			if !ok {
+				missing = append(missing, id)
+			} else if strings.Count(translated, "%") != strings.Count(message, "%") {
+				t.Errorf("language %s: %s has other format verbs than in English", language, id)
+			}
+		}
+		for id := range messages {
+			if _, ok := english[id]; !ok {
+				unknown = append(unknown, id)
+			}
+		}
+		sort.Strings(missing)
+		sort.Strings(unknown)
+		if len(missing) > 0 || len(unknown) > 0 {
+			t.Errorf("language %s: missing %v, not in English %v", language, missing, unknown)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		vars map[string]string
+		want string
+	}{
+		{map[string]string{}, "en"},
+		{map[string]string{"LANG": "de_DE.UTF-8"}, "de"},
+		{map[string]string{"LANG": "es_MX"}, "es"},
+		{map[string]string{"LANG": "de_DE.UTF-8", "LC_MESSAGES": "ja_JP.UTF-8"}, "ja"},
+		{map[string]string{"LANG": "ja_JP.UTF-8", "LC_ALL": "C"}, "en"},
+		{map[string]string{"LANG": "fr_FR.UTF-8"}, "en"},
+		{map[string]string{"LANG": "_"}, "en"},
+	}
+
+	for _, tt := range tests {
+		if got := Detect(env(tt.vars)); got != tt.want {
+			t.Errorf("Detect(%v) = %q, want %q", tt.vars, got, tt.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	german := env(map[string]string{"LANG": "de_DE.UTF-8"})
+	if got := Resolve(Auto, german); got != "de" {
+		t.Errorf("expected auto to follow LANG, got %q", got)
+	}
+	if got := Resolve("", german); got != "de" {
+		t.Errorf("expected no setting to follow LANG, got %q", got)
+	}
+	if got := Resolve("ja", german); got != "ja" {
+		t.Errorf("expected the setting to win over LANG, got %q", got)
+	}
+	if got := Resolve("fr", german); got != Default {
+		t.Errorf("expected English for unknown languages, got %q", got)
+	}
+}
+
+func TestT(t *testing.T) {
+	defer SetLanguage(Default)
+
+	if got := T("menu.ping"); got != "Ping Test" {
+		t.Errorf("expected English by default, got %q", got)
+	}
+	if got := T("app.running", 2); got != "2 running" {
+		t.Errorf("expected formatted message, got %q", got)
+	}
+	if got := T("no.such.message"); got != "no.such.message" {
+		t.Errorf("expected unknown ids as they are, got %q", got)
+	}
+
+	if err := SetLanguage("de"); err != nil {
+		t.Fatal(err)
+	}
+	if Language() != "de" {
+		t.Errorf("expected de, got %q", Language())
+	}
+	if got := T("tab.help"); got != "Hilfe" {
+		t.Errorf("expected German, got %q", got)
+	}
+
+	if err := SetLanguage("fr"); err == nil {
+		t.Error("expected an error for a language without locale file")
+	}
+	if Language() != "de" {
+		t.Errorf("expected the language to be kept, got %q", Language())
+	}
+}
@@ -0,0 +1,43 @@
+// Package keyhint builds the key hints shown at the bottom of the screens:
+// bindings described in the help and rendered with bubbles/help in the
+// muted footer style, so the hints come from the bindings that handle the
+// keys.
+package keyhint
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+)
+
+// New creates a binding of keys described as desc in the help
+func New(desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(strings.Join(keys, "/"), desc))
+}
+
+// Relabel returns binding described as desc, for a screen that gives a key
+// its own meaning
+func Relabel(binding key.Binding, desc string) key.Binding {
+	return key.NewBinding(key.WithKeys(binding.Keys()...), key.WithHelp(binding.Help().Key, desc))
+}
+
+// Help renders hints in the muted style at the bottom of a screen,
+// truncated to width when it is not 0
+func Help(width int) help.Model {
+	subtle := lipgloss.NewStyle().Foreground(colors.Subtle)
+	h := help.New()
+	h.Width = width
+	h.Styles = help.Styles{
+		Ellipsis:       subtle,
+		ShortKey:       subtle.Bold(true),
+		ShortDesc:      subtle,
+		ShortSeparator: subtle,
+		FullKey:        lipgloss.NewStyle().Foreground(colors.Accent).Bold(true),
+		FullDesc:       lipgloss.NewStyle().Foreground(colors.Text),
+		FullSeparator:  subtle,
+	}
+	return h
+}
@@ -0,0 +1,30 @@
+package keyhint
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNewAndRelabel(t *testing.T) {
+	binding := New("refresh", "ctrl+r", "f5")
+	if help := binding.Help(); help.Key != "ctrl+r/f5" || help.Desc != "refresh" {
+		t.Errorf("Unexpected help %+v", help)
+	}
+
+	relabeled := Relabel(binding, "re-run")
+	if help := relabeled.Help(); help.Key != "ctrl+r/f5" || help.Desc != "re-run" {
+		t.Errorf("Unexpected relabeled help %+v", help)
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyCtrlR}, relabeled) {
+		t.Error("Relabeled binding should match the keys of the original")
+	}
+}
+
+func TestHelp(t *testing.T) {
+	view := Help(0).ShortHelpView([]key.Binding{New("lookup", "enter"), New("quit", "q")})
+	if view != "enter lookup • q quit" {
+		t.Errorf("Unexpected hints %q", view)
+	}
+}
@@ -0,0 +1,113 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBufferSize is how many records a Buffer keeps by default
+const DefaultBufferSize = 500
+
+// Entry is a log record kept by a Buffer
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	// Fields are the key/value fields of the record as key=value pairs
+	Fields string
+}
+
+// Buffer keeps the latest records of a logger in memory, at every level, so
+// the TUI can show them while it runs
+type Buffer struct {
+	mu      sync.Mutex
+	size    int
+	entries []Entry
+	updates chan struct{}
+}
+
+// NewBuffer creates a buffer keeping the latest size records
+func NewBuffer(size int) *Buffer {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	return &Buffer{size: size, updates: make(chan struct{}, 1)}
+}
+
+// add keeps r, dropping the oldest record when the buffer is full, and
+// signals Updates without blocking
+func (b *Buffer) add(r slog.Record) {
+	entry := Entry{Time: r.Time, Level: r.Level, Message: r.Message, Fields: formatFields(r)}
+
+	b.mu.Lock()
+	b.entries = append(b.entries, entry)
+	if len(b.entries) > b.size {
+		b.entries = append(b.entries[:0:0], b.entries[len(b.entries)-b.size:]...)
+	}
+	b.mu.Unlock()
+
+	select {
+	case b.updates <- struct{}{}:
+	default:
+	}
+}
+
+// Entries returns the kept records at or above min, oldest first
+func (b *Buffer) Entries(min Level) []Entry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entries := make([]Entry, 0, len(b.entries))
+	for _, entry := range b.entries {
+		if entry.Level >= min {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Updates receives a value after records were added. Records added before
+// the value is taken are signalled once.
+func (b *Buffer) Updates() <-chan struct{} {
+	return b.updates
+}
+
+// String formats e as a line with its time of day, level, message and fields
+func (e Entry) String() string {
+	line := e.Time.Format("15:04:05") + " " + LevelName(e.Level) + " " + e.Message
+	if e.Fields != "" {
+		line += " " + e.Fields
+	}
+	return line
+}
+
+// SetBuffer keeps every record in buffer as well, including those below
+// the level written to the output. Set it before the logger is shared.
+func (l *Logger) SetBuffer(buffer *Buffer) {
+	l.buffer = buffer
+}
+
+// formatFields formats the attributes of r as key=value pairs the way
+// slog's text handler does
+func formatFields(r slog.Record) string {
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if len(groups) == 0 && (attr.Key == slog.TimeKey || attr.Key == slog.LevelKey || attr.Key == slog.MessageKey) {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	})
+	fields := slog.NewRecord(time.Time{}, r.Level, "", 0)
+	r.Attrs(func(attr slog.Attr) bool {
+		fields.AddAttrs(attr)
+		return true
+	})
+	handler.Handle(context.Background(), fields)
+	return strings.TrimSpace(buf.String())
+}
@@ -0,0 +1,123 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/journal"
+)
+
+// journalHandler sends records to systemd-journald through go-systemd,
+// keeping every attribute as a journal field
+type journalHandler struct {
+	level Level
+	// attrs are the fields added with WithAttrs, keyed by journal field name
+	attrs  map[string]string
+	prefix string
+}
+
+// newJournalHandler returns a handler for records at or above level, failing
+// when the journal is not available
+func newJournalHandler(level Level) (slog.Handler, error) {
+	if !journal.Enabled() {
+		return nil, fmt.Errorf("failed to connect to journald")
+	}
+	return &journalHandler{level: level}, nil
+}
+
+func (h *journalHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *journalHandler) Handle(_ context.Context, r slog.Record) error {
+	return journal.Send(r.Message, journalPriority(r.Level), h.journalVars(r))
+}
+
+func (h *journalHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = make(map[string]string, len(h.attrs)+len(attrs))
+	for key, value := range h.attrs {
+		clone.attrs[key] = value
+	}
+	for _, attr := range attrs {
+		addJournalVar(clone.attrs, h.prefix, attr)
+	}
+	return &clone
+}
+
+func (h *journalHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "_"
+	return &clone
+}
+
+// journalVars returns the journal fields of r: the program identifier, the
+// handler's attributes and the record's own
+func (h *journalHandler) journalVars(r slog.Record) map[string]string {
+	vars := map[string]string{"SYSLOG_IDENTIFIER": Identifier}
+	for key, value := range h.attrs {
+		vars[key] = value
+	}
+	r.Attrs(func(attr slog.Attr) bool {
+		addJournalVar(vars, h.prefix, attr)
+		return true
+	})
+	return vars
+}
+
+// addJournalVar adds attr to vars, flattening groups into prefixed names
+func addJournalVar(vars map[string]string, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "_"
+		}
+		for _, member := range attr.Value.Group() {
+			addJournalVar(vars, prefix, member)
+		}
+		return
+	}
+	if key := journalFieldName(prefix + attr.Key); key != "" {
+		vars[key] = attr.Value.String()
+	}
+}
+
+// journalPriority maps levels to syslog priorities, as journald expects
+func journalPriority(level Level) journal.Priority {
+	switch {
+	case level < LevelInfo:
+		return journal.PriDebug
+	case level < LevelWarn:
+		return journal.PriInfo
+	case level < LevelError:
+		return journal.PriWarning
+	case level < LevelFatal:
+		return journal.PriErr
+	}
+	return journal.PriCrit
+}
+
+// journalFieldName converts key to a valid journal field name: uppercase
+// letters, digits and underscores, not starting with an underscore or digit
+func journalFieldName(key string) string {
+	var b strings.Builder
+	for _, c := range strings.ToUpper(key) {
+		switch {
+		case c >= 'A' && c <= 'Z', c == '_':
+			b.WriteRune(c)
+		case c >= '0' && c <= '9':
+			if b.Len() == 0 {
+				b.WriteString("F")
+			}
+			b.WriteRune(c)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return strings.TrimLeft(b.String(), "_")
+}
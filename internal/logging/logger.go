@@ -0,0 +1,267 @@
+// Package logging implements domain.Logger on log/slog according to the
+// logging settings: records below the configured level are dropped, the rest
+// are formatted by slog's text or JSON handler and written to stdout, stderr,
+// a rotating file, syslog or the systemd journal.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/appdir"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// Level is the severity of a log record
+type Level = slog.Level
+
+const (
+	LevelDebug = slog.LevelDebug
+	LevelInfo  = slog.LevelInfo
+	LevelWarn  = slog.LevelWarn
+	LevelError = slog.LevelError
+	// LevelFatal is logged before the program exits
+	LevelFatal = slog.LevelError + 4
+)
+
+// LevelName returns the uppercase name of level, naming LevelFatal FATAL
+// where slog would call it ERROR+4
+func LevelName(level Level) string {
+	if level == LevelFatal {
+		return "FATAL"
+	}
+	return level.String()
+}
+
+// ParseLevel parses a level name; an empty name is info
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return LevelDebug, nil
+	case "", "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	case "fatal":
+		return LevelFatal, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", name)
+}
+
+// Supported values of the logging.output setting
+const (
+	OutputStdout   = "stdout"
+	OutputStderr   = "stderr"
+	OutputFile     = "file"
+	OutputSyslog   = "syslog"
+	OutputJournald = "journald"
+)
+
+// Supported values of the logging.format setting
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Identifier names the program in syslog and the journal
+const Identifier = "nettracex"
+
+// DefaultFilePath returns the log file used when logging.file is empty
+func DefaultFilePath() string {
+	return appdir.ConfigPath("nettracex.log")
+}
+
+// Logger implements domain.Logger by passing records to a slog handler for
+// the configured level, format and output
+type Logger struct {
+	handler slog.Handler
+	closer  io.Closer
+	// console is set when the handler writes to stdout or stderr
+	console bool
+	buffer  *Buffer
+	now     func() time.Time
+	exit    func(code int)
+
+	mu       sync.Mutex
+	failures int
+	muted    bool
+}
+
+var _ domain.Logger = (*Logger)(nil)
+
+// New creates a logger for config. Empty settings use info level text
+// output on stdout.
+func New(config domain.LoggingConfig) (*Logger, error) {
+	level, err := ParseLevel(config.Level)
+	if err != nil {
+		return nil, err
+	}
+	format := config.Format
+	if format == "" {
+		format = FormatText
+	}
+	if format != FormatText && format != FormatJSON {
+		return nil, fmt.Errorf("unknown log format %q", config.Format)
+	}
+
+	switch config.Output {
+	case "", OutputStdout:
+		logger := NewWriter(os.Stdout, level, format)
+		logger.console = true
+		return logger, nil
+	case OutputStderr:
+		logger := NewWriter(os.Stderr, level, format)
+		logger.console = true
+		return logger, nil
+	case OutputFile:
+		path := config.File
+		if path == "" {
+			path = DefaultFilePath()
+		}
+		file, err := OpenRotatingFile(path, config.MaxSize, config.MaxBackups, config.MaxAge)
+		if err != nil {
+			return nil, err
+		}
+		logger := NewWriter(file, level, format)
+		logger.closer = file
+		return logger, nil
+	case OutputSyslog:
+		handler, closer, err := newSyslogHandler(level, format)
+		if err != nil {
+			return nil, err
+		}
+		logger := newLogger(handler)
+		logger.closer = closer
+		return logger, nil
+	case OutputJournald:
+		handler, err := newJournalHandler(level)
+		if err != nil {
+			return nil, err
+		}
+		return newLogger(handler), nil
+	}
+	return nil, fmt.Errorf("unknown log output %q", config.Output)
+}
+
+// NewWriter creates a logger writing records at or above level to w
+func NewWriter(w io.Writer, level Level, format string) *Logger {
+	return newLogger(newFormatHandler(w, level, format, true))
+}
+
+func newLogger(handler slog.Handler) *Logger {
+	return &Logger{handler: handler, now: time.Now, exit: os.Exit}
+}
+
+// newFormatHandler returns slog's handler for format writing records at or
+// above level to w, leaving the time out when withTime is not set
+func newFormatHandler(w io.Writer, level Level, format string, withTime bool) slog.Handler {
+	options := &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if len(groups) > 0 {
+				return attr
+			}
+			switch attr.Key {
+			case slog.TimeKey:
+				if !withTime {
+					return slog.Attr{}
+				}
+			case slog.LevelKey:
+				if level, ok := attr.Value.Any().(slog.Level); ok {
+					attr.Value = slog.StringValue(LevelName(level))
+				}
+			}
+			return attr
+		},
+	}
+	if format == FormatJSON {
+		return slog.NewJSONHandler(w, options)
+	}
+	return slog.NewTextHandler(w, options)
+}
+
+// Debug logs a debug message with key/value fields
+func (l *Logger) Debug(msg string, fields ...interface{}) {
+	l.log(LevelDebug, msg, fields)
+}
+
+// Info logs an informational message with key/value fields
+func (l *Logger) Info(msg string, fields ...interface{}) {
+	l.log(LevelInfo, msg, fields)
+}
+
+// Warn logs a warning with key/value fields
+func (l *Logger) Warn(msg string, fields ...interface{}) {
+	l.log(LevelWarn, msg, fields)
+}
+
+// Error logs an error with key/value fields
+func (l *Logger) Error(msg string, fields ...interface{}) {
+	l.log(LevelError, msg, fields)
+}
+
+// Fatal logs a message, closes the output and exits with status 1
+func (l *Logger) Fatal(msg string, fields ...interface{}) {
+	l.log(LevelFatal, msg, fields)
+	l.Close()
+	l.exit(1)
+}
+
+// MuteConsole stops or resumes writing records to stdout and stderr, which
+// the TUI owns while it runs. Muted records are still kept by the buffer;
+// other outputs are not affected.
+func (l *Logger) MuteConsole(muted bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.muted = muted
+}
+
+// Close flushes and closes the output
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
+
+// log hands a record to the buffer and, when its level is enabled, to the
+// handler. Records that cannot be written are reported on stderr, so a
+// broken output does not hide messages silently.
+func (l *Logger) log(level Level, msg string, fields []interface{}) {
+	ctx := context.Background()
+	enabled := l.handler.Enabled(ctx, level)
+	if !enabled && l.buffer == nil {
+		return
+	}
+	r := slog.NewRecord(l.now(), level, msg, 0)
+	r.Add(fields...)
+	if l.buffer != nil {
+		l.buffer.add(r)
+	}
+	if !enabled {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.muted && l.console {
+		return
+	}
+	if err := l.handler.Handle(ctx, r); err != nil {
+		l.failures++
+		if l.failures == 1 {
+			fmt.Fprintf(os.Stderr, "nettracex: failed to write log: %v\n", err)
+		}
+		newFormatHandler(os.Stderr, level, FormatText, true).Handle(ctx, r)
+	}
+}
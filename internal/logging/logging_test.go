@@ -0,0 +1,210 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/journal"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTime = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// newTestLogger writes to a buffer at a fixed time
+func newTestLogger(level Level, format string) (*Logger, *strings.Builder) {
+	var buf strings.Builder
+	logger := NewWriter(&buf, level, format)
+	logger.now = func() time.Time { return testTime }
+	return logger, &buf
+}
+
+func TestParseLevel(t *testing.T) {
+	for name, expected := range map[string]Level{"debug": LevelDebug, "": LevelInfo, "INFO": LevelInfo, "warning": LevelWarn, "error": LevelError, "fatal": LevelFatal} {
+		level, err := ParseLevel(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, expected, level, name)
+	}
+	_, err := ParseLevel("verbose")
+	assert.Error(t, err)
+}
+
+func TestLogger_TextFormat(t *testing.T) {
+	logger, buf := newTestLogger(LevelInfo, FormatText)
+
+	logger.Debug("hidden")
+	logger.Info("Starting metrics probes", "probes", 2, "interval", 30*time.Second)
+	logger.Warn("Metrics probe failed", "probe", "ssl:example.com", "error", errors.New("handshake failed"), "orphan")
+
+	assert.Equal(t, `time=2024-06-01T12:00:00.000Z level=INFO msg="Starting metrics probes" probes=2 interval=30s`+"\n"+
+		`time=2024-06-01T12:00:00.000Z level=WARN msg="Metrics probe failed" probe=ssl:example.com error="handshake failed" !BADKEY=orphan`+"\n", buf.String())
+}
+
+func TestLogger_MuteConsole(t *testing.T) {
+	var out strings.Builder
+	logger := NewWriter(&out, LevelInfo, FormatText)
+	logger.console = true
+	logger.now = func() time.Time { return testTime }
+	buffer := NewBuffer(10)
+	logger.SetBuffer(buffer)
+
+	logger.MuteConsole(true)
+	logger.Info("Probed capabilities", "icmp", true)
+	assert.Empty(t, out.String())
+	require.Len(t, buffer.Entries(LevelDebug), 1)
+
+	logger.MuteConsole(false)
+	logger.Info("Exiting")
+	assert.Equal(t, "time=2024-06-01T12:00:00.000Z level=INFO msg=Exiting\n", out.String())
+
+	// Other outputs keep writing while the console is muted
+	fileLogger, buf := newTestLogger(LevelInfo, FormatText)
+	fileLogger.MuteConsole(true)
+	fileLogger.Info("written")
+	assert.Contains(t, buf.String(), "written")
+}
+
+func TestLogger_JSONFormat(t *testing.T) {
+	logger, buf := newTestLogger(LevelDebug, FormatJSON)
+
+	logger.Debug("dns query", "server", "1.1.1.1", "answers", 2, "cached", false)
+
+	line := strings.TrimSpace(buf.String())
+	assert.True(t, strings.HasPrefix(line, `{"time":"2024-06-01T12:00:00Z","level":"DEBUG","msg":"dns query",`), line)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(line), &decoded))
+	assert.Equal(t, "1.1.1.1", decoded["server"])
+	assert.Equal(t, float64(2), decoded["answers"])
+	assert.Equal(t, false, decoded["cached"])
+}
+
+func TestLogger_Fatal(t *testing.T) {
+	logger, buf := newTestLogger(LevelError, FormatText)
+	code := -1
+	logger.exit = func(c int) { code = c }
+
+	logger.Fatal("cannot continue")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, buf.String(), "level=FATAL msg=\"cannot continue\"")
+}
+
+func TestNew_Validation(t *testing.T) {
+	_, err := New(domain.LoggingConfig{Level: "loud"})
+	assert.Error(t, err)
+	_, err = New(domain.LoggingConfig{Format: "xml"})
+	assert.Error(t, err)
+	_, err = New(domain.LoggingConfig{Output: "printer"})
+	assert.Error(t, err)
+
+	logger, err := New(domain.LoggingConfig{})
+	require.NoError(t, err)
+	assert.True(t, logger.handler.Enabled(context.Background(), LevelInfo))
+	assert.False(t, logger.handler.Enabled(context.Background(), LevelDebug))
+}
+
+func TestNew_FileOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "nettracex.log")
+	logger, err := New(domain.LoggingConfig{Level: "warn", Format: "json", Output: OutputFile, File: path})
+	require.NoError(t, err)
+
+	logger.Info("skipped")
+	logger.Error("Failed to export trace spans", "error", "timeout")
+	require.NoError(t, logger.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "\n"))
+	assert.Contains(t, string(data), `"msg":"Failed to export trace spans"`)
+}
+
+func TestRotatingFile_Rotates(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nettracex.log")
+	file, err := OpenRotatingFile(path, 1, 2, 0)
+	require.NoError(t, err)
+	defer file.Close()
+	assert.FileExists(t, path, "the file is opened before the first record")
+
+	chunk := []byte(strings.Repeat("x", 600*1024))
+	for i := 0; i < 5; i++ {
+		_, err := file.Write(chunk)
+		require.NoError(t, err)
+	}
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(chunk)), info.Size())
+
+	// lumberjack prunes backups in the background
+	assert.Eventually(t, func() bool {
+		backups, _ := filepath.Glob(filepath.Join(dir, "nettracex-*.log"))
+		return len(backups) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestRotatingFile_ZeroSizeNeverRotates(t *testing.T) {
+	file, err := OpenRotatingFile(filepath.Join(t.TempDir(), "nettracex.log"), 0, 0, 0)
+	require.NoError(t, err)
+	defer file.Close()
+	assert.Equal(t, math.MaxInt32, file.MaxSize)
+}
+
+func TestJournalVars(t *testing.T) {
+	r := slog.NewRecord(testTime, LevelWarn, "line one\nline two", 0)
+	r.Add("probe", "ping:example.com", "retry-count", 3, slog.Group("dns", "server", "1.1.1.1"))
+
+	handler := (&journalHandler{level: LevelInfo}).WithAttrs([]slog.Attr{slog.String("session", "abc")}).(*journalHandler)
+	assert.Equal(t, map[string]string{
+		"SYSLOG_IDENTIFIER": "nettracex",
+		"SESSION":           "abc",
+		"PROBE":             "ping:example.com",
+		"RETRY_COUNT":       "3",
+		"DNS_SERVER":        "1.1.1.1",
+	}, handler.journalVars(r))
+	assert.Equal(t, journal.PriWarning, journalPriority(r.Level))
+	assert.Equal(t, journal.PriCrit, journalPriority(LevelFatal))
+}
+
+func TestJournalFieldName(t *testing.T) {
+	assert.Equal(t, "TARGET", journalFieldName("target"))
+	assert.Equal(t, "DNS_SERVER", journalFieldName("dns.server"))
+	assert.Equal(t, "F6TO4", journalFieldName("6to4"))
+	assert.Equal(t, "HIDDEN", journalFieldName("_hidden"))
+}
+
+func TestBuffer_KeepsLatestRecords(t *testing.T) {
+	logger, buf := newTestLogger(LevelWarn, FormatText)
+	buffer := NewBuffer(3)
+	logger.SetBuffer(buffer)
+
+	logger.Debug("resolving", "host", "example.com")
+	logger.Info("connected")
+	logger.Warn("slow response", "rtt", 2*time.Second, "note", "over budget")
+	logger.Error("query failed", "error", errors.New("timeout"))
+
+	select {
+	case <-buffer.Updates():
+	default:
+		t.Fatal("adding records should signal Updates")
+	}
+
+	entries := buffer.Entries(LevelDebug)
+	require.Len(t, entries, 3, "the oldest record is dropped")
+	assert.Equal(t, "connected", entries[0].Message, "records below the output level are kept")
+	assert.Equal(t, `12:00:00 WARN slow response rtt=2s note="over budget"`, entries[1].String())
+	assert.Equal(t, "12:00:00 FATAL exiting", Entry{Time: testTime, Level: LevelFatal, Message: "exiting"}.String())
+
+	errorsOnly := buffer.Entries(LevelError)
+	require.Len(t, errorsOnly, 1)
+	assert.Equal(t, "error=timeout", errorsOnly[0].Fields)
+	assert.NotContains(t, buf.String(), "connected", "the output still drops records below its level")
+}
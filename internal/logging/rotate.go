@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"fmt"
+	"math"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// OpenRotatingFile opens path for appending through lumberjack, which moves
+// the file aside once it reaches maxSizeMB. Rotated files are named after the
+// time of rotation, for example nettracex-2024-06-01T12-00-00.000.log. A
+// maxSizeMB of zero never rotates, and zero maxBackups or maxAgeDays keeps
+// backups regardless of their number or age.
+func OpenRotatingFile(path string, maxSizeMB, maxBackups, maxAgeDays int) (*lumberjack.Logger, error) {
+	if maxSizeMB == 0 {
+		// lumberjack reads zero as its 100 MB default
+		maxSizeMB = math.MaxInt32
+	}
+	file := &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    maxSizeMB,
+		MaxBackups: maxBackups,
+		MaxAge:     maxAgeDays,
+	}
+	// lumberjack opens the file on the first write; open it now so a bad
+	// path fails at startup
+	if _, err := file.Write(nil); err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	return file, nil
+}
@@ -0,0 +1,14 @@
+//go:build windows || plan9
+
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// newSyslogHandler is not supported on this platform
+func newSyslogHandler(level Level, format string) (slog.Handler, io.Closer, error) {
+	return nil, nil, fmt.Errorf("syslog output is not supported on this platform")
+}
@@ -0,0 +1,74 @@
+//go:build !windows && !plan9
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"log/syslog"
+	"strings"
+)
+
+// syslogHandler sends records to the local syslog daemon with the priority
+// of their level, formatting each with slog's handler for the configured format
+type syslogHandler struct {
+	writer *syslog.Writer
+	level  Level
+	// handler builds the format handler, with any attributes and groups
+	// added so far, over the buffer a record is formatted into
+	handler func(w io.Writer) slog.Handler
+}
+
+func newSyslogHandler(level Level, format string) (slog.Handler, io.Closer, error) {
+	writer, err := syslog.New(syslog.LOG_INFO|syslog.LOG_DAEMON, Identifier)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+	handler := &syslogHandler{
+		writer: writer,
+		level:  level,
+		handler: func(w io.Writer) slog.Handler {
+			// syslog stamps messages itself
+			return newFormatHandler(w, level, format, false)
+		},
+	}
+	return handler, writer, nil
+}
+
+func (h *syslogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *syslogHandler) Handle(ctx context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+	if err := h.handler(&buf).Handle(ctx, r); err != nil {
+		return err
+	}
+	message := strings.TrimSuffix(buf.String(), "\n")
+	switch {
+	case r.Level < LevelInfo:
+		return h.writer.Debug(message)
+	case r.Level < LevelWarn:
+		return h.writer.Info(message)
+	case r.Level < LevelError:
+		return h.writer.Warning(message)
+	case r.Level < LevelFatal:
+		return h.writer.Err(message)
+	}
+	return h.writer.Crit(message)
+}
+
+func (h *syslogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.handler = func(w io.Writer) slog.Handler { return h.handler(w).WithAttrs(attrs) }
+	return &clone
+}
+
+func (h *syslogHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.handler = func(w io.Writer) slog.Handler { return h.handler(w).WithGroup(name) }
+	return &clone
+}
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Latency buckets in seconds, from LAN round trips to slow lookups
+var latencyBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// Exporter turns diagnostic results into Prometheus metrics
+type Exporter struct {
+	pingRTT       *prometheus.HistogramVec
+	packetLoss    *prometheus.GaugeVec
+	certExpiry    *prometheus.GaugeVec
+	dnsLookup     *prometheus.HistogramVec
+	probeSuccess  *prometheus.GaugeVec
+	probeDuration *prometheus.GaugeVec
+	now           func() time.Time
+}
+
+// NewExporter registers the diagnostic metrics with registerer. It panics
+// when they are already registered, as that is a programming error.
+func NewExporter(registerer prometheus.Registerer) *Exporter {
+	e := &Exporter{
+		pingRTT:       histogram("nettracex_ping_rtt_seconds", "Round trip time of ping replies.", "target"),
+		packetLoss:    gauge("nettracex_packet_loss_ratio", "Ratio of ping probes without a reply in the last run.", "target"),
+		certExpiry:    gauge("nettracex_cert_expiry_days", "Days until the TLS certificate of the target expires.", "target"),
+		dnsLookup:     histogram("nettracex_dns_lookup_duration_seconds", "Duration of DNS lookups.", "target"),
+		probeSuccess:  gauge("nettracex_probe_success", "Whether the last run of the probe succeeded.", "tool", "target"),
+		probeDuration: gauge("nettracex_probe_duration_seconds", "Duration of the last run of the probe.", "tool", "target"),
+		now:           time.Now,
+	}
+	registerer.MustRegister(e.pingRTT, e.packetLoss, e.certExpiry, e.dnsLookup, e.probeSuccess, e.probeDuration)
+	return e
+}
+
+// gauge creates a gauge with the given label names
+func gauge(name, help string, labels ...string) *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, labels)
+}
+
+// histogram creates a latency histogram with the given label names
+func histogram(name, help string, labels ...string) *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: latencyBuckets}, labels)
+}
+
+// Observe records the outcome of running tool against target. A failed run
+// only updates the probe success and duration metrics.
+func (e *Exporter) Observe(tool, target string, result domain.Result, err error, duration time.Duration) {
+	e.probeDuration.WithLabelValues(tool, target).Set(duration.Seconds())
+	if err != nil || result == nil {
+		e.probeSuccess.WithLabelValues(tool, target).Set(0)
+		return
+	}
+	e.probeSuccess.WithLabelValues(tool, target).Set(1)
+
+	switch data := result.Data().(type) {
+	case []domain.PingResult:
+		e.observePing(target, data)
+	case domain.MultiPingResult:
+		for _, pinged := range data.Targets {
+			e.observePing(pinged.Host, pinged.Results)
+		}
+	case domain.SSLResult:
+		if !data.Expiry.IsZero() {
+			e.certExpiry.WithLabelValues(target).Set(data.Expiry.Sub(e.now()).Hours() / 24)
+		}
+	case domain.DNSResult:
+		e.dnsLookup.WithLabelValues(target).Observe(data.ResponseTime.Seconds())
+	}
+}
+
+// observePing records the replies of one host and the share of probes that were lost
+func (e *Exporter) observePing(target string, results []domain.PingResult) {
+	if len(results) == 0 {
+		return
+	}
+	lost := 0
+	for _, result := range results {
+		if result.Error != nil {
+			lost++
+			continue
+		}
+		e.pingRTT.WithLabelValues(target).Observe(result.RTT.Seconds())
+	}
+	e.packetLoss.WithLabelValues(target).Set(float64(lost) / float64(len(results)))
+}
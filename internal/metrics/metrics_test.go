@@ -0,0 +1,209 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testLogger implements domain.Logger and discards all output
+type testLogger struct{}
+
+func (testLogger) Debug(msg string, fields ...interface{}) {}
+func (testLogger) Info(msg string, fields ...interface{})  {}
+func (testLogger) Warn(msg string, fields ...interface{})  {}
+func (testLogger) Error(msg string, fields ...interface{}) {}
+func (testLogger) Fatal(msg string, fields ...interface{}) {}
+
+// stubTool returns a fixed result or error and counts its runs
+type stubTool struct {
+	name   string
+	result domain.Result
+	err    error
+	mu     sync.Mutex
+	runs   int
+}
+
+func (t *stubTool) Name() string                            { return t.name }
+func (t *stubTool) Description() string                     { return "stub tool" }
+func (t *stubTool) Validate(params domain.Parameters) error { return nil }
+func (t *stubTool) GetModel() tea.Model                     { return nil }
+
+func (t *stubTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	t.mu.Lock()
+	t.runs++
+	t.mu.Unlock()
+	return t.result, t.err
+}
+
+// stubRegistry implements domain.PluginRegistry over a fixed set of tools
+type stubRegistry map[string]domain.DiagnosticTool
+
+func (r stubRegistry) Register(tool domain.DiagnosticTool) error { return nil }
+func (r stubRegistry) Unregister(name string) error              { return nil }
+func (r stubRegistry) List() []domain.DiagnosticTool             { return nil }
+func (r stubRegistry) Get(name string) (domain.DiagnosticTool, bool) {
+	tool, exists := r[name]
+	return tool, exists
+}
+
+// render scrapes registry as Prometheus does
+func render(t *testing.T, registry *prometheus.Registry) string {
+	recorder := httptest.NewRecorder()
+	Handler(registry).ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, 200, recorder.Code)
+	return recorder.Body.String()
+}
+
+func TestHandler(t *testing.T) {
+	registry := NewRegistry()
+	NewExporter(registry).Observe("dns", "example.com", domain.NewResult(domain.DNSResult{ResponseTime: 30 * time.Millisecond}), nil, time.Second)
+
+	recorder := httptest.NewRecorder()
+	Handler(registry).ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.True(t, strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain"))
+	output := recorder.Body.String()
+	assert.Contains(t, output, "# TYPE nettracex_dns_lookup_duration_seconds histogram\n")
+	assert.Contains(t, output, `nettracex_dns_lookup_duration_seconds_bucket{target="example.com",le="+Inf"} 1`)
+	assert.NotContains(t, output, "go_goroutines")
+}
+
+func TestNewExporter_RegisteredTwice(t *testing.T) {
+	registry := NewRegistry()
+	NewExporter(registry)
+	assert.Panics(t, func() { NewExporter(registry) })
+}
+
+func TestExporter_Observe(t *testing.T) {
+	registry := NewRegistry()
+	exporter := NewExporter(registry)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	exporter.now = func() time.Time { return now }
+
+	exporter.Observe("ping", "example.com", domain.NewResult([]domain.PingResult{
+		{RTT: 20 * time.Millisecond},
+		{RTT: 40 * time.Millisecond},
+		{Error: errors.New("timeout")},
+		{Error: errors.New("timeout")},
+	}), nil, time.Second)
+	exporter.Observe("ssl", "example.com", domain.NewResult(domain.SSLResult{Expiry: now.Add(36 * time.Hour)}), nil, time.Second)
+	exporter.Observe("dns", "example.com", domain.NewResult(domain.DNSResult{ResponseTime: 30 * time.Millisecond}), nil, time.Second)
+	exporter.Observe("ping", "down.example", nil, errors.New("unreachable"), 2*time.Second)
+
+	output := render(t, registry)
+	assert.Contains(t, output, `nettracex_ping_rtt_seconds_count{target="example.com"} 2`)
+	assert.Contains(t, output, `nettracex_ping_rtt_seconds_bucket{target="example.com",le="0.025"} 1`)
+	assert.Contains(t, output, `nettracex_packet_loss_ratio{target="example.com"} 0.5`)
+	assert.Contains(t, output, `nettracex_cert_expiry_days{target="example.com"} 1.5`)
+	assert.Contains(t, output, `nettracex_dns_lookup_duration_seconds_sum{target="example.com"} 0.03`)
+	assert.Contains(t, output, `nettracex_probe_success{target="example.com",tool="ping"} 1`)
+	assert.Contains(t, output, `nettracex_probe_success{target="down.example",tool="ping"} 0`)
+	assert.Contains(t, output, `nettracex_probe_duration_seconds{target="down.example",tool="ping"} 2`)
+	assert.NotContains(t, output, `nettracex_packet_loss_ratio{target="down.example"}`)
+}
+
+func TestExporter_ObserveMultiPing(t *testing.T) {
+	registry := NewRegistry()
+	exporter := NewExporter(registry)
+
+	exporter.Observe("ping", "a.example, b.example", domain.NewResult(domain.MultiPingResult{Targets: []domain.PingTargetResult{
+		{Host: "a.example", Results: []domain.PingResult{{RTT: time.Millisecond}}},
+		{Host: "b.example", Results: []domain.PingResult{{Error: errors.New("timeout")}}},
+	}}), nil, time.Second)
+
+	output := render(t, registry)
+	assert.Contains(t, output, `nettracex_packet_loss_ratio{target="a.example"} 0`)
+	assert.Contains(t, output, `nettracex_packet_loss_ratio{target="b.example"} 1`)
+}
+
+func TestParseProbe(t *testing.T) {
+	probe, err := ParseProbe("ping:2001:db8::1")
+	require.NoError(t, err)
+	assert.Equal(t, Probe{Tool: "ping", Target: "2001:db8::1"}, probe)
+	assert.Equal(t, "ping:2001:db8::1", probe.String())
+
+	for _, invalid := range []string{"example.com", ":example.com", "ping:", ""} {
+		_, err := ParseProbe(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestScheduler_RunOnce(t *testing.T) {
+	ping := &stubTool{name: "ping", result: domain.NewResult([]domain.PingResult{{RTT: time.Millisecond}})}
+	ssl := &stubTool{name: "ssl", err: errors.New("handshake failed")}
+	registry := NewRegistry()
+
+	scheduler := NewScheduler(stubRegistry{"ping": ping, "ssl": ssl}, []Probe{
+		{Tool: "ping", Target: "example.com"},
+		{Tool: "ssl", Target: "example.com"},
+	}, nil, 0, NewExporter(registry), testLogger{})
+	assert.Equal(t, DefaultInterval, scheduler.interval)
+
+	scheduler.RunOnce(context.Background())
+
+	assert.Equal(t, 1, ping.runs)
+	assert.Equal(t, 1, ssl.runs)
+	output := render(t, registry)
+	assert.Contains(t, output, `nettracex_probe_success{target="example.com",tool="ping"} 1`)
+	assert.Contains(t, output, `nettracex_probe_success{target="example.com",tool="ssl"} 0`)
+}
+
+// recordingObserver keeps the probes it observed
+type recordingObserver struct {
+	mu       sync.Mutex
+	observed map[string]error
+}
+
+func (o *recordingObserver) Observe(tool, target string, result domain.Result, err error, duration time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.observed[tool+":"+target] = err
+}
+
+func TestScheduler_AddObserver(t *testing.T) {
+	ping := &stubTool{name: "ping", result: domain.NewResult([]domain.PingResult{{RTT: time.Millisecond}})}
+	ssl := &stubTool{name: "ssl", err: errors.New("handshake failed")}
+	observer := &recordingObserver{observed: make(map[string]error)}
+
+	scheduler := NewScheduler(stubRegistry{"ping": ping, "ssl": ssl}, []Probe{
+		{Tool: "ping", Target: "example.com"},
+		{Tool: "ssl", Target: "example.com"},
+	}, nil, 0, NewExporter(NewRegistry()), testLogger{})
+	scheduler.AddObserver(observer)
+	scheduler.RunOnce(context.Background())
+
+	require.Len(t, observer.observed, 2)
+	assert.NoError(t, observer.observed["ping:example.com"])
+	assert.EqualError(t, observer.observed["ssl:example.com"], "handshake failed")
+}
+
+func TestScheduler_Run(t *testing.T) {
+	ping := &stubTool{name: "ping", result: domain.NewResult([]domain.PingResult{{RTT: time.Millisecond}})}
+	tools := stubRegistry{"ping": ping}
+
+	err := NewScheduler(tools, nil, nil, time.Second, NewExporter(NewRegistry()), testLogger{}).Run(context.Background())
+	assert.Error(t, err, "no probes")
+
+	err = NewScheduler(tools, []Probe{{Tool: "traceroute", Target: "example.com"}}, nil, time.Second, NewExporter(NewRegistry()), testLogger{}).Run(context.Background())
+	assert.Error(t, err, "unknown tool")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = NewScheduler(tools, []Probe{{Tool: "ping", Target: "example.com"}}, nil, 10*time.Millisecond, NewExporter(NewRegistry()), testLogger{}).Run(ctx)
+	assert.NoError(t, err)
+
+	ping.mu.Lock()
+	defer ping.mu.Unlock()
+	assert.GreaterOrEqual(t, ping.runs, 2)
+}
@@ -0,0 +1,21 @@
+// Package metrics exports diagnostic results as Prometheus metrics. The
+// metrics are kept by the Prometheus client library and served with promhttp.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// NewRegistry creates a registry for the diagnostic metrics. It has no Go
+// runtime or process collectors, so a scrape returns only the probes.
+func NewRegistry() *prometheus.Registry {
+	return prometheus.NewRegistry()
+}
+
+// Handler serves the metrics of gatherer for Prometheus scraping
+func Handler(gatherer prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})
+}
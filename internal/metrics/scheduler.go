@@ -0,0 +1,161 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/batch"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultInterval is how often probes run when no interval is given
+const DefaultInterval = 30 * time.Second
+
+// Probe is a tool run against one target on every cycle
+type Probe struct {
+	Tool   string
+	Target string
+}
+
+// String returns the probe in tool:target form
+func (p Probe) String() string {
+	return p.Tool + ":" + p.Target
+}
+
+// ParseProbe parses a tool:target probe. Only the first colon separates the
+// tool, so IPv6 targets need no brackets.
+func ParseProbe(value string) (Probe, error) {
+	tool, target, ok := strings.Cut(value, ":")
+	tool = strings.TrimSpace(tool)
+	target = strings.TrimSpace(target)
+	if !ok || tool == "" || target == "" {
+		return Probe{}, fmt.Errorf("probe %q must be in tool:target form", value)
+	}
+	return Probe{Tool: tool, Target: target}, nil
+}
+
+// Observer receives the outcome of every probe run. Exporter is an observer;
+// others can be added to act on results, such as alerting on failures.
+type Observer interface {
+	Observe(tool, target string, result domain.Result, err error, duration time.Duration)
+}
+
+// Scheduler runs probes at a fixed interval and exports their results
+type Scheduler struct {
+	plugins   domain.PluginRegistry
+	probes    []Probe
+	options   map[string]string
+	interval  time.Duration
+	observers []Observer
+	logger    domain.Logger
+}
+
+// NewScheduler creates a scheduler for probes sharing options. An interval
+// that is not positive uses DefaultInterval.
+func NewScheduler(plugins domain.PluginRegistry, probes []Probe, options map[string]string, interval time.Duration, exporter *Exporter, logger domain.Logger) *Scheduler {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Scheduler{
+		plugins:   plugins,
+		probes:    probes,
+		options:   options,
+		interval:  interval,
+		observers: []Observer{exporter},
+		logger:    logger,
+	}
+}
+
+// AddObserver registers observer for the outcome of every probe run
+func (s *Scheduler) AddObserver(observer Observer) {
+	s.observers = append(s.observers, observer)
+}
+
+// Run validates the probes, then runs them immediately and on every interval
+// until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) error {
+	if len(s.probes) == 0 {
+		return fmt.Errorf("no probes configured")
+	}
+	for _, probe := range s.probes {
+		if _, exists := s.plugins.Get(probe.Tool); !exists {
+			return fmt.Errorf("unknown tool in probe %s", probe)
+		}
+	}
+
+	s.logger.Info("Starting metrics probes", "probes", len(s.probes), "interval", s.interval)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce runs every probe concurrently and waits for them to finish
+func (s *Scheduler) RunOnce(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, probe := range s.probes {
+		wg.Add(1)
+		go func(probe Probe) {
+			defer wg.Done()
+			s.runProbe(ctx, probe)
+		}(probe)
+	}
+	wg.Wait()
+}
+
+// runProbe executes one probe and records its outcome
+func (s *Scheduler) runProbe(ctx context.Context, probe Probe) {
+	tool, exists := s.plugins.Get(probe.Tool)
+	if !exists {
+		return
+	}
+
+	start := time.Now()
+	params, err := batch.BuildParameters(tool, probe.Target, s.options)
+	var result domain.Result
+	if err == nil {
+		result, err = tool.Execute(ctx, params)
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		s.logger.Warn("Metrics probe failed", "probe", probe.String(), "error", err)
+	}
+	duration := time.Since(start)
+	for _, observer := range s.observers {
+		observer.Observe(probe.Tool, probe.Target, result, err, duration)
+	}
+}
+
+// Serve exposes the metrics of gatherer on /metrics at addr until ctx is cancelled
+func Serve(ctx context.Context, addr string, gatherer prometheus.Gatherer) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler(gatherer))
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("metrics server failed: %w", err)
+	}
+	return nil
+}
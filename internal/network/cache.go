@@ -0,0 +1,364 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/appdir"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+const (
+	// DefaultCacheMaxEntries bounds the response cache when
+	// network.cache.max_entries is unset
+	DefaultCacheMaxEntries = 1000
+
+	cacheKindDNS   = "dns"
+	cacheKindWHOIS = "whois"
+)
+
+// DefaultCachePath returns the default location of the persisted response cache
+func DefaultCachePath() string {
+	return appdir.CachePath("responses.json")
+}
+
+// cacheEntry is a cached response together with the time it stops being valid
+type cacheEntry struct {
+	Kind    string              `json:"kind"`
+	Stored  time.Time           `json:"stored"`
+	Expires time.Time           `json:"expires"`
+	DNS     *domain.DNSResult   `json:"dns,omitempty"`
+	WHOIS   *domain.WHOISResult `json:"whois,omitempty"`
+}
+
+// responseCache keeps DNS and WHOIS responses in memory, and on disk when
+// persistence is enabled, so repeated lookups are answered instantly. A nil
+// cache is disabled and never answers.
+type responseCache struct {
+	config domain.CacheConfig
+	path   string
+	logger domain.Logger
+	now    func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]*cacheEntry
+	loaded    bool
+	hits      int
+	misses    int
+	refreshes int
+}
+
+// newResponseCache creates the cache described by config, or nil when caching is disabled
+func newResponseCache(config domain.CacheConfig, logger domain.Logger) *responseCache {
+	if !config.Enabled {
+		return nil
+	}
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = DefaultCacheMaxEntries
+	}
+	c := &responseCache{
+		config:  config,
+		logger:  logger,
+		now:     time.Now,
+		entries: make(map[string]*cacheEntry),
+	}
+	if config.Persist {
+		c.path = config.Path
+		if c.path == "" {
+			c.path = DefaultCachePath()
+		}
+	}
+	return c
+}
+
+// dnsCacheKey identifies a DNS lookup, including everything that can change
+// its answer: the vantage point, the server override and the source binding
+func dnsCacheKey(ctx context.Context, vantage, domainName string, recordType domain.DNSRecordType) string {
+	name := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domainName), "."))
+	return strings.Join([]string{cacheKindDNS, vantage, domain.DNSServerFromContext(ctx), SourceFromContext(ctx),
+		name, fmt.Sprintf("%d", recordType)}, "|")
+}
+
+// whoisCacheKey identifies a WHOIS lookup
+func whoisCacheKey(query string) string {
+	return cacheKindWHOIS + "|" + strings.ToLower(strings.TrimSpace(query))
+}
+
+// lookupDNS returns the cached answer for key with its TTLs counted down
+func (c *responseCache) lookupDNS(ctx context.Context, key string) (domain.DNSResult, bool) {
+	entry, age, ok := c.lookup(ctx, key)
+	if !ok || entry.DNS == nil {
+		return domain.DNSResult{}, false
+	}
+	result := *entry.DNS
+	result.Records = ageRecords(result.Records, age)
+	result.Authority = ageRecords(result.Authority, age)
+	result.Additional = ageRecords(result.Additional, age)
+	result.Cached = true
+	return result, true
+}
+
+// lookupWHOIS returns the cached response for key
+func (c *responseCache) lookupWHOIS(ctx context.Context, key string) (domain.WHOISResult, bool) {
+	entry, _, ok := c.lookup(ctx, key)
+	if !ok || entry.WHOIS == nil {
+		return domain.WHOISResult{}, false
+	}
+	result := *entry.WHOIS
+	result.Cached = true
+	return result, true
+}
+
+// lookup returns the unexpired entry for key and its age. Contexts created
+// by domain.WithRefresh always miss so the response is fetched again.
+func (c *responseCache) lookup(ctx context.Context, key string) (*cacheEntry, time.Duration, bool) {
+	if c == nil {
+		return nil, 0, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+
+	if domain.RefreshFromContext(ctx) {
+		c.refreshes++
+		return nil, 0, false
+	}
+	now := c.now()
+	entry, exists := c.entries[key]
+	if !exists || !now.Before(entry.Expires) {
+		c.misses++
+		return nil, 0, false
+	}
+	c.hits++
+	return entry, now.Sub(entry.Stored), true
+}
+
+// storeDNS caches result for its lowest TTL, capped at the configured
+// maximum. Answers without records or TTLs use the negative TTL.
+func (c *responseCache) storeDNS(key string, result domain.DNSResult) {
+	if c == nil {
+		return
+	}
+	ttl := c.config.NegativeTTL
+	if lowest, ok := lowestTTL(result.Records); ok {
+		ttl = lowest
+		if c.config.MaxTTL > 0 && ttl > c.config.MaxTTL {
+			ttl = c.config.MaxTTL
+		}
+	}
+	result.Cached = false
+	c.store(key, &cacheEntry{Kind: cacheKindDNS, DNS: &result}, ttl)
+}
+
+// storeWHOIS caches result for the configured WHOIS TTL
+func (c *responseCache) storeWHOIS(key string, result domain.WHOISResult) {
+	if c == nil {
+		return
+	}
+	result.Cached = false
+	c.store(key, &cacheEntry{Kind: cacheKindWHOIS, WHOIS: &result}, c.config.WHOISTTL)
+}
+
+// store adds entry under key for ttl, evicting old entries beyond the limit
+func (c *responseCache) store(key string, entry *cacheEntry, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+
+	entry.Stored = c.now()
+	entry.Expires = entry.Stored.Add(ttl)
+	c.entries[key] = entry
+	c.evict()
+	c.save()
+}
+
+// evict drops expired entries, then the oldest ones, until the cache fits
+func (c *responseCache) evict() {
+	if len(c.entries) <= c.config.MaxEntries {
+		return
+	}
+	now := c.now()
+	for key, entry := range c.entries {
+		if !now.Before(entry.Expires) {
+			delete(c.entries, key)
+		}
+	}
+	for len(c.entries) > c.config.MaxEntries {
+		oldest := ""
+		for key, entry := range c.entries {
+			if oldest == "" || entry.Stored.Before(c.entries[oldest].Stored) {
+				oldest = key
+			}
+		}
+		delete(c.entries, oldest)
+	}
+}
+
+// stats reports the cache contents and how lookups were answered
+func (c *responseCache) stats() domain.CacheStats {
+	if c == nil {
+		return domain.CacheStats{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+
+	stats := domain.CacheStats{
+		Enabled:   true,
+		Path:      c.path,
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Refreshes: c.refreshes,
+	}
+	now := c.now()
+	for _, entry := range c.entries {
+		if !now.Before(entry.Expires) {
+			stats.Expired++
+			continue
+		}
+		switch entry.Kind {
+		case cacheKindDNS:
+			stats.DNSEntries++
+		case cacheKindWHOIS:
+			stats.WHOISEntries++
+		}
+	}
+	return stats
+}
+
+// clear drops every cached response, including the persisted copy
+func (c *responseCache) clear() error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loaded = true
+	c.entries = make(map[string]*cacheEntry)
+	if c.path == "" {
+		return nil
+	}
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove response cache: %w", err)
+	}
+	return nil
+}
+
+// load reads the persisted entries on first use. A missing or unreadable
+// file starts an empty cache.
+func (c *responseCache) load() {
+	if c.loaded {
+		return
+	}
+	c.loaded = true
+	if c.path == "" {
+		return
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			c.warn("Failed to read response cache", "path", c.path, "error", err)
+		}
+		return
+	}
+	var entries map[string]*cacheEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		c.warn("Ignoring corrupt response cache", "path", c.path, "error", err)
+		return
+	}
+	now := c.now()
+	for key, entry := range entries {
+		if entry != nil && now.Before(entry.Expires) {
+			c.entries[key] = entry
+		}
+	}
+}
+
+// save writes the entries to disk when persistence is enabled
+func (c *responseCache) save() {
+	if c.path == "" {
+		return
+	}
+
+	data, err := json.Marshal(c.entries)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(c.path), 0700)
+	}
+	if err == nil {
+		tmp := c.path + ".tmp"
+		if err = os.WriteFile(tmp, data, 0600); err == nil {
+			if err = os.Rename(tmp, c.path); err != nil {
+				os.Remove(tmp)
+			}
+		}
+	}
+	if err != nil {
+		c.warn("Failed to write response cache", "path", c.path, "error", err)
+	}
+}
+
+// warn logs a cache problem; the cache keeps working in memory
+func (c *responseCache) warn(msg string, fields ...interface{}) {
+	if c.logger != nil {
+		c.logger.Warn(msg, fields...)
+	}
+}
+
+// lowestTTL returns the lowest known TTL among records
+func lowestTTL(records []domain.DNSRecord) (time.Duration, bool) {
+	var lowest uint32
+	found := false
+	for _, record := range records {
+		if record.TTL > 0 && (!found || record.TTL < lowest) {
+			lowest, found = record.TTL, true
+		}
+	}
+	return time.Duration(lowest) * time.Second, found
+}
+
+// ageRecords returns a copy of records with their TTLs reduced by age. Known
+// TTLs stay at least one second so they are not mistaken for unknown ones.
+func ageRecords(records []domain.DNSRecord, age time.Duration) []domain.DNSRecord {
+	if records == nil {
+		return nil
+	}
+	aged := make([]domain.DNSRecord, len(records))
+	copy(aged, records)
+	elapsed := uint32(age / time.Second)
+	for i := range aged {
+		if aged[i].TTL == 0 {
+			continue
+		}
+		if aged[i].TTL > elapsed {
+			aged[i].TTL -= elapsed
+		} else {
+			aged[i].TTL = 1
+		}
+	}
+	return aged
+}
+
+// CacheStats implements domain.CacheReporter
+func (c *Client) CacheStats() domain.CacheStats {
+	return c.current().cache.stats()
+}
+
+// ClearCache implements domain.CacheReporter
+func (c *Client) ClearCache() error {
+	return c.current().cache.clear()
+}
@@ -0,0 +1,168 @@
+package network
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// newTestCache returns an in-memory cache whose clock is controlled by the returned pointer
+func newTestCache(config domain.CacheConfig) (*responseCache, *time.Time) {
+	config.Enabled = true
+	cache := newResponseCache(config, &mockLogger{})
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	return cache, &now
+}
+
+func TestResponseCache_DNSTTL(t *testing.T) {
+	cache, now := newTestCache(domain.CacheConfig{MaxTTL: time.Hour, NegativeTTL: time.Minute})
+	ctx := context.Background()
+	key := dnsCacheKey(ctx, "", "Example.com.", domain.DNSRecordTypeA)
+
+	if _, ok := cache.lookupDNS(ctx, key); ok {
+		t.Fatal("Expected an empty cache to miss")
+	}
+	cache.storeDNS(key, domain.DNSResult{
+		Query:   "example.com",
+		Records: []domain.DNSRecord{{Name: "example.com", Type: domain.DNSRecordTypeA, Value: "192.0.2.1", TTL: 300}},
+	})
+
+	*now = now.Add(100 * time.Second)
+	result, ok := cache.lookupDNS(ctx, dnsCacheKey(ctx, "", "example.com", domain.DNSRecordTypeA))
+	if !ok || !result.Cached {
+		t.Fatalf("Expected a cached answer, got %+v (%v)", result, ok)
+	}
+	if result.Records[0].TTL != 200 {
+		t.Errorf("Expected the TTL to count down to 200, got %d", result.Records[0].TTL)
+	}
+
+	*now = now.Add(200 * time.Second)
+	if _, ok := cache.lookupDNS(ctx, key); ok {
+		t.Error("Expected the answer to expire with its TTL")
+	}
+
+	stats := cache.stats()
+	if stats.Hits != 1 || stats.Misses != 2 || stats.Expired != 1 || stats.DNSEntries != 0 {
+		t.Errorf("Unexpected statistics %+v", stats)
+	}
+}
+
+func TestResponseCache_NegativeAndCappedTTL(t *testing.T) {
+	cache, now := newTestCache(domain.CacheConfig{MaxTTL: time.Minute, NegativeTTL: 10 * time.Second})
+	ctx := context.Background()
+
+	cache.storeDNS("empty", domain.DNSResult{Query: "missing.example.com"})
+	cache.storeDNS("long", domain.DNSResult{
+		Records: []domain.DNSRecord{{Name: "example.com", Type: domain.DNSRecordTypeNS, Value: "ns1.example.com", TTL: 86400}},
+	})
+
+	*now = now.Add(30 * time.Second)
+	if _, ok := cache.lookupDNS(ctx, "empty"); ok {
+		t.Error("Expected the empty answer to expire after the negative TTL")
+	}
+	if _, ok := cache.lookupDNS(ctx, "long"); !ok {
+		t.Error("Expected the long-lived answer to still be cached")
+	}
+
+	*now = now.Add(time.Minute)
+	if _, ok := cache.lookupDNS(ctx, "long"); ok {
+		t.Error("Expected the answer to expire at the maximum TTL")
+	}
+}
+
+func TestResponseCache_Refresh(t *testing.T) {
+	cache, _ := newTestCache(domain.CacheConfig{WHOISTTL: time.Hour})
+	key := whoisCacheKey("example.com")
+	cache.storeWHOIS(key, domain.WHOISResult{Domain: "example.com", Registrar: "Example Registrar"})
+
+	if result, ok := cache.lookupWHOIS(context.Background(), whoisCacheKey(" EXAMPLE.com")); !ok || result.Registrar != "Example Registrar" {
+		t.Errorf("Expected the cached response, got %+v (%v)", result, ok)
+	}
+	if _, ok := cache.lookupWHOIS(domain.WithRefresh(context.Background()), key); ok {
+		t.Error("Expected a refresh to bypass the cache")
+	}
+	if stats := cache.stats(); stats.Refreshes != 1 || stats.WHOISEntries != 1 {
+		t.Errorf("Unexpected statistics %+v", stats)
+	}
+}
+
+func TestResponseCache_Eviction(t *testing.T) {
+	cache, now := newTestCache(domain.CacheConfig{MaxEntries: 2, WHOISTTL: time.Hour})
+	for _, query := range []string{"a.example", "b.example", "c.example"} {
+		cache.storeWHOIS(whoisCacheKey(query), domain.WHOISResult{Domain: query})
+		*now = now.Add(time.Second)
+	}
+
+	if _, ok := cache.lookupWHOIS(context.Background(), whoisCacheKey("a.example")); ok {
+		t.Error("Expected the oldest entry to be evicted")
+	}
+	if _, ok := cache.lookupWHOIS(context.Background(), whoisCacheKey("c.example")); !ok {
+		t.Error("Expected the newest entry to be kept")
+	}
+}
+
+func TestResponseCache_Persist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "responses.json")
+	config := domain.CacheConfig{Persist: true, Path: path, WHOISTTL: time.Hour}
+
+	cache, _ := newTestCache(config)
+	cache.storeWHOIS(whoisCacheKey("example.com"), domain.WHOISResult{Domain: "example.com"})
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("Expected the cache to be written to disk: %v", err)
+	}
+
+	reloaded, _ := newTestCache(config)
+	if _, ok := reloaded.lookupWHOIS(context.Background(), whoisCacheKey("example.com")); !ok {
+		t.Error("Expected the persisted response to be loaded")
+	}
+
+	if err := reloaded.clear(); err != nil {
+		t.Fatalf("clear failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected the cache file to be removed, got %v", err)
+	}
+	if stats := reloaded.stats(); stats.WHOISEntries != 0 || stats.Path != path {
+		t.Errorf("Unexpected statistics %+v", stats)
+	}
+}
+
+func TestResponseCache_Disabled(t *testing.T) {
+	client := NewClient(&domain.NetworkConfig{}, &mockErrorHandler{}, &mockLogger{})
+	if client.current().cache != nil {
+		t.Fatal("Expected no cache unless enabled")
+	}
+	if stats := client.CacheStats(); stats.Enabled {
+		t.Errorf("Expected disabled statistics, got %+v", stats)
+	}
+	if err := client.ClearCache(); err != nil {
+		t.Errorf("Expected clearing a disabled cache to succeed, got %v", err)
+	}
+}
+
+func TestSSHClient_DNSLookupCached(t *testing.T) {
+	client, commands := newSSHTestClient(t, "example.com.\t\t300\tIN\tA\t192.0.2.1", nil)
+	client.Reconfigure(domain.NetworkConfig{Timeout: 3 * time.Second, RetryAttempts: 1, Cache: domain.CacheConfig{Enabled: true, MaxTTL: time.Hour}})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.DNSLookup(context.Background(), "example.com", domain.DNSRecordTypeA); err != nil {
+			t.Fatalf("DNSLookup failed: %v", err)
+		}
+	}
+	if len(*commands) != 1 {
+		t.Errorf("Expected the second lookup to be served from cache, ran %v", *commands)
+	}
+
+	result, err := client.DNSLookup(domain.WithRefresh(context.Background()), "example.com", domain.DNSRecordTypeA)
+	if err != nil || result.Cached {
+		t.Fatalf("Expected a fresh answer, got %+v (%v)", result, err)
+	}
+	if len(*commands) != 2 {
+		t.Errorf("Expected a refresh to query again, ran %v", *commands)
+	}
+}
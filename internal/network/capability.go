@@ -0,0 +1,44 @@
+// Package network provides the startup probe of privileged operations
+package network
+
+import (
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+var (
+	capabilitiesOnce sync.Once
+	capabilities     domain.Capabilities
+)
+
+// ProbeCapabilities reports the privileged operations available to this
+// process. The probe runs on the first call; later calls return its result.
+func ProbeCapabilities() domain.Capabilities {
+	capabilitiesOnce.Do(func() {
+		capabilities = probeCapabilities()
+		capabilities.Platform = runtime.GOOS + "/" + runtime.GOARCH
+		capabilities.ProbedAt = time.Now()
+	})
+	return capabilities
+}
+
+// Capabilities implements domain.CapabilityReporter
+func (c *Client) Capabilities() domain.Capabilities {
+	return ProbeCapabilities()
+}
+
+// Capabilities implements domain.CapabilityReporter. Probes run on the SSH
+// host with its own ping, traceroute and dig, so no local privileges are needed.
+func (c *SSHClient) Capabilities() domain.Capabilities {
+	return domain.Capabilities{
+		Platform: "ssh " + c.target,
+		ICMP:     true,
+		Notes:    []string{"Ping, traceroute and DNS run on " + c.target + " using its installed commands"},
+	}
+}
+
+// tcpFallbackNote explains the degraded ping mode used without ICMP
+const tcpFallbackNote = "Ping falls back to TCP connects to port 80: reply TTLs are unknown and hosts that filter port 80 appear down"
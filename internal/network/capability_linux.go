@@ -0,0 +1,84 @@
+//go:build linux
+
+// Package network provides the Linux probe of ICMP sockets and CAP_NET_RAW
+package network
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// capNetRaw is the bit of CAP_NET_RAW in the capability sets
+const capNetRaw = 13
+
+// probeCapabilities opens and closes each kind of ICMP socket and reads the
+// effective capabilities of the process
+func probeCapabilities() domain.Capabilities {
+	caps := domain.Capabilities{Privileged: os.Geteuid() == 0}
+
+	dgram := probeSocket(syscall.SOCK_DGRAM, "unprivileged ping socket")
+	raw := probeSocket(syscall.SOCK_RAW, "raw ICMP socket")
+	netRaw := probeNetRaw()
+	caps.Probes = []domain.Capability{dgram, raw, netRaw}
+	caps.ICMP = dgram.Available || raw.Available
+
+	if !caps.ICMP {
+		caps.Notes = append(caps.Notes, tcpFallbackNote,
+			"Grant ICMP with 'sudo setcap cap_net_raw+ep' on the binary or widen the net.ipv4.ping_group_range sysctl")
+	}
+	return caps
+}
+
+// probeSocket reports whether an IPv4 ICMP socket of sockType can be opened
+func probeSocket(sockType int, description string) domain.Capability {
+	name := domain.CapabilityICMPSocket
+	if sockType == syscall.SOCK_RAW {
+		name = domain.CapabilityRawSocket
+	}
+
+	fd, err := syscall.Socket(syscall.AF_INET, sockType|syscall.SOCK_CLOEXEC, syscall.IPPROTO_ICMP)
+	if err != nil {
+		return domain.Capability{Name: name, Detail: fmt.Sprintf("%s: %v", description, err)}
+	}
+	syscall.Close(fd)
+	return domain.Capability{Name: name, Available: true, Detail: description + " can be opened"}
+}
+
+// probeNetRaw reports whether CAP_NET_RAW is in the effective capability set
+func probeNetRaw() domain.Capability {
+	capability := domain.Capability{Name: domain.CapabilityNetRaw}
+
+	file, err := os.Open("/proc/self/status")
+	if err != nil {
+		capability.Detail = fmt.Sprintf("cannot read capabilities: %v", err)
+		return capability
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		value, found := strings.CutPrefix(scanner.Text(), "CapEff:")
+		if !found {
+			continue
+		}
+		effective, err := strconv.ParseUint(strings.TrimSpace(value), 16, 64)
+		if err != nil {
+			capability.Detail = fmt.Sprintf("cannot parse capabilities %q", strings.TrimSpace(value))
+			return capability
+		}
+		capability.Available = effective&(1<<capNetRaw) != 0
+		capability.Detail = "not in the effective capability set"
+		if capability.Available {
+			capability.Detail = "in the effective capability set"
+		}
+		return capability
+	}
+	capability.Detail = "effective capabilities not reported"
+	return capability
+}
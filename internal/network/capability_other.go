@@ -0,0 +1,19 @@
+//go:build !linux && !windows
+
+// Package network provides the capability probe for platforms without ICMP socket support
+package network
+
+import (
+	"os"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// probeCapabilities reports root privileges. ICMP sockets are not
+// implemented on this platform, so ping always uses TCP connects.
+func probeCapabilities() domain.Capabilities {
+	return domain.Capabilities{
+		Privileged: os.Geteuid() == 0,
+		Notes:      []string{"ICMP probes are not implemented on this platform", tcpFallbackNote},
+	}
+}
@@ -0,0 +1,42 @@
+package network
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+func TestProbeCapabilities(t *testing.T) {
+	capabilities := ProbeCapabilities()
+
+	if capabilities.Platform != runtime.GOOS+"/"+runtime.GOARCH {
+		t.Errorf("Expected platform %s/%s, got %q", runtime.GOOS, runtime.GOARCH, capabilities.Platform)
+	}
+	if capabilities.ProbedAt.IsZero() {
+		t.Error("Expected the probe time to be recorded")
+	}
+	if again := ProbeCapabilities(); !again.ProbedAt.Equal(capabilities.ProbedAt) {
+		t.Error("Expected the probe to run only once")
+	}
+
+	icmp := capabilities.Has(domain.CapabilityICMPSocket) || capabilities.Has(domain.CapabilityRawSocket)
+	if capabilities.ICMP != icmp {
+		t.Errorf("Expected ICMP %v to follow the socket probes %+v", capabilities.ICMP, capabilities.Probes)
+	}
+	if !capabilities.ICMP && len(capabilities.Notes) == 0 {
+		t.Error("Expected the degraded ping mode to be explained")
+	}
+	if runtime.GOOS == "linux" && len(capabilities.Probes) != 3 {
+		t.Errorf("Expected socket and CAP_NET_RAW probes, got %+v", capabilities.Probes)
+	}
+}
+
+func TestSSHClient_Capabilities(t *testing.T) {
+	client, _ := newSSHTestClient(t, "", nil)
+
+	capabilities := client.Capabilities()
+	if !capabilities.ICMP || capabilities.Platform != "ssh ops@bastion.example.com" {
+		t.Errorf("Expected remote ICMP via the SSH host, got %+v", capabilities)
+	}
+}
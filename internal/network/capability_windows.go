@@ -0,0 +1,28 @@
+//go:build windows
+
+// Package network provides the Windows probe of administrator rights
+package network
+
+import (
+	"os"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// probeCapabilities checks for administrator rights. ICMP sockets are not
+// implemented on Windows, so ping always uses TCP connects.
+func probeCapabilities() domain.Capabilities {
+	admin := domain.Capability{Name: domain.CapabilityAdmin, Detail: "running without administrator rights"}
+	// Only administrators may open the raw device of the first disk
+	if device, err := os.Open(`\\.\PHYSICALDRIVE0`); err == nil {
+		device.Close()
+		admin.Available = true
+		admin.Detail = "running as administrator"
+	}
+
+	return domain.Capabilities{
+		Privileged: admin.Available,
+		Probes:     []domain.Capability{admin},
+		Notes:      []string{"ICMP probes are not implemented on Windows", tcpFallbackNote},
+	}
+}
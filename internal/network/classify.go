@@ -0,0 +1,91 @@
+package network
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// ClassifyError returns the kind of failure behind err, or an empty kind
+// when the cause is not one the user can act on
+func ClassifyError(err error) domain.ErrorKind {
+	if err == nil {
+		return ""
+	}
+	if kind := domain.ErrorKindOf(err); kind != "" {
+		return kind
+	}
+
+	if errors.Is(err, os.ErrPermission) {
+		return domain.ErrorKindPermissionDenied
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		if dnsErr.IsNotFound && dnsErr.Err == "no such host" {
+			return domain.ErrorKindDNSNXDomain
+		}
+		if dnsErr.IsTimeout {
+			return domain.ErrorKindTimeout
+		}
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return domain.ErrorKindConnRefused
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) {
+		return domain.ErrorKindTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return domain.ErrorKindTimeout
+	}
+
+	var recordErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	var verifyErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	if errors.As(err, &recordErr) || errors.As(err, &alertErr) || errors.As(err, &verifyErr) ||
+		errors.As(err, &authorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &invalidErr) ||
+		strings.Contains(err.Error(), "tls: ") {
+		return domain.ErrorKindTLSHandshake
+	}
+	return ""
+}
+
+// classifyError tags err with its kind so the UI can suggest a fix. Plain
+// errors of a known kind are wrapped in a NetTraceError with message and
+// code; other errors are returned unchanged.
+func classifyError(err error, message, code string) error {
+	if err == nil || domain.ErrorKindOf(err) != "" {
+		return err
+	}
+	kind := ClassifyError(err)
+	if kind == "" {
+		return err
+	}
+
+	if traceErr, ok := err.(*domain.NetTraceError); ok {
+		traceErr.Kind = kind
+		return traceErr
+	}
+	return &domain.NetTraceError{
+		Type:      domain.ErrorTypeNetwork,
+		Message:   message,
+		Cause:     err,
+		Timestamp: time.Now(),
+		Code:      code,
+		Kind:      kind,
+	}
+}
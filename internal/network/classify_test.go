@@ -0,0 +1,65 @@
+package network
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want domain.ErrorKind
+	}{
+		{"nil", nil, ""},
+		{"nxdomain", &net.DNSError{Err: "no such host", Name: "missing.example", IsNotFound: true}, domain.ErrorKindDNSNXDomain},
+		{"nodata", &net.DNSError{Err: "no answer for record type", Name: "example.com", IsNotFound: true}, ""},
+		{"dns timeout", &net.DNSError{Err: "i/o timeout", IsTimeout: true}, domain.ErrorKindTimeout},
+		{"refused", &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, domain.ErrorKindConnRefused},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), domain.ErrorKindTimeout},
+		{"permission", &net.OpError{Op: "listen", Net: "ip4:icmp", Err: os.NewSyscallError("socket", syscall.EPERM)}, domain.ErrorKindPermissionDenied},
+		{"unknown authority", x509.UnknownAuthorityError{}, domain.ErrorKindTLSHandshake},
+		{"tls alert", errors.New("remote error: tls: handshake failure"), domain.ErrorKindTLSHandshake},
+		{"already tagged", &domain.NetTraceError{Kind: domain.ErrorKindTimeout}, domain.ErrorKindTimeout},
+		{"other", errors.New("unexpected response"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyError(tt.err); got != tt.want {
+				t.Errorf("ClassifyError(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyError_Wrap(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+	err := classifyError(refused, "SSL check failed", "SSL_CHECK_FAILED")
+
+	var traceErr *domain.NetTraceError
+	if !errors.As(err, &traceErr) || traceErr.Code != "SSL_CHECK_FAILED" || traceErr.Kind != domain.ErrorKindConnRefused {
+		t.Fatalf("Expected a classified NetTraceError, got %#v", err)
+	}
+	if !errors.Is(err, domain.ErrConnRefused) || !errors.Is(err, syscall.ECONNREFUSED) {
+		t.Error("Expected the kind and the cause to stay matchable")
+	}
+
+	exhausted := &domain.NetTraceError{Code: "RETRY_EXHAUSTED", Cause: fmt.Errorf("read: %w", os.ErrDeadlineExceeded)}
+	if err := classifyError(exhausted, "DNS lookup failed", "DNS_LOOKUP_FAILED"); err != exhausted || exhausted.Kind != domain.ErrorKindTimeout {
+		t.Errorf("Expected the retry error to be tagged in place, got %#v", err)
+	}
+
+	plain := errors.New("unexpected response")
+	if err := classifyError(plain, "WHOIS lookup failed", "WHOIS_LOOKUP_FAILED"); err != plain {
+		t.Errorf("Expected unknown errors to be returned unchanged, got %#v", err)
+	}
+}
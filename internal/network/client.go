@@ -3,31 +3,104 @@ package network
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/proxy"
+	"github.com/nettracex/nettracex-tui/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Client implements the NetworkClient interface with real network operations
 type Client struct {
-	config       *domain.NetworkConfig
 	errorHandler domain.ErrorHandler
 	logger       domain.Logger
+
+	// state is replaced as a whole by Reconfigure, so an operation reads a
+	// consistent configuration while another goroutine applies a new one
+	state atomic.Pointer[clientState]
+	mu    sync.Mutex
+}
+
+// clientState is the network configuration together with the components
+// built from it. It is never modified once published.
+type clientState struct {
+	config       domain.NetworkConfig
 	retryManager *RetryManager
+	dnsHealth    *dnsServerHealth
+	limiter      *limiter
+	cache        *responseCache
 }
 
-// NewClient creates a new network client with the provided configuration
+// NewClient creates a new network client with the provided configuration.
+// The client keeps a copy of config; use Reconfigure to change it.
 func NewClient(config *domain.NetworkConfig, errorHandler domain.ErrorHandler, logger domain.Logger) *Client {
-	return &Client{
-		config:       config,
+	c := &Client{
 		errorHandler: errorHandler,
 		logger:       logger,
+	}
+	c.state.Store(&clientState{
+		config:       *config,
 		retryManager: NewRetryManager(config.RetryAttempts, config.RetryDelay),
+		dnsHealth:    newDNSServerHealth(),
+		limiter:      newLimiter(config.MaxConcurrency, config.RateLimit, config.RateBurst),
+		cache:        newResponseCache(config.Cache, logger),
+	})
+	return c
+}
+
+// current returns the configuration and components for a new operation
+func (c *Client) current() *clientState {
+	return c.state.Load()
+}
+
+// Reconfigure applies config to the operations started from now on, while
+// running ones finish with the settings they began with. The limiter, retry
+// manager, DNS server health and response cache are rebuilt when their
+// settings changed and kept otherwise, so rate limits, server statistics
+// and cached responses survive unrelated changes.
+func (c *Client) Reconfigure(config domain.NetworkConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	old := c.current()
+	next := *old
+	next.config = config
+	if config.RetryAttempts != old.config.RetryAttempts || config.RetryDelay != old.config.RetryDelay {
+		next.retryManager = NewRetryManager(config.RetryAttempts, config.RetryDelay)
+	}
+	if config.MaxConcurrency != old.config.MaxConcurrency || config.RateLimit != old.config.RateLimit || config.RateBurst != old.config.RateBurst {
+		next.limiter = newLimiter(config.MaxConcurrency, config.RateLimit, config.RateBurst)
+	}
+	if !slices.Equal(config.DNSServers, old.config.DNSServers) {
+		next.dnsHealth = newDNSServerHealth()
+	}
+	if config.Cache != old.config.Cache {
+		next.cache = newResponseCache(config.Cache, c.logger)
 	}
+	c.state.Store(&next)
 }
 
+const (
+	// DefaultMinPingInterval is the shortest interval between probes when
+	// network.min_ping_interval is unset
+	DefaultMinPingInterval = 200 * time.Millisecond
+	// DefaultMaxFloodCount caps the probes sent by a flood ping when
+	// network.max_flood_count is unset
+	DefaultMaxFloodCount = 1000
+
+	// maxPingBuffer bounds the result channel buffer for large probe counts
+	maxPingBuffer = 256
+)
+
 // Ping performs ping operations to the specified host
 func (c *Client) Ping(ctx context.Context, host string, opts domain.PingOptions) (<-chan domain.PingResult, error) {
 	if err := c.validateHost(host); err != nil {
@@ -41,16 +114,32 @@ func (c *Client) Ping(ctx context.Context, host string, opts domain.PingOptions)
 		}
 	}
 
-	resultChan := make(chan domain.PingResult, opts.Count)
+	opts = c.applyPingLimits(opts)
+	resultChan := make(chan domain.PingResult, pingBufferSize(opts))
 	
 	go func() {
 		defer close(resultChan)
+		if err := c.current().limiter.wait(ctx, host); err != nil {
+			resultChan <- domain.PingResult{Host: domain.NetworkHost{Hostname: host}, Error: err, Timestamp: time.Now()}
+			return
+		}
 		c.executePing(ctx, host, opts, resultChan)
 	}()
 
 	return resultChan, nil
 }
 
+// pingBufferSize returns the result channel buffer size for opts
+func pingBufferSize(opts domain.PingOptions) int {
+	if opts.Count <= 0 {
+		return 1
+	}
+	if opts.Count > maxPingBuffer {
+		return maxPingBuffer
+	}
+	return opts.Count
+}
+
 // Traceroute performs traceroute operations to the specified host
 func (c *Client) Traceroute(ctx context.Context, host string, opts domain.TraceOptions) (<-chan domain.TraceHop, error) {
 	if err := c.validateHost(host); err != nil {
@@ -68,6 +157,10 @@ func (c *Client) Traceroute(ctx context.Context, host string, opts domain.TraceO
 	
 	go func() {
 		defer close(resultChan)
+		if err := c.current().limiter.wait(ctx, host); err != nil {
+			resultChan <- domain.TraceHop{Host: domain.NetworkHost{Hostname: host}, Error: err, Timestamp: time.Now()}
+			return
+		}
 		c.executeTraceroute(ctx, host, opts, resultChan)
 	}()
 
@@ -76,6 +169,7 @@ func (c *Client) Traceroute(ctx context.Context, host string, opts domain.TraceO
 
 // DNSLookup performs DNS lookups for the specified domain and record type
 func (c *Client) DNSLookup(ctx context.Context, domainName string, recordType domain.DNSRecordType) (domain.DNSResult, error) {
+	state := c.current()
 	if err := c.validateDomain(domainName); err != nil {
 		return domain.DNSResult{}, &domain.NetTraceError{
 			Type:      domain.ErrorTypeValidation,
@@ -87,21 +181,35 @@ func (c *Client) DNSLookup(ctx context.Context, domainName string, recordType do
 		}
 	}
 
-	result, err := c.retryManager.ExecuteWithRetry(ctx, func() (interface{}, error) {
+	key := dnsCacheKey(ctx, "", domainName, recordType)
+	if cached, ok := state.cache.lookupDNS(ctx, key); ok {
+		return cached, nil
+	}
+
+	release, err := state.limiter.acquire(ctx, domainName)
+	if err != nil {
+		return domain.DNSResult{}, err
+	}
+	defer release()
+
+	result, err := state.retryManager.ExecuteWithRetry(ctx, func() (interface{}, error) {
 		return c.executeDNSLookup(ctx, domainName, recordType)
 	}, func(err error) bool {
 		return c.isRetryableNetworkError(err)
 	})
 	
 	if err != nil {
-		return domain.DNSResult{}, err
+		return domain.DNSResult{}, classifyError(err, "DNS lookup failed", "DNS_LOOKUP_FAILED")
 	}
 	
-	return result.(domain.DNSResult), nil
+	dnsResult := result.(domain.DNSResult)
+	state.cache.storeDNS(key, dnsResult)
+	return dnsResult, nil
 }
 
 // WHOISLookup performs WHOIS lookups for the specified query
 func (c *Client) WHOISLookup(ctx context.Context, query string) (domain.WHOISResult, error) {
+	state := c.current()
 	if err := c.validateQuery(query); err != nil {
 		return domain.WHOISResult{}, &domain.NetTraceError{
 			Type:      domain.ErrorTypeValidation,
@@ -113,21 +221,35 @@ func (c *Client) WHOISLookup(ctx context.Context, query string) (domain.WHOISRes
 		}
 	}
 
-	result, err := c.retryManager.ExecuteWithRetry(ctx, func() (interface{}, error) {
+	key := whoisCacheKey(query)
+	if cached, ok := state.cache.lookupWHOIS(ctx, key); ok {
+		return cached, nil
+	}
+
+	release, err := state.limiter.acquire(ctx, query)
+	if err != nil {
+		return domain.WHOISResult{}, err
+	}
+	defer release()
+
+	result, err := state.retryManager.ExecuteWithRetry(ctx, func() (interface{}, error) {
 		return c.executeWHOISLookup(ctx, query)
 	}, func(err error) bool {
 		return c.isRetryableNetworkError(err)
 	})
 	
 	if err != nil {
-		return domain.WHOISResult{}, err
+		return domain.WHOISResult{}, classifyError(err, "WHOIS lookup failed", "WHOIS_LOOKUP_FAILED")
 	}
 	
-	return result.(domain.WHOISResult), nil
+	whoisResult := result.(domain.WHOISResult)
+	state.cache.storeWHOIS(key, whoisResult)
+	return whoisResult, nil
 }
 
 // SSLCheck performs SSL certificate checks for the specified host and port
 func (c *Client) SSLCheck(ctx context.Context, host string, port int) (domain.SSLResult, error) {
+	state := c.current()
 	if err := c.validateHost(host); err != nil {
 		return domain.SSLResult{}, &domain.NetTraceError{
 			Type:      domain.ErrorTypeValidation,
@@ -149,19 +271,91 @@ func (c *Client) SSLCheck(ctx context.Context, host string, port int) (domain.SS
 		}
 	}
 
-	result, err := c.retryManager.ExecuteWithRetry(ctx, func() (interface{}, error) {
+	release, err := state.limiter.acquire(ctx, host)
+	if err != nil {
+		return domain.SSLResult{}, err
+	}
+	defer release()
+
+	result, err := state.retryManager.ExecuteWithRetry(ctx, func() (interface{}, error) {
 		return c.executeSSLCheck(ctx, host, port)
 	}, func(err error) bool {
 		return c.isRetryableNetworkError(err)
 	})
 	
 	if err != nil {
-		return domain.SSLResult{}, err
+		return domain.SSLResult{}, classifyError(err, "SSL check failed", "SSL_CHECK_FAILED")
 	}
 	
 	return result.(domain.SSLResult), nil
 }
 
+// TCPConnect measures how long a TCP handshake to the given address takes
+func (c *Client) TCPConnect(ctx context.Context, ip net.IP, port int) (time.Duration, error) {
+	state := c.current()
+	if ip == nil {
+		return 0, &domain.NetTraceError{
+			Type:      domain.ErrorTypeValidation,
+			Message:   "invalid address for TCP connect",
+			Context:   map[string]interface{}{"port": port},
+			Timestamp: time.Now(),
+			Code:      "CONNECT_INVALID_ADDRESS",
+		}
+	}
+
+	release, err := state.limiter.acquire(ctx, ip.String())
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	network := "tcp4"
+	if ip.To4() == nil {
+		network = "tcp6"
+	}
+
+	address := net.JoinHostPort(ip.String(), fmt.Sprintf("%d", port))
+	dialer, err := newDialer(ctx, state.config.Timeout, address)
+	if err != nil {
+		return 0, &domain.NetTraceError{
+			Type:      domain.ErrorTypeValidation,
+			Message:   "invalid source for TCP connect",
+			Cause:     err,
+			Context:   map[string]interface{}{"address": address, "source": SourceFromContext(ctx)},
+			Timestamp: time.Now(),
+			Code:      "CONNECT_INVALID_SOURCE",
+		}
+	}
+	_, span := tracing.Start(ctx, "connect", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("server.address", address),
+		attribute.String("network.transport", network),
+	))
+	defer span.End()
+	if state.config.Proxy.TCP != "" {
+		span.SetAttributes(attribute.String("nettracex.proxy", proxy.Redact(state.config.Proxy.TCP)))
+	}
+
+	// Through a proxy this measures the proxy handshake and its connect to address
+	start := time.Now()
+	conn, err := proxy.Dial(ctx, dialer, state.config.Proxy.TCP, network, address)
+	elapsed := time.Since(start)
+	tracing.Fail(span, err)
+	if err != nil {
+		return elapsed, &domain.NetTraceError{
+			Type:      domain.ErrorTypeNetwork,
+			Message:   "TCP connection failed",
+			Cause:     err,
+			Context:   map[string]interface{}{"address": address},
+			Timestamp: time.Now(),
+			Code:      "CONNECT_FAILED",
+			Kind:      ClassifyError(err),
+		}
+	}
+	conn.Close()
+
+	return elapsed, nil
+}
+
 // validateHost validates that the host is a valid hostname or IP address
 func (c *Client) validateHost(host string) error {
 	if host == "" {
@@ -205,8 +399,87 @@ func (c *Client) validateQuery(query string) error {
 
 // isRetryableNetworkError determines if an error is retryable
 func (c *Client) isRetryableNetworkError(err error) bool {
-	if netErr, ok := err.(net.Error); ok {
-		return netErr.Timeout() || netErr.Temporary()
+	return IsRetryable(err)
+}
+
+// ReverseLookup returns the names registered for ip, without trailing dots
+func (c *Client) ReverseLookup(ctx context.Context, ip net.IP) ([]string, error) {
+	state := c.current()
+	if ip == nil {
+		return nil, &domain.NetTraceError{
+			Type:      domain.ErrorTypeValidation,
+			Message:   "invalid address for reverse lookup",
+			Timestamp: time.Now(),
+			Code:      "REVERSE_INVALID_ADDRESS",
+		}
 	}
-	return false
-}
\ No newline at end of file
+
+	if state.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, state.config.Timeout)
+		defer cancel()
+	}
+
+	ctx, span := tracing.Start(ctx, "resolve", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attribute.String("network.peer.address", ip.String())))
+	defer span.End()
+
+	names, err := net.DefaultResolver.LookupAddr(ctx, ip.String())
+	tracing.Fail(span, err)
+	if err != nil {
+		return nil, &domain.NetTraceError{
+			Type:      domain.ErrorTypeNetwork,
+			Message:   "reverse lookup failed",
+			Cause:     err,
+			Context:   map[string]interface{}{"ip": ip.String()},
+			Timestamp: time.Now(),
+			Code:      "REVERSE_LOOKUP_FAILED",
+			Kind:      ClassifyError(err),
+		}
+	}
+
+	for i, name := range names {
+		names[i] = strings.TrimSuffix(name, ".")
+	}
+	return names, nil
+}
+
+// ZoneTransfer requests an AXFR of zone from server, an IP address with an
+// optional port. A server that denies the transfer fails with code
+// AXFR_REFUSED; the returned count includes records of unsupported types.
+func (c *Client) ZoneTransfer(ctx context.Context, server, zone string) ([]domain.DNSRecord, int, error) {
+	address := dnsServerAddress(server)
+	ctx, span := tracing.Start(ctx, "zone transfer query", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("dns.question.name", zone),
+		attribute.String("server.address", address),
+	))
+	defer span.End()
+
+	rrs, err := transferZone(ctx, address, zone, c.current().config.Timeout)
+	tracing.Fail(span, err)
+	if err != nil {
+		code := "AXFR_FAILED"
+		message := "zone transfer failed"
+		if errors.Is(err, errZoneTransferRefused) {
+			code = "AXFR_REFUSED"
+			message = "zone transfer refused"
+		}
+		return nil, 0, &domain.NetTraceError{
+			Type:      domain.ErrorTypeNetwork,
+			Message:   message,
+			Cause:     err,
+			Context:   map[string]interface{}{"server": address, "zone": zone},
+			Timestamp: time.Now(),
+			Code:      code,
+			Kind:      ClassifyError(err),
+		}
+	}
+
+	records := make([]domain.DNSRecord, 0, len(rrs))
+	for _, rr := range rrs {
+		if record, ok := dnsRecord(rr); ok {
+			records = append(records, record)
+		}
+	}
+	c.logger.Info("Zone transfer allowed", "server", address, "zone", zone, "records", len(rrs))
+	return records, len(rrs), nil
+}
@@ -43,7 +43,7 @@ func TestNewClient(t *testing.T) {
 		t.Fatal("NewClient returned nil")
 	}
 
-	if client.config != config {
+	if client.current().config.Timeout != config.Timeout {
 		t.Error("Client config not set correctly")
 	}
 
@@ -55,7 +55,7 @@ func TestNewClient(t *testing.T) {
 		t.Error("Client logger not set correctly")
 	}
 
-	if client.retryManager == nil {
+	if client.current().retryManager == nil {
 		t.Error("Client retry manager not initialized")
 	}
 }
@@ -113,6 +113,163 @@ func TestClient_Ping_ValidHost(t *testing.T) {
 	}
 }
 
+func TestClient_ApplyPingLimits(t *testing.T) {
+	config := &domain.NetworkConfig{
+		MinPingInterval: 50 * time.Millisecond,
+		MaxFloodCount:   100,
+	}
+	client := NewClient(config, &mockErrorHandler{}, &mockLogger{})
+
+	tests := []struct {
+		name             string
+		opts             domain.PingOptions
+		expectedCount    int
+		expectedInterval time.Duration
+		expectedMode     domain.PingMode
+	}{
+		{
+			name:             "normal interval above minimum",
+			opts:             domain.PingOptions{Count: 4, Interval: time.Second},
+			expectedCount:    4,
+			expectedInterval: time.Second,
+			expectedMode:     domain.PingModeNormal,
+		},
+		{
+			name:             "normal interval raised to minimum",
+			opts:             domain.PingOptions{Count: 4, Interval: 10 * time.Millisecond, Mode: domain.PingModeNormal},
+			expectedCount:    4,
+			expectedInterval: 50 * time.Millisecond,
+			expectedMode:     domain.PingModeNormal,
+		},
+		{
+			name:             "adaptive keeps interval",
+			opts:             domain.PingOptions{Count: 4, Mode: domain.PingModeAdaptive},
+			expectedCount:    4,
+			expectedInterval: 0,
+			expectedMode:     domain.PingModeAdaptive,
+		},
+		{
+			name:          "flood count capped",
+			opts:          domain.PingOptions{Count: 5000, Mode: domain.PingModeFlood},
+			expectedCount: 100,
+			expectedMode:  domain.PingModeFlood,
+		},
+		{
+			name:          "flood without count uses cap",
+			opts:          domain.PingOptions{Mode: domain.PingModeFlood},
+			expectedCount: 100,
+			expectedMode:  domain.PingModeFlood,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := client.applyPingLimits(tt.opts)
+			if opts.Count != tt.expectedCount {
+				t.Errorf("Expected count %d, got %d", tt.expectedCount, opts.Count)
+			}
+			if opts.Interval != tt.expectedInterval {
+				t.Errorf("Expected interval %v, got %v", tt.expectedInterval, opts.Interval)
+			}
+			if opts.Mode != tt.expectedMode {
+				t.Errorf("Expected mode %s, got %s", tt.expectedMode, opts.Mode)
+			}
+		})
+	}
+
+	// Unset limits fall back to the built-in defaults
+	client = NewClient(&domain.NetworkConfig{}, &mockErrorHandler{}, &mockLogger{})
+	minInterval, maxFlood := client.pingLimits()
+	if minInterval != DefaultMinPingInterval || maxFlood != DefaultMaxFloodCount {
+		t.Errorf("Expected default limits, got %v and %d", minInterval, maxFlood)
+	}
+}
+
+func TestClient_Ping_FloodMode(t *testing.T) {
+	config := &domain.NetworkConfig{
+		Timeout:        time.Second,
+		MaxConcurrency: 4,
+		MaxFloodCount:  20,
+	}
+	client := NewClient(config, &mockErrorHandler{}, &mockLogger{})
+
+	resultChan, err := client.Ping(context.Background(), "127.0.0.1", domain.PingOptions{
+		Timeout:    time.Second,
+		PacketSize: 64,
+		Mode:       domain.PingModeFlood,
+	})
+	if err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+
+	seen := make(map[int]bool)
+	for result := range resultChan {
+		if seen[result.Sequence] {
+			t.Errorf("Duplicate sequence %d", result.Sequence)
+		}
+		seen[result.Sequence] = true
+	}
+
+	if len(seen) != config.MaxFloodCount {
+		t.Errorf("Expected %d flood results, got %d", config.MaxFloodCount, len(seen))
+	}
+	for i := 1; i <= config.MaxFloodCount; i++ {
+		if !seen[i] {
+			t.Errorf("Missing sequence %d", i)
+		}
+	}
+}
+
+func TestClient_Ping_SlotPerProbe(t *testing.T) {
+	config := &domain.NetworkConfig{Timeout: time.Second, MaxConcurrency: 1, MinPingInterval: 10 * time.Millisecond}
+	client := NewClient(config, &mockErrorHandler{}, &mockLogger{})
+
+	resultChan, err := client.Ping(context.Background(), "127.0.0.1", domain.PingOptions{
+		Count:    2,
+		Interval: 500 * time.Millisecond,
+		Timeout:  time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	<-resultChan
+
+	// Between probes the only slot is free for other operations
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	release, err := client.current().limiter.acquire(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("Expected a free slot between probes: %v", err)
+	}
+	release()
+	for range resultChan {
+	}
+}
+
+func TestClient_Ping_SlotCancelled(t *testing.T) {
+	client := NewClient(&domain.NetworkConfig{Timeout: time.Second, MaxConcurrency: 1}, &mockErrorHandler{}, &mockLogger{})
+	release, err := client.current().limiter.acquire(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	resultChan, err := client.Ping(ctx, "127.0.0.1", domain.PingOptions{Count: 1, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+
+	result, ok := <-resultChan
+	if !ok || result.Error == nil {
+		t.Fatal("Expected an error result when no slot frees up")
+	}
+	if result.Error != context.DeadlineExceeded {
+		t.Errorf("Expected the context error, got %v", result.Error)
+	}
+}
+
 func TestClient_Ping_InvalidHost(t *testing.T) {
 	config := &domain.NetworkConfig{
 		Timeout:       5 * time.Second,
@@ -662,4 +819,59 @@ func TestClient_SSLCheck_WithRetryFailure(t *testing.T) {
 	if netErr.Type != domain.ErrorTypeNetwork {
 		t.Errorf("Expected network error, got %v", netErr.Type)
 	}
-}
\ No newline at end of file
+}
+func TestClient_Reconfigure(t *testing.T) {
+	config := &domain.NetworkConfig{Timeout: time.Second, MaxConcurrency: 2, RetryAttempts: 2, DNSServers: []string{"1.1.1.1"}}
+	client := NewClient(config, &mockErrorHandler{}, &mockLogger{})
+	before := client.current()
+
+	// Settings the components do not depend on keep them
+	changed := *config
+	changed.Timeout = 5 * time.Second
+	client.Reconfigure(changed)
+	after := client.current()
+	if after.config.Timeout != 5*time.Second {
+		t.Errorf("Expected the new timeout, got %v", after.config.Timeout)
+	}
+	if after.limiter != before.limiter || after.retryManager != before.retryManager || after.dnsHealth != before.dnsHealth {
+		t.Error("Expected the limiter, retry manager and DNS server health to be kept")
+	}
+	if before.config.Timeout != time.Second {
+		t.Error("Expected the previous snapshot to be left unchanged")
+	}
+
+	changed.MaxConcurrency = 4
+	changed.RetryAttempts = 5
+	changed.DNSServers = []string{"9.9.9.9"}
+	client.Reconfigure(changed)
+	rebuilt := client.current()
+	if rebuilt.limiter == after.limiter {
+		t.Error("Expected the limiter to be rebuilt for the new concurrency")
+	}
+	if rebuilt.retryManager == after.retryManager {
+		t.Error("Expected the retry manager to be rebuilt for the new attempts")
+	}
+	if rebuilt.dnsHealth == after.dnsHealth {
+		t.Error("Expected the DNS server health to be reset for the new servers")
+	}
+	if servers := client.dnsServers(context.Background()); len(servers) != 1 || servers[0] != "9.9.9.9" {
+		t.Errorf("Expected the new DNS servers, got %v", servers)
+	}
+}
+
+func TestClient_ReconfigureWhileRunning(t *testing.T) {
+	client := NewClient(&domain.NetworkConfig{Timeout: time.Second, DNSServers: []string{"1.1.1.1"}}, &mockErrorHandler{}, &mockLogger{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			client.dnsServers(context.Background())
+			client.DNSServerStatus()
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		client.Reconfigure(domain.NetworkConfig{Timeout: time.Duration(i+1) * time.Millisecond, DNSServers: []string{"9.9.9.9"}})
+	}
+	<-done
+}
@@ -0,0 +1,174 @@
+// Package network provides DNS wire protocol queries that report record TTLs
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// dnsRecordTypes maps supported domain record types to wire types
+var dnsRecordTypes = map[domain.DNSRecordType]uint16{
+	domain.DNSRecordTypeA:     dns.TypeA,
+	domain.DNSRecordTypeAAAA:  dns.TypeAAAA,
+	domain.DNSRecordTypeMX:    dns.TypeMX,
+	domain.DNSRecordTypeTXT:   dns.TypeTXT,
+	domain.DNSRecordTypeCNAME: dns.TypeCNAME,
+	domain.DNSRecordTypeNS:    dns.TypeNS,
+}
+
+// exchangeDNS sends a recursive query for name and qtype to address over
+// UDP, retrying over TCP when the response is truncated
+func exchangeDNS(ctx context.Context, address, name string, qtype uint16, timeout time.Duration) (*dns.Msg, error) {
+	query := new(dns.Msg)
+	query.SetQuestion(dns.Fqdn(name), qtype)
+
+	response, err := exchangeDNSOver(ctx, "udp", address, query, timeout)
+	if err != nil || !response.Truncated {
+		return response, err
+	}
+	return exchangeDNSOver(ctx, "tcp", address, query, timeout)
+}
+
+// exchangeDNSOver sends query to address over the given network from the
+// source address bound to ctx
+func exchangeDNSOver(ctx context.Context, network, address string, query *dns.Msg, timeout time.Duration) (*dns.Msg, error) {
+	dialer, err := newDialer(ctx, timeout, address)
+	if err != nil {
+		return nil, err
+	}
+	client := &dns.Client{Net: network, Dialer: dialer, Timeout: timeout}
+	response, _, err := client.ExchangeContext(ctx, query, address)
+	return response, err
+}
+
+// errZoneTransferRefused is returned when a server answers an AXFR with an error rcode
+var errZoneTransferRefused = errors.New("zone transfer refused")
+
+// transferZone requests a full zone transfer of zone from address over TCP
+// and returns every record up to the closing SOA. Servers that deny the
+// transfer answer with an error rcode or an empty answer, which is reported
+// as errZoneTransferRefused.
+func transferZone(ctx context.Context, address, zone string, timeout time.Duration) ([]dns.RR, error) {
+	dialer, err := newDialer(ctx, timeout, address)
+	if err != nil {
+		return nil, err
+	}
+	client := &dns.Client{Net: "tcp", Dialer: dialer, Timeout: timeout}
+	conn, err := client.DialContext(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	// Ending ctx closes the connection, which ends a transfer blocked on a read
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	query := new(dns.Msg)
+	query.SetAxfr(dns.Fqdn(zone))
+	if timeout > 0 {
+		conn.SetWriteDeadline(time.Now().Add(timeout))
+	}
+	if err := conn.WriteMsg(query); err != nil {
+		return nil, err
+	}
+
+	// The transfer starts and ends with the zone's SOA record and may span
+	// several messages
+	var records []dns.RR
+	for {
+		if timeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(timeout))
+		}
+		response, err := conn.ReadMsg()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if len(records) > 0 {
+				return nil, fmt.Errorf("zone transfer ended after %d records: %w", len(records), err)
+			}
+			return nil, err
+		}
+		if response.Id != query.Id {
+			return nil, dns.ErrId
+		}
+		if response.Rcode != dns.RcodeSuccess {
+			return nil, fmt.Errorf("%w (%s)", errZoneTransferRefused, dns.RcodeToString[response.Rcode])
+		}
+		if len(response.Answer) == 0 {
+			return nil, fmt.Errorf("%w (empty answer)", errZoneTransferRefused)
+		}
+
+		for _, rr := range response.Answer {
+			soa := rr.Header().Rrtype == dns.TypeSOA
+			if len(records) == 0 && !soa {
+				return nil, errors.New("zone transfer does not start with SOA")
+			}
+			if len(records) > 0 && soa {
+				return records, nil
+			}
+			records = append(records, rr)
+		}
+	}
+}
+
+// dnsRecord converts rr to a domain record, reporting false for record types
+// the domain model does not represent
+func dnsRecord(rr dns.RR) (domain.DNSRecord, bool) {
+	header := rr.Header()
+	record := domain.DNSRecord{Name: strings.TrimSuffix(header.Name, "."), TTL: header.Ttl}
+	switch rr := rr.(type) {
+	case *dns.A:
+		record.Type, record.Value = domain.DNSRecordTypeA, rr.A.String()
+	case *dns.AAAA:
+		record.Type, record.Value = domain.DNSRecordTypeAAAA, rr.AAAA.String()
+	case *dns.MX:
+		record.Type, record.Value, record.Priority = domain.DNSRecordTypeMX, rr.Mx, int(rr.Preference)
+	case *dns.TXT:
+		record.Type, record.Value = domain.DNSRecordTypeTXT, unescapeTXT(rr.Txt)
+	case *dns.CNAME:
+		record.Type, record.Value = domain.DNSRecordTypeCNAME, rr.Target
+	case *dns.NS:
+		record.Type, record.Value = domain.DNSRecordTypeNS, rr.Ns
+	case *dns.SOA:
+		record.Type, record.Value = domain.DNSRecordTypeSOA, fmt.Sprintf("%s %s %d", rr.Ns, rr.Mbox, rr.Serial)
+	case *dns.PTR:
+		record.Type, record.Value = domain.DNSRecordTypePTR, rr.Ptr
+	default:
+		return domain.DNSRecord{}, false
+	}
+	return record, true
+}
+
+// unescapeTXT joins the character strings of a TXT record, undoing the
+// \" \\ and \DDD escapes the dns package applies to them
+func unescapeTXT(parts []string) string {
+	var value []byte
+	for _, part := range parts {
+		for i := 0; i < len(part); i++ {
+			c := part[i]
+			if c == '\\' && i+1 < len(part) {
+				if i+3 < len(part) && isDigit(part[i+1]) && isDigit(part[i+2]) && isDigit(part[i+3]) {
+					c = (part[i+1]-'0')*100 + (part[i+2]-'0')*10 + part[i+3] - '0'
+					i += 3
+				} else {
+					i++
+					c = part[i]
+				}
+			}
+			value = append(value, c)
+		}
+	}
+	return string(value)
+}
+
+// isDigit reports whether c is an ASCII digit
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
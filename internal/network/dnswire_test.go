@@ -0,0 +1,209 @@
+// Package network provides tests for the DNS wire protocol client
+package network
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// mustRR parses a record in zone file format
+func mustRR(t *testing.T, s string) dns.RR {
+	t.Helper()
+	rr, err := dns.NewRR(s)
+	if err != nil {
+		t.Fatalf("Invalid record %q: %v", s, err)
+	}
+	return rr
+}
+
+// startDNSServer serves handler over UDP on a loopback port until the test ends
+func startDNSServer(t *testing.T, handler dns.HandlerFunc) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("Cannot listen on loopback: %v", err)
+	}
+	server := &dns.Server{PacketConn: conn, Handler: handler}
+	go server.ActivateAndServe()
+	t.Cleanup(func() { server.Shutdown() })
+
+	return conn.LocalAddr().String()
+}
+
+// startTCPDNSServer serves handler over TCP on a loopback port until the test ends
+func startTCPDNSServer(t *testing.T, handler dns.HandlerFunc) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("Cannot listen on loopback: %v", err)
+	}
+	server := &dns.Server{Listener: listener, Handler: handler}
+	go server.ActivateAndServe()
+	t.Cleanup(func() { server.Shutdown() })
+
+	return listener.Addr().String()
+}
+
+func TestDNSRecord(t *testing.T) {
+	tests := []struct {
+		rr       string
+		expected domain.DNSRecord
+	}{
+		{"example.com. 3600 IN MX 10 mail.example.com.", domain.DNSRecord{Name: "example.com", Type: domain.DNSRecordTypeMX, Value: "mail.example.com.", TTL: 3600, Priority: 10}},
+		{`example.com. 60 IN TXT "hello" " world"`, domain.DNSRecord{Name: "example.com", Type: domain.DNSRecordTypeTXT, Value: "hello world", TTL: 60}},
+		{`example.com. 60 IN TXT "say \"hi\" \\ caf\195\169"`, domain.DNSRecord{Name: "example.com", Type: domain.DNSRecordTypeTXT, Value: `say "hi" \ café`, TTL: 60}},
+		{"example.com. 299 IN A 93.184.216.34", domain.DNSRecord{Name: "example.com", Type: domain.DNSRecordTypeA, Value: "93.184.216.34", TTL: 299}},
+		{"example.com. 3600 IN SOA ns.example.com. root.example.com. 42 7200 900 1209600 300", domain.DNSRecord{Name: "example.com", Type: domain.DNSRecordTypeSOA, Value: "ns.example.com. root.example.com. 42", TTL: 3600}},
+		{"1.2.0.192.in-addr.arpa. 300 IN PTR host.example.com.", domain.DNSRecord{Name: "1.2.0.192.in-addr.arpa", Type: domain.DNSRecordTypePTR, Value: "host.example.com.", TTL: 300}},
+	}
+
+	for _, tt := range tests {
+		record, ok := dnsRecord(mustRR(t, tt.rr))
+		if !ok || record != tt.expected {
+			t.Errorf("%s: expected %+v, got %+v (%v)", tt.rr, tt.expected, record, ok)
+		}
+	}
+
+	if _, ok := dnsRecord(mustRR(t, "example.com. 300 IN SRV 0 5 5060 sip.example.com.")); ok {
+		t.Error("Expected unsupported record type to be skipped")
+	}
+}
+
+func TestHostsFileLists(t *testing.T) {
+	hosts := "127.0.0.1 localhost\n# 10.0.0.1 commented.example\n10.0.0.2 Router.LAN router # gateway\n"
+
+	for name, expected := range map[string]bool{
+		"localhost":         true,
+		"router.lan.":       true,
+		"router":            true,
+		"commented.example": false,
+		"gateway":           false,
+		"example.com":       false,
+	} {
+		if got := hostsFileLists(hosts, name); got != expected {
+			t.Errorf("hostsFileLists(%q) = %v, want %v", name, got, expected)
+		}
+	}
+}
+
+func TestClient_WireLookup(t *testing.T) {
+	address := startDNSServer(t, func(w dns.ResponseWriter, query *dns.Msg) {
+		response := new(dns.Msg).SetReply(query)
+		if query.Question[0].Name != "example.com." {
+			response.Rcode = dns.RcodeNameError
+		} else {
+			response.Answer = []dns.RR{
+				mustRR(t, "example.com. 30 IN CNAME example.com."),
+				mustRR(t, "example.com. 1234 IN A 192.0.2.1"),
+			}
+		}
+		w.WriteMsg(response)
+	})
+
+	config := &domain.NetworkConfig{Timeout: 2 * time.Second, DNSServers: []string{address}}
+	client := NewClient(config, &mockErrorHandler{}, &mockLogger{})
+
+	result, err := client.DNSLookup(context.Background(), "example.com", domain.DNSRecordTypeA)
+	if err != nil {
+		t.Fatalf("DNS lookup failed: %v", err)
+	}
+	if len(result.Records) != 1 {
+		t.Fatalf("Expected only the A record, got %+v", result.Records)
+	}
+	if record := result.Records[0]; record.Value != "192.0.2.1" || record.TTL != 1234 {
+		t.Errorf("Expected 192.0.2.1 with the server's TTL 1234, got %+v", record)
+	}
+	if result.Server != address {
+		t.Errorf("Expected server %s, got %s", address, result.Server)
+	}
+
+	_, err = client.wireLookup(context.Background(), address, "missing.example", domain.DNSRecordTypeA)
+	if !isDNSAnswerError(err) {
+		t.Errorf("Expected not found answer for NXDOMAIN, got %v", err)
+	}
+}
+
+func TestExchangeDNS_TruncatedRetriesOverTCP(t *testing.T) {
+	address := startTCPDNSServer(t, func(w dns.ResponseWriter, query *dns.Msg) {
+		response := new(dns.Msg).SetReply(query)
+		response.Answer = []dns.RR{mustRR(t, "example.com. 60 IN A 192.0.2.7")}
+		w.WriteMsg(response)
+	})
+
+	conn, err := net.ListenPacket("udp", address)
+	if err != nil {
+		t.Skipf("Cannot listen on UDP %s: %v", address, err)
+	}
+	server := &dns.Server{PacketConn: conn, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, query *dns.Msg) {
+		response := new(dns.Msg).SetReply(query)
+		response.Truncated = true
+		w.WriteMsg(response)
+	})}
+	go server.ActivateAndServe()
+	t.Cleanup(func() { server.Shutdown() })
+
+	response, err := exchangeDNS(context.Background(), address, "example.com", dns.TypeA, 2*time.Second)
+	if err != nil {
+		t.Fatalf("Exchange failed: %v", err)
+	}
+	if response.Truncated || len(response.Answer) != 1 {
+		t.Errorf("Expected the full answer over TCP, got %v", response)
+	}
+}
+
+func TestClient_ZoneTransfer(t *testing.T) {
+	soa := mustRR(t, "example.com. 3600 IN SOA ns.example.com. root.example.com. 42 7200 900 1209600 300")
+	address := startTCPDNSServer(t, func(w dns.ResponseWriter, query *dns.Msg) {
+		if query.Question[0].Qtype != dns.TypeAXFR {
+			w.WriteMsg(new(dns.Msg).SetRcode(query, dns.RcodeRefused))
+			return
+		}
+		first := new(dns.Msg).SetReply(query)
+		first.Answer = []dns.RR{soa, mustRR(t, "www.example.com. 300 IN A 192.0.2.1")}
+		w.WriteMsg(first)
+
+		second := new(dns.Msg).SetReply(query)
+		second.Answer = []dns.RR{mustRR(t, "_sip._udp.example.com. 300 IN SRV 0 5 5060 sip.example.com."), soa}
+		w.WriteMsg(second)
+	})
+
+	config := &domain.NetworkConfig{Timeout: 2 * time.Second}
+	client := NewClient(config, &mockErrorHandler{}, &mockLogger{})
+
+	records, total, err := client.ZoneTransfer(context.Background(), address, "example.com")
+	if err != nil {
+		t.Fatalf("Zone transfer failed: %v", err)
+	}
+	if total != 3 {
+		t.Errorf("Expected 3 records up to the closing SOA, got %d", total)
+	}
+	if len(records) != 2 || records[0].Type != domain.DNSRecordTypeSOA || records[1].Value != "192.0.2.1" {
+		t.Fatalf("Expected the SOA and A records, got %+v", records)
+	}
+	if records[0].Value != "ns.example.com. root.example.com. 42" {
+		t.Errorf("Unexpected SOA value %q", records[0].Value)
+	}
+}
+
+func TestClient_ZoneTransfer_Refused(t *testing.T) {
+	address := startTCPDNSServer(t, func(w dns.ResponseWriter, query *dns.Msg) {
+		w.WriteMsg(new(dns.Msg).SetRcode(query, dns.RcodeRefused))
+	})
+
+	config := &domain.NetworkConfig{Timeout: 2 * time.Second}
+	client := NewClient(config, &mockErrorHandler{}, &mockLogger{})
+
+	_, _, err := client.ZoneTransfer(context.Background(), address, "example.com")
+	var netErr *domain.NetTraceError
+	if !errors.As(err, &netErr) || netErr.Code != "AXFR_REFUSED" {
+		t.Errorf("Expected AXFR_REFUSED error, got %v", err)
+	}
+}
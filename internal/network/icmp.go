@@ -0,0 +1,69 @@
+// Package network provides ICMP echo message encoding shared by all platforms
+package network
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+)
+
+const (
+	icmpEchoReply   = 0
+	icmpEchoRequest = 8
+
+	// icmpHeaderLen is the length of an ICMP echo header
+	icmpHeaderLen = 8
+)
+
+// errICMPUnavailable reports that ICMP sockets cannot be used on this system,
+// either because the platform is unsupported or the process lacks permission
+var errICMPUnavailable = errors.New("icmp sockets unavailable")
+
+// icmpIdentifier identifies echo requests sent by this process
+var icmpIdentifier = os.Getpid() & 0xffff
+
+// echoRequest builds an ICMP echo request of size bytes including the header
+func echoRequest(id, seq, size int) []byte {
+	if size < icmpHeaderLen {
+		size = icmpHeaderLen
+	}
+
+	packet := make([]byte, size)
+	packet[0] = icmpEchoRequest
+	binary.BigEndian.PutUint16(packet[4:], uint16(id))
+	binary.BigEndian.PutUint16(packet[6:], uint16(seq))
+	for i := icmpHeaderLen; i < size; i++ {
+		packet[i] = byte(i)
+	}
+
+	binary.BigEndian.PutUint16(packet[2:], icmpChecksum(packet))
+	return packet
+}
+
+// isEchoReply reports whether message is the reply to the echo request with
+// the given sequence number. When checkID is false the identifier is ignored,
+// since the kernel rewrites it for unprivileged datagram sockets.
+func isEchoReply(message []byte, id, seq int, checkID bool) bool {
+	if len(message) < icmpHeaderLen || message[0] != icmpEchoReply || message[1] != 0 {
+		return false
+	}
+	if checkID && int(binary.BigEndian.Uint16(message[4:])) != id&0xffff {
+		return false
+	}
+	return int(binary.BigEndian.Uint16(message[6:])) == seq&0xffff
+}
+
+// icmpChecksum computes the RFC 1071 internet checksum of data
+func icmpChecksum(data []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(data); i += 2 {
+		sum += uint32(data[i])<<8 | uint32(data[i+1])
+	}
+	if len(data)%2 == 1 {
+		sum += uint32(data[len(data)-1]) << 8
+	}
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+	return ^uint16(sum)
+}
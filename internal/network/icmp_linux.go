@@ -0,0 +1,133 @@
+//go:build linux
+
+// Package network provides ICMP echo probes that report the reply TTL on Linux
+package network
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+)
+
+// icmpEcho sends one ICMP echo request to ip and waits for the matching reply,
+// returning the round trip time and the TTL of the reply. The request is sent
+// from source when it is set. Only IPv4 is supported; errICMPUnavailable is
+// returned when no ICMP socket can be opened.
+func icmpEcho(ip, source net.IP, id, seq, size int, timeout time.Duration) (time.Duration, int, error) {
+	v4 := ip.To4()
+	if v4 == nil {
+		return 0, 0, errICMPUnavailable
+	}
+
+	fd, raw, err := openICMPSocket()
+	if err != nil {
+		return 0, 0, errICMPUnavailable
+	}
+	defer syscall.Close(fd)
+
+	// Raw sockets deliver the IP header; datagram sockets report the TTL as
+	// ancillary data once asked to
+	if !raw {
+		if err := syscall.SetsockoptInt(fd, syscall.IPPROTO_IP, syscall.IP_RECVTTL, 1); err != nil {
+			return 0, 0, errICMPUnavailable
+		}
+	}
+
+	if source != nil {
+		if source.To4() == nil {
+			return 0, 0, fmt.Errorf("source %s cannot send to IPv4 address %s", source, ip)
+		}
+		local := &syscall.SockaddrInet4{}
+		copy(local.Addr[:], source.To4())
+		if err := syscall.Bind(fd, local); err != nil {
+			return 0, 0, fmt.Errorf("failed to bind to source %s: %w", source, err)
+		}
+	}
+
+	addr := &syscall.SockaddrInet4{}
+	copy(addr.Addr[:], v4)
+
+	start := time.Now()
+	deadline := start.Add(timeout)
+	if err := syscall.Sendto(fd, echoRequest(id, seq, size), 0, addr); err != nil {
+		return 0, 0, fmt.Errorf("failed to send echo request: %w", err)
+	}
+
+	buf := make([]byte, 1500+size)
+	oob := make([]byte, syscall.CmsgSpace(4))
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return 0, 0, fmt.Errorf("request timeout for icmp_seq %d", seq)
+		}
+		tv := syscall.NsecToTimeval(remaining.Nanoseconds())
+		if err := syscall.SetsockoptTimeval(fd, syscall.SOL_SOCKET, syscall.SO_RCVTIMEO, &tv); err != nil {
+			return 0, 0, err
+		}
+
+		n, oobn, _, from, err := syscall.Recvmsg(fd, buf, oob, 0)
+		if err != nil {
+			if err == syscall.EINTR {
+				continue
+			}
+			if err == syscall.EAGAIN || err == syscall.EWOULDBLOCK {
+				return 0, 0, fmt.Errorf("request timeout for icmp_seq %d", seq)
+			}
+			return 0, 0, fmt.Errorf("failed to receive echo reply: %w", err)
+		}
+		rtt := time.Since(start)
+
+		if sender, ok := from.(*syscall.SockaddrInet4); ok && !net.IP(sender.Addr[:]).Equal(v4) {
+			continue
+		}
+
+		message, ttl := buf[:n], 0
+		if raw {
+			if n < 20 {
+				continue
+			}
+			headerLen := int(message[0]&0x0f) * 4
+			if n < headerLen {
+				continue
+			}
+			ttl = int(message[8])
+			message = message[headerLen:]
+		} else {
+			ttl = parseTTLMessage(oob[:oobn])
+		}
+
+		if isEchoReply(message, id, seq, raw) {
+			return rtt, ttl, nil
+		}
+	}
+}
+
+// openICMPSocket opens an unprivileged ICMP datagram socket, falling back to a
+// raw socket when ping sockets are disabled by net.ipv4.ping_group_range
+func openICMPSocket() (int, bool, error) {
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, syscall.IPPROTO_ICMP)
+	if err == nil {
+		return fd, false, nil
+	}
+	fd, err = syscall.Socket(syscall.AF_INET, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, syscall.IPPROTO_ICMP)
+	if err != nil {
+		return -1, false, err
+	}
+	return fd, true, nil
+}
+
+// parseTTLMessage extracts the IP_TTL control message, returning 0 when absent
+func parseTTLMessage(oob []byte) int {
+	messages, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil {
+		return 0
+	}
+	for _, message := range messages {
+		if message.Header.Level == syscall.IPPROTO_IP && message.Header.Type == syscall.IP_TTL && len(message.Data) >= 4 {
+			return int(binary.NativeEndian.Uint32(message.Data))
+		}
+	}
+	return 0
+}
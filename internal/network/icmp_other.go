@@ -0,0 +1,14 @@
+//go:build !linux
+
+// Package network provides the ICMP echo fallback for platforms without ICMP socket support
+package network
+
+import (
+	"net"
+	"time"
+)
+
+// icmpEcho is not supported on this platform; probes fall back to TCP connects
+func icmpEcho(ip, source net.IP, id, seq, size int, timeout time.Duration) (time.Duration, int, error) {
+	return 0, 0, errICMPUnavailable
+}
@@ -0,0 +1,65 @@
+// Package network provides tests for ICMP echo probes
+package network
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestEchoRequest(t *testing.T) {
+	packet := echoRequest(0x1234, 7, 64)
+
+	if len(packet) != 64 {
+		t.Fatalf("Expected 64 byte packet, got %d", len(packet))
+	}
+	if packet[0] != icmpEchoRequest || packet[1] != 0 {
+		t.Errorf("Expected echo request type and code, got %d/%d", packet[0], packet[1])
+	}
+	// A packet with a valid checksum sums to zero
+	if sum := icmpChecksum(packet); sum != 0 {
+		t.Errorf("Expected valid checksum, got residue %#x", sum)
+	}
+
+	if short := echoRequest(1, 1, 2); len(short) != icmpHeaderLen {
+		t.Errorf("Expected undersized packets to be padded to the header, got %d", len(short))
+	}
+}
+
+func TestIsEchoReply(t *testing.T) {
+	reply := echoRequest(0x1234, 7, 16)
+	reply[0] = icmpEchoReply
+
+	if !isEchoReply(reply, 0x1234, 7, true) {
+		t.Error("Expected matching reply to be accepted")
+	}
+	if isEchoReply(reply, 0x1234, 8, true) {
+		t.Error("Expected reply with another sequence to be rejected")
+	}
+	if isEchoReply(reply, 0x4321, 7, true) {
+		t.Error("Expected reply with another identifier to be rejected")
+	}
+	if !isEchoReply(reply, 0x4321, 7, false) {
+		t.Error("Expected identifier to be ignored for datagram sockets")
+	}
+	if isEchoReply(echoRequest(0x1234, 7, 16), 0x1234, 7, true) {
+		t.Error("Expected echo request to be rejected")
+	}
+}
+
+func TestICMPEcho_Loopback(t *testing.T) {
+	rtt, ttl, err := icmpEcho(net.ParseIP("127.0.0.1"), nil, icmpIdentifier, 1, 64, time.Second)
+	if errors.Is(err, errICMPUnavailable) {
+		t.Skip("ICMP sockets are not available")
+	}
+	if err != nil {
+		t.Fatalf("Loopback echo failed: %v", err)
+	}
+	if rtt <= 0 {
+		t.Errorf("Expected positive RTT, got %v", rtt)
+	}
+	if ttl != 64 {
+		t.Errorf("Expected loopback reply TTL 64, got %d", ttl)
+	}
+}
@@ -0,0 +1,133 @@
+package network
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxRateBuckets is the number of destinations tracked before buckets that
+// have refilled completely are dropped
+const maxRateBuckets = 4096
+
+// limiter bounds how many operations run at once and how often operations
+// start against the same destination, so scans cannot flood the local
+// network or look like an attack to intrusion detection
+type limiter struct {
+	slots chan struct{}
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// tokenBucket tracks the operations allowed to start against one destination
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newLimiter creates a limiter running at most maxConcurrent operations at
+// once and starting at most rate operations per second per destination,
+// with bursts of up to burst. Zero values disable the respective limit.
+func newLimiter(maxConcurrent int, rate float64, burst int) *limiter {
+	l := &limiter{
+		rate:    rate,
+		burst:   float64(burst),
+		now:     time.Now,
+		buckets: make(map[string]*tokenBucket),
+	}
+	if maxConcurrent > 0 {
+		l.slots = make(chan struct{}, maxConcurrent)
+	}
+	if l.burst < 1 {
+		l.burst = 1
+	}
+	return l
+}
+
+// acquire waits until an operation against destination may start and
+// returns the function that ends it
+func (l *limiter) acquire(ctx context.Context, destination string) (func(), error) {
+	if err := l.wait(ctx, destination); err != nil {
+		return nil, err
+	}
+	return l.slot(ctx)
+}
+
+// slot waits for a free concurrency slot and returns the function that
+// frees it. Streams call it once per probe, so a long ping or traceroute
+// does not keep other operations waiting for its whole run.
+func (l *limiter) slot(ctx context.Context) (func(), error) {
+	if l.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return func() { <-l.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// wait blocks until the rate limit of destination allows another operation
+func (l *limiter) wait(ctx context.Context, destination string) error {
+	delay := l.reserve(strings.ToLower(destination))
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token from the bucket of destination and returns how long
+// the caller must wait for it
+func (l *limiter) reserve(destination string) time.Duration {
+	if l.rate <= 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if len(l.buckets) >= maxRateBuckets {
+		l.prune(now)
+	}
+	bucket, exists := l.buckets[destination]
+	if !exists {
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[destination] = bucket
+	}
+	if elapsed := now.Sub(bucket.last).Seconds(); elapsed > 0 {
+		bucket.tokens += elapsed * l.rate
+		if bucket.tokens > l.burst {
+			bucket.tokens = l.burst
+		}
+		bucket.last = now
+	}
+
+	bucket.tokens--
+	if bucket.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-bucket.tokens / l.rate * float64(time.Second))
+}
+
+// prune drops the buckets that have refilled, which behave like new ones
+func (l *limiter) prune(now time.Time) {
+	for destination, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, destination)
+		}
+	}
+}
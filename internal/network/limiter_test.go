@@ -0,0 +1,97 @@
+package network
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimiter_MaxConcurrency(t *testing.T) {
+	l := newLimiter(2, 0, 0)
+
+	var running, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := l.acquire(context.Background(), "example.com")
+			if err != nil {
+				t.Errorf("acquire failed: %v", err)
+				return
+			}
+			defer release()
+			now := atomic.AddInt32(&running, 1)
+			for {
+				old := atomic.LoadInt32(&peak)
+				if now <= old || atomic.CompareAndSwapInt32(&peak, old, now) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		}()
+	}
+	wg.Wait()
+
+	if peak != 2 {
+		t.Errorf("Expected at most 2 operations at once, got %d", peak)
+	}
+}
+
+func TestLimiter_RatePerDestination(t *testing.T) {
+	l := newLimiter(0, 2, 2)
+	now := time.Unix(0, 0)
+	l.now = func() time.Time { return now }
+
+	// The burst starts immediately, then tokens arrive every 500ms
+	for i := 0; i < 2; i++ {
+		if delay := l.reserve("example.com"); delay != 0 {
+			t.Errorf("Expected burst operation %d to start at once, waited %v", i, delay)
+		}
+	}
+	if delay := l.reserve("example.com"); delay != 500*time.Millisecond {
+		t.Errorf("Expected to wait 500ms, got %v", delay)
+	}
+	if delay := l.reserve("example.com"); delay != time.Second {
+		t.Errorf("Expected the next operation to queue behind, got %v", delay)
+	}
+
+	// Other destinations have their own budget
+	if delay := l.reserve("example.org"); delay != 0 {
+		t.Errorf("Expected a new destination to start at once, waited %v", delay)
+	}
+
+	now = now.Add(3 * time.Second)
+	if delay := l.reserve("example.com"); delay != 0 {
+		t.Errorf("Expected the bucket to refill, waited %v", delay)
+	}
+}
+
+func TestLimiter_Cancelled(t *testing.T) {
+	l := newLimiter(1, 0, 0)
+	release, err := l.acquire(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx, "example.org"); err == nil {
+		t.Error("Expected acquire to give up when the context ends")
+	}
+}
+
+func TestLimiter_Unlimited(t *testing.T) {
+	l := newLimiter(0, 0, 0)
+	for i := 0; i < 100; i++ {
+		release, err := l.acquire(context.Background(), "example.com")
+		if err != nil {
+			t.Fatalf("acquire failed: %v", err)
+		}
+		release()
+	}
+}
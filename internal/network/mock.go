@@ -21,6 +21,10 @@ type MockClient struct {
 	dnsResponses       map[string]domain.DNSResult
 	whoisResponses     map[string]domain.WHOISResult
 	sslResponses       map[string]domain.SSLResult
+	connectTimes       map[string]time.Duration
+	reverseNames       map[string][]string
+	zoneTransfers      map[string][]domain.DNSRecord
+	capabilities       domain.Capabilities
 	
 	// Error simulation
 	pingErrors         map[string]error
@@ -28,6 +32,7 @@ type MockClient struct {
 	dnsErrors          map[string]error
 	whoisErrors        map[string]error
 	sslErrors          map[string]error
+	connectErrors      map[string]error
 	
 	// Delay simulation
 	pingDelays         map[string]time.Duration
@@ -42,6 +47,7 @@ type MockClient struct {
 	dnsCalls           []MockCall
 	whoisCalls         []MockCall
 	sslCalls           []MockCall
+	connectCalls       []MockCall
 	
 	// Behavior flags
 	simulateTimeout    bool
@@ -64,11 +70,16 @@ func NewMockClient() *MockClient {
 		dnsResponses:   make(map[string]domain.DNSResult),
 		whoisResponses: make(map[string]domain.WHOISResult),
 		sslResponses:   make(map[string]domain.SSLResult),
+		connectTimes:   make(map[string]time.Duration),
+		reverseNames:   make(map[string][]string),
+		zoneTransfers:  make(map[string][]domain.DNSRecord),
+		capabilities:   domain.Capabilities{Platform: "mock", ICMP: true},
 		pingErrors:     make(map[string]error),
 		traceErrors:    make(map[string]error),
 		dnsErrors:      make(map[string]error),
 		whoisErrors:    make(map[string]error),
 		sslErrors:      make(map[string]error),
+		connectErrors:  make(map[string]error),
 		pingDelays:     make(map[string]time.Duration),
 		traceDelays:    make(map[string]time.Duration),
 		dnsDelays:      make(map[string]time.Duration),
@@ -119,8 +130,9 @@ func (m *MockClient) Ping(ctx context.Context, host string, opts domain.PingOpti
 			case <-ctx.Done():
 				return
 			case resultChan <- result:
-				// Simulate interval between pings
-				if i < len(responses)-1 {
+				// Simulate interval between pings; adaptive and flood
+				// modes do not wait for an interval
+				if i < len(responses)-1 && (opts.Mode == "" || opts.Mode == domain.PingModeNormal) {
 					time.Sleep(opts.Interval)
 				}
 			}
@@ -275,6 +287,70 @@ func (m *MockClient) SSLCheck(ctx context.Context, host string, port int) (domai
 	return m.generateDefaultSSLResult(host, port), nil
 }
 
+// TCPConnect implements the ConnectivityClient interface with mock behavior
+func (m *MockClient) TCPConnect(ctx context.Context, ip net.IP, port int) (time.Duration, error) {
+	address := net.JoinHostPort(ip.String(), fmt.Sprintf("%d", port))
+
+	m.mu.Lock()
+	m.callCount++
+	m.connectCalls = append(m.connectCalls, MockCall{
+		Method:    "TCPConnect",
+		Args:      []interface{}{ip.String(), port},
+		Timestamp: time.Now(),
+	})
+	err, hasErr := m.connectErrors[address]
+	elapsed, hasTime := m.connectTimes[address]
+	m.mu.Unlock()
+
+	if hasErr {
+		return elapsed, err
+	}
+	if !hasTime {
+		elapsed = 20 * time.Millisecond
+	}
+
+	return elapsed, nil
+}
+
+// ReverseLookup implements domain.ReverseResolver using configured names.
+// Addresses without configured names fail like an address without PTR records.
+func (m *MockClient) ReverseLookup(ctx context.Context, ip net.IP) ([]string, error) {
+	m.mu.Lock()
+	m.callCount++
+	names, exists := m.reverseNames[ip.String()]
+	m.mu.Unlock()
+
+	if !exists {
+		return nil, fmt.Errorf("no PTR record for %s", ip)
+	}
+	return names, nil
+}
+
+// ZoneTransfer implements domain.ZoneTransferClient using configured zones.
+// Servers without a configured zone refuse the transfer like a well configured server.
+func (m *MockClient) ZoneTransfer(ctx context.Context, server, zone string) ([]domain.DNSRecord, int, error) {
+	m.mu.Lock()
+	m.callCount++
+	records, exists := m.zoneTransfers[zoneTransferKey(server, zone)]
+	m.mu.Unlock()
+
+	if !exists {
+		return nil, 0, &domain.NetTraceError{
+			Type:      domain.ErrorTypeNetwork,
+			Message:   "zone transfer refused",
+			Context:   map[string]interface{}{"server": server, "zone": zone},
+			Timestamp: time.Now(),
+			Code:      "AXFR_REFUSED",
+		}
+	}
+	return records, len(records), nil
+}
+
+// zoneTransferKey identifies a configured zone transfer
+func zoneTransferKey(server, zone string) string {
+	return server + " " + zone
+}
+
 // Configuration methods for setting up mock behavior
 
 // SetPingResponse configures a mock ping response for a specific host
@@ -358,8 +434,51 @@ func (m *MockClient) SetSSLError(host string, port int, err error) {
 	m.sslErrors[key] = err
 }
 
+// SetReverseLookup configures the names returned for a reverse lookup of ip
+func (m *MockClient) SetReverseLookup(ip string, names []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reverseNames[ip] = names
+}
+
+// SetZoneTransfer configures server to allow a transfer of zone returning records
+func (m *MockClient) SetZoneTransfer(server, zone string, records []domain.DNSRecord) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.zoneTransfers[zoneTransferKey(server, zone)] = records
+}
+
+// Capabilities implements domain.CapabilityReporter with the configured
+// capabilities, which allow ICMP by default
+func (m *MockClient) Capabilities() domain.Capabilities {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.capabilities
+}
+
+// SetCapabilities configures the capabilities reported by the mock
+func (m *MockClient) SetCapabilities(capabilities domain.Capabilities) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.capabilities = capabilities
+}
+
 // Inspection methods for testing
 
+// SetConnectTime configures the mock TCP handshake time for an address
+func (m *MockClient) SetConnectTime(ip string, port int, elapsed time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.connectTimes[net.JoinHostPort(ip, fmt.Sprintf("%d", port))] = elapsed
+}
+
+// SetConnectError configures a mock TCP connect error for an address
+func (m *MockClient) SetConnectError(ip string, port int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.connectErrors[net.JoinHostPort(ip, fmt.Sprintf("%d", port))] = err
+}
+
 // GetCallCount returns the total number of method calls made
 func (m *MockClient) GetCallCount() int {
 	m.mu.RLock()
@@ -402,6 +521,13 @@ func (m *MockClient) GetSSLCalls() []MockCall {
 	return append([]MockCall(nil), m.sslCalls...)
 }
 
+// GetConnectCalls returns all recorded TCPConnect calls
+func (m *MockClient) GetConnectCalls() []MockCall {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]MockCall(nil), m.connectCalls...)
+}
+
 // Reset clears all recorded calls and configured responses
 func (m *MockClient) Reset() {
 	m.mu.Lock()
@@ -412,12 +538,15 @@ func (m *MockClient) Reset() {
 	m.dnsResponses = make(map[string]domain.DNSResult)
 	m.whoisResponses = make(map[string]domain.WHOISResult)
 	m.sslResponses = make(map[string]domain.SSLResult)
+	m.connectTimes = make(map[string]time.Duration)
+	m.zoneTransfers = make(map[string][]domain.DNSRecord)
 	
 	m.pingErrors = make(map[string]error)
 	m.traceErrors = make(map[string]error)
 	m.dnsErrors = make(map[string]error)
 	m.whoisErrors = make(map[string]error)
 	m.sslErrors = make(map[string]error)
+	m.connectErrors = make(map[string]error)
 	
 	m.pingDelays = make(map[string]time.Duration)
 	m.traceDelays = make(map[string]time.Duration)
@@ -430,6 +559,7 @@ func (m *MockClient) Reset() {
 	m.dnsCalls = nil
 	m.whoisCalls = nil
 	m.sslCalls = nil
+	m.connectCalls = nil
 	
 	m.callCount = 0
 }
@@ -510,6 +640,11 @@ func (m *MockClient) generateDefaultTraceResults(host string, opts domain.TraceO
 			Timestamp: time.Now(),
 		}
 		
+		// Probes with a destination port report it on the final hop
+		if i == numHops && opts.Protocol != "" && opts.Protocol != domain.TraceProtocolICMP {
+			hop.Host.Port = opts.Port
+		}
+		
 		// Simulate occasional timeout (10% chance)
 		if m.simulateTimeout && i%10 == 0 {
 			hop.Timeout = true
@@ -651,4 +651,22 @@ func TestMockClient_DNSRecordTypes(t *testing.T) {
 			}
 		}
 	}
-}
\ No newline at end of file
+}
+func TestMockClient_ReverseLookup(t *testing.T) {
+	mock := NewMockClient()
+	mock.SetReverseLookup("192.168.1.1", []string{"router.lan"})
+
+	names, err := mock.ReverseLookup(context.Background(), net.ParseIP("192.168.1.1"))
+	if err != nil {
+		t.Fatalf("Mock reverse lookup failed: %v", err)
+	}
+	if len(names) != 1 || names[0] != "router.lan" {
+		t.Errorf("Expected [router.lan], got %v", names)
+	}
+
+	if _, err := mock.ReverseLookup(context.Background(), net.ParseIP("192.168.1.2")); err == nil {
+		t.Error("Expected error for an address without configured names")
+	}
+
+	var _ domain.ReverseResolver = mock
+}
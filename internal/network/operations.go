@@ -4,12 +4,18 @@ package network
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/proxy"
+	"github.com/nettracex/nettracex-tui/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // executePing performs the actual ping operation
@@ -17,7 +23,7 @@ func (c *Client) executePing(ctx context.Context, host string, opts domain.PingO
 	c.logger.Info("Starting ping operation", "host", host, "count", opts.Count)
 
 	// Resolve host to IP address
-	ips, err := net.LookupIP(host)
+	ips, err := c.lookupIP(ctx, host)
 	if err != nil {
 		result := domain.PingResult{
 			Host: domain.NetworkHost{
@@ -59,57 +65,201 @@ func (c *Client) executePing(ctx context.Context, host string, opts domain.PingO
 		IPAddress: targetIP,
 	}
 
-	// Perform ping operations
+	source, err := sourceAddress(ctx, targetIP.To4() == nil)
+	if err != nil {
+		resultChan <- domain.PingResult{Host: networkHost, Error: err, Timestamp: time.Now()}
+		return
+	}
+
+	switch opts.Mode {
+	case domain.PingModeFlood:
+		c.floodPing(ctx, networkHost, source, opts, resultChan)
+	default:
+		c.pacedPing(ctx, networkHost, source, opts, resultChan)
+	}
+
+	c.logger.Info("Ping operation completed", "host", host, "count", opts.Count)
+}
+
+// pacedPing sends one probe at a time. Normal mode waits the interval after each
+// reply; adaptive mode sends the next probe as soon as the reply arrives, but no
+// sooner than the minimum interval after the previous probe was sent.
+func (c *Client) pacedPing(ctx context.Context, host domain.NetworkHost, source net.IP, opts domain.PingOptions, resultChan chan<- domain.PingResult) {
+	minInterval, _ := c.pingLimits()
+
 	for i := 0; i < opts.Count; i++ {
 		select {
 		case <-ctx.Done():
-			c.logger.Info("Ping operation cancelled", "host", host)
+			c.logger.Info("Ping operation cancelled", "host", host.Hostname)
 			return
 		default:
 		}
 
-		start := time.Now()
-		
-		// Simulate ping by attempting to connect (simplified implementation)
-		conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:80", targetIP.String()), opts.Timeout)
-		rtt := time.Since(start)
-		
-		result := domain.PingResult{
-			Host:       networkHost,
-			Sequence:   i + 1,
-			RTT:        rtt,
-			TTL:        64, // Default TTL
-			PacketSize: opts.PacketSize,
-			Timestamp:  time.Now(),
+		release, err := c.current().limiter.slot(ctx)
+		if err != nil {
+			resultChan <- domain.PingResult{Host: host, Sequence: i + 1, Error: err, Timestamp: time.Now()}
+			return
 		}
+		sent := time.Now()
+		result := c.probe(host, source, i+1, opts)
+		release()
+		resultChan <- result
 
+		if i == opts.Count-1 {
+			break
+		}
+
+		wait := opts.Interval
+		if opts.Mode == domain.PingModeAdaptive {
+			wait = minInterval - time.Since(sent)
+		}
+		if wait <= 0 {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// floodPing sends probes without waiting for replies, keeping at most
+// MaxConcurrency probes in flight. Results are delivered in completion order.
+func (c *Client) floodPing(ctx context.Context, host domain.NetworkHost, source net.IP, opts domain.PingOptions, resultChan chan<- domain.PingResult) {
+	state := c.current()
+	inFlight := state.config.MaxConcurrency
+	if inFlight <= 0 {
+		inFlight = 1
+	}
+
+	sem := make(chan struct{}, inFlight)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for i := 0; i < opts.Count; i++ {
+		select {
+		case <-ctx.Done():
+			c.logger.Info("Ping operation cancelled", "host", host.Hostname)
+			return
+		case sem <- struct{}{}:
+		}
+		release, err := state.limiter.slot(ctx)
 		if err != nil {
-			result.Error = err
-		} else {
-			conn.Close()
+			<-sem
+			resultChan <- domain.PingResult{Host: host, Sequence: i + 1, Error: err, Timestamp: time.Now()}
+			return
 		}
 
-		resultChan <- result
+		wg.Add(1)
+		go func(sequence int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			result := c.probe(host, source, sequence, opts)
+			release()
+			resultChan <- result
+		}(i + 1)
+	}
+}
 
-		// Wait for interval before next ping
-		if i < opts.Count-1 {
-			select {
-			case <-ctx.Done():
-				return
-			case <-time.After(opts.Interval):
-			}
+// probe sends a single ping probe to host. An ICMP echo is used where the
+// capability probe found ICMP sockets usable so the reply TTL is known;
+// otherwise the probe falls back to a TCP connect and the TTL is reported as
+// 0 (unknown). Probes are sent from source when it is set.
+func (c *Client) probe(host domain.NetworkHost, source net.IP, sequence int, opts domain.PingOptions) domain.PingResult {
+	result := domain.PingResult{
+		Host:       host,
+		Sequence:   sequence,
+		PacketSize: opts.PacketSize,
+	}
+
+	var rtt time.Duration
+	var ttl int
+	err := errICMPUnavailable
+	if ProbeCapabilities().ICMP {
+		rtt, ttl, err = icmpEcho(host.IPAddress, source, icmpIdentifier, sequence, opts.PacketSize, opts.Timeout)
+	}
+	if errors.Is(err, errICMPUnavailable) {
+		rtt, err = tcpProbe(host.IPAddress, source, opts.Timeout)
+		ttl = 0
+	}
+
+	result.RTT = rtt
+	result.TTL = ttl
+	result.Error = err
+	result.Timestamp = time.Now()
+	return result
+}
+
+// tcpProbe measures the time to connect to port 80 on ip from source
+func tcpProbe(ip, source net.IP, timeout time.Duration) (time.Duration, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	if source != nil {
+		dialer.LocalAddr = &net.TCPAddr{IP: source}
+	}
+	start := time.Now()
+	conn, err := dialer.Dial("tcp", net.JoinHostPort(ip.String(), "80"))
+	rtt := time.Since(start)
+	if err != nil {
+		return rtt, err
+	}
+	conn.Close()
+	return rtt, nil
+}
+
+// pingLimits returns the configured minimum probe interval and flood count cap,
+// falling back to the built-in defaults when they are unset
+func (c *Client) pingLimits() (time.Duration, int) {
+	state := c.current()
+	minInterval := state.config.MinPingInterval
+	if minInterval <= 0 {
+		minInterval = DefaultMinPingInterval
+	}
+	maxFlood := state.config.MaxFloodCount
+	if maxFlood <= 0 {
+		maxFlood = DefaultMaxFloodCount
+	}
+	return minInterval, maxFlood
+}
+
+// applyPingLimits clamps opts to the configured limits so a user supplied
+// interval or count cannot turn a ping into an unbounded flood
+func (c *Client) applyPingLimits(opts domain.PingOptions) domain.PingOptions {
+	minInterval, maxFlood := c.pingLimits()
+
+	if opts.Mode == "" {
+		opts.Mode = domain.PingModeNormal
+	}
+
+	switch opts.Mode {
+	case domain.PingModeFlood:
+		if opts.Count <= 0 || opts.Count > maxFlood {
+			c.logger.Warn("Limiting flood ping count", "requested", opts.Count, "max", maxFlood)
+			opts.Count = maxFlood
+		}
+	case domain.PingModeNormal:
+		if opts.Interval < minInterval {
+			c.logger.Warn("Raising ping interval to configured minimum", "requested", opts.Interval, "min", minInterval)
+			opts.Interval = minInterval
 		}
 	}
 
-	c.logger.Info("Ping operation completed", "host", host, "count", opts.Count)
+	return opts
 }
 
 // executeTraceroute performs the actual traceroute operation
 func (c *Client) executeTraceroute(ctx context.Context, host string, opts domain.TraceOptions, resultChan chan<- domain.TraceHop) {
-	c.logger.Info("Starting traceroute operation", "host", host, "max_hops", opts.MaxHops)
+	if opts.Protocol == "" {
+		opts.Protocol = domain.TraceProtocolICMP
+	}
+	if opts.Port == 0 {
+		opts.Port = domain.DefaultTracePort(opts.Protocol)
+	}
+
+	c.logger.Info("Starting traceroute operation", "host", host, "max_hops", opts.MaxHops, "protocol", opts.Protocol, "port", opts.Port)
 
 	// Resolve target host
-	ips, err := net.LookupIP(host)
+	ips, err := c.lookupIP(ctx, host)
 	if err != nil {
 		c.logger.Error("Failed to resolve host for traceroute", "host", host, "error", err)
 		return
@@ -157,8 +307,15 @@ func (c *Client) executeTraceroute(ctx context.Context, host string, opts domain
 			default:
 			}
 			
+			release, err := c.current().limiter.slot(ctx)
+			if err != nil {
+				resultChan <- domain.TraceHop{Number: hop, Error: err, Timestamp: time.Now()}
+				return
+			}
+
 			// Try to trace this hop using TCP connect with timeout
 			hopIP, rtt, err := c.traceHop(ctx, targetIP, hop, opts.Timeout)
+			release()
 			
 			if err != nil {
 				c.logger.Debug("Hop query failed", "hop", hop, "query", query, "error", err)
@@ -185,6 +342,9 @@ func (c *Client) executeTraceroute(ctx context.Context, host string, opts domain
 				// Check if we reached the target
 				if hopIP.Equal(targetIP) {
 					reachedTarget = true
+					if opts.Protocol != domain.TraceProtocolICMP {
+						hopHost.Port = opts.Port
+					}
 				}
 			}
 		}
@@ -280,6 +440,17 @@ func (c *Client) traceHop(ctx context.Context, targetIP net.IP, ttl int, timeout
 	return hopIP, rtt, nil
 }
 
+// lookupIP resolves host to its addresses inside a resolve span
+func (c *Client) lookupIP(ctx context.Context, host string) ([]net.IP, error) {
+	_, span := tracing.Start(ctx, "resolve", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attribute.String("server.address", host)))
+	defer span.End()
+
+	ips, err := net.LookupIP(host)
+	tracing.Fail(span, err)
+	span.SetAttributes(attribute.Int("nettracex.addresses", len(ips)))
+	return ips, err
+}
+
 // resolveHostname attempts to resolve an IP address to hostname with timeout
 func (c *Client) resolveHostname(ip net.IP, timeout time.Duration) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
@@ -324,28 +495,16 @@ func (c *Client) resolveHostname(ip net.IP, timeout time.Duration) (string, erro
 func (c *Client) executeDNSLookup(ctx context.Context, domainName string, recordType domain.DNSRecordType) (domain.DNSResult, error) {
 	c.logger.Info("Starting DNS lookup", "domain", domainName, "record_type", recordType)
 
-	start := time.Now()
-	
-	var records []domain.DNSRecord
-	var err error
-
-	switch recordType {
-	case domain.DNSRecordTypeA:
-		records, err = c.lookupARecords(ctx, domainName)
-	case domain.DNSRecordTypeAAAA:
-		records, err = c.lookupAAAARecords(ctx, domainName)
-	case domain.DNSRecordTypeMX:
-		records, err = c.lookupMXRecords(ctx, domainName)
-	case domain.DNSRecordTypeTXT:
-		records, err = c.lookupTXTRecords(ctx, domainName)
-	case domain.DNSRecordTypeCNAME:
-		records, err = c.lookupCNAMERecords(ctx, domainName)
-	case domain.DNSRecordTypeNS:
-		records, err = c.lookupNSRecords(ctx, domainName)
-	default:
+	if !isSupportedDNSRecordType(recordType) {
 		return domain.DNSResult{}, fmt.Errorf("unsupported DNS record type: %v", recordType)
 	}
 
+	start := time.Now()
+
+	records, server, err := c.lookupWithFailover(ctx, c.dnsServers(ctx), func(ctx context.Context, server string) ([]domain.DNSRecord, error) {
+		return c.queryServer(ctx, server, domainName, recordType)
+	})
+
 	responseTime := time.Since(start)
 
 	if err != nil {
@@ -353,7 +512,7 @@ func (c *Client) executeDNSLookup(ctx context.Context, domainName string, record
 			Type:      domain.ErrorTypeNetwork,
 			Message:   "DNS lookup failed",
 			Cause:     err,
-			Context:   map[string]interface{}{"domain": domainName, "record_type": recordType},
+			Context:   map[string]interface{}{"domain": domainName, "record_type": recordType, "servers": c.dnsServers(ctx)},
 			Timestamp: time.Now(),
 			Code:      "DNS_LOOKUP_FAILED",
 		}
@@ -364,10 +523,10 @@ func (c *Client) executeDNSLookup(ctx context.Context, domainName string, record
 		RecordType:   recordType,
 		Records:      records,
 		ResponseTime: responseTime,
-		Server:       "system", // Using system resolver
+		Server:       server,
 	}
 
-	c.logger.Info("DNS lookup completed", "domain", domainName, "record_count", len(records))
+	c.logger.Info("DNS lookup completed", "domain", domainName, "server", server, "record_count", len(records))
 	return result, nil
 }
 
@@ -535,20 +694,25 @@ func (c *Client) getWHOISServer(query string) (string, error) {
 }
 
 // queryWHOISServer connects to a WHOIS server and performs the query
-func (c *Client) queryWHOISServer(ctx context.Context, server, query string) (string, error) {
-	// Create connection with timeout
-	dialer := &net.Dialer{
-		Timeout: c.config.Timeout,
-	}
-	
-	conn, err := dialer.DialContext(ctx, "tcp", server)
+func (c *Client) queryWHOISServer(ctx context.Context, server, query string) (response string, err error) {
+	state := c.current()
+	ctx, span := tracing.Start(ctx, "whois query", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("server.address", server),
+		attribute.String("nettracex.query", query),
+	))
+	defer func() {
+		tracing.Fail(span, err)
+		span.End()
+	}()
+
+	conn, err := c.dialTCP(ctx, state.config.Proxy.WHOIS, "tcp", server)
 	if err != nil {
 		return "", fmt.Errorf("failed to connect to WHOIS server %s: %w", server, err)
 	}
 	defer conn.Close()
 
 	// Set read/write timeouts
-	conn.SetDeadline(time.Now().Add(c.config.Timeout))
+	conn.SetDeadline(time.Now().Add(state.config.Timeout))
 
 	// Send query
 	_, err = fmt.Fprintf(conn, "%s\r\n", query)
@@ -557,7 +721,7 @@ func (c *Client) queryWHOISServer(ctx context.Context, server, query string) (st
 	}
 
 	// Read response
-	var response strings.Builder
+	var received strings.Builder
 	buffer := make([]byte, 4096)
 	
 	for {
@@ -575,7 +739,7 @@ func (c *Client) queryWHOISServer(ctx context.Context, server, query string) (st
 			return "", fmt.Errorf("failed to read from WHOIS server: %w", err)
 		}
 		
-		response.Write(buffer[:n])
+		received.Write(buffer[:n])
 		
 		// Break if we've read everything
 		if n < len(buffer) {
@@ -583,7 +747,30 @@ func (c *Client) queryWHOISServer(ctx context.Context, server, query string) (st
 		}
 	}
 
-	return response.String(), nil
+	span.SetAttributes(attribute.Int("nettracex.response_bytes", received.Len()))
+	return received.String(), nil
+}
+
+// dialTCP connects to address inside a connect span, using the configured
+// timeout. The connection goes through proxyURL when one is set.
+func (c *Client) dialTCP(ctx context.Context, proxyURL, network, address string) (net.Conn, error) {
+	_, span := tracing.Start(ctx, "connect", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("server.address", address),
+		attribute.String("network.transport", network),
+	))
+	defer span.End()
+	if proxyURL != "" {
+		span.SetAttributes(attribute.String("nettracex.proxy", proxy.Redact(proxyURL)))
+	}
+
+	dialer, err := newDialer(ctx, c.current().config.Timeout, address)
+	if err != nil {
+		tracing.Fail(span, err)
+		return nil, err
+	}
+	conn, err := proxy.Dial(ctx, dialer, proxyURL, network, address)
+	tracing.Fail(span, err)
+	return conn, err
 }
 
 // parseWHOISResponse parses raw WHOIS data into structured format
@@ -827,13 +1014,7 @@ func (c *Client) executeSSLCheck(ctx context.Context, host string, port int) (do
 	address := fmt.Sprintf("%s:%d", host, port)
 	
 	// Create TLS connection
-	dialer := &net.Dialer{
-		Timeout: c.config.Timeout,
-	}
-	
-	conn, err := tls.DialWithDialer(dialer, "tcp", address, &tls.Config{
-		ServerName: host,
-	})
+	conn, err := c.dialTLS(ctx, address, host)
 	
 	if err != nil {
 		return domain.SSLResult{}, &domain.NetTraceError{
@@ -899,9 +1080,88 @@ func (c *Client) executeSSLCheck(ctx context.Context, host string, port int) (do
 	return result, nil
 }
 
-// DNS lookup helper methods
-func (c *Client) lookupARecords(ctx context.Context, domainName string) ([]domain.DNSRecord, error) {
-	ips, err := net.LookupIP(domainName)
+// dialTLS connects to address and completes a TLS handshake for serverName,
+// tracing the connect and handshake separately. The configured timeout
+// applies to each step.
+func (c *Client) dialTLS(ctx context.Context, address, serverName string) (*tls.Conn, error) {
+	config := c.current().config
+	rawConn, err := c.dialTCP(ctx, config.Proxy.SSL, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	_, span := tracing.Start(ctx, "handshake", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("server.address", serverName),
+	))
+	defer span.End()
+
+	handshakeCtx := ctx
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		handshakeCtx, cancel = context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+	}
+
+	conn := tls.Client(rawConn, &tls.Config{ServerName: serverName})
+	if err := conn.HandshakeContext(handshakeCtx); err != nil {
+		rawConn.Close()
+		tracing.Fail(span, err)
+		return nil, err
+	}
+
+	state := conn.ConnectionState()
+	span.SetAttributes(
+		attribute.String("tls.protocol.version", tls.VersionName(state.Version)),
+		attribute.String("tls.cipher", tls.CipherSuiteName(state.CipherSuite)),
+	)
+	return conn, nil
+}
+
+// DNS lookup helper methods using the stdlib resolver. It does not expose
+// record TTLs, so records from these helpers have a TTL of 0 (unknown).
+// isSupportedDNSRecordType reports whether lookupRecords can query recordType
+func isSupportedDNSRecordType(recordType domain.DNSRecordType) bool {
+	switch recordType {
+	case domain.DNSRecordTypeA, domain.DNSRecordTypeAAAA, domain.DNSRecordTypeMX,
+		domain.DNSRecordTypeTXT, domain.DNSRecordTypeCNAME, domain.DNSRecordTypeNS:
+		return true
+	}
+	return false
+}
+
+// lookupRecords queries a single record type using resolver
+func (c *Client) lookupRecords(ctx context.Context, resolver *net.Resolver, domainName string, recordType domain.DNSRecordType) (records []domain.DNSRecord, err error) {
+	ctx, span := tracing.Start(ctx, "dns query", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("dns.question.name", domainName),
+		attribute.Int("dns.question.type", int(dnsRecordTypes[recordType])),
+		attribute.String("server.address", domain.SystemDNSServer),
+	))
+	defer func() {
+		tracing.Fail(span, err)
+		span.SetAttributes(attribute.Int("dns.answer.count", len(records)))
+		span.End()
+	}()
+
+	switch recordType {
+	case domain.DNSRecordTypeA:
+		return c.lookupARecords(ctx, resolver, domainName)
+	case domain.DNSRecordTypeAAAA:
+		return c.lookupAAAARecords(ctx, resolver, domainName)
+	case domain.DNSRecordTypeMX:
+		return c.lookupMXRecords(ctx, resolver, domainName)
+	case domain.DNSRecordTypeTXT:
+		return c.lookupTXTRecords(ctx, resolver, domainName)
+	case domain.DNSRecordTypeCNAME:
+		return c.lookupCNAMERecords(ctx, resolver, domainName)
+	case domain.DNSRecordTypeNS:
+		return c.lookupNSRecords(ctx, resolver, domainName)
+	default:
+		return nil, fmt.Errorf("unsupported DNS record type: %v", recordType)
+	}
+}
+
+func (c *Client) lookupARecords(ctx context.Context, resolver *net.Resolver, domainName string) ([]domain.DNSRecord, error) {
+	ips, err := resolver.LookupIP(ctx, "ip", domainName)
 	if err != nil {
 		return nil, err
 	}
@@ -913,15 +1173,14 @@ func (c *Client) lookupARecords(ctx context.Context, domainName string) ([]domai
 				Name:  domainName,
 				Type:  domain.DNSRecordTypeA,
 				Value: ip.String(),
-				TTL:   300, // Default TTL
 			})
 		}
 	}
 	return records, nil
 }
 
-func (c *Client) lookupAAAARecords(ctx context.Context, domainName string) ([]domain.DNSRecord, error) {
-	ips, err := net.LookupIP(domainName)
+func (c *Client) lookupAAAARecords(ctx context.Context, resolver *net.Resolver, domainName string) ([]domain.DNSRecord, error) {
+	ips, err := resolver.LookupIP(ctx, "ip", domainName)
 	if err != nil {
 		return nil, err
 	}
@@ -933,15 +1192,14 @@ func (c *Client) lookupAAAARecords(ctx context.Context, domainName string) ([]do
 				Name:  domainName,
 				Type:  domain.DNSRecordTypeAAAA,
 				Value: ip.String(),
-				TTL:   300,
 			})
 		}
 	}
 	return records, nil
 }
 
-func (c *Client) lookupMXRecords(ctx context.Context, domainName string) ([]domain.DNSRecord, error) {
-	mxRecords, err := net.LookupMX(domainName)
+func (c *Client) lookupMXRecords(ctx context.Context, resolver *net.Resolver, domainName string) ([]domain.DNSRecord, error) {
+	mxRecords, err := resolver.LookupMX(ctx, domainName)
 	if err != nil {
 		return nil, err
 	}
@@ -952,15 +1210,14 @@ func (c *Client) lookupMXRecords(ctx context.Context, domainName string) ([]doma
 			Name:     domainName,
 			Type:     domain.DNSRecordTypeMX,
 			Value:    mx.Host,
-			TTL:      300,
 			Priority: int(mx.Pref),
 		})
 	}
 	return records, nil
 }
 
-func (c *Client) lookupTXTRecords(ctx context.Context, domainName string) ([]domain.DNSRecord, error) {
-	txtRecords, err := net.LookupTXT(domainName)
+func (c *Client) lookupTXTRecords(ctx context.Context, resolver *net.Resolver, domainName string) ([]domain.DNSRecord, error) {
+	txtRecords, err := resolver.LookupTXT(ctx, domainName)
 	if err != nil {
 		return nil, err
 	}
@@ -971,14 +1228,13 @@ func (c *Client) lookupTXTRecords(ctx context.Context, domainName string) ([]dom
 			Name:  domainName,
 			Type:  domain.DNSRecordTypeTXT,
 			Value: txt,
-			TTL:   300,
 		})
 	}
 	return records, nil
 }
 
-func (c *Client) lookupCNAMERecords(ctx context.Context, domainName string) ([]domain.DNSRecord, error) {
-	cname, err := net.LookupCNAME(domainName)
+func (c *Client) lookupCNAMERecords(ctx context.Context, resolver *net.Resolver, domainName string) ([]domain.DNSRecord, error) {
+	cname, err := resolver.LookupCNAME(ctx, domainName)
 	if err != nil {
 		return nil, err
 	}
@@ -988,14 +1244,13 @@ func (c *Client) lookupCNAMERecords(ctx context.Context, domainName string) ([]d
 			Name:  domainName,
 			Type:  domain.DNSRecordTypeCNAME,
 			Value: cname,
-			TTL:   300,
 		},
 	}
 	return records, nil
 }
 
-func (c *Client) lookupNSRecords(ctx context.Context, domainName string) ([]domain.DNSRecord, error) {
-	nsRecords, err := net.LookupNS(domainName)
+func (c *Client) lookupNSRecords(ctx context.Context, resolver *net.Resolver, domainName string) ([]domain.DNSRecord, error) {
+	nsRecords, err := resolver.LookupNS(ctx, domainName)
 	if err != nil {
 		return nil, err
 	}
@@ -1006,7 +1261,6 @@ func (c *Client) lookupNSRecords(ctx context.Context, domainName string) ([]doma
 			Name:  domainName,
 			Type:  domain.DNSRecordTypeNS,
 			Value: ns.Host,
-			TTL:   300,
 		})
 	}
 	return records, nil
@@ -0,0 +1,463 @@
+package network
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// FixtureVersion is the format version written to recorded fixture files
+const FixtureVersion = 1
+
+// Fixture holds the network responses captured by a Recorder. Replaying it
+// answers the same queries with the same responses, without network access.
+type Fixture struct {
+	Version      int                   `json:"version"`
+	Recorded     time.Time             `json:"recorded"`
+	Ping         []pingFixture         `json:"ping,omitempty"`
+	Traceroute   []traceFixture        `json:"traceroute,omitempty"`
+	DNS          []dnsFixture          `json:"dns,omitempty"`
+	WHOIS        []whoisFixture        `json:"whois,omitempty"`
+	SSL          []sslFixture          `json:"ssl,omitempty"`
+	Connect      []connectFixture      `json:"connect,omitempty"`
+	Reverse      []reverseFixture      `json:"reverse,omitempty"`
+	ZoneTransfer []zoneTransferFixture `json:"zone_transfer,omitempty"`
+}
+
+// fixtureError is a recorded error. Diagnostic errors keep their type, code
+// and kind so tools classify them the same way when they are replayed.
+type fixtureError struct {
+	Message string           `json:"message"`
+	Type    domain.ErrorType `json:"type,omitempty"`
+	Code    string           `json:"code,omitempty"`
+	Kind    domain.ErrorKind `json:"kind,omitempty"`
+}
+
+// pingReply is a ping result whose error survives the round trip to JSON
+type pingReply struct {
+	domain.PingResult
+	Error string `json:"error,omitempty"`
+}
+
+type pingFixture struct {
+	Host    string        `json:"host"`
+	Results []pingReply   `json:"results,omitempty"`
+	Error   *fixtureError `json:"error,omitempty"`
+}
+
+type traceFixture struct {
+	Host  string            `json:"host"`
+	Hops  []domain.TraceHop `json:"hops,omitempty"`
+	Error *fixtureError     `json:"error,omitempty"`
+}
+
+type dnsFixture struct {
+	Domain string               `json:"domain"`
+	Type   domain.DNSRecordType `json:"type"`
+	Result domain.DNSResult     `json:"result"`
+	Error  *fixtureError        `json:"error,omitempty"`
+}
+
+type whoisFixture struct {
+	Query  string             `json:"query"`
+	Result domain.WHOISResult `json:"result"`
+	Error  *fixtureError      `json:"error,omitempty"`
+}
+
+// sslFixture stores certificates as DER since x509.Certificate does not
+// decode from JSON
+type sslFixture struct {
+	Host        string           `json:"host"`
+	Port        int              `json:"port"`
+	Result      domain.SSLResult `json:"result"`
+	Certificate []byte           `json:"certificate,omitempty"`
+	Chain       [][]byte         `json:"chain,omitempty"`
+	Error       *fixtureError    `json:"error,omitempty"`
+}
+
+type connectFixture struct {
+	IP      string        `json:"ip"`
+	Port    int           `json:"port"`
+	Elapsed time.Duration `json:"elapsed"`
+	Error   *fixtureError `json:"error,omitempty"`
+}
+
+type reverseFixture struct {
+	IP    string   `json:"ip"`
+	Names []string `json:"names,omitempty"`
+}
+
+type zoneTransferFixture struct {
+	Server  string             `json:"server"`
+	Zone    string             `json:"zone"`
+	Records []domain.DNSRecord `json:"records,omitempty"`
+}
+
+// Recorder wraps a network client and captures every response it returns,
+// so a live session can be saved with Save and replayed with LoadReplayClient
+type Recorder struct {
+	client domain.NetworkClient
+
+	mu      sync.Mutex
+	fixture Fixture
+}
+
+// NewRecorder creates a recorder capturing the responses of client
+func NewRecorder(client domain.NetworkClient) *Recorder {
+	return &Recorder{
+		client:  client,
+		fixture: Fixture{Version: FixtureVersion, Recorded: time.Now()},
+	}
+}
+
+// Fixture returns a copy of the responses recorded so far
+func (r *Recorder) Fixture() Fixture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.fixture
+}
+
+// Save writes the recorded responses to path
+func (r *Recorder) Save(path string) error {
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.fixture, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode recording: %w", err)
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create recording directory: %w", err)
+		}
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	return nil
+}
+
+// Ping implements domain.NetworkClient and records the results once the ping ends
+func (r *Recorder) Ping(ctx context.Context, host string, opts domain.PingOptions) (<-chan domain.PingResult, error) {
+	results, err := r.client.Ping(ctx, host, opts)
+	if err != nil {
+		r.record(func(f *Fixture) {
+			f.Ping = append(f.Ping, pingFixture{Host: host, Error: newFixtureError(err)})
+		})
+		return nil, err
+	}
+
+	out := make(chan domain.PingResult, cap(results))
+	go func() {
+		defer close(out)
+		var replies []pingReply
+		for result := range results {
+			reply := pingReply{PingResult: result}
+			if result.Error != nil {
+				reply.Error = result.Error.Error()
+				reply.PingResult.Error = nil
+			}
+			replies = append(replies, reply)
+			select {
+			case out <- result:
+			case <-ctx.Done():
+			}
+		}
+		r.record(func(f *Fixture) {
+			f.Ping = append(f.Ping, pingFixture{Host: host, Results: replies})
+		})
+	}()
+	return out, nil
+}
+
+// Traceroute implements domain.NetworkClient and records the hops once the trace ends
+func (r *Recorder) Traceroute(ctx context.Context, host string, opts domain.TraceOptions) (<-chan domain.TraceHop, error) {
+	hops, err := r.client.Traceroute(ctx, host, opts)
+	if err != nil {
+		r.record(func(f *Fixture) {
+			f.Traceroute = append(f.Traceroute, traceFixture{Host: host, Error: newFixtureError(err)})
+		})
+		return nil, err
+	}
+
+	out := make(chan domain.TraceHop, cap(hops))
+	go func() {
+		defer close(out)
+		var recorded []domain.TraceHop
+		for hop := range hops {
+			recorded = append(recorded, hop)
+			select {
+			case out <- hop:
+			case <-ctx.Done():
+			}
+		}
+		r.record(func(f *Fixture) {
+			f.Traceroute = append(f.Traceroute, traceFixture{Host: host, Hops: recorded})
+		})
+	}()
+	return out, nil
+}
+
+// DNSLookup implements domain.NetworkClient
+func (r *Recorder) DNSLookup(ctx context.Context, domainName string, recordType domain.DNSRecordType) (domain.DNSResult, error) {
+	result, err := r.client.DNSLookup(ctx, domainName, recordType)
+	r.record(func(f *Fixture) {
+		recorded := result
+		recorded.Cached = false
+		f.DNS = append(f.DNS, dnsFixture{Domain: domainName, Type: recordType, Result: recorded, Error: newFixtureError(err)})
+	})
+	return result, err
+}
+
+// WHOISLookup implements domain.NetworkClient
+func (r *Recorder) WHOISLookup(ctx context.Context, query string) (domain.WHOISResult, error) {
+	result, err := r.client.WHOISLookup(ctx, query)
+	r.record(func(f *Fixture) {
+		recorded := result
+		recorded.Cached = false
+		f.WHOIS = append(f.WHOIS, whoisFixture{Query: query, Result: recorded, Error: newFixtureError(err)})
+	})
+	return result, err
+}
+
+// SSLCheck implements domain.NetworkClient
+func (r *Recorder) SSLCheck(ctx context.Context, host string, port int) (domain.SSLResult, error) {
+	result, err := r.client.SSLCheck(ctx, host, port)
+	r.record(func(f *Fixture) {
+		recorded := sslFixture{Host: host, Port: port, Result: result, Error: newFixtureError(err)}
+		recorded.Result.Certificate = nil
+		recorded.Result.Chain = nil
+		if result.Certificate != nil {
+			recorded.Certificate = result.Certificate.Raw
+		}
+		for _, cert := range result.Chain {
+			if cert != nil {
+				recorded.Chain = append(recorded.Chain, cert.Raw)
+			}
+		}
+		f.SSL = append(f.SSL, recorded)
+	})
+	return result, err
+}
+
+// TCPConnect implements domain.ConnectivityClient when the wrapped client does
+func (r *Recorder) TCPConnect(ctx context.Context, ip net.IP, port int) (time.Duration, error) {
+	connector, ok := r.client.(domain.ConnectivityClient)
+	if !ok {
+		return 0, fmt.Errorf("network client does not support TCP connects")
+	}
+	elapsed, err := connector.TCPConnect(ctx, ip, port)
+	r.record(func(f *Fixture) {
+		f.Connect = append(f.Connect, connectFixture{IP: ip.String(), Port: port, Elapsed: elapsed, Error: newFixtureError(err)})
+	})
+	return elapsed, err
+}
+
+// ReverseLookup implements domain.ReverseResolver when the wrapped client does.
+// Failed lookups are not recorded; replayed addresses without names fail anyway.
+func (r *Recorder) ReverseLookup(ctx context.Context, ip net.IP) ([]string, error) {
+	resolver, ok := r.client.(domain.ReverseResolver)
+	if !ok {
+		return nil, fmt.Errorf("network client does not support reverse lookups")
+	}
+	names, err := resolver.ReverseLookup(ctx, ip)
+	if err == nil {
+		r.record(func(f *Fixture) {
+			f.Reverse = append(f.Reverse, reverseFixture{IP: ip.String(), Names: names})
+		})
+	}
+	return names, err
+}
+
+// ZoneTransfer implements domain.ZoneTransferClient when the wrapped client does.
+// Refused transfers are not recorded; replayed transfers are refused by default.
+func (r *Recorder) ZoneTransfer(ctx context.Context, server, zone string) ([]domain.DNSRecord, int, error) {
+	transferer, ok := r.client.(domain.ZoneTransferClient)
+	if !ok {
+		return nil, 0, fmt.Errorf("network client does not support zone transfers")
+	}
+	records, size, err := transferer.ZoneTransfer(ctx, server, zone)
+	if err == nil {
+		r.record(func(f *Fixture) {
+			f.ZoneTransfer = append(f.ZoneTransfer, zoneTransferFixture{Server: server, Zone: zone, Records: records})
+		})
+	}
+	return records, size, err
+}
+
+// Capabilities implements domain.CapabilityReporter for the wrapped client
+func (r *Recorder) Capabilities() domain.Capabilities {
+	if reporter, ok := r.client.(domain.CapabilityReporter); ok {
+		return reporter.Capabilities()
+	}
+	return domain.Capabilities{}
+}
+
+// record applies update to the fixture
+func (r *Recorder) record(update func(f *Fixture)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	update(&r.fixture)
+}
+
+// newFixtureError converts err for recording, or returns nil without an error
+func newFixtureError(err error) *fixtureError {
+	if err == nil {
+		return nil
+	}
+	recorded := &fixtureError{Message: err.Error(), Kind: domain.ErrorKindOf(err)}
+	var netErr *domain.NetTraceError
+	if errors.As(err, &netErr) {
+		recorded.Type = netErr.Type
+		recorded.Code = netErr.Code
+	}
+	return recorded
+}
+
+// err rebuilds the recorded error
+func (e *fixtureError) err() error {
+	if e == nil {
+		return nil
+	}
+	if e.Code == "" && e.Kind == "" && e.Type == domain.ErrorTypeNetwork {
+		return errors.New(e.Message)
+	}
+	return &domain.NetTraceError{
+		Type:      e.Type,
+		Message:   e.Message,
+		Timestamp: time.Now(),
+		Code:      e.Code,
+		Kind:      e.Kind,
+	}
+}
+
+// LoadFixture reads a fixture file written by Recorder.Save
+func LoadFixture(path string) (Fixture, error) {
+	var fixture Fixture
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fixture, fmt.Errorf("failed to read recording: %w", err)
+	}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return fixture, fmt.Errorf("failed to parse recording %s: %w", path, err)
+	}
+	if fixture.Version > FixtureVersion {
+		return fixture, fmt.Errorf("recording %s has unsupported version %d", path, fixture.Version)
+	}
+	return fixture, nil
+}
+
+// LoadReplayClient returns a mock client answering with the responses
+// recorded in path. When a query was recorded more than once the last
+// response wins; queries that were never recorded get the mock's
+// synthetic responses.
+func LoadReplayClient(path string) (*MockClient, error) {
+	fixture, err := LoadFixture(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayClient(fixture)
+}
+
+// NewReplayClient returns a mock client answering with the responses in fixture
+func NewReplayClient(fixture Fixture) (*MockClient, error) {
+	m := NewMockClient()
+	m.SetCapabilities(domain.Capabilities{
+		Platform: "replay",
+		ICMP:     true,
+		Notes:    []string{"Responses are replayed from a recording; no packets are sent"},
+		ProbedAt: fixture.Recorded,
+	})
+
+	for _, p := range fixture.Ping {
+		if p.Error != nil {
+			m.SetPingError(p.Host, p.Error.err())
+			continue
+		}
+		results := make([]domain.PingResult, len(p.Results))
+		for i, reply := range p.Results {
+			results[i] = reply.PingResult
+			if reply.Error != "" {
+				results[i].Error = errors.New(reply.Error)
+			}
+		}
+		delete(m.pingErrors, p.Host)
+		m.SetPingResponse(p.Host, results)
+	}
+	for _, t := range fixture.Traceroute {
+		if t.Error != nil {
+			m.SetTraceError(t.Host, t.Error.err())
+			continue
+		}
+		delete(m.traceErrors, t.Host)
+		m.SetTraceResponse(t.Host, t.Hops)
+	}
+	for _, d := range fixture.DNS {
+		if d.Error != nil {
+			m.SetDNSError(d.Domain, d.Type, d.Error.err())
+			continue
+		}
+		delete(m.dnsErrors, fmt.Sprintf("%s:%d", d.Domain, d.Type))
+		m.SetDNSResponse(d.Domain, d.Type, d.Result)
+	}
+	for _, w := range fixture.WHOIS {
+		if w.Error != nil {
+			m.SetWHOISError(w.Query, w.Error.err())
+			continue
+		}
+		delete(m.whoisErrors, w.Query)
+		m.SetWHOISResponse(w.Query, w.Result)
+	}
+	for _, s := range fixture.SSL {
+		if s.Error != nil {
+			m.SetSSLError(s.Host, s.Port, s.Error.err())
+			continue
+		}
+		result := s.Result
+		if len(s.Certificate) > 0 {
+			cert, err := x509.ParseCertificate(s.Certificate)
+			if err != nil {
+				return nil, fmt.Errorf("invalid certificate recorded for %s:%d: %w", s.Host, s.Port, err)
+			}
+			result.Certificate = cert
+		}
+		for _, der := range s.Chain {
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				return nil, fmt.Errorf("invalid chain certificate recorded for %s:%d: %w", s.Host, s.Port, err)
+			}
+			result.Chain = append(result.Chain, cert)
+		}
+		delete(m.sslErrors, fmt.Sprintf("%s:%d", s.Host, s.Port))
+		m.SetSSLResponse(s.Host, s.Port, result)
+	}
+	for _, c := range fixture.Connect {
+		m.SetConnectTime(c.IP, c.Port, c.Elapsed)
+		if c.Error != nil {
+			m.SetConnectError(c.IP, c.Port, c.Error.err())
+		} else {
+			delete(m.connectErrors, net.JoinHostPort(c.IP, fmt.Sprintf("%d", c.Port)))
+		}
+	}
+	for _, rev := range fixture.Reverse {
+		m.SetReverseLookup(rev.IP, rev.Names)
+	}
+	for _, z := range fixture.ZoneTransfer {
+		m.SetZoneTransfer(z.Server, z.Zone, z.Records)
+	}
+	return m, nil
+}
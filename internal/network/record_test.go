@@ -0,0 +1,173 @@
+package network
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// newTestCertificate returns a self-signed certificate for host
+func newTestCertificate(t *testing.T, host string) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	return cert
+}
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	live := NewMockClient()
+	live.SetPingResponse("example.com", []domain.PingResult{
+		{Sequence: 1, RTT: 12 * time.Millisecond, TTL: 56},
+		{Sequence: 2, Error: errors.New("request timeout")},
+	})
+	live.SetDNSResponse("example.com", domain.DNSRecordTypeA, domain.DNSResult{
+		Query:   "example.com",
+		Records: []domain.DNSRecord{{Name: "example.com", Type: domain.DNSRecordTypeA, Value: "192.0.2.1", TTL: 300}},
+	})
+	live.SetWHOISError("example.invalid", &domain.NetTraceError{Type: domain.ErrorTypeValidation, Message: "unsupported TLD", Code: "WHOIS_TLD"})
+	cert := newTestCertificate(t, "example.com")
+	live.SetSSLResponse("example.com", 443, domain.SSLResult{Host: "example.com", Port: 443, Certificate: cert, Chain: []*x509.Certificate{cert}, Valid: true})
+	live.SetConnectTime("192.0.2.1", 443, 7*time.Millisecond)
+	live.SetReverseLookup("192.0.2.1", []string{"example.com."})
+
+	recorder := NewRecorder(live)
+	ctx := context.Background()
+
+	results, err := recorder.Ping(ctx, "example.com", domain.PingOptions{Count: 2})
+	if err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	for range results {
+	}
+	if _, err := recorder.DNSLookup(ctx, "example.com", domain.DNSRecordTypeA); err != nil {
+		t.Fatalf("DNSLookup failed: %v", err)
+	}
+	if _, err := recorder.WHOISLookup(ctx, "example.invalid"); err == nil {
+		t.Fatal("Expected the WHOIS error to pass through")
+	}
+	if _, err := recorder.SSLCheck(ctx, "example.com", 443); err != nil {
+		t.Fatalf("SSLCheck failed: %v", err)
+	}
+	if _, err := recorder.TCPConnect(ctx, net.ParseIP("192.0.2.1"), 443); err != nil {
+		t.Fatalf("TCPConnect failed: %v", err)
+	}
+	if _, err := recorder.ReverseLookup(ctx, net.ParseIP("192.0.2.1")); err != nil {
+		t.Fatalf("ReverseLookup failed: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "fixtures", "session.json")
+	if err := recorder.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	replay, err := LoadReplayClient(path)
+	if err != nil {
+		t.Fatalf("LoadReplayClient failed: %v", err)
+	}
+
+	pings, err := replay.Ping(ctx, "example.com", domain.PingOptions{Count: 2, Mode: domain.PingModeFlood})
+	if err != nil {
+		t.Fatalf("replayed Ping failed: %v", err)
+	}
+	var replayed []domain.PingResult
+	for result := range pings {
+		replayed = append(replayed, result)
+	}
+	if len(replayed) != 2 || replayed[0].RTT != 12*time.Millisecond || replayed[1].Error == nil || replayed[1].Error.Error() != "request timeout" {
+		t.Errorf("Unexpected replayed ping results %+v", replayed)
+	}
+
+	dns, err := replay.DNSLookup(ctx, "example.com", domain.DNSRecordTypeA)
+	if err != nil || len(dns.Records) != 1 || dns.Records[0].Value != "192.0.2.1" {
+		t.Errorf("Unexpected replayed DNS answer %+v (%v)", dns, err)
+	}
+
+	_, err = replay.WHOISLookup(ctx, "example.invalid")
+	var netErr *domain.NetTraceError
+	if !errors.As(err, &netErr) || netErr.Type != domain.ErrorTypeValidation || netErr.Code != "WHOIS_TLD" {
+		t.Errorf("Expected the recorded validation error, got %v", err)
+	}
+
+	ssl, err := replay.SSLCheck(ctx, "example.com", 443)
+	if err != nil || ssl.Certificate == nil || !ssl.Certificate.Equal(cert) || len(ssl.Chain) != 1 {
+		t.Errorf("Expected the recorded certificate, got %+v (%v)", ssl, err)
+	}
+
+	elapsed, err := replay.TCPConnect(ctx, net.ParseIP("192.0.2.1"), 443)
+	if err != nil || elapsed != 7*time.Millisecond {
+		t.Errorf("Expected the recorded connect time, got %v (%v)", elapsed, err)
+	}
+
+	names, err := replay.ReverseLookup(ctx, net.ParseIP("192.0.2.1"))
+	if err != nil || len(names) != 1 || names[0] != "example.com." {
+		t.Errorf("Expected the recorded names, got %v (%v)", names, err)
+	}
+
+	if capabilities := replay.Capabilities(); capabilities.Platform != "replay" || !capabilities.ICMP {
+		t.Errorf("Unexpected replay capabilities %+v", capabilities)
+	}
+}
+
+func TestRecorder_LastResponseWins(t *testing.T) {
+	live := NewMockClient()
+	recorder := NewRecorder(live)
+	ctx := context.Background()
+
+	live.SetDNSError("example.com", domain.DNSRecordTypeMX, errors.New("timeout"))
+	recorder.DNSLookup(ctx, "example.com", domain.DNSRecordTypeMX)
+	live = NewMockClient()
+	live.SetDNSResponse("example.com", domain.DNSRecordTypeMX, domain.DNSResult{Query: "example.com"})
+	recorder.client = live
+	recorder.DNSLookup(ctx, "example.com", domain.DNSRecordTypeMX)
+
+	replay, err := NewReplayClient(recorder.Fixture())
+	if err != nil {
+		t.Fatalf("NewReplayClient failed: %v", err)
+	}
+	if _, err := replay.DNSLookup(ctx, "example.com", domain.DNSRecordTypeMX); err != nil {
+		t.Errorf("Expected the later answer to replace the recorded error, got %v", err)
+	}
+}
+
+func TestLoadReplayClient_Errors(t *testing.T) {
+	if _, err := LoadReplayClient(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected an error for a missing recording")
+	}
+
+	recorder := NewRecorder(NewMockClient())
+	recorder.fixture.Version = FixtureVersion + 1
+	path := filepath.Join(t.TempDir(), "future.json")
+	if err := recorder.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := LoadReplayClient(path); err == nil {
+		t.Error("Expected an error for an unsupported version")
+	}
+}
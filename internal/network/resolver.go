@@ -0,0 +1,310 @@
+// Package network provides DNS server selection with ordered failover and health tracking
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// dnsHealthProbeDomain is queried when checking DNS server health
+const dnsHealthProbeDomain = "example.com"
+
+// resolvConfPath lists the system nameservers on Unix systems
+const resolvConfPath = "/etc/resolv.conf"
+
+// hostsPath holds static host name overrides on Unix systems
+const hostsPath = "/etc/hosts"
+
+// dnsLookupFunc performs a lookup against a single DNS server
+type dnsLookupFunc func(ctx context.Context, server string) ([]domain.DNSRecord, error)
+
+// dnsServerHealth tracks lookup outcomes per DNS server
+type dnsServerHealth struct {
+	mu     sync.RWMutex
+	status map[string]*domain.DNSServerStatus
+}
+
+// newDNSServerHealth creates an empty health tracker
+func newDNSServerHealth() *dnsServerHealth {
+	return &dnsServerHealth{
+		status: make(map[string]*domain.DNSServerStatus),
+	}
+}
+
+// record stores the outcome of a query against server
+func (h *dnsServerHealth) record(server string, elapsed time.Duration, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	status, exists := h.status[server]
+	if !exists {
+		status = &domain.DNSServerStatus{Server: server}
+		h.status[server] = status
+	}
+
+	status.Checked = true
+	status.LastChecked = time.Now()
+	status.LastResponseTime = elapsed
+	if err != nil {
+		status.Healthy = false
+		status.LastError = err.Error()
+		status.Failures++
+		return
+	}
+	status.Healthy = true
+	status.LastError = ""
+	status.Successes++
+}
+
+// snapshot returns the status of servers in the given order
+func (h *dnsServerHealth) snapshot(servers []string) []domain.DNSServerStatus {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	statuses := make([]domain.DNSServerStatus, 0, len(servers))
+	for _, server := range servers {
+		if status, exists := h.status[server]; exists {
+			statuses = append(statuses, *status)
+			continue
+		}
+		statuses = append(statuses, domain.DNSServerStatus{Server: server})
+	}
+	return statuses
+}
+
+// configuredDNSServers returns the configured DNS servers, or the system resolver when none are set
+func (c *Client) configuredDNSServers() []string {
+	var servers []string
+	for _, server := range c.current().config.DNSServers {
+		if server = strings.TrimSpace(server); server != "" {
+			servers = append(servers, server)
+		}
+	}
+	if len(servers) == 0 {
+		return []string{domain.SystemDNSServer}
+	}
+	return servers
+}
+
+// dnsServers returns the servers to query in order, honouring any override carried by ctx
+func (c *Client) dnsServers(ctx context.Context) []string {
+	if server := strings.TrimSpace(domain.DNSServerFromContext(ctx)); server != "" {
+		return []string{server}
+	}
+	return c.configuredDNSServers()
+}
+
+// resolverFor returns a resolver that sends queries to server
+func (c *Client) resolverFor(server string) *net.Resolver {
+	if server == domain.SystemDNSServer {
+		return net.DefaultResolver
+	}
+
+	address := dnsServerAddress(server)
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			dialer, err := newDialer(ctx, c.current().config.Timeout, address)
+			if err != nil {
+				return nil, err
+			}
+			return dialer.DialContext(ctx, network, address)
+		},
+	}
+}
+
+// queryServer looks up records on server over the DNS wire protocol so the
+// answers carry the TTLs the server returned. The system resolver is queried
+// through the nameservers in resolv.conf, falling back to the stdlib resolver
+// (without TTLs) when they are unknown, unreachable, or do not know the name.
+// Addresses of names listed in the hosts file come from the stdlib resolver
+// so local overrides win over the nameservers, as they do for the system.
+func (c *Client) queryServer(ctx context.Context, server, domainName string, recordType domain.DNSRecordType) ([]domain.DNSRecord, error) {
+	if server != domain.SystemDNSServer {
+		return c.wireLookup(ctx, dnsServerAddress(server), domainName, recordType)
+	}
+
+	addressType := recordType == domain.DNSRecordTypeA || recordType == domain.DNSRecordTypeAAAA
+	if addressType && inHostsFile(domainName) {
+		return c.lookupRecords(ctx, net.DefaultResolver, domainName, recordType)
+	}
+
+	for _, address := range systemNameservers() {
+		records, err := c.wireLookup(ctx, address, domainName, recordType)
+		if err == nil {
+			return records, nil
+		}
+		c.logger.Debug("System nameserver query failed", "server", address, "error", err)
+	}
+	return c.lookupRecords(ctx, net.DefaultResolver, domainName, recordType)
+}
+
+// wireLookup queries address for records of recordType. Records of other
+// types in the answer, such as the CNAME chain for an A query, are skipped.
+func (c *Client) wireLookup(ctx context.Context, address, domainName string, recordType domain.DNSRecordType) (records []domain.DNSRecord, err error) {
+	ctx, span := tracing.Start(ctx, "dns query", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("dns.question.name", domainName),
+		attribute.Int("dns.question.type", int(dnsRecordTypes[recordType])),
+		attribute.String("server.address", address),
+	))
+	defer func() {
+		tracing.Fail(span, err)
+		span.SetAttributes(attribute.Int("dns.answer.count", len(records)))
+		span.End()
+	}()
+
+	response, err := exchangeDNS(ctx, address, domainName, dnsRecordTypes[recordType], c.current().config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	switch response.Rcode {
+	case dns.RcodeSuccess:
+	case dns.RcodeNameError:
+		return nil, &net.DNSError{Err: "no such host", Name: domainName, Server: address, IsNotFound: true}
+	default:
+		return nil, &net.DNSError{Err: fmt.Sprintf("server returned rcode %d", response.Rcode), Name: domainName, Server: address}
+	}
+
+	for _, rr := range response.Answer {
+		if record, ok := dnsRecord(rr); ok && record.Type == recordType {
+			records = append(records, record)
+		}
+	}
+	if len(records) == 0 {
+		return nil, &net.DNSError{Err: "no answer for record type", Name: domainName, Server: address, IsNotFound: true}
+	}
+	return records, nil
+}
+
+// systemNameservers returns the nameservers listed in resolv.conf, or none
+// on platforms without one
+func systemNameservers() []string {
+	data, err := os.ReadFile(resolvConfPath)
+	if err != nil {
+		return nil
+	}
+
+	var servers []string
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			servers = append(servers, dnsServerAddress(fields[1]))
+		}
+	}
+	return servers
+}
+
+// inHostsFile reports whether the hosts file lists name
+func inHostsFile(name string) bool {
+	data, err := os.ReadFile(hostsPath)
+	if err != nil {
+		return false
+	}
+	return hostsFileLists(string(data), name)
+}
+
+// hostsFileLists reports whether name appears as a host name or alias in
+// hosts file contents
+func hostsFileLists(hosts, name string) bool {
+	name = strings.TrimSuffix(name, ".")
+	for _, line := range strings.Split(hosts, "\n") {
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		for _, host := range fields[1:] {
+			if strings.EqualFold(strings.TrimSuffix(host, "."), name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lookupWithFailover queries servers in order until one answers.
+// An authoritative "not found" answer ends the search since other servers
+// would return the same result.
+func (c *Client) lookupWithFailover(ctx context.Context, servers []string, lookup dnsLookupFunc) ([]domain.DNSRecord, string, error) {
+	state := c.current()
+	var lastErr error
+	for _, server := range servers {
+		serverCtx, cancel := ctx, context.CancelFunc(func() {})
+		if state.config.Timeout > 0 {
+			serverCtx, cancel = context.WithTimeout(ctx, state.config.Timeout)
+		}
+
+		start := time.Now()
+		records, err := lookup(serverCtx, server)
+		elapsed := time.Since(start)
+		cancel()
+
+		if err == nil || isDNSAnswerError(err) {
+			state.dnsHealth.record(server, elapsed, nil)
+			return records, server, err
+		}
+
+		state.dnsHealth.record(server, elapsed, err)
+		c.logger.Warn("DNS server failed", "server", server, "error", err)
+		lastErr = err
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, "", lastErr
+}
+
+// DNSServerStatus returns the health of the configured DNS servers
+func (c *Client) DNSServerStatus() []domain.DNSServerStatus {
+	return c.current().dnsHealth.snapshot(c.configuredDNSServers())
+}
+
+// CheckDNSServers probes every configured DNS server and returns the updated health
+func (c *Client) CheckDNSServers(ctx context.Context) []domain.DNSServerStatus {
+	servers := c.configuredDNSServers()
+
+	var wg sync.WaitGroup
+	for _, server := range servers {
+		wg.Add(1)
+		go func(server string) {
+			defer wg.Done()
+			c.lookupWithFailover(ctx, []string{server}, func(ctx context.Context, server string) ([]domain.DNSRecord, error) {
+				_, err := c.resolverFor(server).LookupHost(ctx, dnsHealthProbeDomain)
+				return nil, err
+			})
+		}(server)
+	}
+	wg.Wait()
+
+	return c.current().dnsHealth.snapshot(servers)
+}
+
+// dnsServerAddress adds the default DNS port to server when none is given
+func dnsServerAddress(server string) string {
+	if _, _, err := net.SplitHostPort(server); err == nil {
+		return server
+	}
+	return net.JoinHostPort(strings.Trim(server, "[]"), "53")
+}
+
+// isDNSAnswerError reports whether err is a definitive answer rather than a server failure
+func isDNSAnswerError(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
@@ -0,0 +1,134 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+func newResolverTestClient(servers []string) *Client {
+	config := &domain.NetworkConfig{
+		Timeout:       time.Second,
+		RetryAttempts: 1,
+		RetryDelay:    time.Millisecond,
+		DNSServers:    servers,
+	}
+	return NewClient(config, &mockErrorHandler{}, &mockLogger{})
+}
+
+func TestClient_LookupWithFailover_UsesNextServer(t *testing.T) {
+	client := newResolverTestClient([]string{"192.0.2.1", "192.0.2.2", "192.0.2.3"})
+
+	var queried []string
+	records, server, err := client.lookupWithFailover(context.Background(), client.dnsServers(context.Background()),
+		func(ctx context.Context, server string) ([]domain.DNSRecord, error) {
+			queried = append(queried, server)
+			if server == "192.0.2.1" {
+				return nil, errors.New("i/o timeout")
+			}
+			return []domain.DNSRecord{{Name: "example.com", Type: domain.DNSRecordTypeA, Value: "93.184.216.34"}}, nil
+		})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if server != "192.0.2.2" {
+		t.Errorf("Expected answer from second server, got %s", server)
+	}
+	if len(records) != 1 {
+		t.Errorf("Expected 1 record, got %d", len(records))
+	}
+	if len(queried) != 2 {
+		t.Errorf("Expected failover to stop after first success, queried %v", queried)
+	}
+
+	statuses := client.DNSServerStatus()
+	if len(statuses) != 3 {
+		t.Fatalf("Expected 3 statuses, got %d", len(statuses))
+	}
+	if !statuses[0].Checked || statuses[0].Healthy || statuses[0].Failures != 1 || statuses[0].LastError == "" {
+		t.Errorf("Expected first server to be failing, got %+v", statuses[0])
+	}
+	if !statuses[1].Healthy || statuses[1].Successes != 1 {
+		t.Errorf("Expected second server to be healthy, got %+v", statuses[1])
+	}
+	if statuses[2].Checked {
+		t.Errorf("Expected third server to be unchecked, got %+v", statuses[2])
+	}
+}
+
+func TestClient_LookupWithFailover_NotFoundStopsSearch(t *testing.T) {
+	client := newResolverTestClient([]string{"192.0.2.1", "192.0.2.2"})
+
+	calls := 0
+	_, server, err := client.lookupWithFailover(context.Background(), client.dnsServers(context.Background()),
+		func(ctx context.Context, server string) ([]domain.DNSRecord, error) {
+			calls++
+			return nil, &net.DNSError{Err: "no such host", Name: "missing.example", IsNotFound: true}
+		})
+	if err == nil {
+		t.Fatal("Expected not found error")
+	}
+	if calls != 1 || server != "192.0.2.1" {
+		t.Errorf("Expected a single authoritative answer, got %d calls from %s", calls, server)
+	}
+	if status := client.DNSServerStatus()[0]; !status.Healthy {
+		t.Errorf("NXDOMAIN should not mark the server unhealthy, got %+v", status)
+	}
+}
+
+func TestClient_LookupWithFailover_AllServersFail(t *testing.T) {
+	client := newResolverTestClient([]string{"192.0.2.1", "192.0.2.2"})
+
+	_, _, err := client.lookupWithFailover(context.Background(), client.dnsServers(context.Background()),
+		func(ctx context.Context, server string) ([]domain.DNSRecord, error) {
+			return nil, errors.New("connection refused by " + server)
+		})
+	if err == nil || err.Error() != "connection refused by 192.0.2.2" {
+		t.Errorf("Expected last server error, got %v", err)
+	}
+}
+
+func TestClient_DNSServers(t *testing.T) {
+	client := newResolverTestClient([]string{" 1.1.1.1 ", "", "8.8.8.8"})
+
+	servers := client.dnsServers(context.Background())
+	if len(servers) != 2 || servers[0] != "1.1.1.1" || servers[1] != "8.8.8.8" {
+		t.Errorf("Expected configured servers in order, got %v", servers)
+	}
+
+	override := client.dnsServers(domain.WithDNSServer(context.Background(), "9.9.9.9"))
+	if len(override) != 1 || override[0] != "9.9.9.9" {
+		t.Errorf("Expected override server, got %v", override)
+	}
+
+	// Configuration changes are picked up without recreating the client
+	changed := client.current().config
+	changed.DNSServers = nil
+	client.Reconfigure(changed)
+	servers = client.dnsServers(context.Background())
+	if len(servers) != 1 || servers[0] != domain.SystemDNSServer {
+		t.Errorf("Expected system resolver fallback, got %v", servers)
+	}
+	if client.resolverFor(domain.SystemDNSServer) != net.DefaultResolver {
+		t.Error("Expected system resolver to use net.DefaultResolver")
+	}
+}
+
+func TestDNSServerAddress(t *testing.T) {
+	tests := map[string]string{
+		"8.8.8.8":           "8.8.8.8:53",
+		"8.8.8.8:5353":      "8.8.8.8:5353",
+		"2001:4860::8888":   "[2001:4860::8888]:53",
+		"[2001:4860::8888]": "[2001:4860::8888]:53",
+		"[::1]:5353":        "[::1]:5353",
+	}
+	for server, expected := range tests {
+		if got := dnsServerAddress(server); got != expected {
+			t.Errorf("dnsServerAddress(%q) = %q, want %q", server, got, expected)
+		}
+	}
+}
@@ -3,8 +3,14 @@ package network
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
 	"time"
 
 	"github.com/nettracex/nettracex-tui/internal/domain"
@@ -14,6 +20,7 @@ import (
 type RetryManager struct {
 	maxAttempts int
 	baseDelay   time.Duration
+	jitter      func() float64
 }
 
 // NewRetryManager creates a new retry manager with the specified configuration
@@ -28,6 +35,7 @@ func NewRetryManager(maxAttempts int, baseDelay time.Duration) *RetryManager {
 	return &RetryManager{
 		maxAttempts: maxAttempts,
 		baseDelay:   baseDelay,
+		jitter:      rand.Float64,
 	}
 }
 
@@ -103,6 +111,16 @@ func (rm *RetryManager) calculateDelay(attempt int) time.Duration {
 	delay := time.Duration(float64(rm.baseDelay) * math.Pow(2, float64(attempt-1)))
 	maxDelay := 30 * time.Second
 	
+	if delay > maxDelay || delay < 0 {
+		delay = maxDelay
+	}
+	
+	// Add up to the same again at random so operations that failed together
+	// do not retry in lockstep
+	if rm.jitter != nil {
+		delay += time.Duration(rm.jitter() * float64(delay))
+	}
+	
 	if delay > maxDelay {
 		delay = maxDelay
 	}
@@ -250,4 +268,51 @@ func (rm *RetryManager) SetBaseDelay(baseDelay time.Duration) {
 	if baseDelay > 0 {
 		rm.baseDelay = baseDelay
 	}
-}
\ No newline at end of file
+}
+// HTTPStatusError reports an HTTP response with an unexpected status code
+type HTTPStatusError struct {
+	StatusCode int
+}
+
+// Error implements the error interface
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("unexpected HTTP status %d", e.StatusCode)
+}
+
+// IsRetryable reports whether err is a transient failure worth retrying:
+// timeouts, temporary DNS failures, dropped or refused connections and HTTP
+// 429 or 5xx responses. Validation errors, cancellation, names that do not
+// exist and anything unrecognised are final.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var traceErr *domain.NetTraceError
+	if errors.As(err, &traceErr) && traceErr.Type == domain.ErrorTypeValidation {
+		return false
+	}
+
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests ||
+			(statusErr.StatusCode >= 500 && statusErr.StatusCode != http.StatusNotImplemented)
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return !dnsErr.IsNotFound && (dnsErr.IsTimeout || dnsErr.IsTemporary)
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout() || netErr.Temporary()
+	}
+	return false
+}
@@ -4,7 +4,9 @@ package network
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
+	"syscall"
 	"testing"
 	"time"
 
@@ -383,4 +385,50 @@ func TestRetryManager_SetBaseDelay(t *testing.T) {
 	if rm.GetBaseDelay() != newDelay {
 		t.Errorf("Expected base delay to remain %v, got %v", newDelay, rm.GetBaseDelay())
 	}
-}
\ No newline at end of file
+}
+func TestRetryManager_CalculateDelayJitter(t *testing.T) {
+	rm := NewRetryManager(5, 100*time.Millisecond)
+	rm.jitter = func() float64 { return 0.5 }
+
+	if delay := rm.calculateDelay(1); delay != 150*time.Millisecond {
+		t.Errorf("Expected 150ms with half jitter, got %v", delay)
+	}
+	if delay := rm.calculateDelay(3); delay != 600*time.Millisecond {
+		t.Errorf("Expected 600ms with half jitter, got %v", delay)
+	}
+	if delay := rm.calculateDelay(9); delay != 30*time.Second {
+		t.Errorf("Expected jittered delay to stay capped, got %v", delay)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"timeout", &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{IsTimeout: true}}, true},
+		{"temporary DNS failure", &net.DNSError{Err: "server misbehaving", IsTemporary: true}, true},
+		{"missing name", &net.DNSError{Err: "no such host", IsNotFound: true}, false},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, true},
+		{"truncated response", fmt.Errorf("read response: %w", io.ErrUnexpectedEOF), true},
+		{"rate limited", &HTTPStatusError{StatusCode: 429}, true},
+		{"server error", &HTTPStatusError{StatusCode: 503}, true},
+		{"not implemented", &HTTPStatusError{StatusCode: 501}, false},
+		{"client error", &HTTPStatusError{StatusCode: 404}, false},
+		{"cancelled", fmt.Errorf("lookup: %w", context.Canceled), false},
+		{"validation", &domain.NetTraceError{Type: domain.ErrorTypeValidation, Cause: syscall.ECONNRESET}, false},
+		{"wrapped timeout", &domain.NetTraceError{Type: domain.ErrorTypeNetwork, Cause: &net.DNSError{IsTimeout: true}}, true},
+		{"unknown", fmt.Errorf("certificate signed by unknown authority"), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsRetryable(tc.err); got != tc.expected {
+				t.Errorf("IsRetryable(%v) = %v, expected %v", tc.err, got, tc.expected)
+			}
+		})
+	}
+}
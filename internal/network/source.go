@@ -0,0 +1,166 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// SourceParam is the tool parameter selecting the network interface or local
+// address that operations are bound to
+const SourceParam = "source"
+
+// sourceKey is the context key of the source set by WithSource
+type sourceKey struct{}
+
+// WithSource returns a context whose network operations are bound to source:
+// an interface name such as eth0 or wg0, or a local IP address. An empty
+// source leaves the choice to the routing table.
+func WithSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, sourceKey{}, strings.TrimSpace(source))
+}
+
+// SourceFromContext returns the source set by WithSource
+func SourceFromContext(ctx context.Context) string {
+	source, _ := ctx.Value(sourceKey{}).(string)
+	return source
+}
+
+// ResolveSource returns the local address for source. An IP address must be
+// assigned to this host; for an interface the address of the preferred
+// family is used, falling back to the other family.
+func ResolveSource(source string, ipv6 bool) (net.IP, error) {
+	if ip := net.ParseIP(source); ip != nil {
+		if !isLocalAddress(ip) {
+			return nil, fmt.Errorf("source address %s is not assigned to this host", source)
+		}
+		return ip, nil
+	}
+
+	iface, err := net.InterfaceByName(source)
+	if err != nil {
+		return nil, fmt.Errorf("unknown source interface %q", source)
+	}
+	if iface.Flags&net.FlagUp == 0 {
+		return nil, fmt.Errorf("source interface %s is down", source)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read addresses of interface %s: %w", source, err)
+	}
+
+	var fallback net.IP
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		if (ipNet.IP.To4() == nil) == ipv6 {
+			return ipNet.IP, nil
+		}
+		if fallback == nil {
+			fallback = ipNet.IP
+		}
+	}
+	if fallback == nil {
+		return nil, fmt.Errorf("source interface %s has no usable address", source)
+	}
+	return fallback, nil
+}
+
+// isLocalAddress reports whether ip is assigned to an interface of this host
+func isLocalAddress(ip net.IP) bool {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return false
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// sourceAddress resolves the source in ctx, returning nil when none is set
+func sourceAddress(ctx context.Context, ipv6 bool) (net.IP, error) {
+	source := SourceFromContext(ctx)
+	if source == "" {
+		return nil, nil
+	}
+	return ResolveSource(source, ipv6)
+}
+
+// newDialer returns a dialer with timeout that binds to the source in ctx.
+// The source address family follows address when it is an IP literal.
+func newDialer(ctx context.Context, timeout time.Duration, address string) (*net.Dialer, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+
+	host := address
+	if h, _, err := net.SplitHostPort(address); err == nil {
+		host = h
+	}
+	ip := net.ParseIP(host)
+	source, err := sourceAddress(ctx, ip != nil && ip.To4() == nil)
+	if err != nil || source == nil {
+		return dialer, err
+	}
+	dialer.LocalAddr = &net.TCPAddr{IP: source}
+	return dialer, nil
+}
+
+// SourceTool binds the network operations of a diagnostic tool to the
+// interface or address given in its source parameter
+type SourceTool struct {
+	domain.DiagnosticTool
+}
+
+// BindSource wraps tool so its source parameter takes effect
+func BindSource(tool domain.DiagnosticTool) domain.DiagnosticTool {
+	return &SourceTool{DiagnosticTool: tool}
+}
+
+// Unwrap returns the wrapped diagnostic tool
+func (t *SourceTool) Unwrap() domain.DiagnosticTool {
+	return t.DiagnosticTool
+}
+
+// LiveModel returns the live screen of the wrapped tool when no source is
+// requested; live screens are not bound to one, so such runs use Execute
+func (t *SourceTool) LiveModel(ctx context.Context, params domain.Parameters) (tea.Model, error) {
+	if source, _ := params.Get(SourceParam).(string); strings.TrimSpace(source) != "" {
+		return nil, nil
+	}
+	return domain.LiveModel(ctx, t.DiagnosticTool, params)
+}
+
+// Execute runs the tool bound to the requested source
+func (t *SourceTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	source, _ := params.Get(SourceParam).(string)
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return t.DiagnosticTool.Execute(ctx, params)
+	}
+
+	if _, err := ResolveSource(source, false); err != nil {
+		return nil, &domain.NetTraceError{
+			Type:      domain.ErrorTypeValidation,
+			Message:   "invalid source interface or address",
+			Cause:     err,
+			Context:   map[string]interface{}{"source": source},
+			Timestamp: time.Now(),
+			Code:      "INVALID_SOURCE",
+		}
+	}
+
+	result, err := t.DiagnosticTool.Execute(WithSource(ctx, source), params)
+	if result, ok := result.(*domain.BaseResult); ok {
+		result.SetMetadata(SourceParam, source)
+	}
+	return result, err
+}
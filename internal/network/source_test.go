@@ -0,0 +1,166 @@
+package network
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// loopbackInterface returns the name of the loopback interface, skipping the
+// test when there is none
+func loopbackInterface(t *testing.T) string {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		t.Skipf("Cannot list interfaces: %v", err)
+	}
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagLoopback != 0 && iface.Flags&net.FlagUp != 0 {
+			return iface.Name
+		}
+	}
+	t.Skip("No loopback interface")
+	return ""
+}
+
+func TestResolveSource(t *testing.T) {
+	ip, err := ResolveSource("127.0.0.1", false)
+	if err != nil || !ip.Equal(net.ParseIP("127.0.0.1")) {
+		t.Errorf("Expected 127.0.0.1, got %v (%v)", ip, err)
+	}
+
+	ip, err = ResolveSource(loopbackInterface(t), false)
+	if err != nil || !ip.IsLoopback() || ip.To4() == nil {
+		t.Errorf("Expected the IPv4 loopback address, got %v (%v)", ip, err)
+	}
+
+	if _, err := ResolveSource("192.0.2.77", false); err == nil {
+		t.Error("Expected an address not assigned to this host to be rejected")
+	}
+	if _, err := ResolveSource("nettracex-missing0", false); err == nil {
+		t.Error("Expected an unknown interface to be rejected")
+	}
+}
+
+func TestNewDialer_BindsSource(t *testing.T) {
+	dialer, err := newDialer(context.Background(), time.Second, "192.0.2.1:43")
+	if err != nil || dialer.LocalAddr != nil {
+		t.Fatalf("Expected an unbound dialer without a source, got %v (%v)", dialer.LocalAddr, err)
+	}
+
+	ctx := WithSource(context.Background(), " 127.0.0.1 ")
+	dialer, err = newDialer(ctx, time.Second, "127.0.0.1:43")
+	if err != nil {
+		t.Fatalf("newDialer failed: %v", err)
+	}
+	local, ok := dialer.LocalAddr.(*net.TCPAddr)
+	if !ok || !local.IP.Equal(net.ParseIP("127.0.0.1")) {
+		t.Errorf("Expected dialer bound to 127.0.0.1, got %v", dialer.LocalAddr)
+	}
+	if dialer.Timeout != time.Second {
+		t.Errorf("Expected timeout to be kept, got %v", dialer.Timeout)
+	}
+}
+
+func TestClient_TCPConnectFromSource(t *testing.T) {
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("Cannot listen: %v", err)
+	}
+	defer listener.Close()
+	accepted := make(chan net.Addr, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			accepted <- conn.RemoteAddr()
+			conn.Close()
+		}
+	}()
+
+	client := newResolverTestClient(nil)
+	port := listener.Addr().(*net.TCPAddr).Port
+	ctx := WithSource(context.Background(), "127.0.0.1")
+	if _, err := client.TCPConnect(ctx, net.ParseIP("127.0.0.1"), port); err != nil {
+		t.Fatalf("TCPConnect failed: %v", err)
+	}
+	if remote := (<-accepted).(*net.TCPAddr); !remote.IP.Equal(net.ParseIP("127.0.0.1")) {
+		t.Errorf("Expected connection from 127.0.0.1, got %v", remote)
+	}
+
+	ctx = WithSource(context.Background(), "192.0.2.77")
+	if _, err := client.TCPConnect(ctx, net.ParseIP("127.0.0.1"), port); err == nil {
+		t.Error("Expected TCPConnect from a foreign source address to fail")
+	}
+}
+
+// sourceRecordingTool records the source its operations would be bound to
+type sourceRecordingTool struct {
+	source string
+}
+
+func (t *sourceRecordingTool) Name() string                            { return "recorder" }
+func (t *sourceRecordingTool) Description() string                     { return "records the source" }
+func (t *sourceRecordingTool) Validate(params domain.Parameters) error { return nil }
+func (t *sourceRecordingTool) GetModel() tea.Model                     { return nil }
+
+func (t *sourceRecordingTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	t.source = SourceFromContext(ctx)
+	return domain.NewResult(nil), nil
+}
+
+func TestSourceTool_Execute(t *testing.T) {
+	recorder := &sourceRecordingTool{}
+	tool := BindSource(recorder)
+
+	params := domain.NewParameters()
+	params.Set(SourceParam, "127.0.0.1")
+	result, err := tool.Execute(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if recorder.source != "127.0.0.1" {
+		t.Errorf("Expected source in context, got %q", recorder.source)
+	}
+	if result.Metadata()[SourceParam] != "127.0.0.1" {
+		t.Errorf("Expected source in metadata, got %v", result.Metadata())
+	}
+
+	params.Set(SourceParam, "nettracex-missing0")
+	if _, err := tool.Execute(context.Background(), params); err == nil {
+		t.Error("Expected an unknown source to be rejected")
+	}
+
+	recorder.source = "unchanged"
+	if _, err := tool.Execute(context.Background(), domain.NewParameters()); err != nil || recorder.source != "" {
+		t.Errorf("Expected no source without the parameter, got %q (%v)", recorder.source, err)
+	}
+}
+
+// liveRecordingTool has a live screen, which cannot be bound to a source
+type liveRecordingTool struct {
+	sourceRecordingTool
+	opened int
+}
+
+func (t *liveRecordingTool) LiveModel(ctx context.Context, params domain.Parameters) (tea.Model, error) {
+	t.opened++
+	return t.GetModel(), nil
+}
+
+func TestSourceTool_LiveModel(t *testing.T) {
+	recorder := &liveRecordingTool{}
+	tool := BindSource(recorder)
+
+	if _, err := domain.LiveModel(context.Background(), tool, domain.NewParameters()); err != nil || recorder.opened != 1 {
+		t.Errorf("Expected the live screen without a source, opened %d (%v)", recorder.opened, err)
+	}
+
+	params := domain.NewParameters()
+	params.Set(SourceParam, "127.0.0.1")
+	if model, err := domain.LiveModel(context.Background(), tool, params); model != nil || err != nil || recorder.opened != 1 {
+		t.Errorf("Expected a run from a source to use Execute, got %v (%v)", model, err)
+	}
+}
@@ -0,0 +1,547 @@
+package network
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// SSHClient runs ping, traceroute and DNS lookups on a remote host through
+// the system ssh command, so results show the network as seen from that
+// host. WHOIS lookups, certificate checks and TCP connects are still made
+// from the local host by the embedded client.
+type SSHClient struct {
+	*Client
+	target string
+	port   int
+	run    commandRunner
+}
+
+// commandRunner runs command on the remote host, calling line for each line
+// of its output as it arrives
+type commandRunner func(ctx context.Context, command string, line func(string)) error
+
+// NewSSHClient creates a client running commands on target, given as
+// [user@]host[:port]. Authentication is left to ssh, so keys, agents and
+// ~/.ssh/config apply; password prompts are disabled.
+func NewSSHClient(local *Client, target string) (*SSHClient, error) {
+	destination, port, err := ParseSSHTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	client := &SSHClient{Client: local, target: destination, port: port}
+	client.run = client.runSSH
+	return client, nil
+}
+
+// ParseSSHTarget splits [user@]host[:port] into the ssh destination and port,
+// which is 0 when ssh should pick it
+func ParseSSHTarget(value string) (string, int, error) {
+	value = strings.TrimSpace(value)
+	user, host, found := strings.Cut(value, "@")
+	if !found {
+		user, host = "", value
+	}
+
+	port := 0
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		number, err := strconv.Atoi(p)
+		if err != nil || number <= 0 || number > 65535 {
+			return "", 0, fmt.Errorf("invalid port in SSH target %q", value)
+		}
+		host, port = h, number
+	}
+	if host == "" || strings.HasPrefix(host, "-") || strings.HasPrefix(user, "-") || (found && user == "") {
+		return "", 0, fmt.Errorf("SSH target %q must be [user@]host[:port]", value)
+	}
+
+	if user != "" {
+		return user + "@" + host, port, nil
+	}
+	return host, port, nil
+}
+
+// Target returns the ssh destination commands run on
+func (c *SSHClient) Target() string {
+	return c.target
+}
+
+// Ping runs ping on the remote host
+func (c *SSHClient) Ping(ctx context.Context, host string, opts domain.PingOptions) (<-chan domain.PingResult, error) {
+	if err := c.validateRemoteHost(host); err != nil {
+		return nil, &domain.NetTraceError{
+			Type:      domain.ErrorTypeValidation,
+			Message:   "invalid host for ping operation",
+			Cause:     err,
+			Context:   map[string]interface{}{"host": host, "via": c.target},
+			Timestamp: time.Now(),
+			Code:      "PING_INVALID_HOST",
+		}
+	}
+
+	opts = c.applyPingLimits(opts)
+	if opts.Count <= 0 {
+		opts.Count = 1
+	}
+	resultChan := make(chan domain.PingResult, pingBufferSize(opts))
+
+	// The remote host sends the probes, so the stream takes no local
+	// concurrency slot
+	go func() {
+		defer close(resultChan)
+		if err := c.current().limiter.wait(ctx, host); err != nil {
+			resultChan <- domain.PingResult{Host: domain.NetworkHost{Hostname: host}, Error: err, Timestamp: time.Now()}
+			return
+		}
+		c.executePing(ctx, host, opts, resultChan)
+	}()
+
+	return resultChan, nil
+}
+
+var (
+	pingHeaderPattern  = regexp.MustCompile(`^PING \S+? ?\(([^) ]+)`)
+	pingReplyPattern   = regexp.MustCompile(`^(\d+) bytes from .*icmp_seq=(\d+) ttl=(\d+) time=([\d.]+) ms`)
+	pingMissingPattern = regexp.MustCompile(`^no answer yet for icmp_seq=(\d+)`)
+)
+
+// executePing runs iputils ping remotely and converts its output. Probes the
+// remote ping never answered are reported as timeouts.
+func (c *SSHClient) executePing(ctx context.Context, host string, opts domain.PingOptions, resultChan chan<- domain.PingResult) {
+	c.logger.Info("Starting ping via SSH", "host", host, "via", c.target, "count", opts.Count)
+
+	target := domain.NetworkHost{Hostname: host}
+	timeout := func(sequence int) domain.PingResult {
+		return domain.PingResult{
+			Host:       target,
+			Sequence:   sequence,
+			PacketSize: opts.PacketSize,
+			Timestamp:  time.Now(),
+			Error:      fmt.Errorf("request timeout for icmp_seq %d", sequence),
+		}
+	}
+
+	reported := make(map[int]bool)
+	err := c.run(ctx, pingCommand(host, opts), func(line string) {
+		if match := pingHeaderPattern.FindStringSubmatch(line); match != nil {
+			target.IPAddress = net.ParseIP(match[1])
+			return
+		}
+		if match := pingMissingPattern.FindStringSubmatch(line); match != nil {
+			sequence, _ := strconv.Atoi(match[1])
+			if !reported[sequence] {
+				reported[sequence] = true
+				resultChan <- timeout(sequence)
+			}
+			return
+		}
+		match := pingReplyPattern.FindStringSubmatch(line)
+		if match == nil {
+			return
+		}
+		size, _ := strconv.Atoi(match[1])
+		sequence, _ := strconv.Atoi(match[2])
+		ttl, _ := strconv.Atoi(match[3])
+		ms, _ := strconv.ParseFloat(match[4], 64)
+		if reported[sequence] {
+			return
+		}
+		reported[sequence] = true
+		resultChan <- domain.PingResult{
+			Host:       target,
+			Sequence:   sequence,
+			RTT:        time.Duration(ms * float64(time.Millisecond)),
+			TTL:        ttl,
+			PacketSize: size,
+			Timestamp:  time.Now(),
+		}
+	})
+	if ctx.Err() != nil {
+		c.logger.Info("Ping operation cancelled", "host", host)
+		return
+	}
+
+	// ping exits non-zero when replies were lost, which the missing probes
+	// below already report; without any output the command itself failed
+	if err != nil && len(reported) == 0 && target.IPAddress == nil {
+		c.logger.Error("Ping via SSH failed", "host", host, "via", c.target, "error", err)
+		resultChan <- domain.PingResult{Host: target, Sequence: 1, Timestamp: time.Now(), Error: err}
+		return
+	}
+	for sequence := 1; sequence <= opts.Count; sequence++ {
+		if !reported[sequence] {
+			resultChan <- timeout(sequence)
+		}
+	}
+}
+
+// pingCommand builds the remote ping command line
+func pingCommand(host string, opts domain.PingOptions) string {
+	args := []string{"ping", "-n", "-O", "-c", strconv.Itoa(opts.Count)}
+	if opts.Interval > 0 {
+		args = append(args, "-i", strconv.FormatFloat(opts.Interval.Seconds(), 'f', -1, 64))
+	}
+	if opts.Timeout > 0 {
+		args = append(args, "-W", strconv.Itoa(wholeSeconds(opts.Timeout)))
+	}
+	if opts.PacketSize > 0 {
+		args = append(args, "-s", strconv.Itoa(opts.PacketSize))
+	}
+	if opts.TTL > 0 {
+		args = append(args, "-t", strconv.Itoa(opts.TTL))
+	}
+	if opts.IPv6 {
+		args = append(args, "-6")
+	}
+	return shellJoin(append(args, host))
+}
+
+// Traceroute runs traceroute on the remote host
+func (c *SSHClient) Traceroute(ctx context.Context, host string, opts domain.TraceOptions) (<-chan domain.TraceHop, error) {
+	if err := c.validateRemoteHost(host); err != nil {
+		return nil, &domain.NetTraceError{
+			Type:      domain.ErrorTypeValidation,
+			Message:   "invalid host for traceroute operation",
+			Cause:     err,
+			Context:   map[string]interface{}{"host": host, "via": c.target},
+			Timestamp: time.Now(),
+			Code:      "TRACE_INVALID_HOST",
+		}
+	}
+
+	resultChan := make(chan domain.TraceHop, opts.MaxHops)
+
+	// The remote host sends the probes, so the stream takes no local
+	// concurrency slot
+	go func() {
+		defer close(resultChan)
+		if err := c.current().limiter.wait(ctx, host); err != nil {
+			resultChan <- domain.TraceHop{Host: domain.NetworkHost{Hostname: host}, Error: err, Timestamp: time.Now()}
+			return
+		}
+		c.executeTraceroute(ctx, host, opts, resultChan)
+	}()
+
+	return resultChan, nil
+}
+
+var traceHopPattern = regexp.MustCompile(`^\s*(\d+)\s+(.*)$`)
+
+// executeTraceroute runs traceroute remotely and streams each hop line
+func (c *SSHClient) executeTraceroute(ctx context.Context, host string, opts domain.TraceOptions, resultChan chan<- domain.TraceHop) {
+	if opts.Protocol == "" {
+		opts.Protocol = domain.TraceProtocolICMP
+	}
+	if opts.Port == 0 {
+		opts.Port = domain.DefaultTracePort(opts.Protocol)
+	}
+
+	c.logger.Info("Starting traceroute via SSH", "host", host, "via", c.target, "max_hops", opts.MaxHops, "protocol", opts.Protocol)
+
+	err := c.run(ctx, tracerouteCommand(host, opts), func(line string) {
+		if match := traceHopPattern.FindStringSubmatch(line); match != nil {
+			number, _ := strconv.Atoi(match[1])
+			resultChan <- parseTraceHop(number, match[2])
+		}
+	})
+	if err != nil && ctx.Err() == nil {
+		c.logger.Error("Traceroute via SSH failed", "host", host, "via", c.target, "error", err)
+	}
+}
+
+// tracerouteCommand builds the remote traceroute command line
+func tracerouteCommand(host string, opts domain.TraceOptions) string {
+	args := []string{"traceroute", "-n"}
+	if opts.MaxHops > 0 {
+		args = append(args, "-m", strconv.Itoa(opts.MaxHops))
+	}
+	if opts.Queries > 0 {
+		args = append(args, "-q", strconv.Itoa(opts.Queries))
+	}
+	if opts.Timeout > 0 {
+		args = append(args, "-w", strconv.Itoa(wholeSeconds(opts.Timeout)))
+	}
+	switch opts.Protocol {
+	case domain.TraceProtocolICMP:
+		args = append(args, "-I")
+	case domain.TraceProtocolTCP:
+		args = append(args, "-T", "-p", strconv.Itoa(opts.Port))
+	case domain.TraceProtocolUDP:
+		args = append(args, "-U", "-p", strconv.Itoa(opts.Port))
+	}
+	if opts.IPv6 {
+		args = append(args, "-6")
+	}
+	args = append(args, host)
+	if opts.PacketSize > 0 {
+		args = append(args, strconv.Itoa(opts.PacketSize))
+	}
+	return shellJoin(args)
+}
+
+// parseTraceHop parses the probes of one traceroute hop line, such as
+// "10.0.0.1  0.512 ms  0.488 ms *"
+func parseTraceHop(number int, probes string) domain.TraceHop {
+	hop := domain.TraceHop{Number: number, Timestamp: time.Now()}
+	fields := strings.Fields(probes)
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		if ip := net.ParseIP(field); ip != nil {
+			if hop.Host.IPAddress == nil {
+				hop.Host.IPAddress = ip
+			}
+			continue
+		}
+		if i+1 < len(fields) && fields[i+1] == "ms" {
+			if ms, err := strconv.ParseFloat(field, 64); err == nil {
+				hop.RTT = append(hop.RTT, time.Duration(ms*float64(time.Millisecond)))
+			}
+			i++
+		}
+	}
+	hop.Timeout = len(hop.RTT) == 0
+	return hop
+}
+
+// DNSLookup resolves domainName with dig on the remote host, so the remote
+// resolver configuration applies
+func (c *SSHClient) DNSLookup(ctx context.Context, domainName string, recordType domain.DNSRecordType) (domain.DNSResult, error) {
+	state := c.current()
+	if err := c.validateRemoteDomain(domainName); err != nil {
+		return domain.DNSResult{}, &domain.NetTraceError{
+			Type:      domain.ErrorTypeValidation,
+			Message:   "invalid domain for DNS lookup",
+			Cause:     err,
+			Context:   map[string]interface{}{"domain": domainName, "record_type": recordType, "via": c.target},
+			Timestamp: time.Now(),
+			Code:      "DNS_INVALID_DOMAIN",
+		}
+	}
+	if !isSupportedDNSRecordType(recordType) {
+		return domain.DNSResult{}, fmt.Errorf("unsupported DNS record type: %v", recordType)
+	}
+
+	key := dnsCacheKey(ctx, "ssh:"+c.target, domainName, recordType)
+	if cached, ok := state.cache.lookupDNS(ctx, key); ok {
+		return cached, nil
+	}
+
+	release, err := state.limiter.acquire(ctx, domainName)
+	if err != nil {
+		return domain.DNSResult{}, err
+	}
+	defer release()
+
+	c.logger.Info("Starting DNS lookup via SSH", "domain", domainName, "record_type", recordType, "via", c.target)
+
+	result := domain.DNSResult{Query: domainName, RecordType: recordType}
+	start := time.Now()
+	err = c.run(ctx, digCommand(domainName, recordType, state.config.Timeout), func(line string) {
+		parseDigLine(line, &result)
+	})
+	if result.ResponseTime == 0 {
+		result.ResponseTime = time.Since(start)
+	}
+	if err != nil {
+		return domain.DNSResult{}, &domain.NetTraceError{
+			Type:      domain.ErrorTypeNetwork,
+			Message:   "DNS lookup via SSH failed",
+			Cause:     err,
+			Context:   map[string]interface{}{"domain": domainName, "record_type": recordType, "via": c.target},
+			Timestamp: time.Now(),
+			Code:      "DNS_LOOKUP_FAILED",
+		}
+	}
+	result.Server += " via " + c.target
+	state.cache.storeDNS(key, result)
+
+	return result, nil
+}
+
+// digCommand builds the remote dig command line
+func digCommand(domainName string, recordType domain.DNSRecordType, timeout time.Duration) string {
+	args := []string{"dig", "+noall", "+answer", "+stats", "+tries=1"}
+	if timeout > 0 {
+		args = append(args, "+time="+strconv.Itoa(wholeSeconds(timeout)))
+	}
+	return shellJoin(append(args, domainName, digRecordTypes[recordType]))
+}
+
+// digRecordTypes maps record types to their names in dig queries and output
+var digRecordTypes = map[domain.DNSRecordType]string{
+	domain.DNSRecordTypeA:     "A",
+	domain.DNSRecordTypeAAAA:  "AAAA",
+	domain.DNSRecordTypeMX:    "MX",
+	domain.DNSRecordTypeTXT:   "TXT",
+	domain.DNSRecordTypeCNAME: "CNAME",
+	domain.DNSRecordTypeNS:    "NS",
+}
+
+var (
+	digServerPattern = regexp.MustCompile(`^;; SERVER: ([^#\s]+)`)
+	digTimePattern   = regexp.MustCompile(`^;; Query time: (\d+) msec`)
+	// name ttl class type data
+	digAnswerPattern = regexp.MustCompile(`^(\S+)\s+(\d+)\s+\S+\s+(\S+)\s+(.*)$`)
+)
+
+// parseDigLine adds one line of dig output to result: an answer record or
+// one of the statistics naming the server and response time
+func parseDigLine(line string, result *domain.DNSResult) {
+	if match := digServerPattern.FindStringSubmatch(line); match != nil {
+		result.Server = match[1]
+		return
+	}
+	if match := digTimePattern.FindStringSubmatch(line); match != nil {
+		ms, _ := strconv.Atoi(match[1])
+		result.ResponseTime = time.Duration(ms) * time.Millisecond
+		return
+	}
+	if strings.HasPrefix(line, ";") {
+		return
+	}
+
+	match := digAnswerPattern.FindStringSubmatch(line)
+	if match == nil {
+		return
+	}
+	ttl, err := strconv.ParseUint(match[2], 10, 32)
+	if err != nil {
+		return
+	}
+	record := domain.DNSRecord{Name: strings.TrimSuffix(match[1], "."), TTL: uint32(ttl)}
+	found := false
+	for recordType, name := range digRecordTypes {
+		if strings.EqualFold(match[3], name) {
+			record.Type, found = recordType, true
+		}
+	}
+	if !found {
+		return
+	}
+
+	data := strings.TrimSpace(match[4])
+	switch record.Type {
+	case domain.DNSRecordTypeMX:
+		priority, host, _ := strings.Cut(data, " ")
+		record.Priority, _ = strconv.Atoi(priority)
+		record.Value = strings.TrimSpace(host)
+	case domain.DNSRecordTypeTXT:
+		record.Value = unquoteDigStrings(data)
+	default:
+		record.Value = data
+	}
+	result.Records = append(result.Records, record)
+}
+
+// validateRemoteHost validates host like the local client and also rejects
+// values the remote command would take for an option
+func (c *SSHClient) validateRemoteHost(host string) error {
+	if err := c.validateHost(host); err != nil {
+		return err
+	}
+	if strings.HasPrefix(host, "-") {
+		return fmt.Errorf("host cannot start with '-'")
+	}
+	return nil
+}
+
+// validateRemoteDomain is validateRemoteHost for DNS names, which dig
+// would also take for a query option or server when starting with + or @
+func (c *SSHClient) validateRemoteDomain(domainName string) error {
+	if err := c.validateDomain(domainName); err != nil {
+		return err
+	}
+	if strings.ContainsAny(domainName[:1], "-+@") {
+		return fmt.Errorf("domain cannot start with '%c'", domainName[0])
+	}
+	return nil
+}
+
+// unquoteDigStrings joins the quoted character strings of a TXT record as
+// dig prints them, such as "v=spf1 " "-all"
+func unquoteDigStrings(data string) string {
+	var text strings.Builder
+	quoted, escaped := false, false
+	for _, r := range data {
+		switch {
+		case escaped:
+			text.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case quoted:
+			text.WriteRune(r)
+		}
+	}
+	return text.String()
+}
+
+// runSSH runs command on the target with the system ssh client
+func (c *SSHClient) runSSH(ctx context.Context, command string, line func(string)) error {
+	args := []string{"-o", "BatchMode=yes", "-o", "ConnectTimeout=10"}
+	if c.port > 0 {
+		args = append(args, "-p", strconv.Itoa(c.port))
+	}
+	args = append(args, "--", c.target, command)
+
+	cmd := exec.CommandContext(ctx, "ssh", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start ssh: %w", err)
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		line(scanner.Text())
+	}
+	io.Copy(io.Discard, stdout)
+
+	if err := cmd.Wait(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return fmt.Errorf("%s: %s", c.target, message)
+		}
+		return fmt.Errorf("%s: %w", c.target, err)
+	}
+	return nil
+}
+
+// shellJoin quotes args for the remote shell
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_.:+=/") == "" {
+			quoted[i] = arg
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
+// wholeSeconds rounds d up to whole seconds, at least one, for tools that
+// take timeouts in seconds
+func wholeSeconds(d time.Duration) int {
+	seconds := int(math.Ceil(d.Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
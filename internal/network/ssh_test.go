@@ -0,0 +1,207 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// newSSHTestClient returns a client whose remote commands print output and
+// fail with err, recording the command lines run
+func newSSHTestClient(t *testing.T, output string, err error) (*SSHClient, *[]string) {
+	local := NewClient(&domain.NetworkConfig{Timeout: 3 * time.Second, RetryAttempts: 1}, &mockErrorHandler{}, &mockLogger{})
+	client, parseErr := NewSSHClient(local, "ops@bastion.example.com:2222")
+	if parseErr != nil {
+		t.Fatalf("NewSSHClient failed: %v", parseErr)
+	}
+
+	var commands []string
+	client.run = func(ctx context.Context, command string, line func(string)) error {
+		commands = append(commands, command)
+		for _, text := range strings.Split(output, "\n") {
+			line(text)
+		}
+		return err
+	}
+	return client, &commands
+}
+
+func TestParseSSHTarget(t *testing.T) {
+	tests := []struct {
+		value       string
+		destination string
+		port        int
+	}{
+		{"bastion", "bastion", 0},
+		{"ops@bastion:2222", "ops@bastion", 2222},
+		{"ops@[2001:db8::1]:22", "ops@2001:db8::1", 22},
+		{" root@10.0.0.1 ", "root@10.0.0.1", 0},
+	}
+	for _, tt := range tests {
+		destination, port, err := ParseSSHTarget(tt.value)
+		if err != nil {
+			t.Errorf("ParseSSHTarget(%q) failed: %v", tt.value, err)
+			continue
+		}
+		if destination != tt.destination || port != tt.port {
+			t.Errorf("ParseSSHTarget(%q) = %q, %d; want %q, %d", tt.value, destination, port, tt.destination, tt.port)
+		}
+	}
+
+	for _, invalid := range []string{"", "@bastion", "ops@", "bastion:0", "-oProxyCommand=x", "-oProxyCommand=x@bastion"} {
+		if _, _, err := ParseSSHTarget(invalid); err == nil {
+			t.Errorf("ParseSSHTarget(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestSSHClient_Ping(t *testing.T) {
+	output := `PING example.com (93.184.216.34) 56(84) bytes of data.
+64 bytes from 93.184.216.34: icmp_seq=1 ttl=56 time=11.4 ms
+no answer yet for icmp_seq=2
+64 bytes from 93.184.216.34: icmp_seq=3 ttl=56 time=12.0 ms
+
+--- example.com ping statistics ---
+4 packets transmitted, 2 received, 50% packet loss, time 3004ms`
+	client, commands := newSSHTestClient(t, output, errors.New("exit status 1"))
+
+	results, err := client.Ping(context.Background(), "example.com", domain.PingOptions{Count: 4, Interval: time.Second, Timeout: 1500 * time.Millisecond, PacketSize: 56})
+	if err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	var replies []domain.PingResult
+	for result := range results {
+		replies = append(replies, result)
+	}
+
+	expected := "ping -n -O -c 4 -i 1 -W 2 -s 56 example.com"
+	if len(*commands) != 1 || (*commands)[0] != expected {
+		t.Errorf("Expected command %q, got %v", expected, *commands)
+	}
+	if len(replies) != 4 {
+		t.Fatalf("Expected 4 results, got %d", len(replies))
+	}
+	if replies[0].Sequence != 1 || replies[0].TTL != 56 || replies[0].RTT != 11400*time.Microsecond || replies[0].Error != nil {
+		t.Errorf("Unexpected first reply: %+v", replies[0])
+	}
+	if replies[0].Host.IPAddress.String() != "93.184.216.34" {
+		t.Errorf("Expected resolved address, got %v", replies[0].Host.IPAddress)
+	}
+	if replies[1].Sequence != 2 || replies[1].Error == nil {
+		t.Errorf("Expected timeout for sequence 2, got %+v", replies[1])
+	}
+	if replies[3].Sequence != 4 || replies[3].Error == nil {
+		t.Errorf("Expected timeout for unanswered sequence 4, got %+v", replies[3])
+	}
+}
+
+func TestSSHClient_PingCommandFailure(t *testing.T) {
+	client, _ := newSSHTestClient(t, "", errors.New("ops@bastion.example.com: ping: unknown.invalid: Name or service not known"))
+
+	results, err := client.Ping(context.Background(), "unknown.invalid", domain.PingOptions{Count: 3})
+	if err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	var replies []domain.PingResult
+	for result := range results {
+		replies = append(replies, result)
+	}
+	if len(replies) != 1 || replies[0].Error == nil || !strings.Contains(replies[0].Error.Error(), "Name or service not known") {
+		t.Errorf("Expected a single error result, got %+v", replies)
+	}
+}
+
+func TestSSHClient_RejectsOptionLikeHosts(t *testing.T) {
+	client, commands := newSSHTestClient(t, "", nil)
+
+	if _, err := client.Ping(context.Background(), "-f", domain.PingOptions{Count: 1}); err == nil {
+		t.Error("Expected ping of an option-like host to fail")
+	}
+	if _, err := client.DNSLookup(context.Background(), "@evil.example.com", domain.DNSRecordTypeA); err == nil {
+		t.Error("Expected lookup of a server-like name to fail")
+	}
+	if len(*commands) != 0 {
+		t.Errorf("Expected no remote commands, got %v", *commands)
+	}
+}
+
+func TestSSHClient_Traceroute(t *testing.T) {
+	output := `traceroute to example.com (93.184.216.34), 30 hops max, 60 byte packets
+ 1  10.0.0.1  0.512 ms  0.488 ms  0.470 ms
+ 2  * * *
+ 3  93.184.216.34  11.902 ms *  12.110 ms`
+	client, commands := newSSHTestClient(t, output, nil)
+
+	hops, err := client.Traceroute(context.Background(), "example.com", domain.TraceOptions{MaxHops: 30, Queries: 3, Timeout: 2 * time.Second, Protocol: domain.TraceProtocolTCP})
+	if err != nil {
+		t.Fatalf("Traceroute failed: %v", err)
+	}
+	var got []domain.TraceHop
+	for hop := range hops {
+		got = append(got, hop)
+	}
+
+	expected := "traceroute -n -m 30 -q 3 -w 2 -T -p 80 example.com"
+	if len(*commands) != 1 || (*commands)[0] != expected {
+		t.Errorf("Expected command %q, got %v", expected, *commands)
+	}
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 hops, got %d", len(got))
+	}
+	if got[0].Host.IPAddress.String() != "10.0.0.1" || len(got[0].RTT) != 3 || got[0].Timeout {
+		t.Errorf("Unexpected first hop: %+v", got[0])
+	}
+	if !got[1].Timeout || got[1].Host.IPAddress != nil {
+		t.Errorf("Expected timed out second hop, got %+v", got[1])
+	}
+	if got[2].Number != 3 || len(got[2].RTT) != 2 {
+		t.Errorf("Expected two replies on hop 3, got %+v", got[2])
+	}
+}
+
+func TestSSHClient_DNSLookup(t *testing.T) {
+	output := `example.com.		3600	IN	MX	10 mail.example.com.
+example.com.		3600	IN	TXT	"v=spf1 " "-all"
+;; Query time: 23 msec
+;; SERVER: 10.0.0.53#53(10.0.0.53) (UDP)
+;; WHEN: Thu Oct 15 10:00:00 UTC 2026`
+	client, commands := newSSHTestClient(t, output, nil)
+
+	result, err := client.DNSLookup(context.Background(), "example.com", domain.DNSRecordTypeMX)
+	if err != nil {
+		t.Fatalf("DNSLookup failed: %v", err)
+	}
+
+	expected := "dig +noall +answer +stats +tries=1 +time=3 example.com MX"
+	if len(*commands) != 1 || (*commands)[0] != expected {
+		t.Errorf("Expected command %q, got %v", expected, *commands)
+	}
+	if len(result.Records) != 2 {
+		t.Fatalf("Expected 2 records, got %+v", result.Records)
+	}
+	mx := result.Records[0]
+	if mx.Name != "example.com" || mx.Type != domain.DNSRecordTypeMX || mx.Priority != 10 || mx.Value != "mail.example.com." || mx.TTL != 3600 {
+		t.Errorf("Unexpected MX record: %+v", mx)
+	}
+	if result.Records[1].Value != "v=spf1 -all" {
+		t.Errorf("Expected joined TXT value, got %q", result.Records[1].Value)
+	}
+	if result.Server != "10.0.0.53 via ops@bastion.example.com" {
+		t.Errorf("Unexpected server %q", result.Server)
+	}
+	if result.ResponseTime != 23*time.Millisecond {
+		t.Errorf("Expected dig query time, got %v", result.ResponseTime)
+	}
+}
+
+func TestShellJoin(t *testing.T) {
+	got := shellJoin([]string{"dig", "+time=2", "it's here", "a;b", ""})
+	expected := `dig +time=2 'it'\''s here' 'a;b' ''`
+	if got != expected {
+		t.Errorf("shellJoin = %s, want %s", got, expected)
+	}
+}
@@ -0,0 +1,213 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// notifyTimeout bounds the delivery of one alert to one notifier
+const notifyTimeout = 10 * time.Second
+
+// alertKey identifies the state of one rule for one probe target
+type alertKey struct {
+	rule   Rule
+	tool   string
+	target string
+}
+
+// Monitor evaluates probe outcomes against the configured thresholds and
+// notifies when a rule starts or stops firing. A threshold of zero disables
+// its rule.
+type Monitor struct {
+	config    domain.NotifyConfig
+	notifiers []Notifier
+	logger    domain.Logger
+	now       func() time.Time
+
+	mu       sync.Mutex
+	failures map[alertKey]int
+	firing   map[alertKey]bool
+}
+
+// NewMonitor creates a monitor notifying the webhooks in config
+func NewMonitor(config domain.NotifyConfig, logger domain.Logger) (*Monitor, error) {
+	m := &Monitor{
+		config:   config,
+		logger:   logger,
+		now:      time.Now,
+		failures: make(map[alertKey]int),
+		firing:   make(map[alertKey]bool),
+	}
+
+	for _, url := range config.Webhooks {
+		notifier, err := NewWebhook(url, config.WebhookTemplate)
+		if err != nil {
+			return nil, err
+		}
+		m.notifiers = append(m.notifiers, notifier)
+	}
+	if config.SlackWebhook != "" {
+		notifier, err := NewSlack(config.SlackWebhook)
+		if err != nil {
+			return nil, err
+		}
+		m.notifiers = append(m.notifiers, notifier)
+	}
+	if config.DiscordWebhook != "" {
+		notifier, err := NewDiscord(config.DiscordWebhook)
+		if err != nil {
+			return nil, err
+		}
+		m.notifiers = append(m.notifiers, notifier)
+	}
+
+	return m, nil
+}
+
+// Enabled reports whether any notifier is configured
+func (m *Monitor) Enabled() bool {
+	return len(m.notifiers) > 0
+}
+
+// Observe evaluates the outcome of running tool against target and sends
+// the alerts whose state changed. It implements metrics.Observer.
+func (m *Monitor) Observe(tool, target string, result domain.Result, err error, duration time.Duration) {
+	m.mu.Lock()
+	var alerts []Alert
+	if err != nil || result == nil {
+		if err == nil {
+			err = fmt.Errorf("no result")
+		}
+		alerts = m.evaluateReachability(alerts, tool, target, err)
+	} else {
+		switch data := result.Data().(type) {
+		case []domain.PingResult:
+			alerts = m.evaluatePing(alerts, tool, target, data)
+		case domain.MultiPingResult:
+			for _, pinged := range data.Targets {
+				alerts = m.evaluatePing(alerts, tool, pinged.Host, pinged.Results)
+			}
+		case domain.SSLResult:
+			alerts = m.evaluateReachability(alerts, tool, target, nil)
+			alerts = m.evaluateCertificate(alerts, tool, target, data)
+		default:
+			alerts = m.evaluateReachability(alerts, tool, target, nil)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, alert := range alerts {
+		m.send(alert)
+	}
+}
+
+// evaluateReachability counts consecutive failures of target and reports it
+// down once HostDownAfter probes in a row failed
+func (m *Monitor) evaluateReachability(alerts []Alert, tool, target string, err error) []Alert {
+	if m.config.HostDownAfter <= 0 {
+		return alerts
+	}
+	key := alertKey{rule: RuleHostDown, tool: tool, target: target}
+	if err != nil {
+		m.failures[key]++
+	} else {
+		m.failures[key] = 0
+	}
+	failures := m.failures[key]
+
+	alert := Alert{Value: float64(failures), Threshold: float64(m.config.HostDownAfter)}
+	if err != nil {
+		alert.Message = fmt.Sprintf("host down after %d failed probes: %v", failures, err)
+	} else {
+		alert.Message = "host is reachable again"
+	}
+	return m.transition(alerts, key, failures >= m.config.HostDownAfter, alert)
+}
+
+// evaluatePing treats a run without any reply as a failed probe and checks
+// the packet loss of runs with replies
+func (m *Monitor) evaluatePing(alerts []Alert, tool, target string, results []domain.PingResult) []Alert {
+	if len(results) == 0 {
+		return alerts
+	}
+	lost := 0
+	var lastErr error
+	for _, result := range results {
+		if result.Error != nil {
+			lost++
+			lastErr = result.Error
+		}
+	}
+	if lost == len(results) {
+		return m.evaluateReachability(alerts, tool, target, lastErr)
+	}
+	alerts = m.evaluateReachability(alerts, tool, target, nil)
+
+	if m.config.PacketLossPercent <= 0 {
+		return alerts
+	}
+	loss := float64(lost) / float64(len(results)) * 100
+	alert := Alert{Value: loss, Threshold: m.config.PacketLossPercent}
+	breached := loss > m.config.PacketLossPercent
+	if breached {
+		alert.Message = fmt.Sprintf("packet loss %.1f%% above %.1f%%", loss, m.config.PacketLossPercent)
+	} else {
+		alert.Message = fmt.Sprintf("packet loss %.1f%% back under %.1f%%", loss, m.config.PacketLossPercent)
+	}
+	return m.transition(alerts, alertKey{rule: RulePacketLoss, tool: tool, target: target}, breached, alert)
+}
+
+// evaluateCertificate checks how many days remain until the certificate expires
+func (m *Monitor) evaluateCertificate(alerts []Alert, tool, target string, data domain.SSLResult) []Alert {
+	if m.config.CertExpiryDays <= 0 || data.Expiry.IsZero() {
+		return alerts
+	}
+	days := data.Expiry.Sub(m.now()).Hours() / 24
+	alert := Alert{Value: days, Threshold: float64(m.config.CertExpiryDays)}
+	breached := days < float64(m.config.CertExpiryDays)
+	switch {
+	case days < 0:
+		alert.Message = fmt.Sprintf("certificate expired on %s", data.Expiry.Format("2006-01-02"))
+	case breached:
+		alert.Message = fmt.Sprintf("certificate expires in %.1f days on %s", days, data.Expiry.Format("2006-01-02"))
+	default:
+		alert.Message = fmt.Sprintf("certificate renewed, expires in %.0f days", days)
+	}
+	return m.transition(alerts, alertKey{rule: RuleCertExpiring, tool: tool, target: target}, breached, alert)
+}
+
+// transition appends alert when the rule starts firing or resolves; a rule
+// that keeps its state sends nothing
+func (m *Monitor) transition(alerts []Alert, key alertKey, breached bool, alert Alert) []Alert {
+	if breached == m.firing[key] {
+		return alerts
+	}
+	if breached {
+		m.firing[key] = true
+		alert.State = StateFiring
+	} else {
+		delete(m.firing, key)
+		alert.State = StateResolved
+	}
+	alert.Rule = key.rule
+	alert.Tool = key.tool
+	alert.Target = key.target
+	alert.Time = m.now()
+	return append(alerts, alert)
+}
+
+// send delivers alert to every notifier, logging failed deliveries
+func (m *Monitor) send(alert Alert) {
+	m.logger.Info("Alert state changed", "rule", alert.Rule, "state", alert.State, "tool", alert.Tool, "target", alert.Target)
+	for _, notifier := range m.notifiers {
+		ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+		if err := notifier.Notify(ctx, alert); err != nil {
+			m.logger.Warn("Failed to send alert notification", "notifier", notifier.Name(), "error", err)
+		}
+		cancel()
+	}
+}
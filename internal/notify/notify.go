@@ -0,0 +1,59 @@
+// Package notify sends alerts raised by scheduled probes to webhooks, Slack
+// and Discord. A Monitor evaluates probe results against thresholds and
+// notifies once when a rule starts firing and once when it resolves.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Rule identifies the condition an alert reports
+type Rule string
+
+const (
+	RuleHostDown     Rule = "host_down"
+	RuleCertExpiring Rule = "cert_expiring"
+	RulePacketLoss   Rule = "packet_loss"
+)
+
+// State tells whether an alert started or stopped
+type State string
+
+const (
+	StateFiring   State = "firing"
+	StateResolved State = "resolved"
+)
+
+// Alert is a threshold breach, or its recovery, for one probe target
+type Alert struct {
+	Rule      Rule      `json:"rule"`
+	State     State     `json:"state"`
+	Tool      string    `json:"tool"`
+	Target    string    `json:"target"`
+	Message   string    `json:"message"`
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	Time      time.Time `json:"time"`
+}
+
+// Firing reports whether the alert reports a breach rather than a recovery
+func (a Alert) Firing() bool {
+	return a.State == StateFiring
+}
+
+// Summary returns a one-line description of the alert for chat messages
+func (a Alert) Summary() string {
+	label := "FIRING"
+	if !a.Firing() {
+		label = "RESOLVED"
+	}
+	return fmt.Sprintf("[%s] %s %s: %s", label, a.Tool, a.Target, a.Message)
+}
+
+// Notifier delivers alerts to one destination
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, alert Alert) error
+}
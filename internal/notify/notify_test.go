@@ -0,0 +1,259 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testLogger implements domain.Logger and discards all output
+type testLogger struct{}
+
+func (testLogger) Debug(msg string, fields ...interface{}) {}
+func (testLogger) Info(msg string, fields ...interface{})  {}
+func (testLogger) Warn(msg string, fields ...interface{})  {}
+func (testLogger) Error(msg string, fields ...interface{}) {}
+func (testLogger) Fatal(msg string, fields ...interface{}) {}
+
+// recordingNotifier keeps the alerts it receives
+type recordingNotifier struct {
+	mu     sync.Mutex
+	alerts []Alert
+}
+
+func (n *recordingNotifier) Name() string { return "recording" }
+
+func (n *recordingNotifier) Notify(ctx context.Context, alert Alert) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+// newTestMonitor creates a monitor delivering to a recording notifier
+func newTestMonitor(t *testing.T, config domain.NotifyConfig) (*Monitor, *recordingNotifier) {
+	monitor, err := NewMonitor(config, testLogger{})
+	require.NoError(t, err)
+	recorder := &recordingNotifier{}
+	monitor.notifiers = []Notifier{recorder}
+	return monitor, recorder
+}
+
+// pingRun builds ping results where the first lost probes have no reply
+func pingRun(total, lost int) domain.Result {
+	results := make([]domain.PingResult, total)
+	for i := range results {
+		results[i] = domain.PingResult{Sequence: i, RTT: 10 * time.Millisecond}
+		if i < lost {
+			results[i].Error = errors.New("request timeout")
+		}
+	}
+	return domain.NewResult(results)
+}
+
+func TestMonitor_HostDown(t *testing.T) {
+	monitor, recorder := newTestMonitor(t, domain.NotifyConfig{HostDownAfter: 2})
+	failure := errors.New("connection refused")
+
+	monitor.Observe("ssl", "example.com", nil, failure, time.Second)
+	assert.Empty(t, recorder.alerts)
+
+	monitor.Observe("ssl", "example.com", nil, failure, time.Second)
+	monitor.Observe("ssl", "example.com", nil, failure, time.Second)
+	require.Len(t, recorder.alerts, 1)
+	assert.Equal(t, RuleHostDown, recorder.alerts[0].Rule)
+	assert.True(t, recorder.alerts[0].Firing())
+	assert.Contains(t, recorder.alerts[0].Message, "connection refused")
+
+	monitor.Observe("ssl", "example.com", domain.NewResult(domain.SSLResult{}), nil, time.Second)
+	require.Len(t, recorder.alerts, 2)
+	assert.Equal(t, StateResolved, recorder.alerts[1].State)
+	assert.Equal(t, "ssl", recorder.alerts[1].Tool)
+	assert.Equal(t, "example.com", recorder.alerts[1].Target)
+}
+
+func TestMonitor_PingWithoutRepliesIsDown(t *testing.T) {
+	monitor, recorder := newTestMonitor(t, domain.NotifyConfig{HostDownAfter: 1, PacketLossPercent: 20})
+
+	monitor.Observe("ping", "10.0.0.1", pingRun(4, 4), nil, time.Second)
+	require.Len(t, recorder.alerts, 1)
+	assert.Equal(t, RuleHostDown, recorder.alerts[0].Rule)
+}
+
+func TestMonitor_PacketLoss(t *testing.T) {
+	monitor, recorder := newTestMonitor(t, domain.NotifyConfig{PacketLossPercent: 20})
+
+	monitor.Observe("ping", "example.com", pingRun(4, 0), nil, time.Second)
+	assert.Empty(t, recorder.alerts)
+
+	monitor.Observe("ping", "example.com", pingRun(4, 2), nil, time.Second)
+	monitor.Observe("ping", "example.com", pingRun(4, 1), nil, time.Second)
+	require.Len(t, recorder.alerts, 1)
+	assert.Equal(t, RulePacketLoss, recorder.alerts[0].Rule)
+	assert.Equal(t, 50.0, recorder.alerts[0].Value)
+	assert.Equal(t, 20.0, recorder.alerts[0].Threshold)
+
+	monitor.Observe("ping", "example.com", pingRun(10, 1), nil, time.Second)
+	require.Len(t, recorder.alerts, 2)
+	assert.Equal(t, StateResolved, recorder.alerts[1].State)
+}
+
+func TestMonitor_MultiPingTargets(t *testing.T) {
+	monitor, recorder := newTestMonitor(t, domain.NotifyConfig{PacketLossPercent: 20})
+
+	result := domain.NewResult(domain.MultiPingResult{Targets: []domain.PingTargetResult{
+		{Host: "a.example", Results: pingRun(4, 0).Data().([]domain.PingResult)},
+		{Host: "b.example", Results: pingRun(4, 3).Data().([]domain.PingResult)},
+	}})
+	monitor.Observe("ping", "a.example, b.example", result, nil, time.Second)
+
+	require.Len(t, recorder.alerts, 1)
+	assert.Equal(t, "b.example", recorder.alerts[0].Target)
+}
+
+func TestMonitor_CertExpiring(t *testing.T) {
+	monitor, recorder := newTestMonitor(t, domain.NotifyConfig{CertExpiryDays: 14})
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	monitor.now = func() time.Time { return now }
+
+	monitor.Observe("ssl", "example.com", domain.NewResult(domain.SSLResult{Expiry: now.AddDate(0, 0, 30)}), nil, time.Second)
+	assert.Empty(t, recorder.alerts)
+
+	monitor.Observe("ssl", "example.com", domain.NewResult(domain.SSLResult{Expiry: now.AddDate(0, 0, 5)}), nil, time.Second)
+	require.Len(t, recorder.alerts, 1)
+	assert.Equal(t, RuleCertExpiring, recorder.alerts[0].Rule)
+	assert.Equal(t, 5.0, recorder.alerts[0].Value)
+	assert.Equal(t, now, recorder.alerts[0].Time)
+	assert.Contains(t, recorder.alerts[0].Message, "2024-06-06")
+
+	monitor.Observe("ssl", "example.com", domain.NewResult(domain.SSLResult{Expiry: now.AddDate(0, 3, 0)}), nil, time.Second)
+	require.Len(t, recorder.alerts, 2)
+	assert.Equal(t, StateResolved, recorder.alerts[1].State)
+}
+
+func TestMonitor_DisabledRules(t *testing.T) {
+	monitor, recorder := newTestMonitor(t, domain.NotifyConfig{})
+
+	monitor.Observe("ping", "example.com", nil, errors.New("unreachable"), time.Second)
+	monitor.Observe("ping", "example.com", pingRun(4, 3), nil, time.Second)
+	monitor.Observe("ssl", "example.com", domain.NewResult(domain.SSLResult{Expiry: time.Now()}), nil, time.Second)
+	assert.Empty(t, recorder.alerts)
+}
+
+func TestNewMonitor_Notifiers(t *testing.T) {
+	monitor, err := NewMonitor(domain.NotifyConfig{}, testLogger{})
+	require.NoError(t, err)
+	assert.False(t, monitor.Enabled())
+
+	monitor, err = NewMonitor(domain.NotifyConfig{
+		Webhooks:       []string{"https://hooks.example/a", "https://hooks.example/b"},
+		SlackWebhook:   "https://hooks.slack.com/services/x",
+		DiscordWebhook: "https://discord.com/api/webhooks/x",
+	}, testLogger{})
+	require.NoError(t, err)
+	require.True(t, monitor.Enabled())
+	var names []string
+	for _, notifier := range monitor.notifiers {
+		names = append(names, notifier.Name())
+	}
+	assert.Equal(t, []string{"webhook", "webhook", "slack", "discord"}, names)
+
+	_, err = NewMonitor(domain.NotifyConfig{Webhooks: []string{"https://hooks.example"}, WebhookTemplate: "{{.Missing"}, testLogger{})
+	assert.Error(t, err)
+}
+
+// captureServer records the body and content type of the last post
+func captureServer(t *testing.T) (*httptest.Server, func() (string, string)) {
+	var mu sync.Mutex
+	var body, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		body = string(data)
+		contentType = r.Header.Get("Content-Type")
+	}))
+	t.Cleanup(server.Close)
+	return server, func() (string, string) {
+		mu.Lock()
+		defer mu.Unlock()
+		return body, contentType
+	}
+}
+
+var testAlert = Alert{
+	Rule:      RulePacketLoss,
+	State:     StateFiring,
+	Tool:      "ping",
+	Target:    "example.com",
+	Message:   `packet loss 50.0% above "20.0%"`,
+	Value:     50,
+	Threshold: 20,
+	Time:      time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+}
+
+func TestWebhook_DefaultPayload(t *testing.T) {
+	server, last := captureServer(t)
+	notifier, err := NewWebhook(server.URL, "")
+	require.NoError(t, err)
+	require.NoError(t, notifier.Notify(context.Background(), testAlert))
+
+	body, contentType := last()
+	assert.Equal(t, "application/json", contentType)
+	var decoded Alert
+	require.NoError(t, json.Unmarshal([]byte(body), &decoded))
+	assert.Equal(t, testAlert, decoded)
+}
+
+func TestWebhook_CustomTemplate(t *testing.T) {
+	server, last := captureServer(t)
+	notifier, err := NewWebhook(server.URL, `{"severity": "{{if .Firing}}critical{{else}}ok{{end}}", "summary": {{json .Message}}, "host": "{{upper .Target}}"}`)
+	require.NoError(t, err)
+	require.NoError(t, notifier.Notify(context.Background(), testAlert))
+
+	body, _ := last()
+	var decoded map[string]string
+	require.NoError(t, json.Unmarshal([]byte(body), &decoded))
+	assert.Equal(t, map[string]string{"severity": "critical", "summary": testAlert.Message, "host": "EXAMPLE.COM"}, decoded)
+}
+
+func TestSlackAndDiscordPayloads(t *testing.T) {
+	server, last := captureServer(t)
+
+	slack, err := NewSlack(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, slack.Notify(context.Background(), testAlert))
+	body, _ := last()
+	assert.JSONEq(t, `{"text": "[FIRING] ping example.com: packet loss 50.0% above \"20.0%\""}`, body)
+
+	resolved := testAlert
+	resolved.State = StateResolved
+	discord, err := NewDiscord(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, discord.Notify(context.Background(), resolved))
+	body, _ = last()
+	assert.JSONEq(t, `{"content": "[RESOLVED] ping example.com: packet loss 50.0% above \"20.0%\""}`, body)
+}
+
+func TestWebhook_Rejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_payload", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	notifier, err := NewSlack(server.URL)
+	require.NoError(t, err)
+	err = notifier.Notify(context.Background(), testAlert)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid_payload")
+}
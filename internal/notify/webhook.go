@@ -0,0 +1,100 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Default payload templates. Generic webhooks receive the alert as JSON;
+// Slack and Discord receive the summary in the field their webhooks display.
+const (
+	DefaultWebhookTemplate = `{{json .}}`
+	SlackTemplate          = `{"text": {{json .Summary}}}`
+	DiscordTemplate        = `{"content": {{json .Summary}}}`
+)
+
+// templateFuncs are available to payload templates
+var templateFuncs = template.FuncMap{
+	"json": func(value interface{}) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+	"upper": strings.ToUpper,
+}
+
+// WebhookNotifier posts alerts rendered through a template to a URL
+type WebhookNotifier struct {
+	name     string
+	url      string
+	template *template.Template
+	client   *http.Client
+}
+
+// NewWebhook creates a generic webhook notifier. An empty payload template
+// posts the alert as JSON.
+func NewWebhook(url, payload string) (*WebhookNotifier, error) {
+	if payload == "" {
+		payload = DefaultWebhookTemplate
+	}
+	return newWebhook("webhook", url, payload)
+}
+
+// NewSlack creates a notifier for a Slack incoming webhook
+func NewSlack(url string) (*WebhookNotifier, error) {
+	return newWebhook("slack", url, SlackTemplate)
+}
+
+// NewDiscord creates a notifier for a Discord webhook
+func NewDiscord(url string) (*WebhookNotifier, error) {
+	return newWebhook("discord", url, DiscordTemplate)
+}
+
+func newWebhook(name, url, payload string) (*WebhookNotifier, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s payload template: %w", name, err)
+	}
+	return &WebhookNotifier{
+		name:     name,
+		url:      url,
+		template: tmpl,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Name returns the kind of webhook
+func (w *WebhookNotifier) Name() string {
+	return w.name
+}
+
+// Notify renders the payload for alert and posts it
+func (w *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	var body bytes.Buffer
+	if err := w.template.Execute(&body, alert); err != nil {
+		return fmt.Errorf("failed to render %s payload: %w", w.name, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, &body)
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", w.name, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post %s notification: %w", w.name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s rejected notification: %s %s", w.name, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
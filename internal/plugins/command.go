@@ -0,0 +1,236 @@
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// commandTimeout bounds a command whose configuration sets no timeout
+const commandTimeout = 60 * time.Second
+
+// maxCommandOutput bounds the stdout kept from a command
+const maxCommandOutput = 1 << 20
+
+// CommandTool is a diagnostic tool defined in the configuration that runs
+// an external command with arguments built from its parameters. The
+// command is run directly, not through a shell.
+type CommandTool struct {
+	config  domain.CommandToolConfig
+	args    []*template.Template
+	pattern *regexp.Regexp
+	hints   *domain.RenderHints
+}
+
+// NewCommandTool prepares the command tool config describes
+func NewCommandTool(config domain.CommandToolConfig) (*CommandTool, error) {
+	if config.Name == "" || config.Command == "" {
+		return nil, errors.New("command tools need a name and a command")
+	}
+	t := &CommandTool{config: config, hints: config.Hints}
+	for _, arg := range config.Args {
+		tmpl, err := template.New(config.Name).Option("missingkey=zero").Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("command tool %s: invalid argument %q: %w", config.Name, arg, err)
+		}
+		t.args = append(t.args, tmpl)
+	}
+
+	switch config.Parse {
+	case "", "text", "json":
+	case "regex":
+		pattern, err := regexp.Compile(config.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("command tool %s: invalid pattern: %w", config.Name, err)
+		}
+		var columns []string
+		for _, name := range pattern.SubexpNames() {
+			if name != "" {
+				columns = append(columns, name)
+			}
+		}
+		if len(columns) == 0 {
+			return nil, fmt.Errorf("command tool %s: pattern has no named groups", config.Name)
+		}
+		t.pattern = pattern
+		if t.hints == nil {
+			t.hints = &domain.RenderHints{Format: "table", Columns: columns}
+		}
+	default:
+		return nil, fmt.Errorf("command tool %s: unknown parse mode %q", config.Name, config.Parse)
+	}
+	return t, nil
+}
+
+// Name returns the configured tool name
+func (t *CommandTool) Name() string {
+	return t.config.Name
+}
+
+// Description returns the configured description, or the command line
+func (t *CommandTool) Description() string {
+	if t.config.Description != "" {
+		return t.config.Description
+	}
+	return strings.TrimSpace(t.config.Command + " " + strings.Join(t.config.Args, " "))
+}
+
+// Parameters returns the configured inputs
+func (t *CommandTool) Parameters() []domain.ParameterSpec {
+	return t.config.Parameters
+}
+
+// Path returns the command
+func (t *CommandTool) Path() string {
+	return t.config.Command
+}
+
+// Validate checks the required parameters and rejects values that the
+// command could take for options
+func (t *CommandTool) Validate(params domain.Parameters) error {
+	if err := validateRequired(t.Name(), t.config.Parameters, params); err != nil {
+		return err
+	}
+	for key, value := range executeArgs(params).Params {
+		if strings.HasPrefix(value, "-") {
+			return &domain.NetTraceError{
+				Type:      domain.ErrorTypeValidation,
+				Message:   fmt.Sprintf("%s cannot start with '-'", key),
+				Context:   map[string]interface{}{"tool": t.Name(), "parameter": key},
+				Timestamp: time.Now(),
+				Code:      "PLUGIN_PARAMETER_INVALID",
+			}
+		}
+	}
+	return nil
+}
+
+// Execute runs the command and parses its output into a PluginResult
+func (t *CommandTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	if err := t.Validate(params); err != nil {
+		return nil, err
+	}
+	args, err := t.render(params)
+	if err != nil {
+		return nil, failed(t.Name(), t.config.Command, err)
+	}
+
+	timeout := t.config.Timeout
+	if timeout <= 0 {
+		timeout = commandTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, t.config.Command, args...)
+	var stdout, stderr limitedBuffer
+	stdout.limit, stderr.limit = maxCommandOutput, 4096
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			err = fmt.Errorf("%w: %s", err, message)
+		}
+		return nil, failed(t.Name(), t.config.Command, err)
+	}
+
+	reply, err := t.parse(stdout.Bytes())
+	if err != nil {
+		return nil, failed(t.Name(), t.config.Command, err)
+	}
+	reply.Metadata = map[string]interface{}{"command": strings.Join(append([]string{t.config.Command}, args...), " ")}
+	return newResult(t.Name(), t.config.Command, reply, t.hints), nil
+}
+
+// Check tells whether the command can still be found
+func (t *CommandTool) Check(ctx context.Context) error {
+	_, err := exec.LookPath(t.config.Command)
+	return err
+}
+
+// GetModel returns nil; the TUI builds a form from Parameters
+func (t *CommandTool) GetModel() tea.Model {
+	return nil
+}
+
+// Close releases nothing; commands only run during a call
+func (t *CommandTool) Close() error {
+	return nil
+}
+
+// render expands the argument templates with the parameter values
+func (t *CommandTool) render(params domain.Parameters) ([]string, error) {
+	values := executeArgs(params).Params
+	for _, spec := range t.config.Parameters {
+		if values[spec.Key] == "" {
+			values[spec.Key] = spec.Default
+		}
+	}
+
+	var args []string
+	for _, tmpl := range t.args {
+		var arg strings.Builder
+		if err := tmpl.Execute(&arg, values); err != nil {
+			return nil, err
+		}
+		if arg.Len() > 0 {
+			args = append(args, arg.String())
+		}
+	}
+	return args, nil
+}
+
+// parse turns the output of the command into a reply
+func (t *CommandTool) parse(output []byte) (ExecuteReply, error) {
+	switch t.config.Parse {
+	case "json":
+		var data interface{}
+		if err := json.Unmarshal(output, &data); err != nil {
+			return ExecuteReply{}, fmt.Errorf("output is not JSON: %w", err)
+		}
+		return ExecuteReply{Data: data}, nil
+	case "regex":
+		var rows []interface{}
+		for _, match := range t.pattern.FindAllSubmatch(output, -1) {
+			row := make(map[string]interface{})
+			for i, name := range t.pattern.SubexpNames() {
+				if name != "" {
+					row[name] = string(match[i])
+				}
+			}
+			rows = append(rows, row)
+		}
+		return ExecuteReply{
+			Text: fmt.Sprintf("%d matches", len(rows)),
+			Data: rows,
+		}, nil
+	}
+	return ExecuteReply{Text: strings.TrimRight(string(output), "\n")}, nil
+}
+
+// limitedBuffer keeps the first limit bytes written to it and discards the
+// rest, so a chatty command cannot exhaust memory
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+// Write implements io.Writer, always reporting the full length written
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		b.Buffer.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
@@ -0,0 +1,141 @@
+package plugins
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+func TestCommandTool(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    domain.CommandToolConfig
+		wantText  string
+		wantData  interface{}
+		wantHints *domain.RenderHints
+	}{
+		{
+			name: "text",
+			config: domain.CommandToolConfig{
+				Command: "echo",
+				Args:    []string{"{{.host}}", "{{.flag}}", "port={{.port}}"},
+			},
+			wantText: "example.com port=443",
+		},
+		{
+			name: "json",
+			config: domain.CommandToolConfig{
+				Command: "echo",
+				Args:    []string{`{"host": "{{.host}}", "open": [22, 443]}`},
+				Parse:   "json",
+			},
+			wantData: map[string]interface{}{"host": "example.com", "open": []interface{}{22.0, 443.0}},
+		},
+		{
+			name: "regex",
+			config: domain.CommandToolConfig{
+				Command: "printf",
+				Args:    []string{`22/tcp open ssh\n443/tcp open https\n8080/tcp closed http\n`},
+				Parse:   "regex",
+				Pattern: `(?m)^(?P<port>\d+)/tcp\s+open\s+(?P<service>\S+)$`,
+			},
+			wantText: "2 matches",
+			wantData: []interface{}{
+				map[string]interface{}{"port": "22", "service": "ssh"},
+				map[string]interface{}{"port": "443", "service": "https"},
+			},
+			wantHints: &domain.RenderHints{Format: "table", Columns: []string{"port", "service"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Name = tt.name
+			tt.config.Parameters = []domain.ParameterSpec{{Key: "host", Required: true}, {Key: "port", Default: "443"}}
+			tool, err := NewCommandTool(tt.config)
+			if err != nil {
+				t.Fatalf("NewCommandTool() error = %v", err)
+			}
+
+			params := domain.NewParameters()
+			params.Set("host", "example.com")
+			result, err := tool.Execute(context.Background(), params)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			data := result.Data().(domain.PluginResult)
+			if data.Text != tt.wantText {
+				t.Errorf("Text = %q, want %q", data.Text, tt.wantText)
+			}
+			if !reflect.DeepEqual(data.Data, tt.wantData) {
+				t.Errorf("Data = %#v, want %#v", data.Data, tt.wantData)
+			}
+			if !reflect.DeepEqual(data.Hints, tt.wantHints) {
+				t.Errorf("Hints = %+v, want %+v", data.Hints, tt.wantHints)
+			}
+		})
+	}
+}
+
+func TestCommandToolErrors(t *testing.T) {
+	if _, err := NewCommandTool(domain.CommandToolConfig{Name: "nmap", Command: "nmap", Parse: "regex", Pattern: `(\d+)/tcp`}); err == nil {
+		t.Error("Expected an error for a pattern without named groups")
+	}
+	if _, err := NewCommandTool(domain.CommandToolConfig{Name: "dig", Command: "dig", Args: []string{"{{.name"}}); err == nil {
+		t.Error("Expected an error for an invalid argument template")
+	}
+
+	tool, err := NewCommandTool(domain.CommandToolConfig{
+		Name:       "fails",
+		Command:    "sh",
+		Args:       []string{"-c", "echo connection refused >&2; exit 2", "{{.host}}"},
+		Parameters: []domain.ParameterSpec{{Key: "host", Required: true}},
+	})
+	if err != nil {
+		t.Fatalf("NewCommandTool() error = %v", err)
+	}
+
+	params := domain.NewParameters()
+	if err := tool.Validate(params); err == nil {
+		t.Error("Expected an error for a missing host")
+	}
+	params.Set("host", "-oN/tmp/scan")
+	if err := tool.Validate(params); err == nil || !strings.Contains(err.Error(), "cannot start with '-'") {
+		t.Errorf("Validate() error = %v, want option injection rejected", err)
+	}
+
+	params.Set("host", "example.com")
+	_, err = tool.Execute(context.Background(), params)
+	if err == nil || !strings.Contains(err.(*domain.NetTraceError).Cause.Error(), "exit status 2: connection refused") {
+		t.Errorf("Execute() error = %v", err)
+	}
+
+	slow, _ := NewCommandTool(domain.CommandToolConfig{Name: "slow", Command: "sleep", Args: []string{"5"}, Timeout: 50 * time.Millisecond})
+	start := time.Now()
+	if _, err := slow.Execute(context.Background(), domain.NewParameters()); err != context.DeadlineExceeded {
+		t.Errorf("Execute() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Execute() took %v despite the timeout", elapsed)
+	}
+}
+
+func TestLoadCommandTools(t *testing.T) {
+	tools, errs := Load(domain.PluginConfig{
+		DisabledPlugins: []string{"off"},
+		Commands: []domain.CommandToolConfig{
+			{Name: "hello", Command: "echo", Args: []string{"hello"}},
+			{Name: "off", Command: "echo"},
+			{Name: "broken", Command: "echo", Parse: "xml"},
+		},
+	}, nil, nil, nil)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), `unknown parse mode "xml"`) {
+		t.Errorf("Load() errors = %v", errs)
+	}
+	if len(tools) != 1 || tools[0].Name() != "hello" || tools[0].Description() != "echo hello" {
+		t.Fatalf("Load() tools = %v", tools)
+	}
+}
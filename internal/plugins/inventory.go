@@ -0,0 +1,180 @@
+package plugins
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/policy"
+)
+
+// Inventory records every plugin found at startup: the tools that loaded,
+// the plugins that were disabled and why the others failed. It implements
+// domain.PluginReporter for the plugin manager screen.
+type Inventory struct {
+	mu       sync.Mutex
+	statuses []domain.PluginStatus
+	tools    []Plugin
+	loaded   map[Plugin]int
+	errs     []error
+}
+
+// LoadInventory starts the plugins found in the configured paths and the
+// command tools of the configuration like Load, and records the outcome
+// for each of them
+func LoadInventory(config domain.PluginConfig, client domain.NetworkClient, targets *policy.TargetPolicy, logger domain.Logger) *Inventory {
+	inventory := &Inventory{loaded: make(map[Plugin]int)}
+
+	found := Discover(config.PluginPaths)
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := found[name]
+		status := domain.PluginStatus{Name: name, Kind: pluginKind(path), Path: path, Enabled: Enabled(config, name)}
+		if !status.Enabled {
+			inventory.statuses = append(inventory.statuses, status)
+			continue
+		}
+		var tool Plugin
+		var err error
+		switch status.Kind {
+		case domain.PluginKindWASM:
+			tool, err = StartWASM(path, settings(config, name), logger)
+		case domain.PluginKindScript:
+			tool, err = StartScript(path, name, settings(config, name), client, targets, logger)
+		default:
+			tool, err = Start(path, settings(config, name), logger)
+		}
+		if err == nil && logger != nil {
+			logger.Info("Loaded plugin", "plugin", name, "tool", tool.Name(), "path", tool.Path())
+		}
+		inventory.add(status, tool, err)
+	}
+
+	for _, command := range config.Commands {
+		status := domain.PluginStatus{Name: command.Name, Kind: domain.PluginKindCommand, Path: command.Command, Enabled: Enabled(config, command.Name)}
+		if !status.Enabled {
+			inventory.statuses = append(inventory.statuses, status)
+			continue
+		}
+		tool, err := NewCommandTool(command)
+		inventory.add(status, tool, err)
+	}
+	return inventory
+}
+
+// add records the outcome of loading the plugin status describes
+func (i *Inventory) add(status domain.PluginStatus, tool Plugin, err error) {
+	if err != nil {
+		status.Error = err.Error()
+		i.errs = append(i.errs, err)
+	} else {
+		status.Loaded = true
+		status.Tool = tool.Name()
+		if versioned, ok := tool.(interface{ Version() string }); ok {
+			status.Version = versioned.Version()
+		}
+		i.loaded[tool] = len(i.statuses)
+		i.tools = append(i.tools, tool)
+	}
+	i.statuses = append(i.statuses, status)
+}
+
+// Tools returns the tools of the plugins that loaded
+func (i *Inventory) Tools() []Plugin {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return append([]Plugin(nil), i.tools...)
+}
+
+// Errors returns why plugins failed to load
+func (i *Inventory) Errors() []error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return append([]error(nil), i.errs...)
+}
+
+// Reject closes tool and records that it was not registered, for example
+// because its name is taken by another tool
+func (i *Inventory) Reject(tool Plugin, reason error) {
+	tool.Close()
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	index, ok := i.loaded[tool]
+	if !ok {
+		return
+	}
+	delete(i.loaded, tool)
+	for j, loaded := range i.tools {
+		if loaded == tool {
+			i.tools = append(i.tools[:j:j], i.tools[j+1:]...)
+			break
+		}
+	}
+	i.statuses[index].Loaded = false
+	i.statuses[index].Error = reason.Error()
+	i.errs = append(i.errs, reason)
+}
+
+// PluginStatus implements domain.PluginReporter
+func (i *Inventory) PluginStatus() []domain.PluginStatus {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return append([]domain.PluginStatus(nil), i.statuses...)
+}
+
+// CheckPlugins implements domain.PluginReporter. Plugins that can tell
+// whether they still respond are asked; others are healthy while loaded.
+func (i *Inventory) CheckPlugins(ctx context.Context) []domain.PluginStatus {
+	i.mu.Lock()
+	tools := make(map[Plugin]int, len(i.loaded))
+	for tool, index := range i.loaded {
+		tools[tool] = index
+	}
+	results := make([]error, len(i.statuses))
+	i.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for tool, index := range tools {
+		if checker, ok := tool.(interface{ Check(context.Context) error }); ok {
+			wg.Add(1)
+			go func(index int) {
+				defer wg.Done()
+				results[index] = checker.Check(ctx)
+			}(index)
+		}
+	}
+	wg.Wait()
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for tool, index := range tools {
+		if _, ok := i.loaded[tool]; !ok {
+			continue
+		}
+		status := &i.statuses[index]
+		status.Checked, status.Healthy, status.HealthError = true, results[index] == nil, ""
+		if results[index] != nil {
+			status.HealthError = results[index].Error()
+		}
+	}
+	return append([]domain.PluginStatus(nil), i.statuses...)
+}
+
+// pluginKind tells what kind of plugin the file at path is
+func pluginKind(path string) string {
+	switch {
+	case isWASM(path):
+		return domain.PluginKindWASM
+	case isScript(path):
+		return domain.PluginKindScript
+	default:
+		return domain.PluginKindExecutable
+	}
+}
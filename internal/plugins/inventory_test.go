@@ -0,0 +1,71 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+func TestInventory(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "echo")
+	writePlugin(t, dir, "off")
+	os.WriteFile(filepath.Join(dir, Prefix+"broken"), []byte("#!/bin/sh\nexit 1\n"), 0755)
+
+	inventory := LoadInventory(domain.PluginConfig{
+		PluginPaths:     []string{dir},
+		DisabledPlugins: []string{"off"},
+		Commands: []domain.CommandToolConfig{
+			{Name: "hello", Command: "echo", Args: []string{"hello"}},
+			{Name: "gone", Command: "nettracex-missing-command"},
+		},
+	}, nil, nil, nil)
+	defer Close(inventory.Tools())
+
+	if tools := inventory.Tools(); len(tools) != 3 {
+		t.Fatalf("Tools() = %v", tools)
+	}
+	if errs := inventory.Errors(); len(errs) != 1 || !strings.Contains(errs[0].Error(), "broken") {
+		t.Errorf("Errors() = %v", errs)
+	}
+
+	statuses := inventory.CheckPlugins(context.Background())
+	byName := make(map[string]domain.PluginStatus)
+	for _, status := range statuses {
+		byName[status.Name] = status
+	}
+	if len(statuses) != 5 {
+		t.Fatalf("CheckPlugins() = %+v", statuses)
+	}
+	if status := byName["echo"]; !status.Loaded || status.Version != "1.0.0" || status.Tool != "echo" || !status.Healthy || status.Kind != domain.PluginKindExecutable {
+		t.Errorf("echo = %+v", status)
+	}
+	if status := byName["off"]; status.Enabled || status.Loaded {
+		t.Errorf("off = %+v", status)
+	}
+	if status := byName["broken"]; !status.Enabled || status.Loaded || !strings.Contains(status.Error, "did not describe itself") {
+		t.Errorf("broken = %+v", status)
+	}
+	if status := byName["hello"]; !status.Healthy || status.Kind != domain.PluginKindCommand {
+		t.Errorf("hello = %+v", status)
+	}
+	if status := byName["gone"]; !status.Checked || status.Healthy || status.HealthError == "" {
+		t.Errorf("gone = %+v", status)
+	}
+
+	hello := inventory.Tools()[1]
+	inventory.Reject(hello, errors.New("tool name hello is already taken"))
+	if len(inventory.Tools()) != 2 {
+		t.Errorf("Tools() = %v after Reject", inventory.Tools())
+	}
+	for _, status := range inventory.PluginStatus() {
+		if status.Name == "hello" && (status.Loaded || status.Error != "tool name hello is already taken") {
+			t.Errorf("hello = %+v after Reject", status)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/events"
+	"github.com/nettracex/nettracex-tui/internal/policy"
+)
+
+// Plugin is a tool loaded from a plugin executable, WebAssembly module or
+// check script, or a command tool defined in the configuration
+type Plugin interface {
+	domain.DescribedTool
+	Path() string
+	Close() error
+}
+
+// Discover returns the plugin executables, WebAssembly modules and check
+// scripts in paths, keyed by plugin name.
+// When two paths hold a plugin of the same name the first one wins.
+// Missing directories are skipped.
+func Discover(paths []string) map[string]string {
+	found := make(map[string]string)
+	for _, dir := range paths {
+		entries, err := os.ReadDir(expandHome(dir))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok || entry.IsDir() {
+				continue
+			}
+			if _, exists := found[name]; exists {
+				continue
+			}
+			path := filepath.Join(expandHome(dir), entry.Name())
+			if isWASM(path) || isScript(path) || isExecutable(path) {
+				found[name] = path
+			}
+		}
+	}
+	return found
+}
+
+// Load starts the plugins found in the configured paths and the command
+// tools of the configuration, honouring the enabled and disabled lists, and
+// returns their tools. Check scripts use
+// client for their network primitives, limited to the targets that targets
+// allows. A plugin that fails to start is
+// reported in the errors and the others still load.
+func Load(config domain.PluginConfig, client domain.NetworkClient, targets *policy.TargetPolicy, logger domain.Logger) ([]Plugin, []error) {
+	inventory := LoadInventory(config, client, targets, logger)
+	return inventory.Tools(), inventory.Errors()
+}
+
+// Enabled reports whether the plugin called name may be loaded. When
+// enabled_plugins is set only the plugins it lists load.
+func Enabled(config domain.PluginConfig, name string) bool {
+	for _, disabled := range config.DisabledPlugins {
+		if disabled == name {
+			return false
+		}
+	}
+	if len(config.EnabledPlugins) == 0 {
+		return true
+	}
+	for _, enabled := range config.EnabledPlugins {
+		if enabled == name {
+			return true
+		}
+	}
+	return false
+}
+
+// settings returns the plugin_settings entry of the plugin called name
+func settings(config domain.PluginConfig, name string) map[string]interface{} {
+	switch value := config.PluginSettings[name].(type) {
+	case map[string]interface{}:
+		return value
+	case nil:
+		return nil
+	default:
+		return map[string]interface{}{"value": value}
+	}
+}
+
+// pluginName returns the plugin name of an executable file name
+func pluginName(file string) (string, bool) {
+	if !strings.HasPrefix(file, Prefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(file, Prefix)
+	if strings.HasSuffix(name, WASMExt) {
+		name = strings.TrimSuffix(name, WASMExt)
+	} else if strings.HasSuffix(name, ScriptExt) {
+		name = strings.TrimSuffix(name, ScriptExt)
+	} else if runtime.GOOS == "windows" {
+		if !strings.EqualFold(filepath.Ext(name), ".exe") {
+			return "", false
+		}
+		name = name[:len(name)-len(".exe")]
+	}
+	return name, name != ""
+}
+
+// isWASM reports whether path is a WebAssembly module
+func isWASM(path string) bool {
+	return strings.HasSuffix(path, WASMExt)
+}
+
+// isScript reports whether path is a check script
+func isScript(path string) bool {
+	return strings.HasSuffix(path, ScriptExt)
+}
+
+// isExecutable reports whether path is a file the user may run
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode().Perm()&0111 != 0
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// Close stops every plugin in tools
+func Close(tools []Plugin) {
+	for _, tool := range tools {
+		tool.Close()
+	}
+}
+
+// Subscribe sends the plugins in tools that asked for events the events
+// published on bus
+func Subscribe(bus *events.Bus, tools []Plugin) {
+	for _, tool := range tools {
+		if subscriber, ok := tool.(interface{ Subscribe(*events.Bus) func() }); ok {
+			subscriber.Subscribe(bus)
+		}
+	}
+}
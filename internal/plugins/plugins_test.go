@@ -0,0 +1,240 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/events"
+)
+
+// echoPlugin is served by the test binary when it runs as a plugin. Given
+// an event_log setting it subscribes to results and appends them there.
+type echoPlugin struct {
+	eventLog string
+}
+
+func (p *echoPlugin) Describe(args DescribeArgs) (DescribeReply, error) {
+	reply := DescribeReply{
+		Name:        "echo",
+		Description: fmt.Sprintf("Echoes its input (%v)", args.Settings["greeting"]),
+		Parameters:  []domain.ParameterSpec{{Key: "text", Label: "Text", Required: true}},
+		Version:     "1.0.0",
+	}
+	if path, ok := args.Settings["event_log"].(string); ok {
+		p.eventLog = path
+		reply.Events = []string{string(events.KindResultReceived)}
+	}
+	return reply, nil
+}
+
+func (p *echoPlugin) Event(args EventArgs) error {
+	f, err := os.OpenFile(p.eventLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = fmt.Fprintf(f, "%s %s %s %s\n", args.Kind, args.Tool, args.Target, args.Params["count"])
+	return err
+}
+
+func (p *echoPlugin) Execute(args ExecuteArgs) (ExecuteReply, error) {
+	switch args.Params["text"] {
+	case "fail":
+		return ExecuteReply{}, errors.New("asked to fail")
+	case "exit":
+		os.Exit(3)
+	}
+	return ExecuteReply{
+		Text:     args.Params["text"],
+		Data:     map[string]interface{}{"length": len(args.Params["text"])},
+		Metadata: map[string]interface{}{"echoed": true},
+	}, nil
+}
+
+// TestHelperPlugin is not a real test; it serves echoPlugin when the test
+// binary is started as a plugin
+func TestHelperPlugin(t *testing.T) {
+	if os.Getenv("NETTRACEX_TEST_PLUGIN") != "1" {
+		return
+	}
+	Serve(&echoPlugin{})
+	os.Exit(0)
+}
+
+// writePlugin creates a nettracex-<name> executable in dir that runs the
+// test binary as a plugin
+func writePlugin(t *testing.T, dir, name string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin scripts need a POSIX shell")
+	}
+	path := filepath.Join(dir, Prefix+name)
+	script := fmt.Sprintf("#!/bin/sh\nNETTRACEX_TEST_PLUGIN=1 exec %q -test.run=TestHelperPlugin\n", os.Args[0])
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write plugin: %v", err)
+	}
+	return path
+}
+
+func TestDiscover(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	writePlugin(t, first, "echo")
+	writePlugin(t, second, "echo")
+	writePlugin(t, second, "other")
+	os.WriteFile(filepath.Join(second, Prefix+"data"), []byte("not a plugin"), 0644)
+	os.WriteFile(filepath.Join(second, "README"), []byte("not a plugin"), 0755)
+
+	found := Discover([]string{filepath.Join(first, "missing"), first, second})
+	if len(found) != 2 {
+		t.Fatalf("Expected 2 plugins, got %v", found)
+	}
+	if found["echo"] != filepath.Join(first, Prefix+"echo") {
+		t.Errorf("Expected the first path to win, got %s", found["echo"])
+	}
+	if found["other"] == "" {
+		t.Error("Expected the other plugin to be found")
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	config := domain.PluginConfig{DisabledPlugins: []string{"echo"}}
+	if Enabled(config, "echo") || !Enabled(config, "other") {
+		t.Error("Expected disabled plugins to be skipped")
+	}
+	config = domain.PluginConfig{EnabledPlugins: []string{"echo"}}
+	if !Enabled(config, "echo") || Enabled(config, "other") {
+		t.Error("Expected only enabled plugins to load")
+	}
+}
+
+func TestLoadAndExecute(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "echo")
+	tools, errs := Load(domain.PluginConfig{
+		PluginPaths:    []string{dir},
+		PluginSettings: map[string]interface{}{"echo": map[string]interface{}{"greeting": "hi"}},
+	}, nil, nil, nil)
+	defer Close(tools)
+	if len(errs) > 0 || len(tools) != 1 {
+		t.Fatalf("Expected one plugin, got %v, %v", tools, errs)
+	}
+
+	tool := tools[0]
+	if tool.Name() != "echo" || tool.Description() != "Echoes its input (hi)" {
+		t.Errorf("Unexpected description %q: %q", tool.Name(), tool.Description())
+	}
+	if specs, ok := domain.ParameterSpecs(tool); !ok || len(specs) != 1 || specs[0].Key != "text" {
+		t.Errorf("Expected the text parameter, got %v", specs)
+	}
+
+	params := domain.NewParameters()
+	if err := tool.Validate(params); err == nil {
+		t.Error("Expected the required parameter to be validated")
+	}
+
+	params.Set("text", "hello")
+	result, err := tool.Execute(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	data, ok := result.Data().(domain.PluginResult)
+	if !ok || data.Text != "hello" || data.Tool != "echo" {
+		t.Errorf("Unexpected result %#v", result.Data())
+	}
+	if result.Metadata()["echoed"] != true {
+		t.Errorf("Expected the plugin metadata, got %v", result.Metadata())
+	}
+
+	params.Set("text", "fail")
+	if _, err := tool.Execute(context.Background(), params); err == nil {
+		t.Error("Expected the plugin error to be returned")
+	}
+}
+
+func TestExecuteRestartsPlugin(t *testing.T) {
+	dir := t.TempDir()
+	tool, err := Start(writePlugin(t, dir, "echo"), nil, nil)
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer tool.Close()
+
+	params := domain.NewParameters()
+	params.Set("text", "exit")
+	if _, err := tool.Execute(context.Background(), params); err == nil {
+		t.Error("Expected an error when the plugin exits")
+	}
+	params.Set("text", "again")
+	result, err := tool.Execute(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Expected the plugin to restart, got %v", err)
+	}
+	if result.Data().(domain.PluginResult).Text != "again" {
+		t.Errorf("Unexpected result %#v", result.Data())
+	}
+}
+
+func TestLoadReportsBrokenPlugins(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, Prefix+"broken"), []byte("#!/bin/sh\nexit 1\n"), 0755)
+	tools, errs := Load(domain.PluginConfig{PluginPaths: []string{dir}}, nil, nil, nil)
+	defer Close(tools)
+	if len(tools) != 0 || len(errs) != 1 {
+		t.Errorf("Expected one error, got %v, %v", tools, errs)
+	}
+}
+
+func TestPluginEvents(t *testing.T) {
+	dir := t.TempDir()
+	eventLog := filepath.Join(dir, "events.log")
+	writePlugin(t, dir, "echo")
+	tools, errs := Load(domain.PluginConfig{
+		PluginPaths:    []string{dir},
+		PluginSettings: map[string]interface{}{"echo": map[string]interface{}{"event_log": eventLog}},
+	}, nil, nil, nil)
+	defer Close(tools)
+	if len(errs) > 0 || len(tools) != 1 {
+		t.Fatalf("Expected one plugin, got %v, %v", tools, errs)
+	}
+
+	bus := events.NewBus()
+	Subscribe(bus, tools)
+	bus.Publish(events.Event{Kind: events.KindToolStarted, Tool: "ping", Target: "example.com"})
+	bus.Publish(events.Event{Kind: events.KindResultReceived, Tool: "ping", Target: "example.com", Params: map[string]interface{}{"count": 4}})
+
+	want := "tool.result ping example.com 4\n"
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, _ := os.ReadFile(eventLog)
+		if string(data) == want {
+			break
+		}
+		if time.Now().After(deadline) || len(data) > len(want) {
+			t.Fatalf("Event log = %q, want %q", data, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestStartRejectsUnknownEvents(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin scripts need a POSIX shell")
+	}
+	path := filepath.Join(t.TempDir(), Prefix+"nosy")
+	script := `#!/bin/sh
+read line
+echo '{"id": 0, "result": {"name": "nosy", "events": ["tool.everything"]}, "error": null}'
+`
+	os.WriteFile(path, []byte(script), 0755)
+	if _, err := Start(path, nil, nil); err == nil || !strings.Contains(err.Error(), `unknown event "tool.everything"`) {
+		t.Errorf("Start() error = %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+// Package plugins loads external diagnostic tools from disk. A plugin is an
+// executable named nettracex-<name> in one of the configured plugin paths.
+// NetTraceX starts it once and talks JSON-RPC 1.0 over its stdin and
+// stdout, as implemented by net/rpc/jsonrpc, so plugins can be written in
+// any language:
+//
+//	{"method": "Plugin.Describe", "params": [{"api_version": 1, "settings": {}}], "id": 0}
+//	{"id": 0, "result": {"name": "http", "description": "...", "parameters": [...]}, "error": null}
+//	{"method": "Plugin.Execute", "params": [{"params": {"url": "https://example.com"}}], "id": 1}
+//	{"id": 1, "result": {"text": "200 OK in 84ms", "data": {...}}, "error": null}
+//
+// A plugin that lists event kinds in the events of its Describe reply is
+// sent a Plugin.Event call, without waiting for the reply, whenever one of
+// them is published:
+//
+//	{"method": "Plugin.Event", "params": [{"kind": "tool.result", "tool": "ping", "target": "example.com", ...}], "id": 2}
+//
+// Anything a plugin writes to stderr is logged.
+//
+// A plugin can also be a WebAssembly module named nettracex-<name>.wasm,
+// which runs sandboxed in the wazero runtime; see WASMTool
+// for its ABI. A check script named nettracex-<name>.star is a Starlark
+// program run by go.starlark.net with ping, dns, tcp and http
+// primitives; see ScriptTool.
+package plugins
+
+import (
+	"fmt"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/events"
+)
+
+// APIVersion is the protocol version sent to plugins in Describe
+const APIVersion = 1
+
+// Prefix starts the file name of every plugin executable
+const Prefix = "nettracex-"
+
+// RPC method names
+const (
+	DescribeMethod = "Plugin.Describe"
+	ExecuteMethod  = "Plugin.Execute"
+	EventMethod    = "Plugin.Event"
+)
+
+// DescribeArgs asks a plugin to describe itself. Settings are the
+// plugins.plugin_settings entry named after the plugin, if any.
+type DescribeArgs struct {
+	APIVersion int                    `json:"api_version"`
+	Settings   map[string]interface{} `json:"settings,omitempty"`
+}
+
+// DescribeReply names the tool a plugin provides, its inputs, its version
+// and the kinds of events it wants to be sent
+type DescribeReply struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Parameters  []domain.ParameterSpec `json:"parameters"`
+	Version     string                 `json:"version,omitempty"`
+	Events      []string               `json:"events,omitempty"`
+}
+
+// ExecuteArgs runs the tool with the values entered for its parameters
+type ExecuteArgs struct {
+	Params map[string]string `json:"params"`
+}
+
+// ExecuteReply is the outcome of a run: text to show, and optional data
+// and metadata for export and hints for showing the data
+type ExecuteReply struct {
+	Text     string                 `json:"text"`
+	Data     interface{}            `json:"data,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Hints    *domain.RenderHints    `json:"hints,omitempty"`
+}
+
+// EventArgs reports an event of any tool to a plugin that subscribed to
+// its kind
+type EventArgs struct {
+	Kind       string            `json:"kind"`
+	Tool       string            `json:"tool"`
+	Target     string            `json:"target,omitempty"`
+	Params     map[string]string `json:"params,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	Path       string            `json:"path,omitempty"`
+	DurationMS float64           `json:"duration_ms,omitempty"`
+	Time       time.Time         `json:"time"`
+}
+
+// newEventArgs converts event for the wire
+func newEventArgs(event events.Event) EventArgs {
+	args := EventArgs{
+		Kind:       string(event.Kind),
+		Tool:       event.Tool,
+		Target:     event.Target,
+		Path:       event.Path,
+		DurationMS: float64(event.Duration) / float64(time.Millisecond),
+		Time:       event.Time,
+	}
+	if len(event.Params) > 0 {
+		args.Params = make(map[string]string, len(event.Params))
+		for key, value := range event.Params {
+			args.Params[key] = fmt.Sprint(valueOrEmpty(value))
+		}
+	}
+	if event.Err != nil {
+		args.Error = event.Err.Error()
+	}
+	return args
+}
+
+// Handler implements a plugin in Go, see Serve
+type Handler interface {
+	Describe(args DescribeArgs) (DescribeReply, error)
+	Execute(args ExecuteArgs) (ExecuteReply, error)
+}
+
+// EventHandler is implemented by a Handler that subscribes to events
+type EventHandler interface {
+	Event(args EventArgs) error
+}
+
+// service exposes a Handler under the Plugin name net/rpc requires
+type service struct {
+	handler Handler
+}
+
+// Describe implements Plugin.Describe
+func (s *service) Describe(args *DescribeArgs, reply *DescribeReply) error {
+	r, err := s.handler.Describe(*args)
+	*reply = r
+	return err
+}
+
+// Execute implements Plugin.Execute
+func (s *service) Execute(args *ExecuteArgs, reply *ExecuteReply) error {
+	r, err := s.handler.Execute(*args)
+	*reply = r
+	return err
+}
+
+// Event implements Plugin.Event
+func (s *service) Event(args *EventArgs, reply *struct{}) error {
+	if handler, ok := s.handler.(EventHandler); ok {
+		return handler.Event(*args)
+	}
+	return nil
+}
+
+// Serve answers calls from NetTraceX on stdin and stdout until stdin is
+// closed. Plugins written in Go call it from main.
+func Serve(handler Handler) error {
+	return serve(handler, stdio{os.Stdin, os.Stdout})
+}
+
+// serve answers calls on conn
+func serve(handler Handler, conn io.ReadWriteCloser) error {
+	server := rpc.NewServer()
+	if err := server.RegisterName("Plugin", &service{handler: handler}); err != nil {
+		return err
+	}
+	server.ServeCodec(jsonrpc.NewServerCodec(conn))
+	return nil
+}
+
+// stdio joins a reader and a writer into a connection
+type stdio struct {
+	io.ReadCloser
+	io.WriteCloser
+}
+
+// Close closes both directions
+func (s stdio) Close() error {
+	rerr := s.ReadCloser.Close()
+	if err := s.WriteCloser.Close(); err != nil {
+		return err
+	}
+	return rerr
+}
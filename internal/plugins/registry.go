@@ -0,0 +1,193 @@
+// Package plugins keeps the registry of the diagnostic tools offered by the
+// application: the built-in tools and those loaded from disk. The
+// registry orders tools by priority, rejects duplicate names, records the
+// category of each tool and is safe for concurrent use.
+package plugins
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// Category groups tools by what they diagnose
+type Category string
+
+const (
+	CategoryDNS          Category = "dns"
+	CategoryConnectivity Category = "connectivity"
+	CategorySecurity     Category = "security"
+	CategoryPlugin       Category = "plugin"
+)
+
+// DefaultPriority is the priority of tools registered without one
+const DefaultPriority = 100
+
+// Info is the metadata kept for a registered tool
+type Info struct {
+	Category Category
+	Priority int
+}
+
+// Option sets metadata of a tool being registered
+type Option func(*Info)
+
+// WithCategory files the tool under category
+func WithCategory(category Category) Option {
+	return func(info *Info) {
+		info.Category = category
+	}
+}
+
+// WithPriority orders the tool; lower priorities are listed first
+func WithPriority(priority int) Option {
+	return func(info *Info) {
+		info.Priority = priority
+	}
+}
+
+// entry is a registered tool with its metadata and registration order
+type entry struct {
+	tool  domain.DiagnosticTool
+	info  Info
+	order int
+}
+
+// Registry holds the diagnostic tools by name. It implements
+// domain.PluginRegistry.
+type Registry struct {
+	mu      sync.RWMutex
+	entries map[string]*entry
+	next    int
+	logger  domain.Logger
+}
+
+// NewRegistry creates an empty registry that logs registrations to
+// logger, if any
+func NewRegistry(logger domain.Logger) *Registry {
+	return &Registry{
+		entries: make(map[string]*entry),
+		logger:  logger,
+	}
+}
+
+// Register adds tool with the default priority and no category
+func (r *Registry) Register(tool domain.DiagnosticTool) error {
+	return r.RegisterWith(tool)
+}
+
+// RegisterWith adds tool with the metadata set by options. A tool whose
+// name is already registered is rejected.
+func (r *Registry) RegisterWith(tool domain.DiagnosticTool, options ...Option) error {
+	name := tool.Name()
+	info := Info{Priority: DefaultPriority}
+	for _, option := range options {
+		option(&info)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.entries[name]; exists {
+		return &domain.NetTraceError{
+			Type:      domain.ErrorTypePlugin,
+			Message:   fmt.Sprintf("tool %s is already registered", name),
+			Context:   map[string]interface{}{"tool": name},
+			Timestamp: time.Now(),
+			Code:      "TOOL_ALREADY_REGISTERED",
+		}
+	}
+	r.entries[name] = &entry{tool: tool, info: info, order: r.next}
+	r.next++
+	if r.logger != nil {
+		r.logger.Debug("Registered tool", "tool", name, "category", info.Category, "priority", info.Priority)
+	}
+	return nil
+}
+
+// Get returns the tool called name
+func (r *Registry) Get(name string) (domain.DiagnosticTool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, exists := r.entries[name]
+	if !exists {
+		return nil, false
+	}
+	return e.tool, true
+}
+
+// Info returns the metadata of the tool called name
+func (r *Registry) Info(name string) (Info, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, exists := r.entries[name]
+	if !exists {
+		return Info{}, false
+	}
+	return e.info, true
+}
+
+// List returns the tools by priority, then in the order they were
+// registered
+func (r *Registry) List() []domain.DiagnosticTool {
+	return r.list(func(Info) bool { return true })
+}
+
+// ByCategory returns the tools filed under category, ordered like List
+func (r *Registry) ByCategory(category Category) []domain.DiagnosticTool {
+	return r.list(func(info Info) bool { return info.Category == category })
+}
+
+// Unregister removes the tool called name
+func (r *Registry) Unregister(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.entries[name]; !exists {
+		return &domain.NetTraceError{
+			Type:      domain.ErrorTypePlugin,
+			Message:   fmt.Sprintf("tool %s is not registered", name),
+			Context:   map[string]interface{}{"tool": name},
+			Timestamp: time.Now(),
+			Code:      "TOOL_NOT_REGISTERED",
+		}
+	}
+	delete(r.entries, name)
+	return nil
+}
+
+// Wrap replaces every tool with wrap(tool), keeping its metadata and
+// position. Wrappers such as policy guards and tracing are applied this
+// way; they must keep the name of the tool.
+func (r *Registry) Wrap(wrap func(domain.DiagnosticTool) domain.DiagnosticTool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.entries {
+		e.tool = wrap(e.tool)
+	}
+}
+
+// list returns the tools whose metadata matches, in order
+func (r *Registry) list(match func(Info) bool) []domain.DiagnosticTool {
+	r.mu.RLock()
+	entries := make([]entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		if match(e.info) {
+			entries = append(entries, *e)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].info.Priority != entries[j].info.Priority {
+			return entries[i].info.Priority < entries[j].info.Priority
+		}
+		return entries[i].order < entries[j].order
+	})
+	tools := make([]domain.DiagnosticTool, len(entries))
+	for i, e := range entries {
+		tools[i] = e.tool
+	}
+	return tools
+}
@@ -0,0 +1,116 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubTool is a diagnostic tool that only has a name
+type stubTool struct {
+	name string
+}
+
+func (t *stubTool) Name() string                            { return t.name }
+func (t *stubTool) Description() string                     { return "stub tool" }
+func (t *stubTool) Validate(params domain.Parameters) error { return nil }
+func (t *stubTool) GetModel() tea.Model                     { return nil }
+
+func (t *stubTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	return domain.NewResult(t.name), nil
+}
+
+// wrappedTool stands for wrappers such as policy guards
+type wrappedTool struct {
+	domain.DiagnosticTool
+}
+
+// names returns the names of tools
+func names(tools []domain.DiagnosticTool) []string {
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Name())
+	}
+	return names
+}
+
+func TestRegistryOrder(t *testing.T) {
+	registry := NewRegistry(nil)
+	require.NoError(t, registry.Register(&stubTool{name: "http"}))
+	require.NoError(t, registry.RegisterWith(&stubTool{name: "ping"}, WithCategory(CategoryConnectivity), WithPriority(20)))
+	require.NoError(t, registry.RegisterWith(&stubTool{name: "whois"}, WithCategory(CategoryDNS), WithPriority(10)))
+	require.NoError(t, registry.RegisterWith(&stubTool{name: "dns"}, WithCategory(CategoryDNS), WithPriority(20)))
+	require.NoError(t, registry.RegisterWith(&stubTool{name: "nmap"}, WithCategory(CategoryPlugin)))
+
+	assert.Equal(t, []string{"whois", "ping", "dns", "http", "nmap"}, names(registry.List()))
+	assert.Equal(t, []string{"whois", "dns"}, names(registry.ByCategory(CategoryDNS)))
+	assert.Empty(t, registry.ByCategory(CategorySecurity))
+
+	info, ok := registry.Info("ping")
+	assert.True(t, ok)
+	assert.Equal(t, Info{Category: CategoryConnectivity, Priority: 20}, info)
+	info, _ = registry.Info("http")
+	assert.Equal(t, Info{Priority: DefaultPriority}, info)
+}
+
+func TestRegistryDuplicates(t *testing.T) {
+	registry := NewRegistry(nil)
+	first := &stubTool{name: "ping"}
+	require.NoError(t, registry.Register(first))
+
+	err := registry.Register(&stubTool{name: "ping"})
+	var netErr *domain.NetTraceError
+	require.ErrorAs(t, err, &netErr)
+	assert.Equal(t, "TOOL_ALREADY_REGISTERED", netErr.Code)
+	tool, _ := registry.Get("ping")
+	assert.Same(t, first, tool)
+
+	require.NoError(t, registry.Unregister("ping"))
+	assert.Error(t, registry.Unregister("ping"))
+	_, ok := registry.Get("ping")
+	assert.False(t, ok)
+	assert.NoError(t, registry.Register(&stubTool{name: "ping"}))
+}
+
+func TestRegistryWrap(t *testing.T) {
+	registry := NewRegistry(nil)
+	require.NoError(t, registry.RegisterWith(&stubTool{name: "ssl"}, WithCategory(CategorySecurity), WithPriority(5)))
+	require.NoError(t, registry.Register(&stubTool{name: "dns"}))
+
+	registry.Wrap(func(tool domain.DiagnosticTool) domain.DiagnosticTool {
+		return &wrappedTool{DiagnosticTool: tool}
+	})
+
+	assert.Equal(t, []string{"ssl", "dns"}, names(registry.List()))
+	tool, _ := registry.Get("ssl")
+	assert.IsType(t, &wrappedTool{}, tool)
+	info, _ := registry.Info("ssl")
+	assert.Equal(t, CategorySecurity, info.Category)
+}
+
+func TestRegistryConcurrentAccess(t *testing.T) {
+	registry := NewRegistry(nil)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			registry.Register(&stubTool{name: fmt.Sprintf("tool%d", i)})
+		}(i)
+		go func() {
+			defer wg.Done()
+			registry.List()
+			registry.Get("tool0")
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, registry.List(), 20)
+
+	var _ domain.PluginRegistry = registry
+}
@@ -0,0 +1,656 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/policy"
+	"github.com/nettracex/nettracex-tui/internal/tools/dns"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+// ScriptExt is the file extension of check scripts
+const ScriptExt = ".star"
+
+// A check script is a Starlark file that defines
+//
+//	description = "what the check does"
+//	params = ["host", {"key": "port", "label": "Port", "default": "443"}]
+//	def check(params): ...
+//
+// and optionally name, to use another tool name than the file's, hints, a
+// dict of render hints, and validate(params), which returns an error
+// message or None. check receives the parameters as a dict of strings; a
+// string it returns is shown as text and anything else as data, after
+// the lines it printed. Parameters given as plain strings are required.
+//
+// Besides the settings dict of the plugin, scripts may call
+//
+//	ping(host, count=4, timeout=5)
+//	dns(name, type="A")
+//	tcp(host, port, timeout=5)
+//	http(url, method="GET", timeout=10)
+//
+// Each returns a dict with ok, error and time_ms, and ping also sent,
+// received, loss and min_ms, avg_ms and max_ms, dns records, tcp address
+// and http status, headers and the first 64 KiB of body.
+const (
+	scriptCheck    = "check"
+	scriptValidate = "validate"
+)
+
+// scriptTimeout bounds a run of a script when the caller set no deadline
+const scriptTimeout = 30 * time.Second
+
+// maxScriptBody bounds the response body an http() call returns
+const maxScriptBody = 64 << 10
+
+// Thread locals holding the context of a run and whether its caller
+// acknowledged probing public targets
+const (
+	scriptContext      = "context"
+	scriptAcknowledged = "acknowledged"
+)
+
+// scriptPredeclared are the names the host provides to every script
+var scriptPredeclared = []string{"settings", "ping", "dns", "tcp", "http"}
+
+// ScriptTool is a diagnostic tool defined by a check script. Every run
+// executes the script afresh, so runs share no state.
+type ScriptTool struct {
+	path     string
+	settings map[string]interface{}
+	client   domain.NetworkClient
+	targets  *policy.TargetPolicy
+	logger   domain.Logger
+	program  *starlark.Program
+	describe DescribeReply
+	hints    *domain.RenderHints
+	validate bool // the script defines validate(params)
+}
+
+// StartScript loads the check script at path as the tool name. The network
+// primitives of the script run on client, and the ping, tcp and http
+// primitives probe only the targets that targets allows.
+func StartScript(path, name string, settings map[string]interface{}, client domain.NetworkClient, targets *policy.TargetPolicy, logger domain.Logger) (*ScriptTool, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin %s: %w", path, err)
+	}
+	isPredeclared := func(name string) bool {
+		for _, predeclared := range scriptPredeclared {
+			if name == predeclared {
+				return true
+			}
+		}
+		return false
+	}
+	_, program, err := starlark.SourceProgramOptions(&syntax.FileOptions{}, path, src, isPredeclared)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", path, err)
+	}
+
+	t := &ScriptTool{path: path, settings: settings, client: client, targets: targets, logger: logger, program: program}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	globals, err := t.run(t.newThread(ctx, nil))
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", path, err)
+	}
+	if _, ok := globals[scriptCheck].(*starlark.Function); !ok {
+		return nil, fmt.Errorf("plugin %s does not define %s(params)", path, scriptCheck)
+	}
+
+	t.describe.Name = name
+	if value, ok := starlark.AsString(globals["name"]); ok && value != "" {
+		t.describe.Name = value
+	}
+	if value, ok := starlark.AsString(globals["description"]); ok {
+		t.describe.Description = value
+	}
+	if value, ok := starlark.AsString(globals["version"]); ok {
+		t.describe.Version = value
+	}
+	if t.describe.Parameters, err = scriptParams(globals["params"]); err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", path, err)
+	}
+	if value, ok := globals["hints"].(*starlark.Dict); ok {
+		t.hints = scriptHints(value)
+	}
+	_, t.validate = globals[scriptValidate]
+	return t, nil
+}
+
+// scriptParams reads the params list of a script
+func scriptParams(value starlark.Value) ([]domain.ParameterSpec, error) {
+	if value == nil || value == starlark.None {
+		return nil, nil
+	}
+	list, ok := value.(*starlark.List)
+	if !ok {
+		return nil, fmt.Errorf("params must be a list, not %s", value.Type())
+	}
+
+	specs := make([]domain.ParameterSpec, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		switch elem := toGo(list.Index(i)).(type) {
+		case string:
+			specs = append(specs, domain.ParameterSpec{Key: elem, Required: true})
+		case map[string]interface{}:
+			spec := domain.ParameterSpec{Key: fmt.Sprint(valueOrEmpty(elem["key"]))}
+			spec.Label, _ = elem["label"].(string)
+			spec.Required, _ = elem["required"].(bool)
+			if def, ok := elem["default"]; ok && def != nil {
+				spec.Default = fmt.Sprint(def)
+			}
+			if spec.Key == "" {
+				return nil, fmt.Errorf("params entry %d has no key", i)
+			}
+			specs = append(specs, spec)
+		default:
+			return nil, fmt.Errorf("params entry %d must be a string or dict", i)
+		}
+	}
+	return specs, nil
+}
+
+// scriptHints reads the hints dict of a script
+func scriptHints(d *starlark.Dict) *domain.RenderHints {
+	m, _ := toGo(d).(map[string]interface{})
+	hints := &domain.RenderHints{}
+	hints.Title, _ = m["title"].(string)
+	hints.Format, _ = m["format"].(string)
+	if columns, ok := m["columns"].([]interface{}); ok {
+		for _, column := range columns {
+			hints.Columns = append(hints.Columns, fmt.Sprint(column))
+		}
+	}
+	return hints
+}
+
+// Name returns the tool name
+func (t *ScriptTool) Name() string {
+	return t.describe.Name
+}
+
+// Description returns the description the script sets
+func (t *ScriptTool) Description() string {
+	return t.describe.Description
+}
+
+// Parameters returns the inputs the script asks for
+func (t *ScriptTool) Parameters() []domain.ParameterSpec {
+	return t.describe.Parameters
+}
+
+// Version returns the version the script declares, if any
+func (t *ScriptTool) Version() string {
+	return t.describe.Version
+}
+
+// Path returns the script file
+func (t *ScriptTool) Path() string {
+	return t.path
+}
+
+// Validate checks the required parameters and calls the script's
+// validate(params), when it defines one
+func (t *ScriptTool) Validate(params domain.Parameters) error {
+	if err := validateRequired(t.Name(), t.describe.Parameters, params); err != nil {
+		return err
+	}
+	if !t.validate {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), scriptTimeout)
+	defer cancel()
+	thread := t.newThread(ctx, nil)
+	globals, err := t.run(thread)
+	if err != nil {
+		return failed(t.Name(), t.path, err)
+	}
+	message, err := starlark.Call(thread, globals[scriptValidate], starlark.Tuple{scriptArgs(params)}, nil)
+	if err != nil {
+		return failed(t.Name(), t.path, err)
+	}
+	if message != starlark.None && message.Truth() {
+		text, ok := starlark.AsString(message)
+		if !ok {
+			text = message.String()
+		}
+		return &domain.NetTraceError{
+			Type:      domain.ErrorTypeValidation,
+			Message:   text,
+			Context:   map[string]interface{}{"tool": t.Name()},
+			Timestamp: time.Now(),
+			Code:      "PLUGIN_VALIDATION_FAILED",
+		}
+	}
+	return nil
+}
+
+// Execute runs the script's check(params) and wraps what it printed and
+// returned in a PluginResult
+func (t *ScriptTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	if err := t.Validate(params); err != nil {
+		return nil, err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, scriptTimeout)
+		defer cancel()
+	}
+
+	var output []string
+	thread := t.newThread(ctx, func(message string) { output = append(output, message) })
+	acknowledged, _ := params.Get(policy.AcknowledgeParam).(bool)
+	thread.SetLocal(scriptAcknowledged, acknowledged)
+	globals, err := t.run(thread)
+	if err != nil {
+		return nil, failed(t.Name(), t.path, err)
+	}
+	value, err := starlark.Call(thread, globals[scriptCheck], starlark.Tuple{scriptArgs(params)}, nil)
+	if err != nil {
+		return nil, failed(t.Name(), t.path, err)
+	}
+
+	var reply ExecuteReply
+	switch value := value.(type) {
+	case starlark.NoneType:
+	case starlark.String:
+		output = append(output, string(value))
+	default:
+		reply.Data = toGo(value)
+	}
+	reply.Text = strings.Join(output, "\n")
+	return newResult(t.Name(), t.path, reply, t.hints), nil
+}
+
+// GetModel returns nil; the TUI builds a form from Parameters
+func (t *ScriptTool) GetModel() tea.Model {
+	return nil
+}
+
+// Close releases nothing; scripts only run during a call
+func (t *ScriptTool) Close() error {
+	return nil
+}
+
+// newThread returns a thread that is cancelled when ctx is done. print
+// receives the output of print(); when it is nil the output is dropped.
+func (t *ScriptTool) newThread(ctx context.Context, print func(message string)) *starlark.Thread {
+	thread := &starlark.Thread{
+		Name: t.path,
+		Print: func(_ *starlark.Thread, message string) {
+			if print != nil {
+				print(message)
+			}
+		},
+	}
+	thread.SetLocal(scriptContext, ctx)
+	context.AfterFunc(ctx, func() { thread.Cancel(ctx.Err().Error()) })
+	return thread
+}
+
+// threadContext returns the context of the run on thread, for primitives
+// that do I/O
+func threadContext(thread *starlark.Thread) context.Context {
+	return thread.Local(scriptContext).(context.Context)
+}
+
+// enforce checks a target of the ping, tcp or http primitive against the
+// target policy, as the guard of an active tool would
+func (t *ScriptTool) enforce(thread *starlark.Thread, target string) error {
+	if t.targets == nil {
+		return nil
+	}
+	acknowledged, _ := thread.Local(scriptAcknowledged).(bool)
+	return t.targets.EnforceProbe(threadContext(thread), t.Name(), target, acknowledged)
+}
+
+// run executes the top level of the script on thread and returns its
+// globals
+func (t *ScriptTool) run(thread *starlark.Thread) (starlark.StringDict, error) {
+	return t.program.Init(thread, starlark.StringDict{
+		"settings": fromGo(t.settings),
+		"ping":     starlark.NewBuiltin("ping", t.ping),
+		"dns":      starlark.NewBuiltin("dns", t.dns),
+		"tcp":      starlark.NewBuiltin("tcp", t.tcp),
+		"http":     starlark.NewBuiltin("http", t.http),
+	})
+}
+
+// scriptArgs passes params to a script as a dict of strings
+func scriptArgs(params domain.Parameters) starlark.Value {
+	return fromGo(executeArgs(params).Params)
+}
+
+// seconds reads a timeout argument
+func seconds(value starlark.Value) (time.Duration, error) {
+	if _, ok := value.(starlark.Bool); !ok {
+		if f, ok := starlark.AsFloat(value); ok {
+			return time.Duration(f * float64(time.Second)), nil
+		}
+	}
+	return 0, fmt.Errorf("timeout must be a number of seconds, not %s", value.Type())
+}
+
+// milliseconds converts a duration for scripts
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// outcome builds the dict a primitive returns
+func outcome(elapsed time.Duration, err error, fields map[string]interface{}) starlark.Value {
+	fields["ok"] = err == nil
+	fields["error"] = ""
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	fields["time_ms"] = milliseconds(elapsed)
+	return fromGo(fields)
+}
+
+func (t *ScriptTool) ping(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var host string
+	var count = 4
+	var timeout starlark.Value = starlark.MakeInt(5)
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "host", &host, "count?", &count, "timeout?", &timeout); err != nil {
+		return nil, err
+	}
+	if count < 1 || count > 100 {
+		return nil, fmt.Errorf("count must be an int from 1 to 100")
+	}
+	wait, err := seconds(timeout)
+	if err != nil {
+		return nil, err
+	}
+	if t.client == nil {
+		return nil, fmt.Errorf("no network client")
+	}
+	if err := t.enforce(thread, host); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	fields := map[string]interface{}{"host": host, "sent": 0, "received": 0, "loss": 100.0}
+	replies, err := t.client.Ping(threadContext(thread), host, domain.PingOptions{
+		Count:      count,
+		Interval:   time.Second,
+		Timeout:    wait,
+		PacketSize: 64,
+		TTL:        64,
+	})
+	if err != nil {
+		return outcome(time.Since(start), err, fields), nil
+	}
+
+	sent, received := 0, 0
+	var total, lowest, highest time.Duration
+	var lastErr error
+	for reply := range replies {
+		sent++
+		if reply.Error != nil {
+			lastErr = reply.Error
+			continue
+		}
+		received++
+		total += reply.RTT
+		if received == 1 || reply.RTT < lowest {
+			lowest = reply.RTT
+		}
+		highest = max(highest, reply.RTT)
+	}
+	if err := threadContext(thread).Err(); err != nil {
+		return nil, err
+	}
+
+	fields["sent"], fields["received"] = sent, received
+	if sent > 0 {
+		fields["loss"] = float64(sent-received) * 100 / float64(sent)
+	}
+	if received > 0 {
+		fields["min_ms"] = milliseconds(lowest)
+		fields["avg_ms"] = milliseconds(total / time.Duration(received))
+		fields["max_ms"] = milliseconds(highest)
+		lastErr = nil
+	} else if lastErr == nil {
+		lastErr = fmt.Errorf("no replies from %s", host)
+	}
+	return outcome(time.Since(start), lastErr, fields), nil
+}
+
+func (t *ScriptTool) dns(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	var recordType = "A"
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "name", &name, "type?", &recordType); err != nil {
+		return nil, err
+	}
+	parsed, err := dns.ParseRecordTypeString(recordType)
+	if err != nil {
+		return nil, err
+	}
+	if t.client == nil {
+		return nil, fmt.Errorf("no network client")
+	}
+
+	start := time.Now()
+	result, err := t.client.DNSLookup(threadContext(thread), name, parsed)
+	records := make([]interface{}, 0, len(result.Records))
+	for _, record := range result.Records {
+		records = append(records, record.Value)
+	}
+	return outcome(time.Since(start), err, map[string]interface{}{
+		"name":    name,
+		"type":    dns.GetRecordTypeString(parsed),
+		"records": records,
+		"server":  result.Server,
+	}), nil
+}
+
+func (t *ScriptTool) tcp(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var host string
+	var port int
+	var timeout starlark.Value = starlark.MakeInt(5)
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "host", &host, "port", &port, "timeout?", &timeout); err != nil {
+		return nil, err
+	}
+	if port < 1 || port > 65535 {
+		return nil, fmt.Errorf("port must be an int from 1 to 65535")
+	}
+	wait, err := seconds(timeout)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.enforce(thread, host); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(threadContext(thread), wait)
+	defer cancel()
+
+	start := time.Now()
+	fields := map[string]interface{}{"host": host, "port": port, "address": ""}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return outcome(time.Since(start), err, fields), nil
+		}
+		ip = addrs[0].IP
+	}
+	fields["address"] = net.JoinHostPort(ip.String(), fmt.Sprint(port))
+
+	if connector, ok := t.client.(domain.ConnectivityClient); ok {
+		elapsed, err := connector.TCPConnect(ctx, ip, port)
+		return outcome(elapsed, err, fields), nil
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", fields["address"].(string))
+	elapsed := time.Since(start)
+	if err == nil {
+		conn.Close()
+	}
+	return outcome(elapsed, err, fields), nil
+}
+
+func (t *ScriptTool) http(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var url string
+	var method = "GET"
+	var timeout starlark.Value = starlark.MakeInt(10)
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "url", &url, "method?", &method, "timeout?", &timeout); err != nil {
+		return nil, err
+	}
+	wait, err := seconds(timeout)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(threadContext(thread), wait)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, strings.ToUpper(method), url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.enforce(thread, request.URL.Hostname()); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	fields := map[string]interface{}{"url": url, "status": 0, "headers": map[string]interface{}{}, "body": ""}
+	// Redirects are followed only to targets the policy allows as well
+	client := &http.Client{CheckRedirect: func(next *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return fmt.Errorf("stopped after 10 redirects")
+		}
+		return t.enforce(thread, next.URL.Hostname())
+	}}
+	response, err := client.Do(request)
+	if err != nil {
+		return outcome(time.Since(start), err, fields), nil
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxScriptBody))
+	elapsed := time.Since(start)
+
+	headers := make(map[string]interface{}, len(response.Header))
+	for key := range response.Header {
+		headers[strings.ToLower(key)] = response.Header.Get(key)
+	}
+	fields["status"] = response.StatusCode
+	fields["headers"] = headers
+	fields["body"] = string(body)
+	if err == nil && response.StatusCode >= 400 {
+		err = fmt.Errorf("%s", response.Status)
+	}
+	return outcome(elapsed, err, fields), nil
+}
+
+// fromGo converts nil, bools, numbers, strings, slices and string-keyed maps
+// to Starlark values. Anything else becomes its string form.
+func fromGo(v interface{}) starlark.Value {
+	switch v := v.(type) {
+	case nil:
+		return starlark.None
+	case starlark.Value:
+		return v
+	case bool:
+		return starlark.Bool(v)
+	case int:
+		return starlark.MakeInt(v)
+	case int32:
+		return starlark.MakeInt64(int64(v))
+	case int64:
+		return starlark.MakeInt64(v)
+	case uint32:
+		return starlark.MakeUint(uint(v))
+	case float64:
+		return starlark.Float(v)
+	case float32:
+		return starlark.Float(v)
+	case string:
+		return starlark.String(v)
+	case []string:
+		elems := make([]starlark.Value, len(v))
+		for i, s := range v {
+			elems[i] = starlark.String(s)
+		}
+		return starlark.NewList(elems)
+	case []interface{}:
+		elems := make([]starlark.Value, len(v))
+		for i, elem := range v {
+			elems[i] = fromGo(elem)
+		}
+		return starlark.NewList(elems)
+	case map[string]string:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[key] = value
+		}
+		return fromGo(m)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		d := starlark.NewDict(len(keys))
+		for _, key := range keys {
+			d.SetKey(starlark.String(key), fromGo(v[key]))
+		}
+		return d
+	}
+	return starlark.String(fmt.Sprint(v))
+}
+
+// toGo converts a Starlark value to nil, bool, int64, float64, string,
+// []interface{} or map[string]interface{}, which encode as JSON. Dict keys
+// and ints beyond int64 become their string form.
+func toGo(v starlark.Value) interface{} {
+	switch v := v.(type) {
+	case starlark.NoneType:
+		return nil
+	case starlark.Bool:
+		return bool(v)
+	case starlark.Int:
+		if i, ok := v.Int64(); ok {
+			return i
+		}
+	case starlark.Float:
+		return float64(v)
+	case starlark.String:
+		return string(v)
+	case *starlark.List:
+		s := make([]interface{}, v.Len())
+		for i := range s {
+			s[i] = toGo(v.Index(i))
+		}
+		return s
+	case starlark.Tuple:
+		s := make([]interface{}, len(v))
+		for i, elem := range v {
+			s[i] = toGo(elem)
+		}
+		return s
+	case *starlark.Dict:
+		m := make(map[string]interface{}, v.Len())
+		for _, item := range v.Items() {
+			key, ok := starlark.AsString(item[0])
+			if !ok {
+				key = item[0].String()
+			}
+			m[key] = toGo(item[1])
+		}
+		return m
+	}
+	return v.String()
+}
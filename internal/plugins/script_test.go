@@ -0,0 +1,200 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/network"
+	"github.com/nettracex/nettracex-tui/internal/policy"
+)
+
+const checkScript = `
+description = "Checks that a site resolves, answers and serves its page"
+params = ["host", {"key": "port", "label": "Port", "default": "443"}]
+hints = {"title": "Site check", "format": "table", "columns": ["check", "ok"]}
+
+def validate(params):
+    if "." not in params["host"]:
+        return "host must be a domain name"
+
+def check(params):
+    host = params["host"]
+    rows = []
+    lookup = dns(host)
+    rows.append({"check": "dns", "ok": lookup["ok"], "detail": ", ".join(lookup["records"])})
+    reply = ping(host, count=1)
+    rows.append({"check": "ping", "ok": reply["ok"], "detail": reply["avg_ms"]})
+    conn = tcp("127.0.0.1", int(params["port"]))
+    rows.append({"check": "tcp", "ok": conn["ok"], "detail": conn["address"]})
+    page = http(settings["url"])
+    rows.append({"check": "http", "ok": page["ok"], "detail": page["body"]})
+    print("checked %s with %d probes" % (host, len(rows)))
+    return rows
+`
+
+// writeScript writes a check script called name to dir
+func writeScript(t *testing.T, dir, name, src string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, Prefix+name+ScriptExt), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScriptPlugin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	client := network.NewMockClient()
+	client.SetDNSResponse("example.com", domain.DNSRecordTypeA, domain.DNSResult{
+		Records: []domain.DNSRecord{{Name: "example.com", Type: domain.DNSRecordTypeA, Value: "93.184.216.34"}},
+	})
+	client.SetPingResponse("example.com", []domain.PingResult{{Sequence: 1, RTT: 12 * time.Millisecond}})
+
+	dir := t.TempDir()
+	writeScript(t, dir, "site", checkScript)
+	tools, errs := Load(domain.PluginConfig{
+		PluginPaths:    []string{dir},
+		PluginSettings: map[string]interface{}{"site": map[string]interface{}{"url": server.URL}},
+	}, client, nil, nil)
+	if len(errs) > 0 || len(tools) != 1 {
+		t.Fatalf("Expected one plugin, got %v, %v", tools, errs)
+	}
+
+	tool := tools[0]
+	if tool.Name() != "site" || !strings.HasPrefix(tool.Description(), "Checks that a site") {
+		t.Errorf("Unexpected description %q: %q", tool.Name(), tool.Description())
+	}
+	want := []domain.ParameterSpec{{Key: "host", Required: true}, {Key: "port", Label: "Port", Default: "443"}}
+	if !reflect.DeepEqual(tool.Parameters(), want) {
+		t.Errorf("Parameters() = %+v, want %+v", tool.Parameters(), want)
+	}
+
+	params := domain.NewParameters()
+	params.Set("host", "localhost")
+	if err := tool.Validate(params); err == nil || !strings.Contains(err.Error(), "host must be a domain name") {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	params.Set("host", "example.com")
+	params.Set("port", "8443")
+	result, err := tool.Execute(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	data := result.Data().(domain.PluginResult)
+	if data.Text != "checked example.com with 4 probes" {
+		t.Errorf("Text = %q", data.Text)
+	}
+	if data.Hints == nil || data.Hints.Title != "Site check" || !reflect.DeepEqual(data.Hints.Columns, []string{"check", "ok"}) {
+		t.Errorf("Hints = %+v", data.Hints)
+	}
+	rows, ok := data.Data.([]interface{})
+	if !ok || len(rows) != 4 {
+		t.Fatalf("Data = %#v", data.Data)
+	}
+	details := make([]interface{}, len(rows))
+	for i, row := range rows {
+		row := row.(map[string]interface{})
+		if row["ok"] != true {
+			t.Errorf("Check %v failed: %v", row["check"], row)
+		}
+		details[i] = row["detail"]
+	}
+	if !reflect.DeepEqual(details, []interface{}{"93.184.216.34", 12.0, "127.0.0.1:8443", "hello"}) {
+		t.Errorf("Details = %v", details)
+	}
+}
+
+func TestScriptPluginErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"syntax", "def check(params)\n  return 1\n", "want ':'"},
+		{"no check", "description = 'nothing'\n", "does not define check(params)"},
+		{"bad params", "params = 'host'\ndef check(params):\n  return 1\n", "params must be a list"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeScript(t, dir, "broken", tt.src)
+			_, errs := Load(domain.PluginConfig{PluginPaths: []string{dir}}, network.NewMockClient(), nil, nil)
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), tt.want) {
+				t.Errorf("Load() errors = %v, want one containing %q", errs, tt.want)
+			}
+		})
+	}
+
+	dir := t.TempDir()
+	writeScript(t, dir, "fails", "def check(params):\n  fail('unreachable:', params['host'])\n")
+	tools, errs := Load(domain.PluginConfig{PluginPaths: []string{dir}}, network.NewMockClient(), nil, nil)
+	if len(errs) > 0 || len(tools) != 1 {
+		t.Fatalf("Expected one plugin, got %v, %v", tools, errs)
+	}
+	params := domain.NewParameters()
+	params.Set("host", "example.com")
+	_, err := tools[0].Execute(context.Background(), params)
+	var netErr *domain.NetTraceError
+	if !errors.As(err, &netErr) || netErr.Type != domain.ErrorTypePlugin || !strings.Contains(netErr.Cause.Error(), "fail: unreachable: example.com") {
+		t.Errorf("Execute() error = %v", err)
+	}
+}
+
+func TestScriptPluginStopsAtDeadline(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, "slow", "def check(params):\n  for i in range(1 << 40):\n    pass\n")
+	tools, errs := Load(domain.PluginConfig{PluginPaths: []string{dir}}, network.NewMockClient(), nil, nil)
+	if len(errs) > 0 || len(tools) != 1 {
+		t.Fatalf("Expected one plugin, got %v, %v", tools, errs)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := tools[0].Execute(ctx, domain.NewParameters()); err == nil || !strings.Contains(err.Error(), "deadline") {
+		t.Errorf("Execute() error = %v, want the deadline to stop the script", err)
+	}
+}
+
+func TestScriptPluginTargetPolicy(t *testing.T) {
+	targets, err := policy.NewTargetPolicy(domain.PolicyConfig{PublicTargetMode: policy.ModeBlock}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		call    string
+		blocked bool
+	}{
+		{"ping", `ping("8.8.8.8", count=1)`, true},
+		{"tcp", `tcp("8.8.8.8", 53, timeout=1)`, true},
+		{"http", `http("http://8.8.8.8/")`, true},
+		{"private tcp", `tcp("127.0.0.1", 9, timeout=1)`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeScript(t, dir, "probe", "def check(params):\n  return "+tt.call+"\n")
+			tools, errs := Load(domain.PluginConfig{PluginPaths: []string{dir}}, network.NewMockClient(), targets, nil)
+			if len(errs) > 0 || len(tools) != 1 {
+				t.Fatalf("Expected one plugin, got %v, %v", tools, errs)
+			}
+
+			_, err := tools[0].Execute(context.Background(), domain.NewParameters())
+			if blocked := policy.IsBlocked(err); blocked != tt.blocked {
+				t.Errorf("Execute() error = %v, want blocked %v", err, tt.blocked)
+			}
+		})
+	}
+}
@@ -0,0 +1,326 @@
+package plugins
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os/exec"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/events"
+)
+
+// Tool is a diagnostic tool provided by a plugin executable. The process
+// is started when the plugin is loaded and restarted if it exits.
+type Tool struct {
+	path     string
+	settings map[string]interface{}
+	logger   domain.Logger
+
+	describe DescribeReply
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	client *rpc.Client
+}
+
+// Start runs the plugin executable at path and asks it to describe itself
+func Start(path string, settings map[string]interface{}, logger domain.Logger) (*Tool, error) {
+	t := &Tool{path: path, settings: settings, logger: logger}
+	client, err := t.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	args := DescribeArgs{APIVersion: APIVersion, Settings: settings}
+	if err := call(context.Background(), client, DescribeMethod, &args, &t.describe, 10*time.Second); err != nil {
+		t.Close()
+		return nil, fmt.Errorf("plugin %s did not describe itself: %w", path, err)
+	}
+	if t.describe.Name == "" {
+		t.Close()
+		return nil, fmt.Errorf("plugin %s did not report a tool name", path)
+	}
+	for _, name := range t.describe.Events {
+		if _, ok := events.ParseKind(name); !ok {
+			t.Close()
+			return nil, fmt.Errorf("plugin %s subscribes to unknown event %q", path, name)
+		}
+	}
+	return t, nil
+}
+
+// Name returns the tool name reported by the plugin
+func (t *Tool) Name() string {
+	return t.describe.Name
+}
+
+// Description returns the tool description reported by the plugin
+func (t *Tool) Description() string {
+	return t.describe.Description
+}
+
+// Parameters returns the inputs the plugin asks for
+func (t *Tool) Parameters() []domain.ParameterSpec {
+	return t.describe.Parameters
+}
+
+// Version returns the version reported by the plugin, if any
+func (t *Tool) Version() string {
+	return t.describe.Version
+}
+
+// Path returns the plugin executable
+func (t *Tool) Path() string {
+	return t.path
+}
+
+// Validate checks that every required parameter has a value
+func (t *Tool) Validate(params domain.Parameters) error {
+	return validateRequired(t.Name(), t.describe.Parameters, params)
+}
+
+// Execute sends params to the plugin and wraps its reply in a PluginResult
+func (t *Tool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	if err := t.Validate(params); err != nil {
+		return nil, err
+	}
+
+	args := executeArgs(params)
+	client, err := t.conn()
+	if err != nil {
+		return nil, failed(t.Name(), t.path, err)
+	}
+	var reply ExecuteReply
+	err = call(ctx, client, ExecuteMethod, &args, &reply, 0)
+	if errors.Is(err, rpc.ErrShutdown) || errors.Is(err, io.ErrUnexpectedEOF) {
+		// The plugin exited since the last run; start it once more
+		t.reset(client)
+		if client, err = t.conn(); err == nil {
+			err = call(ctx, client, ExecuteMethod, &args, &reply, 0)
+		}
+	}
+	if err != nil {
+		return nil, failed(t.Name(), t.path, err)
+	}
+	return newResult(t.Name(), t.path, reply, nil), nil
+}
+
+// GetModel returns nil; the TUI builds a form from Parameters
+func (t *Tool) GetModel() tea.Model {
+	return nil
+}
+
+// Check asks the plugin to describe itself again to tell that it still
+// responds, restarting it if it exited
+func (t *Tool) Check(ctx context.Context) error {
+	client, err := t.conn()
+	if err != nil {
+		return err
+	}
+	args := DescribeArgs{APIVersion: APIVersion, Settings: t.settings}
+	var reply DescribeReply
+	err = call(ctx, client, DescribeMethod, &args, &reply, 5*time.Second)
+	if errors.Is(err, rpc.ErrShutdown) || errors.Is(err, io.ErrUnexpectedEOF) {
+		t.reset(client)
+	}
+	return err
+}
+
+// Subscribe sends the plugin the events of the kinds it asked for in
+// Describe. Events are sent without waiting for the plugin to handle them.
+func (t *Tool) Subscribe(bus *events.Bus) func() {
+	if len(t.describe.Events) == 0 {
+		return func() {}
+	}
+	kinds := make([]events.Kind, len(t.describe.Events))
+	for i, name := range t.describe.Events {
+		kinds[i], _ = events.ParseKind(name)
+	}
+	return bus.Subscribe(func(event events.Event) {
+		client, err := t.conn()
+		if err != nil {
+			return
+		}
+		args := newEventArgs(event)
+		client.Go(EventMethod, &args, &struct{}{}, make(chan *rpc.Call, 1))
+	}, kinds...)
+}
+
+// Close stops the plugin process
+func (t *Tool) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stop()
+}
+
+// conn returns the client of the running plugin, starting it if needed
+func (t *Tool) conn() (*rpc.Client, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client != nil {
+		return t.client, nil
+	}
+	return t.startLocked()
+}
+
+// connect starts the plugin for Start
+func (t *Tool) connect() (*rpc.Client, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.startLocked()
+}
+
+// reset stops the plugin if client is still its current connection
+func (t *Tool) reset(client *rpc.Client) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client == client {
+		t.stop()
+	}
+}
+
+// startLocked starts the plugin process; t.mu must be held
+func (t *Tool) startLocked() (*rpc.Client, error) {
+	cmd := exec.Command(t.path)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start plugin %s: %w", t.path, err)
+	}
+	go t.logStderr(stderr)
+
+	t.cmd = cmd
+	t.client = rpc.NewClientWithCodec(jsonrpc.NewClientCodec(stdio{stdout, stdin}))
+	return t.client, nil
+}
+
+// stop closes the connection and kills the process; t.mu must be held
+func (t *Tool) stop() error {
+	if t.cmd == nil {
+		return nil
+	}
+	t.client.Close()
+	t.cmd.Process.Kill()
+	t.cmd.Wait()
+	t.cmd, t.client = nil, nil
+	return nil
+}
+
+// logStderr logs the lines a plugin writes to stderr
+func (t *Tool) logStderr(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if t.logger != nil {
+			t.logger.Info("Plugin output", "plugin", t.path, "line", scanner.Text())
+		}
+	}
+}
+
+// call invokes method and waits for the reply, ctx or the timeout, if any
+func call(ctx context.Context, client *rpc.Client, method string, args, reply interface{}, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	pending := client.Go(method, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-pending.Done:
+		return pending.Error
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// validateRequired checks that params has a value for every required
+// parameter in specs
+func validateRequired(tool string, specs []domain.ParameterSpec, params domain.Parameters) error {
+	for _, spec := range specs {
+		if spec.Required && fmt.Sprint(valueOrEmpty(params.Get(spec.Key))) == "" {
+			label := spec.Label
+			if label == "" {
+				label = spec.Key
+			}
+			return &domain.NetTraceError{
+				Type:      domain.ErrorTypeValidation,
+				Message:   fmt.Sprintf("%s is required", label),
+				Context:   map[string]interface{}{"tool": tool, "parameter": spec.Key},
+				Timestamp: time.Now(),
+				Code:      "PLUGIN_PARAMETER_REQUIRED",
+			}
+		}
+	}
+	return nil
+}
+
+// executeArgs passes params to a plugin as strings
+func executeArgs(params domain.Parameters) ExecuteArgs {
+	args := ExecuteArgs{Params: make(map[string]string)}
+	for key, value := range params.ToMap() {
+		args.Params[key] = fmt.Sprint(valueOrEmpty(value))
+	}
+	return args
+}
+
+// newResult wraps the reply of a plugin in a PluginResult. hints apply when
+// the reply has none of its own.
+func newResult(tool, path string, reply ExecuteReply, hints *domain.RenderHints) domain.Result {
+	if reply.Hints != nil {
+		hints = reply.Hints
+	}
+	result := domain.NewResult(domain.PluginResult{
+		Tool:      tool,
+		Text:      reply.Text,
+		Data:      reply.Data,
+		Hints:     hints,
+		Timestamp: time.Now(),
+	})
+	for key, value := range reply.Metadata {
+		result.SetMetadata(key, value)
+	}
+	result.SetMetadata("tool", tool)
+	result.SetMetadata("plugin", path)
+	result.SetMetadata("timestamp", time.Now())
+	return result
+}
+
+// failed wraps an error of a plugin
+func failed(tool, path string, err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return &domain.NetTraceError{
+		Type:      domain.ErrorTypePlugin,
+		Message:   fmt.Sprintf("Plugin %s failed", tool),
+		Cause:     err,
+		Context:   map[string]interface{}{"plugin": path},
+		Timestamp: time.Now(),
+		Code:      "PLUGIN_EXECUTION_FAILED",
+	}
+}
+
+// valueOrEmpty turns a missing parameter into an empty string
+func valueOrEmpty(value interface{}) interface{} {
+	if value == nil {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,325 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// WASMExt is the file extension of WebAssembly plugins
+const WASMExt = ".wasm"
+
+// Functions of the WebAssembly plugin ABI. A module exports its memory and
+//
+//	nettracex_alloc(size i32) -> i32
+//	nettracex_describe(ptr i32, len i32) -> i64
+//	nettracex_execute(ptr i32, len i32) -> i64
+//
+// and optionally
+//
+//	nettracex_validate(ptr i32, len i32) -> i64
+//	nettracex_render_hints() -> i64
+//
+// The host writes a JSON request into memory it obtained from
+// nettracex_alloc and passes its address and length; describe takes
+// DescribeArgs and execute and validate take ExecuteArgs. Each function
+// answers with JSON in its memory, returned as the address in the upper and
+// the length in the lower 32 bits: DescribeReply, ExecuteReply, a
+// domain.RenderHints object, and for validate an error message, or 0 when
+// the parameters are valid.
+//
+// Modules have no access to files, processes or sockets. They may import
+// these functions from the "nettracex" module:
+//
+//	log(ptr i32, len i32)
+//	lookup_host(ptr i32, len i32, out i32, cap i32) -> i32
+//
+// log writes a message to the NetTraceX log. lookup_host resolves the host
+// name at ptr and writes its addresses, separated by commas, to out; it
+// returns their full length, writing at most cap bytes, or -1 when the
+// lookup fails.
+const (
+	wasmAlloc       = "nettracex_alloc"
+	wasmDescribe    = "nettracex_describe"
+	wasmExecute     = "nettracex_execute"
+	wasmValidate    = "nettracex_validate"
+	wasmRenderHints = "nettracex_render_hints"
+	wasmHostModule  = "nettracex"
+)
+
+// wasmTimeout bounds a call into a module when the caller set no deadline
+const wasmTimeout = 30 * time.Second
+
+// wasmMaxPages caps the memory of a module at 16 MiB, whatever it declares
+const wasmMaxPages = 256
+
+// WASMTool is a diagnostic tool provided by a WebAssembly module. Every
+// call runs in a fresh instance, so runs share no state.
+type WASMTool struct {
+	path     string
+	logger   domain.Logger
+	runtime  wazero.Runtime
+	module   wazero.CompiledModule
+	describe DescribeReply
+	hints    *domain.RenderHints
+}
+
+// StartWASM loads the module at path and asks it to describe itself
+func StartWASM(path string, settings map[string]interface{}, logger domain.Logger) (*WASMTool, error) {
+	binary, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin %s: %w", path, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	t := &WASMTool{path: path, logger: logger, runtime: newWASMRuntime(ctx)}
+	if err := t.start(ctx, binary, settings); err != nil {
+		t.runtime.Close(ctx)
+		return nil, err
+	}
+	return t, nil
+}
+
+// newWASMRuntime returns a runtime that bounds the memory of modules and
+// stops them when the context of a call is done
+func newWASMRuntime(ctx context.Context) wazero.Runtime {
+	return wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(wasmMaxPages).
+		WithCloseOnContextDone(true))
+}
+
+// start compiles the module, provides the host functions and asks the
+// module to describe itself
+func (t *WASMTool) start(ctx context.Context, binary []byte, settings map[string]interface{}) error {
+	module, err := t.runtime.CompileModule(ctx, binary)
+	if err != nil {
+		return fmt.Errorf("plugin %s: %w", t.path, err)
+	}
+	t.module = module
+	for _, name := range []string{wasmAlloc, wasmDescribe, wasmExecute} {
+		if !t.exports(name) {
+			return fmt.Errorf("plugin %s does not export %s", t.path, name)
+		}
+	}
+	if err := t.instantiateHost(ctx); err != nil {
+		return fmt.Errorf("plugin %s: %w", t.path, err)
+	}
+
+	reply, err := t.call(ctx, wasmDescribe, DescribeArgs{APIVersion: APIVersion, Settings: settings})
+	if err != nil {
+		return fmt.Errorf("plugin %s did not describe itself: %w", t.path, err)
+	}
+	if err := json.Unmarshal(reply, &t.describe); err != nil {
+		return fmt.Errorf("plugin %s sent an invalid description: %w", t.path, err)
+	}
+	if t.describe.Name == "" {
+		return fmt.Errorf("plugin %s did not report a tool name", t.path)
+	}
+
+	if t.exports(wasmRenderHints) {
+		reply, err := t.call(ctx, wasmRenderHints, nil)
+		if err == nil && len(reply) > 0 {
+			var hints domain.RenderHints
+			if err := json.Unmarshal(reply, &hints); err != nil {
+				return fmt.Errorf("plugin %s sent invalid render hints: %w", t.path, err)
+			}
+			t.hints = &hints
+		}
+	}
+	return nil
+}
+
+// exports reports whether the module exports the function name
+func (t *WASMTool) exports(name string) bool {
+	_, ok := t.module.ExportedFunctions()[name]
+	return ok
+}
+
+// Name returns the tool name reported by the module
+func (t *WASMTool) Name() string {
+	return t.describe.Name
+}
+
+// Description returns the tool description reported by the module
+func (t *WASMTool) Description() string {
+	return t.describe.Description
+}
+
+// Parameters returns the inputs the module asks for
+func (t *WASMTool) Parameters() []domain.ParameterSpec {
+	return t.describe.Parameters
+}
+
+// Version returns the version reported by the module, if any
+func (t *WASMTool) Version() string {
+	return t.describe.Version
+}
+
+// Path returns the module file
+func (t *WASMTool) Path() string {
+	return t.path
+}
+
+// Validate checks the required parameters and asks the module to validate
+// the rest, when it exports nettracex_validate
+func (t *WASMTool) Validate(params domain.Parameters) error {
+	if err := validateRequired(t.Name(), t.describe.Parameters, params); err != nil {
+		return err
+	}
+	if !t.exports(wasmValidate) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), wasmTimeout)
+	defer cancel()
+	reply, err := t.call(ctx, wasmValidate, executeArgs(params))
+	if err != nil {
+		return failed(t.Name(), t.path, err)
+	}
+	if len(reply) > 0 {
+		return &domain.NetTraceError{
+			Type:      domain.ErrorTypeValidation,
+			Message:   string(reply),
+			Context:   map[string]interface{}{"tool": t.Name()},
+			Timestamp: time.Now(),
+			Code:      "PLUGIN_VALIDATION_FAILED",
+		}
+	}
+	return nil
+}
+
+// Execute runs the module with params and wraps its reply in a
+// PluginResult
+func (t *WASMTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	if err := t.Validate(params); err != nil {
+		return nil, err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, wasmTimeout)
+		defer cancel()
+	}
+
+	reply, err := t.call(ctx, wasmExecute, executeArgs(params))
+	if err != nil {
+		return nil, failed(t.Name(), t.path, err)
+	}
+	var decoded ExecuteReply
+	if err := json.Unmarshal(reply, &decoded); err != nil {
+		return nil, failed(t.Name(), t.path, fmt.Errorf("invalid reply: %w", err))
+	}
+	return newResult(t.Name(), t.path, decoded, t.hints), nil
+}
+
+// GetModel returns nil; the TUI builds a form from Parameters
+func (t *WASMTool) GetModel() tea.Model {
+	return nil
+}
+
+// Close releases the compiled module and its runtime
+func (t *WASMTool) Close() error {
+	return t.runtime.Close(context.Background())
+}
+
+// call instantiates the module, passes request as JSON to fn and returns
+// the bytes fn answers with. A nil request calls fn without arguments.
+func (t *WASMTool) call(ctx context.Context, fn string, request interface{}) ([]byte, error) {
+	// An empty name lets calls instantiate the module concurrently
+	inst, err := t.runtime.InstantiateModule(ctx, t.module, wazero.NewModuleConfig().WithName(""))
+	if err != nil {
+		return nil, err
+	}
+	defer inst.Close(ctx)
+	memory := inst.Memory()
+	if memory == nil {
+		return nil, errors.New("module has no memory")
+	}
+
+	var args []uint64
+	if request != nil {
+		input, err := json.Marshal(request)
+		if err != nil {
+			return nil, err
+		}
+		allocated, err := inst.ExportedFunction(wasmAlloc).Call(ctx, uint64(len(input)))
+		if err != nil {
+			return nil, fmt.Errorf("%s failed: %w", wasmAlloc, err)
+		}
+		if len(allocated) != 1 {
+			return nil, fmt.Errorf("%s must return one i32", wasmAlloc)
+		}
+		ptr := uint32(allocated[0])
+		if !memory.Write(ptr, input) {
+			return nil, fmt.Errorf("%s returned memory out of bounds", wasmAlloc)
+		}
+		args = []uint64{uint64(ptr), uint64(len(input))}
+	}
+	results, err := inst.ExportedFunction(fn).Call(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) != 1 {
+		return nil, fmt.Errorf("%s must return one i64", fn)
+	}
+
+	packed := results[0]
+	if packed == 0 {
+		return nil, nil
+	}
+	reply, ok := memory.Read(uint32(packed>>32), uint32(packed))
+	if !ok {
+		return nil, fmt.Errorf("%s returned memory out of bounds", fn)
+	}
+	// Read returns a view of memory, which closes with the instance
+	return append([]byte(nil), reply...), nil
+}
+
+// instantiateHost provides the host functions modules may use. A failing
+// host function panics, which aborts the call with that error.
+func (t *WASMTool) instantiateHost(ctx context.Context) error {
+	_, err := t.runtime.NewHostModuleBuilder(wasmHostModule).
+		NewFunctionBuilder().
+		WithFunc(func(ctx context.Context, mod api.Module, ptr, size uint32) {
+			message, ok := mod.Memory().Read(ptr, size)
+			if !ok {
+				panic(errors.New("log message out of bounds"))
+			}
+			if t.logger != nil {
+				t.logger.Info("Plugin output", "plugin", t.path, "line", string(message))
+			}
+		}).
+		Export("log").
+		NewFunctionBuilder().
+		WithFunc(func(ctx context.Context, mod api.Module, ptr, size, out, capacity uint32) int32 {
+			host, ok := mod.Memory().Read(ptr, size)
+			if !ok {
+				panic(errors.New("host name out of bounds"))
+			}
+			addrs, err := net.DefaultResolver.LookupHost(ctx, string(host))
+			if err != nil {
+				return -1
+			}
+			joined := []byte(strings.Join(addrs, ","))
+			written := joined
+			if uint32(len(written)) > capacity {
+				written = written[:capacity]
+			}
+			if !mod.Memory().Write(out, written) {
+				panic(errors.New("lookup buffer out of bounds"))
+			}
+			return int32(len(joined))
+		}).
+		Export("lookup_host").
+		Instantiate(ctx)
+	return err
+}
@@ -0,0 +1,192 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// Replies of the test module, placed in its memory by data segments
+const (
+	describeJSON = `{"name":"subnet","description":"Subnet calculator","parameters":[{"key":"cidr","label":"CIDR","required":true}]}`
+	executeJSON  = `{"text":"256 addresses","data":[{"network":"10.0.0.0","size":256}]}`
+	hintsJSON    = `{"title":"Subnet","format":"table","columns":["network","size"]}`
+)
+
+// wasmPlugin assembles a module implementing the plugin ABI. Its replies
+// are fixed; execute logs its request so tests can check what was sent.
+func wasmPlugin() []byte {
+	packed := func(offset int64, text string) []byte {
+		return append([]byte{0x42}, sleb(offset<<32|int64(len(text)))...)
+	}
+	types := [][]byte{
+		{0x60, 2, 0x7f, 0x7f, 0},       // (i32, i32)
+		{0x60, 1, 0x7f, 1, 0x7f},       // (i32) -> i32
+		{0x60, 2, 0x7f, 0x7f, 1, 0x7e}, // (i32, i32) -> i64
+		{0x60, 0, 1, 0x7e},             // () -> i64
+	}
+	imports := [][]byte{append(append(name("nettracex"), name("log")...), 0x00, 0)}
+	funcs := [][]byte{{1}, {2}, {2}, {2}, {3}}
+	globals := [][]byte{append([]byte{0x7f, 1, 0x41}, append(sleb(8192), 0x0b)...)}
+	exports := [][]byte{
+		append(name("memory"), 2, 0),
+		append(name(wasmAlloc), 0, 1),
+		append(name(wasmDescribe), 0, 2),
+		append(name(wasmExecute), 0, 3),
+		append(name(wasmValidate), 0, 4),
+		append(name(wasmRenderHints), 0, 5),
+	}
+	codes := [][]byte{
+		{0, 0x23, 0, 0x23, 0, 0x20, 0, 0x6a, 0x24, 0, 0x0b}, // return heap, heap += size
+		append(append([]byte{0}, packed(1024, describeJSON)...), 0x0b),
+		append(append([]byte{0, 0x20, 0, 0x20, 1, 0x10, 0}, packed(2048, executeJSON)...), 0x0b),
+		{0, 0x42, 0, 0x0b},
+		append(append([]byte{0}, packed(3072, hintsJSON)...), 0x0b),
+	}
+	var bodies [][]byte
+	for _, c := range codes {
+		bodies = append(bodies, append(uleb(len(c)), c...))
+	}
+	segment := func(offset int64, text string) []byte {
+		return append(append([]byte{0, 0x41}, append(sleb(offset), 0x0b)...), name(text)...)
+	}
+	data := [][]byte{segment(1024, describeJSON), segment(2048, executeJSON), segment(3072, hintsJSON)}
+
+	out := []byte("\x00asm\x01\x00\x00\x00")
+	for _, s := range []struct {
+		id    byte
+		items [][]byte
+	}{
+		{1, types}, {2, imports}, {3, funcs}, {5, [][]byte{{0, 1}}}, {6, globals},
+		{7, exports}, {10, bodies}, {11, data},
+	} {
+		contents := uleb(len(s.items))
+		for _, item := range s.items {
+			contents = append(contents, item...)
+		}
+		out = append(append(append(out, s.id), uleb(len(contents))...), contents...)
+	}
+	return out
+}
+
+func name(s string) []byte {
+	return append(uleb(len(s)), s...)
+}
+
+func uleb(v int) []byte {
+	var out []byte
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			return append(out, c)
+		}
+		out = append(out, c|0x80)
+	}
+}
+
+func sleb(v int64) []byte {
+	var out []byte
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && c&0x40 == 0) || (v == -1 && c&0x40 != 0) {
+			return append(out, c)
+		}
+		out = append(out, c|0x80)
+	}
+}
+
+// recordingLogger keeps the lines plugins log
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *recordingLogger) Debug(msg string, fields ...interface{}) {}
+func (l *recordingLogger) Warn(msg string, fields ...interface{})  {}
+func (l *recordingLogger) Error(msg string, fields ...interface{}) {}
+func (l *recordingLogger) Fatal(msg string, fields ...interface{}) {}
+func (l *recordingLogger) Info(msg string, fields ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprint(fields...))
+}
+
+func TestWASMPlugin(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, Prefix+"subnet"+WASMExt), wasmPlugin(), 0644); err != nil {
+		t.Fatalf("Failed to write module: %v", err)
+	}
+	logger := &recordingLogger{}
+	tools, errs := Load(domain.PluginConfig{PluginPaths: []string{dir}}, nil, nil, logger)
+	defer Close(tools)
+	if len(errs) > 0 || len(tools) != 1 {
+		t.Fatalf("Expected one plugin, got %v, %v", tools, errs)
+	}
+
+	tool := tools[0]
+	if tool.Name() != "subnet" || len(tool.Parameters()) != 1 {
+		t.Errorf("Unexpected description %q: %v", tool.Name(), tool.Parameters())
+	}
+	if err := tool.Validate(domain.NewParameters()); err == nil {
+		t.Error("Expected the required parameter to be validated")
+	}
+
+	params := domain.NewParameters()
+	params.Set("cidr", "10.0.0.0/24")
+	result, err := tool.Execute(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	data := result.Data().(domain.PluginResult)
+	if data.Text != "256 addresses" || data.Hints == nil || data.Hints.Format != "table" {
+		t.Errorf("Unexpected result %#v", data)
+	}
+	logged := strings.Join(logger.lines, "\n")
+	if !strings.Contains(logged, `{"params":{"cidr":"10.0.0.0/24"}}`) {
+		t.Errorf("Expected the module to receive the parameters, logged %q", logged)
+	}
+}
+
+func TestWASMPluginStopsAtDeadline(t *testing.T) {
+	// Exports a memory and spin() -> i64, which loops forever
+	binary := []byte("\x00asm\x01\x00\x00\x00")
+	binary = append(binary, 1, 5, 1, 0x60, 0, 1, 0x7e)
+	binary = append(binary, 3, 2, 1, 0)
+	binary = append(binary, 5, 3, 1, 0, 1)
+	exports := append(append([]byte{2}, append(name("memory"), 2, 0)...), append(name("spin"), 0, 0)...)
+	binary = append(append(append(binary, 7), uleb(len(exports))...), exports...)
+	body := []byte{0, 0x03, 0x40, 0x0c, 0, 0x0b, 0x42, 0, 0x0b}
+	binary = append(append(binary, 10, byte(len(body)+2), 1, byte(len(body))), body...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	tool := &WASMTool{runtime: newWASMRuntime(ctx)}
+	defer tool.Close()
+	module, err := tool.runtime.CompileModule(ctx, binary)
+	if err != nil {
+		t.Fatalf("Failed to compile module: %v", err)
+	}
+	tool.module = module
+
+	if _, err := tool.call(ctx, "spin", nil); err == nil {
+		t.Error("Expected the call to stop at the deadline")
+	}
+}
+
+func TestWASMPluginRejectsInvalidModules(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, Prefix+"broken"+WASMExt)
+	os.WriteFile(path, []byte("\x00asm\x01\x00\x00\x00"), 0644)
+	if _, err := StartWASM(path, nil, nil); err == nil || !strings.Contains(err.Error(), wasmAlloc) {
+		t.Errorf("Expected a missing export error, got %v", err)
+	}
+}
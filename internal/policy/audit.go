@@ -0,0 +1,122 @@
+// Package policy provides the audit log for policy decisions
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/appdir"
+)
+
+// Audit event names
+const (
+	AuditEventConsent = "consent_acknowledged"
+	AuditEventBlocked = "target_blocked"
+)
+
+// AuditEntry is a single line in the audit log
+type AuditEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Event     string    `json:"event"`
+	Tool      string    `json:"tool"`
+	Target    string    `json:"target"`
+	Addresses []string  `json:"addresses,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	User      string    `json:"user,omitempty"`
+}
+
+// AuditLog appends policy decisions to a JSON lines file
+type AuditLog struct {
+	mu   sync.Mutex
+	path string
+	now  func() time.Time
+}
+
+// NewAuditLog creates an audit log writing to path, or the default location if empty
+func NewAuditLog(path string) *AuditLog {
+	if path == "" {
+		path = DefaultAuditLogPath()
+	}
+	return &AuditLog{
+		path: path,
+		now:  time.Now,
+	}
+}
+
+// DefaultAuditLogPath returns the default audit log location
+func DefaultAuditLogPath() string {
+	return appdir.ConfigPath("audit.log")
+}
+
+// Path returns the audit log file path
+func (a *AuditLog) Path() string {
+	return a.path
+}
+
+// Record appends an entry to the audit log
+func (a *AuditLog) Record(entry AuditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = a.now()
+	}
+	if entry.User == "" {
+		if current, err := user.Current(); err == nil {
+			entry.User = current.Username
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(a.path), 0755); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(a.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// Entries reads all entries from the audit log
+func (a *AuditLog) Entries() ([]AuditEntry, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	data, err := os.ReadFile(a.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	var entries []AuditEntry
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for decoder.More() {
+		var entry AuditEntry
+		if err := decoder.Decode(&entry); err != nil {
+			return entries, fmt.Errorf("failed to decode audit entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,93 @@
+// Package policy provides a diagnostic tool wrapper that enforces target policy
+package policy
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// GuardedTool wraps a diagnostic tool and checks its target against the policy before execution
+type GuardedTool struct {
+	domain.DiagnosticTool
+	policy *TargetPolicy
+}
+
+// NewGuardedTool wraps tool with the given policy
+func NewGuardedTool(tool domain.DiagnosticTool, policy *TargetPolicy) *GuardedTool {
+	return &GuardedTool{
+		DiagnosticTool: tool,
+		policy:         policy,
+	}
+}
+
+// Guard wraps tool when it is subject to the policy, otherwise returns it unchanged
+func (p *TargetPolicy) Guard(tool domain.DiagnosticTool) domain.DiagnosticTool {
+	if !p.IsActiveTool(tool.Name()) {
+		return tool
+	}
+	return NewGuardedTool(tool, p)
+}
+
+// Unwrap returns the wrapped diagnostic tool
+func (g *GuardedTool) Unwrap() domain.DiagnosticTool {
+	return g.DiagnosticTool
+}
+
+// Execute enforces the policy and then runs the wrapped tool
+func (g *GuardedTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	if err := g.enforce(ctx, params); err != nil {
+		return nil, err
+	}
+	return g.DiagnosticTool.Execute(ctx, params)
+}
+
+// LiveModel returns the live screen of the wrapped tool once the policy
+// allows its targets, as Execute does. The screen probes nothing before it
+// is shown and only the targets checked here after: it hands new queries
+// back to the screen that opened it.
+func (g *GuardedTool) LiveModel(ctx context.Context, params domain.Parameters) (tea.Model, error) {
+	model, err := domain.LiveModel(ctx, g.DiagnosticTool, params)
+	if err != nil || model == nil {
+		return nil, err
+	}
+	if err := g.enforce(ctx, params); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// GetModel returns no model, since the wrapped tool's own screen starts
+// probes against any target typed into it; LiveModel offers its live
+// screen for checked targets instead
+func (g *GuardedTool) GetModel() tea.Model {
+	return nil
+}
+
+// enforce validates params and checks every target they probe
+func (g *GuardedTool) enforce(ctx context.Context, params domain.Parameters) error {
+	// Validation normalises parameters, expanding host lists into "targets",
+	// so it runs before the targets are checked
+	if err := g.DiagnosticTool.Validate(params); err != nil {
+		return err
+	}
+	acknowledged, _ := params.Get(AcknowledgeParam).(bool)
+	for _, target := range targetsFromParams(params) {
+		if err := g.policy.Enforce(ctx, g.Name(), target, acknowledged); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// targetsFromParams extracts the probe targets from tool parameters
+func targetsFromParams(params domain.Parameters) []string {
+	if targets, ok := params.Get("targets").([]string); ok && len(targets) > 0 {
+		return targets
+	}
+	if target := domain.TargetParam(params); target != "" {
+		return []string{target}
+	}
+	return nil
+}
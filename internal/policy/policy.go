@@ -0,0 +1,400 @@
+// Package policy enforces safety policies for active network diagnostic tools
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// Public target modes
+const (
+	ModeOff   = "off"
+	ModeWarn  = "warn"
+	ModeBlock = "block"
+)
+
+// AcknowledgeParam is the parameter key used to confirm consent for a public target
+const AcknowledgeParam = "acknowledge_public_target"
+
+// Error codes returned by policy checks
+const (
+	CodeConsentRequired = "POLICY_CONSENT_REQUIRED"
+	CodeTargetBlocked   = "POLICY_TARGET_BLOCKED"
+	CodeAuditFailed     = "POLICY_AUDIT_FAILED"
+)
+
+// Action represents the outcome of a policy evaluation
+type Action int
+
+const (
+	ActionAllow Action = iota
+	ActionWarn
+	ActionBlock
+)
+
+// String returns a human-readable action name
+func (a Action) String() string {
+	switch a {
+	case ActionAllow:
+		return "allow"
+	case ActionWarn:
+		return "warn"
+	case ActionBlock:
+		return "block"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(a))
+	}
+}
+
+// Decision describes the result of evaluating a target against the policy
+type Decision struct {
+	Action          Action
+	Tool            string
+	Target          string
+	PublicAddresses []net.IP
+	Reason          string
+}
+
+// Resolver resolves a host name to its IP addresses
+type Resolver func(ctx context.Context, host string) ([]net.IP, error)
+
+// TargetPolicy evaluates whether active tools may probe a target
+type TargetPolicy struct {
+	mode        string
+	allowNets   []*net.IPNet
+	allowHosts  map[string]bool
+	activeTools map[string]bool
+	resolver    Resolver
+	audit       *AuditLog
+}
+
+// NewTargetPolicy creates a target policy from configuration
+func NewTargetPolicy(config domain.PolicyConfig, audit *AuditLog) (*TargetPolicy, error) {
+	mode := strings.ToLower(strings.TrimSpace(config.PublicTargetMode))
+	if mode == "" {
+		mode = ModeWarn
+	}
+	if mode != ModeOff && mode != ModeWarn && mode != ModeBlock {
+		return nil, fmt.Errorf("invalid public target mode: %s", config.PublicTargetMode)
+	}
+
+	p := &TargetPolicy{
+		mode:        mode,
+		allowHosts:  make(map[string]bool),
+		activeTools: make(map[string]bool),
+		resolver:    defaultResolver,
+		audit:       audit,
+	}
+
+	for _, entry := range config.AllowList {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			_, network, err := net.ParseCIDR(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid allow list CIDR %q: %w", entry, err)
+			}
+			p.allowNets = append(p.allowNets, network)
+			continue
+		}
+		if ip := net.ParseIP(entry); ip != nil {
+			p.allowNets = append(p.allowNets, singleHostNet(ip))
+			continue
+		}
+		p.allowHosts[normalizeHost(entry)] = true
+	}
+
+	for _, tool := range config.ActiveTools {
+		p.activeTools[strings.TrimSpace(tool)] = true
+	}
+
+	return p, nil
+}
+
+// SetResolver replaces the resolver used to look up target addresses
+func (p *TargetPolicy) SetResolver(resolver Resolver) {
+	p.resolver = resolver
+}
+
+// Mode returns the configured public target mode
+func (p *TargetPolicy) Mode() string {
+	return p.mode
+}
+
+// AuditLog returns the audit log used to record consent
+func (p *TargetPolicy) AuditLog() *AuditLog {
+	return p.audit
+}
+
+// IsActiveTool reports whether a tool generates traffic subject to the policy
+func (p *TargetPolicy) IsActiveTool(name string) bool {
+	return p.activeTools[name]
+}
+
+// Evaluate checks a target for the given tool and returns the policy decision
+func (p *TargetPolicy) Evaluate(ctx context.Context, tool, target string) (Decision, error) {
+	decision := Decision{
+		Action: ActionAllow,
+		Tool:   tool,
+		Target: target,
+	}
+
+	if !p.IsActiveTool(tool) {
+		decision.Reason = "policy not applicable"
+		return decision, nil
+	}
+	return p.evaluate(ctx, decision)
+}
+
+// evaluate checks the target of decision against the mode and allow list
+func (p *TargetPolicy) evaluate(ctx context.Context, decision Decision) (Decision, error) {
+	target := decision.Target
+	if p.mode == ModeOff {
+		decision.Reason = "policy not applicable"
+		return decision, nil
+	}
+
+	host := normalizeHost(target)
+	if p.allowHosts[host] {
+		decision.Reason = "target is in allow list"
+		return decision, nil
+	}
+
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	} else {
+		resolved, err := p.resolver(ctx, host)
+		if err != nil {
+			// A target that cannot be checked is treated as public
+			decision.Reason = fmt.Sprintf("%s could not be resolved to check it against the allow list", target)
+			decision.Action = p.restrictedAction()
+			return decision, nil
+		}
+		ips = resolved
+	}
+
+	for _, ip := range ips {
+		if IsPublicIP(ip) && !p.isAllowed(ip) {
+			decision.PublicAddresses = append(decision.PublicAddresses, ip)
+		}
+	}
+
+	if len(decision.PublicAddresses) == 0 {
+		decision.Reason = "target is private or allow-listed"
+		return decision, nil
+	}
+
+	decision.Reason = fmt.Sprintf("%s resolves to public address %s outside the allow list", target, decision.PublicAddresses[0])
+	decision.Action = p.restrictedAction()
+	return decision, nil
+}
+
+// restrictedAction returns the action of the mode for a target that is not
+// known to be private
+func (p *TargetPolicy) restrictedAction() Action {
+	if p.mode == ModeBlock {
+		return ActionBlock
+	}
+	return ActionWarn
+}
+
+// Enforce evaluates a target and converts warn/block decisions into errors.
+// Warn decisions pass once the caller has acknowledged consent, which is
+// recorded in the audit log; a consent that cannot be recorded refuses the
+// probe.
+func (p *TargetPolicy) Enforce(ctx context.Context, tool, target string, acknowledged bool) error {
+	decision, err := p.Evaluate(ctx, tool, target)
+	if err != nil {
+		return err
+	}
+	return p.enforce(decision, acknowledged)
+}
+
+// EnforceProbe is Enforce for traffic that is active whichever tool sends
+// it, such as the network primitives of check scripts
+func (p *TargetPolicy) EnforceProbe(ctx context.Context, tool, target string, acknowledged bool) error {
+	decision, err := p.evaluate(ctx, Decision{Action: ActionAllow, Tool: tool, Target: target})
+	if err != nil {
+		return err
+	}
+	return p.enforce(decision, acknowledged)
+}
+
+// enforce converts a warn or block decision into an error
+func (p *TargetPolicy) enforce(decision Decision, acknowledged bool) error {
+	switch decision.Action {
+	case ActionBlock:
+		blocked := newPolicyError(decision, CodeTargetBlocked, "scanning public targets is blocked by policy")
+		if err := p.record(AuditEventBlocked, decision); err != nil {
+			blocked.Cause = err
+		}
+		return blocked
+	case ActionWarn:
+		if !acknowledged {
+			message := "target is public; acknowledgement required before probing third-party networks"
+			if len(decision.PublicAddresses) == 0 {
+				message = "target could not be resolved; acknowledgement required before probing it"
+			}
+			return newPolicyError(decision, CodeConsentRequired, message)
+		}
+		if err := p.record(AuditEventConsent, decision); err != nil {
+			auditErr := newPolicyError(decision, CodeAuditFailed, "consent could not be recorded in the audit log; refusing to probe public target")
+			auditErr.Type = domain.ErrorTypeSystem
+			auditErr.Cause = err
+			return auditErr
+		}
+	}
+
+	return nil
+}
+
+// record writes a policy decision to the audit log if one is configured
+func (p *TargetPolicy) record(event string, decision Decision) error {
+	if p.audit == nil {
+		return nil
+	}
+
+	addresses := make([]string, 0, len(decision.PublicAddresses))
+	for _, ip := range decision.PublicAddresses {
+		addresses = append(addresses, ip.String())
+	}
+
+	return p.audit.Record(AuditEntry{
+		Event:     event,
+		Tool:      decision.Tool,
+		Target:    decision.Target,
+		Addresses: addresses,
+		Reason:    decision.Reason,
+	})
+}
+
+// isAllowed reports whether an address is covered by the allow list
+func (p *TargetPolicy) isAllowed(ip net.IP) bool {
+	for _, network := range p.allowNets {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// newPolicyError builds a NetTraceError describing a policy decision
+func newPolicyError(decision Decision, code, message string) *domain.NetTraceError {
+	addresses := make([]string, 0, len(decision.PublicAddresses))
+	for _, ip := range decision.PublicAddresses {
+		addresses = append(addresses, ip.String())
+	}
+
+	return &domain.NetTraceError{
+		Type:    domain.ErrorTypeValidation,
+		Message: message,
+		Context: map[string]interface{}{
+			"tool":      decision.Tool,
+			"target":    decision.Target,
+			"addresses": addresses,
+			"reason":    decision.Reason,
+		},
+		Timestamp: time.Now(),
+		Code:      code,
+	}
+}
+
+// IsConsentRequired reports whether an error asks the user to acknowledge a public target
+func IsConsentRequired(err error) bool {
+	return hasCode(err, CodeConsentRequired)
+}
+
+// IsBlocked reports whether an error was caused by a blocked public target
+func IsBlocked(err error) bool {
+	return hasCode(err, CodeTargetBlocked)
+}
+
+// hasCode checks whether err wraps a NetTraceError with the given code
+func hasCode(err error, code string) bool {
+	var netErr *domain.NetTraceError
+	for errors.As(err, &netErr) {
+		if netErr.Code == code {
+			return true
+		}
+		if netErr.Cause == nil {
+			return false
+		}
+		err = netErr.Cause
+		netErr = nil
+	}
+	return false
+}
+
+// IsPublicIP reports whether an address is globally routable public space
+func IsPublicIP(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range reservedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// reservedNetworks lists special-purpose ranges that are not public internet space
+var reservedNetworks = func() []*net.IPNet {
+	cidrs := []string{
+		"0.0.0.0/8",       // "this" network
+		"100.64.0.0/10",   // carrier-grade NAT
+		"192.0.0.0/24",    // IETF protocol assignments
+		"192.0.2.0/24",    // TEST-NET-1
+		"198.18.0.0/15",   // benchmarking
+		"198.51.100.0/24", // TEST-NET-2
+		"203.0.113.0/24",  // TEST-NET-3
+		"240.0.0.0/4",     // reserved
+		"2001:db8::/32",   // documentation
+	}
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, _ := net.ParseCIDR(cidr)
+		networks = append(networks, network)
+	}
+	return networks
+}()
+
+// singleHostNet returns a network containing exactly one address
+func singleHostNet(ip net.IP) *net.IPNet {
+	if v4 := ip.To4(); v4 != nil {
+		return &net.IPNet{IP: v4, Mask: net.CIDRMask(32, 32)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
+
+// normalizeHost lowercases a host and strips any trailing dot
+func normalizeHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+}
+
+// defaultResolver resolves hosts using the system resolver
+func defaultResolver(ctx context.Context, host string) ([]net.IP, error) {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	ips := make([]net.IP, 0, len(addrs))
+	for _, addr := range addrs {
+		ips = append(ips, addr.IP)
+	}
+	return ips, nil
+}
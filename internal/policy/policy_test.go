@@ -0,0 +1,380 @@
+// Package policy provides tests for target policy enforcement
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// staticResolver returns a resolver that maps hosts to fixed addresses
+func staticResolver(entries map[string][]string) Resolver {
+	return func(ctx context.Context, host string) ([]net.IP, error) {
+		addrs, ok := entries[host]
+		if !ok {
+			return nil, fmt.Errorf("no such host: %s", host)
+		}
+		var ips []net.IP
+		for _, addr := range addrs {
+			ips = append(ips, net.ParseIP(addr))
+		}
+		return ips, nil
+	}
+}
+
+func newTestPolicy(t *testing.T, config domain.PolicyConfig) (*TargetPolicy, *AuditLog) {
+	t.Helper()
+
+	audit := NewAuditLog(filepath.Join(t.TempDir(), "audit.log"))
+	if config.ActiveTools == nil {
+		config.ActiveTools = []string{"ping", "traceroute"}
+	}
+
+	p, err := NewTargetPolicy(config, audit)
+	if err != nil {
+		t.Fatalf("NewTargetPolicy returned error: %v", err)
+	}
+	p.SetResolver(staticResolver(map[string][]string{
+		"example.com":   {"93.184.216.34"},
+		"router.lan":    {"192.168.1.1"},
+		"mixed.example": {"10.0.0.5", "8.8.8.8"},
+		"internal.corp": {"10.1.2.3"},
+	}))
+	return p, audit
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip     string
+		public bool
+	}{
+		{"8.8.8.8", true},
+		{"93.184.216.34", true},
+		{"2606:4700::1111", true},
+		{"10.0.0.1", false},
+		{"172.16.5.4", false},
+		{"192.168.1.1", false},
+		{"127.0.0.1", false},
+		{"169.254.1.1", false},
+		{"100.64.0.1", false},
+		{"192.0.2.10", false},
+		{"0.0.0.0", false},
+		{"::1", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"2001:db8::1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := IsPublicIP(net.ParseIP(tt.ip)); got != tt.public {
+				t.Errorf("IsPublicIP(%s) = %t, want %t", tt.ip, got, tt.public)
+			}
+		})
+	}
+
+	if IsPublicIP(nil) {
+		t.Error("IsPublicIP(nil) should be false")
+	}
+}
+
+func TestNewTargetPolicy_Validation(t *testing.T) {
+	if _, err := NewTargetPolicy(domain.PolicyConfig{PublicTargetMode: "sometimes"}, nil); err == nil {
+		t.Error("Expected error for invalid mode")
+	}
+
+	if _, err := NewTargetPolicy(domain.PolicyConfig{AllowList: []string{"10.0.0.0/99"}}, nil); err == nil {
+		t.Error("Expected error for invalid CIDR")
+	}
+
+	p, err := NewTargetPolicy(domain.PolicyConfig{}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p.Mode() != ModeWarn {
+		t.Errorf("Expected default mode %s, got %s", ModeWarn, p.Mode())
+	}
+}
+
+func TestTargetPolicy_Evaluate(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   domain.PolicyConfig
+		tool     string
+		target   string
+		expected Action
+	}{
+		{"public warn", domain.PolicyConfig{PublicTargetMode: ModeWarn}, "ping", "example.com", ActionWarn},
+		{"public block", domain.PolicyConfig{PublicTargetMode: ModeBlock}, "ping", "example.com", ActionBlock},
+		{"public off", domain.PolicyConfig{PublicTargetMode: ModeOff}, "ping", "example.com", ActionAllow},
+		{"public literal", domain.PolicyConfig{PublicTargetMode: ModeWarn}, "traceroute", "1.1.1.1", ActionWarn},
+		{"private host", domain.PolicyConfig{PublicTargetMode: ModeBlock}, "ping", "router.lan", ActionAllow},
+		{"private literal", domain.PolicyConfig{PublicTargetMode: ModeBlock}, "ping", "10.0.0.1", ActionAllow},
+		{"mixed addresses", domain.PolicyConfig{PublicTargetMode: ModeWarn}, "ping", "mixed.example", ActionWarn},
+		{"passive tool", domain.PolicyConfig{PublicTargetMode: ModeBlock}, "whois", "example.com", ActionAllow},
+		{"allow list cidr", domain.PolicyConfig{PublicTargetMode: ModeBlock, AllowList: []string{"93.184.216.0/24"}}, "ping", "example.com", ActionAllow},
+		{"allow list ip", domain.PolicyConfig{PublicTargetMode: ModeBlock, AllowList: []string{"1.1.1.1"}}, "ping", "1.1.1.1", ActionAllow},
+		{"allow list host", domain.PolicyConfig{PublicTargetMode: ModeBlock, AllowList: []string{"Example.com."}}, "ping", "example.com", ActionAllow},
+		{"unresolvable block", domain.PolicyConfig{PublicTargetMode: ModeBlock}, "ping", "missing.invalid", ActionBlock},
+		{"unresolvable warn", domain.PolicyConfig{PublicTargetMode: ModeWarn}, "ping", "missing.invalid", ActionWarn},
+		{"unresolvable off", domain.PolicyConfig{PublicTargetMode: ModeOff}, "ping", "missing.invalid", ActionAllow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := newTestPolicy(t, tt.config)
+
+			decision, err := p.Evaluate(context.Background(), tt.tool, tt.target)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if decision.Action != tt.expected {
+				t.Errorf("Expected action %s, got %s (%s)", tt.expected, decision.Action, decision.Reason)
+			}
+		})
+	}
+}
+
+func TestTargetPolicy_Enforce_ConsentRecorded(t *testing.T) {
+	p, audit := newTestPolicy(t, domain.PolicyConfig{PublicTargetMode: ModeWarn})
+	ctx := context.Background()
+
+	err := p.Enforce(ctx, "ping", "example.com", false)
+	if !IsConsentRequired(err) {
+		t.Fatalf("Expected consent required error, got %v", err)
+	}
+
+	entries, _ := audit.Entries()
+	if len(entries) != 0 {
+		t.Errorf("Expected no audit entries before consent, got %d", len(entries))
+	}
+
+	if err := p.Enforce(ctx, "ping", "example.com", true); err != nil {
+		t.Fatalf("Expected acknowledged target to pass, got %v", err)
+	}
+
+	entries, err = audit.Entries()
+	if err != nil {
+		t.Fatalf("Failed to read audit log: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 audit entry, got %d", len(entries))
+	}
+
+	entry := entries[0]
+	if entry.Event != AuditEventConsent || entry.Tool != "ping" || entry.Target != "example.com" {
+		t.Errorf("Unexpected audit entry: %+v", entry)
+	}
+	if len(entry.Addresses) != 1 || entry.Addresses[0] != "93.184.216.34" {
+		t.Errorf("Expected public address in audit entry, got %v", entry.Addresses)
+	}
+	if entry.Timestamp.IsZero() {
+		t.Error("Expected audit entry timestamp")
+	}
+}
+
+func TestTargetPolicy_Enforce_Block(t *testing.T) {
+	p, audit := newTestPolicy(t, domain.PolicyConfig{PublicTargetMode: ModeBlock})
+
+	// Acknowledgement does not override block mode
+	err := p.Enforce(context.Background(), "ping", "example.com", true)
+	if !IsBlocked(err) {
+		t.Fatalf("Expected blocked error, got %v", err)
+	}
+	if IsConsentRequired(err) {
+		t.Error("Blocked error should not ask for consent")
+	}
+
+	entries, _ := audit.Entries()
+	if len(entries) != 1 || entries[0].Event != AuditEventBlocked {
+		t.Errorf("Expected blocked audit entry, got %+v", entries)
+	}
+}
+
+func TestTargetPolicy_Enforce_AuditFailure(t *testing.T) {
+	// The audit log's directory is a regular file, so every write fails
+	parent := filepath.Join(t.TempDir(), "not-a-dir")
+	if err := os.WriteFile(parent, nil, 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	p, err := NewTargetPolicy(domain.PolicyConfig{PublicTargetMode: ModeWarn, ActiveTools: []string{"ping"}}, NewAuditLog(filepath.Join(parent, "audit.log")))
+	if err != nil {
+		t.Fatalf("NewTargetPolicy returned error: %v", err)
+	}
+	p.SetResolver(staticResolver(map[string][]string{"example.com": {"93.184.216.34"}}))
+
+	err = p.Enforce(context.Background(), "ping", "example.com", true)
+	if err == nil {
+		t.Fatal("Expected acknowledged probe to be refused when consent cannot be recorded")
+	}
+	var netErr *domain.NetTraceError
+	if !errors.As(err, &netErr) || netErr.Code != CodeAuditFailed || netErr.Cause == nil {
+		t.Errorf("Expected audit failure error with cause, got %v", err)
+	}
+	if IsConsentRequired(err) {
+		t.Error("Audit failure should not ask for consent again")
+	}
+}
+
+func TestIsConsentRequired_Wrapped(t *testing.T) {
+	inner := &domain.NetTraceError{Code: CodeConsentRequired, Message: "consent"}
+	outer := &domain.NetTraceError{Code: "PING_OPERATION_FAILED", Message: "ping failed", Cause: inner}
+
+	if !IsConsentRequired(outer) {
+		t.Error("Expected wrapped consent error to be detected")
+	}
+	if IsConsentRequired(fmt.Errorf("plain error")) {
+		t.Error("Plain errors should not require consent")
+	}
+	if IsConsentRequired(nil) {
+		t.Error("Nil error should not require consent")
+	}
+}
+
+// stubTool is a minimal diagnostic tool for guard tests
+type stubTool struct {
+	name     string
+	executed int
+	invalid  error
+}
+
+func (s *stubTool) Name() string        { return s.name }
+func (s *stubTool) Description() string { return "stub" }
+func (s *stubTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	s.executed++
+	return domain.NewResult("ok"), nil
+}
+func (s *stubTool) Validate(params domain.Parameters) error { return s.invalid }
+func (s *stubTool) GetModel() tea.Model                     { return nil }
+
+func TestTargetPolicy_Guard(t *testing.T) {
+	p, _ := newTestPolicy(t, domain.PolicyConfig{PublicTargetMode: ModeWarn})
+
+	whois := &stubTool{name: "whois"}
+	if guarded := p.Guard(whois); guarded != domain.DiagnosticTool(whois) {
+		t.Error("Passive tools should not be wrapped")
+	}
+
+	ping := &stubTool{name: "ping"}
+	guarded := p.Guard(ping)
+	if _, ok := guarded.(*GuardedTool); !ok {
+		t.Fatal("Active tools should be wrapped")
+	}
+	if guarded.Name() != "ping" {
+		t.Errorf("Guarded tool should keep its name, got %s", guarded.Name())
+	}
+
+	params := domain.NewPingParameters("example.com", domain.PingOptions{Count: 1})
+	if _, err := guarded.Execute(context.Background(), params); !IsConsentRequired(err) {
+		t.Fatalf("Expected consent error, got %v", err)
+	}
+	if ping.executed != 0 {
+		t.Error("Wrapped tool should not run without consent")
+	}
+
+	params.Set(AcknowledgeParam, true)
+	if _, err := guarded.Execute(context.Background(), params); err != nil {
+		t.Fatalf("Unexpected error after consent: %v", err)
+	}
+	if ping.executed != 1 {
+		t.Errorf("Expected wrapped tool to run once, ran %d times", ping.executed)
+	}
+
+	private := domain.NewPingParameters("router.lan", domain.PingOptions{Count: 1})
+	if _, err := guarded.Execute(context.Background(), private); err != nil {
+		t.Fatalf("Private targets should pass, got %v", err)
+	}
+}
+
+func TestGuardedTool_MultipleTargets(t *testing.T) {
+	p, _ := newTestPolicy(t, domain.PolicyConfig{PublicTargetMode: ModeWarn})
+
+	ping := &stubTool{name: "ping"}
+	guarded := p.Guard(ping)
+
+	// Every expanded target is checked, not just the raw host string
+	params := domain.NewPingParameters("router.lan, example.com", domain.PingOptions{Count: 1})
+	params.Set("targets", []string{"router.lan", "example.com"})
+	if _, err := guarded.Execute(context.Background(), params); !IsConsentRequired(err) {
+		t.Fatalf("Expected consent error for public target in list, got %v", err)
+	}
+	if ping.executed != 0 {
+		t.Error("Wrapped tool should not run without consent")
+	}
+
+	params.Set("targets", []string{"router.lan"})
+	if _, err := guarded.Execute(context.Background(), params); err != nil {
+		t.Fatalf("Private targets should pass, got %v", err)
+	}
+}
+
+func TestGuardedTool_InvalidParameters(t *testing.T) {
+	p, _ := newTestPolicy(t, domain.PolicyConfig{PublicTargetMode: ModeWarn})
+
+	invalid := errors.New("host is required")
+	ping := &stubTool{name: "ping", invalid: invalid}
+	guarded := p.Guard(ping)
+
+	params := domain.NewPingParameters("example.com", domain.PingOptions{Count: 1})
+	params.Set(AcknowledgeParam, true)
+	if _, err := guarded.Execute(context.Background(), params); !errors.Is(err, invalid) {
+		t.Fatalf("Expected the validation error, got %v", err)
+	}
+	if ping.executed != 0 {
+		t.Error("Wrapped tool should not run with invalid parameters")
+	}
+}
+
+// liveStubTool is a stub tool with a live screen
+type liveStubTool struct {
+	stubTool
+}
+
+func (s *liveStubTool) LiveModel(ctx context.Context, params domain.Parameters) (tea.Model, error) {
+	return &liveStubModel{}, nil
+}
+
+// liveStubModel is the screen of liveStubTool
+type liveStubModel struct{}
+
+func (m *liveStubModel) Init() tea.Cmd                           { return nil }
+func (m *liveStubModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) { return m, nil }
+func (m *liveStubModel) View() string                            { return "live" }
+
+func TestGuardedTool_LiveModel(t *testing.T) {
+	p, _ := newTestPolicy(t, domain.PolicyConfig{PublicTargetMode: ModeWarn})
+
+	ping := &liveStubTool{stubTool{name: "ping"}}
+	guarded := p.Guard(ping)
+	if guarded.GetModel() != nil {
+		t.Error("Guarded tool should not offer the unchecked screen of the wrapped tool")
+	}
+
+	params := domain.NewPingParameters("example.com", domain.PingOptions{Count: 1})
+	if model, err := domain.LiveModel(context.Background(), guarded, params); !IsConsentRequired(err) || model != nil {
+		t.Fatalf("Expected consent error and no screen, got %v, %v", model, err)
+	}
+
+	params.Set(AcknowledgeParam, true)
+	model, err := domain.LiveModel(context.Background(), guarded, params)
+	if err != nil || model == nil {
+		t.Fatalf("Expected the live screen after consent, got %v, %v", model, err)
+	}
+	if ping.executed != 0 {
+		t.Error("Live runs should not execute the wrapped tool")
+	}
+
+	// A tool without a live screen runs through Execute
+	plain := p.Guard(&stubTool{name: "ping"})
+	if model, err := domain.LiveModel(context.Background(), plain, params); model != nil || err != nil {
+		t.Errorf("Expected no live screen, got %v, %v", model, err)
+	}
+}
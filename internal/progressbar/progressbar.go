@@ -0,0 +1,104 @@
+// Package progressbar shows how far an operation of a known number of steps
+// has come: a bubbles progress bar that animates towards each step, followed
+// by the time left, estimated from the pace of the steps so far.
+package progressbar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Bar is a progress bar over total steps
+type Bar struct {
+	model   progress.Model
+	total   int
+	done    int
+	started time.Time
+	now     func() time.Time
+}
+
+// New creates a bar for an operation of total steps starting now
+func New(total int) *Bar {
+	b := &Bar{model: progress.New(progress.WithDefaultGradient()), now: time.Now}
+	b.Start(total)
+	return b
+}
+
+// Start restarts the bar for an operation of total steps starting now
+func (b *Bar) Start(total int) {
+	b.total = total
+	b.done = 0
+	b.started = b.now()
+	b.model.SetPercent(0)
+}
+
+// Set records that done steps have completed. The returned command animates
+// the bar towards them; its frames go to Update.
+func (b *Bar) Set(done int) tea.Cmd {
+	b.done = done
+	return b.model.SetPercent(b.Percent())
+}
+
+// Update moves the animation on a progress.FrameMsg of this bar
+func (b *Bar) Update(msg tea.Msg) tea.Cmd {
+	model, cmd := b.model.Update(msg)
+	b.model = model.(progress.Model)
+	return cmd
+}
+
+// SetWidth sets the width of the bar with its percentage
+func (b *Bar) SetWidth(width int) {
+	b.model.Width = width
+}
+
+// Percent returns the completed fraction
+func (b *Bar) Percent() float64 {
+	if b.total <= 0 {
+		return 0
+	}
+	if b.done >= b.total {
+		return 1
+	}
+	return float64(b.done) / float64(b.total)
+}
+
+// Remaining estimates the time left from the average time per step so far.
+// It reports false before the first step completes.
+func (b *Bar) Remaining() (time.Duration, bool) {
+	if b.done <= 0 || b.total <= 0 {
+		return 0, false
+	}
+	if b.done >= b.total {
+		return 0, true
+	}
+	perStep := b.now().Sub(b.started) / time.Duration(b.done)
+	return perStep * time.Duration(b.total-b.done), true
+}
+
+// ETA describes the time left, such as "ETA 12s"
+func (b *Bar) ETA() string {
+	remaining, ok := b.Remaining()
+	switch {
+	case b.total > 0 && b.done >= b.total:
+		return "done"
+	case !ok:
+		return "ETA --"
+	case remaining < time.Second:
+		return "ETA <1s"
+	}
+	return fmt.Sprintf("ETA %s", remaining.Round(time.Second))
+}
+
+// View renders the animated bar and the time left
+func (b *Bar) View() string {
+	return b.model.View() + "  " + b.ETA()
+}
+
+// StaticView renders the bar at the completed fraction without animating,
+// for output written outside a Bubble Tea program
+func (b *Bar) StaticView() string {
+	return b.model.ViewAs(b.Percent()) + "  " + b.ETA()
+}
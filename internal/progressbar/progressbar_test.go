@@ -0,0 +1,70 @@
+package progressbar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+)
+
+func TestBar_Remaining(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	bar := New(4)
+	bar.now = func() time.Time { return now }
+	bar.Start(4)
+
+	if _, ok := bar.Remaining(); ok {
+		t.Error("Expected no estimate before the first step")
+	}
+	if got := bar.ETA(); got != "ETA --" {
+		t.Errorf("Expected ETA --, got %q", got)
+	}
+
+	now = now.Add(2 * time.Second)
+	bar.Set(1)
+	if remaining, ok := bar.Remaining(); !ok || remaining != 6*time.Second {
+		t.Errorf("Expected 6s left after one step of 2s, got %v", remaining)
+	}
+	if got := bar.ETA(); got != "ETA 6s" {
+		t.Errorf("Expected ETA 6s, got %q", got)
+	}
+	if got := bar.Percent(); got != 0.25 {
+		t.Errorf("Expected 25%%, got %v", got)
+	}
+
+	bar.Set(5)
+	if got := bar.Percent(); got != 1 {
+		t.Errorf("Expected the fraction to stop at 1, got %v", got)
+	}
+	if got := bar.ETA(); got != "done" {
+		t.Errorf("Expected done, got %q", got)
+	}
+}
+
+func TestBar_Animates(t *testing.T) {
+	bar := New(2)
+	bar.SetWidth(30)
+
+	cmd := bar.Set(1)
+	if cmd == nil {
+		t.Fatal("Expected a command animating the bar")
+	}
+	if !strings.Contains(bar.View(), " 0%") {
+		t.Errorf("Expected the animation to start from 0%%, got %q", bar.View())
+	}
+	if !strings.Contains(bar.StaticView(), "50%") {
+		t.Errorf("Expected the static view at 50%%, got %q", bar.StaticView())
+	}
+
+	msg := cmd()
+	if _, ok := msg.(progress.FrameMsg); !ok {
+		t.Fatalf("Expected a frame message, got %T", msg)
+	}
+	if bar.Update(msg) == nil {
+		t.Error("Expected the animation to continue")
+	}
+	if strings.Contains(bar.View(), " 0%") {
+		t.Errorf("Expected the bar to move, got %q", bar.View())
+	}
+}
@@ -0,0 +1,301 @@
+// Package provider identifies CDN and hosting providers from DNS, HTTP and IP evidence
+package provider
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// Provider categories
+const (
+	CategoryCDN   = "cdn"
+	CategoryCloud = "cloud"
+)
+
+// Evidence weights used to build a confidence score
+const (
+	weightCNAME  = 0.5
+	weightHeader = 0.4
+	weightIP     = 0.3
+)
+
+// HeaderMatch describes a response header that identifies a provider.
+// An empty Contains value matches on header presence alone.
+type HeaderMatch struct {
+	Name     string
+	Contains string
+}
+
+// Signature describes how to recognise a single provider
+type Signature struct {
+	Name          string
+	Category      string
+	CNAMESuffixes []string
+	Headers       []HeaderMatch
+	Networks      []*net.IPNet
+}
+
+// Evidence contains the observations used for provider detection
+type Evidence struct {
+	CNAMEs  []string
+	Headers http.Header
+	IPs     []net.IP
+}
+
+// Detector matches evidence against a set of provider signatures
+type Detector struct {
+	signatures []Signature
+}
+
+// NewDetector creates a detector loaded with the built-in provider signatures
+func NewDetector() *Detector {
+	return &Detector{
+		signatures: DefaultSignatures(),
+	}
+}
+
+// NewDetectorWithSignatures creates a detector using custom signatures
+func NewDetectorWithSignatures(signatures []Signature) *Detector {
+	return &Detector{
+		signatures: signatures,
+	}
+}
+
+// Signatures returns the signatures known to the detector
+func (d *Detector) Signatures() []Signature {
+	return d.signatures
+}
+
+// Detect returns the most likely provider for the evidence, or nil if nothing matched
+func (d *Detector) Detect(evidence Evidence) *domain.ProviderInfo {
+	matches := d.DetectAll(evidence)
+	if len(matches) == 0 {
+		return nil
+	}
+	return &matches[0]
+}
+
+// DetectAll returns every matching provider ordered by confidence
+func (d *Detector) DetectAll(evidence Evidence) []domain.ProviderInfo {
+	var matches []domain.ProviderInfo
+
+	for _, sig := range d.signatures {
+		info := domain.ProviderInfo{
+			Name:     sig.Name,
+			Category: sig.Category,
+		}
+
+		if cname, ok := matchCNAME(sig, evidence.CNAMEs); ok {
+			info.Confidence += weightCNAME
+			info.Evidence = append(info.Evidence, fmt.Sprintf("CNAME %s", cname))
+		}
+
+		if header, ok := matchHeader(sig, evidence.Headers); ok {
+			info.Confidence += weightHeader
+			info.Evidence = append(info.Evidence, fmt.Sprintf("header %s", header))
+		}
+
+		if ip, ok := matchIP(sig, evidence.IPs); ok {
+			info.Confidence += weightIP
+			info.Evidence = append(info.Evidence, fmt.Sprintf("IP %s in provider range", ip))
+		}
+
+		if len(info.Evidence) == 0 {
+			continue
+		}
+
+		if info.Confidence > 1 {
+			info.Confidence = 1
+		}
+		matches = append(matches, info)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Confidence > matches[j].Confidence
+	})
+
+	return matches
+}
+
+// matchCNAME checks whether any CNAME in the chain ends with a provider suffix
+func matchCNAME(sig Signature, cnames []string) (string, bool) {
+	for _, cname := range cnames {
+		name := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(cname), "."))
+		for _, suffix := range sig.CNAMESuffixes {
+			if name == suffix || strings.HasSuffix(name, "."+suffix) {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// matchHeader checks whether the response headers carry a provider fingerprint
+func matchHeader(sig Signature, headers http.Header) (string, bool) {
+	if headers == nil {
+		return "", false
+	}
+
+	for _, hm := range sig.Headers {
+		values := headers.Values(hm.Name)
+		if len(values) == 0 {
+			continue
+		}
+		if hm.Contains == "" {
+			return hm.Name, true
+		}
+		for _, value := range values {
+			if strings.Contains(strings.ToLower(value), strings.ToLower(hm.Contains)) {
+				return fmt.Sprintf("%s: %s", hm.Name, value), true
+			}
+		}
+	}
+	return "", false
+}
+
+// matchIP checks whether any address falls inside a provider network
+func matchIP(sig Signature, ips []net.IP) (string, bool) {
+	for _, ip := range ips {
+		if ip == nil {
+			continue
+		}
+		for _, network := range sig.Networks {
+			if network.Contains(ip) {
+				return ip.String(), true
+			}
+		}
+	}
+	return "", false
+}
+
+// DefaultSignatures returns the built-in signatures for well known providers.
+// IP ranges are a representative subset of each provider's published prefixes.
+func DefaultSignatures() []Signature {
+	return []Signature{
+		{
+			Name:          "Cloudflare",
+			Category:      CategoryCDN,
+			CNAMESuffixes: []string{"cdn.cloudflare.net", "cloudflare.net"},
+			Headers: []HeaderMatch{
+				{Name: "CF-Ray"},
+				{Name: "Server", Contains: "cloudflare"},
+			},
+			Networks: mustParseCIDRs(
+				"173.245.48.0/20", "103.21.244.0/22", "103.22.200.0/22",
+				"103.31.4.0/22", "141.101.64.0/18", "108.162.192.0/18",
+				"190.93.240.0/20", "188.114.96.0/20", "197.234.240.0/22",
+				"198.41.128.0/17", "162.158.0.0/15", "104.16.0.0/13",
+				"104.24.0.0/14", "172.64.0.0/13", "131.0.72.0/22",
+				"2400:cb00::/32", "2606:4700::/32", "2803:f800::/32",
+				"2405:b500::/32", "2405:8100::/32", "2a06:98c0::/29",
+				"2c0f:f248::/32",
+			),
+		},
+		{
+			Name:          "Akamai",
+			Category:      CategoryCDN,
+			CNAMESuffixes: []string{"akamaiedge.net", "akamai.net", "edgekey.net", "edgesuite.net", "akamaihd.net", "akamaized.net"},
+			Headers: []HeaderMatch{
+				{Name: "X-Akamai-Transformed"},
+				{Name: "Akamai-GRN"},
+				{Name: "Server", Contains: "AkamaiGHost"},
+			},
+			Networks: mustParseCIDRs(
+				"23.32.0.0/11", "23.192.0.0/11", "2.16.0.0/13",
+				"104.64.0.0/10", "184.24.0.0/13", "95.100.0.0/15",
+				"2600:1400::/24", "2a02:26f0::/29",
+			),
+		},
+		{
+			Name:          "Fastly",
+			Category:      CategoryCDN,
+			CNAMESuffixes: []string{"fastly.net", "fastlylb.net"},
+			Headers: []HeaderMatch{
+				{Name: "X-Fastly-Request-ID"},
+				{Name: "X-Served-By", Contains: "cache-"},
+				{Name: "Fastly-Debug-Digest"},
+			},
+			Networks: mustParseCIDRs(
+				"23.235.32.0/20", "43.249.72.0/22", "103.244.50.0/24",
+				"103.245.222.0/23", "103.245.224.0/24", "104.156.80.0/20",
+				"140.248.64.0/18", "140.248.128.0/17", "146.75.0.0/17",
+				"151.101.0.0/16", "157.52.64.0/18", "167.82.0.0/17",
+				"172.111.64.0/18", "185.31.16.0/22", "199.27.72.0/21",
+				"199.232.0.0/16", "2a04:4e40::/32", "2a04:4e42::/32",
+			),
+		},
+		{
+			Name:          "Amazon CloudFront",
+			Category:      CategoryCDN,
+			CNAMESuffixes: []string{"cloudfront.net"},
+			Headers: []HeaderMatch{
+				{Name: "X-Amz-Cf-Id"},
+				{Name: "Via", Contains: "cloudfront"},
+			},
+			Networks: mustParseCIDRs(
+				"13.32.0.0/15", "13.224.0.0/14", "18.64.0.0/14",
+				"52.84.0.0/15", "54.182.0.0/16", "54.192.0.0/16",
+				"54.230.0.0/16", "54.239.128.0/18", "99.84.0.0/16",
+				"205.251.192.0/19", "2600:9000::/28",
+			),
+		},
+		{
+			Name:          "Amazon Web Services",
+			Category:      CategoryCloud,
+			CNAMESuffixes: []string{"amazonaws.com", "elb.amazonaws.com", "awsglobalaccelerator.com"},
+			Headers: []HeaderMatch{
+				{Name: "X-Amz-Request-Id"},
+				{Name: "X-Amz-Id-2"},
+				{Name: "Server", Contains: "AmazonS3"},
+				{Name: "Server", Contains: "awselb"},
+			},
+			Networks: mustParseCIDRs(
+				"3.0.0.0/9", "18.128.0.0/9", "34.192.0.0/10",
+				"35.152.0.0/13", "44.192.0.0/10", "52.0.0.0/11",
+				"54.64.0.0/11", "2600:1f00::/24",
+			),
+		},
+		{
+			Name:          "Google Cloud",
+			Category:      CategoryCloud,
+			CNAMESuffixes: []string{"googlehosted.com", "ghs.googlehosted.com", "googleusercontent.com", "appspot.com", "run.app", "web.app", "firebaseapp.com"},
+			Headers: []HeaderMatch{
+				{Name: "X-Goog-Generation"},
+				{Name: "X-GUploader-UploadID"},
+				{Name: "Via", Contains: "google"},
+				{Name: "Server", Contains: "Google Frontend"},
+			},
+			Networks: mustParseCIDRs(
+				"34.64.0.0/10", "35.184.0.0/13", "35.192.0.0/12",
+				"35.208.0.0/12", "35.224.0.0/12", "104.154.0.0/15",
+				"104.196.0.0/14", "130.211.0.0/16", "2600:1900::/28",
+			),
+		},
+	}
+}
+
+// mustParseCIDRs parses a list of CIDR strings, panicking on invalid input
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(fmt.Sprintf("invalid provider CIDR %q: %v", cidr, err))
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// FormatProvider returns a short human-readable description of a provider match
+func FormatProvider(info *domain.ProviderInfo) string {
+	if info == nil {
+		return "Unknown"
+	}
+	return fmt.Sprintf("%s (%s, %.0f%% confidence)", info.Name, info.Category, info.Confidence*100)
+}
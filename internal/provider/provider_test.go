@@ -0,0 +1,207 @@
+// Package provider provides tests for CDN and hosting provider detection
+package provider
+
+import (
+	"net"
+	"net/http"
+	"testing"
+)
+
+func TestNewDetector(t *testing.T) {
+	detector := NewDetector()
+
+	if detector == nil {
+		t.Fatal("NewDetector returned nil")
+	}
+
+	expected := map[string]bool{
+		"Cloudflare":          false,
+		"Akamai":              false,
+		"Fastly":              false,
+		"Amazon CloudFront":   false,
+		"Amazon Web Services": false,
+		"Google Cloud":        false,
+	}
+
+	for _, sig := range detector.Signatures() {
+		expected[sig.Name] = true
+	}
+
+	for name, found := range expected {
+		if !found {
+			t.Errorf("Expected built-in signature for %s", name)
+		}
+	}
+}
+
+func TestDetector_Detect_CNAME(t *testing.T) {
+	detector := NewDetector()
+
+	tests := []struct {
+		name     string
+		cname    string
+		expected string
+	}{
+		{"cloudflare", "www.example.com.cdn.cloudflare.net.", "Cloudflare"},
+		{"akamai", "e1234.a.akamaiedge.net", "Akamai"},
+		{"fastly", "dualstack.n.sni.global.fastly.net", "Fastly"},
+		{"cloudfront", "d111111abcdef8.cloudfront.net", "Amazon CloudFront"},
+		{"aws elb", "my-lb-1234.us-east-1.elb.amazonaws.com", "Amazon Web Services"},
+		{"gcp", "ghs.googlehosted.com", "Google Cloud"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := detector.Detect(Evidence{CNAMEs: []string{tt.cname}})
+			if info == nil {
+				t.Fatalf("Expected provider for CNAME %s", tt.cname)
+			}
+			if info.Name != tt.expected {
+				t.Errorf("Expected provider %s, got %s", tt.expected, info.Name)
+			}
+			if len(info.Evidence) != 1 {
+				t.Errorf("Expected 1 evidence entry, got %d", len(info.Evidence))
+			}
+		})
+	}
+}
+
+func TestDetector_Detect_CNAMESuffixBoundary(t *testing.T) {
+	detector := NewDetector()
+
+	// A name that merely contains the suffix text must not match
+	info := detector.Detect(Evidence{CNAMEs: []string{"notfastly.net"}})
+	if info != nil {
+		t.Errorf("Expected no match for look-alike domain, got %s", info.Name)
+	}
+}
+
+func TestDetector_Detect_Headers(t *testing.T) {
+	detector := NewDetector()
+
+	tests := []struct {
+		name     string
+		headers  http.Header
+		expected string
+	}{
+		{"cf-ray", http.Header{"Cf-Ray": []string{"7d1e2f3a4b5c6d7e-AMS"}}, "Cloudflare"},
+		{"server cloudflare", http.Header{"Server": []string{"cloudflare"}}, "Cloudflare"},
+		{"akamai ghost", http.Header{"Server": []string{"AkamaiGHost"}}, "Akamai"},
+		{"fastly served by", http.Header{"X-Served-By": []string{"cache-ams21028-AMS"}}, "Fastly"},
+		{"cloudfront", http.Header{"X-Amz-Cf-Id": []string{"abc"}}, "Amazon CloudFront"},
+		{"google frontend", http.Header{"Server": []string{"Google Frontend"}}, "Google Cloud"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := detector.Detect(Evidence{Headers: tt.headers})
+			if info == nil {
+				t.Fatal("Expected provider from headers")
+			}
+			if info.Name != tt.expected {
+				t.Errorf("Expected provider %s, got %s", tt.expected, info.Name)
+			}
+		})
+	}
+}
+
+func TestDetector_Detect_IPRanges(t *testing.T) {
+	detector := NewDetector()
+
+	tests := []struct {
+		ip       string
+		expected string
+	}{
+		{"104.16.132.229", "Cloudflare"},
+		{"2606:4700::6810:84e5", "Cloudflare"},
+		{"151.101.1.69", "Fastly"},
+		{"13.224.10.20", "Amazon CloudFront"},
+		{"35.190.0.1", "Google Cloud"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			info := detector.Detect(Evidence{IPs: []net.IP{net.ParseIP(tt.ip)}})
+			if info == nil {
+				t.Fatalf("Expected provider for IP %s", tt.ip)
+			}
+			if info.Name != tt.expected {
+				t.Errorf("Expected provider %s, got %s", tt.expected, info.Name)
+			}
+		})
+	}
+}
+
+func TestDetector_Detect_NoMatch(t *testing.T) {
+	detector := NewDetector()
+
+	info := detector.Detect(Evidence{
+		CNAMEs:  []string{"canonical.example.com"},
+		Headers: http.Header{"Server": []string{"nginx"}},
+		IPs:     []net.IP{net.ParseIP("192.168.1.100")},
+	})
+
+	if info != nil {
+		t.Errorf("Expected no provider, got %s", info.Name)
+	}
+}
+
+func TestDetector_DetectAll_OrdersByConfidence(t *testing.T) {
+	detector := NewDetector()
+
+	matches := detector.DetectAll(Evidence{
+		CNAMEs:  []string{"www.example.com.cdn.cloudflare.net"},
+		Headers: http.Header{"Cf-Ray": []string{"abc"}},
+		IPs:     []net.IP{net.ParseIP("104.16.0.1"), net.ParseIP("151.101.1.1")},
+	})
+
+	if len(matches) != 2 {
+		t.Fatalf("Expected 2 matches, got %d", len(matches))
+	}
+
+	if matches[0].Name != "Cloudflare" {
+		t.Errorf("Expected Cloudflare first, got %s", matches[0].Name)
+	}
+
+	if matches[0].Confidence != 1 {
+		t.Errorf("Expected confidence capped at 1, got %f", matches[0].Confidence)
+	}
+
+	if len(matches[0].Evidence) != 3 {
+		t.Errorf("Expected 3 evidence entries, got %d", len(matches[0].Evidence))
+	}
+
+	if matches[1].Name != "Fastly" {
+		t.Errorf("Expected Fastly second, got %s", matches[1].Name)
+	}
+}
+
+func TestNewDetectorWithSignatures(t *testing.T) {
+	detector := NewDetectorWithSignatures([]Signature{
+		{
+			Name:          "Example CDN",
+			Category:      CategoryCDN,
+			CNAMESuffixes: []string{"examplecdn.net"},
+		},
+	})
+
+	info := detector.Detect(Evidence{CNAMEs: []string{"edge.examplecdn.net"}})
+	if info == nil || info.Name != "Example CDN" {
+		t.Fatal("Expected custom signature to match")
+	}
+
+	if info := detector.Detect(Evidence{CNAMEs: []string{"x.cloudfront.net"}}); info != nil {
+		t.Errorf("Expected built-in signatures to be absent, got %s", info.Name)
+	}
+}
+
+func TestFormatProvider(t *testing.T) {
+	if got := FormatProvider(nil); got != "Unknown" {
+		t.Errorf("Expected Unknown for nil provider, got %s", got)
+	}
+
+	info := NewDetector().Detect(Evidence{CNAMEs: []string{"x.cloudfront.net"}})
+	if got := FormatProvider(info); got != "Amazon CloudFront (cdn, 50% confidence)" {
+		t.Errorf("Unexpected formatted provider: %s", got)
+	}
+}
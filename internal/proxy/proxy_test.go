@@ -0,0 +1,165 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serve accepts one connection on a local listener and runs handle on it
+func serve(t *testing.T, handle func(conn net.Conn)) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		handle(conn)
+	}()
+	return listener.Addr().String()
+}
+
+// socksServer is a SOCKS5 proxy that accepts user:secret, records the
+// requested destination and then echoes the tunnel
+func socksServer(t *testing.T, reply byte, destination chan<- string) string {
+	return serve(t, func(conn net.Conn) {
+		greeting := make([]byte, 2)
+		io.ReadFull(conn, greeting)
+		methods := make([]byte, greeting[1])
+		io.ReadFull(conn, methods)
+		conn.Write([]byte{5, socksAuthPassword})
+
+		auth := make([]byte, 2)
+		io.ReadFull(conn, auth)
+		username := make([]byte, auth[1])
+		io.ReadFull(conn, username)
+		length := make([]byte, 1)
+		io.ReadFull(conn, length)
+		password := make([]byte, length[0])
+		io.ReadFull(conn, password)
+		if string(username) != "user" || string(password) != "secret" {
+			conn.Write([]byte{1, 1})
+			return
+		}
+		conn.Write([]byte{1, 0})
+
+		header := make([]byte, 5)
+		io.ReadFull(conn, header)
+		host := make([]byte, header[4])
+		io.ReadFull(conn, host)
+		port := make([]byte, 2)
+		io.ReadFull(conn, port)
+		destination <- net.JoinHostPort(string(host), strconv.Itoa(int(binary.BigEndian.Uint16(port))))
+
+		conn.Write([]byte{5, reply, 0, socksAddrIPv4, 127, 0, 0, 1, 0, 80})
+		io.Copy(conn, conn)
+	})
+}
+
+func TestParse(t *testing.T) {
+	parsed, err := Parse("")
+	require.NoError(t, err)
+	assert.Nil(t, parsed)
+
+	for _, valid := range []string{"socks5://127.0.0.1:1080", "socks5h://user:pw@proxy:1080", "http://proxy.internal:3128"} {
+		_, err := Parse(valid)
+		assert.NoError(t, err, valid)
+	}
+	for _, invalid := range []string{"proxy:3128", "https://proxy:443", "socks5://proxy", "ftp://proxy:21"} {
+		_, err := Parse(invalid)
+		assert.Error(t, err, invalid)
+	}
+
+	assert.Equal(t, "socks5://proxy:1080", Redact("socks5://user:pw@proxy:1080"))
+}
+
+func TestDial_SOCKS5(t *testing.T) {
+	destination := make(chan string, 1)
+	address := socksServer(t, 0, destination)
+
+	conn, err := Dial(context.Background(), &net.Dialer{Timeout: time.Second}, "socks5://user:secret@"+address, "tcp", "whois.example.com:43")
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, "whois.example.com:43", <-destination)
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	echoed := make([]byte, 4)
+	_, err = io.ReadFull(conn, echoed)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(echoed))
+}
+
+func TestDial_SOCKS5Refused(t *testing.T) {
+	address := socksServer(t, 5, make(chan string, 1))
+
+	_, err := Dial(context.Background(), &net.Dialer{Timeout: time.Second}, "socks5://user:secret@"+address, "tcp", "example.com:443")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection refused")
+}
+
+func TestDial_HTTPConnect(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	address := serve(t, func(conn net.Conn) {
+		request, err := http.ReadRequest(bufio.NewReader(conn))
+		if err != nil {
+			return
+		}
+		requests <- request
+		// The banner arrives together with the response and must not be lost
+		conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\nbanner"))
+	})
+
+	conn, err := Dial(context.Background(), &net.Dialer{Timeout: time.Second}, "http://user:secret@"+address, "tcp", "example.com:443")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	request := <-requests
+	assert.Equal(t, http.MethodConnect, request.Method)
+	assert.Equal(t, "example.com:443", request.Host)
+	assert.Equal(t, "Basic dXNlcjpzZWNyZXQ=", request.Header.Get("Proxy-Authorization"))
+
+	banner := make([]byte, 6)
+	_, err = io.ReadFull(conn, banner)
+	require.NoError(t, err)
+	assert.Equal(t, "banner", string(banner))
+}
+
+func TestDial_HTTPConnectRefused(t *testing.T) {
+	address := serve(t, func(conn net.Conn) {
+		http.ReadRequest(bufio.NewReader(conn))
+		conn.Write([]byte("HTTP/1.1 403 Forbidden\r\nContent-Length: 0\r\n\r\n"))
+	})
+
+	_, err := Dial(context.Background(), &net.Dialer{Timeout: time.Second}, "http://"+address, "tcp", "example.com:443")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+}
+
+func TestTransport_FollowsProxySetting(t *testing.T) {
+	current := ""
+	transport := Transport(func() string { return current })
+	request, _ := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+
+	proxyURL, err := transport.Proxy(request)
+	require.NoError(t, err)
+	assert.Nil(t, proxyURL)
+
+	current = "socks5://127.0.0.1:1080"
+	proxyURL, err = transport.Proxy(request)
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:1080", proxyURL.Host)
+}
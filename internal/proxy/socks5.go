@@ -0,0 +1,155 @@
+package proxy
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+)
+
+// SOCKS5 protocol values (RFC 1928, RFC 1929)
+const (
+	socksVersion         = 5
+	socksAuthNone        = 0
+	socksAuthPassword    = 2
+	socksAuthNoMethod    = 0xff
+	socksPasswordVersion = 1
+	socksConnect         = 1
+	socksAddrIPv4        = 1
+	socksAddrDomain      = 3
+	socksAddrIPv6        = 4
+)
+
+// socksReplies describes the failure codes of a SOCKS5 reply
+var socksReplies = map[byte]string{
+	1: "general SOCKS server failure",
+	2: "connection not allowed by ruleset",
+	3: "network unreachable",
+	4: "host unreachable",
+	5: "connection refused",
+	6: "TTL expired",
+	7: "command not supported",
+	8: "address type not supported",
+}
+
+// connectSOCKS5 asks the SOCKS5 proxy on conn to connect to address
+func connectSOCKS5(conn net.Conn, proxyURL *url.URL, address string) error {
+	host, portText, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	port, err := strconv.Atoi(portText)
+	if err != nil || port <= 0 || port > 65535 {
+		return fmt.Errorf("invalid port in %q", address)
+	}
+
+	methods := []byte{socksAuthNone}
+	if proxyURL.User != nil {
+		methods = append(methods, socksAuthPassword)
+	}
+	if _, err := conn.Write(append([]byte{socksVersion, byte(len(methods))}, methods...)); err != nil {
+		return fmt.Errorf("failed to send SOCKS5 greeting: %w", err)
+	}
+
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return fmt.Errorf("failed to read SOCKS5 greeting: %w", err)
+	}
+	if reply[0] != socksVersion {
+		return fmt.Errorf("unexpected SOCKS version %d", reply[0])
+	}
+	switch reply[1] {
+	case socksAuthNone:
+	case socksAuthPassword:
+		if err := authenticateSOCKS5(conn, proxyURL.User); err != nil {
+			return err
+		}
+	case socksAuthNoMethod:
+		return errors.New("SOCKS5 proxy accepts none of the offered authentication methods")
+	default:
+		return fmt.Errorf("SOCKS5 proxy chose unsupported authentication method %d", reply[1])
+	}
+
+	request := []byte{socksVersion, socksConnect, 0}
+	if ip := net.ParseIP(host); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			request = append(append(request, socksAddrIPv4), ip4...)
+		} else {
+			request = append(append(request, socksAddrIPv6), ip.To16()...)
+		}
+	} else {
+		if len(host) > 255 {
+			return fmt.Errorf("host name %q too long for SOCKS5", host)
+		}
+		request = append(append(request, socksAddrDomain, byte(len(host))), host...)
+	}
+	request = binary.BigEndian.AppendUint16(request, uint16(port))
+	if _, err := conn.Write(request); err != nil {
+		return fmt.Errorf("failed to send SOCKS5 connect: %w", err)
+	}
+
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return fmt.Errorf("failed to read SOCKS5 reply: %w", err)
+	}
+	if header[1] != 0 {
+		message, known := socksReplies[header[1]]
+		if !known {
+			message = fmt.Sprintf("SOCKS5 reply code %d", header[1])
+		}
+		return fmt.Errorf("connect to %s failed: %s", address, message)
+	}
+
+	// Skip the bound address and port
+	var skip int
+	switch header[3] {
+	case socksAddrIPv4:
+		skip = net.IPv4len + 2
+	case socksAddrIPv6:
+		skip = net.IPv6len + 2
+	case socksAddrDomain:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return fmt.Errorf("failed to read SOCKS5 reply: %w", err)
+		}
+		skip = int(length[0]) + 2
+	default:
+		return fmt.Errorf("unexpected SOCKS5 address type %d", header[3])
+	}
+	if _, err := io.CopyN(io.Discard, conn, int64(skip)); err != nil {
+		return fmt.Errorf("failed to read SOCKS5 reply: %w", err)
+	}
+	return nil
+}
+
+// authenticateSOCKS5 performs username/password authentication
+func authenticateSOCKS5(conn net.Conn, user *url.Userinfo) error {
+	if user == nil {
+		return errors.New("SOCKS5 proxy requires a username and password")
+	}
+	username := user.Username()
+	password, _ := user.Password()
+	if len(username) > 255 || len(password) > 255 {
+		return errors.New("SOCKS5 username and password must be at most 255 bytes")
+	}
+
+	request := []byte{socksPasswordVersion, byte(len(username))}
+	request = append(request, username...)
+	request = append(request, byte(len(password)))
+	request = append(request, password...)
+	if _, err := conn.Write(request); err != nil {
+		return fmt.Errorf("failed to send SOCKS5 credentials: %w", err)
+	}
+
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return fmt.Errorf("failed to read SOCKS5 authentication reply: %w", err)
+	}
+	if reply[1] != 0 {
+		return errors.New("SOCKS5 proxy rejected the credentials")
+	}
+	return nil
+}
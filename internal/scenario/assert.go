@@ -0,0 +1,244 @@
+package scenario
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/stats"
+	"github.com/nettracex/nettracex-tui/internal/tools/dns"
+)
+
+// assertionPattern matches "<metric> <operator> <value>"
+var assertionPattern = regexp.MustCompile(`^\s*([a-z0-9_]+)\s*(<=|>=|==|!=|<|>)\s*(.+?)\s*$`)
+
+// Assertion compares a metric of a tool result against an expected value
+type Assertion struct {
+	Expression string
+	Metric     string
+	Operator   string
+	Expected   string
+}
+
+// ParseAssertion parses an expression such as "avg_rtt < 50ms"
+func ParseAssertion(expression string) (Assertion, error) {
+	match := assertionPattern.FindStringSubmatch(expression)
+	if match == nil {
+		return Assertion{}, fmt.Errorf("invalid assertion %q: expected <metric> <operator> <value>", expression)
+	}
+	return Assertion{
+		Expression: strings.TrimSpace(expression),
+		Metric:     match[1],
+		Operator:   match[2],
+		Expected:   match[3],
+	}, nil
+}
+
+// Evaluate checks the assertion against metrics and returns the actual value
+func (a Assertion) Evaluate(metrics map[string]interface{}) domain.ScenarioAssertionResult {
+	outcome := domain.ScenarioAssertionResult{Expression: a.Expression}
+
+	actual, ok := metrics[a.Metric]
+	if !ok {
+		outcome.Error = fmt.Sprintf("unknown metric %s (available: %s)", a.Metric, strings.Join(metricNames(metrics), ", "))
+		return outcome
+	}
+	outcome.Actual = formatMetric(actual)
+
+	passed, err := a.compare(actual)
+	if err != nil {
+		outcome.Error = err.Error()
+		return outcome
+	}
+	outcome.Passed = passed
+	return outcome
+}
+
+// compare applies the operator to the actual value and the expected value parsed as the same type
+func (a Assertion) compare(actual interface{}) (bool, error) {
+	switch value := actual.(type) {
+	case time.Duration:
+		expected, err := time.ParseDuration(a.Expected)
+		if err != nil {
+			return false, fmt.Errorf("%s is a duration, %q is not (use e.g. 50ms)", a.Metric, a.Expected)
+		}
+		return compareOrdered(float64(value), float64(expected), a.Operator), nil
+	case float64:
+		expected, err := strconv.ParseFloat(strings.TrimSuffix(a.Expected, "%"), 64)
+		if err != nil {
+			return false, fmt.Errorf("%s is a number, %q is not", a.Metric, a.Expected)
+		}
+		return compareOrdered(value, expected, a.Operator), nil
+	case bool:
+		expected, err := strconv.ParseBool(a.Expected)
+		if err != nil {
+			return false, fmt.Errorf("%s is true or false, %q is not", a.Metric, a.Expected)
+		}
+		return compareEqual(value == expected, a.Operator, a.Metric)
+	case string:
+		return compareEqual(value == strings.Trim(a.Expected, `"'`), a.Operator, a.Metric)
+	default:
+		return false, fmt.Errorf("%s cannot be compared", a.Metric)
+	}
+}
+
+// compareOrdered applies an ordering operator to two numbers
+func compareOrdered(actual, expected float64, operator string) bool {
+	switch operator {
+	case "<":
+		return actual < expected
+	case "<=":
+		return actual <= expected
+	case ">":
+		return actual > expected
+	case ">=":
+		return actual >= expected
+	case "==":
+		return actual == expected
+	default:
+		return actual != expected
+	}
+}
+
+// compareEqual applies an equality operator given whether the values are equal
+func compareEqual(equal bool, operator, metric string) (bool, error) {
+	switch operator {
+	case "==":
+		return equal, nil
+	case "!=":
+		return !equal, nil
+	default:
+		return false, fmt.Errorf("%s only supports == and !=", metric)
+	}
+}
+
+// formatMetric formats a metric value for reports
+func formatMetric(value interface{}) string {
+	switch v := value.(type) {
+	case time.Duration:
+		return v.Round(time.Microsecond).String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// metricNames returns the sorted metric names for error messages
+func metricNames(metrics map[string]interface{}) []string {
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Metrics extracts the values assertions can refer to from a tool result.
+// Numbers are float64, latencies time.Duration, flags bool and names string.
+func Metrics(data interface{}, now time.Time) map[string]interface{} {
+	switch data := data.(type) {
+	case []domain.PingResult:
+		return pingMetrics(data)
+	case domain.MultiPingResult:
+		var results []domain.PingResult
+		for _, target := range data.Targets {
+			results = append(results, target.Results...)
+		}
+		return pingMetrics(results)
+	case []domain.TraceHop:
+		return traceMetrics(data)
+	case domain.DNSResult:
+		metrics := map[string]interface{}{
+			"records":       float64(len(data.Records)),
+			"resolves":      len(data.Records) > 0,
+			"response_time": data.ResponseTime,
+		}
+		for _, record := range data.Records {
+			key := strings.ToLower(dns.GetRecordTypeString(record.Type)) + "_records"
+			count, _ := metrics[key].(float64)
+			metrics[key] = count + 1
+		}
+		return metrics
+	case domain.WHOISResult:
+		return map[string]interface{}{
+			"registrar":         data.Registrar,
+			"days_until_expiry": daysUntil(data.Expires, now),
+			"name_servers":      float64(len(data.NameServers)),
+		}
+	case domain.SSLResult:
+		return map[string]interface{}{
+			"valid":             data.Valid,
+			"expired":           now.After(data.Expiry),
+			"days_until_expiry": daysUntil(data.Expiry, now),
+			"errors":            float64(len(data.Errors)),
+			"issuer":            data.Issuer,
+		}
+	case domain.DualStackResult:
+		return map[string]interface{}{
+			"ipv4_connected":    data.IPv4.Success,
+			"ipv6_connected":    data.IPv6.Success,
+			"ipv4_connect_time": data.IPv4.ConnectTime,
+			"ipv6_connect_time": data.IPv6.ConnectTime,
+			"winner":            data.Winner,
+		}
+	case domain.SweepResult:
+		return map[string]interface{}{
+			"alive":   float64(len(data.Hosts)),
+			"scanned": float64(data.Scanned),
+		}
+	case domain.ZoneTransferResult:
+		return map[string]interface{}{
+			"exposed": float64(len(data.Exposed())),
+			"servers": float64(len(data.Servers)),
+		}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// pingMetrics summarises ping replies into loss and latency metrics
+func pingMetrics(results []domain.PingResult) map[string]interface{} {
+	var samples []time.Duration
+	for _, result := range results {
+		if result.Error == nil {
+			samples = append(samples, result.RTT)
+		}
+	}
+	summary := stats.Summarize(samples)
+
+	return map[string]interface{}{
+		"sent":        float64(len(results)),
+		"received":    float64(len(samples)),
+		"packet_loss": stats.Loss(len(results), len(samples)),
+		"reachable":   len(samples) > 0,
+		"avg_rtt":     summary.Mean,
+		"min_rtt":     summary.Min,
+		"max_rtt":     summary.Max,
+		"jitter":      summary.Jitter,
+	}
+}
+
+// traceMetrics describes the path length and whether the destination answered
+func traceMetrics(hops []domain.TraceHop) map[string]interface{} {
+	metrics := map[string]interface{}{
+		"hops":      float64(len(hops)),
+		"reached":   false,
+		"final_rtt": time.Duration(0),
+	}
+	if len(hops) > 0 {
+		last := hops[len(hops)-1]
+		metrics["reached"] = !last.Timeout
+		metrics["final_rtt"] = stats.Mean(last.RTT)
+	}
+	return metrics
+}
+
+// daysUntil returns the whole days from now until t, negative once t has passed
+func daysUntil(t, now time.Time) float64 {
+	return float64(int(t.Sub(now).Hours() / 24))
+}
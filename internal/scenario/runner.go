@@ -0,0 +1,126 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/batch"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// Runner executes scenario steps using the tools of a plugin registry
+type Runner struct {
+	registry domain.PluginRegistry
+	shared   map[string]string
+	logger   domain.Logger
+	now      func() time.Time
+}
+
+// NewRunner creates a scenario runner. Shared options apply to every step
+// unless the step sets the same option itself.
+func NewRunner(registry domain.PluginRegistry, shared map[string]string, logger domain.Logger) *Runner {
+	return &Runner{
+		registry: registry,
+		shared:   shared,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// Run executes the steps in order and returns a pass/fail report. With
+// stop_on_failure set, steps after the first failure are skipped.
+func (r *Runner) Run(ctx context.Context, scenario *Scenario) domain.Result {
+	start := r.now()
+	r.logger.Info("Running scenario", "name", scenario.Name, "steps", len(scenario.Steps))
+
+	report := domain.ScenarioResult{Name: scenario.Name}
+	failed := false
+	for _, step := range scenario.Steps {
+		if (failed && scenario.StopOnFailure) || ctx.Err() != nil {
+			report.Steps = append(report.Steps, domain.ScenarioStepResult{
+				Name:    step.DisplayName(),
+				Tool:    step.Tool,
+				Target:  step.Target,
+				Skipped: true,
+			})
+			continue
+		}
+
+		outcome := r.runStep(ctx, step)
+		if !outcome.Passed {
+			failed = true
+		}
+		report.Steps = append(report.Steps, outcome)
+	}
+	report.Duration = r.now().Sub(start)
+	report.Timestamp = r.now()
+
+	result := domain.NewResult(report)
+	result.SetMetadata("tool", "scenario")
+	result.SetMetadata("scenario", scenario.Name)
+	result.SetMetadata("passed", report.Passed())
+	result.SetMetadata("steps", len(report.Steps))
+	result.SetMetadata("timestamp", report.Timestamp)
+
+	r.logger.Info("Scenario completed", "name", scenario.Name, "passed", report.Passed(), "steps_passed", report.PassedSteps())
+	return result
+}
+
+// runStep executes one step and evaluates its assertions
+func (r *Runner) runStep(ctx context.Context, step Step) (outcome domain.ScenarioStepResult) {
+	outcome = domain.ScenarioStepResult{
+		Name:   step.DisplayName(),
+		Tool:   step.Tool,
+		Target: step.Target,
+	}
+	start := r.now()
+	defer func() { outcome.Duration = r.now().Sub(start) }()
+
+	tool, exists := r.registry.Get(step.Tool)
+	if !exists {
+		outcome.Error = fmt.Sprintf("unknown tool: %s", step.Tool)
+		return outcome
+	}
+
+	options := make(map[string]string, len(r.shared)+len(step.Options))
+	for key, value := range r.shared {
+		options[key] = value
+	}
+	for key, value := range step.Options {
+		options[key] = value
+	}
+
+	params, err := batch.BuildParameters(tool, step.Target, options)
+	if err != nil {
+		outcome.Error = err.Error()
+		return outcome
+	}
+
+	if step.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, step.Timeout)
+		defer cancel()
+	}
+
+	result, err := tool.Execute(ctx, params)
+	if err != nil {
+		r.logger.Debug("Scenario step failed", "step", outcome.Name, "error", err)
+		outcome.Error = err.Error()
+		return outcome
+	}
+
+	metrics := Metrics(result.Data(), r.now())
+	outcome.Passed = true
+	for _, expression := range step.Assert {
+		// Expressions were checked when the scenario was loaded
+		assertion, _ := ParseAssertion(expression)
+		evaluated := assertion.Evaluate(metrics)
+		if !evaluated.Passed {
+			outcome.Passed = false
+		}
+		outcome.Assertions = append(outcome.Assertions, evaluated)
+	}
+
+	return outcome
+}
@@ -0,0 +1,108 @@
+// Package scenario runs sequences of diagnostic tools with assertions defined in YAML.
+//
+// A scenario file lists steps that run in order, for example:
+//
+//	name: example.com health
+//	stop_on_failure: true
+//	steps:
+//	  - tool: dns
+//	    target: example.com
+//	    options:
+//	      record_types: A,AAAA
+//	    assert:
+//	      - resolves == true
+//	  - tool: ping
+//	    target: example.com
+//	    options:
+//	      count: 5
+//	    assert:
+//	      - avg_rtt < 50ms
+//	      - packet_loss <= 0
+//	  - tool: ssl
+//	    target: example.com
+//	    assert:
+//	      - valid == true
+//	      - days_until_expiry > 30
+package scenario
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario is a named sequence of tool invocations
+type Scenario struct {
+	Name          string `yaml:"name"`
+	Description   string `yaml:"description"`
+	StopOnFailure bool   `yaml:"stop_on_failure"`
+	Steps         []Step `yaml:"steps"`
+}
+
+// Step runs one tool against one target and checks assertions on its result
+type Step struct {
+	Name    string            `yaml:"name"`
+	Tool    string            `yaml:"tool"`
+	Target  string            `yaml:"target"`
+	Options map[string]string `yaml:"options"`
+	Timeout time.Duration     `yaml:"timeout"`
+	Assert  []string          `yaml:"assert"`
+}
+
+// DisplayName returns the step name, or "<tool> <target>" when it has none
+func (s Step) DisplayName() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return fmt.Sprintf("%s %s", s.Tool, s.Target)
+}
+
+// Load reads and validates a scenario file
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes and validates a YAML scenario
+func Parse(data []byte) (*Scenario, error) {
+	var scenario Scenario
+	if err := yaml.Unmarshal(data, &scenario); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario: %w", err)
+	}
+	if err := scenario.Validate(); err != nil {
+		return nil, err
+	}
+	return &scenario, nil
+}
+
+// Validate checks that every step names a tool and target and that its assertions parse
+func (s *Scenario) Validate() error {
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("scenario must define at least one step")
+	}
+
+	for i, step := range s.Steps {
+		if strings.TrimSpace(step.Tool) == "" {
+			return fmt.Errorf("step %d: tool is required", i+1)
+		}
+		if strings.TrimSpace(step.Target) == "" {
+			return fmt.Errorf("step %d: target is required", i+1)
+		}
+		if step.Timeout < 0 {
+			return fmt.Errorf("step %d: timeout must be positive", i+1)
+		}
+		for _, expression := range step.Assert {
+			if _, err := ParseAssertion(expression); err != nil {
+				return fmt.Errorf("step %d: %w", i+1, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,262 @@
+package scenario
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/network"
+	"github.com/nettracex/nettracex-tui/internal/tools/dns"
+	"github.com/nettracex/nettracex-tui/internal/tools/ping"
+	"github.com/nettracex/nettracex-tui/internal/tools/ssl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testLogger implements domain.Logger and discards all output
+type testLogger struct{}
+
+func (testLogger) Debug(msg string, fields ...interface{}) {}
+func (testLogger) Info(msg string, fields ...interface{})  {}
+func (testLogger) Warn(msg string, fields ...interface{})  {}
+func (testLogger) Error(msg string, fields ...interface{}) {}
+func (testLogger) Fatal(msg string, fields ...interface{}) {}
+
+// testRegistry is a minimal plugin registry for scenario tests
+type testRegistry map[string]domain.DiagnosticTool
+
+func (r testRegistry) Register(tool domain.DiagnosticTool) error {
+	r[tool.Name()] = tool
+	return nil
+}
+
+func (r testRegistry) Get(name string) (domain.DiagnosticTool, bool) {
+	tool, exists := r[name]
+	return tool, exists
+}
+
+func (r testRegistry) List() []domain.DiagnosticTool {
+	var tools []domain.DiagnosticTool
+	for _, tool := range r {
+		tools = append(tools, tool)
+	}
+	return tools
+}
+
+func (r testRegistry) Unregister(name string) error {
+	delete(r, name)
+	return nil
+}
+
+const healthScenario = `
+name: example.com health
+stop_on_failure: true
+steps:
+  - tool: dns
+    target: example.com
+    options:
+      record_types: A
+    assert:
+      - resolves == true
+      - a_records >= 1
+  - name: latency
+    tool: ping
+    target: example.com
+    options:
+      count: 3
+      interval: 1ms
+    assert:
+      - avg_rtt < 50ms
+      - packet_loss <= 0%
+  - tool: ssl
+    target: example.com
+    timeout: 10s
+    assert:
+      - valid == true
+      - days_until_expiry > 30
+`
+
+// newHealthRegistry creates dns, ping and ssl tools where example.com is healthy
+// apart from a ping latency of pingRTT
+func newHealthRegistry(pingRTT time.Duration) testRegistry {
+	client := network.NewMockClient()
+	client.SetDNSResponse("example.com", domain.DNSRecordTypeA, domain.DNSResult{
+		Query:   "example.com",
+		Records: []domain.DNSRecord{{Name: "example.com", Type: domain.DNSRecordTypeA, Value: "93.184.216.34", TTL: 300}},
+	})
+	client.SetPingResponse("example.com", []domain.PingResult{
+		{Sequence: 1, RTT: pingRTT},
+		{Sequence: 2, RTT: pingRTT},
+		{Sequence: 3, RTT: pingRTT},
+	})
+	client.SetSSLResponse("example.com", 443, domain.SSLResult{
+		Host:   "example.com",
+		Port:   443,
+		Valid:  true,
+		Expiry: time.Now().Add(90 * 24 * time.Hour),
+	})
+
+	registry := testRegistry{}
+	registry.Register(dns.NewTool(client, testLogger{}))
+	registry.Register(ping.NewTool(client, testLogger{}))
+	registry.Register(ssl.NewTool(client, testLogger{}))
+	return registry
+}
+
+func TestParse(t *testing.T) {
+	scenario, err := Parse([]byte(healthScenario))
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com health", scenario.Name)
+	assert.True(t, scenario.StopOnFailure)
+	require.Len(t, scenario.Steps, 3)
+	assert.Equal(t, "dns example.com", scenario.Steps[0].DisplayName())
+	assert.Equal(t, "latency", scenario.Steps[1].DisplayName())
+	assert.Equal(t, map[string]string{"count": "3", "interval": "1ms"}, scenario.Steps[1].Options)
+	assert.Equal(t, 10*time.Second, scenario.Steps[2].Timeout)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		scenario string
+		wantErr  string
+	}{
+		{"no steps", "name: empty\n", "at least one step"},
+		{"missing tool", "steps:\n  - target: example.com\n", "step 1: tool is required"},
+		{"missing target", "steps:\n  - tool: ping\n", "step 1: target is required"},
+		{"bad assertion", "steps:\n  - tool: ping\n    target: example.com\n    assert:\n      - fast\n", "invalid assertion"},
+		{"bad yaml", "steps: [", "failed to parse scenario"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.scenario))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestAssertion_Evaluate(t *testing.T) {
+	metrics := map[string]interface{}{
+		"avg_rtt":     20 * time.Millisecond,
+		"packet_loss": 25.0,
+		"valid":       true,
+		"winner":      "ipv6",
+	}
+
+	tests := []struct {
+		expression string
+		passed     bool
+		wantErr    bool
+	}{
+		{"avg_rtt < 50ms", true, false},
+		{"avg_rtt >= 1s", false, false},
+		{"packet_loss <= 30%", true, false},
+		{"packet_loss == 0", false, false},
+		{"valid == true", true, false},
+		{"valid != true", false, false},
+		{"winner == ipv6", true, false},
+		{"winner == \"ipv4\"", false, false},
+		{"avg_rtt < fast", false, true},
+		{"valid > false", false, true},
+		{"hops > 3", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			assertion, err := ParseAssertion(tt.expression)
+			require.NoError(t, err)
+
+			outcome := assertion.Evaluate(metrics)
+			assert.Equal(t, tt.passed, outcome.Passed)
+			assert.Equal(t, tt.wantErr, outcome.Error != "", outcome.Error)
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	pingMetrics := Metrics([]domain.PingResult{
+		{RTT: 10 * time.Millisecond},
+		{RTT: 30 * time.Millisecond},
+		{Error: errors.New("timeout")},
+		{RTT: 20 * time.Millisecond},
+	}, now)
+	assert.Equal(t, 20*time.Millisecond, pingMetrics["avg_rtt"])
+	assert.Equal(t, 25.0, pingMetrics["packet_loss"])
+	assert.Equal(t, true, pingMetrics["reachable"])
+
+	sslMetrics := Metrics(domain.SSLResult{Valid: true, Expiry: now.Add(45*24*time.Hour + time.Hour)}, now)
+	assert.Equal(t, 45.0, sslMetrics["days_until_expiry"])
+	assert.Equal(t, false, sslMetrics["expired"])
+
+	traceMetrics := Metrics([]domain.TraceHop{
+		{Number: 1, RTT: []time.Duration{time.Millisecond}},
+		{Number: 2, Timeout: true},
+	}, now)
+	assert.Equal(t, 2.0, traceMetrics["hops"])
+	assert.Equal(t, false, traceMetrics["reached"])
+}
+
+func TestRunner_Run(t *testing.T) {
+	scenario, err := Parse([]byte(healthScenario))
+	require.NoError(t, err)
+
+	t.Run("all steps pass", func(t *testing.T) {
+		runner := NewRunner(newHealthRegistry(10*time.Millisecond), nil, testLogger{})
+		result := runner.Run(context.Background(), scenario)
+
+		report, ok := result.Data().(domain.ScenarioResult)
+		require.True(t, ok)
+		assert.True(t, report.Passed())
+		assert.Equal(t, 3, report.PassedSteps())
+		assert.Equal(t, "10ms", report.Steps[1].Assertions[0].Actual)
+		assert.Equal(t, true, result.Metadata()["passed"])
+	})
+
+	t.Run("failure skips remaining steps", func(t *testing.T) {
+		runner := NewRunner(newHealthRegistry(80*time.Millisecond), nil, testLogger{})
+		result := runner.Run(context.Background(), scenario)
+
+		report := result.Data().(domain.ScenarioResult)
+		assert.False(t, report.Passed())
+		assert.Equal(t, "PASS", report.Steps[0].Status())
+		assert.Equal(t, "FAIL", report.Steps[1].Status())
+		assert.False(t, report.Steps[1].Assertions[0].Passed)
+		assert.True(t, report.Steps[1].Assertions[1].Passed)
+		assert.Equal(t, "SKIP", report.Steps[2].Status())
+	})
+
+	t.Run("unknown tool fails the step", func(t *testing.T) {
+		runner := NewRunner(testRegistry{}, nil, testLogger{})
+		result := runner.Run(context.Background(), &Scenario{Steps: []Step{{Tool: "mtr", Target: "example.com"}}})
+
+		report := result.Data().(domain.ScenarioResult)
+		assert.False(t, report.Passed())
+		assert.Equal(t, "unknown tool: mtr", report.Steps[0].Error)
+	})
+}
+
+func TestRunner_ReportExport(t *testing.T) {
+	scenario, err := Parse([]byte(healthScenario))
+	require.NoError(t, err)
+
+	runner := NewRunner(newHealthRegistry(80*time.Millisecond), nil, testLogger{})
+	result := runner.Run(context.Background(), scenario)
+
+	text, err := result.Export(domain.ExportFormatText)
+	require.NoError(t, err)
+	assert.Contains(t, string(text), "Scenario example.com health: FAIL (1 of 3 steps passed)")
+	assert.Contains(t, string(text), "FAILED: avg_rtt < 50ms (actual 80ms)")
+	assert.Contains(t, string(text), "[SKIP] ssl example.com")
+
+	csv, err := result.Export(domain.ExportFormatCSV)
+	require.NoError(t, err)
+	assert.Contains(t, string(csv), "latency,ping,example.com,FAIL,")
+	assert.Contains(t, string(csv), "avg_rtt < 50ms,80ms,false,")
+}
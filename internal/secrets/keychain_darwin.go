@@ -0,0 +1,68 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// errSecItemNotFound is the exit status of security(1) for a missing item
+const errSecItemNotFound = 44
+
+// securityStore keeps secrets as generic passwords in the login Keychain
+// through the security(1) command line tool
+type securityStore struct{}
+
+func newKeychain() Store {
+	return securityStore{}
+}
+
+// run runs security with args and input on stdin and returns its output
+func (securityStore) run(input string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("security", args...)
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == errSecItemNotFound {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("security %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// Get implements Store
+func (s securityStore) Get(name string) (string, error) {
+	out, err := s.run("", "find-generic-password", "-s", Service, "-a", name, "-w")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(out, "\n"), nil
+}
+
+// Set implements Store, updating an existing item. The command is read
+// by security's interactive mode from stdin, with the value hex encoded,
+// so the secret never appears on a command line where ps could show it.
+func (s securityStore) Set(name, value string) error {
+	command := fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n",
+		quote(Service), quote(name), hex.EncodeToString([]byte(value)))
+	_, err := s.run(command, "-i")
+	return err
+}
+
+// quote quotes an argument for security's interactive mode
+func quote(arg string) string {
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// Delete implements Store
+func (s securityStore) Delete(name string) error {
+	_, err := s.run("", "delete-generic-password", "-s", Service, "-a", name)
+	return err
+}
@@ -0,0 +1,66 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// secretToolStore keeps secrets in the Secret Service of the desktop
+// session (GNOME Keyring, KWallet) through libsecret's secret-tool(1), so
+// they persist across reboots. Secret values are passed on stdin and never
+// appear on a command line.
+type secretToolStore struct{}
+
+func newKeychain() Store {
+	return secretToolStore{}
+}
+
+// attributes returns the attributes identifying the secret name
+func (secretToolStore) attributes(name string) []string {
+	return []string{"service", Service, "account", name}
+}
+
+// run runs secret-tool with args and input on stdin and returns its output.
+// secret-tool exits with status 1 and prints nothing when an item is
+// missing.
+func (secretToolStore) run(input string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", args...)
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && stderr.Len() == 0 && stdout.Len() == 0 {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("secret-tool %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// Get implements Store
+func (s secretToolStore) Get(name string) (string, error) {
+	return s.run("", append([]string{"lookup"}, s.attributes(name)...)...)
+}
+
+// Set implements Store. Storing a secret with the same attributes
+// replaces its value.
+func (s secretToolStore) Set(name, value string) error {
+	args := append([]string{"store", "--label", Service + " " + name}, s.attributes(name)...)
+	_, err := s.run(value, args...)
+	return err
+}
+
+// Delete implements Store
+func (s secretToolStore) Delete(name string) error {
+	// clear succeeds whether or not the item exists
+	if _, err := s.run("", append([]string{"lookup"}, s.attributes(name)...)...); err != nil {
+		return err
+	}
+	_, err := s.run("", append([]string{"clear"}, s.attributes(name)...)...)
+	return err
+}
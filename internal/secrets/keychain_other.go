@@ -0,0 +1,19 @@
+//go:build !linux && !darwin && !windows
+
+package secrets
+
+// unsupportedStore reports that secrets cannot be stored on this platform
+type unsupportedStore struct{}
+
+func newKeychain() Store {
+	return unsupportedStore{}
+}
+
+// Get implements Store
+func (unsupportedStore) Get(name string) (string, error) { return "", ErrUnsupported }
+
+// Set implements Store
+func (unsupportedStore) Set(name, value string) error { return ErrUnsupported }
+
+// Delete implements Store
+func (unsupportedStore) Delete(name string) error { return ErrUnsupported }
@@ -0,0 +1,96 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// dpapiStore keeps each secret in a file under the user's configuration
+// directory, encrypted with DPAPI so only the same Windows user can read it
+type dpapiStore struct{}
+
+func newKeychain() Store {
+	return dpapiStore{}
+}
+
+// path returns the file holding the secret name
+func (dpapiStore) path(name string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, Service, "secrets", name+".dpapi"), nil
+}
+
+// Get implements Store
+func (s dpapiStore) Get(name string) (string, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", ErrNotFound
+	} else if err != nil {
+		return "", err
+	}
+	plain, err := dpapi(data, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret %s: %w", name, err)
+	}
+	return string(plain), nil
+}
+
+// Set implements Store
+func (s dpapiStore) Set(name, value string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	data, err := dpapi([]byte(value), true)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt secret %s: %w", name, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Delete implements Store
+func (s dpapiStore) Delete(name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// dpapi encrypts or decrypts data for the current user
+func dpapi(data []byte, encrypt bool) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	in := windows.DataBlob{Size: uint32(len(data)), Data: &data[0]}
+	var out windows.DataBlob
+	var err error
+	if encrypt {
+		err = windows.CryptProtectData(&in, nil, nil, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out)
+	} else {
+		err = windows.CryptUnprotectData(&in, nil, nil, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer windows.LocalFree(windows.Handle(unsafe.Pointer(out.Data)))
+	return append([]byte(nil), unsafe.Slice(out.Data, out.Size)...), nil
+}
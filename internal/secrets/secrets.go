@@ -0,0 +1,111 @@
+// Package secrets keeps credentials such as tokens and webhook URLs in the
+// operating system keychain, so the configuration file only has to hold a
+// reference to them instead of the plain text value.
+package secrets
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// Service names the keychain entries of NetTraceX
+const Service = "nettracex"
+
+// ReferencePrefix marks a configuration value that names a keychain entry,
+// e.g. keychain:notify.slack_webhook
+const ReferencePrefix = "keychain:"
+
+var (
+	// ErrNotFound is returned when the keychain has no entry of that name
+	ErrNotFound = errors.New("secret not found in keychain")
+	// ErrUnsupported is returned on platforms without a supported keychain
+	ErrUnsupported = errors.New("no keychain available on this platform")
+)
+
+// Store reads and writes named secrets
+type Store interface {
+	Get(name string) (string, error)
+	Set(name, value string) error
+	Delete(name string) error
+}
+
+// Default returns the keychain of the operating system: the Secret Service
+// through secret-tool on Linux, the login Keychain on macOS and
+// DPAPI-encrypted files on Windows.
+func Default() Store {
+	return newKeychain()
+}
+
+// Reference returns the configuration value referring to the secret name
+func Reference(name string) string {
+	return ReferencePrefix + name
+}
+
+// ParseReference returns the secret name a configuration value refers to
+func ParseReference(value string) (string, bool) {
+	if !strings.HasPrefix(value, ReferencePrefix) {
+		return "", false
+	}
+	name := strings.TrimSpace(strings.TrimPrefix(value, ReferencePrefix))
+	return name, name != ""
+}
+
+// Resolve returns value, or the secret it refers to if it is a reference
+func Resolve(store Store, value string) (string, error) {
+	name, ok := ParseReference(value)
+	if !ok {
+		return value, nil
+	}
+	return store.Get(name)
+}
+
+// Mask hides a secret for display, keeping only whether it is set
+func Mask(value string) string {
+	if value == "" {
+		return ""
+	}
+	return strings.Repeat("•", 8)
+}
+
+// MemoryStore keeps secrets in memory, for tests and as a stand-in where
+// no keychain is available
+type MemoryStore struct {
+	mu      sync.RWMutex
+	secrets map[string]string
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{secrets: make(map[string]string)}
+}
+
+// Get implements Store
+func (s *MemoryStore) Get(name string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.secrets[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// Set implements Store
+func (s *MemoryStore) Set(name, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.secrets[name] = value
+	return nil
+}
+
+// Delete implements Store
+func (s *MemoryStore) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.secrets[name]; !ok {
+		return ErrNotFound
+	}
+	delete(s.secrets, name)
+	return nil
+}
@@ -0,0 +1,74 @@
+package secrets
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseReference(t *testing.T) {
+	name, ok := ParseReference(Reference("notify.slack_webhook"))
+	if !ok || name != "notify.slack_webhook" {
+		t.Errorf("Expected notify.slack_webhook, got %q (%v)", name, ok)
+	}
+	for _, value := range []string{"", "https://hooks.slack.com/x", "keychain:", "keychain:  "} {
+		if _, ok := ParseReference(value); ok {
+			t.Errorf("Expected %q not to be a reference", value)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	store := NewMemoryStore()
+	store.Set("agent.token", "s3cret")
+
+	if value, err := Resolve(store, "plain"); err != nil || value != "plain" {
+		t.Errorf("Expected plain values to pass through, got %q, %v", value, err)
+	}
+	if value, err := Resolve(store, "keychain:agent.token"); err != nil || value != "s3cret" {
+		t.Errorf("Expected the stored secret, got %q, %v", value, err)
+	}
+	if _, err := Resolve(store, "keychain:missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestKeychain(t *testing.T) {
+	store := Default()
+	if err := store.Set("test.probe", "probe"); err != nil {
+		t.Skipf("Keychain not available: %v", err)
+	}
+	store.Delete("test.probe")
+	testStore(t, store)
+}
+
+func testStore(t *testing.T, store Store) {
+	t.Helper()
+	const name = "test.secret"
+	store.Delete(name)
+
+	if _, err := store.Get(name); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound before Set, got %v", err)
+	}
+	if err := store.Set(name, "first"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := store.Set(name, "second"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if value, err := store.Get(name); err != nil || value != "second" {
+		t.Errorf("Expected the replaced value, got %q, %v", value, err)
+	}
+	if err := store.Delete(name); err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+	if _, err := store.Get(name); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after Delete, got %v", err)
+	}
+	if err := store.Delete(name); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound deleting a missing secret, got %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// Kinds of saved results, one per result data type
+const (
+	KindPing         = "ping"
+	KindMultiPing    = "multiping"
+	KindTrace        = "traceroute"
+	KindDNS          = "dns"
+	KindWHOIS        = "whois"
+	KindSSL          = "ssl"
+	KindDualStack    = "dualstack"
+	KindSweep        = "sweep"
+	KindZoneTransfer = "axfr"
+	KindPlugin       = "plugin"
+)
+
+// ErrUnsupportedResult is returned for results that cannot be saved
+var ErrUnsupportedResult = errors.New("result type cannot be saved in a session")
+
+// savedPingResult replaces the error of a ping reply with its message,
+// since error values do not survive a JSON round trip
+type savedPingResult struct {
+	domain.PingResult
+	Error string `json:"error,omitempty"`
+}
+
+// savedPingTarget replaces the errors of a multi-ping target with their messages
+type savedPingTarget struct {
+	domain.PingTargetResult
+	Results []savedPingResult `json:"results"`
+	Error   string            `json:"error,omitempty"`
+}
+
+// savedSSLResult omits the parsed certificates, which cannot be decoded from JSON
+type savedSSLResult struct {
+	domain.SSLResult
+	Certificate *struct{}  `json:"certificate,omitempty"`
+	Chain       []struct{} `json:"chain,omitempty"`
+}
+
+// NewEntry encodes result for saving
+func NewEntry(result domain.Result, timestamp time.Time) (Entry, error) {
+	var kind string
+	var data interface{}
+	switch value := result.Data().(type) {
+	case []domain.PingResult:
+		kind, data = KindPing, savePingResults(value)
+	case domain.MultiPingResult:
+		targets := make([]savedPingTarget, len(value.Targets))
+		for i, target := range value.Targets {
+			targets[i] = savedPingTarget{PingTargetResult: target, Results: savePingResults(target.Results), Error: errorMessage(target.Error)}
+		}
+		kind, data = KindMultiPing, targets
+	case []domain.TraceHop:
+		kind, data = KindTrace, value
+	case domain.DNSResult:
+		kind, data = KindDNS, value
+	case domain.WHOISResult:
+		kind, data = KindWHOIS, value
+	case domain.SSLResult:
+		kind, data = KindSSL, savedSSLResult{SSLResult: value}
+	case domain.DualStackResult:
+		kind, data = KindDualStack, value
+	case domain.SweepResult:
+		kind, data = KindSweep, value
+	case domain.ZoneTransferResult:
+		kind, data = KindZoneTransfer, value
+	case domain.PluginResult:
+		kind, data = KindPlugin, value
+	default:
+		return Entry{}, fmt.Errorf("%w: %T", ErrUnsupportedResult, result.Data())
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to encode %s result: %w", kind, err)
+	}
+	return Entry{
+		Kind:      kind,
+		Timestamp: timestamp,
+		Metadata:  saveMetadata(result.Metadata()),
+		Data:      raw,
+	}, nil
+}
+
+// Result decodes the saved result, restoring its scalar metadata and timestamp
+func (e Entry) Result() (domain.Result, error) {
+	var data interface{}
+	var err error
+	switch e.Kind {
+	case KindPing:
+		var saved []savedPingResult
+		err = json.Unmarshal(e.Data, &saved)
+		data = loadPingResults(saved)
+	case KindMultiPing:
+		var saved []savedPingTarget
+		err = json.Unmarshal(e.Data, &saved)
+		targets := make([]domain.PingTargetResult, len(saved))
+		for i, target := range saved {
+			targets[i] = target.PingTargetResult
+			targets[i].Results = loadPingResults(target.Results)
+			targets[i].Error = messageError(target.Error)
+		}
+		data = domain.MultiPingResult{Targets: targets}
+	case KindTrace:
+		var hops []domain.TraceHop
+		err = json.Unmarshal(e.Data, &hops)
+		data = hops
+	case KindDNS:
+		var result domain.DNSResult
+		err = json.Unmarshal(e.Data, &result)
+		data = result
+	case KindWHOIS:
+		var result domain.WHOISResult
+		err = json.Unmarshal(e.Data, &result)
+		data = result
+	case KindSSL:
+		var saved savedSSLResult
+		err = json.Unmarshal(e.Data, &saved)
+		data = saved.SSLResult
+	case KindDualStack:
+		var result domain.DualStackResult
+		err = json.Unmarshal(e.Data, &result)
+		data = result
+	case KindSweep:
+		var result domain.SweepResult
+		err = json.Unmarshal(e.Data, &result)
+		data = result
+	case KindZoneTransfer:
+		var result domain.ZoneTransferResult
+		err = json.Unmarshal(e.Data, &result)
+		data = result
+	case KindPlugin:
+		var result domain.PluginResult
+		err = json.Unmarshal(e.Data, &result)
+		data = result
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedResult, e.Kind)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s result: %w", e.Kind, err)
+	}
+
+	result := domain.NewResult(data)
+	for key, value := range e.Metadata {
+		result.SetMetadata(key, loadMetadataValue(value))
+	}
+	result.SetMetadata("timestamp", e.Timestamp)
+	return result, nil
+}
+
+// savePingResults converts ping replies to their saved form
+func savePingResults(results []domain.PingResult) []savedPingResult {
+	saved := make([]savedPingResult, len(results))
+	for i, result := range results {
+		saved[i] = savedPingResult{PingResult: result, Error: errorMessage(result.Error)}
+	}
+	return saved
+}
+
+// loadPingResults converts saved ping replies back to domain results
+func loadPingResults(saved []savedPingResult) []domain.PingResult {
+	results := make([]domain.PingResult, len(saved))
+	for i, result := range saved {
+		results[i] = result.PingResult
+		results[i].Error = messageError(result.Error)
+	}
+	return results
+}
+
+// saveMetadata keeps the scalar and string list metadata values; structured
+// values such as statistics are optional for the views and the timestamp is
+// saved separately
+func saveMetadata(metadata map[string]interface{}) map[string]interface{} {
+	saved := make(map[string]interface{})
+	for key, value := range metadata {
+		if key == "timestamp" {
+			continue
+		}
+		switch value.(type) {
+		case string, []string, bool, int, int64, uint32, float64:
+			saved[key] = value
+		}
+	}
+	if len(saved) == 0 {
+		return nil
+	}
+	return saved
+}
+
+// loadMetadataValue restores whole numbers decoded as float64 to ints and
+// lists decoded as []interface{} to string lists
+func loadMetadataValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case float64:
+		if value == float64(int(value)) {
+			return int(value)
+		}
+	case []interface{}:
+		list := make([]string, 0, len(value))
+		for _, item := range value {
+			list = append(list, fmt.Sprint(item))
+		}
+		return list
+	}
+	return value
+}
+
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func messageError(message string) error {
+	if message == "" {
+		return nil
+	}
+	return errors.New(message)
+}
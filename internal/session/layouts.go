@@ -0,0 +1,71 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LayoutsFileName is the name of the table layouts file in the configuration
+// directory
+const LayoutsFileName = "tables.json"
+
+// TableLayout is how the user arranged the columns of a result table. Columns
+// are named by their header so a layout survives columns being added later.
+type TableLayout struct {
+	Order      []string       `json:"order,omitempty"`
+	Hidden     []string       `json:"hidden,omitempty"`
+	Widths     map[string]int `json:"widths,omitempty"`
+	SortColumn string         `json:"sort_column,omitempty"`
+	Descending bool           `json:"descending,omitempty"`
+}
+
+// Layouts holds the result table layout of each tool. Unlike the session,
+// layouts are kept whether or not the session is restored.
+type Layouts map[string]TableLayout
+
+// LayoutsPath returns the table layouts file in the nettracex configuration
+// directory
+func LayoutsPath() string {
+	return filepath.Join(filepath.Dir(DefaultPath()), LayoutsFileName)
+}
+
+// LoadLayouts reads the table layouts at path; a missing file yields no layouts
+func LoadLayouts(path string) (Layouts, error) {
+	layouts := make(Layouts)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return layouts, nil
+	}
+	if err != nil {
+		return layouts, fmt.Errorf("failed to read table layouts: %w", err)
+	}
+	if err := json.Unmarshal(data, &layouts); err != nil {
+		return make(Layouts), fmt.Errorf("failed to parse table layouts %s: %w", path, err)
+	}
+	return layouts, nil
+}
+
+// SaveLayouts writes the table layouts to path, replacing the previous file
+// atomically
+func SaveLayouts(path string, layouts Layouts) error {
+	data, err := json.MarshalIndent(layouts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode table layouts: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create table layouts directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write table layouts: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write table layouts: %w", err)
+	}
+	return nil
+}
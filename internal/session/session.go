@@ -0,0 +1,137 @@
+// Package session persists the state of the interactive TUI between runs:
+// the tool that was open, the values entered in each tool's form and the
+// recent results of each tool, so an interrupted session can be restored.
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/appdir"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// Version is the format version of saved sessions; sessions written with
+// another version are ignored rather than misread
+const Version = 1
+
+// FileName is the name of the session file in the configuration directory
+const FileName = "session.json"
+
+// Session is the saved state of the TUI. Running records that the active
+// tool was still running and Result that it was showing its last result.
+type Session struct {
+	Version    int                          `json:"version"`
+	SavedAt    time.Time                    `json:"saved_at"`
+	ActiveTool string                       `json:"active_tool,omitempty"`
+	Running    bool                         `json:"running,omitempty"`
+	Result     bool                         `json:"result,omitempty"`
+	Forms      map[string]map[string]string `json:"forms,omitempty"`
+	History    map[string][]Entry           `json:"history,omitempty"`
+}
+
+// Entry is a saved result of a tool
+type Entry struct {
+	Kind      string                 `json:"kind"`
+	Timestamp time.Time              `json:"timestamp"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	Data      json.RawMessage        `json:"data"`
+}
+
+// New creates an empty session
+func New() *Session {
+	return &Session{
+		Version: Version,
+		Forms:   make(map[string]map[string]string),
+		History: make(map[string][]Entry),
+	}
+}
+
+// DefaultPath returns the session file in the nettracex configuration directory
+func DefaultPath() string {
+	return appdir.ConfigPath(FileName)
+}
+
+// Empty reports whether the session holds nothing worth restoring
+func (s *Session) Empty() bool {
+	if s.ActiveTool != "" {
+		return false
+	}
+	for _, entries := range s.History {
+		if len(entries) > 0 {
+			return false
+		}
+	}
+	return len(s.Forms) == 0
+}
+
+// AddResult saves a result of tool; results of unknown types are skipped
+func (s *Session) AddResult(tool string, result domain.Result, timestamp time.Time) error {
+	entry, err := NewEntry(result, timestamp)
+	if err != nil {
+		return err
+	}
+	s.History[tool] = append(s.History[tool], entry)
+	return nil
+}
+
+// Load reads the session at path; a missing file or a session written by
+// another version yields nil without an error
+func Load(path string) (*Session, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+
+	var saved Session
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse session %s: %w", path, err)
+	}
+	if saved.Version != Version {
+		return nil, nil
+	}
+	if saved.Forms == nil {
+		saved.Forms = make(map[string]map[string]string)
+	}
+	if saved.History == nil {
+		saved.History = make(map[string][]Entry)
+	}
+	return &saved, nil
+}
+
+// Save writes the session to path, replacing any previous session atomically
+func Save(path string, s *Session) error {
+	s.Version = Version
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the session at path if there is one
+func Remove(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove session: %w", err)
+	}
+	return nil
+}
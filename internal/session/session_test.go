@@ -0,0 +1,188 @@
+package session
+
+import (
+	"crypto/x509"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+func TestSaveLoad_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", FileName)
+	timestamp := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	s := New()
+	s.ActiveTool = "ping"
+	s.Running = true
+	s.Forms["ping"] = map[string]string{"host": "example.com", "count": "0"}
+
+	result := domain.NewResult([]domain.PingResult{
+		{Host: domain.NetworkHost{Hostname: "example.com", IPAddress: net.ParseIP("93.184.216.34")}, Sequence: 1, RTT: 12 * time.Millisecond, TTL: 56},
+		{Host: domain.NetworkHost{Hostname: "example.com"}, Sequence: 2, Error: errors.New("request timeout")},
+	})
+	result.SetMetadata("tool", "ping")
+	result.SetMetadata("count", 2)
+	result.SetMetadata("networks", []string{"10.0.0.0/8"})
+	result.SetMetadata("statistics", struct{ Sent int }{2})
+	require.NoError(t, s.AddResult("ping", result, timestamp))
+
+	require.NoError(t, Save(path, s))
+	_, err := os.Stat(path + ".tmp")
+	assert.True(t, os.IsNotExist(err), "temporary file should be renamed into place")
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	require.NotNil(t, loaded)
+	assert.Equal(t, "ping", loaded.ActiveTool)
+	assert.True(t, loaded.Running)
+	assert.Equal(t, "example.com", loaded.Forms["ping"]["host"])
+	require.Len(t, loaded.History["ping"], 1)
+
+	restored, err := loaded.History["ping"][0].Result()
+	require.NoError(t, err)
+	replies, ok := restored.Data().([]domain.PingResult)
+	require.True(t, ok)
+	require.Len(t, replies, 2)
+	assert.Equal(t, 12*time.Millisecond, replies[0].RTT)
+	assert.True(t, replies[0].Host.IPAddress.Equal(net.ParseIP("93.184.216.34")))
+	assert.Nil(t, replies[0].Error)
+	require.Error(t, replies[1].Error)
+	assert.Equal(t, "request timeout", replies[1].Error.Error())
+
+	metadata := restored.Metadata()
+	assert.Equal(t, "ping", metadata["tool"])
+	assert.Equal(t, 2, metadata["count"])
+	assert.Equal(t, []string{"10.0.0.0/8"}, metadata["networks"])
+	assert.NotContains(t, metadata, "statistics")
+	assert.True(t, timestamp.Equal(metadata["timestamp"].(time.Time)))
+}
+
+func TestLoad_MissingOrOutdated(t *testing.T) {
+	dir := t.TempDir()
+
+	loaded, err := Load(filepath.Join(dir, FileName))
+	assert.NoError(t, err)
+	assert.Nil(t, loaded)
+
+	outdated := filepath.Join(dir, "old.json")
+	require.NoError(t, os.WriteFile(outdated, []byte(`{"version": 99, "active_tool": "ping"}`), 0600))
+	loaded, err = Load(outdated)
+	assert.NoError(t, err)
+	assert.Nil(t, loaded)
+
+	corrupt := filepath.Join(dir, "corrupt.json")
+	require.NoError(t, os.WriteFile(corrupt, []byte(`{`), 0600))
+	_, err = Load(corrupt)
+	assert.Error(t, err)
+}
+
+func TestRemove(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	require.NoError(t, Save(path, New()))
+
+	assert.NoError(t, Remove(path))
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+	assert.NoError(t, Remove(path), "removing a missing session is not an error")
+}
+
+func TestSession_Empty(t *testing.T) {
+	s := New()
+	assert.True(t, s.Empty())
+
+	s.Forms["dns"] = map[string]string{"domain": "example.com"}
+	assert.False(t, s.Empty())
+}
+
+func TestEntry_RoundTripKinds(t *testing.T) {
+	timestamp := time.Now().UTC().Truncate(time.Second)
+	tests := []struct {
+		name string
+		data interface{}
+		kind string
+	}{
+		{"multiping", domain.MultiPingResult{Targets: []domain.PingTargetResult{
+			{Host: "a.example", PacketsSent: 2, Results: []domain.PingResult{{Sequence: 1}}},
+			{Host: "b.example", Error: errors.New("unreachable")},
+		}}, KindMultiPing},
+		{"traceroute", []domain.TraceHop{{Number: 1, RTT: []time.Duration{time.Millisecond}}}, KindTrace},
+		{"dns", domain.DNSResult{Query: "example.com", Records: []domain.DNSRecord{{Name: "example.com", Value: "1.2.3.4"}}}, KindDNS},
+		{"whois", domain.WHOISResult{Domain: "example.com", Contacts: map[string]domain.Contact{"admin": {Name: "Admin"}}}, KindWHOIS},
+		{"ssl", domain.SSLResult{Host: "example.com", Port: 443, Certificate: &x509.Certificate{}, Valid: true}, KindSSL},
+		{"dualstack", domain.DualStackResult{Host: "example.com", Winner: domain.AddressFamilyIPv6}, KindDualStack},
+		{"sweep", domain.SweepResult{CIDR: "10.0.0.0/30", Hosts: []domain.SweepHost{{IP: net.ParseIP("10.0.0.1")}}}, KindSweep},
+		{"axfr", domain.ZoneTransferResult{Domain: "example.com", Servers: []domain.ZoneTransferServer{{Nameserver: "ns1", Allowed: true}}}, KindZoneTransfer},
+		{"plugin", domain.PluginResult{Tool: "http", Text: "200 OK", Data: map[string]interface{}{"status": 200.0}}, KindPlugin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := NewEntry(domain.NewResult(tt.data), timestamp)
+			require.NoError(t, err)
+			assert.Equal(t, tt.kind, entry.Kind)
+
+			restored, err := entry.Result()
+			require.NoError(t, err)
+			assert.IsType(t, tt.data, restored.Data())
+		})
+	}
+}
+
+func TestEntry_DropsCertificates(t *testing.T) {
+	entry, err := NewEntry(domain.NewResult(domain.SSLResult{
+		Host:        "example.com",
+		Certificate: &x509.Certificate{},
+		Chain:       []*x509.Certificate{{}},
+		SANs:        []string{"example.com"},
+	}), time.Now())
+	require.NoError(t, err)
+	assert.NotContains(t, string(entry.Data), "certificate")
+
+	restored, err := entry.Result()
+	require.NoError(t, err)
+	ssl := restored.Data().(domain.SSLResult)
+	assert.Nil(t, ssl.Certificate)
+	assert.Equal(t, []string{"example.com"}, ssl.SANs)
+}
+
+func TestEntry_Unsupported(t *testing.T) {
+	_, err := NewEntry(domain.NewResult(domain.BatchResult{}), time.Now())
+	assert.ErrorIs(t, err, ErrUnsupportedResult)
+
+	_, err = Entry{Kind: "unknown"}.Result()
+	assert.ErrorIs(t, err, ErrUnsupportedResult)
+}
+
+func TestLayouts_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", LayoutsFileName)
+
+	layouts, err := LoadLayouts(path)
+	require.NoError(t, err, "a missing file is no layouts")
+	assert.Empty(t, layouts)
+
+	layouts["traceroute"] = TableLayout{
+		Order:      []string{"RTT 1", "Hop", "Hostname"},
+		Hidden:     []string{"ASN"},
+		Widths:     map[string]int{"Hostname": 30},
+		SortColumn: "RTT 1",
+		Descending: true,
+	}
+	require.NoError(t, SaveLayouts(path, layouts))
+
+	loaded, err := LoadLayouts(path)
+	require.NoError(t, err)
+	assert.Equal(t, layouts, loaded)
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0600))
+	loaded, err = LoadLayouts(path)
+	assert.Error(t, err)
+	assert.NotNil(t, loaded, "a broken file still yields layouts to fill")
+}
@@ -0,0 +1,122 @@
+package stats
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// histogramGamma is the ratio between the bounds of a histogram bucket.
+// Reporting the middle of a bucket keeps percentiles within 1% of the exact
+// value.
+const histogramGamma = 1.02
+
+var logHistogramGamma = math.Log(histogramGamma)
+
+// Histogram counts samples in buckets whose width grows with their value,
+// so percentiles of any number of samples take memory bounded by the range
+// of the samples: under 1,500 buckets cover one nanosecond to an hour.
+// The zero value is an empty histogram.
+type Histogram struct {
+	buckets map[int]int
+	count   int
+	min     time.Duration
+	max     time.Duration
+}
+
+// Bin is a range of a histogram and the number of samples in it
+type Bin struct {
+	Low   time.Duration
+	High  time.Duration
+	Count int
+}
+
+// Add records a sample
+func (h *Histogram) Add(sample time.Duration) {
+	if h.buckets == nil {
+		h.buckets = make(map[int]int)
+	}
+	h.buckets[bucketOf(sample)]++
+	h.count++
+	if h.count == 1 || sample < h.min {
+		h.min = sample
+	}
+	if h.count == 1 || sample > h.max {
+		h.max = sample
+	}
+}
+
+// Count returns the number of samples recorded
+func (h *Histogram) Count() int {
+	return h.count
+}
+
+// Percentile returns the p-th percentile (0-100) of the samples within 1%
+func (h *Histogram) Percentile(p float64) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+
+	rank := p / 100 * float64(h.count-1)
+	seen := 0
+	for _, bucket := range h.sortedBuckets() {
+		seen += h.buckets[bucket]
+		if float64(seen) > rank {
+			return h.clamp(bucketValue(bucket))
+		}
+	}
+	return h.max
+}
+
+// Bins groups the samples into n ranges of equal width between the smallest
+// and the largest sample
+func (h *Histogram) Bins(n int) []Bin {
+	if h.count == 0 || n < 1 {
+		return nil
+	}
+	if h.min == h.max {
+		return []Bin{{Low: h.min, High: h.max, Count: h.count}}
+	}
+
+	width := float64(h.max-h.min) / float64(n)
+	bins := make([]Bin, n)
+	for i := range bins {
+		bins[i].Low = h.min + time.Duration(math.Round(float64(i)*width))
+		bins[i].High = h.min + time.Duration(math.Round(float64(i+1)*width))
+	}
+	for bucket, count := range h.buckets {
+		i := int(float64(h.clamp(bucketValue(bucket))-h.min) / width)
+		bins[min(i, n-1)].Count += count
+	}
+	return bins
+}
+
+// sortedBuckets returns the indexes of the buckets holding samples, smallest first
+func (h *Histogram) sortedBuckets() []int {
+	buckets := make([]int, 0, len(h.buckets))
+	for bucket := range h.buckets {
+		buckets = append(buckets, bucket)
+	}
+	sort.Ints(buckets)
+	return buckets
+}
+
+// clamp keeps a bucket value within the samples, so the smallest and largest
+// percentiles are exact
+func (h *Histogram) clamp(value time.Duration) time.Duration {
+	return max(h.min, min(h.max, value))
+}
+
+// bucketOf returns the index of the bucket holding sample
+func bucketOf(sample time.Duration) int {
+	if sample < 1 {
+		sample = 1
+	}
+	return int(math.Ceil(math.Log(float64(sample)) / logHistogramGamma))
+}
+
+// bucketValue returns the middle of a bucket, within 1% of every sample in it
+func bucketValue(bucket int) time.Duration {
+	upper := math.Pow(histogramGamma, float64(bucket))
+	return time.Duration(math.Round(2 * upper / (histogramGamma + 1)))
+}
@@ -0,0 +1,68 @@
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestHistogram_Percentile(t *testing.T) {
+	var histogram Histogram
+	if histogram.Percentile(50) != 0 || histogram.Bins(4) != nil {
+		t.Error("Expected an empty histogram")
+	}
+
+	random := rand.New(rand.NewSource(1))
+	samples := make([]time.Duration, 10000)
+	for i := range samples {
+		samples[i] = time.Duration(random.ExpFloat64() * float64(20*time.Millisecond))
+		histogram.Add(samples[i])
+	}
+
+	exact := Percentiles(samples, 0, 50, 90, 99, 100)
+	for i, p := range []float64{0, 50, 90, 99, 100} {
+		got := histogram.Percentile(p)
+		if diff := math.Abs(float64(got-exact[i])) / float64(exact[i]); diff > 0.01 {
+			t.Errorf("Expected p%v within 1%% of %v, got %v", p, exact[i], got)
+		}
+	}
+}
+
+func TestHistogram_BoundedMemory(t *testing.T) {
+	var histogram Histogram
+	for i := 0; i < 200000; i++ {
+		histogram.Add(time.Microsecond + time.Duration(i)*50*time.Microsecond)
+	}
+	if histogram.Count() != 200000 {
+		t.Errorf("Expected 200000 samples, got %d", histogram.Count())
+	}
+	if buckets := len(histogram.buckets); buckets > 1000 {
+		t.Errorf("Expected buckets bounded by the range of the samples, got %d", buckets)
+	}
+}
+
+func TestHistogram_Bins(t *testing.T) {
+	var histogram Histogram
+	for _, sample := range ms(10, 11, 12, 19, 20) {
+		histogram.Add(sample)
+	}
+
+	bins := histogram.Bins(2)
+	if len(bins) != 2 {
+		t.Fatalf("Expected 2 bins, got %+v", bins)
+	}
+	if bins[0].Low != 10*time.Millisecond || bins[1].High != 20*time.Millisecond {
+		t.Errorf("Expected bins from 10ms to 20ms, got %+v", bins)
+	}
+	if bins[0].Count != 3 || bins[1].Count != 2 {
+		t.Errorf("Expected 3 and 2 samples, got %+v", bins)
+	}
+
+	var constant Histogram
+	constant.Add(time.Millisecond)
+	constant.Add(time.Millisecond)
+	if bins := constant.Bins(4); len(bins) != 1 || bins[0].Count != 2 {
+		t.Errorf("Expected one bin for equal samples, got %+v", bins)
+	}
+}
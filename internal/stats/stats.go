@@ -0,0 +1,279 @@
+// Package stats provides canonical latency and loss statistics shared by diagnostic tools
+package stats
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Summary describes a set of latency samples
+type Summary struct {
+	Count  int           `json:"count"`
+	Min    time.Duration `json:"min"`
+	Max    time.Duration `json:"max"`
+	Mean   time.Duration `json:"mean"`
+	StdDev time.Duration `json:"stddev"`
+	Jitter time.Duration `json:"jitter"`
+}
+
+// Summarize computes min, max, mean, population standard deviation, and jitter for samples
+func Summarize(samples []time.Duration) Summary {
+	var running Running
+	for _, sample := range samples {
+		running.Add(sample)
+	}
+	return running.Summary()
+}
+
+// Loss returns the percentage of sent probes that were not received
+func Loss(sent, received int) float64 {
+	if sent <= 0 {
+		return 0
+	}
+	if received > sent {
+		received = sent
+	}
+	return float64(sent-received) / float64(sent) * 100
+}
+
+// Mean returns the arithmetic mean of samples
+func Mean(samples []time.Duration) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, sample := range samples {
+		total += sample
+	}
+	return total / time.Duration(len(samples))
+}
+
+// StdDev returns the population standard deviation of samples
+func StdDev(samples []time.Duration) time.Duration {
+	return Summarize(samples).StdDev
+}
+
+// Jitter returns the mean absolute difference between consecutive samples
+func Jitter(samples []time.Duration) time.Duration {
+	return Summarize(samples).Jitter
+}
+
+// Percentile returns the p-th percentile (0-100) of samples using linear interpolation
+// between closest ranks
+func Percentile(samples []time.Duration, p float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	sorted := make([]time.Duration, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return percentileSorted(sorted, p)
+}
+
+// Percentiles returns several percentiles of samples, sorting only once
+func Percentiles(samples []time.Duration, ps ...float64) []time.Duration {
+	values := make([]time.Duration, len(ps))
+	if len(samples) == 0 {
+		return values
+	}
+
+	sorted := make([]time.Duration, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	for i, p := range ps {
+		values[i] = percentileSorted(sorted, p)
+	}
+	return values
+}
+
+// percentileSorted computes a percentile from already sorted samples
+func percentileSorted(sorted []time.Duration, p float64) time.Duration {
+	switch {
+	case p <= 0:
+		return sorted[0]
+	case p >= 100:
+		return sorted[len(sorted)-1]
+	}
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sorted[lower]
+	}
+
+	fraction := rank - float64(lower)
+	return sorted[lower] + time.Duration(math.Round(fraction*float64(sorted[upper]-sorted[lower])))
+}
+
+// Running accumulates statistics one sample at a time, for live displays.
+// Its memory does not grow with the number of samples.
+type Running struct {
+	sent      int
+	received  int
+	min       time.Duration
+	max       time.Duration
+	last      time.Duration
+	total     time.Duration
+	mean      float64
+	m2        float64
+	jitterSum time.Duration
+	histogram Histogram
+}
+
+// Add records a received sample
+func (r *Running) Add(sample time.Duration) {
+	r.sent++
+	r.received++
+
+	if r.received == 1 || sample < r.min {
+		r.min = sample
+	}
+	if r.received == 1 || sample > r.max {
+		r.max = sample
+	}
+	if r.received > 1 {
+		r.jitterSum += abs(sample - r.last)
+	}
+
+	r.last = sample
+	r.total += sample
+	r.histogram.Add(sample)
+
+	// Welford's algorithm keeps the variance numerically stable
+	delta := float64(sample) - r.mean
+	r.mean += delta / float64(r.received)
+	r.m2 += delta * (float64(sample) - r.mean)
+}
+
+// AddLoss records a probe that received no reply
+func (r *Running) AddLoss() {
+	r.sent++
+}
+
+// Sent returns the number of probes recorded
+func (r *Running) Sent() int {
+	return r.sent
+}
+
+// Received returns the number of samples recorded
+func (r *Running) Received() int {
+	return r.received
+}
+
+// Last returns the most recent sample
+func (r *Running) Last() time.Duration {
+	return r.last
+}
+
+// Loss returns the loss percentage so far
+func (r *Running) Loss() float64 {
+	return Loss(r.sent, r.received)
+}
+
+// Percentile returns the p-th percentile (0-100) of the samples so far
+// within 1%
+func (r *Running) Percentile(p float64) time.Duration {
+	return r.histogram.Percentile(p)
+}
+
+// Histogram returns the distribution of the samples so far
+func (r *Running) Histogram() *Histogram {
+	return &r.histogram
+}
+
+// Summary returns the statistics accumulated so far
+func (r *Running) Summary() Summary {
+	summary := Summary{Count: r.received}
+	if r.received == 0 {
+		return summary
+	}
+
+	variance := r.m2 / float64(r.received)
+
+	summary.Min = r.min
+	summary.Max = r.max
+	summary.Mean = r.total / time.Duration(r.received)
+	summary.StdDev = time.Duration(math.Sqrt(variance))
+	if r.received > 1 {
+		summary.Jitter = r.jitterSum / time.Duration(r.received-1)
+	}
+	return summary
+}
+
+// MovingAverage averages the most recent samples within a fixed window
+type MovingAverage struct {
+	window  int
+	samples []time.Duration
+	total   time.Duration
+}
+
+// NewMovingAverage creates a moving average over window samples
+func NewMovingAverage(window int) *MovingAverage {
+	if window < 1 {
+		window = 1
+	}
+	return &MovingAverage{window: window}
+}
+
+// Add records a sample and returns the updated average
+func (m *MovingAverage) Add(sample time.Duration) time.Duration {
+	m.samples = append(m.samples, sample)
+	m.total += sample
+	if len(m.samples) > m.window {
+		m.total -= m.samples[0]
+		m.samples = m.samples[1:]
+	}
+	return m.Value()
+}
+
+// Value returns the current average, or zero before any samples are added
+func (m *MovingAverage) Value() time.Duration {
+	if len(m.samples) == 0 {
+		return 0
+	}
+	return m.total / time.Duration(len(m.samples))
+}
+
+// ExponentialMovingAverage weights recent samples more heavily using a smoothing factor
+type ExponentialMovingAverage struct {
+	alpha       float64
+	value       float64
+	initialized bool
+}
+
+// NewExponentialMovingAverage creates an EMA with smoothing factor alpha in (0, 1]
+func NewExponentialMovingAverage(alpha float64) *ExponentialMovingAverage {
+	if alpha <= 0 || alpha > 1 {
+		alpha = 1
+	}
+	return &ExponentialMovingAverage{alpha: alpha}
+}
+
+// Add records a sample and returns the updated average
+func (e *ExponentialMovingAverage) Add(sample time.Duration) time.Duration {
+	if !e.initialized {
+		e.value = float64(sample)
+		e.initialized = true
+	} else {
+		e.value += e.alpha * (float64(sample) - e.value)
+	}
+	return e.Value()
+}
+
+// Value returns the current average
+func (e *ExponentialMovingAverage) Value() time.Duration {
+	return time.Duration(e.value)
+}
+
+// abs returns the absolute value of d
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,220 @@
+// Package stats provides latency and loss statistics tests
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func ms(values ...float64) []time.Duration {
+	samples := make([]time.Duration, len(values))
+	for i, v := range values {
+		samples[i] = time.Duration(v * float64(time.Millisecond))
+	}
+	return samples
+}
+
+func TestSummarize(t *testing.T) {
+	summary := Summarize(ms(10, 20, 30, 40))
+
+	if summary.Count != 4 {
+		t.Errorf("Expected count 4, got %d", summary.Count)
+	}
+	if summary.Min != 10*time.Millisecond || summary.Max != 40*time.Millisecond {
+		t.Errorf("Expected min 10ms and max 40ms, got %v and %v", summary.Min, summary.Max)
+	}
+	if summary.Mean != 25*time.Millisecond {
+		t.Errorf("Expected mean 25ms, got %v", summary.Mean)
+	}
+	// Population variance is 125ms², so the standard deviation is ~11.18ms
+	if got := summary.StdDev.Round(10 * time.Microsecond); got != 11180*time.Microsecond {
+		t.Errorf("Expected stddev ~11.18ms, got %v", summary.StdDev)
+	}
+	if summary.Jitter != 10*time.Millisecond {
+		t.Errorf("Expected jitter 10ms, got %v", summary.Jitter)
+	}
+}
+
+func TestSummarize_Empty(t *testing.T) {
+	if summary := Summarize(nil); summary != (Summary{}) {
+		t.Errorf("Expected zero summary, got %+v", summary)
+	}
+}
+
+func TestSummarize_SingleSample(t *testing.T) {
+	summary := Summarize(ms(15))
+	if summary.Min != 15*time.Millisecond || summary.Max != 15*time.Millisecond || summary.Mean != 15*time.Millisecond {
+		t.Errorf("Expected all values to be 15ms, got %+v", summary)
+	}
+	if summary.StdDev != 0 || summary.Jitter != 0 {
+		t.Errorf("Expected zero spread, got stddev=%v jitter=%v", summary.StdDev, summary.Jitter)
+	}
+}
+
+func TestSummarize_ConstantLargeSamples(t *testing.T) {
+	samples := make([]time.Duration, 1000)
+	for i := range samples {
+		samples[i] = 5 * time.Second
+	}
+	if summary := Summarize(samples); summary.StdDev != 0 {
+		t.Errorf("Expected zero stddev for constant samples, got %v", summary.StdDev)
+	}
+}
+
+func TestLoss(t *testing.T) {
+	tests := []struct {
+		name     string
+		sent     int
+		received int
+		expected float64
+	}{
+		{"no probes", 0, 0, 0},
+		{"no loss", 5, 5, 0},
+		{"partial loss", 5, 3, 40},
+		{"total loss", 4, 0, 100},
+		{"duplicates", 2, 3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Loss(tt.sent, tt.received); got != tt.expected {
+				t.Errorf("Expected %.1f%% loss, got %.1f%%", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMeanStdDevJitter(t *testing.T) {
+	samples := ms(10, 15)
+
+	if got := Mean(samples); got != 12500*time.Microsecond {
+		t.Errorf("Expected mean 12.5ms, got %v", got)
+	}
+	if got := StdDev(samples); got != 2500*time.Microsecond {
+		t.Errorf("Expected stddev 2.5ms, got %v", got)
+	}
+	if got := Jitter(ms(10, 15, 5)); got != 7500*time.Microsecond {
+		t.Errorf("Expected jitter 7.5ms, got %v", got)
+	}
+	if Mean(nil) != 0 || StdDev(nil) != 0 || Jitter(nil) != 0 {
+		t.Error("Expected zero values for no samples")
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	samples := ms(40, 10, 30, 20, 50)
+
+	tests := []struct {
+		p        float64
+		expected time.Duration
+	}{
+		{-5, 10 * time.Millisecond},
+		{0, 10 * time.Millisecond},
+		{25, 20 * time.Millisecond},
+		{50, 30 * time.Millisecond},
+		{90, 46 * time.Millisecond},
+		{100, 50 * time.Millisecond},
+		{150, 50 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		if got := Percentile(samples, tt.p); got != tt.expected {
+			t.Errorf("p%.0f: expected %v, got %v", tt.p, tt.expected, got)
+		}
+	}
+
+	if samples[0] != 40*time.Millisecond {
+		t.Error("Percentile should not reorder the input samples")
+	}
+	if Percentile(nil, 50) != 0 {
+		t.Error("Expected zero percentile for no samples")
+	}
+}
+
+func TestPercentiles(t *testing.T) {
+	values := Percentiles(ms(1, 2, 3, 4), 50, 95, 99)
+	expected := []time.Duration{2500 * time.Microsecond, 3850 * time.Microsecond, 3970 * time.Microsecond}
+
+	for i := range expected {
+		if values[i] != expected[i] {
+			t.Errorf("Percentile %d: expected %v, got %v", i, expected[i], values[i])
+		}
+	}
+
+	empty := Percentiles(nil, 50, 99)
+	if len(empty) != 2 || empty[0] != 0 || empty[1] != 0 {
+		t.Errorf("Expected zero percentiles for no samples, got %v", empty)
+	}
+}
+
+func TestRunning(t *testing.T) {
+	var running Running
+
+	if running.Loss() != 0 || running.Summary().Count != 0 {
+		t.Error("Expected empty running statistics")
+	}
+
+	running.Add(10 * time.Millisecond)
+	running.AddLoss()
+	running.Add(15 * time.Millisecond)
+	running.AddLoss()
+	running.AddLoss()
+
+	if running.Sent() != 5 || running.Received() != 2 {
+		t.Errorf("Expected 5 sent and 2 received, got %d and %d", running.Sent(), running.Received())
+	}
+	if running.Loss() != 60 {
+		t.Errorf("Expected 60%% loss, got %.1f%%", running.Loss())
+	}
+	if running.Last() != 15*time.Millisecond {
+		t.Errorf("Expected last sample 15ms, got %v", running.Last())
+	}
+
+	// Lost probes must not affect latency statistics
+	if summary := running.Summary(); summary != Summarize(ms(10, 15)) {
+		t.Errorf("Expected running summary to match batch summary, got %+v", summary)
+	}
+	if running.Percentile(0) != 10*time.Millisecond || running.Percentile(100) != 15*time.Millisecond {
+		t.Errorf("Expected percentiles between 10ms and 15ms, got %v and %v", running.Percentile(0), running.Percentile(100))
+	}
+	if running.Histogram().Count() != 2 {
+		t.Errorf("Expected 2 samples in the histogram, got %d", running.Histogram().Count())
+	}
+}
+
+func TestMovingAverage(t *testing.T) {
+	average := NewMovingAverage(3)
+
+	if average.Value() != 0 {
+		t.Errorf("Expected zero before samples, got %v", average.Value())
+	}
+
+	expected := []time.Duration{10, 15, 20, 30, 40}
+	for i, sample := range ms(10, 20, 30, 40, 50) {
+		if got := average.Add(sample); got != expected[i]*time.Millisecond {
+			t.Errorf("Sample %d: expected %vms, got %v", i, int(expected[i]), got)
+		}
+	}
+
+	if NewMovingAverage(0).Add(7*time.Millisecond) != 7*time.Millisecond {
+		t.Error("Expected invalid window to fall back to a single sample")
+	}
+}
+
+func TestExponentialMovingAverage(t *testing.T) {
+	average := NewExponentialMovingAverage(0.5)
+
+	if got := average.Add(10 * time.Millisecond); got != 10*time.Millisecond {
+		t.Errorf("Expected first sample to seed the average, got %v", got)
+	}
+	if got := average.Add(20 * time.Millisecond); got != 15*time.Millisecond {
+		t.Errorf("Expected 15ms, got %v", got)
+	}
+	if got := average.Add(15 * time.Millisecond); got != 15*time.Millisecond {
+		t.Errorf("Expected 15ms, got %v", got)
+	}
+
+	if NewExponentialMovingAverage(2).Add(time.Second) != time.Second {
+		t.Error("Expected invalid alpha to track the latest sample")
+	}
+}
@@ -0,0 +1,85 @@
+// Package stats provides RFC 3550 jitter, reordering and MOS estimates for voice quality
+package stats
+
+import (
+	"time"
+)
+
+// Interarrival tracks RFC 3550 interarrival jitter and reordering for a stream
+// of probe replies, fed in arrival order
+type Interarrival struct {
+	jitter      float64
+	lastTransit time.Duration
+	highestSeq  int
+	samples     int
+	reordered   int
+}
+
+// Add records the transit time of the reply to probe seq. For round-trip
+// probes the RTT stands in for the transit time; only differences between
+// consecutive replies are used, so the return path does not skew the estimate.
+func (i *Interarrival) Add(seq int, transit time.Duration) {
+	if i.samples > 0 {
+		if seq < i.highestSeq {
+			i.reordered++
+		}
+		// J(i) = J(i-1) + (|D(i-1,i)| - J(i-1))/16
+		d := float64(abs(transit - i.lastTransit))
+		i.jitter += (d - i.jitter) / 16
+	}
+	if seq > i.highestSeq {
+		i.highestSeq = seq
+	}
+	i.lastTransit = transit
+	i.samples++
+}
+
+// Jitter returns the smoothed interarrival jitter
+func (i *Interarrival) Jitter() time.Duration {
+	return time.Duration(i.jitter)
+}
+
+// Reordered returns the number of replies that arrived after a reply to a later probe
+func (i *Interarrival) Reordered() int {
+	return i.reordered
+}
+
+// MOS estimates the mean opinion score (1.0-4.5) of a voice call over a path
+// with the given average latency, jitter and loss percentage, using the
+// simplified ITU-T G.107 E-model commonly applied to ping measurements
+func MOS(latency, jitter time.Duration, loss float64) float64 {
+	// Jitter buffers add delay, weighted double, plus a fixed codec delay
+	effective := float64(latency+2*jitter)/float64(time.Millisecond) + 10
+
+	var r float64
+	if effective < 160 {
+		r = 93.2 - effective/40
+	} else {
+		r = 93.2 - (effective-120)/10
+	}
+	r -= 2.5 * loss
+
+	switch {
+	case r < 0:
+		r = 0
+	case r > 100:
+		r = 100
+	}
+	return 1 + 0.035*r + 0.000007*r*(r-60)*(100-r)
+}
+
+// VoIPGrade describes the voice call quality expected at a MOS score
+func VoIPGrade(mos float64) string {
+	switch {
+	case mos >= 4.3:
+		return "Excellent"
+	case mos >= 4.0:
+		return "Good"
+	case mos >= 3.6:
+		return "Fair"
+	case mos >= 3.1:
+		return "Poor"
+	default:
+		return "Bad"
+	}
+}
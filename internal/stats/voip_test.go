@@ -0,0 +1,71 @@
+// Package stats provides voice quality estimate tests
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestInterarrival(t *testing.T) {
+	var arrival Interarrival
+
+	arrival.Add(1, 10*time.Millisecond)
+	if arrival.Jitter() != 0 {
+		t.Errorf("Expected no jitter after one reply, got %v", arrival.Jitter())
+	}
+
+	// A 16ms transit change moves the estimate by 1/16 of the difference
+	arrival.Add(2, 26*time.Millisecond)
+	if arrival.Jitter() != time.Millisecond {
+		t.Errorf("Expected 1ms jitter, got %v", arrival.Jitter())
+	}
+
+	arrival.Add(4, 26*time.Millisecond)
+	if expected := time.Duration(0.9375 * float64(time.Millisecond)); arrival.Jitter() != expected {
+		t.Errorf("Expected jitter to decay to %v, got %v", expected, arrival.Jitter())
+	}
+
+	if arrival.Reordered() != 0 {
+		t.Errorf("Expected no reordering yet, got %d", arrival.Reordered())
+	}
+	arrival.Add(3, 26*time.Millisecond)
+	if arrival.Reordered() != 1 {
+		t.Errorf("Expected the late reply to count as reordered, got %d", arrival.Reordered())
+	}
+}
+
+func TestMOS(t *testing.T) {
+	tests := []struct {
+		name    string
+		latency time.Duration
+		jitter  time.Duration
+		loss    float64
+		grade   string
+	}{
+		{name: "lan", latency: 2 * time.Millisecond, grade: "Excellent"},
+		{name: "typical internet", latency: 40 * time.Millisecond, jitter: 5 * time.Millisecond, grade: "Excellent"},
+		{name: "satellite", latency: 600 * time.Millisecond, jitter: 30 * time.Millisecond, grade: "Bad"},
+		{name: "lossy", latency: 30 * time.Millisecond, jitter: 5 * time.Millisecond, loss: 10, grade: "Poor"},
+		{name: "heavy loss", latency: 20 * time.Millisecond, loss: 50, grade: "Bad"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mos := MOS(tt.latency, tt.jitter, tt.loss)
+			if mos < 1 || mos > 4.5 {
+				t.Errorf("Expected MOS within 1.0-4.5, got %.2f", mos)
+			}
+			if grade := VoIPGrade(mos); grade != tt.grade {
+				t.Errorf("Expected %s for MOS %.2f, got %s", tt.grade, mos, grade)
+			}
+		})
+	}
+
+	if mos := MOS(20*time.Millisecond, 0, 0); math.Abs(mos-4.39) > 0.01 {
+		t.Errorf("Expected MOS 4.39 for 20ms latency, got %.3f", mos)
+	}
+	if MOS(40*time.Millisecond, 0, 1) >= MOS(40*time.Millisecond, 0, 0) {
+		t.Error("Expected loss to lower the score")
+	}
+}
@@ -0,0 +1,266 @@
+// Package targetinput is the text input the tools take their target in: a
+// host, a host and port, or a URL. A pasted URL such as
+// https://example.com:8443 is reduced to its host, with the port left for a
+// port field of the tool; the value is checked as it is typed, a hint tells
+// what a scheme or port was recognised as, and up and down recall the
+// targets entered before.
+package targetinput
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/validate"
+)
+
+// MaxHistory is how many entered targets an input recalls
+const MaxHistory = 20
+
+// schemePorts are the default ports of the URL schemes a target is pasted with
+var schemePorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"ws":    "80",
+	"wss":   "443",
+	"ftp":   "21",
+	"ftps":  "990",
+	"ssh":   "22",
+	"smtp":  "25",
+	"smtps": "465",
+	"imap":  "143",
+	"imaps": "993",
+	"pop3":  "110",
+	"pop3s": "995",
+	"ldap":  "389",
+	"ldaps": "636",
+}
+
+// services name the well-known ports in the hints
+var services = map[string]string{
+	"21":   "FTP",
+	"22":   "SSH",
+	"25":   "SMTP",
+	"53":   "DNS",
+	"80":   "HTTP",
+	"110":  "POP3",
+	"143":  "IMAP",
+	"389":  "LDAP",
+	"443":  "HTTPS",
+	"465":  "SMTPS",
+	"587":  "SMTP submission",
+	"636":  "LDAPS",
+	"853":  "DNS over TLS",
+	"990":  "FTPS",
+	"993":  "IMAPS",
+	"995":  "POP3S",
+	"8080": "HTTP alternate",
+	"8443": "HTTPS alternate",
+}
+
+// Target is a value of the input split into its parts
+type Target struct {
+	// Scheme is the scheme of a URL in lower case, or empty
+	Scheme string
+	Host   string
+	// Port is the port typed with the host, or the default port of the
+	// scheme, or empty
+	Port string
+}
+
+// Parse splits value into a target. A URL gives its scheme, host and port,
+// as does host:port or [IPv6]:port; anything else, including a bare IPv6
+// address or a list of hosts, is taken as the host.
+func Parse(value string) Target {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, "://") {
+		if u, err := url.Parse(value); err == nil && u.Hostname() != "" {
+			scheme := strings.ToLower(u.Scheme)
+			port := u.Port()
+			if port == "" {
+				port = schemePorts[scheme]
+			}
+			return Target{Scheme: scheme, Host: u.Hostname(), Port: port}
+		}
+	}
+	if strings.HasPrefix(value, "[") || strings.Count(value, ":") == 1 {
+		if host, port, err := net.SplitHostPort(value); err == nil && host != "" && validate.Port(port) == nil {
+			return Target{Host: host, Port: port}
+		}
+	}
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		value = value[1 : len(value)-1]
+	}
+	return Target{Host: value}
+}
+
+// Model is a target input. It embeds the text input, so the placeholder,
+// width and character limit are set on it as on a textinput.Model.
+type Model struct {
+	textinput.Model
+
+	// Check validates the host of the value as it is typed; nil accepts
+	// any value
+	Check validate.Check
+	// TakesPort tells that the tool has a port field the port of a pasted
+	// target goes to; without one the hint points out the port is not used
+	TakesPort bool
+
+	history []string
+	// recalled is the history entry shown, -1 for none, and draft the value
+	// typed before recalling one
+	recalled int
+	draft    string
+	// port is the port of a pasted target not taken by the tool yet
+	port string
+}
+
+// New creates a target input showing placeholder while empty
+func New(placeholder string) Model {
+	input := textinput.New()
+	input.Placeholder = placeholder
+	input.CharLimit = 253
+	input.Width = 50
+	return Model{Model: input, recalled: -1}
+}
+
+// Update handles the keys of the input: up and down recall the targets
+// entered before, and a pasted URL or host:port is reduced to its host
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && m.Focused() {
+		switch msg.String() {
+		case "up":
+			m.recall(m.recalled + 1)
+			return m, nil
+		case "down":
+			m.recall(m.recalled - 1)
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.Model, cmd = m.Model.Update(msg)
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		m.recalled = -1
+		if msg.Paste {
+			if port, ok := m.Split(); ok {
+				m.port = port
+			}
+		}
+	}
+	return m, cmd
+}
+
+// Target returns the value split into its parts
+func (m Model) Target() Target {
+	return Parse(m.Value())
+}
+
+// Split reduces a value holding a URL or host:port to its host and returns
+// the port, reporting whether there was one
+func (m *Model) Split() (string, bool) {
+	target := m.Target()
+	if target.Scheme == "" && target.Port == "" {
+		return "", false
+	}
+	m.SetValue(target.Host)
+	m.CursorEnd()
+	return target.Port, target.Port != ""
+}
+
+// TakePort returns the port of a target pasted since the last call, for
+// the port field of the tool, reporting whether there was one
+func (m *Model) TakePort() (string, bool) {
+	port := m.port
+	m.port = ""
+	return port, port != ""
+}
+
+// ValidationError returns why the host of the value is invalid, or nil when
+// it is valid or empty
+func (m Model) ValidationError() error {
+	host := m.Target().Host
+	if host == "" || m.Check == nil {
+		return nil
+	}
+	return m.Check(host)
+}
+
+// Hint describes what the scheme or port of the value was recognised as,
+// or returns an empty string for a bare host
+func (m Model) Hint() string {
+	target := m.Target()
+	if target.Scheme == "" && target.Port == "" {
+		return ""
+	}
+
+	recognised := "host " + target.Host
+	if target.Scheme != "" {
+		recognised = target.Scheme + " URL: " + recognised
+	}
+	if !m.TakesPort {
+		return recognised + " (the port is not used)"
+	}
+	if target.Port == "" {
+		return recognised + ", no default port for " + target.Scheme
+	}
+	if service, ok := services[target.Port]; ok {
+		return fmt.Sprintf("%s, port %s (%s)", recognised, target.Port, service)
+	}
+	return fmt.Sprintf("%s, port %s", recognised, target.Port)
+}
+
+// Feedback renders the error of an invalid value or the hint of the value
+// on a line of its own, or nothing
+func (m Model) Feedback() string {
+	if err := m.ValidationError(); err != nil {
+		return "\n" + lipgloss.NewStyle().Foreground(colors.Error).Render("✗ "+err.Error())
+	}
+	if hint := m.Hint(); hint != "" {
+		return "\n" + lipgloss.NewStyle().Foreground(colors.Subtle).Render("→ "+hint)
+	}
+	return ""
+}
+
+// Remember records the value as the most recently entered target
+func (m *Model) Remember() {
+	value := strings.TrimSpace(m.Value())
+	if value == "" {
+		return
+	}
+	history := []string{value}
+	for _, entry := range m.history {
+		if entry != value && len(history) < MaxHistory {
+			history = append(history, entry)
+		}
+	}
+	m.history = history
+	m.recalled = -1
+}
+
+// History returns the entered targets, most recent first
+func (m Model) History() []string {
+	return m.history
+}
+
+// recall shows history entry i, or the draft for -1
+func (m *Model) recall(i int) {
+	if i < -1 || i >= len(m.history) {
+		return
+	}
+	if m.recalled == -1 {
+		m.draft = m.Value()
+	}
+	m.recalled = i
+	if i == -1 {
+		m.SetValue(m.draft)
+	} else {
+		m.SetValue(m.history[i])
+	}
+	m.CursorEnd()
+}
@@ -0,0 +1,113 @@
+package targetinput
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/validate"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Target
+	}{
+		{"example.com", Target{Host: "example.com"}},
+		{" https://example.com:8443/path ", Target{Scheme: "https", Host: "example.com", Port: "8443"}},
+		{"HTTPS://example.com", Target{Scheme: "https", Host: "example.com", Port: "443"}},
+		{"gopher://example.com", Target{Scheme: "gopher", Host: "example.com"}},
+		{"example.com:25", Target{Host: "example.com", Port: "25"}},
+		{"[2001:db8::1]:443", Target{Host: "2001:db8::1", Port: "443"}},
+		{"[2001:db8::1]", Target{Host: "2001:db8::1"}},
+		{"2001:db8::1", Target{Host: "2001:db8::1"}},
+		{"example.com:http", Target{Host: "example.com:http"}},
+		{"a.example, b.example", Target{Host: "a.example, b.example"}},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.value); got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestModel_PasteSplitsTarget(t *testing.T) {
+	input := New("host")
+	input.TakesPort = true
+	input.Focus()
+
+	input, _ = input.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("https://example.com:8443"), Paste: true})
+	if got := input.Value(); got != "example.com" {
+		t.Errorf("Expected the host of the pasted URL, got %q", got)
+	}
+	if port, ok := input.TakePort(); !ok || port != "8443" {
+		t.Errorf("Expected the pasted port, got %q", port)
+	}
+	if _, ok := input.TakePort(); ok {
+		t.Error("Expected the port to be taken once")
+	}
+
+	// Typed targets are left as they are until split
+	input.SetValue("")
+	input, _ = input.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("example.com:25")})
+	if got := input.Value(); got != "example.com:25" {
+		t.Errorf("Expected the typed value, got %q", got)
+	}
+	if hint := input.Hint(); !strings.Contains(hint, "port 25 (SMTP)") {
+		t.Errorf("Expected a hint naming the port, got %q", hint)
+	}
+	if port, ok := input.Split(); !ok || port != "25" || input.Value() != "example.com" {
+		t.Errorf("Expected the split host and port, got %q and %q", input.Value(), port)
+	}
+}
+
+func TestModel_Validation(t *testing.T) {
+	input := New("host")
+	input.Check = validate.Host
+
+	if err := input.ValidationError(); err != nil {
+		t.Errorf("Expected an empty value to pass, got %v", err)
+	}
+	input.SetValue("https://example.com")
+	if err := input.ValidationError(); err != nil {
+		t.Errorf("Expected the host of a URL to be checked, got %v", err)
+	}
+	if !strings.Contains(input.Feedback(), "the port is not used") {
+		t.Errorf("Expected the hint, got %q", input.Feedback())
+	}
+	input.SetValue("not a host")
+	if input.ValidationError() == nil || !strings.Contains(input.Feedback(), "✗") {
+		t.Errorf("Expected the error, got %q", input.Feedback())
+	}
+}
+
+func TestModel_History(t *testing.T) {
+	input := New("host")
+	input.Focus()
+	up := tea.KeyMsg{Type: tea.KeyUp}
+	down := tea.KeyMsg{Type: tea.KeyDown}
+
+	for _, target := range []string{"a.example", "b.example", "a.example"} {
+		input.SetValue(target)
+		input.Remember()
+	}
+	if got := input.History(); len(got) != 2 || got[0] != "a.example" {
+		t.Errorf("Expected the targets once, most recent first, got %v", got)
+	}
+
+	input.SetValue("draft")
+	input, _ = input.Update(up)
+	input, _ = input.Update(up)
+	if got := input.Value(); got != "b.example" {
+		t.Errorf("Expected the older target, got %q", got)
+	}
+	input, _ = input.Update(up)
+	if got := input.Value(); got != "b.example" {
+		t.Errorf("Expected to stop at the oldest target, got %q", got)
+	}
+	input, _ = input.Update(down)
+	input, _ = input.Update(down)
+	if got := input.Value(); got != "draft" {
+		t.Errorf("Expected the draft back, got %q", got)
+	}
+}
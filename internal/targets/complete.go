@@ -0,0 +1,55 @@
+package targets
+
+import "strings"
+
+// Suggestion is a target offered while typing a host
+type Suggestion struct {
+	Target  string
+	Source  string
+	Starred bool
+}
+
+// Complete returns up to limit targets for input: starred targets first,
+// then recent ones, then the hosts of the system. Targets starting with the
+// input come before those merely containing it, and an empty input suggests
+// the starred and recent targets only.
+func (l *List) Complete(input string, hosts []Host, limit int) []Suggestion {
+	input = strings.ToLower(strings.TrimSpace(input))
+
+	candidates := make([]Suggestion, 0, len(l.Starred)+len(l.Recent)+len(hosts))
+	for _, target := range l.Starred {
+		candidates = append(candidates, Suggestion{Target: target, Source: SourceStarred, Starred: true})
+	}
+	for _, target := range l.Recent {
+		candidates = append(candidates, Suggestion{Target: target, Source: SourceRecent})
+	}
+	if input != "" {
+		for _, host := range hosts {
+			candidates = append(candidates, Suggestion{Target: host.Name, Source: host.Source})
+		}
+	}
+
+	var prefixed, contained []Suggestion
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		name := strings.ToLower(candidate.Target)
+		if seen[name] || name == input {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(name, input):
+			prefixed = append(prefixed, candidate)
+		case strings.Contains(name, input):
+			contained = append(contained, candidate)
+		default:
+			continue
+		}
+		seen[name] = true
+	}
+
+	suggestions := append(prefixed, contained...)
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
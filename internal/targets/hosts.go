@@ -0,0 +1,125 @@
+package targets
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Sources of the suggested targets
+const (
+	SourceStarred   = "starred"
+	SourceRecent    = "recent"
+	SourceHosts     = "hosts"
+	SourceSSHConfig = "ssh config"
+)
+
+// Host is a hostname known from the system and where it was found
+type Host struct {
+	Name   string
+	Source string
+}
+
+// HostsFile returns the hosts file of the system
+func HostsFile() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("SystemRoot"), "System32", "drivers", "etc", "hosts")
+	}
+	return "/etc/hosts"
+}
+
+// SSHConfigFile returns the SSH client configuration of the user, or ""
+// when the home directory is unknown
+func SSHConfigFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ssh", "config")
+}
+
+// ParseHosts returns the hostnames and aliases of a hosts file, skipping
+// comments and the addresses
+func ParseHosts(r io.Reader) []string {
+	var names []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		// Link-local IPv6 addresses carry a zone
+		if address, _, _ := strings.Cut(fields[0], "%"); net.ParseIP(address) == nil {
+			continue
+		}
+		names = append(names, fields[1:]...)
+	}
+	return names
+}
+
+// ParseSSHConfig returns the hosts of an SSH client configuration: the Host
+// aliases without wildcards and the HostName they stand for
+func ParseSSHConfig(r io.Reader) []string {
+	var names []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.IndexAny(line, " \t=")
+		if i < 0 {
+			continue
+		}
+		keyword, args := line[:i], strings.TrimLeft(line[i:], " \t=")
+		switch strings.ToLower(keyword) {
+		case "host":
+			for _, pattern := range strings.Fields(args) {
+				if !strings.ContainsAny(pattern, "*?!") {
+					names = append(names, pattern)
+				}
+			}
+		case "hostname":
+			if !strings.Contains(args, "%") {
+				names = append(names, strings.Trim(args, `"`))
+			}
+		}
+	}
+	return names
+}
+
+// LoadHosts reads the hostnames of the hosts file and of the SSH client
+// configuration at the given paths; files that cannot be read add none
+func LoadHosts(hostsFile, sshConfig string) []Host {
+	var hosts []Host
+	seen := make(map[string]bool)
+	for _, source := range []struct {
+		path   string
+		name   string
+		parser func(io.Reader) []string
+	}{
+		{hostsFile, SourceHosts, ParseHosts},
+		{sshConfig, SourceSSHConfig, ParseSSHConfig},
+	} {
+		file, err := os.Open(source.path)
+		if err != nil {
+			continue
+		}
+		for _, name := range source.parser(file) {
+			if !seen[name] {
+				seen[name] = true
+				hosts = append(hosts, Host{Name: name, Source: source.name})
+			}
+		}
+		file.Close()
+	}
+	return hosts
+}
@@ -0,0 +1,118 @@
+// Package targets keeps the targets recently used in the TUI and those the
+// user starred, and completes host inputs from them and from the hostnames of
+// the hosts file and the SSH client configuration.
+package targets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nettracex/nettracex-tui/internal/appdir"
+)
+
+// FileName is the name of the targets file in the configuration directory
+const FileName = "targets.json"
+
+// MaxRecent is how many recently used targets are kept
+const MaxRecent = 50
+
+// List holds the recently used targets, most recent first, and the starred
+// ones in the order they were starred
+type List struct {
+	Recent  []string `json:"recent,omitempty"`
+	Starred []string `json:"starred,omitempty"`
+}
+
+// DefaultPath returns the targets file in the nettracex configuration directory
+func DefaultPath() string {
+	return appdir.ConfigPath(FileName)
+}
+
+// Use records target as the most recently used one
+func (l *List) Use(target string) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return
+	}
+	l.Recent = append([]string{target}, remove(l.Recent, target)...)
+	if len(l.Recent) > MaxRecent {
+		l.Recent = l.Recent[:MaxRecent]
+	}
+}
+
+// IsStarred reports whether target is starred
+func (l *List) IsStarred(target string) bool {
+	for _, starred := range l.Starred {
+		if starred == target {
+			return true
+		}
+	}
+	return false
+}
+
+// ToggleStar stars target, or unstars it when it is starred, and reports
+// whether it is starred now
+func (l *List) ToggleStar(target string) bool {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return false
+	}
+	if l.IsStarred(target) {
+		l.Starred = remove(l.Starred, target)
+		return false
+	}
+	l.Starred = append(l.Starred, target)
+	return true
+}
+
+// remove returns targets without target
+func remove(targets []string, target string) []string {
+	kept := make([]string, 0, len(targets))
+	for _, t := range targets {
+		if t != target {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// Load reads the targets at path; a missing file yields an empty list
+func Load(path string) (*List, error) {
+	list := &List{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return list, nil
+	}
+	if err != nil {
+		return list, fmt.Errorf("failed to read targets: %w", err)
+	}
+	if err := json.Unmarshal(data, list); err != nil {
+		return &List{}, fmt.Errorf("failed to parse targets %s: %w", path, err)
+	}
+	return list, nil
+}
+
+// Save writes the targets to path, replacing the previous file atomically
+func Save(path string, list *List) error {
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode targets: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create targets directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write targets: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write targets: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// Package targets provides recent, starred and system target tests
+package targets
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestList_UseAndStar(t *testing.T) {
+	list := &List{}
+	for _, target := range []string{"a.example", "b.example", "a.example", " "} {
+		list.Use(target)
+	}
+	if want := []string{"a.example", "b.example"}; !reflect.DeepEqual(list.Recent, want) {
+		t.Errorf("Recent = %v, want %v", list.Recent, want)
+	}
+
+	for i := 0; i < MaxRecent+5; i++ {
+		list.Use(strings.Repeat("x", i+1))
+	}
+	if len(list.Recent) != MaxRecent {
+		t.Errorf("kept %d recent targets, want %d", len(list.Recent), MaxRecent)
+	}
+
+	if !list.ToggleStar("8.8.8.8") || !list.IsStarred("8.8.8.8") {
+		t.Error("ToggleStar should star an unstarred target")
+	}
+	if list.ToggleStar("8.8.8.8") || list.IsStarred("8.8.8.8") {
+		t.Error("ToggleStar should unstar a starred target")
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", FileName)
+	list, err := Load(path)
+	if err != nil || len(list.Recent) != 0 {
+		t.Fatalf("Load of a missing file = %v, %v; want an empty list", list, err)
+	}
+
+	list.Use("example.com")
+	list.ToggleStar("1.1.1.1")
+	if err := Save(path, list); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, list) {
+		t.Errorf("Load = %+v, want %+v", loaded, list)
+	}
+
+	if err := os.WriteFile(path, []byte("["), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if list, err := Load(path); err == nil || list == nil {
+		t.Errorf("Load of a broken file = %v, %v; want an empty list and an error", list, err)
+	}
+}
+
+func TestParseHosts(t *testing.T) {
+	hosts := `# static hosts
+127.0.0.1   localhost
+192.168.1.10 nas.lan nas # the NAS
+fe80::1%lo0 link-local
+not-an-address ignored
+`
+	want := []string{"localhost", "nas.lan", "nas", "link-local"}
+	if got := ParseHosts(strings.NewReader(hosts)); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseHosts = %v, want %v", got, want)
+	}
+}
+
+func TestParseSSHConfig(t *testing.T) {
+	config := `Host bastion jump
+    HostName bastion.example.com
+    User admin
+
+Host *.internal !skip
+	Hostname=%h.example.net
+
+host	db
+  hostname "db.example.org"
+`
+	want := []string{"bastion", "jump", "bastion.example.com", "db", "db.example.org"}
+	if got := ParseSSHConfig(strings.NewReader(config)); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSSHConfig = %v, want %v", got, want)
+	}
+}
+
+func TestLoadHosts(t *testing.T) {
+	dir := t.TempDir()
+	hostsFile := filepath.Join(dir, "hosts")
+	if err := os.WriteFile(hostsFile, []byte("10.0.0.1 router.lan\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	hosts := LoadHosts(hostsFile, filepath.Join(dir, "missing"))
+	want := []Host{{Name: "router.lan", Source: SourceHosts}}
+	if !reflect.DeepEqual(hosts, want) {
+		t.Errorf("LoadHosts = %v, want %v", hosts, want)
+	}
+}
+
+func TestList_Complete(t *testing.T) {
+	list := &List{
+		Recent:  []string{"mail.example.com", "example.org", "10.0.0.1"},
+		Starred: []string{"example.com"},
+	}
+	hosts := []Host{{Name: "router.example.lan", Source: SourceHosts}, {Name: "example.org", Source: SourceSSHConfig}}
+
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"", []string{"example.com", "mail.example.com", "example.org", "10.0.0.1"}},
+		{"ex", []string{"example.com", "example.org", "mail.example.com", "router.example.lan"}},
+		{"EXAMPLE.COM", []string{"mail.example.com"}},
+		{"router", []string{"router.example.lan"}},
+		{"nothing", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, suggestion := range list.Complete(tt.input, hosts, 5) {
+			got = append(got, suggestion.Target)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Complete(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	if got := list.Complete("", hosts, 2); len(got) != 2 || !got[0].Starred || got[1].Source != SourceRecent {
+		t.Errorf("Complete limited to 2 = %+v", got)
+	}
+}
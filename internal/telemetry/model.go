@@ -0,0 +1,45 @@
+package telemetry
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Model is a tea.Model that runs model and counts its crashes. The panic
+// goes on to Bubble Tea, which restores the terminal and prints it.
+type Model struct {
+	model     tea.Model
+	collector *Collector
+}
+
+// Wrap counts the crashes of model with collector
+func Wrap(model tea.Model, collector *Collector) *Model {
+	return &Model{model: model, collector: collector}
+}
+
+// Init implements tea.Model
+func (m *Model) Init() tea.Cmd {
+	defer m.recoverCrash()
+	return m.model.Init()
+}
+
+// Update implements tea.Model
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	defer m.recoverCrash()
+	var cmd tea.Cmd
+	m.model, cmd = m.model.Update(msg)
+	return m, cmd
+}
+
+// View implements tea.Model
+func (m *Model) View() string {
+	defer m.recoverCrash()
+	return m.model.View()
+}
+
+// recoverCrash counts a panic and panics again
+func (m *Model) recoverCrash() {
+	if r := recover(); r != nil {
+		m.collector.Crash(r)
+		panic(r)
+	}
+}
@@ -0,0 +1,292 @@
+// Package telemetry counts which features are used and the signatures of
+// crashes, for the opt-in usage reports that guide which tools get work.
+// Nothing else is kept: no targets, results, parameters, addresses, paths or
+// identifiers. The pending report is a JSON file that can be read before it
+// is sent, and nothing is recorded or sent unless telemetry is enabled.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/appdir"
+	"github.com/nettracex/nettracex-tui/internal/events"
+)
+
+const (
+	// FileName is the file the pending report is kept in
+	FileName = "telemetry.json"
+	// DefaultInterval is how often a report is sent
+	DefaultInterval = 7 * 24 * time.Hour
+	// maxCrashFrames is how many functions a crash signature names
+	maxCrashFrames = 4
+)
+
+// Report is what is sent: counts since the start of the day Since
+type Report struct {
+	Version  string         `json:"version"`
+	OS       string         `json:"os"`
+	Arch     string         `json:"arch"`
+	Since    time.Time      `json:"since"`
+	Features map[string]int `json:"features"`
+	Crashes  map[string]int `json:"crashes"`
+}
+
+// Empty reports whether nothing was counted
+func (r Report) Empty() bool {
+	return len(r.Features) == 0 && len(r.Crashes) == 0
+}
+
+// DefaultPath returns where the pending report is kept
+func DefaultPath() string {
+	return appdir.ConfigPath(FileName)
+}
+
+// Load reads the pending report at path; a missing file yields an empty one
+func Load(path string) (Report, error) {
+	var report Report
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return report, nil
+	}
+	if err != nil {
+		return report, fmt.Errorf("failed to read telemetry report: %w", err)
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return Report{}, fmt.Errorf("invalid telemetry report %s: %w", path, err)
+	}
+	return report, nil
+}
+
+// Collector counts into the pending report kept at a path
+type Collector struct {
+	mu     sync.Mutex
+	path   string
+	report Report
+	// now returns the time, so tests control the period of a report
+	now func() time.Time
+}
+
+// Open continues the pending report at path for version
+func Open(path, version string) (*Collector, error) {
+	report, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Collector{path: path, report: report, now: time.Now}
+	c.report.Version, c.report.OS, c.report.Arch = version, runtime.GOOS, runtime.GOARCH
+	if c.report.Since.IsZero() {
+		c.report.Since = day(c.now())
+	}
+	return c, nil
+}
+
+// day truncates t to the start of its day in UTC, so a report does not
+// tell when it was started
+func day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// Count adds a use of feature, such as "tool.ping" or "mode.batch"
+func (c *Collector) Count(feature string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.report.Features == nil {
+		c.report.Features = make(map[string]int)
+	}
+	c.report.Features[feature]++
+}
+
+// Crash counts a crash with value, the value of a recovered panic, and saves
+// the report at once as the process is about to end. Call it from the
+// deferred function that recovered, which the signature leaves out.
+func (c *Collector) Crash(value interface{}) {
+	signature := Signature(value, 4)
+	c.mu.Lock()
+	if c.report.Crashes == nil {
+		c.report.Crashes = make(map[string]int)
+	}
+	c.report.Crashes[signature]++
+	c.mu.Unlock()
+	c.Save()
+}
+
+// Signature names a crash by the type of the panic value and the functions
+// it went through, innermost first, e.g.
+// "runtime.boundsError at tui.(*MainModel).update < tui.(*MainModel).Update".
+// The panic message is left out, as it may hold targets or addresses. skip
+// is the number of callers to leave out, as for runtime.Callers.
+func Signature(value interface{}, skip int) string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(skip, pcs)])
+	var names []string
+	for len(names) < maxCrashFrames {
+		frame, more := frames.Next()
+		name := frame.Function
+		if name != "" && !strings.HasPrefix(name, "runtime.") {
+			names = append(names, shortFunction(name))
+		}
+		if !more {
+			break
+		}
+	}
+	signature := fmt.Sprintf("%T", value)
+	if len(names) > 0 {
+		signature += " at " + strings.Join(names, " < ")
+	}
+	return signature
+}
+
+// shortFunction drops the import path of a function name, keeping the
+// package: github.com/x/y/internal/tui.(*M).f becomes tui.(*M).f
+func shortFunction(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// Report returns a copy of the pending report
+func (c *Collector) Report() Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	report := c.report
+	report.Features = copyCounts(c.report.Features)
+	report.Crashes = copyCounts(c.report.Crashes)
+	return report
+}
+
+func copyCounts(counts map[string]int) map[string]int {
+	if counts == nil {
+		return nil
+	}
+	copied := make(map[string]int, len(counts))
+	for key, n := range counts {
+		copied[key] = n
+	}
+	return copied
+}
+
+// Save writes the pending report to its file
+func (c *Collector) Save() error {
+	report := c.Report()
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("failed to save telemetry report: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to save telemetry report: %w", err)
+	}
+	return nil
+}
+
+// Due reports whether the pending report covers interval and has counts
+func (c *Collector) Due(interval time.Duration) bool {
+	report := c.Report()
+	return !report.Empty() && c.now().Sub(report.Since) >= interval
+}
+
+// Send posts the pending report as JSON to endpoint and starts a new one
+// when it was accepted
+func (c *Collector) Send(ctx context.Context, client *http.Client, endpoint string) error {
+	report := c.Report()
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send telemetry report: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to send telemetry report: %s", resp.Status)
+	}
+
+	// Counts made while sending stay for the next report
+	c.mu.Lock()
+	subtract(c.report.Features, report.Features)
+	subtract(c.report.Crashes, report.Crashes)
+	c.report.Since = day(c.now())
+	c.mu.Unlock()
+	return c.Save()
+}
+
+// subtract removes the counts sent from counts
+func subtract(counts, sent map[string]int) {
+	for key, n := range sent {
+		if counts[key] -= n; counts[key] <= 0 {
+			delete(counts, key)
+		}
+	}
+}
+
+// Subscribe counts the tools run and the reports exported on bus. Tools
+// not in known are plugins and counted together, as their names are the
+// user's own.
+func (c *Collector) Subscribe(bus *events.Bus, known []string) func() {
+	builtin := make(map[string]bool, len(known))
+	for _, name := range known {
+		builtin[name] = true
+	}
+	return bus.Subscribe(func(event events.Event) {
+		switch event.Kind {
+		case events.KindToolStarted:
+			if builtin[event.Tool] {
+				c.Count("tool." + event.Tool)
+			} else {
+				c.Count("tool.plugin")
+			}
+		case events.KindResultExported:
+			if ext := strings.TrimPrefix(filepath.Ext(event.Path), "."); ext != "" {
+				c.Count("export." + strings.ToLower(ext))
+			} else {
+				c.Count("export")
+			}
+		}
+	}, events.KindToolStarted, events.KindResultExported)
+}
+
+// Format renders report for reading before it is sent
+func Format(report Report) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Version %s on %s/%s, counted since %s\n", report.Version, report.OS, report.Arch, report.Since.Format("2006-01-02"))
+	for _, section := range []struct {
+		title  string
+		counts map[string]int
+	}{{"Features", report.Features}, {"Crashes", report.Crashes}} {
+		fmt.Fprintf(&b, "%s:\n", section.title)
+		if len(section.counts) == 0 {
+			b.WriteString("  (none)\n")
+			continue
+		}
+		keys := make([]string, 0, len(section.counts))
+		for key := range section.counts {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(&b, "  %6d  %s\n", section.counts[key], key)
+		}
+	}
+	return b.String()
+}
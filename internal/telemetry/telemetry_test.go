@@ -0,0 +1,180 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/events"
+)
+
+func TestCollector(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nettracex", FileName)
+	collector, err := Open(path, "1.2.3")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	bus := events.NewBus()
+	collector.Subscribe(bus, []string{"ping", "dns"})
+	bus.Publish(events.Event{Kind: events.KindToolStarted, Tool: "ping", Target: "secret.example.com"})
+	bus.Publish(events.Event{Kind: events.KindToolStarted, Tool: "ping", Target: "192.0.2.1"})
+	bus.Publish(events.Event{Kind: events.KindToolStarted, Tool: "acme-inventory"})
+	bus.Publish(events.Event{Kind: events.KindToolFailed, Tool: "dns"})
+	bus.Publish(events.Event{Kind: events.KindResultExported, Tool: "ping", Path: "/home/me/report.JSON"})
+	collector.Count("mode.tui")
+	if err := collector.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// The report is read back as it would be sent
+	report, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	want := map[string]int{"tool.ping": 2, "tool.plugin": 1, "export.json": 1, "mode.tui": 1}
+	if len(report.Features) != len(want) {
+		t.Errorf("Expected features %v, got %v", want, report.Features)
+	}
+	for feature, n := range want {
+		if report.Features[feature] != n {
+			t.Errorf("Expected %s counted %d times, got %d", feature, n, report.Features[feature])
+		}
+	}
+	if report.Version != "1.2.3" || report.Since.IsZero() || report.Since.Hour() != 0 {
+		t.Errorf("Expected the version and the day counting started, got %+v", report)
+	}
+	data, _ := json.Marshal(report)
+	for _, private := range []string{"secret.example.com", "192.0.2.1", "acme", "/home/me"} {
+		if strings.Contains(string(data), private) {
+			t.Errorf("Expected %q to be left out of the report, got %s", private, data)
+		}
+	}
+
+	// A new run continues the report
+	again, err := Open(path, "1.2.4")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	again.Count("tool.ping")
+	if got := again.Report(); got.Features["tool.ping"] != 3 || got.Version != "1.2.4" {
+		t.Errorf("Expected the counts to carry over, got %+v", got)
+	}
+}
+
+// crashing is a model that panics in Update
+type crashing struct{}
+
+func (crashing) Init() tea.Cmd                       { return nil }
+func (crashing) Update(tea.Msg) (tea.Model, tea.Cmd) { var rows []int; _ = rows[3]; return nil, nil }
+func (crashing) View() string                        { return "" }
+
+func TestModel_Crash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	collector, err := Open(path, "1.2.3")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	model := Wrap(crashing{}, collector)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected the panic to go on")
+			}
+		}()
+		model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	}()
+
+	// The crash is saved at once
+	report, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(report.Crashes) != 1 {
+		t.Fatalf("Expected one crash, got %v", report.Crashes)
+	}
+	for signature, n := range report.Crashes {
+		want := "runtime.boundsError at telemetry.crashing.Update < telemetry.(*Model).Update"
+		if !strings.HasPrefix(signature, want) || n != 1 {
+			t.Errorf("Expected a crash signature starting %q, got %q x%d", want, signature, n)
+		}
+		if strings.Contains(signature, "index out of range") {
+			t.Errorf("Expected the panic message to be left out, got %q", signature)
+		}
+	}
+}
+
+func TestCollector_Send(t *testing.T) {
+	var received Report
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected a POST, got %s", r.Method)
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	collector, err := Open(filepath.Join(t.TempDir(), FileName), "1.2.3")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	now := time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC)
+	collector.now = func() time.Time { return now }
+	collector.report.Since = time.Date(2024, 2, 20, 0, 0, 0, 0, time.UTC)
+
+	if collector.Due(DefaultInterval) {
+		t.Error("Expected an empty report not to be due")
+	}
+	collector.Count("tool.dns")
+	if !collector.Due(DefaultInterval) {
+		t.Error("Expected a report over a week old to be due")
+	}
+
+	if err := collector.Send(context.Background(), server.Client(), server.URL); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if received.Features["tool.dns"] != 1 || received.Version != "1.2.3" {
+		t.Errorf("Expected the pending report to be sent, got %+v", received)
+	}
+	if report := collector.Report(); !report.Empty() || !report.Since.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected a new report from today, got %+v", report)
+	}
+
+	// A refused report is kept
+	collector.Count("tool.dns")
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	if err := collector.Send(context.Background(), server.Client(), server.URL); err == nil {
+		t.Error("Expected an error for a refused report")
+	}
+	if collector.Report().Features["tool.dns"] != 1 {
+		t.Error("Expected the counts to be kept when sending fails")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	report := Report{
+		Version:  "1.2.3",
+		OS:       "linux",
+		Arch:     "amd64",
+		Since:    time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Features: map[string]int{"tool.ping": 12, "mode.tui": 3},
+	}
+	got := Format(report)
+	for _, want := range []string{"Version 1.2.3 on linux/amd64, counted since 2024-03-01", "    12  tool.ping", "Crashes:\n  (none)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in\n%s", want, got)
+		}
+	}
+	if strings.Index(got, "mode.tui") > strings.Index(got, "tool.ping") {
+		t.Errorf("Expected features sorted by name, got\n%s", got)
+	}
+}
@@ -0,0 +1,170 @@
+// Package toolparams builds the parameters of a diagnostic tool from the
+// string values of its form, so the TUI, batch runs, scenarios and metric
+// probes start every tool from the same defaults.
+package toolparams
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// targetKeys names the form field holding the target of each built-in tool
+var targetKeys = map[string]string{
+	"whois":      "query",
+	"ping":       "host",
+	"dns":        "domain",
+	"ssl":        "host",
+	"traceroute": "host",
+	"dualstack":  "host",
+	"axfr":       "domain",
+	"sweep":      "cidr",
+}
+
+// allRecordTypes are the DNS record types looked up when the form asks for
+// none or ALL
+var allRecordTypes = []domain.DNSRecordType{
+	domain.DNSRecordTypeA,
+	domain.DNSRecordTypeAAAA,
+	domain.DNSRecordTypeMX,
+	domain.DNSRecordTypeTXT,
+	domain.DNSRecordTypeCNAME,
+	domain.DNSRecordTypeNS,
+}
+
+// TargetKey returns the form field holding the target of tool. For tools
+// that describe their inputs it is the first one named like a target, such
+// as host or domain, or else the first input.
+func TargetKey(tool domain.DiagnosticTool) string {
+	if key, ok := targetKeys[tool.Name()]; ok {
+		return key
+	}
+	specs, _ := domain.ParameterSpecs(tool)
+	for _, key := range domain.TargetParamKeys {
+		for _, spec := range specs {
+			if spec.Key == key {
+				return key
+			}
+		}
+	}
+	if len(specs) > 0 {
+		return specs[0].Key
+	}
+	return ""
+}
+
+// Build creates the parameters for running tool with the values of its
+// form. Built-in tools start from their defaults; tools that describe their
+// inputs receive each of them as a string, or its default when values lacks
+// the key.
+func Build(tool domain.DiagnosticTool, values map[string]string) (domain.Parameters, error) {
+	var params domain.Parameters
+
+	switch tool.Name() {
+	case "whois":
+		params = domain.NewWHOISParameters(values["query"])
+	case "ping":
+		count, err := strconv.Atoi(strings.TrimSpace(values["count"]))
+		continuous := err == nil && count == 0
+		if err != nil || count <= 0 {
+			count = 4
+		}
+		interval := time.Second
+		if seconds, err := strconv.ParseFloat(strings.TrimSpace(values["interval"]), 64); err == nil && seconds > 0 {
+			interval = time.Duration(seconds * float64(time.Second))
+		}
+		params = domain.NewPingParameters(values["host"], domain.PingOptions{
+			Count:      count,
+			Interval:   interval,
+			Timeout:    5 * time.Second,
+			PacketSize: 64,
+			TTL:        64,
+		})
+		// The tool validates and normalises the mode
+		params.Set("mode", values["mode"])
+		// A count of 0 pings until stopped on the live screen; runs that
+		// end with a result send the default count
+		if continuous {
+			params.Set("continuous", true)
+		}
+	case "dns":
+		recordType := strings.ToUpper(strings.TrimSpace(values["record_type"]))
+		params = domain.NewDNSParameters(values["domain"], parseRecordType(recordType))
+		if server := strings.TrimSpace(values["server"]); server != "" {
+			params.Set("server", server)
+		}
+		if recordType == "" || recordType == "ALL" {
+			params.Set("record_types", append([]domain.DNSRecordType(nil), allRecordTypes...))
+		}
+	case "ssl":
+		params = domain.NewSSLParameters(values["host"], 443)
+	case "traceroute":
+		params = domain.NewTracerouteParameters(values["host"], domain.TraceOptions{
+			MaxHops:    30,
+			Timeout:    5 * time.Second,
+			PacketSize: 64,
+			Queries:    3,
+		})
+		// The tool validates and normalises protocol and port
+		params.Set("protocol", values["protocol"])
+		params.Set("port", values["port"])
+		// A repeat interval re-runs the trace on its live screen
+		if seconds, err := strconv.ParseFloat(strings.TrimSpace(values["repeat"]), 64); err == nil && seconds > 0 {
+			params.Set("repeat", time.Duration(seconds*float64(time.Second)))
+		}
+	case "dualstack":
+		port, err := strconv.Atoi(strings.TrimSpace(values["port"]))
+		if err != nil {
+			port = 443
+		}
+		params = domain.NewDualStackParameters(values["host"], port)
+	case "axfr":
+		params = domain.NewParameters()
+		params.Set("domain", values["domain"])
+	case "sweep":
+		// The tool validates the range and concurrency
+		params = domain.NewParameters()
+		params.Set("cidr", values["cidr"])
+		params.Set("concurrency", values["concurrency"])
+	default:
+		specs, ok := domain.ParameterSpecs(tool)
+		if !ok {
+			return nil, fmt.Errorf("unsupported tool: %s", tool.Name())
+		}
+		params = domain.NewParameters()
+		for _, spec := range specs {
+			value, ok := values[spec.Key]
+			if !ok {
+				value = spec.Default
+			}
+			params.Set(spec.Key, value)
+		}
+	}
+
+	return params, nil
+}
+
+// parseRecordType returns the record type named in a form, A when it names
+// none the form offers
+func parseRecordType(name string) domain.DNSRecordType {
+	switch name {
+	case "AAAA":
+		return domain.DNSRecordTypeAAAA
+	case "MX":
+		return domain.DNSRecordTypeMX
+	case "TXT":
+		return domain.DNSRecordTypeTXT
+	case "CNAME":
+		return domain.DNSRecordTypeCNAME
+	case "NS":
+		return domain.DNSRecordTypeNS
+	case "SOA":
+		return domain.DNSRecordTypeSOA
+	case "PTR":
+		return domain.DNSRecordTypePTR
+	}
+	return domain.DNSRecordTypeA
+}
@@ -0,0 +1,93 @@
+package toolparams
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubTool is a tool that only has a name
+type stubTool struct{ name string }
+
+func (t stubTool) Name() string                            { return t.name }
+func (t stubTool) Description() string                     { return "stub tool" }
+func (t stubTool) Validate(params domain.Parameters) error { return nil }
+func (t stubTool) GetModel() tea.Model                     { return nil }
+func (t stubTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	return domain.NewResult(nil), nil
+}
+
+// pluginTool is a tool that describes its inputs
+type pluginTool struct {
+	stubTool
+	specs []domain.ParameterSpec
+}
+
+func (t pluginTool) Parameters() []domain.ParameterSpec { return t.specs }
+
+func TestBuild(t *testing.T) {
+	t.Run("ping form values", func(t *testing.T) {
+		params, err := Build(stubTool{"ping"}, map[string]string{"host": "example.com", "count": "10", "interval": "0.5"})
+		require.NoError(t, err)
+		assert.Equal(t, "example.com", params.Get("host"))
+		assert.Equal(t, 10, params.Get("count"))
+		assert.Equal(t, 500*time.Millisecond, params.Get("interval"))
+	})
+
+	t.Run("ping count 0 runs until stopped", func(t *testing.T) {
+		params, err := Build(stubTool{"ping"}, map[string]string{"host": "example.com", "count": "0"})
+		require.NoError(t, err)
+		assert.Equal(t, 4, params.Get("count"))
+		assert.Equal(t, true, params.Get("continuous"))
+	})
+
+	t.Run("traceroute repeat interval", func(t *testing.T) {
+		params, err := Build(stubTool{"traceroute"}, map[string]string{"host": "example.com", "repeat": "30"})
+		require.NoError(t, err)
+		assert.Equal(t, 30*time.Second, params.Get("repeat"))
+
+		params, err = Build(stubTool{"traceroute"}, map[string]string{"host": "example.com"})
+		require.NoError(t, err)
+		assert.Nil(t, params.Get("repeat"))
+	})
+
+	t.Run("dns looks up every type unless one is chosen", func(t *testing.T) {
+		params, err := Build(stubTool{"dns"}, map[string]string{"domain": "example.com", "record_type": "all"})
+		require.NoError(t, err)
+		assert.Len(t, params.Get("record_types"), len(allRecordTypes))
+
+		params, err = Build(stubTool{"dns"}, map[string]string{"domain": "example.com", "record_type": "mx"})
+		require.NoError(t, err)
+		assert.Equal(t, domain.DNSRecordTypeMX, params.Get("record_type"))
+		assert.Nil(t, params.Get("record_types"))
+	})
+
+	t.Run("plugin inputs fall back to their defaults", func(t *testing.T) {
+		tool := pluginTool{stubTool{"http"}, []domain.ParameterSpec{
+			{Key: "url", Required: true},
+			{Key: "method", Default: "GET"},
+		}}
+		params, err := Build(tool, map[string]string{"url": "https://example.com"})
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com", params.Get("url"))
+		assert.Equal(t, "GET", params.Get("method"))
+	})
+
+	t.Run("tools without inputs are unsupported", func(t *testing.T) {
+		_, err := Build(stubTool{"unknown"}, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestTargetKey(t *testing.T) {
+	assert.Equal(t, "query", TargetKey(stubTool{"whois"}))
+	assert.Equal(t, "cidr", TargetKey(stubTool{"sweep"}))
+	assert.Equal(t, "url", TargetKey(pluginTool{stubTool{"http"}, []domain.ParameterSpec{{Key: "method"}, {Key: "url"}}}))
+	assert.Equal(t, "name", TargetKey(pluginTool{stubTool{"echo"}, []domain.ParameterSpec{{Key: "name"}}}))
+	assert.Equal(t, "", TargetKey(stubTool{"unknown"}))
+}
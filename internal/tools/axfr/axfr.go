@@ -0,0 +1,244 @@
+// Package axfr provides a zone transfer (AXFR) exposure check for domains
+package axfr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+const (
+	// maxConcurrentTransfers limits how many nameserver addresses are tried at once
+	maxConcurrentTransfers = 4
+
+	// sampleRecords is how many leaked records are kept per nameserver for display
+	sampleRecords = 10
+)
+
+// Tool implements the DiagnosticTool interface for zone transfer checks
+type Tool struct {
+	client domain.NetworkClient
+	logger domain.Logger
+}
+
+// NewTool creates a new zone transfer check tool
+func NewTool(client domain.NetworkClient, logger domain.Logger) *Tool {
+	return &Tool{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Name returns the tool name
+func (t *Tool) Name() string {
+	return "axfr"
+}
+
+// Description returns the tool description
+func (t *Tool) Description() string {
+	return "Checks whether a domain's nameservers allow zone transfers (AXFR) to anyone"
+}
+
+// Execute locates the nameservers of the domain and attempts AXFR against each address
+func (t *Tool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	t.logger.Info("Executing zone transfer check", "tool", t.Name())
+
+	if err := t.Validate(params); err != nil {
+		return nil, &domain.NetTraceError{
+			Type:      domain.ErrorTypeValidation,
+			Message:   "AXFR parameter validation failed",
+			Cause:     err,
+			Context:   map[string]interface{}{"params": params.ToMap()},
+			Timestamp: time.Now(),
+			Code:      "AXFR_VALIDATION_FAILED",
+		}
+	}
+
+	transfer, ok := t.client.(domain.ZoneTransferClient)
+	if !ok {
+		return nil, &domain.NetTraceError{
+			Type:      domain.ErrorTypeSystem,
+			Message:   "network client does not support zone transfers",
+			Timestamp: time.Now(),
+			Code:      "AXFR_UNSUPPORTED_CLIENT",
+		}
+	}
+
+	zone := strings.TrimSuffix(strings.TrimSpace(params.Get("domain").(string)), ".")
+
+	nsResult, err := t.client.DNSLookup(ctx, zone, domain.DNSRecordTypeNS)
+	if err != nil || len(nsResult.Records) == 0 {
+		return nil, &domain.NetTraceError{
+			Type:      domain.ErrorTypeNetwork,
+			Message:   "domain has no NS records",
+			Cause:     err,
+			Context:   map[string]interface{}{"domain": zone},
+			Timestamp: time.Now(),
+			Code:      "AXFR_NO_NAMESERVERS",
+		}
+	}
+
+	var targets []domain.ZoneTransferServer
+	for _, nameserver := range nameservers(nsResult.Records) {
+		addresses := t.resolve(ctx, nameserver)
+		if len(addresses) == 0 {
+			targets = append(targets, domain.ZoneTransferServer{Nameserver: nameserver, Error: "nameserver address not found"})
+			continue
+		}
+		for _, address := range addresses {
+			targets = append(targets, domain.ZoneTransferServer{Nameserver: nameserver, Address: address})
+		}
+	}
+
+	servers := t.attemptTransfers(ctx, transfer, zone, targets)
+	zoneTransfer := domain.ZoneTransferResult{
+		Domain:    zone,
+		Servers:   servers,
+		Timestamp: time.Now(),
+	}
+	exposed := zoneTransfer.Exposed()
+
+	result := domain.NewResult(zoneTransfer)
+	result.SetMetadata("tool", t.Name())
+	result.SetMetadata("domain", zone)
+	result.SetMetadata("timestamp", zoneTransfer.Timestamp)
+	result.SetMetadata("servers", len(servers))
+	result.SetMetadata("exposed", len(exposed))
+
+	if len(exposed) > 0 {
+		t.logger.Warn("Zone transfer allowed", "domain", zone, "exposed", len(exposed), "servers", len(servers))
+	} else {
+		t.logger.Info("Zone transfer check completed", "domain", zone, "servers", len(servers))
+	}
+	return result, nil
+}
+
+// Validate validates the parameters for zone transfer checks
+func (t *Tool) Validate(params domain.Parameters) error {
+	zone, ok := params.Get("domain").(string)
+	if !ok {
+		return fmt.Errorf("domain parameter is required")
+	}
+
+	zone = strings.TrimSuffix(strings.TrimSpace(zone), ".")
+	if zone == "" {
+		return fmt.Errorf("domain parameter cannot be empty")
+	}
+	if !isValidZone(zone) {
+		return fmt.Errorf("domain must be a valid domain name")
+	}
+
+	return nil
+}
+
+// GetModel returns the Bubble Tea model for the zone transfer tool
+func (t *Tool) GetModel() tea.Model {
+	return NewModel(t)
+}
+
+// nameservers returns the distinct NS host names, sorted and without trailing dots
+func nameservers(records []domain.DNSRecord) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, record := range records {
+		if record.Type != domain.DNSRecordTypeNS {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSuffix(record.Value, "."))
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolve returns the IPv4 and IPv6 addresses of a nameserver
+func (t *Tool) resolve(ctx context.Context, nameserver string) []string {
+	var addresses []string
+	for _, recordType := range []domain.DNSRecordType{domain.DNSRecordTypeA, domain.DNSRecordTypeAAAA} {
+		dnsResult, err := t.client.DNSLookup(ctx, nameserver, recordType)
+		if err != nil {
+			t.logger.Debug("Nameserver lookup failed", "nameserver", nameserver, "record_type", recordType, "error", err)
+			continue
+		}
+		for _, record := range dnsResult.Records {
+			if record.Type == recordType && net.ParseIP(record.Value) != nil {
+				addresses = append(addresses, record.Value)
+			}
+		}
+	}
+	return addresses
+}
+
+// attemptTransfers tries AXFR against each target address with bounded concurrency
+func (t *Tool) attemptTransfers(ctx context.Context, transfer domain.ZoneTransferClient, zone string, targets []domain.ZoneTransferServer) []domain.ZoneTransferServer {
+	servers := make([]domain.ZoneTransferServer, len(targets))
+	semaphore := make(chan struct{}, maxConcurrentTransfers)
+	var wg sync.WaitGroup
+
+	for i, target := range targets {
+		if target.Address == "" {
+			servers[i] = target
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, server domain.ZoneTransferServer) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			start := time.Now()
+			records, total, err := transfer.ZoneTransfer(ctx, server.Address, zone)
+			server.Duration = time.Since(start)
+			if err != nil {
+				var netErr *domain.NetTraceError
+				server.Refused = errors.As(err, &netErr) && netErr.Code == "AXFR_REFUSED"
+				server.Error = err.Error()
+				t.logger.Debug("Zone transfer denied", "nameserver", server.Nameserver, "address", server.Address, "error", err)
+			} else {
+				server.Allowed = true
+				server.Records = total
+				if len(records) > sampleRecords {
+					records = records[:sampleRecords]
+				}
+				server.Sample = records
+			}
+			servers[i] = server
+		}(i, target)
+	}
+
+	wg.Wait()
+	return servers
+}
+
+// isValidZone validates a domain name made of letters, digits and hyphens
+func isValidZone(zone string) bool {
+	if len(zone) > 253 || !strings.Contains(zone, ".") {
+		return false
+	}
+	for _, label := range strings.Split(zone, ".") {
+		if len(label) == 0 || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return false
+		}
+		for _, char := range label {
+			if !((char >= 'a' && char <= 'z') ||
+				(char >= 'A' && char <= 'Z') ||
+				(char >= '0' && char <= '9') ||
+				char == '-' || char == '_') {
+				return false
+			}
+		}
+	}
+	return true
+}
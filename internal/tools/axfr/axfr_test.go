@@ -0,0 +1,184 @@
+// Package axfr provides zone transfer check tests
+package axfr
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/network"
+	"github.com/nettracex/nettracex-tui/internal/tui"
+)
+
+// testLogger implements domain.Logger and discards all output
+type testLogger struct{}
+
+func (testLogger) Debug(msg string, fields ...interface{}) {}
+func (testLogger) Info(msg string, fields ...interface{})  {}
+func (testLogger) Warn(msg string, fields ...interface{})  {}
+func (testLogger) Error(msg string, fields ...interface{}) {}
+func (testLogger) Fatal(msg string, fields ...interface{}) {}
+
+// newAXFRTool creates a tool for example.com where ns1 (192.0.2.1) allows
+// the transfer, ns2 (192.0.2.2) refuses it and ns3 does not resolve
+func newAXFRTool() (*Tool, *network.MockClient) {
+	client := network.NewMockClient()
+	client.SetDNSResponse("example.com", domain.DNSRecordTypeNS, domain.DNSResult{
+		Records: []domain.DNSRecord{
+			{Name: "example.com", Type: domain.DNSRecordTypeNS, Value: "ns2.example.com."},
+			{Name: "example.com", Type: domain.DNSRecordTypeNS, Value: "ns1.example.com."},
+			{Name: "example.com", Type: domain.DNSRecordTypeNS, Value: "NS1.example.com"},
+			{Name: "example.com", Type: domain.DNSRecordTypeNS, Value: "ns3.example.com."},
+		},
+	})
+	for name, address := range map[string]string{"ns1.example.com": "192.0.2.1", "ns2.example.com": "192.0.2.2"} {
+		client.SetDNSResponse(name, domain.DNSRecordTypeA, domain.DNSResult{
+			Records: []domain.DNSRecord{{Name: name, Type: domain.DNSRecordTypeA, Value: address}},
+		})
+		client.SetDNSResponse(name, domain.DNSRecordTypeAAAA, domain.DNSResult{})
+	}
+	client.SetDNSError("ns3.example.com", domain.DNSRecordTypeA, errors.New("no such host"))
+	client.SetDNSError("ns3.example.com", domain.DNSRecordTypeAAAA, errors.New("no such host"))
+
+	var zone []domain.DNSRecord
+	for i := 0; i < 12; i++ {
+		zone = append(zone, domain.DNSRecord{Name: "host.example.com", Type: domain.DNSRecordTypeA, Value: "10.0.0.1", TTL: 300})
+	}
+	client.SetZoneTransfer("192.0.2.1", "example.com", zone)
+
+	return NewTool(client, testLogger{}), client
+}
+
+func TestTool_NameAndDescription(t *testing.T) {
+	tool := NewTool(network.NewMockClient(), testLogger{})
+
+	if tool.Name() != "axfr" {
+		t.Errorf("Expected name 'axfr', got %s", tool.Name())
+	}
+	if tool.Description() == "" {
+		t.Error("Expected non-empty description")
+	}
+	if tool.GetModel() == nil {
+		t.Error("Expected a model")
+	}
+}
+
+func TestTool_Validate(t *testing.T) {
+	tool := NewTool(network.NewMockClient(), testLogger{})
+
+	tests := []struct {
+		name    string
+		domain  interface{}
+		wantErr bool
+	}{
+		{"valid domain", "example.com", false},
+		{"trailing dot", "example.com.", false},
+		{"missing domain", nil, true},
+		{"empty domain", "  ", true},
+		{"single label", "localhost", true},
+		{"invalid characters", "exa mple.com", true},
+		{"empty label", "example..com", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := domain.NewParameters()
+			if tt.domain != nil {
+				params.Set("domain", tt.domain)
+			}
+			err := tool.Validate(params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTool_Execute(t *testing.T) {
+	tool, _ := newAXFRTool()
+
+	params := domain.NewParameters()
+	params.Set("domain", "example.com.")
+	result, err := tool.Execute(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	zoneTransfer := result.Data().(domain.ZoneTransferResult)
+	if zoneTransfer.Domain != "example.com" {
+		t.Errorf("Expected domain example.com, got %s", zoneTransfer.Domain)
+	}
+	if len(zoneTransfer.Servers) != 3 {
+		t.Fatalf("Expected 3 deduplicated nameservers, got %+v", zoneTransfer.Servers)
+	}
+
+	ns1, ns2, ns3 := zoneTransfer.Servers[0], zoneTransfer.Servers[1], zoneTransfer.Servers[2]
+	if ns1.Nameserver != "ns1.example.com" || !ns1.Allowed || ns1.Records != 12 || len(ns1.Sample) != sampleRecords {
+		t.Errorf("Expected ns1 to leak 12 records with a sample of %d, got %+v", sampleRecords, ns1)
+	}
+	if ns2.Allowed || !ns2.Refused {
+		t.Errorf("Expected ns2 to refuse the transfer, got %+v", ns2)
+	}
+	if ns3.Address != "" || ns3.Refused || ns3.Error == "" {
+		t.Errorf("Expected ns3 to be reported as unresolved, got %+v", ns3)
+	}
+
+	if exposed := zoneTransfer.Exposed(); len(exposed) != 1 || exposed[0].Address != "192.0.2.1" {
+		t.Errorf("Expected only 192.0.2.1 to be exposed, got %+v", exposed)
+	}
+	if result.Metadata()["exposed"] != 1 {
+		t.Errorf("Expected exposed metadata 1, got %v", result.Metadata()["exposed"])
+	}
+}
+
+func TestTool_Execute_NoNameservers(t *testing.T) {
+	client := network.NewMockClient()
+	client.SetDNSResponse("example.com", domain.DNSRecordTypeNS, domain.DNSResult{})
+	tool := NewTool(client, testLogger{})
+
+	params := domain.NewParameters()
+	params.Set("domain", "example.com")
+	_, err := tool.Execute(context.Background(), params)
+
+	var netErr *domain.NetTraceError
+	if !errors.As(err, &netErr) || netErr.Code != "AXFR_NO_NAMESERVERS" {
+		t.Errorf("Expected AXFR_NO_NAMESERVERS error, got %v", err)
+	}
+}
+
+func TestModel_ResultView(t *testing.T) {
+	tool, _ := newAXFRTool()
+	model := NewModel(tool)
+
+	model.domainInput.SetValue("example.com")
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.state != tui.ViewStateLoading || cmd == nil {
+		t.Fatal("Expected enter to start the check")
+	}
+
+	model.Update(cmd())
+	if model.state != tui.ViewStateResult {
+		t.Fatalf("Expected result state, got %v", model.state)
+	}
+
+	view := model.View()
+	for _, expected := range []string{
+		"1 of 3 nameserver addresses allow zone transfers",
+		"ns1.example.com (192.0.2.1): transfer allowed, 12 records leaked",
+		"ns2.example.com (192.0.2.2): transfer refused",
+		"ns3.example.com: not checked",
+		"Leaked records from ns1.example.com (first 10 of 12)",
+	} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected view to contain %q, got:\n%s", expected, view)
+		}
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.state != tui.ViewStateInput {
+		t.Error("Expected esc to return to the input form")
+	}
+}
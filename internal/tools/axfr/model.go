@@ -0,0 +1,278 @@
+// Package axfr provides zone transfer check TUI components
+package axfr
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/clipboard"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/targetinput"
+	"github.com/nettracex/nettracex-tui/internal/tui"
+	"github.com/nettracex/nettracex-tui/internal/validate"
+)
+
+// CompleteMsg is sent when a zone transfer check finishes
+type CompleteMsg struct {
+	Result domain.ZoneTransferResult
+}
+
+// ErrorMsg is sent when a zone transfer check fails
+type ErrorMsg struct {
+	Error error
+}
+
+// Model represents the zone transfer check TUI model
+type Model struct {
+	tool        *Tool
+	state       tui.ViewState
+	domainInput targetinput.Model
+	result      *domain.ZoneTransferResult
+	error       error
+	width       int
+	height      int
+	theme       domain.Theme
+	toast       clipboard.Toast
+}
+
+// NewModel creates a new zone transfer check model
+func NewModel(tool *Tool) *Model {
+	domainInput := targetinput.New("Enter domain (e.g., example.com)")
+	domainInput.Check = validate.Domain
+	domainInput.Focus()
+
+	return &Model{
+		tool:        tool,
+		state:       tui.ViewStateInput,
+		domainInput: domainInput,
+		theme:       tui.NewDefaultTheme(),
+	}
+}
+
+// Init initializes the model
+func (m *Model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles messages and updates the model
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			if m.state == tui.ViewStateResult || m.state == tui.ViewStateError {
+				m.state = tui.ViewStateInput
+				m.result = nil
+				m.error = nil
+				return m, nil
+			}
+		case "enter":
+			if m.state == tui.ViewStateInput {
+				return m, m.executeCheck()
+			}
+		case "y":
+			if m.state == tui.ViewStateResult && m.result != nil {
+				return m, clipboard.CopyResult(*m.result)
+			}
+		}
+
+	case CompleteMsg:
+		m.state = tui.ViewStateResult
+		m.result = &msg.Result
+		return m, nil
+
+	case ErrorMsg:
+		m.state = tui.ViewStateError
+		m.error = msg.Error
+		return m, nil
+
+	case clipboard.CopiedMsg:
+		return m, m.toast.Show(msg.Status())
+
+	case clipboard.ToastExpiredMsg:
+		m.toast.Update(msg)
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.SetSize(msg.Width, msg.Height)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	if m.state == tui.ViewStateInput {
+		m.domainInput, cmd = m.domainInput.Update(msg)
+	}
+
+	return m, cmd
+}
+
+// View renders the model
+func (m *Model) View() string {
+	switch m.state {
+	case tui.ViewStateInput:
+		return m.renderInputView()
+	case tui.ViewStateLoading:
+		return m.style("primary").Bold(true).Render("Attempting zone transfers...")
+	case tui.ViewStateResult:
+		return m.renderResultView()
+	case tui.ViewStateError:
+		return m.style("error").Render(fmt.Sprintf("Error: %v", m.error)) + "\n\n" + m.renderHelp("Esc: Back • Ctrl+C: Quit")
+	default:
+		return "Unknown state"
+	}
+}
+
+// SetSize sets the model size
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	if width > 10 && width-10 < 50 {
+		m.domainInput.Width = width - 10
+	}
+}
+
+// SetTheme sets the model theme
+func (m *Model) SetTheme(theme domain.Theme) {
+	m.theme = theme
+}
+
+// Focus focuses the model
+func (m *Model) Focus() {
+	if m.state == tui.ViewStateInput {
+		m.domainInput.Focus()
+	}
+}
+
+// Blur blurs the model
+func (m *Model) Blur() {
+	m.domainInput.Blur()
+}
+
+// executeCheck runs the zone transfer check
+func (m *Model) executeCheck() tea.Cmd {
+	m.domainInput.Split()
+	zone := strings.TrimSpace(m.domainInput.Value())
+	if zone == "" {
+		return func() tea.Msg {
+			return ErrorMsg{Error: fmt.Errorf("domain is required")}
+		}
+	}
+	if err := m.domainInput.ValidationError(); err != nil {
+		return func() tea.Msg {
+			return ErrorMsg{Error: err}
+		}
+	}
+	m.domainInput.Remember()
+
+	m.state = tui.ViewStateLoading
+
+	return func() tea.Msg {
+		params := domain.NewParameters()
+		params.Set("domain", zone)
+
+		result, err := m.tool.Execute(context.Background(), params)
+		if err != nil {
+			return ErrorMsg{Error: err}
+		}
+
+		zoneTransfer, ok := result.Data().(domain.ZoneTransferResult)
+		if !ok {
+			return ErrorMsg{Error: fmt.Errorf("invalid result type")}
+		}
+
+		return CompleteMsg{Result: zoneTransfer}
+	}
+}
+
+// renderInputView renders the input form
+func (m *Model) renderInputView() string {
+	var b strings.Builder
+
+	b.WriteString(m.style("primary").Bold(true).Render("Zone Transfer (AXFR) Check"))
+	b.WriteString("\n\n")
+	b.WriteString(m.style("text").Bold(true).Render("Domain:"))
+	b.WriteString("\n")
+	b.WriteString(m.domainInput.View())
+	b.WriteString(m.domainInput.Feedback())
+	b.WriteString("\n\n")
+	b.WriteString(m.renderHelp("↑/↓: Recent domains • Enter: Check • Esc: Back • Ctrl+C: Quit"))
+
+	return b.String()
+}
+
+// renderResultView renders the outcome for each nameserver address
+func (m *Model) renderResultView() string {
+	if m.result == nil {
+		return "No results available"
+	}
+
+	var b strings.Builder
+	b.WriteString(m.style("primary").Bold(true).Render(fmt.Sprintf("Zone Transfer: %s", m.result.Domain)))
+	b.WriteString("\n\n")
+
+	exposed := m.result.Exposed()
+	if len(exposed) > 0 {
+		b.WriteString(m.style("error").Bold(true).Render(fmt.Sprintf("⚠ %d of %d nameserver addresses allow zone transfers", len(exposed), len(m.result.Servers))))
+	} else {
+		b.WriteString(m.style("success").Bold(true).Render("✅ No nameserver allowed a zone transfer"))
+	}
+	b.WriteString("\n\n")
+
+	for _, server := range m.result.Servers {
+		b.WriteString(m.renderServer(server))
+		b.WriteString("\n")
+	}
+
+	// Exposed servers serve the same zone, so one sample is enough
+	if len(exposed) > 0 && len(exposed[0].Sample) > 0 {
+		server := exposed[0]
+		b.WriteString("\n")
+		b.WriteString(m.style("accent").Bold(true).Render(fmt.Sprintf("Leaked records from %s (first %d of %d):", server.Nameserver, len(server.Sample), server.Records)))
+		b.WriteString("\n")
+		for _, record := range server.Sample {
+			b.WriteString(m.style("text").Render(fmt.Sprintf("  %s %d %s", record.Name, record.TTL, record.Value)))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	if message := m.toast.Message(); message != "" {
+		b.WriteString(m.style("success").Render("✓ " + message))
+		b.WriteString("\n")
+	}
+	b.WriteString(m.renderHelp("y: Copy • Esc: Back • Ctrl+C: Quit"))
+
+	return b.String()
+}
+
+// renderServer renders the outcome for one nameserver address
+func (m *Model) renderServer(server domain.ZoneTransferServer) string {
+	label := server.Nameserver
+	if server.Address != "" {
+		label = fmt.Sprintf("%s (%s)", server.Nameserver, server.Address)
+	}
+	if server.Allowed {
+		return m.style("error").Render(fmt.Sprintf("❌ %s: transfer allowed, %d records leaked", label, server.Records))
+	}
+	if server.Refused {
+		return m.style("success").Render(fmt.Sprintf("✅ %s: transfer refused", label))
+	}
+	return m.style("warning").Render(fmt.Sprintf("⚠ %s: not checked (%s)", label, server.Error))
+}
+
+// renderHelp renders a help line
+func (m *Model) renderHelp(text string) string {
+	return m.style("muted").Italic(true).Render(text)
+}
+
+// style returns a style using the named theme color
+func (m *Model) style(color string) lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(colors.Adaptive(m.theme.GetColor(color)))
+}
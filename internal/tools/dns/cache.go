@@ -0,0 +1,128 @@
+// Package dns provides TTL countdowns and cache change tracking for DNS answers
+package dns
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// AnswerState describes how the answer for one record type differs from the previous query
+type AnswerState int
+
+const (
+	// AnswerUnchanged means the same records were served from cache
+	AnswerUnchanged AnswerState = iota
+	// AnswerRefreshed means the same records came back with a fresh TTL,
+	// so the resolver re-fetched them from the authoritative servers
+	AnswerRefreshed
+	// AnswerChanged means the set of records differs
+	AnswerChanged
+)
+
+// CacheSample records the answer states observed by one re-query in watch mode
+type CacheSample struct {
+	Timestamp time.Time                            `json:"timestamp"`
+	States    map[domain.DNSRecordType]AnswerState `json:"states"`
+}
+
+// AnswerChange records a record type whose answer changed between queries
+type AnswerChange struct {
+	Timestamp  time.Time            `json:"timestamp"`
+	RecordType domain.DNSRecordType `json:"record_type"`
+	Previous   []string             `json:"previous"`
+	Current    []string             `json:"current"`
+}
+
+// String returns a change log line such as "12:00:03 A: 192.0.2.1 → 192.0.2.2"
+func (c AnswerChange) String() string {
+	return fmt.Sprintf("%s %s: %s → %s", c.Timestamp.Format("15:04:05"), GetRecordTypeString(c.RecordType),
+		formatAnswerValues(c.Previous), formatAnswerValues(c.Current))
+}
+
+// formatAnswerValues joins record values, or "(none)" for an empty answer
+func formatAnswerValues(values []string) string {
+	if len(values) == 0 {
+		return "(none)"
+	}
+	return strings.Join(values, ", ")
+}
+
+// RemainingTTL returns how long record stays cached after it was fetched at
+// fetched. It reports false when the TTL is unknown.
+func RemainingTTL(record domain.DNSRecord, fetched, now time.Time) (time.Duration, bool) {
+	if record.TTL == 0 {
+		return 0, false
+	}
+	remaining := time.Duration(record.TTL)*time.Second - now.Sub(fetched)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining.Truncate(time.Second), true
+}
+
+// FormatRemainingTTL formats a TTL countdown such as "4m05s", "expired", or "-" when unknown
+func FormatRemainingTTL(record domain.DNSRecord, fetched, now time.Time) string {
+	remaining, ok := RemainingTTL(record, fetched, now)
+	switch {
+	case !ok:
+		return "-"
+	case remaining == 0:
+		return "expired"
+	case remaining < time.Minute:
+		return fmt.Sprintf("%ds", int(remaining.Seconds()))
+	case remaining < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(remaining.Minutes()), int(remaining.Seconds())%60)
+	default:
+		return fmt.Sprintf("%dh%02dm", int(remaining.Hours()), int(remaining.Minutes())%60)
+	}
+}
+
+// answerValues returns the sorted values of the records of recordType
+func answerValues(records []domain.DNSRecord, recordType domain.DNSRecordType) []string {
+	var values []string
+	for _, record := range records {
+		if record.Type == recordType {
+			values = append(values, record.Value)
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+// minTTL returns the lowest known TTL among the records of recordType
+func minTTL(records []domain.DNSRecord, recordType domain.DNSRecordType) (uint32, bool) {
+	var ttl uint32
+	found := false
+	for _, record := range records {
+		if record.Type == recordType && record.TTL > 0 && (!found || record.TTL < ttl) {
+			ttl, found = record.TTL, true
+		}
+	}
+	return ttl, found
+}
+
+// CompareAnswers classifies the answer for each record type in recordTypes.
+// A cached answer's TTL counts down between queries, so a TTL that went up
+// means the cache entry expired and was fetched again. Authoritative servers
+// return a constant TTL, which is reported as unchanged.
+func CompareAnswers(previous, current []domain.DNSRecord, recordTypes []domain.DNSRecordType) map[domain.DNSRecordType]AnswerState {
+	states := make(map[domain.DNSRecordType]AnswerState, len(recordTypes))
+	for _, recordType := range recordTypes {
+		if strings.Join(answerValues(previous, recordType), "\n") != strings.Join(answerValues(current, recordType), "\n") {
+			states[recordType] = AnswerChanged
+			continue
+		}
+
+		states[recordType] = AnswerUnchanged
+		before, okBefore := minTTL(previous, recordType)
+		after, okAfter := minTTL(current, recordType)
+		if okBefore && okAfter && after > before {
+			states[recordType] = AnswerRefreshed
+		}
+	}
+	return states
+}
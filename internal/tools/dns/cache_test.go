@@ -0,0 +1,82 @@
+// Package dns provides tests for TTL countdowns and cache change tracking
+package dns
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+func TestFormatRemainingTTL(t *testing.T) {
+	fetched := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		ttl      uint32
+		elapsed  time.Duration
+		expected string
+	}{
+		{"unknown TTL", 0, 0, "-"},
+		{"seconds", 300, 250 * time.Second, "50s"},
+		{"minutes", 300, 55 * time.Second, "4m05s"},
+		{"hours", 86400, time.Hour, "23h00m"},
+		{"expired", 60, 2 * time.Minute, "expired"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := domain.DNSRecord{Name: "example.com", Type: domain.DNSRecordTypeA, TTL: tt.ttl}
+			if got := FormatRemainingTTL(record, fetched, fetched.Add(tt.elapsed)); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCompareAnswers(t *testing.T) {
+	a := func(value string, ttl uint32) domain.DNSRecord {
+		return domain.DNSRecord{Name: "example.com", Type: domain.DNSRecordTypeA, Value: value, TTL: ttl}
+	}
+	mx := domain.DNSRecord{Name: "example.com", Type: domain.DNSRecordTypeMX, Value: "mail.example.com", TTL: 3600}
+	types := []domain.DNSRecordType{domain.DNSRecordTypeA, domain.DNSRecordTypeMX}
+
+	tests := []struct {
+		name     string
+		previous []domain.DNSRecord
+		current  []domain.DNSRecord
+		expected AnswerState
+	}{
+		{"cached answer counts down", []domain.DNSRecord{a("192.0.2.1", 300), mx}, []domain.DNSRecord{a("192.0.2.1", 295), mx}, AnswerUnchanged},
+		{"refreshed answer", []domain.DNSRecord{a("192.0.2.1", 3), mx}, []domain.DNSRecord{a("192.0.2.1", 300), mx}, AnswerRefreshed},
+		{"changed answer", []domain.DNSRecord{a("192.0.2.1", 300), mx}, []domain.DNSRecord{a("192.0.2.2", 300), mx}, AnswerChanged},
+		{"order does not matter", []domain.DNSRecord{a("192.0.2.1", 300), a("192.0.2.2", 300), mx}, []domain.DNSRecord{a("192.0.2.2", 295), a("192.0.2.1", 295), mx}, AnswerUnchanged},
+		{"unknown TTLs", []domain.DNSRecord{a("192.0.2.1", 0), mx}, []domain.DNSRecord{a("192.0.2.1", 0), mx}, AnswerUnchanged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states := CompareAnswers(tt.previous, tt.current, types)
+			if states[domain.DNSRecordTypeA] != tt.expected {
+				t.Errorf("Expected A state %v, got %v", tt.expected, states[domain.DNSRecordTypeA])
+			}
+			if states[domain.DNSRecordTypeMX] != AnswerUnchanged {
+				t.Errorf("Expected MX unchanged, got %v", states[domain.DNSRecordTypeMX])
+			}
+		})
+	}
+}
+
+func TestAnswerChange_String(t *testing.T) {
+	change := AnswerChange{
+		Timestamp:  time.Date(2024, 1, 1, 12, 0, 3, 0, time.UTC),
+		RecordType: domain.DNSRecordTypeA,
+		Previous:   []string{"192.0.2.1"},
+		Current:    nil,
+	}
+
+	expected := "12:00:03 A: 192.0.2.1 → (none)"
+	if got := change.String(); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
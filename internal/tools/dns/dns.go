@@ -4,26 +4,32 @@ package dns
 import (
 	"context"
 	"fmt"
+	"net"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/provider"
 )
 
 // Tool implements the DiagnosticTool interface for DNS operations
 type Tool struct {
-	client domain.NetworkClient
-	logger domain.Logger
+	client   domain.NetworkClient
+	logger   domain.Logger
+	detector *provider.Detector
 }
 
 // NewTool creates a new DNS diagnostic tool
 func NewTool(client domain.NetworkClient, logger domain.Logger) *Tool {
 	return &Tool{
-		client: client,
-		logger: logger,
+		client:   client,
+		logger:   logger,
+		detector: provider.NewDetector(),
 	}
 }
 
@@ -56,6 +62,12 @@ func (t *Tool) Execute(ctx context.Context, params domain.Parameters) (domain.Re
 	domainName := params.Get("domain").(string)
 	recordTypes := t.getRecordTypes(params)
 
+	// Route lookups to a specific server when the user overrides the configured list
+	server, _ := params.Get("server").(string)
+	if server = strings.TrimSpace(server); server != "" {
+		ctx = domain.WithDNSServer(ctx, server)
+	}
+
 	// Perform concurrent DNS lookups for multiple record types
 	results, err := t.performConcurrentLookups(ctx, domainName, recordTypes)
 	if err != nil {
@@ -79,6 +91,15 @@ func (t *Tool) Execute(ctx context.Context, params domain.Parameters) (domain.Re
 	result.SetMetadata("timestamp", time.Now())
 	result.SetMetadata("record_types", recordTypes)
 	result.SetMetadata("total_records", len(consolidatedResult.Records))
+	if server != "" {
+		result.SetMetadata("server", server)
+	}
+	if consolidatedResult.Cached {
+		result.SetMetadata("cached", true)
+	}
+	if info := t.detectProvider(consolidatedResult); info != nil {
+		result.SetMetadata("provider", info)
+	}
 
 	t.logger.Info("DNS lookup completed successfully", "domain", domainName, "record_types", len(recordTypes), "total_records", len(consolidatedResult.Records))
 	return result, nil
@@ -105,6 +126,17 @@ func (t *Tool) Validate(params domain.Parameters) error {
 		return fmt.Errorf("domain must be a valid domain name")
 	}
 
+	// Validate DNS server override if specified
+	if serverParam := params.Get("server"); serverParam != nil {
+		server, ok := serverParam.(string)
+		if !ok {
+			return fmt.Errorf("server parameter must be a string")
+		}
+		if server = strings.TrimSpace(server); server != "" && !isValidServer(server) {
+			return fmt.Errorf("server must be an IP address, IP:port, or %q", domain.SystemDNSServer)
+		}
+	}
+
 	// Validate record types if specified
 	if recordTypesParam := params.Get("record_types"); recordTypesParam != nil {
 		recordTypes, ok := recordTypesParam.([]domain.DNSRecordType)
@@ -251,22 +283,38 @@ func (t *Tool) consolidateResults(domainName string, results map[domain.DNSRecor
 		Authority:    []domain.DNSRecord{},
 		Additional:   []domain.DNSRecord{},
 		ResponseTime: 0,
-		Server:       "system",
+		Server:       domain.SystemDNSServer,
 	}
 
 	var totalResponseTime time.Duration
 	recordCount := 0
+	servers := make(map[string]bool)
+	consolidated.Cached = len(results) > 0
 
 	// Consolidate all records from different types
 	for _, result := range results {
+		if result.Server != "" {
+			servers[result.Server] = true
+		}
 		consolidated.Records = append(consolidated.Records, result.Records...)
 		consolidated.Authority = append(consolidated.Authority, result.Authority...)
 		consolidated.Additional = append(consolidated.Additional, result.Additional...)
+		consolidated.Cached = consolidated.Cached && result.Cached
 		
 		totalResponseTime += result.ResponseTime
 		recordCount++
 	}
 
+	// Report which servers answered, since failover may have used more than one
+	if len(servers) > 0 {
+		answered := make([]string, 0, len(servers))
+		for server := range servers {
+			answered = append(answered, server)
+		}
+		sort.Strings(answered)
+		consolidated.Server = strings.Join(answered, ", ")
+	}
+
 	// Calculate average response time
 	if recordCount > 0 {
 		consolidated.ResponseTime = totalResponseTime / time.Duration(recordCount)
@@ -275,6 +323,31 @@ func (t *Tool) consolidateResults(domainName string, results map[domain.DNSRecor
 	return consolidated
 }
 
+// detectProvider identifies the CDN or hosting provider from CNAME chain and address records
+func (t *Tool) detectProvider(result domain.DNSResult) *domain.ProviderInfo {
+	if t.detector == nil {
+		return nil
+	}
+
+	var evidence provider.Evidence
+	for _, record := range result.Records {
+		switch record.Type {
+		case domain.DNSRecordTypeCNAME:
+			evidence.CNAMEs = append(evidence.CNAMEs, record.Value)
+		case domain.DNSRecordTypeA, domain.DNSRecordTypeAAAA:
+			if ip := net.ParseIP(record.Value); ip != nil {
+				evidence.IPs = append(evidence.IPs, ip)
+			}
+		}
+	}
+
+	info := t.detector.Detect(evidence)
+	if info != nil {
+		t.logger.Debug("Detected hosting provider", "domain", result.Query, "provider", info.Name)
+	}
+	return info
+}
+
 // GetRecordTypeString returns a human-readable string for a DNS record type
 func GetRecordTypeString(recordType domain.DNSRecordType) string {
 	switch recordType {
@@ -432,4 +505,17 @@ func isValidRecordTypeForValidation(recordType domain.DNSRecordType) bool {
 	default:
 		return false
 	}
-}
\ No newline at end of file
+}
+
+// isValidServer validates a DNS server override
+func isValidServer(server string) bool {
+	if server == domain.SystemDNSServer || net.ParseIP(server) != nil {
+		return true
+	}
+	host, port, err := net.SplitHostPort(server)
+	if err != nil || net.ParseIP(host) == nil {
+		return false
+	}
+	portNum, err := strconv.Atoi(port)
+	return err == nil && portNum > 0 && portNum <= 65535
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -591,6 +592,59 @@ func TestConsolidateResults(t *testing.T) {
 	}
 }
 
+func TestTool_Execute_DetectsProvider(t *testing.T) {
+	mockClient := network.NewMockClient()
+	mockLogger := &MockLogger{}
+	tool := NewTool(mockClient, mockLogger)
+
+	mockClient.SetDNSResponse("cdn.example.com", domain.DNSRecordTypeCNAME, domain.DNSResult{
+		Query:      "cdn.example.com",
+		RecordType: domain.DNSRecordTypeCNAME,
+		Records: []domain.DNSRecord{
+			{Name: "cdn.example.com", Type: domain.DNSRecordTypeCNAME, Value: "d111111abcdef8.cloudfront.net.", TTL: 300},
+		},
+		ResponseTime: 10 * time.Millisecond,
+	})
+
+	params := domain.NewDNSParameters("cdn.example.com", domain.DNSRecordTypeCNAME)
+	params.Set("record_types", []domain.DNSRecordType{domain.DNSRecordTypeCNAME})
+
+	result, err := tool.Execute(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	info, ok := result.Metadata()["provider"].(*domain.ProviderInfo)
+	if !ok || info == nil {
+		t.Fatal("Expected provider metadata to be set")
+	}
+
+	if info.Name != "Amazon CloudFront" {
+		t.Errorf("Expected Amazon CloudFront, got %s", info.Name)
+	}
+}
+
+func TestDetectProvider_NoMatch(t *testing.T) {
+	tool := NewTool(network.NewMockClient(), &MockLogger{})
+
+	info := tool.detectProvider(domain.DNSResult{
+		Query: "example.com",
+		Records: []domain.DNSRecord{
+			{Name: "example.com", Type: domain.DNSRecordTypeA, Value: "192.168.1.100", TTL: 300},
+			{Name: "example.com", Type: domain.DNSRecordTypeCNAME, Value: "canonical.example.com", TTL: 300},
+		},
+	})
+
+	if info != nil {
+		t.Errorf("Expected no provider, got %s", info.Name)
+	}
+
+	// Tools constructed without a detector skip detection
+	if (&Tool{}).detectProvider(domain.DNSResult{}) != nil {
+		t.Error("Expected nil provider without detector")
+	}
+}
+
 func TestGetRecordTypeString(t *testing.T) {
 	tests := []struct {
 		recordType domain.DNSRecordType
@@ -824,4 +878,77 @@ func TestValidateDNSRecord(t *testing.T) {
 // Helper function to check if a string contains another string (case insensitive)
 func containsIgnoreCase(s, substr string) bool {
 	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
-}
\ No newline at end of file
+}
+// serverCapturingClient records the DNS server override carried by each lookup context
+type serverCapturingClient struct {
+	*network.MockClient
+	mu      sync.Mutex
+	servers []string
+}
+
+func (c *serverCapturingClient) DNSLookup(ctx context.Context, domainName string, recordType domain.DNSRecordType) (domain.DNSResult, error) {
+	c.mu.Lock()
+	c.servers = append(c.servers, domain.DNSServerFromContext(ctx))
+	c.mu.Unlock()
+	return c.MockClient.DNSLookup(ctx, domainName, recordType)
+}
+
+func TestTool_ServerOverride(t *testing.T) {
+	client := &serverCapturingClient{MockClient: network.NewMockClient()}
+	tool := NewTool(client, &MockLogger{})
+
+	params := domain.NewDNSParameters("example.com", domain.DNSRecordTypeA)
+	params.Set("server", "9.9.9.9:53")
+
+	result, err := tool.Execute(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(client.servers) == 0 || client.servers[0] != "9.9.9.9:53" {
+		t.Errorf("Expected lookups to use the override server, got %v", client.servers)
+	}
+	if result.Metadata()["server"] != "9.9.9.9:53" {
+		t.Errorf("Expected server metadata, got %v", result.Metadata()["server"])
+	}
+
+	// Without an override the configured servers are used
+	client.servers = nil
+	if _, err := tool.Execute(context.Background(), domain.NewDNSParameters("example.com", domain.DNSRecordTypeA)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(client.servers) == 0 || client.servers[0] != "" {
+		t.Errorf("Expected no override, got %v", client.servers)
+	}
+}
+
+func TestTool_ValidateServer(t *testing.T) {
+	tool := NewTool(network.NewMockClient(), &MockLogger{})
+
+	tests := []struct {
+		server      interface{}
+		expectError bool
+	}{
+		{"", false},
+		{"8.8.8.8", false},
+		{"8.8.8.8:5353", false},
+		{"[2001:4860::8888]:53", false},
+		{"system", false},
+		{"dns.google", true},
+		{"8.8.8.8:99999", true},
+		{53, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v", tt.server), func(t *testing.T) {
+			params := domain.NewDNSParameters("example.com", domain.DNSRecordTypeA)
+			params.Set("server", tt.server)
+			err := tool.Validate(params)
+			if tt.expectError && err == nil {
+				t.Error("Expected validation error")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package dns
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
+	"github.com/nettracex/nettracex-tui/internal/keyhint"
+)
+
+// KeyMap holds the keys of the DNS screen. Update matches keys against it
+// and the footer is rendered from it.
+type KeyMap struct {
+	Lookup key.Binding
+	Types  key.Binding
+	Up     key.Binding
+	Down   key.Binding
+	Left   key.Binding
+	Right  key.Binding
+	Toggle key.Binding
+	Watch  key.Binding
+	Pause  key.Binding
+	Copy   key.Binding
+	Back   key.Binding
+	Quit   key.Binding
+}
+
+// DefaultKeyMap returns the keys of the DNS screen, described in the
+// current language
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Lookup: keyhint.New(i18n.T("key.lookup"), "enter"),
+		Types:  keyhint.New(i18n.T("key.select_record_types"), "tab"),
+		Up:     key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", i18n.T("key.move_up"))),
+		Down:   key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", i18n.T("key.move_down"))),
+		Left:   key.NewBinding(key.WithKeys("left"), key.WithHelp("←", i18n.T("key.previous_tab"))),
+		Right:  key.NewBinding(key.WithKeys("right"), key.WithHelp("→", i18n.T("key.next_tab"))),
+		Toggle: key.NewBinding(key.WithKeys(" "), key.WithHelp("space", i18n.T("key.toggle"))),
+		Watch:  keyhint.New(i18n.T("key.watch_cache"), "w"),
+		Pause:  keyhint.New(i18n.T("key.pause"), "p"),
+		Copy:   keyhint.New(i18n.T("key.copy"), "y"),
+		Back:   keyhint.New(i18n.T("key.new_lookup"), "esc"),
+		Quit:   key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", i18n.T("key.quit"))),
+	}
+}
+
+// pair describes two bindings as one hint, e.g. ↑/↓ scroll
+func pair(a, b key.Binding, desc string) key.Binding {
+	return key.NewBinding(
+		key.WithKeys(append(a.Keys(), b.Keys()...)...),
+		key.WithHelp(a.Help().Key+"/"+b.Help().Key, desc),
+	)
+}
+
+// ShortHelp implements help.KeyMap with the keys of the current state
+func (m *Model) ShortHelp() []key.Binding {
+	switch m.state {
+	case StateInput:
+		return []key.Binding{m.keys.Lookup, m.keys.Types, m.keys.Quit}
+	case StateTypeSelection:
+		return []key.Binding{
+			pair(m.keys.Up, m.keys.Down, i18n.T("key.navigate")),
+			m.keys.Toggle,
+			keyhint.Relabel(m.keys.Lookup, i18n.T("key.confirm")),
+			keyhint.Relabel(m.keys.Back, i18n.T("key.back")),
+		}
+	case StateResult:
+		var bindings []key.Binding
+		if len(m.resultTabs) > 1 {
+			bindings = append(bindings, pair(m.keys.Left, m.keys.Right, i18n.T("key.switch_tabs")))
+		}
+		bindings = append(bindings, pair(m.keys.Up, m.keys.Down, i18n.T("key.scroll")))
+		switch {
+		case m.watchPaused:
+			bindings = append(bindings, keyhint.Relabel(m.keys.Pause, i18n.T("key.resume")), keyhint.Relabel(m.keys.Watch, i18n.T("key.stop_watching")))
+		case m.watching:
+			bindings = append(bindings, m.keys.Pause, keyhint.Relabel(m.keys.Watch, i18n.T("key.stop_watching")))
+		default:
+			bindings = append(bindings, m.keys.Watch)
+		}
+		return append(bindings, m.keys.Copy, m.keys.Back, m.keys.Quit)
+	case StateError:
+		return []key.Binding{m.keys.Back, m.keys.Quit}
+	}
+	return []key.Binding{m.keys.Quit}
+}
+
+// FullHelp implements help.KeyMap
+func (m *Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
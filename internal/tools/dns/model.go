@@ -4,19 +4,27 @@ package dns
 import (
 	"context"
 	"fmt"
+	"net"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/clipboard"
+	"github.com/nettracex/nettracex-tui/internal/colors"
 	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/keyhint"
+	"github.com/nettracex/nettracex-tui/internal/targetinput"
+	"github.com/nettracex/nettracex-tui/internal/validate"
 )
 
 // Model represents the DNS tool TUI model
 type Model struct {
 	tool           *Tool
 	state          ModelState
-	input          textinput.Model
+	input          targetinput.Model
 	result         domain.DNSResult
 	error          error
 	width          int
@@ -30,8 +38,35 @@ type Model struct {
 	resultTabs     []ResultTab
 	scrollOffset   int
 	maxScroll      int
+
+	// TTL countdowns run from the time the answer was fetched
+	fetchedAt  time.Time
+	now        time.Time
+	generation int
+
+	// Watch mode re-queries the domain and charts when the cached answer changes
+	query         string
+	queryTypes    []domain.DNSRecordType
+	watching      bool
+	watchPaused   bool
+	watchInterval time.Duration
+	watchRun      int
+	samples       []CacheSample
+	answerChanges []AnswerChange
+	watchErr      error
+
+	// Toast confirms copying the result to the clipboard
+	toast clipboard.Toast
+	keys  KeyMap
 }
 
+// Watch mode defaults
+const (
+	DefaultWatchInterval = 5 * time.Second
+	maxCacheSamples      = 40
+	maxAnswerChanges     = 10
+)
+
 // ModelState represents the current state of the model
 type ModelState int
 
@@ -52,11 +87,9 @@ type ResultTab struct {
 
 // NewModel creates a new DNS model
 func NewModel(tool *Tool) *Model {
-	input := textinput.New()
-	input.Placeholder = "Enter domain name (e.g., example.com, google.com)"
+	input := targetinput.New("Enter domain name (e.g., example.com, google.com)")
+	input.Check = lookupName
 	input.Focus()
-	input.CharLimit = 253
-	input.Width = 50
 
 	// Default to all record types selected
 	selectedTypes := map[domain.DNSRecordType]bool{
@@ -80,6 +113,8 @@ func NewModel(tool *Tool) *Model {
 		resultTabs:     []ResultTab{},
 		scrollOffset:   0,
 		maxScroll:      0,
+		watchInterval:  DefaultWatchInterval,
+		keys:           DefaultKeyMap(),
 	}
 }
 
@@ -94,10 +129,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
+		switch {
+		case key.Matches(msg, m.keys.Quit):
 			return m, tea.Quit
-		case "esc":
+		case key.Matches(msg, m.keys.Back):
 			if m.state == StateTypeSelection {
 				m.state = StateInput
 				m.showTypeSelect = false
@@ -113,17 +148,46 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.resultTab = 0
 				m.scrollOffset = 0
 				m.maxScroll = 0
+				m.watching = false
+				m.watchPaused = false
+				m.resetWatchHistory()
 				return m, nil
 			}
-		case "tab":
+		case key.Matches(msg, m.keys.Watch):
+			if m.state == StateResult {
+				if m.watching {
+					m.watching = false
+					m.watchPaused = false
+					return m, nil
+				}
+				m.watching = true
+				m.watchRun++
+				return m, m.scheduleRequery()
+			}
+		case key.Matches(msg, m.keys.Pause):
+			// Pausing drops the pending requery and keeps the chart;
+			// resuming requeries at once
+			if m.state == StateResult && m.watching {
+				m.watchPaused = !m.watchPaused
+				m.watchRun++
+				if m.watchPaused {
+					return m, nil
+				}
+				return m, m.requery()
+			}
+		case key.Matches(msg, m.keys.Copy):
+			if m.state == StateResult {
+				return m, clipboard.CopyResult(m.result)
+			}
+		case key.Matches(msg, m.keys.Types):
 			if m.state == StateInput {
 				m.state = StateTypeSelection
 				m.showTypeSelect = true
 				m.input.Blur()
 				return m, nil
 			}
-		case "enter":
-			if m.state == StateInput && m.input.Value() != "" {
+		case key.Matches(msg, m.keys.Lookup):
+			if m.state == StateInput && m.input.Value() != "" && m.input.ValidationError() == nil {
 				return m, m.performLookup()
 			} else if m.state == StateTypeSelection {
 				m.state = StateInput
@@ -131,35 +195,42 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.input.Focus()
 				return m, nil
 			}
-		case "up":
+		case key.Matches(msg, m.keys.Up):
 			if m.state == StateTypeSelection && m.typeSelection > 0 {
 				m.typeSelection--
 			} else if m.state == StateResult && m.scrollOffset > 0 {
 				m.scrollOffset--
 			}
-		case "down":
+		case key.Matches(msg, m.keys.Down):
 			if m.state == StateTypeSelection && m.typeSelection < 5 {
 				m.typeSelection++
 			} else if m.state == StateResult && m.scrollOffset < m.maxScroll {
 				m.scrollOffset++
 			}
-		case "left":
+		case key.Matches(msg, m.keys.Left):
 			if m.state == StateResult && len(m.resultTabs) > 0 && m.resultTab > 0 {
 				m.resultTab--
 				m.scrollOffset = 0 // Reset scroll when changing tabs
 			}
-		case "right":
+		case key.Matches(msg, m.keys.Right):
 			if m.state == StateResult && len(m.resultTabs) > 0 && m.resultTab < len(m.resultTabs)-1 {
 				m.resultTab++
 				m.scrollOffset = 0 // Reset scroll when changing tabs
 			}
-		case " ":
+		case key.Matches(msg, m.keys.Toggle):
 			if m.state == StateTypeSelection {
 				recordType := m.getRecordTypeByIndex(m.typeSelection)
 				m.selectedTypes[recordType] = !m.selectedTypes[recordType]
 			}
 		}
 
+	case clipboard.CopiedMsg:
+		return m, m.toast.Show(msg.Status())
+
+	case clipboard.ToastExpiredMsg:
+		m.toast.Update(msg)
+		return m, nil
+
 	case lookupStartMsg:
 		m.state = StateLoading
 		m.loading = true
@@ -169,9 +240,43 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.state = StateResult
 		m.loading = false
 		m.result = msg.result
+		m.fetchedAt = time.Now()
+		m.now = m.fetchedAt
 		m.buildResultTabs()
 		m.calculateMaxScroll()
-		return m, nil
+		m.generation++
+		if m.watching && !m.watchPaused {
+			m.watchRun++
+			return m, tea.Batch(m.scheduleCountdown(), m.scheduleRequery())
+		}
+		return m, m.scheduleCountdown()
+
+	case countdownTickMsg:
+		// Ignore ticks from earlier lookups
+		if m.state != StateResult || msg.generation != m.generation {
+			return m, nil
+		}
+		m.now = msg.now
+		return m, m.scheduleCountdown()
+
+	case requeryMsg:
+		// Ignore requeries from earlier lookups or watch sessions
+		if !m.watching || m.state != StateResult || msg.run != m.watchRun {
+			return m, nil
+		}
+		return m, m.requery()
+
+	case requeryResultMsg:
+		if !m.watching || m.state != StateResult || msg.run != m.watchRun {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.watchErr = msg.err
+			return m, m.scheduleRequery()
+		}
+		m.watchErr = nil
+		m.recordSample(msg.result, msg.timestamp)
+		return m, m.scheduleRequery()
 
 	case lookupErrorMsg:
 		m.state = StateError
@@ -253,11 +358,11 @@ func (m *Model) renderHeader() string {
 	
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
+		Foreground(colors.Info).
 		MarginBottom(1)
 	
 	descStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241"))
+		Foreground(colors.Subtle)
 	
 	return titleStyle.Render(title) + "\n" + descStyle.Render(description)
 }
@@ -268,18 +373,19 @@ func (m *Model) renderInput() string {
 	
 	labelStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("205"))
+		Foreground(colors.Accent)
 	
 	content.WriteString(labelStyle.Render("Domain:"))
 	content.WriteString("\n")
 	content.WriteString(m.input.View())
+	content.WriteString(m.input.Feedback())
 	content.WriteString("\n\n")
 	
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(colors.Subtle).
 		Italic(true)
 	
-	content.WriteString(helpStyle.Render("Enter a domain name (e.g., example.com, google.com)"))
+	content.WriteString(helpStyle.Render("Enter a domain name (e.g., example.com, google.com) • ↑/↓: Recent domains"))
 	
 	return content.String()
 }
@@ -290,7 +396,7 @@ func (m *Model) renderTypeSelection() string {
 	
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39"))
+		Foreground(colors.Info)
 	
 	content.WriteString(titleStyle.Render("Record Types:"))
 	content.WriteString("\n")
@@ -329,7 +435,7 @@ func (m *Model) renderTypeSelection() string {
 		// Style based on selection
 		if i == m.typeSelection {
 			selectedStyle := lipgloss.NewStyle().
-				Foreground(lipgloss.Color("39")).
+				Foreground(colors.Info).
 				Bold(true)
 			content.WriteString(selectedStyle.Render(line.String()))
 		} else {
@@ -339,7 +445,7 @@ func (m *Model) renderTypeSelection() string {
 	}
 	
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(colors.Subtle).
 		Italic(true)
 	
 	content.WriteString("\n")
@@ -351,7 +457,7 @@ func (m *Model) renderTypeSelection() string {
 // renderLoading renders the loading state
 func (m *Model) renderLoading() string {
 	loadingStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("214")).
+		Foreground(colors.Warning).
 		Bold(true)
 	
 	selectedCount := 0
@@ -394,7 +500,7 @@ func (m *Model) renderResult() string {
 		// No records, show message
 		content.WriteString("\n\n")
 		noRecordsStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("243")).
+			Foreground(colors.Muted).
 			Italic(true)
 		content.WriteString(noRecordsStyle.Render("No DNS records found"))
 	}
@@ -410,26 +516,112 @@ func (m *Model) renderResult() string {
 		content.WriteString(m.renderAdditionalSection())
 	}
 	
+	if m.watching || len(m.samples) > 0 {
+		content.WriteString("\n\n")
+		content.WriteString(m.renderCacheWatch())
+	}
+	
 	return content.String()
 }
 
+// renderCacheWatch charts the answer state of each record type across the
+// watch mode queries and lists the recent answer changes
+func (m *Model) renderCacheWatch() string {
+	var content strings.Builder
+	
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colors.Info).
+		MarginBottom(1)
+	
+	title := fmt.Sprintf("Cache Watch (every %v, %d queries)", m.watchInterval, len(m.samples))
+	if !m.watching {
+		title += " - stopped"
+	} else if m.watchPaused {
+		title += " - paused"
+	}
+	content.WriteString(titleStyle.Render(title))
+	content.WriteString("\n")
+	
+	recordTypes := m.queryTypes
+	if len(recordTypes) == 0 {
+		recordTypes = m.getRecordTypes()
+	}
+	
+	changedStyle := lipgloss.NewStyle().Foreground(colors.Error).Bold(true)
+	refreshedStyle := lipgloss.NewStyle().Foreground(colors.Warning)
+	cachedStyle := lipgloss.NewStyle().Foreground(colors.Muted)
+	
+	for _, recordType := range m.getRecordTypes() {
+		if !containsRecordType(recordTypes, recordType) {
+			continue
+		}
+		var line strings.Builder
+		line.WriteString(fmt.Sprintf("  %-6s ", GetRecordTypeString(recordType)))
+		for _, sample := range m.samples {
+			switch sample.States[recordType] {
+			case AnswerChanged:
+				line.WriteString(changedStyle.Render("●"))
+			case AnswerRefreshed:
+				line.WriteString(refreshedStyle.Render("▲"))
+			default:
+				line.WriteString(cachedStyle.Render("·"))
+			}
+		}
+		content.WriteString(line.String())
+		content.WriteString("\n")
+	}
+	
+	helpStyle := lipgloss.NewStyle().
+		Foreground(colors.Subtle).
+		Italic(true)
+	content.WriteString(helpStyle.Render("  · cached  ▲ TTL refreshed  ● answer changed"))
+	
+	if m.watchErr != nil {
+		content.WriteString("\n")
+		content.WriteString(changedStyle.Render(fmt.Sprintf("  Last query failed: %v", m.watchErr)))
+	}
+	
+	if len(m.answerChanges) > 0 {
+		content.WriteString("\n\n")
+		content.WriteString(titleStyle.Render("Answer Changes"))
+		content.WriteString("\n")
+		for _, change := range m.answerChanges {
+			content.WriteString("  " + change.String())
+			content.WriteString("\n")
+		}
+	}
+	
+	return content.String()
+}
+
+// containsRecordType reports whether recordTypes includes recordType
+func containsRecordType(recordTypes []domain.DNSRecordType, recordType domain.DNSRecordType) bool {
+	for _, rt := range recordTypes {
+		if rt == recordType {
+			return true
+		}
+	}
+	return false
+}
+
 // renderSection renders a section with key-value pairs
 func (m *Model) renderSection(title string, data [][]string) string {
 	var content strings.Builder
 	
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
+		Foreground(colors.Info).
 		MarginBottom(1)
 	
 	keyStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("205")).
+		Foreground(colors.Accent).
 		Width(15).
 		Align(lipgloss.Right)
 	
 	valueStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252"))
+		Foreground(colors.Text)
 	
 	content.WriteString(titleStyle.Render(title))
 	content.WriteString("\n")
@@ -454,14 +646,14 @@ func (m *Model) renderAuthoritySection() string {
 	
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
+		Foreground(colors.Info).
 		MarginBottom(1)
 	
 	content.WriteString(titleStyle.Render("Authority Records"))
 	content.WriteString("\n")
 	
 	recordStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252"))
+		Foreground(colors.Text)
 	
 	for _, record := range m.result.Authority {
 		content.WriteString(recordStyle.Render(fmt.Sprintf("  %s %d %s", 
@@ -478,14 +670,14 @@ func (m *Model) renderAdditionalSection() string {
 	
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
+		Foreground(colors.Info).
 		MarginBottom(1)
 	
 	content.WriteString(titleStyle.Render("Additional Records"))
 	content.WriteString("\n")
 	
 	recordStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252"))
+		Foreground(colors.Text)
 	
 	for _, record := range m.result.Additional {
 		content.WriteString(recordStyle.Render(fmt.Sprintf("  %s %d %s", 
@@ -499,41 +691,36 @@ func (m *Model) renderAdditionalSection() string {
 // renderError renders the error state
 func (m *Model) renderError() string {
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("196")).
+		Foreground(colors.Error).
 		Bold(true)
 	
 	return errorStyle.Render(fmt.Sprintf("❌ Error: %s", m.error.Error()))
 }
 
-// renderFooter renders the footer with help text
+// renderFooter renders the footer with the keys of the current state
 func (m *Model) renderFooter() string {
-	var help []string
-	
-	switch m.state {
-	case StateInput:
-		help = []string{"enter: lookup", "tab: select record types", "q: quit"}
-	case StateTypeSelection:
-		help = []string{"↑/↓: navigate", "space: toggle", "enter: confirm", "esc: back"}
-	case StateResult:
-		if len(m.resultTabs) > 1 {
-			help = []string{"←/→: switch tabs", "↑/↓: scroll", "esc: new lookup", "q: quit"}
-		} else {
-			help = []string{"↑/↓: scroll", "esc: new lookup", "q: quit"}
-		}
-	case StateError:
-		help = []string{"esc: new lookup", "q: quit"}
-	case StateLoading:
-		help = []string{"q: quit"}
+	footer := keyhint.Help(m.width).View(m)
+	if message := m.toast.Message(); message != "" && m.state == StateResult {
+		toastStyle := lipgloss.NewStyle().
+			Foreground(colors.Success).
+			Bold(true)
+		footer = toastStyle.Render("✓ "+message) + "\n" + footer
 	}
-	
-	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241"))
-	
-	return helpStyle.Render(strings.Join(help, " • "))
+	return footer
+}
+
+// lookupName accepts a domain to look up, or an address for a PTR lookup
+func lookupName(value string) error {
+	if net.ParseIP(strings.TrimSpace(value)) != nil {
+		return nil
+	}
+	return validate.Domain(value)
 }
 
 // performLookup performs the DNS lookup
 func (m *Model) performLookup() tea.Cmd {
+	m.input.Split()
+	m.input.Remember()
 	domainName := strings.TrimSpace(m.input.Value())
 	
 	// Get selected record types
@@ -544,30 +731,141 @@ func (m *Model) performLookup() tea.Cmd {
 		}
 	}
 	
+	m.resetWatchHistory()
+	m.query = domainName
+	m.queryTypes = selectedTypes
+	
 	return tea.Batch(
 		func() tea.Msg { return lookupStartMsg{} },
 		func() tea.Msg {
-			// Create parameters
-			params := domain.NewDNSParameters(domainName, domain.DNSRecordTypeA) // Default type, will be overridden
-			params.Set("record_types", selectedTypes)
-			
-			// Execute lookup
-			result, err := m.tool.Execute(context.Background(), params)
+			dnsResult, err := m.lookup(domainName, selectedTypes)
 			if err != nil {
 				return lookupErrorMsg{error: err}
 			}
-			
-			// Extract DNS result
-			dnsResult, ok := result.Data().(domain.DNSResult)
-			if !ok {
-				return lookupErrorMsg{error: fmt.Errorf("invalid result type")}
-			}
-			
 			return lookupResultMsg{result: dnsResult}
 		},
 	)
 }
 
+// lookup executes the tool for domainName and the given record types
+func (m *Model) lookup(domainName string, recordTypes []domain.DNSRecordType) (domain.DNSResult, error) {
+	// Create parameters
+	params := domain.NewDNSParameters(domainName, domain.DNSRecordTypeA) // Default type, will be overridden
+	params.Set("record_types", recordTypes)
+	
+	// Execute lookup
+	result, err := m.tool.Execute(context.Background(), params)
+	if err != nil {
+		return domain.DNSResult{}, err
+	}
+	
+	// Extract DNS result
+	dnsResult, ok := result.Data().(domain.DNSResult)
+	if !ok {
+		return domain.DNSResult{}, fmt.Errorf("invalid result type")
+	}
+	return dnsResult, nil
+}
+
+// scheduleCountdown refreshes the TTL countdowns once a second
+func (m *Model) scheduleCountdown() tea.Cmd {
+	generation := m.generation
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return countdownTickMsg{generation: generation, now: t}
+	})
+}
+
+// scheduleRequery waits for the watch interval before querying again
+func (m *Model) scheduleRequery() tea.Cmd {
+	run := m.watchRun
+	return tea.Tick(m.watchInterval, func(time.Time) tea.Msg {
+		return requeryMsg{run: run}
+	})
+}
+
+// requery repeats the last lookup for watch mode
+func (m *Model) requery() tea.Cmd {
+	run := m.watchRun
+	domainName, recordTypes := m.query, m.queryTypes
+	return func() tea.Msg {
+		result, err := m.lookup(domainName, recordTypes)
+		return requeryResultMsg{run: run, result: result, err: err, timestamp: time.Now()}
+	}
+}
+
+// recordSample compares a watch mode answer with the current one, logs the
+// record types whose answer changed and shows the new answer
+func (m *Model) recordSample(result domain.DNSResult, timestamp time.Time) {
+	recordTypes := m.queryTypes
+	if len(recordTypes) == 0 {
+		recordTypes = m.getRecordTypes()
+	}
+	sample := CacheSample{
+		Timestamp: timestamp,
+		States:    CompareAnswers(m.result.Records, result.Records, recordTypes),
+	}
+	for _, recordType := range recordTypes {
+		if sample.States[recordType] == AnswerChanged {
+			m.answerChanges = append(m.answerChanges, AnswerChange{
+				Timestamp:  timestamp,
+				RecordType: recordType,
+				Previous:   answerValues(m.result.Records, recordType),
+				Current:    answerValues(result.Records, recordType),
+			})
+		}
+	}
+	if len(m.answerChanges) > maxAnswerChanges {
+		m.answerChanges = m.answerChanges[len(m.answerChanges)-maxAnswerChanges:]
+	}
+	m.samples = append(m.samples, sample)
+	if len(m.samples) > maxCacheSamples {
+		m.samples = m.samples[len(m.samples)-maxCacheSamples:]
+	}
+
+	// Keep the selected tab while the new answer replaces the old one
+	tab := m.resultTab
+	m.result = result
+	m.fetchedAt = timestamp
+	m.now = timestamp
+	m.buildResultTabs()
+	if tab < len(m.resultTabs) {
+		m.resultTab = tab
+	}
+	m.calculateMaxScroll()
+}
+
+// resetWatchHistory clears the samples and changes recorded in watch mode
+func (m *Model) resetWatchHistory() {
+	m.samples = nil
+	m.answerChanges = nil
+	m.watchErr = nil
+}
+
+// SetWatch enables re-querying the domain every interval once a result is shown
+func (m *Model) SetWatch(enabled bool, interval time.Duration) {
+	m.watching = enabled
+	if interval > 0 {
+		m.watchInterval = interval
+	}
+}
+
+// AnswerChanges returns the detected answer changes, oldest first
+func (m *Model) AnswerChanges() []AnswerChange {
+	return m.answerChanges
+}
+
+// getRecordTypes returns the record types shown in the type selection, in order
+func (m *Model) getRecordTypes() []domain.DNSRecordType {
+	return []domain.DNSRecordType{
+		domain.DNSRecordTypeA,
+		domain.DNSRecordTypeAAAA,
+		domain.DNSRecordTypeMX,
+		domain.DNSRecordTypeTXT,
+		domain.DNSRecordTypeCNAME,
+		domain.DNSRecordTypeNS,
+	}
+}
+
 // getRecordTypeByIndex returns the record type at the given index
 func (m *Model) getRecordTypeByIndex(index int) domain.DNSRecordType {
 	recordTypes := []domain.DNSRecordType{
@@ -679,14 +977,14 @@ func (m *Model) renderTabs() string {
 		if i == m.resultTab {
 			// Active tab
 			tabStyle = tabStyle.
-				Foreground(lipgloss.Color("230")).
-				Background(lipgloss.Color("62")).
+				Foreground(colors.Highlight).
+				Background(colors.Primary).
 				Bold(true)
 		} else {
 			// Inactive tab
 			tabStyle = tabStyle.
-				Foreground(lipgloss.Color("243")).
-				Background(lipgloss.Color("236"))
+				Foreground(colors.Muted).
+				Background(colors.Surface)
 		}
 		
 		tabText := fmt.Sprintf("%s (%d)", tab.Name, len(tab.Records))
@@ -711,7 +1009,7 @@ func (m *Model) renderTabContent(tab ResultTab) string {
 	
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
+		Foreground(colors.Info).
 		MarginBottom(1)
 	
 	content.WriteString(titleStyle.Render(fmt.Sprintf("%s Records (%d)", tab.Name, len(tab.Records))))
@@ -719,7 +1017,7 @@ func (m *Model) renderTabContent(tab ResultTab) string {
 	
 	if len(tab.Records) == 0 {
 		emptyStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("243")).
+			Foreground(colors.Muted).
 			Italic(true)
 		content.WriteString(emptyStyle.Render("No records found"))
 		return content.String()
@@ -743,19 +1041,20 @@ func (m *Model) renderTabContent(tab ResultTab) string {
 	}
 	
 	recordStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252")).
+		Foreground(colors.Text).
 		Padding(0, 2)
 	
 	for i := startIdx; i < endIdx; i++ {
 		record := tab.Records[i]
 		var recordLine string
 		
+		ttl := FormatRemainingTTL(record, m.fetchedAt, m.now)
 		if record.Priority > 0 {
-			recordLine = fmt.Sprintf("%-30s %6d  %-50s (Priority: %d)", 
-				record.Name, record.TTL, record.Value, record.Priority)
+			recordLine = fmt.Sprintf("%-30s %8s  %-50s (Priority: %d)", 
+				record.Name, ttl, record.Value, record.Priority)
 		} else {
-			recordLine = fmt.Sprintf("%-30s %6d  %s", 
-				record.Name, record.TTL, record.Value)
+			recordLine = fmt.Sprintf("%-30s %8s  %s", 
+				record.Name, ttl, record.Value)
 		}
 		
 		content.WriteString(recordStyle.Render(recordLine))
@@ -764,7 +1063,7 @@ func (m *Model) renderTabContent(tab ResultTab) string {
 	
 	// Show scroll indicator if needed
 	scrollStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("243")).
+		Foreground(colors.Muted).
 		Italic(true)
 	
 	scrollInfo := fmt.Sprintf("Showing %d-%d of %d records", 
@@ -788,4 +1087,23 @@ type lookupResultMsg struct {
 
 type lookupErrorMsg struct {
 	error error
+}
+
+// countdownTickMsg refreshes the TTL countdowns of the given lookup
+type countdownTickMsg struct {
+	generation int
+	now        time.Time
+}
+
+// requeryMsg repeats the lookup in watch mode
+type requeryMsg struct {
+	run int
+}
+
+// requeryResultMsg carries the answer of a watch mode lookup
+type requeryResultMsg struct {
+	run       int
+	result    domain.DNSResult
+	err       error
+	timestamp time.Time
 }
\ No newline at end of file
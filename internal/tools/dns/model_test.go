@@ -4,6 +4,7 @@ package dns
 import (
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/nettracex/nettracex-tui/internal/domain"
@@ -513,4 +514,81 @@ func (m *MockTheme) GetStyle(element string) map[string]interface{} {
 
 func (m *MockTheme) SetColor(element, color string) {
 	// Mock implementation
-}
\ No newline at end of file
+}
+func TestModel_WatchMode(t *testing.T) {
+	mockClient := network.NewMockClient()
+	mockLogger := &MockLogger{}
+	tool := NewTool(mockClient, mockLogger)
+	model := NewModel(tool)
+	model.queryTypes = []domain.DNSRecordType{domain.DNSRecordTypeA}
+
+	model.Update(lookupResultMsg{
+		result: domain.DNSResult{
+			Query: "example.com",
+			Records: []domain.DNSRecord{
+				{Name: "example.com", Type: domain.DNSRecordTypeA, Value: "192.0.2.1", TTL: 300},
+			},
+		},
+	})
+	if !strings.Contains(model.View(), "5m00s") {
+		t.Error("Expected the A record TTL countdown in the result view")
+	}
+
+	// Countdown ticks move the remaining TTL
+	model.Update(countdownTickMsg{generation: model.generation, now: model.fetchedAt.Add(61 * time.Second)})
+	if !strings.Contains(model.View(), "3m59s") {
+		t.Error("Expected the countdown to advance")
+	}
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	if !model.watching || cmd == nil {
+		t.Fatal("Expected w to start watching")
+	}
+
+	model.Update(requeryResultMsg{
+		run: model.watchRun,
+		result: domain.DNSResult{
+			Query: "example.com",
+			Records: []domain.DNSRecord{
+				{Name: "example.com", Type: domain.DNSRecordTypeA, Value: "192.0.2.2", TTL: 300},
+			},
+		},
+		timestamp: model.fetchedAt.Add(5 * time.Second),
+	})
+	if len(model.AnswerChanges()) != 1 {
+		t.Fatalf("Expected one answer change, got %+v", model.AnswerChanges())
+	}
+	if model.result.Records[0].Value != "192.0.2.2" {
+		t.Error("Expected the new answer to replace the old one")
+	}
+
+	view := model.View()
+	if !strings.Contains(view, "Cache Watch") || !strings.Contains(view, "192.0.2.1 → 192.0.2.2") {
+		t.Errorf("Expected the cache watch chart and change log, got:\n%s", view)
+	}
+
+	// Pausing drops the pending requery and keeps the chart
+	pending := model.watchRun
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	if _, cmd := model.Update(requeryMsg{run: pending}); cmd != nil {
+		t.Error("Expected no requery while paused")
+	}
+	if view := model.View(); !strings.Contains(view, "paused") || !strings.Contains(view, "192.0.2.1 → 192.0.2.2") {
+		t.Errorf("Expected the paused chart, got:\n%s", view)
+	}
+	if view := model.View(); !strings.Contains(view, "p resume") || !strings.Contains(view, "w stop watching") {
+		t.Errorf("Expected the footer to offer resuming and stopping the watch, got:\n%s", view)
+	}
+
+	// Resuming requeries at once
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}}); cmd == nil || model.watchPaused {
+		t.Error("Expected p to resume watching")
+	}
+
+		// Results from a stopped watch session are ignored
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	model.Update(requeryResultMsg{run: model.watchRun, result: domain.DNSResult{Query: "example.com"}})
+	if len(model.samples) != 1 {
+		t.Errorf("Expected no samples after stopping, got %d", len(model.samples))
+	}
+}
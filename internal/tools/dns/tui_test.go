@@ -376,9 +376,9 @@ func TestDNSTUI_ViewContent(t *testing.T) {
 		"Query DNS records for domains",
 		"Domain:",
 		"Enter a domain name",
-		"enter: lookup",
-		"tab: select record types",
-		"q: quit",
+		"enter lookup",
+		"tab select record types",
+		"q quit",
 	}
 	
 	for _, content := range expectedContent {
@@ -444,7 +444,7 @@ func TestDNSTUI_ViewContent(t *testing.T) {
 		"system",
 		"A (1)",  // Tab format instead of "A Records:"
 		"93.184.216.34",
-		"esc: new lookup",
+		"esc new lookup",
 	}
 	
 	for _, content := range resultContent {
@@ -787,7 +787,7 @@ func TestDNSTUI_SingleRecordTypeDisplay(t *testing.T) {
 	view := harness.GetView()
 	
 	// Should not show tab navigation for single tab
-	if strings.Contains(view, "←/→: switch tabs") {
+	if strings.Contains(view, "←/→ switch tabs") {
 		t.Error("Should not show tab navigation help for single tab")
 	}
 	
@@ -834,7 +834,7 @@ func TestDNSTUI_EmptyResultsDisplay(t *testing.T) {
 	}
 	
 	// Should not show tab navigation
-	if strings.Contains(view, "←/→: switch tabs") {
+	if strings.Contains(view, "←/→ switch tabs") {
 		t.Error("Should not show tab navigation for empty results")
 	}
 }
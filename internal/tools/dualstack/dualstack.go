@@ -0,0 +1,315 @@
+// Package dualstack provides IPv4/IPv6 "Happy Eyeballs" comparison functionality
+package dualstack
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// ConnectionAttemptDelay is the head start RFC 8305 gives IPv6 before IPv4 is attempted
+const ConnectionAttemptDelay = 250 * time.Millisecond
+
+// maxAddressesPerFamily limits how many addresses are tried for each family
+const maxAddressesPerFamily = 3
+
+// Tool implements the DiagnosticTool interface for dual-stack comparisons
+type Tool struct {
+	client domain.NetworkClient
+	logger domain.Logger
+}
+
+// NewTool creates a new dual-stack comparison tool
+func NewTool(client domain.NetworkClient, logger domain.Logger) *Tool {
+	return &Tool{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Name returns the tool name
+func (t *Tool) Name() string {
+	return "dualstack"
+}
+
+// Description returns the tool description
+func (t *Tool) Description() string {
+	return "Compares IPv4 and IPv6 connection times to diagnose broken or slow IPv6 deployments"
+}
+
+// Execute resolves A and AAAA records and races IPv4 against IPv6 connections
+func (t *Tool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	t.logger.Info("Executing dual-stack comparison", "tool", t.Name())
+
+	if err := t.Validate(params); err != nil {
+		return nil, &domain.NetTraceError{
+			Type:      domain.ErrorTypeValidation,
+			Message:   "dual-stack parameter validation failed",
+			Cause:     err,
+			Context:   map[string]interface{}{"params": params.ToMap()},
+			Timestamp: time.Now(),
+			Code:      "DUALSTACK_VALIDATION_FAILED",
+		}
+	}
+
+	connector, ok := t.client.(domain.ConnectivityClient)
+	if !ok {
+		return nil, &domain.NetTraceError{
+			Type:      domain.ErrorTypeSystem,
+			Message:   "network client does not support connection timing",
+			Timestamp: time.Now(),
+			Code:      "DUALSTACK_UNSUPPORTED_CLIENT",
+		}
+	}
+
+	host := strings.TrimSpace(params.Get("host").(string))
+	port := params.Get("port").(int)
+
+	ipv4, ipv6 := t.resolve(ctx, host)
+	if len(ipv4) == 0 && len(ipv6) == 0 {
+		return nil, &domain.NetTraceError{
+			Type:      domain.ErrorTypeNetwork,
+			Message:   "host has no A or AAAA records",
+			Context:   map[string]interface{}{"host": host},
+			Timestamp: time.Now(),
+			Code:      "DUALSTACK_RESOLUTION_FAILED",
+		}
+	}
+
+	// Race both families in parallel so neither result is skewed by the other
+	var wg sync.WaitGroup
+	var v4Result, v6Result domain.DualStackFamilyResult
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		v4Result = t.connectFamily(ctx, connector, domain.AddressFamilyIPv4, ipv4, port)
+	}()
+	go func() {
+		defer wg.Done()
+		v6Result = t.connectFamily(ctx, connector, domain.AddressFamilyIPv6, ipv6, port)
+	}()
+	wg.Wait()
+
+	dualStack := Compare(v4Result, v6Result)
+	dualStack.Host = host
+	dualStack.Port = port
+	dualStack.Timestamp = time.Now()
+
+	result := domain.NewResult(dualStack)
+	result.SetMetadata("tool", t.Name())
+	result.SetMetadata("host", host)
+	result.SetMetadata("port", port)
+	result.SetMetadata("timestamp", dualStack.Timestamp)
+	result.SetMetadata("winner", dualStack.Winner)
+	result.SetMetadata("preferred", dualStack.Preferred)
+
+	t.logger.Info("Dual-stack comparison completed", "host", host, "winner", dualStack.Winner, "margin", dualStack.Margin)
+	return result, nil
+}
+
+// Validate validates the parameters for dual-stack comparisons
+func (t *Tool) Validate(params domain.Parameters) error {
+	hostStr, ok := params.Get("host").(string)
+	if !ok {
+		return fmt.Errorf("host parameter is required")
+	}
+
+	if strings.TrimSpace(hostStr) == "" {
+		return fmt.Errorf("host parameter cannot be empty")
+	}
+
+	if !isValidHost(hostStr) {
+		return fmt.Errorf("host must be a valid hostname or IP address")
+	}
+
+	var portInt int
+	switch v := params.Get("port").(type) {
+	case nil:
+		portInt = 443
+	case int:
+		portInt = v
+	case string:
+		var err error
+		portInt, err = strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return fmt.Errorf("port parameter must be a valid integer")
+		}
+	default:
+		return fmt.Errorf("port parameter must be an integer or string")
+	}
+
+	if portInt <= 0 || portInt > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535")
+	}
+
+	// Normalise the port parameter to an integer
+	params.Set("port", portInt)
+
+	return nil
+}
+
+// GetModel returns the Bubble Tea model for the dual-stack tool
+func (t *Tool) GetModel() tea.Model {
+	return NewModel(t)
+}
+
+// resolve returns the IPv4 and IPv6 addresses for host
+func (t *Tool) resolve(ctx context.Context, host string) ([]net.IP, []net.IP) {
+	if ip := net.ParseIP(host); ip != nil {
+		if ip.To4() != nil {
+			return []net.IP{ip}, nil
+		}
+		return nil, []net.IP{ip}
+	}
+
+	return t.lookup(ctx, host, domain.DNSRecordTypeA), t.lookup(ctx, host, domain.DNSRecordTypeAAAA)
+}
+
+// lookup queries a single record type and returns the parsed addresses
+func (t *Tool) lookup(ctx context.Context, host string, recordType domain.DNSRecordType) []net.IP {
+	dnsResult, err := t.client.DNSLookup(ctx, host, recordType)
+	if err != nil {
+		t.logger.Debug("Dual-stack lookup failed", "host", host, "record_type", recordType, "error", err)
+		return nil
+	}
+
+	var ips []net.IP
+	for _, record := range dnsResult.Records {
+		if record.Type != recordType {
+			continue
+		}
+		if ip := net.ParseIP(record.Value); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
+
+// connectFamily tries addresses of one family in order until a connection succeeds
+func (t *Tool) connectFamily(ctx context.Context, connector domain.ConnectivityClient, family string, ips []net.IP, port int) domain.DualStackFamilyResult {
+	result := domain.DualStackFamilyResult{Family: family}
+	for _, ip := range ips {
+		result.Addresses = append(result.Addresses, ip.String())
+	}
+
+	if len(ips) == 0 {
+		result.Error = "no addresses"
+		return result
+	}
+
+	if len(ips) > maxAddressesPerFamily {
+		ips = ips[:maxAddressesPerFamily]
+	}
+
+	var total time.Duration
+	for _, ip := range ips {
+		elapsed, err := connector.TCPConnect(ctx, ip, port)
+		total += elapsed
+		if err == nil {
+			result.Success = true
+			result.Connected = ip.String()
+			result.ConnectTime = total
+			result.Error = ""
+			return result
+		}
+		result.Error = err.Error()
+	}
+
+	result.ConnectTime = total
+	return result
+}
+
+// Compare builds a dual-stack result from the per-family connection results
+func Compare(v4, v6 domain.DualStackFamilyResult) domain.DualStackResult {
+	result := domain.DualStackResult{
+		IPv4: v4,
+		IPv6: v6,
+	}
+
+	switch {
+	case v4.Success && v6.Success:
+		if v6.ConnectTime <= v4.ConnectTime {
+			result.Winner = domain.AddressFamilyIPv6
+			result.Margin = v4.ConnectTime - v6.ConnectTime
+		} else {
+			result.Winner = domain.AddressFamilyIPv4
+			result.Margin = v6.ConnectTime - v4.ConnectTime
+		}
+	case v4.Success:
+		result.Winner = domain.AddressFamilyIPv4
+	case v6.Success:
+		result.Winner = domain.AddressFamilyIPv6
+	}
+
+	// A Happy Eyeballs client starts IPv6 first and only races IPv4 after the attempt delay
+	switch {
+	case v6.Success && (!v4.Success || v6.ConnectTime <= v4.ConnectTime+ConnectionAttemptDelay):
+		result.Preferred = domain.AddressFamilyIPv6
+	case v4.Success:
+		result.Preferred = domain.AddressFamilyIPv4
+	}
+
+	result.Diagnosis = diagnose(result)
+	return result
+}
+
+// diagnose explains the comparison in terms of likely deployment problems
+func diagnose(result domain.DualStackResult) []string {
+	v4, v6 := result.IPv4, result.IPv6
+	var notes []string
+
+	switch {
+	case len(v4.Addresses) == 0 && len(v6.Addresses) == 0:
+		notes = append(notes, "No A or AAAA records were found")
+	case len(v6.Addresses) == 0:
+		notes = append(notes, "No AAAA records published; the host is reachable over IPv4 only")
+	case len(v4.Addresses) == 0:
+		notes = append(notes, "No A records published; the host is reachable over IPv6 only")
+	}
+
+	switch {
+	case !v4.Success && !v6.Success:
+		notes = append(notes, "No connection could be established over either address family")
+	case len(v6.Addresses) > 0 && !v6.Success && v4.Success:
+		notes = append(notes, "IPv6 appears broken: AAAA records exist but no IPv6 connection succeeded (check local IPv6 connectivity too)")
+	case len(v4.Addresses) > 0 && !v4.Success && v6.Success:
+		notes = append(notes, "IPv4 appears broken: A records exist but no IPv4 connection succeeded")
+	case v4.Success && v6.Success && result.Preferred == domain.AddressFamilyIPv4:
+		notes = append(notes, fmt.Sprintf("IPv6 is more than %v slower than IPv4; Happy Eyeballs clients will fall back to IPv4", ConnectionAttemptDelay))
+	case v4.Success && v6.Success:
+		notes = append(notes, "Both address families are healthy")
+	}
+
+	return notes
+}
+
+// isValidHost validates if the host is a valid hostname or IP address
+func isValidHost(host string) bool {
+	host = strings.TrimSpace(host)
+
+	if len(host) == 0 || len(host) > 253 {
+		return false
+	}
+
+	if net.ParseIP(host) != nil {
+		return true
+	}
+
+	for _, char := range host {
+		if !((char >= 'a' && char <= 'z') ||
+			(char >= 'A' && char <= 'Z') ||
+			(char >= '0' && char <= '9') ||
+			char == '.' || char == '-') {
+			return false
+		}
+	}
+
+	return true
+}
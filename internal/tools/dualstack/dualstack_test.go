@@ -0,0 +1,225 @@
+// Package dualstack provides dual-stack comparison functionality tests
+package dualstack
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/network"
+)
+
+// testLogger implements domain.Logger and discards all output
+type testLogger struct{}
+
+func (testLogger) Debug(msg string, fields ...interface{}) {}
+func (testLogger) Info(msg string, fields ...interface{})  {}
+func (testLogger) Warn(msg string, fields ...interface{})  {}
+func (testLogger) Error(msg string, fields ...interface{}) {}
+func (testLogger) Fatal(msg string, fields ...interface{}) {}
+
+// newMockHost configures A and AAAA responses for host on the mock client
+func newMockHost(client *network.MockClient, host string, v4, v6 []string) {
+	records := func(recordType domain.DNSRecordType, values []string) domain.DNSResult {
+		result := domain.DNSResult{Query: host, RecordType: recordType}
+		for _, value := range values {
+			result.Records = append(result.Records, domain.DNSRecord{Name: host, Type: recordType, Value: value, TTL: 300})
+		}
+		return result
+	}
+	client.SetDNSResponse(host, domain.DNSRecordTypeA, records(domain.DNSRecordTypeA, v4))
+	client.SetDNSResponse(host, domain.DNSRecordTypeAAAA, records(domain.DNSRecordTypeAAAA, v6))
+}
+
+func TestTool_NameAndDescription(t *testing.T) {
+	tool := NewTool(network.NewMockClient(), testLogger{})
+
+	if tool.Name() != "dualstack" {
+		t.Errorf("Expected name 'dualstack', got %s", tool.Name())
+	}
+	if tool.Description() == "" {
+		t.Error("Expected non-empty description")
+	}
+	if tool.GetModel() == nil {
+		t.Error("Expected a model")
+	}
+}
+
+func TestTool_Validate(t *testing.T) {
+	tool := NewTool(network.NewMockClient(), testLogger{})
+
+	tests := []struct {
+		name        string
+		host        interface{}
+		port        interface{}
+		expectError bool
+		expectPort  int
+	}{
+		{"valid host and port", "example.com", 443, false, 443},
+		{"string port", "example.com", "8443", false, 8443},
+		{"default port", "example.com", nil, false, 443},
+		{"ipv6 literal", "2001:db8::1", 80, false, 80},
+		{"missing host", nil, 443, true, 0},
+		{"empty host", "  ", 443, true, 0},
+		{"invalid host", "bad@host", 443, true, 0},
+		{"invalid port", "example.com", 70000, true, 0},
+		{"non-numeric port", "example.com", "https", true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := domain.NewParameters()
+			if tt.host != nil {
+				params.Set("host", tt.host)
+			}
+			if tt.port != nil {
+				params.Set("port", tt.port)
+			}
+
+			err := tool.Validate(params)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected validation error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if params.Get("port") != tt.expectPort {
+				t.Errorf("Expected port %d, got %v", tt.expectPort, params.Get("port"))
+			}
+		})
+	}
+}
+
+func TestTool_Execute_IPv6Wins(t *testing.T) {
+	client := network.NewMockClient()
+	newMockHost(client, "example.com", []string{"93.184.216.34"}, []string{"2606:2800:220:1::1"})
+	client.SetConnectTime("93.184.216.34", 443, 40*time.Millisecond)
+	client.SetConnectTime("2606:2800:220:1::1", 443, 25*time.Millisecond)
+
+	tool := NewTool(client, testLogger{})
+	result, err := tool.Execute(context.Background(), domain.NewDualStackParameters("example.com", 443))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	dualStack, ok := result.Data().(domain.DualStackResult)
+	if !ok {
+		t.Fatalf("Expected DualStackResult, got %T", result.Data())
+	}
+	if dualStack.Winner != domain.AddressFamilyIPv6 {
+		t.Errorf("Expected IPv6 to win, got %s", dualStack.Winner)
+	}
+	if dualStack.Margin != 15*time.Millisecond {
+		t.Errorf("Expected 15ms margin, got %v", dualStack.Margin)
+	}
+	if dualStack.Preferred != domain.AddressFamilyIPv6 {
+		t.Errorf("Expected Happy Eyeballs to prefer IPv6, got %s", dualStack.Preferred)
+	}
+	if result.Metadata()["winner"] != domain.AddressFamilyIPv6 {
+		t.Errorf("Expected winner metadata, got %v", result.Metadata()["winner"])
+	}
+	if len(client.GetConnectCalls()) != 2 {
+		t.Errorf("Expected 2 connect calls, got %d", len(client.GetConnectCalls()))
+	}
+}
+
+func TestTool_Execute_BrokenIPv6(t *testing.T) {
+	client := network.NewMockClient()
+	newMockHost(client, "broken.example", []string{"93.184.216.34"}, []string{"2606:2800:220:1::1", "2606:2800:220:1::2"})
+	client.SetConnectError("2606:2800:220:1::1", 443, fmt.Errorf("network unreachable"))
+	client.SetConnectError("2606:2800:220:1::2", 443, fmt.Errorf("network unreachable"))
+
+	tool := NewTool(client, testLogger{})
+	result, err := tool.Execute(context.Background(), domain.NewDualStackParameters("broken.example", 443))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	dualStack := result.Data().(domain.DualStackResult)
+	if dualStack.IPv6.Success {
+		t.Error("Expected IPv6 to fail")
+	}
+	if dualStack.Winner != domain.AddressFamilyIPv4 || dualStack.Preferred != domain.AddressFamilyIPv4 {
+		t.Errorf("Expected IPv4 fallback, got winner=%s preferred=%s", dualStack.Winner, dualStack.Preferred)
+	}
+	if len(dualStack.Diagnosis) == 0 || !containsText(dualStack.Diagnosis, "IPv6 appears broken") {
+		t.Errorf("Expected broken IPv6 diagnosis, got %v", dualStack.Diagnosis)
+	}
+}
+
+func TestTool_Execute_NoRecords(t *testing.T) {
+	client := network.NewMockClient()
+	newMockHost(client, "empty.example", nil, nil)
+
+	tool := NewTool(client, testLogger{})
+	_, err := tool.Execute(context.Background(), domain.NewDualStackParameters("empty.example", 443))
+
+	netErr, ok := err.(*domain.NetTraceError)
+	if !ok {
+		t.Fatalf("Expected NetTraceError, got %v", err)
+	}
+	if netErr.Code != "DUALSTACK_RESOLUTION_FAILED" {
+		t.Errorf("Expected DUALSTACK_RESOLUTION_FAILED, got %s", netErr.Code)
+	}
+}
+
+func TestTool_Execute_IPLiteral(t *testing.T) {
+	client := network.NewMockClient()
+
+	tool := NewTool(client, testLogger{})
+	result, err := tool.Execute(context.Background(), domain.NewDualStackParameters("192.0.2.10", 80))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	dualStack := result.Data().(domain.DualStackResult)
+	if !dualStack.IPv4.Success || len(dualStack.IPv6.Addresses) != 0 {
+		t.Errorf("Expected IPv4-only comparison, got %+v", dualStack)
+	}
+	if len(client.GetDNSCalls()) != 0 {
+		t.Error("IP literals should not be resolved")
+	}
+}
+
+func TestCompare_HappyEyeballsDelay(t *testing.T) {
+	v4 := domain.DualStackFamilyResult{Family: domain.AddressFamilyIPv4, Addresses: []string{"192.0.2.1"}, Success: true, ConnectTime: 20 * time.Millisecond}
+
+	// IPv6 loses the race but within the attempt delay, so clients still use it
+	v6 := domain.DualStackFamilyResult{Family: domain.AddressFamilyIPv6, Addresses: []string{"2001:db8::1"}, Success: true, ConnectTime: 120 * time.Millisecond}
+	result := Compare(v4, v6)
+	if result.Winner != domain.AddressFamilyIPv4 || result.Preferred != domain.AddressFamilyIPv6 {
+		t.Errorf("Expected IPv4 winner with IPv6 preferred, got winner=%s preferred=%s", result.Winner, result.Preferred)
+	}
+
+	// IPv6 slower than the attempt delay allows
+	v6.ConnectTime = 400 * time.Millisecond
+	result = Compare(v4, v6)
+	if result.Preferred != domain.AddressFamilyIPv4 {
+		t.Errorf("Expected IPv4 preferred, got %s", result.Preferred)
+	}
+	if !containsText(result.Diagnosis, "slower than IPv4") {
+		t.Errorf("Expected slow IPv6 diagnosis, got %v", result.Diagnosis)
+	}
+
+	// Neither family connects
+	result = Compare(domain.DualStackFamilyResult{Addresses: []string{"192.0.2.1"}}, domain.DualStackFamilyResult{})
+	if result.Winner != "" || result.Preferred != "" {
+		t.Errorf("Expected no winner, got winner=%s preferred=%s", result.Winner, result.Preferred)
+	}
+}
+
+// containsText reports whether any note contains text
+func containsText(notes []string, text string) bool {
+	for _, note := range notes {
+		if strings.Contains(note, text) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,334 @@
+// Package dualstack provides dual-stack comparison TUI components
+package dualstack
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/clipboard"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/targetinput"
+	"github.com/nettracex/nettracex-tui/internal/tui"
+	"github.com/nettracex/nettracex-tui/internal/validate"
+)
+
+// CompleteMsg is sent when a dual-stack comparison finishes
+type CompleteMsg struct {
+	Result domain.DualStackResult
+}
+
+// ErrorMsg is sent when a dual-stack comparison fails
+type ErrorMsg struct {
+	Error error
+}
+
+// Model represents the dual-stack comparison TUI model
+type Model struct {
+	tool         *Tool
+	state        tui.ViewState
+	hostInput    targetinput.Model
+	portInput    textinput.Model
+	focusedInput int
+	result       *domain.DualStackResult
+	error        error
+	width        int
+	height       int
+	theme        domain.Theme
+	toast        clipboard.Toast
+}
+
+// NewModel creates a new dual-stack model
+func NewModel(tool *Tool) *Model {
+	hostInput := targetinput.New("Enter hostname or URL (e.g., google.com, https://example.com:8443)")
+	hostInput.Check = validate.Host
+	hostInput.TakesPort = true
+	hostInput.Focus()
+
+	portInput := textinput.New()
+	portInput.Placeholder = "443"
+	portInput.CharLimit = 5
+	portInput.Width = 10
+
+	return &Model{
+		tool:      tool,
+		state:     tui.ViewStateInput,
+		hostInput: hostInput,
+		portInput: portInput,
+		theme:     tui.NewDefaultTheme(),
+	}
+}
+
+// Init initializes the model
+func (m *Model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles messages and updates the model
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			if m.state == tui.ViewStateResult || m.state == tui.ViewStateError {
+				m.state = tui.ViewStateInput
+				m.result = nil
+				m.error = nil
+				return m, nil
+			}
+		case "enter":
+			if m.state == tui.ViewStateInput {
+				return m, m.executeComparison()
+			}
+		case "y":
+			if m.state == tui.ViewStateResult && m.result != nil {
+				return m, clipboard.CopyResult(*m.result)
+			}
+		case "tab", "shift+tab":
+			if m.state == tui.ViewStateInput {
+				m.focusedInput = (m.focusedInput + 1) % 2
+				m.updateInputFocus()
+				return m, nil
+			}
+		}
+
+	case CompleteMsg:
+		m.state = tui.ViewStateResult
+		m.result = &msg.Result
+		return m, nil
+
+	case ErrorMsg:
+		m.state = tui.ViewStateError
+		m.error = msg.Error
+		return m, nil
+
+	case clipboard.CopiedMsg:
+		return m, m.toast.Show(msg.Status())
+
+	case clipboard.ToastExpiredMsg:
+		m.toast.Update(msg)
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.SetSize(msg.Width, msg.Height)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	if m.state == tui.ViewStateInput {
+		if m.focusedInput == 0 {
+			m.hostInput, cmd = m.hostInput.Update(msg)
+			if port, ok := m.hostInput.TakePort(); ok {
+				m.portInput.SetValue(port)
+			}
+		} else {
+			m.portInput, cmd = m.portInput.Update(msg)
+		}
+	}
+
+	return m, cmd
+}
+
+// View renders the model
+func (m *Model) View() string {
+	switch m.state {
+	case tui.ViewStateInput:
+		return m.renderInputView()
+	case tui.ViewStateLoading:
+		return m.style("primary").Bold(true).Render("Racing IPv4 against IPv6...")
+	case tui.ViewStateResult:
+		return m.renderResultView()
+	case tui.ViewStateError:
+		return m.style("error").Render(fmt.Sprintf("Error: %v", m.error)) + "\n\n" + m.renderHelp("Esc: Back • Ctrl+C: Quit")
+	default:
+		return "Unknown state"
+	}
+}
+
+// SetSize sets the model size
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	if width > 10 && width-10 < 50 {
+		m.hostInput.Width = width - 10
+	}
+}
+
+// SetTheme sets the model theme
+func (m *Model) SetTheme(theme domain.Theme) {
+	m.theme = theme
+}
+
+// Focus focuses the model
+func (m *Model) Focus() {
+	if m.state == tui.ViewStateInput {
+		m.updateInputFocus()
+	}
+}
+
+// Blur blurs the model
+func (m *Model) Blur() {
+	m.hostInput.Blur()
+	m.portInput.Blur()
+}
+
+// updateInputFocus updates the focus state of inputs
+func (m *Model) updateInputFocus() {
+	if m.focusedInput == 0 {
+		m.hostInput.Focus()
+		m.portInput.Blur()
+	} else {
+		m.hostInput.Blur()
+		m.portInput.Focus()
+	}
+}
+
+// executeComparison runs the dual-stack comparison
+func (m *Model) executeComparison() tea.Cmd {
+	// A port typed with the host, as in example.com:8443, fills the port field
+	if port, ok := m.hostInput.Split(); ok {
+		m.portInput.SetValue(port)
+	}
+	host := strings.TrimSpace(m.hostInput.Value())
+	port := strings.TrimSpace(m.portInput.Value())
+
+	if host == "" {
+		return func() tea.Msg {
+			return ErrorMsg{Error: fmt.Errorf("host is required")}
+		}
+	}
+	if err := m.hostInput.ValidationError(); err != nil {
+		return func() tea.Msg {
+			return ErrorMsg{Error: err}
+		}
+	}
+	m.hostInput.Remember()
+	if port == "" {
+		port = "443"
+	}
+
+	m.state = tui.ViewStateLoading
+
+	return func() tea.Msg {
+		params := domain.NewParameters()
+		params.Set("host", host)
+		params.Set("port", port)
+
+		result, err := m.tool.Execute(context.Background(), params)
+		if err != nil {
+			return ErrorMsg{Error: err}
+		}
+
+		dualStack, ok := result.Data().(domain.DualStackResult)
+		if !ok {
+			return ErrorMsg{Error: fmt.Errorf("invalid result type")}
+		}
+
+		return CompleteMsg{Result: dualStack}
+	}
+}
+
+// renderInputView renders the input form
+func (m *Model) renderInputView() string {
+	var b strings.Builder
+
+	b.WriteString(m.style("primary").Bold(true).Render("Dual-Stack Comparison"))
+	b.WriteString("\n\n")
+	b.WriteString(m.style("text").Bold(true).Render("Host:"))
+	b.WriteString("\n")
+	b.WriteString(m.hostInput.View())
+	b.WriteString(m.hostInput.Feedback())
+	b.WriteString("\n\n")
+	b.WriteString(m.style("text").Bold(true).Render("Port:"))
+	b.WriteString("\n")
+	b.WriteString(m.portInput.View())
+	b.WriteString("\n\n")
+	b.WriteString(m.renderHelp("Tab: Switch fields • ↑/↓: Recent hosts • Enter: Compare • Esc: Back • Ctrl+C: Quit"))
+
+	return b.String()
+}
+
+// renderResultView renders the comparison results
+func (m *Model) renderResultView() string {
+	if m.result == nil {
+		return "No results available"
+	}
+
+	var b strings.Builder
+	label := m.style("accent").Bold(true)
+
+	b.WriteString(m.style("primary").Bold(true).Render(fmt.Sprintf("Dual-Stack: %s:%d", m.result.Host, m.result.Port)))
+	b.WriteString("\n\n")
+
+	for _, family := range []domain.DualStackFamilyResult{m.result.IPv6, m.result.IPv4} {
+		b.WriteString(label.Render(strings.ToUpper(family.Family) + ": "))
+		b.WriteString(m.renderFamily(family))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(label.Render("Winner: "))
+	b.WriteString(m.style("text").Render(formatWinner(*m.result)))
+	b.WriteString("\n")
+	b.WriteString(label.Render("Happy Eyeballs choice: "))
+	b.WriteString(m.style("text").Render(strings.ToUpper(orNone(m.result.Preferred))))
+	b.WriteString("\n\n")
+
+	for _, note := range m.result.Diagnosis {
+		b.WriteString(m.style("text").Render("  • " + note))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	if message := m.toast.Message(); message != "" {
+		b.WriteString(m.style("success").Render("✓ " + message))
+		b.WriteString("\n")
+	}
+	b.WriteString(m.renderHelp("y: Copy • Esc: Back • Ctrl+C: Quit"))
+
+	return b.String()
+}
+
+// renderFamily renders the outcome for one address family
+func (m *Model) renderFamily(family domain.DualStackFamilyResult) string {
+	if family.Success {
+		return m.style("success").Render(fmt.Sprintf("✅ %s in %v", family.Connected, family.ConnectTime))
+	}
+	return m.style("error").Render(fmt.Sprintf("❌ %s", family.Error))
+}
+
+// renderHelp renders a help line
+func (m *Model) renderHelp(text string) string {
+	return m.style("muted").Italic(true).Render(text)
+}
+
+// style returns a style using the named theme color
+func (m *Model) style(color string) lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(colors.Adaptive(m.theme.GetColor(color)))
+}
+
+// formatWinner describes which family won and by how much
+func formatWinner(result domain.DualStackResult) string {
+	switch {
+	case result.Winner == "":
+		return "NONE"
+	case result.IPv4.Success && result.IPv6.Success:
+		return fmt.Sprintf("%s by %v", strings.ToUpper(result.Winner), result.Margin)
+	default:
+		return fmt.Sprintf("%s (only family that connected)", strings.ToUpper(result.Winner))
+	}
+}
+
+// orNone returns "none" for empty strings
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
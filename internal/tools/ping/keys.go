@@ -0,0 +1,105 @@
+package ping
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
+	"github.com/nettracex/nettracex-tui/internal/keyhint"
+)
+
+// KeyMap holds the keys of the ping screen. Update matches keys against it
+// and the footer and running instructions are rendered from it.
+type KeyMap struct {
+	NextField      key.Binding
+	PrevField      key.Binding
+	Mode           key.Binding
+	Start          key.Binding
+	Copy           key.Binding
+	Pause          key.Binding
+	StopContinuous key.Binding
+	ScrollGraph    key.Binding
+	Up             key.Binding
+	Down           key.Binding
+	LiveView       key.Binding
+	Back           key.Binding
+	Quit           key.Binding
+}
+
+// DefaultKeyMap returns the keys of the ping screen, described in the
+// current language
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		NextField:      keyhint.New(i18n.T("key.next_field"), "tab"),
+		PrevField:      keyhint.New(i18n.T("key.previous_field"), "shift+tab"),
+		Mode:           key.NewBinding(key.WithKeys("left", "right", " "), key.WithHelp("←/→", i18n.T("key.change_mode"))),
+		Start:          keyhint.New(i18n.T("key.start_ping"), "enter"),
+		Copy:           keyhint.New(i18n.T("key.copy"), "y"),
+		Pause:          keyhint.New(i18n.T("key.pause"), "p"),
+		StopContinuous: keyhint.New(i18n.T("key.stop_continuous"), "s"),
+		// The wheel is a mouse button, so this binding only describes it
+		ScrollGraph: keyhint.New(i18n.T("key.scroll_graph"), "wheel"),
+		Up:          key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑", i18n.T("key.move_up"))),
+		Down:        key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓", i18n.T("key.move_down"))),
+		LiveView:    keyhint.New(i18n.T("key.live_view"), "enter"),
+		Back:        keyhint.New(i18n.T("key.new_ping"), "esc"),
+		Quit:        key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", i18n.T("key.quit"))),
+	}
+}
+
+// pair describes two bindings as one hint, e.g. ↑/↓ select host
+func pair(a, b key.Binding, desc string) key.Binding {
+	return key.NewBinding(
+		key.WithKeys(append(a.Keys(), b.Keys()...)...),
+		key.WithHelp(a.Help().Key+"/"+b.Help().Key, desc),
+	)
+}
+
+// ShortHelp implements help.KeyMap with the keys of the current state
+func (m *Model) ShortHelp() []key.Binding {
+	// Esc leaves the live view of one host for the overview of all hosts
+	if m.isMulti() && m.drillDown >= 0 {
+		return []key.Binding{m.keys.Copy, keyhint.Relabel(m.keys.Back, i18n.T("key.overview")), m.keys.Quit}
+	}
+	// A live model has no form to return to: esc leaves it
+	if m.live && m.state != StateRunning {
+		back := keyhint.Relabel(m.keys.Back, i18n.T("key.back"))
+		if m.state == StateResult {
+			return []key.Binding{m.keys.Copy, back}
+		}
+		return []key.Binding{back}
+	}
+	switch m.state {
+	case StateInput:
+		return []key.Binding{m.keys.NextField, m.keys.Mode, m.keys.Start, m.keys.Quit}
+	case StateResult:
+		return []key.Binding{m.keys.Copy, m.keys.Back, m.keys.Quit}
+	case StateError:
+		return []key.Binding{m.keys.Back, m.keys.Quit}
+	case StateRunning:
+		return []key.Binding{m.keys.Copy, keyhint.Relabel(m.keys.Quit, i18n.T("key.stop"))}
+	}
+	return []key.Binding{m.keys.Quit}
+}
+
+// FullHelp implements help.KeyMap
+func (m *Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+// targetKeys returns the keys choosing a host in the multi-target overview
+func (m *Model) targetKeys() []key.Binding {
+	return []key.Binding{pair(m.keys.Up, m.keys.Down, i18n.T("key.select_host")), m.keys.LiveView}
+}
+
+// runningKeys returns the keys controlling a running ping
+func (m *Model) runningKeys() []key.Binding {
+	var bindings []key.Binding
+	if m.paused {
+		bindings = append(bindings, keyhint.Relabel(m.keys.Pause, i18n.T("key.resume")), m.keys.StopContinuous)
+	} else if m.continuousMode {
+		bindings = append(bindings, m.keys.Pause, m.keys.StopContinuous)
+	}
+	if len(m.latencyGraph.History) > m.latencyGraph.MaxValues {
+		bindings = append(bindings, m.keys.ScrollGraph)
+	}
+	return bindings
+}
@@ -8,19 +8,30 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/chart"
+	"github.com/nettracex/nettracex-tui/internal/clipboard"
+	"github.com/nettracex/nettracex-tui/internal/colors"
 	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/keyhint"
+	"github.com/nettracex/nettracex-tui/internal/progressbar"
+	"github.com/nettracex/nettracex-tui/internal/stats"
+	"github.com/nettracex/nettracex-tui/internal/targetinput"
+	"github.com/nettracex/nettracex-tui/internal/validate"
 )
 
 // Model represents the ping tool TUI model
 type Model struct {
 	tool         *Tool
 	state        ModelState
-	hostInput    textinput.Model
+	hostInput    targetinput.Model
 	countInput   textinput.Model
 	intervalInput textinput.Model
+	mode         domain.PingMode
 	focusedInput int
 	results      []domain.PingResult
 	statistics   PingStatistics
@@ -31,6 +42,7 @@ type Model struct {
 	loading      bool
 	progress     int
 	totalPings   int
+	progressBar  *progressbar.Bar
 	
 	// Real-time display components
 	liveStats    LiveStatistics
@@ -46,8 +58,47 @@ type Model struct {
 	// Continuous ping mode
 	continuousMode bool
 	cancelFunc     context.CancelFunc
+
+	// Pausing a continuous ping stops the probes but keeps the statistics and
+	// graph. draining is set until the run stopped by the pause has ended.
+	paused   bool
+	draining bool
+	pausedAt time.Time
+
+	// Multi-target ping: one row per host, with drillDown indexing the host
+	// shown in the full live view or -1 for the overview
+	targets      []*targetRow
+	selected     int
+	drillDown    int
+	multiResults <-chan taggedPingResult
+
+	// Toast confirms copying the results to the clipboard
+	toast clipboard.Toast
+	keys  KeyMap
+
+	// ctx is the parent of every run and options holds the probe settings
+	// the input form does not offer
+	ctx     context.Context
+	options domain.PingOptions
+
+	// live is set for a model opened by Tool.LiveModel: it starts on Init
+	// and leaves with domain.LiveExitMsg instead of asking for new targets
+	live bool
 }
 
+// pingModes lists the modes in the order the mode selector cycles through them
+var pingModes = []domain.PingMode{domain.PingModeNormal, domain.PingModeAdaptive, domain.PingModeFlood}
+
+const (
+	// modeInputIndex is the focus index of the mode selector
+	modeInputIndex = 3
+	// maxRetainedResults bounds the individual results kept for display;
+	// live statistics are tracked incrementally and cover every sample
+	maxRetainedResults = 500
+	// maxIntervalSeconds bounds the interval typed between pings
+	maxIntervalSeconds = 60
+)
+
 // ModelState represents the current state of the model
 type ModelState int
 
@@ -68,17 +119,78 @@ type LiveStatistics struct {
 	AvgRTT          time.Duration `json:"avg_rtt"`
 	LastRTT         time.Duration `json:"last_rtt"`
 	Jitter          time.Duration `json:"jitter"`
+	Reordered       int           `json:"reordered"`
+	MOS             float64       `json:"mos"`
+	P50RTT          time.Duration `json:"p50_rtt"`
+	P90RTT          time.Duration `json:"p90_rtt"`
+	P99RTT          time.Duration `json:"p99_rtt"`
 	ElapsedTime     time.Duration `json:"elapsed_time"`
+
+	rtt     stats.Running
+	arrival stats.Interarrival
+	first   time.Time
+	latest  time.Time
 }
 
-// LatencyGraph represents a simple ASCII graph of latency over time
+// LatencyGraph holds the latencies charted over time, with lost pings
+// recorded as LostPing. Values holds the MaxValues shown, taken from History
+// Offset values before the latest, so the wheel can scroll back through
+// earlier pings.
 type LatencyGraph struct {
-	Values    []time.Duration
-	MaxValues int
-	MaxRTT    time.Duration
-	MinRTT    time.Duration
-	Width     int
-	Height    int
+	Values     []time.Duration
+	MaxValues  int
+	History    []time.Duration
+	MaxHistory int
+	Offset     int
+	MaxRTT     time.Duration
+	MinRTT     time.Duration
+	Width      int
+	Height     int
+}
+
+// LostPing marks a lost ping in the values of a LatencyGraph
+const LostPing time.Duration = -1
+
+// averagePings is the number of pings in the moving average of the graph
+const averagePings = 10
+
+// Add records rtt. A graph scrolled back keeps showing the same values.
+func (g *LatencyGraph) Add(rtt time.Duration) {
+	g.History = append(g.History, rtt)
+	if len(g.History) > g.MaxHistory {
+		g.History = g.History[len(g.History)-g.MaxHistory:]
+	}
+	if g.Offset > 0 {
+		g.Offset++
+	}
+	g.refresh()
+}
+
+// AddLoss records a lost ping
+func (g *LatencyGraph) AddLoss() {
+	g.Add(LostPing)
+}
+
+// Scroll moves back by values, or forward when values is negative
+func (g *LatencyGraph) Scroll(values int) {
+	g.Offset += values
+	g.refresh()
+}
+
+// refresh clamps Offset to the history and takes Values from it
+func (g *LatencyGraph) refresh() {
+	if max := len(g.History) - g.MaxValues; g.Offset > max {
+		g.Offset = max
+	}
+	if g.Offset < 0 {
+		g.Offset = 0
+	}
+	end := len(g.History) - g.Offset
+	start := end - g.MaxValues
+	if start < 0 {
+		start = 0
+	}
+	g.Values = append(g.Values[:0], g.History[start:end]...)
 }
 
 // PacketLossIndicator shows packet loss visualization
@@ -91,11 +203,10 @@ type PacketLossIndicator struct {
 
 // NewModel creates a new ping model
 func NewModel(tool *Tool) *Model {
-	hostInput := textinput.New()
-	hostInput.Placeholder = "Enter hostname or IP address (e.g., google.com, 8.8.8.8)"
+	hostInput := targetinput.New("Enter hosts, comma-separated, or @file (e.g., google.com, 8.8.8.8)")
+	hostInput.Check = checkTargets
+	hostInput.CharLimit = 1024
 	hostInput.Focus()
-	hostInput.CharLimit = 253
-	hostInput.Width = 50
 
 	countInput := textinput.New()
 	countInput.Placeholder = "Number of pings (0 = continuous)"
@@ -104,8 +215,8 @@ func NewModel(tool *Tool) *Model {
 	countInput.SetValue("4")
 
 	intervalInput := textinput.New()
-	intervalInput.Placeholder = "Interval in seconds (default: 1)"
-	intervalInput.CharLimit = 3
+	intervalInput.Placeholder = "Interval in seconds, e.g. 0.5 (default: 1)"
+	intervalInput.CharLimit = 6
 	intervalInput.Width = 30
 	intervalInput.SetValue("1")
 
@@ -115,16 +226,27 @@ func NewModel(tool *Tool) *Model {
 		hostInput:        hostInput,
 		countInput:       countInput,
 		intervalInput:    intervalInput,
+		mode:             domain.PingModeNormal,
 		focusedInput:     0,
+		drillDown:        -1,
 		loading:          false,
+		progressBar:      progressbar.New(0),
+		keys:             DefaultKeyMap(),
 		updateInterval:   100 * time.Millisecond, // 10 FPS for smooth updates
+		ctx:              context.Background(),
+		options: domain.PingOptions{
+			Timeout:    5 * time.Second,
+			PacketSize: 64,
+			TTL:        64,
+		},
 		
 		// Initialize real-time components
 		latencyGraph: LatencyGraph{
-			Values:    make([]time.Duration, 0),
-			MaxValues: 50, // Keep last 50 values for graph
-			Width:     60,
-			Height:    8,
+			Values:     make([]time.Duration, 0),
+			MaxValues:  100, // Keep last 100 values for graph
+			MaxHistory: maxRetainedResults,
+			Width:      60,
+			Height:     8,
 		},
 		packetLoss: PacketLossIndicator{
 			RecentResults: make([]bool, 0),
@@ -135,9 +257,27 @@ func NewModel(tool *Tool) *Model {
 
 // Init initializes the model
 func (m *Model) Init() tea.Cmd {
+	if m.live {
+		return m.startPing()
+	}
 	return textinput.Blink
 }
 
+// Stop cancels the running ping
+func (m *Model) Stop() {
+	if m.cancelFunc != nil {
+		m.cancelFunc()
+		m.cancelFunc = nil
+	}
+}
+
+// exitLive stops a live model and hands control back to the screen that
+// opened it
+func (m *Model) exitLive() tea.Cmd {
+	m.Stop()
+	return func() tea.Msg { return domain.LiveExitMsg{} }
+}
+
 // Update handles messages and updates the model
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
@@ -145,44 +285,87 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
+		if m.handleTargetKey(msg) {
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, m.keys.Quit):
 			if m.state == StateRunning && m.cancelFunc != nil {
 				m.cancelFunc()
 				m.state = StateResult
 				return m, nil
 			}
+			if m.live {
+				return m, m.exitLive()
+			}
 			return m, tea.Quit
-		case "esc":
+		case key.Matches(msg, m.keys.Back):
+			if m.live {
+				return m, m.exitLive()
+			}
 			if m.state != StateInput {
 				m.resetToInput()
 				return m, nil
 			}
-		case "tab":
+		case key.Matches(msg, m.keys.NextField):
 			if m.state == StateInput {
 				m.nextInput()
 				return m, nil
 			}
-		case "shift+tab":
+		case key.Matches(msg, m.keys.Mode):
+			if m.state == StateInput && m.focusedInput == modeInputIndex {
+				m.cycleMode(msg.String() == "left")
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.PrevField):
 			if m.state == StateInput {
 				m.prevInput()
 				return m, nil
 			}
-		case "enter":
-			if m.state == StateInput && m.hostInput.Value() != "" {
+		case key.Matches(msg, m.keys.Start):
+			// Start stays disabled while a field is invalid
+			if m.state == StateInput && m.hostInput.Value() != "" && len(m.inputErrors()) == 0 {
 				return m, m.startPing()
 			}
-		case "s":
+		case key.Matches(msg, m.keys.Copy):
+			if m.state == StateRunning || m.state == StateResult {
+				return m, clipboard.CopyResult(m.copyableResults())
+			}
+		case key.Matches(msg, m.keys.StopContinuous):
 			if m.state == StateRunning && m.continuousMode {
 				// Stop continuous ping
 				if m.cancelFunc != nil {
 					m.cancelFunc()
 				}
+				m.paused = false
 				m.state = StateResult
 				return m, nil
 			}
+		case key.Matches(msg, m.keys.Pause):
+			if m.state == StateRunning && m.continuousMode {
+				return m, m.togglePause()
+			}
 		}
 
+	case tea.MouseMsg:
+		// The wheel scrolls the latency graph through earlier pings
+		if m.state == StateRunning && msg.Action == tea.MouseActionPress {
+			switch msg.Button {
+			case tea.MouseButtonWheelUp:
+				m.latencyGraph.Scroll(1)
+			case tea.MouseButtonWheelDown:
+				m.latencyGraph.Scroll(-1)
+			}
+		}
+		return m, nil
+
+	case clipboard.CopiedMsg:
+		return m, m.toast.Show(msg.Status())
+
+	case clipboard.ToastExpiredMsg:
+		m.toast.Update(msg)
+		return m, nil
+
 	case pingStartMsg:
 		m.state = StateRunning
 		m.loading = true
@@ -191,13 +374,13 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.startTime = time.Now()
 		m.lastUpdate = time.Now()
 		m.continuousMode = msg.continuous
+		m.clearPause()
+		m.progressBar.Start(m.totalPings)
+		m.targets = nil
+		m.drillDown = -1
 		
 		// Reset live components
-		m.liveStats = LiveStatistics{}
-		m.latencyGraph.Values = make([]time.Duration, 0)
-		m.packetLoss.RecentResults = make([]bool, 0)
-		m.packetLoss.LossCount = 0
-		m.packetLoss.TotalCount = 0
+		m.resetLiveComponents()
 		
 		// Start animation ticker for smooth updates
 		return m, tea.Batch(
@@ -207,15 +390,27 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case pingProgressMsg:
 		m.progress = msg.completed
-		m.results = append(m.results, msg.result)
+		m.appendResult(msg.result)
 		m.updateLiveStats(msg.result)
 		m.lastUpdate = time.Now()
-		return m, nil
+		return m, tea.Batch(m.progressBar.Set(msg.completed), waitForPingResult(msg.stream))
+
+	case progress.FrameMsg:
+		return m, m.progressBar.Update(msg)
 
 	case pingCompleteMsg:
+		if m.paused {
+			// The run stopped by the pause has ended
+			m.draining = false
+			return m, nil
+		}
 		m.state = StateResult
 		m.loading = false
 		m.statistics = msg.statistics
+		if m.liveStats.PacketsSent > 0 {
+			// The live statistics cover every reply, across pauses too
+			m.statistics = m.finalStatistics()
+		}
 		if m.cancelFunc != nil {
 			m.cancelFunc()
 			m.cancelFunc = nil
@@ -223,6 +418,11 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case pingErrorMsg:
+		if m.paused {
+			// The run stopped by the pause had no results yet
+			m.draining = false
+			return m, nil
+		}
 		m.state = StateError
 		m.loading = false
 		m.error = msg.error
@@ -234,8 +434,11 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tickMsg:
 		if m.state == StateRunning {
-			// Update elapsed time and continue ticking
-			m.liveStats.ElapsedTime = time.Since(m.startTime)
+			// Update elapsed time and continue ticking; the clock stops
+			// while paused
+			if !m.paused {
+				m.liveStats.ElapsedTime = time.Since(m.startTime)
+			}
 			return m, m.tickCmd()
 		}
 		return m, nil
@@ -243,6 +446,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case pingInitMsg:
 		// Start the actual ping operation
 		return m, m.executePing()
+
+	case multiPingStartMsg, multiPingResultMsg, multiPingCompleteMsg:
+		return m, m.handleMultiPingMsg(msg)
 	}
 
 	// Update input fields
@@ -275,9 +481,17 @@ func (m *Model) View() string {
 	case StateInput:
 		content.WriteString(m.renderInput())
 	case StateRunning:
-		content.WriteString(m.renderRunning())
+		if m.isMulti() && m.drillDown < 0 {
+			content.WriteString(m.renderTargetOverview())
+		} else {
+			content.WriteString(m.renderRunning())
+		}
 	case StateResult:
-		content.WriteString(m.renderResult())
+		if m.isMulti() && m.drillDown < 0 {
+			content.WriteString(m.renderTargetOverview())
+		} else {
+			content.WriteString(m.renderResult())
+		}
 	case StateError:
 		content.WriteString(m.renderError())
 	}
@@ -293,6 +507,7 @@ func (m *Model) View() string {
 func (m *Model) SetSize(width, height int) {
 	m.width = width
 	m.height = height
+	m.progressBar.SetWidth(min(width-8, 60))
 	m.hostInput.Width = width - 4
 	m.countInput.Width = width - 4
 	m.intervalInput.Width = width - 4
@@ -333,11 +548,11 @@ func (m *Model) renderHeader() string {
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
+		Foreground(colors.Info).
 		MarginBottom(1)
 
 	descStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241"))
+		Foreground(colors.Subtle)
 
 	return titleStyle.Render(title) + "\n" + descStyle.Render(description)
 }
@@ -345,19 +560,20 @@ func (m *Model) renderHeader() string {
 // renderInput renders the input form
 func (m *Model) renderInput() string {
 	var content strings.Builder
+	errors := m.inputErrors()
 
 	labelStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("205"))
+		Foreground(colors.Accent)
 
 	focusedStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
+		BorderForeground(colors.Primary).
 		Padding(0, 1)
 
 	unfocusedStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(colors.Border).
 		Padding(0, 1)
 
 	// Host input
@@ -368,6 +584,7 @@ func (m *Model) renderInput() string {
 	} else {
 		content.WriteString(unfocusedStyle.Render(m.hostInput.View()))
 	}
+	content.WriteString(m.hostInput.Feedback())
 	content.WriteString("\n\n")
 
 	// Count input
@@ -378,6 +595,7 @@ func (m *Model) renderInput() string {
 	} else {
 		content.WriteString(unfocusedStyle.Render(m.countInput.View()))
 	}
+	content.WriteString(m.renderInputError(errors[1]))
 	content.WriteString("\n\n")
 
 	// Interval input
@@ -388,17 +606,129 @@ func (m *Model) renderInput() string {
 	} else {
 		content.WriteString(unfocusedStyle.Render(m.intervalInput.View()))
 	}
+	content.WriteString(m.renderInputError(errors[2]))
+	content.WriteString("\n\n")
+
+	// Mode selector
+	content.WriteString(labelStyle.Render("Mode:"))
+	content.WriteString("\n")
+	if m.focusedInput == modeInputIndex {
+		content.WriteString(focusedStyle.Render(m.renderModeSelector()))
+	} else {
+		content.WriteString(unfocusedStyle.Render(m.renderModeSelector()))
+	}
 	content.WriteString("\n\n")
 
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(colors.Subtle).
 		Italic(true)
 
-	content.WriteString(helpStyle.Render("Use Tab to navigate • Enter 0 for continuous ping"))
+	help := "Use Tab to navigate • ↑/↓ for recent hosts • Enter 0 for continuous ping • ←/→ to change mode"
+	if len(errors) > 0 {
+		help = "Correct the fields marked in red to start • " + help
+	}
+	content.WriteString(helpStyle.Render(help))
 
 	return content.String()
 }
 
+// inputErrors checks the typed values as they are typed, by input index.
+// Empty count and interval fields use the defaults.
+func (m *Model) inputErrors() map[int]error {
+	errors := make(map[int]error)
+	if err := m.hostInput.ValidationError(); err != nil {
+		errors[0] = err
+	}
+	if count := strings.TrimSpace(m.countInput.Value()); count != "" {
+		if err := validate.Int(0, 9999)(count); err != nil {
+			errors[1] = err
+		}
+	}
+	if interval := strings.TrimSpace(m.intervalInput.Value()); interval != "" {
+		if err := validate.Seconds(maxIntervalSeconds)(interval); err != nil {
+			errors[2] = err
+		}
+	}
+	return errors
+}
+
+// checkTargets accepts the hosts, comma-separated, or the @file the host
+// input takes
+func checkTargets(value string) error {
+	targets, err := ParseTargets(value)
+	if err != nil {
+		return err
+	}
+	for _, target := range targets {
+		if err := validate.Host(target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderInputError renders the hint under an invalid input, or nothing
+func (m *Model) renderInputError(err error) string {
+	if err == nil {
+		return ""
+	}
+	return "\n" + lipgloss.NewStyle().Foreground(colors.Error).Render("✗ "+err.Error())
+}
+
+// renderModeSelector renders the ping modes with the selected one highlighted
+func (m *Model) renderModeSelector() string {
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colors.Accent)
+
+	options := make([]string, len(pingModes))
+	for i, mode := range pingModes {
+		if mode == m.mode {
+			options[i] = selectedStyle.Render("[" + string(mode) + "]")
+		} else {
+			options[i] = " " + string(mode) + " "
+		}
+	}
+
+	var description string
+	switch m.mode {
+	case domain.PingModeAdaptive:
+		description = "next probe is sent as soon as a reply arrives"
+	case domain.PingModeFlood:
+		description = "probes are sent without waiting, capped by max_flood_count"
+	default:
+		description = "one probe per interval"
+	}
+
+	return strings.Join(options, " ") + "  " + description
+}
+
+// cycleMode selects the next ping mode, or the previous one when backward is set
+func (m *Model) cycleMode(backward bool) {
+	current := 0
+	for i, mode := range pingModes {
+		if mode == m.mode {
+			current = i
+			break
+		}
+	}
+
+	step := 1
+	if backward {
+		step = len(pingModes) - 1
+	}
+	m.mode = pingModes[(current+step)%len(pingModes)]
+}
+
+// appendResult records a result for display, keeping at most
+// maxRetainedResults so long or flood runs do not grow without bound
+func (m *Model) appendResult(result domain.PingResult) {
+	m.results = append(m.results, result)
+	if len(m.results) > 2*maxRetainedResults {
+		m.results = append(m.results[:0], m.results[len(m.results)-maxRetainedResults:]...)
+	}
+}
+
 // renderRunning renders the running state with real-time results
 func (m *Model) renderRunning() string {
 	var sections []string
@@ -417,6 +747,11 @@ func (m *Model) renderRunning() string {
 		sections = append(sections, graphSection)
 	}
 
+	// RTT distribution
+	if m.liveStats.PacketsReceived > 1 {
+		sections = append(sections, m.renderRTTHistogram())
+	}
+
 	// Packet loss indicator
 	if len(m.packetLoss.RecentResults) > 0 {
 		lossSection := m.renderPacketLossIndicator()
@@ -439,41 +774,55 @@ func (m *Model) renderRunning() string {
 // renderRunningHeader renders the header with progress information
 func (m *Model) renderRunningHeader() string {
 	progressStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("214")).
+		Foreground(colors.Warning).
 		Bold(true)
 
+	// A run of a known count shows a bar with the time left; the elapsed
+	// time and the bar's estimate refresh on every tick
+	determinate := !m.continuousMode && m.totalPings > 0
 	var headerText string
-	if m.continuousMode {
+	switch {
+	case m.paused:
+		headerText = fmt.Sprintf("⏸ Paused pinging %s (%d sent)",
+			m.displayHost(), m.liveStats.PacketsSent)
+	case m.continuousMode:
 		headerText = fmt.Sprintf("🔍 Pinging %s continuously... (%d sent)",
-			m.hostInput.Value(), m.liveStats.PacketsSent)
-	} else {
-		headerText = fmt.Sprintf("🔍 Pinging %s... (%d/%d)",
-			m.hostInput.Value(), m.progress, m.totalPings)
+			m.displayHost(), m.liveStats.PacketsSent)
+	case determinate:
+		headerText = fmt.Sprintf("🔍 Pinging %s...", m.displayHost())
+	default:
+		headerText = fmt.Sprintf("🔍 Pinging %s... (%d sent)",
+			m.displayHost(), m.liveStats.PacketsSent)
+	}
+	if m.mode != "" && m.mode != domain.PingModeNormal {
+		headerText += fmt.Sprintf(" [%s]", m.mode)
 	}
 
 	// Add elapsed time
 	elapsedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("243")).
+		Foreground(colors.Muted).
 		Italic(true)
 
 	elapsed := fmt.Sprintf("Elapsed: %v", m.liveStats.ElapsedTime.Truncate(time.Second))
 
-	return lipgloss.JoinVertical(lipgloss.Left,
-		progressStyle.Render(headerText),
-		elapsedStyle.Render(elapsed),
-	)
+	lines := []string{progressStyle.Render(headerText)}
+	if determinate {
+		lines = append(lines, m.progressBar.View())
+	}
+	lines = append(lines, elapsedStyle.Render(elapsed))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
 
 // renderLiveStatistics renders real-time statistics
 func (m *Model) renderLiveStatistics() string {
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
+		Foreground(colors.Info).
 		MarginBottom(1)
 
 	statsStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
+		BorderForeground(colors.Primary).
 		Padding(1).
 		Width(m.width - 4)
 
@@ -493,7 +842,7 @@ func (m *Model) renderLiveStatistics() string {
 		m.liveStats.PacketsReceived,
 		m.liveStats.PacketLoss)
 
-	lossStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(lossColor))
+	lossStyle := lipgloss.NewStyle().Foreground(colors.Adaptive(lossColor))
 	statsLines = append(statsLines, lossStyle.Render(packetsLine))
 
 	// RTT statistics (only if we have successful pings)
@@ -513,12 +862,20 @@ func (m *Model) renderLiveStatistics() string {
 			rttColor = "196" // Red
 		}
 
-		rttStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(rttColor))
+		rttStyle := lipgloss.NewStyle().Foreground(colors.Adaptive(rttColor))
 		statsLines = append(statsLines, rttStyle.Render(rttLine))
-
-		// Jitter
-		if m.liveStats.Jitter > 0 {
-			jitterLine := fmt.Sprintf("Jitter: %v", m.liveStats.Jitter.Truncate(time.Microsecond))
+		statsLines = append(statsLines, fmt.Sprintf("Percentiles: p50=%v, p90=%v, p99=%v",
+			m.liveStats.P50RTT.Truncate(time.Microsecond),
+			m.liveStats.P90RTT.Truncate(time.Microsecond),
+			m.liveStats.P99RTT.Truncate(time.Microsecond)))
+
+		// Jitter, reordering and estimated call quality
+		if m.liveStats.PacketsReceived > 1 {
+			jitterLine := fmt.Sprintf("Jitter: %v, Reordered: %d, MOS: %.2f (%s)",
+				m.liveStats.Jitter.Truncate(time.Microsecond),
+				m.liveStats.Reordered,
+				m.liveStats.MOS,
+				stats.VoIPGrade(m.liveStats.MOS))
 			statsLines = append(statsLines, jitterLine)
 		}
 	}
@@ -535,129 +892,117 @@ func (m *Model) renderLiveStatistics() string {
 func (m *Model) renderLatencyGraph() string {
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
+		Foreground(colors.Info).
 		MarginBottom(1)
 
 	graphStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
+		BorderForeground(colors.Primary).
 		Padding(1).
 		Width(m.width - 4)
 
 	graph := m.generateLatencyGraph()
 
+	title := fmt.Sprintf("📈 Latency Graph (last %d pings)", m.latencyGraph.MaxValues)
+	if m.latencyGraph.Offset > 0 {
+		title = fmt.Sprintf("📈 Latency Graph (%d pings, %d before the latest)", len(m.latencyGraph.Values), m.latencyGraph.Offset)
+	}
+
 	return lipgloss.JoinVertical(lipgloss.Left,
-		titleStyle.Render("📈 Latency Graph (last 50 pings)"),
+		titleStyle.Render(title),
 		graphStyle.Render(graph),
 	)
 }
 
-// generateLatencyGraph creates an ASCII graph of latency values
+// generateLatencyGraph charts the latencies with their moving average and
+// marks the lost pings on the time axis
 func (m *Model) generateLatencyGraph() string {
 	if len(m.latencyGraph.Values) == 0 {
 		return "No data yet..."
 	}
 
-	// Calculate graph dimensions
 	graphWidth := m.latencyGraph.Width
 	if graphWidth > m.width-8 {
 		graphWidth = m.width - 8
 	}
-	graphHeight := m.latencyGraph.Height
 
-	// Find min/max for scaling
-	minRTT := m.latencyGraph.Values[0]
-	maxRTT := m.latencyGraph.Values[0]
-	for _, rtt := range m.latencyGraph.Values {
-		if rtt < minRTT {
-			minRTT = rtt
-		}
-		if rtt > maxRTT {
-			maxRTT = rtt
-		}
-	}
+	graph := chart.New(graphWidth, m.latencyGraph.Height)
+	graph.Label = chart.DurationLabel
+	graph.AverageWindow = averagePings
+	rendered := graph.Render(chart.Durations(m.latencyGraph.Values))
 
-	// Add some padding to the range
-	rttRange := maxRTT - minRTT
-	if rttRange == 0 {
-		rttRange = time.Millisecond
-	}
-	minRTT -= rttRange / 10
-	maxRTT += rttRange / 10
+	legendStyle := lipgloss.NewStyle().
+		Foreground(colors.Muted).
+		Italic(true)
+	legend := fmt.Sprintf("%s RTT  %s average of %d  %s lost",
+		graph.LineStyle.Render("⣿"), graph.AverageStyle.Render("⣿"), averagePings, graph.LossStyle.Render("×"))
 
-	// Create graph grid
-	grid := make([][]rune, graphHeight)
-	for i := range grid {
-		grid[i] = make([]rune, graphWidth)
-		for j := range grid[i] {
-			grid[i][j] = ' '
-		}
-	}
+	return lipgloss.JoinVertical(lipgloss.Left, rendered, legendStyle.Render(legend))
+}
 
-	// Plot values
-	valueCount := len(m.latencyGraph.Values)
-	for i, rtt := range m.latencyGraph.Values {
-		// Calculate x position (spread across width)
-		x := (i * graphWidth) / valueCount
-		if x >= graphWidth {
-			x = graphWidth - 1
-		}
+// renderRTTHistogram renders the distribution of the round-trip times
+// accumulated so far as horizontal bars
+func (m *Model) renderRTTHistogram() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colors.Info).
+		MarginBottom(1)
 
-		// Calculate y position (inverted for display)
-		normalizedRTT := float64(rtt-minRTT) / float64(maxRTT-minRTT)
-		y := graphHeight - 1 - int(normalizedRTT*float64(graphHeight-1))
-		if y < 0 {
-			y = 0
-		}
-		if y >= graphHeight {
-			y = graphHeight - 1
-		}
+	histogramStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colors.Primary).
+		Padding(0, 1).
+		Width(m.width - 4)
 
-		// Choose character based on latency level
-		var char rune
-		if rtt < 50*time.Millisecond {
-			char = '▁' // Low latency
-		} else if rtt < 100*time.Millisecond {
-			char = '▃' // Medium latency
-		} else if rtt < 200*time.Millisecond {
-			char = '▅' // High latency
-		} else {
-			char = '▇' // Very high latency
-		}
+	barStyle := lipgloss.NewStyle().Foreground(colors.Success)
 
-		grid[y][x] = char
+	bins := m.liveStats.rtt.Histogram().Bins(histogramBins)
+	peak := 0
+	for _, bin := range bins {
+		peak = max(peak, bin.Count)
 	}
 
-	// Convert grid to string
 	var lines []string
-	for _, row := range grid {
-		lines = append(lines, string(row))
+	for _, bin := range bins {
+		label := fmt.Sprintf("%9v - %-9v", bin.Low.Truncate(10*time.Microsecond), bin.High.Truncate(10*time.Microsecond))
+		count := fmt.Sprintf("%6d", bin.Count)
+		barWidth := max(m.width-10-lipgloss.Width(label)-lipgloss.Width(count)-2, 10)
+		lines = append(lines, label+" "+barStyle.Render(histogramBar(bin.Count, peak, barWidth))+" "+count)
 	}
 
-	// Add scale information
-	scaleInfo := fmt.Sprintf("Scale: %v - %v",
-		minRTT.Truncate(time.Microsecond),
-		maxRTT.Truncate(time.Microsecond))
-
-	scaleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("243")).
-		Italic(true)
+	return lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render("📶 RTT Distribution"),
+		histogramStyle.Render(strings.Join(lines, "\n")),
+	)
+}
 
-	lines = append(lines, scaleStyle.Render(scaleInfo))
+// histogramBins is the number of bars of the RTT distribution
+const histogramBins = 8
 
-	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+// histogramBar draws count as a bar of up to width cells, in eighths of a
+// cell, scaled so that peak fills the width
+func histogramBar(count, peak, width int) string {
+	if peak == 0 {
+		return strings.Repeat(" ", width)
+	}
+	eighths := count * width * 8 / peak
+	bar := strings.Repeat("█", eighths/8)
+	if partial := eighths % 8; partial > 0 {
+		bar += string([]rune("▏▎▍▌▋▊▉")[partial-1])
+	}
+	return bar + strings.Repeat(" ", width-lipgloss.Width(bar))
 }
 
 // renderPacketLossIndicator renders packet loss visualization
 func (m *Model) renderPacketLossIndicator() string {
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
+		Foreground(colors.Info).
 		MarginBottom(1)
 
 	indicatorStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
+		BorderForeground(colors.Primary).
 		Padding(1).
 		Width(m.width - 4)
 
@@ -668,10 +1013,10 @@ func (m *Model) renderPacketLossIndicator() string {
 	var resultChars []string
 	for _, success := range m.packetLoss.RecentResults {
 		if success {
-			successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("46"))
+			successStyle := lipgloss.NewStyle().Foreground(colors.Online)
 			resultChars = append(resultChars, successStyle.Render("✓"))
 		} else {
-			lossStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+			lossStyle := lipgloss.NewStyle().Foreground(colors.Error)
 			resultChars = append(resultChars, lossStyle.Render("✗"))
 		}
 	}
@@ -703,12 +1048,12 @@ func (m *Model) renderPacketLossIndicator() string {
 func (m *Model) renderRecentResults() string {
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
+		Foreground(colors.Info).
 		MarginBottom(1)
 
 	resultsStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
+		BorderForeground(colors.Primary).
 		Padding(1).
 		Width(m.width - 4)
 
@@ -723,13 +1068,13 @@ func (m *Model) renderRecentResults() string {
 	for i := startIdx; i < len(m.results); i++ {
 		result := m.results[i]
 		if result.Error != nil {
-			errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+			errorStyle := lipgloss.NewStyle().Foreground(colors.Error)
 			line := fmt.Sprintf("Ping %d: %v", result.Sequence, result.Error)
 			resultLines = append(resultLines, errorStyle.Render(line))
 		} else {
-			successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("46"))
-			line := fmt.Sprintf("Ping %d: %s time=%v ttl=%d",
-				result.Sequence, result.Host.IPAddress, result.RTT.Truncate(time.Microsecond), result.TTL)
+			successStyle := lipgloss.NewStyle().Foreground(colors.Online)
+			line := fmt.Sprintf("Ping %d: %s time=%v %s",
+				result.Sequence, result.Host.IPAddress, result.RTT.Truncate(time.Microsecond), formatTTL(result.TTL))
 			resultLines = append(resultLines, successStyle.Render(line))
 		}
 	}
@@ -746,20 +1091,17 @@ func (m *Model) renderRecentResults() string {
 	)
 }
 
-// renderRunningInstructions renders instructions for the running state
+// renderRunningInstructions renders the keys controlling a running ping
 func (m *Model) renderRunningInstructions() string {
 	instructionStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("243")).
 		Italic(true).
 		MarginTop(1)
 
-	var instructions []string
-	if m.continuousMode {
-		instructions = append(instructions, "s: stop continuous ping")
+	bindings := m.runningKeys()
+	if len(bindings) == 0 {
+		return ""
 	}
-	instructions = append(instructions, "q: quit", "ctrl+c: stop")
-
-	return instructionStyle.Render(strings.Join(instructions, " • "))
+	return instructionStyle.Render(keyhint.Help(m.width).ShortHelpView(bindings))
 }
 
 // renderResult renders the final ping results and statistics
@@ -769,7 +1111,7 @@ func (m *Model) renderResult() string {
 	// Results summary
 	summaryStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
+		Foreground(colors.Info).
 		MarginBottom(1)
 
 	content.WriteString(summaryStyle.Render("Ping Results Summary"))
@@ -777,7 +1119,7 @@ func (m *Model) renderResult() string {
 
 	// Individual results (last few)
 	resultStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252"))
+		Foreground(colors.Text)
 
 	maxDisplay := 5
 	startIdx := 0
@@ -790,13 +1132,13 @@ func (m *Model) renderResult() string {
 	for i := startIdx; i < len(m.results); i++ {
 		result := m.results[i]
 		if result.Error != nil {
-			errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+			errorStyle := lipgloss.NewStyle().Foreground(colors.Error)
 			content.WriteString(errorStyle.Render(fmt.Sprintf("❌ Ping %d: %v",
 				result.Sequence, result.Error)))
 		} else {
-			successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("46"))
-			content.WriteString(successStyle.Render(fmt.Sprintf("✅ Ping %d: %s time=%v ttl=%d",
-				result.Sequence, result.Host.IPAddress, result.RTT, result.TTL)))
+			successStyle := lipgloss.NewStyle().Foreground(colors.Online)
+			content.WriteString(successStyle.Render(fmt.Sprintf("✅ Ping %d: %s time=%v %s",
+				result.Sequence, result.Host.IPAddress, result.RTT, formatTTL(result.TTL))))
 		}
 		content.WriteString("\n")
 	}
@@ -805,43 +1147,68 @@ func (m *Model) renderResult() string {
 	content.WriteString("\n")
 	statsStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("214")).
+		Foreground(colors.Warning).
 		Border(lipgloss.RoundedBorder()).
 		Padding(1).
 		MarginTop(1)
 
 	statsText := FormatPingStatistics(m.statistics)
+	if hint, ok := domain.PingTTLHint(m.results); ok {
+		statsText += "\nOS hint: " + hint.String()
+	}
 	content.WriteString(statsStyle.Render(statsText))
 
+	if m.liveStats.PacketsReceived > 1 {
+		content.WriteString("\n")
+		content.WriteString(m.renderRTTHistogram())
+	}
+
 	return content.String()
 }
 
+// formatTTL formats a reply TTL, which is 0 when the probe could not read it
+func formatTTL(ttl int) string {
+	if ttl <= 0 {
+		return "ttl=?"
+	}
+	return fmt.Sprintf("ttl=%d", ttl)
+}
+
 // renderError renders the error state
 func (m *Model) renderError() string {
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("196")).
+		Foreground(colors.Error).
 		Bold(true)
 
 	return errorStyle.Render(fmt.Sprintf("❌ Error: %s", m.error.Error()))
 }
 
-// renderFooter renders the footer with help text
+// renderFooter renders the footer with the keys of the current state
 func (m *Model) renderFooter() string {
-	var help []string
-
-	switch m.state {
-	case StateInput:
-		help = []string{"tab: next field", "enter: start ping", "q: quit"}
-	case StateResult, StateError:
-		help = []string{"esc: new ping", "q: quit"}
-	case StateRunning:
-		help = []string{"q: quit"}
+	footer := keyhint.Help(m.width).View(m)
+	if message := m.toast.Message(); message != "" {
+		toastStyle := lipgloss.NewStyle().
+			Foreground(colors.Success).
+			Bold(true)
+		footer = toastStyle.Render("✓ "+message) + "\n" + footer
 	}
+	return footer
+}
 
-	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241"))
-
-	return helpStyle.Render(strings.Join(help, " • "))
+// copyableResults returns the replies currently shown: those of the drilled
+// into host, of every host in the multi-target overview, or of the single host
+func (m *Model) copyableResults() []domain.PingResult {
+	if !m.isMulti() {
+		return m.results
+	}
+	if m.drillDown >= 0 && m.drillDown < len(m.targets) {
+		return m.targets[m.drillDown].results
+	}
+	var results []domain.PingResult
+	for _, target := range m.targets {
+		results = append(results, target.results...)
+	}
+	return results
 }
 
 // nextInput moves focus to the next input field
@@ -851,7 +1218,7 @@ func (m *Model) nextInput() {
 	m.intervalInput.Blur()
 
 	m.focusedInput++
-	if m.focusedInput > 2 {
+	if m.focusedInput > modeInputIndex {
 		m.focusedInput = 0
 	}
 
@@ -866,7 +1233,7 @@ func (m *Model) prevInput() {
 
 	m.focusedInput--
 	if m.focusedInput < 0 {
-		m.focusedInput = 2
+		m.focusedInput = modeInputIndex
 	}
 
 	m.focusCurrentInput()
@@ -896,18 +1263,59 @@ func (m *Model) resetToInput() {
 	m.hostInput.SetValue("")
 	m.countInput.SetValue("4")
 	m.intervalInput.SetValue("1")
+	m.mode = domain.PingModeNormal
 	m.focusedInput = 0
 	m.hostInput.Focus()
 	m.countInput.Blur()
 	m.intervalInput.Blur()
 	m.error = nil
-	m.results = []domain.PingResult{}
 	m.progress = 0
 	m.continuousMode = false
+	m.clearPause()
+	m.targets = nil
+	m.selected = 0
+	m.drillDown = -1
 	
 	// Reset live components
+	m.resetLiveComponents()
+}
+
+// togglePause pauses a continuous ping by stopping its run, or resumes it
+// with a new run once the stopped one has handed over its results
+func (m *Model) togglePause() tea.Cmd {
+	if !m.paused {
+		m.paused = true
+		m.pausedAt = time.Now()
+		m.draining = m.cancelFunc != nil
+		if m.cancelFunc != nil {
+			m.cancelFunc()
+			m.cancelFunc = nil
+		}
+		return nil
+	}
+	if m.draining {
+		return nil
+	}
+
+	// Leave the paused time out of the elapsed time
+	m.paused = false
+	m.startTime = m.startTime.Add(time.Since(m.pausedAt))
+	return m.executePing()
+}
+
+// clearPause forgets the pause state
+func (m *Model) clearPause() {
+	m.paused = false
+	m.draining = false
+}
+
+// resetLiveComponents clears the live statistics, graph and loss indicator
+func (m *Model) resetLiveComponents() {
+	m.results = []domain.PingResult{}
 	m.liveStats = LiveStatistics{}
 	m.latencyGraph.Values = make([]time.Duration, 0)
+	m.latencyGraph.History = nil
+	m.latencyGraph.Offset = 0
 	m.packetLoss.RecentResults = make([]bool, 0)
 	m.packetLoss.LossCount = 0
 	m.packetLoss.TotalCount = 0
@@ -915,6 +1323,8 @@ func (m *Model) resetToInput() {
 
 // startPing starts the ping operation
 func (m *Model) startPing() tea.Cmd {
+	m.hostInput.Split()
+	m.hostInput.Remember()
 	host := strings.TrimSpace(m.hostInput.Value())
 	countStr := strings.TrimSpace(m.countInput.Value())
 	intervalStr := strings.TrimSpace(m.intervalInput.Value())
@@ -933,8 +1343,21 @@ func (m *Model) startPing() tea.Cmd {
 		}
 	}
 
+	// Several hosts are pinged concurrently with a per-host overview
+	if targets, err := ParseTargets(host); err != nil {
+		return func() tea.Msg { return pingErrorMsg{error: err} }
+	} else if len(targets) > 1 {
+		m.totalPings = count
+		opts := m.runOptions(count, interval)
+		return func() tea.Msg { return multiPingStartMsg{hosts: targets, opts: opts} }
+	} else if targets[0] != host {
+		// A target file naming one host is pinged like a typed host
+		m.hostInput.SetValue(targets[0])
+	}
+
 	m.totalPings = count
-	continuous := count == 0
+	// A flood ping without a count runs to the configured cap rather than forever
+	continuous := count == 0 && m.mode != domain.PingModeFlood
 
 	return func() tea.Msg {
 		return pingStartMsg{
@@ -946,110 +1369,102 @@ func (m *Model) startPing() tea.Cmd {
 	}
 }
 
-// executePing executes the actual ping operation with real-time updates
+// runOptions returns the options of a run of count pings at interval in
+// the selected mode
+func (m *Model) runOptions(count int, interval time.Duration) domain.PingOptions {
+	opts := m.options
+	opts.Count = count
+	opts.Interval = interval
+	opts.Mode = m.mode
+	return opts
+}
+
+// executePing starts the ping and streams its results to Update one message
+// at a time, each pingProgressMsg carrying the stream to wait on next
 func (m *Model) executePing() tea.Cmd {
-	return func() tea.Msg {
-		host := strings.TrimSpace(m.hostInput.Value())
-		countStr := strings.TrimSpace(m.countInput.Value())
-		intervalStr := strings.TrimSpace(m.intervalInput.Value())
-
-		count := 4 // default
-		if countStr != "" {
-			if c, err := strconv.Atoi(countStr); err == nil && c >= 0 {
-				count = c
-			}
+	host := strings.TrimSpace(m.hostInput.Value())
+	countStr := strings.TrimSpace(m.countInput.Value())
+	intervalStr := strings.TrimSpace(m.intervalInput.Value())
+
+	count := 4 // default
+	if countStr != "" {
+		if c, err := strconv.Atoi(countStr); err == nil && c >= 0 {
+			count = c
 		}
+	}
 
-		interval := time.Second // default
-		if intervalStr != "" {
-			if i, err := strconv.ParseFloat(intervalStr, 64); err == nil && i > 0 {
-				interval = time.Duration(i * float64(time.Second))
-			}
+	interval := time.Second // default
+	if intervalStr != "" {
+		if i, err := strconv.ParseFloat(intervalStr, 64); err == nil && i > 0 {
+			interval = time.Duration(i * float64(time.Second))
 		}
+	}
 
-		// Create context with cancellation
-		ctx, cancel := context.WithCancel(context.Background())
-		m.cancelFunc = cancel
+	// Create context with cancellation
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.cancelFunc = cancel
 
-		// Create parameters
-		opts := domain.PingOptions{
-			Count:      count,
-			Interval:   interval,
-			Timeout:    5 * time.Second,
-			PacketSize: 64,
-			TTL:        64,
-			IPv6:       false,
-		}
+	// Create parameters
+	opts := m.runOptions(count, interval)
 
-		// Start ping operation
-		resultChan, err := m.tool.client.Ping(ctx, host, opts)
+	// A continuous run has no count to stop at
+	total := m.totalPings
+	if m.continuousMode {
+		total = 0
+	}
+
+	client := m.tool.client
+	return func() tea.Msg {
+		resultChan, err := client.Ping(ctx, host, opts)
 		if err != nil {
 			return pingErrorMsg{error: err}
 		}
-
-		// Return a command that will listen for ping results
-		return tea.Batch(m.listenForPingResults(resultChan, ctx))
+		stream := &pingStream{ctx: ctx, results: resultChan, total: total}
+		return stream.next()
 	}
 }
 
-// listenForPingResults creates a command that listens for ping results and sends progress updates
-func (m *Model) listenForPingResults(resultChan <-chan domain.PingResult, ctx context.Context) tea.Cmd {
-	return func() tea.Msg {
-		var results []domain.PingResult
-		completed := 0
-
-		for {
-			select {
-			case result, ok := <-resultChan:
-				if !ok {
-					// Channel closed, ping complete
-					tool := &Tool{}
-					stats := tool.calculateStatistics(results)
-					return pingCompleteMsg{
-						results:    results,
-						statistics: stats,
-					}
-				}
-
-				results = append(results, result)
-				completed++
-
-				// Send progress update immediately
-				go func(r domain.PingResult, c int) {
-					// This is a simplified approach - in a real implementation,
-					// we'd need a proper way to send messages back to the UI
-					// For now, we'll just return the progress message
-				}(result, completed)
+// pingStream is a running ping whose results are delivered one at a time.
+// Only one command waits on it at once, so received needs no lock. The
+// results are not kept: the model accumulates its statistics as they arrive.
+type pingStream struct {
+	ctx      context.Context
+	results  <-chan domain.PingResult
+	total    int
+	received int
+}
 
-				// For continuous mode, keep listening
-				if m.continuousMode {
-					continue
-				}
+// next waits for the next result, or for the end of the run
+func (s *pingStream) next() tea.Msg {
+	// For counted mode, check if we're done. Without a count (continuous or
+	// flood mode) the channel closes when the run is cancelled or at the cap.
+	if s.total > 0 && s.received >= s.total {
+		return pingCompleteMsg{}
+	}
 
-				// For counted mode, check if we're done
-				if completed >= m.totalPings {
-					tool := &Tool{}
-					stats := tool.calculateStatistics(results)
-					return pingCompleteMsg{
-						results:    results,
-						statistics: stats,
-					}
-				}
+	select {
+	case result, ok := <-s.results:
+		if !ok {
+			return pingCompleteMsg{}
+		}
+		s.received++
+		return pingProgressMsg{completed: s.received, result: result, stream: s}
 
-			case <-ctx.Done():
-				// Ping was cancelled
-				if len(results) > 0 {
-					tool := &Tool{}
-					stats := tool.calculateStatistics(results)
-					return pingCompleteMsg{
-						results:    results,
-						statistics: stats,
-					}
-				}
-				return pingErrorMsg{error: fmt.Errorf("ping cancelled")}
-			}
+	case <-s.ctx.Done():
+		// Ping was cancelled
+		if s.received > 0 {
+			return pingCompleteMsg{}
 		}
+		return pingErrorMsg{error: fmt.Errorf("ping cancelled")}
+	}
+}
+
+// waitForPingResult returns a command delivering the next message of stream
+func waitForPingResult(stream *pingStream) tea.Cmd {
+	if stream == nil {
+		return nil
 	}
+	return stream.next
 }
 
 // Messages for async operations
@@ -1065,10 +1480,10 @@ type pingInitMsg struct{}
 type pingProgressMsg struct {
 	completed int
 	result    domain.PingResult
+	stream    *pingStream
 }
 
 type pingCompleteMsg struct {
-	results    []domain.PingResult
 	statistics PingStatistics
 }
 
@@ -1087,50 +1502,30 @@ func (m *Model) tickCmd() tea.Cmd {
 
 // updateLiveStats updates the live statistics with a new ping result
 func (m *Model) updateLiveStats(result domain.PingResult) {
-	m.liveStats.PacketsSent++
-	
-	if result.Error == nil {
-		m.liveStats.PacketsReceived++
-		
-		// Update RTT statistics
-		if m.liveStats.PacketsReceived == 1 {
-			m.liveStats.MinRTT = result.RTT
-			m.liveStats.MaxRTT = result.RTT
-			m.liveStats.AvgRTT = result.RTT
-		} else {
-			if result.RTT < m.liveStats.MinRTT {
-				m.liveStats.MinRTT = result.RTT
-			}
-			if result.RTT > m.liveStats.MaxRTT {
-				m.liveStats.MaxRTT = result.RTT
-			}
-			
-			// Update average (simple moving average)
-			totalRTT := m.liveStats.AvgRTT * time.Duration(m.liveStats.PacketsReceived-1)
-			m.liveStats.AvgRTT = (totalRTT + result.RTT) / time.Duration(m.liveStats.PacketsReceived)
+	if !result.Timestamp.IsZero() {
+		if m.liveStats.first.IsZero() {
+			m.liveStats.first = result.Timestamp
 		}
-		
-		// Calculate jitter (difference from previous RTT)
-		if m.liveStats.LastRTT > 0 {
-			diff := result.RTT - m.liveStats.LastRTT
-			if diff < 0 {
-				diff = -diff
-			}
-			m.liveStats.Jitter = diff
-		}
-		
-		m.liveStats.LastRTT = result.RTT
-		
+		m.liveStats.latest = result.Timestamp
+	}
+	if result.Error == nil {
+		m.liveStats.rtt.Add(result.RTT)
+		m.liveStats.arrival.Add(result.Sequence, result.RTT)
+	} else {
+		m.liveStats.rtt.AddLoss()
+	}
+
+	m.refreshLiveStats()
+
+	if result.Error == nil {
 		// Update latency graph
-		m.latencyGraph.Values = append(m.latencyGraph.Values, result.RTT)
-		if len(m.latencyGraph.Values) > m.latencyGraph.MaxValues {
-			m.latencyGraph.Values = m.latencyGraph.Values[1:]
-		}
+		m.latencyGraph.Add(result.RTT)
 		
 		// Update packet loss indicator
 		m.packetLoss.RecentResults = append(m.packetLoss.RecentResults, true)
 	} else {
 		// Packet loss
+		m.latencyGraph.AddLoss()
 		m.packetLoss.LossCount++
 		m.packetLoss.RecentResults = append(m.packetLoss.RecentResults, false)
 	}
@@ -1141,9 +1536,31 @@ func (m *Model) updateLiveStats(result domain.PingResult) {
 	}
 	
 	m.packetLoss.TotalCount++
-	
-	// Calculate packet loss percentage
-	if m.liveStats.PacketsSent > 0 {
-		m.liveStats.PacketLoss = float64(m.liveStats.PacketsSent-m.liveStats.PacketsReceived) / float64(m.liveStats.PacketsSent) * 100
+}
+
+// refreshLiveStats derives the displayed live statistics from the running totals
+func (m *Model) refreshLiveStats() {
+	summary := m.liveStats.rtt.Summary()
+	m.liveStats.PacketsSent = m.liveStats.rtt.Sent()
+	m.liveStats.PacketsReceived = m.liveStats.rtt.Received()
+	m.liveStats.PacketLoss = m.liveStats.rtt.Loss()
+	m.liveStats.MinRTT = summary.Min
+	m.liveStats.MaxRTT = summary.Max
+	m.liveStats.AvgRTT = summary.Mean
+	m.liveStats.LastRTT = m.liveStats.rtt.Last()
+	m.liveStats.Jitter = m.liveStats.arrival.Jitter()
+	m.liveStats.Reordered = m.liveStats.arrival.Reordered()
+	m.liveStats.P50RTT = m.liveStats.rtt.Percentile(50)
+	m.liveStats.P90RTT = m.liveStats.rtt.Percentile(90)
+	m.liveStats.P99RTT = m.liveStats.rtt.Percentile(99)
+	if m.liveStats.PacketsReceived > 0 {
+		m.liveStats.MOS = stats.MOS(summary.Mean, m.liveStats.Jitter, m.liveStats.PacketLoss)
 	}
-}
\ No newline at end of file
+}
+
+// finalStatistics returns the statistics of the run from the live ones
+func (m *Model) finalStatistics() PingStatistics {
+	statistics := streamedStatistics(&m.liveStats.rtt, &m.liveStats.arrival)
+	statistics.TotalTime = m.liveStats.latest.Sub(m.liveStats.first)
+	return statistics
+}
@@ -4,10 +4,13 @@ package ping
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/stats"
 )
 
 // Tool implements the DiagnosticTool interface for ping operations
@@ -51,21 +54,14 @@ func (t *Tool) Execute(ctx context.Context, params domain.Parameters) (domain.Re
 	}
 
 	// Extract parameters
-	host := params.Get("host").(string)
-	count := params.Get("count").(int)
-	interval := params.Get("interval").(time.Duration)
-	timeout := params.Get("timeout").(time.Duration)
-	packetSize := params.Get("packet_size").(int)
-	ttl := params.Get("ttl").(int)
-	ipv6 := params.Get("ipv6").(bool)
-
-	opts := domain.PingOptions{
-		Count:      count,
-		Interval:   interval,
-		Timeout:    timeout,
-		PacketSize: packetSize,
-		TTL:        ttl,
-		IPv6:       ipv6,
+	targets := params.Get("targets").([]string)
+	host := targets[0]
+	opts := pingOptions(params)
+	count := opts.Count
+	mode := opts.Mode
+
+	if len(targets) > 1 {
+		return t.executeMulti(ctx, targets, opts), nil
 	}
 
 	// Perform ping operation
@@ -92,16 +88,105 @@ func (t *Tool) Execute(ctx context.Context, params domain.Parameters) (domain.Re
 	result.SetMetadata("tool", t.Name())
 	result.SetMetadata("host", host)
 	result.SetMetadata("count", count)
+	result.SetMetadata("mode", string(mode))
 	result.SetMetadata("timestamp", time.Now())
 
 	// Calculate statistics
-	stats := t.calculateStatistics(results)
+	stats := calculateStatistics(results)
 	result.SetMetadata("statistics", stats)
+	if hint, ok := domain.PingTTLHint(results); ok {
+		result.SetMetadata("os_hint", hint)
+	}
+	t.setProbeMethod(result)
 
 	t.logger.Info("Ping operation completed", "host", host, "count", len(results))
 	return result, nil
 }
 
+// LiveModel implements domain.LiveTool, running params on the ping screen
+// with its latency graph and, for several hosts, the per-host overview. The
+// screen probes through the tool's client and cannot be given new targets,
+// so a policy guard wrapping the tool checks everything it pings. The
+// continuous parameter pings until the screen is left.
+func (t *Tool) LiveModel(ctx context.Context, params domain.Parameters) (tea.Model, error) {
+	if err := t.Validate(params); err != nil {
+		return nil, &domain.NetTraceError{
+			Type:      domain.ErrorTypeValidation,
+			Message:   "Ping parameter validation failed",
+			Cause:     err,
+			Context:   map[string]interface{}{"params": params.ToMap()},
+			Timestamp: time.Now(),
+			Code:      "PING_VALIDATION_FAILED",
+		}
+	}
+
+	opts := pingOptions(params)
+	if continuous, _ := params.Get("continuous").(bool); continuous {
+		opts.Count = 0
+	}
+
+	m := NewModel(t)
+	m.live = true
+	m.ctx = ctx
+	m.options = opts
+	m.mode = opts.Mode
+	m.hostInput.SetValue(strings.Join(params.Get("targets").([]string), ", "))
+	m.countInput.SetValue(strconv.Itoa(opts.Count))
+	m.intervalInput.SetValue(strconv.FormatFloat(opts.Interval.Seconds(), 'f', -1, 64))
+	m.Blur()
+	return m, nil
+}
+
+// pingOptions returns the options of validated ping parameters
+func pingOptions(params domain.Parameters) domain.PingOptions {
+	return domain.PingOptions{
+		Count:      params.Get("count").(int),
+		Interval:   params.Get("interval").(time.Duration),
+		Timeout:    params.Get("timeout").(time.Duration),
+		PacketSize: params.Get("packet_size").(int),
+		TTL:        params.Get("ttl").(int),
+		IPv6:       params.Get("ipv6").(bool),
+		Mode:       params.Get("mode").(domain.PingMode),
+	}
+}
+
+// executeMulti pings several hosts concurrently and returns a MultiPingResult
+func (t *Tool) executeMulti(ctx context.Context, targets []string, opts domain.PingOptions) domain.Result {
+	t.logger.Info("Pinging multiple targets", "targets", len(targets))
+
+	multi := t.pingTargets(ctx, targets, opts)
+
+	result := domain.NewResult(multi)
+	result.SetMetadata("tool", t.Name())
+	result.SetMetadata("host", strings.Join(targets, ", "))
+	result.SetMetadata("targets", len(targets))
+	result.SetMetadata("count", opts.Count)
+	result.SetMetadata("mode", string(opts.Mode))
+	result.SetMetadata("timestamp", time.Now())
+	t.setProbeMethod(result)
+
+	t.logger.Info("Multi-target ping completed", "targets", len(targets))
+	return result
+}
+
+// setProbeMethod records whether probes were ICMP echoes or TCP connects,
+// explaining the degraded mode when the client reports no ICMP capability
+func (t *Tool) setProbeMethod(result *domain.BaseResult) {
+	reporter, ok := t.client.(domain.CapabilityReporter)
+	if !ok {
+		return
+	}
+	capabilities := reporter.Capabilities()
+	if capabilities.ICMP {
+		result.SetMetadata("probe_method", "icmp")
+		return
+	}
+	result.SetMetadata("probe_method", "tcp")
+	if len(capabilities.Notes) > 0 {
+		result.SetMetadata("degraded", strings.Join(capabilities.Notes, ". "))
+	}
+}
+
 // Validate validates the parameters for ping operations
 func (t *Tool) Validate(params domain.Parameters) error {
 	host := params.Get("host")
@@ -118,6 +203,13 @@ func (t *Tool) Validate(params domain.Parameters) error {
 		return fmt.Errorf("host parameter cannot be empty")
 	}
 
+	// A host list or target file pings several hosts at once
+	targets, err := ParseTargets(hostStr)
+	if err != nil {
+		return err
+	}
+	params.Set("targets", targets)
+
 	// Validate count
 	if count := params.Get("count"); count != nil {
 		if countInt, ok := count.(int); ok && countInt <= 0 {
@@ -125,6 +217,35 @@ func (t *Tool) Validate(params domain.Parameters) error {
 		}
 	}
 
+	// Validate interval
+	if interval := params.Get("interval"); interval != nil {
+		if intervalDur, ok := interval.(time.Duration); ok && intervalDur < 0 {
+			return fmt.Errorf("interval must be non-negative")
+		}
+	}
+
+	// Validate mode, defaulting to normal
+	var mode domain.PingMode
+	switch v := params.Get("mode").(type) {
+	case nil:
+		mode = domain.PingModeNormal
+	case domain.PingMode:
+		mode = v
+	case string:
+		mode = domain.PingMode(strings.ToLower(strings.TrimSpace(v)))
+		if mode == "" {
+			mode = domain.PingModeNormal
+		}
+	default:
+		return fmt.Errorf("mode parameter must be a string")
+	}
+	switch mode {
+	case domain.PingModeNormal, domain.PingModeAdaptive, domain.PingModeFlood:
+	default:
+		return fmt.Errorf("mode must be normal, adaptive, or flood")
+	}
+	params.Set("mode", mode)
+
 	// Validate packet size
 	if packetSize := params.Get("packet_size"); packetSize != nil {
 		if sizeInt, ok := packetSize.(int); ok && (sizeInt <= 0 || sizeInt > 65507) {
@@ -156,22 +277,27 @@ type PingStatistics struct {
 	MaxRTT          time.Duration `json:"max_rtt"`
 	AvgRTT          time.Duration `json:"avg_rtt"`
 	StdDevRTT       time.Duration `json:"stddev_rtt"`
+	P50RTT          time.Duration `json:"p50_rtt"`
+	P90RTT          time.Duration `json:"p90_rtt"`
+	P99RTT          time.Duration `json:"p99_rtt"`
+	Jitter          time.Duration `json:"jitter"`
+	Reordered       int           `json:"reordered"`
+	MOS             float64       `json:"mos"`
 	TotalTime       time.Duration `json:"total_time"`
 }
 
 // calculateStatistics calculates ping statistics from results
-func (t *Tool) calculateStatistics(results []domain.PingResult) PingStatistics {
-	stats := PingStatistics{
+func calculateStatistics(results []domain.PingResult) PingStatistics {
+	statistics := PingStatistics{
 		PacketsSent: len(results),
 	}
 
 	if len(results) == 0 {
-		return stats
+		return statistics
 	}
 
-	var validResults []domain.PingResult
-	var totalRTT time.Duration
-	var minRTT, maxRTT time.Duration
+	var rtts []time.Duration
+	var arrival stats.Interarrival
 	var startTime, endTime time.Time
 
 	// Find first and last timestamps
@@ -188,50 +314,60 @@ func (t *Tool) calculateStatistics(results []domain.PingResult) PingStatistics {
 
 		// Only count successful pings
 		if result.Error == nil {
-			validResults = append(validResults, result)
-			totalRTT += result.RTT
-
-			if len(validResults) == 1 {
-				minRTT = result.RTT
-				maxRTT = result.RTT
-			} else {
-				if result.RTT < minRTT {
-					minRTT = result.RTT
-				}
-				if result.RTT > maxRTT {
-					maxRTT = result.RTT
-				}
-			}
+			rtts = append(rtts, result.RTT)
+			arrival.Add(result.Sequence, result.RTT)
 		}
 	}
 
-	stats.PacketsReceived = len(validResults)
-	stats.TotalTime = endTime.Sub(startTime)
-
-	if stats.PacketsSent > 0 {
-		stats.PacketLoss = float64(stats.PacketsSent-stats.PacketsReceived) / float64(stats.PacketsSent) * 100
+	summary := stats.Summarize(rtts)
+	statistics.PacketsReceived = summary.Count
+	statistics.TotalTime = endTime.Sub(startTime)
+	statistics.PacketLoss = stats.Loss(statistics.PacketsSent, statistics.PacketsReceived)
+	statistics.MinRTT = summary.Min
+	statistics.MaxRTT = summary.Max
+	statistics.AvgRTT = summary.Mean
+	statistics.StdDevRTT = summary.StdDev
+	if len(rtts) > 0 {
+		percentiles := stats.Percentiles(rtts, 50, 90, 99)
+		statistics.P50RTT, statistics.P90RTT, statistics.P99RTT = percentiles[0], percentiles[1], percentiles[2]
+	}
+	statistics.Jitter = arrival.Jitter()
+	statistics.Reordered = arrival.Reordered()
+	if statistics.PacketsReceived > 0 {
+		statistics.MOS = stats.MOS(statistics.AvgRTT, statistics.Jitter, statistics.PacketLoss)
 	}
 
-	if len(validResults) > 0 {
-		stats.MinRTT = minRTT
-		stats.MaxRTT = maxRTT
-		stats.AvgRTT = totalRTT / time.Duration(len(validResults))
+	return statistics
+}
 
-		// Calculate standard deviation
-		var variance time.Duration
-		for _, result := range validResults {
-			diff := result.RTT - stats.AvgRTT
-			variance += diff * diff / time.Duration(len(validResults))
-		}
-		stats.StdDevRTT = time.Duration(float64(variance) * 0.5) // Approximate square root
+// streamedStatistics returns the statistics accumulated one reply at a time,
+// with percentiles estimated within 1%. It keeps no replies, so it suits
+// continuous pings of any length; TotalTime is left to the caller.
+func streamedStatistics(rtt *stats.Running, arrival *stats.Interarrival) PingStatistics {
+	summary := rtt.Summary()
+	statistics := PingStatistics{
+		PacketsSent:     rtt.Sent(),
+		PacketsReceived: rtt.Received(),
+		PacketLoss:      rtt.Loss(),
+		MinRTT:          summary.Min,
+		MaxRTT:          summary.Max,
+		AvgRTT:          summary.Mean,
+		StdDevRTT:       summary.StdDev,
+		P50RTT:          rtt.Percentile(50),
+		P90RTT:          rtt.Percentile(90),
+		P99RTT:          rtt.Percentile(99),
+		Jitter:          arrival.Jitter(),
+		Reordered:       arrival.Reordered(),
 	}
-
-	return stats
+	if statistics.PacketsReceived > 0 {
+		statistics.MOS = stats.MOS(summary.Mean, statistics.Jitter, statistics.PacketLoss)
+	}
+	return statistics
 }
 
 // FormatPingStatistics formats ping statistics for display
 func FormatPingStatistics(stats PingStatistics) string {
-	return fmt.Sprintf(
+	formatted := fmt.Sprintf(
 		"--- Ping Statistics ---\n"+
 			"Packets: Sent = %d, Received = %d, Lost = %d (%.1f%% loss)\n"+
 			"Round-trip times: Min = %v, Max = %v, Avg = %v\n"+
@@ -245,4 +381,25 @@ func FormatPingStatistics(stats PingStatistics) string {
 		stats.AvgRTT,
 		stats.TotalTime,
 	)
+	if stats.PacketsReceived > 0 {
+		formatted += fmt.Sprintf("\nPercentiles: p50 = %v, p90 = %v, p99 = %v",
+			stats.P50RTT,
+			stats.P90RTT,
+			stats.P99RTT,
+		)
+		formatted += fmt.Sprintf("\nJitter (RFC 3550): %v, Reordered: %d\n"+
+			"VoIP readiness: %s (MOS %.2f)",
+			stats.Jitter,
+			stats.Reordered,
+			voipGrade(stats.MOS),
+			stats.MOS,
+		)
+	}
+	return formatted
+}
+
+// voipGrade returns the VoIP readiness grade for a MOS score; FormatPingStatistics
+// cannot call the stats package directly as its parameter shadows it
+func voipGrade(mos float64) string {
+	return stats.VoIPGrade(mos)
 }
\ No newline at end of file
@@ -104,6 +104,36 @@ func TestTool_Validate(t *testing.T) {
 			}(),
 			wantErr: false,
 		},
+		{
+			name: "invalid interval parameter",
+			params: func() domain.Parameters {
+				p := domain.NewParameters()
+				p.Set("host", "google.com")
+				p.Set("interval", -time.Second)
+				return p
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "flood mode parameter",
+			params: func() domain.Parameters {
+				p := domain.NewParameters()
+				p.Set("host", "google.com")
+				p.Set("mode", "Flood")
+				return p
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "invalid mode parameter",
+			params: func() domain.Parameters {
+				p := domain.NewParameters()
+				p.Set("host", "google.com")
+				p.Set("mode", "burst")
+				return p
+			}(),
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -116,6 +146,27 @@ func TestTool_Validate(t *testing.T) {
 	}
 }
 
+func TestTool_Validate_NormalizesMode(t *testing.T) {
+	tool := &Tool{}
+
+	params := domain.NewParameters()
+	params.Set("host", "google.com")
+	if err := tool.Validate(params); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if mode := params.Get("mode"); mode != domain.PingModeNormal {
+		t.Errorf("Expected default mode %q, got %v", domain.PingModeNormal, mode)
+	}
+
+	params.Set("mode", " Adaptive ")
+	if err := tool.Validate(params); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if mode := params.Get("mode"); mode != domain.PingModeAdaptive {
+		t.Errorf("Expected mode %q, got %v", domain.PingModeAdaptive, mode)
+	}
+}
+
 // MockLogger implements a simple logger for testing
 type MockLogger struct{}
 
@@ -214,12 +265,19 @@ func TestTool_Execute(t *testing.T) {
 	if stats.PacketsReceived != 2 {
 		t.Errorf("Statistics PacketsReceived = %d, want 2", stats.PacketsReceived)
 	}
+
+	// Replies arrive with the initial TTL, so the hint reports no hops
+	hint, ok := metadata["os_hint"].(domain.TTLHint)
+	if !ok {
+		t.Fatal("Tool.Execute() metadata missing os_hint")
+	}
+	if hint.Family != "Linux/Unix" || hint.Hops != 0 {
+		t.Errorf("OS hint = %+v, want Linux/Unix with 0 hops", hint)
+	}
 }
 
 // TestCalculateStatistics tests ping statistics calculation
 func TestCalculateStatistics(t *testing.T) {
-	tool := &Tool{}
-
 	tests := []struct {
 		name     string
 		results  []domain.PingResult
@@ -282,7 +340,7 @@ func TestCalculateStatistics(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			stats := tool.calculateStatistics(tt.results)
+			stats := calculateStatistics(tt.results)
 
 			if stats.PacketsSent != tt.expected.PacketsSent {
 				t.Errorf("PacketsSent = %d, want %d", stats.PacketsSent, tt.expected.PacketsSent)
@@ -346,4 +404,68 @@ func TestFormatPingStatistics(t *testing.T) {
 			t.Errorf("FormatPingStatistics() missing expected string: %s", expected)
 		}
 	}
-}
\ No newline at end of file
+}
+
+// TestCalculateStatistics_VoIP tests jitter, reordering and MOS estimation
+func TestCalculateStatistics_VoIP(t *testing.T) {
+	// Flood mode delivers replies in completion order, so sequence 2 arrives last
+	results := []domain.PingResult{
+		{Sequence: 1, RTT: 20 * time.Millisecond, Timestamp: time.Now()},
+		{Sequence: 3, RTT: 36 * time.Millisecond, Timestamp: time.Now()},
+		{Sequence: 2, RTT: 36 * time.Millisecond, Timestamp: time.Now()},
+	}
+
+	statistics := calculateStatistics(results)
+	if statistics.Reordered != 1 {
+		t.Errorf("Reordered = %d, want 1", statistics.Reordered)
+	}
+	if statistics.Jitter <= 0 || statistics.Jitter > time.Millisecond {
+		t.Errorf("Jitter = %v, want RFC 3550 estimate just under 1ms", statistics.Jitter)
+	}
+	if statistics.MOS < 4.3 {
+		t.Errorf("MOS = %.2f, want an excellent score for a clean 30ms path", statistics.MOS)
+	}
+
+	formatted := FormatPingStatistics(statistics)
+	for _, expected := range []string{"Jitter (RFC 3550)", "Reordered: 1", "VoIP readiness: Excellent"} {
+		if !strings.Contains(formatted, expected) {
+			t.Errorf("FormatPingStatistics() missing expected string: %s", expected)
+		}
+	}
+
+	if formatted := FormatPingStatistics(calculateStatistics(nil)); strings.Contains(formatted, "VoIP") {
+		t.Error("Expected no VoIP grade without replies")
+	}
+}
+// TestTool_Execute_DegradedProbeMethod tests that a client without ICMP explains the TCP fallback
+func TestTool_Execute_DegradedProbeMethod(t *testing.T) {
+	mockClient := network.NewMockClient()
+	tool := NewTool(mockClient, &MockLogger{})
+	mockClient.SetPingResponse("example.com", []domain.PingResult{
+		{Host: domain.NetworkHost{Hostname: "example.com", IPAddress: []byte{192, 0, 2, 1}}, Sequence: 1, RTT: 10 * time.Millisecond},
+	})
+	params := domain.NewPingParameters("example.com", domain.PingOptions{Count: 1, Interval: time.Second, Timeout: time.Second, PacketSize: 64, TTL: 64})
+
+	result, err := tool.Execute(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Tool.Execute() error = %v", err)
+	}
+	if method := result.Metadata()["probe_method"]; method != "icmp" {
+		t.Errorf("Expected icmp probe method, got %v", method)
+	}
+	if _, degraded := result.Metadata()["degraded"]; degraded {
+		t.Error("Expected no degraded note with ICMP available")
+	}
+
+	mockClient.SetCapabilities(domain.Capabilities{Notes: []string{"Ping falls back to TCP connects"}})
+	result, err = tool.Execute(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Tool.Execute() error = %v", err)
+	}
+	if method := result.Metadata()["probe_method"]; method != "tcp" {
+		t.Errorf("Expected tcp probe method, got %v", method)
+	}
+	if note := result.Metadata()["degraded"]; note != "Ping falls back to TCP connects" {
+		t.Errorf("Expected the degraded mode to be explained, got %v", note)
+	}
+}
@@ -0,0 +1,135 @@
+// Package ping provides multi-target parsing and concurrent execution
+package ping
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+const (
+	// MaxTargets bounds the number of hosts a single ping run accepts
+	MaxTargets = 64
+
+	// maxConcurrentTargets bounds how many hosts are pinged at the same time
+	maxConcurrentTargets = 8
+)
+
+// ParseTargets parses a host list. The input is either a comma or whitespace
+// separated list of hosts, or "@path" naming a file with one host per line.
+// Blank lines and "#" comments in files are ignored and duplicates are dropped.
+func ParseTargets(input string) ([]string, error) {
+	input = strings.TrimSpace(input)
+	if strings.HasPrefix(input, "@") {
+		return readTargetsFile(strings.TrimSpace(input[1:]))
+	}
+
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+	return uniqueTargets(fields)
+}
+
+// readTargetsFile reads a host list from path
+func readTargetsFile(path string) ([]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("target file path cannot be empty")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open target file: %w", err)
+	}
+	defer file.Close()
+
+	var hosts []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		hosts = append(hosts, strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read target file: %w", err)
+	}
+
+	return uniqueTargets(hosts)
+}
+
+// uniqueTargets removes duplicates while keeping the original order
+func uniqueTargets(hosts []string) ([]string, error) {
+	seen := make(map[string]bool, len(hosts))
+	var targets []string
+	for _, host := range hosts {
+		key := strings.ToLower(host)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		targets = append(targets, host)
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no hosts specified")
+	}
+	if len(targets) > MaxTargets {
+		return nil, fmt.Errorf("too many hosts: %d (maximum %d)", len(targets), MaxTargets)
+	}
+	return targets, nil
+}
+
+// pingTargets pings every host concurrently and summarises each one.
+// Targets are returned in the order given regardless of completion order.
+func (t *Tool) pingTargets(ctx context.Context, hosts []string, opts domain.PingOptions) domain.MultiPingResult {
+	multi := domain.MultiPingResult{Targets: make([]domain.PingTargetResult, len(hosts))}
+
+	sem := make(chan struct{}, maxConcurrentTargets)
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		go func(i int, host string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			target := domain.PingTargetResult{Host: host}
+			resultChan, err := t.client.Ping(ctx, host, opts)
+			if err != nil {
+				target.Error = err
+				multi.Targets[i] = target
+				return
+			}
+			for result := range resultChan {
+				target.Results = append(target.Results, result)
+			}
+			multi.Targets[i] = summarizeTarget(target)
+		}(i, host)
+	}
+	wg.Wait()
+
+	return multi
+}
+
+// summarizeTarget fills in the loss and average RTT of target from its results
+func summarizeTarget(target domain.PingTargetResult) domain.PingTargetResult {
+	statistics := calculateStatistics(target.Results)
+	target.PacketsSent = statistics.PacketsSent
+	target.PacketsReceived = statistics.PacketsReceived
+	target.PacketLoss = statistics.PacketLoss
+	target.AvgRTT = statistics.AvgRTT
+
+	// A host that never resolved reports a single errored result
+	if target.PacketsReceived == 0 && len(target.Results) > 0 && target.Results[0].Sequence == 0 {
+		target.Error = target.Results[0].Error
+	}
+	return target
+}
@@ -0,0 +1,322 @@
+// Package ping provides the multi-target overview and drill-down for the ping model
+package ping
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/keyhint"
+	"github.com/nettracex/nettracex-tui/internal/stats"
+)
+
+// targetRow tracks the live state of one host in a multi-target ping
+type targetRow struct {
+	host    string
+	rtt     stats.Running
+	arrival stats.Interarrival
+	results []domain.PingResult
+	err     error
+}
+
+// record adds result to the row, keeping a bounded window of results
+func (r *targetRow) record(result domain.PingResult) {
+	// A result without a sequence number reports a host that could not be pinged
+	if result.Sequence == 0 && result.Error != nil {
+		r.err = result.Error
+		return
+	}
+
+	if result.Error == nil {
+		r.rtt.Add(result.RTT)
+		r.arrival.Add(result.Sequence, result.RTT)
+	} else {
+		r.rtt.AddLoss()
+	}
+
+	r.results = append(r.results, result)
+	if len(r.results) > 2*maxRetainedResults {
+		r.results = append(r.results[:0], r.results[len(r.results)-maxRetainedResults:]...)
+	}
+}
+
+// statistics returns the row's statistics over every probe sent
+func (r *targetRow) statistics() PingStatistics {
+	statistics := streamedStatistics(&r.rtt, &r.arrival)
+	if len(r.results) > 1 {
+		statistics.TotalTime = r.results[len(r.results)-1].Timestamp.Sub(r.results[0].Timestamp)
+	}
+	return statistics
+}
+
+// taggedPingResult is a ping result labelled with the index of its target
+type taggedPingResult struct {
+	index  int
+	result domain.PingResult
+}
+
+// multiPingStartMsg starts a multi-target ping
+type multiPingStartMsg struct {
+	hosts []string
+	opts  domain.PingOptions
+}
+
+// multiPingResultMsg delivers one result for a target
+type multiPingResultMsg taggedPingResult
+
+// multiPingCompleteMsg signals that every target has finished
+type multiPingCompleteMsg struct{}
+
+// isMulti reports whether the model is pinging several hosts
+func (m *Model) isMulti() bool {
+	return len(m.targets) > 1
+}
+
+// displayHost returns the host shown in the live view header
+func (m *Model) displayHost() string {
+	if m.isMulti() && m.drillDown >= 0 {
+		return m.targets[m.drillDown].host
+	}
+	return m.hostInput.Value()
+}
+
+// executeMultiPing pings every target concurrently, merging the results into
+// a single channel that the model drains one message at a time
+func (m *Model) executeMultiPing(hosts []string, opts domain.PingOptions) tea.Cmd {
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.cancelFunc = cancel
+
+	merged := make(chan taggedPingResult, len(hosts))
+	m.multiResults = merged
+
+	go func() {
+		defer close(merged)
+
+		sem := make(chan struct{}, maxConcurrentTargets)
+		var wg sync.WaitGroup
+		for i, host := range hosts {
+			wg.Add(1)
+			go func(i int, host string) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				resultChan, err := m.tool.client.Ping(ctx, host, opts)
+				if err != nil {
+					failed := taggedPingResult{index: i, result: domain.PingResult{
+						Host:      domain.NetworkHost{Hostname: host},
+						Error:     err,
+						Timestamp: time.Now(),
+					}}
+					select {
+					case merged <- failed:
+					case <-ctx.Done():
+					}
+					return
+				}
+				for result := range resultChan {
+					select {
+					case merged <- taggedPingResult{index: i, result: result}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}(i, host)
+		}
+		wg.Wait()
+	}()
+
+	return m.waitForMultiPing()
+}
+
+// waitForMultiPing returns a command that waits for the next merged result
+func (m *Model) waitForMultiPing() tea.Cmd {
+	results := m.multiResults
+	return func() tea.Msg {
+		tagged, ok := <-results
+		if !ok {
+			return multiPingCompleteMsg{}
+		}
+		return multiPingResultMsg(tagged)
+	}
+}
+
+// handleMultiPingMsg updates the model for multi-target messages
+func (m *Model) handleMultiPingMsg(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case multiPingStartMsg:
+		m.state = StateRunning
+		m.loading = true
+		m.startTime = time.Now()
+		m.lastUpdate = time.Now()
+		m.targets = make([]*targetRow, len(msg.hosts))
+		for i, host := range msg.hosts {
+			m.targets[i] = &targetRow{host: host}
+		}
+		m.selected = 0
+		m.drillDown = -1
+		m.resetLiveComponents()
+		return tea.Batch(m.tickCmd(), m.executeMultiPing(msg.hosts, msg.opts))
+
+	case multiPingResultMsg:
+		if msg.index < 0 || msg.index >= len(m.targets) {
+			return m.waitForMultiPing()
+		}
+		row := m.targets[msg.index]
+		row.record(msg.result)
+		if msg.index == m.drillDown && msg.result.Sequence != 0 {
+			m.appendResult(msg.result)
+			m.updateLiveStats(msg.result)
+		}
+		m.lastUpdate = time.Now()
+		return m.waitForMultiPing()
+
+	case multiPingCompleteMsg:
+		if m.state == StateRunning {
+			m.state = StateResult
+		}
+		m.loading = false
+		if m.drillDown >= 0 {
+			m.statistics = m.targets[m.drillDown].statistics()
+		}
+		if m.cancelFunc != nil {
+			m.cancelFunc()
+			m.cancelFunc = nil
+		}
+	}
+	return nil
+}
+
+// handleTargetKey handles overview navigation and drill-down keys. It reports
+// whether the key was consumed.
+func (m *Model) handleTargetKey(msg tea.KeyMsg) bool {
+	if !m.isMulti() || m.state == StateInput {
+		return false
+	}
+
+	if m.drillDown >= 0 {
+		if key.Matches(msg, m.keys.Back) {
+			m.drillDown = -1
+			return true
+		}
+		return false
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.selected > 0 {
+			m.selected--
+		}
+		return true
+	case key.Matches(msg, m.keys.Down):
+		if m.selected < len(m.targets)-1 {
+			m.selected++
+		}
+		return true
+	case key.Matches(msg, m.keys.LiveView):
+		m.drillInto(m.selected)
+		return true
+	}
+	return false
+}
+
+// drillInto switches to the full live view for the target at index, rebuilding
+// the graph and loss indicator from its retained results
+func (m *Model) drillInto(index int) {
+	row := m.targets[index]
+	m.drillDown = index
+	m.resetLiveComponents()
+
+	for _, result := range row.results {
+		m.updateLiveStats(result)
+	}
+	m.results = append([]domain.PingResult(nil), row.results...)
+
+	// The retained window may be shorter than the run; totals and jitter come from the row
+	m.liveStats.rtt = row.rtt
+	m.liveStats.arrival = row.arrival
+	m.refreshLiveStats()
+	m.liveStats.ElapsedTime = time.Since(m.startTime)
+	m.statistics = row.statistics()
+	m.error = row.err
+}
+
+// renderTargetOverview renders one compact row per target with loss and average RTT
+func (m *Model) renderTargetOverview() string {
+	var content strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colors.Warning)
+
+	title := fmt.Sprintf("🔍 Pinging %d hosts...", len(m.targets))
+	if m.state == StateResult {
+		title = fmt.Sprintf("Ping Results: %d hosts", len(m.targets))
+	}
+	content.WriteString(titleStyle.Render(title))
+	content.WriteString("\n\n")
+
+	hostWidth := len("Host")
+	for _, row := range m.targets {
+		if len(row.host) > hostWidth {
+			hostWidth = len(row.host)
+		}
+	}
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colors.Info)
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colors.Accent)
+
+	content.WriteString(headerStyle.Render(fmt.Sprintf("  %-*s %6s %6s %7s %10s %10s", hostWidth, "Host", "Sent", "Recv", "Loss", "Avg RTT", "Last")))
+	content.WriteString("\n")
+
+	for i, row := range m.targets {
+		avg, last := "-", "-"
+		if row.rtt.Received() > 0 {
+			avg = row.rtt.Summary().Mean.Round(10 * time.Microsecond).String()
+			last = row.rtt.Last().Round(10 * time.Microsecond).String()
+		}
+		if row.err != nil {
+			avg, last = "error", ""
+		}
+
+		line := fmt.Sprintf("%-*s %6d %6d %6.1f%% %10s %10s",
+			hostWidth, row.host, row.rtt.Sent(), row.rtt.Received(), row.rtt.Loss(), avg, last)
+		if i == m.selected {
+			content.WriteString(selectedStyle.Render("> " + line))
+		} else {
+			content.WriteString(m.lossStyle(row).Render("  " + line))
+		}
+		content.WriteString("\n")
+	}
+
+	instructionStyle := lipgloss.NewStyle().
+		Italic(true).
+		MarginTop(1)
+	content.WriteString(instructionStyle.Render(keyhint.Help(m.width).ShortHelpView(m.targetKeys())))
+
+	return content.String()
+}
+
+// lossStyle colors a row by its packet loss
+func (m *Model) lossStyle(row *targetRow) lipgloss.Style {
+	style := lipgloss.NewStyle()
+	switch {
+	case row.err != nil || (row.rtt.Sent() > 0 && row.rtt.Received() == 0):
+		return style.Foreground(colors.Error)
+	case row.rtt.Loss() > 0:
+		return style.Foreground(colors.Warning)
+	default:
+		return style.Foreground(colors.Text)
+	}
+}
@@ -0,0 +1,198 @@
+// Package ping provides tests for multi-target ping
+package ping
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/network"
+)
+
+func TestParseTargets(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "single host", input: "google.com", expected: []string{"google.com"}},
+		{name: "comma separated", input: "google.com, 8.8.8.8,1.1.1.1", expected: []string{"google.com", "8.8.8.8", "1.1.1.1"}},
+		{name: "whitespace separated", input: "google.com 8.8.8.8", expected: []string{"google.com", "8.8.8.8"}},
+		{name: "duplicates removed", input: "google.com,Google.com,8.8.8.8", expected: []string{"google.com", "8.8.8.8"}},
+		{name: "empty", input: " , ", wantErr: true},
+		{name: "missing file", input: "@/nonexistent/targets.txt", wantErr: true},
+		{name: "empty file path", input: "@", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets, err := ParseTargets(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTargets(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.expected != nil && !reflect.DeepEqual(targets, tt.expected) {
+				t.Errorf("ParseTargets(%q) = %v, expected %v", tt.input, targets, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseTargets_Limit(t *testing.T) {
+	hosts := make([]string, MaxTargets+1)
+	for i := range hosts {
+		hosts[i] = strings.Repeat("a", i+1) + ".example"
+	}
+
+	if _, err := ParseTargets(strings.Join(hosts[:MaxTargets], ",")); err != nil {
+		t.Errorf("Expected %d hosts to be accepted, got %v", MaxTargets, err)
+	}
+	if _, err := ParseTargets(strings.Join(hosts, ",")); err == nil {
+		t.Errorf("Expected more than %d hosts to be rejected", MaxTargets)
+	}
+}
+
+func TestParseTargets_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "targets.txt")
+	content := "# core routers\ngoogle.com\n\n8.8.8.8  # resolver\n1.1.1.1, 9.9.9.9\ngoogle.com\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write target file: %v", err)
+	}
+
+	targets, err := ParseTargets("@" + path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{"google.com", "8.8.8.8", "1.1.1.1", "9.9.9.9"}
+	if !reflect.DeepEqual(targets, expected) {
+		t.Errorf("Expected %v, got %v", expected, targets)
+	}
+}
+
+// newMultiTargetTool creates a tool whose mock answers for two hosts and fails a third
+func newMultiTargetTool() *Tool {
+	mockClient := network.NewMockClient()
+	mockClient.SetPingResponse("a.example", []domain.PingResult{
+		{Host: domain.NetworkHost{Hostname: "a.example"}, Sequence: 1, RTT: 10 * time.Millisecond},
+		{Host: domain.NetworkHost{Hostname: "a.example"}, Sequence: 2, RTT: 20 * time.Millisecond},
+	})
+	mockClient.SetPingResponse("b.example", []domain.PingResult{
+		{Host: domain.NetworkHost{Hostname: "b.example"}, Sequence: 1, RTT: 30 * time.Millisecond},
+		{Host: domain.NetworkHost{Hostname: "b.example"}, Sequence: 2, Error: errors.New("timeout")},
+	})
+	mockClient.SetPingError("c.example", errors.New("no route"))
+	return NewTool(mockClient, &MockLogger{})
+}
+
+func TestTool_Execute_MultipleTargets(t *testing.T) {
+	tool := newMultiTargetTool()
+
+	params := domain.NewPingParameters("a.example, b.example, c.example", domain.PingOptions{
+		Count:      2,
+		Timeout:    time.Second,
+		PacketSize: 64,
+		TTL:        64,
+	})
+
+	result, err := tool.Execute(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	multi, ok := result.Data().(domain.MultiPingResult)
+	if !ok {
+		t.Fatalf("Expected MultiPingResult, got %T", result.Data())
+	}
+	if len(multi.Targets) != 3 {
+		t.Fatalf("Expected 3 targets, got %d", len(multi.Targets))
+	}
+
+	a, b, c := multi.Targets[0], multi.Targets[1], multi.Targets[2]
+	if a.Host != "a.example" || a.PacketsReceived != 2 || a.PacketLoss != 0 || a.AvgRTT != 15*time.Millisecond {
+		t.Errorf("Unexpected summary for a.example: %+v", a)
+	}
+	if b.Host != "b.example" || b.PacketsSent != 2 || b.PacketsReceived != 1 || b.PacketLoss != 50 {
+		t.Errorf("Unexpected summary for b.example: %+v", b)
+	}
+	if c.Host != "c.example" || c.Error == nil {
+		t.Errorf("Expected error for c.example, got %+v", c)
+	}
+
+	if targets := result.Metadata()["targets"]; targets != 3 {
+		t.Errorf("Expected targets metadata 3, got %v", targets)
+	}
+}
+
+func TestModel_MultiTargetOverview(t *testing.T) {
+	tool := newMultiTargetTool()
+	model := NewModel(tool)
+	model.hostInput.SetValue("a.example,b.example")
+
+	// Run the start command and drain every result through Update
+	cmd := model.startPing()
+	msg := cmd()
+	if _, ok := msg.(multiPingStartMsg); !ok {
+		t.Fatalf("Expected multiPingStartMsg, got %T", msg)
+	}
+	model.Update(msg)
+	if !model.isMulti() || model.state != StateRunning {
+		t.Fatalf("Expected running multi-target ping, got state %v with %d targets", model.state, len(model.targets))
+	}
+
+	next := model.waitForMultiPing()
+	for i := 0; i < 10; i++ {
+		msg := next()
+		_, cmd := model.Update(msg)
+		if _, done := msg.(multiPingCompleteMsg); done {
+			break
+		}
+		next = cmd
+	}
+
+	if model.state != StateResult {
+		t.Fatalf("Expected result state after all targets finished, got %v", model.state)
+	}
+	if sent := model.targets[1].rtt.Sent(); sent != 2 {
+		t.Errorf("Expected 2 probes for b.example, got %d", sent)
+	}
+
+	view := model.View()
+	for _, expected := range []string{"a.example", "b.example", "50.0%", "select host"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected overview to contain %q", expected)
+		}
+	}
+
+	// Drill down into the second host and back out
+	model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.drillDown != 1 {
+		t.Fatalf("Expected drill-down into host 1, got %d", model.drillDown)
+	}
+	if model.liveStats.PacketsSent != 2 || model.liveStats.PacketsReceived != 1 {
+		t.Errorf("Expected live statistics for b.example, got %+v", model.liveStats)
+	}
+	if model.statistics.PacketLoss != 50 {
+		t.Errorf("Expected 50%% loss in drill-down statistics, got %.1f", model.statistics.PacketLoss)
+	}
+	if !strings.Contains(model.View(), "Ping Results Summary") {
+		t.Error("Expected the full result view after drilling down")
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.drillDown != -1 || model.state != StateResult {
+		t.Errorf("Expected esc to return to the overview, got drillDown %d state %v", model.drillDown, model.state)
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.state != StateInput || model.isMulti() {
+		t.Errorf("Expected second esc to start a new ping, got state %v", model.state)
+	}
+}
@@ -3,6 +3,7 @@ package ping
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -62,17 +63,134 @@ func TestModel_InputNavigation(t *testing.T) {
 		t.Errorf("Expected focused input to be 2 after second Tab, got %d", model.focusedInput)
 	}
 
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	model = updatedModel.(*Model)
+	if model.focusedInput != modeInputIndex {
+		t.Errorf("Expected focused input to be the mode selector after third Tab, got %d", model.focusedInput)
+	}
+
 	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyTab})
 	model = updatedModel.(*Model)
 	if model.focusedInput != 0 {
-		t.Errorf("Expected focused input to wrap to 0 after third Tab, got %d", model.focusedInput)
+		t.Errorf("Expected focused input to wrap to 0 after fourth Tab, got %d", model.focusedInput)
 	}
 
 	// Test Shift+Tab navigation
 	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
 	model = updatedModel.(*Model)
-	if model.focusedInput != 2 {
-		t.Errorf("Expected focused input to be 2 after Shift+Tab, got %d", model.focusedInput)
+	if model.focusedInput != modeInputIndex {
+		t.Errorf("Expected focused input to be the mode selector after Shift+Tab, got %d", model.focusedInput)
+	}
+}
+
+// TestModel_ModeSelection tests cycling the ping mode from the input form
+func TestModel_ModeSelection(t *testing.T) {
+	mockClient := network.NewMockClient()
+	mockLogger := &MockLogger{}
+	tool := NewTool(mockClient, mockLogger)
+	model := NewModel(tool)
+
+	if model.mode != domain.PingModeNormal {
+		t.Errorf("Expected default mode normal, got %s", model.mode)
+	}
+
+	// Arrow keys only change the mode while the selector is focused
+	model.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if model.mode != domain.PingModeNormal {
+		t.Errorf("Expected mode to stay normal while host is focused, got %s", model.mode)
+	}
+
+	model.focusedInput = modeInputIndex
+	expected := []domain.PingMode{domain.PingModeAdaptive, domain.PingModeFlood, domain.PingModeNormal}
+	for _, mode := range expected {
+		model.Update(tea.KeyMsg{Type: tea.KeyRight})
+		if model.mode != mode {
+			t.Errorf("Expected mode %s, got %s", mode, model.mode)
+		}
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	if model.mode != domain.PingModeFlood {
+		t.Errorf("Expected Left to wrap to flood, got %s", model.mode)
+	}
+
+	if !strings.Contains(model.View(), "[flood]") {
+		t.Error("Expected the selected mode to be shown in the input form")
+	}
+
+	model.resetToInput()
+	if model.mode != domain.PingModeNormal {
+		t.Errorf("Expected reset to restore normal mode, got %s", model.mode)
+	}
+}
+
+// TestModel_RetainedResults tests that displayed results stay bounded
+func TestModel_RetainedResults(t *testing.T) {
+	mockClient := network.NewMockClient()
+	mockLogger := &MockLogger{}
+	tool := NewTool(mockClient, mockLogger)
+	model := NewModel(tool)
+
+	total := 5 * maxRetainedResults
+	for i := 1; i <= total; i++ {
+		model.Update(pingProgressMsg{
+			completed: i,
+			result:    domain.PingResult{Sequence: i, RTT: time.Duration(i) * time.Microsecond},
+		})
+	}
+
+	if len(model.results) > 2*maxRetainedResults {
+		t.Errorf("Expected at most %d retained results, got %d", 2*maxRetainedResults, len(model.results))
+	}
+	if last := model.results[len(model.results)-1]; last.Sequence != total {
+		t.Errorf("Expected the newest result to be retained, got sequence %d", last.Sequence)
+	}
+	if model.liveStats.PacketsSent != total {
+		t.Errorf("Expected live statistics to cover all %d samples, got %d", total, model.liveStats.PacketsSent)
+	}
+	if model.liveStats.MaxRTT != time.Duration(total)*time.Microsecond {
+		t.Errorf("Expected max RTT from all samples, got %v", model.liveStats.MaxRTT)
+	}
+}
+
+// TestModel_StreamsResults tests that every ping result reaches the live view
+func TestModel_StreamsResults(t *testing.T) {
+	mockClient := network.NewMockClient()
+	mockLogger := &MockLogger{}
+	tool := NewTool(mockClient, mockLogger)
+	model := NewModel(tool)
+	model.SetSize(100, 40)
+	model.hostInput.SetValue("example.com")
+	model.countInput.SetValue("3")
+	model.intervalInput.SetValue("0.01")
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model.Update(cmd())
+	_, cmd = model.Update(pingInitMsg{})
+	if cmd == nil {
+		t.Fatal("Expected a command starting the ping")
+	}
+
+	msg := cmd()
+	for sent := 1; sent <= 3; sent++ {
+		progress, ok := msg.(pingProgressMsg)
+		if !ok {
+			t.Fatalf("Expected result %d as a progress message, got %T", sent, msg)
+		}
+		model.Update(progress)
+		if model.liveStats.PacketsSent != sent || len(model.latencyGraph.Values) != sent {
+			t.Errorf("Expected the live statistics and graph to show %d pings, got %d and %d",
+				sent, model.liveStats.PacketsSent, len(model.latencyGraph.Values))
+		}
+		msg = waitForPingResult(progress.stream)()
+	}
+
+	if _, ok := msg.(pingCompleteMsg); !ok {
+		t.Fatalf("Expected the run to complete, got %T", msg)
+	}
+	model.Update(msg)
+	if model.state != StateResult || model.statistics.PacketsSent != 3 {
+		t.Errorf("Expected the result of 3 pings, got state %v with %d sent", model.state, model.statistics.PacketsSent)
 	}
 }
 
@@ -99,6 +217,36 @@ func TestModel_InputValidation(t *testing.T) {
 	}
 }
 
+func TestModel_TargetInput(t *testing.T) {
+	mockClient := network.NewMockClient()
+	mockLogger := &MockLogger{}
+	tool := NewTool(mockClient, mockLogger)
+	model := NewModel(tool)
+
+	// An invalid host in the list blocks the start
+	model.hostInput.SetValue("a.example, -bad-")
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil {
+		t.Error("Expected no command while a host is invalid")
+	}
+	if !strings.Contains(model.View(), "✗") {
+		t.Error("Expected the error under the host input")
+	}
+
+	// A URL is pinged by its host and recalled from the history
+	model.hostInput.SetValue("https://example.com/status")
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil {
+		t.Fatal("Expected the ping to start")
+	}
+	if got := model.hostInput.Value(); got != "example.com" {
+		t.Errorf("Expected the host of the URL, got %q", got)
+	}
+	model.resetToInput()
+	model.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if got := model.hostInput.Value(); got != "example.com" {
+		t.Errorf("Expected the previous host recalled, got %q", got)
+	}
+}
+
 // TestModel_RealTimeUpdates tests real-time statistics updates
 func TestModel_RealTimeUpdates(t *testing.T) {
 	mockClient := network.NewMockClient()
@@ -244,6 +392,225 @@ func TestModel_LatencyGraph(t *testing.T) {
 	}
 }
 
+// TestModel_LatencyGraphScroll tests scrolling the graph through earlier pings
+// TestModel_LatencyGraphChart tests the chart of latencies and lost pings
+func TestModel_LatencyGraphChart(t *testing.T) {
+	mockClient := network.NewMockClient()
+	mockLogger := &MockLogger{}
+	tool := NewTool(mockClient, mockLogger)
+	model := NewModel(tool)
+	model.SetSize(100, 40)
+
+	model.updateLiveStats(domain.PingResult{RTT: 10 * time.Millisecond})
+	model.updateLiveStats(domain.PingResult{Error: errors.New("timeout")})
+	model.updateLiveStats(domain.PingResult{RTT: 30 * time.Millisecond})
+
+	if got := model.latencyGraph.Values[1]; got != LostPing {
+		t.Errorf("Expected the lost ping in the graph, got %v", got)
+	}
+
+	graph := model.generateLatencyGraph()
+	if !strings.Contains(graph, "×") {
+		t.Error("Expected a marker for the lost ping")
+	}
+	if !strings.Contains(graph, "32ms┤") {
+		t.Errorf("Expected the value axis in milliseconds, got:\n%s", graph)
+	}
+	if !strings.ContainsAny(graph, "⠁⠂⠄⡀⠈⠐⠠⢀") {
+		t.Error("Expected the latencies drawn in braille")
+	}
+}
+
+func TestModel_RTTPercentilesAndHistogram(t *testing.T) {
+	mockClient := network.NewMockClient()
+	mockLogger := &MockLogger{}
+	tool := NewTool(mockClient, mockLogger)
+	model := NewModel(tool)
+	model.SetSize(100, 40)
+
+	for i := 1; i <= 100; i++ {
+		model.updateLiveStats(domain.PingResult{Sequence: i, RTT: time.Duration(i) * time.Millisecond})
+	}
+
+	for _, tc := range []struct {
+		name string
+		got  time.Duration
+		want time.Duration
+	}{
+		{"p50", model.liveStats.P50RTT, 50 * time.Millisecond},
+		{"p90", model.liveStats.P90RTT, 90 * time.Millisecond},
+		{"p99", model.liveStats.P99RTT, 99 * time.Millisecond},
+	} {
+		if diff := tc.got - tc.want; diff < -tc.want/100 || diff > tc.want/100 {
+			t.Errorf("Expected %s within 1%% of %v, got %v", tc.name, tc.want, tc.got)
+		}
+	}
+	if !strings.Contains(model.renderLiveStatistics(), "Percentiles: p50=") {
+		t.Error("Expected the percentiles in the live statistics")
+	}
+
+	histogram := model.renderRTTHistogram()
+	if !strings.Contains(histogram, "RTT Distribution") {
+		t.Error("Expected the RTT distribution title")
+	}
+	if got := strings.Count(histogram, "█"); got == 0 {
+		t.Errorf("Expected histogram bars, got:\n%s", histogram)
+	}
+
+	statistics := model.finalStatistics()
+	if statistics.P50RTT != model.liveStats.P50RTT || statistics.PacketsReceived != 100 {
+		t.Errorf("Expected the final statistics from the streamed ones, got %+v", statistics)
+	}
+}
+
+func TestHistogramBar(t *testing.T) {
+	if got := histogramBar(1, 2, 4); got != "██  " {
+		t.Errorf("Expected half the width, got %q", got)
+	}
+	if got := histogramBar(1, 16, 4); got != "▎   " {
+		t.Errorf("Expected a quarter cell, got %q", got)
+	}
+	if got := histogramBar(0, 0, 3); got != "   " {
+		t.Errorf("Expected an empty bar, got %q", got)
+	}
+}
+
+func TestModel_LatencyGraphScroll(t *testing.T) {
+	mockClient := network.NewMockClient()
+	mockLogger := &MockLogger{}
+	tool := NewTool(mockClient, mockLogger)
+	model := NewModel(tool)
+	model.SetSize(100, 40)
+	model.state = StateRunning
+	model.latencyGraph.MaxValues = 3
+
+	for i := 1; i <= 5; i++ {
+		model.updateLiveStats(domain.PingResult{RTT: time.Duration(i) * time.Millisecond})
+	}
+
+	wheel := func(button tea.MouseButton) {
+		model.Update(tea.MouseMsg{Action: tea.MouseActionPress, Button: button})
+	}
+	wheel(tea.MouseButtonWheelUp)
+	if model.latencyGraph.Values[2] != 4*time.Millisecond {
+		t.Errorf("Expected the graph to end at the previous ping, got %v", model.latencyGraph.Values)
+	}
+
+	// New pings keep the scrolled graph in place
+	model.updateLiveStats(domain.PingResult{RTT: 6 * time.Millisecond})
+	if model.latencyGraph.Values[2] != 4*time.Millisecond {
+		t.Errorf("Expected the scrolled graph to stay in place, got %v", model.latencyGraph.Values)
+	}
+	if !strings.Contains(model.renderLatencyGraph(), "2 before the latest") {
+		t.Error("Expected the title to show how far the graph is scrolled back")
+	}
+
+	// Scrolling stops at the oldest and the latest pings
+	for i := 0; i < 10; i++ {
+		wheel(tea.MouseButtonWheelUp)
+	}
+	if model.latencyGraph.Values[0] != time.Millisecond {
+		t.Errorf("Expected the graph to start at the first ping, got %v", model.latencyGraph.Values)
+	}
+	for i := 0; i < 10; i++ {
+		wheel(tea.MouseButtonWheelDown)
+	}
+	if model.latencyGraph.Offset != 0 || model.latencyGraph.Values[2] != 6*time.Millisecond {
+		t.Errorf("Expected the graph to follow the latest ping again, got %v", model.latencyGraph.Values)
+	}
+}
+
+// TestModel_ProgressBar tests the progress bar of a run with a count
+func TestModel_ProgressBar(t *testing.T) {
+	mockClient := network.NewMockClient()
+	mockLogger := &MockLogger{}
+	tool := NewTool(mockClient, mockLogger)
+	model := NewModel(tool)
+	model.SetSize(100, 40)
+	model.hostInput.SetValue("example.com")
+	model.totalPings = 4
+
+	model.Update(pingStartMsg{host: "example.com", count: 4, interval: time.Second})
+	header := model.renderRunningHeader()
+	if !strings.Contains(header, "ETA --") {
+		t.Errorf("Expected no estimate before the first reply, got %q", header)
+	}
+	if strings.Contains(header, "0/4") {
+		t.Error("Expected the bar to replace the counter")
+	}
+
+	_, cmd := model.Update(pingProgressMsg{completed: 2, result: domain.PingResult{RTT: 10 * time.Millisecond}})
+	if cmd == nil {
+		t.Fatal("Expected the bar to animate towards the new count")
+	}
+	model.Update(cmd())
+	if header := model.renderRunningHeader(); !strings.Contains(header, "ETA") || strings.Contains(header, "ETA --") {
+		t.Errorf("Expected an estimate after two replies, got %q", header)
+	}
+
+	// Continuous runs have no end to show
+	model.Update(pingStartMsg{host: "example.com", continuous: true})
+	if header := model.renderRunningHeader(); strings.Contains(header, "ETA") {
+		t.Errorf("Expected no bar in continuous mode, got %q", header)
+	}
+}
+
+// TestModel_PauseContinuous tests pausing and resuming a continuous ping
+func TestModel_PauseContinuous(t *testing.T) {
+	mockClient := network.NewMockClient()
+	mockLogger := &MockLogger{}
+	tool := NewTool(mockClient, mockLogger)
+	model := NewModel(tool)
+	model.SetSize(100, 40)
+	model.hostInput.SetValue("example.com")
+
+	model.Update(pingStartMsg{host: "example.com", continuous: true})
+	model.Update(pingProgressMsg{completed: 1, result: domain.PingResult{Sequence: 1, RTT: 10 * time.Millisecond}})
+	cancelled := false
+	model.cancelFunc = func() { cancelled = true }
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if !model.paused || !cancelled {
+		t.Fatal("Expected the pause to stop the run")
+	}
+	if header := model.renderRunningHeader(); !strings.Contains(header, "Paused") {
+		t.Errorf("Expected the header to show the pause, got %q", header)
+	}
+
+	// A reply in flight is still counted and the stopped run ends without
+	// ending the ping
+	model.Update(pingProgressMsg{completed: 2, result: domain.PingResult{Sequence: 2, RTT: 20 * time.Millisecond}})
+	model.Update(pingCompleteMsg{})
+	if model.state != StateRunning {
+		t.Fatalf("Expected the ping to stay running while paused, got state %v", model.state)
+	}
+	if model.liveStats.PacketsSent != 2 || len(model.latencyGraph.Values) != 2 {
+		t.Error("Expected the live statistics and graph to be kept")
+	}
+
+	elapsed := model.liveStats.ElapsedTime
+	model.startTime = model.startTime.Add(-time.Hour)
+	model.Update(tickMsg(time.Now()))
+	if model.liveStats.ElapsedTime != elapsed {
+		t.Error("Expected the elapsed time to stop while paused")
+	}
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if model.paused || cmd == nil {
+		t.Fatal("Expected the resume to start a new run")
+	}
+
+	// Stopping after the resume counts the results of both runs
+	model.Update(pingProgressMsg{completed: 1, result: domain.PingResult{Sequence: 3, RTT: 30 * time.Millisecond}})
+	model.Update(pingCompleteMsg{})
+	if model.state != StateResult {
+		t.Fatalf("Expected the ping to end, got state %v", model.state)
+	}
+	if model.statistics.PacketsSent != 3 {
+		t.Errorf("Expected statistics over 3 pings, got %d", model.statistics.PacketsSent)
+	}
+}
+
 // TestModel_PacketLossIndicator tests packet loss tracking
 func TestModel_PacketLossIndicator(t *testing.T) {
 	mockClient := network.NewMockClient()
@@ -507,4 +874,51 @@ func TestModel_MemoryCleanup(t *testing.T) {
 	if len(model.packetLoss.RecentResults) != 0 {
 		t.Errorf("Expected packet loss results to be cleared after reset, got %d", len(model.packetLoss.RecentResults))
 	}
-}
\ No newline at end of file
+}
+// TestTool_LiveModel tests the live screen started from parameters
+func TestTool_LiveModel(t *testing.T) {
+	tool := NewTool(network.NewMockClient(), &MockLogger{})
+
+	if _, err := tool.LiveModel(context.Background(), domain.NewPingParameters("", domain.PingOptions{Count: 1})); err == nil {
+		t.Error("Expected invalid parameters to be rejected")
+	}
+
+	params := domain.NewPingParameters("a.example, b.example", domain.PingOptions{
+		Count:      2,
+		Interval:   500 * time.Millisecond,
+		Timeout:    2 * time.Second,
+		PacketSize: 100,
+		TTL:        32,
+	})
+	params.Set("continuous", true)
+	live, err := tool.LiveModel(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	model := live.(*Model)
+	if model.countInput.Value() != "0" || model.intervalInput.Value() != "0.5" {
+		t.Errorf("Expected a continuous run every 0.5s, got count %q and interval %q", model.countInput.Value(), model.intervalInput.Value())
+	}
+
+	// The screen starts on Init with the given options, without its form
+	start, ok := model.Init()().(multiPingStartMsg)
+	if !ok {
+		t.Fatal("Expected Init to start pinging both hosts")
+	}
+	if len(start.hosts) != 2 || start.opts.Count != 0 || start.opts.PacketSize != 100 || start.opts.TTL != 32 || start.opts.Timeout != 2*time.Second {
+		t.Errorf("Unexpected run %+v", start)
+	}
+	model.Update(start)
+
+	// Esc hands control back instead of asking for new targets
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil {
+		t.Fatal("Expected esc to leave the live screen")
+	}
+	if _, ok := cmd().(domain.LiveExitMsg); !ok {
+		t.Error("Expected a LiveExitMsg")
+	}
+	if model.state == StateInput || model.cancelFunc != nil {
+		t.Error("Expected the run to stop without showing the form")
+	}
+}
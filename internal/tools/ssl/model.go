@@ -9,15 +9,19 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/clipboard"
+	"github.com/nettracex/nettracex-tui/internal/colors"
 	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/targetinput"
 	"github.com/nettracex/nettracex-tui/internal/tui"
+	"github.com/nettracex/nettracex-tui/internal/validate"
 )
 
 // Model represents the SSL certificate check TUI model
 type Model struct {
 	tool         *Tool
 	state        tui.ViewState
-	hostInput    textinput.Model
+	hostInput    targetinput.Model
 	portInput    textinput.Model
 	focusedInput int
 	result       *domain.SSLResult
@@ -25,15 +29,15 @@ type Model struct {
 	width        int
 	height       int
 	theme        domain.Theme
+	toast        clipboard.Toast
 }
 
 // NewModel creates a new SSL model
 func NewModel(tool *Tool) *Model {
-	hostInput := textinput.New()
-	hostInput.Placeholder = "Enter hostname (e.g., google.com)"
+	hostInput := targetinput.New("Enter hostname or URL (e.g., google.com, https://example.com:8443)")
+	hostInput.Check = validate.Host
+	hostInput.TakesPort = true
 	hostInput.Focus()
-	hostInput.CharLimit = 253
-	hostInput.Width = 50
 
 	portInput := textinput.New()
 	portInput.Placeholder = "443"
@@ -75,6 +79,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.state == tui.ViewStateInput {
 				return m, m.executeSSLCheck()
 			}
+		case "y":
+			if m.state == tui.ViewStateResult && m.result != nil {
+				return m, clipboard.CopyResult(*m.result)
+			}
 		case "tab", "shift+tab":
 			if m.state == tui.ViewStateInput {
 				if msg.String() == "tab" {
@@ -96,6 +104,13 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.error = msg.Error
 		return m, nil
 
+	case clipboard.CopiedMsg:
+		return m, m.toast.Show(msg.Status())
+
+	case clipboard.ToastExpiredMsg:
+		m.toast.Update(msg)
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -109,6 +124,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.focusedInput == 0 {
 			m.hostInput, cmd = m.hostInput.Update(msg)
 			cmds = append(cmds, cmd)
+			if port, ok := m.hostInput.TakePort(); ok {
+				m.portInput.SetValue(port)
+			}
 		} else {
 			m.portInput, cmd = m.portInput.Update(msg)
 			cmds = append(cmds, cmd)
@@ -172,6 +190,10 @@ func (m *Model) updateInputFocus() {
 
 // executeSSLCheck executes the SSL certificate check
 func (m *Model) executeSSLCheck() tea.Cmd {
+	// A port typed with the host, as in example.com:8443, fills the port field
+	if port, ok := m.hostInput.Split(); ok {
+		m.portInput.SetValue(port)
+	}
 	host := strings.TrimSpace(m.hostInput.Value())
 	portStr := strings.TrimSpace(m.portInput.Value())
 	
@@ -180,6 +202,12 @@ func (m *Model) executeSSLCheck() tea.Cmd {
 			return tui.SSLCheckErrorMsg{Error: fmt.Errorf("host is required")}
 		}
 	}
+	if err := m.hostInput.ValidationError(); err != nil {
+		return func() tea.Msg {
+			return tui.SSLCheckErrorMsg{Error: err}
+		}
+	}
+	m.hostInput.Remember()
 	
 	// Default port if not specified
 	if portStr == "" {
@@ -215,12 +243,12 @@ func (m *Model) renderInputView() string {
 	
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color(m.theme.GetColor("primary"))).
+		Foreground(colors.Adaptive(m.theme.GetColor("primary"))).
 		MarginBottom(1)
 	
 	labelStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color(m.theme.GetColor("text")))
+		Foreground(colors.Adaptive(m.theme.GetColor("text")))
 	
 	b.WriteString(titleStyle.Render("SSL Certificate Check"))
 	b.WriteString("\n\n")
@@ -229,6 +257,7 @@ func (m *Model) renderInputView() string {
 	b.WriteString(labelStyle.Render("Host:"))
 	b.WriteString("\n")
 	b.WriteString(m.hostInput.View())
+	b.WriteString(m.hostInput.Feedback())
 	b.WriteString("\n\n")
 	
 	// Port input
@@ -239,10 +268,10 @@ func (m *Model) renderInputView() string {
 	
 	// Instructions
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(m.theme.GetColor("muted"))).
+		Foreground(colors.Adaptive(m.theme.GetColor("muted"))).
 		Italic(true)
 	
-	b.WriteString(helpStyle.Render("Tab: Switch fields • Enter: Check certificate • Esc: Back • Ctrl+C: Quit"))
+	b.WriteString(helpStyle.Render("Tab: Switch fields • ↑/↓: Recent hosts • Enter: Check certificate • Esc: Back • Ctrl+C: Quit"))
 	
 	return b.String()
 }
@@ -251,7 +280,7 @@ func (m *Model) renderInputView() string {
 func (m *Model) renderLoadingView() string {
 	loadingStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color(m.theme.GetColor("primary")))
+		Foreground(colors.Adaptive(m.theme.GetColor("primary")))
 	
 	return loadingStyle.Render("Checking SSL certificate...")
 }
@@ -266,7 +295,7 @@ func (m *Model) renderResultView() string {
 	
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color(m.theme.GetColor("primary"))).
+		Foreground(colors.Adaptive(m.theme.GetColor("primary"))).
 		MarginBottom(1)
 	
 	b.WriteString(titleStyle.Render(fmt.Sprintf("SSL Certificate: %s:%d", m.result.Host, m.result.Port)))
@@ -275,10 +304,10 @@ func (m *Model) renderResultView() string {
 	// Certificate status
 	statusStyle := lipgloss.NewStyle().Bold(true)
 	if m.result.Valid {
-		statusStyle = statusStyle.Foreground(lipgloss.Color(m.theme.GetColor("success")))
+		statusStyle = statusStyle.Foreground(colors.Adaptive(m.theme.GetColor("success")))
 		b.WriteString(statusStyle.Render("✅ Certificate Valid"))
 	} else {
-		statusStyle = statusStyle.Foreground(lipgloss.Color(m.theme.GetColor("error")))
+		statusStyle = statusStyle.Foreground(colors.Adaptive(m.theme.GetColor("error")))
 		b.WriteString(statusStyle.Render("❌ Certificate Invalid"))
 	}
 	b.WriteString("\n\n")
@@ -287,8 +316,8 @@ func (m *Model) renderResultView() string {
 	if m.result.Certificate != nil {
 		cert := m.result.Certificate
 		
-		detailStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.theme.GetColor("text")))
-		labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(m.theme.GetColor("accent")))
+		detailStyle := lipgloss.NewStyle().Foreground(colors.Adaptive(m.theme.GetColor("text")))
+		labelStyle := lipgloss.NewStyle().Bold(true).Foreground(colors.Adaptive(m.theme.GetColor("accent")))
 		
 		b.WriteString(labelStyle.Render("Subject: "))
 		b.WriteString(detailStyle.Render(m.result.Subject))
@@ -310,9 +339,9 @@ func (m *Model) renderResultView() string {
 		daysUntilExpiry := int(cert.NotAfter.Sub(cert.NotBefore).Hours() / 24)
 		expiryStyle := detailStyle
 		if daysUntilExpiry <= 30 && daysUntilExpiry > 0 {
-			expiryStyle = expiryStyle.Foreground(lipgloss.Color(m.theme.GetColor("warning")))
+			expiryStyle = expiryStyle.Foreground(colors.Adaptive(m.theme.GetColor("warning")))
 		} else if daysUntilExpiry <= 0 {
-			expiryStyle = expiryStyle.Foreground(lipgloss.Color(m.theme.GetColor("error")))
+			expiryStyle = expiryStyle.Foreground(colors.Adaptive(m.theme.GetColor("error")))
 		}
 		
 		b.WriteString(labelStyle.Render("Days Until Expiry: "))
@@ -334,7 +363,7 @@ func (m *Model) renderResultView() string {
 				b.WriteString(labelStyle.Render("Key Size: "))
 				keyStyle := detailStyle
 				if keySize < 2048 {
-					keyStyle = keyStyle.Foreground(lipgloss.Color(m.theme.GetColor("warning")))
+					keyStyle = keyStyle.Foreground(colors.Adaptive(m.theme.GetColor("warning")))
 				}
 				b.WriteString(keyStyle.Render(fmt.Sprintf("%d bits", keySize)))
 				b.WriteString("\n")
@@ -373,12 +402,12 @@ func (m *Model) renderResultView() string {
 		b.WriteString("\n")
 		errorStyle := lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color(m.theme.GetColor("error")))
+			Foreground(colors.Adaptive(m.theme.GetColor("error")))
 		
 		b.WriteString(errorStyle.Render("Security Issues:"))
 		b.WriteString("\n")
 		
-		issueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.theme.GetColor("error")))
+		issueStyle := lipgloss.NewStyle().Foreground(colors.Adaptive(m.theme.GetColor("error")))
 		for _, err := range m.result.Errors {
 			b.WriteString(issueStyle.Render(fmt.Sprintf("  ⚠️  %s", err)))
 			b.WriteString("\n")
@@ -391,23 +420,27 @@ func (m *Model) renderResultView() string {
 		b.WriteString("\n")
 		recStyle := lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color(m.theme.GetColor("accent")))
+			Foreground(colors.Adaptive(m.theme.GetColor("accent")))
 		
 		b.WriteString(recStyle.Render("Recommendations:"))
 		b.WriteString("\n")
 		
 		for _, rec := range recommendations {
-			b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(m.theme.GetColor("text"))).Render(fmt.Sprintf("  • %s", rec)))
+			b.WriteString(lipgloss.NewStyle().Foreground(colors.Adaptive(m.theme.GetColor("text"))).Render(fmt.Sprintf("  • %s", rec)))
 			b.WriteString("\n")
 		}
 	}
 	
 	b.WriteString("\n")
+	if message := m.toast.Message(); message != "" {
+		b.WriteString(lipgloss.NewStyle().Foreground(colors.Adaptive(m.theme.GetColor("success"))).Render("✓ " + message))
+		b.WriteString("\n")
+	}
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(m.theme.GetColor("muted"))).
+		Foreground(colors.Adaptive(m.theme.GetColor("muted"))).
 		Italic(true)
 	
-	b.WriteString(helpStyle.Render("Esc: Back • Ctrl+C: Quit"))
+	b.WriteString(helpStyle.Render("y: Copy • Esc: Back • Ctrl+C: Quit"))
 	
 	return b.String()
 }
@@ -418,18 +451,18 @@ func (m *Model) renderErrorView() string {
 	
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color(m.theme.GetColor("error"))).
+		Foreground(colors.Adaptive(m.theme.GetColor("error"))).
 		MarginBottom(1)
 	
 	b.WriteString(titleStyle.Render("SSL Check Error"))
 	b.WriteString("\n\n")
 	
-	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.theme.GetColor("error")))
+	errorStyle := lipgloss.NewStyle().Foreground(colors.Adaptive(m.theme.GetColor("error")))
 	b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.error)))
 	b.WriteString("\n\n")
 	
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(m.theme.GetColor("muted"))).
+		Foreground(colors.Adaptive(m.theme.GetColor("muted"))).
 		Italic(true)
 	
 	b.WriteString(helpStyle.Render("Esc: Back • Ctrl+C: Quit"))
@@ -141,6 +141,26 @@ func TestSSLTUIModel_InputNavigation(t *testing.T) {
 	assert.Equal(t, 0, sslModel.focusedInput) // Should wrap back to host input
 }
 
+func TestSSLTUIModel_PastedURLFillsHostAndPort(t *testing.T) {
+	mockClient := network.NewMockClient()
+	mockLogger := &SimpleMockLogger{}
+	
+	tool := NewTool(mockClient, mockLogger)
+	model := NewModel(tool)
+	
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("https://example.com:8443/login"), Paste: true})
+	assert.Equal(t, "example.com", model.hostInput.Value())
+	assert.Equal(t, "8443", model.portInput.Value())
+	
+	// A port typed with the host fills the port field when checking
+	model.hostInput.SetValue("example.org:465")
+	assert.Contains(t, model.View(), "port 465 (SMTPS)")
+	model.executeSSLCheck()
+	assert.Equal(t, "example.org", model.hostInput.Value())
+	assert.Equal(t, "465", model.portInput.Value())
+	assert.Equal(t, []string{"example.org"}, model.hostInput.History())
+}
+
 func TestSSLTUIModel_SecurityIndicators_ValidCertificate(t *testing.T) {
 	mockClient := network.NewMockClient()
 	mockLogger := &SimpleMockLogger{}
@@ -0,0 +1,366 @@
+// Package sweep provides ping sweep TUI components
+package sweep
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/clipboard"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/targetinput"
+	"github.com/nettracex/nettracex-tui/internal/tui"
+	"github.com/nettracex/nettracex-tui/internal/validate"
+)
+
+// CompleteMsg is sent when a sweep finishes
+type CompleteMsg struct {
+	Result domain.SweepResult
+}
+
+// ErrorMsg is sent when a sweep fails
+type ErrorMsg struct {
+	Error error
+}
+
+// ExportedMsg is sent when the inventory has been written to disk
+type ExportedMsg struct {
+	Path  string
+	Error error
+}
+
+// Model represents the ping sweep TUI model
+type Model struct {
+	tool             *Tool
+	state            tui.ViewState
+	cidrInput        targetinput.Model
+	concurrencyInput textinput.Model
+	focusedInput     int
+	result           *domain.SweepResult
+	error            error
+	exportStatus     string
+	width            int
+	height           int
+	theme            domain.Theme
+	toast            clipboard.Toast
+}
+
+// NewModel creates a new ping sweep model
+func NewModel(tool *Tool) *Model {
+	cidrInput := targetinput.New("Enter CIDR range (e.g., 192.168.1.0/24)")
+	cidrInput.Check = validate.CIDR(MaxSweepHosts)
+	cidrInput.CharLimit = 49
+	cidrInput.Focus()
+
+	concurrencyInput := textinput.New()
+	concurrencyInput.Placeholder = fmt.Sprintf("%d", DefaultConcurrency)
+	concurrencyInput.CharLimit = 3
+	concurrencyInput.Width = 10
+
+	return &Model{
+		tool:             tool,
+		state:            tui.ViewStateInput,
+		cidrInput:        cidrInput,
+		concurrencyInput: concurrencyInput,
+		theme:            tui.NewDefaultTheme(),
+	}
+}
+
+// Init initializes the model
+func (m *Model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles messages and updates the model
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			if m.state == tui.ViewStateResult || m.state == tui.ViewStateError {
+				m.state = tui.ViewStateInput
+				m.result = nil
+				m.error = nil
+				m.exportStatus = ""
+				return m, nil
+			}
+		case "enter":
+			if m.state == tui.ViewStateInput {
+				return m, m.executeSweep()
+			}
+		case "s":
+			if m.state == tui.ViewStateResult {
+				return m, m.exportInventory()
+			}
+		case "y":
+			if m.state == tui.ViewStateResult && m.result != nil {
+				return m, clipboard.CopyResult(*m.result)
+			}
+		case "tab", "shift+tab":
+			if m.state == tui.ViewStateInput {
+				m.focusedInput = (m.focusedInput + 1) % 2
+				m.updateInputFocus()
+				return m, nil
+			}
+		}
+
+	case CompleteMsg:
+		m.state = tui.ViewStateResult
+		m.result = &msg.Result
+		return m, nil
+
+	case ErrorMsg:
+		m.state = tui.ViewStateError
+		m.error = msg.Error
+		return m, nil
+
+	case ExportedMsg:
+		if msg.Error != nil {
+			m.exportStatus = fmt.Sprintf("Export failed: %v", msg.Error)
+		} else {
+			m.exportStatus = fmt.Sprintf("Inventory saved to %s", msg.Path)
+		}
+		return m, nil
+
+	case clipboard.CopiedMsg:
+		return m, m.toast.Show(msg.Status())
+
+	case clipboard.ToastExpiredMsg:
+		m.toast.Update(msg)
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.SetSize(msg.Width, msg.Height)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	if m.state == tui.ViewStateInput {
+		if m.focusedInput == 0 {
+			m.cidrInput, cmd = m.cidrInput.Update(msg)
+		} else {
+			m.concurrencyInput, cmd = m.concurrencyInput.Update(msg)
+		}
+	}
+
+	return m, cmd
+}
+
+// View renders the model
+func (m *Model) View() string {
+	switch m.state {
+	case tui.ViewStateInput:
+		return m.renderInputView()
+	case tui.ViewStateLoading:
+		return m.style("primary").Bold(true).Render(fmt.Sprintf("Sweeping %s...", strings.TrimSpace(m.cidrInput.Value())))
+	case tui.ViewStateResult:
+		return m.renderResultView()
+	case tui.ViewStateError:
+		return m.style("error").Render(fmt.Sprintf("Error: %v", m.error)) + "\n\n" + m.renderHelp("Esc: Back • Ctrl+C: Quit")
+	default:
+		return "Unknown state"
+	}
+}
+
+// SetSize sets the model size
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	if width > 10 && width-10 < 50 {
+		m.cidrInput.Width = width - 10
+	}
+}
+
+// SetTheme sets the model theme
+func (m *Model) SetTheme(theme domain.Theme) {
+	m.theme = theme
+}
+
+// Focus focuses the model
+func (m *Model) Focus() {
+	if m.state == tui.ViewStateInput {
+		m.updateInputFocus()
+	}
+}
+
+// Blur blurs the model
+func (m *Model) Blur() {
+	m.cidrInput.Blur()
+	m.concurrencyInput.Blur()
+}
+
+// updateInputFocus updates the focus state of inputs
+func (m *Model) updateInputFocus() {
+	if m.focusedInput == 0 {
+		m.cidrInput.Focus()
+		m.concurrencyInput.Blur()
+	} else {
+		m.cidrInput.Blur()
+		m.concurrencyInput.Focus()
+	}
+}
+
+// executeSweep runs the ping sweep
+func (m *Model) executeSweep() tea.Cmd {
+	cidr := strings.TrimSpace(m.cidrInput.Value())
+	concurrency := strings.TrimSpace(m.concurrencyInput.Value())
+
+	if cidr == "" {
+		return func() tea.Msg {
+			return ErrorMsg{Error: fmt.Errorf("CIDR range is required")}
+		}
+	}
+	if err := m.cidrInput.ValidationError(); err != nil {
+		return func() tea.Msg {
+			return ErrorMsg{Error: err}
+		}
+	}
+	m.cidrInput.Remember()
+
+	m.state = tui.ViewStateLoading
+
+	return func() tea.Msg {
+		params := domain.NewParameters()
+		params.Set("cidr", cidr)
+		params.Set("concurrency", concurrency)
+
+		result, err := m.tool.Execute(context.Background(), params)
+		if err != nil {
+			return ErrorMsg{Error: err}
+		}
+
+		sweep, ok := result.Data().(domain.SweepResult)
+		if !ok {
+			return ErrorMsg{Error: fmt.Errorf("invalid result type")}
+		}
+
+		return CompleteMsg{Result: sweep}
+	}
+}
+
+// exportInventory writes the live hosts as a CSV inventory to the current directory
+func (m *Model) exportInventory() tea.Cmd {
+	if m.result == nil {
+		return nil
+	}
+	sweep := *m.result
+
+	return func() tea.Msg {
+		data, err := domain.NewResult(sweep).Export(domain.ExportFormatCSV)
+		if err != nil {
+			return ExportedMsg{Error: err}
+		}
+
+		path := filepath.Join(".", inventoryFileName(sweep))
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return ExportedMsg{Error: err}
+		}
+		return ExportedMsg{Path: path}
+	}
+}
+
+// inventoryFileName names the inventory file after the range and sweep time
+func inventoryFileName(sweep domain.SweepResult) string {
+	name := strings.NewReplacer("/", "_", ":", "-").Replace(sweep.CIDR)
+	return fmt.Sprintf("sweep-%s-%s.csv", name, sweep.Timestamp.Format("20060102-150405"))
+}
+
+// renderInputView renders the input form
+func (m *Model) renderInputView() string {
+	var b strings.Builder
+
+	b.WriteString(m.style("primary").Bold(true).Render("Ping Sweep"))
+	b.WriteString("\n\n")
+	b.WriteString(m.style("text").Bold(true).Render("CIDR range:"))
+	b.WriteString("\n")
+	b.WriteString(m.cidrInput.View())
+	b.WriteString(m.cidrInput.Feedback())
+	b.WriteString("\n\n")
+	b.WriteString(m.style("text").Bold(true).Render("Concurrency:"))
+	b.WriteString("\n")
+	b.WriteString(m.concurrencyInput.View())
+	b.WriteString("\n\n")
+	b.WriteString(m.renderHelp("Tab: Switch fields • ↑/↓: Recent ranges • Enter: Sweep • Esc: Back • Ctrl+C: Quit"))
+
+	return b.String()
+}
+
+// renderResultView renders the table of live hosts
+func (m *Model) renderResultView() string {
+	if m.result == nil {
+		return "No results available"
+	}
+
+	var b strings.Builder
+
+	b.WriteString(m.style("primary").Bold(true).Render(fmt.Sprintf("Sweep: %s", m.result.CIDR)))
+	b.WriteString("\n")
+	b.WriteString(m.style("muted").Render(fmt.Sprintf("%d of %d addresses alive in %v",
+		len(m.result.Hosts), m.result.Scanned, m.result.Duration.Round(time.Millisecond))))
+	b.WriteString("\n\n")
+
+	if len(m.result.Hosts) == 0 {
+		b.WriteString(m.style("warning").Render("No hosts answered"))
+		b.WriteString("\n")
+	} else {
+		b.WriteString(m.style("accent").Bold(true).Render(fmt.Sprintf("%-39s %-30s %-17s %-18s %10s", "IP", "Hostname", "MAC", "Vendor", "RTT")))
+		b.WriteString("\n")
+		for _, host := range m.result.Hosts {
+			line := fmt.Sprintf("%-39s %-30s %-17s %-18s %10s",
+				host.IP, truncate(orDash(host.Hostname), 30), orDash(host.MAC), truncate(orDash(host.Vendor), 18),
+				host.RTT.Round(10*time.Microsecond))
+			b.WriteString(m.style("text").Render(line))
+			b.WriteString("\n")
+		}
+	}
+
+	if m.exportStatus != "" {
+		b.WriteString("\n")
+		b.WriteString(m.style("success").Render(m.exportStatus))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	if message := m.toast.Message(); message != "" {
+		b.WriteString(m.style("success").Render("✓ " + message))
+		b.WriteString("\n")
+	}
+	b.WriteString(m.renderHelp("s: Save inventory (CSV) • y: Copy • Esc: Back • Ctrl+C: Quit"))
+
+	return b.String()
+}
+
+// renderHelp renders a help line
+func (m *Model) renderHelp(text string) string {
+	return m.style("muted").Italic(true).Render(text)
+}
+
+// style returns a style using the named theme color
+func (m *Model) style(color string) lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(colors.Adaptive(m.theme.GetColor(color)))
+}
+
+// orDash returns "-" for empty table cells
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// truncate shortens s to at most width characters
+func truncate(s string, width int) string {
+	if len(s) <= width {
+		return s
+	}
+	return s[:width-1] + "…"
+}
@@ -0,0 +1,366 @@
+// Package sweep provides ping sweep host discovery over CIDR ranges
+package sweep
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+const (
+	// MaxSweepHosts bounds the number of addresses a single sweep probes
+	MaxSweepHosts = 1024
+
+	// DefaultConcurrency is the number of addresses probed at the same time
+	DefaultConcurrency = 32
+	// MaxConcurrency bounds the user supplied concurrency
+	MaxConcurrency = 256
+
+	// DefaultProbeTimeout is how long each address is given to answer
+	DefaultProbeTimeout = time.Second
+)
+
+// Tool implements the DiagnosticTool interface for ping sweeps
+type Tool struct {
+	client    domain.NetworkClient
+	logger    domain.Logger
+	neighbors NeighborTable
+}
+
+// NewTool creates a new ping sweep tool
+func NewTool(client domain.NetworkClient, logger domain.Logger) *Tool {
+	return &Tool{
+		client:    client,
+		logger:    logger,
+		neighbors: systemNeighbors,
+	}
+}
+
+// SetNeighborTable replaces the source of MAC addresses used for vendor lookup
+func (t *Tool) SetNeighborTable(neighbors NeighborTable) {
+	t.neighbors = neighbors
+}
+
+// Name returns the tool name
+func (t *Tool) Name() string {
+	return "sweep"
+}
+
+// Description returns the tool description
+func (t *Tool) Description() string {
+	return "Discover live hosts in a CIDR range with reverse DNS and vendor details"
+}
+
+// Execute pings every address in the range and returns the hosts that answered
+func (t *Tool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	t.logger.Info("Executing ping sweep", "tool", t.Name())
+
+	if err := t.Validate(params); err != nil {
+		return nil, &domain.NetTraceError{
+			Type:      domain.ErrorTypeValidation,
+			Message:   "Sweep parameter validation failed",
+			Cause:     err,
+			Context:   map[string]interface{}{"params": params.ToMap()},
+			Timestamp: time.Now(),
+			Code:      "SWEEP_VALIDATION_FAILED",
+		}
+	}
+
+	cidr := params.Get("cidr").(string)
+	addresses := params.Get("targets").([]string)
+	concurrency := params.Get("concurrency").(int)
+	timeout := params.Get("timeout").(time.Duration)
+	resolve := params.Get("resolve").(bool)
+
+	start := time.Now()
+	hosts := t.sweep(ctx, addresses, concurrency, timeout)
+
+	if resolve {
+		t.resolveNames(ctx, hosts, concurrency)
+	}
+	t.annotateVendors(hosts)
+
+	sweep := domain.SweepResult{
+		CIDR:      cidr,
+		Scanned:   len(addresses),
+		Hosts:     hosts,
+		Duration:  time.Since(start),
+		Timestamp: time.Now(),
+	}
+
+	result := domain.NewResult(sweep)
+	result.SetMetadata("tool", t.Name())
+	result.SetMetadata("cidr", cidr)
+	result.SetMetadata("scanned", sweep.Scanned)
+	result.SetMetadata("alive", len(hosts))
+	result.SetMetadata("timestamp", sweep.Timestamp)
+
+	t.logger.Info("Ping sweep completed", "cidr", cidr, "scanned", sweep.Scanned, "alive", len(hosts))
+	return result, nil
+}
+
+// Validate validates the parameters for ping sweeps
+func (t *Tool) Validate(params domain.Parameters) error {
+	cidr, ok := params.Get("cidr").(string)
+	if !ok || strings.TrimSpace(cidr) == "" {
+		return fmt.Errorf("cidr parameter is required")
+	}
+	cidr = strings.TrimSpace(cidr)
+
+	addresses, err := ExpandCIDR(cidr)
+	if err != nil {
+		return err
+	}
+
+	concurrency, err := intParam(params.Get("concurrency"), DefaultConcurrency)
+	if err != nil {
+		return fmt.Errorf("concurrency parameter must be a valid integer")
+	}
+	if concurrency <= 0 || concurrency > MaxConcurrency {
+		return fmt.Errorf("concurrency must be between 1 and %d", MaxConcurrency)
+	}
+
+	timeout := DefaultProbeTimeout
+	switch v := params.Get("timeout").(type) {
+	case nil:
+	case time.Duration:
+		timeout = v
+	default:
+		return fmt.Errorf("timeout parameter must be a duration")
+	}
+	if timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+
+	resolve := true
+	if v, ok := params.Get("resolve").(bool); ok {
+		resolve = v
+	}
+
+	// Normalise parameters; the expanded targets let the policy guard check
+	// every address rather than the range as a whole
+	targets := make([]string, len(addresses))
+	for i, ip := range addresses {
+		targets[i] = ip.String()
+	}
+	params.Set("cidr", cidr)
+	params.Set("targets", targets)
+	params.Set("concurrency", concurrency)
+	params.Set("timeout", timeout)
+	params.Set("resolve", resolve)
+
+	return nil
+}
+
+// GetModel returns the Bubble Tea model for the sweep tool
+func (t *Tool) GetModel() tea.Model {
+	return NewModel(t)
+}
+
+// sweep probes every address with bounded concurrency and returns the hosts
+// that answered, in address order
+func (t *Tool) sweep(ctx context.Context, addresses []string, concurrency int, timeout time.Duration) []domain.SweepHost {
+	opts := domain.PingOptions{
+		Count:      1,
+		Timeout:    timeout,
+		PacketSize: 64,
+		TTL:        64,
+		Mode:       domain.PingModeNormal,
+	}
+
+	var mu sync.Mutex
+	var hosts []domain.SweepHost
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, address := range addresses {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return sortHosts(hosts)
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(address string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			rtt, alive := t.probe(ctx, address, opts)
+			if !alive {
+				return
+			}
+			mu.Lock()
+			hosts = append(hosts, domain.SweepHost{IP: net.ParseIP(address), RTT: rtt})
+			mu.Unlock()
+		}(address)
+	}
+	wg.Wait()
+
+	return sortHosts(hosts)
+}
+
+// probe sends a single ping to address and reports whether it answered
+func (t *Tool) probe(ctx context.Context, address string, opts domain.PingOptions) (time.Duration, bool) {
+	resultChan, err := t.client.Ping(ctx, address, opts)
+	if err != nil {
+		t.logger.Debug("Sweep probe failed", "address", address, "error", err)
+		return 0, false
+	}
+
+	var rtt time.Duration
+	alive := false
+	for result := range resultChan {
+		if answered(result) && !alive {
+			rtt = result.RTT
+			alive = true
+		}
+	}
+	return rtt, alive
+}
+
+// answered reports whether a probe got a reply. A refused connection still
+// proves the host is up, since only a live host sends the reset.
+func answered(result domain.PingResult) bool {
+	return result.Error == nil || errors.Is(result.Error, syscall.ECONNREFUSED)
+}
+
+// resolveNames fills in reverse DNS names when the client supports them
+func (t *Tool) resolveNames(ctx context.Context, hosts []domain.SweepHost, concurrency int) {
+	resolver, ok := t.client.(domain.ReverseResolver)
+	if !ok {
+		return
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range hosts {
+		wg.Add(1)
+		go func(host *domain.SweepHost) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			names, err := resolver.ReverseLookup(ctx, host.IP)
+			if err != nil || len(names) == 0 {
+				return
+			}
+			host.Hostname = names[0]
+		}(&hosts[i])
+	}
+	wg.Wait()
+}
+
+// annotateVendors adds MAC addresses and vendors for hosts on local links.
+// The neighbour table is read after probing since the probes populate it.
+func (t *Tool) annotateVendors(hosts []domain.SweepHost) {
+	if t.neighbors == nil || len(hosts) == 0 {
+		return
+	}
+
+	neighbors := t.neighbors()
+	for i := range hosts {
+		mac, ok := neighbors[hosts[i].IP.String()]
+		if !ok {
+			continue
+		}
+		hosts[i].MAC = mac
+		hosts[i].Vendor = LookupVendor(mac)
+	}
+}
+
+// ExpandCIDR returns the host addresses in cidr. For IPv4 prefixes shorter
+// than /31 the network and broadcast addresses are excluded. A bare address
+// is treated as a single host.
+func ExpandCIDR(cidr string) ([]net.IP, error) {
+	if !strings.Contains(cidr, "/") {
+		ip := net.ParseIP(cidr)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid CIDR range: %s", cidr)
+		}
+		return []net.IP{ip}, nil
+	}
+
+	ip, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CIDR range: %s", cidr)
+	}
+
+	// Allow for the network and broadcast addresses excluded below
+	ones, bits := network.Mask.Size()
+	size := new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+	if size.Cmp(big.NewInt(MaxSweepHosts+2)) > 0 {
+		return nil, fmt.Errorf("range %s has too many addresses (maximum %d)", cidr, MaxSweepHosts)
+	}
+
+	count := int(size.Int64())
+	first, last := 0, count
+	if ip.To4() != nil && bits-ones > 1 {
+		first, last = 1, count-1
+	}
+
+	base := network.IP
+	if v4 := base.To4(); v4 != nil {
+		start := binary.BigEndian.Uint32(v4)
+		addresses := make([]net.IP, 0, last-first)
+		for i := first; i < last; i++ {
+			address := make(net.IP, net.IPv4len)
+			binary.BigEndian.PutUint32(address, start+uint32(i))
+			addresses = append(addresses, address)
+		}
+		return addresses, nil
+	}
+
+	start := new(big.Int).SetBytes(base.To16())
+	addresses := make([]net.IP, 0, last-first)
+	for i := first; i < last; i++ {
+		value := new(big.Int).Add(start, big.NewInt(int64(i))).Bytes()
+		address := make(net.IP, net.IPv6len)
+		copy(address[net.IPv6len-len(value):], value)
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
+
+// sortHosts orders hosts by address
+func sortHosts(hosts []domain.SweepHost) []domain.SweepHost {
+	sort.Slice(hosts, func(i, j int) bool {
+		a, b := hosts[i].IP.To16(), hosts[j].IP.To16()
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+	return hosts
+}
+
+// intParam reads an integer parameter given as an int or a string
+func intParam(value interface{}, fallback int) (int, error) {
+	switch v := value.(type) {
+	case nil:
+		return fallback, nil
+	case int:
+		return v, nil
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return fallback, nil
+		}
+		return strconv.Atoi(strings.TrimSpace(v))
+	default:
+		return 0, fmt.Errorf("unsupported type %T", value)
+	}
+}
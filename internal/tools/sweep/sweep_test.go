@@ -0,0 +1,340 @@
+// Package sweep provides ping sweep functionality tests
+package sweep
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/clipboard"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/network"
+	"github.com/nettracex/nettracex-tui/internal/tui"
+)
+
+// testLogger implements domain.Logger and discards all output
+type testLogger struct{}
+
+func (testLogger) Debug(msg string, fields ...interface{}) {}
+func (testLogger) Info(msg string, fields ...interface{})  {}
+func (testLogger) Warn(msg string, fields ...interface{})  {}
+func (testLogger) Error(msg string, fields ...interface{}) {}
+func (testLogger) Fatal(msg string, fields ...interface{}) {}
+
+// newSweepTool creates a tool over 10.0.0.0/29 where .1 and .3 answer, .5
+// refuses the probe and every other address times out
+func newSweepTool() (*Tool, *network.MockClient) {
+	client := network.NewMockClient()
+	for i := 1; i <= 6; i++ {
+		address := fmt.Sprintf("10.0.0.%d", i)
+		client.SetPingResponse(address, []domain.PingResult{{Sequence: 1, Error: errors.New("timeout")}})
+	}
+	client.SetPingResponse("10.0.0.1", []domain.PingResult{{Sequence: 1, RTT: 2 * time.Millisecond}})
+	client.SetPingResponse("10.0.0.3", []domain.PingResult{{Sequence: 1, RTT: 5 * time.Millisecond}})
+	client.SetPingResponse("10.0.0.5", []domain.PingResult{{Sequence: 1, RTT: 7 * time.Millisecond, Error: fmt.Errorf("dial: %w", syscall.ECONNREFUSED)}})
+	client.SetReverseLookup("10.0.0.1", []string{"router.lan"})
+
+	tool := NewTool(client, testLogger{})
+	tool.SetNeighborTable(func() map[string]string {
+		return map[string]string{"10.0.0.1": "b8:27:eb:12:34:56"}
+	})
+	return tool, client
+}
+
+func TestTool_NameAndDescription(t *testing.T) {
+	tool := NewTool(network.NewMockClient(), testLogger{})
+
+	if tool.Name() != "sweep" {
+		t.Errorf("Expected name 'sweep', got %s", tool.Name())
+	}
+	if tool.Description() == "" {
+		t.Error("Expected non-empty description")
+	}
+	if tool.GetModel() == nil {
+		t.Error("Expected a model")
+	}
+}
+
+func TestExpandCIDR(t *testing.T) {
+	tests := []struct {
+		name      string
+		cidr      string
+		expected  []string
+		wantCount int
+		wantErr   bool
+	}{
+		{name: "slash 30", cidr: "192.168.1.0/30", expected: []string{"192.168.1.1", "192.168.1.2"}},
+		{name: "slash 31", cidr: "192.168.1.0/31", expected: []string{"192.168.1.0", "192.168.1.1"}},
+		{name: "slash 32", cidr: "192.168.1.7/32", expected: []string{"192.168.1.7"}},
+		{name: "bare address", cidr: "10.1.2.3", expected: []string{"10.1.2.3"}},
+		{name: "host bits ignored", cidr: "192.168.1.77/30", expected: []string{"192.168.1.77", "192.168.1.78"}},
+		{name: "slash 24", cidr: "192.168.1.0/24", wantCount: 254},
+		{name: "largest allowed", cidr: "10.0.0.0/22", wantCount: 1022},
+		{name: "ipv6", cidr: "2001:db8::/126", expected: []string{"2001:db8::", "2001:db8::1", "2001:db8::2", "2001:db8::3"}},
+		{name: "too large", cidr: "10.0.0.0/16", wantErr: true},
+		{name: "invalid", cidr: "not-a-range", wantErr: true},
+		{name: "invalid prefix", cidr: "10.0.0.0/33", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addresses, err := ExpandCIDR(tt.cidr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExpandCIDR(%q) error = %v, wantErr %v", tt.cidr, err, tt.wantErr)
+			}
+			if tt.wantCount > 0 && len(addresses) != tt.wantCount {
+				t.Errorf("Expected %d addresses, got %d", tt.wantCount, len(addresses))
+			}
+			if tt.expected == nil {
+				return
+			}
+			var got []string
+			for _, address := range addresses {
+				got = append(got, address.String())
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("ExpandCIDR(%q) = %v, expected %v", tt.cidr, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTool_Validate(t *testing.T) {
+	tool := NewTool(network.NewMockClient(), testLogger{})
+
+	tests := []struct {
+		name        string
+		values      map[string]interface{}
+		expectError bool
+	}{
+		{name: "defaults", values: map[string]interface{}{"cidr": "10.0.0.0/30"}},
+		{name: "string concurrency", values: map[string]interface{}{"cidr": "10.0.0.0/30", "concurrency": "8"}},
+		{name: "missing cidr", values: map[string]interface{}{}, expectError: true},
+		{name: "empty cidr", values: map[string]interface{}{"cidr": "  "}, expectError: true},
+		{name: "invalid cidr", values: map[string]interface{}{"cidr": "10.0.0.0/40"}, expectError: true},
+		{name: "zero concurrency", values: map[string]interface{}{"cidr": "10.0.0.0/30", "concurrency": 0}, expectError: true},
+		{name: "excessive concurrency", values: map[string]interface{}{"cidr": "10.0.0.0/30", "concurrency": MaxConcurrency + 1}, expectError: true},
+		{name: "non-numeric concurrency", values: map[string]interface{}{"cidr": "10.0.0.0/30", "concurrency": "many"}, expectError: true},
+		{name: "negative timeout", values: map[string]interface{}{"cidr": "10.0.0.0/30", "timeout": -time.Second}, expectError: true},
+		{name: "non-duration timeout", values: map[string]interface{}{"cidr": "10.0.0.0/30", "timeout": "1s"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := domain.NewParameters()
+			for key, value := range tt.values {
+				params.Set(key, value)
+			}
+
+			err := tool.Validate(params)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("Validate() error = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestTool_Validate_Normalizes(t *testing.T) {
+	tool := NewTool(network.NewMockClient(), testLogger{})
+
+	params := domain.NewParameters()
+	params.Set("cidr", " 10.0.0.0/30 ")
+	params.Set("concurrency", "8")
+	if err := tool.Validate(params); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cidr := params.Get("cidr"); cidr != "10.0.0.0/30" {
+		t.Errorf("Expected trimmed cidr, got %v", cidr)
+	}
+	if concurrency := params.Get("concurrency"); concurrency != 8 {
+		t.Errorf("Expected concurrency 8, got %v", concurrency)
+	}
+	if timeout := params.Get("timeout"); timeout != DefaultProbeTimeout {
+		t.Errorf("Expected default timeout, got %v", timeout)
+	}
+	if resolve := params.Get("resolve"); resolve != true {
+		t.Errorf("Expected reverse DNS enabled by default, got %v", resolve)
+	}
+	targets, ok := params.Get("targets").([]string)
+	if !ok || strings.Join(targets, ",") != "10.0.0.1,10.0.0.2" {
+		t.Errorf("Expected expanded targets, got %v", params.Get("targets"))
+	}
+}
+
+func TestTool_Execute(t *testing.T) {
+	tool, _ := newSweepTool()
+
+	params := domain.NewParameters()
+	params.Set("cidr", "10.0.0.0/29")
+	params.Set("concurrency", 3)
+
+	result, err := tool.Execute(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	sweep, ok := result.Data().(domain.SweepResult)
+	if !ok {
+		t.Fatalf("Expected SweepResult, got %T", result.Data())
+	}
+	if sweep.CIDR != "10.0.0.0/29" || sweep.Scanned != 6 {
+		t.Errorf("Expected 6 addresses scanned in 10.0.0.0/29, got %d in %s", sweep.Scanned, sweep.CIDR)
+	}
+	if len(sweep.Hosts) != 3 {
+		t.Fatalf("Expected 3 live hosts, got %d: %+v", len(sweep.Hosts), sweep.Hosts)
+	}
+
+	router, second, refused := sweep.Hosts[0], sweep.Hosts[1], sweep.Hosts[2]
+	if router.IP.String() != "10.0.0.1" || router.RTT != 2*time.Millisecond {
+		t.Errorf("Unexpected first host: %+v", router)
+	}
+	if router.Hostname != "router.lan" {
+		t.Errorf("Expected reverse DNS name router.lan, got %q", router.Hostname)
+	}
+	if router.MAC != "b8:27:eb:12:34:56" || router.Vendor != "Raspberry Pi" {
+		t.Errorf("Expected MAC and vendor from the neighbour table, got %q %q", router.MAC, router.Vendor)
+	}
+	if second.IP.String() != "10.0.0.3" || second.Hostname != "" || second.Vendor != "" {
+		t.Errorf("Unexpected second host: %+v", second)
+	}
+	if refused.IP.String() != "10.0.0.5" {
+		t.Errorf("Expected a refused probe to count as alive, got %+v", refused)
+	}
+
+	if alive := result.Metadata()["alive"]; alive != 3 {
+		t.Errorf("Expected alive metadata 3, got %v", alive)
+	}
+}
+
+func TestTool_Execute_WithoutReverseDNS(t *testing.T) {
+	tool, client := newSweepTool()
+
+	params := domain.NewParameters()
+	params.Set("cidr", "10.0.0.1")
+	params.Set("resolve", false)
+
+	result, err := tool.Execute(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	sweep := result.Data().(domain.SweepResult)
+	if len(sweep.Hosts) != 1 || sweep.Hosts[0].Hostname != "" {
+		t.Errorf("Expected one host without a name, got %+v", sweep.Hosts)
+	}
+	if calls := client.GetPingCalls(); len(calls) != 1 {
+		t.Errorf("Expected 1 ping call, got %d", len(calls))
+	}
+}
+
+func TestTool_Execute_InvalidRange(t *testing.T) {
+	tool := NewTool(network.NewMockClient(), testLogger{})
+
+	params := domain.NewParameters()
+	params.Set("cidr", "10.0.0.0/8")
+
+	_, err := tool.Execute(context.Background(), params)
+	var netErr *domain.NetTraceError
+	if !errors.As(err, &netErr) || netErr.Code != "SWEEP_VALIDATION_FAILED" {
+		t.Errorf("Expected SWEEP_VALIDATION_FAILED, got %v", err)
+	}
+}
+
+func TestLookupVendor(t *testing.T) {
+	tests := []struct {
+		mac      string
+		expected string
+	}{
+		{"b8:27:eb:12:34:56", "Raspberry Pi"},
+		{"00-50-56-AA-BB-CC", "VMware"},
+		{"12:34:56:78:9a:bc", ""},
+		{"b8:27", ""},
+	}
+
+	for _, tt := range tests {
+		if vendor := LookupVendor(tt.mac); vendor != tt.expected {
+			t.Errorf("LookupVendor(%q) = %q, expected %q", tt.mac, vendor, tt.expected)
+		}
+	}
+}
+
+func TestParseARPTable(t *testing.T) {
+	table := `IP address       HW type     Flags       HW address            Mask     Device
+192.168.1.1      0x1         0x2         B8:27:EB:12:34:56     *        eth0
+192.168.1.20     0x1         0x0         00:00:00:00:00:00     *        eth0
+192.168.1.30     0x1         0x2         08:00:27:aa:bb:cc     *        eth0
+`
+	neighbors := parseARPTable(bufio.NewScanner(strings.NewReader(table)))
+
+	if len(neighbors) != 2 {
+		t.Fatalf("Expected 2 resolved neighbours, got %d: %v", len(neighbors), neighbors)
+	}
+	if mac := neighbors["192.168.1.1"]; mac != "b8:27:eb:12:34:56" {
+		t.Errorf("Expected lower-cased MAC, got %q", mac)
+	}
+	if _, ok := neighbors["192.168.1.20"]; ok {
+		t.Error("Expected incomplete entry to be skipped")
+	}
+}
+
+func TestModel_SweepAndExport(t *testing.T) {
+	tool, _ := newSweepTool()
+	model := NewModel(tool)
+	model.cidrInput.SetValue("10.0.0.0/29")
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.state != tui.ViewStateLoading || cmd == nil {
+		t.Fatalf("Expected loading state with a command, got %v", model.state)
+	}
+
+	model.Update(cmd())
+	if model.state != tui.ViewStateResult {
+		t.Fatalf("Expected result state, got %v (error %v)", model.state, model.error)
+	}
+
+	view := model.View()
+	for _, expected := range []string{"10.0.0.1", "router.lan", "Raspberry Pi", "3 of 6 addresses alive"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected view to contain %q", expected)
+		}
+	}
+
+	if name := inventoryFileName(*model.result); !strings.HasPrefix(name, "sweep-10.0.0.0_29-") || !strings.HasSuffix(name, ".csv") {
+		t.Errorf("Unexpected inventory file name %q", name)
+	}
+
+	model.Update(ExportedMsg{Path: "sweep.csv"})
+	if !strings.Contains(model.View(), "Inventory saved to sweep.csv") {
+		t.Error("Expected export confirmation in the view")
+	}
+
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}); cmd == nil {
+		t.Error("Expected y to copy the result")
+	}
+	model.Update(clipboard.CopiedMsg{Description: "result"})
+	if !strings.Contains(model.View(), "Copied result to clipboard") {
+		t.Error("Expected copy confirmation in the view")
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.state != tui.ViewStateInput || model.result != nil || model.exportStatus != "" {
+		t.Errorf("Expected esc to return to the input form, got state %v", model.state)
+	}
+}
+
+func TestModel_EmptyRange(t *testing.T) {
+	model := NewModel(NewTool(network.NewMockClient(), testLogger{}))
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model.Update(cmd())
+	if model.state != tui.ViewStateError {
+		t.Errorf("Expected error state for an empty range, got %v", model.state)
+	}
+}
@@ -0,0 +1,96 @@
+// Package sweep provides MAC address discovery and vendor lookup for swept hosts
+package sweep
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// arpTablePath is the Linux kernel neighbour table
+const arpTablePath = "/proc/net/arp"
+
+// ouiVendors maps IEEE OUI prefixes to vendor names for common network equipment
+// and device manufacturers. It is intentionally small; unknown prefixes are
+// reported without a vendor.
+var ouiVendors = map[string]string{
+	"00:00:0C": "Cisco",
+	"00:05:69": "VMware",
+	"00:0C:29": "VMware",
+	"00:1A:11": "Google",
+	"00:1B:21": "Intel",
+	"00:1C:42": "Parallels",
+	"00:15:5D": "Microsoft Hyper-V",
+	"00:16:3E": "Xen",
+	"00:17:88": "Philips Hue",
+	"00:50:56": "VMware",
+	"00:1D:D8": "Microsoft",
+	"00:24:D4": "Freebox",
+	"08:00:27": "VirtualBox",
+	"18:E8:29": "Ubiquiti",
+	"24:A4:3C": "Ubiquiti",
+	"28:CF:E9": "Apple",
+	"3C:22:FB": "Apple",
+	"44:D9:E7": "Ubiquiti",
+	"52:54:00": "QEMU/KVM",
+	"60:A4:4C": "ASUSTek",
+	"74:83:C2": "Ubiquiti",
+	"78:8A:20": "Ubiquiti",
+	"80:2A:A8": "Ubiquiti",
+	"94:10:3E": "Belkin",
+	"A4:5E:60": "Apple",
+	"B8:27:EB": "Raspberry Pi",
+	"B8:AE:ED": "Elitegroup",
+	"C0:56:27": "Belkin",
+	"D8:3A:DD": "Raspberry Pi",
+	"DC:A6:32": "Raspberry Pi",
+	"E4:5F:01": "Raspberry Pi",
+	"F0:9F:C2": "Ubiquiti",
+	"F4:F5:D8": "Google",
+	"FC:EC:DA": "Ubiquiti",
+}
+
+// LookupVendor returns the vendor registered for the OUI of mac, or "" if unknown
+func LookupVendor(mac string) string {
+	mac = strings.ToUpper(strings.ReplaceAll(mac, "-", ":"))
+	if len(mac) < 8 {
+		return ""
+	}
+	return ouiVendors[mac[:8]]
+}
+
+// NeighborTable returns the MAC address known for each IP address on local links
+type NeighborTable func() map[string]string
+
+// systemNeighbors reads the kernel ARP table. MAC addresses are only known for
+// hosts on directly attached networks, and only on Linux; elsewhere the table
+// is empty and vendors are not reported.
+func systemNeighbors() map[string]string {
+	file, err := os.Open(arpTablePath)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	return parseARPTable(bufio.NewScanner(file))
+}
+
+// parseARPTable parses /proc/net/arp:
+//
+//	IP address       HW type     Flags       HW address            Mask     Device
+//	192.168.1.1      0x1         0x2         aa:bb:cc:dd:ee:ff     *        eth0
+func parseARPTable(scanner *bufio.Scanner) map[string]string {
+	neighbors := make(map[string]string)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[0] == "IP" {
+			continue
+		}
+		// Flags 0x0 marks an incomplete entry with no resolved address
+		if fields[2] == "0x0" || fields[3] == "00:00:00:00:00:00" {
+			continue
+		}
+		neighbors[fields[0]] = strings.ToLower(fields[3])
+	}
+	return neighbors
+}
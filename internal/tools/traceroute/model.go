@@ -10,7 +10,10 @@ import (
 	"github.com/charmbracelet/bubbles/progress"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/clipboard"
+	"github.com/nettracex/nettracex-tui/internal/colors"
 	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/progressbar"
 	"github.com/nettracex/nettracex-tui/internal/tui"
 )
 
@@ -24,9 +27,11 @@ type Model struct {
 	packetSize  int
 	queries     int
 	ipv6        bool
+	protocol    domain.TraceProtocol
+	port        int
 	
 	// UI components
-	progress    progress.Model
+	progress    *progressbar.Bar
 	table       *tui.TableModel
 	
 	// Results
@@ -47,13 +52,37 @@ type Model struct {
 	lastUpdate  time.Time
 	updateCount int
 	
+	// Continuous mode re-runs the trace and tracks path changes
+	continuous     bool
+	interval       time.Duration
+	run            int
+	previousHops   []domain.TraceHop
+	lastChanges    []PathChange
+	changeLog      []PathChangeEvent
+	paused         bool
+	
 	// Error handling
 	err         error
 	
+	// Toast confirms copying the hops to the clipboard
+	toast       clipboard.Toast
+	
 	// Styles
 	styles      ModelStyles
+
+	// parent is the context every run derives from. live is set for a
+	// model opened by Tool.LiveModel: it starts on Init and leaves with
+	// domain.LiveExitMsg instead of returning to its input form.
+	parent context.Context
+	live   bool
 }
 
+// Continuous mode defaults
+const (
+	DefaultContinuousInterval = 30 * time.Second
+	maxChangeLogEntries       = 10
+)
+
 // ModelState represents the current state of the model
 type ModelState int
 
@@ -79,9 +108,6 @@ type ModelStyles struct {
 
 // NewModel creates a new traceroute model
 func NewModel(tool *Tool) *Model {
-	// Create progress bar
-	p := progress.New(progress.WithDefaultGradient())
-
 	// Create table with traceroute-specific headers
 	headers := []string{"Hop", "Hostname", "IP Address", "RTT 1", "RTT 2", "RTT 3", "Status"}
 	table := tui.NewTableModel(headers)
@@ -94,10 +120,12 @@ func NewModel(tool *Tool) *Model {
 		packetSize: 60,
 		queries:    3,
 		ipv6:       false,
-		progress:   p,
+		progress:   progressbar.New(0),
 		table:      table,
 		hops:       []domain.TraceHop{},
+		interval:   DefaultContinuousInterval,
 		styles:     NewModelStyles(),
+		parent:     context.Background(),
 	}
 
 	return m
@@ -109,37 +137,40 @@ func NewModelStyles() ModelStyles {
 		Base: lipgloss.NewStyle().
 			Padding(1, 2),
 		Header: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("205")).
+			Foreground(colors.Accent).
 			Bold(true).
 			Padding(0, 1),
 		Table: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("240")),
+			BorderForeground(colors.Border),
 		Progress: lipgloss.NewStyle().
 			Padding(0, 1),
 		Statistics: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("240")).
+			BorderForeground(colors.Border).
 			Padding(1).
 			Margin(1, 0),
 		Error: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("196")).
+			Foreground(colors.Error).
 			Bold(true).
 			Padding(1),
 		Help: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241")).
+			Foreground(colors.Subtle).
 			Padding(1, 0),
 		Focused: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("205")),
+			BorderForeground(colors.Accent),
 		Blurred: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("240")),
+			BorderForeground(colors.Border),
 	}
 }
 
 // Init initializes the model
 func (m *Model) Init() tea.Cmd {
+	if m.live {
+		return m.startTraceroute()
+	}
 	return nil
 }
 
@@ -152,6 +183,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		m.updateTableSize()
+		m.progress.SetWidth(min(msg.Width-8, 60))
 		
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -159,6 +191,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.cancel != nil {
 				m.cancel()
 			}
+			if m.live {
+				return m, exitLive
+			}
 			return m, tea.Quit
 			
 		case "enter":
@@ -166,13 +201,42 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, m.startTraceroute()
 			}
 			
+		case "c":
+			if m.state == StateInput {
+				m.continuous = !m.continuous
+				return m, nil
+			}
+			
+		case "s":
+			if m.continuous && m.state != StateInput {
+				m.continuous = false
+				m.paused = false
+				return m, nil
+			}
+			
+		case "p":
+			if m.continuous && (m.state == StateRunning || m.state == StateCompleted) {
+				return m, m.togglePause()
+			}
+			
+		case "y":
+			if m.state == StateRunning || m.state == StateCompleted {
+				return m, clipboard.CopyResult(m.hops)
+			}
+			
 		case "r":
-			if m.state == StateCompleted || m.state == StateError {
+			if !m.live && (m.state == StateCompleted || m.state == StateError) {
 				m.reset()
 				return m, nil
 			}
 			
 		case "esc":
+			if m.live {
+				if m.cancel != nil {
+					m.cancel()
+				}
+				return m, exitLive
+			}
 			if m.state == StateRunning && m.cancel != nil {
 				m.cancel()
 				m.state = StateInput
@@ -180,23 +244,57 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		
+	case clipboard.CopiedMsg:
+		return m, m.toast.Show(msg.Status())
+		
+	case clipboard.ToastExpiredMsg:
+		m.toast.Update(msg)
+		return m, nil
+		
 	case StartTracerouteMsg:
 		m.state = StateRunning
+		m.progress.Start(m.maxHops)
 		return m, m.waitForNextHop()
 
 	case HopReceivedMsg:
+		// Hops and the end of a run stopped by a pause are dropped
+		if m.paused {
+			return m, nil
+		}
 		m.hops = append(m.hops, msg.Hop)
 		m.lastUpdate = time.Now()
 		m.updateCount++
 		m.updateTable()
-		return m, m.waitForNextHop()
+		return m, tea.Batch(m.progress.Set(len(m.hops)), m.waitForNextHop())
+
+	case progress.FrameMsg:
+		return m, m.progress.Update(msg)
 		
 	case TracerouteCompleteMsg:
+		if m.paused {
+			return m, nil
+		}
 		m.state = StateCompleted
 		m.statistics = m.tool.calculateStatistics(m.hops)
-		return m, nil
+		if !m.continuous {
+			return m, nil
+		}
+		m.recordPathChanges()
+		m.updateTable()
+		return m, m.scheduleNextRun()
+		
+	case NextTracerouteRunMsg:
+		// Ignore ticks from runs that were stopped, paused or reset
+		if !m.continuous || m.paused || msg.Run != m.run || m.state != StateCompleted {
+			return m, nil
+		}
+		return m, m.nextRun()
 		
 	case TracerouteErrorMsg:
+		// The run stopped by a pause ends with its context cancelled
+		if m.paused {
+			return m, nil
+		}
 		m.err = msg.Error
 		m.state = StateError
 		return m, nil
@@ -233,6 +331,9 @@ func (m *Model) View() string {
 	case StateCompleted:
 		sections = append(sections, m.renderTable())
 		sections = append(sections, m.renderStatistics())
+		if m.continuous || len(m.changeLog) > 0 {
+			sections = append(sections, m.renderChangeLog())
+		}
 		
 	case StateError:
 		sections = append(sections, m.renderError())
@@ -249,6 +350,7 @@ func (m *Model) SetSize(width, height int) {
 	m.width = width
 	m.height = height
 	m.updateTableSize()
+	m.progress.SetWidth(min(width-8, 60))
 	if m.table != nil {
 		m.table.SetSize(width-4, height-10) // Leave space for header, progress, and help
 	}
@@ -275,6 +377,19 @@ func (m *Model) SetHost(host string) {
 	m.host = host
 }
 
+// SetContinuous enables re-running the trace every interval to detect path changes
+func (m *Model) SetContinuous(enabled bool, interval time.Duration) {
+	m.continuous = enabled
+	if interval > 0 {
+		m.interval = interval
+	}
+}
+
+// ChangeLog returns the detected path changes, oldest first
+func (m *Model) ChangeLog() []PathChangeEvent {
+	return m.changeLog
+}
+
 // SetOptions sets traceroute options
 func (m *Model) SetOptions(maxHops int, timeout time.Duration, packetSize int, queries int, ipv6 bool) {
 	m.maxHops = maxHops
@@ -284,6 +399,11 @@ func (m *Model) SetOptions(maxHops int, timeout time.Duration, packetSize int, q
 	m.ipv6 = ipv6
 }
 
+// exitLive hands control back to the screen that opened a live model
+func exitLive() tea.Msg {
+	return domain.LiveExitMsg{}
+}
+
 // Custom messages for traceroute operations
 type HopReceivedMsg struct {
 	Hop domain.TraceHop
@@ -297,11 +417,17 @@ type TracerouteErrorMsg struct {
 
 type StartTracerouteMsg struct{}
 
+// NextTracerouteRunMsg starts the next run in continuous mode
+type NextTracerouteRunMsg struct {
+	Run int
+}
+
 // startTraceroute begins the traceroute operation
 func (m *Model) startTraceroute() tea.Cmd {
+	m.run++
 	return func() tea.Msg {
 		// Create context with cancellation
-		ctx, cancel := context.WithCancel(context.Background())
+		ctx, cancel := context.WithCancel(m.parent)
 		m.ctx = ctx
 		m.cancel = cancel
 		m.state = StateRunning
@@ -313,6 +439,8 @@ func (m *Model) startTraceroute() tea.Cmd {
 			PacketSize: m.packetSize,
 			Queries:    m.queries,
 			IPv6:       m.ipv6,
+			Protocol:   m.protocol,
+			Port:       m.port,
 		})
 		if err != nil {
 			return TracerouteErrorMsg{Error: err}
@@ -338,6 +466,9 @@ func (m *Model) waitForNextHop() tea.Cmd {
 			if !ok {
 				return TracerouteCompleteMsg{}
 			}
+			if hop.Error != nil {
+				return TracerouteErrorMsg{Error: hop.Error}
+			}
 			return HopReceivedMsg{Hop: hop}
 		case <-m.ctx.Done():
 			return TracerouteErrorMsg{Error: m.ctx.Err()}
@@ -359,6 +490,11 @@ func (m *Model) reset() {
 	m.resultChan = nil
 	m.lastUpdate = time.Time{}
 	m.updateCount = 0
+	m.run = 0
+	m.previousHops = nil
+	m.lastChanges = nil
+	m.changeLog = nil
+	m.paused = false
 	
 	// Clear table data
 	if m.table != nil {
@@ -384,16 +520,100 @@ func (m *Model) updateTable() {
 		return
 	}
 
-	// Convert hops to table rows
+	inserted := make(map[int]bool)
+	var removed []PathChange
+	for _, change := range m.lastChanges {
+		if change.Type == PathChangeInserted {
+			inserted[change.Hop] = true
+		} else {
+			removed = append(removed, change)
+		}
+	}
+
+	// Convert hops to table rows, placing removed hops where they used to be
 	var rows [][]string
 	for _, hop := range m.hops {
+		for len(removed) > 0 && removed[0].Hop <= hop.Number {
+			rows = append(rows, removedHopRow(removed[0]))
+			removed = removed[1:]
+		}
+
 		row := m.hopToTableRow(hop)
+		if inserted[hop.Number] {
+			row[len(row)-1] = "+ New hop"
+		}
 		rows = append(rows, row)
 	}
+	for _, change := range removed {
+		rows = append(rows, removedHopRow(change))
+	}
 
 	m.table.SetData(rows)
 }
 
+// removedHopRow renders a hop that disappeared since the previous run
+func removedHopRow(change PathChange) []string {
+	return []string{fmt.Sprintf("(%d)", change.Hop), "-", change.Address, "", "", "", "- Removed"}
+}
+
+// recordPathChanges compares the finished run with the previous one
+func (m *Model) recordPathChanges() {
+	m.lastChanges = nil
+	if m.previousHops != nil {
+		m.lastChanges = DiffPaths(m.previousHops, m.hops)
+		if len(m.lastChanges) > 0 {
+			m.changeLog = append(m.changeLog, PathChangeEvent{
+				Run:       m.run,
+				Timestamp: time.Now(),
+				Changes:   m.lastChanges,
+			})
+			if len(m.changeLog) > maxChangeLogEntries {
+				m.changeLog = m.changeLog[len(m.changeLog)-maxChangeLogEntries:]
+			}
+		}
+	}
+	m.previousHops = make([]domain.TraceHop, len(m.hops))
+	copy(m.previousHops, m.hops)
+}
+
+// nextRun clears the hops of the finished run and starts the next one
+func (m *Model) nextRun() tea.Cmd {
+	m.hops = []domain.TraceHop{}
+	m.updateCount = 0
+	return m.startTraceroute()
+}
+
+// togglePause pauses continuous mode, or resumes it with a new run. A run in
+// progress is stopped and the last finished path is shown again, so the run
+// count, statistics and change log only cover finished runs.
+func (m *Model) togglePause() tea.Cmd {
+	if m.paused {
+		m.paused = false
+		return m.nextRun()
+	}
+
+	m.paused = true
+	if m.state == StateRunning {
+		if m.cancel != nil {
+			m.cancel()
+		}
+		m.run--
+		m.hops = make([]domain.TraceHop, len(m.previousHops))
+		copy(m.hops, m.previousHops)
+		m.state = StateCompleted
+		m.updateTable()
+	}
+	return nil
+}
+
+// scheduleNextRun waits for the interval before starting the next run
+func (m *Model) scheduleNextRun() tea.Cmd {
+	run := m.run
+	return tea.Tick(m.interval, func(time.Time) tea.Msg {
+		return NextTracerouteRunMsg{Run: run}
+	})
+}
+
 // renderInputForm renders the input form
 func (m *Model) renderInputForm() string {
 	var lines []string
@@ -406,6 +626,11 @@ func (m *Model) renderInputForm() string {
 	lines = append(lines, fmt.Sprintf("Packet Size: %d bytes", m.packetSize))
 	lines = append(lines, fmt.Sprintf("Queries per hop: %d", m.queries))
 	lines = append(lines, fmt.Sprintf("IPv6: %t", m.ipv6))
+	if m.continuous {
+		lines = append(lines, fmt.Sprintf("Continuous: on (every %v)", m.interval))
+	} else {
+		lines = append(lines, "Continuous: off")
+	}
 	lines = append(lines, "")
 	lines = append(lines, "Press Enter to start traceroute")
 	
@@ -418,15 +643,9 @@ func (m *Model) renderProgress() string {
 		return m.styles.Progress.Render("Starting traceroute...")
 	}
 	
-	progress := float64(len(m.hops)) / float64(m.maxHops)
-	if progress > 1.0 {
-		progress = 1.0
-	}
-	
-	progressBar := m.progress.ViewAs(progress)
 	status := fmt.Sprintf("Hop %d/%d", len(m.hops), m.maxHops)
 	
-	return m.styles.Progress.Render(fmt.Sprintf("%s\n%s", status, progressBar))
+	return m.styles.Progress.Render(fmt.Sprintf("%s\n%s", status, m.progress.View()))
 }
 
 // renderTable renders the hops table
@@ -495,6 +714,23 @@ func (m *Model) renderStatistics() string {
 	return m.styles.Statistics.Render(stats)
 }
 
+// renderChangeLog renders the path changes detected in continuous mode
+func (m *Model) renderChangeLog() string {
+	lines := []string{fmt.Sprintf("Path Changes (%d runs)", m.run)}
+	if len(m.changeLog) == 0 {
+		lines = append(lines, "No path changes detected")
+	}
+	for i := len(m.changeLog) - 1; i >= 0; i-- {
+		lines = append(lines, m.changeLog[i].String())
+	}
+	if m.paused {
+		lines = append(lines, "Paused")
+	} else if m.continuous {
+		lines = append(lines, fmt.Sprintf("Next run in %v", m.interval))
+	}
+	return m.styles.Statistics.Render(strings.Join(lines, "\n"))
+}
+
 // renderError renders error information
 func (m *Model) renderError() string {
 	errorText := fmt.Sprintf("Error: %v", m.err)
@@ -505,14 +741,32 @@ func (m *Model) renderError() string {
 func (m *Model) renderHelp() string {
 	var help []string
 	
-	switch m.state {
-	case StateInput:
-		help = append(help, "Enter: Start traceroute • q: Quit")
-	case StateRunning:
-		help = append(help, "Esc: Cancel • q: Quit")
-	case StateCompleted, StateError:
+	switch {
+	case m.live && (m.state == StateRunning || m.state == StateCompleted):
+		help = append(help, "y: Copy • Esc: Back")
+	case m.live:
+		help = append(help, "Esc: Back")
+	case m.state == StateInput:
+		help = append(help, "Enter: Start traceroute • c: Toggle continuous • q: Quit")
+	case m.state == StateRunning:
+		help = append(help, "y: Copy • Esc: Cancel • q: Quit")
+	case m.state == StateCompleted:
+		help = append(help, "y: Copy • r: Reset • q: Quit")
+	case m.state == StateError:
 		help = append(help, "r: Reset • q: Quit")
 	}
+	if m.continuous && m.state != StateInput {
+		if m.paused {
+			help = append(help, "p: Resume")
+		} else {
+			help = append(help, "p: Pause")
+		}
+		help = append(help, "s: Stop continuous")
+	}
 	
-	return m.styles.Help.Render(strings.Join(help, " • "))
+	rendered := m.styles.Help.Render(strings.Join(help, " • "))
+	if message := m.toast.Message(); message != "" {
+		rendered = m.styles.Statistics.Render("✓ "+message) + "\n" + rendered
+	}
+	return rendered
 }
\ No newline at end of file
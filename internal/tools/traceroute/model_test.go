@@ -2,6 +2,7 @@
 package traceroute
 
 import (
+	"context"
 	"net"
 	"testing"
 	"time"
@@ -10,6 +11,8 @@ import (
 	"github.com/nettracex/nettracex-tui/internal/domain"
 	"github.com/nettracex/nettracex-tui/internal/network"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewModel(t *testing.T) {
@@ -640,3 +643,159 @@ func TestModel_ImplementsTeaModel(t *testing.T) {
 	_, cmd = model.Update(keyMsg)
 	assert.Nil(t, cmd)
 }
+
+func TestModel_ContinuousMode(t *testing.T) {
+	mockClient := network.NewMockClient()
+	mockLogger := &MockLogger{}
+	mockLogger.On("Info", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Debug", mock.Anything, mock.Anything).Return()
+	tool := NewTool(mockClient, mockLogger)
+	model := NewModel(tool)
+	model.SetHost("example.com")
+
+	// Toggle continuous mode from the input form
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	assert.True(t, model.continuous)
+	assert.Contains(t, model.renderInputForm(), "Continuous: on")
+	model.SetContinuous(true, time.Minute)
+
+	// First run establishes the baseline
+	model.startTraceroute()
+	model.hops = testPath("10.0.0.1", "192.0.2.1", "198.51.100.1")
+	_, cmd := model.Update(TracerouteCompleteMsg{})
+	assert.NotNil(t, cmd, "expected the next run to be scheduled")
+	assert.Empty(t, model.ChangeLog())
+
+	// A stale tick from an earlier run is ignored
+	_, cmd = model.Update(NextTracerouteRunMsg{Run: 0})
+	assert.Nil(t, cmd)
+
+	// Second run goes through a different router
+	_, cmd = model.Update(NextTracerouteRunMsg{Run: 1})
+	assert.NotNil(t, cmd)
+	assert.Equal(t, 2, model.run)
+	assert.Empty(t, model.hops)
+
+	model.hops = testPath("10.0.0.1", "192.0.2.9", "198.51.100.1")
+	model.Update(TracerouteCompleteMsg{})
+
+	require.Len(t, model.ChangeLog(), 1)
+	assert.Equal(t, 2, model.ChangeLog()[0].Run)
+	assert.Len(t, model.ChangeLog()[0].Changes, 2)
+
+	view := model.View()
+	assert.Contains(t, view, "Path Changes (2 runs)")
+	assert.Contains(t, view, "-2 192.0.2.1")
+	assert.Contains(t, view, "s: Stop continuous")
+
+	tableView := model.table.View()
+	assert.Contains(t, tableView, "Removed")
+	assert.Contains(t, tableView, "New hop")
+
+	// Stopping continuous mode drops the pending run
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	assert.False(t, model.continuous)
+	_, cmd = model.Update(NextTracerouteRunMsg{Run: 2})
+	assert.Nil(t, cmd)
+}
+
+func TestModel_ContinuousMode_Reset(t *testing.T) {
+	mockClient := network.NewMockClient()
+	tool := NewTool(mockClient, &MockLogger{})
+	model := NewModel(tool)
+	model.SetContinuous(true, time.Second)
+
+	model.state = StateCompleted
+	model.run = 3
+	model.previousHops = testPath("10.0.0.1")
+	model.changeLog = []PathChangeEvent{{Run: 2}}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+
+	assert.Equal(t, 0, model.run)
+	assert.Nil(t, model.previousHops)
+	assert.Empty(t, model.ChangeLog())
+	assert.True(t, model.continuous, "reset keeps the continuous setting")
+}
+
+func TestModel_ContinuousMode_Pause(t *testing.T) {
+	mockClient := network.NewMockClient()
+	mockLogger := &MockLogger{}
+	mockLogger.On("Info", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Debug", mock.Anything, mock.Anything).Return()
+	tool := NewTool(mockClient, mockLogger)
+	model := NewModel(tool)
+	model.SetHost("example.com")
+	model.SetContinuous(true, time.Minute)
+
+	model.startTraceroute()
+	model.hops = testPath("10.0.0.1", "192.0.2.1")
+	model.Update(TracerouteCompleteMsg{})
+
+	// Pausing between runs drops the pending run
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	assert.True(t, model.paused)
+	_, cmd := model.Update(NextTracerouteRunMsg{Run: 1})
+	assert.Nil(t, cmd)
+	assert.Contains(t, model.View(), "p: Resume")
+
+	// Resuming starts the next run at once
+	_, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	assert.NotNil(t, cmd)
+	assert.False(t, model.paused)
+	assert.Equal(t, 2, model.run)
+
+	// Pausing a run in progress stops it and shows the last finished path
+	cancelled := false
+	model.cancel = func() { cancelled = true }
+	model.state = StateRunning
+	model.hops = testPath("10.0.0.1")
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	assert.True(t, cancelled)
+	assert.Equal(t, StateCompleted, model.state)
+	assert.Equal(t, 1, model.run, "the stopped run is not counted")
+	assert.Len(t, model.hops, 2)
+
+	// The end of the stopped run is ignored
+	model.Update(TracerouteErrorMsg{Error: context.Canceled})
+	model.Update(TracerouteCompleteMsg{})
+	assert.Equal(t, StateCompleted, model.state)
+	assert.Empty(t, model.ChangeLog())
+}
+
+func TestTool_LiveModel(t *testing.T) {
+	tool := NewTool(network.NewMockClient(), &MockLogger{})
+	params := domain.NewTracerouteParameters("example.com", domain.TraceOptions{
+		MaxHops:    20,
+		Timeout:    time.Second,
+		PacketSize: 64,
+		Queries:    3,
+	})
+	params.Set("protocol", "tcp")
+
+	// A single trace runs to a result
+	live, err := tool.LiveModel(context.Background(), params)
+	require.NoError(t, err)
+	assert.Nil(t, live)
+
+	params.Set("repeat", 10*time.Second)
+	live, err = tool.LiveModel(context.Background(), params)
+	require.NoError(t, err)
+	model := live.(*Model)
+	assert.True(t, model.continuous)
+	assert.Equal(t, 10*time.Second, model.interval)
+	assert.Equal(t, "example.com", model.host)
+	assert.Equal(t, 20, model.maxHops)
+	assert.Equal(t, domain.TraceProtocolTCP, model.protocol)
+	assert.Equal(t, domain.DefaultTracePort(domain.TraceProtocolTCP), model.port)
+	assert.NotNil(t, model.Init(), "the screen starts tracing on Init")
+
+	// Esc leaves the screen instead of showing the input form
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	require.NotNil(t, cmd)
+	assert.Equal(t, domain.LiveExitMsg{}, cmd())
+
+	params.Set("max_hops", 0)
+	_, err = tool.LiveModel(context.Background(), params)
+	assert.Error(t, err)
+}
@@ -0,0 +1,121 @@
+// Package traceroute provides path comparison between traceroute runs
+package traceroute
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// PathChangeType describes how a hop differs between two traceroute runs
+type PathChangeType string
+
+const (
+	PathChangeInserted PathChangeType = "inserted"
+	PathChangeRemoved  PathChangeType = "removed"
+)
+
+// PathChange describes a single hop that appeared in or disappeared from the path.
+// Hop is the hop number in the run the address belongs to: the current run for
+// inserted hops and the previous run for removed hops.
+type PathChange struct {
+	Type    PathChangeType `json:"type"`
+	Hop     int            `json:"hop"`
+	Address string         `json:"address"`
+}
+
+// String returns a compact description such as "+5 192.0.2.1"
+func (c PathChange) String() string {
+	sign := "+"
+	if c.Type == PathChangeRemoved {
+		sign = "-"
+	}
+	return fmt.Sprintf("%s%d %s", sign, c.Hop, c.Address)
+}
+
+// PathChangeEvent records the changes detected at the end of a run
+type PathChangeEvent struct {
+	Run       int          `json:"run"`
+	Timestamp time.Time    `json:"timestamp"`
+	Changes   []PathChange `json:"changes"`
+}
+
+// String returns a single change log line
+func (e PathChangeEvent) String() string {
+	changes := make([]string, len(e.Changes))
+	for i, change := range e.Changes {
+		changes[i] = change.String()
+	}
+	return fmt.Sprintf("Run %d at %s: %s", e.Run, e.Timestamp.Format("15:04:05"), strings.Join(changes, ", "))
+}
+
+// pathHop is a responding hop reduced to what path comparison needs
+type pathHop struct {
+	number  int
+	address string
+}
+
+// responsiveHops returns the hops that answered, in order. Timed out hops are
+// skipped because an unanswered probe does not mean the route changed.
+func responsiveHops(hops []domain.TraceHop) []pathHop {
+	var path []pathHop
+	for _, hop := range hops {
+		if hop.Timeout || hop.Host.IPAddress == nil {
+			continue
+		}
+		path = append(path, pathHop{number: hop.Number, address: hop.Host.IPAddress.String()})
+	}
+	return path
+}
+
+// DiffPaths compares the hop sequences of two runs and returns the hops that
+// were removed from previous and inserted into current, in path order.
+// It aligns the runs on their longest common subsequence of hop addresses so a
+// single new router does not mark every later hop as changed.
+func DiffPaths(previous, current []domain.TraceHop) []PathChange {
+	before := responsiveHops(previous)
+	after := responsiveHops(current)
+
+	// lcs[i][j] is the common subsequence length of before[i:] and after[j:]
+	lcs := make([][]int, len(before)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i].address == after[j].address {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var changes []PathChange
+	i, j := 0, 0
+	for i < len(before) && j < len(after) {
+		switch {
+		case before[i].address == after[j].address:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			changes = append(changes, PathChange{Type: PathChangeRemoved, Hop: before[i].number, Address: before[i].address})
+			i++
+		default:
+			changes = append(changes, PathChange{Type: PathChangeInserted, Hop: after[j].number, Address: after[j].address})
+			j++
+		}
+	}
+	for ; i < len(before); i++ {
+		changes = append(changes, PathChange{Type: PathChangeRemoved, Hop: before[i].number, Address: before[i].address})
+	}
+	for ; j < len(after); j++ {
+		changes = append(changes, PathChange{Type: PathChangeInserted, Hop: after[j].number, Address: after[j].address})
+	}
+
+	return changes
+}
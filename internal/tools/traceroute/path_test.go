@@ -0,0 +1,101 @@
+// Package traceroute provides unit tests for path comparison
+package traceroute
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// testPath builds hops from addresses, using "*" for a timed out hop
+func testPath(addresses ...string) []domain.TraceHop {
+	hops := make([]domain.TraceHop, len(addresses))
+	for i, address := range addresses {
+		hops[i] = domain.TraceHop{Number: i + 1, Timestamp: time.Now()}
+		if address == "*" {
+			hops[i].Timeout = true
+			continue
+		}
+		hops[i].Host.IPAddress = net.ParseIP(address)
+	}
+	return hops
+}
+
+func TestDiffPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous []domain.TraceHop
+		current  []domain.TraceHop
+		expected []PathChange
+	}{
+		{
+			name:     "unchanged",
+			previous: testPath("10.0.0.1", "192.0.2.1", "198.51.100.1"),
+			current:  testPath("10.0.0.1", "192.0.2.1", "198.51.100.1"),
+		},
+		{
+			name:     "hop inserted",
+			previous: testPath("10.0.0.1", "192.0.2.1", "198.51.100.1"),
+			current:  testPath("10.0.0.1", "192.0.2.1", "203.0.113.7", "198.51.100.1"),
+			expected: []PathChange{
+				{Type: PathChangeInserted, Hop: 3, Address: "203.0.113.7"},
+			},
+		},
+		{
+			name:     "hop removed",
+			previous: testPath("10.0.0.1", "192.0.2.1", "198.51.100.1"),
+			current:  testPath("10.0.0.1", "198.51.100.1"),
+			expected: []PathChange{
+				{Type: PathChangeRemoved, Hop: 2, Address: "192.0.2.1"},
+			},
+		},
+		{
+			name:     "hop replaced",
+			previous: testPath("10.0.0.1", "192.0.2.1", "198.51.100.1"),
+			current:  testPath("10.0.0.1", "192.0.2.9", "198.51.100.1"),
+			expected: []PathChange{
+				{Type: PathChangeRemoved, Hop: 2, Address: "192.0.2.1"},
+				{Type: PathChangeInserted, Hop: 2, Address: "192.0.2.9"},
+			},
+		},
+		{
+			name:     "timeouts are ignored",
+			previous: testPath("10.0.0.1", "*", "198.51.100.1"),
+			current:  testPath("10.0.0.1", "192.0.2.1", "*"),
+			expected: []PathChange{
+				{Type: PathChangeRemoved, Hop: 3, Address: "198.51.100.1"},
+				{Type: PathChangeInserted, Hop: 2, Address: "192.0.2.1"},
+			},
+		},
+		{
+			name:     "first run",
+			previous: nil,
+			current:  testPath("10.0.0.1"),
+			expected: []PathChange{
+				{Type: PathChangeInserted, Hop: 1, Address: "10.0.0.1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DiffPaths(tt.previous, tt.current))
+		})
+	}
+}
+
+func TestPathChangeEvent_String(t *testing.T) {
+	event := PathChangeEvent{
+		Run:       3,
+		Timestamp: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+		Changes: []PathChange{
+			{Type: PathChangeRemoved, Hop: 2, Address: "192.0.2.1"},
+			{Type: PathChangeInserted, Hop: 2, Address: "192.0.2.9"},
+		},
+	}
+
+	assert.Equal(t, "Run 3 at 15:04:05: -2 192.0.2.1, +2 192.0.2.9", event.String())
+}
@@ -4,16 +4,24 @@ package traceroute
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/stats"
 )
 
+// maxConcurrentASNLookups limits parallel ASN lookups to stay within provider rate limits
+const maxConcurrentASNLookups = 4
+
 // Tool implements the DiagnosticTool interface for traceroute operations
 type Tool struct {
 	client domain.NetworkClient
 	logger domain.Logger
+	geo    domain.GeoLocationService
 }
 
 // NewTool creates a new traceroute diagnostic tool
@@ -24,6 +32,11 @@ func NewTool(client domain.NetworkClient, logger domain.Logger) *Tool {
 	}
 }
 
+// SetGeoLocationService enables ASN enrichment of traceroute hops
+func (t *Tool) SetGeoLocationService(geo domain.GeoLocationService) {
+	t.geo = geo
+}
+
 // Name returns the tool name
 func (t *Tool) Name() string {
 	return "traceroute"
@@ -52,19 +65,10 @@ func (t *Tool) Execute(ctx context.Context, params domain.Parameters) (domain.Re
 
 	// Extract parameters
 	host := params.Get("host").(string)
-	maxHops := params.Get("max_hops").(int)
-	timeout := params.Get("timeout").(time.Duration)
-	packetSize := params.Get("packet_size").(int)
-	queries := params.Get("queries").(int)
-	ipv6 := params.Get("ipv6").(bool)
-
-	opts := domain.TraceOptions{
-		MaxHops:    maxHops,
-		Timeout:    timeout,
-		PacketSize: packetSize,
-		Queries:    queries,
-		IPv6:       ipv6,
-	}
+	opts := traceOptions(params)
+	maxHops := opts.MaxHops
+	protocol := opts.Protocol
+	port := opts.Port
 
 	// Perform traceroute operation
 	resultChan, err := t.client.Traceroute(ctx, host, opts)
@@ -82,16 +86,36 @@ func (t *Tool) Execute(ctx context.Context, params domain.Parameters) (domain.Re
 	// Collect all traceroute hops
 	var hops []domain.TraceHop
 	for hop := range resultChan {
+		if hop.Error != nil {
+			return nil, &domain.NetTraceError{
+				Type:      domain.ErrorTypeNetwork,
+				Message:   "Traceroute operation failed",
+				Cause:     hop.Error,
+				Context:   map[string]interface{}{"host": host, "hop": hop.Number},
+				Timestamp: time.Now(),
+				Code:      "TRACEROUTE_OPERATION_FAILED",
+			}
+		}
 		hops = append(hops, hop)
 		t.logger.Debug("Received hop", "number", hop.Number, "host", hop.Host.Hostname, "timeout", hop.Timeout)
 	}
 
+	// Identify the networks each hop belongs to
+	t.annotateASN(ctx, hops)
+
 	// Create result with metadata
 	result := domain.NewResult(hops)
 	result.SetMetadata("tool", t.Name())
 	result.SetMetadata("host", host)
 	result.SetMetadata("max_hops", maxHops)
 	result.SetMetadata("total_hops", len(hops))
+	result.SetMetadata("protocol", string(protocol))
+	if protocol != domain.TraceProtocolICMP {
+		result.SetMetadata("port", port)
+	}
+	if networks := PathNetworks(hops); len(networks) > 0 {
+		result.SetMetadata("networks", networks)
+	}
 	result.SetMetadata("timestamp", time.Now())
 
 	// Calculate statistics
@@ -102,6 +126,50 @@ func (t *Tool) Execute(ctx context.Context, params domain.Parameters) (domain.Re
 	return result, nil
 }
 
+// LiveModel implements domain.LiveTool. A trace repeated every repeat
+// interval runs on the traceroute screen, which marks the hops that changed
+// between runs; a single trace has no live screen and runs to a result.
+func (t *Tool) LiveModel(ctx context.Context, params domain.Parameters) (tea.Model, error) {
+	repeat, _ := params.Get("repeat").(time.Duration)
+	if repeat <= 0 {
+		return nil, nil
+	}
+	if err := t.Validate(params); err != nil {
+		return nil, &domain.NetTraceError{
+			Type:      domain.ErrorTypeValidation,
+			Message:   "Traceroute parameter validation failed",
+			Cause:     err,
+			Context:   map[string]interface{}{"params": params.ToMap()},
+			Timestamp: time.Now(),
+			Code:      "TRACEROUTE_VALIDATION_FAILED",
+		}
+	}
+
+	opts := traceOptions(params)
+	m := NewModel(t)
+	m.live = true
+	m.parent = ctx
+	m.SetHost(params.Get("host").(string))
+	m.SetOptions(opts.MaxHops, opts.Timeout, opts.PacketSize, opts.Queries, opts.IPv6)
+	m.protocol = opts.Protocol
+	m.port = opts.Port
+	m.SetContinuous(true, repeat)
+	return m, nil
+}
+
+// traceOptions returns the options of validated traceroute parameters
+func traceOptions(params domain.Parameters) domain.TraceOptions {
+	return domain.TraceOptions{
+		MaxHops:    params.Get("max_hops").(int),
+		Timeout:    params.Get("timeout").(time.Duration),
+		PacketSize: params.Get("packet_size").(int),
+		Queries:    params.Get("queries").(int),
+		IPv6:       params.Get("ipv6").(bool),
+		Protocol:   params.Get("protocol").(domain.TraceProtocol),
+		Port:       params.Get("port").(int),
+	}
+}
+
 // Validate validates the parameters for traceroute operations
 func (t *Tool) Validate(params domain.Parameters) error {
 	host := params.Get("host")
@@ -146,9 +214,122 @@ func (t *Tool) Validate(params domain.Parameters) error {
 		}
 	}
 
+	// Validate protocol, defaulting to ICMP
+	var protocol domain.TraceProtocol
+	switch v := params.Get("protocol").(type) {
+	case nil:
+		protocol = domain.TraceProtocolICMP
+	case domain.TraceProtocol:
+		protocol = v
+	case string:
+		protocol = domain.TraceProtocol(strings.ToLower(strings.TrimSpace(v)))
+		if protocol == "" {
+			protocol = domain.TraceProtocolICMP
+		}
+	default:
+		return fmt.Errorf("protocol parameter must be a string")
+	}
+
+	switch protocol {
+	case domain.TraceProtocolICMP, domain.TraceProtocolUDP, domain.TraceProtocolTCP:
+	default:
+		return fmt.Errorf("protocol must be icmp, udp, or tcp")
+	}
+
+	// Validate destination port, defaulting to the protocol's conventional port
+	var portInt int
+	switch v := params.Get("port").(type) {
+	case nil:
+		portInt = domain.DefaultTracePort(protocol)
+	case int:
+		portInt = v
+	case string:
+		if strings.TrimSpace(v) == "" {
+			portInt = domain.DefaultTracePort(protocol)
+			break
+		}
+		var err error
+		portInt, err = strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return fmt.Errorf("port parameter must be a valid integer")
+		}
+	default:
+		return fmt.Errorf("port parameter must be an integer or string")
+	}
+
+	if protocol != domain.TraceProtocolICMP && (portInt <= 0 || portInt > 65535) {
+		return fmt.Errorf("port must be between 1 and 65535")
+	}
+
+	// Normalise protocol and port for Execute
+	params.Set("protocol", protocol)
+	params.Set("port", portInt)
+
 	return nil
 }
 
+// annotateASN looks up the autonomous system of each responding hop.
+// Lookup failures are logged and leave the hop without ASN information.
+func (t *Tool) annotateASN(ctx context.Context, hops []domain.TraceHop) {
+	if t.geo == nil {
+		return
+	}
+
+	// Resolve each distinct address once
+	indexes := make(map[string][]int)
+	for i, hop := range hops {
+		if hop.Timeout || hop.Host.IPAddress == nil || hop.Host.ASN != nil {
+			continue
+		}
+		key := hop.Host.IPAddress.String()
+		indexes[key] = append(indexes[key], i)
+	}
+
+	// Each goroutine writes only its own hop indexes
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentASNLookups)
+
+	for _, hopIndexes := range indexes {
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(hopIndexes []int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			ip := hops[hopIndexes[0]].Host.IPAddress
+			asn, err := t.geo.GetASNInfo(ip)
+			if err != nil {
+				t.logger.Debug("ASN lookup failed", "ip", ip.String(), "error", err)
+				return
+			}
+
+			for _, i := range hopIndexes {
+				hops[i].Host.ASN = asn
+			}
+		}(hopIndexes)
+	}
+	wg.Wait()
+}
+
+// PathNetworks returns the networks crossed by the path in hop order,
+// collapsing consecutive hops in the same AS
+func PathNetworks(hops []domain.TraceHop) []string {
+	var networks []string
+	last := 0
+	for _, hop := range hops {
+		if hop.Host.ASN == nil || hop.Host.ASN.Number == 0 || hop.Host.ASN.Number == last {
+			continue
+		}
+		last = hop.Host.ASN.Number
+		networks = append(networks, hop.Host.ASN.String())
+	}
+	return networks
+}
+
 // GetModel returns the Bubble Tea model for the traceroute tool
 func (t *Tool) GetModel() tea.Model {
 	return NewModel(t)
@@ -170,12 +351,12 @@ type TracerouteStatistics struct {
 
 // calculateStatistics calculates traceroute statistics from hops
 func (t *Tool) calculateStatistics(hops []domain.TraceHop) TracerouteStatistics {
-	stats := TracerouteStatistics{
+	statistics := TracerouteStatistics{
 		TotalHops: len(hops),
 	}
 
 	if len(hops) == 0 {
-		return stats
+		return statistics
 	}
 
 	var validHops []domain.TraceHop
@@ -195,10 +376,10 @@ func (t *Tool) calculateStatistics(hops []domain.TraceHop) TracerouteStatistics
 		}
 
 		if hop.Timeout {
-			stats.TimeoutHops++
+			statistics.TimeoutHops++
 		} else {
 			validHops = append(validHops, hop)
-			stats.CompletedHops++
+			statistics.CompletedHops++
 			
 			// Collect all RTT measurements for this hop
 			for _, rtt := range hop.RTT {
@@ -207,39 +388,26 @@ func (t *Tool) calculateStatistics(hops []domain.TraceHop) TracerouteStatistics
 		}
 	}
 
-	stats.TotalTime = endTime.Sub(startTime)
-	stats.FinalHop = len(hops)
+	statistics.TotalTime = endTime.Sub(startTime)
+	statistics.FinalHop = len(hops)
 
-	if stats.TotalHops > 0 {
-		stats.SuccessRate = float64(stats.CompletedHops) / float64(stats.TotalHops) * 100
+	if statistics.TotalHops > 0 {
+		statistics.SuccessRate = float64(statistics.CompletedHops) / float64(statistics.TotalHops) * 100
 	}
 
 	// Calculate RTT statistics from all measurements
-	if len(allRTTs) > 0 {
-		stats.MinRTT = allRTTs[0]
-		stats.MaxRTT = allRTTs[0]
-		var totalRTT time.Duration
-
-		for _, rtt := range allRTTs {
-			totalRTT += rtt
-			if rtt < stats.MinRTT {
-				stats.MinRTT = rtt
-			}
-			if rtt > stats.MaxRTT {
-				stats.MaxRTT = rtt
-			}
-		}
-
-		stats.AvgRTT = totalRTT / time.Duration(len(allRTTs))
-	}
+	summary := stats.Summarize(allRTTs)
+	statistics.MinRTT = summary.Min
+	statistics.MaxRTT = summary.Max
+	statistics.AvgRTT = summary.Mean
 
 	// Determine if we reached the target (last hop is not a timeout)
 	if len(hops) > 0 {
 		lastHop := hops[len(hops)-1]
-		stats.ReachedTarget = !lastHop.Timeout
+		statistics.ReachedTarget = !lastHop.Timeout
 	}
 
-	return stats
+	return statistics
 }
 
 // FormatTracerouteStatistics formats traceroute statistics for display
@@ -3,7 +3,9 @@ package traceroute
 
 import (
 	"context"
+	"fmt"
 	"net"
+	"sync"
 	"testing"
 	"time"
 
@@ -299,6 +301,162 @@ func TestTool_Execute_Success(t *testing.T) {
 	mockLogger.AssertExpectations(t)
 }
 
+// fakeGeoService returns fixed ASN information per address
+type fakeGeoService struct {
+	mu    sync.Mutex
+	asns  map[string]*domain.ASNInfo
+	calls map[string]int
+}
+
+func (f *fakeGeoService) GetLocation(ip net.IP) (*domain.GeoLocation, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeGeoService) GetASNInfo(ip net.IP) (*domain.ASNInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[ip.String()]++
+	if asn, ok := f.asns[ip.String()]; ok {
+		return asn, nil
+	}
+	return nil, fmt.Errorf("no ASN for %s", ip)
+}
+
+func (f *fakeGeoService) GetISPInfo(ip net.IP) (*domain.ISPInfo, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func TestTool_Validate_Protocol(t *testing.T) {
+	tool := &Tool{}
+
+	tests := []struct {
+		name             string
+		protocol         interface{}
+		port             interface{}
+		expectError      bool
+		expectedProtocol domain.TraceProtocol
+		expectedPort     int
+	}{
+		{"default protocol", nil, nil, false, domain.TraceProtocolICMP, 0},
+		{"udp default port", "udp", nil, false, domain.TraceProtocolUDP, 33434},
+		{"tcp custom port", "TCP", "443", false, domain.TraceProtocolTCP, 443},
+		{"typed protocol", domain.TraceProtocolTCP, 22, false, domain.TraceProtocolTCP, 22},
+		{"blank port uses default", "tcp", " ", false, domain.TraceProtocolTCP, 80},
+		{"unknown protocol", "sctp", nil, true, "", 0},
+		{"invalid port", "tcp", 70000, true, "", 0},
+		{"non-numeric port", "udp", "dns", true, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := domain.NewParameters()
+			params.Set("host", "example.com")
+			if tt.protocol != nil {
+				params.Set("protocol", tt.protocol)
+			}
+			if tt.port != nil {
+				params.Set("port", tt.port)
+			}
+
+			err := tool.Validate(params)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedProtocol, params.Get("protocol"))
+			assert.Equal(t, tt.expectedPort, params.Get("port"))
+		})
+	}
+}
+
+func TestTool_Execute_ProtocolAndASN(t *testing.T) {
+	mockClient := network.NewMockClient()
+	mockLogger := &MockLogger{}
+	mockLogger.On("Info", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Debug", mock.Anything, mock.Anything).Return()
+
+	google := &domain.ASNInfo{Number: 15169, Name: "GOOGLE"}
+	cogent := &domain.ASNInfo{Number: 174, Name: "COGENT-174"}
+	geo := &fakeGeoService{
+		asns: map[string]*domain.ASNInfo{
+			"38.142.1.1": cogent,
+			"38.142.1.2": cogent,
+			"8.8.8.8":    google,
+		},
+		calls: make(map[string]int),
+	}
+
+	tool := NewTool(mockClient, mockLogger)
+	tool.SetGeoLocationService(geo)
+
+	hop := func(number int, ip string) domain.TraceHop {
+		return domain.TraceHop{
+			Number:    number,
+			Host:      domain.NetworkHost{IPAddress: net.ParseIP(ip)},
+			RTT:       []time.Duration{time.Duration(number) * time.Millisecond},
+			Timestamp: time.Now(),
+		}
+	}
+	mockClient.SetTraceResponse("dns.google", []domain.TraceHop{
+		hop(1, "192.168.1.1"),
+		hop(2, "38.142.1.1"),
+		hop(3, "38.142.1.2"),
+		{Number: 4, Timeout: true, Timestamp: time.Now()},
+		hop(5, "8.8.8.8"),
+	})
+
+	params := domain.NewTracerouteParameters("dns.google", domain.TraceOptions{
+		MaxHops:    30,
+		Timeout:    5 * time.Second,
+		PacketSize: 60,
+		Queries:    1,
+		Protocol:   domain.TraceProtocolTCP,
+		Port:       443,
+	})
+
+	result, err := tool.Execute(context.Background(), params)
+	require.NoError(t, err)
+
+	calls := mockClient.GetTraceCalls()
+	require.Len(t, calls, 1)
+	opts := calls[0].Args[1].(domain.TraceOptions)
+	assert.Equal(t, domain.TraceProtocolTCP, opts.Protocol)
+	assert.Equal(t, 443, opts.Port)
+
+	hops := result.Data().([]domain.TraceHop)
+	assert.Nil(t, hops[0].Host.ASN, "private hop has no ASN")
+	assert.Equal(t, cogent, hops[1].Host.ASN)
+	assert.Equal(t, cogent, hops[2].Host.ASN)
+	assert.Nil(t, hops[3].Host.ASN, "timed out hop has no ASN")
+	assert.Equal(t, google, hops[4].Host.ASN)
+
+	metadata := result.Metadata()
+	assert.Equal(t, "tcp", metadata["protocol"])
+	assert.Equal(t, 443, metadata["port"])
+	assert.Equal(t, []string{"AS174 COGENT-174", "AS15169 GOOGLE"}, metadata["networks"])
+
+	for ip, count := range geo.calls {
+		assert.Equal(t, 1, count, "address %s should be looked up once", ip)
+	}
+}
+
+func TestPathNetworks(t *testing.T) {
+	a := &domain.ASNInfo{Number: 64500, Name: "EXAMPLE-A"}
+	b := &domain.ASNInfo{Number: 64501}
+
+	hops := []domain.TraceHop{
+		{Host: domain.NetworkHost{ASN: a}},
+		{Host: domain.NetworkHost{ASN: a}},
+		{Host: domain.NetworkHost{}},
+		{Host: domain.NetworkHost{ASN: b}},
+		{Host: domain.NetworkHost{ASN: a}},
+	}
+
+	assert.Equal(t, []string{"AS64500 EXAMPLE-A", "AS64501", "AS64500 EXAMPLE-A"}, PathNetworks(hops))
+	assert.Empty(t, PathNetworks(nil))
+}
+
 func TestTool_Execute_WithTimeouts(t *testing.T) {
 	mockClient := network.NewMockClient()
 	mockLogger := &MockLogger{}
@@ -493,6 +493,26 @@ func TestModel_ProgressIndicator(t *testing.T) {
 	assert.Contains(t, progress, "Hop 15/10")
 }
 
+// TestModel_ProgressBarETA tests the hop bar estimating the time left
+func TestModel_ProgressBarETA(t *testing.T) {
+	mockClient := network.NewMockClient()
+	mockLogger := &MockLogger{}
+	tool := NewTool(mockClient, mockLogger)
+	model := NewModel(tool)
+	model.SetSize(100, 40)
+	model.maxHops = 4
+
+	model.Update(StartTracerouteMsg{})
+	assert.Contains(t, model.renderProgress(), "Starting traceroute...")
+
+	_, cmd := model.Update(HopReceivedMsg{Hop: domain.TraceHop{Number: 1}})
+	require.NotNil(t, cmd)
+	progress := model.renderProgress()
+	assert.Contains(t, progress, "Hop 1/4")
+	assert.Contains(t, progress, "ETA")
+	assert.NotContains(t, progress, "ETA --", "one hop gives the pace of the rest")
+}
+
 // TestModel_StatisticsRendering tests statistics rendering
 func TestModel_StatisticsRendering(t *testing.T) {
 	mockClient := network.NewMockClient()
@@ -0,0 +1,45 @@
+package whois
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
+	"github.com/nettracex/nettracex-tui/internal/keyhint"
+)
+
+// KeyMap holds the keys of the WHOIS screen. Update matches keys against
+// it and the footer is rendered from it.
+type KeyMap struct {
+	Lookup key.Binding
+	Copy   key.Binding
+	Back   key.Binding
+	Quit   key.Binding
+}
+
+// DefaultKeyMap returns the keys of the WHOIS screen, described in the
+// current language
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Lookup: keyhint.New(i18n.T("key.lookup"), "enter"),
+		Copy:   keyhint.New(i18n.T("key.copy"), "y"),
+		Back:   keyhint.New(i18n.T("key.new_lookup"), "esc"),
+		Quit:   key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", i18n.T("key.quit"))),
+	}
+}
+
+// ShortHelp implements help.KeyMap with the keys of the current state
+func (m *Model) ShortHelp() []key.Binding {
+	switch m.state {
+	case StateInput:
+		return []key.Binding{m.keys.Lookup, m.keys.Quit}
+	case StateResult:
+		return []key.Binding{m.keys.Copy, m.keys.Back, m.keys.Quit}
+	case StateError:
+		return []key.Binding{m.keys.Back, m.keys.Quit}
+	}
+	return []key.Binding{m.keys.Quit}
+}
+
+// FullHelp implements help.KeyMap
+func (m *Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
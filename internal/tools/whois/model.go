@@ -6,23 +6,31 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/clipboard"
+	"github.com/nettracex/nettracex-tui/internal/colors"
 	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/keyhint"
+	"github.com/nettracex/nettracex-tui/internal/targetinput"
+	"github.com/nettracex/nettracex-tui/internal/validate"
 )
 
 // Model represents the WHOIS tool TUI model
 type Model struct {
 	tool        *Tool
 	state       ModelState
-	input       textinput.Model
+	input       targetinput.Model
 	result      domain.WHOISResult
 	error       error
 	width       int
 	height      int
 	theme       domain.Theme
 	loading     bool
+	toast       clipboard.Toast
+	keys        KeyMap
 }
 
 // ModelState represents the current state of the model
@@ -37,17 +45,16 @@ const (
 
 // NewModel creates a new WHOIS model
 func NewModel(tool *Tool) *Model {
-	input := textinput.New()
-	input.Placeholder = "Enter domain name or IP address (e.g., example.com, 8.8.8.8)"
+	input := targetinput.New("Enter domain name or IP address (e.g., example.com, 8.8.8.8)")
+	input.Check = validate.Host
 	input.Focus()
-	input.CharLimit = 253
-	input.Width = 50
 
 	return &Model{
 		tool:    tool,
 		state:   StateInput,
 		input:   input,
 		loading: false,
+		keys:    DefaultKeyMap(),
 	}
 }
 
@@ -62,10 +69,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
+		switch {
+		case key.Matches(msg, m.keys.Quit):
 			return m, tea.Quit
-		case "esc":
+		case key.Matches(msg, m.keys.Back):
 			if m.state != StateInput {
 				m.state = StateInput
 				m.input.SetValue("")
@@ -73,12 +80,23 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.error = nil
 				return m, nil
 			}
-		case "enter":
-			if m.state == StateInput && m.input.Value() != "" {
+		case key.Matches(msg, m.keys.Lookup):
+			if m.state == StateInput && m.input.Value() != "" && m.input.ValidationError() == nil {
 				return m, m.performLookup()
 			}
+		case key.Matches(msg, m.keys.Copy):
+			if m.state == StateResult {
+				return m, clipboard.CopyResult(m.result)
+			}
 		}
 
+	case clipboard.CopiedMsg:
+		return m, m.toast.Show(msg.Status())
+
+	case clipboard.ToastExpiredMsg:
+		m.toast.Update(msg)
+		return m, nil
+
 	case lookupStartMsg:
 		m.state = StateLoading
 		m.loading = true
@@ -162,11 +180,11 @@ func (m *Model) renderHeader() string {
 	
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
+		Foreground(colors.Info).
 		MarginBottom(1)
 	
 	descStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241"))
+		Foreground(colors.Subtle)
 	
 	return titleStyle.Render(title) + "\n" + descStyle.Render(description)
 }
@@ -177,18 +195,19 @@ func (m *Model) renderInput() string {
 	
 	labelStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("205"))
+		Foreground(colors.Accent)
 	
 	content.WriteString(labelStyle.Render("Query:"))
 	content.WriteString("\n")
 	content.WriteString(m.input.View())
+	content.WriteString(m.input.Feedback())
 	content.WriteString("\n\n")
 	
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(colors.Subtle).
 		Italic(true)
 	
-	content.WriteString(helpStyle.Render("Enter a domain name (e.g., example.com) or IP address (e.g., 8.8.8.8)"))
+	content.WriteString(helpStyle.Render("Enter a domain name (e.g., example.com) or IP address (e.g., 8.8.8.8) • ↑/↓: Recent queries"))
 	
 	return content.String()
 }
@@ -196,7 +215,7 @@ func (m *Model) renderInput() string {
 // renderLoading renders the loading state
 func (m *Model) renderLoading() string {
 	loadingStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("214")).
+		Foreground(colors.Warning).
 		Bold(true)
 	
 	return loadingStyle.Render(fmt.Sprintf("🔍 Looking up WHOIS information for '%s'...", m.input.Value()))
@@ -268,17 +287,17 @@ func (m *Model) renderSection(title string, data [][]string) string {
 	
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
+		Foreground(colors.Info).
 		MarginBottom(1)
 	
 	keyStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("205")).
+		Foreground(colors.Accent).
 		Width(15).
 		Align(lipgloss.Right)
 	
 	valueStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252"))
+		Foreground(colors.Text)
 	
 	content.WriteString(titleStyle.Render(title))
 	content.WriteString("\n")
@@ -301,7 +320,7 @@ func (m *Model) renderContactsSection() string {
 	
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
+		Foreground(colors.Info).
 		MarginBottom(1)
 	
 	content.WriteString(titleStyle.Render("Contacts"))
@@ -337,33 +356,28 @@ func (m *Model) renderContactsSection() string {
 // renderError renders the error state
 func (m *Model) renderError() string {
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("196")).
+		Foreground(colors.Error).
 		Bold(true)
 	
 	return errorStyle.Render(fmt.Sprintf("❌ Error: %s", m.error.Error()))
 }
 
-// renderFooter renders the footer with help text
+// renderFooter renders the footer with the keys of the current state
 func (m *Model) renderFooter() string {
-	var help []string
-	
-	switch m.state {
-	case StateInput:
-		help = []string{"enter: lookup", "q: quit"}
-	case StateResult, StateError:
-		help = []string{"esc: new lookup", "q: quit"}
-	case StateLoading:
-		help = []string{"q: quit"}
+	footer := keyhint.Help(m.width).View(m)
+	if message := m.toast.Message(); message != "" && m.state == StateResult {
+		toastStyle := lipgloss.NewStyle().
+			Foreground(colors.Success).
+			Bold(true)
+		footer = toastStyle.Render("✓ "+message) + "\n" + footer
 	}
-	
-	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241"))
-	
-	return helpStyle.Render(strings.Join(help, " • "))
+	return footer
 }
 
 // performLookup performs the WHOIS lookup
 func (m *Model) performLookup() tea.Cmd {
+	m.input.Split()
+	m.input.Remember()
 	query := strings.TrimSpace(m.input.Value())
 	
 	return tea.Batch(
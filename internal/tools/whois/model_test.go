@@ -220,7 +220,7 @@ func TestModel_View_States(t *testing.T) {
 	view := model.View()
 	assert.Contains(t, view, "WHOIS Lookup Tool")
 	assert.Contains(t, view, "Query:")
-	assert.Contains(t, view, "enter: lookup")
+	assert.Contains(t, view, "enter lookup")
 
 	// Test loading state view
 	model.state = StateLoading
@@ -257,14 +257,14 @@ func TestModel_View_States(t *testing.T) {
 	assert.Contains(t, view, "clientTransferProhibited")
 	assert.Contains(t, view, "Contacts")
 	assert.Contains(t, view, "John Doe")
-	assert.Contains(t, view, "esc: new lookup")
+	assert.Contains(t, view, "esc new lookup")
 
 	// Test error state view
 	model.state = StateError
 	model.error = assert.AnError
 	view = model.View()
 	assert.Contains(t, view, "Error:")
-	assert.Contains(t, view, "esc: new lookup")
+	assert.Contains(t, view, "esc new lookup")
 }
 
 func TestModel_renderSection(t *testing.T) {
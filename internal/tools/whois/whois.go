@@ -74,6 +74,9 @@ func (t *Tool) Execute(ctx context.Context, params domain.Parameters) (domain.Re
 	result.SetMetadata("query", query)
 	result.SetMetadata("timestamp", time.Now())
 	result.SetMetadata("query_type", t.determineQueryType(query))
+	if whoisResult.Cached {
+		result.SetMetadata("cached", true)
+	}
 
 	t.logger.Info("WHOIS lookup completed successfully", "query", query, "domain", whoisResult.Domain)
 	return result, nil
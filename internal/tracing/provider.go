@@ -0,0 +1,78 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+// DefaultServiceName is the service.name resource attribute when OTEL_SERVICE_NAME is unset
+const DefaultServiceName = "nettracex"
+
+// tracesPath is the OTLP/HTTP path for trace exports
+const tracesPath = "/v1/traces"
+
+// Configured reports whether an OTLP endpoint is set with the standard
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT or OTEL_EXPORTER_OTLP_ENDPOINT variables
+func Configured() bool {
+	return os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != ""
+}
+
+// TracesEndpoint completes endpoint with a scheme and the traces path when they are missing
+func TracesEndpoint(endpoint string) string {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	if parsed.Path == "" || parsed.Path == "/" {
+		parsed.Path = tracesPath
+	}
+	return parsed.String()
+}
+
+// NewProvider installs a global tracer provider that exports spans in
+// batches over OTLP/HTTP and propagates the trace context of outgoing
+// calls. An empty endpoint uses the standard OTEL_EXPORTER_OTLP_*
+// variables, which also set the export headers; OTEL_SERVICE_NAME and
+// OTEL_RESOURCE_ATTRIBUTES describe the service. Export failures are logged
+// to logger, as the terminal belongs to the TUI. Shut the provider down to
+// export the remaining spans.
+func NewProvider(ctx context.Context, endpoint, serviceVersion string, logger domain.Logger) (*sdktrace.TracerProvider, error) {
+	var options []otlptracehttp.Option
+	if endpoint != "" {
+		options = append(options, otlptracehttp.WithEndpointURL(TracesEndpoint(endpoint)))
+	}
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(DefaultServiceName), semconv.ServiceVersion(serviceVersion)),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe the traced service: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Warn("Failed to export trace spans", "error", err)
+	}))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider, nil
+}
@@ -0,0 +1,46 @@
+package tracing
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracedTool wraps a diagnostic tool and records a span for every execution.
+// Network operations of the tool become child spans through the context.
+type TracedTool struct {
+	domain.DiagnosticTool
+}
+
+// Instrument wraps tool so its executions are traced
+func Instrument(tool domain.DiagnosticTool) domain.DiagnosticTool {
+	return &TracedTool{DiagnosticTool: tool}
+}
+
+// Unwrap returns the wrapped diagnostic tool
+func (t *TracedTool) Unwrap() domain.DiagnosticTool {
+	return t.DiagnosticTool
+}
+
+// Execute runs the wrapped tool inside a span named after it
+func (t *TracedTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	ctx, span := Start(ctx, "execute "+t.Name(), trace.WithAttributes(attribute.String("nettracex.tool", t.Name())))
+	defer span.End()
+
+	if target := domain.TargetParam(params); target != "" {
+		span.SetAttributes(attribute.String("nettracex.target", target))
+	}
+
+	result, err := t.DiagnosticTool.Execute(ctx, params)
+	Fail(span, err)
+	return result, err
+}
+
+// LiveModel returns the live screen of the wrapped tool, whose probes are
+// not traced
+func (t *TracedTool) LiveModel(ctx context.Context, params domain.Parameters) (tea.Model, error) {
+	return domain.LiveModel(ctx, t.DiagnosticTool, params)
+}
@@ -0,0 +1,31 @@
+// Package tracing traces tool executions and network operations with
+// OpenTelemetry. Spans are started on the global tracer provider, which does
+// nothing until NewProvider installs one exporting over OTLP, so
+// instrumented code pays almost nothing while tracing is off.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names the tracer of the application's spans
+const instrumentationName = "github.com/nettracex/nettracex-tui"
+
+// Start starts a span named name as a child of the span in ctx, if any, and
+// returns a context carrying the new span
+func Start(ctx context.Context, name string, options ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, options...)
+}
+
+// Fail records err on span and marks the span as failed; a nil error is ignored
+func Fail(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
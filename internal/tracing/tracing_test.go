@@ -0,0 +1,141 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// testLogger implements domain.Logger and discards all output
+type testLogger struct{}
+
+func (testLogger) Debug(msg string, fields ...interface{}) {}
+func (testLogger) Info(msg string, fields ...interface{})  {}
+func (testLogger) Warn(msg string, fields ...interface{})  {}
+func (testLogger) Error(msg string, fields ...interface{}) {}
+func (testLogger) Fatal(msg string, fields ...interface{}) {}
+
+// stubTool starts a child span like a tool calling the network client
+type stubTool struct {
+	err error
+}
+
+func (t *stubTool) Name() string                            { return "ssl" }
+func (t *stubTool) Description() string                     { return "stub tool" }
+func (t *stubTool) Validate(params domain.Parameters) error { return nil }
+func (t *stubTool) GetModel() tea.Model                     { return nil }
+
+func (t *stubTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	_, span := Start(ctx, "handshake", trace.WithSpanKind(trace.SpanKindClient))
+	span.End()
+	return domain.NewResult("ok"), t.err
+}
+
+// recordSpans records finished spans in memory for the duration of the test
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return exporter
+}
+
+func TestStart_ParentAndChild(t *testing.T) {
+	exporter := recordSpans(t)
+
+	ctx, parent := Start(context.Background(), "parent", trace.WithAttributes(attribute.Int("count", 3)))
+	_, child := Start(ctx, "child", trace.WithSpanKind(trace.SpanKindClient))
+	Fail(child, errors.New("connection refused"))
+	child.End()
+	Fail(parent, nil)
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	childSpan, parentSpan := spans[0], spans[1]
+	assert.Equal(t, parentSpan.SpanContext.TraceID(), childSpan.SpanContext.TraceID())
+	assert.Equal(t, parentSpan.SpanContext.SpanID(), childSpan.Parent.SpanID())
+	assert.False(t, parentSpan.Parent.IsValid())
+	assert.Equal(t, trace.SpanKindClient, childSpan.SpanKind)
+	assert.Equal(t, codes.Error, childSpan.Status.Code)
+	assert.Equal(t, "connection refused", childSpan.Status.Description)
+	require.Len(t, childSpan.Events, 1)
+	assert.Equal(t, "exception", childSpan.Events[0].Name)
+	assert.Equal(t, codes.Unset, parentSpan.Status.Code)
+	assert.Equal(t, []attribute.KeyValue{attribute.Int("count", 3)}, parentSpan.Attributes)
+}
+
+func TestTracedTool_Execute(t *testing.T) {
+	exporter := recordSpans(t)
+
+	tool := Instrument(&stubTool{err: errors.New("handshake failed")})
+	params := domain.NewSSLParameters("example.com", 443)
+	_, err := tool.Execute(context.Background(), params)
+	assert.Error(t, err)
+	assert.Equal(t, "ssl", tool.Name())
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	handshake, execute := spans[0], spans[1]
+	assert.Equal(t, "execute ssl", execute.Name)
+	assert.Contains(t, execute.Attributes, attribute.String("nettracex.tool", "ssl"))
+	assert.Contains(t, execute.Attributes, attribute.String("nettracex.target", "example.com"))
+	assert.Equal(t, codes.Error, execute.Status.Code)
+	assert.Equal(t, execute.SpanContext.SpanID(), handshake.Parent.SpanID())
+}
+
+func TestNewProvider_ExportsOnShutdown(t *testing.T) {
+	var mu sync.Mutex
+	var path, contentType, apiKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		path, contentType, apiKey = r.URL.Path, r.Header.Get("Content-Type"), r.Header.Get("Api-Key")
+	}))
+	defer server.Close()
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "api-key=secret%20value")
+
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	provider, err := NewProvider(context.Background(), server.URL, "1.2.3", testLogger{})
+	require.NoError(t, err)
+
+	_, span := Start(context.Background(), "dns query")
+	span.End()
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "/v1/traces", path)
+	assert.Equal(t, "application/x-protobuf", contentType)
+	assert.Equal(t, "secret value", apiKey)
+}
+
+func TestTracesEndpoint(t *testing.T) {
+	assert.Equal(t, "http://localhost:4318/v1/traces", TracesEndpoint("localhost:4318"))
+	assert.Equal(t, "https://collector.example/v1/traces", TracesEndpoint("https://collector.example/"))
+	assert.Equal(t, "http://collector:4318/otlp/traces", TracesEndpoint("http://collector:4318/otlp/traces"))
+}
+
+func TestConfigured(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	assert.False(t, Configured())
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318")
+	assert.True(t, Configured())
+}
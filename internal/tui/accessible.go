@@ -0,0 +1,82 @@
+// Package tui contains the plain text rendering of the accessible mode
+package tui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// accessibleSymbols spells out the symbols that carry meaning before the
+// remaining pictographs are dropped
+var accessibleSymbols = strings.NewReplacer(
+	"✓", "ok",
+	"✔", "ok",
+	"✅", "ok",
+	"✗", "failed",
+	"✘", "failed",
+	"❌", "failed",
+	"⚠️", "warning:",
+	"⚠", "warning:",
+	"•", "-",
+	"↑", "up",
+	"↓", "down",
+	"←", "left",
+	"→", "right",
+	"…", "...",
+	"▶", ">",
+	"►", ">",
+)
+
+// plainRune returns what r is shown as in the accessible mode, or -1 to
+// drop it: box-drawing becomes spaces, bars become # and emoji go away
+func plainRune(r rune) rune {
+	switch {
+	case r >= 0x2500 && r <= 0x257F:
+		return ' '
+	case r >= 0x2580 && r <= 0x2590, r >= 0x2594 && r <= 0x259F:
+		return '#'
+	case r >= 0x2591 && r <= 0x2593:
+		return '.'
+	case r >= 0x2800 && r <= 0x28FF,
+		r >= 0x2190 && r <= 0x21FF,
+		r >= 0x2300 && r <= 0x23FF,
+		r >= 0x2600 && r <= 0x27BF,
+		r >= 0x2B00 && r <= 0x2BFF,
+		r >= 0x1F000 && r <= 0x1FAFF,
+		r == 0xFE0F, r == 0x200D:
+		return -1
+	}
+	return r
+}
+
+// plainText rewrites a rendered screen for screen readers. Borders, emoji
+// and spinner frames are removed, meaningful symbols spelled out, and the
+// blank lines left by borders collapsed, so the screen reads top to bottom.
+func plainText(view string) string {
+	lines := strings.Split(accessibleSymbols.Replace(view), "\n")
+	out := make([]string, 0, len(lines))
+	blank := true
+	for _, line := range lines {
+		line = strings.TrimRightFunc(strings.Map(plainRune, line), unicode.IsSpace)
+		if line == "" {
+			if blank {
+				continue
+			}
+			blank = true
+		} else {
+			blank = false
+		}
+		out = append(out, line)
+	}
+	return strings.TrimRight(strings.Join(out, "\n"), "\n")
+}
+
+// accessible reports whether the screen is rendered as plain text
+func (m *MainModel) accessible() bool {
+	return m.config != nil && m.config.UI.Accessible
+}
+
+// reducedMotion reports whether spinners and once a second refreshes are off
+func (m *MainModel) reducedMotion() bool {
+	return m.config != nil && (m.config.UI.Accessible || m.config.UI.ReducedMotion)
+}
@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	configpkg "github.com/nettracex/nettracex-tui/internal/config"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlainText(t *testing.T) {
+	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1).Render("🏓 Ping ✓\n\n⚠️ slow")
+	assert.Equal(t, "   Ping ok\n\n  warning: slow", plainText(box))
+
+	assert.Equal(t, "up/down: navigate - q: quit", plainText("↑/↓: navigate • q: quit"))
+	assert.Equal(t, "loss ####.. 60%", plainText("loss ████░░ 60%"))
+	assert.Equal(t, "a\n\nb", plainText("a\n\n\n───\n\nb\n\n"))
+}
+
+func TestMainModel_Accessible(t *testing.T) {
+	config := &domain.Config{UI: domain.UIConfig{Accessible: true}}
+	model := NewMainModel(newDashboardRegistry(t), config, configpkg.NewManager(), nil)
+	model.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	assert.True(t, model.reducedMotion(), "the accessible mode implies reduced motion")
+
+	view := model.View()
+	assert.Contains(t, view, "Settings")
+	for _, symbol := range []string{"╭", "│", "⚙", "🏠", "•", "↑"} {
+		assert.NotContains(t, view, symbol)
+	}
+	assert.False(t, strings.HasSuffix(view, "\n"))
+}
+
+func TestJobsViewModel_ReducedMotion(t *testing.T) {
+	jobs := NewJobsViewModel(NewJobList())
+	assert.NotNil(t, jobs.Init())
+
+	jobs.reducedMotion = true
+	assert.Nil(t, jobs.Init(), "elapsed times are not refreshed every second")
+}
+
+func TestAnimatedProgress_ReducedMotion(t *testing.T) {
+	progress := NewAnimatedProgress()
+	progress.SetReducedMotion(true)
+	assert.Nil(t, progress.Start("Pinging"))
+	assert.Equal(t, "Pinging", progress.View())
+}
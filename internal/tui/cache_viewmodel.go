@@ -0,0 +1,202 @@
+// Package tui contains the response cache statistics screen
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
+)
+
+// CacheClearedMsg reports the outcome of clearing the response cache
+type CacheClearedMsg struct {
+	Error error
+}
+
+// CacheViewModel shows statistics of the DNS and WHOIS response cache
+type CacheViewModel struct {
+	reporter domain.CacheReporter
+	table    *TableModel
+	stats    domain.CacheStats
+	status   string
+	width    int
+	height   int
+	theme    domain.Theme
+	refresh  key.Binding
+	clear    key.Binding
+}
+
+// NewCacheViewModel creates a cache statistics view backed by reporter
+func NewCacheViewModel(reporter domain.CacheReporter) *CacheViewModel {
+	m := &CacheViewModel{
+		reporter: reporter,
+		table:    NewTableModel([]string{"Statistic", "Value"}),
+		refresh: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", i18n.T("key.reload_statistics")),
+		),
+		clear: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", i18n.T("key.clear_cache")),
+		),
+	}
+	m.reload()
+	return m
+}
+
+// Init implements tea.Model
+func (m *CacheViewModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m *CacheViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		if mouse, ok := mouseOver(msg, m.View(), m.table.View()); ok {
+			m.table.Update(mouse)
+		}
+		return m, nil
+	case tea.KeyMsg:
+		if m.table.CapturesInput() {
+			break
+		}
+		switch {
+		case key.Matches(msg, m.refresh):
+			m.status = ""
+			m.reload()
+			return m, nil
+		case key.Matches(msg, m.clear) && m.reporter != nil:
+			reporter := m.reporter
+			return m, func() tea.Msg {
+				return CacheClearedMsg{Error: reporter.ClearCache()}
+			}
+		}
+	case CacheClearedMsg:
+		m.status = "Cache cleared"
+		if msg.Error != nil {
+			m.status = fmt.Sprintf("Failed to clear cache: %v", msg.Error)
+		}
+		m.reload()
+		return m, nil
+	}
+
+	_, cmd := m.table.Update(msg)
+	return m, cmd
+}
+
+// View implements tea.Model
+func (m *CacheViewModel) View() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).MarginBottom(1)
+	mutedStyle := lipgloss.NewStyle().Foreground(colors.Muted).Italic(true)
+	if m.theme != nil {
+		titleStyle = titleStyle.Foreground(colors.Adaptive(m.theme.GetColor("primary")))
+		mutedStyle = mutedStyle.Foreground(colors.Adaptive(m.theme.GetColor("muted")))
+	}
+
+	if m.reporter == nil {
+		return titleStyle.Render("Response Cache") + "\n\n" + mutedStyle.Render("Response caching is not available for this network client")
+	}
+	if !m.stats.Enabled {
+		return titleStyle.Render("Response Cache") + "\n\n" + mutedStyle.Render("Response caching is disabled (network.cache.enabled)")
+	}
+
+	status := screenHelp(m.width).ShortHelpView(m.ShortHelp())
+	if m.status != "" {
+		status = mutedStyle.Render(m.status+" • ") + status
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render("Response Cache"),
+		mutedStyle.Render("DNS answers are kept for their TTL and WHOIS responses for the configured TTL; press ctrl+r on a result to refresh it"),
+		"",
+		m.table.View(),
+		"",
+		status,
+	)
+}
+
+// ShortHelp implements help.KeyMap
+func (m *CacheViewModel) ShortHelp() []key.Binding {
+	return []key.Binding{m.refresh, m.clear}
+}
+
+// FullHelp implements help.KeyMap
+func (m *CacheViewModel) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+// SetSize implements domain.TUIComponent
+func (m *CacheViewModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.table.SetSize(width, height-6)
+}
+
+// SetTheme implements domain.TUIComponent
+func (m *CacheViewModel) SetTheme(theme domain.Theme) {
+	m.theme = theme
+	m.table.SetTheme(theme)
+}
+
+// SetKeyMap implements keyMapSetter
+func (m *CacheViewModel) SetKeyMap(keyMap KeyMap) {
+	m.table.SetKeyMap(keyMap)
+}
+
+// CapturesInput reports whether the vi search prompt of the table is open
+func (m *CacheViewModel) CapturesInput() bool {
+	return m.table.CapturesInput()
+}
+
+// JumpTo selects the row on line, counting from 1
+func (m *CacheViewModel) JumpTo(line int) {
+	m.table.JumpTo(line)
+}
+
+// Focus implements domain.TUIComponent
+func (m *CacheViewModel) Focus() {
+	m.table.Focus()
+}
+
+// Blur implements domain.TUIComponent
+func (m *CacheViewModel) Blur() {
+	m.table.Blur()
+}
+
+// Stats returns the most recently loaded cache statistics
+func (m *CacheViewModel) Stats() domain.CacheStats {
+	return m.stats
+}
+
+// reload reads the current statistics into the table
+func (m *CacheViewModel) reload() {
+	if m.reporter == nil {
+		return
+	}
+	m.stats = m.reporter.CacheStats()
+
+	hitRate := "-"
+	if lookups := m.stats.Hits + m.stats.Misses; lookups > 0 {
+		hitRate = fmt.Sprintf("%.1f%%", float64(m.stats.Hits)*100/float64(lookups))
+	}
+	location := m.stats.Path
+	if location == "" {
+		location = "memory only"
+	}
+
+	m.table.SetData([][]string{
+		{"DNS answers", fmt.Sprintf("%d", m.stats.DNSEntries)},
+		{"WHOIS responses", fmt.Sprintf("%d", m.stats.WHOISEntries)},
+		{"Expired", fmt.Sprintf("%d", m.stats.Expired)},
+		{"Hits", fmt.Sprintf("%d", m.stats.Hits)},
+		{"Misses", fmt.Sprintf("%d", m.stats.Misses)},
+		{"Forced refreshes", fmt.Sprintf("%d", m.stats.Refreshes)},
+		{"Hit rate", hitRate},
+		{"Stored in", location},
+	})
+}
@@ -0,0 +1,146 @@
+// Package tui contains the system capabilities diagnostics screen
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// CapabilitiesViewModel shows the privileged operations available to the
+// tools and the degraded modes used without them
+type CapabilitiesViewModel struct {
+	reporter     domain.CapabilityReporter
+	table        *TableModel
+	capabilities domain.Capabilities
+	width        int
+	height       int
+	theme        domain.Theme
+}
+
+// NewCapabilitiesViewModel creates a capabilities view backed by reporter
+func NewCapabilitiesViewModel(reporter domain.CapabilityReporter) *CapabilitiesViewModel {
+	m := &CapabilitiesViewModel{
+		reporter: reporter,
+		table:    NewTableModel([]string{"Capability", "Status", "Detail"}),
+	}
+	if reporter != nil {
+		m.setCapabilities(reporter.Capabilities())
+	}
+	return m
+}
+
+// Init implements tea.Model
+func (m *CapabilitiesViewModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m *CapabilitiesViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if mouse, ok := msg.(tea.MouseMsg); ok {
+		if mouse, ok := mouseOver(mouse, m.View(), m.table.View()); ok {
+			m.table.Update(mouse)
+		}
+		return m, nil
+	}
+	_, cmd := m.table.Update(msg)
+	return m, cmd
+}
+
+// View implements tea.Model
+func (m *CapabilitiesViewModel) View() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).MarginBottom(1)
+	mutedStyle := lipgloss.NewStyle().Foreground(colors.Muted).Italic(true)
+	warningStyle := lipgloss.NewStyle().Foreground(colors.Warning)
+	if m.theme != nil {
+		titleStyle = titleStyle.Foreground(colors.Adaptive(m.theme.GetColor("primary")))
+		mutedStyle = mutedStyle.Foreground(colors.Adaptive(m.theme.GetColor("muted")))
+		warningStyle = warningStyle.Foreground(colors.Adaptive(m.theme.GetColor("warning")))
+	}
+
+	if m.reporter == nil {
+		return titleStyle.Render("System Capabilities") + "\n\n" + mutedStyle.Render("Capabilities are not available for this network client")
+	}
+
+	privileges := "unprivileged"
+	if m.capabilities.Privileged {
+		privileges = "privileged"
+	}
+	ping := "ICMP echo"
+	if !m.capabilities.ICMP {
+		ping = "TCP connect (degraded)"
+	}
+	summary := mutedStyle.Render(strings.Join([]string{m.capabilities.Platform, privileges, "ping uses " + ping}, " • "))
+
+	sections := []string{titleStyle.Render("System Capabilities"), summary, ""}
+	if len(m.capabilities.Probes) > 0 {
+		sections = append(sections, m.table.View(), "")
+	}
+	for _, note := range m.capabilities.Notes {
+		sections = append(sections, warningStyle.Render("⚠ "+note))
+	}
+	sections = append(sections, "", mutedStyle.Render("Probed at startup"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// SetSize implements domain.TUIComponent
+func (m *CapabilitiesViewModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.table.SetSize(width, height-8)
+}
+
+// SetTheme implements domain.TUIComponent
+func (m *CapabilitiesViewModel) SetTheme(theme domain.Theme) {
+	m.theme = theme
+	m.table.SetTheme(theme)
+}
+
+// SetKeyMap implements keyMapSetter
+func (m *CapabilitiesViewModel) SetKeyMap(keyMap KeyMap) {
+	m.table.SetKeyMap(keyMap)
+}
+
+// CapturesInput reports whether the vi search prompt of the table is open
+func (m *CapabilitiesViewModel) CapturesInput() bool {
+	return m.table.CapturesInput()
+}
+
+// JumpTo selects the row on line, counting from 1
+func (m *CapabilitiesViewModel) JumpTo(line int) {
+	m.table.JumpTo(line)
+}
+
+// Focus implements domain.TUIComponent
+func (m *CapabilitiesViewModel) Focus() {
+	m.table.Focus()
+}
+
+// Blur implements domain.TUIComponent
+func (m *CapabilitiesViewModel) Blur() {
+	m.table.Blur()
+}
+
+// Capabilities returns the capabilities shown by the view
+func (m *CapabilitiesViewModel) Capabilities() domain.Capabilities {
+	return m.capabilities
+}
+
+// setCapabilities updates the table from probe results
+func (m *CapabilitiesViewModel) setCapabilities(capabilities domain.Capabilities) {
+	m.capabilities = capabilities
+
+	rows := make([][]string, 0, len(capabilities.Probes))
+	for _, probe := range capabilities.Probes {
+		status := "unavailable"
+		if probe.Available {
+			status = "available"
+		}
+		rows = append(rows, []string{probe.Name, status, probe.Detail})
+	}
+	m.table.SetData(rows)
+}
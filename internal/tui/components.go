@@ -3,14 +3,15 @@ package tui
 
 import (
 	"fmt"
-	"sort"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
 	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/targets"
 )
 
 // FormField represents a single form field
@@ -22,6 +23,8 @@ type FormField struct {
 	Validator   domain.Validator
 	HelpText    string
 	ErrorText   string
+	// Complete suggests values for what was typed, such as recent targets
+	Complete    func(input string) []targets.Suggestion
 }
 
 // FormModel provides input forms with validation
@@ -34,15 +37,23 @@ type FormModel struct {
 	title     string
 	validator domain.Validator
 	submitted bool
+	// attempted records a submit, after which empty required fields are
+	// pointed out too
+	attempted bool
 	keyMap    KeyMap
+	// suggestions is the dropdown of the focused field and suggestion the
+	// highlighted entry, -1 for none
+	suggestions []targets.Suggestion
+	suggestion  int
 }
 
 // NewFormModel creates a new form model
 func NewFormModel(title string) *FormModel {
 	return &FormModel{
-		title:   title,
-		focused: 0,
-		keyMap:  DefaultKeyMap(),
+		title:      title,
+		focused:    0,
+		keyMap:     DefaultKeyMap(),
+		suggestion: -1,
 	}
 }
 
@@ -72,6 +83,10 @@ func (m *FormModel) SetFieldValue(key, value string) {
 	for i := range m.fields {
 		if m.fields[i].Key == key {
 			m.fields[i].Input.SetValue(value)
+			m.checkField(i)
+			if i == m.focused {
+				m.refreshSuggestions()
+			}
 			break
 		}
 	}
@@ -107,7 +122,15 @@ func (m *FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		m.updateMouse(msg)
+		return m, nil
+
 	case tea.KeyMsg:
+		if m.updateSuggestions(msg) {
+			return m, nil
+		}
+
 		switch {
 		case key.Matches(msg, m.keyMap.Tab):
 			m.nextField()
@@ -134,8 +157,13 @@ func (m *FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// Update the focused field
 	if m.focused >= 0 && m.focused < len(m.fields) {
+		value := m.fields[m.focused].Input.Value()
 		m.fields[m.focused].Input, cmd = m.fields[m.focused].Input.Update(msg)
 		cmds = append(cmds, cmd)
+		if m.fields[m.focused].Input.Value() != value {
+			m.checkField(m.focused)
+			m.refreshSuggestions()
+		}
 	}
 
 	return m, tea.Batch(cmds...)
@@ -149,7 +177,7 @@ func (m *FormModel) View() string {
 	if m.title != "" {
 		titleStyle := lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("205")).
+			Foreground(colors.Accent).
 			Padding(1, 0)
 		content = append(content, titleStyle.Render(m.title))
 		content = append(content, "")
@@ -164,17 +192,30 @@ func (m *FormModel) View() string {
 
 	// Instructions
 	if !m.submitted {
-		instructionStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("243")).
-			Italic(true)
-		
-		instructions := "Tab/↑↓: navigate • Enter: submit • Esc: back"
-		content = append(content, instructionStyle.Render(instructions))
+		content = append(content, screenHelp(m.width).ShortHelpView(m.ShortHelp()))
 	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, content...)
 }
 
+// ShortHelp implements help.KeyMap
+func (m *FormModel) ShortHelp() []key.Binding {
+	submit := relabel(m.keyMap.Enter, "submit")
+	if !m.Valid() {
+		submit = relabel(m.keyMap.Enter, "submit once the fields marked * are filled in and none are red")
+	}
+	return []key.Binding{relabel(m.keyMap.Tab, "next field"), relabel(m.keyMap.Up, "previous field"), submit}
+}
+
+// FullHelp implements help.KeyMap
+func (m *FormModel) FullHelp() [][]key.Binding {
+	suggestions := suggestionKeys()
+	return [][]key.Binding{
+		append(m.ShortHelp(), relabel(m.keyMap.Down, "next field")),
+		{suggestions.Next, suggestions.Prev, suggestions.Accept, suggestions.Close},
+	}
+}
+
 // renderField renders a single form field
 func (m *FormModel) renderField(field FormField, focused bool) string {
 	var parts []string
@@ -182,7 +223,7 @@ func (m *FormModel) renderField(field FormField, focused bool) string {
 	// Label
 	labelStyle := lipgloss.NewStyle().Bold(true)
 	if field.Required {
-		labelStyle = labelStyle.Foreground(lipgloss.Color("196"))
+		labelStyle = labelStyle.Foreground(colors.Error)
 		parts = append(parts, labelStyle.Render(field.Label+" *"))
 	} else {
 		parts = append(parts, labelStyle.Render(field.Label))
@@ -193,20 +234,29 @@ func (m *FormModel) renderField(field FormField, focused bool) string {
 		Width(m.width - 4).
 		Padding(0, 1)
 	
-	if focused {
+	if m.theme != nil && focused {
+		inputStyle = themeStyle(m.theme, "form_input_focused").Width(m.width - 4)
+	} else if m.theme != nil {
+		inputStyle = themeStyle(m.theme, "form_input").Width(m.width - 4)
+	} else if focused {
 		inputStyle = inputStyle.Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("62"))
+			BorderForeground(colors.Primary)
 	} else {
 		inputStyle = inputStyle.Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("240"))
+			BorderForeground(colors.Border)
 	}
 
 	parts = append(parts, inputStyle.Render(field.Input.View()))
+	if focused && field.Input.Focused() {
+		if dropdown := m.renderSuggestions(); dropdown != "" {
+			parts = append(parts, dropdown)
+		}
+	}
 
 	// Error text
 	if field.ErrorText != "" {
 		errorStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("196")).
+			Foreground(colors.Error).
 			Italic(true)
 		parts = append(parts, errorStyle.Render("Error: "+field.ErrorText))
 	}
@@ -214,7 +264,7 @@ func (m *FormModel) renderField(field FormField, focused bool) string {
 	// Help text
 	if field.HelpText != "" && field.ErrorText == "" {
 		helpStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("243")).
+			Foreground(colors.Muted).
 			Italic(true)
 		parts = append(parts, helpStyle.Render(field.HelpText))
 	}
@@ -241,6 +291,7 @@ func (m *FormModel) nextField() {
 
 	// Focus new field
 	m.fields[m.focused].Input.Focus()
+	m.refreshSuggestions()
 }
 
 // prevField moves focus to the previous field
@@ -262,33 +313,16 @@ func (m *FormModel) prevField() {
 
 	// Focus new field
 	m.fields[m.focused].Input.Focus()
+	m.refreshSuggestions()
 }
 
-// validate validates all form fields
+// validate validates all form fields, showing the error of each invalid one
 func (m *FormModel) validate() bool {
-	valid := true
-
+	m.attempted = true
 	for i := range m.fields {
-		field := &m.fields[i]
-		field.ErrorText = ""
-
-		// Check required fields
-		if field.Required && strings.TrimSpace(field.Input.Value()) == "" {
-			field.ErrorText = "This field is required"
-			valid = false
-			continue
-		}
-
-		// Run custom validator if present
-		if field.Validator != nil {
-			if err := field.Validator.Validate(field.Input.Value()); err != nil {
-				field.ErrorText = err.Error()
-				valid = false
-			}
-		}
+		m.checkField(i)
 	}
-
-	return valid
+	return m.Valid()
 }
 
 // SetSize implements domain.TUIComponent
@@ -306,6 +340,7 @@ func (m *FormModel) SetTheme(theme domain.Theme) {
 func (m *FormModel) Focus() {
 	if len(m.fields) > 0 && m.focused >= 0 && m.focused < len(m.fields) {
 		m.fields[m.focused].Input.Focus()
+		m.refreshSuggestions()
 	}
 }
 
@@ -321,7 +356,8 @@ type FormSubmitMsg struct {
 	Values map[string]string
 }
 
-// TableModel displays tabular data with sorting and filtering
+// TableModel displays tabular data with sorting and filtering. Its columns
+// can be sorted, hidden, resized and reordered from the keyboard.
 type TableModel struct {
 	headers   []string
 	rows      [][]string
@@ -334,10 +370,21 @@ type TableModel struct {
 	theme     domain.Theme
 	focused   bool
 	keyMap    KeyMap
+	vi        viKeys
+	search    textSearch
+	// columns lists the shown columns in order, column is the one under the
+	// column cursor and widths holds the widths the user set
+	columns   []int
+	column    int
+	widths    map[int]int
 }
 
 // NewTableModel creates a new table model
 func NewTableModel(headers []string) *TableModel {
+	columns := make([]int, len(headers))
+	for i := range columns {
+		columns[i] = i
+	}
 	return &TableModel{
 		headers:  headers,
 		rows:     [][]string{},
@@ -345,12 +392,17 @@ func NewTableModel(headers []string) *TableModel {
 		selected: 0,
 		focused:  true,
 		keyMap:   DefaultKeyMap(),
+		columns:  columns,
+		widths:   make(map[int]int),
 	}
 }
 
 // SetData sets the table data
 func (m *TableModel) SetData(rows [][]string) {
 	m.rows = rows
+	if m.sortBy >= 0 {
+		m.sortRows()
+	}
 	if m.selected >= len(m.rows) {
 		m.selected = len(m.rows) - 1
 	}
@@ -359,6 +411,15 @@ func (m *TableModel) SetData(rows [][]string) {
 	}
 }
 
+// SelectedRow returns the row under the cursor
+func (m *TableModel) SelectedRow() ([]string, bool) {
+	rows := m.getFilteredRows()
+	if m.selected < 0 || m.selected >= len(rows) {
+		return nil, false
+	}
+	return rows[m.selected], true
+}
+
 // AddRow adds a row to the table
 func (m *TableModel) AddRow(row []string) {
 	m.rows = append(m.rows, row)
@@ -372,30 +433,50 @@ func (m *TableModel) Init() tea.Cmd {
 // Update implements tea.Model
 func (m *TableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		if m.focused {
+			m.updateMouse(msg)
+		}
+
 	case tea.KeyMsg:
 		if !m.focused {
 			return m, nil
 		}
 
+		if event, ok := m.search.update(msg); ok {
+			m.applySearch(event)
+			return m, nil
+		}
+
+		if motion, ok := m.vi.update(msg, m.keyMap); ok {
+			m.applyViMotion(motion)
+			return m, nil
+		}
+
+		if m.updateColumns(msg) {
+			return m, nil
+		}
+
+		rows := m.getFilteredRows()
 		switch {
 		case key.Matches(msg, m.keyMap.Up):
 			m.selected--
 			if m.selected < 0 {
-				m.selected = len(m.rows) - 1
+				m.selected = len(rows) - 1
 			}
 
 		case key.Matches(msg, m.keyMap.Down):
 			m.selected++
-			if m.selected >= len(m.rows) {
+			if m.selected >= len(rows) {
 				m.selected = 0
 			}
 
 		case key.Matches(msg, m.keyMap.Enter):
-			if m.selected >= 0 && m.selected < len(m.rows) {
+			if m.selected >= 0 && m.selected < len(rows) {
 				return m, func() tea.Msg {
 					return TableSelectMsg{
 						Row:   m.selected,
-						Data:  m.rows[m.selected],
+						Data:  rows[m.selected],
 					}
 				}
 			}
@@ -433,12 +514,22 @@ func (m *TableModel) View() string {
 
 	if len(filteredRows) == 0 {
 		emptyStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("243")).
+			Foreground(colors.Muted).
 			Italic(true).
 			Padding(1, 0)
 		content = append(content, emptyStyle.Render("No data available"))
 	}
 
+	if prompt := m.vi.prompt(); prompt != "" {
+		content = append(content, prompt)
+	}
+	if prompt := m.search.prompt(); prompt != "" {
+		content = append(content, prompt)
+	}
+	if status := m.search.status(rowTexts(m.rows)); status != "" {
+		content = append(content, status)
+	}
+
 	return lipgloss.JoinVertical(lipgloss.Left, content...)
 }
 
@@ -446,27 +537,30 @@ func (m *TableModel) View() string {
 func (m *TableModel) calculateColumnWidths() []int {
 	if m.width == 0 {
 		// Default widths if no size set
-		widths := make([]int, len(m.headers))
+		widths := make([]int, len(m.columns))
 		for i := range widths {
 			widths[i] = 15
 		}
-		return widths
+		return m.withUserWidths(widths)
 	}
 
 	// Calculate available width (accounting for borders and padding)
-	availableWidth := m.width - (len(m.headers) * 3) - 2
-
-	// Start with header widths
-	widths := make([]int, len(m.headers))
-	for i, header := range m.headers {
-		widths[i] = len(header)
+	availableWidth := m.width - (len(m.columns) * 3) - 2
+
+	// Start with header widths, leaving room for the sort arrow
+	widths := make([]int, len(m.columns))
+	for i, column := range m.columns {
+		widths[i] = len(m.headers[column])
+		if column == m.sortBy {
+			widths[i] += 2
+		}
 	}
 
 	// Check data rows for maximum width
 	for _, row := range m.rows {
-		for i, cell := range row {
-			if i < len(widths) && len(cell) > widths[i] {
-				widths[i] = len(cell)
+		for i, column := range m.columns {
+			if column < len(row) && len(row[column]) > widths[i] {
+				widths[i] = len(row[column])
 			}
 		}
 	}
@@ -482,11 +576,22 @@ func (m *TableModel) calculateColumnWidths() []int {
 		for i := range widths {
 			widths[i] = (widths[i] * availableWidth) / totalWidth
 			if widths[i] < 5 {
-				widths[i] = 5 // Minimum width
+				widths[i] = minColumnWidth
 			}
 		}
 	}
 
+	return m.withUserWidths(widths)
+}
+
+// withUserWidths replaces the calculated widths of the shown columns with
+// those the user set
+func (m *TableModel) withUserWidths(widths []int) []int {
+	for i, column := range m.columns {
+		if width, ok := m.widths[column]; ok {
+			widths[i] = width
+		}
+	}
 	return widths
 }
 
@@ -496,13 +601,25 @@ func (m *TableModel) renderHeaderRow(colWidths []int) string {
 
 	headerStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("230")).
-		Background(lipgloss.Color("62")).
+		Foreground(colors.Highlight).
+		Background(colors.Primary).
 		Padding(0, 1)
 
-	for i, header := range m.headers {
-		width := colWidths[i]
-		cell := headerStyle.Width(width).Render(header)
+	for i, column := range m.columns {
+		header := m.headers[column]
+		if column == m.sortBy {
+			if m.sortDesc {
+				header += " ▼"
+			} else {
+				header += " ▲"
+			}
+		}
+		style := headerStyle
+		if m.focused && i == m.column {
+			style = style.Background(colors.Accent)
+		}
+		// The width holds the padding too, as the separator does
+		cell := style.Width(colWidths[i] + 2).Render(header)
 		cells = append(cells, cell)
 	}
 
@@ -525,13 +642,14 @@ func (m *TableModel) renderDataRow(row []string, selected bool, colWidths []int)
 	style := lipgloss.NewStyle().Padding(0, 1)
 	if selected {
 		style = style.
-			Background(lipgloss.Color("62")).
-			Foreground(lipgloss.Color("230"))
+			Background(colors.Primary).
+			Foreground(colors.Highlight)
 	}
 
-	for i, cell := range row {
-		if i >= len(colWidths) {
-			break
+	for i, column := range m.columns {
+		var cell string
+		if column < len(row) {
+			cell = row[column]
 		}
 		width := colWidths[i]
 		
@@ -540,16 +658,17 @@ func (m *TableModel) renderDataRow(row []string, selected bool, colWidths []int)
 			cell = cell[:width-3] + "..."
 		}
 		
-		styledCell := style.Width(width).Render(cell)
+		styledCell := style.Width(width + 2).Render(m.search.highlight(cell))
 		cells = append(cells, styledCell)
 	}
 
 	return lipgloss.JoinHorizontal(lipgloss.Top, cells...)
 }
 
-// getFilteredRows returns rows filtered by the current filter
+// getFilteredRows returns rows filtered by the current filter and the &
+// search filter
 func (m *TableModel) getFilteredRows() [][]string {
-	if m.filter == "" {
+	if m.filter == "" && !m.search.filtering {
 		return m.rows
 	}
 
@@ -557,6 +676,9 @@ func (m *TableModel) getFilteredRows() [][]string {
 	filterLower := strings.ToLower(m.filter)
 
 	for _, row := range m.rows {
+		if m.search.hides(strings.Join(row, " ")) {
+			continue
+		}
 		for _, cell := range row {
 			if strings.Contains(strings.ToLower(cell), filterLower) {
 				filtered = append(filtered, row)
@@ -568,6 +690,15 @@ func (m *TableModel) getFilteredRows() [][]string {
 	return filtered
 }
 
+// rowTexts returns the cells of each row joined, as they are searched
+func rowTexts(rows [][]string) []string {
+	texts := make([]string, len(rows))
+	for i, row := range rows {
+		texts[i] = strings.Join(row, " ")
+	}
+	return texts
+}
+
 // SetSize implements domain.TUIComponent
 func (m *TableModel) SetSize(width, height int) {
 	m.width = width
@@ -579,6 +710,65 @@ func (m *TableModel) SetTheme(theme domain.Theme) {
 	m.theme = theme
 }
 
+// SetKeyMap sets the key bindings of the table
+func (m *TableModel) SetKeyMap(keyMap KeyMap) {
+	m.keyMap = keyMap
+}
+
+// CapturesInput reports whether a search prompt is open and needs every key
+func (m *TableModel) CapturesInput() bool {
+	return m.vi.Searching() || m.search.Typing()
+}
+
+// JumpTo selects the row on line, counting from 1, as :<line> does in vi
+func (m *TableModel) JumpTo(line int) {
+	rows := m.getFilteredRows()
+	m.selected = line - 1
+	if m.selected >= len(rows) {
+		m.selected = len(rows) - 1
+	}
+	if m.selected < 0 {
+		m.selected = 0
+	}
+}
+
+// applyViMotion moves the selection as a vi key asked
+func (m *TableModel) applyViMotion(motion viMotion) {
+	switch motion {
+	case viTop:
+		m.JumpTo(1)
+	case viBottom:
+		m.JumpTo(len(m.getFilteredRows()))
+	case viNextMatch, viPrevMatch:
+		texts := rowTexts(m.getFilteredRows())
+		if i := viFind(texts, m.vi.query, m.selected, motion == viPrevMatch); i >= 0 {
+			m.selected = i
+		}
+	}
+}
+
+// applySearch moves the selection to the match a search key asked for: the
+// first one from the selection on when a search is applied, the first row
+// when it filters, and the next or previous one for n and N
+func (m *TableModel) applySearch(event searchEvent) {
+	texts := rowTexts(m.getFilteredRows())
+	switch event {
+	case searchApplied:
+		if m.search.filtering {
+			m.JumpTo(1)
+			return
+		}
+		if i := m.search.find(texts, m.selected-1, false); i >= 0 {
+			m.selected = i
+		}
+		m.JumpTo(m.selected + 1)
+	case searchNextMatch, searchPrevMatch:
+		if i := m.search.find(texts, m.selected, event == searchPrevMatch); i >= 0 {
+			m.selected = i
+		}
+	}
+}
+
 // Focus implements domain.TUIComponent
 func (m *TableModel) Focus() {
 	m.focused = true
@@ -597,18 +787,7 @@ func (m *TableModel) SortBy(column int, descending bool) {
 
 	m.sortBy = column
 	m.sortDesc = descending
-
-	sort.Slice(m.rows, func(i, j int) bool {
-		if column >= len(m.rows[i]) || column >= len(m.rows[j]) {
-			return false
-		}
-
-		a, b := m.rows[i][column], m.rows[j][column]
-		if descending {
-			return a > b
-		}
-		return a < b
-	})
+	m.sortRows()
 }
 
 // SetFilter sets the table filter
@@ -690,8 +869,8 @@ func (m *ProgressModel) View() string {
 
 	// Style the progress bar
 	progressStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("62")).
-		Background(lipgloss.Color("240"))
+		Foreground(colors.Primary).
+		Background(colors.Border)
 
 	styledBar := progressStyle.Render(progressBar)
 
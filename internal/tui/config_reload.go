@@ -0,0 +1,91 @@
+// Package tui contains the hot reloading of the configuration file
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// ConfigFileChangedMsg is sent when the configuration file changed on disk
+type ConfigFileChangedMsg struct{}
+
+// ConfigChangedMsg is passed to the view of every tab after the configuration
+// was reloaded. Config is the new configuration, which views holding the
+// previous one should switch to; Keys lists what changed, e.g. "ui.theme".
+type ConfigChangedMsg struct {
+	Keys   []string
+	Config *domain.Config
+}
+
+// Has reports whether key changed
+func (msg ConfigChangedMsg) Has(key string) bool {
+	for _, changed := range msg.Keys {
+		if changed == key {
+			return true
+		}
+	}
+	return false
+}
+
+// applyColorMode renders with the colors ui.color_mode allows, none in the
+// accessible mode
+func (m *MainModel) applyColorMode() {
+	if m.accessible() {
+		colors.Apply(colors.ModeNever)
+	} else if m.config != nil {
+		colors.Apply(m.config.UI.ColorMode)
+	}
+}
+
+// reloadConfig applies the configuration file and propagates the changes
+// to the running views
+func (m *MainModel) reloadConfig() (*MainModel, tea.Cmd) {
+	if m.configManager == nil {
+		return m, nil
+	}
+
+	keys, err := m.configManager.Reload()
+	if err != nil {
+		m.configStatus = fmt.Sprintf("⚠ config not reloaded: %v", err)
+		return m, nil
+	}
+	if len(keys) == 0 {
+		return m, nil
+	}
+	m.configStatus = fmt.Sprintf("⟳ config reloaded (%d changed)", len(keys))
+
+	changed := ConfigChangedMsg{Keys: keys, Config: m.config}
+	if changed.Has("ui.theme") && m.config != nil {
+		m.useTheme(m.config.UI.Theme)
+	}
+	if changed.Has("ui.color_mode") {
+		m.applyColorMode()
+	}
+	for _, key := range keys {
+		if strings.HasPrefix(key, "keys.") || key == "ui.key_mode" {
+			m.applyKeys()
+			break
+		}
+	}
+	if m.configView != nil {
+		m.configView.Refresh()
+	}
+
+	var cmd tea.Cmd
+	if m.activeView != nil && m.activeView != m.configView {
+		m.activeView, cmd = m.activeView.Update(changed)
+	}
+	cmds := []tea.Cmd{cmd}
+	for i, tab := range m.tabs {
+		if i != m.activeTab && tab.view != nil && tab.view != m.configView && tab.view != m.activeView {
+			var tabCmd tea.Cmd
+			tab.view, tabCmd = tab.view.Update(changed)
+			cmds = append(cmds, tagCmd(tab.id, tabCmd))
+		}
+	}
+	return m, tea.Batch(cmds...)
+}
@@ -0,0 +1,444 @@
+// Package tui contains the home dashboard screen
+package tui
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/batch"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/events"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
+	"github.com/nettracex/nettracex-tui/internal/metrics"
+)
+
+const (
+	// defaultDashboardInterval is how often certificates and checks rerun
+	// when the configuration sets no interval
+	defaultDashboardInterval = time.Minute
+
+	// dashboardTimeout bounds a refresh of certificates or checks
+	dashboardTimeout = 30 * time.Second
+
+	// dashboardPingTimeout bounds the wait for a reply to a live ping
+	dashboardPingTimeout = 2 * time.Second
+)
+
+// DashboardStatus is the latest state of one target watched by a widget
+type DashboardStatus struct {
+	Target  string
+	OK      bool
+	Summary string
+	Checked time.Time
+}
+
+// DashboardUpdateMsg carries the refreshed statuses of a dashboard widget
+type DashboardUpdateMsg struct {
+	Widget   string
+	Statuses []DashboardStatus
+	model    *DashboardViewModel
+}
+
+// dashboardTickMsg asks a dashboard to refresh a widget
+type dashboardTickMsg struct {
+	widget string
+	model  *DashboardViewModel
+}
+
+// DashboardViewModel is the home screen: it shows the last result of each
+// tool, the watched certificates, the configured checks and a live ping of
+// the favorite hosts before the user opens a particular tool
+type DashboardViewModel struct {
+	plugins    domain.PluginRegistry
+	history    *ResultHistory
+	config     domain.DashboardConfig
+	widgets    []string
+	refresh    time.Duration
+	interval   time.Duration
+	statuses   map[string][]DashboardStatus
+	running    map[string]bool
+	width      int
+	height     int
+	theme      domain.Theme
+	refreshKey key.Binding
+}
+
+// NewDashboardViewModel creates a dashboard running its checks with the
+// tools of plugins and listing the results recorded in history. Favorite
+// hosts are pinged every refresh; certificates and checks rerun on the
+// configured interval.
+func NewDashboardViewModel(plugins domain.PluginRegistry, history *ResultHistory, config domain.DashboardConfig, refresh time.Duration) *DashboardViewModel {
+	widgets := config.Widgets
+	if len(widgets) == 0 {
+		widgets = domain.DashboardWidgets
+	}
+	interval := config.Interval
+	if interval <= 0 {
+		interval = defaultDashboardInterval
+	}
+	if refresh <= 0 {
+		refresh = interval
+	}
+	return &DashboardViewModel{
+		plugins:  plugins,
+		history:  history,
+		config:   config,
+		widgets:  widgets,
+		refresh:  refresh,
+		interval: interval,
+		statuses: make(map[string][]DashboardStatus),
+		running:  make(map[string]bool),
+		refreshKey: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", i18n.T("key.refresh")),
+		),
+	}
+}
+
+// Init implements tea.Model and runs every widget that watches targets
+func (m *DashboardViewModel) Init() tea.Cmd {
+	return m.refreshAll()
+}
+
+// Update implements tea.Model
+func (m *DashboardViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, m.refreshKey) {
+			return m, m.refreshAll()
+		}
+	case DashboardUpdateMsg:
+		if msg.model != m {
+			return m, nil
+		}
+		m.running[msg.Widget] = false
+		m.statuses[msg.Widget] = msg.Statuses
+		return m, m.tick(msg.Widget)
+	case dashboardTickMsg:
+		if msg.model != m || m.running[msg.widget] {
+			return m, nil
+		}
+		return m, m.run(msg.widget)
+	}
+	return m, nil
+}
+
+// View implements tea.Model
+func (m *DashboardViewModel) View() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).MarginBottom(1)
+	headingStyle := lipgloss.NewStyle().Bold(true)
+	mutedStyle := lipgloss.NewStyle().Foreground(colors.Muted).Italic(true)
+	okStyle := lipgloss.NewStyle().Foreground(colors.Success)
+	errorStyle := lipgloss.NewStyle().Foreground(colors.Error)
+	if m.theme != nil {
+		titleStyle = titleStyle.Foreground(colors.Adaptive(m.theme.GetColor("primary")))
+		headingStyle = headingStyle.Foreground(colors.Adaptive(m.theme.GetColor("secondary")))
+		mutedStyle = mutedStyle.Foreground(colors.Adaptive(m.theme.GetColor("muted")))
+		okStyle = okStyle.Foreground(colors.Adaptive(m.theme.GetColor("success")))
+		errorStyle = errorStyle.Foreground(colors.Adaptive(m.theme.GetColor("error")))
+	}
+
+	sections := []string{titleStyle.Render("Dashboard")}
+	for _, widget := range m.widgets {
+		sections = append(sections, headingStyle.Render(dashboardTitle(widget)))
+		if widget == domain.DashboardWidgetResults {
+			sections = append(sections, m.renderResults(mutedStyle)...)
+		} else {
+			sections = append(sections, m.renderStatuses(widget, mutedStyle, okStyle, errorStyle)...)
+		}
+		sections = append(sections, "")
+	}
+	sections = append(sections, screenHelp(m.width).ShortHelpView(m.ShortHelp()))
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// ShortHelp implements help.KeyMap
+func (m *DashboardViewModel) ShortHelp() []key.Binding {
+	return []key.Binding{m.refreshKey}
+}
+
+// FullHelp implements help.KeyMap
+func (m *DashboardViewModel) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+// SetSize implements domain.TUIComponent
+func (m *DashboardViewModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// SetTheme implements domain.TUIComponent
+func (m *DashboardViewModel) SetTheme(theme domain.Theme) {
+	m.theme = theme
+}
+
+// Focus implements domain.TUIComponent
+func (m *DashboardViewModel) Focus() {}
+
+// Blur implements domain.TUIComponent
+func (m *DashboardViewModel) Blur() {}
+
+// Statuses returns the latest statuses of widget
+func (m *DashboardViewModel) Statuses(widget string) []DashboardStatus {
+	return m.statuses[widget]
+}
+
+// renderResults lists the most recent result of each tool in the history
+func (m *DashboardViewModel) renderResults(mutedStyle lipgloss.Style) []string {
+	if m.history == nil || len(m.history.Tools()) == 0 {
+		return []string{mutedStyle.Render("  No results yet; run a tool from the menu")}
+	}
+	var lines []string
+	for _, tool := range m.history.Tools() {
+		entries := m.history.Entries(tool)
+		last := entries[len(entries)-1]
+		line := fmt.Sprintf("  %-12s %s", tool, summarizeResult(last.Result.Data()))
+		if target := events.Target(last.Result.Metadata()); target != "" {
+			line = fmt.Sprintf("  %-12s %s: %s", tool, target, summarizeResult(last.Result.Data()))
+		}
+		lines = append(lines, line+" "+mutedStyle.Render("at "+last.Timestamp.Format("15:04:05")))
+	}
+	return lines
+}
+
+// renderStatuses lists the statuses of a widget that watches targets
+func (m *DashboardViewModel) renderStatuses(widget string, mutedStyle, okStyle, errorStyle lipgloss.Style) []string {
+	targets := m.targets(widget)
+	if len(targets) == 0 {
+		return []string{mutedStyle.Render("  Nothing configured; add entries to ui.dashboard." + widget)}
+	}
+	statuses := m.statuses[widget]
+	if len(statuses) == 0 {
+		return []string{mutedStyle.Render("  Checking...")}
+	}
+	lines := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		mark := okStyle.Render("✓")
+		if !status.OK {
+			mark = errorStyle.Render("✗")
+		}
+		lines = append(lines, fmt.Sprintf("  %s %-28s %s %s", mark, status.Target, status.Summary,
+			mutedStyle.Render("at "+status.Checked.Format("15:04:05"))))
+	}
+	return lines
+}
+
+// targets returns what widget watches according to the configuration
+func (m *DashboardViewModel) targets(widget string) []string {
+	switch widget {
+	case domain.DashboardWidgetHosts:
+		return m.config.Hosts
+	case domain.DashboardWidgetCertificates:
+		return m.config.Certificates
+	case domain.DashboardWidgetChecks:
+		return m.config.Checks
+	default:
+		return nil
+	}
+}
+
+// refreshAll runs every shown widget that watches targets and is not
+// already running
+func (m *DashboardViewModel) refreshAll() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, widget := range m.widgets {
+		if !m.running[widget] {
+			cmds = append(cmds, m.run(widget))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// tick schedules the next refresh of widget
+func (m *DashboardViewModel) tick(widget string) tea.Cmd {
+	interval := m.interval
+	if widget == domain.DashboardWidgetHosts {
+		interval = m.refresh
+	}
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return dashboardTickMsg{widget: widget, model: m}
+	})
+}
+
+// run checks the targets of widget in the background
+func (m *DashboardViewModel) run(widget string) tea.Cmd {
+	targets := m.targets(widget)
+	if len(targets) == 0 || m.plugins == nil {
+		return nil
+	}
+	var check func(ctx context.Context, target string) DashboardStatus
+	switch widget {
+	case domain.DashboardWidgetHosts:
+		check = m.pingHost
+	case domain.DashboardWidgetCertificates:
+		check = m.checkCertificate
+	case domain.DashboardWidgetChecks:
+		check = m.runCheck
+	default:
+		return nil
+	}
+
+	m.running[widget] = true
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), dashboardTimeout)
+		defer cancel()
+
+		statuses := make([]DashboardStatus, len(targets))
+		var wg sync.WaitGroup
+		for i, target := range targets {
+			wg.Add(1)
+			go func(i int, target string) {
+				defer wg.Done()
+				statuses[i] = check(ctx, target)
+				statuses[i].Target = target
+				statuses[i].Checked = time.Now()
+			}(i, target)
+		}
+		wg.Wait()
+		return DashboardUpdateMsg{Widget: widget, Statuses: statuses, model: m}
+	}
+}
+
+// pingHost sends a single ping to host
+func (m *DashboardViewModel) pingHost(ctx context.Context, host string) DashboardStatus {
+	tool, exists := m.plugins.Get("ping")
+	if !exists {
+		return DashboardStatus{Summary: "ping is not available"}
+	}
+	params := domain.NewPingParameters(host, domain.PingOptions{
+		Count:      1,
+		Interval:   time.Second,
+		Timeout:    dashboardPingTimeout,
+		PacketSize: 64,
+		TTL:        64,
+	})
+	result, err := tool.Execute(ctx, params)
+	if err != nil {
+		return DashboardStatus{Summary: err.Error()}
+	}
+	replies, ok := result.Data().([]domain.PingResult)
+	if !ok || len(replies) == 0 {
+		return DashboardStatus{Summary: "no reply"}
+	}
+	if replies[0].Error != nil {
+		return DashboardStatus{Summary: replies[0].Error.Error()}
+	}
+	return DashboardStatus{OK: true, Summary: replies[0].RTT.Round(10 * time.Microsecond).String()}
+}
+
+// checkCertificate fetches the certificate of a host or host:port target
+func (m *DashboardViewModel) checkCertificate(ctx context.Context, target string) DashboardStatus {
+	tool, exists := m.plugins.Get("ssl")
+	if !exists {
+		return DashboardStatus{Summary: "ssl is not available"}
+	}
+	host, port := target, 443
+	if h, p, err := net.SplitHostPort(target); err == nil {
+		host = h
+		if port, err = strconv.Atoi(p); err != nil {
+			return DashboardStatus{Summary: fmt.Sprintf("invalid port %q", p)}
+		}
+	}
+	result, err := tool.Execute(ctx, domain.NewSSLParameters(host, port))
+	if err != nil {
+		return DashboardStatus{Summary: err.Error()}
+	}
+	data, ok := result.Data().(domain.SSLResult)
+	if !ok {
+		return DashboardStatus{Summary: "no certificate"}
+	}
+	return DashboardStatus{OK: data.Valid && time.Now().Before(data.Expiry), Summary: summarizeResult(data)}
+}
+
+// runCheck runs a tool:target check with the tool's default parameters
+func (m *DashboardViewModel) runCheck(ctx context.Context, check string) DashboardStatus {
+	probe, err := metrics.ParseProbe(check)
+	if err != nil {
+		return DashboardStatus{Summary: err.Error()}
+	}
+	tool, exists := m.plugins.Get(probe.Tool)
+	if !exists {
+		return DashboardStatus{Summary: fmt.Sprintf("unknown tool %s", probe.Tool)}
+	}
+	params, err := batch.BuildParameters(tool, probe.Target, nil)
+	if err != nil {
+		return DashboardStatus{Summary: err.Error()}
+	}
+	start := time.Now()
+	result, err := tool.Execute(ctx, params)
+	if err != nil {
+		return DashboardStatus{Summary: err.Error()}
+	}
+	return DashboardStatus{OK: true, Summary: fmt.Sprintf("%s in %s", summarizeResult(result.Data()), time.Since(start).Round(time.Millisecond))}
+}
+
+// dashboardTitle returns the heading of widget
+func dashboardTitle(widget string) string {
+	switch widget {
+	case domain.DashboardWidgetResults:
+		return "Last Results"
+	case domain.DashboardWidgetCertificates:
+		return "Watched Certificates"
+	case domain.DashboardWidgetChecks:
+		return "Scheduled Checks"
+	case domain.DashboardWidgetHosts:
+		return "Favorite Hosts"
+	default:
+		return widget
+	}
+}
+
+// summarizeResult describes result data in a few words
+func summarizeResult(data interface{}) string {
+	switch data := data.(type) {
+	case []domain.PingResult:
+		replies := 0
+		var total time.Duration
+		for _, result := range data {
+			if result.Error == nil {
+				replies++
+				total += result.RTT
+			}
+		}
+		if replies == 0 {
+			return fmt.Sprintf("0/%d replies", len(data))
+		}
+		return fmt.Sprintf("%d/%d replies, avg %s", replies, len(data), (total / time.Duration(replies)).Round(10*time.Microsecond))
+	case domain.MultiPingResult:
+		return fmt.Sprintf("%d hosts pinged", len(data.Targets))
+	case domain.DNSResult:
+		return fmt.Sprintf("%d records", len(data.Records))
+	case domain.SSLResult:
+		if data.Expiry.IsZero() {
+			return "no certificate"
+		}
+		days := int(time.Until(data.Expiry).Hours() / 24)
+		summary := fmt.Sprintf("expires in %d days (%s)", days, data.Expiry.Format("2006-01-02"))
+		if days < 0 {
+			summary = "expired on " + data.Expiry.Format("2006-01-02")
+		}
+		if !data.Valid && len(data.Errors) > 0 {
+			summary += ", " + strings.Join(data.Errors, "; ")
+		}
+		return summary
+	case domain.WHOISResult:
+		if data.Registrar == "" {
+			return "registrar unknown"
+		}
+		return "registrar " + data.Registrar
+	case []domain.TraceHop:
+		return fmt.Sprintf("%d hops", len(data))
+	default:
+		return "completed"
+	}
+}
@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/plugins"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dashboardTool answers every run with a fixed result or error and records
+// the parameters it received
+type dashboardTool struct {
+	name   string
+	data   interface{}
+	err    error
+	params []domain.Parameters
+}
+
+func (t *dashboardTool) Name() string                            { return t.name }
+func (t *dashboardTool) Description() string                     { return "dashboard test tool" }
+func (t *dashboardTool) Validate(params domain.Parameters) error { return nil }
+func (t *dashboardTool) GetModel() tea.Model                     { return nil }
+
+func (t *dashboardTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	t.params = append(t.params, params)
+	if t.err != nil {
+		return nil, t.err
+	}
+	return domain.NewResult(t.data), nil
+}
+
+func newDashboardRegistry(t *testing.T, tools ...*dashboardTool) *plugins.Registry {
+	registry := plugins.NewRegistry(nil)
+	for _, tool := range tools {
+		require.NoError(t, registry.Register(tool))
+	}
+	return registry
+}
+
+// runWidget refreshes widget and applies the update
+func runWidget(t *testing.T, model *DashboardViewModel, widget string) tea.Cmd {
+	_, cmd := model.Update(dashboardTickMsg{widget: widget, model: model})
+	require.NotNil(t, cmd)
+	_, next := model.Update(cmd())
+	return next
+}
+
+func TestDashboardViewModel_Hosts(t *testing.T) {
+	ping := &dashboardTool{name: "ping", data: []domain.PingResult{{RTT: 12 * time.Millisecond}}}
+	config := domain.DashboardConfig{Widgets: []string{domain.DashboardWidgetHosts}, Hosts: []string{"router.lan"}}
+	model := NewDashboardViewModel(newDashboardRegistry(t, ping), nil, config, 5*time.Second)
+
+	assert.Contains(t, model.View(), "Favorite Hosts")
+	assert.Contains(t, model.View(), "Checking...")
+	next := runWidget(t, model, domain.DashboardWidgetHosts)
+	assert.NotNil(t, next, "the next live ping is scheduled")
+
+	statuses := model.Statuses(domain.DashboardWidgetHosts)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "router.lan", statuses[0].Target)
+	assert.True(t, statuses[0].OK)
+	assert.Equal(t, "12ms", statuses[0].Summary)
+	assert.Equal(t, 1, ping.params[0].Get("count"))
+
+	ping.err = errors.New("host unreachable")
+	runWidget(t, model, domain.DashboardWidgetHosts)
+	statuses = model.Statuses(domain.DashboardWidgetHosts)
+	assert.False(t, statuses[0].OK)
+	assert.Contains(t, model.View(), "host unreachable")
+}
+
+func TestDashboardViewModel_CertificatesAndChecks(t *testing.T) {
+	ssl := &dashboardTool{name: "ssl", data: domain.SSLResult{Valid: true, Expiry: time.Now().Add(45*24*time.Hour + time.Hour)}}
+	dns := &dashboardTool{name: "dns", data: domain.DNSResult{Records: make([]domain.DNSRecord, 3)}}
+	config := domain.DashboardConfig{
+		Widgets:      []string{domain.DashboardWidgetCertificates, domain.DashboardWidgetChecks},
+		Certificates: []string{"example.com:8443"},
+		Checks:       []string{"dns:example.com", "http:example.com"},
+	}
+	model := NewDashboardViewModel(newDashboardRegistry(t, ssl, dns), nil, config, 0)
+
+	runWidget(t, model, domain.DashboardWidgetCertificates)
+	certificates := model.Statuses(domain.DashboardWidgetCertificates)
+	require.Len(t, certificates, 1)
+	assert.True(t, certificates[0].OK)
+	assert.Contains(t, certificates[0].Summary, "expires in 45 days")
+	assert.Equal(t, "example.com", ssl.params[0].Get("host"))
+	assert.Equal(t, 8443, ssl.params[0].Get("port"))
+
+	runWidget(t, model, domain.DashboardWidgetChecks)
+	checks := model.Statuses(domain.DashboardWidgetChecks)
+	require.Len(t, checks, 2)
+	assert.True(t, checks[0].OK)
+	assert.Contains(t, checks[0].Summary, "3 records")
+	assert.False(t, checks[1].OK)
+	assert.Equal(t, "unknown tool http", checks[1].Summary)
+}
+
+func TestDashboardViewModel_Results(t *testing.T) {
+	history := NewResultHistory(DefaultResultHistoryLimit)
+	result := domain.NewResult(domain.WHOISResult{Registrar: "Example Registrar"})
+	result.SetMetadata("domain", "example.com")
+	history.Add("whois", result)
+
+	config := domain.DashboardConfig{Hosts: []string{"router.lan"}}
+	model := NewDashboardViewModel(nil, history, config, 0)
+
+	view := model.View()
+	assert.Contains(t, view, "Last Results")
+	assert.Contains(t, view, "example.com: registrar Example Registrar")
+	assert.Contains(t, view, "Nothing configured; add entries to ui.dashboard.certificates")
+	assert.Nil(t, model.Init(), "nothing runs without tools")
+}
+
+func TestDashboardViewModel_IgnoresOtherDashboards(t *testing.T) {
+	ping := &dashboardTool{name: "ping", data: []domain.PingResult{{RTT: time.Millisecond}}}
+	config := domain.DashboardConfig{Hosts: []string{"router.lan"}}
+	registry := newDashboardRegistry(t, ping)
+	old := NewDashboardViewModel(registry, nil, config, time.Second)
+	model := NewDashboardViewModel(registry, nil, config, time.Second)
+
+	_, cmd := model.Update(dashboardTickMsg{widget: domain.DashboardWidgetHosts, model: old})
+	assert.Nil(t, cmd)
+	model.Update(DashboardUpdateMsg{Widget: domain.DashboardWidgetHosts, Statuses: []DashboardStatus{{Target: "stale"}}, model: old})
+	assert.Empty(t, model.Statuses(domain.DashboardWidgetHosts))
+}
@@ -4,13 +4,20 @@ package tui
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
 	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
+	"github.com/nettracex/nettracex-tui/internal/network"
+	"github.com/nettracex/nettracex-tui/internal/policy"
+	"github.com/nettracex/nettracex-tui/internal/toolparams"
 )
 
 // ViewState represents the current state of a view
@@ -31,21 +38,60 @@ const (
 	DiagnosticStateLoading
 	DiagnosticStateResult
 	DiagnosticStateError
+	// DiagnosticStateLive shows the live screen a tool runs on, such as
+	// ping's latency graph
+	DiagnosticStateLive
 )
 
+// refreshValue marks form values whose query must bypass cached responses
+const refreshValue = "_refresh"
+
 // DiagnosticViewModel wraps diagnostic tools for TUI integration
 type DiagnosticViewModel struct {
-	tool        domain.DiagnosticTool
-	inputForm   *FormModel
-	resultView  *ResultViewModel
-	state       DiagnosticViewState
-	width       int
-	height      int
-	theme       domain.Theme
-	keyMap      KeyMap
-	error       error
-	loading     bool
-	result      domain.Result
+	tool         domain.DiagnosticTool
+	inputForm    *FormModel
+	resultView   *ResultViewModel
+	state        DiagnosticViewState
+	width        int
+	height       int
+	theme        domain.Theme
+	keyMap       KeyMap
+	error        error
+	loading      bool
+	result       domain.Result
+	lastValues   map[string]string
+	targets      *TargetBook
+	targetsErr   error
+	needsConsent bool
+	cancel       context.CancelFunc
+	cancelled    bool
+	started      time.Time
+	watch        watchMode
+	live         tea.Model
+}
+
+// diagnosticKeyMap holds the keys of a tool screen besides those of the
+// key map
+type diagnosticKeyMap struct {
+	Star          key.Binding
+	Refresh       key.Binding
+	Acknowledge   key.Binding
+	Watch         key.Binding
+	WatchPause    key.Binding
+	WatchInterval key.Binding
+}
+
+// diagnosticKeys returns the keys of a tool screen, described in the
+// current language
+func diagnosticKeys() diagnosticKeyMap {
+	return diagnosticKeyMap{
+		Star:          hint(i18n.T("key.star_target"), "ctrl+s"),
+		Refresh:       hint(i18n.T("key.refresh"), "ctrl+r"),
+		Acknowledge:   hint(i18n.T("key.acknowledge"), "y", "Y"),
+		Watch:         hint(i18n.T("key.watch"), "w"),
+		WatchPause:    hint(i18n.T("key.watch_pause"), "p"),
+		WatchInterval: hint(i18n.T("key.watch_interval"), "+", "-"),
+	}
 }
 
 // NewDiagnosticViewModel creates a new diagnostic view model
@@ -59,13 +105,18 @@ func NewDiagnosticViewModel(tool domain.DiagnosticTool) *DiagnosticViewModel {
 		form.AddField("query", "Domain or IP Address", true)
 		form.SetFieldValue("query", "")
 	case "ping":
-		form.AddField("host", "Host", true)
-		form.AddField("count", "Count", false)
+		form.AddField("host", "Host (comma-separated list or @file for several)", true)
+		form.AddField("count", "Count (0 pings until stopped)", false)
 		form.SetFieldValue("count", "4")
+		form.AddField("interval", "Interval in seconds (e.g. 0.5)", false)
+		form.SetFieldValue("interval", "1")
+		form.AddField("mode", "Mode (normal, adaptive, or flood)", false)
+		form.SetFieldValue("mode", "normal")
 	case "dns":
 		form.AddField("domain", "Domain", true)
 		form.AddField("record_type", "Record Type (A, AAAA, MX, TXT, CNAME, NS, or ALL for all types)", false)
 		form.SetFieldValue("record_type", "A")
+		form.AddField("server", "DNS Server (blank uses configured servers)", false)
 	case "ssl":
 		form.AddField("host", "Host", true)
 		form.AddField("port", "Port", false)
@@ -74,12 +125,44 @@ func NewDiagnosticViewModel(tool domain.DiagnosticTool) *DiagnosticViewModel {
 		form.AddField("host", "Host", true)
 		form.AddField("max_hops", "Max Hops", false)
 		form.SetFieldValue("max_hops", "30")
+		form.AddField("protocol", "Protocol (icmp, udp, or tcp)", false)
+		form.SetFieldValue("protocol", "icmp")
+		form.AddField("port", "Port (udp/tcp only, blank for default)", false)
+		form.AddField("repeat", "Repeat every N seconds to watch for path changes (blank runs once)", false)
+	case "dualstack":
+		form.AddField("host", "Host", true)
+		form.AddField("port", "Port", false)
+		form.SetFieldValue("port", "443")
+	case "axfr":
+		form.AddField("domain", "Domain", true)
+	case "sweep":
+		form.AddField("cidr", "CIDR Range (e.g., 192.168.1.0/24)", true)
+		form.AddField("concurrency", "Concurrency", false)
+		form.SetFieldValue("concurrency", "32")
+	default:
+		// Plugins describe their own inputs
+		specs, _ := domain.ParameterSpecs(tool)
+		for _, spec := range specs {
+			label := spec.Label
+			if label == "" {
+				label = spec.Key
+			}
+			form.AddField(spec.Key, label, spec.Required)
+			form.SetFieldValue(spec.Key, spec.Default)
+		}
 	}
+	form.AddField(network.SourceParam, "Source interface or address (e.g. eth1, wg0; blank for default)", false)
+	for key, check := range toolChecks[tool.Name()] {
+		form.SetValidator(key, fieldCheck(check))
+	}
+
+	resultView := NewResultViewModel()
+	resultView.SetHistory(NewResultHistory(DefaultResultHistoryLimit), tool.Name())
 
 	return &DiagnosticViewModel{
 		tool:       tool,
 		inputForm:  form,
-		resultView: NewResultViewModel(),
+		resultView: resultView,
 		state:      DiagnosticStateInput,
 		keyMap:     DefaultKeyMap(),
 	}
@@ -94,21 +177,76 @@ func (m *DiagnosticViewModel) Init() tea.Cmd {
 func (m *DiagnosticViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	if mouse, ok := msg.(tea.MouseMsg); ok && m.state != DiagnosticStateLive {
+		// The form and the result are drawn below the header and the watch panel
+		above := m.renderHeader() + "\n\n"
+		if m.state == DiagnosticStateResult && m.showsWatch() {
+			above += m.renderWatch() + "\n"
+		}
+		msg = mouseBelow(mouse, above)
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.SetSize(msg.Width, msg.Height)
 
 	case tea.KeyMsg:
+		keys := diagnosticKeys()
+		if key.Matches(msg, keys.Star) && m.state == DiagnosticStateInput && m.targets != nil {
+			// Star the target in the focused field, or unstar it
+			if _, target, ok := m.inputForm.CompletionTarget(); ok && target != "" {
+				_, m.targetsErr = m.targets.ToggleStar(target)
+				m.inputForm.refreshSuggestions()
+			}
+			return m, nil
+		}
+		if m.CapturesInput() {
+			break
+		}
+
 		switch {
 		case key.Matches(msg, m.keyMap.Quit):
 			return m, tea.Quit
 
+		case m.needsConsent && key.Matches(msg, keys.Acknowledge):
+			// Acknowledge the public target and re-run with consent recorded
+			values := make(map[string]string, len(m.lastValues)+1)
+			for k, v := range m.lastValues {
+				values[k] = v
+			}
+			values[policy.AcknowledgeParam] = "true"
+			m.needsConsent = false
+			m.error = nil
+			return m, m.executeDiagnostic(values)
+
+		case key.Matches(msg, keys.Refresh) && m.lastValues != nil && (m.state == DiagnosticStateResult || m.state == DiagnosticStateError):
+			// Re-run the last query, bypassing cached responses
+			values := make(map[string]string, len(m.lastValues)+1)
+			for k, v := range m.lastValues {
+				values[k] = v
+			}
+			values[refreshValue] = "true"
+			m.needsConsent = false
+			m.error = nil
+			return m, m.executeDiagnostic(values)
+
+		case key.Matches(msg, keys.Watch) && m.state == DiagnosticStateResult && m.lastValues != nil:
+			return m, m.toggleWatch()
+
+		case m.watch.active && key.Matches(msg, keys.WatchPause):
+			return m, m.toggleWatchPause()
+
+		case m.watch.active && key.Matches(msg, keys.WatchInterval):
+			return m, m.changeWatchInterval(msg.String() == "+")
+
 		case key.Matches(msg, m.keyMap.Back):
 			if m.state != DiagnosticStateInput {
+				m.resetWatch()
 				m.state = DiagnosticStateInput
 				m.inputForm.Focus()
 				m.error = nil
 				m.result = nil
+				m.needsConsent = false
 				return m, nil
 			}
 			// Send navigation back message
@@ -120,15 +258,48 @@ func (m *DiagnosticViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case FormSubmitMsg:
-		// Handle form submission
+		// Handle form submission, remembering the targets for completion
+		if m.targets != nil {
+			m.targetsErr = m.targets.Use(msg.Values)
+		}
+		m.resetWatch()
 		return m, m.executeDiagnostic(msg.Values)
 
+	case watchTickMsg:
+		return m, m.updateWatchTick(msg)
+
 	case DiagnosticStartMsg:
-		m.state = DiagnosticStateLoading
+		// The live screen of a quick run may already be shown
+		if m.state == DiagnosticStateLive {
+			return m, nil
+		}
+		// A run of the watch keeps the result on screen
+		if !m.watch.running {
+			m.state = DiagnosticStateLoading
+		}
 		m.loading = true
 		return m, nil
 
+	case DiagnosticLiveMsg:
+		m.state = DiagnosticStateLive
+		m.loading = false
+		m.live = msg.Model
+		if component, ok := m.live.(domain.TUIComponent); ok {
+			component.SetSize(m.width, m.height)
+			if m.theme != nil {
+				component.SetTheme(m.theme)
+			}
+		}
+		return m, m.live.Init()
+
+	case domain.LiveExitMsg:
+		m.stopLive()
+		return m, nil
+
 	case DiagnosticResultMsg:
+		if m.watch.running {
+			return m, m.finishWatchRun(msg.Result, nil)
+		}
 		m.state = DiagnosticStateResult
 		m.loading = false
 		m.result = msg.Result
@@ -136,9 +307,13 @@ func (m *DiagnosticViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case DiagnosticErrorMsg:
+		if m.watch.running {
+			return m, m.finishWatchRun(nil, msg.Error)
+		}
 		m.state = DiagnosticStateError
 		m.loading = false
 		m.error = msg.Error
+		m.needsConsent = policy.IsConsentRequired(msg.Error)
 		return m, nil
 	}
 
@@ -155,6 +330,11 @@ func (m *DiagnosticViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.resultView = updatedResult.(*ResultViewModel)
 			cmds = append(cmds, resultCmd)
 		}
+
+	case DiagnosticStateLive:
+		var liveCmd tea.Cmd
+		m.live, liveCmd = m.live.Update(msg)
+		cmds = append(cmds, liveCmd)
 	}
 
 	return m, tea.Batch(cmds...)
@@ -162,6 +342,11 @@ func (m *DiagnosticViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View implements tea.Model
 func (m *DiagnosticViewModel) View() string {
+	// A live screen has its own header and keys
+	if m.state == DiagnosticStateLive {
+		return m.live.View()
+	}
+
 	var content strings.Builder
 
 	// Header
@@ -175,6 +360,10 @@ func (m *DiagnosticViewModel) View() string {
 	case DiagnosticStateLoading:
 		content.WriteString(m.renderLoading())
 	case DiagnosticStateResult:
+		if m.showsWatch() {
+			content.WriteString(m.renderWatch())
+			content.WriteString("\n")
+		}
 		if m.resultView != nil {
 			content.WriteString(m.resultView.View())
 		}
@@ -193,11 +382,11 @@ func (m *DiagnosticViewModel) View() string {
 func (m *DiagnosticViewModel) renderHeader() string {
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
+		Foreground(colors.Info).
 		MarginBottom(1)
 
 	descStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241"))
+		Foreground(colors.Subtle)
 
 	title := strings.ToUpper(m.tool.Name()) + " Diagnostic Tool"
 	description := m.tool.Description()
@@ -208,7 +397,7 @@ func (m *DiagnosticViewModel) renderHeader() string {
 // renderLoading renders the loading state
 func (m *DiagnosticViewModel) renderLoading() string {
 	loadingStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("214")).
+		Foreground(colors.Warning).
 		Bold(true)
 
 	// For simplicity, just show a static loading message
@@ -218,127 +407,144 @@ func (m *DiagnosticViewModel) renderLoading() string {
 // renderError renders the error state
 func (m *DiagnosticViewModel) renderError() string {
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("196")).
+		Foreground(colors.Error).
 		Bold(true)
 
 	retryStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(colors.Subtle).
 		Italic(true)
 
 	var content strings.Builder
+	if m.needsConsent {
+		warningStyle := lipgloss.NewStyle().
+			Foreground(colors.Warning).
+			Bold(true)
+
+		content.WriteString(warningStyle.Render(fmt.Sprintf("⚠️  Warning: %s", m.error.Error())))
+		content.WriteString("\n\n")
+		content.WriteString(retryStyle.Render("This will send traffic to a third-party network. Press Y to acknowledge (recorded in the audit log) or ESC to cancel"))
+		return content.String()
+	}
+
 	content.WriteString(errorStyle.Render(fmt.Sprintf("❌ Error: %s", m.error.Error())))
 	content.WriteString("\n\n")
+	if hint := domain.RemediationHint(m.error); hint != "" {
+		hintStyle := lipgloss.NewStyle().
+			Foreground(colors.Warning)
+		content.WriteString(hintStyle.Render("💡 " + hint))
+		content.WriteString("\n\n")
+	}
 	content.WriteString(retryStyle.Render("Press ESC to try again or Q to quit"))
 
 	return content.String()
 }
 
-// renderFooter renders the footer with help text
-func (m *DiagnosticViewModel) renderFooter() string {
-	var help []string
-
+// ShortHelp implements help.KeyMap with the keys of the current state
+func (m *DiagnosticViewModel) ShortHelp() []key.Binding {
+	keys := diagnosticKeys()
+	newQuery := relabel(m.keyMap.Back, i18n.T("key.new_query"))
 	switch m.state {
 	case DiagnosticStateInput:
-		help = []string{"tab: next field", "enter: execute", "esc: back", "q: quit"}
+		if m.targets != nil {
+			return []key.Binding{keys.Star}
+		}
 	case DiagnosticStateResult, DiagnosticStateError:
-		help = []string{"esc: new query", "q: quit"}
+		if m.needsConsent {
+			return []key.Binding{keys.Acknowledge, relabel(m.keyMap.Back, i18n.T("key.cancel"))}
+		}
+		if m.watch.active {
+			return []key.Binding{newQuery, relabel(keys.Watch, i18n.T("key.stop_watching")), keys.WatchPause, keys.WatchInterval}
+		}
+		if m.state == DiagnosticStateResult {
+			return []key.Binding{newQuery, keys.Refresh, keys.Watch}
+		}
+		return []key.Binding{newQuery, keys.Refresh}
 	case DiagnosticStateLoading:
-		help = []string{"q: quit"}
+		return []key.Binding{relabel(m.keyMap.Back, i18n.T("key.continue_background"))}
 	}
+	return nil
+}
 
-	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241"))
+// FullHelp implements help.KeyMap with the keys of the current state and
+// of the form, result or live screen shown
+func (m *DiagnosticViewModel) FullHelp() [][]key.Binding {
+	groups := [][]key.Binding{m.ShortHelp()}
+	switch m.state {
+	case DiagnosticStateLive:
+		if keyMap, ok := m.live.(help.KeyMap); ok {
+			groups = keyMap.FullHelp()
+		}
+	case DiagnosticStateInput:
+		groups = append(m.inputForm.FullHelp(), groups...)
+	case DiagnosticStateResult:
+		if m.resultView != nil && !m.needsConsent {
+			groups = append(groups, m.resultView.FullHelp()...)
+		}
+	}
+	return groups
+}
 
-	return helpStyle.Render(strings.Join(help, " • "))
+// renderFooter renders the footer with help text
+func (m *DiagnosticViewModel) renderFooter() string {
+	footer := screenHelp(m.width).ShortHelpView(m.ShortHelp())
+	if (m.state == DiagnosticStateResult || m.state == DiagnosticStateError) && m.result != nil && m.result.Metadata()["cached"] == true && !m.needsConsent {
+		footer = lipgloss.NewStyle().Foreground(colors.Subtle).Render("⚡ served from cache • ") + footer
+	}
+	if m.targetsErr != nil {
+		warningStyle := lipgloss.NewStyle().
+			Foreground(colors.Warning)
+		footer = warningStyle.Render("⚠ Saving targets failed: "+m.targetsErr.Error()) + "\n" + footer
+	}
+	if m.state == DiagnosticStateResult && m.result != nil {
+		if degraded, ok := m.result.Metadata()["degraded"].(string); ok && degraded != "" {
+			warningStyle := lipgloss.NewStyle().
+				Foreground(colors.Warning)
+			footer = warningStyle.Render("⚠ "+degraded) + "\n" + footer
+		}
+	}
+	return footer
 }
 
 // executeDiagnostic executes the diagnostic tool with the provided parameters
 func (m *DiagnosticViewModel) executeDiagnostic(values map[string]string) tea.Cmd {
+	m.lastValues = values
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.cancelled = false
+	m.started = time.Now()
+
 	return tea.Batch(
 		func() tea.Msg { return DiagnosticStartMsg{} },
 		func() tea.Msg {
-			// Create parameters based on tool type
-			var params domain.Parameters
-			var err error
-
-			switch m.tool.Name() {
-			case "whois":
-				query := values["query"]
-				params = domain.NewWHOISParameters(query)
-			case "ping":
-				host := values["host"]
-				// For now, use default ping options
-				options := domain.PingOptions{
-					Count:      4,
-					PacketSize: 64,
-					TTL:        64,
-				}
-				params = domain.NewPingParameters(host, options)
-			case "dns":
-				domainName := values["domain"]
-				recordTypeStr := values["record_type"]
-				
-				// Parse the record type string
-				var recordType domain.DNSRecordType
-				if recordTypeStr != "" {
-					switch strings.ToUpper(strings.TrimSpace(recordTypeStr)) {
-					case "A":
-						recordType = domain.DNSRecordTypeA
-					case "AAAA":
-						recordType = domain.DNSRecordTypeAAAA
-					case "MX":
-						recordType = domain.DNSRecordTypeMX
-					case "TXT":
-						recordType = domain.DNSRecordTypeTXT
-					case "CNAME":
-						recordType = domain.DNSRecordTypeCNAME
-					case "NS":
-						recordType = domain.DNSRecordTypeNS
-					case "SOA":
-						recordType = domain.DNSRecordTypeSOA
-					case "PTR":
-						recordType = domain.DNSRecordTypePTR
-					default:
-						recordType = domain.DNSRecordTypeA // Default fallback
-					}
-				} else {
-					recordType = domain.DNSRecordTypeA // Default to A record
-				}
-				
-				params = domain.NewDNSParameters(domainName, recordType)
-				
-				// If user wants all record types (empty or "ALL"), set multiple types
-				if recordTypeStr == "" || strings.ToUpper(strings.TrimSpace(recordTypeStr)) == "ALL" {
-					allTypes := []domain.DNSRecordType{
-						domain.DNSRecordTypeA,
-						domain.DNSRecordTypeAAAA,
-						domain.DNSRecordTypeMX,
-						domain.DNSRecordTypeTXT,
-						domain.DNSRecordTypeCNAME,
-						domain.DNSRecordTypeNS,
-					}
-					params.Set("record_types", allTypes)
-				}
-			case "ssl":
-				host := values["host"]
-				port := 443 // Default HTTPS port
-				params = domain.NewSSLParameters(host, port)
-			case "traceroute":
-				host := values["host"]
-				options := domain.TraceOptions{
-					MaxHops:    30,
-					Timeout:    5 * time.Second, // Add missing timeout
-					PacketSize: 64,
-					Queries:    3,
-					IPv6:       false,
-				}
-				params = domain.NewTracerouteParameters(host, options)
-			default:
-				return DiagnosticErrorMsg{Error: fmt.Errorf("unsupported tool: %s", m.tool.Name())}
+			params, err := toolparams.Build(m.tool, values)
+			if err != nil {
+				return DiagnosticErrorMsg{Error: err}
+			}
+
+			if values[policy.AcknowledgeParam] == "true" {
+				params.Set(policy.AcknowledgeParam, true)
+			}
+			if source := strings.TrimSpace(values[network.SourceParam]); source != "" {
+				params.Set(network.SourceParam, source)
+			}
+
+			if values[refreshValue] == "true" {
+				ctx = domain.WithRefresh(ctx)
+			}
+
+			// A tool with a live screen runs on it until the screen is left
+			live, err := domain.LiveModel(ctx, m.tool, params)
+			if err != nil {
+				cancel()
+				return DiagnosticErrorMsg{Error: err}
+			}
+			if live != nil {
+				return DiagnosticLiveMsg{Model: live}
 			}
 
-			// Execute the diagnostic
-			result, err := m.tool.Execute(context.Background(), params)
+			// Execute the diagnostic; Cancel stops it
+			defer cancel()
+			result, err := m.tool.Execute(ctx, params)
 			if err != nil {
 				return DiagnosticErrorMsg{Error: err}
 			}
@@ -357,7 +563,15 @@ func (m *DiagnosticViewModel) SetSize(width, height int) {
 		m.inputForm.SetSize(width, height)
 	}
 
+	if component, ok := m.live.(domain.TUIComponent); ok {
+		component.SetSize(width, height)
+	}
+
 	if m.resultView != nil {
+		// The watch panel takes lines above the result
+		if m.showsWatch() {
+			height = max(1, height-watchPanelHeight)
+		}
 		m.resultView.SetSize(width, height)
 	}
 }
@@ -373,6 +587,10 @@ func (m *DiagnosticViewModel) SetTheme(theme domain.Theme) {
 	if m.resultView != nil {
 		m.resultView.SetTheme(theme)
 	}
+
+	if component, ok := m.live.(domain.TUIComponent); ok {
+		component.SetTheme(theme)
+	}
 }
 
 // Focus implements domain.TUIComponent
@@ -389,6 +607,161 @@ func (m *DiagnosticViewModel) Blur() {
 	}
 }
 
+// SetHistory shares the session result history so earlier results of the
+// tool can be compared with new ones
+func (m *DiagnosticViewModel) SetHistory(history *ResultHistory) {
+	m.resultView.SetHistory(history, m.tool.Name())
+}
+
+// SetTargets makes the target fields of the form suggest the recent, starred
+// and system targets of book, and records the targets the tool runs against
+func (m *DiagnosticViewModel) SetTargets(book *TargetBook) {
+	m.targets = book
+	for _, field := range m.inputForm.fields {
+		if targetFields[field.Key] {
+			m.inputForm.SetCompleter(field.Key, book.Complete)
+		}
+	}
+}
+
+// SetTableLayouts shares the saved column layouts of the result tables
+func (m *DiagnosticViewModel) SetTableLayouts(layouts *TableLayouts) {
+	m.resultView.SetTableLayouts(layouts)
+}
+
+// SetKeyMap sets the key bindings of the view and its results
+func (m *DiagnosticViewModel) SetKeyMap(keyMap KeyMap) {
+	m.keyMap = keyMap
+	m.resultView.SetKeyMap(keyMap)
+}
+
+// SetExportDirectory sets where reports exported from the result view are written
+func (m *DiagnosticViewModel) SetExportDirectory(dir string) {
+	m.resultView.SetExportDirectory(dir)
+}
+
+// SetExportFormat sets the format preselected when saving a result
+func (m *DiagnosticViewModel) SetExportFormat(format domain.ExportFormat) {
+	m.resultView.SetExportFormat(format)
+}
+
+// CapturesInput reports whether a live screen is shown, the result view is
+// editing a file name or the form has a suggested target highlighted
+func (m *DiagnosticViewModel) CapturesInput() bool {
+	if m.state == DiagnosticStateLive {
+		return true
+	}
+	if m.state == DiagnosticStateInput {
+		return m.inputForm.CapturesInput()
+	}
+	return m.state == DiagnosticStateResult && m.resultView != nil && m.resultView.CapturesInput()
+}
+
+// FormValues returns the values currently entered in the input form
+func (m *DiagnosticViewModel) FormValues() map[string]string {
+	return m.inputForm.GetValues()
+}
+
+// SetFormValues fills the input form, for example with targets from a saved session
+func (m *DiagnosticViewModel) SetFormValues(values map[string]string) {
+	for key, value := range values {
+		m.inputForm.SetFieldValue(key, value)
+	}
+}
+
+// ShowResult displays a result that is already in the history, such as the
+// last result of a restored session
+func (m *DiagnosticViewModel) ShowResult(result domain.Result) {
+	m.state = DiagnosticStateResult
+	m.result = result
+	m.inputForm.Blur()
+	m.resultView.showResult(result)
+}
+
+// Resume runs the diagnostic again with the values in the input form, to
+// continue a run that was interrupted when the previous session ended
+func (m *DiagnosticViewModel) Resume() tea.Cmd {
+	return m.executeDiagnostic(m.FormValues())
+}
+
+// Cancel stops the running diagnostic; the view then shows the error the
+// tool returns when cancelled. A live screen is closed, showing the form.
+func (m *DiagnosticViewModel) Cancel() {
+	if m.state == DiagnosticStateLive {
+		m.cancelled = true
+		m.stopLive()
+		return
+	}
+	if m.loading && m.cancel != nil {
+		m.cancelled = true
+		m.cancel()
+	}
+}
+
+// IsLive reports whether a live screen is shown
+func (m *DiagnosticViewModel) IsLive() bool {
+	return m.state == DiagnosticStateLive
+}
+
+// stopLive ends the runs of the live screen and shows the form again
+func (m *DiagnosticViewModel) stopLive() {
+	if m.state != DiagnosticStateLive {
+		return
+	}
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.live = nil
+	m.state = DiagnosticStateInput
+	m.inputForm.Focus()
+}
+
+// Cancelled reports whether the last run was cancelled
+func (m *DiagnosticViewModel) Cancelled() bool {
+	return m.cancelled
+}
+
+// StartedAt returns when the last run started
+func (m *DiagnosticViewModel) StartedAt() time.Time {
+	return m.started
+}
+
+// Target returns the host, domain or range of the last run
+func (m *DiagnosticViewModel) Target() string {
+	for _, key := range []string{"host", "query", "domain", "cidr"} {
+		if target := strings.TrimSpace(m.lastValues[key]); target != "" {
+			return target
+		}
+	}
+	return ""
+}
+
+// Expected estimates how long the last run takes, or returns 0 when the
+// tool gives no estimate. A ping run lasts about count × interval.
+func (m *DiagnosticViewModel) Expected() time.Duration {
+	if m.tool.Name() != "ping" || m.lastValues == nil {
+		return 0
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(m.lastValues["count"]))
+	if err != nil || count <= 0 {
+		count = 4
+	}
+	interval := time.Second
+	if seconds, err := strconv.ParseFloat(strings.TrimSpace(m.lastValues["interval"]), 64); err == nil && seconds > 0 {
+		interval = time.Duration(seconds * float64(time.Second))
+	}
+	return time.Duration(count) * interval
+}
+
+// ExportResult writes the displayed result in format to the export
+// directory, or returns nil when no result is shown
+func (m *DiagnosticViewModel) ExportResult(format domain.ExportFormat, extension string) tea.Cmd {
+	if m.state != DiagnosticStateResult {
+		return nil
+	}
+	return m.resultView.exportReport(format, extension)
+}
+
 // GetTool returns the underlying diagnostic tool
 func (m *DiagnosticViewModel) GetTool() domain.DiagnosticTool {
 	return m.tool
@@ -425,6 +798,11 @@ type DiagnosticErrorMsg struct {
 	Error error
 }
 
+// DiagnosticLiveMsg carries the live screen a tool runs on in place of a result
+type DiagnosticLiveMsg struct {
+	Model tea.Model
+}
+
 // SSL-specific messages
 type SSLCheckCompleteMsg struct {
 	Result domain.SSLResult
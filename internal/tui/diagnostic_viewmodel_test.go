@@ -337,23 +337,23 @@ func TestDiagnosticViewModel_FormFieldConfiguration(t *testing.T) {
 	}{
 		{
 			toolName:      "whois",
-			expectedFields: []string{"query"},
+			expectedFields: []string{"query", "source"},
 		},
 		{
 			toolName:      "ping",
-			expectedFields: []string{"host", "count"},
+			expectedFields: []string{"host", "count", "interval", "mode", "source"},
 		},
 		{
 			toolName:      "dns",
-			expectedFields: []string{"domain", "record_type"},
+			expectedFields: []string{"domain", "record_type", "server", "source"},
 		},
 		{
 			toolName:      "ssl",
-			expectedFields: []string{"host", "port"},
+			expectedFields: []string{"host", "port", "source"},
 		},
 		{
 			toolName:      "traceroute",
-			expectedFields: []string{"host", "max_hops"},
+			expectedFields: []string{"host", "max_hops", "protocol", "port", "repeat", "source"},
 		},
 	}
 
@@ -411,4 +411,61 @@ func TestDiagnosticViewModel_StateTransitions(t *testing.T) {
 	assert.Equal(t, DiagnosticStateInput, viewModel.GetState())
 
 	mockTool.AssertExpectations(t)
-}
\ No newline at end of file
+}
+// liveTool runs on a live screen instead of returning a result
+type liveTool struct {
+	blockingTool
+	screen *liveScreen
+}
+
+func (t *liveTool) LiveModel(ctx context.Context, params domain.Parameters) (tea.Model, error) {
+	t.screen.ctx = ctx
+	return t.screen, nil
+}
+
+// liveScreen records the keys it receives and leaves on esc
+type liveScreen struct {
+	ctx     context.Context
+	started bool
+	keys    []string
+}
+
+func (s *liveScreen) Init() tea.Cmd {
+	s.started = true
+	return nil
+}
+
+func (s *liveScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		s.keys = append(s.keys, msg.String())
+		if msg.String() == "esc" {
+			return s, func() tea.Msg { return domain.LiveExitMsg{} }
+		}
+	}
+	return s, nil
+}
+
+func (s *liveScreen) View() string { return "live screen" }
+
+func TestDiagnosticViewModel_LiveScreen(t *testing.T) {
+	tool := &liveTool{screen: &liveScreen{}}
+	view := NewDiagnosticViewModel(tool)
+
+	_, cmd := view.Update(FormSubmitMsg{Values: map[string]string{"host": "example.com", "count": "0"}})
+	for _, msg := range runTabCmd(cmd) {
+		_, cmd = view.Update(msg)
+	}
+	assert.True(t, view.IsLive())
+	assert.True(t, tool.screen.started)
+	assert.False(t, view.IsLoading(), "a live screen is not a job to wait for")
+	assert.Equal(t, "live screen", view.View())
+	assert.True(t, view.CapturesInput(), "keys such as q and esc belong to the screen")
+
+	view.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	_, cmd = view.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, []string{"q", "esc"}, tool.screen.keys)
+	view.Update(cmd())
+
+	assert.Equal(t, DiagnosticStateInput, view.GetState())
+	assert.Error(t, tool.screen.ctx.Err(), "leaving the screen stops its runs")
+}
@@ -0,0 +1,204 @@
+// Package tui contains the DNS server health diagnostics screen
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
+)
+
+// dnsServerCheckTimeout bounds a full health check of all configured servers
+const dnsServerCheckTimeout = 10 * time.Second
+
+// DNSServerCheckMsg carries the results of a DNS server health check
+type DNSServerCheckMsg struct {
+	Statuses []domain.DNSServerStatus
+}
+
+// DNSServersViewModel shows the health of the configured DNS servers
+type DNSServersViewModel struct {
+	reporter domain.DNSServerReporter
+	table    *TableModel
+	statuses []domain.DNSServerStatus
+	checking bool
+	width    int
+	height   int
+	theme    domain.Theme
+	refresh  key.Binding
+}
+
+// NewDNSServersViewModel creates a DNS server health view backed by reporter
+func NewDNSServersViewModel(reporter domain.DNSServerReporter) *DNSServersViewModel {
+	m := &DNSServersViewModel{
+		reporter: reporter,
+		table:    NewTableModel([]string{"#", "Server", "Status", "Last Response", "OK", "Failed", "Last Error"}),
+		refresh: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", i18n.T("key.check_servers")),
+		),
+	}
+	if reporter != nil {
+		m.setStatuses(reporter.DNSServerStatus())
+	}
+	return m
+}
+
+// Init implements tea.Model and starts a health check
+func (m *DNSServersViewModel) Init() tea.Cmd {
+	return m.check()
+}
+
+// Update implements tea.Model
+func (m *DNSServersViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		if mouse, ok := mouseOver(msg, m.View(), m.table.View()); ok {
+			m.table.Update(mouse)
+		}
+		return m, nil
+	case tea.KeyMsg:
+		if m.table.CapturesInput() {
+			break
+		}
+		if key.Matches(msg, m.refresh) && !m.checking {
+			return m, m.check()
+		}
+	case DNSServerCheckMsg:
+		m.checking = false
+		m.setStatuses(msg.Statuses)
+		return m, nil
+	}
+
+	_, cmd := m.table.Update(msg)
+	return m, cmd
+}
+
+// View implements tea.Model
+func (m *DNSServersViewModel) View() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).MarginBottom(1)
+	mutedStyle := lipgloss.NewStyle().Foreground(colors.Muted).Italic(true)
+	if m.theme != nil {
+		titleStyle = titleStyle.Foreground(colors.Adaptive(m.theme.GetColor("primary")))
+		mutedStyle = mutedStyle.Foreground(colors.Adaptive(m.theme.GetColor("muted")))
+	}
+
+	if m.reporter == nil {
+		return titleStyle.Render("DNS Server Health") + "\n\n" + mutedStyle.Render("DNS server health is not available for this network client")
+	}
+
+	status := screenHelp(m.width).ShortHelpView(m.ShortHelp())
+	if m.checking {
+		status = mutedStyle.Render("Checking servers...")
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render("DNS Server Health"),
+		mutedStyle.Render("Servers are tried in order; lookups fail over to the next server on error"),
+		"",
+		m.table.View(),
+		"",
+		status,
+	)
+}
+
+// ShortHelp implements help.KeyMap
+func (m *DNSServersViewModel) ShortHelp() []key.Binding {
+	return []key.Binding{m.refresh}
+}
+
+// FullHelp implements help.KeyMap
+func (m *DNSServersViewModel) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+// SetSize implements domain.TUIComponent
+func (m *DNSServersViewModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.table.SetSize(width, height-6)
+}
+
+// SetTheme implements domain.TUIComponent
+func (m *DNSServersViewModel) SetTheme(theme domain.Theme) {
+	m.theme = theme
+	m.table.SetTheme(theme)
+}
+
+// SetKeyMap implements keyMapSetter
+func (m *DNSServersViewModel) SetKeyMap(keyMap KeyMap) {
+	m.table.SetKeyMap(keyMap)
+}
+
+// CapturesInput reports whether the vi search prompt of the table is open
+func (m *DNSServersViewModel) CapturesInput() bool {
+	return m.table.CapturesInput()
+}
+
+// JumpTo selects the row on line, counting from 1
+func (m *DNSServersViewModel) JumpTo(line int) {
+	m.table.JumpTo(line)
+}
+
+// Focus implements domain.TUIComponent
+func (m *DNSServersViewModel) Focus() {
+	m.table.Focus()
+}
+
+// Blur implements domain.TUIComponent
+func (m *DNSServersViewModel) Blur() {
+	m.table.Blur()
+}
+
+// Statuses returns the most recently reported server statuses
+func (m *DNSServersViewModel) Statuses() []domain.DNSServerStatus {
+	return m.statuses
+}
+
+// check probes all configured servers in the background
+func (m *DNSServersViewModel) check() tea.Cmd {
+	if m.reporter == nil {
+		return nil
+	}
+	m.checking = true
+	reporter := m.reporter
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), dnsServerCheckTimeout)
+		defer cancel()
+		return DNSServerCheckMsg{Statuses: reporter.CheckDNSServers(ctx)}
+	}
+}
+
+// setStatuses updates the table from server statuses
+func (m *DNSServersViewModel) setStatuses(statuses []domain.DNSServerStatus) {
+	m.statuses = statuses
+
+	rows := make([][]string, 0, len(statuses))
+	for i, status := range statuses {
+		state := "unchecked"
+		response := "-"
+		if status.Checked {
+			state = "healthy"
+			if !status.Healthy {
+				state = "failing"
+			}
+			response = status.LastResponseTime.Round(time.Millisecond).String()
+		}
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", i+1),
+			status.Server,
+			state,
+			response,
+			fmt.Sprintf("%d", status.Successes),
+			fmt.Sprintf("%d", status.Failures),
+			status.LastError,
+		})
+	}
+	m.table.SetData(rows)
+}
@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDNSReporter returns fixed DNS server statuses
+type fakeDNSReporter struct {
+	statuses []domain.DNSServerStatus
+	checks   int
+}
+
+func (f *fakeDNSReporter) DNSServerStatus() []domain.DNSServerStatus {
+	return f.statuses
+}
+
+func (f *fakeDNSReporter) CheckDNSServers(ctx context.Context) []domain.DNSServerStatus {
+	f.checks++
+	for i := range f.statuses {
+		f.statuses[i].Checked = true
+		f.statuses[i].Healthy = i == 0
+		f.statuses[i].LastResponseTime = 12 * time.Millisecond
+	}
+	return f.statuses
+}
+
+func TestDNSServersViewModel_Check(t *testing.T) {
+	reporter := &fakeDNSReporter{statuses: []domain.DNSServerStatus{{Server: "8.8.8.8"}, {Server: "1.1.1.1"}}}
+	model := NewDNSServersViewModel(reporter)
+
+	assert.Len(t, model.Statuses(), 2)
+	assert.Contains(t, model.View(), "unchecked")
+
+	cmd := model.Init()
+	assert.NotNil(t, cmd)
+	assert.Contains(t, model.View(), "Checking servers")
+
+	updated, _ := model.Update(cmd())
+	view := updated.View()
+	assert.Equal(t, 1, reporter.checks)
+	assert.Contains(t, view, "healthy")
+	assert.Contains(t, view, "failing")
+
+	_, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	assert.NotNil(t, cmd)
+}
+
+func TestDNSServersViewModel_NoReporter(t *testing.T) {
+	model := NewDNSServersViewModel(nil)
+
+	assert.Nil(t, model.Init())
+	assert.Contains(t, model.View(), "not available")
+}
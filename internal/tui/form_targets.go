@@ -0,0 +1,216 @@
+// Package tui contains the completion of the target fields of the tool forms
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
+	"github.com/nettracex/nettracex-tui/internal/targets"
+)
+
+// maxSuggestions is how many targets the dropdown of a field offers
+const maxSuggestions = 6
+
+// targetFields are the form fields that take a host, domain or address
+var targetFields = map[string]bool{
+	"query":  true,
+	"host":   true,
+	"domain": true,
+	"target": true,
+}
+
+// suggestionKeyMap holds the keys of the dropdown of a target field
+type suggestionKeyMap struct {
+	Next   key.Binding
+	Prev   key.Binding
+	Accept key.Binding
+	Close  key.Binding
+}
+
+// suggestionKeys returns the keys of the dropdown, described in the current
+// language
+func suggestionKeys() suggestionKeyMap {
+	return suggestionKeyMap{
+		Next:   key.NewBinding(key.WithKeys("down", "ctrl+n"), key.WithHelp("↓/ctrl+n", i18n.T("key.pick_suggestion"))),
+		Prev:   key.NewBinding(key.WithKeys("up", "ctrl+p"), key.WithHelp("↑/ctrl+p", i18n.T("key.previous_suggestion"))),
+		Accept: hint(i18n.T("key.fill_suggestion"), "tab", "enter"),
+		Close:  hint(i18n.T("key.close_suggestions"), "esc"),
+	}
+}
+
+// TargetBook keeps the recently used and starred targets, saving them for the
+// next launch when it has a path, and completes the target fields of the tool
+// forms from them and from the hosts of the system
+type TargetBook struct {
+	path  string
+	list  *targets.List
+	hosts []targets.Host
+}
+
+// NewTargetBook creates the targets saved at path, starting from list and
+// also suggesting hosts; an empty path keeps them for this run only
+func NewTargetBook(path string, list *targets.List, hosts []targets.Host) *TargetBook {
+	if list == nil {
+		list = &targets.List{}
+	}
+	return &TargetBook{path: path, list: list, hosts: hosts}
+}
+
+// Complete returns the targets suggested for input
+func (b *TargetBook) Complete(input string) []targets.Suggestion {
+	return b.list.Complete(input, b.hosts, maxSuggestions)
+}
+
+// Use records the targets of a run. A field can hold a comma-separated list
+// of targets; @file references are not targets themselves.
+func (b *TargetBook) Use(values map[string]string) error {
+	used := false
+	for key, value := range values {
+		if !targetFields[key] {
+			continue
+		}
+		for _, target := range strings.Split(value, ",") {
+			if target = strings.TrimSpace(target); target != "" && !strings.HasPrefix(target, "@") {
+				b.list.Use(target)
+				used = true
+			}
+		}
+	}
+	if !used {
+		return nil
+	}
+	return b.save()
+}
+
+// ToggleStar stars target or unstars it, reporting whether it is starred now
+func (b *TargetBook) ToggleStar(target string) (bool, error) {
+	starred := b.list.ToggleStar(target)
+	return starred, b.save()
+}
+
+// save writes the targets when they are kept between runs
+func (b *TargetBook) save() error {
+	if b.path == "" {
+		return nil
+	}
+	return targets.Save(b.path, b.list)
+}
+
+// SetCompleter makes the field key offer the targets complete returns for
+// what was typed in a dropdown
+func (m *FormModel) SetCompleter(key string, complete func(input string) []targets.Suggestion) {
+	for i := range m.fields {
+		if m.fields[i].Key == key {
+			m.fields[i].Complete = complete
+		}
+	}
+	m.refreshSuggestions()
+}
+
+// refreshSuggestions offers the targets for the value of the focused field,
+// with none highlighted
+func (m *FormModel) refreshSuggestions() {
+	m.suggestions = nil
+	m.suggestion = -1
+	if m.focused < 0 || m.focused >= len(m.fields) || m.fields[m.focused].Complete == nil {
+		return
+	}
+	m.suggestions = m.fields[m.focused].Complete(m.fields[m.focused].Input.Value())
+}
+
+// updateSuggestions handles the keys of the dropdown: down or ctrl+n and up
+// or ctrl+p highlight a target, tab or enter put the highlighted one in the
+// field and esc closes the dropdown. It reports whether the key was used.
+func (m *FormModel) updateSuggestions(msg tea.KeyMsg) bool {
+	if len(m.suggestions) == 0 {
+		return false
+	}
+	keys := suggestionKeys()
+	switch {
+	case key.Matches(msg, keys.Next):
+		if m.suggestion < len(m.suggestions)-1 {
+			m.suggestion++
+		}
+		return true
+	case key.Matches(msg, keys.Prev):
+		if m.suggestion < 0 {
+			return false
+		}
+		m.suggestion--
+		return true
+	case key.Matches(msg, keys.Accept):
+		if m.suggestion < 0 {
+			return false
+		}
+		input := &m.fields[m.focused].Input
+		input.SetValue(m.suggestions[m.suggestion].Target)
+		input.CursorEnd()
+		m.refreshSuggestions()
+		return true
+	case key.Matches(msg, keys.Close):
+		if m.suggestion < 0 {
+			return false
+		}
+		m.suggestions = nil
+		m.suggestion = -1
+		return true
+	}
+	return false
+}
+
+// CapturesInput reports whether a suggested target is highlighted, so that
+// enter and esc act on the dropdown rather than the form
+func (m *FormModel) CapturesInput() bool {
+	return m.suggestion >= 0 && m.suggestion < len(m.suggestions)
+}
+
+// CompletionTarget returns the key of the focused field when it completes
+// targets and the target it shows: the highlighted suggestion, or the value
+func (m *FormModel) CompletionTarget() (string, string, bool) {
+	if m.focused < 0 || m.focused >= len(m.fields) || m.fields[m.focused].Complete == nil {
+		return "", "", false
+	}
+	if m.CapturesInput() {
+		return m.fields[m.focused].Key, m.suggestions[m.suggestion].Target, true
+	}
+	return m.fields[m.focused].Key, strings.TrimSpace(m.fields[m.focused].Input.Value()), true
+}
+
+// renderSuggestions renders the dropdown of the focused field
+func (m *FormModel) renderSuggestions() string {
+	if len(m.suggestions) == 0 {
+		return ""
+	}
+
+	sourceStyle := lipgloss.NewStyle().Foreground(colors.Subtle)
+	highlightStyle := lipgloss.NewStyle().Background(colors.Primary).Foreground(colors.Highlight)
+	lines := make([]string, 0, len(m.suggestions)+1)
+	for i, suggestion := range m.suggestions {
+		marker := "  "
+		if suggestion.Starred {
+			marker = "★ "
+		}
+		line := marker + suggestion.Target
+		if i == m.suggestion {
+			line = highlightStyle.Render(line)
+		}
+		if !suggestion.Starred {
+			line += sourceStyle.Render("  " + suggestion.Source)
+		}
+		lines = append(lines, line)
+	}
+	keys := suggestionKeys()
+	lines = append(lines, sourceStyle.Italic(true).Render(fmt.Sprintf("%d suggested", len(m.suggestions))+" • "+
+		screenHelp(0).ShortHelpView([]key.Binding{keys.Next, keys.Accept, diagnosticKeys().Star})))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(colors.Border).
+		PaddingLeft(1).
+		Render(strings.Join(lines, "\n"))
+}
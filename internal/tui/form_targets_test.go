@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/nettracex/nettracex-tui/internal/targets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormModel_Suggestions(t *testing.T) {
+	book := NewTargetBook("", &targets.List{Recent: []string{"mail.example.com", "example.org"}},
+		[]targets.Host{{Name: "router.lan", Source: targets.SourceHosts}})
+	form := NewFormModel("ping")
+	form.AddField("host", "Host", true)
+	form.AddField("count", "Count", false)
+	form.SetCompleter("host", book.Complete)
+	form.Focus()
+
+	assert.Len(t, form.suggestions, 2, "an empty field offers the recent targets")
+	typeKeys(form, "ro")
+	require.Len(t, form.suggestions, 1)
+	assert.Contains(t, ansi.Strip(form.View()), "router.lan  hosts")
+	assert.False(t, form.CapturesInput())
+
+	// Pick and fill in the suggestion
+	form.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.True(t, form.CapturesInput())
+	form.Update(tea.KeyMsg{Type: tea.KeyTab})
+	assert.Equal(t, "router.lan", form.GetFieldValue("host"))
+	assert.Empty(t, form.suggestions, "the filled in target is not suggested again")
+	assert.Equal(t, 0, form.focused)
+
+	// Esc closes the dropdown, up leaves it
+	form.SetFieldValue("host", "ex")
+	form.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	form.Update(tea.KeyMsg{Type: tea.KeyUp})
+	assert.False(t, form.CapturesInput())
+	form.Update(tea.KeyMsg{Type: tea.KeyDown})
+	form.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Empty(t, form.suggestions)
+
+	// Fields without a completer have no dropdown
+	form.Update(tea.KeyMsg{Type: tea.KeyTab})
+	assert.Equal(t, 1, form.focused)
+	assert.Empty(t, form.suggestions)
+}
+
+func TestDiagnosticViewModel_Targets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), targets.FileName)
+	book := NewTargetBook(path, nil, nil)
+
+	view := NewDiagnosticViewModel(&dashboardTool{name: "ping"})
+	view.SetTargets(book)
+	view.Focus()
+	view.Update(FormSubmitMsg{Values: map[string]string{"host": "a.example, @hosts.txt ,b.example", "count": "4"}})
+
+	saved, err := targets.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b.example", "a.example"}, saved.Recent, "each target of a run is remembered")
+
+	view.inputForm.SetFieldValue("host", "")
+	view.Update(tea.KeyMsg{Type: tea.KeyDown})
+	require.True(t, view.CapturesInput(), "the dropdown takes the keys")
+	view.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	saved, err = targets.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b.example"}, saved.Starred)
+	assert.True(t, view.inputForm.suggestions[0].Starred, "starred targets are offered first")
+	assert.Contains(t, view.View(), "★ b.example")
+}
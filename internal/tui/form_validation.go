@@ -0,0 +1,130 @@
+// Package tui contains the live validation of the tool forms
+package tui
+
+import (
+	"net"
+	"strings"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/tools/ping"
+	"github.com/nettracex/nettracex-tui/internal/validate"
+)
+
+// fieldCheck adapts a check of one typed value to the validator of a form field
+type fieldCheck validate.Check
+
+// Validate implements domain.Validator
+func (c fieldCheck) Validate(data interface{}) error {
+	value, _ := data.(string)
+	return c(value)
+}
+
+// ValidateField implements domain.Validator
+func (c fieldCheck) ValidateField(field string, value interface{}) error {
+	return c.Validate(value)
+}
+
+// GetRules implements domain.Validator
+func (c fieldCheck) GetRules() map[string][]domain.ValidationRule {
+	return nil
+}
+
+// toolChecks are the checks of the form fields of the built-in tools. Fields
+// left empty are not checked: optional ones use their defaults.
+var toolChecks = map[string]map[string]validate.Check{
+	"whois": {"query": validate.Host},
+	"ping": {
+		"host":     pingTargets,
+		"count":    validate.Int(0, 9999),
+		"interval": validate.Seconds(60),
+		"mode":     validate.OneOf("normal", "adaptive", "flood"),
+	},
+	"dns": {
+		"domain":      dnsName,
+		"record_type": validate.OneOf("A", "AAAA", "MX", "TXT", "CNAME", "NS", "SOA", "PTR", "ALL"),
+		"server":      validate.DNSServer,
+	},
+	"ssl": {"host": validate.Host, "port": validate.Port},
+	"traceroute": {
+		"host":     validate.Host,
+		"max_hops": validate.Int(1, 255),
+		"protocol": validate.OneOf("icmp", "udp", "tcp"),
+		"port":     validate.Port,
+		"repeat":   validate.Seconds(3600),
+	},
+	"dualstack": {"host": validate.Host, "port": validate.Port},
+	"axfr":      {"domain": validate.Domain},
+	// The limits of the sweep tool, which cannot be imported here
+	"sweep": {
+		"cidr":        validate.CIDR(1024),
+		"concurrency": validate.Int(1, 256),
+	},
+}
+
+// pingTargets accepts the host list or @file the ping tool takes
+func pingTargets(value string) error {
+	targets, err := ping.ParseTargets(value)
+	if err != nil {
+		return err
+	}
+	for _, target := range targets {
+		if err := validate.Host(target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dnsName accepts a domain to look up, or an address for a PTR lookup
+func dnsName(value string) error {
+	if net.ParseIP(strings.TrimSpace(value)) != nil {
+		return nil
+	}
+	return validate.Domain(value)
+}
+
+// SetValidator makes the field key check its value as it is typed
+func (m *FormModel) SetValidator(key string, validator domain.Validator) {
+	for i := range m.fields {
+		if m.fields[i].Key == key {
+			m.fields[i].Validator = validator
+			m.checkField(i)
+		}
+	}
+}
+
+// checkField shows the error of the value of field i next to it. An empty
+// field is only pointed out once submitting failed because it is required.
+func (m *FormModel) checkField(i int) {
+	field := &m.fields[i]
+	field.ErrorText = ""
+	value := strings.TrimSpace(field.Input.Value())
+	if value == "" {
+		if field.Required && m.attempted {
+			field.ErrorText = "This field is required"
+		}
+		return
+	}
+	if field.Validator != nil {
+		if err := field.Validator.Validate(field.Input.Value()); err != nil {
+			field.ErrorText = err.Error()
+		}
+	}
+}
+
+// Valid reports whether every field holds a value that can be submitted
+func (m *FormModel) Valid() bool {
+	for _, field := range m.fields {
+		value := strings.TrimSpace(field.Input.Value())
+		if value == "" {
+			if field.Required {
+				return false
+			}
+			continue
+		}
+		if field.Validator != nil && field.Validator.Validate(field.Input.Value()) != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormModel_Validation(t *testing.T) {
+	view := NewDiagnosticViewModel(&dashboardTool{name: "ping"})
+	view.Focus()
+	form := view.inputForm
+
+	assert.False(t, form.Valid(), "the required host is empty")
+	assert.Empty(t, form.fields[0].ErrorText, "an empty field is not pointed out before submitting")
+	assert.Contains(t, view.View(), "enter submit once the fields marked * are filled in")
+
+	typeKeys(form, "bad host!")
+	assert.Contains(t, form.fields[0].ErrorText, "not a valid hostname")
+	form.SetFieldValue("host", "example.com, 10.0.0.1")
+	assert.Empty(t, form.fields[0].ErrorText)
+	assert.True(t, form.Valid())
+
+	form.SetFieldValue("count", "10000")
+	form.SetFieldValue("interval", "fast")
+	assert.Equal(t, "enter a whole number from 0 to 9999", form.fields[1].ErrorText)
+	assert.Contains(t, form.fields[2].ErrorText, "seconds above 0")
+	assert.False(t, form.Valid())
+
+	// Enter does not submit while a field is invalid
+	_, cmd := form.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, cmd)
+
+	form.SetFieldValue("count", "")
+	form.SetFieldValue("interval", "0.5")
+	assert.True(t, form.Valid(), "an empty optional field uses its default")
+	assert.NotContains(t, view.View(), "submit once")
+}
+
+func TestFormModel_RequiredAfterSubmit(t *testing.T) {
+	form := NewFormModel("whois")
+	form.AddField("query", "Query", true)
+	form.SetValidator("query", fieldCheck(toolChecks["whois"]["query"]))
+	form.Focus()
+
+	assert.False(t, form.validate())
+	assert.Equal(t, "This field is required", form.fields[0].ErrorText)
+	form.SetFieldValue("query", "example.com")
+	assert.True(t, form.validate())
+}
+
+func TestToolChecks(t *testing.T) {
+	tests := []struct {
+		tool, field, value string
+		valid              bool
+	}{
+		{"dns", "domain", "example.com", true},
+		{"dns", "domain", "8.8.8.8", true},
+		{"dns", "record_type", "mx", true},
+		{"dns", "record_type", "B", false},
+		{"dns", "server", "1.1.1.1:53", true},
+		{"ping", "host", "@missing-file.txt", false},
+		{"ping", "mode", "turbo", false},
+		{"traceroute", "max_hops", "300", false},
+		{"sweep", "cidr", "192.168.1.0/24", true},
+		{"sweep", "cidr", "10.0.0.0/8", false},
+		{"axfr", "domain", "10.0.0.1", false},
+	}
+	for _, tt := range tests {
+		err := toolChecks[tt.tool][tt.field](tt.value)
+		assert.Equal(t, tt.valid, err == nil, "%s %s %q: %v", tt.tool, tt.field, tt.value, err)
+	}
+}
@@ -2,13 +2,14 @@
 package tui
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
 	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
 )
 
 // HelpModel displays help information and keyboard shortcuts using viewport for smooth scrolling
@@ -67,6 +68,11 @@ func (m *HelpModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 
+	case tea.MouseMsg:
+		// The viewport scrolls with the wheel
+		_, cmd = m.viewport.Update(msg)
+		cmds = append(cmds, cmd)
+
 	case tea.WindowSizeMsg:
 		headerHeight := 2 // Space for title
 		footerHeight := 2 // Space for help text
@@ -166,6 +172,21 @@ func (m *HelpModel) SetTheme(theme domain.Theme) {
 	}
 }
 
+// SetKeyMap sets the keys that close the help
+func (m *HelpModel) SetKeyMap(keyMap KeyMap) {
+	m.keyMap = keyMap
+}
+
+// ShortHelp implements help.KeyMap
+func (m *HelpModel) ShortHelp() []key.Binding {
+	return []key.Binding{m.keyMap.Up, m.keyMap.Down, m.keyMap.PageUp, m.keyMap.PageDown}
+}
+
+// FullHelp implements help.KeyMap
+func (m *HelpModel) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{m.keyMap.Up, m.keyMap.Down, m.keyMap.PageUp, m.keyMap.PageDown, m.keyMap.Home, m.keyMap.End, m.keyMap.Back}}
+}
+
 // Focus implements domain.TUIComponent
 func (m *HelpModel) Focus() {
 	m.focused = true
@@ -190,11 +211,11 @@ func (m *HelpModel) Blur() {
 func (m *HelpModel) headerView() string {
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
+		Foreground(colors.Info).
 		Align(lipgloss.Center).
 		Width(m.width)
 
-	return titleStyle.Render("NetTraceX Help")
+	return titleStyle.Render(i18n.T("help.title"))
 }
 
 // footerView renders the help footer
@@ -206,8 +227,8 @@ func (m *HelpModel) footerView() string {
 	}
 	
 	info := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
-		Render(fmt.Sprintf("%.0f%% • Press Esc or ? to close • Use ↑/↓ PgUp/PgDown to scroll", scrollPercent))
+		Foreground(colors.Subtle).
+		Render(i18n.T("help.footer", scrollPercent))
 	
 	line := strings.Repeat("─", max(0, m.width-lipgloss.Width(info)))
 	return lipgloss.JoinHorizontal(lipgloss.Center, line, info)
@@ -218,44 +239,56 @@ func (m *HelpModel) generateHelpContent() string {
 	var content strings.Builder
 	
 	// Navigation & Scrolling section
-	content.WriteString(m.renderHelpSection("Navigation & Scrolling", []HelpItem{
-		NewHelpItem("↑/↓ or j/k", "Navigate up/down in menus or scroll content"),
-		NewHelpItem("←/→ or h/l", "Navigate left/right (when applicable)"),
-		NewHelpItem("PgUp/PgDown", "Scroll page up/down in help and results"),
-		NewHelpItem("Home/End", "Jump to top/bottom of scrollable content"),
-		NewHelpItem("Enter", "Select menu item or execute action"),
-		NewHelpItem("Esc", "Return to tool input"),
-		NewHelpItem("Tab", "Switch between input fields"),
+	content.WriteString(m.renderHelpSection(i18n.T("help.navigation"), []HelpItem{
+		NewHelpItem("↑/↓ or j/k", i18n.T("help.navigation.up_down")),
+		NewHelpItem("←/→ or h/l", i18n.T("help.navigation.left_right")),
+		NewHelpItem("PgUp/PgDown", i18n.T("help.navigation.page")),
+		NewHelpItem("Home/End", i18n.T("help.navigation.jump")),
+		NewHelpItem("Enter", i18n.T("help.navigation.enter")),
+		NewHelpItem("Esc", i18n.T("help.navigation.esc")),
+		NewHelpItem("Tab", i18n.T("help.navigation.tab")),
 	}))
 	
 	// Tool Operations section
-	content.WriteString(m.renderHelpSection("Tool Operations", []HelpItem{
-		NewHelpItem("Enter", "Execute diagnostic tool with current parameters"),
-		NewHelpItem("f/t/r", "Switch between formatted/table/raw result views"),
-		NewHelpItem("Tab", "Cycle through result view modes"),
-		NewHelpItem("s", "Save configuration (in settings)"),
-		NewHelpItem("e", "Export results (when available)"),
+	content.WriteString(m.renderHelpSection(i18n.T("help.tools"), []HelpItem{
+		NewHelpItem("Enter", i18n.T("help.tools.enter")),
+		NewHelpItem("f/t/r", i18n.T("help.tools.views")),
+		NewHelpItem("Tab", i18n.T("help.tools.tab")),
+		NewHelpItem("d", i18n.T("help.tools.compare")),
+		NewHelpItem("[ ] { }", i18n.T("help.tools.pick")),
+		NewHelpItem("H/M", i18n.T("help.tools.report")),
+		NewHelpItem("s", i18n.T("help.tools.save")),
+		NewHelpItem("y", i18n.T("help.tools.copy")),
+		NewHelpItem("Y", i18n.T("help.tools.copy_json")),
+		NewHelpItem("e", i18n.T("help.tools.export")),
+		NewHelpItem("/ & n/N", i18n.T("help.tools.search")),
+		NewHelpItem("← → s x < > [ ]", i18n.T("help.tools.columns")),
+		NewHelpItem("↓ Tab Ctrl+S", i18n.T("help.tools.targets")),
+		NewHelpItem("Ctrl+R", i18n.T("help.tools.rerun")),
+		NewHelpItem("w", i18n.T("help.tools.watch")),
 	}))
 	
 	// Tips & Examples section
-	content.WriteString(m.renderHelpSection("Tips & Examples", []HelpItem{
-		NewHelpItem("Domain examples", "google.com, github.io, example.dev, lavan.dev"),
-		NewHelpItem("IP examples", "8.8.8.8, 1.1.1.1, 192.168.1.1"),
-		NewHelpItem("Ping counts", "Use 1-100 for ping count (default: 4)"),
-		NewHelpItem("DNS records", "A, AAAA, MX, TXT, CNAME, NS supported"),
-		NewHelpItem("SSL ports", "443 (HTTPS), 993 (IMAPS), 995 (POP3S)"),
-		NewHelpItem("WHOIS queries", "Works with domains and IP addresses"),
-		NewHelpItem("Traceroute", "Shows network path with hop details"),
+	content.WriteString(m.renderHelpSection(i18n.T("help.tips"), []HelpItem{
+		NewHelpItem(i18n.T("help.tips.domains"), "google.com, github.io, example.dev, lavan.dev"),
+		NewHelpItem(i18n.T("help.tips.ips"), "8.8.8.8, 1.1.1.1, 192.168.1.1"),
+		NewHelpItem(i18n.T("help.tips.ping"), i18n.T("help.tips.ping.text")),
+		NewHelpItem(i18n.T("help.tips.dns"), i18n.T("help.tips.dns.text")),
+		NewHelpItem(i18n.T("help.tips.ssl"), "443 (HTTPS), 993 (IMAPS), 995 (POP3S)"),
+		NewHelpItem(i18n.T("help.tips.whois"), i18n.T("help.tips.whois.text")),
+		NewHelpItem(i18n.T("help.tips.traceroute"), i18n.T("help.tips.traceroute.text")),
+		NewHelpItem(i18n.T("help.tips.sessions"), i18n.T("help.tips.sessions.text")),
 	}))
 	
 	// Troubleshooting section
-	content.WriteString(m.renderHelpSection("Troubleshooting", []HelpItem{
-		NewHelpItem("No results", "Check network connection and query format"),
-		NewHelpItem("Timeout errors", "Try again or check if host is reachable"),
-		NewHelpItem("WHOIS no data", "Some domains may have privacy protection"),
-		NewHelpItem("DNS failures", "Verify domain exists and DNS servers work"),
-		NewHelpItem("SSL errors", "Check if port supports SSL/TLS"),
-		NewHelpItem("Long results", "Use ↑/↓ or PgUp/PgDown to scroll"),
+	content.WriteString(m.renderHelpSection(i18n.T("help.troubleshooting"), []HelpItem{
+		NewHelpItem(i18n.T("help.troubleshooting.no_results"), i18n.T("help.troubleshooting.no_results.text")),
+		NewHelpItem(i18n.T("help.troubleshooting.timeout"), i18n.T("help.troubleshooting.timeout.text")),
+		NewHelpItem(i18n.T("help.troubleshooting.whois"), i18n.T("help.troubleshooting.whois.text")),
+		NewHelpItem(i18n.T("help.troubleshooting.dns"), i18n.T("help.troubleshooting.dns.text")),
+		NewHelpItem(i18n.T("help.troubleshooting.ssl"), i18n.T("help.troubleshooting.ssl.text")),
+		NewHelpItem(i18n.T("help.troubleshooting.long"), i18n.T("help.troubleshooting.long.text")),
+		NewHelpItem(i18n.T("help.troubleshooting.logs"), i18n.T("help.troubleshooting.logs.text")),
 	}))
 	
 	return content.String()
@@ -268,7 +301,7 @@ func (m *HelpModel) renderHelpSection(title string, items []HelpItem) string {
 	// Section title
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("205")).
+		Foreground(colors.Accent).
 		MarginBottom(1)
 	
 	content.WriteString(titleStyle.Render(title))
@@ -277,12 +310,12 @@ func (m *HelpModel) renderHelpSection(title string, items []HelpItem) string {
 	// Section items
 	keyStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
+		Foreground(colors.Info).
 		Width(20).
 		Align(lipgloss.Left)
 	
 	valueStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252"))
+		Foreground(colors.Text)
 	
 	for _, item := range items {
 		key := keyStyle.Render(item.Key)
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
 	"github.com/nettracex/nettracex-tui/internal/domain"
 )
 
@@ -53,13 +54,13 @@ func (hs *HelpSection) Render(width int, selected bool, theme domain.Theme) stri
 	// Section title styling
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("205")).
+		Foreground(colors.Accent).
 		MarginBottom(1)
 	
 	if selected {
 		// Highlight selected section with background
 		titleStyle = titleStyle.
-			Background(lipgloss.Color("237")).
+			Background(colors.Adaptive("237")).
 			Padding(0, 1)
 	}
 	
@@ -69,16 +70,16 @@ func (hs *HelpSection) Render(width int, selected bool, theme domain.Theme) stri
 	// Render help items
 	keyStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
+		Foreground(colors.Info).
 		Width(20).
 		Align(lipgloss.Left)
 	
 	valueStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252"))
+		Foreground(colors.Text)
 	
 	if selected {
 		// Slightly different styling for selected section items
-		valueStyle = valueStyle.Foreground(lipgloss.Color("255"))
+		valueStyle = valueStyle.Foreground(colors.Adaptive("255"))
 	}
 	
 	for _, item := range hs.Items {
@@ -101,6 +102,15 @@ func (hs *HelpSection) IsSelectable() bool {
 	return true // Help sections can be selected for navigation
 }
 
+// SearchText returns the text the vi / search matches
+func (hs *HelpSection) SearchText() string {
+	text := hs.Title
+	for _, item := range hs.Items {
+		text += " " + item.Key + " " + item.Description
+	}
+	return text
+}
+
 // GetID implements ScrollableItem interface
 func (hs *HelpSection) GetID() string {
 	return hs.ID
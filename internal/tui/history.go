@@ -0,0 +1,66 @@
+// Package tui contains the result history used to compare runs of a tool
+package tui
+
+import (
+	"sort"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// DefaultResultHistoryLimit is the number of results kept per tool
+const DefaultResultHistoryLimit = 20
+
+// ResultHistoryEntry is a result recorded in the history
+type ResultHistoryEntry struct {
+	Result    domain.Result
+	Timestamp time.Time
+}
+
+// ResultHistory keeps the most recent results of each tool for the session
+type ResultHistory struct {
+	entries map[string][]ResultHistoryEntry
+	limit   int
+}
+
+// NewResultHistory creates a history that keeps up to limit results per tool
+func NewResultHistory(limit int) *ResultHistory {
+	if limit < 2 {
+		limit = DefaultResultHistoryLimit
+	}
+	return &ResultHistory{
+		entries: make(map[string][]ResultHistoryEntry),
+		limit:   limit,
+	}
+}
+
+// Add records a result of tool, dropping the oldest once the limit is reached
+func (h *ResultHistory) Add(tool string, result domain.Result) {
+	timestamp, ok := result.Metadata()["timestamp"].(time.Time)
+	if !ok {
+		timestamp = time.Now()
+	}
+
+	entries := append(h.entries[tool], ResultHistoryEntry{Result: result, Timestamp: timestamp})
+	if len(entries) > h.limit {
+		entries = entries[len(entries)-h.limit:]
+	}
+	h.entries[tool] = entries
+}
+
+// Entries returns the results of tool, oldest first
+func (h *ResultHistory) Entries(tool string) []ResultHistoryEntry {
+	return h.entries[tool]
+}
+
+// Tools returns the tools with recorded results in name order
+func (h *ResultHistory) Tools() []string {
+	tools := make([]string, 0, len(h.entries))
+	for tool, entries := range h.entries {
+		if len(entries) > 0 {
+			tools = append(tools, tool)
+		}
+	}
+	sort.Strings(tools)
+	return tools
+}
@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/clipboard"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultHistory_Limit(t *testing.T) {
+	history := NewResultHistory(3)
+	for i := 0; i < 5; i++ {
+		history.Add("ping", domain.NewResult(i))
+	}
+	history.Add("dns", domain.NewResult("other tool"))
+
+	entries := history.Entries("ping")
+	require.Len(t, entries, 3)
+	assert.Equal(t, 2, entries[0].Result.Data())
+	assert.Equal(t, 4, entries[2].Result.Data())
+	assert.Len(t, history.Entries("dns"), 1)
+}
+
+func TestResultViewModel_CompareMode(t *testing.T) {
+	hops := func(second string) []domain.TraceHop {
+		return []domain.TraceHop{
+			{Number: 1, Host: domain.NetworkHost{IPAddress: net.ParseIP("192.168.1.1")}, RTT: []time.Duration{time.Millisecond}},
+			{Number: 2, Host: domain.NetworkHost{IPAddress: net.ParseIP(second)}, RTT: []time.Duration{10 * time.Millisecond}},
+		}
+	}
+
+	model := NewResultViewModel()
+	model.SetSize(120, 40)
+	model.SetHistory(NewResultHistory(DefaultResultHistoryLimit), "traceroute")
+
+	// Compare mode needs two results
+	model.SetResult(domain.NewResult(hops("10.0.0.1")))
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	assert.Equal(t, ResultViewModeFormatted, model.mode)
+
+	model.SetResult(domain.NewResult(hops("10.0.0.2")))
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	require.Equal(t, ResultViewModeDiff, model.mode)
+	assert.Equal(t, 0, model.diffBase)
+	assert.Equal(t, 1, model.diffTarget)
+
+	view := model.renderDiffResult()
+	assert.Contains(t, view, "Comparing #1")
+	assert.Contains(t, view, "1 changed • 0 added • 0 removed")
+	assert.True(t, strings.Contains(view, "10.0.0.1") && strings.Contains(view, "10.0.0.2"))
+
+	// Selection stays within the history
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("[")})
+	assert.Equal(t, 0, model.diffBase)
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("}")})
+	assert.Equal(t, 1, model.diffTarget)
+
+	// A new result leaves compare mode
+	model.SetResult(domain.NewResult(hops("10.0.0.3")))
+	assert.Equal(t, ResultViewModeFormatted, model.mode)
+}
+
+func TestResultViewModel_ExportHTMLReport(t *testing.T) {
+	dir := t.TempDir()
+	result := domain.NewResult([]domain.PingResult{{Sequence: 1, RTT: 12 * time.Millisecond}})
+	result.SetMetadata("tool", "ping")
+	result.SetMetadata("timestamp", time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC))
+
+	model := NewResultViewModel()
+	model.SetExportDirectory(dir)
+	model.SetResult(result)
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("H")})
+	require.NotNil(t, cmd)
+	msg, ok := cmd().(ResultExportedMsg)
+	require.True(t, ok)
+	require.NoError(t, msg.Error)
+	assert.Equal(t, filepath.Join(dir, "ping-20240501-123000.html"), msg.Path)
+
+	data, err := os.ReadFile(msg.Path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "NetTraceX ping Report")
+
+	model.Update(msg)
+	assert.Contains(t, model.renderViewModeHelp(), "Report saved to")
+}
+
+func TestResultViewModel_SaveDialog(t *testing.T) {
+	dir := t.TempDir()
+	result := domain.NewResult(domain.DNSResult{Query: "example.com", Records: []domain.DNSRecord{{Name: "example.com", Value: "93.184.216.34", TTL: 300}}})
+	result.SetMetadata("tool", "dns")
+	result.SetMetadata("timestamp", time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC))
+
+	model := NewResultViewModel()
+	model.SetExportDirectory(dir)
+	model.SetExportFormat(domain.ExportFormatJSON)
+	model.SetResult(result)
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	require.True(t, model.CapturesInput())
+	assert.Equal(t, filepath.Join(dir, "dns-20240501-123000.json"), model.save.path.Value())
+	assert.Contains(t, model.renderViewModeHelp(), "Save Result")
+
+	// Keys that switch view modes are typed into the file name instead
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	assert.Equal(t, ResultViewModeFormatted, model.mode)
+
+	// Changing the format follows the extension
+	model.save.path.SetValue(filepath.Join(dir, "records.json"))
+	model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	assert.Equal(t, filepath.Join(dir, "records.txt"), model.save.path.Value())
+	model.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	model.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	assert.Equal(t, filepath.Join(dir, "records.csv"), model.save.path.Value())
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	assert.False(t, model.CapturesInput())
+
+	msg, ok := cmd().(ResultExportedMsg)
+	require.True(t, ok)
+	require.NoError(t, msg.Error)
+	data, err := os.ReadFile(msg.Path)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "name,type,value,ttl,priority\n"))
+
+	// Escape closes the dialog without saving
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	_, cmd = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Nil(t, cmd)
+	assert.False(t, model.CapturesInput())
+}
+
+func TestResultViewModel_CopyConfirmation(t *testing.T) {
+	model := NewResultViewModel()
+	model.SetSize(120, 40)
+	model.SetResult(domain.NewResult(domain.DNSResult{Query: "example.com", Records: []domain.DNSRecord{{Name: "example.com", Value: "93.184.216.34", TTL: 300}}}))
+
+	// Table mode copies the selected row
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	row, ok := model.tableModel.SelectedRow()
+	require.True(t, ok)
+	assert.Contains(t, row, "93.184.216.34")
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	assert.NotNil(t, cmd)
+
+	_, cmd = model.Update(clipboard.CopiedMsg{Description: "selected row"})
+	assert.NotNil(t, cmd)
+	assert.Contains(t, model.renderViewModeHelp(), "Copied selected row to clipboard")
+
+	// A new result hides the toast
+	model.SetResult(domain.NewResult(domain.DNSResult{Query: "example.org"}))
+	assert.NotContains(t, model.renderViewModeHelp(), "Copied")
+}
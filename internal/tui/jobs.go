@@ -0,0 +1,424 @@
+// Package tui contains the background jobs and the jobs panel
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
+)
+
+// Job states
+const (
+	JobRunning   = "running"
+	JobDone      = "done"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+// JobStatus describes a background job in the jobs panel
+type JobStatus struct {
+	ID      int
+	Tool    string
+	Target  string
+	State   string
+	Started time.Time
+	Elapsed time.Duration
+	// Progress is the estimated completed fraction, or -1 when unknown
+	Progress float64
+}
+
+// job is a diagnostic that kept running after the user left it. Its id is
+// the id of the tab it was started in, so its messages keep reaching it.
+type job struct {
+	id       int
+	view     *DiagnosticViewModel
+	finished time.Time
+}
+
+// JobList holds the diagnostics running, or finished, in the background
+type JobList struct {
+	jobs []*job
+}
+
+// NewJobList creates an empty job list
+func NewJobList() *JobList {
+	return &JobList{}
+}
+
+// Add moves view to the background as job id
+func (l *JobList) Add(id int, view *DiagnosticViewModel) {
+	l.jobs = append(l.jobs, &job{id: id, view: view})
+}
+
+// Statuses describes the jobs in the order they were added
+func (l *JobList) Statuses() []JobStatus {
+	now := time.Now()
+	statuses := make([]JobStatus, 0, len(l.jobs))
+	for _, j := range l.jobs {
+		status := JobStatus{
+			ID:       j.id,
+			Tool:     j.view.GetTool().Name(),
+			Target:   j.view.Target(),
+			State:    jobState(j.view),
+			Started:  j.view.StartedAt(),
+			Progress: -1,
+		}
+		end := now
+		if !j.finished.IsZero() {
+			end = j.finished
+		}
+		status.Elapsed = end.Sub(status.Started)
+		switch {
+		case status.State == JobDone:
+			status.Progress = 1
+		case status.State == JobRunning && j.view.Expected() > 0:
+			status.Progress = float64(status.Elapsed) / float64(j.view.Expected())
+			if status.Progress > 0.99 {
+				status.Progress = 0.99
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Running returns the number of jobs still running
+func (l *JobList) Running() int {
+	running := 0
+	for _, j := range l.jobs {
+		if j.view.IsLoading() {
+			running++
+		}
+	}
+	return running
+}
+
+// Take removes job id from the list and returns its view to be shown again
+func (l *JobList) Take(id int) (*DiagnosticViewModel, bool) {
+	for i, j := range l.jobs {
+		if j.id == id {
+			l.jobs = append(l.jobs[:i], l.jobs[i+1:]...)
+			return j.view, true
+		}
+	}
+	return nil, false
+}
+
+// Cancel stops job id if it is running
+func (l *JobList) Cancel(id int) bool {
+	for _, j := range l.jobs {
+		if j.id == id && j.view.IsLoading() {
+			j.view.Cancel()
+			return true
+		}
+	}
+	return false
+}
+
+// Remove dismisses job id once it is no longer running
+func (l *JobList) Remove(id int) bool {
+	for i, j := range l.jobs {
+		if j.id == id && !j.view.IsLoading() {
+			l.jobs = append(l.jobs[:i], l.jobs[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// update delivers a message produced by job id. It returns the job's
+// command and, once the job stops running, its final status.
+func (l *JobList) update(id int, msg tea.Msg) (tea.Cmd, *JobStatus, bool) {
+	for _, j := range l.jobs {
+		if j.id != id {
+			continue
+		}
+		wasRunning := j.view.IsLoading()
+		_, cmd := j.view.Update(msg)
+		if j.view.IsLive() {
+			// A live screen has no result to wait for in the background
+			j.view.Cancel()
+			cmd = nil
+		}
+		if !wasRunning || j.view.IsLoading() {
+			return cmd, nil, true
+		}
+		j.finished = time.Now()
+		status := JobStatus{ID: j.id, Tool: j.view.GetTool().Name(), Target: j.view.Target(), State: jobState(j.view)}
+		return cmd, &status, true
+	}
+	return nil, nil, false
+}
+
+// jobState describes the outcome of the run of view
+func jobState(view *DiagnosticViewModel) string {
+	switch {
+	case view.IsLoading():
+		return JobRunning
+	case view.Cancelled():
+		return JobCancelled
+	case view.GetState() == DiagnosticStateError:
+		return JobFailed
+	default:
+		return JobDone
+	}
+}
+
+// JobAttachMsg asks to show a background job again
+type JobAttachMsg struct {
+	ID int
+}
+
+// jobsTickMsg refreshes the elapsed times of a jobs panel
+type jobsTickMsg struct {
+	model *JobsViewModel
+}
+
+// JobsViewModel lists the background jobs with their progress, and
+// re-attaches, cancels or dismisses them
+type JobsViewModel struct {
+	jobs     *JobList
+	table    *TableModel
+	statuses []JobStatus
+	status   string
+	width    int
+	height   int
+	theme    domain.Theme
+	attach   key.Binding
+	cancel   key.Binding
+	dismiss  key.Binding
+	// reducedMotion stops the once a second refresh of the elapsed times
+	reducedMotion bool
+}
+
+// NewJobsViewModel creates a jobs panel for jobs
+func NewJobsViewModel(jobs *JobList) *JobsViewModel {
+	m := &JobsViewModel{
+		jobs:  jobs,
+		table: NewTableModel([]string{"Tool", "Target", "State", "Progress", "Elapsed"}),
+		attach: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", i18n.T("key.attach")),
+		),
+		cancel: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", i18n.T("key.cancel")),
+		),
+		dismiss: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", i18n.T("key.dismiss")),
+		),
+	}
+	m.refresh()
+	return m
+}
+
+// Init implements tea.Model and starts refreshing the elapsed times
+func (m *JobsViewModel) Init() tea.Cmd {
+	return m.tick()
+}
+
+// Update implements tea.Model
+func (m *JobsViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		if mouse, ok := mouseOver(msg, m.View(), m.table.View()); ok {
+			m.table.Update(mouse)
+		}
+		return m, nil
+	case tea.KeyMsg:
+		if m.table.CapturesInput() {
+			break
+		}
+		status, ok := m.Selected()
+		switch {
+		case key.Matches(msg, m.attach) && ok:
+			return m, func() tea.Msg { return JobAttachMsg{ID: status.ID} }
+		case key.Matches(msg, m.cancel) && ok:
+			if m.jobs.Cancel(status.ID) {
+				m.status = fmt.Sprintf("Cancelling %s", status.Tool)
+			}
+			m.refresh()
+			return m, nil
+		case key.Matches(msg, m.dismiss) && ok:
+			if !m.jobs.Remove(status.ID) {
+				m.status = fmt.Sprintf("%s is still running; cancel it first", status.Tool)
+			}
+			m.refresh()
+			return m, nil
+		}
+	case jobsTickMsg:
+		if msg.model != m {
+			return m, nil
+		}
+		m.refresh()
+		return m, m.tick()
+	}
+
+	_, cmd := m.table.Update(msg)
+	return m, cmd
+}
+
+// View implements tea.Model
+func (m *JobsViewModel) View() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).MarginBottom(1)
+	mutedStyle := lipgloss.NewStyle().Foreground(colors.Muted).Italic(true)
+	if m.theme != nil {
+		titleStyle = titleStyle.Foreground(colors.Adaptive(m.theme.GetColor("primary")))
+		mutedStyle = mutedStyle.Foreground(colors.Adaptive(m.theme.GetColor("muted")))
+	}
+
+	if len(m.statuses) == 0 {
+		return titleStyle.Render("Background Jobs") + "\n\n" +
+			mutedStyle.Render("No background jobs; press esc while a tool runs to keep it running here")
+	}
+
+	help := screenHelp(m.width).ShortHelpView(m.ShortHelp())
+	if m.status != "" {
+		help = mutedStyle.Render(m.status+" • ") + help
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render("Background Jobs"),
+		m.table.View(),
+		"",
+		help,
+	)
+}
+
+// ShortHelp implements help.KeyMap
+func (m *JobsViewModel) ShortHelp() []key.Binding {
+	return []key.Binding{m.attach, m.cancel, m.dismiss}
+}
+
+// FullHelp implements help.KeyMap
+func (m *JobsViewModel) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+// SetSize implements domain.TUIComponent
+func (m *JobsViewModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.table.SetSize(width, height-8)
+}
+
+// SetTheme implements domain.TUIComponent
+func (m *JobsViewModel) SetTheme(theme domain.Theme) {
+	m.theme = theme
+	m.table.SetTheme(theme)
+}
+
+// SetKeyMap implements keyMapSetter
+func (m *JobsViewModel) SetKeyMap(keyMap KeyMap) {
+	m.table.SetKeyMap(keyMap)
+}
+
+// CapturesInput reports whether the vi search prompt of the table is open
+func (m *JobsViewModel) CapturesInput() bool {
+	return m.table.CapturesInput()
+}
+
+// JumpTo selects the row on line, counting from 1
+func (m *JobsViewModel) JumpTo(line int) {
+	m.table.JumpTo(line)
+}
+
+// Focus implements domain.TUIComponent
+func (m *JobsViewModel) Focus() {
+	m.table.Focus()
+}
+
+// Blur implements domain.TUIComponent
+func (m *JobsViewModel) Blur() {
+	m.table.Blur()
+}
+
+// Statuses returns the jobs as last refreshed
+func (m *JobsViewModel) Statuses() []JobStatus {
+	return m.statuses
+}
+
+// Selected returns the job under the cursor
+func (m *JobsViewModel) Selected() (JobStatus, bool) {
+	if m.table.selected < 0 || m.table.selected >= len(m.statuses) {
+		return JobStatus{}, false
+	}
+	return m.statuses[m.table.selected], true
+}
+
+// tick schedules the next refresh of the elapsed times
+func (m *JobsViewModel) tick() tea.Cmd {
+	if m.reducedMotion {
+		return nil
+	}
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return jobsTickMsg{model: m}
+	})
+}
+
+// refresh updates the table from the job list
+func (m *JobsViewModel) refresh() {
+	m.statuses = m.jobs.Statuses()
+	rows := make([][]string, 0, len(m.statuses))
+	for _, status := range m.statuses {
+		progress := "-"
+		if status.Progress >= 0 {
+			progress = fmt.Sprintf("%.0f%%", status.Progress*100)
+		}
+		target := status.Target
+		if target == "" {
+			target = "-"
+		}
+		rows = append(rows, []string{
+			status.Tool,
+			target,
+			status.State,
+			progress,
+			status.Elapsed.Round(time.Second).String(),
+		})
+	}
+	m.table.SetData(rows)
+}
+
+// detachJob keeps the diagnostic running in the active tab as a background
+// job. The job takes over the tab's id, so the results on their way reach
+// it, and the tab gets a new one. A live screen is closed instead.
+func (m *MainModel) detachJob() {
+	diagnosticView, ok := m.activeView.(*DiagnosticViewModel)
+	if ok && diagnosticView.IsLive() {
+		// A live screen runs only while it is open
+		diagnosticView.Cancel()
+		return
+	}
+	if !ok || !diagnosticView.IsLoading() {
+		return
+	}
+	tab := m.tabs[m.activeTab]
+	m.jobs.Add(tab.id, diagnosticView)
+	tab.id = m.nextTabID
+	m.nextTabID++
+	m.configStatus = fmt.Sprintf("⏳ %s continues in the background; see Background Jobs", diagnosticView.GetTool().Name())
+}
+
+// attachJob shows background job id in the active tab, which takes over
+// the job's id
+func (m *MainModel) attachJob(id int) (*MainModel, tea.Cmd) {
+	diagnosticView, ok := m.jobs.Take(id)
+	if !ok {
+		return m, nil
+	}
+	m.tabs[m.activeTab].id = id
+	m.state = StateDiagnostic
+	m.screenTitle = diagnosticView.GetTool().Name()
+	m.activeView = diagnosticView
+	diagnosticView.SetSize(m.width, m.height)
+	diagnosticView.SetTheme(m.theme)
+	diagnosticView.Focus()
+	return m, nil
+}
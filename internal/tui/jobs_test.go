@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"context"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	configpkg "github.com/nettracex/nettracex-tui/internal/config"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/plugins"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingTool runs until its context is cancelled
+type blockingTool struct{}
+
+func (t *blockingTool) Name() string                            { return "ping" }
+func (t *blockingTool) Description() string                     { return "blocks until cancelled" }
+func (t *blockingTool) Validate(params domain.Parameters) error { return nil }
+func (t *blockingTool) GetModel() tea.Model                     { return nil }
+
+func (t *blockingTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// startJob opens ping, submits a run and returns the messages its execution
+// will deliver
+func startJob(t *testing.T, model *MainModel) <-chan tea.Msg {
+	model.Update(NavigationMsg{Action: NavigationActionSelect, Data: NavigationItem{ID: "ping", Title: "Ping Test"}})
+	_, cmd := model.Update(FormSubmitMsg{Values: map[string]string{"host": "example.com", "count": "10", "interval": "1"}})
+	require.NotNil(t, cmd)
+	batch, ok := cmd().(tea.BatchMsg)
+	require.True(t, ok)
+	require.Len(t, batch, 2)
+
+	model.Update(batch[0]())
+	require.True(t, model.activeView.(*DiagnosticViewModel).IsLoading())
+
+	results := make(chan tea.Msg, 1)
+	go func() { results <- batch[1]() }()
+	return results
+}
+
+func TestMainModel_BackgroundJobs(t *testing.T) {
+	registry := plugins.NewRegistry(nil)
+	require.NoError(t, registry.Register(&blockingTool{}))
+	model := NewMainModel(registry, &domain.Config{}, configpkg.NewManager(), nil)
+	model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	results := startJob(t, model)
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, StateMainMenu, model.state)
+	assert.Equal(t, 1, model.jobs.Running())
+	assert.Contains(t, model.View(), "⏳ 1 running")
+
+	_, cmd := model.Update(NavigationMsg{Action: NavigationActionSelect, Data: NavigationItem{ID: "jobs", Title: "Background Jobs"}})
+	assert.NotNil(t, cmd, "the panel refreshes elapsed times")
+	panel := model.activeView.(*JobsViewModel)
+	require.Len(t, panel.Statuses(), 1)
+	status := panel.Statuses()[0]
+	assert.Equal(t, "ping", status.Tool)
+	assert.Equal(t, "example.com", status.Target)
+	assert.Equal(t, JobRunning, status.State)
+	assert.GreaterOrEqual(t, status.Progress, 0.0)
+
+	// Dismissing needs the job to stop first
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	assert.Contains(t, model.View(), "ping is still running; cancel it first")
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	model.Update(<-results)
+	assert.Equal(t, 0, model.jobs.Running())
+	assert.Contains(t, model.configStatus, "ping example.com cancelled")
+
+	_, cmd = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	model.Update(cmd())
+	require.IsType(t, &DiagnosticViewModel{}, model.activeView)
+	assert.Equal(t, StateDiagnostic, model.state)
+	assert.Equal(t, DiagnosticStateError, model.activeView.(*DiagnosticViewModel).GetState())
+	assert.Empty(t, model.jobs.Statuses())
+}
+
+func TestMainModel_ClosingTabKeepsJob(t *testing.T) {
+	registry := plugins.NewRegistry(nil)
+	require.NoError(t, registry.Register(&blockingTool{}))
+	model := NewMainModel(registry, &domain.Config{}, configpkg.NewManager(), nil)
+	model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	results := startJob(t, model)
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+
+	titles, _ := model.Tabs()
+	assert.Len(t, titles, 1)
+	require.Len(t, model.jobs.Statuses(), 1)
+
+	id := model.jobs.Statuses()[0].ID
+	assert.True(t, model.jobs.Cancel(id))
+	model.Update(<-results)
+	assert.Equal(t, JobCancelled, model.jobs.Statuses()[0].State)
+	assert.True(t, model.jobs.Remove(id))
+}
@@ -0,0 +1,156 @@
+// Package tui contains the key help. The footer hints and the ? overlay are
+// rendered with bubbles/help from the same bindings the screens match keys
+// against, so they list the keys that actually work.
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
+	"github.com/nettracex/nettracex-tui/internal/keyhint"
+)
+
+// hint creates a binding of keys described as desc in the help
+func hint(desc string, keys ...string) key.Binding {
+	return keyhint.New(desc, keys...)
+}
+
+// relabel returns binding described as desc, for a screen that gives a key
+// of the key map its own meaning
+func relabel(binding key.Binding, desc string) key.Binding {
+	return keyhint.Relabel(binding, desc)
+}
+
+// footerHelp renders hints on the footer bar, which colors them itself
+func footerHelp(width int) help.Model {
+	h := help.New()
+	h.Width = width
+	h.Styles = help.Styles{}
+	return h
+}
+
+// screenHelp renders the hints at the bottom of a screen
+func screenHelp(width int) help.Model {
+	return keyhint.Help(width)
+}
+
+// helpColumns renders each group of bindings as a column of the full help,
+// wrapping the columns that do not fit in width onto the next row
+func helpColumns(groups [][]key.Binding, width int) string {
+	h := screenHelp(0)
+	columnStyle := lipgloss.NewStyle().PaddingRight(4).MarginBottom(1)
+
+	var rows, row []string
+	rowWidth := 0
+	for _, group := range groups {
+		column := h.FullHelpView([][]key.Binding{group})
+		if column == "" {
+			continue
+		}
+		column = columnStyle.Render(column)
+		if len(row) > 0 && rowWidth+lipgloss.Width(column) > width {
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+			row, rowWidth = nil, 0
+		}
+		row = append(row, column)
+		rowWidth += lipgloss.Width(column)
+	}
+	if len(row) > 0 {
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// guideKey opens the guide with tips and troubleshooting from the ? overlay
+func guideKey() key.Binding {
+	return hint(i18n.T("footer.guide"), "enter")
+}
+
+// openKeyHelp shows the keys of the active screen over it
+func (m *MainModel) openKeyHelp() (*MainModel, tea.Cmd) {
+	m.keyHelp = true
+	return m, nil
+}
+
+// updateKeyHelp handles a key while the overlay is shown: esc or ? close
+// it and enter opens the guide with tips and troubleshooting
+func (m *MainModel) updateKeyHelp(msg tea.KeyMsg) (*MainModel, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keyMap.Back, m.keyMap.Help):
+		m.keyHelp = false
+	case key.Matches(msg, guideKey()):
+		m.keyHelp = false
+		m.leaveScreen()
+		return m.openHelp()
+	case key.Matches(msg, m.keyMap.Quit):
+		return m.quit()
+	}
+	return m, nil
+}
+
+// renderKeyHelp renders the keys of the active screen, then the keys that
+// work on every screen
+func (m *MainModel) renderKeyHelp() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(colors.Primary).MarginBottom(1)
+	width := m.width - 2
+
+	var sections []string
+	if keys, ok := m.activeView.(help.KeyMap); ok {
+		if columns := helpColumns(keys.FullHelp(), width); columns != "" {
+			sections = append(sections, titleStyle.Render(i18n.T("keyhelp.screen")), columns)
+		}
+	}
+	sections = append(sections, titleStyle.Render(i18n.T("keyhelp.global")), helpColumns(m.keyMap.FullHelp(), width))
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// footerBindings returns the keys the footer describes for the active screen
+func (m *MainModel) footerBindings() []key.Binding {
+	closeKey := func(keys ...key.Binding) key.Binding {
+		var bound []string
+		for _, binding := range keys {
+			bound = append(bound, binding.Keys()...)
+		}
+		return hint(i18n.T("footer.close"), bound...)
+	}
+
+	switch {
+	case m.keyHelp:
+		return []key.Binding{closeKey(m.keyMap.Back, m.keyMap.Help), guideKey(), m.keyMap.Quit}
+	case m.palette != nil:
+		return []key.Binding{
+			hint(i18n.T("footer.select"), "↑", "↓"),
+			hint(i18n.T("footer.run"), "enter"),
+			closeKey(m.keyMap.Back),
+		}
+	case m.logs.Open():
+		return append(m.logs.ShortHelp(), closeKey(m.keyMap.Logs), m.keyMap.Quit)
+	}
+
+	var bindings []key.Binding
+	if len(m.tabs) > 1 && m.state != StateRestore {
+		bindings = append(bindings, m.keyMap.PrevTab, m.keyMap.NextTab, m.keyMap.CloseTab)
+	}
+	switch m.state {
+	case StateMainMenu, StateNavigation:
+		bindings = append(bindings, m.navigation.ShortHelp()...)
+		bindings = append(bindings, m.keyMap.NewTab, m.keyMap.Palette, m.keyMap.Help, m.keyMap.Quit)
+	case StateRestore:
+		bindings = []key.Binding{
+			hint(i18n.T("footer.restore"), "y"),
+			hint(i18n.T("footer.fresh"), "n"),
+			m.keyMap.Quit,
+		}
+	case StateHelp:
+		bindings = append(bindings, m.helpView.ShortHelp()...)
+		bindings = append(bindings, m.keyMap.Back, m.keyMap.Quit)
+	default:
+		bindings = append(bindings, m.keyMap.ShortHelp()...)
+	}
+	return bindings
+}
@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	configpkg "github.com/nettracex/nettracex-tui/internal/config"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMainModel_KeyHelpOverlay(t *testing.T) {
+	config := &domain.Config{Keys: domain.KeyConfig{NewTab: []string{"ctrl+n"}}}
+	model := NewMainModel(newDashboardRegistry(t), config, configpkg.NewManager(), nil)
+	model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	view := ansi.Strip(model.View())
+	assert.Contains(t, view, "Keys of this screen")
+	assert.Contains(t, view, "PgDown page down", "the keys of the menu are listed")
+	assert.Contains(t, view, "Keys on every screen")
+	assert.Regexp(t, `ctrl\+n\s+new tab`, view, "remapped keys are shown as bound")
+	assert.Regexp(t, `ctrl\+l\s+logs`, view)
+	assert.Contains(t, view, "enter guide with tips")
+
+	// Keys do not reach the screen below while the overlay is shown
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	assert.Len(t, model.tabs, 1)
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	assert.NotContains(t, ansi.Strip(model.View()), "Keys on every screen")
+
+	// Enter opens the guide, where ? goes back
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, StateHelp, model.state)
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	assert.Equal(t, StateMainMenu, model.state)
+}
+
+func TestResultViewModel_KeyHelp(t *testing.T) {
+	view := NewResultViewModel()
+	view.SetKeyMap(NewKeyMap(domain.KeyConfig{Export: []string{"x"}}))
+	view.SetResult(domain.NewResult([]domain.TraceHop{{Number: 1, Host: domain.NetworkHost{Hostname: "router"}}}))
+
+	var described []string
+	for _, group := range view.FullHelp() {
+		for _, binding := range group {
+			described = append(described, binding.Help().Key+" "+binding.Help().Desc)
+		}
+	}
+	assert.Contains(t, described, "x export")
+	assert.Contains(t, described, "Y copy JSON")
+	assert.Contains(t, described, "n next match")
+	assert.NotContains(t, described, "s sort by column", "column keys are listed in table mode")
+
+	view.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	assert.Contains(t, ansi.Strip(screenHelp(0).ShortHelpView(view.ShortHelp())), "s sort by column")
+}
+
+func TestFooter_TruncatesToWidth(t *testing.T) {
+	model := NewMainModel(newDashboardRegistry(t), &domain.Config{}, configpkg.NewManager(), nil)
+	model.Update(tea.WindowSizeMsg{Width: 40, Height: 20})
+
+	footer := ansi.Strip(model.renderFooter())
+	assert.LessOrEqual(t, ansi.StringWidth(footer), 40)
+	assert.Contains(t, footer, "…", "hints that do not fit are cut off")
+}
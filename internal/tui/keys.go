@@ -0,0 +1,140 @@
+// Package tui contains the key bindings read from the configuration
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// keyMapSetter is implemented by views whose key bindings can be remapped
+type keyMapSetter interface {
+	SetKeyMap(keyMap KeyMap)
+}
+
+// NewKeyMap returns the default key bindings with the actions of config
+// bound to its keys
+func NewKeyMap(config domain.KeyConfig) KeyMap {
+	keyMap := DefaultKeyMap()
+	defaults := domain.DefaultKeyConfig().Actions()
+	for i, action := range config.Actions() {
+		binding := keyMap.binding(action.Name)
+		if binding == nil || strings.Join(action.Keys, " ") == strings.Join(defaults[i].Keys, " ") {
+			continue
+		}
+		binding.SetKeys(action.Keys...)
+		binding.SetHelp(strings.Join(action.Keys, "/"), binding.Help().Desc)
+	}
+	return keyMap
+}
+
+// binding returns the binding of the action called name in the keys
+// section of the configuration
+func (k *KeyMap) binding(name string) *key.Binding {
+	switch name {
+	case "up":
+		return &k.Up
+	case "down":
+		return &k.Down
+	case "left":
+		return &k.Left
+	case "right":
+		return &k.Right
+	case "select":
+		return &k.Enter
+	case "back":
+		return &k.Back
+	case "quit":
+		return &k.Quit
+	case "help":
+		return &k.Help
+	case "next_field":
+		return &k.Tab
+	case "page_up":
+		return &k.PageUp
+	case "page_down":
+		return &k.PageDown
+	case "home":
+		return &k.Home
+	case "end":
+		return &k.End
+	case "new_tab":
+		return &k.NewTab
+	case "close_tab":
+		return &k.CloseTab
+	case "next_tab":
+		return &k.NextTab
+	case "prev_tab":
+		return &k.PrevTab
+	case "palette":
+		return &k.Palette
+	case "export":
+		return &k.Export
+	case "raw_view":
+		return &k.RawView
+	case "formatted_view":
+		return &k.FormattedView
+	case "table_view":
+		return &k.TableView
+	case "diff_view":
+		return &k.DiffView
+	case "logs":
+		return &k.Logs
+	}
+	return nil
+}
+
+// applyKeys binds the keys of the configuration in the main model and in
+// the views open in every tab
+func (m *MainModel) applyKeys() {
+	if m.config == nil {
+		return
+	}
+	m.keyMap = NewKeyMap(m.config.Keys)
+	if m.config.UI.KeyMode == domain.KeyModeVi {
+		m.keyMap.ViMode = true
+		addKey(&m.keyMap.Left, "h")
+		addKey(&m.keyMap.Down, "j")
+		addKey(&m.keyMap.Up, "k")
+		addKey(&m.keyMap.Right, "l")
+	}
+	m.navigation.SetKeyMap(m.keyMap)
+	if m.helpView != nil {
+		m.helpView.SetKeyMap(m.keyMap)
+	}
+	views := m.backgroundViews()
+	if m.activeView != nil {
+		views = append(views, m.activeView)
+	}
+	for _, view := range views {
+		if setter, ok := view.(keyMapSetter); ok {
+			setter.SetKeyMap(m.keyMap)
+		}
+	}
+}
+
+// addKey binds k to binding as well, unless it already is
+func addKey(binding *key.Binding, k string) {
+	for _, bound := range binding.Keys() {
+		if bound == k {
+			return
+		}
+	}
+	binding.SetKeys(append(binding.Keys(), k)...)
+}
+
+// ShortHelp implements help.KeyMap with the keys of the screens that are
+// not menus
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Back, k.Palette, k.Help, k.Quit}
+}
+
+// FullHelp implements help.KeyMap with the keys that work on every screen
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Left, k.Right, k.PageUp, k.PageDown, k.Home, k.End},
+		{k.Enter, k.Back, k.Tab, k.Help, k.Palette, k.Logs, k.Quit},
+		{k.NewTab, k.CloseTab, k.NextTab, k.PrevTab},
+	}
+}
@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	configpkg "github.com/nettracex/nettracex-tui/internal/config"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewKeyMap(t *testing.T) {
+	keyMap := NewKeyMap(domain.KeyConfig{Down: []string{"ctrl+n"}, Export: []string{"x"}})
+
+	assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeyCtrlN}, keyMap.Down))
+	assert.False(t, key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")}, keyMap.Down))
+	assert.Equal(t, "ctrl+n", keyMap.Down.Help().Key)
+	assert.Equal(t, "move down", keyMap.Down.Help().Desc)
+	assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")}, keyMap.Export))
+
+	// Untouched actions keep their default help
+	assert.Equal(t, "↑/k", keyMap.Up.Help().Key)
+}
+
+func TestMainModel_RemappedKeys(t *testing.T) {
+	config := &domain.Config{Keys: domain.KeyConfig{Palette: []string{"ctrl+k"}, Quit: []string{"ctrl+q"}}}
+	model := NewMainModel(newDashboardRegistry(t), config, configpkg.NewManager(), nil)
+	model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	assert.Contains(t, model.View(), "ctrl+k commands")
+	assert.Contains(t, model.View(), "ctrl+q quit")
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	assert.False(t, model.quitting, "q is no longer bound to quit")
+
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	assert.Nil(t, model.palette)
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlK})
+	assert.NotNil(t, model.palette)
+}
@@ -0,0 +1,27 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	configpkg "github.com/nettracex/nettracex-tui/internal/config"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMainModel_Language(t *testing.T) {
+	require.NoError(t, i18n.SetLanguage("de"))
+	defer i18n.SetLanguage(i18n.Default)
+
+	model := NewMainModel(newDashboardRegistry(t), &domain.Config{}, configpkg.NewManager(), nil)
+	model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	view := model.View()
+	assert.Contains(t, view, "Netzwerk-Diagnosewerkzeuge")
+	assert.Contains(t, view, "Einstellungen")
+	assert.Contains(t, view, "? Hilfe")
+	titles, _ := model.Tabs()
+	assert.Equal(t, []string{"Menü"}, titles)
+}
@@ -0,0 +1,219 @@
+// Package tui contains the log panel shown below the active screen
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
+	"github.com/nettracex/nettracex-tui/internal/logging"
+)
+
+// logPanelHeight is how many log entries the panel shows at once
+const logPanelHeight = 8
+
+// logUpdatedMsg reports that the logger kept new records
+type logUpdatedMsg struct{}
+
+// LogPanel shows the latest records of the logger below the active screen,
+// following new records as they arrive. While it is open it takes the keys:
+// d, i, w and e show the records at or above debug, info, warn or error, and
+// the arrows scroll back.
+type LogPanel struct {
+	buffer  *logging.Buffer
+	keys    logPanelKeys
+	open    bool
+	level   logging.Level
+	offset  int
+	waiting bool
+}
+
+// logPanelKeys are the keys of the open panel
+type logPanelKeys struct {
+	Level     key.Binding
+	Older     key.Binding
+	Newer     key.Binding
+	PageOlder key.Binding
+	PageNewer key.Binding
+	Oldest    key.Binding
+	Newest    key.Binding
+	Close     key.Binding
+}
+
+// logLevelKeys are the levels the level keys show the records from
+var logLevelKeys = map[string]logging.Level{
+	"d": logging.LevelDebug,
+	"i": logging.LevelInfo,
+	"w": logging.LevelWarn,
+	"e": logging.LevelError,
+}
+
+// NewLogPanel creates a closed panel for the records kept in buffer; a nil
+// buffer shows no records
+func NewLogPanel(buffer *logging.Buffer) *LogPanel {
+	return &LogPanel{
+		buffer: buffer,
+		level:  logging.LevelInfo,
+		keys: logPanelKeys{
+			Level:     hint(i18n.T("footer.log_level"), "d", "i", "w", "e"),
+			Older:     key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", i18n.T("key.move_up"))),
+			Newer:     key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", i18n.T("key.move_down"))),
+			PageOlder: key.NewBinding(key.WithKeys("pgup", "ctrl+b"), key.WithHelp("PgUp", i18n.T("key.page_up"))),
+			PageNewer: key.NewBinding(key.WithKeys("pgdown", "ctrl+f"), key.WithHelp("PgDown", i18n.T("key.page_down"))),
+			Oldest:    key.NewBinding(key.WithKeys("home", "g"), key.WithHelp("Home/g", i18n.T("key.top"))),
+			Newest:    key.NewBinding(key.WithKeys("end", "G"), key.WithHelp("End/G", i18n.T("key.bottom"))),
+			Close:     hint(i18n.T("footer.close"), "esc"),
+		},
+	}
+}
+
+// ShortHelp implements help.KeyMap
+func (p *LogPanel) ShortHelp() []key.Binding {
+	return []key.Binding{p.keys.Level, p.keys.Older, p.keys.Newer}
+}
+
+// FullHelp implements help.KeyMap
+func (p *LogPanel) FullHelp() [][]key.Binding {
+	k := p.keys
+	return [][]key.Binding{{k.Level, k.Older, k.Newer, k.PageOlder, k.PageNewer, k.Oldest, k.Newest, k.Close}}
+}
+
+// Open reports whether the panel is shown
+func (p *LogPanel) Open() bool {
+	return p.open
+}
+
+// Toggle shows or hides the panel, following new records while it is shown
+func (p *LogPanel) Toggle() tea.Cmd {
+	p.open = !p.open
+	p.offset = 0
+	if !p.open {
+		return nil
+	}
+	return p.wait()
+}
+
+// wait returns a command receiving the next update of the buffer, unless
+// one is already waiting
+func (p *LogPanel) wait() tea.Cmd {
+	if p.buffer == nil || p.waiting {
+		return nil
+	}
+	p.waiting = true
+	updates := p.buffer.Updates()
+	return func() tea.Msg {
+		<-updates
+		return logUpdatedMsg{}
+	}
+}
+
+// updated handles logUpdatedMsg, waiting for the next update while the
+// panel is shown
+func (p *LogPanel) updated() tea.Cmd {
+	p.waiting = false
+	if !p.open {
+		return nil
+	}
+	return p.wait()
+}
+
+// Update handles the keys of the open panel
+func (p *LogPanel) Update(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, p.keys.Level):
+		p.setLevel(logLevelKeys[msg.String()])
+	case key.Matches(msg, p.keys.Older):
+		p.scroll(1)
+	case key.Matches(msg, p.keys.Newer):
+		p.scroll(-1)
+	case key.Matches(msg, p.keys.PageOlder):
+		p.scroll(logPanelHeight)
+	case key.Matches(msg, p.keys.PageNewer):
+		p.scroll(-logPanelHeight)
+	case key.Matches(msg, p.keys.Oldest):
+		p.scroll(len(p.entries()))
+	case key.Matches(msg, p.keys.Newest):
+		p.offset = 0
+	case key.Matches(msg, p.keys.Close):
+		return p.Toggle()
+	}
+	return nil
+}
+
+// setLevel shows the records at or above level from the newest one
+func (p *LogPanel) setLevel(level logging.Level) {
+	p.level = level
+	p.offset = 0
+}
+
+// scroll moves back by lines entries, or forward when lines is negative
+func (p *LogPanel) scroll(lines int) {
+	p.offset += lines
+	if max := len(p.entries()) - logPanelHeight; p.offset > max {
+		p.offset = max
+	}
+	if p.offset < 0 {
+		p.offset = 0
+	}
+}
+
+// entries returns the records shown at the current level
+func (p *LogPanel) entries() []logging.Entry {
+	if p.buffer == nil {
+		return nil
+	}
+	return p.buffer.Entries(p.level)
+}
+
+// View renders the panel width columns wide, always logPanelHeight entries
+// high so the screen above it does not jump
+func (p *LogPanel) View(width int) string {
+	entries := p.entries()
+	end := len(entries) - p.offset
+	if end < 0 {
+		end = 0
+	}
+	start := end - logPanelHeight
+	if start < 0 {
+		start = 0
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(colors.Primary)
+	mutedStyle := lipgloss.NewStyle().Foreground(colors.Muted).Italic(true)
+	lines := []string{titleStyle.Render(i18n.T("logs.title", logging.LevelName(p.level), end-start, len(entries)))}
+	if len(entries) == 0 {
+		lines = append(lines, mutedStyle.Render(i18n.T("logs.empty")))
+	}
+	for _, entry := range entries[start:end] {
+		line := ansi.Truncate(entry.String(), width-2, "…")
+		lines = append(lines, logLevelStyle(entry.Level).Render(line))
+	}
+	for len(lines) < logPanelHeight+1 {
+		lines = append(lines, "")
+	}
+
+	return lipgloss.NewStyle().
+		Width(width).
+		Padding(0, 1).
+		Border(lipgloss.NormalBorder(), true, false, false, false).
+		BorderForeground(colors.Border).
+		Render(strings.Join(lines, "\n"))
+}
+
+// logLevelStyle colours an entry by its level
+func logLevelStyle(level logging.Level) lipgloss.Style {
+	style := lipgloss.NewStyle()
+	switch {
+	case level >= logging.LevelError:
+		return style.Foreground(colors.Error)
+	case level == logging.LevelWarn:
+		return style.Foreground(colors.Warning)
+	case level == logging.LevelDebug:
+		return style.Foreground(colors.Subtle)
+	}
+	return style.Foreground(colors.Text)
+}
@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	configpkg "github.com/nettracex/nettracex-tui/internal/config"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogPanel_FiltersAndFollows(t *testing.T) {
+	buffer := logging.NewBuffer(0)
+	logger := logging.NewWriter(&strings.Builder{}, logging.LevelError, logging.FormatText)
+	logger.SetBuffer(buffer)
+	logger.Debug("resolving", "host", "example.com")
+	logger.Warn("slow response", "rtt", "2s")
+
+	panel := NewLogPanel(buffer)
+	cmd := panel.Toggle()
+	require.NotNil(t, cmd, "opening the panel follows new records")
+	assert.Equal(t, logUpdatedMsg{}, cmd(), "records already kept are signalled")
+	assert.NotNil(t, panel.updated())
+
+	view := ansi.Strip(panel.View(80))
+	assert.Contains(t, view, "info and above (1 of 1)")
+	assert.Contains(t, view, "WARN slow response rtt=2s")
+	assert.NotContains(t, view, "resolving")
+	assert.Equal(t, logPanelHeight+2, strings.Count(view, "\n")+1, "the panel keeps its height")
+
+	panel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	assert.Contains(t, ansi.Strip(panel.View(80)), "DEBUG resolving host=example.com")
+	panel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	assert.Contains(t, ansi.Strip(panel.View(80)), "No log entries at this level yet")
+
+	// Scrolling back stops at the oldest page
+	panel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	for i := 0; i < 20; i++ {
+		logger.Info("probe", "n", i)
+	}
+	panel.Update(tea.KeyMsg{Type: tea.KeyHome})
+	assert.Equal(t, 22-logPanelHeight, panel.offset)
+	assert.Contains(t, ansi.Strip(panel.View(80)), "DEBUG resolving")
+	panel.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	assert.Contains(t, ansi.Strip(panel.View(80)), "n=19")
+
+	assert.Nil(t, panel.Update(tea.KeyMsg{Type: tea.KeyEsc}))
+	assert.False(t, panel.Open())
+}
+
+func TestMainModel_LogPanel(t *testing.T) {
+	buffer := logging.NewBuffer(0)
+	logger := logging.NewWriter(&strings.Builder{}, logging.LevelInfo, logging.FormatText)
+	logger.SetBuffer(buffer)
+	logger.Error("whois lookup failed", "error", "connection refused")
+
+	model := NewMainModel(newDashboardRegistry(t), &domain.Config{}, configpkg.NewManager(), nil)
+	model.SetLogBuffer(buffer)
+	model.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	require.NotNil(t, cmd)
+	view := ansi.Strip(model.View())
+	assert.Contains(t, view, "ERROR whois lookup failed error=\"connection refused\"")
+	assert.Contains(t, view, "d/i/w/e level")
+	assert.Equal(t, 30, strings.Count(view, "\n")+1, "the screen above makes room for the panel")
+
+	// The update arrives addressed to the active tab and keeps the panel following
+	_, cmd = model.Update(cmd())
+	assert.NotNil(t, cmd)
+
+	// Keys go to the panel while it is open
+	selected := model.navigation.GetSelected().ID
+	model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, selected, model.navigation.GetSelected().ID)
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	assert.NotContains(t, ansi.Strip(model.View()), "whois lookup failed")
+}
@@ -2,13 +2,20 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
 	configpkg "github.com/nettracex/nettracex-tui/internal/config"
 	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
+	"github.com/nettracex/nettracex-tui/internal/events"
+	"github.com/nettracex/nettracex-tui/internal/logging"
+	"github.com/nettracex/nettracex-tui/internal/session"
 )
 
 // AppState represents the current state of the application
@@ -21,6 +28,7 @@ const (
 	StateSettings
 	StateHelp
 	StateExit
+	StateRestore
 )
 
 // MainModel represents the root application model
@@ -30,10 +38,36 @@ type MainModel struct {
 	helpView      *HelpModel
 	configView    *configpkg.ConfigUIModel
 	activeView    tea.Model
+	screenTitle   string
+	tabs          []*workspace
+	activeTab     int
+	nextTabID     int
 	plugins       domain.PluginRegistry
 	config        *domain.Config
 	configManager *configpkg.Manager
 	theme         domain.Theme
+	themes        *ThemeManager
+	configStatus  string
+	dnsReporter   domain.DNSServerReporter
+	cacheReporter domain.CacheReporter
+	capabilities  domain.CapabilityReporter
+	pluginReporter domain.PluginReporter
+	events        *events.Bus
+	history       *ResultHistory
+	tableLayouts  *TableLayouts
+	targets       *TargetBook
+	jobs          *JobList
+	logs          *LogPanel
+	palette       *PaletteModel
+	// keyHelp shows the keys of the active screen over it
+	keyHelp       bool
+	command       *viCommandLine
+	forms         map[string]map[string]string
+	sessionPath   string
+	pendingSession *session.Session
+	sessionErr    error
+	// newRelease is the newer release announced in the header
+	newRelease    *UpdateAvailableMsg
 	width         int
 	height        int
 	keyMap        KeyMap
@@ -55,6 +89,19 @@ type KeyMap struct {
 	PageDown key.Binding
 	Home     key.Binding
 	End      key.Binding
+	NewTab   key.Binding
+	CloseTab key.Binding
+	NextTab  key.Binding
+	PrevTab  key.Binding
+	Palette  key.Binding
+	Export   key.Binding
+	RawView  key.Binding
+	FormattedView key.Binding
+	TableView     key.Binding
+	DiffView      key.Binding
+	Logs          key.Binding
+	// ViMode adds the vi keys: gg and G jumps, / search and the : command line
+	ViMode bool
 }
 
 // DefaultKeyMap returns the default key bindings
@@ -62,55 +109,99 @@ func DefaultKeyMap() KeyMap {
 	return KeyMap{
 		Up: key.NewBinding(
 			key.WithKeys("up", "k"),
-			key.WithHelp("↑/k", "move up"),
+			key.WithHelp("↑/k", i18n.T("key.move_up")),
 		),
 		Down: key.NewBinding(
 			key.WithKeys("down", "j"),
-			key.WithHelp("↓/j", "move down"),
+			key.WithHelp("↓/j", i18n.T("key.move_down")),
 		),
 		Left: key.NewBinding(
 			key.WithKeys("left", "h"),
-			key.WithHelp("←/h", "move left"),
+			key.WithHelp("←/h", i18n.T("key.move_left")),
 		),
 		Right: key.NewBinding(
 			key.WithKeys("right", "l"),
-			key.WithHelp("→/l", "move right"),
+			key.WithHelp("→/l", i18n.T("key.move_right")),
 		),
 		Enter: key.NewBinding(
 			key.WithKeys("enter"),
-			key.WithHelp("enter", "select"),
+			key.WithHelp("enter", i18n.T("key.select")),
 		),
 		Back: key.NewBinding(
 			key.WithKeys("esc"),
-			key.WithHelp("esc", "back"),
+			key.WithHelp("esc", i18n.T("key.back")),
 		),
 		Quit: key.NewBinding(
 			key.WithKeys("q", "ctrl+c"),
-			key.WithHelp("q", "quit"),
+			key.WithHelp("q", i18n.T("key.quit")),
 		),
 		Help: key.NewBinding(
 			key.WithKeys("?"),
-			key.WithHelp("?", "help"),
+			key.WithHelp("?", i18n.T("key.help")),
 		),
 		Tab: key.NewBinding(
 			key.WithKeys("tab"),
-			key.WithHelp("tab", "next field"),
+			key.WithHelp("tab", i18n.T("key.next_field")),
 		),
 		PageUp: key.NewBinding(
 			key.WithKeys("pgup", "ctrl+b"),
-			key.WithHelp("PgUp", "page up"),
+			key.WithHelp("PgUp", i18n.T("key.page_up")),
 		),
 		PageDown: key.NewBinding(
 			key.WithKeys("pgdown", "ctrl+f"),
-			key.WithHelp("PgDown", "page down"),
+			key.WithHelp("PgDown", i18n.T("key.page_down")),
 		),
 		Home: key.NewBinding(
 			key.WithKeys("home", "ctrl+a"),
-			key.WithHelp("Home", "go to top"),
+			key.WithHelp("Home", i18n.T("key.top")),
 		),
 		End: key.NewBinding(
 			key.WithKeys("end", "ctrl+e"),
-			key.WithHelp("End", "go to bottom"),
+			key.WithHelp("End", i18n.T("key.bottom")),
+		),
+		NewTab: key.NewBinding(
+			key.WithKeys("ctrl+t"),
+			key.WithHelp("ctrl+t", i18n.T("key.new_tab")),
+		),
+		CloseTab: key.NewBinding(
+			key.WithKeys("ctrl+w"),
+			key.WithHelp("ctrl+w", i18n.T("key.close_tab")),
+		),
+		NextTab: key.NewBinding(
+			key.WithKeys("ctrl+pgdown", "alt+]"),
+			key.WithHelp("ctrl+PgDown", i18n.T("key.next_tab")),
+		),
+		PrevTab: key.NewBinding(
+			key.WithKeys("ctrl+pgup", "alt+["),
+			key.WithHelp("ctrl+PgUp", i18n.T("key.previous_tab")),
+		),
+		Palette: key.NewBinding(
+			key.WithKeys("ctrl+p"),
+			key.WithHelp("ctrl+p", i18n.T("key.commands")),
+		),
+		Export: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", i18n.T("key.export")),
+		),
+		RawView: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", i18n.T("key.raw_view")),
+		),
+		FormattedView: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", i18n.T("key.formatted_view")),
+		),
+		TableView: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", i18n.T("key.table_view")),
+		),
+		DiffView: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", i18n.T("key.compare")),
+		),
+		Logs: key.NewBinding(
+			key.WithKeys("ctrl+l"),
+			key.WithHelp("ctrl+l", i18n.T("key.logs")),
 		),
 	}
 }
@@ -120,29 +211,126 @@ func NewMainModel(plugins domain.PluginRegistry, config *domain.Config, configMa
 	nav := NewNavigationModel()
 	help := NewHelpModel()
 	configUI := configpkg.NewConfigUIModel(configManager)
+
+	// Plugin tools get a menu entry each
+	if plugins != nil {
+		for _, tool := range plugins.List() {
+			if _, ok := domain.ParameterSpecs(tool); ok {
+				nav.AddItem(NavigationItem{
+					ID:          tool.Name(),
+					Title:       tool.Name(),
+					Description: tool.Description(),
+					Icon:        "🧩",
+					Enabled:     true,
+				})
+			}
+		}
+	}
 	
-	return &MainModel{
+	m := &MainModel{
 		state:         StateMainMenu,
 		navigation:    nav,
 		helpView:      help,
 		configView:    configUI,
 		activeView:    nav,
+		tabs:          []*workspace{{id: 0, title: i18n.T("tab.menu"), state: StateMainMenu, view: nav}},
+		nextTabID:     1,
 		plugins:       plugins,
 		config:        config,
 		configManager: configManager,
 		theme:         theme,
+		themes:        NewThemeManager(),
+		history:       NewResultHistory(DefaultResultHistoryLimit),
+		tableLayouts:  NewTableLayouts("", nil),
+		targets:       NewTargetBook("", nil, nil),
+		jobs:          NewJobList(),
+		logs:          NewLogPanel(nil),
+		forms:         make(map[string]map[string]string),
 		keyMap:        DefaultKeyMap(),
 		quitting:      false,
 	}
+	if configManager != nil {
+		// Changes publish a new configuration rather than modifying the
+		// one held here; they are made from Update, so no locking is needed
+		configManager.AddChangeListener(func(key string, oldValue, newValue interface{}) {
+			m.config = configManager.GetConfig()
+		})
+	}
+	m.applyKeys()
+	return m
+}
+
+// SetDNSServerReporter provides the source for the DNS server health screen
+func (m *MainModel) SetDNSServerReporter(reporter domain.DNSServerReporter) {
+	m.dnsReporter = reporter
 }
 
-// Init implements tea.Model
+// SetTableLayouts sets the column layouts of the result tables, which are
+// saved as the user changes them
+func (m *MainModel) SetTableLayouts(layouts *TableLayouts) {
+	m.tableLayouts = layouts
+}
+
+// SetTargets sets the recent and starred targets offered by the host fields
+func (m *MainModel) SetTargets(book *TargetBook) {
+	m.targets = book
+}
+
+// SetCacheReporter provides the source for the response cache screen
+func (m *MainModel) SetCacheReporter(reporter domain.CacheReporter) {
+	m.cacheReporter = reporter
+}
+
+// SetCapabilityReporter provides the source for the system capabilities screen
+func (m *MainModel) SetCapabilityReporter(reporter domain.CapabilityReporter) {
+	m.capabilities = reporter
+}
+
+// SetPluginReporter provides the source for the plugin manager screen
+func (m *MainModel) SetPluginReporter(reporter domain.PluginReporter) {
+	m.pluginReporter = reporter
+}
+
+// SetLogBuffer provides the records the log panel shows
+func (m *MainModel) SetLogBuffer(buffer *logging.Buffer) {
+	m.logs = NewLogPanel(buffer)
+}
+
+// SetEventBus provides the bus reports exported from the result view are
+// published on
+func (m *MainModel) SetEventBus(bus *events.Bus) {
+	m.events = bus
+}
+
+// Init implements tea.Model and opens the dashboard when it is shown on
+// start and no session is waiting to be restored
 func (m *MainModel) Init() tea.Cmd {
+	if m.state == StateMainMenu && m.config != nil && m.config.UI.Dashboard.ShowOnStart {
+		_, cmd := m.selectNavigationItem(NavigationItem{ID: "dashboard", Title: i18n.T("menu.dashboard")})
+		return tea.Batch(tea.EnterAltScreen, tagCmd(m.tabs[m.activeTab].id, cmd))
+	}
 	return tea.EnterAltScreen
 }
 
-// Update implements tea.Model
+// Update implements tea.Model. Messages produced by the screen of a tab are
+// delivered to that tab, whether or not it is active.
 func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if tagged, ok := msg.(tabMsg); ok {
+		// The log panel belongs to no tab
+		if _, ok := tagged.msg.(logUpdatedMsg); ok {
+			return m, m.logs.updated()
+		}
+		if tagged.id != m.tabs[m.activeTab].id {
+			return m, m.updateTab(tagged)
+		}
+		msg = tagged.msg
+	}
+	model, cmd := m.update(msg)
+	return model, tagCmd(m.tabs[m.activeTab].id, cmd)
+}
+
+// update handles a message for the active tab
+func (m *MainModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
 
@@ -172,24 +360,91 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				component.SetSize(msg.Width, msg.Height)
 			}
 		}
+		
+		// Update the views of the other tabs
+		for _, view := range m.backgroundViews() {
+			if component, ok := view.(domain.TUIComponent); ok {
+				component.SetSize(msg.Width, msg.Height)
+			}
+		}
+
+		if m.palette != nil {
+			m.palette.SetSize(msg.Width, msg.Height)
+		}
+
+	case ConfigFileChangedMsg:
+		return m.reloadConfig()
+
+	case UpdateAvailableMsg:
+		m.newRelease = &msg
+
+	case ResultExportedMsg:
+		m.publishExport(msg)
+
+	case JobAttachMsg:
+		return m.attachJob(msg.ID)
+
+	case ChainToolMsg:
+		return m.runTool(msg.Tool, msg.Values)
+
+	case configpkg.ThemePreviewMsg:
+		m.useTheme(msg.Theme)
+		return m, nil
 
 	case tea.KeyMsg:
+		m.configStatus = ""
+		if m.state == StateRestore {
+			return m.updateRestorePrompt(msg)
+		}
+		if m.palette != nil {
+			return m.updatePalette(msg)
+		}
+		if m.keyHelp {
+			return m.updateKeyHelp(msg)
+		}
+		if key.Matches(msg, m.keyMap.Logs) {
+			return m, m.logs.Toggle()
+		}
+		if m.logs.Open() {
+			if key.Matches(msg, m.keyMap.Quit) {
+				return m.quit()
+			}
+			return m, m.logs.Update(msg)
+		}
+		if key.Matches(msg, m.keyMap.Palette) {
+			return m.openPalette()
+		}
+		if model, cmd, handled := m.updateTabKey(msg); handled {
+			return model, cmd
+		}
+		if m.command != nil {
+			return m.updateCommandLine(msg)
+		}
+		if msg.String() == ":" && m.acceptsCommand() {
+			m.command = &viCommandLine{}
+			return m, nil
+		}
+		if capturer, ok := m.activeView.(inputCapturer); ok && capturer.CapturesInput() && msg.String() != "ctrl+c" {
+			break
+		}
+
 		switch {
 		case key.Matches(msg, m.keyMap.Quit):
-			m.quitting = true
-			return m, tea.Quit
+			return m.quit()
 
 		case key.Matches(msg, m.keyMap.Back):
 			return m.handleBack()
 
 		case key.Matches(msg, m.keyMap.Help):
-			m.state = StateHelp
-			m.activeView = m.helpView
-			m.helpView.SetSize(m.width, m.height)
-			m.helpView.Focus()
-			return m, nil
+			if m.state == StateHelp {
+				return m.handleBack()
+			}
+			return m.openKeyHelp()
 		}
 
+	case tea.MouseMsg:
+		return m.updateMouse(msg)
+
 	case NavigationMsg:
 		return m.handleNavigation(msg)
 	}
@@ -205,18 +460,36 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View implements tea.Model
 func (m *MainModel) View() string {
+	view := m.render()
+	if m.accessible() {
+		return plainText(view)
+	}
+	return view
+}
+
+// render lays out the header, the active view and the footer
+func (m *MainModel) render() string {
 	if m.quitting {
-		return "Goodbye!\n"
+		if m.sessionErr != nil {
+			return i18n.T("app.goodbye_unsaved", m.sessionErr) + "\n"
+		}
+		return i18n.T("app.goodbye") + "\n"
 	}
 
 	if m.width == 0 || m.height == 0 {
-		return "Loading..."
+		return i18n.T("app.loading")
 	}
 
 	// Create the main layout
 	header := m.renderHeader()
+	if tabBar := m.renderTabBar(); tabBar != "" {
+		header = lipgloss.JoinVertical(lipgloss.Left, header, tabBar)
+	}
 	content := m.renderContent()
 	footer := m.renderFooter()
+	if m.logs.Open() {
+		footer = lipgloss.JoinVertical(lipgloss.Left, m.logs.View(m.width), footer)
+	}
 
 	// Calculate content height
 	headerHeight := lipgloss.Height(header)
@@ -228,6 +501,10 @@ func (m *MainModel) View() string {
 		Width(m.width).
 		Height(contentHeight).
 		Padding(0, 1)
+	if m.logs.Open() {
+		// Keep the panel on screen when the content is too tall
+		contentStyle = contentStyle.MaxHeight(contentHeight)
+	}
 
 	styledContent := contentStyle.Render(content)
 
@@ -236,72 +513,98 @@ func (m *MainModel) View() string {
 
 // renderHeader renders the application header
 func (m *MainModel) renderHeader() string {
-	title := "NetTraceX - Network Diagnostic Toolkit"
-	
+	title := i18n.T("app.title")
+
+	// Show which traffic leaves through a proxy
+	if m.config != nil {
+		if proxied := m.config.Network.Proxy.Proxied(); len(proxied) > 0 {
+			title += " • ⇄ " + i18n.T("app.proxied", strings.Join(proxied, ", "))
+		}
+	}
+	if running := m.jobs.Running(); running > 0 {
+		title += " • ⏳ " + i18n.T("app.running", running)
+	}
+	if m.newRelease != nil {
+		title += " • ⬆ " + i18n.T("app.update_available", m.newRelease.Version)
+	}
+
 	headerStyle := lipgloss.NewStyle().
 		Width(m.width).
 		Padding(0, 1).
-		Background(lipgloss.Color("62")).
-		Foreground(lipgloss.Color("230")).
+		Background(colors.Primary).
+		Foreground(colors.Highlight).
 		Bold(true)
+	if m.theme != nil {
+		headerStyle = themeStyle(m.theme, "header").Width(m.width).Padding(0, 1)
+	}
 
 	return headerStyle.Render(title)
 }
 
 // renderContent renders the main content area
 func (m *MainModel) renderContent() string {
+	if m.state == StateRestore {
+		return m.renderRestorePrompt()
+	}
+	if m.palette != nil {
+		return m.palette.View()
+	}
+	if m.keyHelp {
+		return m.renderKeyHelp()
+	}
 	if m.activeView == nil {
-		return "No active view"
+		return i18n.T("app.no_view")
 	}
 	return m.activeView.View()
 }
 
 // renderFooter renders the application footer with key bindings
 func (m *MainModel) renderFooter() string {
-	var keys []string
-	
-	switch m.state {
-	case StateMainMenu, StateNavigation:
-		keys = []string{
-			"↑/↓: navigate",
-			"PgUp/PgDown: page",
-			"Home/End: jump",
-			"enter: select",
-			"?: help",
-			"q: quit",
-		}
-	case StateHelp:
-		keys = []string{
-			"↑/↓: scroll",
-			"PgUp/PgDown: page",
-			"Home/End: jump",
-			"esc: back",
-			"q: quit",
-		}
-	default:
-		keys = []string{
-			"esc: back",
-			"?: help",
-			"q: quit",
-		}
-	}
-
 	footerStyle := lipgloss.NewStyle().
 		Width(m.width).
 		Padding(0, 1).
-		Background(lipgloss.Color("240")).
-		Foreground(lipgloss.Color("252"))
+		Background(colors.Border).
+		Foreground(colors.Text)
+	if m.theme != nil {
+		footerStyle = themeStyle(m.theme, "footer").Width(m.width).Padding(0, 1)
+	}
 
-	return footerStyle.Render(strings.Join(keys, " • "))
+	var prefix string
+	if m.configStatus != "" {
+		prefix = m.configStatus + " • "
+	}
+	if m.command != nil && m.palette == nil && !m.keyHelp {
+		return footerStyle.Render(prefix + m.command.prompt())
+	}
+	keys := footerHelp(m.width - 2 - lipgloss.Width(prefix)).ShortHelpView(m.footerBindings())
+	return footerStyle.Render(prefix + keys)
+}
+
+// openHelp shows the help screen in the active tab
+func (m *MainModel) openHelp() (*MainModel, tea.Cmd) {
+	m.state = StateHelp
+	m.activeView = m.helpView
+	m.helpView.SetSize(m.width, m.height)
+	m.helpView.Focus()
+	return m, nil
 }
 
 // handleBack handles the back navigation
 func (m *MainModel) handleBack() (*MainModel, tea.Cmd) {
 	switch m.state {
 	case StateMainMenu:
-		m.quitting = true
-		return m, tea.Quit
+		if len(m.tabs) > 1 {
+			return m.closeTab()
+		}
+		return m.quit()
 	case StateDiagnostic, StateSettings, StateHelp:
+		if m.state == StateSettings {
+			// Key bindings and the color mode edited in the settings apply on leaving them
+			m.applyKeys()
+			m.applyColorMode()
+		}
+		m.rememberForm()
+		m.detachJob()
 		m.state = StateMainMenu
 		m.activeView = m.navigation
 		m.navigation.Focus()
@@ -331,54 +634,142 @@ func (m *MainModel) handleNavigation(msg NavigationMsg) (*MainModel, tea.Cmd) {
 	return m, nil
 }
 
+// newDiagnosticView creates a diagnostic view for tool sharing the session's
+// size, theme, result history, export directory and previously entered values
+func (m *MainModel) newDiagnosticView(tool domain.DiagnosticTool) *DiagnosticViewModel {
+	diagnosticView := NewDiagnosticViewModel(tool)
+	if values, ok := m.forms[tool.Name()]; ok {
+		diagnosticView.SetFormValues(values)
+	}
+	diagnosticView.SetSize(m.width, m.height)
+	diagnosticView.SetTheme(m.theme)
+	diagnosticView.SetHistory(m.history)
+	diagnosticView.SetTableLayouts(m.tableLayouts)
+	diagnosticView.SetTargets(m.targets)
+	diagnosticView.SetKeyMap(m.keyMap)
+	if m.config != nil {
+		if m.config.Export.OutputDirectory != "" {
+			diagnosticView.SetExportDirectory(m.config.Export.OutputDirectory)
+		}
+		diagnosticView.SetExportFormat(m.config.Export.DefaultFormat)
+	}
+	return diagnosticView
+}
+
 // selectNavigationItem handles navigation item selection
 func (m *MainModel) selectNavigationItem(item NavigationItem) (*MainModel, tea.Cmd) {
+	defer func() {
+		if setter, ok := m.activeView.(keyMapSetter); ok {
+			setter.SetKeyMap(m.keyMap)
+		}
+	}()
+	m.screenTitle = item.Title
+	if m.screenTitle == "" {
+		m.screenTitle = item.ID
+	}
 	switch item.ID {
+	case "dashboard":
+		m.state = StateDiagnostic
+		var dashboard domain.DashboardConfig
+		var refresh time.Duration
+		if m.config != nil {
+			dashboard = m.config.UI.Dashboard
+			refresh = m.config.UI.RefreshInterval
+		}
+		dashboardView := NewDashboardViewModel(m.plugins, m.history, dashboard, refresh)
+		dashboardView.SetSize(m.width, m.height)
+		dashboardView.SetTheme(m.theme)
+		m.activeView = dashboardView
+		return m, dashboardView.Init()
 	case "whois":
 		m.state = StateDiagnostic
 		if tool, exists := m.plugins.Get("whois"); exists {
-			diagnosticView := NewDiagnosticViewModel(tool)
-			diagnosticView.SetSize(m.width, m.height)
-			diagnosticView.SetTheme(m.theme)
-			m.activeView = diagnosticView
+			m.activeView = m.newDiagnosticView(tool)
 		}
 		return m, nil
 	case "ping":
 		m.state = StateDiagnostic
 		if tool, exists := m.plugins.Get("ping"); exists {
-			diagnosticView := NewDiagnosticViewModel(tool)
-			diagnosticView.SetSize(m.width, m.height)
-			diagnosticView.SetTheme(m.theme)
-			m.activeView = diagnosticView
+			m.activeView = m.newDiagnosticView(tool)
 		}
 		return m, nil
 	case "traceroute":
 		m.state = StateDiagnostic
 		if tool, exists := m.plugins.Get("traceroute"); exists {
-			diagnosticView := NewDiagnosticViewModel(tool)
-			diagnosticView.SetSize(m.width, m.height)
-			diagnosticView.SetTheme(m.theme)
-			m.activeView = diagnosticView
+			m.activeView = m.newDiagnosticView(tool)
 		}
 		return m, nil
 	case "dns":
 		m.state = StateDiagnostic
 		if tool, exists := m.plugins.Get("dns"); exists {
-			diagnosticView := NewDiagnosticViewModel(tool)
-			diagnosticView.SetSize(m.width, m.height)
-			diagnosticView.SetTheme(m.theme)
-			m.activeView = diagnosticView
+			m.activeView = m.newDiagnosticView(tool)
 		}
 		return m, nil
 	case "ssl":
 		m.state = StateDiagnostic
 		if tool, exists := m.plugins.Get("ssl"); exists {
-			diagnosticView := NewDiagnosticViewModel(tool)
-			diagnosticView.SetSize(m.width, m.height)
-			diagnosticView.SetTheme(m.theme)
-			m.activeView = diagnosticView
+			m.activeView = m.newDiagnosticView(tool)
 		}
 		return m, nil
+	case "dualstack":
+		m.state = StateDiagnostic
+		if tool, exists := m.plugins.Get("dualstack"); exists {
+			m.activeView = m.newDiagnosticView(tool)
+		}
+		return m, nil
+	case "sweep":
+		m.state = StateDiagnostic
+		if tool, exists := m.plugins.Get("sweep"); exists {
+			m.activeView = m.newDiagnosticView(tool)
+		}
+		return m, nil
+	case "axfr":
+		m.state = StateDiagnostic
+		if tool, exists := m.plugins.Get("axfr"); exists {
+			m.activeView = m.newDiagnosticView(tool)
+		}
+		return m, nil
+	case "dns_servers":
+		m.state = StateDiagnostic
+		serversView := NewDNSServersViewModel(m.dnsReporter)
+		serversView.SetSize(m.width, m.height)
+		serversView.SetTheme(m.theme)
+		m.activeView = serversView
+		return m, serversView.Init()
+	case "cache":
+		m.state = StateDiagnostic
+		cacheView := NewCacheViewModel(m.cacheReporter)
+		cacheView.SetSize(m.width, m.height)
+		cacheView.SetTheme(m.theme)
+		m.activeView = cacheView
+		return m, cacheView.Init()
+	case "capabilities":
+		m.state = StateDiagnostic
+		capabilitiesView := NewCapabilitiesViewModel(m.capabilities)
+		capabilitiesView.SetSize(m.width, m.height)
+		capabilitiesView.SetTheme(m.theme)
+		m.activeView = capabilitiesView
+		return m, capabilitiesView.Init()
+	case "jobs":
+		m.state = StateDiagnostic
+		jobsView := NewJobsViewModel(m.jobs)
+		jobsView.reducedMotion = m.reducedMotion()
+		jobsView.SetSize(m.width, m.height)
+		jobsView.SetTheme(m.theme)
+		jobsView.Focus()
+		m.activeView = jobsView
+		return m, jobsView.Init()
+	case "plugins":
+		m.state = StateDiagnostic
+		var enable func(name string, enabled bool) error
+		if m.configManager != nil {
+			enable = m.configManager.SetPluginEnabled
+		}
+		pluginsView := NewPluginsViewModel(m.pluginReporter, enable)
+		pluginsView.SetSize(m.width, m.height)
+		pluginsView.SetTheme(m.theme)
+		m.activeView = pluginsView
+		return m, pluginsView.Init()
 	case "settings":
 		m.state = StateSettings
 		m.activeView = m.configView
@@ -387,6 +778,11 @@ func (m *MainModel) selectNavigationItem(item NavigationItem) (*MainModel, tea.C
 		m.configView.Focus()
 		return m, nil
 	default:
+		// Plugin tools are listed under their own names
+		if tool, exists := m.plugins.Get(item.ID); exists {
+			m.state = StateDiagnostic
+			m.activeView = m.newDiagnosticView(tool)
+		}
 		return m, nil
 	}
 }
@@ -436,6 +832,40 @@ func (m *MainModel) SetTheme(theme domain.Theme) {
 			component.SetTheme(theme)
 		}
 	}
+	
+	for _, view := range m.backgroundViews() {
+		if component, ok := view.(domain.TUIComponent); ok {
+			component.SetTheme(theme)
+		}
+	}
+}
+
+// SetThemeManager provides the themes, such as those of the theme files,
+// and shows the UI in the current one
+func (m *MainModel) SetThemeManager(themes *ThemeManager) {
+	m.themes = themes
+	m.SetTheme(themes.GetTheme())
+}
+
+// useTheme shows the UI in the theme called name, reading the theme files
+// again when it is not one of the loaded themes
+func (m *MainModel) useTheme(name string) {
+	if !m.themes.SetTheme(name) && m.configManager != nil {
+		for _, err := range m.themes.LoadDir(m.configManager.ThemeDir()) {
+			m.configStatus = fmt.Sprintf("⚠ theme not loaded: %v", err)
+		}
+		if !m.themes.SetTheme(name) {
+			return
+		}
+	}
+	m.SetTheme(m.themes.GetTheme())
+}
+
+// publishExport announces a report saved from the result view of any tab
+func (m *MainModel) publishExport(msg ResultExportedMsg) {
+	if msg.Error == nil {
+		m.events.Publish(events.Event{Kind: events.KindResultExported, Tool: msg.Tool, Path: msg.Path})
+	}
 }
 
 // Focus implements domain.TUIComponent
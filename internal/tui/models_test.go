@@ -275,7 +275,7 @@ func TestMainModel_SelectNavigationItem(t *testing.T) {
 	config := &domain.Config{}
 
 	// Create mock diagnostic tools for each tool type
-	diagnosticTools := []string{"whois", "ping", "traceroute", "dns", "ssl"}
+	diagnosticTools := []string{"whois", "ping", "traceroute", "dns", "ssl", "dualstack", "sweep", "axfr"}
 	for _, toolName := range diagnosticTools {
 		mockTool := &MockDiagnosticTool{}
 		mockTool.On("Name").Return(toolName)
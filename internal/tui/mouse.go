@@ -0,0 +1,161 @@
+// Package tui contains the mouse support. The program reports clicks and the
+// wheel; the main model hands them to the tab bar, the log panel or the
+// active screen, which sees positions relative to its own top left corner.
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// wheelDelta returns -1 when msg turns the wheel up, 1 when it turns it
+// down and 0 for any other mouse event
+func wheelDelta(msg tea.MouseMsg) int {
+	if msg.Action != tea.MouseActionPress {
+		return 0
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		return -1
+	case tea.MouseButtonWheelDown:
+		return 1
+	}
+	return 0
+}
+
+// leftClick reports whether msg presses the left button
+func leftClick(msg tea.MouseMsg) bool {
+	return msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft
+}
+
+// mouseBelow moves msg into the coordinates of content rendered after
+// above, which ends with the newline that starts the content
+func mouseBelow(msg tea.MouseMsg, above string) tea.MouseMsg {
+	msg.Y -= strings.Count(above, "\n")
+	return msg
+}
+
+// mouseOver moves msg into the coordinates of part, a block left aligned
+// somewhere in view. It reports false when view does not show part.
+func mouseOver(msg tea.MouseMsg, view, part string) (tea.MouseMsg, bool) {
+	first := strings.TrimRight(strings.SplitN(part, "\n", 2)[0], " ")
+	if first == "" {
+		return msg, false
+	}
+	for i, line := range strings.Split(view, "\n") {
+		if strings.HasPrefix(line, first) {
+			msg.Y -= i
+			return msg, true
+		}
+	}
+	return msg, false
+}
+
+// updateMouse hands a mouse event to what is drawn under it: a click on the
+// tab bar switches tabs, the wheel over the log panel scrolls it and
+// everything within the content area goes to the active screen
+func (m *MainModel) updateMouse(msg tea.MouseMsg) (*MainModel, tea.Cmd) {
+	if m.state == StateRestore || m.palette != nil || m.keyHelp || m.width == 0 {
+		return m, nil
+	}
+
+	top := lipgloss.Height(m.renderHeader())
+	if len(m.tabs) > 1 {
+		if msg.Y == top {
+			if index := m.tabAt(msg.X); index >= 0 && leftClick(msg) {
+				return m.switchTab(index)
+			}
+			return m, nil
+		}
+		top++
+	}
+
+	bottom := m.height - lipgloss.Height(m.renderFooter())
+	if m.logs.Open() {
+		bottom -= lipgloss.Height(m.logs.View(m.width))
+		if msg.Y >= bottom {
+			// Up goes back to older records
+			m.logs.scroll(-wheelDelta(msg))
+			return m, nil
+		}
+	}
+	if msg.Y < top || msg.Y >= bottom || m.activeView == nil {
+		return m, nil
+	}
+
+	// The content area is padded by a column on the left
+	msg.X--
+	msg.Y -= top
+	var cmd tea.Cmd
+	m.activeView, cmd = m.activeView.Update(msg)
+	return m, cmd
+}
+
+// tabAt returns the index of the tab whose label covers column x of the tab
+// bar, or -1 when x is past the last tab
+func (m *MainModel) tabAt(x int) int {
+	for i, label := range m.renderTabLabels() {
+		width := lipgloss.Width(label)
+		if x < width {
+			return i
+		}
+		x -= width
+	}
+	return -1
+}
+
+// updateMouse selects the row clicked on and moves the selection with the
+// wheel. The header and its separator take the first two lines.
+func (m *TableModel) updateMouse(msg tea.MouseMsg) {
+	rows := m.getFilteredRows()
+	if len(rows) == 0 {
+		return
+	}
+	if delta := wheelDelta(msg); delta != 0 {
+		m.selected += delta
+	} else if leftClick(msg) && msg.Y >= 2 && msg.Y-2 < len(rows) {
+		m.selected = msg.Y - 2
+	}
+	if m.selected < 0 {
+		m.selected = 0
+	}
+	if m.selected >= len(rows) {
+		m.selected = len(rows) - 1
+	}
+}
+
+// updateMouse focuses the field clicked on, laying the fields out as View
+// does
+func (m *FormModel) updateMouse(msg tea.MouseMsg) {
+	if !leftClick(msg) {
+		return
+	}
+	y := 0
+	if m.title != "" {
+		// The padded title and the blank line after it
+		y = 4
+	}
+	for i, field := range m.fields {
+		height := lipgloss.Height(m.renderField(field, i == m.focused))
+		if msg.Y >= y && msg.Y < y+height {
+			m.focusField(i)
+			return
+		}
+		y += height + 1
+	}
+}
+
+// focusField moves focus to the field at index i
+func (m *FormModel) focusField(i int) {
+	if i == m.focused {
+		return
+	}
+	if m.focused >= 0 && m.focused < len(m.fields) {
+		m.fields[m.focused].Input.Blur()
+	}
+	m.focused = i
+	m.fields[m.focused].Input.Focus()
+	m.refreshSuggestions()
+}
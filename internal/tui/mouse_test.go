@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	configpkg "github.com/nettracex/nettracex-tui/internal/config"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clickAt presses the left button at x, y
+func clickAt(x, y int) tea.MouseMsg {
+	return tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft}
+}
+
+// wheelAt turns the wheel over x, y
+func wheelAt(x, y int, button tea.MouseButton) tea.MouseMsg {
+	return tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: button}
+}
+
+// screenLine returns the line of view containing text and where text starts
+func screenLine(t *testing.T, view, text string) (int, int) {
+	for y, line := range strings.Split(ansi.Strip(view), "\n") {
+		if x := strings.Index(line, text); x >= 0 {
+			return ansi.StringWidth(line[:x]), y
+		}
+	}
+	require.Failf(t, "text not shown", "%q is not in the view", text)
+	return 0, 0
+}
+
+func TestMainModel_MouseSwitchesTabs(t *testing.T) {
+	model := newTabsModel(t)
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	_, active := model.Tabs()
+	require.Equal(t, 1, active)
+
+	x, y := screenLine(t, model.View(), "1 Menu")
+	model.Update(clickAt(x, y))
+	_, active = model.Tabs()
+	assert.Equal(t, 0, active)
+
+	model.Update(clickAt(119, y))
+	_, active = model.Tabs()
+	assert.Equal(t, 0, active, "clicks past the last tab do nothing")
+}
+
+func TestTableModel_Mouse(t *testing.T) {
+	table := NewTableModel([]string{"Host"})
+	table.SetData([][]string{{"a"}, {"b"}, {"c"}})
+
+	table.Update(clickAt(1, 3))
+	row, _ := table.SelectedRow()
+	assert.Equal(t, []string{"b"}, row, "rows start below the header and its separator")
+
+	table.Update(wheelAt(1, 0, tea.MouseButtonWheelDown))
+	table.Update(wheelAt(1, 0, tea.MouseButtonWheelDown))
+	row, _ = table.SelectedRow()
+	assert.Equal(t, []string{"c"}, row, "the wheel stops at the last row")
+
+	table.Update(clickAt(1, 1))
+	row, _ = table.SelectedRow()
+	assert.Equal(t, []string{"c"}, row, "clicks on the header keep the selection")
+}
+
+func TestMainModel_MouseOnDiagnostic(t *testing.T) {
+	model := NewMainModel(newDashboardRegistry(t, &dashboardTool{name: "ping"}), &domain.Config{}, configpkg.NewManager(), nil)
+	model.Update(tea.WindowSizeMsg{Width: 120, Height: 50})
+	model.Update(NavigationMsg{Action: NavigationActionSelect, Data: NavigationItem{ID: "ping"}})
+	view, ok := model.activeView.(*DiagnosticViewModel)
+	require.True(t, ok)
+
+	// Clicking a field focuses it
+	label := view.inputForm.fields[1].Label
+	x, y := screenLine(t, model.View(), label)
+	model.Update(clickAt(x, y+1))
+	assert.Equal(t, 1, view.inputForm.focused)
+	assert.True(t, view.inputForm.fields[1].Input.Focused())
+	assert.False(t, view.inputForm.fields[0].Input.Focused())
+
+	// Clicking a row of the result table selects it
+	view.Update(DiagnosticResultMsg{Result: domain.NewResult([]domain.PingResult{
+		{Sequence: 1, Host: domain.NetworkHost{Hostname: "gw1.example"}},
+		{Sequence: 2, Host: domain.NetworkHost{Hostname: "gw2.example"}},
+		{Sequence: 3, Host: domain.NetworkHost{Hostname: "gw3.example"}},
+	})})
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	x, y = screenLine(t, model.View(), "gw2.example")
+	model.Update(clickAt(x, y))
+	row, ok := view.resultView.tableModel.SelectedRow()
+	require.True(t, ok)
+	assert.Contains(t, row, "gw2.example")
+}
+
+func TestMainModel_MouseScrollsLogPanel(t *testing.T) {
+	buffer := logging.NewBuffer(0)
+	logger := logging.NewWriter(&strings.Builder{}, logging.LevelError, logging.FormatText)
+	logger.SetBuffer(buffer)
+	for i := 0; i < logPanelHeight+3; i++ {
+		logger.Info(fmt.Sprintf("record %d", i))
+	}
+
+	model := NewMainModel(newDashboardRegistry(t), &domain.Config{}, configpkg.NewManager(), nil)
+	model.SetLogBuffer(buffer)
+	model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	require.True(t, model.logs.Open())
+
+	footer := lipgloss.Height(model.renderFooter())
+	model.Update(wheelAt(10, 40-footer-1, tea.MouseButtonWheelUp))
+	assert.Equal(t, 1, model.logs.offset, "the wheel scrolls back through the records")
+	model.Update(wheelAt(10, 40-footer-1, tea.MouseButtonWheelDown))
+	assert.Equal(t, 0, model.logs.offset)
+}
+
+func TestStandardScrollPager_Wheel(t *testing.T) {
+	pager := NewStandardScrollPager()
+	pager.SetSize(80, 10)
+	pager.SetItems([]ScrollableItem{
+		NewStringScrollableItem("one", "1"),
+		NewStringScrollableItem("two", "2"),
+	})
+
+	pager.Update(wheelAt(0, 0, tea.MouseButtonWheelDown))
+	assert.Equal(t, 1, pager.GetSelected())
+	pager.Update(wheelAt(0, 0, tea.MouseButtonWheelUp))
+	assert.Equal(t, 0, pager.GetSelected())
+}
@@ -5,7 +5,9 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
 	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
 )
 
 // NavigationAction represents different navigation actions
@@ -57,12 +59,12 @@ func (n NavigationItem) Render(width int, selected bool, theme domain.Theme) str
 		var descStyle lipgloss.Style
 		if theme != nil {
 			descStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color(theme.GetColor("muted"))).
+				Foreground(colors.Adaptive(theme.GetColor("muted"))).
 				Italic(true)
 		} else {
 			// Fallback styling
 			descStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("243")).
+				Foreground(colors.Muted).
 				Italic(true)
 		}
 		itemText += "\n  " + descStyle.Render(n.Description)
@@ -91,6 +93,11 @@ func (n NavigationItem) IsSelectable() bool {
 	return n.Enabled
 }
 
+// SearchText returns the text the vi / search matches
+func (n NavigationItem) SearchText() string {
+	return n.Title + " " + n.Description
+}
+
 // GetID implements ScrollableItem interface
 // Returns a unique identifier for this item
 func (n NavigationItem) GetID() string {
@@ -107,26 +114,26 @@ func (n NavigationItem) getItemStyle(width int, selected, enabled bool, theme do
 	if theme != nil {
 		// Use theme-aware styling
 		if !enabled {
-			style = style.Foreground(lipgloss.Color(theme.GetColor("muted")))
+			style = style.Foreground(colors.Adaptive(theme.GetColor("muted")))
 		} else if selected {
 			style = style.
-				Background(lipgloss.Color(theme.GetColor("primary"))).
-				Foreground(lipgloss.Color(theme.GetColor("highlight"))).
+				Background(colors.Adaptive(theme.GetColor("primary"))).
+				Foreground(colors.Adaptive(theme.GetColor("highlight"))).
 				Bold(true)
 		} else {
-			style = style.Foreground(lipgloss.Color(theme.GetColor("foreground")))
+			style = style.Foreground(colors.Adaptive(theme.GetColor("foreground")))
 		}
 	} else {
 		// Fallback styling when no theme is available
 		if !enabled {
-			style = style.Foreground(lipgloss.Color("240"))
+			style = style.Foreground(colors.Border)
 		} else if selected {
 			style = style.
-				Background(lipgloss.Color("62")).
-				Foreground(lipgloss.Color("230")).
+				Background(colors.Primary).
+				Foreground(colors.Highlight).
 				Bold(true)
 		} else {
-			style = style.Foreground(lipgloss.Color("252"))
+			style = style.Foreground(colors.Text)
 		}
 	}
 
@@ -145,45 +152,108 @@ type NavigationModel struct {
 // NewNavigationModel creates a new navigation model
 func NewNavigationModel() *NavigationModel {
 	items := []NavigationItem{
+		{
+			ID:          "dashboard",
+			Title:       i18n.T("menu.dashboard"),
+			Description: i18n.T("menu.dashboard.description"),
+			Icon:        "🏠",
+			Enabled:     true,
+		},
+		{
+			ID:          "jobs",
+			Title:       i18n.T("menu.jobs"),
+			Description: i18n.T("menu.jobs.description"),
+			Icon:        "⏳",
+			Enabled:     true,
+		},
 		{
 			ID:          "whois",
-			Title:       "WHOIS Lookup",
-			Description: "Domain and IP registration information",
+			Title:       i18n.T("menu.whois"),
+			Description: i18n.T("menu.whois.description"),
 			Icon:        "🔍",
 			Enabled:     true,
 		},
 		{
 			ID:          "ping",
-			Title:       "Ping Test",
-			Description: "Test connectivity and measure latency",
+			Title:       i18n.T("menu.ping"),
+			Description: i18n.T("menu.ping.description"),
 			Icon:        "📡",
 			Enabled:     true,
 		},
 		{
 			ID:          "traceroute",
-			Title:       "Traceroute",
-			Description: "Trace network path to destination",
+			Title:       i18n.T("menu.traceroute"),
+			Description: i18n.T("menu.traceroute.description"),
 			Icon:        "🗺️",
 			Enabled:     true,
 		},
 		{
 			ID:          "dns",
-			Title:       "DNS Lookup",
-			Description: "Query DNS records for domains",
+			Title:       i18n.T("menu.dns"),
+			Description: i18n.T("menu.dns.description"),
 			Icon:        "🌐",
 			Enabled:     true,
 		},
 		{
 			ID:          "ssl",
-			Title:       "SSL Certificate Check",
-			Description: "Verify SSL certificate validity",
+			Title:       i18n.T("menu.ssl"),
+			Description: i18n.T("menu.ssl.description"),
 			Icon:        "🔒",
 			Enabled:     true,
 		},
+		{
+			ID:          "dualstack",
+			Title:       i18n.T("menu.dualstack"),
+			Description: i18n.T("menu.dualstack.description"),
+			Icon:        "🔀",
+			Enabled:     true,
+		},
+		{
+			ID:          "sweep",
+			Title:       i18n.T("menu.sweep"),
+			Description: i18n.T("menu.sweep.description"),
+			Icon:        "📶",
+			Enabled:     true,
+		},
+		{
+			ID:          "axfr",
+			Title:       i18n.T("menu.axfr"),
+			Description: i18n.T("menu.axfr.description"),
+			Icon:        "🔓",
+			Enabled:     true,
+		},
+		{
+			ID:          "dns_servers",
+			Title:       i18n.T("menu.dns_servers"),
+			Description: i18n.T("menu.dns_servers.description"),
+			Icon:        "🩺",
+			Enabled:     true,
+		},
+		{
+			ID:          "cache",
+			Title:       i18n.T("menu.cache"),
+			Description: i18n.T("menu.cache.description"),
+			Icon:        "🗄️",
+			Enabled:     true,
+		},
+		{
+			ID:          "capabilities",
+			Title:       i18n.T("menu.capabilities"),
+			Description: i18n.T("menu.capabilities.description"),
+			Icon:        "🛡️",
+			Enabled:     true,
+		},
+		{
+			ID:          "plugins",
+			Title:       i18n.T("menu.plugins"),
+			Description: i18n.T("menu.plugins.description"),
+			Icon:        "🔌",
+			Enabled:     true,
+		},
 		{
 			ID:          "settings",
-			Title:       "Settings",
-			Description: "Configure application preferences",
+			Title:       i18n.T("menu.settings"),
+			Description: i18n.T("menu.settings.description"),
 			Icon:        "⚙️",
 			Enabled:     true,
 		},
@@ -219,11 +289,16 @@ func (m *NavigationModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// Delegate scrolling to StandardScrollPager
 	var cmd tea.Cmd
+	searching := m.scrollPager.CapturesInput()
 	updatedModel, scrollCmd := m.scrollPager.Update(msg)
 	if pager, ok := updatedModel.(*StandardScrollPager); ok {
 		m.scrollPager = pager
 		cmd = scrollCmd
 	}
+	if searching {
+		// Keys typed into the vi search prompt
+		return m, cmd
+	}
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -260,10 +335,10 @@ func (m *NavigationModel) View() string {
 	// Add title section
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("205")).
+		Foreground(colors.Accent).
 		Padding(1, 0)
 	
-	title := titleStyle.Render("Network Diagnostic Tools")
+	title := titleStyle.Render(i18n.T("menu.title"))
 	
 	// Render through StandardScrollPager
 	content := m.scrollPager.View()
@@ -291,6 +366,32 @@ func (m *NavigationModel) SetTheme(theme domain.Theme) {
 	m.scrollPager.SetTheme(theme)
 }
 
+// SetKeyMap sets the key bindings of the menu
+func (m *NavigationModel) SetKeyMap(keyMap KeyMap) {
+	m.keyMap = keyMap
+	m.scrollPager.SetKeyMap(keyMap)
+}
+
+// ShortHelp implements help.KeyMap
+func (m *NavigationModel) ShortHelp() []key.Binding {
+	return []key.Binding{m.keyMap.Up, m.keyMap.Down, m.keyMap.Enter}
+}
+
+// FullHelp implements help.KeyMap
+func (m *NavigationModel) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{m.keyMap.Up, m.keyMap.Down, m.keyMap.PageUp, m.keyMap.PageDown, m.keyMap.Home, m.keyMap.End, m.keyMap.Enter}}
+}
+
+// CapturesInput reports whether the vi search prompt is open
+func (m *NavigationModel) CapturesInput() bool {
+	return m.scrollPager.CapturesInput()
+}
+
+// JumpTo selects the item on line, counting from 1
+func (m *NavigationModel) JumpTo(line int) {
+	m.scrollPager.JumpTo(line)
+}
+
 // Focus implements domain.TUIComponent
 func (m *NavigationModel) Focus() {
 	m.focused = true
@@ -315,6 +416,17 @@ func (m *NavigationModel) GetSelected() *NavigationItem {
 	return nil
 }
 
+// Items returns the menu items in display order
+func (m *NavigationModel) Items() []NavigationItem {
+	var items []NavigationItem
+	for _, item := range m.scrollPager.GetItems() {
+		if navItem, ok := item.(NavigationItem); ok {
+			items = append(items, navItem)
+		}
+	}
+	return items
+}
+
 // SetSelected sets the selected item by index
 func (m *NavigationModel) SetSelected(index int) {
 	m.scrollPager.SetSelected(index)
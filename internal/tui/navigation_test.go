@@ -20,7 +20,7 @@ func TestNewNavigationModel(t *testing.T) {
 	assert.Empty(t, model.breadcrumbs)
 
 	// Check that default items are present
-	expectedItems := []string{"whois", "ping", "traceroute", "dns", "ssl", "settings"}
+	expectedItems := []string{"dashboard", "jobs", "whois", "ping", "traceroute", "dns", "ssl", "dualstack", "sweep", "axfr", "dns_servers", "cache", "capabilities", "plugins", "settings"}
 	assert.Equal(t, len(expectedItems), len(items))
 	
 	for i, expectedID := range expectedItems {
@@ -118,7 +118,7 @@ func TestNavigationModel_Update_Enter_DisabledItem(t *testing.T) {
 	model.scrollPager.SetSelected(0)
 	
 	// Disable first item
-	model.DisableItem("whois")
+	model.DisableItem("dashboard")
 
 	// Test enter key on disabled item
 	msg := tea.KeyMsg{Type: tea.KeyEnter}
@@ -35,6 +35,7 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
 	"github.com/nettracex/nettracex-tui/internal/domain"
 )
 
@@ -77,6 +78,18 @@ func (p *Pager) SetContentLines(lines []string) {
 // Update handles pager input
 func (p *Pager) Update(msg tea.Msg) (*Pager, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		if !p.focused {
+			return p, nil
+		}
+
+		switch wheelDelta(msg) {
+		case -1:
+			p.scrollUp()
+		case 1:
+			p.scrollDown()
+		}
+
 	case tea.KeyMsg:
 		if !p.focused {
 			return p, nil
@@ -132,7 +145,7 @@ func (p *Pager) View() string {
 	// Top scroll indicator
 	if p.showScrollIndicators && p.scrollY > 0 {
 		scrollIndicator := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241")).
+			Foreground(colors.Subtle).
 			Align(lipgloss.Center).
 			Width(p.width).
 			Render("▲ More content above - Use ↑ or PgUp to scroll")
@@ -146,7 +159,7 @@ func (p *Pager) View() string {
 	if p.showScrollIndicators && endLine < len(p.content) {
 		result.WriteString("\n")
 		scrollIndicator := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241")).
+			Foreground(colors.Subtle).
 			Align(lipgloss.Center).
 			Width(p.width).
 			Render("▼ More content below - Use ↓ or PgDown to scroll")
@@ -328,6 +341,11 @@ func (s *StringScrollableItem) IsSelectable() bool {
 	return false // String items are not selectable by default
 }
 
+// SearchText returns the text the vi / search matches
+func (s *StringScrollableItem) SearchText() string {
+	return s.content
+}
+
 // GetID implements ScrollableItem interface
 func (s *StringScrollableItem) GetID() string {
 	return s.id
@@ -0,0 +1,429 @@
+// Package tui contains the command palette
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// paletteLimit is the number of matching actions listed
+const paletteLimit = 12
+
+// recentTargetLimit is the number of recent targets offered
+const recentTargetLimit = 10
+
+// recentTargetKeys are the form fields and result metadata that hold the
+// target of a run, in order of preference
+var recentTargetKeys = []string{"host", "query", "domain", "cidr"}
+
+// PaletteAction is an entry of the command palette
+type PaletteAction struct {
+	Category string
+	Title    string
+	run      func(m *MainModel) (*MainModel, tea.Cmd)
+}
+
+// PaletteModel fuzzy-searches the actions offered by the main model and
+// runs the chosen one
+type PaletteModel struct {
+	input    textinput.Model
+	actions  []PaletteAction
+	matches  []PaletteAction
+	selected int
+	width    int
+	theme    domain.Theme
+}
+
+// NewPaletteModel creates a palette offering actions
+func NewPaletteModel(actions []PaletteAction) *PaletteModel {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "Type to search tools, recent targets, settings and exports"
+	input.CharLimit = 128
+	input.Width = 60
+	input.Focus()
+
+	m := &PaletteModel{input: input, actions: actions}
+	m.filter()
+	return m
+}
+
+// Matches returns the actions matching the query, best first
+func (m *PaletteModel) Matches() []PaletteAction {
+	return m.matches
+}
+
+// Selected returns the highlighted action
+func (m *PaletteModel) Selected() (PaletteAction, bool) {
+	if m.selected < 0 || m.selected >= len(m.matches) {
+		return PaletteAction{}, false
+	}
+	return m.matches[m.selected], true
+}
+
+// Update handles a key. It returns true when the palette should close,
+// along with the action to run, if one was chosen.
+func (m *PaletteModel) Update(msg tea.KeyMsg) (*PaletteAction, bool, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlP:
+		return nil, true, nil
+	case tea.KeyEnter:
+		action, ok := m.Selected()
+		if !ok {
+			return nil, false, nil
+		}
+		return &action, true, nil
+	case tea.KeyUp, tea.KeyCtrlK:
+		if m.selected > 0 {
+			m.selected--
+		}
+		return nil, false, nil
+	case tea.KeyDown, tea.KeyCtrlJ, tea.KeyTab:
+		if m.selected < len(m.matches)-1 {
+			m.selected++
+		}
+		return nil, false, nil
+	}
+
+	var cmd tea.Cmd
+	query := m.input.Value()
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != query {
+		m.filter()
+	}
+	return nil, false, cmd
+}
+
+// View renders the search field and the matching actions
+func (m *PaletteModel) View() string {
+	borderColor := colors.Primary
+	categoryStyle := lipgloss.NewStyle().Foreground(colors.Muted).Width(10)
+	selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(colors.Highlight).Background(colors.Primary)
+	mutedStyle := lipgloss.NewStyle().Foreground(colors.Muted).Italic(true)
+	if m.theme != nil {
+		categoryStyle = categoryStyle.Foreground(colors.Adaptive(m.theme.GetColor("muted")))
+		mutedStyle = mutedStyle.Foreground(colors.Adaptive(m.theme.GetColor("muted")))
+	}
+
+	lines := []string{m.input.View(), ""}
+	if len(m.matches) == 0 {
+		lines = append(lines, mutedStyle.Render("No matching actions"))
+	}
+	for i, action := range m.matches {
+		line := categoryStyle.Render(action.Category) + " " + action.Title
+		if i == m.selected {
+			line = categoryStyle.Render(action.Category) + " " + selectedStyle.Render(action.Title)
+		}
+		lines = append(lines, line)
+	}
+
+	width := 72
+	if m.width > 0 && m.width-4 < width {
+		width = m.width - 4
+	}
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Padding(0, 1)
+	if m.theme != nil {
+		boxStyle = themeStyle(m.theme, "panel")
+	}
+	return boxStyle.Width(width).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// SetSize sets the width available to the palette
+func (m *PaletteModel) SetSize(width, height int) {
+	m.width = width
+}
+
+// SetTheme sets the palette colors
+func (m *PaletteModel) SetTheme(theme domain.Theme) {
+	m.theme = theme
+}
+
+// filter ranks the actions against the query
+func (m *PaletteModel) filter() {
+	query := strings.TrimSpace(m.input.Value())
+	type scored struct {
+		action PaletteAction
+		score  int
+	}
+	var ranked []scored
+	for _, action := range m.actions {
+		score, ok := fuzzyScore(query, action.Category+" "+action.Title)
+		if ok {
+			ranked = append(ranked, scored{action: action, score: score})
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].score > ranked[j].score
+	})
+
+	m.matches = m.matches[:0]
+	for _, r := range ranked {
+		if len(m.matches) == paletteLimit {
+			break
+		}
+		m.matches = append(m.matches, r.action)
+	}
+	m.selected = 0
+}
+
+// fuzzyScore reports whether the characters of query appear in text in
+// order, ignoring case and spaces, and scores the best match: consecutive
+// characters and characters at the start of words score higher
+func fuzzyScore(query, text string) (int, bool) {
+	query = strings.ToLower(strings.ReplaceAll(query, " ", ""))
+	if query == "" {
+		return 0, true
+	}
+	runes := []rune(strings.ToLower(text))
+	wanted := []rune(query)
+	best, found := 0, false
+	for start, r := range runes {
+		if r != wanted[0] {
+			continue
+		}
+		if score, ok := fuzzyScoreFrom(wanted, runes, start); ok && (!found || score > best) {
+			best, found = score, true
+		}
+	}
+	return best, found
+}
+
+// fuzzyScoreFrom matches wanted against runes starting at start, taking
+// each character at its first occurrence
+func fuzzyScoreFrom(wanted, runes []rune, start int) (int, bool) {
+	score, next, last := 0, 0, start-2
+	for i := start; i < len(runes) && next < len(wanted); i++ {
+		if runes[i] != wanted[next] {
+			continue
+		}
+		score++
+		if i == last+1 {
+			score += 2
+		}
+		if i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]) {
+			score += 3
+		}
+		last = i
+		next++
+	}
+	return score, next == len(wanted)
+}
+
+// openPalette shows the command palette with the actions available now
+func (m *MainModel) openPalette() (*MainModel, tea.Cmd) {
+	m.palette = NewPaletteModel(m.paletteActions())
+	m.palette.SetSize(m.width, m.height)
+	m.palette.SetTheme(m.theme)
+	return m, textinput.Blink
+}
+
+// updatePalette passes a key to the open palette and runs the chosen action
+func (m *MainModel) updatePalette(msg tea.KeyMsg) (*MainModel, tea.Cmd) {
+	if key.Matches(msg, key.NewBinding(key.WithKeys("ctrl+c"))) {
+		return m.quit()
+	}
+	action, closed, cmd := m.palette.Update(msg)
+	if closed {
+		m.palette = nil
+	}
+	if action != nil {
+		return action.run(m)
+	}
+	return m, cmd
+}
+
+// paletteActions lists the screens, recent targets, settings, tab actions
+// and exports offered by the palette
+func (m *MainModel) paletteActions() []PaletteAction {
+	var actions []PaletteAction
+
+	for _, target := range m.recentTargets() {
+		target := target
+		actions = append(actions, PaletteAction{
+			Category: "Recent",
+			Title:    fmt.Sprintf("Run %s %s", target.tool, target.value),
+			run: func(m *MainModel) (*MainModel, tea.Cmd) {
+				return m.runTarget(target)
+			},
+		})
+	}
+
+	for _, item := range m.navigation.Items() {
+		if !item.Enabled {
+			continue
+		}
+		item := item
+		actions = append(actions, PaletteAction{
+			Category: "Open",
+			Title:    item.Title,
+			run: func(m *MainModel) (*MainModel, tea.Cmd) {
+				m.leaveScreen()
+				return m.selectNavigationItem(item)
+			},
+		})
+	}
+
+	if diagnosticView, ok := m.activeView.(*DiagnosticViewModel); ok && diagnosticView.GetState() == DiagnosticStateResult {
+		for _, format := range saveFormats {
+			format := format
+			actions = append(actions, PaletteAction{
+				Category: "Export",
+				Title:    fmt.Sprintf("Export %s result as %s", diagnosticView.GetTool().Name(), format.Name),
+				run: func(m *MainModel) (*MainModel, tea.Cmd) {
+					return m, diagnosticView.ExportResult(format.Format, format.Extension)
+				},
+			})
+		}
+	}
+
+	if m.configManager != nil {
+		actions = append(actions, PaletteAction{
+			Category: "Config",
+			Title:    "Reload configuration file",
+			run: func(m *MainModel) (*MainModel, tea.Cmd) {
+				model, cmd := m.reloadConfig()
+				if model.configStatus == "" {
+					model.configStatus = "⟳ config unchanged"
+				}
+				return model, cmd
+			},
+		})
+	}
+	themes := m.themes.GetAvailableThemes()
+	sort.Strings(themes)
+	for _, name := range themes {
+		name := name
+		actions = append(actions, PaletteAction{
+			Category: "Config",
+			Title:    "Use " + name + " theme",
+			run: func(m *MainModel) (*MainModel, tea.Cmd) {
+				m.useTheme(name)
+				return m, nil
+			},
+		})
+	}
+
+	actions = append(actions, PaletteAction{
+		Category: "Tab",
+		Title:    "New tab",
+		run: func(m *MainModel) (*MainModel, tea.Cmd) {
+			return m.newTab()
+		},
+	})
+	if len(m.tabs) > 1 {
+		actions = append(actions, PaletteAction{
+			Category: "Tab",
+			Title:    "Close tab",
+			run: func(m *MainModel) (*MainModel, tea.Cmd) {
+				return m.closeTab()
+			},
+		})
+	}
+	return actions
+}
+
+// recentTarget is a target a tool ran against in this session
+type recentTarget struct {
+	tool  string
+	key   string
+	value string
+}
+
+// recentTargets returns the targets of the results in the history, newest
+// first, followed by those entered in forms
+func (m *MainModel) recentTargets() []recentTarget {
+	var targets []recentTarget
+	seen := make(map[recentTarget]bool)
+	add := func(tool string, values func(key string) string) {
+		for _, key := range recentTargetKeys {
+			value := strings.TrimSpace(values(key))
+			if value == "" {
+				continue
+			}
+			target := recentTarget{tool: tool, key: key, value: value}
+			if !seen[target] && len(targets) < recentTargetLimit {
+				seen[target] = true
+				targets = append(targets, target)
+			}
+			return
+		}
+	}
+
+	type entry struct {
+		tool  string
+		entry ResultHistoryEntry
+	}
+	var entries []entry
+	for _, tool := range m.history.Tools() {
+		for _, e := range m.history.Entries(tool) {
+			entries = append(entries, entry{tool: tool, entry: e})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].entry.Timestamp.After(entries[j].entry.Timestamp)
+	})
+	for _, e := range entries {
+		metadata := e.entry.Result.Metadata()
+		add(e.tool, func(key string) string {
+			value, _ := metadata[key].(string)
+			return value
+		})
+	}
+
+	tools := make([]string, 0, len(m.forms))
+	for tool := range m.forms {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	for _, tool := range tools {
+		values := m.forms[tool]
+		add(tool, func(key string) string { return values[key] })
+	}
+	return targets
+}
+
+// runTarget opens the tool of target in the active tab and runs it again
+// with the values last entered for the tool
+func (m *MainModel) runTarget(target recentTarget) (*MainModel, tea.Cmd) {
+	return m.runTool(target.tool, map[string]string{target.key: target.value})
+}
+
+// runTool opens tool in the active tab with values filled in over those
+// last entered for the tool, and runs it
+func (m *MainModel) runTool(name string, values map[string]string) (*MainModel, tea.Cmd) {
+	tool, exists := m.plugins.Get(name)
+	if !exists {
+		m.configStatus = fmt.Sprintf("⚠ %s is not available", name)
+		return m, nil
+	}
+	m.leaveScreen()
+	diagnosticView := m.newDiagnosticView(tool)
+	diagnosticView.SetFormValues(values)
+	m.state = StateDiagnostic
+	m.screenTitle = tool.Name()
+	m.activeView = diagnosticView
+	m.navigation.Blur()
+	return m, diagnosticView.Resume()
+}
+
+// leaveScreen prepares the active tab for another screen, keeping the
+// values entered in the open tool and a running tool as a background job
+func (m *MainModel) leaveScreen() {
+	m.rememberForm()
+	m.detachJob()
+	if m.helpView != nil {
+		m.helpView.Blur()
+	}
+}
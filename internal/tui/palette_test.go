@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// typePalette enters text into the open palette of model
+func typePalette(model *MainModel, text string) {
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)})
+}
+
+func TestFuzzyScore(t *testing.T) {
+	_, ok := fuzzyScore("", "anything")
+	assert.True(t, ok, "an empty query matches everything")
+
+	_, ok = fuzzyScore("pngt", "Open Ping Test")
+	assert.True(t, ok)
+	_, ok = fuzzyScore("tping", "Open Ping Test")
+	assert.False(t, ok, "characters must appear in order")
+
+	prefix, _ := fuzzyScore("ping", "Open Ping Test")
+	scattered, _ := fuzzyScore("ping", "Open Port Scanning")
+	assert.Greater(t, prefix, scattered, "consecutive characters at a word start rank first")
+}
+
+func TestPaletteModel_Filter(t *testing.T) {
+	palette := NewPaletteModel([]PaletteAction{
+		{Category: "Open", Title: "Port Scanning"},
+		{Category: "Open", Title: "Ping Test"},
+		{Category: "Tab", Title: "New tab"},
+	})
+	assert.Len(t, palette.Matches(), 3)
+
+	palette.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ping")})
+	require.NotEmpty(t, palette.Matches())
+	assert.Equal(t, "Ping Test", palette.Matches()[0].Title)
+
+	palette.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("zzz")})
+	assert.Empty(t, palette.Matches())
+	assert.Contains(t, palette.View(), "No matching actions")
+
+	action, closed, _ := palette.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, action)
+	assert.False(t, closed, "nothing to run")
+
+	_, closed, _ = palette.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.True(t, closed)
+}
+
+func TestMainModel_PaletteOpensScreen(t *testing.T) {
+	model := newTabsModel(t)
+
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	require.NotNil(t, model.palette)
+	assert.Contains(t, model.View(), "Dashboard")
+
+	typePalette(model, "dashbrd")
+	action, ok := model.palette.Selected()
+	require.True(t, ok)
+	assert.Equal(t, "Dashboard", action.Title)
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, model.palette)
+	assert.IsType(t, &DashboardViewModel{}, model.activeView)
+
+	// Keys reach the screen again once the palette is closed
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Nil(t, model.palette)
+	assert.IsType(t, &DashboardViewModel{}, model.activeView)
+}
+
+func TestMainModel_PaletteRunsRecentTarget(t *testing.T) {
+	model := newTabsModel(t)
+	result := domain.NewResult([]domain.PingResult{{RTT: time.Millisecond}})
+	result.SetMetadata("host", "old.example.com")
+	model.history.Add("ping", result)
+	model.forms["ping"] = map[string]string{"host": "example.com", "count": "2"}
+
+	targets := model.recentTargets()
+	require.Len(t, targets, 2)
+	assert.Equal(t, "old.example.com", targets[0].value, "results come first")
+	assert.Equal(t, "example.com", targets[1].value)
+
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	typePalette(model, "run old")
+	action, ok := model.palette.Selected()
+	require.True(t, ok)
+	assert.Equal(t, "Run ping old.example.com", action.Title)
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	diagnosticView, ok := model.activeView.(*DiagnosticViewModel)
+	require.True(t, ok)
+	assert.Equal(t, StateDiagnostic, model.state)
+	assert.Equal(t, "old.example.com", diagnosticView.FormValues()["host"])
+	assert.Equal(t, "2", diagnosticView.FormValues()["count"], "other values are kept")
+}
@@ -0,0 +1,324 @@
+// Package tui contains the plugin manager screen
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
+)
+
+// pluginCheckTimeout bounds a health check of all loaded plugins
+const pluginCheckTimeout = 10 * time.Second
+
+// PluginCheckMsg carries the results of a plugin health check
+type PluginCheckMsg struct {
+	Statuses []domain.PluginStatus
+}
+
+// PluginToggledMsg reports the outcome of enabling or disabling a plugin
+type PluginToggledMsg struct {
+	Name    string
+	Enabled bool
+	Error   error
+}
+
+// PluginsViewModel lists the discovered plugins with their version, status
+// and health, shows why a plugin failed to load, and enables or disables
+// plugins in the configuration
+type PluginsViewModel struct {
+	reporter domain.PluginReporter
+	enable   func(name string, enabled bool) error
+	table    *TableModel
+	statuses []domain.PluginStatus
+	changed  map[string]bool
+	checking bool
+	status   string
+	width    int
+	height   int
+	theme    domain.Theme
+	refresh  key.Binding
+	toggle   key.Binding
+}
+
+// NewPluginsViewModel creates a plugin manager backed by reporter. enable
+// persists the choice to enable or disable a plugin; without it the list
+// is read-only.
+func NewPluginsViewModel(reporter domain.PluginReporter, enable func(name string, enabled bool) error) *PluginsViewModel {
+	m := &PluginsViewModel{
+		reporter: reporter,
+		enable:   enable,
+		table:    NewTableModel([]string{"Plugin", "Kind", "Version", "Status", "Health", "Tool"}),
+		changed:  make(map[string]bool),
+		refresh: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", i18n.T("key.check_plugins")),
+		),
+		toggle: key.NewBinding(
+			key.WithKeys(" ", "e"),
+			key.WithHelp("space", i18n.T("key.enable_disable")),
+		),
+	}
+	if reporter != nil {
+		m.setStatuses(reporter.PluginStatus())
+	}
+	return m
+}
+
+// Init implements tea.Model and starts a health check
+func (m *PluginsViewModel) Init() tea.Cmd {
+	return m.check()
+}
+
+// Update implements tea.Model
+func (m *PluginsViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		if mouse, ok := mouseOver(msg, m.View(), m.table.View()); ok {
+			m.table.Update(mouse)
+		}
+		return m, nil
+	case tea.KeyMsg:
+		if m.table.CapturesInput() {
+			break
+		}
+		switch {
+		case key.Matches(msg, m.refresh) && !m.checking:
+			m.status = ""
+			return m, m.check()
+		case key.Matches(msg, m.toggle):
+			return m, m.toggleSelected()
+		}
+	case PluginCheckMsg:
+		m.checking = false
+		m.setStatuses(msg.Statuses)
+		return m, nil
+	case PluginToggledMsg:
+		if msg.Error != nil {
+			m.status = fmt.Sprintf("Failed to update %s: %v", msg.Name, msg.Error)
+			return m, nil
+		}
+		m.changed[msg.Name] = msg.Enabled
+		state := "disabled"
+		if msg.Enabled {
+			state = "enabled"
+		}
+		m.status = fmt.Sprintf("%s %s; restart to apply", msg.Name, state)
+		m.setStatuses(m.statuses)
+		return m, nil
+	}
+
+	_, cmd := m.table.Update(msg)
+	return m, cmd
+}
+
+// View implements tea.Model
+func (m *PluginsViewModel) View() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).MarginBottom(1)
+	mutedStyle := lipgloss.NewStyle().Foreground(colors.Muted).Italic(true)
+	errorStyle := lipgloss.NewStyle().Foreground(colors.Error)
+	if m.theme != nil {
+		titleStyle = titleStyle.Foreground(colors.Adaptive(m.theme.GetColor("primary")))
+		mutedStyle = mutedStyle.Foreground(colors.Adaptive(m.theme.GetColor("muted")))
+		errorStyle = errorStyle.Foreground(colors.Adaptive(m.theme.GetColor("error")))
+	}
+
+	if m.reporter == nil {
+		return titleStyle.Render("Plugins") + "\n\n" + mutedStyle.Render("Plugins are not available")
+	}
+	if len(m.statuses) == 0 {
+		return titleStyle.Render("Plugins") + "\n\n" + mutedStyle.Render("No plugins found in plugins.plugin_paths and no plugins.commands configured")
+	}
+
+	help := screenHelp(m.width).ShortHelpView(m.ShortHelp())
+	if m.checking {
+		help = mutedStyle.Render("Checking plugins...")
+	}
+	if m.status != "" {
+		help = mutedStyle.Render(m.status+" • ") + help
+	}
+
+	details := []string{mutedStyle.Render("Select a plugin to see its details")}
+	if status, ok := m.Selected(); ok {
+		details = []string{mutedStyle.Render(status.Path)}
+		if status.Error != "" {
+			details = append(details, errorStyle.Render("Load error: "+status.Error))
+		}
+		if status.HealthError != "" {
+			details = append(details, errorStyle.Render("Health check: "+status.HealthError))
+		}
+	}
+
+	sections := []string{
+		titleStyle.Render("Plugins"),
+		mutedStyle.Render("Plugins load at startup; enabling or disabling one is saved to the configuration and applies after a restart"),
+		"",
+		m.table.View(),
+		"",
+	}
+	sections = append(sections, details...)
+	sections = append(sections, "", help)
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// ShortHelp implements help.KeyMap; plugins can only be toggled when their
+// state can be saved
+func (m *PluginsViewModel) ShortHelp() []key.Binding {
+	if m.enable == nil {
+		return []key.Binding{m.refresh}
+	}
+	return []key.Binding{m.refresh, m.toggle}
+}
+
+// FullHelp implements help.KeyMap
+func (m *PluginsViewModel) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+// SetSize implements domain.TUIComponent
+func (m *PluginsViewModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.table.SetSize(width, height-10)
+}
+
+// SetTheme implements domain.TUIComponent
+func (m *PluginsViewModel) SetTheme(theme domain.Theme) {
+	m.theme = theme
+	m.table.SetTheme(theme)
+}
+
+// SetKeyMap implements keyMapSetter
+func (m *PluginsViewModel) SetKeyMap(keyMap KeyMap) {
+	m.table.SetKeyMap(keyMap)
+}
+
+// CapturesInput reports whether the vi search prompt of the table is open
+func (m *PluginsViewModel) CapturesInput() bool {
+	return m.table.CapturesInput()
+}
+
+// JumpTo selects the row on line, counting from 1
+func (m *PluginsViewModel) JumpTo(line int) {
+	m.table.JumpTo(line)
+}
+
+// Focus implements domain.TUIComponent
+func (m *PluginsViewModel) Focus() {
+	m.table.Focus()
+}
+
+// Blur implements domain.TUIComponent
+func (m *PluginsViewModel) Blur() {
+	m.table.Blur()
+}
+
+// Statuses returns the most recently reported plugin statuses
+func (m *PluginsViewModel) Statuses() []domain.PluginStatus {
+	return m.statuses
+}
+
+// Selected returns the status of the plugin under the cursor
+func (m *PluginsViewModel) Selected() (domain.PluginStatus, bool) {
+	if m.table.selected < 0 || m.table.selected >= len(m.statuses) {
+		return domain.PluginStatus{}, false
+	}
+	return m.statuses[m.table.selected], true
+}
+
+// check asks the loaded plugins whether they respond, in the background
+func (m *PluginsViewModel) check() tea.Cmd {
+	if m.reporter == nil {
+		return nil
+	}
+	m.checking = true
+	reporter := m.reporter
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), pluginCheckTimeout)
+		defer cancel()
+		return PluginCheckMsg{Statuses: reporter.CheckPlugins(ctx)}
+	}
+}
+
+// toggleSelected enables the selected plugin if it is disabled and
+// disables it otherwise
+func (m *PluginsViewModel) toggleSelected() tea.Cmd {
+	status, ok := m.Selected()
+	if !ok || m.enable == nil {
+		return nil
+	}
+	enable := m.enable
+	enabled := !m.enabled(status)
+	return func() tea.Msg {
+		return PluginToggledMsg{Name: status.Name, Enabled: enabled, Error: enable(status.Name, enabled)}
+	}
+}
+
+// enabled reports whether status is enabled, counting changes made on
+// this screen
+func (m *PluginsViewModel) enabled(status domain.PluginStatus) bool {
+	if enabled, ok := m.changed[status.Name]; ok {
+		return enabled
+	}
+	return status.Enabled
+}
+
+// setStatuses updates the table from plugin statuses
+func (m *PluginsViewModel) setStatuses(statuses []domain.PluginStatus) {
+	m.statuses = statuses
+
+	rows := make([][]string, 0, len(statuses))
+	for _, status := range statuses {
+		version := status.Version
+		if version == "" {
+			version = "-"
+		}
+		rows = append(rows, []string{
+			status.Name,
+			status.Kind,
+			version,
+			m.loadState(status),
+			healthState(status),
+			status.Tool,
+		})
+	}
+	m.table.SetData(rows)
+}
+
+// loadState describes whether a plugin is loaded and whether that changes
+// on the next start
+func (m *PluginsViewModel) loadState(status domain.PluginStatus) string {
+	enabled := m.enabled(status)
+	switch {
+	case status.Loaded && !enabled:
+		return "loaded, disabled on restart"
+	case status.Loaded:
+		return "loaded"
+	case enabled && !status.Enabled:
+		return "enabled on restart"
+	case !enabled:
+		return "disabled"
+	default:
+		return "failed"
+	}
+}
+
+// healthState describes the outcome of the last health check of a plugin
+func healthState(status domain.PluginStatus) string {
+	switch {
+	case !status.Loaded:
+		return "-"
+	case !status.Checked:
+		return "unchecked"
+	case status.Healthy:
+		return "healthy"
+	default:
+		return "failing"
+	}
+}
@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakePluginReporter returns fixed plugin statuses
+type fakePluginReporter struct {
+	statuses []domain.PluginStatus
+	checks   int
+}
+
+func (f *fakePluginReporter) PluginStatus() []domain.PluginStatus {
+	return f.statuses
+}
+
+func (f *fakePluginReporter) CheckPlugins(ctx context.Context) []domain.PluginStatus {
+	f.checks++
+	for i := range f.statuses {
+		if f.statuses[i].Loaded {
+			f.statuses[i].Checked = true
+			f.statuses[i].Healthy = i == 0
+		}
+	}
+	return f.statuses
+}
+
+func newFakePluginReporter() *fakePluginReporter {
+	return &fakePluginReporter{statuses: []domain.PluginStatus{
+		{Name: "http", Kind: domain.PluginKindExecutable, Path: "/plugins/nettracex-http", Tool: "http", Version: "1.2.0", Enabled: true, Loaded: true},
+		{Name: "dig", Kind: domain.PluginKindCommand, Path: "dig", Tool: "dig", Enabled: true, Loaded: true},
+		{Name: "broken", Kind: domain.PluginKindWASM, Path: "/plugins/nettracex-broken.wasm", Enabled: true, Error: "does not export describe"},
+		{Name: "nmap", Kind: domain.PluginKindCommand, Path: "nmap"},
+	}}
+}
+
+func TestPluginsViewModel_Check(t *testing.T) {
+	reporter := newFakePluginReporter()
+	model := NewPluginsViewModel(reporter, nil)
+
+	assert.Len(t, model.Statuses(), 4)
+	view := model.View()
+	assert.Contains(t, view, "1.2.0")
+	assert.Contains(t, view, "unchecked")
+	assert.Contains(t, view, "failed")
+	assert.Contains(t, view, "disabled")
+
+	cmd := model.Init()
+	assert.NotNil(t, cmd)
+	assert.Contains(t, model.View(), "Checking plugins")
+
+	updated, _ := model.Update(cmd())
+	view = updated.View()
+	assert.Equal(t, 1, reporter.checks)
+	assert.Contains(t, view, "healthy")
+	assert.Contains(t, view, "failing")
+}
+
+func TestPluginsViewModel_LoadError(t *testing.T) {
+	model := NewPluginsViewModel(newFakePluginReporter(), nil)
+
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	model.Update(down)
+	model.Update(down)
+
+	status, ok := model.Selected()
+	assert.True(t, ok)
+	assert.Equal(t, "broken", status.Name)
+	assert.Contains(t, model.View(), "Load error: does not export describe")
+}
+
+func TestPluginsViewModel_Toggle(t *testing.T) {
+	var calls []string
+	enable := func(name string, enabled bool) error {
+		if name == "nmap" && enabled {
+			calls = append(calls, "enable "+name)
+			return nil
+		}
+		calls = append(calls, "disable "+name)
+		return errors.New("config file is read-only")
+	}
+	model := NewPluginsViewModel(newFakePluginReporter(), enable)
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+
+	_, cmd := model.Update(space)
+	model.Update(cmd())
+	assert.Equal(t, []string{"disable http"}, calls)
+	assert.Contains(t, model.View(), "Failed to update http: config file is read-only")
+
+	for i := 0; i < 3; i++ {
+		model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	_, cmd = model.Update(space)
+	model.Update(cmd())
+	assert.Equal(t, []string{"disable http", "enable nmap"}, calls)
+	assert.Contains(t, model.View(), "nmap enabled; restart to apply")
+	assert.Equal(t, "enabled on restart", model.table.rows[3][3])
+}
+
+func TestPluginsViewModel_NoReporter(t *testing.T) {
+	model := NewPluginsViewModel(nil, nil)
+
+	assert.Nil(t, model.Init())
+	assert.Contains(t, model.View(), "not available")
+}
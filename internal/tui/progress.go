@@ -8,6 +8,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
 	"github.com/nettracex/nettracex-tui/internal/domain"
 )
 
@@ -21,6 +22,8 @@ type AnimatedProgress struct {
 	frame     int
 	isActive  bool
 	style     lipgloss.Style
+	// reducedMotion shows the message without spinning
+	reducedMotion bool
 }
 
 // ProgressTickMsg is sent to update the progress animation
@@ -33,7 +36,7 @@ func NewAnimatedProgress() *AnimatedProgress {
 		frame:   0,
 		isActive: false,
 		style: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("214")).
+			Foreground(colors.Warning).
 			Bold(true),
 	}
 }
@@ -43,9 +46,17 @@ func (p *AnimatedProgress) Start(message string) tea.Cmd {
 	p.message = message
 	p.isActive = true
 	p.frame = 0
+	if p.reducedMotion {
+		return nil
+	}
 	return p.tick()
 }
 
+// SetReducedMotion stops the spinner, leaving the message
+func (p *AnimatedProgress) SetReducedMotion(reduced bool) {
+	p.reducedMotion = reduced
+}
+
 // Stop stops the progress animation
 func (p *AnimatedProgress) Stop() {
 	p.isActive = false
@@ -69,6 +80,9 @@ func (p *AnimatedProgress) View() string {
 		return ""
 	}
 
+	if p.reducedMotion {
+		return p.style.Render(p.message)
+	}
 	spinner := p.spinner[p.frame]
 	content := fmt.Sprintf("%s %s", spinner, p.message)
 	
@@ -120,7 +134,7 @@ func NewProgressBar() *ProgressBar {
 	return &ProgressBar{
 		showPercentage: true,
 		style: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("39")),
+			Foreground(colors.Info),
 	}
 }
 
@@ -163,8 +177,8 @@ func (p *ProgressBar) View() string {
 
 	// Style the progress bar
 	progressStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("39")).
-		Background(lipgloss.Color("240"))
+		Foreground(colors.Info).
+		Background(colors.Border)
 
 	styledBar := progressStyle.Render(progressBar)
 
@@ -0,0 +1,237 @@
+// Package tui contains running other tools on the items of a result
+package tui
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// actionMenuHeight is the number of actions the menu lists at a time
+const actionMenuHeight = 10
+
+// ChainToolMsg asks the main model to open tool with its form filled in
+// with values and run it
+type ChainToolMsg struct {
+	Tool   string
+	Values map[string]string
+}
+
+// resultAction is a tool that can be run on an item of a result
+type resultAction struct {
+	Title  string
+	Tool   string
+	Values map[string]string
+}
+
+// actionMenu lists the tools that can be run on the items of the result
+type actionMenu struct {
+	active   bool
+	actions  []resultAction
+	selected int
+}
+
+// addressActions are the tools offered for an IP address
+var addressActions = []struct {
+	verb, tool, key string
+}{
+	{"Ping", "ping", "host"},
+	{"Traceroute to", "traceroute", "host"},
+	{"WHOIS", "whois", "query"},
+}
+
+// hostnameActions are the tools offered for a hostname
+var hostnameActions = []struct {
+	verb, tool, key string
+}{
+	{"Look up DNS of", "dns", "domain"},
+	{"Ping", "ping", "host"},
+	{"Traceroute to", "traceroute", "host"},
+	{"Check the certificate of", "ssl", "host"},
+}
+
+// resultTargets returns the addresses and hostnames in the data of a
+// result, in the order the result lists them
+func resultTargets(data interface{}) []string {
+	var targets []string
+	add := func(target string) {
+		if target = strings.TrimSuffix(strings.TrimSpace(target), "."); target != "" && target != "-" {
+			targets = append(targets, target)
+		}
+	}
+
+	switch data := data.(type) {
+	case domain.DNSResult:
+		for _, record := range data.Records {
+			switch record.Type {
+			case domain.DNSRecordTypeA, domain.DNSRecordTypeAAAA, domain.DNSRecordTypeMX,
+				domain.DNSRecordTypeNS, domain.DNSRecordTypeCNAME, domain.DNSRecordTypePTR:
+				add(record.Value)
+			}
+		}
+	case []domain.TraceHop:
+		for _, hop := range data {
+			if hop.Host.IPAddress != nil {
+				add(hop.Host.IPAddress.String())
+			}
+			if hop.Host.Hostname != "" && net.ParseIP(hop.Host.Hostname) == nil {
+				add(hop.Host.Hostname)
+			}
+		}
+	case domain.SSLResult:
+		add(data.Host)
+		for _, san := range data.SANs {
+			// A wildcard names no host to run a tool on
+			if !strings.HasPrefix(san, "*.") {
+				add(san)
+			}
+		}
+	case domain.WHOISResult:
+		for _, nameServer := range data.NameServers {
+			add(strings.ToLower(nameServer))
+		}
+	case domain.SweepResult:
+		for _, host := range data.Hosts {
+			add(host.IP.String())
+			add(host.Hostname)
+		}
+	case domain.ZoneTransferResult:
+		for _, server := range data.Servers {
+			add(server.Nameserver)
+			add(server.Address)
+		}
+	case domain.MultiPingResult:
+		for _, target := range data.Targets {
+			add(target.Host)
+		}
+	case []domain.PingResult:
+		if len(data) > 0 && data[0].Host.IPAddress != nil {
+			add(data[0].Host.IPAddress.String())
+		}
+	}
+	return targets
+}
+
+// actionsFor returns the tools offered for targets, each target once
+func actionsFor(targets []string) []resultAction {
+	var actions []resultAction
+	seen := make(map[string]bool)
+	for _, target := range targets {
+		if seen[target] {
+			continue
+		}
+		seen[target] = true
+
+		offered := hostnameActions
+		if net.ParseIP(target) != nil {
+			offered = addressActions
+		}
+		for _, action := range offered {
+			actions = append(actions, resultAction{
+				Title:  fmt.Sprintf("%s %s", action.verb, target),
+				Tool:   action.tool,
+				Values: map[string]string{action.key: target},
+			})
+		}
+	}
+	return actions
+}
+
+// openActionMenu offers the tools for the items of the result: in the table
+// those of the selected row, otherwise those of the whole result
+func (m *ResultViewModel) openActionMenu() tea.Cmd {
+	if m.result == nil {
+		return nil
+	}
+
+	targets := resultTargets(m.result.Data())
+	if row, ok := m.tableModel.SelectedRow(); ok && m.mode == ResultViewModeTable {
+		cells := make(map[string]bool, len(row))
+		for _, cell := range row {
+			cells[strings.TrimSuffix(cell, ".")] = true
+		}
+		var selected []string
+		for _, target := range targets {
+			if cells[target] {
+				selected = append(selected, target)
+			}
+		}
+		targets = selected
+	}
+
+	actions := actionsFor(targets)
+	if len(actions) == 0 {
+		return m.toast.Show("Nothing to run another tool on here")
+	}
+	m.actions = actionMenu{active: true, actions: actions}
+	return nil
+}
+
+// updateActionMenu handles keys while the action menu is open
+func (m *ResultViewModel) updateActionMenu(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "a":
+		m.actions.active = false
+	case "up", "k":
+		if m.actions.selected > 0 {
+			m.actions.selected--
+		}
+	case "down", "j":
+		if m.actions.selected < len(m.actions.actions)-1 {
+			m.actions.selected++
+		}
+	case "enter":
+		action := m.actions.actions[m.actions.selected]
+		m.actions.active = false
+		return func() tea.Msg {
+			return ChainToolMsg{Tool: action.Tool, Values: action.Values}
+		}
+	}
+	return nil
+}
+
+// renderActionMenu renders the actions around the selected one
+func (m *ResultViewModel) renderActionMenu() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colors.Info)
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colors.Highlight).
+		Background(colors.Primary).
+		Padding(0, 1)
+	optionStyle := lipgloss.NewStyle().
+		Foreground(colors.Text).
+		Padding(0, 1)
+	helpStyle := lipgloss.NewStyle().
+		Foreground(colors.Subtle).
+		Italic(true)
+
+	first := max(0, min(m.actions.selected-actionMenuHeight/2, len(m.actions.actions)-actionMenuHeight))
+	last := min(first+actionMenuHeight, len(m.actions.actions))
+
+	var content strings.Builder
+	content.WriteString(titleStyle.Render("Run a Tool"))
+	content.WriteString("\n\n")
+	for i := first; i < last; i++ {
+		if i == m.actions.selected {
+			content.WriteString(selectedStyle.Render(m.actions.actions[i].Title))
+		} else {
+			content.WriteString(optionStyle.Render(m.actions.actions[i].Title))
+		}
+		content.WriteString("\n")
+	}
+	content.WriteString("\n")
+	content.WriteString(helpStyle.Render(fmt.Sprintf("%d of %d • ↑/↓: choose • enter: run • esc: cancel", m.actions.selected+1, len(m.actions.actions))))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colors.Primary).
+		Padding(0, 1).
+		Render(content.String())
+}
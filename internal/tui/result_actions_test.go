@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"net"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chainDNSResult is a lookup with an address, a mail server and a TXT record
+func chainDNSResult() domain.Result {
+	return domain.NewResult(domain.DNSResult{Query: "example.com", Records: []domain.DNSRecord{
+		{Name: "example.com", Type: domain.DNSRecordTypeA, Value: "93.184.216.34", TTL: 300},
+		{Name: "example.com", Type: domain.DNSRecordTypeMX, Value: "mail.example.com.", TTL: 300, Priority: 10},
+		{Name: "example.com", Type: domain.DNSRecordTypeTXT, Value: "v=spf1 -all", TTL: 300},
+	}})
+}
+
+func TestResultTargets(t *testing.T) {
+	assert.Equal(t, []string{"93.184.216.34", "mail.example.com"}, resultTargets(chainDNSResult().Data()))
+
+	hops := []domain.TraceHop{
+		{Number: 1, Host: domain.NetworkHost{Hostname: "router.lan", IPAddress: net.ParseIP("10.0.0.1")}},
+		{Number: 2, Timeout: true},
+	}
+	assert.Equal(t, []string{"10.0.0.1", "router.lan"}, resultTargets(hops))
+
+	ssl := domain.SSLResult{Host: "example.com", SANs: []string{"example.com", "*.example.com", "www.example.com"}}
+	assert.Equal(t, []string{"example.com", "example.com", "www.example.com"}, resultTargets(ssl),
+		"wildcards are skipped")
+
+	actions := actionsFor([]string{"10.0.0.1", "www.example.com", "10.0.0.1"})
+	require.Len(t, actions, len(addressActions)+len(hostnameActions), "each target once")
+	assert.Equal(t, "Ping 10.0.0.1", actions[0].Title)
+	assert.Equal(t, "whois", actions[2].Tool)
+	assert.Equal(t, map[string]string{"query": "10.0.0.1"}, actions[2].Values)
+	assert.Equal(t, "dns", actions[3].Tool)
+	assert.Equal(t, map[string]string{"domain": "www.example.com"}, actions[3].Values)
+}
+
+func TestResultViewModel_ActionMenu(t *testing.T) {
+	model := NewResultViewModel()
+	model.SetSize(100, 40)
+	model.SetResult(chainDNSResult())
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	require.True(t, model.actions.active)
+	assert.True(t, model.CapturesInput())
+	view := model.View()
+	assert.Contains(t, view, "Ping 93.184.216.34")
+	assert.Contains(t, view, "Look up DNS of mail.example.com")
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, model.actions.active)
+
+	// The table offers the tools for the selected record only
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	require.Equal(t, ResultViewModeTable, model.mode)
+	model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	require.True(t, model.actions.active)
+	for _, action := range model.actions.actions {
+		assert.Contains(t, action.Title, "mail.example.com")
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	assert.Equal(t, ChainToolMsg{Tool: "ping", Values: map[string]string{"host": "mail.example.com"}}, cmd())
+	assert.False(t, model.actions.active)
+
+	// The TXT record names nothing to run a tool on
+	model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	assert.False(t, model.actions.active)
+}
+
+func TestMainModel_ChainTool(t *testing.T) {
+	model := newTabsModel(t)
+	model.forms["ping"] = map[string]string{"host": "example.com", "count": "2"}
+
+	_, cmd := model.Update(ChainToolMsg{Tool: "ping", Values: map[string]string{"host": "93.184.216.34"}})
+	require.NotNil(t, cmd)
+	diagnosticView, ok := model.activeView.(*DiagnosticViewModel)
+	require.True(t, ok)
+	assert.Equal(t, StateDiagnostic, model.state)
+	assert.Equal(t, "93.184.216.34", diagnosticView.FormValues()["host"])
+	assert.Equal(t, "2", diagnosticView.FormValues()["count"], "other values are kept")
+
+	model.Update(ChainToolMsg{Tool: "portscan", Values: map[string]string{"host": "93.184.216.34"}})
+	assert.Contains(t, model.configStatus, "portscan is not available")
+}
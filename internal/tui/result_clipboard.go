@@ -0,0 +1,44 @@
+// Package tui contains copying results to the clipboard
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/clipboard"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// copyCurrent copies the part of the result the current view mode shows
+func (m *ResultViewModel) copyCurrent() tea.Cmd {
+	if m.result == nil {
+		return nil
+	}
+
+	switch m.mode {
+	case ResultViewModeTable:
+		row, ok := m.tableModel.SelectedRow()
+		if !ok {
+			return nil
+		}
+		return clipboard.Copy(strings.Join(row, "\t"), "selected row")
+	case ResultViewModeRaw:
+		return m.copyExport(domain.ExportFormatJSON, "raw JSON")
+	default:
+		return m.copyExport(domain.ExportFormatText, "result")
+	}
+}
+
+// copyExport copies the result exported in format to the clipboard
+func (m *ResultViewModel) copyExport(format domain.ExportFormat, description string) tea.Cmd {
+	if m.result == nil {
+		return nil
+	}
+	data, err := m.result.Export(format)
+	if err != nil {
+		return func() tea.Msg {
+			return clipboard.CopiedMsg{Description: description, Error: err}
+		}
+	}
+	return clipboard.Copy(string(data), description)
+}
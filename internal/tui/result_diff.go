@@ -0,0 +1,111 @@
+// Package tui contains the side-by-side comparison of two results
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/diff"
+)
+
+// renderDiffResult renders the selected pair of historical results side by side
+func (m *ResultViewModel) renderDiffResult() string {
+	entries := m.history.Entries(m.historyKey)
+	if len(entries) < 2 {
+		return "Run the tool again to compare results"
+	}
+	last := len(entries) - 1
+	m.diffBase = clampIndex(m.diffBase, last)
+	m.diffTarget = clampIndex(m.diffTarget, last)
+
+	base, target := entries[m.diffBase], entries[m.diffTarget]
+
+	var content strings.Builder
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colors.Info)
+	content.WriteString(titleStyle.Render(fmt.Sprintf("Comparing #%d (%s) → #%d (%s) of %d results",
+		m.diffBase+1, base.Timestamp.Format("15:04:05"),
+		m.diffTarget+1, target.Timestamp.Format("15:04:05"), len(entries))))
+	content.WriteString("\n\n")
+
+	rows, err := diff.Results(base.Result.Data(), target.Result.Data())
+	if err != nil {
+		content.WriteString(fmt.Sprintf("Cannot compare these results: %v\n", err))
+		return content.String()
+	}
+
+	changed, added, removed := diff.Summary(rows)
+	if changed+added+removed == 0 {
+		content.WriteString("No differences\n\n")
+	} else {
+		content.WriteString(fmt.Sprintf("%d changed • %d added • %d removed\n\n", changed, added, removed))
+	}
+
+	labelWidth, deltaWidth := 12, 10
+	for _, row := range rows {
+		if len(row.Label) > labelWidth {
+			labelWidth = len(row.Label)
+		}
+	}
+	valueWidth := (m.width - labelWidth - deltaWidth - 10) / 2
+	if valueWidth < 20 {
+		valueWidth = 20
+	}
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colors.Info)
+	content.WriteString(headerStyle.Render(fmt.Sprintf("  %-*s %-*s %-*s %*s",
+		labelWidth, "", valueWidth, fmt.Sprintf("#%d", m.diffBase+1), valueWidth, fmt.Sprintf("#%d", m.diffTarget+1), deltaWidth, "Δ")))
+	content.WriteString("\n")
+
+	for _, row := range rows {
+		marker, style := diffRowStyle(row.Change)
+		line := fmt.Sprintf("%s %-*s %-*s %-*s %*s", marker,
+			labelWidth, row.Label,
+			valueWidth, truncateDiffValue(valueOrDash(row.Before), valueWidth),
+			valueWidth, truncateDiffValue(valueOrDash(row.After), valueWidth),
+			deltaWidth, row.Delta)
+		content.WriteString(style.Render(line))
+		content.WriteString("\n")
+	}
+
+	return content.String()
+}
+
+// diffRowStyle returns the marker and the style of a row with change
+func diffRowStyle(change diff.Change) (string, lipgloss.Style) {
+	marker, color := " ", "252"
+	switch change {
+	case diff.Changed:
+		marker, color = "~", "214"
+	case diff.Added:
+		marker, color = "+", "42"
+	case diff.Removed:
+		marker, color = "-", "196"
+	}
+	return marker, lipgloss.NewStyle().Foreground(colors.Adaptive(color))
+}
+
+// truncateDiffValue shortens value to fit a comparison column
+func truncateDiffValue(value string, width int) string {
+	runes := []rune(value)
+	if len(runes) <= width {
+		return value
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// clampIndex limits index to the range [0, last]
+func clampIndex(index, last int) int {
+	if index < 0 {
+		return 0
+	}
+	if index > last {
+		return last
+	}
+	return index
+}
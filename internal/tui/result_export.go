@@ -0,0 +1,237 @@
+// Package tui contains report export from the result view
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
+)
+
+// ResultExportedMsg is sent when a report of the displayed result has been written to disk
+type ResultExportedMsg struct {
+	Tool  string
+	Path  string
+	Error error
+}
+
+// inputCapturer is implemented by views that temporarily need every key,
+// such as while a text field is being edited, so global shortcuts are suspended
+type inputCapturer interface {
+	CapturesInput() bool
+}
+
+// saveFormat is an export format offered by the save dialog
+type saveFormat struct {
+	Name      string
+	Format    domain.ExportFormat
+	Extension string
+}
+
+// saveFormats lists the formats offered by the save dialog in display order
+var saveFormats = []saveFormat{
+	{Name: "CSV", Format: domain.ExportFormatCSV, Extension: "csv"},
+	{Name: "JSON", Format: domain.ExportFormatJSON, Extension: "json"},
+	{Name: "Text", Format: domain.ExportFormatText, Extension: "txt"},
+	{Name: "HTML", Format: domain.ExportFormatHTML, Extension: "html"},
+	{Name: "Markdown", Format: domain.ExportFormatMarkdown, Extension: "md"},
+	{Name: "PDF", Format: domain.ExportFormatPDF, Extension: "pdf"},
+}
+
+// saveDialog lets the user choose a format and file name for the displayed result
+type saveDialog struct {
+	active bool
+	format int
+	path   textinput.Model
+}
+
+// SetExportDirectory sets the directory reports are written to
+func (m *ResultViewModel) SetExportDirectory(dir string) {
+	m.exportDir = dir
+}
+
+// SetExportFormat sets the format preselected in the save dialog
+func (m *ResultViewModel) SetExportFormat(format domain.ExportFormat) {
+	m.exportFormat = format
+}
+
+// CapturesInput reports whether the save dialog, the action menu or a search
+// prompt is open and needs every key
+func (m *ResultViewModel) CapturesInput() bool {
+	return m.save.active || m.actions.active || m.searching()
+}
+
+// searching reports whether the search prompt of the view, or the vi search
+// prompt of its pager or a search prompt of its table is open
+func (m *ResultViewModel) searching() bool {
+	return m.search.Typing() || m.scrollPager != nil && m.scrollPager.CapturesInput() ||
+		m.tableModel != nil && m.tableModel.CapturesInput()
+}
+
+// openSaveDialog opens the save dialog with the default format and file name
+func (m *ResultViewModel) openSaveDialog() {
+	if m.result == nil {
+		return
+	}
+
+	m.save.format = 0
+	for i, format := range saveFormats {
+		if format.Format == m.exportFormat {
+			m.save.format = i
+		}
+	}
+
+	input := textinput.New()
+	input.Prompt = ""
+	input.CharLimit = 512
+	input.Width = 60
+	input.SetValue(filepath.Join(m.exportDir, reportFileName(m.result, m.historyKey, saveFormats[m.save.format].Extension)))
+	input.Focus()
+
+	m.save.path = input
+	m.save.active = true
+}
+
+// saveKeys are the keys of the save dialog besides those of the key map
+var saveKeys = struct {
+	PrevFormat key.Binding
+}{
+	PrevFormat: hint(i18n.T("key.previous_format"), "shift+tab"),
+}
+
+// saveDialogKeys returns the keys of the save dialog, described for it
+func (m *ResultViewModel) saveDialogKeys() []key.Binding {
+	return []key.Binding{
+		relabel(m.keyMap.Tab, i18n.T("key.format")),
+		relabel(m.keyMap.Enter, i18n.T("key.save")),
+		relabel(m.keyMap.Back, i18n.T("key.cancel")),
+	}
+}
+
+// updateSaveDialog handles keys while the save dialog is open
+func (m *ResultViewModel) updateSaveDialog(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keyMap.Back):
+		m.save.active = false
+		return nil
+	case key.Matches(msg, m.keyMap.Enter):
+		path := strings.TrimSpace(m.save.path.Value())
+		if path == "" {
+			return nil
+		}
+		m.save.active = false
+		return saveReport(m.result, saveFormats[m.save.format].Format, path)
+	case key.Matches(msg, m.keyMap.Tab, saveKeys.PrevFormat):
+		previous := saveFormats[m.save.format].Extension
+		if key.Matches(msg, m.keyMap.Tab) {
+			m.save.format = (m.save.format + 1) % len(saveFormats)
+		} else {
+			m.save.format = (m.save.format + len(saveFormats) - 1) % len(saveFormats)
+		}
+		// Keep the extension in step with the format unless the user renamed it
+		path := m.save.path.Value()
+		if strings.HasSuffix(path, "."+previous) {
+			m.save.path.SetValue(strings.TrimSuffix(path, previous) + saveFormats[m.save.format].Extension)
+			m.save.path.CursorEnd()
+		}
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.save.path, cmd = m.save.path.Update(msg)
+	return cmd
+}
+
+// renderSaveDialog renders the format choice and file name of the save dialog
+func (m *ResultViewModel) renderSaveDialog() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colors.Info)
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colors.Highlight).
+		Background(colors.Primary).
+		Padding(0, 1)
+	optionStyle := lipgloss.NewStyle().
+		Foreground(colors.Text).
+		Padding(0, 1)
+	helpStyle := lipgloss.NewStyle().
+		Italic(true)
+
+	options := make([]string, len(saveFormats))
+	for i, format := range saveFormats {
+		if i == m.save.format {
+			options[i] = selectedStyle.Render(format.Name)
+		} else {
+			options[i] = optionStyle.Render(format.Name)
+		}
+	}
+
+	var content strings.Builder
+	content.WriteString(titleStyle.Render("Save Result"))
+	content.WriteString("\n\n")
+	content.WriteString("Format: " + lipgloss.JoinHorizontal(lipgloss.Top, options...))
+	content.WriteString("\n")
+	content.WriteString("File:   " + m.save.path.View())
+	content.WriteString("\n\n")
+	content.WriteString(helpStyle.Render(screenHelp(m.width).ShortHelpView(m.saveDialogKeys())))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colors.Primary).
+		Padding(0, 1).
+		Render(content.String())
+}
+
+// exportReport writes the displayed result in format to the export directory
+func (m *ResultViewModel) exportReport(format domain.ExportFormat, extension string) tea.Cmd {
+	if m.result == nil {
+		return nil
+	}
+	return saveReport(m.result, format, filepath.Join(m.exportDir, reportFileName(m.result, m.historyKey, extension)))
+}
+
+// saveReport writes result in format to path, creating its directory as needed
+func saveReport(result domain.Result, format domain.ExportFormat, path string) tea.Cmd {
+	return func() tea.Msg {
+		data, err := result.Export(format)
+		if err != nil {
+			return ResultExportedMsg{Error: err}
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return ResultExportedMsg{Error: err}
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return ResultExportedMsg{Error: err}
+		}
+		tool, _ := result.Metadata()["tool"].(string)
+		return ResultExportedMsg{Tool: tool, Path: path}
+	}
+}
+
+// reportFileName names a report after the tool and the time of the result
+func reportFileName(result domain.Result, fallbackTool, extension string) string {
+	metadata := result.Metadata()
+	tool, _ := metadata["tool"].(string)
+	if tool == "" {
+		tool = fallbackTool
+	}
+	if tool == "" {
+		tool = "result"
+	}
+	timestamp, ok := metadata["timestamp"].(time.Time)
+	if !ok {
+		timestamp = time.Now()
+	}
+	return fmt.Sprintf("%s-%s.%s", tool, timestamp.Format("20060102-150405"), extension)
+}
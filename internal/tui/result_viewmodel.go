@@ -2,13 +2,18 @@
 package tui
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/clipboard"
+	"github.com/nettracex/nettracex-tui/internal/colors"
 	"github.com/nettracex/nettracex-tui/internal/domain"
 )
 
@@ -19,6 +24,7 @@ const (
 	ResultViewModeFormatted ResultViewMode = iota
 	ResultViewModeTable
 	ResultViewModeRaw
+	ResultViewModeDiff
 )
 
 // ResultViewModel handles display of diagnostic results
@@ -32,6 +38,22 @@ type ResultViewModel struct {
 	keyMap     KeyMap
 	focused    bool
 	scrollPager *StandardScrollPager // Migrated to StandardScrollPager for consistency
+	history     *ResultHistory
+	historyKey  string
+	diffBase    int
+	diffTarget  int
+	exportDir    string
+	exportFormat domain.ExportFormat
+	toast        clipboard.Toast
+	save         saveDialog
+	actions      actionMenu
+	search       textSearch
+	layouts      *TableLayouts
+	// shown counts the results shown, so that the pager starts at the top
+	// of a new result or mode and keeps its position otherwise
+	shown        int
+	pagerShown   int
+	pagerMode    ResultViewMode
 }
 
 // NewResultViewModel creates a new result view model
@@ -45,6 +67,9 @@ func NewResultViewModel() *ResultViewModel {
 		keyMap:      DefaultKeyMap(),
 		focused:     true,
 		scrollPager: scrollPager,
+		history:     NewResultHistory(DefaultResultHistoryLimit),
+		exportDir:   ".",
+		exportFormat: domain.ExportFormatCSV,
 	}
 }
 
@@ -57,8 +82,17 @@ func (m *ResultViewModel) Init() tea.Cmd {
 func (m *ResultViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	// Search the lines of the pager; the table searches its own rows
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.focused && !m.save.active && !m.actions.active && m.result != nil && m.mode != ResultViewModeTable {
+		if event, handled := m.search.update(keyMsg); handled {
+			m.applySearch(event)
+			return m, nil
+		}
+	}
+
 	// Update scroll pager for non-table modes
-	if m.mode != ResultViewModeTable && m.scrollPager != nil {
+	searching := m.searching()
+	if m.mode != ResultViewModeTable && m.scrollPager != nil && !m.actions.active {
 		updatedModel, scrollCmd := m.scrollPager.Update(msg)
 		if pager, ok := updatedModel.(*StandardScrollPager); ok {
 			m.scrollPager = pager
@@ -67,38 +101,130 @@ func (m *ResultViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
+	case ResultExportedMsg:
+		if msg.Error != nil {
+			return m, tea.Batch(cmd, m.toast.Show(fmt.Sprintf("Export failed: %v", msg.Error)))
+		}
+		return m, tea.Batch(cmd, m.toast.Show(fmt.Sprintf("Report saved to %s", msg.Path)))
+
+	case clipboard.CopiedMsg:
+		return m, tea.Batch(cmd, m.toast.Show(msg.Status()))
+
+	case clipboard.ToastExpiredMsg:
+		m.toast.Update(msg)
+		return m, cmd
+
+	case tea.MouseMsg:
+		// The table is drawn below the mode indicator
+		if m.focused && m.result != nil && m.mode == ResultViewModeTable && m.tableModel != nil && !m.save.active && !m.actions.active {
+			_, cmd = m.tableModel.Update(mouseBelow(msg, m.renderModeIndicator()+"\n\n"))
+		}
+		return m, cmd
+
 	case tea.KeyMsg:
 		if !m.focused {
 			return m, nil
 		}
 
+		if m.save.active {
+			return m, m.updateSaveDialog(msg)
+		}
+
+		if m.actions.active {
+			return m, m.updateActionMenu(msg)
+		}
+
+		if searching {
+			// Keys typed into the vi search prompt
+			if m.mode == ResultViewModeTable && m.tableModel != nil {
+				_, cmd = m.tableModel.Update(msg)
+			}
+			return m, cmd
+		}
+
 		switch {
 		case key.Matches(msg, m.keyMap.Tab):
 			// Cycle through view modes
 			m.cycleViewMode()
 			return m, cmd
 
-		case key.Matches(msg, key.NewBinding(key.WithKeys("r"))):
+		case key.Matches(msg, m.keyMap.RawView):
 			// Switch to raw mode
 			m.mode = ResultViewModeRaw
 			return m, cmd
 
-		case key.Matches(msg, key.NewBinding(key.WithKeys("f"))):
+		case key.Matches(msg, m.keyMap.FormattedView):
 			// Switch to formatted mode
 			m.mode = ResultViewModeFormatted
 			return m, cmd
 
-		case key.Matches(msg, key.NewBinding(key.WithKeys("t"))):
+		case key.Matches(msg, m.keyMap.TableView):
 			// Switch to table mode
 			m.mode = ResultViewModeTable
 			return m, cmd
+
+		case key.Matches(msg, m.keyMap.DiffView):
+			// Compare the two most recent results of this tool
+			if entries := m.history.Entries(m.historyKey); len(entries) >= 2 {
+				m.mode = ResultViewModeDiff
+				m.diffBase = len(entries) - 2
+				m.diffTarget = len(entries) - 1
+			}
+			return m, cmd
+
+		case key.Matches(msg, m.keyMap.Export):
+			// Choose a format and file name for the result
+			m.openSaveDialog()
+			return m, cmd
+
+		case key.Matches(msg, resultKeys.Copy):
+			// Copy what the current mode shows: the selected row, raw JSON or text
+			return m, tea.Batch(cmd, m.copyCurrent())
+
+		case key.Matches(msg, resultKeys.Actions):
+			// Run another tool on an address or hostname of the result
+			return m, tea.Batch(cmd, m.openActionMenu())
+
+		case key.Matches(msg, resultKeys.CopyJSON):
+			// Copy the raw JSON regardless of mode
+			return m, tea.Batch(cmd, m.copyExport(domain.ExportFormatJSON, "raw JSON"))
+
+		case key.Matches(msg, resultKeys.HTMLReport):
+			// Write a standalone HTML report of the result
+			return m, m.exportReport(domain.ExportFormatHTML, "html")
+
+		case key.Matches(msg, resultKeys.MarkdownReport):
+			// Write a Markdown report of the result for tickets and wikis
+			return m, m.exportReport(domain.ExportFormatMarkdown, "md")
+
+		case m.mode == ResultViewModeDiff && key.Matches(msg, resultKeys.Older, resultKeys.Newer):
+			// Step the older result with [ ] and the newer result with { }
+			last := len(m.history.Entries(m.historyKey)) - 1
+			switch msg.String() {
+			case "[":
+				m.diffBase = clampIndex(m.diffBase-1, last)
+			case "]":
+				m.diffBase = clampIndex(m.diffBase+1, last)
+			case "{":
+				m.diffTarget = clampIndex(m.diffTarget-1, last)
+			case "}":
+				m.diffTarget = clampIndex(m.diffTarget+1, last)
+			}
+			return m, cmd
 		}
 
 		// Pass through to table model if in table mode
 		if m.mode == ResultViewModeTable && m.tableModel != nil {
+			layout := m.tableModel.Layout()
 			updatedTable, tableCmd := m.tableModel.Update(msg)
 			m.tableModel = updatedTable.(*TableModel)
 			cmd = tableCmd
+			if m.layouts != nil && !reflect.DeepEqual(layout, m.tableModel.Layout()) {
+				// Keep the columns as arranged for the next result of the tool
+				if err := m.layouts.Set(m.historyKey, m.tableModel.Layout()); err != nil {
+					cmd = tea.Batch(cmd, m.toast.Show(fmt.Sprintf("Saving the table layout failed: %v", err)))
+				}
+			}
 		}
 	}
 
@@ -122,34 +248,24 @@ func (m *ResultViewModel) View() string {
 		return content.String()
 	}
 
-	// For formatted and raw modes, use pager
-	var mainContent strings.Builder
-	
-	// Only put the main result content in the pager
-	switch m.mode {
-	case ResultViewModeFormatted:
-		mainContent.WriteString(m.renderFormattedResult())
-	case ResultViewModeRaw:
-		mainContent.WriteString(m.renderRawResult())
-	}
-
 	// Build the full view with header, scroll pager content, and footer
 	var fullView strings.Builder
 	fullView.WriteString(m.renderModeIndicator())
 	fullView.WriteString("\n\n")
 
-	// Convert content to scrollable items and set in scroll pager
+	// Only put the main result content in the pager
 	if m.scrollPager != nil {
-		// Split content into lines and create scrollable items
-		lines := strings.Split(mainContent.String(), "\n")
-		items := make([]ScrollableItem, len(lines))
-		for i, line := range lines {
-			items[i] = NewStringScrollableItem(line, fmt.Sprintf("line_%d", i))
-		}
-		m.scrollPager.SetItems(items)
+		m.refreshPager()
 		fullView.WriteString(m.scrollPager.View())
 	} else {
-		fullView.WriteString(mainContent.String())
+		fullView.WriteString(strings.Join(m.resultLines(), "\n"))
+	}
+
+	if prompt := m.search.prompt(); prompt != "" {
+		fullView.WriteString("\n" + prompt)
+	}
+	if status := m.search.status(m.resultLines()); status != "" {
+		fullView.WriteString("\n" + status)
 	}
 
 	// View mode help
@@ -159,16 +275,54 @@ func (m *ResultViewModel) View() string {
 	return fullView.String()
 }
 
-// SetResult sets the result to display
+// SetResult sets the result to display and records it in the history
 func (m *ResultViewModel) SetResult(result domain.Result) {
+	if result != nil {
+		m.history.Add(m.historyKey, result)
+	}
+	m.showResult(result)
+}
+
+// showResult displays result without recording it, for results that are
+// already in the history such as restored ones
+func (m *ResultViewModel) showResult(result domain.Result) {
+	m.result = result
+	m.shown++
+	m.toast.Clear()
+	m.save.active = false
+	m.actions.active = false
+	if m.mode == ResultViewModeDiff {
+		m.mode = ResultViewModeFormatted
+	}
+	m.updateTableModel()
+}
+
+// refreshResult records and displays a newer result of the same query,
+// keeping the mode and the scroll position, for a watched query
+func (m *ResultViewModel) refreshResult(result domain.Result) {
+	m.history.Add(m.historyKey, result)
 	m.result = result
 	m.updateTableModel()
 }
 
+// SetHistory shares a result history so that results of tool can be
+// compared across visits to the tool
+func (m *ResultViewModel) SetHistory(history *ResultHistory, tool string) {
+	m.history = history
+	m.historyKey = tool
+}
+
+// SetTableLayouts shares the table layouts so that the columns of the tool's
+// table stay sorted, hidden, sized and ordered as the user left them
+func (m *ResultViewModel) SetTableLayouts(layouts *TableLayouts) {
+	m.layouts = layouts
+	m.updateTableModel()
+}
+
 // renderNoResult renders a message when no result is available
 func (m *ResultViewModel) renderNoResult() string {
 	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(colors.Subtle).
 		Italic(true)
 
 	return style.Render("No result available")
@@ -184,12 +338,14 @@ func (m *ResultViewModel) renderModeIndicator() string {
 		modeText = "📊 Table View"
 	case ResultViewModeRaw:
 		modeText = "📄 Raw Data View"
+	case ResultViewModeDiff:
+		modeText = "🔀 Compare View"
 	}
 
 	style := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
-		Background(lipgloss.Color("236")).
+		Foreground(colors.Info).
+		Background(colors.Surface).
 		Padding(0, 1)
 
 	return style.Render(modeText)
@@ -201,22 +357,38 @@ func (m *ResultViewModel) renderFormattedResult() string {
 		return "No result to display"
 	}
 
-	// Handle different result types
-	switch data := m.result.Data().(type) {
+	return m.renderData(m.result.Data())
+}
+
+// renderData renders result data by its type
+func (m *ResultViewModel) renderData(data interface{}) string {
+	switch data := data.(type) {
 	case domain.WHOISResult:
 		return m.renderWHOISResult(data)
 	case []domain.PingResult:
 		return m.renderPingResults(data)
 	case domain.PingResult:
 		return m.renderPingResult(data)
+	case domain.MultiPingResult:
+		return m.renderMultiPingResult(data)
 	case domain.DNSResult:
 		return m.renderDNSResult(data)
 	case domain.SSLResult:
 		return m.renderSSLResult(data)
+	case domain.DualStackResult:
+		return m.renderDualStackResult(data)
+	case domain.SweepResult:
+		return m.renderSweepResult(data)
+	case domain.ZoneTransferResult:
+		return m.renderZoneTransferResult(data)
 	case []domain.TraceHop:
 		return m.renderTracerouteResults(data)
 	case domain.TraceHop:
 		return m.renderTraceHopResult(data)
+	case domain.VantageResults:
+		return m.renderVantageResults(data)
+	case domain.PluginResult:
+		return m.renderPluginResult(data)
 	default:
 		return fmt.Sprintf("Unsupported result type: %T", data)
 	}
@@ -293,11 +465,18 @@ func (m *ResultViewModel) renderPingResults(results []domain.PingResult) string
 	}
 
 	// Summary section
-	content.WriteString(m.renderSection("Ping Summary", [][]string{
+	summary := [][]string{
 		{"Target Host", results[0].Host.Hostname},
 		{"Target IP", results[0].Host.IPAddress.String()},
 		{"Total Pings", fmt.Sprintf("%d", len(results))},
-	}))
+	}
+	if hint, ok := domain.PingTTLHint(results); ok {
+		summary = append(summary, []string{"OS Hint", hint.String()})
+	}
+	if m.result != nil && m.result.Metadata()["probe_method"] == "tcp" {
+		summary = append(summary, []string{"Probe Method", "TCP connect to port 80 (ICMP unavailable)"})
+	}
+	content.WriteString(m.renderSection("Ping Summary", summary))
 
 	// Individual results (show last 5 for brevity)
 	content.WriteString("\n")
@@ -316,7 +495,7 @@ func (m *ResultViewModel) renderPingResults(results []domain.PingResult) string
 		if result.Error != nil {
 			status = fmt.Sprintf("❌ Seq %d: %v", result.Sequence, result.Error)
 		} else {
-			status = fmt.Sprintf("✅ Seq %d: time=%v ttl=%d", result.Sequence, result.RTT, result.TTL)
+			status = fmt.Sprintf("✅ Seq %d: time=%v ttl=%s", result.Sequence, result.RTT, formatReplyTTL(result.TTL))
 		}
 		content.WriteString("  " + status + "\n")
 	}
@@ -337,11 +516,110 @@ func (m *ResultViewModel) renderPingResult(result domain.PingResult) string {
 		{"IP", result.Host.IPAddress.String()},
 		{"Sequence", fmt.Sprintf("%d", result.Sequence)},
 		{"RTT", result.RTT.String()},
-		{"TTL", fmt.Sprintf("%d", result.TTL)},
+		{"TTL", formatReplyTTL(result.TTL)},
 		{"Timestamp", result.Timestamp.Format("2006-01-02 15:04:05")},
 	})
 }
 
+// formatReplyTTL formats a reply TTL with its OS hint, or "unknown" when the
+// probe could not read it
+func formatReplyTTL(ttl int) string {
+	hint, ok := domain.InferTTLHint(ttl)
+	if !ok {
+		return "unknown"
+	}
+	return fmt.Sprintf("%d (%s)", ttl, hint.Family)
+}
+
+// renderMultiPingResult renders one compact row per host of a multi-target ping
+func (m *ResultViewModel) renderMultiPingResult(result domain.MultiPingResult) string {
+	var content strings.Builder
+
+	if len(result.Targets) == 0 {
+		return "No ping results available"
+	}
+
+	reachable := 0
+	for _, target := range result.Targets {
+		if target.PacketsReceived > 0 {
+			reachable++
+		}
+	}
+
+	content.WriteString(m.renderSection("Ping Summary", [][]string{
+		{"Targets", fmt.Sprintf("%d", len(result.Targets))},
+		{"Reachable", fmt.Sprintf("%d", reachable)},
+	}))
+	content.WriteString("\n")
+
+	hostWidth := len("Host")
+	for _, target := range result.Targets {
+		if len(target.Host) > hostWidth {
+			hostWidth = len(target.Host)
+		}
+	}
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colors.Info)
+	content.WriteString(headerStyle.Render(fmt.Sprintf("  %-*s %6s %6s %7s %10s", hostWidth, "Host", "Sent", "Recv", "Loss", "Avg RTT")))
+	content.WriteString("\n")
+
+	for _, target := range result.Targets {
+		status := "✅"
+		avg := target.AvgRTT.Round(10 * time.Microsecond).String()
+		switch {
+		case target.Error != nil && target.PacketsReceived == 0:
+			status = "❌"
+			avg = "error"
+		case target.PacketsReceived == 0:
+			status = "❌"
+			avg = "-"
+		case target.PacketLoss > 0:
+			status = "⚠️"
+		}
+		content.WriteString(fmt.Sprintf("%s %-*s %6d %6d %6.1f%% %10s\n",
+			status, hostWidth, target.Host, target.PacketsSent, target.PacketsReceived, target.PacketLoss, avg))
+	}
+
+	return content.String()
+}
+
+// renderVantageResults renders the outcome from each vantage point under its own heading
+func (m *ResultViewModel) renderVantageResults(result domain.VantageResults) string {
+	var content strings.Builder
+
+	headingStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colors.Accent)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(colors.Error)
+	hintStyle := lipgloss.NewStyle().
+		Foreground(colors.Warning)
+
+	for i, point := range result.Points {
+		if i > 0 {
+			content.WriteString("\n")
+		}
+		content.WriteString(headingStyle.Render(fmt.Sprintf("━━ From %s ━━", point.Name)))
+		content.WriteString("\n")
+		switch {
+		case point.Error != nil:
+			content.WriteString(errorStyle.Render("❌ " + point.Error.Error()))
+			content.WriteString("\n")
+			if hint := domain.RemediationHint(point.Error); hint != "" {
+				content.WriteString(hintStyle.Render("💡 " + hint))
+				content.WriteString("\n")
+			}
+		case point.Result != nil:
+			content.WriteString(m.renderData(point.Result.Data()))
+			content.WriteString("\n")
+		}
+	}
+
+	return content.String()
+}
+
 // renderDNSResult renders DNS results with proper formatting and grouping
 func (m *ResultViewModel) renderDNSResult(result domain.DNSResult) string {
 	var content strings.Builder
@@ -354,6 +632,14 @@ func (m *ResultViewModel) renderDNSResult(result domain.DNSResult) string {
 		{"Total Records", fmt.Sprintf("%d", len(result.Records))},
 	}))
 
+	// Hosting provider section if detected
+	if m.result != nil {
+		if info, ok := m.result.Metadata()["provider"].(*domain.ProviderInfo); ok && info != nil {
+			content.WriteString("\n")
+			content.WriteString(m.renderProviderSection(info))
+		}
+	}
+
 	if len(result.Records) > 0 {
 		// Group records by type for better display
 		recordsByType := make(map[domain.DNSRecordType][]domain.DNSRecord)
@@ -427,6 +713,19 @@ func (m *ResultViewModel) renderDNSResult(result domain.DNSResult) string {
 	return content.String()
 }
 
+// renderProviderSection renders the detected CDN or hosting provider
+func (m *ResultViewModel) renderProviderSection(info *domain.ProviderInfo) string {
+	providerInfo := [][]string{
+		{"Provider", info.Name},
+		{"Category", strings.ToUpper(info.Category)},
+		{"Confidence", fmt.Sprintf("%.0f%%", info.Confidence*100)},
+	}
+	for i, evidence := range info.Evidence {
+		providerInfo = append(providerInfo, []string{fmt.Sprintf("Evidence %d", i+1), evidence})
+	}
+	return m.renderSection("Hosting Provider", providerInfo)
+}
+
 // renderSSLResult renders SSL results (placeholder)
 func (m *ResultViewModel) renderSSLResult(result domain.SSLResult) string {
 	return m.renderSection("SSL Certificate", [][]string{
@@ -439,6 +738,221 @@ func (m *ResultViewModel) renderSSLResult(result domain.SSLResult) string {
 	})
 }
 
+// renderDualStackResult renders an IPv4/IPv6 connection comparison
+func (m *ResultViewModel) renderDualStackResult(result domain.DualStackResult) string {
+	var content strings.Builder
+
+	winner := "none"
+	if result.Winner != "" {
+		winner = strings.ToUpper(result.Winner)
+		if result.IPv4.Success && result.IPv6.Success {
+			winner = fmt.Sprintf("%s by %v", winner, result.Margin)
+		}
+	}
+	preferred := "none"
+	if result.Preferred != "" {
+		preferred = strings.ToUpper(result.Preferred)
+	}
+
+	content.WriteString(m.renderSection("Dual-Stack Comparison", [][]string{
+		{"Host", result.Host},
+		{"Port", fmt.Sprintf("%d", result.Port)},
+		{"Winner", winner},
+		{"Happy Eyeballs", preferred},
+	}))
+
+	for _, family := range []domain.DualStackFamilyResult{result.IPv6, result.IPv4} {
+		status := "failed: " + family.Error
+		if family.Success {
+			status = fmt.Sprintf("%s in %v", family.Connected, family.ConnectTime)
+		}
+		addresses := strings.Join(family.Addresses, ", ")
+		if addresses == "" {
+			addresses = "none"
+		}
+		content.WriteString("\n\n")
+		content.WriteString(m.renderSection(strings.ToUpper(family.Family), [][]string{
+			{"Addresses", addresses},
+			{"Connect", status},
+		}))
+	}
+
+	if len(result.Diagnosis) > 0 {
+		diagnosis := make([][]string, 0, len(result.Diagnosis))
+		for i, note := range result.Diagnosis {
+			diagnosis = append(diagnosis, []string{fmt.Sprintf("%d", i+1), note})
+		}
+		content.WriteString("\n\n")
+		content.WriteString(m.renderSection("Diagnosis", diagnosis))
+	}
+
+	return content.String()
+}
+
+// renderSweepResult renders the live hosts found by a ping sweep
+func (m *ResultViewModel) renderSweepResult(result domain.SweepResult) string {
+	var content strings.Builder
+
+	content.WriteString(m.renderSection("Ping Sweep", [][]string{
+		{"Range", result.CIDR},
+		{"Scanned", fmt.Sprintf("%d", result.Scanned)},
+		{"Alive", fmt.Sprintf("%d", len(result.Hosts))},
+		{"Duration", result.Duration.Round(time.Millisecond).String()},
+	}))
+	content.WriteString("\n")
+
+	if len(result.Hosts) == 0 {
+		content.WriteString("No hosts answered\n")
+		return content.String()
+	}
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colors.Info)
+	content.WriteString(headerStyle.Render(fmt.Sprintf("%-39s %-30s %-17s %-18s %10s", "IP", "Hostname", "MAC", "Vendor", "RTT")))
+	content.WriteString("\n")
+
+	for _, host := range result.Hosts {
+		content.WriteString(fmt.Sprintf("%-39s %-30s %-17s %-18s %10s\n",
+			host.IP, valueOrDash(host.Hostname), valueOrDash(host.MAC), valueOrDash(host.Vendor),
+			host.RTT.Round(10*time.Microsecond)))
+	}
+
+	return content.String()
+}
+
+// renderZoneTransferResult renders which nameservers allowed a zone transfer
+func (m *ResultViewModel) renderZoneTransferResult(result domain.ZoneTransferResult) string {
+	var content strings.Builder
+
+	exposed := result.Exposed()
+	content.WriteString(m.renderSection("Zone Transfer Check", [][]string{
+		{"Domain", result.Domain},
+		{"Addresses", fmt.Sprintf("%d", len(result.Servers))},
+		{"Exposed", fmt.Sprintf("%d", len(exposed))},
+	}))
+	content.WriteString("\n")
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colors.Info)
+	content.WriteString(headerStyle.Render(fmt.Sprintf("%-30s %-39s %-10s %8s", "Nameserver", "Address", "AXFR", "Records")))
+	content.WriteString("\n")
+
+	for _, server := range result.Servers {
+		status := "error"
+		records := "-"
+		switch {
+		case server.Allowed:
+			status = "ALLOWED"
+			records = fmt.Sprintf("%d", server.Records)
+		case server.Refused:
+			status = "refused"
+		}
+		content.WriteString(fmt.Sprintf("%-30s %-39s %-10s %8s\n", server.Nameserver, valueOrDash(server.Address), status, records))
+	}
+
+	if len(exposed) > 0 {
+		content.WriteString("\n⚠ Allowing zone transfers to anyone leaks every host name in the zone. Restrict AXFR to secondary nameservers.\n")
+	}
+
+	return content.String()
+}
+
+// renderPluginResult renders the text of a plugin and any data it returned,
+// as a table when the plugin's render hints ask for one
+func (m *ResultViewModel) renderPluginResult(result domain.PluginResult) string {
+	var content strings.Builder
+
+	title, format := "Plugin", ""
+	var columns []string
+	if result.Hints != nil {
+		if result.Hints.Title != "" {
+			title = result.Hints.Title
+		}
+		format, columns = result.Hints.Format, result.Hints.Columns
+	}
+	content.WriteString(m.renderSection(title, [][]string{
+		{"Tool", result.Tool},
+		{"Completed", result.Timestamp.Format("2006-01-02 15:04:05")},
+	}))
+	content.WriteString("\n")
+	if result.Text != "" {
+		content.WriteString(result.Text)
+		content.WriteString("\n")
+	}
+	if result.Data == nil || format == "text" {
+		return content.String()
+	}
+
+	content.WriteString("\n")
+	if rows, ok := result.Data.([]interface{}); ok && format == "table" {
+		content.WriteString(renderPluginTable(rows, columns))
+	} else if data, err := json.MarshalIndent(result.Data, "", "  "); err == nil {
+		content.WriteString(string(data))
+		content.WriteString("\n")
+	}
+	return content.String()
+}
+
+// renderPluginTable renders a list of objects with a column per key. Without
+// columns the keys of the first object are used, sorted.
+func renderPluginTable(rows []interface{}, columns []string) string {
+	if len(columns) == 0 && len(rows) > 0 {
+		if first, ok := rows[0].(map[string]interface{}); ok {
+			for key := range first {
+				columns = append(columns, key)
+			}
+			sort.Strings(columns)
+		}
+	}
+
+	cells := make([][]string, len(rows))
+	widths := make([]int, len(columns))
+	for i, column := range columns {
+		widths[i] = len(column)
+	}
+	for r, row := range rows {
+		object, _ := row.(map[string]interface{})
+		cells[r] = make([]string, len(columns))
+		for i, column := range columns {
+			value := "-"
+			if v, ok := object[column]; ok && v != nil {
+				value = fmt.Sprint(v)
+			}
+			cells[r][i] = value
+			widths[i] = max(widths[i], len(value))
+		}
+	}
+
+	var content strings.Builder
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colors.Info)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = fmt.Sprintf("%-*s", widths[i], column)
+	}
+	content.WriteString(headerStyle.Render(strings.Join(header, "  ")))
+	content.WriteString("\n")
+	for _, row := range cells {
+		for i, value := range row {
+			row[i] = fmt.Sprintf("%-*s", widths[i], value)
+		}
+		content.WriteString(strings.Join(row, "  "))
+		content.WriteString("\n")
+	}
+	return content.String()
+}
+
+// valueOrDash returns "-" for empty table cells
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
 // renderTracerouteResults renders multiple traceroute hop results
 func (m *ResultViewModel) renderTracerouteResults(results []domain.TraceHop) string {
 	var content strings.Builder
@@ -467,11 +981,23 @@ func (m *ResultViewModel) renderTracerouteResults(results []domain.TraceHop) str
 		}
 	}
 
-	content.WriteString(m.renderSection("Traceroute Summary", [][]string{
+	summary := [][]string{
 		{"Target Host", targetHost},
 		{"Target IP", targetIP},
 		{"Total Hops", fmt.Sprintf("%d", len(results))},
-	}))
+	}
+	if m.result != nil {
+		if protocol, ok := m.result.Metadata()["protocol"].(string); ok && protocol != "" {
+			if port, ok := m.result.Metadata()["port"].(int); ok && port > 0 {
+				protocol = fmt.Sprintf("%s/%d", protocol, port)
+			}
+			summary = append(summary, []string{"Protocol", strings.ToUpper(protocol)})
+		}
+		if networks, ok := m.result.Metadata()["networks"].([]string); ok && len(networks) > 0 {
+			summary = append(summary, []string{"Networks", strings.Join(networks, " → ")})
+		}
+	}
+	content.WriteString(m.renderSection("Traceroute Summary", summary))
 
 	// Hop results
 	content.WriteString("\n")
@@ -527,6 +1053,9 @@ func (m *ResultViewModel) renderTracerouteResults(results []domain.TraceHop) str
 
 		hopLine := fmt.Sprintf("  %2d  %-20s %-15s %s  %s", 
 			hop.Number, hostname, ipAddr, rttInfo, status)
+		if asn := hop.Host.ASN.String(); asn != "" {
+			hopLine += "  [" + asn + "]"
+		}
 		content.WriteString(hopLine + "\n")
 	}
 
@@ -556,17 +1085,17 @@ func (m *ResultViewModel) renderSection(title string, data [][]string) string {
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
+		Foreground(colors.Info).
 		MarginBottom(1)
 
 	keyStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("205")).
+		Foreground(colors.Accent).
 		Width(15).
 		Align(lipgloss.Right)
 
 	valueStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252"))
+		Foreground(colors.Text)
 
 	content.WriteString(titleStyle.Render(title))
 	content.WriteString("\n")
@@ -589,7 +1118,7 @@ func (m *ResultViewModel) renderContactsSection(contacts map[string]domain.Conta
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
+		Foreground(colors.Info).
 		MarginBottom(1)
 
 	content.WriteString(titleStyle.Render("Contacts"))
@@ -644,27 +1173,154 @@ func (m *ResultViewModel) renderRawResult() string {
 	}
 
 	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252")).
-		Background(lipgloss.Color("236")).
+		Foreground(colors.Text).
+		Background(colors.Surface).
 		Padding(1).
 		Width(m.width - 4)
 
 	return style.Render(string(rawData))
 }
 
+// resultKeys are the keys of a result besides those of the key map
+var resultKeys = struct {
+	Copy           key.Binding
+	CopyJSON       key.Binding
+	HTMLReport     key.Binding
+	MarkdownReport key.Binding
+	Older          key.Binding
+	Newer          key.Binding
+	Actions        key.Binding
+}{
+	Copy:           hint("copy", "y"),
+	CopyJSON:       hint("copy JSON", "Y"),
+	HTMLReport:     hint("HTML report", "H"),
+	MarkdownReport: hint("Markdown report", "M"),
+	Older:          hint("older result", "[", "]"),
+	Newer:          hint("newer result", "{", "}"),
+	Actions:        hint("run a tool on an item", "a"),
+}
+
+// ShortHelp implements help.KeyMap with the keys of the current mode
+func (m *ResultViewModel) ShortHelp() []key.Binding {
+	if m.mode == ResultViewModeDiff {
+		return []key.Binding{resultKeys.Older, resultKeys.Newer, m.keyMap.FormattedView, m.keyMap.Up, m.keyMap.Down, m.keyMap.PageUp, m.keyMap.PageDown}
+	}
+
+	bindings := []key.Binding{
+		m.keyMap.FormattedView, m.keyMap.TableView, m.keyMap.RawView, m.keyMap.DiffView,
+		searchKeys.Search, searchKeys.Filter, resultKeys.Copy, resultKeys.Actions, m.keyMap.Export, relabel(m.keyMap.Tab, "cycle modes"),
+	}
+	if m.mode == ResultViewModeTable && m.tableModel != nil {
+		return append(bindings, m.tableModel.ColumnHelp()...)
+	}
+	return append(bindings, m.keyMap.Up, m.keyMap.Down, m.keyMap.PageUp, m.keyMap.PageDown, m.keyMap.Home, m.keyMap.End)
+}
+
+// FullHelp implements help.KeyMap
+func (m *ResultViewModel) FullHelp() [][]key.Binding {
+	groups := [][]key.Binding{
+		{m.keyMap.FormattedView, m.keyMap.TableView, m.keyMap.RawView, m.keyMap.DiffView, relabel(m.keyMap.Tab, "cycle modes")},
+		{searchKeys.Search, searchKeys.Filter, searchKeys.Next, searchKeys.Prev},
+		{resultKeys.Copy, resultKeys.CopyJSON, m.keyMap.Export, resultKeys.HTMLReport, resultKeys.MarkdownReport},
+		{resultKeys.Actions},
+	}
+	switch {
+	case m.mode == ResultViewModeTable && m.tableModel != nil:
+		groups = append(groups, m.tableModel.ColumnHelp())
+	case m.mode == ResultViewModeDiff:
+		groups = append(groups, []key.Binding{resultKeys.Older, resultKeys.Newer})
+	}
+	return groups
+}
+
 // renderViewModeHelp renders help text for view modes
 func (m *ResultViewModel) renderViewModeHelp() string {
-	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
-		Italic(true)
+	if m.save.active {
+		return m.renderSaveDialog()
+	}
+	if m.actions.active {
+		return m.renderActionMenu()
+	}
 
-	var help string
-	if m.mode == ResultViewModeTable {
-		help = "f: formatted • t: table • r: raw • tab: cycle modes • ↑/↓: navigate table"
+	help := screenHelp(m.width).ShortHelpView(m.ShortHelp())
+	if message := m.toast.Message(); message != "" {
+		toastStyle := lipgloss.NewStyle().
+			Foreground(colors.Success).
+			Bold(true)
+		return toastStyle.Render("✓ "+message) + "\n" + help
+	}
+	return help
+}
+
+// resultLines returns the lines of the result in the formatted, raw or
+// compare mode
+func (m *ResultViewModel) resultLines() []string {
+	var content string
+	switch m.mode {
+	case ResultViewModeFormatted:
+		content = m.renderFormattedResult()
+	case ResultViewModeRaw:
+		content = m.renderRawResult()
+	case ResultViewModeDiff:
+		content = m.renderDiffResult()
+	}
+	return strings.Split(content, "\n")
+}
+
+// pagerLines returns the lines of the result the search filter leaves
+func (m *ResultViewModel) pagerLines() []string {
+	lines := m.resultLines()
+	if !m.search.filtering {
+		return lines
+	}
+	var shown []string
+	for _, line := range lines {
+		if !m.search.hides(line) {
+			shown = append(shown, line)
+		}
+	}
+	return shown
+}
+
+// refreshPager puts the lines of the result into the pager with the search
+// matches highlighted. The position is kept unless the result or the mode
+// changed.
+func (m *ResultViewModel) refreshPager() []string {
+	lines := m.pagerLines()
+	items := make([]ScrollableItem, len(lines))
+	for i, line := range lines {
+		items[i] = NewStringScrollableItem(m.search.highlight(line), fmt.Sprintf("line_%d", i))
+	}
+	if m.shown != m.pagerShown || m.mode != m.pagerMode {
+		m.pagerShown, m.pagerMode = m.shown, m.mode
+		m.scrollPager.SetItems(items)
 	} else {
-		help = "f: formatted • t: table • r: raw • tab: cycle modes • ↑/↓: scroll • PgUp/PgDown: page • Home/End: jump"
+		m.scrollPager.ReplaceItems(items)
+	}
+	return lines
+}
+
+// applySearch scrolls to the match a search key asked for: the first one
+// from the selected line on when a search is applied, the top when it
+// filters, and the next or previous one for n and N
+func (m *ResultViewModel) applySearch(event searchEvent) {
+	if m.scrollPager == nil || event == searchNone {
+		return
+	}
+	lines := m.refreshPager()
+	selected := m.scrollPager.GetSelected()
+	switch event {
+	case searchApplied:
+		if m.search.filtering {
+			m.scrollPager.SetSelected(0)
+		} else if i := m.search.find(lines, selected-1, false); i >= 0 {
+			m.scrollPager.SetSelected(i)
+		}
+	case searchNextMatch, searchPrevMatch:
+		if i := m.search.find(lines, selected, event == searchPrevMatch); i >= 0 {
+			m.scrollPager.SetSelected(i)
+		}
 	}
-	return helpStyle.Render(help)
 }
 
 // cycleViewMode cycles through available view modes
@@ -674,7 +1330,7 @@ func (m *ResultViewModel) cycleViewMode() {
 		m.mode = ResultViewModeTable
 	case ResultViewModeTable:
 		m.mode = ResultViewModeRaw
-	case ResultViewModeRaw:
+	case ResultViewModeRaw, ResultViewModeDiff:
 		m.mode = ResultViewModeFormatted
 	}
 }
@@ -684,6 +1340,7 @@ func (m *ResultViewModel) updateTableModel() {
 	if m.result == nil {
 		return
 	}
+	previous := m.tableModel
 
 	// Create table data based on result type
 	switch data := m.result.Data().(type) {
@@ -691,14 +1348,31 @@ func (m *ResultViewModel) updateTableModel() {
 		m.updateWHOISTable(data)
 	case []domain.PingResult:
 		m.updatePingTable(data)
+	case domain.MultiPingResult:
+		m.updateMultiPingTable(data)
 	case domain.DNSResult:
 		m.updateDNSTable(data)
+	case domain.SweepResult:
+		m.updateSweepTable(data)
 	case []domain.TraceHop:
 		m.updateTracerouteTable(data)
 	default:
 		// Generic table for other types
 		m.updateGenericTable()
 	}
+	if m.tableModel != nil {
+		m.tableModel.SetKeyMap(m.keyMap)
+		if previous != nil && previous != m.tableModel {
+			// The search of the rows applies to the new result too
+			m.tableModel.search = previous.search
+			m.tableModel.search.typing = false
+		}
+		if layout, ok := m.layouts.Get(m.historyKey); ok {
+			m.tableModel.ApplyLayout(layout)
+		} else if previous != nil && previous != m.tableModel {
+			m.tableModel.ApplyLayout(previous.Layout())
+		}
+	}
 }
 
 // updateWHOISTable updates table model for WHOIS results
@@ -741,12 +1415,55 @@ func (m *ResultViewModel) updatePingTable(results []domain.PingResult) {
 			result.Host.Hostname,
 			result.Host.IPAddress.String(),
 			rtt,
-			fmt.Sprintf("%d", result.TTL),
+			formatReplyTTL(result.TTL),
+			status,
+		})
+	}
+}
+
+// updateMultiPingTable updates table model for multi-target ping results
+func (m *ResultViewModel) updateMultiPingTable(result domain.MultiPingResult) {
+	headers := []string{"Host", "Sent", "Received", "Loss %", "Avg RTT", "Status"}
+	m.tableModel = NewTableModel(headers)
+
+	for _, target := range result.Targets {
+		status := "Success"
+		avg := target.AvgRTT.String()
+		if target.PacketsReceived == 0 {
+			status = "Unreachable"
+			avg = "N/A"
+			if target.Error != nil {
+				status = "Failed: " + target.Error.Error()
+			}
+		}
+
+		m.tableModel.AddRow([]string{
+			target.Host,
+			fmt.Sprintf("%d", target.PacketsSent),
+			fmt.Sprintf("%d", target.PacketsReceived),
+			fmt.Sprintf("%.1f", target.PacketLoss),
+			avg,
 			status,
 		})
 	}
 }
 
+// updateSweepTable updates table model for ping sweep results
+func (m *ResultViewModel) updateSweepTable(result domain.SweepResult) {
+	headers := []string{"IP", "Hostname", "MAC", "Vendor", "RTT"}
+	m.tableModel = NewTableModel(headers)
+
+	for _, host := range result.Hosts {
+		m.tableModel.AddRow([]string{
+			host.IP.String(),
+			host.Hostname,
+			host.MAC,
+			host.Vendor,
+			fmt.Sprintf("%.1f ms", float64(host.RTT.Nanoseconds())/1000000.0),
+		})
+	}
+}
+
 // updateDNSTable updates table model for DNS results
 func (m *ResultViewModel) updateDNSTable(result domain.DNSResult) {
 	headers := []string{"Name", "Type", "Value", "TTL"}
@@ -764,7 +1481,7 @@ func (m *ResultViewModel) updateDNSTable(result domain.DNSResult) {
 
 // updateTracerouteTable updates table model for traceroute results
 func (m *ResultViewModel) updateTracerouteTable(results []domain.TraceHop) {
-	headers := []string{"Hop", "Hostname", "IP Address", "RTT 1", "RTT 2", "RTT 3", "Status"}
+	headers := []string{"Hop", "Hostname", "IP Address", "ASN", "RTT 1", "RTT 2", "RTT 3", "Status"}
 	m.tableModel = NewTableModel(headers)
 
 	for _, hop := range results {
@@ -792,6 +1509,11 @@ func (m *ResultViewModel) updateTracerouteTable(results []domain.TraceHop) {
 			ipAddr = hop.Host.IPAddress.String()
 		}
 		
+		asn := hop.Host.ASN.String()
+		if asn == "" {
+			asn = "-"
+		}
+		
 		status := "✓ OK"
 		if hop.Timeout {
 			status = "✗ Timeout"
@@ -801,6 +1523,7 @@ func (m *ResultViewModel) updateTracerouteTable(results []domain.TraceHop) {
 			fmt.Sprintf("%d", hop.Number),
 			hostname,
 			ipAddr,
+			asn,
 			rtt1,
 			rtt2,
 			rtt3,
@@ -933,6 +1656,17 @@ func (m *ResultViewModel) SetSize(width, height int) {
 	}
 }
 
+// SetKeyMap sets the key bindings of the view, its table and its pager
+func (m *ResultViewModel) SetKeyMap(keyMap KeyMap) {
+	m.keyMap = keyMap
+	if m.tableModel != nil {
+		m.tableModel.SetKeyMap(keyMap)
+	}
+	if m.scrollPager != nil {
+		m.scrollPager.SetKeyMap(keyMap)
+	}
+}
+
 // SetTheme implements domain.TUIComponent
 func (m *ResultViewModel) SetTheme(theme domain.Theme) {
 	m.theme = theme
@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
 	"github.com/nettracex/nettracex-tui/internal/domain"
 )
 
@@ -235,7 +236,7 @@ func (s *ScrollableView) renderHeader() string {
 
 	style := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("39")).
+		Foreground(colors.Info).
 		Align(lipgloss.Center).
 		Width(s.width)
 
@@ -254,7 +255,7 @@ func (s *ScrollableView) renderFooter() string {
 	}
 
 	info := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(colors.Subtle).
 		Render(scrollInfo + s.footerText)
 
 	line := strings.Repeat("─", max(0, s.width-lipgloss.Width(info)))
@@ -0,0 +1,234 @@
+// Package tui contains the search and filter of result views and tables
+package tui
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+)
+
+// searchEvent is what a key handled by a textSearch asks the view to do
+type searchEvent int
+
+const (
+	searchNone searchEvent = iota
+	// searchApplied means a search was confirmed or cleared
+	searchApplied
+	searchNextMatch
+	searchPrevMatch
+)
+
+// searchKeys are the keys starting a search and jumping between matches
+var searchKeys = struct {
+	Search key.Binding
+	Filter key.Binding
+	Next   key.Binding
+	Prev   key.Binding
+}{
+	Search: hint("search", "/"),
+	Filter: hint("filter", "&"),
+	Next:   hint("next match", "n"),
+	Prev:   hint("previous match", "N"),
+}
+
+// matchStyle highlights the text matching a search
+var matchStyle = lipgloss.NewStyle().Background(colors.Warning).Foreground(colors.Adaptive("0"))
+
+// textSearch searches the lines of a result or the rows of a table, as less
+// does: / finds and highlights the matches, n and N jump between them, and
+// & shows only the matching ones. Tab switches the prompt between substring
+// and regular expression, and confirming an empty prompt clears the search.
+type textSearch struct {
+	typing  bool
+	filter  bool
+	regex   bool
+	input   string
+	err     error
+	query   string
+	pattern *regexp.Regexp
+	// filtering reports whether the active search hides what does not match
+	filtering bool
+}
+
+// update handles a key, reporting what the view should do and whether the
+// key was consumed
+func (s *textSearch) update(msg tea.KeyMsg) (searchEvent, bool) {
+	if !s.typing {
+		switch {
+		case key.Matches(msg, searchKeys.Search, searchKeys.Filter):
+			s.typing = true
+			s.filter = key.Matches(msg, searchKeys.Filter)
+			s.input = ""
+			s.err = nil
+			return searchNone, true
+		case key.Matches(msg, searchKeys.Next):
+			if s.pattern != nil {
+				return searchNextMatch, true
+			}
+		case key.Matches(msg, searchKeys.Prev):
+			if s.pattern != nil {
+				return searchPrevMatch, true
+			}
+		}
+		return searchNone, false
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		s.typing = false
+	case tea.KeyEnter:
+		if s.input == "" {
+			s.typing = false
+			s.pattern = nil
+			s.filtering = false
+			return searchApplied, true
+		}
+		pattern, err := s.compile()
+		if err != nil {
+			s.err = err
+			return searchNone, true
+		}
+		s.typing = false
+		s.query = s.input
+		s.pattern = pattern
+		s.filtering = s.filter
+		return searchApplied, true
+	case tea.KeyTab:
+		s.regex = !s.regex
+		s.err = nil
+	case tea.KeyBackspace:
+		if s.input == "" {
+			s.typing = false
+		} else {
+			runes := []rune(s.input)
+			s.input = string(runes[:len(runes)-1])
+			s.err = nil
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		s.input += string(msg.Runes)
+		s.err = nil
+	}
+	return searchNone, true
+}
+
+// compile returns the pattern of the prompt: a case-insensitive substring,
+// or a regular expression as typed
+func (s *textSearch) compile() (*regexp.Regexp, error) {
+	if !s.regex {
+		return regexp.MustCompile("(?i)" + regexp.QuoteMeta(s.input)), nil
+	}
+	pattern, err := regexp.Compile(s.input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression: %w", err)
+	}
+	return pattern, nil
+}
+
+// Typing reports whether the prompt is open and needs every key
+func (s *textSearch) Typing() bool {
+	return s.typing
+}
+
+// Active reports whether a search is applied
+func (s *textSearch) Active() bool {
+	return s.pattern != nil
+}
+
+// matches reports whether text, ANSI styles left out, matches the search
+func (s *textSearch) matches(text string) bool {
+	return s.pattern != nil && s.pattern.MatchString(ansi.Strip(text))
+}
+
+// hides reports whether the search filters text out
+func (s *textSearch) hides(text string) bool {
+	return s.filtering && !s.matches(text)
+}
+
+// highlight returns text with the matches highlighted. Matching lines lose
+// their own styles so that the highlights show on any terminal.
+func (s *textSearch) highlight(text string) string {
+	if s.pattern == nil {
+		return text
+	}
+	plain := ansi.Strip(text)
+	spans := s.pattern.FindAllStringIndex(plain, -1)
+	if len(spans) == 0 {
+		return text
+	}
+
+	var highlighted string
+	last := 0
+	for _, span := range spans {
+		if span[0] == span[1] {
+			continue
+		}
+		highlighted += plain[last:span[0]] + matchStyle.Render(plain[span[0]:span[1]])
+		last = span[1]
+	}
+	if last == 0 {
+		return text
+	}
+	return highlighted + plain[last:]
+}
+
+// find returns the index of the next text matching the search after from,
+// or before it when backwards, wrapping around; -1 when none does
+func (s *textSearch) find(texts []string, from int, backwards bool) int {
+	count := len(texts)
+	for step := 1; step <= count; step++ {
+		i := from + step
+		if backwards {
+			i = from - step
+		}
+		i = ((i % count) + count) % count
+		if s.matches(texts[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// prompt renders the open prompt, or nothing
+func (s *textSearch) prompt() string {
+	if !s.typing {
+		return ""
+	}
+	prefix := "/"
+	if s.filter {
+		prefix = "&"
+	}
+	kind := "text"
+	if s.regex {
+		kind = "regex"
+	}
+	prompt := lipgloss.NewStyle().Bold(true).Render(prefix+s.input+"█") +
+		lipgloss.NewStyle().Foreground(colors.Subtle).Render(fmt.Sprintf("  (%s, tab: text/regex, enter: apply, esc: cancel)", kind))
+	if s.err != nil {
+		prompt += "\n" + lipgloss.NewStyle().Foreground(colors.Error).Render(s.err.Error())
+	}
+	return prompt
+}
+
+// status describes the applied search and how many of texts match it
+func (s *textSearch) status(texts []string) string {
+	if s.typing || s.pattern == nil {
+		return ""
+	}
+	count := 0
+	for _, text := range texts {
+		if s.matches(text) {
+			count++
+		}
+	}
+	verb := "search"
+	if s.filtering {
+		verb = "filter"
+	}
+	return lipgloss.NewStyle().Foreground(colors.Info).Render(
+		fmt.Sprintf("%s %q: %d of %d match • n/N: next/previous • / enter: clear", verb, s.query, count, len(texts)))
+}
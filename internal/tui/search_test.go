@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextSearch_Update(t *testing.T) {
+	var search textSearch
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	_, handled := search.update(runes("n"))
+	assert.False(t, handled, "n does nothing before a search")
+
+	search.update(runes("/"))
+	require.True(t, search.Typing())
+	search.update(runes("MX"))
+	assert.Contains(t, search.prompt(), "/MX")
+	event, _ := search.update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, searchApplied, event)
+	assert.True(t, search.matches("example.com mx 10"), "text searches ignore case")
+	assert.False(t, search.hides("NS ns1.example.com"), "/ does not filter")
+
+	event, _ = search.update(runes("N"))
+	assert.Equal(t, searchPrevMatch, event)
+
+	// & filters, and tab switches to a regular expression
+	search.update(runes("&"))
+	search.update(tea.KeyMsg{Type: tea.KeyTab})
+	search.update(runes("^ns[0-9]("))
+	event, _ = search.update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, searchNone, event)
+	assert.Contains(t, search.prompt(), "invalid regular expression")
+	search.update(tea.KeyMsg{Type: tea.KeyBackspace})
+	search.update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, search.Typing())
+	assert.True(t, search.hides("mx.example.com"))
+	assert.False(t, search.hides("ns1.example.com"))
+
+	// An empty search clears it
+	search.update(runes("/"))
+	event, _ = search.update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, searchApplied, event)
+	assert.False(t, search.Active())
+}
+
+func TestTextSearch_Highlight(t *testing.T) {
+	var search textSearch
+	typeSearch(&search, "/ex")
+
+	highlighted := search.highlight("\x1b[1mexample.com\x1b[0m NEXT")
+	assert.Equal(t, "example.com NEXT", ansi.Strip(highlighted))
+	assert.Equal(t, "no match", search.highlight("no match"))
+	assert.Equal(t, 1, search.find([]string{"a", "ex", "b"}, 1, true), "wraps around")
+	assert.Equal(t, -1, search.find([]string{"a", "b"}, 0, false))
+}
+
+func TestTableModel_SearchAndFilter(t *testing.T) {
+	table := NewTableModel([]string{"Type", "Value"})
+	table.SetSize(80, 20)
+	table.AddRow([]string{"A", "93.184.216.34"})
+	table.AddRow([]string{"MX", "10 mail.example.com"})
+	table.AddRow([]string{"NS", "a.iana-servers.net"})
+	table.AddRow([]string{"MX", "20 backup.example.com"})
+
+	typeKeys(table, "/mx")
+	assert.True(t, table.CapturesInput())
+	table.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	row, _ := table.SelectedRow()
+	assert.Equal(t, "10 mail.example.com", row[1], "the search works without vi mode")
+	typeKeys(table, "n")
+	row, _ = table.SelectedRow()
+	assert.Equal(t, "20 backup.example.com", row[1])
+	assert.Contains(t, table.View(), `search "mx": 2 of 4 match`)
+
+	typeKeys(table, "&")
+	table.Update(tea.KeyMsg{Type: tea.KeyTab})
+	typeKeys(table, `^(A|NS) `)
+	table.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Len(t, table.getFilteredRows(), 2)
+	row, _ = table.SelectedRow()
+	assert.Equal(t, "A", row[0])
+	table.Update(tea.KeyMsg{Type: tea.KeyDown})
+	row, _ = table.SelectedRow()
+	assert.Equal(t, "NS", row[0], "the selection moves over the shown rows")
+	assert.NotContains(t, ansi.Strip(table.View()), "mail.example.com")
+}
+
+func TestResultViewModel_Search(t *testing.T) {
+	hops := make([]domain.TraceHop, 0, 40)
+	for i := 1; i <= 40; i++ {
+		hops = append(hops, domain.TraceHop{Number: i})
+	}
+	hops[29].Host = domain.NetworkHost{Hostname: "edge.example.net"}
+
+	model := NewResultViewModel()
+	model.SetSize(100, 20)
+	model.Focus()
+	model.SetResult(domain.NewResult(hops))
+	model.View()
+
+	typeKeys(model, "/edge")
+	assert.True(t, model.CapturesInput())
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	match := model.scrollPager.GetSelected()
+	assert.Greater(t, match, 20, "the pager scrolls to the match")
+	view := model.View()
+	assert.Equal(t, match, model.scrollPager.GetSelected(), "rendering keeps the scroll position")
+	assert.Contains(t, ansi.Strip(view), "edge.example.net")
+	assert.Contains(t, ansi.Strip(view), `search "edge": 1 of`)
+	typeKeys(model, "n")
+	assert.Equal(t, match, model.scrollPager.GetSelected(), "n wraps around to the only match")
+
+	typeKeys(model, "&example")
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	lines := model.pagerLines()
+	require.Len(t, lines, 1)
+	assert.Contains(t, ansi.Strip(lines[0]), "edge.example.net")
+
+	typeKeys(model, "r")
+	assert.Equal(t, ResultViewModeRaw, model.mode, "keys reach the view once the prompt is closed")
+}
+
+// typeSearch types query into search and applies it
+func typeSearch(search *textSearch, query string) {
+	for _, r := range query {
+		search.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	search.update(tea.KeyMsg{Type: tea.KeyEnter})
+}
@@ -0,0 +1,178 @@
+// Package tui contains the saving and restoring of the TUI session
+package tui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
+	"github.com/nettracex/nettracex-tui/internal/session"
+)
+
+// SetSession sets where the session is saved on exit. When saved holds a
+// previous session the user is asked whether to restore it before the main menu.
+func (m *MainModel) SetSession(path string, saved *session.Session) {
+	m.sessionPath = path
+	if saved != nil && !saved.Empty() {
+		m.pendingSession = saved
+		m.state = StateRestore
+	}
+}
+
+// updateRestorePrompt handles the keys of the restore prompt
+func (m *MainModel) updateRestorePrompt(msg tea.KeyMsg) (*MainModel, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y", "enter":
+		saved := m.pendingSession
+		m.pendingSession = nil
+		m.state = StateMainMenu
+		return m, m.restoreSession(saved)
+	case "n", "N", "esc":
+		m.pendingSession = nil
+		m.state = StateMainMenu
+		m.sessionErr = session.Remove(m.sessionPath)
+		return m, nil
+	case "q", "ctrl+c":
+		// Keep the saved session for the next launch
+		m.sessionPath = ""
+		m.quitting = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// restoreSession restores the form values and result history of saved and
+// reopens the tool that was active, resuming it if it was still running
+func (m *MainModel) restoreSession(saved *session.Session) tea.Cmd {
+	for tool, values := range saved.Forms {
+		m.forms[tool] = values
+	}
+	for tool, entries := range saved.History {
+		for _, entry := range entries {
+			if result, err := entry.Result(); err == nil {
+				m.history.Add(tool, result)
+			}
+		}
+	}
+
+	if saved.ActiveTool == "" {
+		return nil
+	}
+	tool, exists := m.plugins.Get(saved.ActiveTool)
+	if !exists {
+		return nil
+	}
+
+	diagnosticView := m.newDiagnosticView(tool)
+	m.state = StateDiagnostic
+	m.activeView = diagnosticView
+	m.navigation.Blur()
+
+	if saved.Running {
+		return diagnosticView.Resume()
+	}
+	if entries := m.history.Entries(tool.Name()); saved.Result && len(entries) > 0 {
+		diagnosticView.ShowResult(entries[len(entries)-1].Result)
+	}
+	return nil
+}
+
+// rememberForm keeps the values entered for the active tool so they are
+// offered again when the tool is reopened and saved with the session
+func (m *MainModel) rememberForm() {
+	if diagnosticView, ok := m.activeView.(*DiagnosticViewModel); ok {
+		m.forms[diagnosticView.GetTool().Name()] = diagnosticView.FormValues()
+	}
+}
+
+// snapshotSession captures the open tool, entered values and result history
+func (m *MainModel) snapshotSession() *session.Session {
+	snapshot := session.New()
+	snapshot.SavedAt = time.Now()
+
+	m.rememberForm()
+	for _, view := range m.backgroundViews() {
+		if diagnosticView, ok := view.(*DiagnosticViewModel); ok {
+			m.forms[diagnosticView.GetTool().Name()] = diagnosticView.FormValues()
+		}
+	}
+	for tool, values := range m.forms {
+		snapshot.Forms[tool] = values
+	}
+
+	if diagnosticView, ok := m.activeView.(*DiagnosticViewModel); ok && m.state == StateDiagnostic {
+		snapshot.ActiveTool = diagnosticView.GetTool().Name()
+		snapshot.Running = diagnosticView.IsLoading()
+		snapshot.Result = diagnosticView.GetState() == DiagnosticStateResult
+	}
+
+	for _, tool := range m.history.Tools() {
+		for _, entry := range m.history.Entries(tool) {
+			// Results of types that cannot be saved, such as plugin results, are skipped
+			snapshot.AddResult(tool, entry.Result, entry.Timestamp)
+		}
+	}
+	return snapshot
+}
+
+// saveSession writes the session for the next launch, or removes the saved
+// session when there is nothing worth restoring
+func (m *MainModel) saveSession() {
+	if m.sessionPath == "" {
+		return
+	}
+	snapshot := m.snapshotSession()
+	if snapshot.Empty() {
+		m.sessionErr = session.Remove(m.sessionPath)
+		return
+	}
+	m.sessionErr = session.Save(m.sessionPath, snapshot)
+}
+
+// quit saves the session and ends the program
+func (m *MainModel) quit() (*MainModel, tea.Cmd) {
+	m.saveSession()
+	m.quitting = true
+	return m, tea.Quit
+}
+
+// renderRestorePrompt asks whether to restore the previous session
+func (m *MainModel) renderRestorePrompt() string {
+	saved := m.pendingSession
+
+	var details []string
+	if saved.ActiveTool != "" {
+		state := i18n.T("restore.open")
+		if saved.Running {
+			state = i18n.T("restore.running")
+		}
+		details = append(details, i18n.T("restore.active_tool", saved.ActiveTool, state))
+	}
+	results := 0
+	for _, entries := range saved.History {
+		results += len(entries)
+	}
+	details = append(details, i18n.T("restore.results", results))
+	if !saved.SavedAt.IsZero() {
+		details = append(details, i18n.T("restore.saved_at", saved.SavedAt.Format("2006-01-02 15:04:05")))
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(colors.Accent)
+	detailStyle := lipgloss.NewStyle().Foreground(colors.Text)
+	promptStyle := lipgloss.NewStyle().Foreground(colors.Subtle)
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colors.Primary).
+		Padding(1, 2)
+
+	return boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render(i18n.T("restore.title")),
+		"",
+		detailStyle.Render(strings.Join(details, "\n")),
+		"",
+		promptStyle.Render(i18n.T("restore.prompt")),
+	))
+}
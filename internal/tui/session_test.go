@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	configpkg "github.com/nettracex/nettracex-tui/internal/config"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sessionTestTool is a DNS tool stub whose runs are counted
+type sessionTestTool struct {
+	runs int
+}
+
+func (t *sessionTestTool) Name() string        { return "dns" }
+func (t *sessionTestTool) Description() string { return "DNS lookup" }
+func (t *sessionTestTool) Validate(params domain.Parameters) error {
+	return nil
+}
+func (t *sessionTestTool) GetModel() tea.Model { return nil }
+func (t *sessionTestTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	t.runs++
+	return domain.NewResult(domain.DNSResult{Query: params.Get("domain").(string)}), nil
+}
+
+// sessionTestRegistry provides a single tool
+type sessionTestRegistry struct {
+	tool domain.DiagnosticTool
+}
+
+func (r *sessionTestRegistry) Register(tool domain.DiagnosticTool) error { return nil }
+func (r *sessionTestRegistry) Unregister(name string) error              { return nil }
+func (r *sessionTestRegistry) List() []domain.DiagnosticTool {
+	return []domain.DiagnosticTool{r.tool}
+}
+func (r *sessionTestRegistry) Get(name string) (domain.DiagnosticTool, bool) {
+	return r.tool, name == r.tool.Name()
+}
+
+// newSessionTestModel creates a main model offering tool
+func newSessionTestModel(tool domain.DiagnosticTool) *MainModel {
+	return NewMainModel(&sessionTestRegistry{tool: tool}, &domain.Config{}, configpkg.NewManager(), nil)
+}
+
+func TestMainModel_SessionSaveAndRestore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), session.FileName)
+	tool := &sessionTestTool{}
+
+	first := newSessionTestModel(tool)
+	first.SetSession(path, nil)
+	assert.Equal(t, StateMainMenu, first.state)
+
+	first.selectNavigationItem(NavigationItem{ID: "dns"})
+	view := first.activeView.(*DiagnosticViewModel)
+	view.SetFormValues(map[string]string{"domain": "example.com", "record_type": "MX"})
+	result := domain.NewResult(domain.DNSResult{Query: "example.com"})
+	result.SetMetadata("timestamp", time.Now())
+	view.Update(DiagnosticResultMsg{Result: result})
+
+	_, cmd := first.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	require.NotNil(t, cmd)
+	require.NoError(t, first.sessionErr)
+
+	saved, err := session.Load(path)
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	assert.Equal(t, "dns", saved.ActiveTool)
+	assert.True(t, saved.Result)
+	assert.False(t, saved.Running)
+	assert.Equal(t, "MX", saved.Forms["dns"]["record_type"])
+	require.Len(t, saved.History["dns"], 1)
+
+	second := newSessionTestModel(tool)
+	second.SetSession(path, saved)
+	assert.Equal(t, StateRestore, second.state)
+	second.width, second.height = 80, 24
+	assert.Contains(t, second.View(), "Restore previous session?")
+
+	second.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	assert.Equal(t, StateDiagnostic, second.state)
+	restored := second.activeView.(*DiagnosticViewModel)
+	assert.Equal(t, DiagnosticStateResult, restored.GetState())
+	assert.Equal(t, "example.com", restored.FormValues()["domain"])
+	assert.Equal(t, "MX", restored.FormValues()["record_type"])
+	require.NotNil(t, restored.GetResult())
+	assert.Equal(t, "example.com", restored.GetResult().Data().(domain.DNSResult).Query)
+	assert.Len(t, second.history.Entries("dns"), 1, "the restored result is not recorded twice")
+}
+
+func TestMainModel_SessionResumesRunningTool(t *testing.T) {
+	tool := &sessionTestTool{}
+	model := newSessionTestModel(tool)
+
+	saved := session.New()
+	saved.ActiveTool = "dns"
+	saved.Running = true
+	saved.Forms["dns"] = map[string]string{"domain": "example.org"}
+	model.SetSession(filepath.Join(t.TempDir(), session.FileName), saved)
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	require.NotNil(t, cmd)
+	for _, msg := range cmd().(tea.BatchMsg) {
+		model.Update(msg())
+	}
+
+	assert.Equal(t, 1, tool.runs)
+	view := model.activeView.(*DiagnosticViewModel)
+	assert.Equal(t, DiagnosticStateResult, view.GetState())
+	assert.Equal(t, "example.org", view.GetResult().Data().(domain.DNSResult).Query)
+}
+
+func TestMainModel_SessionDiscard(t *testing.T) {
+	path := filepath.Join(t.TempDir(), session.FileName)
+	saved := session.New()
+	saved.Forms["dns"] = map[string]string{"domain": "example.com"}
+	require.NoError(t, session.Save(path, saved))
+
+	model := newSessionTestModel(&sessionTestTool{})
+	model.SetSession(path, saved)
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+
+	assert.Equal(t, StateMainMenu, model.state)
+	loaded, err := session.Load(path)
+	require.NoError(t, err)
+	assert.Nil(t, loaded)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
 	"github.com/nettracex/nettracex-tui/internal/domain"
 )
 
@@ -16,6 +17,7 @@ type StandardScrollPager struct {
 	width   int
 	height  int
 	focused bool
+	vi      viKeys
 }
 
 // NewStandardScrollPager creates a new StandardScrollPager
@@ -38,19 +40,28 @@ func (p *StandardScrollPager) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		if delta := wheelDelta(msg); delta != 0 {
+			p.wheel(delta)
+		}
+
 	case tea.KeyMsg:
+		if motion, ok := p.vi.update(msg, p.content.KeyMap); ok {
+			p.applyViMotion(motion)
+			return p, nil
+		}
 		switch {
 		case key.Matches(msg, p.content.KeyMap.Up):
 			p.MoveUp()
 		case key.Matches(msg, p.content.KeyMap.Down):
 			p.MoveDown()
-		case key.Matches(msg, key.NewBinding(key.WithKeys("pgup", "ctrl+b"))):
+		case key.Matches(msg, p.content.KeyMap.PageUp):
 			p.PageUp()
-		case key.Matches(msg, key.NewBinding(key.WithKeys("pgdown", "ctrl+f"))):
+		case key.Matches(msg, p.content.KeyMap.PageDown):
 			p.PageDown()
-		case key.Matches(msg, key.NewBinding(key.WithKeys("home", "ctrl+a"))):
+		case key.Matches(msg, p.content.KeyMap.Home):
 			p.Home()
-		case key.Matches(msg, key.NewBinding(key.WithKeys("end", "ctrl+e"))):
+		case key.Matches(msg, p.content.KeyMap.End):
 			p.End()
 		}
 	}
@@ -117,6 +128,10 @@ func (p *StandardScrollPager) View() string {
 		result.WriteString(indicator)
 	}
 
+	if prompt := p.vi.prompt(); prompt != "" {
+		result.WriteString("\n" + prompt)
+	}
+
 	return result.String()
 }
 
@@ -143,6 +158,11 @@ func (p *StandardScrollPager) SetTheme(theme domain.Theme) {
 	p.content.Theme = theme
 }
 
+// SetKeyMap sets the keys that scroll the pager
+func (p *StandardScrollPager) SetKeyMap(keyMap KeyMap) {
+	p.content.KeyMap = keyMap
+}
+
 // Focus implements domain.TUIComponent
 func (p *StandardScrollPager) Focus() {
 	p.focused = true
@@ -158,6 +178,13 @@ func (p *StandardScrollPager) SetItems(items []ScrollableItem) {
 	p.content.SetItems(items)
 }
 
+// ReplaceItems replaces the items like SetItems but keeps the selection and
+// the scroll position, for content rebuilt on every render
+func (p *StandardScrollPager) ReplaceItems(items []ScrollableItem) {
+	p.content.Items = items
+	p.content.Position.EnsureSelectionVisible(len(items))
+}
+
 // GetItems implements ScrollableList
 func (p *StandardScrollPager) GetItems() []ScrollableItem {
 	return p.content.Items
@@ -348,7 +375,7 @@ func (p *StandardScrollPager) SetShowScrollIndicators(show bool) {
 // renderScrollIndicator renders a scroll indicator with styling
 func (p *StandardScrollPager) renderScrollIndicator(icon, helpText string) string {
 	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(colors.Subtle).
 		Align(lipgloss.Center).
 		Width(p.width)
 
@@ -363,11 +390,56 @@ func (p *StandardScrollPager) renderScrollIndicator(icon, helpText string) strin
 // renderEmptyState renders the empty state when no items are present
 func (p *StandardScrollPager) renderEmptyState() string {
 	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("243")).
+		Foreground(colors.Muted).
 		Italic(true).
 		Align(lipgloss.Center).
 		Width(p.width).
 		Height(p.height)
 
 	return style.Render("No items to display")
-}
\ No newline at end of file
+}
+// CapturesInput reports whether the vi search prompt is open and needs
+// every key
+func (p *StandardScrollPager) CapturesInput() bool {
+	return p.vi.Searching()
+}
+
+// JumpTo selects the item on line, counting from 1, as :<line> does in vi
+func (p *StandardScrollPager) JumpTo(line int) {
+	p.SetSelected(line - 1)
+}
+
+// applyViMotion moves the selection as a vi key asked
+func (p *StandardScrollPager) applyViMotion(motion viMotion) {
+	switch motion {
+	case viTop:
+		if !p.Home() {
+			p.SetSelected(0)
+		}
+	case viBottom:
+		if !p.End() {
+			p.SetSelected(len(p.content.Items) - 1)
+		}
+	case viNextMatch, viPrevMatch:
+		texts := make([]string, len(p.content.Items))
+		for i, item := range p.content.Items {
+			if searchable, ok := item.(searchableItem); ok {
+				texts[i] = searchable.SearchText()
+			}
+		}
+		if i := viFind(texts, p.vi.query, p.content.Position.SelectedIndex, motion == viPrevMatch); i >= 0 {
+			p.SetSelected(i)
+		}
+	}
+}
+
+// wheel moves the selection a step of the mouse wheel: to the next
+// selectable item, or by a line through content that has none
+func (p *StandardScrollPager) wheel(delta int) {
+	if delta < 0 && p.MoveUp() || delta > 0 && p.MoveDown() {
+		return
+	}
+	if len(p.content.Items) > 0 && !p.content.Items[p.GetSelected()].IsSelectable() {
+		p.SetSelected(p.GetSelected() + delta)
+	}
+}
@@ -0,0 +1,316 @@
+// Package tui contains the sorting, hiding, resizing and reordering of table columns
+package tui
+
+import (
+	"bytes"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/session"
+)
+
+// minColumnWidth is the narrowest a column is shown
+const minColumnWidth = 5
+
+// columnKeys are the keys arranging the columns of a table besides ←/→
+var columnKeys = struct {
+	Sort      key.Binding
+	Hide      key.Binding
+	ShowAll   key.Binding
+	Narrower  key.Binding
+	Wider     key.Binding
+	MoveLeft  key.Binding
+	MoveRight key.Binding
+}{
+	Sort:      hint("sort by column", "s"),
+	Hide:      hint("hide column", "x"),
+	ShowAll:   hint("show all columns", "X"),
+	Narrower:  hint("narrower", "<"),
+	Wider:     hint("wider", ">"),
+	MoveLeft:  hint("move column left", "["),
+	MoveRight: hint("move column right", "]"),
+}
+
+// updateColumns handles the column keys of a table: ←/→ pick a column, s
+// sorts by it ascending then descending, x hides it and X shows every column
+// again, < and > narrow and widen it, and [ and ] move it left and right. It
+// reports whether the key was one of them.
+func (m *TableModel) updateColumns(msg tea.KeyMsg) bool {
+	switch {
+	case key.Matches(msg, m.keyMap.Left):
+		if m.column > 0 {
+			m.column--
+		}
+	case key.Matches(msg, m.keyMap.Right):
+		if m.column < len(m.columns)-1 {
+			m.column++
+		}
+	case key.Matches(msg, columnKeys.Sort):
+		column, ok := m.focusedColumn()
+		if !ok {
+			return true
+		}
+		m.SortBy(column, m.sortBy == column && !m.sortDesc)
+	case key.Matches(msg, columnKeys.Hide):
+		m.HideColumn()
+	case key.Matches(msg, columnKeys.ShowAll):
+		m.ShowAllColumns()
+	case key.Matches(msg, columnKeys.Narrower):
+		m.ResizeColumn(-2)
+	case key.Matches(msg, columnKeys.Wider):
+		m.ResizeColumn(2)
+	case key.Matches(msg, columnKeys.MoveLeft):
+		m.MoveColumn(-1)
+	case key.Matches(msg, columnKeys.MoveRight):
+		m.MoveColumn(1)
+	default:
+		return false
+	}
+	return true
+}
+
+// ColumnHelp returns the keys arranging the columns
+func (m *TableModel) ColumnHelp() []key.Binding {
+	return []key.Binding{
+		relabel(m.keyMap.Left, "previous column"), relabel(m.keyMap.Right, "next column"),
+		columnKeys.Sort, columnKeys.Hide, columnKeys.ShowAll,
+		columnKeys.Narrower, columnKeys.Wider, columnKeys.MoveLeft, columnKeys.MoveRight,
+	}
+}
+
+// focusedColumn returns the index in the headers of the column under the
+// column cursor
+func (m *TableModel) focusedColumn() (int, bool) {
+	if m.column < 0 || m.column >= len(m.columns) {
+		return 0, false
+	}
+	return m.columns[m.column], true
+}
+
+// HideColumn hides the column under the column cursor; the last shown
+// column cannot be hidden
+func (m *TableModel) HideColumn() {
+	if len(m.columns) <= 1 || m.column >= len(m.columns) {
+		return
+	}
+	m.columns = append(m.columns[:m.column], m.columns[m.column+1:]...)
+	if m.column >= len(m.columns) {
+		m.column = len(m.columns) - 1
+	}
+}
+
+// ShowAllColumns shows the hidden columns again after the shown ones
+func (m *TableModel) ShowAllColumns() {
+	shown := make(map[int]bool, len(m.columns))
+	for _, column := range m.columns {
+		shown[column] = true
+	}
+	for column := range m.headers {
+		if !shown[column] {
+			m.columns = append(m.columns, column)
+		}
+	}
+}
+
+// ResizeColumn widens the column under the column cursor by delta, or
+// narrows it for a negative delta
+func (m *TableModel) ResizeColumn(delta int) {
+	column, ok := m.focusedColumn()
+	if !ok {
+		return
+	}
+	width, ok := m.widths[column]
+	if !ok {
+		widths := m.calculateColumnWidths()
+		width = widths[m.column]
+	}
+	width += delta
+	if width < minColumnWidth {
+		width = minColumnWidth
+	}
+	m.widths[column] = width
+}
+
+// MoveColumn swaps the column under the column cursor with its neighbour,
+// to the right for a positive step and to the left for a negative one
+func (m *TableModel) MoveColumn(step int) {
+	target := m.column + step
+	if m.column >= len(m.columns) || target < 0 || target >= len(m.columns) {
+		return
+	}
+	m.columns[m.column], m.columns[target] = m.columns[target], m.columns[m.column]
+	m.column = target
+}
+
+// sortRows orders the rows by the sort column, keeping the selected row
+// under the cursor
+func (m *TableModel) sortRows() {
+	column, descending := m.sortBy, m.sortDesc
+	selected, hasSelection := m.SelectedRow()
+
+	sort.SliceStable(m.rows, func(i, j int) bool {
+		if column >= len(m.rows[i]) || column >= len(m.rows[j]) {
+			return false
+		}
+		if descending {
+			return compareCells(m.rows[i][column], m.rows[j][column]) > 0
+		}
+		return compareCells(m.rows[i][column], m.rows[j][column]) < 0
+	})
+
+	if hasSelection {
+		for i, row := range m.getFilteredRows() {
+			if strings.Join(row, "\x00") == strings.Join(selected, "\x00") {
+				m.selected = i
+				break
+			}
+		}
+	}
+}
+
+// compareCells orders two cells as IP addresses when both are, by the number
+// they start with when both do, such as an RTT of "12.5 ms" or a port, and
+// as text otherwise. Cells without a number, such as "*" for a timed out
+// hop, come after those with one.
+func compareCells(a, b string) int {
+	if ipA, ipB := net.ParseIP(a), net.ParseIP(b); ipA != nil && ipB != nil {
+		return bytes.Compare(ipA.To16(), ipB.To16())
+	}
+
+	numberA, okA := leadingNumber(a)
+	numberB, okB := leadingNumber(b)
+	switch {
+	case okA && okB:
+		switch {
+		case numberA < numberB:
+			return -1
+		case numberA > numberB:
+			return 1
+		}
+		return strings.Compare(a, b)
+	case okA:
+		return -1
+	case okB:
+		return 1
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// leadingNumber parses the number a cell starts with
+func leadingNumber(cell string) (float64, bool) {
+	cell = strings.TrimSpace(cell)
+	end := 0
+	for end < len(cell) && (cell[end] >= '0' && cell[end] <= '9' || cell[end] == '.' || end == 0 && cell[end] == '-') {
+		end++
+	}
+	number, err := strconv.ParseFloat(cell[:end], 64)
+	return number, err == nil
+}
+
+// Layout returns how the columns are arranged, sorted and sized, by header
+func (m *TableModel) Layout() session.TableLayout {
+	var layout session.TableLayout
+	shown := make(map[int]bool, len(m.columns))
+	for _, column := range m.columns {
+		shown[column] = true
+		layout.Order = append(layout.Order, m.headers[column])
+	}
+	for column, header := range m.headers {
+		if !shown[column] {
+			layout.Hidden = append(layout.Hidden, header)
+		}
+	}
+	for column, width := range m.widths {
+		if layout.Widths == nil {
+			layout.Widths = make(map[string]int)
+		}
+		layout.Widths[m.headers[column]] = width
+	}
+	if m.sortBy >= 0 && m.sortBy < len(m.headers) {
+		layout.SortColumn = m.headers[m.sortBy]
+		layout.Descending = m.sortDesc
+	}
+	return layout
+}
+
+// ApplyLayout arranges, sorts and sizes the columns as layout says. Columns
+// the layout does not name are shown after those it orders.
+func (m *TableModel) ApplyLayout(layout session.TableLayout) {
+	index := make(map[string]int, len(m.headers))
+	for column, header := range m.headers {
+		index[header] = column
+	}
+
+	hidden := make(map[int]bool)
+	for _, header := range layout.Hidden {
+		if column, ok := index[header]; ok {
+			hidden[column] = true
+		}
+	}
+	placed := make(map[int]bool)
+	columns := make([]int, 0, len(m.headers))
+	for _, header := range layout.Order {
+		if column, ok := index[header]; ok && !hidden[column] && !placed[column] {
+			columns = append(columns, column)
+			placed[column] = true
+		}
+	}
+	for column := range m.headers {
+		if !hidden[column] && !placed[column] {
+			columns = append(columns, column)
+		}
+	}
+	if len(columns) > 0 {
+		m.columns = columns
+		m.column = 0
+	}
+
+	m.widths = make(map[int]int)
+	for header, width := range layout.Widths {
+		if column, ok := index[header]; ok && width >= minColumnWidth {
+			m.widths[column] = width
+		}
+	}
+
+	if column, ok := index[layout.SortColumn]; ok && layout.SortColumn != "" {
+		m.SortBy(column, layout.Descending)
+	}
+}
+
+// TableLayouts keeps the layout of each tool's result table, saving them for
+// the next launch when it has a path
+type TableLayouts struct {
+	path    string
+	layouts session.Layouts
+}
+
+// NewTableLayouts creates the table layouts saved at path, starting from
+// saved; an empty path keeps them for this run only
+func NewTableLayouts(path string, saved session.Layouts) *TableLayouts {
+	if saved == nil {
+		saved = make(session.Layouts)
+	}
+	return &TableLayouts{path: path, layouts: saved}
+}
+
+// Get returns the table layout of tool; nil layouts have none
+func (l *TableLayouts) Get(tool string) (session.TableLayout, bool) {
+	if l == nil {
+		return session.TableLayout{}, false
+	}
+	layout, ok := l.layouts[tool]
+	return layout, ok
+}
+
+// Set keeps the table layout of tool and saves the layouts
+func (l *TableLayouts) Set(tool string, layout session.TableLayout) error {
+	l.layouts[tool] = layout
+	if l.path == "" {
+		return nil
+	}
+	return session.SaveLayouts(l.path, l.layouts)
+}
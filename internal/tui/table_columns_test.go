@@ -0,0 +1,121 @@
+package tui
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareCells(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"9.8 ms", "12.1 ms", -1},
+		{"443", "80", 1},
+		{"*", "1.0 ms", 1},
+		{"N/A", "N/A", 0},
+		{"10.0.0.2", "9.9.9.9", 1},
+		{"2001:db8::2", "2001:db8::10", -1},
+		{"alpha", "Beta", -1},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, compareCells(tt.a, tt.b), "%q vs %q", tt.a, tt.b)
+	}
+}
+
+func TestTableModel_ColumnKeys(t *testing.T) {
+	table := NewTableModel([]string{"Hop", "Hostname", "RTT"})
+	table.SetSize(80, 20)
+	table.SetData([][]string{
+		{"1", "router.lan", "1.2 ms"},
+		{"2", "*", "*"},
+		{"3", "edge.example.net", "12.5 ms"},
+		{"4", "core.example.net", "8.0 ms"},
+	})
+
+	// Sort by RTT, ascending then descending
+	typeKeys(table, "lls")
+	assert.Equal(t, []string{"1", "4", "3", "2"}, hopColumn(table))
+	assert.Contains(t, table.View(), "RTT ▲")
+	typeKeys(table, "s")
+	assert.Equal(t, []string{"2", "3", "4", "1"}, hopColumn(table))
+	assert.Contains(t, table.View(), "RTT ▼")
+
+	// Hide Hostname, move RTT first and widen it
+	typeKeys(table, "hx")
+	assert.NotContains(t, table.View(), "edge.example.net")
+	typeKeys(table, "[>>")
+	layout := table.Layout()
+	assert.Equal(t, []string{"RTT", "Hop"}, layout.Order)
+	assert.Equal(t, []string{"Hostname"}, layout.Hidden)
+	assert.Equal(t, "RTT", layout.SortColumn)
+	assert.True(t, layout.Descending)
+	require.Contains(t, layout.Widths, "RTT")
+
+	typeKeys(table, "X")
+	assert.Contains(t, table.View(), "edge.example.net")
+	assert.Empty(t, table.Layout().Hidden)
+}
+
+func TestTableModel_ApplyLayout(t *testing.T) {
+	table := NewTableModel([]string{"Hop", "Hostname", "RTT", "Status"})
+	table.SetData([][]string{{"1", "a", "5 ms", "ok"}, {"2", "b", "3 ms", "ok"}})
+
+	table.ApplyLayout(session.TableLayout{
+		Order:      []string{"RTT", "Removed", "Hop"},
+		Hidden:     []string{"Hostname"},
+		Widths:     map[string]int{"RTT": 12, "Hop": 1},
+		SortColumn: "RTT",
+	})
+	assert.Equal(t, []int{2, 0, 3}, table.columns, "unnamed columns follow the ordered ones")
+	assert.Equal(t, map[int]int{2: 12}, table.widths, "widths below the minimum are ignored")
+	assert.Equal(t, []string{"2", "1"}, hopColumn(table))
+}
+
+func TestResultViewModel_TableLayoutsPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), session.LayoutsFileName)
+	layouts := NewTableLayouts(path, nil)
+	hops := []domain.TraceHop{
+		{Number: 1, RTT: []time.Duration{20 * time.Millisecond}},
+		{Number: 2, RTT: []time.Duration{5 * time.Millisecond}},
+	}
+
+	model := NewResultViewModel()
+	model.SetSize(120, 30)
+	model.SetHistory(NewResultHistory(DefaultResultHistoryLimit), "traceroute")
+	model.SetTableLayouts(layouts)
+	model.SetResult(domain.NewResult(hops))
+	typeKeys(model, "t")
+	typeKeys(model, "lllls")
+	assert.Equal(t, []string{"2", "1"}, hopColumn(model.tableModel))
+
+	saved, err := session.LoadLayouts(path)
+	require.NoError(t, err)
+	assert.Equal(t, "RTT 1", saved["traceroute"].SortColumn, "changes are saved as they are made")
+
+	// The next result, even in a new run, comes sorted the same way
+	next := NewResultViewModel()
+	next.SetHistory(NewResultHistory(DefaultResultHistoryLimit), "traceroute")
+	next.SetTableLayouts(NewTableLayouts(path, saved))
+	next.SetResult(domain.NewResult(hops))
+	assert.Equal(t, "RTT 1", next.tableModel.Layout().SortColumn)
+	next.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	assert.Contains(t, ansi.Strip(next.View()), "RTT 1 ▲")
+}
+
+// hopColumn returns the first cell of each row as shown
+func hopColumn(table *TableModel) []string {
+	var cells []string
+	for _, row := range table.getFilteredRows() {
+		cells = append(cells, row[0])
+	}
+	return cells
+}
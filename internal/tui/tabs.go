@@ -0,0 +1,243 @@
+// Package tui contains the tabbed workspaces of the main model
+package tui
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
+)
+
+// teaPackage is the import path of Bubble Tea, whose own messages, such as
+// quitting or entering the alternate screen, must reach the program as is
+var teaPackage = reflect.TypeOf(tea.QuitMsg{}).PkgPath()
+
+// workspace is a tab with its own open screen. The state and screen of the
+// active tab live in MainModel.state and MainModel.activeView; they are
+// stored here while another tab is active.
+type workspace struct {
+	id    int
+	title string
+	state AppState
+	view  tea.Model
+}
+
+// tabMsg is a message produced by the screen of a tab, delivered to that
+// tab even when another one is active
+type tabMsg struct {
+	id  int
+	msg tea.Msg
+}
+
+// tagCmd marks the messages cmd produces as belonging to the tab id
+func tagCmd(id int, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		msg := cmd()
+		switch msg := msg.(type) {
+		case nil:
+			return nil
+		case tabMsg:
+			// Already addressed to a tab
+			return msg
+		case tea.BatchMsg:
+			cmds := make(tea.BatchMsg, len(msg))
+			for i, cmd := range msg {
+				cmds[i] = tagCmd(id, cmd)
+			}
+			return cmds
+		}
+		if reflect.TypeOf(msg).PkgPath() == teaPackage {
+			return msg
+		}
+		return tabMsg{id: id, msg: msg}
+	}
+}
+
+// Tabs returns the titles of the open tabs and the index of the active one
+func (m *MainModel) Tabs() ([]string, int) {
+	titles := make([]string, len(m.tabs))
+	for i, tab := range m.tabs {
+		titles[i] = tab.title
+		if i == m.activeTab {
+			titles[i] = m.tabTitle()
+		}
+	}
+	return titles, m.activeTab
+}
+
+// tabTitle names the screen open in the active tab
+func (m *MainModel) tabTitle() string {
+	switch m.state {
+	case StateMainMenu, StateNavigation:
+		return i18n.T("tab.menu")
+	case StateHelp:
+		return i18n.T("tab.help")
+	case StateSettings:
+		return i18n.T("tab.settings")
+	}
+	if diagnosticView, ok := m.activeView.(*DiagnosticViewModel); ok {
+		return diagnosticView.GetTool().Name()
+	}
+	return m.screenTitle
+}
+
+// storeTab keeps the state and screen of the active tab in its workspace
+func (m *MainModel) storeTab() {
+	tab := m.tabs[m.activeTab]
+	tab.title = m.tabTitle()
+	tab.state = m.state
+	tab.view = m.activeView
+}
+
+// switchTab makes the tab at index active
+func (m *MainModel) switchTab(index int) (*MainModel, tea.Cmd) {
+	if index < 0 || index >= len(m.tabs) || index == m.activeTab {
+		return m, nil
+	}
+	m.rememberForm()
+	m.storeTab()
+	m.activeTab = index
+	tab := m.tabs[index]
+	m.state = tab.state
+	m.activeView = tab.view
+	m.screenTitle = tab.title
+	if component, ok := m.activeView.(domain.TUIComponent); ok {
+		component.SetSize(m.width, m.height)
+		component.Focus()
+	}
+	return m, nil
+}
+
+// newTab opens a tab on the main menu after the active one
+func (m *MainModel) newTab() (*MainModel, tea.Cmd) {
+	m.rememberForm()
+	m.storeTab()
+	tab := &workspace{id: m.nextTabID, title: i18n.T("tab.menu"), state: StateMainMenu, view: m.navigation}
+	m.nextTabID++
+	m.activeTab++
+	m.tabs = append(m.tabs[:m.activeTab], append([]*workspace{tab}, m.tabs[m.activeTab:]...)...)
+	m.state = tab.state
+	m.activeView = tab.view
+	m.screenTitle = tab.title
+	m.navigation.Focus()
+	return m, nil
+}
+
+// closeTab closes the active tab and activates its neighbour. The last tab
+// cannot be closed; quit instead.
+func (m *MainModel) closeTab() (*MainModel, tea.Cmd) {
+	if len(m.tabs) < 2 {
+		return m, nil
+	}
+	m.rememberForm()
+	m.detachJob()
+	m.tabs = append(m.tabs[:m.activeTab], m.tabs[m.activeTab+1:]...)
+	if m.activeTab == len(m.tabs) {
+		m.activeTab--
+	}
+	tab := m.tabs[m.activeTab]
+	m.state = tab.state
+	m.activeView = tab.view
+	m.screenTitle = tab.title
+	if component, ok := m.activeView.(domain.TUIComponent); ok {
+		component.SetSize(m.width, m.height)
+		component.Focus()
+	}
+	return m, nil
+}
+
+// updateTabKey handles the keys that open, close and switch tabs
+func (m *MainModel) updateTabKey(msg tea.KeyMsg) (*MainModel, tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.keyMap.NewTab):
+		model, cmd := m.newTab()
+		return model, cmd, true
+	case key.Matches(msg, m.keyMap.CloseTab):
+		model, cmd := m.closeTab()
+		return model, cmd, true
+	case key.Matches(msg, m.keyMap.NextTab):
+		model, cmd := m.switchTab((m.activeTab + 1) % len(m.tabs))
+		return model, cmd, true
+	case key.Matches(msg, m.keyMap.PrevTab):
+		model, cmd := m.switchTab((m.activeTab + len(m.tabs) - 1) % len(m.tabs))
+		return model, cmd, true
+	}
+	if digit := strings.TrimPrefix(msg.String(), "alt+"); len(digit) == 1 && digit != msg.String() && digit >= "1" && digit <= "9" {
+		model, cmd := m.switchTab(int(digit[0] - '1'))
+		return model, cmd, true
+	}
+	return m, nil, false
+}
+
+// updateTab delivers a message produced by the screen of a background tab
+func (m *MainModel) updateTab(msg tabMsg) tea.Cmd {
+	for _, tab := range m.tabs {
+		if tab.id != msg.id {
+			continue
+		}
+		if exported, ok := msg.msg.(ResultExportedMsg); ok {
+			m.publishExport(exported)
+		}
+		var cmd tea.Cmd
+		tab.view, cmd = tab.view.Update(msg.msg)
+		return tagCmd(tab.id, cmd)
+	}
+	if cmd, finished, ok := m.jobs.update(msg.id, msg.msg); ok {
+		if finished != nil {
+			m.configStatus = fmt.Sprintf("⏳ background %s %s %s", finished.Tool, finished.Target, finished.State)
+		}
+		return tagCmd(msg.id, cmd)
+	}
+	// The tab or job was closed
+	return nil
+}
+
+// backgroundViews returns the screens open in tabs other than the active one
+func (m *MainModel) backgroundViews() []tea.Model {
+	var views []tea.Model
+	for i, tab := range m.tabs {
+		if i != m.activeTab && tab.view != nil && tab.view != m.activeView {
+			views = append(views, tab.view)
+		}
+	}
+	return views
+}
+
+// renderTabBar lists the open tabs, highlighting the active one. Nothing is
+// shown while a single tab is open.
+func (m *MainModel) renderTabBar() string {
+	if len(m.tabs) < 2 {
+		return ""
+	}
+	return lipgloss.NewStyle().Width(m.width).Render(lipgloss.JoinHorizontal(lipgloss.Top, m.renderTabLabels()...))
+}
+
+// renderTabLabels renders the label of each tab as shown on the tab bar
+func (m *MainModel) renderTabLabels() []string {
+	activeStyle := lipgloss.NewStyle().Padding(0, 1).Bold(true).
+		Background(colors.Primary).
+		Foreground(colors.Highlight)
+	inactiveStyle := lipgloss.NewStyle().Padding(0, 1).
+		Foreground(colors.Adaptive("245"))
+
+	titles, active := m.Tabs()
+	rendered := make([]string, len(titles))
+	for i, title := range titles {
+		label := fmt.Sprintf("%d %s", i+1, title)
+		if i == active {
+			rendered[i] = activeStyle.Render(label)
+		} else {
+			rendered[i] = inactiveStyle.Render(label)
+		}
+	}
+	return rendered
+}
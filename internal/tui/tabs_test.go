@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	configpkg "github.com/nettracex/nettracex-tui/internal/config"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runTabCmd runs cmd, expanding batches, and returns the messages it produced
+func runTabCmd(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, cmd := range batch {
+			msgs = append(msgs, runTabCmd(cmd)...)
+		}
+		return msgs
+	}
+	if msg == nil {
+		return nil
+	}
+	return []tea.Msg{msg}
+}
+
+func newTabsModel(t *testing.T) *MainModel {
+	ping := &dashboardTool{name: "ping", data: []domain.PingResult{{RTT: 3 * time.Millisecond}}}
+	config := &domain.Config{UI: domain.UIConfig{Dashboard: domain.DashboardConfig{Hosts: []string{"router.lan"}}}}
+	model := NewMainModel(newDashboardRegistry(t, ping), config, configpkg.NewManager(), nil)
+	model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	return model
+}
+
+func TestMainModel_Tabs(t *testing.T) {
+	model := newTabsModel(t)
+	titles, active := model.Tabs()
+	assert.Equal(t, []string{"Menu"}, titles)
+	assert.Equal(t, 0, active)
+	assert.NotContains(t, model.View(), "1 Menu", "no tab bar with a single tab")
+
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	model.Update(NavigationMsg{Action: NavigationActionSelect, Data: NavigationItem{ID: "dashboard", Title: "Dashboard"}})
+	titles, active = model.Tabs()
+	assert.Equal(t, []string{"Menu", "Dashboard"}, titles)
+	assert.Equal(t, 1, active)
+	view := model.View()
+	assert.Contains(t, view, "1 Menu")
+	assert.Contains(t, view, "2 Dashboard")
+
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlPgUp})
+	_, active = model.Tabs()
+	assert.Equal(t, 0, active)
+	assert.Equal(t, StateMainMenu, model.state)
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2"), Alt: true})
+	_, active = model.Tabs()
+	assert.Equal(t, 1, active)
+	assert.IsType(t, &DashboardViewModel{}, model.activeView)
+
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	titles, _ = model.Tabs()
+	assert.Equal(t, []string{"Menu"}, titles)
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	titles, _ = model.Tabs()
+	assert.Len(t, titles, 1, "the last tab stays open")
+}
+
+func TestMainModel_TabMessagesReachTheirTab(t *testing.T) {
+	model := newTabsModel(t)
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	_, cmd := model.Update(NavigationMsg{Action: NavigationActionSelect, Data: NavigationItem{ID: "dashboard", Title: "Dashboard"}})
+	dashboard := model.activeView.(*DashboardViewModel)
+	require.NotNil(t, cmd)
+
+	// The ping finishes while the first tab is active
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlPgDown})
+	assert.Equal(t, StateMainMenu, model.state)
+	msgs := runTabCmd(cmd)
+	require.Len(t, msgs, 1)
+	require.IsType(t, tabMsg{}, msgs[0])
+	model.Update(msgs[0])
+
+	assert.Equal(t, StateMainMenu, model.state)
+	require.Len(t, dashboard.Statuses(domain.DashboardWidgetHosts), 1)
+	assert.True(t, dashboard.Statuses(domain.DashboardWidgetHosts)[0].OK)
+
+	// Messages of closed tabs are dropped
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlPgDown})
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	_, cmd = model.Update(tabMsg{id: msgs[0].(tabMsg).id, msg: msgs[0].(tabMsg).msg})
+	assert.Nil(t, cmd)
+}
+
+func TestMainModel_BackClosesTab(t *testing.T) {
+	model := newTabsModel(t)
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Nil(t, cmd)
+	assert.False(t, model.quitting)
+	titles, _ := model.Tabs()
+	assert.Len(t, titles, 1)
+}
+
+func TestTagCmd(t *testing.T) {
+	assert.Nil(t, tagCmd(1, nil))
+	assert.Equal(t, tea.QuitMsg{}, tagCmd(1, tea.Quit)(), "program messages pass through")
+	assert.Equal(t, tabMsg{id: 1, msg: "done"}, tagCmd(1, func() tea.Msg { return "done" })())
+
+	nested := tagCmd(2, tagCmd(1, func() tea.Msg { return "done" }))
+	assert.Equal(t, tabMsg{id: 1, msg: "done"}, nested(), "the innermost tab wins")
+}
@@ -3,6 +3,7 @@ package tui
 
 import (
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
 	"github.com/nettracex/nettracex-tui/internal/domain"
 )
 
@@ -28,45 +29,51 @@ func NewDefaultTheme() *DefaultTheme {
 		"highlight":   "230",  // White
 	}
 
+	// Styles name the colors they use, so changing a color restyles them
 	styles := map[string]map[string]interface{}{
 		"header": {
-			"background": colors["primary"],
-			"foreground": colors["highlight"],
+			"background": "primary",
+			"foreground": "highlight",
 			"bold":       true,
 			"padding":    "0 1",
 		},
 		"footer": {
-			"background": colors["border"],
-			"foreground": colors["foreground"],
+			"background": "border",
+			"foreground": "foreground",
 			"padding":    "0 1",
 		},
+		"panel": {
+			"border":            "rounded",
+			"border_foreground": "primary",
+			"padding":           "0 1",
+		},
 		"menu_item": {
 			"padding": "0 2",
 		},
 		"menu_item_selected": {
-			"background": colors["primary"],
-			"foreground": colors["highlight"],
+			"background": "primary",
+			"foreground": "highlight",
 			"bold":       true,
 		},
 		"menu_item_disabled": {
-			"foreground": colors["border"],
+			"foreground": "border",
 		},
 		"form_label": {
 			"bold": true,
 		},
 		"form_input": {
 			"border":           "rounded",
-			"border_foreground": colors["border"],
+			"border_foreground": "border",
 			"padding":          "0 1",
 		},
 		"form_input_focused": {
 			"border":           "rounded",
-			"border_foreground": colors["primary"],
+			"border_foreground": "primary",
 			"padding":          "0 1",
 		},
 		"table_header": {
-			"background": colors["primary"],
-			"foreground": colors["highlight"],
+			"background": "primary",
+			"foreground": "highlight",
 			"bold":       true,
 			"padding":    "0 1",
 		},
@@ -74,28 +81,28 @@ func NewDefaultTheme() *DefaultTheme {
 			"padding": "0 1",
 		},
 		"table_row_selected": {
-			"background": colors["primary"],
-			"foreground": colors["highlight"],
+			"background": "primary",
+			"foreground": "highlight",
 		},
 		"progress_bar": {
-			"foreground": colors["primary"],
-			"background": colors["border"],
+			"foreground": "primary",
+			"background": "border",
 		},
 		"error": {
-			"foreground": colors["error"],
+			"foreground": "error",
 			"italic":     true,
 		},
 		"success": {
-			"foreground": colors["success"],
+			"foreground": "success",
 		},
 		"warning": {
-			"foreground": colors["warning"],
+			"foreground": "warning",
 		},
 		"info": {
-			"foreground": colors["info"],
+			"foreground": "info",
 		},
 		"muted": {
-			"foreground": colors["muted"],
+			"foreground": "muted",
 			"italic":     true,
 		},
 	}
@@ -114,12 +121,27 @@ func (t *DefaultTheme) GetColor(element string) string {
 	return t.colors["foreground"] // Default color
 }
 
-// GetStyle implements domain.Theme
+// colorProperties are the style properties that take a color, or the name
+// of a color of the theme
+var colorProperties = map[string]bool{
+	"background":        true,
+	"foreground":        true,
+	"border_foreground": true,
+}
+
+// GetStyle implements domain.Theme, with the color names of the style
+// replaced by the colors of the theme
 func (t *DefaultTheme) GetStyle(element string) map[string]interface{} {
-	if style, exists := t.styles[element]; exists {
-		return style
+	style := make(map[string]interface{})
+	for property, value := range t.styles[element] {
+		if name, ok := value.(string); ok && colorProperties[property] {
+			if color, exists := t.colors[name]; exists {
+				value = color
+			}
+		}
+		style[property] = value
 	}
-	return make(map[string]interface{}) // Empty style
+	return style
 }
 
 // SetColor implements domain.Theme
@@ -129,15 +151,20 @@ func (t *DefaultTheme) SetColor(element, color string) {
 
 // GetLipglossStyle returns a lipgloss.Style for the given element
 func (t *DefaultTheme) GetLipglossStyle(element string) lipgloss.Style {
+	return themeStyle(t, element)
+}
+
+// themeStyle returns the style of element in theme as a lipgloss.Style
+func themeStyle(theme domain.Theme, element string) lipgloss.Style {
 	style := lipgloss.NewStyle()
-	styleMap := t.GetStyle(element)
+	styleMap := theme.GetStyle(element)
 
 	// Apply style properties
 	if bg, ok := styleMap["background"].(string); ok {
-		style = style.Background(lipgloss.Color(bg))
+		style = style.Background(colors.Adaptive(bg))
 	}
 	if fg, ok := styleMap["foreground"].(string); ok {
-		style = style.Foreground(lipgloss.Color(fg))
+		style = style.Foreground(colors.Adaptive(fg))
 	}
 	if bold, ok := styleMap["bold"].(bool); ok && bold {
 		style = style.Bold(true)
@@ -145,27 +172,35 @@ func (t *DefaultTheme) GetLipglossStyle(element string) lipgloss.Style {
 	if italic, ok := styleMap["italic"].(bool); ok && italic {
 		style = style.Italic(true)
 	}
+	if underline, ok := styleMap["underline"].(bool); ok && underline {
+		style = style.Underline(true)
+	}
 	if _, ok := styleMap["padding"].(string); ok {
 		// Parse padding string (e.g., "0 1" -> top/bottom=0, left/right=1)
 		style = style.Padding(0, 1) // Simplified for now
 	}
 	if border, ok := styleMap["border"].(string); ok {
-		switch border {
-		case "rounded":
-			style = style.Border(lipgloss.RoundedBorder())
-		case "normal":
-			style = style.Border(lipgloss.NormalBorder())
-		case "thick":
-			style = style.Border(lipgloss.ThickBorder())
+		if b, known := borders[border]; known && border != "none" {
+			style = style.Border(b)
 		}
 	}
 	if borderFg, ok := styleMap["border_foreground"].(string); ok {
-		style = style.BorderForeground(lipgloss.Color(borderFg))
+		style = style.BorderForeground(colors.Adaptive(borderFg))
 	}
 
 	return style
 }
 
+// borders are the border styles a theme can draw boxes with
+var borders = map[string]lipgloss.Border{
+	"rounded": lipgloss.RoundedBorder(),
+	"normal":  lipgloss.NormalBorder(),
+	"thick":   lipgloss.ThickBorder(),
+	"double":  lipgloss.DoubleBorder(),
+	"hidden":  lipgloss.HiddenBorder(),
+	"none":    {},
+}
+
 // DarkTheme creates a dark theme variant
 type DarkTheme struct {
 	*DefaultTheme
@@ -199,29 +234,157 @@ func NewLightTheme() *LightTheme {
 	base.colors["muted"] = "8"         // Gray
 	base.colors["border"] = "7"        // Light Gray
 	base.colors["primary"] = "4"       // Blue
+	base.colors["secondary"] = "125"   // Dark Pink
+	base.colors["success"] = "28"      // Dark Green
+	base.colors["warning"] = "130"     // Dark Orange
+	base.colors["error"] = "160"       // Dark Red
+	base.colors["info"] = "25"         // Dark Blue
+	base.colors["highlight"] = "15"    // White
+	
+	// Black footer text on the light gray border color
+	base.styles["footer"]["foreground"] = "foreground"
 	
 	return &LightTheme{DefaultTheme: base}
 }
 
+// SolarizedTheme creates a theme with the Solarized dark palette
+type SolarizedTheme struct {
+	*DefaultTheme
+}
+
+// NewSolarizedTheme creates a new solarized theme
+func NewSolarizedTheme() *SolarizedTheme {
+	base := NewDefaultTheme()
+	
+	base.colors["primary"] = "#268bd2"    // Blue
+	base.colors["secondary"] = "#d33682"  // Magenta
+	base.colors["success"] = "#859900"    // Green
+	base.colors["warning"] = "#b58900"    // Yellow
+	base.colors["error"] = "#dc322f"      // Red
+	base.colors["info"] = "#2aa198"       // Cyan
+	base.colors["background"] = "#002b36" // Base03
+	base.colors["foreground"] = "#839496" // Base0
+	base.colors["muted"] = "#586e75"      // Base01
+	base.colors["border"] = "#073642"     // Base02
+	base.colors["highlight"] = "#fdf6e3"  // Base3
+	
+	return &SolarizedTheme{DefaultTheme: base}
+}
+
+// HighContrastTheme creates a theme of bright colors and thick borders
+type HighContrastTheme struct {
+	*DefaultTheme
+}
+
+// NewHighContrastTheme creates a new high contrast theme
+func NewHighContrastTheme() *HighContrastTheme {
+	base := NewDefaultTheme()
+	
+	base.colors["primary"] = "11"    // Bright Yellow
+	base.colors["secondary"] = "14"  // Bright Cyan
+	base.colors["success"] = "10"    // Bright Green
+	base.colors["warning"] = "11"    // Bright Yellow
+	base.colors["error"] = "9"       // Bright Red
+	base.colors["info"] = "14"       // Bright Cyan
+	base.colors["background"] = "0"  // Black
+	base.colors["foreground"] = "15" // White
+	base.colors["muted"] = "7"       // Light Gray
+	base.colors["border"] = "15"     // White
+	base.colors["highlight"] = "0"   // Black
+	base.setBorder("thick")
+	
+	// Black text on the white footer and bold selections
+	base.styles["footer"]["foreground"] = "highlight"
+	base.styles["table_row_selected"]["bold"] = true
+	base.styles["muted"]["italic"] = false
+	
+	return &HighContrastTheme{DefaultTheme: base}
+}
+
+// MinimalTheme creates a monochrome theme variant
+type MinimalTheme struct {
+	*DefaultTheme
+}
+
+// NewMinimalTheme creates a new minimal theme with plain borders
+func NewMinimalTheme() *MinimalTheme {
+	base := NewDefaultTheme()
+	
+	// Shades of gray instead of colors
+	base.colors["primary"] = "240"
+	base.colors["secondary"] = "250"
+	base.colors["info"] = "250"
+	base.colors["highlight"] = "255"
+	base.setBorder("normal")
+	
+	return &MinimalTheme{DefaultTheme: base}
+}
+
+// setBorder draws every bordered style of the theme with border
+func (t *DefaultTheme) setBorder(border string) {
+	for _, style := range t.styles {
+		if _, ok := style["border"]; ok {
+			style["border"] = border
+		}
+	}
+}
+
+// clone returns a copy of the theme that can be changed on its own
+func (t *DefaultTheme) clone() *DefaultTheme {
+	colors := make(map[string]string, len(t.colors))
+	for name, color := range t.colors {
+		colors[name] = color
+	}
+	styles := make(map[string]map[string]interface{}, len(t.styles))
+	for element, style := range t.styles {
+		styles[element] = make(map[string]interface{}, len(style))
+		for property, value := range style {
+			styles[element][property] = value
+		}
+	}
+	return &DefaultTheme{colors: colors, styles: styles}
+}
+
 // ThemeManager manages theme switching and application
 type ThemeManager struct {
-	themes      map[string]domain.Theme
-	currentName string
-	current     domain.Theme
+	themes         map[string]domain.Theme
+	currentName    string
+	current        domain.Theme
+	darkBackground bool
 }
 
 // NewThemeManager creates a new theme manager
 func NewThemeManager() *ThemeManager {
 	themes := map[string]domain.Theme{
-		"default": NewDefaultTheme(),
-		"dark":    NewDarkTheme(),
-		"light":   NewLightTheme(),
+		"default":       NewDefaultTheme(),
+		"dark":          NewDarkTheme(),
+		"light":         NewLightTheme(),
+		"minimal":       NewMinimalTheme(),
+		"solarized":     NewSolarizedTheme(),
+		"high-contrast": NewHighContrastTheme(),
 	}
 
 	return &ThemeManager{
-		themes:      themes,
-		currentName: "default",
-		current:     themes["default"],
+		themes:         themes,
+		currentName:    "default",
+		current:        themes["default"],
+		darkBackground: true,
+	}
+}
+
+// DetectBackground asks the terminal for its background color (OSC 11),
+// falling back to COLORFGBG, for the auto theme. It reads the answer from
+// the terminal, so it must run before the TUI does.
+func (tm *ThemeManager) DetectBackground() {
+	tm.SetDarkBackground(lipgloss.HasDarkBackground())
+}
+
+// SetDarkBackground sets whether the terminal background is dark, which
+// picks the dark or the light theme for the auto theme
+func (tm *ThemeManager) SetDarkBackground(dark bool) {
+	tm.darkBackground = dark
+	if tm.currentName == domain.ThemeAuto {
+		tm.SetTheme(domain.ThemeAuto)
 	}
 }
 
@@ -232,7 +395,14 @@ func (tm *ThemeManager) GetTheme() domain.Theme {
 
 // SetTheme sets the current theme by name
 func (tm *ThemeManager) SetTheme(name string) bool {
-	if theme, exists := tm.themes[name]; exists {
+	theme, exists := tm.themes[name]
+	if name == domain.ThemeAuto {
+		theme, exists = tm.themes["light"]
+		if tm.darkBackground {
+			theme, exists = tm.themes["dark"]
+		}
+	}
+	if exists {
 		tm.currentName = name
 		tm.current = theme
 		return true
@@ -247,7 +417,7 @@ func (tm *ThemeManager) GetCurrentThemeName() string {
 
 // GetAvailableThemes returns a list of available theme names
 func (tm *ThemeManager) GetAvailableThemes() []string {
-	var names []string
+	names := []string{domain.ThemeAuto}
 	for name := range tm.themes {
 		names = append(names, name)
 	}
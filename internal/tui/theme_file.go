@@ -0,0 +1,213 @@
+// Package tui contains the theme files read from the configuration directory
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+
+	configpkg "github.com/nettracex/nettracex-tui/internal/config"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// themeFile is a theme read from a YAML file of the themes directory, e.g.
+//
+//	extends: dark
+//	colors:
+//	  primary: "#5fafff"
+//	border: double
+//	styles:
+//	  header: {underline: true}
+//	  error: {bold: true, italic: false}
+//
+// It changes the colors, the border of every box and the emphasis of single
+// elements of the theme it extends, the default theme unless named.
+type themeFile struct {
+	Extends string                            `yaml:"extends"`
+	Colors  map[string]string                 `yaml:"colors"`
+	Border  string                            `yaml:"border"`
+	Styles  map[string]map[string]interface{} `yaml:"styles"`
+}
+
+// hexColor matches #rgb and #rrggbb colors
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validColor reports whether color is an ANSI color number or a hex color
+func validColor(color string) bool {
+	if n, err := strconv.Atoi(color); err == nil {
+		return n >= 0 && n <= 255
+	}
+	return hexColor.MatchString(color)
+}
+
+// apply returns a copy of base changed as the file says
+func (f *themeFile) apply(base *DefaultTheme) (*DefaultTheme, error) {
+	theme := base.clone()
+	for name, color := range f.Colors {
+		if !validColor(color) {
+			return nil, fmt.Errorf("color %s: %q is neither an ANSI color 0-255 nor a #rrggbb color", name, color)
+		}
+		theme.colors[name] = color
+	}
+
+	if f.Border != "" {
+		if _, ok := borders[f.Border]; !ok {
+			return nil, fmt.Errorf("unknown border %q, use rounded, normal, thick, double, hidden or none", f.Border)
+		}
+		theme.setBorder(f.Border)
+	}
+
+	for element, properties := range f.Styles {
+		style := theme.styles[element]
+		if style == nil {
+			style = make(map[string]interface{})
+			theme.styles[element] = style
+		}
+		for property, value := range properties {
+			if err := checkStyleProperty(theme, property, value); err != nil {
+				return nil, fmt.Errorf("style %s: %w", element, err)
+			}
+			style[property] = value
+		}
+	}
+	return theme, nil
+}
+
+// checkStyleProperty reports a style property a theme cannot apply
+func checkStyleProperty(theme *DefaultTheme, property string, value interface{}) error {
+	switch property {
+	case "bold", "italic", "underline":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s must be true or false", property)
+		}
+	case "background", "foreground", "border_foreground":
+		color, ok := value.(string)
+		if _, named := theme.colors[color]; !ok || (!named && !validColor(color)) {
+			return fmt.Errorf("%s must be a color or the name of one of the colors", property)
+		}
+	case "border":
+		border, ok := value.(string)
+		if _, known := borders[border]; !ok || !known {
+			return fmt.Errorf("unknown border %v", value)
+		}
+	case "padding":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("padding must be a string such as \"0 1\"")
+		}
+	default:
+		return fmt.Errorf("unknown property %q", property)
+	}
+	return nil
+}
+
+// builtinTheme returns a new copy of the built-in theme called name, or nil
+func builtinTheme(name string) *DefaultTheme {
+	switch name {
+	case "default":
+		return NewDefaultTheme()
+	case "dark":
+		return NewDarkTheme().DefaultTheme
+	case "light":
+		return NewLightTheme().DefaultTheme
+	case "minimal":
+		return NewMinimalTheme().DefaultTheme
+	case "solarized":
+		return NewSolarizedTheme().DefaultTheme
+	case "high-contrast":
+		return NewHighContrastTheme().DefaultTheme
+	}
+	return nil
+}
+
+// themeLoader reads the theme files of a directory, each once, following
+// what they extend
+type themeLoader struct {
+	files   map[string]string
+	loaded  map[string]*DefaultTheme
+	loading map[string]bool
+}
+
+// load returns the theme of the file called name
+func (l *themeLoader) load(name string) (*DefaultTheme, error) {
+	if theme, ok := l.loaded[name]; ok {
+		return theme, nil
+	}
+	if l.loading[name] {
+		return nil, fmt.Errorf("theme %s extends itself", name)
+	}
+	l.loading[name] = true
+	defer delete(l.loading, name)
+
+	path := l.files[name]
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read theme file: %w", err)
+	}
+	var file themeFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	base, err := l.base(name, file.Extends)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	theme, err := file.apply(base)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	l.loaded[name] = theme
+	return theme, nil
+}
+
+// base returns the theme the file called name extends. A file named after
+// a built-in theme may extend it to change it.
+func (l *themeLoader) base(name, extends string) (*DefaultTheme, error) {
+	if extends == "" {
+		extends = "default"
+	}
+	if _, ok := l.files[extends]; ok && extends != name {
+		return l.load(extends)
+	}
+	if theme := builtinTheme(extends); theme != nil {
+		return theme, nil
+	}
+	return nil, fmt.Errorf("extends unknown theme %q", extends)
+}
+
+// LoadDir registers the themes of the theme files in dir, replacing those
+// read before and the built-in themes of the same name. It returns the
+// errors of the files that could not be read; their themes are left as
+// they were.
+func (tm *ThemeManager) LoadDir(dir string) []error {
+	files := configpkg.ThemeFiles(dir)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	loader := &themeLoader{files: files, loaded: make(map[string]*DefaultTheme), loading: make(map[string]bool)}
+	var errs []error
+	for _, name := range names {
+		if name == domain.ThemeAuto {
+			errs = append(errs, fmt.Errorf("%s: %s names the theme picked for the terminal background", files[name], domain.ThemeAuto))
+			continue
+		}
+		theme, err := loader.load(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		tm.RegisterTheme(name, theme)
+	}
+	tm.SetTheme(tm.currentName)
+	return errs
+}
@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	configpkg "github.com/nettracex/nettracex-tui/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeThemes writes theme files named after the keys of files into dir
+func writeThemes(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+}
+
+func TestDefaultTheme_StylesFollowColors(t *testing.T) {
+	theme := NewDefaultTheme()
+	theme.SetColor("primary", "33")
+	assert.Equal(t, "33", theme.GetStyle("header")["background"])
+	assert.Equal(t, "33", theme.GetStyle("panel")["border_foreground"])
+
+	minimal := NewMinimalTheme()
+	assert.Equal(t, "normal", minimal.GetStyle("panel")["border"])
+}
+
+func TestThemeManager_AutoTheme(t *testing.T) {
+	manager := NewThemeManager()
+	assert.Contains(t, manager.GetAvailableThemes(), "auto")
+	assert.Contains(t, manager.GetAvailableThemes(), "solarized")
+	assert.Contains(t, manager.GetAvailableThemes(), "high-contrast")
+
+	require.True(t, manager.SetTheme("auto"))
+	assert.Equal(t, "auto", manager.GetCurrentThemeName())
+	assert.Equal(t, "15", manager.GetTheme().GetColor("foreground"), "dark until told otherwise")
+
+	manager.SetDarkBackground(false)
+	assert.Equal(t, "0", manager.GetTheme().GetColor("foreground"), "light terminals get the light theme")
+
+	// Theme files replacing the light theme are picked too
+	dir := t.TempDir()
+	writeThemes(t, dir, map[string]string{"light.yaml": "extends: light\ncolors: {primary: \"18\"}\n", "auto.yaml": ""})
+	errs := manager.LoadDir(dir)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "terminal background")
+	assert.Equal(t, "18", manager.GetTheme().GetColor("primary"))
+}
+
+func TestNewHighContrastTheme(t *testing.T) {
+	theme := NewHighContrastTheme()
+	footer := theme.GetStyle("footer")
+	assert.NotEqual(t, footer["background"], footer["foreground"])
+	assert.Equal(t, "thick", theme.GetStyle("panel")["border"])
+
+	light := NewLightTheme()
+	assert.Equal(t, "0", light.GetStyle("footer")["foreground"])
+}
+
+func TestThemeManager_LoadDir(t *testing.T) {
+	dir := t.TempDir()
+	writeThemes(t, dir, map[string]string{
+		"ocean.yaml": `extends: dark
+colors:
+  primary: "#5fafff"
+border: double
+styles:
+  header: {underline: true, foreground: secondary}
+`,
+		"reef.yaml":  "extends: ocean\ncolors: {secondary: \"214\"}\n",
+		"dark.yaml":  "extends: dark\ncolors: {muted: \"245\"}\n",
+		"bad.yaml":   "styles:\n  header: {blink: true}\n",
+		"color.yaml": "colors: {primary: blue}\n",
+		"loop.yaml":  "extends: loop2\n",
+		"loop2.yaml": "extends: loop\n",
+	})
+
+	manager := NewThemeManager()
+	manager.SetTheme("dark")
+	errs := manager.LoadDir(dir)
+	require.Len(t, errs, 4)
+	assert.Contains(t, errs[0].Error(), `unknown property "blink"`)
+	assert.Contains(t, errs[1].Error(), "neither an ANSI color")
+	assert.Contains(t, errs[2].Error(), "extends itself")
+
+	require.True(t, manager.SetTheme("ocean"))
+	ocean := manager.GetTheme()
+	assert.Equal(t, "#5fafff", ocean.GetColor("primary"))
+	assert.Equal(t, "15", ocean.GetColor("foreground"), "colors of dark are kept")
+	header := ocean.GetStyle("header")
+	assert.Equal(t, "#5fafff", header["background"])
+	assert.Equal(t, "205", header["foreground"])
+	assert.Equal(t, true, header["underline"])
+	assert.Equal(t, true, header["bold"])
+	assert.Equal(t, "double", ocean.GetStyle("form_input")["border"])
+
+	require.True(t, manager.SetTheme("reef"))
+	assert.Equal(t, "214", manager.GetTheme().GetStyle("header")["foreground"])
+	assert.Equal(t, "double", manager.GetTheme().GetStyle("panel")["border"])
+
+	require.True(t, manager.SetTheme("dark"))
+	assert.Equal(t, "245", manager.GetTheme().GetColor("muted"), "a file may change a built-in theme")
+	assert.False(t, manager.SetTheme("bad"))
+
+	assert.Empty(t, manager.LoadDir(filepath.Join(dir, "missing")))
+}
+
+func TestMainModel_ThemePreview(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "nettracex.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("ui:\n  theme: default\n"), 0644))
+	configManager := configpkg.NewManager()
+	require.NoError(t, configManager.LoadFromFile(configFile))
+
+	model := NewMainModel(newDashboardRegistry(t), configManager.GetConfig(), configManager, nil)
+	model.SetThemeManager(NewThemeManager())
+
+	// Theme files added while the TUI runs are read when first used
+	writeThemes(t, filepath.Join(dir, "themes"), map[string]string{"ocean.yaml": "colors: {primary: \"33\"}\n"})
+	model.Update(configpkg.ThemePreviewMsg{Theme: "ocean"})
+	assert.Equal(t, "ocean", model.themes.GetCurrentThemeName())
+	assert.Equal(t, "33", model.theme.GetColor("primary"))
+
+	model.Update(configpkg.ThemePreviewMsg{Theme: "missing"})
+	assert.Equal(t, "ocean", model.themes.GetCurrentThemeName())
+
+	model.Update(configpkg.ThemePreviewMsg{Theme: "light"})
+	assert.Equal(t, "4", model.theme.GetColor("primary"))
+}
@@ -0,0 +1,10 @@
+package tui
+
+// UpdateAvailableMsg announces a newer release in the header; send it to the
+// program when a check for updates finds one
+type UpdateAvailableMsg struct {
+	// Version is the tag of the release, e.g. v1.3.0
+	Version string
+	// URL is the page of the release
+	URL string
+}
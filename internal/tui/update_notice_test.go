@@ -0,0 +1,24 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	configpkg "github.com/nettracex/nettracex-tui/internal/config"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/plugins"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMainModel_UpdateAvailable(t *testing.T) {
+	model := NewMainModel(plugins.NewRegistry(nil), &domain.Config{}, configpkg.NewManager(), nil)
+	model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	assert.NotContains(t, model.View(), "nettracex update")
+
+	model.Update(UpdateAvailableMsg{Version: "v1.3.0", URL: "https://github.com/nettracex/nettracex-tui/releases/tag/v1.3.0"})
+	assert.Contains(t, model.View(), "⬆ v1.3.0 available, run nettracex update")
+
+	// The notice stays on other screens
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	assert.Contains(t, model.View(), "v1.3.0 available")
+}
@@ -0,0 +1,216 @@
+// Package tui contains the vi-style motions of lists, tables and pagers
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// viMotion is a jump requested through the vi keys
+type viMotion int
+
+const (
+	viNone viMotion = iota
+	viTop
+	viBottom
+	viNextMatch
+	viPrevMatch
+)
+
+// viKeys interprets the vi keys shared by the lists, tables and pagers: gg
+// and G jump to the top and bottom, / searches and n and N repeat the search
+// forwards and backwards. It does nothing unless vi mode is on.
+type viKeys struct {
+	pendingG  bool
+	searching bool
+	input     string
+	query     string
+}
+
+// update handles a key, reporting the motion it completes and whether the
+// key was consumed
+func (v *viKeys) update(msg tea.KeyMsg, keyMap KeyMap) (viMotion, bool) {
+	if !keyMap.ViMode {
+		return viNone, false
+	}
+
+	if v.searching {
+		switch msg.Type {
+		case tea.KeyEsc:
+			v.searching = false
+		case tea.KeyEnter:
+			v.searching = false
+			if v.input != "" {
+				v.query = v.input
+				return viNextMatch, true
+			}
+		case tea.KeyBackspace:
+			if v.input == "" {
+				v.searching = false
+			} else {
+				runes := []rune(v.input)
+				v.input = string(runes[:len(runes)-1])
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			v.input += string(msg.Runes)
+		}
+		return viNone, true
+	}
+
+	pendingG := v.pendingG
+	v.pendingG = false
+	switch msg.String() {
+	case "g":
+		if pendingG {
+			return viTop, true
+		}
+		v.pendingG = true
+		return viNone, true
+	case "G":
+		return viBottom, true
+	case "/":
+		v.searching = true
+		v.input = ""
+		return viNone, true
+	case "n":
+		if v.query != "" {
+			return viNextMatch, true
+		}
+	case "N":
+		if v.query != "" {
+			return viPrevMatch, true
+		}
+	}
+	return viNone, false
+}
+
+// Searching reports whether the search prompt is open and needs every key
+func (v *viKeys) Searching() bool {
+	return v.searching
+}
+
+// prompt renders the open search prompt, or nothing
+func (v *viKeys) prompt() string {
+	if !v.searching {
+		return ""
+	}
+	return lipgloss.NewStyle().Bold(true).Render("/" + v.input + "█")
+}
+
+// viFind returns the index of the next text containing query after from, or
+// before it when backwards, wrapping around; -1 when none does
+func viFind(texts []string, query string, from int, backwards bool) int {
+	query = strings.ToLower(query)
+	count := len(texts)
+	for step := 1; step <= count; step++ {
+		i := from + step
+		if backwards {
+			i = from - step
+		}
+		i = ((i % count) + count) % count
+		if strings.Contains(strings.ToLower(texts[i]), query) {
+			return i
+		}
+	}
+	return -1
+}
+
+// searchableItem is a scrollable item with text for the / search
+type searchableItem interface {
+	SearchText() string
+}
+
+// lineJumper is implemented by views that can select a line by number
+type lineJumper interface {
+	JumpTo(line int)
+}
+
+// viCommandLine is the text typed after : in vi mode
+type viCommandLine struct {
+	input string
+}
+
+// prompt renders the command line
+func (c *viCommandLine) prompt() string {
+	return lipgloss.NewStyle().Bold(true).Render(":" + c.input + "█")
+}
+
+// acceptsCommand reports whether : opens the command line, which it never
+// does while a form or the settings take text
+func (m *MainModel) acceptsCommand() bool {
+	if !m.keyMap.ViMode || m.state == StateSettings {
+		return false
+	}
+	if diagnosticView, ok := m.activeView.(*DiagnosticViewModel); ok && diagnosticView.GetState() != DiagnosticStateResult {
+		return false
+	}
+	capturer, ok := m.activeView.(inputCapturer)
+	return !ok || !capturer.CapturesInput()
+}
+
+// updateCommandLine handles a key typed into the open command line
+func (m *MainModel) updateCommandLine(msg tea.KeyMsg) (*MainModel, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m.quit()
+	case tea.KeyEsc:
+		m.command = nil
+	case tea.KeyEnter:
+		input := m.command.input
+		m.command = nil
+		return m.runCommand(input)
+	case tea.KeyBackspace:
+		if m.command.input == "" {
+			m.command = nil
+		} else {
+			runes := []rune(m.command.input)
+			m.command.input = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.command.input += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// runCommand runs a command line: a line number, q, help, the tab commands
+// or the ID of a screen of the menu such as ping or settings
+func (m *MainModel) runCommand(input string) (*MainModel, tea.Cmd) {
+	command := strings.TrimSpace(input)
+	if line, err := strconv.Atoi(command); err == nil {
+		if jumper, ok := m.activeView.(lineJumper); ok {
+			jumper.JumpTo(line)
+		}
+		return m, nil
+	}
+
+	switch command {
+	case "":
+		return m, nil
+	case "q", "q!", "qa", "quit":
+		return m.quit()
+	case "help", "h":
+		m.leaveScreen()
+		return m.openHelp()
+	case "tabnew":
+		return m.newTab()
+	case "tabclose", "tabc":
+		return m.closeTab()
+	case "tabnext", "tabn":
+		return m.switchTab((m.activeTab + 1) % len(m.tabs))
+	case "tabprevious", "tabp", "tabN":
+		return m.switchTab((m.activeTab + len(m.tabs) - 1) % len(m.tabs))
+	}
+
+	for _, item := range m.navigation.Items() {
+		if item.Enabled && item.ID == command {
+			m.leaveScreen()
+			return m.selectNavigationItem(item)
+		}
+	}
+	m.configStatus = fmt.Sprintf("unknown command: %s", command)
+	return m, nil
+}
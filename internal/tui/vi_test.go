@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	configpkg "github.com/nettracex/nettracex-tui/internal/config"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// viKeyMap returns the default key bindings with vi mode on
+func viKeyMap() KeyMap {
+	keyMap := DefaultKeyMap()
+	keyMap.ViMode = true
+	return keyMap
+}
+
+// typeKeys sends text to model one key at a time
+func typeKeys(model tea.Model, text string) {
+	for _, r := range text {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+		if r == ' ' {
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{r}}
+		}
+		model.Update(msg)
+	}
+}
+
+func TestViFind(t *testing.T) {
+	texts := []string{"Ping", "DNS Lookup", "SSL Check", "Ping Sweep"}
+	assert.Equal(t, 3, viFind(texts, "ping", 0, false))
+	assert.Equal(t, 0, viFind(texts, "ping", 3, false), "wraps around")
+	assert.Equal(t, 3, viFind(texts, "ping", 0, true))
+	assert.Equal(t, -1, viFind(texts, "whois", 0, false))
+}
+
+func TestViKeys_Disabled(t *testing.T) {
+	var vi viKeys
+	motion, handled := vi.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")}, DefaultKeyMap())
+	assert.Equal(t, viNone, motion)
+	assert.False(t, handled)
+}
+
+func TestViKeys_Update(t *testing.T) {
+	var vi viKeys
+	keyMap := viKeyMap()
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	motion, handled := vi.update(runes("g"), keyMap)
+	assert.Equal(t, viNone, motion)
+	assert.True(t, handled)
+	motion, _ = vi.update(runes("g"), keyMap)
+	assert.Equal(t, viTop, motion)
+	motion, _ = vi.update(runes("G"), keyMap)
+	assert.Equal(t, viBottom, motion)
+
+	_, handled = vi.update(runes("n"), keyMap)
+	assert.False(t, handled, "n does nothing before a search")
+
+	vi.update(runes("/"), keyMap)
+	assert.True(t, vi.Searching())
+	vi.update(runes("dns"), keyMap)
+	assert.Contains(t, vi.prompt(), "/dns")
+	motion, _ = vi.update(tea.KeyMsg{Type: tea.KeyEnter}, keyMap)
+	assert.Equal(t, viNextMatch, motion)
+	assert.False(t, vi.Searching())
+
+	motion, _ = vi.update(runes("N"), keyMap)
+	assert.Equal(t, viPrevMatch, motion)
+
+	vi.update(runes("/"), keyMap)
+	vi.update(tea.KeyMsg{Type: tea.KeyEsc}, keyMap)
+	assert.False(t, vi.Searching())
+	assert.Equal(t, "dns", vi.query, "esc keeps the last search")
+}
+
+func TestStandardScrollPager_ViMode(t *testing.T) {
+	pager := NewStandardScrollPager()
+	pager.SetSize(80, 20)
+	pager.SetItems([]ScrollableItem{
+		NewStringScrollableItem("alpha", "1"),
+		NewStringScrollableItem("bravo", "2"),
+		NewStringScrollableItem("charlie", "3"),
+		NewStringScrollableItem("delta", "4"),
+	})
+
+	typeKeys(pager, "G")
+	assert.Equal(t, 0, pager.GetSelected(), "vi keys are off by default")
+
+	pager.SetKeyMap(viKeyMap())
+	typeKeys(pager, "G")
+	assert.Equal(t, 3, pager.GetSelected())
+	typeKeys(pager, "gg")
+	assert.Equal(t, 0, pager.GetSelected())
+
+	typeKeys(pager, "/char")
+	assert.True(t, pager.CapturesInput())
+	assert.Contains(t, pager.View(), "/char")
+	pager.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, pager.CapturesInput())
+	assert.Equal(t, 2, pager.GetSelected())
+
+	pager.JumpTo(2)
+	assert.Equal(t, 1, pager.GetSelected(), "lines count from 1")
+}
+
+func TestTableModel_ViSearch(t *testing.T) {
+	table := NewTableModel([]string{"Host", "Address"})
+	table.SetSize(80, 20)
+	table.AddRow([]string{"router", "192.168.1.1"})
+	table.AddRow([]string{"printer", "192.168.1.20"})
+	table.AddRow([]string{"nas", "192.168.1.30"})
+	table.SetKeyMap(viKeyMap())
+
+	typeKeys(table, "/1.30")
+	table.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	row, ok := table.SelectedRow()
+	require.True(t, ok)
+	assert.Equal(t, "nas", row[0], "cells are searched too")
+
+	typeKeys(table, "gg")
+	row, _ = table.SelectedRow()
+	assert.Equal(t, "router", row[0])
+}
+
+func TestMainModel_ViCommandLine(t *testing.T) {
+	config := &domain.Config{UI: domain.UIConfig{KeyMode: domain.KeyModeVi}}
+	model := NewMainModel(newDashboardRegistry(t, &dashboardTool{name: "ping"}), config, configpkg.NewManager(), nil)
+	model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	require.True(t, model.keyMap.ViMode)
+
+	typeKeys(model, ":pin")
+	assert.Contains(t, model.View(), ":pin")
+	model.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	typeKeys(model, "ng")
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, model.command)
+	assert.Equal(t, StateDiagnostic, model.state)
+	assert.IsType(t, &DiagnosticViewModel{}, model.activeView)
+
+	// : is typed into the form rather than opening the command line
+	typeKeys(model, ":")
+	assert.Nil(t, model.command)
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	typeKeys(model, ":nope")
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Contains(t, model.View(), "unknown command: nope")
+
+	typeKeys(model, ":tabnew")
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	titles, _ := model.Tabs()
+	assert.Len(t, titles, 2)
+
+	typeKeys(model, ":q")
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.True(t, model.quitting)
+}
+
+func TestMainModel_ViCommandLineOff(t *testing.T) {
+	model := newTabsModel(t)
+	typeKeys(model, ":")
+	assert.Nil(t, model.command, "the command line needs vi mode")
+}
@@ -0,0 +1,329 @@
+// Package tui contains watch mode, which runs a tool again on an interval
+// and highlights what changed since the run before
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/diff"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// Watch mode defaults
+const (
+	DefaultWatchInterval = 10 * time.Second
+	// maxWatchEvents is how many changes the timeline keeps
+	maxWatchEvents = 100
+	// watchShownRows and watchShownEvents are how many changed fields and
+	// timeline entries the watch panel lists
+	watchShownRows   = 4
+	watchShownEvents = 3
+	// watchPanelHeight is the most lines the watch panel takes: the status,
+	// the summary, the rows and a line for the rest, then the timeline title,
+	// its entries and a blank line
+	watchPanelHeight = 3 + watchShownRows + 1 + watchShownEvents + 1
+)
+
+// watchIntervals are the intervals + and - step through
+var watchIntervals = []time.Duration{
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	time.Minute,
+	5 * time.Minute,
+	15 * time.Minute,
+}
+
+// watchTickMsg asks view to run its tool again for watch session session
+type watchTickMsg struct {
+	view    *DiagnosticViewModel
+	session int
+}
+
+// watchEvent is a run of the watch whose result differed from the one
+// before it, or that failed
+type watchEvent struct {
+	run  int
+	at   time.Time
+	rows []diff.Row
+	err  error
+}
+
+// watchMode runs the last query of a diagnostic view again every interval.
+// The interval counts from the end of a run, so a slow tool never overlaps
+// itself.
+type watchMode struct {
+	active   bool
+	paused   bool
+	interval time.Duration
+	// session tells the ticks of the current watch from those of a stopped,
+	// paused or rescheduled one
+	session int
+	// running is set while a run of the watch is in flight, so its result
+	// updates the shown result in place
+	running bool
+	runs    int
+	// changes are the fields the latest run changed, and err why it failed
+	changes  []diff.Row
+	err      error
+	timeline []watchEvent
+}
+
+// toggleWatch starts watching the shown result, or stops watching
+func (m *DiagnosticViewModel) toggleWatch() tea.Cmd {
+	if m.watch.active {
+		m.stopWatch()
+		return nil
+	}
+	interval := m.watch.interval
+	if interval == 0 {
+		interval = DefaultWatchInterval
+	}
+	m.watch = watchMode{active: true, interval: interval, session: m.watch.session + 1}
+	m.SetSize(m.width, m.height)
+	return m.scheduleWatch()
+}
+
+// stopWatch stops watching, keeping the timeline on screen
+func (m *DiagnosticViewModel) stopWatch() {
+	m.watch.active = false
+	m.watch.paused = false
+	m.watch.session++
+}
+
+// resetWatch stops watching and forgets the timeline, for a new query
+func (m *DiagnosticViewModel) resetWatch() {
+	m.watch = watchMode{interval: m.watch.interval, session: m.watch.session + 1, running: m.watch.running}
+	m.SetSize(m.width, m.height)
+}
+
+// toggleWatchPause pauses the watch, dropping the pending run, or resumes
+// it with a run at once
+func (m *DiagnosticViewModel) toggleWatchPause() tea.Cmd {
+	m.watch.paused = !m.watch.paused
+	m.watch.session++
+	if m.watch.paused || m.watch.running {
+		return nil
+	}
+	return m.runWatch()
+}
+
+// changeWatchInterval steps to the next longer or shorter interval and
+// reschedules the pending run
+func (m *DiagnosticViewModel) changeWatchInterval(longer bool) tea.Cmd {
+	i := 0
+	for i < len(watchIntervals)-1 && watchIntervals[i] < m.watch.interval {
+		i++
+	}
+	if longer && i < len(watchIntervals)-1 {
+		i++
+	} else if !longer && i > 0 {
+		i--
+	}
+	m.watch.interval = watchIntervals[i]
+	m.watch.session++
+	if m.watch.paused || m.watch.running {
+		return nil
+	}
+	return m.scheduleWatch()
+}
+
+// scheduleWatch runs the tool again after the interval
+func (m *DiagnosticViewModel) scheduleWatch() tea.Cmd {
+	view, session := m, m.watch.session
+	return tea.Tick(m.watch.interval, func(time.Time) tea.Msg {
+		return watchTickMsg{view: view, session: session}
+	})
+}
+
+// updateWatchTick runs the tool again unless the tick belongs to another
+// view or to an earlier watch session. While another run is in flight,
+// such as a refresh, the run waits for the next interval.
+func (m *DiagnosticViewModel) updateWatchTick(msg watchTickMsg) tea.Cmd {
+	if msg.view != m || msg.session != m.watch.session || !m.watch.active || m.watch.paused {
+		return nil
+	}
+	if m.loading || m.state != DiagnosticStateResult {
+		return m.scheduleWatch()
+	}
+	return m.runWatch()
+}
+
+// runWatch runs the last query again, bypassing cached responses
+func (m *DiagnosticViewModel) runWatch() tea.Cmd {
+	values := make(map[string]string, len(m.lastValues)+1)
+	for k, v := range m.lastValues {
+		values[k] = v
+	}
+	values[refreshValue] = "true"
+	m.watch.running = true
+	return m.executeDiagnostic(values)
+}
+
+// finishWatchRun records the outcome of a run of the watch, either a result
+// or an error, and schedules the next run. A run that finishes after the
+// watch stopped is dropped.
+func (m *DiagnosticViewModel) finishWatchRun(result domain.Result, err error) tea.Cmd {
+	m.watch.running = false
+	m.loading = false
+	if !m.watch.active {
+		return nil
+	}
+
+	m.watch.runs++
+	m.watch.err = err
+	m.watch.changes = nil
+	if err == nil {
+		m.watch.changes = watchChanges(m.result, result)
+		m.result = result
+		m.resultView.refreshResult(result)
+	}
+	if err != nil || len(m.watch.changes) > 0 {
+		m.watch.timeline = append(m.watch.timeline, watchEvent{
+			run:  m.watch.runs,
+			at:   time.Now(),
+			rows: m.watch.changes,
+			err:  err,
+		})
+		if len(m.watch.timeline) > maxWatchEvents {
+			m.watch.timeline = m.watch.timeline[len(m.watch.timeline)-maxWatchEvents:]
+		}
+	}
+	if m.watch.paused {
+		return nil
+	}
+	return m.scheduleWatch()
+}
+
+// showsWatch reports whether the watch panel is shown above the result
+func (m *DiagnosticViewModel) showsWatch() bool {
+	return m.watch.active || m.watch.runs > 0
+}
+
+// watchChanges returns the fields of after that differ from before. Results
+// the diff package cannot compare count as changed as a whole when their
+// data differs.
+func watchChanges(before, after domain.Result) []diff.Row {
+	if before == nil || after == nil {
+		return nil
+	}
+	rows, err := diff.Results(before.Data(), after.Data())
+	if err != nil {
+		if fmt.Sprintf("%+v", before.Data()) == fmt.Sprintf("%+v", after.Data()) {
+			return nil
+		}
+		return []diff.Row{{Label: "Result", Change: diff.Changed}}
+	}
+
+	var changes []diff.Row
+	for _, row := range rows {
+		if row.Change != diff.Unchanged {
+			changes = append(changes, row)
+		}
+	}
+	return changes
+}
+
+// watchSummary counts the changes of rows, such as "2 changed, 1 added"
+func watchSummary(rows []diff.Row) string {
+	changed, added, removed := diff.Summary(rows)
+	var parts []string
+	for _, part := range []struct {
+		count int
+		what  string
+	}{{changed, "changed"}, {added, "added"}, {removed, "removed"}} {
+		if part.count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", part.count, part.what))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// renderWatch renders the state of the watch, the fields the latest run
+// changed and the most recent changes of the timeline
+func (m *DiagnosticViewModel) renderWatch() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colors.Info)
+	subtleStyle := lipgloss.NewStyle().
+		Foreground(colors.Subtle)
+	warningStyle := lipgloss.NewStyle().
+		Foreground(colors.Warning)
+
+	status := fmt.Sprintf("👁 Watching every %v", m.watch.interval)
+	switch {
+	case !m.watch.active:
+		status = "👁 Watch stopped"
+	case m.watch.paused:
+		status += " (paused)"
+	case m.watch.running:
+		status += " (running...)"
+	}
+	status += fmt.Sprintf(" • runs: %d", m.watch.runs)
+
+	var content strings.Builder
+	content.WriteString(titleStyle.Render(status))
+	content.WriteString("\n")
+
+	switch {
+	case m.watch.err != nil:
+		content.WriteString(warningStyle.Render(fmt.Sprintf("⚠ Run %d failed: %v", m.watch.runs, m.watch.err)))
+		content.WriteString("\n")
+	case len(m.watch.changes) > 0:
+		content.WriteString(fmt.Sprintf("Changed in run %d: %s\n", m.watch.runs, watchSummary(m.watch.changes)))
+		valueWidth := max(20, (m.width-30)/2)
+		for i, row := range m.watch.changes {
+			if i == watchShownRows {
+				content.WriteString(subtleStyle.Render(fmt.Sprintf("  ... %d more; press d to compare the runs", len(m.watch.changes)-watchShownRows)))
+				content.WriteString("\n")
+				break
+			}
+			marker, style := diffRowStyle(row.Change)
+			line := fmt.Sprintf("  %s %s: ", marker, row.Label)
+			switch row.Change {
+			case diff.Added:
+				line += truncateDiffValue(row.After, valueWidth)
+			case diff.Removed:
+				line += truncateDiffValue(row.Before, valueWidth)
+			default:
+				line += truncateDiffValue(valueOrDash(row.Before), valueWidth) + " → " + truncateDiffValue(valueOrDash(row.After), valueWidth)
+			}
+			content.WriteString(style.Render(line))
+			content.WriteString("\n")
+		}
+	case m.watch.runs > 0:
+		content.WriteString(subtleStyle.Render(fmt.Sprintf("No changes in run %d", m.watch.runs)))
+		content.WriteString("\n")
+	default:
+		content.WriteString(subtleStyle.Render("Waiting for the first run"))
+		content.WriteString("\n")
+	}
+
+	if len(m.watch.timeline) > 0 {
+		content.WriteString(titleStyle.Render(fmt.Sprintf("Timeline of changes (%d)", len(m.watch.timeline))))
+		content.WriteString("\n")
+		for i := len(m.watch.timeline) - 1; i >= 0 && i >= len(m.watch.timeline)-watchShownEvents; i-- {
+			event := m.watch.timeline[i]
+			line := fmt.Sprintf("  %s run %d • ", event.at.Format("15:04:05"), event.run)
+			if event.err != nil {
+				content.WriteString(warningStyle.Render(line + "failed: " + event.err.Error()))
+				content.WriteString("\n")
+				continue
+			}
+			labels := make([]string, len(event.rows))
+			for j, row := range event.rows {
+				labels[j] = row.Label
+			}
+			line += watchSummary(event.rows) + ": " + strings.Join(labels, ", ")
+			content.WriteString(subtleStyle.Render(truncateDiffValue(line, max(20, m.width-4))))
+			content.WriteString("\n")
+		}
+	}
+
+	return content.String()
+}
@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// watchTool answers each run with the next of its answers, repeating the
+// last one, or fails with err
+type watchTool struct {
+	answers [][]string
+	runs    int
+	err     error
+}
+
+func (t *watchTool) Name() string                            { return "dns" }
+func (t *watchTool) Description() string                     { return "answers from a list" }
+func (t *watchTool) Validate(params domain.Parameters) error { return nil }
+func (t *watchTool) GetModel() tea.Model                     { return nil }
+
+func (t *watchTool) Execute(ctx context.Context, params domain.Parameters) (domain.Result, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	result := domain.DNSResult{Query: "example.com"}
+	for _, address := range t.answers[min(t.runs, len(t.answers)-1)] {
+		result.Records = append(result.Records, domain.DNSRecord{Name: "example.com", Type: domain.DNSRecordTypeA, Value: address, TTL: 300})
+	}
+	t.runs++
+	return domain.NewResult(result), nil
+}
+
+// deliver runs cmd and hands the messages it produced to view
+func deliver(view *DiagnosticViewModel, cmd tea.Cmd) {
+	for _, msg := range runTabCmd(cmd) {
+		view.Update(msg)
+	}
+}
+
+// tickWatch delivers the pending tick of the watch of view
+func tickWatch(view *DiagnosticViewModel) {
+	_, cmd := view.Update(watchTickMsg{view: view, session: view.watch.session})
+	deliver(view, cmd)
+}
+
+func TestDiagnosticViewModel_Watch(t *testing.T) {
+	tool := &watchTool{answers: [][]string{{"192.0.2.1"}, {"192.0.2.1", "192.0.2.2"}}}
+	view := NewDiagnosticViewModel(tool)
+	view.SetSize(120, 40)
+	_, cmd := view.Update(FormSubmitMsg{Values: map[string]string{"domain": "example.com", "record_type": "A"}})
+	deliver(view, cmd)
+	require.Equal(t, DiagnosticStateResult, view.GetState())
+
+	_, cmd = view.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	require.NotNil(t, cmd)
+	require.True(t, view.watch.active)
+	assert.Contains(t, view.View(), "Watching every 10s")
+
+	// A new record is highlighted and enters the timeline, and the result
+	// stays on screen while the tool runs
+	tickWatch(view)
+	assert.Equal(t, DiagnosticStateResult, view.GetState())
+	assert.Equal(t, 2, tool.runs)
+	screen := view.View()
+	assert.Contains(t, screen, "Changed in run 1: 1 added")
+	assert.Contains(t, screen, "+ A: 192.0.2.2")
+	assert.Contains(t, screen, "Timeline of changes (1)")
+	assert.Len(t, view.GetResult().Data().(domain.DNSResult).Records, 2)
+
+	// Ticks of an earlier session are dropped
+	_, cmd = view.Update(watchTickMsg{view: view, session: view.watch.session - 1})
+	assert.Nil(t, cmd)
+
+	// Pausing drops the pending run and resuming runs at once
+	view.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	assert.Contains(t, view.View(), "(paused)")
+	_, cmd = view.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	deliver(view, cmd)
+	assert.Equal(t, 3, tool.runs)
+	assert.Contains(t, view.View(), "No changes in run 2")
+	assert.Len(t, view.watch.timeline, 1, "unchanged runs stay off the timeline")
+
+	view.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("+")})
+	assert.Contains(t, view.View(), "Watching every 30s")
+
+	// A failed run keeps the last result
+	tool.err = errors.New("server failure")
+	tickWatch(view)
+	assert.Equal(t, DiagnosticStateResult, view.GetState())
+	assert.Contains(t, view.View(), "Run 3 failed: server failure")
+	assert.Len(t, view.watch.timeline, 2)
+
+	// Stopping keeps the timeline until the next query
+	view.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	assert.Contains(t, view.View(), "Watch stopped • runs: 3")
+	_, cmd = view.Update(watchTickMsg{view: view, session: view.watch.session})
+	assert.Nil(t, cmd)
+
+	view.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, view.showsWatch())
+}
@@ -0,0 +1,87 @@
+package update
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Channel is a release channel. Following a channel gets the releases of
+// the channels more stable than it too, so beta testers move on to the
+// release candidates and the release.
+type Channel string
+
+const (
+	Stable Channel = "stable"
+	RC     Channel = "rc"
+	Beta   Channel = "beta"
+	Alpha  Channel = "alpha"
+)
+
+// Channels lists the channels, most stable first
+var Channels = []Channel{Stable, RC, Beta, Alpha}
+
+// ChannelNames returns the names of the channels, most stable first
+func ChannelNames() []string {
+	names := make([]string, len(Channels))
+	for i, channel := range Channels {
+		names[i] = string(channel)
+	}
+	return names
+}
+
+// ParseChannel parses the name of a channel; an empty name is Stable
+func ParseChannel(name string) (Channel, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return Stable, nil
+	}
+	for _, channel := range Channels {
+		if string(channel) == name {
+			return channel, nil
+		}
+	}
+	return "", fmt.Errorf("unknown release channel %q, use one of %s", name, strings.Join(ChannelNames(), ", "))
+}
+
+// VersionChannel returns the channel of a version from the first identifier
+// of its prerelease: 1.3.0-rc.1 is an RC and 1.3.0-beta.2 a beta. Other
+// prereleases, such as 1.3.0-dev.5, are alphas.
+func VersionChannel(version string) Channel {
+	parsed, ok := parseVersion(version)
+	if !ok || parsed.prerelease == "" {
+		return Stable
+	}
+	identifier, _, _ := strings.Cut(parsed.prerelease, ".")
+	switch strings.ToLower(identifier) {
+	case "rc", "pre":
+		return RC
+	case "beta", "b", "preview":
+		return Beta
+	}
+	return Alpha
+}
+
+// Follows reports whether following the channel gets releases of release
+func (c Channel) Follows(release Channel) bool {
+	return release.stability() <= c.stability()
+}
+
+// stability orders the channels, most stable first
+func (c Channel) stability() int {
+	for i, channel := range Channels {
+		if channel == c {
+			return i
+		}
+	}
+	return 0
+}
+
+// channelManifestURL returns the manifest of the latest release of a
+// channel: latest.json of a mirror becomes latest-beta.json for the beta
+// channel
+func channelManifestURL(manifestURL string, channel Channel) string {
+	if channel == Stable || channel == "" || !strings.HasSuffix(manifestURL, "/latest.json") {
+		return manifestURL
+	}
+	return strings.TrimSuffix(manifestURL, "latest.json") + "latest-" + string(channel) + ".json"
+}
@@ -0,0 +1,130 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestVersionChannel(t *testing.T) {
+	tests := map[string]Channel{
+		"v1.3.0":         Stable,
+		"1.3.0-rc.1":     RC,
+		"v1.3.0-beta.2":  Beta,
+		"v1.3.0-preview": Beta,
+		"v1.3.0-dev.5":   Alpha,
+		"v1.3.0-alpha":   Alpha,
+	}
+	for version, want := range tests {
+		if got := VersionChannel(version); got != want {
+			t.Errorf("VersionChannel(%q) = %q, want %q", version, got, want)
+		}
+	}
+}
+
+func TestParseChannel(t *testing.T) {
+	if channel, err := ParseChannel(""); err != nil || channel != Stable {
+		t.Errorf("Expected an empty name to be stable, got %q, %v", channel, err)
+	}
+	if channel, err := ParseChannel(" Beta "); err != nil || channel != Beta {
+		t.Errorf("Expected beta, got %q, %v", channel, err)
+	}
+	if _, err := ParseChannel("nightly"); err == nil {
+		t.Error("Expected an error for an unknown channel")
+	}
+}
+
+func TestChannel_Follows(t *testing.T) {
+	if !Beta.Follows(Stable) || !Beta.Follows(RC) || !Beta.Follows(Beta) {
+		t.Error("Expected beta to follow the more stable channels")
+	}
+	if Beta.Follows(Alpha) || Stable.Follows(RC) {
+		t.Error("Expected channels not to follow less stable ones")
+	}
+}
+
+// channelServer serves a list of releases, newest first as GitHub does
+func channelServer(t *testing.T, releases []Release) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/nettracex/nettracex-tui/releases" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(releases)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLatest_Channel(t *testing.T) {
+	server := channelServer(t, []Release{
+		{Tag: "v1.4.0-dev.1", Prerelease: true},
+		{Tag: "v1.4.0-beta.2", Prerelease: true, Draft: true},
+		{Tag: "v1.4.0-beta.1", Prerelease: true},
+		{Tag: "v1.3.1-rc.1", Prerelease: true},
+		{Tag: "v1.3.0"},
+	})
+	u := New()
+	u.APIURL = server.URL
+
+	for channel, want := range map[Channel]string{RC: "v1.3.1-rc.1", Beta: "v1.4.0-beta.1", Alpha: "v1.4.0-dev.1"} {
+		u.Channel = channel
+		release, err := u.Latest(context.Background())
+		if err != nil {
+			t.Fatalf("Latest(%s) failed: %v", channel, err)
+		}
+		if release.Tag != want {
+			t.Errorf("Latest(%s) = %q, want %q", channel, release.Tag, want)
+		}
+	}
+}
+
+func TestLatest_ChannelManifest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tags := map[string]string{"/latest.json": "v1.3.0", "/latest-beta.json": "v1.4.0-beta.1"}
+		tag, ok := tags[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(Release{Tag: tag})
+	}))
+	defer server.Close()
+
+	u := New()
+	u.ManifestURL = server.URL + "/latest.json"
+	u.Channel = Beta
+	release, err := u.Latest(context.Background())
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	if release.Tag != "v1.4.0-beta.1" {
+		t.Errorf("Expected the beta manifest, got %q", release.Tag)
+	}
+}
+
+func TestCheckCached_Channel(t *testing.T) {
+	server := channelServer(t, []Release{{Tag: "v1.4.0-beta.1", Prerelease: true}, {Tag: "v1.3.0"}})
+	u := New()
+	u.APIURL = server.URL
+	u.Channel = Beta
+	path := filepath.Join(t.TempDir(), StateFileName)
+
+	// A stable check does not answer for the beta channel
+	state, _ := json.Marshal(State{CheckedAt: time.Now(), Latest: "v1.3.0"})
+	if err := os.WriteFile(path, state, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	release, newer, err := u.CheckCached(context.Background(), "1.3.0", path, time.Hour)
+	if err != nil {
+		t.Fatalf("CheckCached failed: %v", err)
+	}
+	if !newer || release.Tag != "v1.4.0-beta.1" {
+		t.Errorf("Expected the beta to be newer, got %q, newer %v", release.Tag, newer)
+	}
+}
@@ -0,0 +1,246 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	// ChecksumsFile is the asset with the SHA-256 sums of the archives
+	ChecksumsFile = "checksums.txt"
+	// SignatureFile is the ed25519 signature of ChecksumsFile
+	SignatureFile = ChecksumsFile + ".sig"
+
+	// maxArchiveSize is the largest archive downloaded
+	maxArchiveSize = 200 << 20
+)
+
+// ArchiveName returns the name of the release archive for a platform, as
+// .goreleaser.yaml names it, e.g. nettracex_Linux_x86_64.tar.gz
+func ArchiveName(goos, goarch string) string {
+	arch := goarch
+	switch goarch {
+	case "amd64":
+		arch = "x86_64"
+	case "386":
+		arch = "i386"
+	}
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("nettracex_%s_%s%s", strings.ToUpper(goos[:1])+goos[1:], arch, ext)
+}
+
+// BinaryName returns the name of the binary in the archive for goos
+func BinaryName(goos string) string {
+	if goos == "windows" {
+		return "nettracex.exe"
+	}
+	return "nettracex"
+}
+
+// Install downloads the archive of release for the running platform,
+// verifies it and replaces the binary at exe with the one it holds
+func (u *Updater) Install(ctx context.Context, release Release, exe string) error {
+	binary, err := u.Download(ctx, release, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return err
+	}
+	return replaceBinary(exe, binary)
+}
+
+// Download returns the verified binary of release for a platform
+func (u *Updater) Download(ctx context.Context, release Release, goos, goarch string) ([]byte, error) {
+	name := ArchiveName(goos, goarch)
+	archive, ok := release.Asset(name)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no archive for %s/%s", release.Tag, goos, goarch)
+	}
+	sums, err := u.checksums(ctx, release)
+	if err != nil {
+		return nil, err
+	}
+	want, ok := sums[name]
+	if !ok {
+		return nil, fmt.Errorf("%s has no checksum for %s", ChecksumsFile, name)
+	}
+
+	data, err := u.get(ctx, archive.URL, maxArchiveSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return nil, fmt.Errorf("checksum mismatch for %s: got %s, want %s", name, got, want)
+	}
+
+	binary, err := extractBinary(name, data, BinaryName(goos))
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+	return binary, nil
+}
+
+// checksums downloads and verifies checksums.txt of release and returns the
+// sums by file name
+func (u *Updater) checksums(ctx context.Context, release Release) (map[string]string, error) {
+	asset, ok := release.Asset(ChecksumsFile)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no %s", release.Tag, ChecksumsFile)
+	}
+	data, err := u.get(ctx, asset.URL, 1<<20)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", ChecksumsFile, err)
+	}
+
+	if u.PublicKey != "" {
+		signature, ok := release.Asset(SignatureFile)
+		if !ok {
+			return nil, fmt.Errorf("release %s has no %s", release.Tag, SignatureFile)
+		}
+		sig, err := u.get(ctx, signature.URL, 4096)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download %s: %w", SignatureFile, err)
+		}
+		if err := verifySignature(u.PublicKey, data, sig); err != nil {
+			return nil, err
+		}
+	}
+	return parseChecksums(data), nil
+}
+
+// verifySignature checks the ed25519 signature of data, raw as openssl
+// pkeyutl writes it or base64 encoded
+func verifySignature(publicKey string, data, signature []byte) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return errors.New("invalid update public key")
+	}
+	if len(signature) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", SignatureFile, err)
+		}
+		signature = decoded
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), data, signature) {
+		return fmt.Errorf("%s does not match its signature", ChecksumsFile)
+	}
+	return nil
+}
+
+// parseChecksums reads "sum  name" lines as sha256sum writes them
+func parseChecksums(data []byte) map[string]string {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 {
+			sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+		}
+	}
+	return sums
+}
+
+// extractBinary returns the file called binary from the archive name
+func extractBinary(name string, data []byte, binary string) ([]byte, error) {
+	if strings.HasSuffix(name, ".zip") {
+		reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range reader.File {
+			if path.Base(file.Name) != binary || file.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := file.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			return io.ReadAll(io.LimitReader(rc, maxArchiveSize))
+		}
+		return nil, fmt.Errorf("no %s in the archive", binary)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no %s in the archive", binary)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag == tar.TypeReg && path.Base(header.Name) == binary {
+			return io.ReadAll(io.LimitReader(reader, maxArchiveSize))
+		}
+	}
+}
+
+// replaceBinary writes binary next to exe and renames it over exe, so the
+// running binary is never left half written. Windows cannot replace a
+// running binary, so it is moved aside to exe.old first.
+func replaceBinary(exe string, binary []byte) error {
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	info, err := os.Stat(exe)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(exe), ".nettracex-update-*")
+	if err != nil {
+		return fmt.Errorf("failed to write the new binary: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write the new binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write the new binary: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	old := ""
+	if runtime.GOOS == "windows" {
+		old = exe + ".old"
+		os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			return fmt.Errorf("failed to replace %s: %w", exe, err)
+		}
+	}
+	if err := os.Rename(tmp.Name(), exe); err != nil {
+		if old != "" {
+			os.Rename(old, exe)
+		}
+		return fmt.Errorf("failed to replace %s: %w", exe, err)
+	}
+	return nil
+}
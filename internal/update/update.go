@@ -0,0 +1,352 @@
+// Package update checks the GitHub releases of NetTraceX for a newer version
+// and replaces the running binary with it. A download is checked against its
+// SHA-256 sum in the checksums.txt of the release, and checksums.txt against
+// its ed25519 signature when the build carries the release public key.
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nettracex/nettracex-tui/internal/appdir"
+)
+
+const (
+	// DefaultAPIURL is the GitHub API the releases are read from
+	DefaultAPIURL = "https://api.github.com"
+	// DefaultRepository is the GitHub repository NetTraceX is released from
+	DefaultRepository = "nettracex/nettracex-tui"
+	// DefaultCheckInterval is how long the result of a check is reused
+	DefaultCheckInterval = 24 * time.Hour
+	// StateFileName is the file the last check is kept in
+	StateFileName = "update.json"
+)
+
+// PublicKey is the base64 ed25519 public key checksums.txt is signed with.
+// Release builds set it with -ldflags "-X .../internal/update.PublicKey=...";
+// without it only the checksums are verified. For a key made with openssl
+// genpkey -algorithm ed25519 it is
+// openssl pkey -in key.pem -pubout -outform DER | tail -c 32 | base64
+var PublicKey string
+
+// ManifestURL is a release manifest read in place of the latest GitHub
+// release, such as the latest.json an object storage mirror keeps. Release
+// builds may set it with -ldflags "-X .../internal/update.ManifestURL=...".
+var ManifestURL string
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+	Size int64  `json:"size"`
+}
+
+// Release is a published release of NetTraceX
+type Release struct {
+	Tag        string  `json:"tag_name"`
+	Name       string  `json:"name"`
+	URL        string  `json:"html_url"`
+	Prerelease bool    `json:"prerelease"`
+	Draft      bool    `json:"draft"`
+	Assets     []Asset `json:"assets"`
+}
+
+// Version returns the version of the release without the leading v
+func (r Release) Version() string {
+	return strings.TrimPrefix(r.Tag, "v")
+}
+
+// Channel returns the channel of the release from its version. A release
+// marked a prerelease without a prerelease version is a beta.
+func (r Release) Channel() Channel {
+	channel := VersionChannel(r.Tag)
+	if channel == Stable && r.Prerelease {
+		return Beta
+	}
+	return channel
+}
+
+// Asset returns the asset named name
+func (r Release) Asset(name string) (Asset, bool) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset, true
+		}
+	}
+	return Asset{}, false
+}
+
+// Updater reads the releases of a repository and installs them
+type Updater struct {
+	APIURL     string
+	Repository string
+	// PublicKey verifies the signature of checksums.txt, see PublicKey
+	PublicKey string
+	// ManifestURL is read for the latest release instead of the GitHub
+	// API when set, see ManifestURL
+	ManifestURL string
+	// Channel is the release channel followed, Stable by default
+	Channel Channel
+	Client  *http.Client
+}
+
+// New creates an updater of the NetTraceX releases on GitHub
+func New() *Updater {
+	return &Updater{
+		APIURL:      DefaultAPIURL,
+		Repository:  DefaultRepository,
+		PublicKey:   PublicKey,
+		ManifestURL: ManifestURL,
+		Channel:     Stable,
+		Client:      &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+// Latest returns the latest release of the channel followed. The latest
+// stable release is the one GitHub names latest, which is never a draft or
+// a prerelease; other channels look through the recent releases. A manifest
+// names the release in the same form, with one manifest per channel.
+func (u *Updater) Latest(ctx context.Context) (Release, error) {
+	if u.ManifestURL == "" && u.Channel != "" && u.Channel != Stable {
+		return u.latestOfChannel(ctx)
+	}
+	url := fmt.Sprintf("%s/repos/%s/releases/latest", strings.TrimSuffix(u.APIURL, "/"), u.Repository)
+	if u.ManifestURL != "" {
+		url = channelManifestURL(u.ManifestURL, u.Channel)
+	}
+	body, err := u.get(ctx, url, 1<<20)
+	if err != nil {
+		return Release{}, fmt.Errorf("failed to check for updates: %w", err)
+	}
+	var release Release
+	if err := json.Unmarshal(body, &release); err != nil {
+		return Release{}, fmt.Errorf("invalid release: %w", err)
+	}
+	if release.Tag == "" {
+		return Release{}, errors.New("invalid release: no tag")
+	}
+	return release, nil
+}
+
+// latestOfChannel returns the newest of the recent releases the channel
+// follows, skipping drafts
+func (u *Updater) latestOfChannel(ctx context.Context) (Release, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases?per_page=30", strings.TrimSuffix(u.APIURL, "/"), u.Repository)
+	body, err := u.get(ctx, url, 8<<20)
+	if err != nil {
+		return Release{}, fmt.Errorf("failed to check for updates: %w", err)
+	}
+	var releases []Release
+	if err := json.Unmarshal(body, &releases); err != nil {
+		return Release{}, fmt.Errorf("invalid releases: %w", err)
+	}
+
+	var latest Release
+	var latestVersion semver
+	for _, release := range releases {
+		version, ok := parseVersion(release.Tag)
+		if release.Draft || !ok || !u.Channel.Follows(release.Channel()) {
+			continue
+		}
+		if latest.Tag == "" || compareVersions(version, latestVersion) > 0 {
+			latest, latestVersion = release, version
+		}
+	}
+	if latest.Tag == "" {
+		return Release{}, fmt.Errorf("no %s release found", u.Channel)
+	}
+	return latest, nil
+}
+
+// Check returns the latest release and whether it is newer than current
+func (u *Updater) Check(ctx context.Context, current string) (Release, bool, error) {
+	release, err := u.Latest(ctx)
+	if err != nil {
+		return Release{}, false, err
+	}
+	return release, Newer(current, release.Version()), nil
+}
+
+// State is the result of the last check, so the TUI asks GitHub at most once
+// per interval
+type State struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Latest    string    `json:"latest"`
+	URL       string    `json:"url,omitempty"`
+	// Channel is the channel checked; a check of another channel is not
+	// reused
+	Channel Channel `json:"channel,omitempty"`
+}
+
+// StatePath returns where the last check is kept
+func StatePath() string {
+	return appdir.ConfigPath(StateFileName)
+}
+
+// CheckCached is Check reusing the check kept at path while it is younger
+// than interval. The release it returns from the kept check only has its tag
+// and page.
+func (u *Updater) CheckCached(ctx context.Context, current, path string, interval time.Duration) (Release, bool, error) {
+	var state State
+	channel := u.Channel
+	if channel == "" {
+		channel = Stable
+	}
+	if data, err := os.ReadFile(path); err == nil && json.Unmarshal(data, &state) == nil &&
+		state.Latest != "" && time.Since(state.CheckedAt) < interval && (state.Channel == channel || state.Channel == "" && channel == Stable) {
+		release := Release{Tag: state.Latest, URL: state.URL}
+		return release, Newer(current, release.Version()), nil
+	}
+
+	release, newer, err := u.Check(ctx, current)
+	if err != nil {
+		return Release{}, false, err
+	}
+	state = State{CheckedAt: time.Now(), Latest: release.Tag, URL: release.URL, Channel: channel}
+	if data, err := json.MarshalIndent(state, "", "  "); err == nil {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err == nil {
+			_ = os.WriteFile(path, data, 0o644)
+		}
+	}
+	return release, newer, nil
+}
+
+// get reads url, failing on a response larger than limit
+func (u *Updater) get(ctx context.Context, url string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "nettracex-updater")
+	client := u.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("%s: larger than %d bytes", url, limit)
+	}
+	return body, nil
+}
+
+// Newer reports whether latest is a newer version than current. Versions are
+// compared as semantic versions, with or without a leading v; a current
+// version that is not one, such as "dev", is never older.
+func Newer(current, latest string) bool {
+	c, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+	l, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	return compareVersions(l, c) > 0
+}
+
+// Released reports whether version is that of a release rather than of a
+// development build such as "dev"
+func Released(version string) bool {
+	_, ok := parseVersion(version)
+	return ok
+}
+
+// semver is a parsed semantic version
+type semver struct {
+	numbers    [3]int
+	prerelease string
+}
+
+// parseVersion parses MAJOR.MINOR.PATCH with an optional -prerelease and
+// +build, which is ignored
+func parseVersion(version string) (semver, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	version, _, _ = strings.Cut(version, "+")
+	version, prerelease, _ := strings.Cut(version, "-")
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return semver{}, false
+	}
+	parsed := semver{prerelease: prerelease}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return semver{}, false
+		}
+		parsed.numbers[i] = n
+	}
+	return parsed, true
+}
+
+// compareVersions orders versions; a prerelease comes before its release
+func compareVersions(a, b semver) int {
+	for i := range a.numbers {
+		if a.numbers[i] != b.numbers[i] {
+			if a.numbers[i] < b.numbers[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case a.prerelease == b.prerelease:
+		return 0
+	case a.prerelease == "":
+		return 1
+	case b.prerelease == "":
+		return -1
+	}
+	return comparePrerelease(a.prerelease, b.prerelease)
+}
+
+// comparePrerelease compares dot separated identifiers, numbers numerically
+func comparePrerelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		case as[i] != bs[i]:
+			return strings.Compare(as[i], bs[i])
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,267 @@
+package update
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"1.2.3", "1.2.4", true},
+		{"v1.2.3", "v1.10.0", true},
+		{"1.2.3", "2.0.0", true},
+		{"1.2.3", "1.2.3", false},
+		{"1.3.0", "1.2.9", false},
+		{"1.3.0-rc.1", "1.3.0", true},
+		{"1.3.0", "1.3.0-rc.2", false},
+		{"1.3.0-rc.2", "1.3.0-rc.10", true},
+		{"1.3.0-alpha", "1.3.0-beta", true},
+		{"1.2.3+abc", "1.2.3", false},
+		{"dev", "1.2.3", false},
+		{"1.2.3", "latest", false},
+	}
+	for _, tt := range tests {
+		if got := Newer(tt.current, tt.latest); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+		}
+	}
+
+	if Released("dev") || Released("(devel)") || !Released("v1.2.3") {
+		t.Error("Expected only semantic versions to be releases")
+	}
+}
+
+func TestArchiveName(t *testing.T) {
+	tests := map[[2]string]string{
+		{"linux", "amd64"}:   "nettracex_Linux_x86_64.tar.gz",
+		{"darwin", "arm64"}:  "nettracex_Darwin_arm64.tar.gz",
+		{"windows", "amd64"}: "nettracex_Windows_x86_64.zip",
+	}
+	for platform, want := range tests {
+		if got := ArchiveName(platform[0], platform[1]); got != want {
+			t.Errorf("ArchiveName(%s, %s) = %q, want %q", platform[0], platform[1], got, want)
+		}
+	}
+}
+
+// releaseServer serves a release of v1.3.0 with a Linux archive holding
+// binary, its checksums and, when key is set, their signature
+type releaseServer struct {
+	*httptest.Server
+	archive   []byte
+	checksums []byte
+	signature []byte
+	requests  atomic.Int32
+}
+
+func newReleaseServer(t *testing.T, binary []byte, key ed25519.PrivateKey) *releaseServer {
+	t.Helper()
+	s := &releaseServer{archive: tarGz(t, map[string][]byte{"README.md": []byte("readme"), "nettracex": binary})}
+	sum := sha256.Sum256(s.archive)
+	s.checksums = []byte(fmt.Sprintf("%s  nettracex_Linux_x86_64.tar.gz\n%s  nettracex_Windows_x86_64.zip\n",
+		hex.EncodeToString(sum[:]), strings.Repeat("0", 64)))
+	if key != nil {
+		s.signature = ed25519.Sign(key, s.checksums)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/nettracex/nettracex-tui/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		assets := []Asset{
+			{Name: "nettracex_Linux_x86_64.tar.gz", URL: s.URL + "/download/archive"},
+			{Name: ChecksumsFile, URL: s.URL + "/download/checksums"},
+		}
+		if s.signature != nil {
+			assets = append(assets, Asset{Name: SignatureFile, URL: s.URL + "/download/signature"})
+		}
+		json.NewEncoder(w).Encode(Release{Tag: "v1.3.0", URL: "https://github.com/nettracex/nettracex-tui/releases/tag/v1.3.0", Assets: assets})
+	})
+	mux.HandleFunc("/download/archive", func(w http.ResponseWriter, r *http.Request) { w.Write(s.archive) })
+	mux.HandleFunc("/download/checksums", func(w http.ResponseWriter, r *http.Request) { w.Write(s.checksums) })
+	mux.HandleFunc("/download/signature", func(w http.ResponseWriter, r *http.Request) { w.Write(s.signature) })
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *releaseServer) updater(publicKey string) *Updater {
+	u := New()
+	u.APIURL = s.URL
+	u.PublicKey = publicKey
+	return u
+}
+
+// tarGz builds a gzipped tar holding files
+func tarGz(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, data := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write(data)
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func TestCheck(t *testing.T) {
+	server := newReleaseServer(t, []byte("new binary"), nil)
+	u := server.updater("")
+
+	release, newer, err := u.Check(context.Background(), "1.2.0")
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if !newer || release.Version() != "1.3.0" {
+		t.Errorf("Expected v1.3.0 to be newer, got %q, newer %v", release.Tag, newer)
+	}
+	if _, newer, _ := u.Check(context.Background(), "1.3.0"); newer {
+		t.Error("Expected the same version not to be newer")
+	}
+
+	u.Repository = "nettracex/missing"
+	if _, _, err := u.Check(context.Background(), "1.2.0"); err == nil {
+		t.Error("Expected an error for a missing repository")
+	}
+}
+
+func TestCheck_Manifest(t *testing.T) {
+	server := newReleaseServer(t, []byte("new binary"), nil)
+	u := server.updater("")
+	u.APIURL = "http://127.0.0.1:0"
+	u.ManifestURL = server.URL + "/repos/nettracex/nettracex-tui/releases/latest"
+
+	release, newer, err := u.Check(context.Background(), "1.2.0")
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if !newer || release.Tag != "v1.3.0" {
+		t.Errorf("Expected v1.3.0 from the manifest, got %q, newer %v", release.Tag, newer)
+	}
+	if _, err := u.Download(context.Background(), release, "linux", "amd64"); err != nil {
+		t.Errorf("Expected the manifest assets to download, got %v", err)
+	}
+}
+
+func TestCheckCached(t *testing.T) {
+	server := newReleaseServer(t, []byte("new binary"), nil)
+	u := server.updater("")
+	path := filepath.Join(t.TempDir(), "nettracex", StateFileName)
+
+	for i := 0; i < 2; i++ {
+		release, newer, err := u.CheckCached(context.Background(), "1.2.0", path, time.Hour)
+		if err != nil {
+			t.Fatalf("CheckCached failed: %v", err)
+		}
+		if !newer || release.Tag != "v1.3.0" || release.URL == "" {
+			t.Errorf("Expected v1.3.0 with its page, got %+v, newer %v", release, newer)
+		}
+	}
+	if got := server.requests.Load(); got != 1 {
+		t.Errorf("Expected the second check to reuse the first, GitHub was asked %d times", got)
+	}
+
+	// A check older than the interval asks again
+	u.CheckCached(context.Background(), "1.2.0", path, 0)
+	if got := server.requests.Load(); got != 2 {
+		t.Errorf("Expected an expired check to ask again, GitHub was asked %d times", got)
+	}
+}
+
+func TestDownload(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey := base64.StdEncoding.EncodeToString(public)
+	server := newReleaseServer(t, []byte("new binary"), private)
+	ctx := context.Background()
+
+	release, err := server.updater(publicKey).Latest(ctx)
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	binary, err := server.updater(publicKey).Download(ctx, release, "linux", "amd64")
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if string(binary) != "new binary" {
+		t.Errorf("Expected the binary from the archive, got %q", binary)
+	}
+
+	if _, err := server.updater(publicKey).Download(ctx, release, "darwin", "arm64"); err == nil || !strings.Contains(err.Error(), "no archive") {
+		t.Errorf("Expected an error for a platform without archive, got %v", err)
+	}
+
+	// The checksums were signed with another key
+	other, _, _ := ed25519.GenerateKey(nil)
+	if _, err := server.updater(base64.StdEncoding.EncodeToString(other)).Download(ctx, release, "linux", "amd64"); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Errorf("Expected a signature error, got %v", err)
+	}
+
+	// The archive was altered after the checksums were made
+	server.archive = append([]byte(nil), server.archive...)
+	server.archive[len(server.archive)-1] ^= 0xff
+	if _, err := server.updater(publicKey).Download(ctx, release, "linux", "amd64"); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Expected a checksum mismatch, got %v", err)
+	}
+}
+
+func TestDownload_SignatureRequired(t *testing.T) {
+	public, _, _ := ed25519.GenerateKey(nil)
+	server := newReleaseServer(t, []byte("new binary"), nil)
+	u := server.updater(base64.StdEncoding.EncodeToString(public))
+
+	release, err := u.Latest(context.Background())
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	if _, err := u.Download(context.Background(), release, "linux", "amd64"); err == nil || !strings.Contains(err.Error(), SignatureFile) {
+		t.Errorf("Expected an unsigned release to be refused with a public key, got %v", err)
+	}
+}
+
+func TestReplaceBinary(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), "nettracex")
+	if err := os.WriteFile(exe, []byte("old binary"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := replaceBinary(exe, []byte("new binary")); err != nil {
+		t.Fatalf("replaceBinary failed: %v", err)
+	}
+	data, _ := os.ReadFile(exe)
+	if string(data) != "new binary" {
+		t.Errorf("Expected the new binary, got %q", data)
+	}
+	info, _ := os.Stat(exe)
+	if info.Mode().Perm() != 0o750 {
+		t.Errorf("Expected the mode to be kept, got %v", info.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(exe))
+	if len(entries) != 1 {
+		t.Errorf("Expected no temporary file left, got %d files", len(entries))
+	}
+}
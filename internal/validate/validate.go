@@ -0,0 +1,138 @@
+// Package validate checks the values typed into the tool forms, so that a
+// mistake is pointed out while it is typed instead of being replaced by a
+// default when the tool runs. Each check takes the value as typed and returns
+// an error worded to be shown next to the field.
+package validate
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/nettracex/nettracex-tui/internal/domain"
+)
+
+// Check validates one value typed into a field
+type Check func(value string) error
+
+// Host accepts a hostname or an IPv4 or IPv6 address
+func Host(value string) error {
+	value = strings.TrimSpace(value)
+	if net.ParseIP(value) != nil || isHostname(value) {
+		return nil
+	}
+	if strings.ContainsAny(value, " ,") {
+		return fmt.Errorf("enter a single host")
+	}
+	return fmt.Errorf("%q is not a valid hostname or IP address", value)
+}
+
+// Domain accepts a domain name such as example.com
+func Domain(value string) error {
+	value = strings.TrimSuffix(strings.TrimSpace(value), ".")
+	if net.ParseIP(value) != nil {
+		return fmt.Errorf("enter a domain name rather than an IP address")
+	}
+	if !isHostname(value) || !strings.Contains(value, ".") && value != "localhost" {
+		return fmt.Errorf("%q is not a valid domain name", value)
+	}
+	return nil
+}
+
+// isHostname reports whether value is made of letters, digits and hyphens in
+// dot separated labels of up to 63 characters, without leading or trailing
+// hyphens; a trailing dot is allowed
+func isHostname(value string) bool {
+	value = strings.TrimSuffix(value, ".")
+	if value == "" || len(value) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(value, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Int accepts a whole number from min to max
+func Int(min, max int) Check {
+	return func(value string) error {
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || n < min || n > max {
+			return fmt.Errorf("enter a whole number from %d to %d", min, max)
+		}
+		return nil
+	}
+}
+
+// Seconds accepts a number of seconds above zero and up to max, such as 0.5
+func Seconds(max float64) Check {
+	return func(value string) error {
+		seconds, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || seconds <= 0 || seconds > max {
+			return fmt.Errorf("enter a number of seconds above 0 and up to %g, e.g. 0.5", max)
+		}
+		return nil
+	}
+}
+
+// OneOf accepts one of options, ignoring case
+func OneOf(options ...string) Check {
+	return func(value string) error {
+		value = strings.TrimSpace(value)
+		for _, option := range options {
+			if strings.EqualFold(value, option) {
+				return nil
+			}
+		}
+		return fmt.Errorf("enter one of %s", strings.Join(options, ", "))
+	}
+}
+
+// Port accepts a TCP or UDP port number
+func Port(value string) error {
+	return Int(1, 65535)(value)
+}
+
+// CIDR accepts an address, or a CIDR range such as 192.168.1.0/24 of at
+// most maxHosts addresses besides the network and broadcast addresses
+func CIDR(maxHosts int) Check {
+	return func(value string) error {
+		value = strings.TrimSpace(value)
+		if !strings.Contains(value, "/") {
+			if net.ParseIP(value) == nil {
+				return fmt.Errorf("enter an address or a CIDR range such as 192.168.1.0/24")
+			}
+			return nil
+		}
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return fmt.Errorf("enter an address or a CIDR range such as 192.168.1.0/24")
+		}
+		ones, bits := network.Mask.Size()
+		if bits-ones > 62 || 1<<uint(bits-ones) > maxHosts+2 {
+			return fmt.Errorf("the range has more than %d addresses", maxHosts)
+		}
+		return nil
+	}
+}
+
+// DNSServer accepts an IP address, an IP address and port, or system for the
+// resolvers of the operating system
+func DNSServer(value string) error {
+	value = strings.TrimSpace(value)
+	if value == domain.SystemDNSServer || net.ParseIP(value) != nil {
+		return nil
+	}
+	if host, port, err := net.SplitHostPort(value); err == nil && net.ParseIP(host) != nil && Port(port) == nil {
+		return nil
+	}
+	return fmt.Errorf("enter an IP address, IP:port or %s", domain.SystemDNSServer)
+}
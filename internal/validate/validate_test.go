@@ -0,0 +1,63 @@
+// Package validate provides form value check tests
+package validate
+
+import "testing"
+
+func TestChecks(t *testing.T) {
+	tests := []struct {
+		name  string
+		check Check
+		value string
+		valid bool
+	}{
+		{"hostname", Host, "example.com", true},
+		{"IPv4", Host, "8.8.8.8", true},
+		{"IPv6", Host, "2001:db8::1", true},
+		{"single label", Host, "router", true},
+		{"trailing dot", Host, "example.com.", true},
+		{"space", Host, "example .com", false},
+		{"list", Host, "a.example,b.example", false},
+		{"leading hyphen", Host, "-bad.example", false},
+		{"empty label", Host, "bad..example", false},
+		{"domain", Domain, "example.co.uk", true},
+		{"localhost", Domain, "localhost", true},
+		{"domain without dot", Domain, "example", false},
+		{"domain as IP", Domain, "1.1.1.1", false},
+		{"count", Int(1, 9999), "4", true},
+		{"count zero", Int(1, 9999), "0", false},
+		{"count text", Int(1, 9999), "four", false},
+		{"interval", Seconds(60), "0.5", true},
+		{"interval zero", Seconds(60), "0", false},
+		{"interval too long", Seconds(60), "61", false},
+		{"mode", OneOf("normal", "adaptive", "flood"), "Flood", true},
+		{"unknown mode", OneOf("normal", "adaptive", "flood"), "fast", false},
+		{"port", Port, "443", true},
+		{"port too high", Port, "70000", false},
+		{"range", CIDR(1024), "192.168.1.0/24", true},
+		{"single address", CIDR(1024), "10.0.0.1", true},
+		{"range too large", CIDR(1024), "10.0.0.0/8", false},
+		{"IPv6 range too large", CIDR(1024), "2001:db8::/32", false},
+		{"not a range", CIDR(1024), "10.0.0.0/33", false},
+		{"server", DNSServer, "1.1.1.1", true},
+		{"server with port", DNSServer, "[2606:4700::1111]:53", true},
+		{"system server", DNSServer, "system", true},
+		{"server hostname", DNSServer, "dns.google", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.check(tt.value)
+			if tt.valid && err != nil {
+				t.Errorf("%q: unexpected error %v", tt.value, err)
+			}
+			if !tt.valid && err == nil {
+				t.Errorf("%q: expected an error", tt.value)
+			}
+		})
+	}
+}
+
+func TestInt_Message(t *testing.T) {
+	if err := Int(1, 255)("300"); err == nil || err.Error() != "enter a whole number from 1 to 255" {
+		t.Errorf("Int(1, 255) error = %v", err)
+	}
+}
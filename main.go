@@ -2,116 +2,501 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/nettracex/nettracex-tui/internal/agent"
+	"github.com/nettracex/nettracex-tui/internal/batch"
+	"github.com/nettracex/nettracex-tui/internal/cast"
+	"github.com/nettracex/nettracex-tui/internal/colors"
+	"github.com/nettracex/nettracex-tui/internal/completion"
 	"github.com/nettracex/nettracex-tui/internal/config"
+	"github.com/nettracex/nettracex-tui/internal/events"
 	"github.com/nettracex/nettracex-tui/internal/domain"
+	"github.com/nettracex/nettracex-tui/internal/geo"
+	"github.com/nettracex/nettracex-tui/internal/i18n"
+	"github.com/nettracex/nettracex-tui/internal/logging"
+	"github.com/nettracex/nettracex-tui/internal/metrics"
+	"github.com/nettracex/nettracex-tui/internal/notify"
 	"github.com/nettracex/nettracex-tui/internal/network"
+	"github.com/nettracex/nettracex-tui/internal/plugins"
+	"github.com/nettracex/nettracex-tui/internal/policy"
+	"github.com/nettracex/nettracex-tui/internal/progressbar"
+	"github.com/nettracex/nettracex-tui/internal/scenario"
+	"github.com/nettracex/nettracex-tui/internal/secrets"
+	"github.com/nettracex/nettracex-tui/internal/session"
+	"github.com/nettracex/nettracex-tui/internal/targets"
+	"github.com/nettracex/nettracex-tui/internal/tools/axfr"
 	"github.com/nettracex/nettracex-tui/internal/tools/dns"
+	"github.com/nettracex/nettracex-tui/internal/tools/dualstack"
 	"github.com/nettracex/nettracex-tui/internal/tools/ping"
 	"github.com/nettracex/nettracex-tui/internal/tools/ssl"
+	"github.com/nettracex/nettracex-tui/internal/tools/sweep"
 	"github.com/nettracex/nettracex-tui/internal/tools/traceroute"
 	"github.com/nettracex/nettracex-tui/internal/tools/whois"
+	"github.com/nettracex/nettracex-tui/internal/telemetry"
+	"github.com/nettracex/nettracex-tui/internal/tracing"
 	"github.com/nettracex/nettracex-tui/internal/tui"
+	"github.com/nettracex/nettracex-tui/internal/update"
 	"github.com/nettracex/nettracex-tui/internal/version"
 )
 
+// optionList collects repeated key=value command line options
+type optionList map[string]string
 
+func (o optionList) String() string {
+	var pairs []string
+	for key, value := range o {
+		pairs = append(pairs, key+"="+value)
+	}
+	return strings.Join(pairs, ",")
+}
 
-// SimplePluginRegistry implements a basic plugin registry
-type SimplePluginRegistry struct {
-	tools map[string]domain.DiagnosticTool
+func (o optionList) Set(option string) error {
+	key, value, err := batch.ParseOption(option)
+	if err != nil {
+		return err
+	}
+	o[key] = value
+	return nil
 }
 
-func NewSimplePluginRegistry() *SimplePluginRegistry {
-	return &SimplePluginRegistry{
-		tools: make(map[string]domain.DiagnosticTool),
+// probeList collects repeated tool:target metrics probes
+type probeList []metrics.Probe
+
+func (p *probeList) String() string {
+	var probes []string
+	for _, probe := range *p {
+		probes = append(probes, probe.String())
 	}
+	return strings.Join(probes, ",")
 }
 
-func (r *SimplePluginRegistry) Register(tool domain.DiagnosticTool) error {
-	r.tools[tool.Name()] = tool
+func (p *probeList) Set(value string) error {
+	probe, err := metrics.ParseProbe(value)
+	if err != nil {
+		return err
+	}
+	*p = append(*p, probe)
 	return nil
 }
 
-func (r *SimplePluginRegistry) Get(name string) (domain.DiagnosticTool, bool) {
-	tool, exists := r.tools[name]
-	return tool, exists
+// agentList collects repeated name=address agents the TUI connects to
+type agentList []string
+
+func (a *agentList) String() string {
+	return strings.Join(*a, ",")
 }
 
-func (r *SimplePluginRegistry) List() []domain.DiagnosticTool {
-	var tools []domain.DiagnosticTool
-	for _, tool := range r.tools {
-		tools = append(tools, tool)
+func (a *agentList) Set(value string) error {
+	if _, _, err := agent.ParseAddress(value); err != nil {
+		return err
 	}
-	return tools
+	*a = append(*a, value)
+	return nil
 }
 
-func (r *SimplePluginRegistry) Unregister(name string) error {
-	delete(r.tools, name)
-	return nil
+// batchSettings holds the command line settings for a batch run
+type batchSettings struct {
+	tool        string
+	targets     string
+	format      string
+	output      string
+	concurrency int
+	acknowledge bool
+	options     optionList
+}
+
+// runBatch runs one tool against a target list and writes the aggregated report.
+// It returns the number of targets that failed.
+func runBatch(registry domain.PluginRegistry, logger domain.Logger, settings batchSettings) (int, error) {
+	tool, exists := registry.Get(settings.tool)
+	if !exists {
+		return 0, fmt.Errorf("unknown tool: %s", settings.tool)
+	}
+
+	format, err := batch.ParseFormat(settings.format)
+	if err != nil {
+		return 0, err
+	}
+
+	targets, err := batch.LoadTargets(settings.targets, os.Stdin)
+	if err != nil {
+		return 0, err
+	}
+	if len(targets) == 0 {
+		return 0, fmt.Errorf("target list is empty")
+	}
+
+	options := make(map[string]string, len(settings.options)+1)
+	for key, value := range settings.options {
+		options[key] = value
+	}
+	if settings.acknowledge {
+		options[policy.AcknowledgeParam] = "true"
+	}
+
+	// Interrupting the batch reports the targets completed so far
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Show progress on stderr only when someone watches it, not in logs
+	runner := batch.NewRunner(tool, options, settings.concurrency, logger)
+	info, err := os.Stderr.Stat()
+	interactive := err == nil && info.Mode()&os.ModeCharDevice != 0
+	if interactive {
+		bar := progressbar.New(len(targets))
+		bar.SetWidth(40)
+		runner.OnProgress(func(done, total int) {
+			bar.Set(done)
+			fmt.Fprintf(os.Stderr, "\r%s%d/%d targets  %s", ansi.EraseEntireLine, done, total, bar.StaticView())
+		})
+	}
+
+	result := runner.Run(ctx, targets)
+	if interactive {
+		fmt.Fprint(os.Stderr, "\r"+ansi.EraseEntireLine)
+	}
+	if err := batch.WriteReport(result, format, settings.output, os.Stdout); err != nil {
+		return 0, err
+	}
+
+	return result.Data().(domain.BatchResult).Failed(), nil
+}
+
+// runScenario runs a scenario file and writes its pass/fail report. It
+// returns whether every step passed.
+func runScenario(registry domain.PluginRegistry, logger domain.Logger, path string, settings batchSettings) (bool, error) {
+	loaded, err := scenario.Load(path)
+	if err != nil {
+		return false, err
+	}
+
+	format, err := batch.ParseFormat(settings.format)
+	if err != nil {
+		return false, err
+	}
+
+	shared := make(map[string]string, len(settings.options)+1)
+	for key, value := range settings.options {
+		shared[key] = value
+	}
+	if settings.acknowledge {
+		shared[policy.AcknowledgeParam] = "true"
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	result := scenario.NewRunner(registry, shared, logger).Run(ctx, loaded)
+	if err := batch.WriteReport(result, format, settings.output, os.Stdout); err != nil {
+		return false, err
+	}
+
+	return result.Data().(domain.ScenarioResult).Passed(), nil
+}
+
+// runMetrics serves Prometheus metrics on addr while the probes run on every
+// interval, until the process is interrupted. Threshold breaches are sent to
+// the notifiers in notifyConfig.
+func runMetrics(registry domain.PluginRegistry, logger domain.Logger, addr string, probes probeList, interval time.Duration, settings batchSettings, notifyConfig domain.NotifyConfig) error {
+	options := make(map[string]string, len(settings.options)+1)
+	for key, value := range settings.options {
+		options[key] = value
+	}
+	if settings.acknowledge {
+		options[policy.AcknowledgeParam] = "true"
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	metricsRegistry := metrics.NewRegistry()
+	scheduler := metrics.NewScheduler(registry, probes, options, interval, metrics.NewExporter(metricsRegistry), logger)
+
+	monitor, err := notify.NewMonitor(notifyConfig, logger)
+	if err != nil {
+		return err
+	}
+	if monitor.Enabled() {
+		scheduler.AddObserver(monitor)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- metrics.Serve(ctx, addr, metricsRegistry)
+	}()
+	logger.Info("Serving metrics", "address", addr, "path", "/metrics")
+
+	if err := scheduler.Run(ctx); err != nil {
+		stop()
+		<-serveErr
+		return err
+	}
+	return <-serveErr
 }
 
-// SimpleTheme implements a basic theme
-type SimpleTheme struct{}
+// agentToken returns the shared agent token from the environment, else
+// from the keychain
+func agentToken() string {
+	if token := os.Getenv(agent.TokenEnv); token != "" {
+		return token
+	}
+	token, _ := secrets.Default().Get(config.AgentTokenSecret)
+	return token
+}
 
-func (t *SimpleTheme) GetColor(element string) string {
-	colors := map[string]string{
-		"primary":   "#62a0ea",
-		"secondary": "#f6d32d",
-		"success":   "#26a269",
-		"warning":   "#f57c00",
-		"error":     "#e01b24",
-		"text":      "#ffffff",
-		"background": "#1e1e1e",
+// runAgent serves the registered tools to remote clients on addr until the
+// process is interrupted. Without a token it refuses to start unless
+// insecure allows unauthenticated calls.
+func runAgent(registry domain.PluginRegistry, logger domain.Logger, addr, name, certFile, keyFile string, insecure bool) error {
+	if name == "" {
+		name, _ = os.Hostname()
 	}
-	if color, exists := colors[element]; exists {
-		return color
+	token := agentToken()
+	if token == "" {
+		if !insecure {
+			return fmt.Errorf("no agent token; set %s or the %s keychain secret, or pass -agent-insecure to accept unauthenticated calls", agent.TokenEnv, config.AgentTokenSecret)
+		}
+		logger.Warn("Agent accepts calls without a token", "variable", agent.TokenEnv, "keychain", config.AgentTokenSecret)
 	}
-	return "#ffffff"
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.Info("Serving agent", "name", name, "address", addr, "tls", certFile != "")
+	return agent.NewServer(name, registry, token, logger).Serve(ctx, addr, certFile, keyFile)
 }
 
-func (t *SimpleTheme) GetStyle(element string) map[string]interface{} {
-	return make(map[string]interface{})
+// connectAgents makes every registered tool also run on agents and warns
+// about agents that cannot be reached now. The token is only sent over TLS
+// unless allowPlaintext is set.
+func connectAgents(registry *plugins.Registry, logger domain.Logger, agents agentList, allowPlaintext bool) error {
+	token := agentToken()
+	var clients []*agent.Client
+	for _, value := range agents {
+		name, address, err := agent.ParseAddress(value)
+		if err != nil {
+			return err
+		}
+		client, err := agent.NewClient(name, address, token, allowPlaintext)
+		if err != nil {
+			return err
+		}
+		clients = append(clients, client)
+	}
+
+	for _, client := range clients {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		info, err := client.Info(ctx)
+		cancel()
+		if err != nil {
+			logger.Warn("Agent is not reachable", "agent", client.Name(), "error", err)
+			continue
+		}
+		logger.Info("Connected to agent", "agent", client.Name(), "version", info.Version, "tools", len(info.Tools))
+	}
+	registry.Wrap(func(tool domain.DiagnosticTool) domain.DiagnosticTool {
+		return agent.Distribute(tool, clients)
+	})
+	return nil
 }
 
-func (t *SimpleTheme) SetColor(element, color string) {
-	// Not implemented for simple theme
+// playSession plays the session recorded to path on the terminal until it
+// ends or the process is interrupted
+func playSession(path string, options cast.Options) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Hide the cursor while the frames are drawn
+	fmt.Print("\x1b[?25l")
+	defer fmt.Print("\x1b[?25h\r\n")
+	err = cast.Play(ctx, file, os.Stdout, options)
+	if err == context.Canceled {
+		return nil
+	}
+	return err
 }
 
-// SimpleLogger implements a basic logger
-type SimpleLogger struct{}
+// runUpdate checks for a newer release and installs it in place of the
+// running binary unless only a check is asked for
+func runUpdate(args []string) error {
+	flags := flag.NewFlagSet("update", flag.ExitOnError)
+	checkOnly := flags.Bool("check", false, "Only report whether a newer release exists")
+	force := flags.Bool("force", false, "Install the latest release even when it is not newer")
+	channelName := flags.String("channel", "", "Release channel to update from: "+strings.Join(update.ChannelNames(), ", ")+" (default ui.update_channel)")
+	flags.Parse(args)
+
+	if *channelName == "" {
+		configManager := config.NewManager()
+		if err := configManager.Load(); err == nil {
+			*channelName = configManager.GetConfig().UI.UpdateChannel
+		}
+	}
+	channel, err := update.ParseChannel(*channelName)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	current := version.Get().Version
+	updater := update.New()
+	updater.Channel = channel
+	release, newer, err := updater.Check(ctx, current)
+	if err != nil {
+		return err
+	}
+	if !newer && !*force {
+		fmt.Printf("NetTraceX %s is up to date (latest release: %s)\n", current, release.Tag)
+		return nil
+	}
+	fmt.Printf("NetTraceX %s is available (running %s): %s\n", release.Tag, current, release.URL)
+	if *checkOnly {
+		return nil
+	}
 
-func (l *SimpleLogger) Debug(msg string, fields ...interface{}) {
-	log.Printf("[DEBUG] %s %v", msg, fields)
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the running binary: %w", err)
+	}
+	if updater.PublicKey == "" {
+		fmt.Fprintln(os.Stderr, "Warning: this build has no release public key, only the checksum is verified")
+	}
+	if err := updater.Install(ctx, release, exe); err != nil {
+		return err
+	}
+	fmt.Printf("Updated %s to %s\n", exe, release.Tag)
+	return nil
 }
 
-func (l *SimpleLogger) Info(msg string, fields ...interface{}) {
-	log.Printf("[INFO] %s %v", msg, fields)
+// runCompletion prints the completion script of the shell named in args for
+// the flags of the command line
+func runCompletion(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: nettracex completion <%s>", strings.Join(completion.Shells, "|"))
+	}
+	commands := []completion.Command{
+		{Name: "update", Usage: "Update to the latest release"},
+		{Name: "completion", Usage: "Print a shell completion script"},
+	}
+	return completion.Write(os.Stdout, args[0], "nettracex", flag.CommandLine, commands)
 }
 
-func (l *SimpleLogger) Warn(msg string, fields ...interface{}) {
-	log.Printf("[WARN] %s %v", msg, fields)
+// runMode names the mode the flags start, for telemetry
+func runMode(batchTool, scenario, metricsAddr, agentAddr string) string {
+	switch {
+	case batchTool != "":
+		return "batch"
+	case scenario != "":
+		return "scenario"
+	case metricsAddr != "":
+		return "metrics"
+	case agentAddr != "":
+		return "agent"
+	}
+	return "tui"
 }
 
-func (l *SimpleLogger) Error(msg string, fields ...interface{}) {
-	log.Printf("[ERROR] %s %v", msg, fields)
+// sendTelemetry posts the pending telemetry report once it covers the
+// configured interval; without an endpoint it is only kept locally
+func sendTelemetry(usage *telemetry.Collector, settings domain.TelemetryConfig, logger domain.Logger) {
+	interval := settings.Interval
+	if interval <= 0 {
+		interval = telemetry.DefaultInterval
+	}
+	if settings.Endpoint == "" || !usage.Due(interval) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := usage.Send(ctx, http.DefaultClient, settings.Endpoint); err != nil {
+		logger.Debug("Telemetry report not sent", "error", err)
+		return
+	}
+	logger.Info("Sent the telemetry report", "endpoint", settings.Endpoint)
 }
 
-func (l *SimpleLogger) Fatal(msg string, fields ...interface{}) {
-	log.Fatalf("[FATAL] %s %v", msg, fields)
+// showTelemetryReport prints the pending telemetry report
+func showTelemetryReport(settings domain.TelemetryConfig) error {
+	report, err := telemetry.Load(telemetry.DefaultPath())
+	if err != nil {
+		return err
+	}
+	switch {
+	case !settings.Enabled:
+		fmt.Println("Telemetry is off; set telemetry.enabled to true to opt in")
+	case settings.Endpoint == "":
+		fmt.Println("Telemetry is on and kept locally, telemetry.endpoint is not set")
+	default:
+		fmt.Printf("Telemetry is on and sent to %s every %s\n", settings.Endpoint, settings.Interval)
+	}
+	if report.Since.IsZero() {
+		fmt.Println("No report is pending")
+		return nil
+	}
+	fmt.Printf("Pending report in %s:\n\n", telemetry.DefaultPath())
+	fmt.Print(telemetry.Format(report))
+	return nil
 }
 
 func main() {
 	// Parse command line flags
 	var (
-		showVersion = flag.Bool("version", false, "Show version information")
-		showHelp    = flag.Bool("help", false, "Show help information")
+		showVersion  = flag.Bool("version", false, "Show version information")
+		showHelp     = flag.Bool("help", false, "Show help information")
+		batchRun     = batchSettings{options: optionList{}}
+		scenarioFile = flag.String("scenario", "", "Run a YAML scenario file and report pass/fail")
+		fresh        = flag.Bool("fresh", false, "Start the TUI without offering to restore the previous session")
+		metricsAddr  = flag.String("metrics", "", "Serve Prometheus metrics of scheduled probes on this address, e.g. :9090")
+		interval     = flag.Duration("interval", metrics.DefaultInterval, "How often metrics probes run")
+		otlpEndpoint = flag.String("otlp", "", "Export traces of tool executions to this OTLP/HTTP endpoint, e.g. localhost:4318")
+		agentAddr    = flag.String("agent", "", "Run headless as a remote agent serving gRPC on this address, e.g. :7443")
+		agentName    = flag.String("agent-name", "", "Name the agent reports to clients (default: hostname)")
+		agentCert    = flag.String("agent-cert", "", "TLS certificate file for the agent")
+		agentKey     = flag.String("agent-key", "", "TLS key file for the agent")
+		agentOpen    = flag.Bool("agent-insecure", false, "Let the agent accept calls without a token, or send the token to -connect agents without TLS")
+		sshVia       = flag.String("via", "", "Run ping, traceroute and DNS lookups on this SSH host, as [user@]host[:port]")
+		configFile   = flag.String("config", "", "Load the configuration from this YAML, TOML or JSON file")
+		migrateTo    = flag.String("migrate-config", "", "Convert the configuration file to this file, in the format of its extension, and exit")
+		storeSecrets = flag.Bool("store-secrets", false, "Move plain text credentials of the configuration file into the OS keychain and exit")
+		recordFile   = flag.String("record", "", "Record the responses of network operations to this fixture file")
+		replayFile   = flag.String("replay", "", "Replay the responses recorded in this fixture file instead of using the network")
+		castFile     = flag.String("record-session", "", "Record the screens and keys of the TUI session to this asciinema file")
+		playFile     = flag.String("play", "", "Play back a session recorded with -record-session")
+		playSpeed    = flag.Float64("speed", 1, "Playback speed of -play, e.g. 2 for twice as fast")
+		maxIdle      = flag.Duration("max-idle", 0, "Shorten pauses longer than this in -play, e.g. 2s")
+		showUsage    = flag.Bool("telemetry-report", false, "Show the pending usage telemetry report and exit")
+		probes       probeList
+		agents       agentList
 	)
+	flag.Var(&probes, "probe", "Metrics probe as tool:target, e.g. ping:example.com (repeatable)")
+	flag.Var(&agents, "connect", "Agent to run diagnostics from as name=address (repeatable)")
+	flag.StringVar(&batchRun.tool, "batch", "", "Run a tool against a target list instead of starting the TUI")
+	flag.StringVar(&batchRun.targets, "targets", batch.StdinPath, "Target list file for batch mode, one target per line (- for stdin)")
+	flag.StringVar(&batchRun.format, "format", "text", "Batch and scenario report format: json, csv, text, html, markdown, pdf, or junit")
+	flag.StringVar(&batchRun.output, "output", "", "Write the batch or scenario report to a file instead of stdout")
+	flag.IntVar(&batchRun.concurrency, "concurrency", batch.DefaultConcurrency, "Number of targets processed at once in batch mode")
+	flag.BoolVar(&batchRun.acknowledge, "acknowledge", false, "Acknowledge probing public targets in batch and scenario mode")
+	flag.Var(batchRun.options, "param", "Tool option as key=value for batch and scenario mode (repeatable)")
+	configFlags := config.RegisterFlags(flag.CommandLine)
 	flag.Parse()
 
 	// Handle version flag
@@ -127,80 +512,703 @@ func main() {
 		fmt.Println()
 		fmt.Println("Usage:")
 		fmt.Println("  nettracex [flags]")
+		fmt.Println("  nettracex -batch <tool> [-targets file] [-param key=value ...]")
+		fmt.Println("  nettracex -scenario <file.yaml>")
+		fmt.Println("  nettracex -metrics :9090 -probe ping:example.com [-probe ssl:example.com ...]")
+		fmt.Println("  nettracex -agent :7443")
+		fmt.Println("  nettracex -connect eu=eu.example.com:7443 [-connect us=https://us.example.com:7443 ...]")
+		fmt.Println("  nettracex -via ops@bastion.example.com")
+		fmt.Println("  nettracex -record session.json | -replay session.json")
+		fmt.Println("  nettracex -record-session incident.cast | -play incident.cast [-speed 2]")
+		fmt.Println("  nettracex update [-check] [-force]")
+		fmt.Println("  nettracex completion bash|zsh|fish")
 		fmt.Println()
 		fmt.Println("Flags:")
-		fmt.Println("  -version    Show version information")
-		fmt.Println("  -help       Show this help message")
+		fmt.Println("  -version         Show version information")
+		fmt.Println("  -help            Show this help message")
+		fmt.Println("  -fresh           Start without offering to restore the previous session")
+		fmt.Println("  -otlp <endpoint> Export traces of tool executions over OTLP/HTTP, e.g. localhost:4318")
+		fmt.Println("                   The standard OTEL_EXPORTER_OTLP_* and OTEL_SERVICE_NAME variables also apply")
+		fmt.Println()
+		fmt.Println("Batch Flags:")
+		fmt.Println("  -batch <tool>    Run a tool against a target list instead of starting the TUI")
+		fmt.Println("  -targets <file>  Target list, one per line, # for comments (default: stdin)")
+		fmt.Println("  -param key=value Tool option shared by all targets, e.g. count=10 (repeatable)")
+		fmt.Println("                   source=<interface or address> binds every tool to a NIC, VPN or VLAN")
+		fmt.Println("  -concurrency <n> Number of targets processed at once (default: 4)")
+		fmt.Println("  -format <name>   Report format: json, csv, text, html, markdown, pdf, or junit (default: text)")
+		fmt.Println("  -output <file>   Write the report to a file instead of stdout")
+		fmt.Println("                   A progress bar with the time left is drawn on stderr when it is a terminal")
+		fmt.Println("  -acknowledge     Acknowledge probing public targets with active tools")
+		fmt.Println()
+		fmt.Println("Scenario Flags:")
+		fmt.Println("  -scenario <file> Run the steps of a YAML scenario and check their assertions")
+		fmt.Println("                   Exits with status 2 when a step fails, for use in CI")
+		fmt.Println("                   -format, -output, -param and -acknowledge also apply")
+		fmt.Println()
+		fmt.Println("Metrics Flags:")
+		fmt.Println("  -metrics <addr>  Serve Prometheus metrics on addr (e.g. :9090) instead of starting the TUI")
+		fmt.Println("  -probe tool:host Probe run on every interval: ping, ssl, dns, ... (repeatable)")
+		fmt.Println("  -interval <d>    How often the probes run (default: 30s)")
+		fmt.Println("                   -param and -acknowledge also apply")
+		fmt.Println("                   Exports nettracex_ping_rtt_seconds, nettracex_packet_loss_ratio,")
+		fmt.Println("                   nettracex_cert_expiry_days and nettracex_dns_lookup_duration_seconds")
+		fmt.Println("                   Alerts for hosts down, expiring certificates and packet loss are")
+		fmt.Println("                   posted to the webhooks, Slack and Discord URLs in the notify config")
+		fmt.Println()
+		fmt.Println("Agent Flags:")
+		fmt.Println("  -agent <addr>    Run headless as a remote agent serving gRPC on addr (e.g. :7443)")
+		fmt.Println("  -agent-name <n>  Name reported to clients (default: hostname)")
+		fmt.Println("  -agent-cert <f>  TLS certificate file; without one the agent serves without TLS")
+		fmt.Println("  -agent-key <f>   TLS key file")
+		fmt.Println("  -agent-insecure  Start the agent without a token, accepting calls from anyone")
+		fmt.Println("                   who can reach it; otherwise the agent refuses to start without one")
+		fmt.Println("  -connect name=addr")
+		fmt.Println("                   Also run diagnostics on this agent and show results per agent")
+		fmt.Println("                   (repeatable); use https:// addresses for agents with TLS")
+		fmt.Println("                   Both sides read the shared token from " + agent.TokenEnv)
+		fmt.Println("                   The token is only sent over TLS; with -agent-insecure it is also")
+		fmt.Println("                   sent to plain host:port agents")
+		fmt.Println()
+		fmt.Println("SSH Flags:")
+		fmt.Println("  -via [user@]host[:port]")
+		fmt.Println("                   Run ping, traceroute and DNS lookups on this host over ssh to see")
+		fmt.Println("                   the network from inside it; needs ping, traceroute and dig there")
+		fmt.Println("                   and key-based ssh login. WHOIS and SSL checks still run locally")
+		fmt.Println("                   Works with every mode, e.g. -batch and -agent")
+		fmt.Println()
+		fmt.Println("Recording Flags:")
+		fmt.Println("  -record <file>   Save the responses of every network operation to a fixture file on exit")
+		fmt.Println("  -replay <file>   Answer from a recorded fixture instead of the network, for demos,")
+		fmt.Println("                   offline training and deterministic tests; queries that were not")
+		fmt.Println("                   recorded get synthetic responses")
+		fmt.Println("                   Both work with every mode, e.g. -batch and -scenario")
+		fmt.Println("  -record-session <file>")
+		fmt.Println("                   Record every screen drawn, the keys pressed, resizes and tool runs")
+		fmt.Println("                   of the TUI session to an asciinema v2 file, for incident reviews")
+		fmt.Println("                   and bug reports; asciinema play and the asciinema web player show it")
+		fmt.Println("  -play <file>     Play back a recorded session in the terminal and exit")
+		fmt.Println("  -speed <n>       Playback speed, e.g. 2 for twice as fast (default: 1)")
+		fmt.Println("  -max-idle <d>    Shorten pauses longer than this on playback, e.g. 2s")
+		fmt.Println()
+		fmt.Println("Update Command:")
+		fmt.Println("  update           Download the latest GitHub release for this platform, verify it")
+		fmt.Println("                   against the SHA-256 sums of checksums.txt and their ed25519")
+		fmt.Println("                   signature, and replace the running binary with it")
+		fmt.Println("  update -check    Only report whether a newer release exists")
+		fmt.Println("  update -force    Install the latest release even when it is not newer, e.g. over")
+		fmt.Println("                   a development build")
+		fmt.Println("                   The TUI looks for a newer release once a day and shows it in the")
+		fmt.Println("                   header; set ui.check_updates to false to turn this off")
+		fmt.Println("  update -channel <name>  Follow the stable, rc, beta or alpha channel; each one")
+		fmt.Println("                   includes the more stable ones, by default ui.update_channel")
+		fmt.Println()
+		fmt.Println("Completion Command:")
+		fmt.Println("  completion <shell>  Print the completion script of bash, zsh or fish, e.g.")
+		fmt.Println("                   nettracex completion bash > /etc/bash_completion.d/nettracex")
+		fmt.Println()
+		fmt.Println("Telemetry:")
+		fmt.Println("  Off unless telemetry.enabled is set. It then counts the tools run, the report")
+		fmt.Println("  formats exported, the mode started and crash signatures (the panic type and")
+		fmt.Println("  the functions it went through), nothing else: no targets, results, addresses")
+		fmt.Println("  or identifiers, and plugins are counted together. The counts are kept in")
+		fmt.Println("  telemetry.json next to the session and posted to telemetry.endpoint every")
+		fmt.Println("  telemetry.interval (default: a week); with no endpoint they stay local")
+		fmt.Println("  -telemetry-report")
+		fmt.Println("                   Show the pending report, exactly what would be sent, and exit")
+		fmt.Println()
+		fmt.Println("Configuration Flags:")
+		fmt.Println("  -config <file>   Load the configuration from this file instead of the first")
+		fmt.Println("                   nettracex.{yaml,toml,json} in ., ~/.config/nettracex or /etc/nettracex")
+		fmt.Println("  -migrate-config <file>")
+		fmt.Println("                   Convert the configuration file to the format of the file's extension")
+		fmt.Println("                   (.yaml, .toml or .json), keeping comments where possible, and exit")
+		fmt.Println("  -store-secrets   Move plain text webhook and proxy credentials of the configuration")
+		fmt.Println("                   file into the OS keychain, leaving keychain:<key> references, and exit")
+		fmt.Println("                   The agent token is read from NETTRACEX_AGENT_TOKEN, else from the")
+		fmt.Println("                   keychain; set it in the Secrets section of the settings screen")
+		fmt.Println("  -<key> <value>   Override any configuration key, e.g. -network.timeout 5s")
+		fmt.Println("                   or -network.dns_servers 9.9.9.9,1.1.1.1; every key can also be")
+		fmt.Println("                   set as NETTRACEX_<KEY>, e.g. NETTRACEX_NETWORK_TIMEOUT=5s")
+		fmt.Println("                   Precedence: settings > flag > env > file > default; the")
+		fmt.Println("                   settings screen shows where each value comes from")
+		fmt.Println("                   Files written by older versions are upgraded when loaded; the")
+		fmt.Println("                   original is kept next to it as <file>.v<N>.bak")
+		line := "                   Keys:"
+		for _, key := range config.Keys() {
+			if len(line)+len(key) > 90 {
+				fmt.Println(line)
+				line = "                  "
+			}
+			line += " " + key
+		}
+		fmt.Println(line)
+		fmt.Println()
+		fmt.Println("Plugins:")
+		fmt.Println("  Executables named nettracex-<name> in plugins.plugin_paths (default: ./plugins)")
+		fmt.Println("  are started at launch and added to the menu as tools, with a form built from the")
+		fmt.Println("  parameters they describe. They speak JSON-RPC on stdin and stdout; see internal/plugins")
+		fmt.Println("  and can subscribe to tool.started, tool.result, tool.error and result.exported events")
+		fmt.Println("  WebAssembly modules named nettracex-<name>.wasm run sandboxed in-process, without")
+		fmt.Println("  access to files, processes or the network beyond host name lookups")
+		fmt.Println("  Check scripts named nettracex-<name>.star define check(params) in Starlark and")
+		fmt.Println("  may call ping, dns, tcp and http; see internal/plugins/script.go")
+		fmt.Println("  plugins.commands wraps external commands such as dig or nmap as tools: each entry")
+		fmt.Println("  has a name, a command, args templated like {{.host}}, parameters, and parse set to")
+		fmt.Println("  text, json or regex (rows from the named groups of pattern)")
+		fmt.Println("  plugins.enabled_plugins and plugins.disabled_plugins choose which ones load, and")
+		fmt.Println("  plugins.plugin_settings.<name> is passed to the plugin when it starts")
+		fmt.Println("  The Plugins screen shows their version, health and load errors and enables or disables them")
 		fmt.Println()
 		fmt.Println("Interactive Mode:")
 		fmt.Println("  Run without flags to start the interactive TUI")
-		fmt.Println("  Available tools: WHOIS, Ping, DNS, Traceroute, SSL")
+		fmt.Println("  The open tool, entered targets and results are saved on exit and")
+		fmt.Println("  can be restored on the next launch")
+		fmt.Println("  Edits to the configuration file are applied while the TUI runs")
+		fmt.Println("  ctrl+t opens a tab, ctrl+w closes it, ctrl+PgUp/PgDown or alt+1..9 switch tabs;")
+		fmt.Println("  each tab keeps its own tool and results, and tools keep running in background tabs")
+		fmt.Println("  esc while a tool runs keeps it running as a background job; Background Jobs shows")
+		fmt.Println("  their progress and re-attaches (enter), cancels (x) or dismisses (d) them")
+		fmt.Println("  ? lists the keys of the screen shown and those working everywhere, as bound;")
+		fmt.Println("  enter there opens the guide with tips and troubleshooting")
+		fmt.Println("  ctrl+p opens the command palette to fuzzy-search and run tools, recent targets,")
+		fmt.Println("  themes, config reloads, tab actions and exports of the shown result")
+		fmt.Println("  keys.<action> remaps keys, e.g. keys.down: [ctrl+n, down]; a key may be bound to")
+		fmt.Println("  one action only, and the Keys settings section edits them")
+		fmt.Println("  Themes: auto (default; dark or light to suit the terminal background, from")
+		fmt.Println("  OSC 11 or COLORFGBG), default, dark, light, solarized, high-contrast, minimal")
+		fmt.Println("  Theme files <config dir>/themes/<name>.yaml add themes for ui.theme: extends a")
+		fmt.Println("  theme and sets colors, border (rounded, normal, thick, double, hidden, none) and")
+		fmt.Println("  styles such as header: {bold: true}; editing ui.theme previews it, tab cycles")
+		fmt.Println("  ui.color_mode: auto (default; the colors of the terminal, none if NO_COLOR is set),")
+		fmt.Println("  always or never; 8 and 16-color terminals get the closest base colors")
+		fmt.Println("  ui.language: auto (default; from LC_ALL, LC_MESSAGES or LANG), en, de, es or ja")
+		fmt.Println("  translates the menu, help, footer and key hints; applies on the next launch")
+		fmt.Println("  ui.accessible: plain text without borders, emoji or colors in the normal screen")
+		fmt.Println("  buffer for screen readers; ui.reduced_motion stops spinners and ticking refreshes")
+		fmt.Println("  Results and tables: / searches and highlights, & shows only matching lines or rows,")
+		fmt.Println("  tab in the prompt switches to a regular expression, n/N jump between matches")
+		fmt.Println("  Result tables: ←/→ pick a column, s sorts it, x/X hide and show columns,")
+		fmt.Println("  </> change its width and [/] move it; layouts are kept per tool in tables.json")
+		fmt.Println("  Host fields: ↓ picks a recent, starred, /etc/hosts or ~/.ssh/config target,")
+		fmt.Println("  tab fills it in and ctrl+s stars the target; targets are kept in targets.json")
+		fmt.Println("  ctrl+l shows the latest log records below the screen as they arrive; d/i/w/e show")
+		fmt.Println("  those at or above debug, info, warn or error, esc or ctrl+l hides the panel")
+		fmt.Println("  Mouse: click a tab, a table row or a form field to select it; the wheel scrolls")
+		fmt.Println("  menus, pagers, tables and the log panel")
+		fmt.Println("  ui.key_mode: vi adds gg/G jumps, / search (n/N repeat) and a : command line")
+		fmt.Println("  (:ping, :settings, :42, :tabnew, :q) to lists, tables and pagers")
+		fmt.Println("  The dashboard opens first (ui.dashboard.show_on_start) with the last results,")
+		fmt.Println("  certificates, tool:target checks and favorite hosts listed under ui.dashboard")
+		fmt.Println("  Available tools: whois, ping, dns, traceroute, ssl, dualstack, sweep, axfr, and plugins")
+		return
+	}
+
+	// Update the binary instead of starting the TUI when requested
+	if flag.Arg(0) == "update" {
+		if err := runUpdate(flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Update failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Print a shell completion script when requested
+	if flag.Arg(0) == "completion" {
+		if err := runCompletion(flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Completion failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Play back a recorded session instead of starting the TUI when requested
+	if *playFile != "" {
+		if err := playSession(*playFile, cast.Options{Speed: *playSpeed, MaxIdle: *maxIdle}); err != nil {
+			fmt.Fprintf(os.Stderr, "Playback failed: %v\n", err)
+			os.Exit(1)
+		}
 		return
 	}
 
 	// Initialize configuration manager
 	configManager := config.NewManager()
-	if err := configManager.Load(); err != nil {
+	configManager.BindFlags(configFlags)
+	if *configFile != "" {
+		if err := configManager.LoadFromFile(*configFile); err != nil {
+			log.Fatalf("Failed to load configuration: %v", err)
+		}
+	} else if err := configManager.Load(); err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
+	if upgrade := configManager.Upgraded(); upgrade != nil {
+		fmt.Fprintf(os.Stderr, "Upgraded %s from configuration version %d to %d", upgrade.File, upgrade.From, upgrade.To)
+		if upgrade.Backup != "" {
+			fmt.Fprintf(os.Stderr, ", the previous file is saved as %s", upgrade.Backup)
+		}
+		fmt.Fprintln(os.Stderr)
+		if upgrade.Err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: the upgrade only applies to this run: %v\n", upgrade.Err)
+		}
+	}
+	
+	// Convert the configuration file to another format when requested
+	if *migrateTo != "" {
+		if err := configManager.Migrate(*migrateTo); err != nil {
+			log.Fatalf("Failed to migrate configuration: %v", err)
+		}
+		fmt.Printf("Converted %s to %s\n", configManager.GetConfigFile(), *migrateTo)
+		return
+	}
+	
+	// Move plain text credentials into the keychain when requested
+	if *storeSecrets {
+		if configManager.GetConfigFile() == "" {
+			log.Fatalf("Failed to store secrets: no configuration file loaded")
+		}
+		moved, err := configManager.StoreSecrets()
+		if err != nil {
+			log.Fatalf("Failed to store secrets: %v", err)
+		}
+		if err := configManager.Save(); err != nil {
+			log.Fatalf("Failed to save configuration: %v", err)
+		}
+		for _, key := range moved {
+			fmt.Printf("Moved %s to the keychain\n", key)
+		}
+		if len(moved) == 0 {
+			fmt.Println("No plain text secrets found")
+		}
+		return
+	}
 	
 	cfg := configManager.GetConfig()
 	
-	// Initialize logger
-	logger := &SimpleLogger{}
+	// Show what the next telemetry report would send when requested
+	if *showUsage {
+		if err := showTelemetryReport(cfg.Telemetry); err != nil {
+			log.Fatalf("Failed to show the telemetry report: %v", err)
+		}
+		return
+	}
+	
+	// Degrade colors to what the terminal shows, or drop them
+	colorMode := cfg.UI.ColorMode
+	if cfg.UI.Accessible {
+		colorMode = colors.ModeNever
+	}
+	colors.Apply(colorMode)
+	
+	// Show the TUI in the configured language or that of the locale
+	if err := i18n.SetLanguage(i18n.Resolve(cfg.UI.Language, os.Getenv)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	
+	// Initialize logger from the logging settings
+	logger, err := logging.New(cfg.Logging)
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Close()
+	
+	// Keep the latest records for the log panel of the TUI
+	logBuffer := logging.NewBuffer(logging.DefaultBufferSize)
+	logger.SetBuffer(logBuffer)
 	
 	// Initialize network client (using nil for error handler for now)
 	networkClient := network.NewClient(&cfg.Network, nil, logger)
 	
-	// Initialize plugin registry
-	registry := NewSimplePluginRegistry()
+	// Run the network commands of the tools on the SSH host when one is given
+	var toolClient domain.NetworkClient = networkClient
+	if *sshVia != "" {
+		sshClient, err := network.NewSSHClient(networkClient, *sshVia)
+		if err != nil {
+			log.Fatalf("Invalid -via target: %v", err)
+		}
+		logger.Info("Running network commands via SSH", "target", sshClient.Target())
+		toolClient = sshClient
+	}
+	
+	// Answer from a recording, or record the live responses, when requested
+	if *replayFile != "" && *recordFile != "" {
+		log.Fatalf("-record and -replay cannot be combined")
+	}
+	if *replayFile != "" {
+		replayClient, err := network.LoadReplayClient(*replayFile)
+		if err != nil {
+			log.Fatalf("Failed to load -replay fixture: %v", err)
+		}
+		logger.Info("Replaying recorded responses", "file", *replayFile)
+		toolClient = replayClient
+	}
+	saveRecording := func() {}
+	if *recordFile != "" {
+		recorder := network.NewRecorder(toolClient)
+		logger.Info("Recording responses", "file", *recordFile)
+		toolClient = recorder
+		saveRecording = func() {
+			if err := recorder.Save(*recordFile); err != nil {
+				log.Printf("Failed to save recording: %v", err)
+			}
+		}
+	}
+	defer saveRecording()
+	
+	// Probe privileged operations up front so tools pick their implementation
+	// and degraded modes are explained once
+	if reporter, ok := toolClient.(domain.CapabilityReporter); ok {
+		capabilities := reporter.Capabilities()
+		logger.Info("Probed capabilities", "platform", capabilities.Platform, "privileged", capabilities.Privileged, "icmp", capabilities.ICMP)
+		if !capabilities.ICMP {
+			for _, note := range capabilities.Notes {
+				logger.Warn("Degraded mode", "note", note)
+			}
+		}
+	}
+	
+	// Initialize target policy for active scanning tools
+	targetPolicy, err := policy.NewTargetPolicy(cfg.Policy, policy.NewAuditLog(cfg.Policy.AuditLog))
+	if err != nil {
+		log.Fatalf("Failed to initialize target policy: %v", err)
+	}
+	
+	// Initialize the tool registry
+	registry := plugins.NewRegistry(logger)
 	
 	// Register WHOIS tool
-	whoisTool := whois.NewTool(networkClient, logger)
-	if err := registry.Register(whoisTool); err != nil {
+	whoisTool := whois.NewTool(toolClient, logger)
+	if err := registry.RegisterWith(whoisTool, plugins.WithCategory(plugins.CategoryDNS), plugins.WithPriority(10)); err != nil {
 		log.Fatalf("Failed to register WHOIS tool: %v", err)
 	}
 	
 	// Register Ping tool
-	pingTool := ping.NewTool(networkClient, logger)
-	if err := registry.Register(pingTool); err != nil {
+	pingTool := ping.NewTool(toolClient, logger)
+	if err := registry.RegisterWith(targetPolicy.Guard(pingTool), plugins.WithCategory(plugins.CategoryConnectivity), plugins.WithPriority(20)); err != nil {
 		log.Fatalf("Failed to register Ping tool: %v", err)
 	}
 	
 	// Register DNS tool
-	dnsTool := dns.NewTool(networkClient, logger)
-	if err := registry.Register(dnsTool); err != nil {
+	dnsTool := dns.NewTool(toolClient, logger)
+	if err := registry.RegisterWith(dnsTool, plugins.WithCategory(plugins.CategoryDNS), plugins.WithPriority(30)); err != nil {
 		log.Fatalf("Failed to register DNS tool: %v", err)
 	}
 	
 	// Register Traceroute tool
-	tracerouteTool := traceroute.NewTool(networkClient, logger)
-	if err := registry.Register(tracerouteTool); err != nil {
+	tracerouteTool := traceroute.NewTool(toolClient, logger)
+	geoService := geo.NewService(&cfg.Network, logger)
+	tracerouteTool.SetGeoLocationService(geoService)
+	if err := registry.RegisterWith(targetPolicy.Guard(tracerouteTool), plugins.WithCategory(plugins.CategoryConnectivity), plugins.WithPriority(40)); err != nil {
 		log.Fatalf("Failed to register Traceroute tool: %v", err)
 	}
 	
+	// Apply network settings changed in the settings screen or the
+	// configuration file without restarting; operations already running
+	// finish with the settings they started with
+	configManager.AddChangeListener(func(key string, oldValue, newValue interface{}) {
+		if strings.HasPrefix(key, "network.") {
+			networkConfig := configManager.GetNetworkConfig()
+			networkClient.Reconfigure(networkConfig)
+			geoService.Reconfigure(networkConfig)
+		}
+	})
+	
 	// Register SSL tool
-	sslTool := ssl.NewTool(networkClient, logger)
-	if err := registry.Register(sslTool); err != nil {
+	sslTool := ssl.NewTool(toolClient, logger)
+	if err := registry.RegisterWith(sslTool, plugins.WithCategory(plugins.CategorySecurity), plugins.WithPriority(50)); err != nil {
 		log.Fatalf("Failed to register SSL tool: %v", err)
 	}
 	
-	// Initialize theme
-	theme := &SimpleTheme{}
+	// Register dual-stack comparison tool
+	dualStackTool := dualstack.NewTool(toolClient, logger)
+	if err := registry.RegisterWith(targetPolicy.Guard(dualStackTool), plugins.WithCategory(plugins.CategoryConnectivity), plugins.WithPriority(60)); err != nil {
+		log.Fatalf("Failed to register dual-stack tool: %v", err)
+	}
+	
+	// Register ping sweep tool
+	sweepTool := sweep.NewTool(toolClient, logger)
+	if err := registry.RegisterWith(targetPolicy.Guard(sweepTool), plugins.WithCategory(plugins.CategoryConnectivity), plugins.WithPriority(70)); err != nil {
+		log.Fatalf("Failed to register ping sweep tool: %v", err)
+	}
+	
+	// Register zone transfer check tool
+	axfrTool := axfr.NewTool(toolClient, logger)
+	if err := registry.RegisterWith(targetPolicy.Guard(axfrTool), plugins.WithCategory(plugins.CategorySecurity), plugins.WithPriority(80)); err != nil {
+		log.Fatalf("Failed to register zone transfer tool: %v", err)
+	}
+	
+	// Register external tools found in the plugin paths
+	pluginInventory := plugins.LoadInventory(cfg.Plugins, toolClient, targetPolicy, logger)
+	for _, err := range pluginInventory.Errors() {
+		logger.Warn("Failed to load plugin", "error", err)
+	}
+	for _, tool := range pluginInventory.Tools() {
+		if err := registry.RegisterWith(targetPolicy.Guard(tool), plugins.WithCategory(plugins.CategoryPlugin)); err != nil {
+			logger.Warn("Plugin tool name is already taken", "tool", tool.Name(), "plugin", tool.Path())
+			pluginInventory.Reject(tool, fmt.Errorf("tool name %s is already taken", tool.Name()))
+		}
+	}
+	pluginTools := pluginInventory.Tools()
+	defer plugins.Close(pluginTools)
+	
+	// Bind network operations to the interface or address chosen per run
+	registry.Wrap(network.BindSource)
+	
+	// Publish tool runs and exports to the log and to plugins that subscribe
+	bus := events.NewBus()
+	registry.Wrap(func(tool domain.DiagnosticTool) domain.DiagnosticTool {
+		return events.Observe(bus, tool)
+	})
+	bus.Subscribe(func(event events.Event) {
+		fields := []interface{}{"kind", event.Kind, "tool", event.Tool}
+		if event.Target != "" {
+			fields = append(fields, "target", event.Target)
+		}
+		if event.Duration > 0 {
+			fields = append(fields, "duration", event.Duration)
+		}
+		if event.Err != nil {
+			fields = append(fields, "error", event.Err)
+		}
+		if event.Path != "" {
+			fields = append(fields, "path", event.Path)
+		}
+		logger.Info("Tool event", fields...)
+	})
+	plugins.Subscribe(bus, pluginTools)
+	
+	// Count the features used and crashes when the user opted in
+	var usage *telemetry.Collector
+	saveUsage := func() {}
+	if cfg.Telemetry.Enabled {
+		usage, err = telemetry.Open(telemetry.DefaultPath(), version.Get().Version)
+		if err != nil {
+			logger.Warn("Telemetry is off for this run", "error", err)
+		} else {
+			var builtin []string
+			for _, tool := range registry.List() {
+				if info, ok := registry.Info(tool.Name()); ok && info.Category != plugins.CategoryPlugin {
+					builtin = append(builtin, tool.Name())
+				}
+			}
+			usage.Subscribe(bus, builtin)
+			usage.Count("mode." + runMode(batchRun.tool, *scenarioFile, *metricsAddr, *agentAddr))
+			saveUsage = func() {
+				if err := usage.Save(); err != nil {
+					logger.Warn("Telemetry report not saved", "error", err)
+				}
+			}
+			go sendTelemetry(usage, cfg.Telemetry, logger)
+		}
+	}
+	defer saveUsage()
+	
+	// Trace tool executions when an OTLP endpoint is configured
+	shutdownTracing := func() {}
+	if *otlpEndpoint != "" || tracing.Configured() {
+		provider, err := tracing.NewProvider(context.Background(), *otlpEndpoint, version.Get().Version, logger)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Tracing disabled: %v\n", err)
+		} else {
+			shutdownTracing = func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if err := provider.Shutdown(ctx); err != nil {
+					log.Printf("Failed to export traces: %v", err)
+				}
+			}
+			registry.Wrap(tracing.Instrument)
+		}
+	}
+	defer shutdownTracing()
+	exit := func(code int) {
+		saveUsage()
+		saveRecording()
+		shutdownTracing()
+		logger.Close()
+		os.Exit(code)
+	}
+	
+	// Run a batch report instead of the TUI when requested
+	if batchRun.tool != "" {
+		failed, err := runBatch(registry, logger, batchRun)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Batch run failed: %v\n", err)
+			exit(1)
+		}
+		if failed > 0 {
+			exit(2)
+		}
+		return
+	}
+	
+	// Run a scenario instead of the TUI when requested
+	if *scenarioFile != "" {
+		passed, err := runScenario(registry, logger, *scenarioFile, batchRun)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Scenario run failed: %v\n", err)
+			exit(1)
+		}
+		if !passed {
+			exit(2)
+		}
+		return
+	}
+	
+	// Serve metrics of scheduled probes instead of the TUI when requested
+	if *metricsAddr != "" {
+		if err := runMetrics(registry, logger, *metricsAddr, probes, *interval, batchRun, cfg.Notify); err != nil {
+			fmt.Fprintf(os.Stderr, "Metrics mode failed: %v\n", err)
+			exit(1)
+		}
+		return
+	}
+	
+	// Serve the tools to remote clients instead of the TUI when requested
+	if *agentAddr != "" {
+		if err := runAgent(registry, logger, *agentAddr, *agentName, *agentCert, *agentKey, *agentOpen); err != nil {
+			fmt.Fprintf(os.Stderr, "Agent mode failed: %v\n", err)
+			exit(1)
+		}
+		return
+	}
+	
+	// Run diagnostics from the connected agents as well
+	if len(agents) > 0 {
+		if err := connectAgents(registry, logger, agents, *agentOpen); err != nil {
+			fmt.Fprintf(os.Stderr, "Connecting to agents failed: %v\n", err)
+			exit(1)
+		}
+	}
+	
+	// Load the theme files and select the configured theme
+	themes := tui.NewThemeManager()
+	themes.DetectBackground()
+	for _, err := range themes.LoadDir(configManager.ThemeDir()) {
+		logger.Warn("Theme file not loaded", "error", err)
+	}
+	if !themes.SetTheme(cfg.UI.Theme) {
+		logger.Warn("Theme not found, using the default theme", "theme", cfg.UI.Theme)
+	}
 	
 	// Create main TUI model
-	mainModel := tui.NewMainModel(registry, cfg, configManager, theme)
+	mainModel := tui.NewMainModel(registry, cfg, configManager, themes.GetTheme())
+	mainModel.SetThemeManager(themes)
+	mainModel.SetDNSServerReporter(networkClient)
+	mainModel.SetEventBus(bus)
+	mainModel.SetLogBuffer(logBuffer)
+	mainModel.SetPluginReporter(pluginInventory)
+	mainModel.SetCacheReporter(networkClient)
+	if reporter, ok := toolClient.(domain.CapabilityReporter); ok {
+		mainModel.SetCapabilityReporter(reporter)
+	}
 	
-	// Create Bubble Tea program
-	program := tea.NewProgram(
-		mainModel,
-		tea.WithAltScreen(),
-		tea.WithMouseCellMotion(),
-	)
+	// Offer to restore the session saved when the TUI last exited
+	sessionPath := session.DefaultPath()
+	var savedSession *session.Session
+	if !*fresh {
+		savedSession, err = session.Load(sessionPath)
+		if err != nil {
+			log.Printf("Ignoring saved session: %v", err)
+		}
+	}
+	mainModel.SetSession(sessionPath, savedSession)
+
+	// Result tables keep the columns as the user sorted, hid, sized and moved them
+	layoutsPath := session.LayoutsPath()
+	layouts, err := session.LoadLayouts(layoutsPath)
+	if err != nil {
+		log.Printf("Ignoring saved table layouts: %v", err)
+	}
+	mainModel.SetTableLayouts(tui.NewTableLayouts(layoutsPath, layouts))
+
+	// Host fields complete recent and starred targets and the system's hosts
+	targetsPath := targets.DefaultPath()
+	targetList, err := targets.Load(targetsPath)
+	if err != nil {
+		log.Printf("Ignoring saved targets: %v", err)
+	}
+	hosts := targets.LoadHosts(targets.HostsFile(), targets.SSHConfigFile())
+	mainModel.SetTargets(tui.NewTargetBook(targetsPath, targetList, hosts))
 	
-	// Start the TUI
-	if _, err := program.Run(); err != nil {
+	// Create Bubble Tea program. The accessible mode stays in the normal
+	// screen buffer, where screen readers follow the output line by line.
+	options := []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion()}
+	if cfg.UI.Accessible {
+		options = nil
+	}
+	var model tea.Model = mainModel
+	var recorder *cast.Recorder
+	if *castFile != "" {
+		castOut, err := os.Create(*castFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to record the session: %v\n", err)
+			exit(1)
+		}
+		defer castOut.Close()
+		recorder = cast.NewRecorder(mainModel, castOut, "NetTraceX "+version.Get().Version)
+		model = recorder
+	}
+	if usage != nil {
+		model = telemetry.Wrap(model, usage)
+	}
+	program := tea.NewProgram(model, options...)
+
+	// Mark the tool runs in the recording
+	if recorder != nil {
+		bus.Subscribe(func(event events.Event) {
+			label := event.Tool
+			if event.Target != "" {
+				label += " " + event.Target
+			}
+			program.Send(cast.MarkMsg{Label: label})
+		}, events.KindToolStarted)
+	}
+	
+	// Announce a newer release in the header, asking GitHub at most once a
+	// day; development builds have no version to compare
+	if current := version.Get().Version; cfg.UI.CheckUpdates && update.Released(current) {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			updater := update.New()
+			if channel, err := update.ParseChannel(cfg.UI.UpdateChannel); err == nil {
+				updater.Channel = channel
+			}
+			release, newer, err := updater.CheckCached(ctx, current, update.StatePath(), update.DefaultCheckInterval)
+			if err != nil {
+				logger.Debug("Update check failed", "error", err)
+				return
+			}
+			if newer {
+				program.Send(tui.UpdateAvailableMsg{Version: release.Tag, URL: release.URL})
+			}
+		}()
+	}
+	
+	// Apply changes to the configuration file without restarting
+	if configManager.GetConfigFile() != "" {
+		stopWatching, err := configManager.Watch(func() {
+			program.Send(tui.ConfigFileChangedMsg{})
+		})
+		if err != nil {
+			logger.Warn("Configuration changes will not be reloaded", "error", err)
+		} else {
+			defer stopWatching()
+		}
+	}
+	
+	// Start the TUI. It owns the terminal while it runs, so console logs
+	// go only to the buffer behind the log panel until it exits.
+	logger.MuteConsole(true)
+	_, err = program.Run()
+	logger.MuteConsole(false)
+	if recorder != nil {
+		if err := recorder.Close(); err != nil {
+			log.Printf("Failed to save the session recording: %v", err)
+		}
+	}
+	if err != nil {
 		log.Printf("Error running TUI: %v", err)
-		os.Exit(1)
+		exit(1)
 	}
 }
\ No newline at end of file